@@ -0,0 +1,131 @@
+// Package floopv1 is the floop gRPC API: the FloopService definition in
+// floop.proto, its generated Go code, and client helpers.
+//
+// Start a server with 'floop mcp-server --grpc 127.0.0.1:7346' (or
+// --grpc unix:/path/to/floop.sock), then:
+//
+//	client, err := floopv1.Dial("127.0.0.1:7346")
+//	if err != nil {
+//		return err
+//	}
+//	defer client.Close()
+//	behaviors, err := client.Active(ctx, &floopv1.ActivationContext{File: "main.go"})
+//
+// A server started with FLOOP_SERVER_TOKEN, which may then listen beyond
+// loopback, needs the token on every call:
+//
+//	client, err := floopv1.Dial(target,
+//		grpc.WithTransportCredentials(insecure.NewCredentials()),
+//		floopv1.WithToken(os.Getenv("FLOOP_SERVER_TOKEN")))
+//
+// Regenerate the code after editing floop.proto with 'make proto'.
+package floopv1
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// Client is a FloopServiceClient that owns its connection.
+type Client struct {
+	FloopServiceClient
+	conn *grpc.ClientConn
+}
+
+// Dial returns a client for the floop server at target, such as
+// "127.0.0.1:7346" or "unix:///path/to/floop.sock". Without options the
+// connection uses no transport security, which suits a server on the
+// loopback interface or a Unix socket.
+func Dial(target string, opts ...grpc.DialOption) (*Client, error) {
+	if len(opts) == 0 {
+		opts = []grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}
+	}
+	conn, err := grpc.NewClient(target, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Client{FloopServiceClient: NewFloopServiceClient(conn), conn: conn}, nil
+}
+
+// WithToken sends token as a bearer token on every call, for a server
+// started with FLOOP_SERVER_TOKEN.
+func WithToken(token string) grpc.DialOption {
+	return grpc.WithPerRPCCredentials(bearerToken(token))
+}
+
+// bearerToken implements credentials.PerRPCCredentials.
+type bearerToken string
+
+// GetRequestMetadata implements credentials.PerRPCCredentials.
+func (t bearerToken) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(t)}, nil
+}
+
+// RequireTransportSecurity implements credentials.PerRPCCredentials. The
+// token is also accepted over plaintext so it works with the default Dial
+// options; use TLS when the server is reached over a network.
+func (t bearerToken) RequireTransportSecurity() bool {
+	return false
+}
+
+// Close closes the client's connection.
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+// Learn captures a correction: the agent did wrong, and should have done
+// right. actx may be nil.
+func (c *Client) Learn(ctx context.Context, wrong, right string, actx *ActivationContext) (*ProcessCorrectionResponse, error) {
+	return c.ProcessCorrection(ctx, &ProcessCorrectionRequest{Wrong: wrong, Right: right, Context: actx})
+}
+
+// Active returns the behaviors active in actx.
+func (c *Client) Active(ctx context.Context, actx *ActivationContext) ([]*Behavior, error) {
+	resp, err := c.GetActive(ctx, &GetActiveRequest{Context: actx})
+	if err != nil {
+		return nil, err
+	}
+	return resp.GetActive(), nil
+}
+
+// Confirm records that a behavior was helpful.
+func (c *Client) Confirm(ctx context.Context, behaviorID string) error {
+	_, err := c.Feedback(ctx, &FeedbackRequest{BehaviorId: behaviorID, Signal: FeedbackSignal_FEEDBACK_SIGNAL_CONFIRMED})
+	return err
+}
+
+// Override records that a behavior was contradicted.
+func (c *Client) Override(ctx context.Context, behaviorID string) error {
+	_, err := c.Feedback(ctx, &FeedbackRequest{BehaviorId: behaviorID, Signal: FeedbackSignal_FEEDBACK_SIGNAL_OVERRIDDEN})
+	return err
+}
+
+// Watch calls fn with each change to the active set of actx until ctx is
+// done, the server ends the stream, or fn returns an error. The first
+// change carries the whole active set. Watch returns nil when the stream
+// ends cleanly and fn's error if it stopped the watch.
+func (c *Client) Watch(ctx context.Context, actx *ActivationContext, fn func(*ActiveChange) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	stream, err := c.WatchActive(ctx, &WatchActiveRequest{Context: actx})
+	if err != nil {
+		return err
+	}
+	for {
+		change, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := fn(change); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,1300 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: floop/v1/floop.proto
+
+package floopv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// FeedbackSignal is the outcome of an active behavior.
+type FeedbackSignal int32
+
+const (
+	FeedbackSignal_FEEDBACK_SIGNAL_UNSPECIFIED FeedbackSignal = 0
+	// The behavior was helpful.
+	FeedbackSignal_FEEDBACK_SIGNAL_CONFIRMED FeedbackSignal = 1
+	// The behavior was contradicted.
+	FeedbackSignal_FEEDBACK_SIGNAL_OVERRIDDEN FeedbackSignal = 2
+)
+
+// Enum value maps for FeedbackSignal.
+var (
+	FeedbackSignal_name = map[int32]string{
+		0: "FEEDBACK_SIGNAL_UNSPECIFIED",
+		1: "FEEDBACK_SIGNAL_CONFIRMED",
+		2: "FEEDBACK_SIGNAL_OVERRIDDEN",
+	}
+	FeedbackSignal_value = map[string]int32{
+		"FEEDBACK_SIGNAL_UNSPECIFIED": 0,
+		"FEEDBACK_SIGNAL_CONFIRMED":   1,
+		"FEEDBACK_SIGNAL_OVERRIDDEN":  2,
+	}
+)
+
+func (x FeedbackSignal) Enum() *FeedbackSignal {
+	p := new(FeedbackSignal)
+	*p = x
+	return p
+}
+
+func (x FeedbackSignal) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (FeedbackSignal) Descriptor() protoreflect.EnumDescriptor {
+	return file_floop_v1_floop_proto_enumTypes[0].Descriptor()
+}
+
+func (FeedbackSignal) Type() protoreflect.EnumType {
+	return &file_floop_v1_floop_proto_enumTypes[0]
+}
+
+func (x FeedbackSignal) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use FeedbackSignal.Descriptor instead.
+func (FeedbackSignal) EnumDescriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{0}
+}
+
+// ActivationContext describes where an agent is working.
+type ActivationContext struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Current file path, relative to the project root.
+	File string `protobuf:"bytes,1,opt,name=file,proto3" json:"file,omitempty"`
+	// Current task type (e.g. "development", "testing", "refactoring").
+	Task string `protobuf:"bytes,2,opt,name=task,proto3" json:"task,omitempty"`
+	// Programming language. Overrides file extension inference.
+	Language      string `protobuf:"bytes,3,opt,name=language,proto3" json:"language,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivationContext) Reset() {
+	*x = ActivationContext{}
+	mi := &file_floop_v1_floop_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivationContext) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivationContext) ProtoMessage() {}
+
+func (x *ActivationContext) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivationContext.ProtoReflect.Descriptor instead.
+func (*ActivationContext) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ActivationContext) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *ActivationContext) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+func (x *ActivationContext) GetLanguage() string {
+	if x != nil {
+		return x.Language
+	}
+	return ""
+}
+
+// Behavior is an active behavior, with content for its token budget tier.
+type Behavior struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name  string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Kind  string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	// Token budget tier: full, summary, or name_only.
+	Tier string `protobuf:"bytes,4,opt,name=tier,proto3" json:"tier,omitempty"`
+	// Content fields for the tier; always includes canonical.
+	Content    *structpb.Struct `protobuf:"bytes,5,opt,name=content,proto3" json:"content,omitempty"`
+	Confidence float64          `protobuf:"fixed64,6,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	// Activation conditions.
+	When *structpb.Struct `protobuf:"bytes,7,opt,name=when,proto3" json:"when,omitempty"`
+	Tags []string         `protobuf:"bytes,8,rep,name=tags,proto3" json:"tags,omitempty"`
+	// Spreading activation level, when reached by spreading.
+	Activation float64 `protobuf:"fixed64,9,opt,name=activation,proto3" json:"activation,omitempty"`
+	// Hops from the seed behavior, when reached by spreading.
+	Distance int32 `protobuf:"varint,10,opt,name=distance,proto3" json:"distance,omitempty"`
+	// Seed behavior spreading started from.
+	SeedSource    string `protobuf:"bytes,11,opt,name=seed_source,json=seedSource,proto3" json:"seed_source,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Behavior) Reset() {
+	*x = Behavior{}
+	mi := &file_floop_v1_floop_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Behavior) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Behavior) ProtoMessage() {}
+
+func (x *Behavior) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Behavior.ProtoReflect.Descriptor instead.
+func (*Behavior) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Behavior) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Behavior) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Behavior) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *Behavior) GetTier() string {
+	if x != nil {
+		return x.Tier
+	}
+	return ""
+}
+
+func (x *Behavior) GetContent() *structpb.Struct {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+func (x *Behavior) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *Behavior) GetWhen() *structpb.Struct {
+	if x != nil {
+		return x.When
+	}
+	return nil
+}
+
+func (x *Behavior) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *Behavior) GetActivation() float64 {
+	if x != nil {
+		return x.Activation
+	}
+	return 0
+}
+
+func (x *Behavior) GetDistance() int32 {
+	if x != nil {
+		return x.Distance
+	}
+	return 0
+}
+
+func (x *Behavior) GetSeedSource() string {
+	if x != nil {
+		return x.SeedSource
+	}
+	return ""
+}
+
+type ProcessCorrectionRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// What the agent did; stored as provenance only.
+	Wrong string `protobuf:"bytes,1,opt,name=wrong,proto3" json:"wrong,omitempty"`
+	// What should have been done instead.
+	Right   string             `protobuf:"bytes,2,opt,name=right,proto3" json:"right,omitempty"`
+	Context *ActivationContext `protobuf:"bytes,3,opt,name=context,proto3" json:"context,omitempty"`
+	// Merge into a duplicate behavior instead of adding a new one.
+	AutoMerge bool `protobuf:"varint,4,opt,name=auto_merge,json=autoMerge,proto3" json:"auto_merge,omitempty"`
+	// Tags to apply, merged with inferred tags (max 5).
+	Tags          []string `protobuf:"bytes,5,rep,name=tags,proto3" json:"tags,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessCorrectionRequest) Reset() {
+	*x = ProcessCorrectionRequest{}
+	mi := &file_floop_v1_floop_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessCorrectionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessCorrectionRequest) ProtoMessage() {}
+
+func (x *ProcessCorrectionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessCorrectionRequest.ProtoReflect.Descriptor instead.
+func (*ProcessCorrectionRequest) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ProcessCorrectionRequest) GetWrong() string {
+	if x != nil {
+		return x.Wrong
+	}
+	return ""
+}
+
+func (x *ProcessCorrectionRequest) GetRight() string {
+	if x != nil {
+		return x.Right
+	}
+	return ""
+}
+
+func (x *ProcessCorrectionRequest) GetContext() *ActivationContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *ProcessCorrectionRequest) GetAutoMerge() bool {
+	if x != nil {
+		return x.AutoMerge
+	}
+	return false
+}
+
+func (x *ProcessCorrectionRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+type ProcessCorrectionResponse struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	CorrectionId string                 `protobuf:"bytes,1,opt,name=correction_id,json=correctionId,proto3" json:"correction_id,omitempty"`
+	BehaviorId   string                 `protobuf:"bytes,2,opt,name=behavior_id,json=behaviorId,proto3" json:"behavior_id,omitempty"`
+	// Where the behavior was stored: local or global.
+	Scope        string `protobuf:"bytes,3,opt,name=scope,proto3" json:"scope,omitempty"`
+	AutoAccepted bool   `protobuf:"varint,4,opt,name=auto_accepted,json=autoAccepted,proto3" json:"auto_accepted,omitempty"`
+	// Placement confidence (0.0-1.0).
+	Confidence     float64  `protobuf:"fixed64,5,opt,name=confidence,proto3" json:"confidence,omitempty"`
+	RequiresReview bool     `protobuf:"varint,6,opt,name=requires_review,json=requiresReview,proto3" json:"requires_review,omitempty"`
+	ReviewReasons  []string `protobuf:"bytes,7,rep,name=review_reasons,json=reviewReasons,proto3" json:"review_reasons,omitempty"`
+	// The behavior is pending and inactive until approved.
+	HeldForReview   bool    `protobuf:"varint,8,opt,name=held_for_review,json=heldForReview,proto3" json:"held_for_review,omitempty"`
+	MergedIntoId    string  `protobuf:"bytes,9,opt,name=merged_into_id,json=mergedIntoId,proto3" json:"merged_into_id,omitempty"`
+	MergeSimilarity float64 `protobuf:"fixed64,10,opt,name=merge_similarity,json=mergeSimilarity,proto3" json:"merge_similarity,omitempty"`
+	// Forgotten behavior the correction matched; nothing was learned.
+	ForgottenId   string `protobuf:"bytes,11,opt,name=forgotten_id,json=forgottenId,proto3" json:"forgotten_id,omitempty"`
+	Message       string `protobuf:"bytes,12,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ProcessCorrectionResponse) Reset() {
+	*x = ProcessCorrectionResponse{}
+	mi := &file_floop_v1_floop_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ProcessCorrectionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ProcessCorrectionResponse) ProtoMessage() {}
+
+func (x *ProcessCorrectionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ProcessCorrectionResponse.ProtoReflect.Descriptor instead.
+func (*ProcessCorrectionResponse) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ProcessCorrectionResponse) GetCorrectionId() string {
+	if x != nil {
+		return x.CorrectionId
+	}
+	return ""
+}
+
+func (x *ProcessCorrectionResponse) GetBehaviorId() string {
+	if x != nil {
+		return x.BehaviorId
+	}
+	return ""
+}
+
+func (x *ProcessCorrectionResponse) GetScope() string {
+	if x != nil {
+		return x.Scope
+	}
+	return ""
+}
+
+func (x *ProcessCorrectionResponse) GetAutoAccepted() bool {
+	if x != nil {
+		return x.AutoAccepted
+	}
+	return false
+}
+
+func (x *ProcessCorrectionResponse) GetConfidence() float64 {
+	if x != nil {
+		return x.Confidence
+	}
+	return 0
+}
+
+func (x *ProcessCorrectionResponse) GetRequiresReview() bool {
+	if x != nil {
+		return x.RequiresReview
+	}
+	return false
+}
+
+func (x *ProcessCorrectionResponse) GetReviewReasons() []string {
+	if x != nil {
+		return x.ReviewReasons
+	}
+	return nil
+}
+
+func (x *ProcessCorrectionResponse) GetHeldForReview() bool {
+	if x != nil {
+		return x.HeldForReview
+	}
+	return false
+}
+
+func (x *ProcessCorrectionResponse) GetMergedIntoId() string {
+	if x != nil {
+		return x.MergedIntoId
+	}
+	return ""
+}
+
+func (x *ProcessCorrectionResponse) GetMergeSimilarity() float64 {
+	if x != nil {
+		return x.MergeSimilarity
+	}
+	return 0
+}
+
+func (x *ProcessCorrectionResponse) GetForgottenId() string {
+	if x != nil {
+		return x.ForgottenId
+	}
+	return ""
+}
+
+func (x *ProcessCorrectionResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type GetActiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       *ActivationContext     `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActiveRequest) Reset() {
+	*x = GetActiveRequest{}
+	mi := &file_floop_v1_floop_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveRequest) ProtoMessage() {}
+
+func (x *GetActiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveRequest.ProtoReflect.Descriptor instead.
+func (*GetActiveRequest) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetActiveRequest) GetContext() *ActivationContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+// TokenStats reports how the active set fits the token budget.
+type TokenStats struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	TotalCanonicalTokens int32                  `protobuf:"varint,1,opt,name=total_canonical_tokens,json=totalCanonicalTokens,proto3" json:"total_canonical_tokens,omitempty"`
+	BudgetDefault        int32                  `protobuf:"varint,2,opt,name=budget_default,json=budgetDefault,proto3" json:"budget_default,omitempty"`
+	BehaviorCount        int32                  `protobuf:"varint,3,opt,name=behavior_count,json=behaviorCount,proto3" json:"behavior_count,omitempty"`
+	FullCount            int32                  `protobuf:"varint,4,opt,name=full_count,json=fullCount,proto3" json:"full_count,omitempty"`
+	SummaryCount         int32                  `protobuf:"varint,5,opt,name=summary_count,json=summaryCount,proto3" json:"summary_count,omitempty"`
+	NameOnlyCount        int32                  `protobuf:"varint,6,opt,name=name_only_count,json=nameOnlyCount,proto3" json:"name_only_count,omitempty"`
+	OmittedCount         int32                  `protobuf:"varint,7,opt,name=omitted_count,json=omittedCount,proto3" json:"omitted_count,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *TokenStats) Reset() {
+	*x = TokenStats{}
+	mi := &file_floop_v1_floop_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenStats) ProtoMessage() {}
+
+func (x *TokenStats) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenStats.ProtoReflect.Descriptor instead.
+func (*TokenStats) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TokenStats) GetTotalCanonicalTokens() int32 {
+	if x != nil {
+		return x.TotalCanonicalTokens
+	}
+	return 0
+}
+
+func (x *TokenStats) GetBudgetDefault() int32 {
+	if x != nil {
+		return x.BudgetDefault
+	}
+	return 0
+}
+
+func (x *TokenStats) GetBehaviorCount() int32 {
+	if x != nil {
+		return x.BehaviorCount
+	}
+	return 0
+}
+
+func (x *TokenStats) GetFullCount() int32 {
+	if x != nil {
+		return x.FullCount
+	}
+	return 0
+}
+
+func (x *TokenStats) GetSummaryCount() int32 {
+	if x != nil {
+		return x.SummaryCount
+	}
+	return 0
+}
+
+func (x *TokenStats) GetNameOnlyCount() int32 {
+	if x != nil {
+		return x.NameOnlyCount
+	}
+	return 0
+}
+
+func (x *TokenStats) GetOmittedCount() int32 {
+	if x != nil {
+		return x.OmittedCount
+	}
+	return 0
+}
+
+type GetActiveResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Context used for activation.
+	Context    *structpb.Struct `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	Active     []*Behavior      `protobuf:"bytes,2,rep,name=active,proto3" json:"active,omitempty"`
+	TokenStats *TokenStats      `protobuf:"bytes,3,opt,name=token_stats,json=tokenStats,proto3" json:"token_stats,omitempty"`
+	// Activation exceeded its time budget and shed load.
+	Degraded      bool `protobuf:"varint,4,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetActiveResponse) Reset() {
+	*x = GetActiveResponse{}
+	mi := &file_floop_v1_floop_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetActiveResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetActiveResponse) ProtoMessage() {}
+
+func (x *GetActiveResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetActiveResponse.ProtoReflect.Descriptor instead.
+func (*GetActiveResponse) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetActiveResponse) GetContext() *structpb.Struct {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+func (x *GetActiveResponse) GetActive() []*Behavior {
+	if x != nil {
+		return x.Active
+	}
+	return nil
+}
+
+func (x *GetActiveResponse) GetTokenStats() *TokenStats {
+	if x != nil {
+		return x.TokenStats
+	}
+	return nil
+}
+
+func (x *GetActiveResponse) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+type FeedbackRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BehaviorId    string                 `protobuf:"bytes,1,opt,name=behavior_id,json=behaviorId,proto3" json:"behavior_id,omitempty"`
+	Signal        FeedbackSignal         `protobuf:"varint,2,opt,name=signal,proto3,enum=floop.v1.FeedbackSignal" json:"signal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedbackRequest) Reset() {
+	*x = FeedbackRequest{}
+	mi := &file_floop_v1_floop_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedbackRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedbackRequest) ProtoMessage() {}
+
+func (x *FeedbackRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedbackRequest.ProtoReflect.Descriptor instead.
+func (*FeedbackRequest) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *FeedbackRequest) GetBehaviorId() string {
+	if x != nil {
+		return x.BehaviorId
+	}
+	return ""
+}
+
+func (x *FeedbackRequest) GetSignal() FeedbackSignal {
+	if x != nil {
+		return x.Signal
+	}
+	return FeedbackSignal_FEEDBACK_SIGNAL_UNSPECIFIED
+}
+
+type FeedbackResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	BehaviorId    string                 `protobuf:"bytes,1,opt,name=behavior_id,json=behaviorId,proto3" json:"behavior_id,omitempty"`
+	Signal        FeedbackSignal         `protobuf:"varint,2,opt,name=signal,proto3,enum=floop.v1.FeedbackSignal" json:"signal,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FeedbackResponse) Reset() {
+	*x = FeedbackResponse{}
+	mi := &file_floop_v1_floop_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FeedbackResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FeedbackResponse) ProtoMessage() {}
+
+func (x *FeedbackResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FeedbackResponse.ProtoReflect.Descriptor instead.
+func (*FeedbackResponse) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *FeedbackResponse) GetBehaviorId() string {
+	if x != nil {
+		return x.BehaviorId
+	}
+	return ""
+}
+
+func (x *FeedbackResponse) GetSignal() FeedbackSignal {
+	if x != nil {
+		return x.Signal
+	}
+	return FeedbackSignal_FEEDBACK_SIGNAL_UNSPECIFIED
+}
+
+func (x *FeedbackResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type SearchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Query string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	// Maximum number of results (default 10).
+	Limit         int32 `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchRequest) Reset() {
+	*x = SearchRequest{}
+	mi := &file_floop_v1_floop_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchRequest) ProtoMessage() {}
+
+func (x *SearchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchRequest.ProtoReflect.Descriptor instead.
+func (*SearchRequest) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *SearchRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+// SearchResult is one behavior matching a search.
+type SearchResult struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	Id        string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Kind      string                 `protobuf:"bytes,3,opt,name=kind,proto3" json:"kind,omitempty"`
+	Canonical string                 `protobuf:"bytes,4,opt,name=canonical,proto3" json:"canonical,omitempty"`
+	// Score relative to the best match (0.0-1.0).
+	Score         float64 `protobuf:"fixed64,5,opt,name=score,proto3" json:"score,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResult) Reset() {
+	*x = SearchResult{}
+	mi := &file_floop_v1_floop_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResult) ProtoMessage() {}
+
+func (x *SearchResult) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResult.ProtoReflect.Descriptor instead.
+func (*SearchResult) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *SearchResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *SearchResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *SearchResult) GetKind() string {
+	if x != nil {
+		return x.Kind
+	}
+	return ""
+}
+
+func (x *SearchResult) GetCanonical() string {
+	if x != nil {
+		return x.Canonical
+	}
+	return ""
+}
+
+func (x *SearchResult) GetScore() float64 {
+	if x != nil {
+		return x.Score
+	}
+	return 0
+}
+
+type SearchResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*SearchResult        `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchResponse) Reset() {
+	*x = SearchResponse{}
+	mi := &file_floop_v1_floop_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchResponse) ProtoMessage() {}
+
+func (x *SearchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchResponse.ProtoReflect.Descriptor instead.
+func (*SearchResponse) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SearchResponse) GetResults() []*SearchResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type WatchActiveRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Context       *ActivationContext     `protobuf:"bytes,1,opt,name=context,proto3" json:"context,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchActiveRequest) Reset() {
+	*x = WatchActiveRequest{}
+	mi := &file_floop_v1_floop_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchActiveRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchActiveRequest) ProtoMessage() {}
+
+func (x *WatchActiveRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchActiveRequest.ProtoReflect.Descriptor instead.
+func (*WatchActiveRequest) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchActiveRequest) GetContext() *ActivationContext {
+	if x != nil {
+		return x.Context
+	}
+	return nil
+}
+
+// ActiveChange is a change to a watched active set.
+type ActiveChange struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Behaviors that became active.
+	Added []*Behavior `protobuf:"bytes,1,rep,name=added,proto3" json:"added,omitempty"`
+	// IDs of behaviors that are no longer active.
+	Removed []string `protobuf:"bytes,2,rep,name=removed,proto3" json:"removed,omitempty"`
+	// Number of behaviors still active from the previous message.
+	Unchanged int32 `protobuf:"varint,3,opt,name=unchanged,proto3" json:"unchanged,omitempty"`
+	// Total number of active behaviors.
+	Count         int32 `protobuf:"varint,4,opt,name=count,proto3" json:"count,omitempty"`
+	Degraded      bool  `protobuf:"varint,5,opt,name=degraded,proto3" json:"degraded,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActiveChange) Reset() {
+	*x = ActiveChange{}
+	mi := &file_floop_v1_floop_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActiveChange) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActiveChange) ProtoMessage() {}
+
+func (x *ActiveChange) ProtoReflect() protoreflect.Message {
+	mi := &file_floop_v1_floop_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActiveChange.ProtoReflect.Descriptor instead.
+func (*ActiveChange) Descriptor() ([]byte, []int) {
+	return file_floop_v1_floop_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ActiveChange) GetAdded() []*Behavior {
+	if x != nil {
+		return x.Added
+	}
+	return nil
+}
+
+func (x *ActiveChange) GetRemoved() []string {
+	if x != nil {
+		return x.Removed
+	}
+	return nil
+}
+
+func (x *ActiveChange) GetUnchanged() int32 {
+	if x != nil {
+		return x.Unchanged
+	}
+	return 0
+}
+
+func (x *ActiveChange) GetCount() int32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *ActiveChange) GetDegraded() bool {
+	if x != nil {
+		return x.Degraded
+	}
+	return false
+}
+
+var File_floop_v1_floop_proto protoreflect.FileDescriptor
+
+const file_floop_v1_floop_proto_rawDesc = "" +
+	"\n" +
+	"\x14floop/v1/floop.proto\x12\bfloop.v1\x1a\x1cgoogle/protobuf/struct.proto\"W\n" +
+	"\x11ActivationContext\x12\x12\n" +
+	"\x04file\x18\x01 \x01(\tR\x04file\x12\x12\n" +
+	"\x04task\x18\x02 \x01(\tR\x04task\x12\x1a\n" +
+	"\blanguage\x18\x03 \x01(\tR\blanguage\"\xc7\x02\n" +
+	"\bBehavior\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x12\n" +
+	"\x04tier\x18\x04 \x01(\tR\x04tier\x121\n" +
+	"\acontent\x18\x05 \x01(\v2\x17.google.protobuf.StructR\acontent\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x06 \x01(\x01R\n" +
+	"confidence\x12+\n" +
+	"\x04when\x18\a \x01(\v2\x17.google.protobuf.StructR\x04when\x12\x12\n" +
+	"\x04tags\x18\b \x03(\tR\x04tags\x12\x1e\n" +
+	"\n" +
+	"activation\x18\t \x01(\x01R\n" +
+	"activation\x12\x1a\n" +
+	"\bdistance\x18\n" +
+	" \x01(\x05R\bdistance\x12\x1f\n" +
+	"\vseed_source\x18\v \x01(\tR\n" +
+	"seedSource\"\xb0\x01\n" +
+	"\x18ProcessCorrectionRequest\x12\x14\n" +
+	"\x05wrong\x18\x01 \x01(\tR\x05wrong\x12\x14\n" +
+	"\x05right\x18\x02 \x01(\tR\x05right\x125\n" +
+	"\acontext\x18\x03 \x01(\v2\x1b.floop.v1.ActivationContextR\acontext\x12\x1d\n" +
+	"\n" +
+	"auto_merge\x18\x04 \x01(\bR\tautoMerge\x12\x12\n" +
+	"\x04tags\x18\x05 \x03(\tR\x04tags\"\xc2\x03\n" +
+	"\x19ProcessCorrectionResponse\x12#\n" +
+	"\rcorrection_id\x18\x01 \x01(\tR\fcorrectionId\x12\x1f\n" +
+	"\vbehavior_id\x18\x02 \x01(\tR\n" +
+	"behaviorId\x12\x14\n" +
+	"\x05scope\x18\x03 \x01(\tR\x05scope\x12#\n" +
+	"\rauto_accepted\x18\x04 \x01(\bR\fautoAccepted\x12\x1e\n" +
+	"\n" +
+	"confidence\x18\x05 \x01(\x01R\n" +
+	"confidence\x12'\n" +
+	"\x0frequires_review\x18\x06 \x01(\bR\x0erequiresReview\x12%\n" +
+	"\x0ereview_reasons\x18\a \x03(\tR\rreviewReasons\x12&\n" +
+	"\x0fheld_for_review\x18\b \x01(\bR\rheldForReview\x12$\n" +
+	"\x0emerged_into_id\x18\t \x01(\tR\fmergedIntoId\x12)\n" +
+	"\x10merge_similarity\x18\n" +
+	" \x01(\x01R\x0fmergeSimilarity\x12!\n" +
+	"\fforgotten_id\x18\v \x01(\tR\vforgottenId\x12\x18\n" +
+	"\amessage\x18\f \x01(\tR\amessage\"I\n" +
+	"\x10GetActiveRequest\x125\n" +
+	"\acontext\x18\x01 \x01(\v2\x1b.floop.v1.ActivationContextR\acontext\"\xa1\x02\n" +
+	"\n" +
+	"TokenStats\x124\n" +
+	"\x16total_canonical_tokens\x18\x01 \x01(\x05R\x14totalCanonicalTokens\x12%\n" +
+	"\x0ebudget_default\x18\x02 \x01(\x05R\rbudgetDefault\x12%\n" +
+	"\x0ebehavior_count\x18\x03 \x01(\x05R\rbehaviorCount\x12\x1d\n" +
+	"\n" +
+	"full_count\x18\x04 \x01(\x05R\tfullCount\x12#\n" +
+	"\rsummary_count\x18\x05 \x01(\x05R\fsummaryCount\x12&\n" +
+	"\x0fname_only_count\x18\x06 \x01(\x05R\rnameOnlyCount\x12#\n" +
+	"\romitted_count\x18\a \x01(\x05R\fomittedCount\"\xc5\x01\n" +
+	"\x11GetActiveResponse\x121\n" +
+	"\acontext\x18\x01 \x01(\v2\x17.google.protobuf.StructR\acontext\x12*\n" +
+	"\x06active\x18\x02 \x03(\v2\x12.floop.v1.BehaviorR\x06active\x125\n" +
+	"\vtoken_stats\x18\x03 \x01(\v2\x14.floop.v1.TokenStatsR\n" +
+	"tokenStats\x12\x1a\n" +
+	"\bdegraded\x18\x04 \x01(\bR\bdegraded\"d\n" +
+	"\x0fFeedbackRequest\x12\x1f\n" +
+	"\vbehavior_id\x18\x01 \x01(\tR\n" +
+	"behaviorId\x120\n" +
+	"\x06signal\x18\x02 \x01(\x0e2\x18.floop.v1.FeedbackSignalR\x06signal\"\x7f\n" +
+	"\x10FeedbackResponse\x12\x1f\n" +
+	"\vbehavior_id\x18\x01 \x01(\tR\n" +
+	"behaviorId\x120\n" +
+	"\x06signal\x18\x02 \x01(\x0e2\x18.floop.v1.FeedbackSignalR\x06signal\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\";\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x05R\x05limit\"z\n" +
+	"\fSearchResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04kind\x18\x03 \x01(\tR\x04kind\x12\x1c\n" +
+	"\tcanonical\x18\x04 \x01(\tR\tcanonical\x12\x14\n" +
+	"\x05score\x18\x05 \x01(\x01R\x05score\"B\n" +
+	"\x0eSearchResponse\x120\n" +
+	"\aresults\x18\x01 \x03(\v2\x16.floop.v1.SearchResultR\aresults\"K\n" +
+	"\x12WatchActiveRequest\x125\n" +
+	"\acontext\x18\x01 \x01(\v2\x1b.floop.v1.ActivationContextR\acontext\"\xa2\x01\n" +
+	"\fActiveChange\x12(\n" +
+	"\x05added\x18\x01 \x03(\v2\x12.floop.v1.BehaviorR\x05added\x12\x18\n" +
+	"\aremoved\x18\x02 \x03(\tR\aremoved\x12\x1c\n" +
+	"\tunchanged\x18\x03 \x01(\x05R\tunchanged\x12\x14\n" +
+	"\x05count\x18\x04 \x01(\x05R\x05count\x12\x1a\n" +
+	"\bdegraded\x18\x05 \x01(\bR\bdegraded*p\n" +
+	"\x0eFeedbackSignal\x12\x1f\n" +
+	"\x1bFEEDBACK_SIGNAL_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19FEEDBACK_SIGNAL_CONFIRMED\x10\x01\x12\x1e\n" +
+	"\x1aFEEDBACK_SIGNAL_OVERRIDDEN\x10\x022\xf9\x02\n" +
+	"\fFloopService\x12\\\n" +
+	"\x11ProcessCorrection\x12\".floop.v1.ProcessCorrectionRequest\x1a#.floop.v1.ProcessCorrectionResponse\x12D\n" +
+	"\tGetActive\x12\x1a.floop.v1.GetActiveRequest\x1a\x1b.floop.v1.GetActiveResponse\x12A\n" +
+	"\bFeedback\x12\x19.floop.v1.FeedbackRequest\x1a\x1a.floop.v1.FeedbackResponse\x12;\n" +
+	"\x06Search\x12\x17.floop.v1.SearchRequest\x1a\x18.floop.v1.SearchResponse\x12E\n" +
+	"\vWatchActive\x12\x1c.floop.v1.WatchActiveRequest\x1a\x16.floop.v1.ActiveChange0\x01B2Z0github.com/nvandessel/floop/api/floop/v1;floopv1b\x06proto3"
+
+var (
+	file_floop_v1_floop_proto_rawDescOnce sync.Once
+	file_floop_v1_floop_proto_rawDescData []byte
+)
+
+func file_floop_v1_floop_proto_rawDescGZIP() []byte {
+	file_floop_v1_floop_proto_rawDescOnce.Do(func() {
+		file_floop_v1_floop_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_floop_v1_floop_proto_rawDesc), len(file_floop_v1_floop_proto_rawDesc)))
+	})
+	return file_floop_v1_floop_proto_rawDescData
+}
+
+var file_floop_v1_floop_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_floop_v1_floop_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
+var file_floop_v1_floop_proto_goTypes = []any{
+	(FeedbackSignal)(0),               // 0: floop.v1.FeedbackSignal
+	(*ActivationContext)(nil),         // 1: floop.v1.ActivationContext
+	(*Behavior)(nil),                  // 2: floop.v1.Behavior
+	(*ProcessCorrectionRequest)(nil),  // 3: floop.v1.ProcessCorrectionRequest
+	(*ProcessCorrectionResponse)(nil), // 4: floop.v1.ProcessCorrectionResponse
+	(*GetActiveRequest)(nil),          // 5: floop.v1.GetActiveRequest
+	(*TokenStats)(nil),                // 6: floop.v1.TokenStats
+	(*GetActiveResponse)(nil),         // 7: floop.v1.GetActiveResponse
+	(*FeedbackRequest)(nil),           // 8: floop.v1.FeedbackRequest
+	(*FeedbackResponse)(nil),          // 9: floop.v1.FeedbackResponse
+	(*SearchRequest)(nil),             // 10: floop.v1.SearchRequest
+	(*SearchResult)(nil),              // 11: floop.v1.SearchResult
+	(*SearchResponse)(nil),            // 12: floop.v1.SearchResponse
+	(*WatchActiveRequest)(nil),        // 13: floop.v1.WatchActiveRequest
+	(*ActiveChange)(nil),              // 14: floop.v1.ActiveChange
+	(*structpb.Struct)(nil),           // 15: google.protobuf.Struct
+}
+var file_floop_v1_floop_proto_depIdxs = []int32{
+	15, // 0: floop.v1.Behavior.content:type_name -> google.protobuf.Struct
+	15, // 1: floop.v1.Behavior.when:type_name -> google.protobuf.Struct
+	1,  // 2: floop.v1.ProcessCorrectionRequest.context:type_name -> floop.v1.ActivationContext
+	1,  // 3: floop.v1.GetActiveRequest.context:type_name -> floop.v1.ActivationContext
+	15, // 4: floop.v1.GetActiveResponse.context:type_name -> google.protobuf.Struct
+	2,  // 5: floop.v1.GetActiveResponse.active:type_name -> floop.v1.Behavior
+	6,  // 6: floop.v1.GetActiveResponse.token_stats:type_name -> floop.v1.TokenStats
+	0,  // 7: floop.v1.FeedbackRequest.signal:type_name -> floop.v1.FeedbackSignal
+	0,  // 8: floop.v1.FeedbackResponse.signal:type_name -> floop.v1.FeedbackSignal
+	11, // 9: floop.v1.SearchResponse.results:type_name -> floop.v1.SearchResult
+	1,  // 10: floop.v1.WatchActiveRequest.context:type_name -> floop.v1.ActivationContext
+	2,  // 11: floop.v1.ActiveChange.added:type_name -> floop.v1.Behavior
+	3,  // 12: floop.v1.FloopService.ProcessCorrection:input_type -> floop.v1.ProcessCorrectionRequest
+	5,  // 13: floop.v1.FloopService.GetActive:input_type -> floop.v1.GetActiveRequest
+	8,  // 14: floop.v1.FloopService.Feedback:input_type -> floop.v1.FeedbackRequest
+	10, // 15: floop.v1.FloopService.Search:input_type -> floop.v1.SearchRequest
+	13, // 16: floop.v1.FloopService.WatchActive:input_type -> floop.v1.WatchActiveRequest
+	4,  // 17: floop.v1.FloopService.ProcessCorrection:output_type -> floop.v1.ProcessCorrectionResponse
+	7,  // 18: floop.v1.FloopService.GetActive:output_type -> floop.v1.GetActiveResponse
+	9,  // 19: floop.v1.FloopService.Feedback:output_type -> floop.v1.FeedbackResponse
+	12, // 20: floop.v1.FloopService.Search:output_type -> floop.v1.SearchResponse
+	14, // 21: floop.v1.FloopService.WatchActive:output_type -> floop.v1.ActiveChange
+	17, // [17:22] is the sub-list for method output_type
+	12, // [12:17] is the sub-list for method input_type
+	12, // [12:12] is the sub-list for extension type_name
+	12, // [12:12] is the sub-list for extension extendee
+	0,  // [0:12] is the sub-list for field type_name
+}
+
+func init() { file_floop_v1_floop_proto_init() }
+func file_floop_v1_floop_proto_init() {
+	if File_floop_v1_floop_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_floop_v1_floop_proto_rawDesc), len(file_floop_v1_floop_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   14,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_floop_v1_floop_proto_goTypes,
+		DependencyIndexes: file_floop_v1_floop_proto_depIdxs,
+		EnumInfos:         file_floop_v1_floop_proto_enumTypes,
+		MessageInfos:      file_floop_v1_floop_proto_msgTypes,
+	}.Build()
+	File_floop_v1_floop_proto = out.File
+	file_floop_v1_floop_proto_goTypes = nil
+	file_floop_v1_floop_proto_depIdxs = nil
+}
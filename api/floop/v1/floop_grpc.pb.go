@@ -0,0 +1,299 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: floop/v1/floop.proto
+
+package floopv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	FloopService_ProcessCorrection_FullMethodName = "/floop.v1.FloopService/ProcessCorrection"
+	FloopService_GetActive_FullMethodName         = "/floop.v1.FloopService/GetActive"
+	FloopService_Feedback_FullMethodName          = "/floop.v1.FloopService/Feedback"
+	FloopService_Search_FullMethodName            = "/floop.v1.FloopService/Search"
+	FloopService_WatchActive_FullMethodName       = "/floop.v1.FloopService/WatchActive"
+)
+
+// FloopServiceClient is the client API for FloopService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FloopService exposes floop's core operations over gRPC. It mirrors the
+// floop_learn, floop_active, and floop_feedback MCP tools and 'floop search',
+// and adds a server stream of active-set changes for watch mode.
+type FloopServiceClient interface {
+	// ProcessCorrection captures a correction and extracts a behavior from it.
+	ProcessCorrection(ctx context.Context, in *ProcessCorrectionRequest, opts ...grpc.CallOption) (*ProcessCorrectionResponse, error)
+	// GetActive returns the behaviors active in a context.
+	GetActive(ctx context.Context, in *GetActiveRequest, opts ...grpc.CallOption) (*GetActiveResponse, error)
+	// Feedback records whether an active behavior helped or was contradicted.
+	Feedback(ctx context.Context, in *FeedbackRequest, opts ...grpc.CallOption) (*FeedbackResponse, error)
+	// Search ranks behaviors by a full-text match against a query.
+	Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
+	// WatchActive streams changes to the active set of a context. The first
+	// message carries the whole active set as added behaviors; later messages
+	// are sent when behaviors become active or inactive.
+	WatchActive(ctx context.Context, in *WatchActiveRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ActiveChange], error)
+}
+
+type floopServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFloopServiceClient(cc grpc.ClientConnInterface) FloopServiceClient {
+	return &floopServiceClient{cc}
+}
+
+func (c *floopServiceClient) ProcessCorrection(ctx context.Context, in *ProcessCorrectionRequest, opts ...grpc.CallOption) (*ProcessCorrectionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ProcessCorrectionResponse)
+	err := c.cc.Invoke(ctx, FloopService_ProcessCorrection_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *floopServiceClient) GetActive(ctx context.Context, in *GetActiveRequest, opts ...grpc.CallOption) (*GetActiveResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetActiveResponse)
+	err := c.cc.Invoke(ctx, FloopService_GetActive_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *floopServiceClient) Feedback(ctx context.Context, in *FeedbackRequest, opts ...grpc.CallOption) (*FeedbackResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(FeedbackResponse)
+	err := c.cc.Invoke(ctx, FloopService_Feedback_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *floopServiceClient) Search(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SearchResponse)
+	err := c.cc.Invoke(ctx, FloopService_Search_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *floopServiceClient) WatchActive(ctx context.Context, in *WatchActiveRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ActiveChange], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FloopService_ServiceDesc.Streams[0], FloopService_WatchActive_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchActiveRequest, ActiveChange]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FloopService_WatchActiveClient = grpc.ServerStreamingClient[ActiveChange]
+
+// FloopServiceServer is the server API for FloopService service.
+// All implementations must embed UnimplementedFloopServiceServer
+// for forward compatibility.
+//
+// FloopService exposes floop's core operations over gRPC. It mirrors the
+// floop_learn, floop_active, and floop_feedback MCP tools and 'floop search',
+// and adds a server stream of active-set changes for watch mode.
+type FloopServiceServer interface {
+	// ProcessCorrection captures a correction and extracts a behavior from it.
+	ProcessCorrection(context.Context, *ProcessCorrectionRequest) (*ProcessCorrectionResponse, error)
+	// GetActive returns the behaviors active in a context.
+	GetActive(context.Context, *GetActiveRequest) (*GetActiveResponse, error)
+	// Feedback records whether an active behavior helped or was contradicted.
+	Feedback(context.Context, *FeedbackRequest) (*FeedbackResponse, error)
+	// Search ranks behaviors by a full-text match against a query.
+	Search(context.Context, *SearchRequest) (*SearchResponse, error)
+	// WatchActive streams changes to the active set of a context. The first
+	// message carries the whole active set as added behaviors; later messages
+	// are sent when behaviors become active or inactive.
+	WatchActive(*WatchActiveRequest, grpc.ServerStreamingServer[ActiveChange]) error
+	mustEmbedUnimplementedFloopServiceServer()
+}
+
+// UnimplementedFloopServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedFloopServiceServer struct{}
+
+func (UnimplementedFloopServiceServer) ProcessCorrection(context.Context, *ProcessCorrectionRequest) (*ProcessCorrectionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ProcessCorrection not implemented")
+}
+func (UnimplementedFloopServiceServer) GetActive(context.Context, *GetActiveRequest) (*GetActiveResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActive not implemented")
+}
+func (UnimplementedFloopServiceServer) Feedback(context.Context, *FeedbackRequest) (*FeedbackResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Feedback not implemented")
+}
+func (UnimplementedFloopServiceServer) Search(context.Context, *SearchRequest) (*SearchResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Search not implemented")
+}
+func (UnimplementedFloopServiceServer) WatchActive(*WatchActiveRequest, grpc.ServerStreamingServer[ActiveChange]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchActive not implemented")
+}
+func (UnimplementedFloopServiceServer) mustEmbedUnimplementedFloopServiceServer() {}
+func (UnimplementedFloopServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeFloopServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FloopServiceServer will
+// result in compilation errors.
+type UnsafeFloopServiceServer interface {
+	mustEmbedUnimplementedFloopServiceServer()
+}
+
+func RegisterFloopServiceServer(s grpc.ServiceRegistrar, srv FloopServiceServer) {
+	// If the following call pancis, it indicates UnimplementedFloopServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&FloopService_ServiceDesc, srv)
+}
+
+func _FloopService_ProcessCorrection_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ProcessCorrectionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FloopServiceServer).ProcessCorrection(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FloopService_ProcessCorrection_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FloopServiceServer).ProcessCorrection(ctx, req.(*ProcessCorrectionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FloopService_GetActive_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetActiveRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FloopServiceServer).GetActive(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FloopService_GetActive_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FloopServiceServer).GetActive(ctx, req.(*GetActiveRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FloopService_Feedback_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FeedbackRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FloopServiceServer).Feedback(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FloopService_Feedback_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FloopServiceServer).Feedback(ctx, req.(*FeedbackRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FloopService_Search_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FloopServiceServer).Search(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FloopService_Search_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FloopServiceServer).Search(ctx, req.(*SearchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FloopService_WatchActive_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchActiveRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FloopServiceServer).WatchActive(m, &grpc.GenericServerStream[WatchActiveRequest, ActiveChange]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type FloopService_WatchActiveServer = grpc.ServerStreamingServer[ActiveChange]
+
+// FloopService_ServiceDesc is the grpc.ServiceDesc for FloopService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FloopService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "floop.v1.FloopService",
+	HandlerType: (*FloopServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ProcessCorrection",
+			Handler:    _FloopService_ProcessCorrection_Handler,
+		},
+		{
+			MethodName: "GetActive",
+			Handler:    _FloopService_GetActive_Handler,
+		},
+		{
+			MethodName: "Feedback",
+			Handler:    _FloopService_Feedback_Handler,
+		},
+		{
+			MethodName: "Search",
+			Handler:    _FloopService_Search_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchActive",
+			Handler:       _FloopService_WatchActive_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "floop/v1/floop.proto",
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestApplyChaosFlag(t *testing.T) {
+	t.Setenv(store.EnvChaos, "")
+
+	newCmd := func(spec string) *cobra.Command {
+		cmd := &cobra.Command{Use: "floop"}
+		cmd.Flags().String("chaos", "", "")
+		_ = cmd.Flags().Set("chaos", spec)
+		return cmd
+	}
+
+	if err := applyChaosFlag(newCmd("write=2"), nil); err == nil {
+		t.Error("expected error for invalid spec")
+	}
+	if os.Getenv(store.EnvChaos) != "" {
+		t.Error("invalid spec should not be exported")
+	}
+
+	if err := applyChaosFlag(newCmd("write=0.5,seed=1"), nil); err != nil {
+		t.Fatalf("applyChaosFlag: %v", err)
+	}
+	if got := os.Getenv(store.EnvChaos); got != "write=0.5,seed=1" {
+		t.Errorf("%s = %q", store.EnvChaos, got)
+	}
+}
+
+// TestLearnUnderChaos checks that a learn interrupted by store write faults
+// fails loudly and leaves a graph that still passes validation.
+func TestLearnUnderChaos(t *testing.T) {
+	tmpDir := setupDeinitTest(t)
+
+	t.Setenv(store.EnvChaos, "write=1")
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newLearnCmd())
+	rootCmd.SetArgs([]string{"learn", "--right", "use uv for python packages", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	err := rootCmd.Execute()
+	if !errors.Is(err, store.ErrInjectedFault) {
+		t.Fatalf("learn error = %v, want injected fault", err)
+	}
+
+	t.Setenv(store.EnvChaos, "")
+	gs, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore: %v", err)
+	}
+	defer gs.Close()
+
+	ctx := context.Background()
+	problems, err := gs.ValidateBehaviorGraph(ctx)
+	if err != nil {
+		t.Fatalf("ValidateBehaviorGraph: %v", err)
+	}
+	if len(problems) != 0 {
+		t.Errorf("graph invalid after chaos run: %v", problems)
+	}
+
+	nodes, err := gs.LocalStore().QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		t.Fatalf("QueryNodes: %v", err)
+	}
+	if len(nodes) != 0 {
+		t.Errorf("failed learn left %d behaviors in the local store", len(nodes))
+	}
+}
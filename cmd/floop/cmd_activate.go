@@ -7,10 +7,12 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/metrics"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/session"
 	"github.com/nvandessel/floop/internal/spreading"
@@ -52,6 +54,7 @@ Examples:
 
 // runActivate executes the activate command logic.
 func runActivate(cmd *cobra.Command, args []string) error {
+	start := time.Now()
 	root, _ := cmd.Flags().GetString("root")
 	file, _ := cmd.Flags().GetString("file")
 	task, _ := cmd.Flags().GetString("task")
@@ -72,9 +75,15 @@ func runActivate(cmd *cobra.Command, args []string) error {
 		return nil // silently exit if not initialized (hook context)
 	}
 
+	cfg, err := loadConfig(cmd)
+	if err != nil {
+		cfg = config.Default() // don't block the hook on a bad config
+	}
+
 	// Build context
 	ctxBuilder := activation.NewContextBuilder().
-		WithRepoRoot(root)
+		WithRepoRoot(root).
+		WithGitDetection(cfg.Activation.GitContext)
 	if file != "" {
 		ctxBuilder.WithFile(file)
 	}
@@ -106,13 +115,28 @@ func runActivate(cmd *cobra.Command, args []string) error {
 
 	sessState.IncrementPromptCount()
 
-	// Run spreading activation pipeline
+	// Run spreading activation pipeline within the activation time budget,
+	// so a slow store never blocks the agent's turn.
+	budget := activation.NewBudget(start, cfg.Activation.TimeBudget, cfg.Activation.ShedTopN, nil)
 	ctx := context.Background()
 	pipeline := spreading.NewPipeline(graphStore, spreading.DefaultConfig())
-	results, err := pipeline.Run(ctx, actCtx)
+	results, degraded, err := pipeline.RunWithin(ctx, actCtx, budget)
 	if err != nil {
 		return fmt.Errorf("spreading activation: %w", err)
 	}
+	var activated int
+	defer func() {
+		openMetrics(cfg, root).Record(metrics.Event{
+			Kind:       metrics.KindActivation,
+			DurationMS: metrics.Since(start),
+			Count:      activated,
+			Degraded:   degraded,
+		})
+	}()
+	if degraded {
+		fmt.Fprintf(os.Stderr, "Warning: activation exceeded its %v time budget (%v elapsed); spreading skipped\n",
+			cfg.Activation.TimeBudget, time.Since(start).Round(time.Millisecond))
+	}
 
 	if len(results) == 0 {
 		// Save state (prompt count) even if no results
@@ -147,6 +171,8 @@ func runActivate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	activated = len(budgeted)
+
 	// Record injections in session state
 	for _, fr := range budgeted {
 		cost := estimateTokenCost(fr.BehaviorID, fr.Tier)
@@ -163,7 +189,7 @@ func runActivate(cmd *cobra.Command, args []string) error {
 
 	// Output
 	if jsonOut || format == "json" {
-		return outputJSON(cmd, budgeted, behaviorMap, triggerReason)
+		return outputJSON(cmd, budgeted, behaviorMap, triggerReason, degraded)
 	}
 	return outputMarkdown(cmd, budgeted, behaviorMap, triggerReason)
 }
@@ -268,7 +294,8 @@ func buildTriggerReason(signals triggerSignals) string {
 }
 
 // outputJSON writes the activation results as JSON.
-func outputJSON(cmd *cobra.Command, results []session.FilteredResult, behaviorMap map[string]models.Behavior, triggerReason string) error {
+func outputJSON(cmd *cobra.Command, results []session.FilteredResult, behaviorMap map[string]models.Behavior, triggerReason string, degraded bool) error {
+	out := newOutput(cmd)
 	type jsonBehavior struct {
 		BehaviorID  string               `json:"behavior_id"`
 		Name        string               `json:"name"`
@@ -305,14 +332,18 @@ func outputJSON(cmd *cobra.Command, results []session.FilteredResult, behaviorMa
 		"count":     len(behaviors),
 		"scope":     "local",
 	}
+	if degraded {
+		output["degraded"] = true
+	}
 
-	enc := json.NewEncoder(cmd.OutOrStdout())
+	enc := json.NewEncoder(out)
 	enc.SetIndent("", "  ")
 	return enc.Encode(output)
 }
 
 // outputMarkdown writes the activation results as markdown.
 func outputMarkdown(cmd *cobra.Command, results []session.FilteredResult, behaviorMap map[string]models.Behavior, triggerReason string) error {
+	out := newOutput(cmd)
 	var sb strings.Builder
 
 	sb.WriteString("## Dynamic Context Update\n\n")
@@ -353,7 +384,7 @@ func outputMarkdown(cmd *cobra.Command, results []session.FilteredResult, behavi
 		return nil
 	}
 
-	fmt.Fprint(cmd.OutOrStdout(), output)
+	fmt.Fprint(out, output)
 	return nil
 }
 
@@ -2,15 +2,20 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"os"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/session"
+	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 )
 
@@ -271,7 +276,7 @@ func TestOutputJSON(t *testing.T) {
 	buf := new(bytes.Buffer)
 	cmd.SetOut(buf)
 
-	err := outputJSON(cmd, results, behaviorMap, "file change to `*.py`")
+	err := outputJSON(cmd, results, behaviorMap, "file change to `*.py`", false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -446,6 +451,359 @@ func TestActiveCmdJSON(t *testing.T) {
 	}
 }
 
+func TestActiveCmdRulesFormat(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newActiveCmd())
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetArgs([]string{"active", "--format", "claude-md", "--file", "main.go", "--task", "coding", "--root", tmpDir})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("active --format claude-md failed: %v", err)
+	}
+	if !strings.HasPrefix(buf.String(), "# Learned Behaviors") || !strings.Contains(buf.String(), "slog structured logging") {
+		t.Errorf("unexpected claude-md output:\n%s", buf.String())
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newActiveCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"active", "--format", "yaml", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("--format yaml should fail")
+	}
+}
+
+func TestActiveCmdContextCache(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	active := func(args ...string) (count int, cached bool) {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newActiveCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append([]string{"active", "--json", "--file", "main.go", "--task", "coding", "--root", tmpDir}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("active failed: %v", err)
+		}
+		var resp struct {
+			Count  int  `json:"count"`
+			Cached bool `json:"cached"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		return resp.Count, resp.Cached
+	}
+
+	count, cached := active()
+	if cached || count != 1 {
+		t.Fatalf("first call: count=%d cached=%v, want 1 uncached", count, cached)
+	}
+	if count, cached = active(); !cached || count != 1 {
+		t.Errorf("second call: count=%d cached=%v, want 1 from cache", count, cached)
+	}
+	if _, cached = active("--no-cache"); cached {
+		t.Error("--no-cache call was served from the cache")
+	}
+
+	// A store write invalidates the cached result
+	learnCmd := newTestRootCmd()
+	learnCmd.AddCommand(newLearnCmd())
+	learnCmd.SetOut(&bytes.Buffer{})
+	learnCmd.SetArgs([]string{"learn", "--wrong", "ignored errors", "--right", "always wrap errors with context",
+		"--file", "main.go", "--task", "coding", "--root", tmpDir})
+	if err := learnCmd.Execute(); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+	if count, cached = active(); cached || count != 2 {
+		t.Errorf("after learn: count=%d cached=%v, want 2 uncached", count, cached)
+	}
+}
+
+func TestActiveCmdNearMisses(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	b := models.Behavior{
+		ID:      "b-refactor",
+		Name:    "refactor-in-small-steps",
+		Kind:    models.BehaviorKindDirective,
+		When:    map[string]interface{}{"language": "go", "task": "refactor"},
+		Content: models.BehaviorContent{Canonical: "Refactor in small, tested steps"},
+	}
+	if _, err := s.AddNode(context.Background(), models.BehaviorToNode(&b)); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	s.Close()
+
+	runActive := func(args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newActiveCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append([]string{"active", "--near-misses", "--file", "main.go", "--task", "debugging", "--root", tmpDir}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("active --near-misses failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	text := runActive()
+	if !strings.Contains(text, "Near misses (1)") || !strings.Contains(text, "Failed: task=refactor") {
+		t.Fatalf("expected near miss on task, got:\n%s", text)
+	}
+
+	var result struct {
+		NearMisses []nearMissReport `json:"near_misses"`
+	}
+	if err := json.Unmarshal([]byte(runActive("--json")), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(result.NearMisses) != 1 {
+		t.Fatalf("near_misses = %+v, want 1", result.NearMisses)
+	}
+	if got := result.NearMisses[0].Stats.Count; got != 2 {
+		t.Errorf("recorded near misses = %d, want 2", got)
+	}
+}
+
+func TestActiveCmdSpread(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	ctx := context.Background()
+	for _, b := range []models.Behavior{
+		{
+			ID:      "b-seed",
+			Name:    "wrap-errors",
+			Kind:    models.BehaviorKindDirective,
+			When:    map[string]interface{}{"language": "go"},
+			Content: models.BehaviorContent{Canonical: "Wrap errors with context"},
+		},
+		{
+			ID:      "b-related",
+			Name:    "check-error-paths",
+			Kind:    models.BehaviorKindDirective,
+			When:    map[string]interface{}{"task": "review"},
+			Content: models.BehaviorContent{Canonical: "Test error paths"},
+		},
+	} {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&b)); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	if err := s.AddEdge(ctx, store.Edge{Source: "b-seed", Target: "b-related", Kind: store.EdgeKindSimilarTo, Weight: 1.0, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+	s.Close()
+
+	runActive := func(args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newActiveCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append([]string{"active", "--file", "main.go", "--task", "debugging", "--root", tmpDir}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("active failed: %v", err)
+		}
+		return buf.String()
+	}
+
+	if text := runActive(); strings.Contains(text, "check-error-paths") {
+		t.Errorf("related behavior should not activate without --spread:\n%s", text)
+	}
+
+	text := runActive("--spread")
+	if !strings.Contains(text, "check-error-paths") || !strings.Contains(text, "Related: spread from") {
+		t.Errorf("expected related behavior with --spread, got:\n%s", text)
+	}
+
+	var result struct {
+		Related []relatedBehavior `json:"related"`
+	}
+	if err := json.Unmarshal([]byte(runActive("--spread", "--json")), &result); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(result.Related) != 1 || result.Related[0].ID != "b-related" || result.Related[0].Distance != 1 {
+		t.Errorf("related = %+v, want b-related at distance 1", result.Related)
+	}
+}
+
+func TestActiveCmdNestedStores(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	addBehaviors := func(root string, behaviors ...models.Behavior) {
+		t.Helper()
+		s, err := store.NewSQLiteGraphStore(root)
+		if err != nil {
+			t.Fatalf("open store: %v", err)
+		}
+		defer s.Close()
+		for _, b := range behaviors {
+			if _, err := s.AddNode(context.Background(), models.BehaviorToNode(&b)); err != nil {
+				t.Fatalf("AddNode: %v", err)
+			}
+		}
+	}
+	addBehaviors(tmpDir, models.Behavior{
+		ID:      "b-root-pip",
+		Name:    "package-manager",
+		Kind:    models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "Always use pip for Python packages"},
+	})
+	serviceDir := filepath.Join(tmpDir, "services", "api")
+	addBehaviors(serviceDir, models.Behavior{
+		ID:      "b-api-uv",
+		Name:    "package-manager",
+		Kind:    models.BehaviorKindDirective,
+		When:    map[string]interface{}{"path_prefix": "handlers"},
+		Content: models.BehaviorContent{Canonical: "Always use uv for Python packages"},
+	})
+
+	runActive := func(file string) (active, overridden []string, nested []string) {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newActiveCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"active", "--file", file, "--json", "--root", tmpDir})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("active failed: %v", err)
+		}
+		var result struct {
+			Active       []models.Behavior         `json:"active"`
+			Overridden   []activation.OverrideInfo `json:"overridden"`
+			NestedStores []string                  `json:"nested_stores"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		for _, b := range result.Active {
+			active = append(active, b.ID)
+		}
+		for _, o := range result.Overridden {
+			overridden = append(overridden, o.Behavior.ID)
+		}
+		return active, overridden, result.NestedStores
+	}
+
+	active, overridden, nested := runActive("services/api/handlers/user.py")
+	if len(nested) != 1 || nested[0] != "services/api" {
+		t.Errorf("nested_stores = %v, want [services/api]", nested)
+	}
+	if !slices.Contains(active, "b-api-uv") || slices.Contains(active, "b-root-pip") {
+		t.Errorf("active = %v, want the service behavior instead of the root one", active)
+	}
+	if !slices.Contains(overridden, "b-root-pip") {
+		t.Errorf("overridden = %v, want b-root-pip", overridden)
+	}
+
+	// Outside the service, only the root store applies.
+	active, _, nested = runActive("scripts/build.py")
+	if len(nested) != 0 || !slices.Contains(active, "b-root-pip") || slices.Contains(active, "b-api-uv") {
+		t.Errorf("outside the service: active = %v, nested = %v", active, nested)
+	}
+}
+
+func TestActiveCmdShedsLoadOverBudget(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	floopHome := filepath.Join(os.Getenv("HOME"), ".floop")
+	if err := os.MkdirAll(floopHome, 0700); err != nil {
+		t.Fatal(err)
+	}
+	cfg := "activation:\n  time_budget: 1ns\n  shed_top_n: 1\n"
+	if err := os.WriteFile(filepath.Join(floopHome, "config.yaml"), []byte(cfg), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newActiveCmd())
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetArgs([]string{"active", "--json", "--file", "main.go", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("active --json failed: %v", err)
+	}
+
+	var result struct {
+		Degraded bool              `json:"degraded"`
+		Active   []json.RawMessage `json:"active"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if !result.Degraded {
+		t.Errorf("degraded = false, want true:\n%s", buf.String())
+	}
+	if len(result.Active) > 1 {
+		t.Errorf("active = %d behaviors, want at most shed_top_n=1", len(result.Active))
+	}
+}
+
+func TestActiveCmdJSONLStore(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	// Leave only the JSONL exports, as in a CI checkout of a committed .floop
+	homeDir, _ := os.UserHomeDir()
+	var dbs []string
+	for _, root := range []string{tmpDir, homeDir} {
+		for _, name := range []string{"floop.db", "floop.db-wal", "floop.db-shm"} {
+			os.Remove(filepath.Join(root, ".floop", name))
+		}
+		dbs = append(dbs, filepath.Join(root, ".floop", "floop.db"))
+	}
+
+	active := func(args ...string) (int, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newActiveCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append([]string{"active", "--json", "--no-cache", "--file", "main.go", "--task", "coding", "--root", tmpDir}, args...))
+		if err := rootCmd.Execute(); err != nil {
+			return 0, err
+		}
+		var resp struct {
+			Count int `json:"count"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		return resp.Count, nil
+	}
+
+	for _, backend := range []string{"jsonl", "auto"} {
+		count, err := active("--store", backend)
+		if err != nil {
+			t.Fatalf("active --store %s failed: %v", backend, err)
+		}
+		if count != 1 {
+			t.Errorf("active --store %s: count = %d, want 1", backend, count)
+		}
+	}
+	for _, db := range dbs {
+		if _, err := os.Stat(db); !os.IsNotExist(err) {
+			t.Errorf("%s was created by a JSONL read", db)
+		}
+	}
+
+	if _, err := active("--store", "postgres"); err == nil {
+		t.Error("--store postgres should fail")
+	}
+}
+
 func TestActiveCmdNotInitialized(t *testing.T) {
 	tmpDir := t.TempDir()
 	isolateHome(t, tmpDir)
@@ -456,8 +814,47 @@ func TestActiveCmdNotInitialized(t *testing.T) {
 	rootCmd.SetOut(&buf)
 	rootCmd.SetArgs([]string{"active", "--file", "main.go", "--root", tmpDir})
 
-	// Should not error — prints not initialized message
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
+}
+
+func TestActiveCmdFailEmpty(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newActiveCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"active", "--fail-empty", "--file", "main.go", "--task", "coding", "--root", tmpDir})
 	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("active not initialized failed: %v", err)
+		t.Fatalf("active --fail-empty with matches failed: %v", err)
+	}
+
+	// A project and home with no behaviors
+	emptyDir := t.TempDir()
+	isolateHome(t, emptyDir)
+	if err := os.MkdirAll(filepath.Join(emptyDir, ".floop"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newActiveCmd())
+	rootCmd.SilenceUsage = true
+	rootCmd.SilenceErrors = true
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetArgs([]string{"active", "--fail-empty", "--json", "--store", "jsonl", "--file", "main.go", "--root", emptyDir})
+	err := rootCmd.Execute()
+	wantErrorCode(t, err, codeEmpty)
+	if exitCode(err) != exitEmpty {
+		t.Errorf("exitCode = %d, want %d", exitCode(err), exitEmpty)
+	}
+
+	// The result is still written, and no error envelope follows it
+	var resp map[string]interface{}
+	if jerr := json.Unmarshal(buf.Bytes(), &resp); jerr != nil || resp["count"] != float64(0) {
+		t.Fatalf("expected an empty JSON result, got %q (%v)", buf.String(), jerr)
+	}
+	var envelope bytes.Buffer
+	reportError(rootCmd, &envelope, &envelope, err)
+	if envelope.Len() != 0 {
+		t.Errorf("silent error was reported: %q", envelope.String())
 	}
 }
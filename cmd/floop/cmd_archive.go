@@ -0,0 +1,293 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/archive"
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/pathutil"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newArchiveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Archive or restore the entire .floop state",
+		Long: `Bundle everything floop stores — graph database, corrections, config,
+tags, templates, and the vector index — into one portable tarball, and
+extract it on another machine.
+
+Unlike 'floop backup', which covers graph data only, an archive is a
+file-level copy of the .floop directories. Backups and session state are
+not included.
+
+Examples:
+  floop archive create                          # Archive local and global stores
+  floop archive create --scope global -o ~/.floop/backups/move.tar.gz
+  floop archive extract ~/.floop/backups/move.tar.gz
+  floop archive extract move.tar.gz --only graph,corrections --force`,
+	}
+
+	cmd.AddCommand(
+		newArchiveCreateCmd(),
+		newArchiveExtractCmd(),
+	)
+
+	return cmd
+}
+
+func newArchiveCreateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Write the .floop state to an archive",
+		Long: `Write the local and/or global .floop directories to a gzip-compressed
+tarball with a versioned manifest of every file and its checksum.
+
+Default location: ~/.floop/backups/floop-archive-YYYYMMDD-HHMMSS.tar.gz`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			outputPath, _ := cmd.Flags().GetString("output")
+			scope, _ := cmd.Flags().GetString("scope")
+
+			targets, err := archiveTargets(root, scope)
+			if err != nil {
+				return err
+			}
+
+			if outputPath == "" {
+				dir, err := backup.DefaultBackupDir()
+				if err != nil {
+					return fmt.Errorf("failed to get backup directory: %w", err)
+				}
+				outputPath = filepath.Join(dir, fmt.Sprintf("floop-archive-%s.tar.gz", time.Now().Format("20060102-150405")))
+			} else if err := validateArchivePath(root, outputPath); err != nil {
+				return fmt.Errorf("archive path rejected: %w", err)
+			}
+
+			var sources []archive.Source
+			for _, s := range []string{archive.ScopeLocal, archive.ScopeGlobal} {
+				if dir, ok := targets[s]; ok {
+					sources = append(sources, archive.Source{Scope: s, Dir: dir})
+				}
+			}
+
+			manifest, err := archive.Create(context.Background(), outputPath, sources, archive.CreateOptions{
+				FloopVersion: version,
+			})
+			if err != nil {
+				return fmt.Errorf("archive failed: %w", err)
+			}
+
+			counts := sectionCounts(manifest.Files)
+			if jsonOut {
+				info, _ := os.Stat(outputPath)
+				var sizeBytes int64
+				if info != nil {
+					sizeBytes = info.Size()
+				}
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"path":           outputPath,
+					"format_version": manifest.FormatVersion,
+					"scopes":         manifest.Scopes,
+					"file_count":     len(manifest.Files),
+					"sections":       counts,
+					"size_bytes":     sizeBytes,
+					"message":        fmt.Sprintf("Archive created: %d files", len(manifest.Files)),
+				})
+			}
+
+			fmt.Fprintf(out, "Archive created: %d files (%s)\n", len(manifest.Files), strings.Join(manifest.Scopes, ", "))
+			printSectionCounts(out, counts)
+			fmt.Fprintf(out, "  Path: %s\n", outputPath)
+			return nil
+		},
+	}
+
+	cmd.Flags().StringP("output", "o", "", "Output file path (default: auto-generated in ~/.floop/backups/)")
+	cmd.Flags().String("scope", "both", "Stores to archive: local, global, or both")
+
+	return cmd
+}
+
+func newArchiveExtractCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extract <file>",
+		Short: "Restore the .floop state from an archive",
+		Long: `Extract an archive created by 'floop archive create' into the local and/or
+global .floop directories. Every file is verified against the manifest
+checksum before it is written.
+
+Extraction refuses to overwrite existing files unless --force is given,
+which asks for confirmation first. Use --only to extract selected sections:
+  graph        floop.db, nodes.jsonl, edges.jsonl
+  corrections  corrections.jsonl, corrections-archive.jsonl.gz
+  config       config.yaml, manifest.yaml, .gitignore, packs.lock
+  vectors      vector index
+  other        everything else (tags, templates, packs, labels)
+
+Examples:
+  floop archive extract move.tar.gz --dry-run
+  floop archive extract move.tar.gz --scope global --only config
+  floop archive extract move.tar.gz --force --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			inputPath := args[0]
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			scope, _ := cmd.Flags().GetString("scope")
+			only, _ := cmd.Flags().GetStringSlice("only")
+			force, _ := cmd.Flags().GetBool("force")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			if err := validateArchivePath(root, inputPath); err != nil {
+				return fmt.Errorf("archive path rejected: %w", err)
+			}
+			targets, err := archiveTargets(root, scope)
+			if err != nil {
+				return err
+			}
+
+			opts := archive.ExtractOptions{
+				Targets:   targets,
+				Sections:  only,
+				Overwrite: force,
+				DryRun:    dryRun,
+			}
+
+			if force && !dryRun {
+				manifest, err := archive.ReadManifest(inputPath)
+				if err != nil {
+					return err
+				}
+				if _, existing := archive.Plan(manifest, opts); len(existing) > 0 {
+					yes, _ := cmd.Flags().GetBool("yes")
+					confirmed, err := confirmDestructive(config.OpArchiveOverwrite, yes, jsonOut, func() {
+						fmt.Fprintf(out, "Extracting %s will overwrite %d existing file(s):\n", inputPath, len(existing))
+						for _, p := range existing {
+							fmt.Fprintf(out, "  %s\n", p)
+						}
+					})
+					if err != nil {
+						return err
+					}
+					if !confirmed {
+						fmt.Fprintln(out, "Cancelled.")
+						return nil
+					}
+				}
+			}
+
+			result, err := archive.Extract(inputPath, opts)
+			var conflict *archive.ConflictError
+			if errors.As(err, &conflict) {
+				return fmt.Errorf("extract would overwrite %d existing file(s), e.g. %s (use --force to overwrite)", len(conflict.Paths), conflict.Paths[0])
+			}
+			if err != nil {
+				return fmt.Errorf("extract failed: %w", err)
+			}
+
+			counts := sectionCounts(result.Extracted)
+			if jsonOut {
+				verb := "Extracted"
+				if dryRun {
+					verb = "Would extract"
+				}
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"format_version": result.Manifest.FormatVersion,
+					"created_at":     result.Manifest.CreatedAt,
+					"file_count":     len(result.Extracted),
+					"sections":       counts,
+					"overwritten":    result.Overwritten,
+					"dry_run":        dryRun,
+					"message":        fmt.Sprintf("%s %d files", verb, len(result.Extracted)),
+				})
+			}
+
+			if dryRun {
+				fmt.Fprintf(out, "Would extract %d files from archive created %s:\n", len(result.Extracted), result.Manifest.CreatedAt.Local().Format("2006-01-02 15:04"))
+				for _, e := range result.Extracted {
+					fmt.Fprintf(out, "  %s/%s\n", e.Scope, e.Path)
+				}
+				if len(result.Overwritten) > 0 {
+					fmt.Fprintf(out, "%d existing file(s) would be overwritten.\n", len(result.Overwritten))
+				}
+				return nil
+			}
+			fmt.Fprintf(out, "Extracted %d files\n", len(result.Extracted))
+			printSectionCounts(out, counts)
+			if len(result.Overwritten) > 0 {
+				fmt.Fprintf(out, "  Overwritten: %d\n", len(result.Overwritten))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("scope", "both", "Stores to extract: local, global, or both")
+	cmd.Flags().StringSlice("only", nil, "Extract only these sections (graph, corrections, config, vectors, other)")
+	cmd.Flags().Bool("force", false, "Overwrite existing files")
+	cmd.Flags().Bool("dry-run", false, "Show what would be extracted without writing")
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// archiveTargets maps the scopes selected by --scope to their .floop
+// directories.
+func archiveTargets(root, scope string) (map[string]string, error) {
+	targets := make(map[string]string)
+	switch scope {
+	case "local", "global", "both":
+	default:
+		return nil, fmt.Errorf("invalid scope: %s (must be local, global, or both)", scope)
+	}
+	if scope != "global" {
+		targets[archive.ScopeLocal] = store.LocalFloopPath(root)
+	}
+	if scope != "local" {
+		globalDir, err := store.GlobalFloopPath()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get global path: %w", err)
+		}
+		targets[archive.ScopeGlobal] = globalDir
+	}
+	return targets, nil
+}
+
+// validateArchivePath restricts archive files to the backup directories.
+func validateArchivePath(root, path string) error {
+	allowedDirs, err := pathutil.DefaultAllowedBackupDirsWithProjectRoot(root)
+	if err != nil {
+		return fmt.Errorf("failed to determine allowed backup dirs: %w", err)
+	}
+	return pathutil.ValidatePath(path, allowedDirs)
+}
+
+// sectionCounts counts archive entries per section.
+func sectionCounts(files []archive.FileEntry) map[string]int {
+	counts := make(map[string]int)
+	for _, f := range files {
+		counts[f.Section]++
+	}
+	return counts
+}
+
+// printSectionCounts prints non-empty section counts in section order.
+func printSectionCounts(out *output, counts map[string]int) {
+	for _, s := range archive.Sections {
+		if n := counts[s]; n > 0 {
+			fmt.Fprintf(out, "  %-12s %d\n", s+":", n)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestArchiveCmdRoundTrip(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	outputPath := backupOutputPath(t, filepath.Join(tmpDir, "home"), "state.tar.gz")
+
+	out, err := runCmd(t, newArchiveCmd(), "create", "--scope", "local", "-o", outputPath, "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("archive create failed: %v", err)
+	}
+	var created map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &created); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if created["file_count"].(float64) == 0 {
+		t.Error("archive should contain files")
+	}
+
+	// Existing files block extraction without --force.
+	if _, err := runCmd(t, newArchiveCmd(), "extract", outputPath, "--scope", "local", "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Errorf("extract over existing state error = %v, want --force hint", err)
+	}
+
+	// Extract the corrections section into a fresh project.
+	freshRoot := filepath.Join(tmpDir, "fresh")
+	if err := os.MkdirAll(freshRoot, 0700); err != nil {
+		t.Fatal(err)
+	}
+	out, err = runCmd(t, newArchiveCmd(), "extract", outputPath, "--scope", "local", "--only", "corrections", "--root", freshRoot)
+	if err != nil {
+		t.Fatalf("archive extract failed: %v", err)
+	}
+	if !strings.Contains(out, "Extracted 1 files") {
+		t.Errorf("output = %q", out)
+	}
+	want, _ := os.ReadFile(filepath.Join(tmpDir, ".floop", "corrections.jsonl"))
+	got, err := os.ReadFile(filepath.Join(freshRoot, ".floop", "corrections.jsonl"))
+	if err != nil || !bytes.Equal(got, want) {
+		t.Errorf("extracted corrections differ: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(freshRoot, ".floop", "floop.db")); !os.IsNotExist(err) {
+		t.Error("--only corrections should not extract the graph")
+	}
+
+	// --force --yes overwrites.
+	if _, err := runCmd(t, newArchiveCmd(), "extract", outputPath, "--scope", "local", "--force", "--yes", "--root", tmpDir); err != nil {
+		t.Errorf("extract --force failed: %v", err)
+	}
+}
+
+func TestArchiveCmdRejectsInvalidInput(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runCmd(t, newArchiveCmd(), "create", "--scope", "bogus", "--root", tmpDir); err == nil {
+		t.Error("create --scope bogus should fail")
+	}
+	if _, err := runCmd(t, newArchiveCmd(), "create", "-o", filepath.Join(tmpDir, "elsewhere.tar.gz"), "--root", tmpDir); err == nil {
+		t.Error("create outside backup dirs should fail")
+	}
+}
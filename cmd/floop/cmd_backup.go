@@ -3,9 +3,12 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/nvandessel/floop/internal/backup"
 	"github.com/nvandessel/floop/internal/config"
@@ -13,6 +16,7 @@ import (
 	"github.com/nvandessel/floop/internal/pathutil"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func newBackupCmd() *cobra.Command {
@@ -24,31 +28,58 @@ func newBackupCmd() *cobra.Command {
 Default location: ~/.floop/backups/floop-backup-YYYYMMDD-HHMMSS.json.gz
 Keeps backups according to retention policy (default: last 10).
 
+--incremental writes a V3 differential backup holding only the nodes and
+edges changed since the newest backup in the same directory, which it names
+as its base. Restoring it replays the chain from the last full backup, so
+retention never deletes a base that a kept increment needs. Take a full
+backup now and then to start a new chain.
+
 Examples:
   floop backup                              # Backup to default location (V2 compressed)
   floop backup --output my-backup.json.gz   # Backup to specific file
   floop backup --no-compress                # Create V1 uncompressed backup
+  floop backup --incremental                # Record only changes since the last backup
+  floop backup --encrypt                    # Encrypt with a passphrase
   floop backup list                         # List all backups
-  floop backup verify <file>                # Verify backup integrity`,
+  floop backup verify <file>                # Verify backup integrity
+  floop backup auto status                  # Show the automatic backup schedule`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			outputPath, _ := cmd.Flags().GetString("output")
 			noCompress, _ := cmd.Flags().GetBool("no-compress")
+			incremental, _ := cmd.Flags().GetBool("incremental")
+			encrypt, _ := cmd.Flags().GetBool("encrypt")
 
-			cfg, err := config.Load()
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				cfg = config.Default()
 			}
 
+			if incremental && noCompress {
+				return fmt.Errorf("--incremental backups are always compressed; drop --no-compress")
+			}
+			if encrypt && noCompress {
+				return fmt.Errorf("--encrypt requires a compressed backup; drop --no-compress")
+			}
+			var passphrase string
+			if encrypt {
+				if passphrase, err = readBackupPassphrase(true); err != nil {
+					return err
+				}
+			}
 			compress := cfg.Backup.Compression && !noCompress
+			defaultPath := outputPath == ""
 
-			if outputPath == "" {
+			if defaultPath {
 				dir, err := backup.DefaultBackupDir()
 				if err != nil {
 					return fmt.Errorf("failed to get backup directory: %w", err)
 				}
-				if compress {
+				if incremental {
+					outputPath = backup.GenerateIncrementalPath(dir)
+				} else if compress {
 					outputPath = backup.GenerateBackupPath(dir)
 				} else {
 					outputPath = backup.GenerateBackupPathV1(dir)
@@ -70,9 +101,28 @@ Examples:
 			}
 			defer graphStore.Close()
 
+			if incremental {
+				basePath, err := latestBackup(filepath.Dir(outputPath))
+				if err != nil {
+					return err
+				}
+				if basePath != "" {
+					return runIncrementalBackup(ctx, cmd, graphStore, basePath, outputPath, passphrase, &cfg.Backup)
+				}
+				slog.Info("no backup to base an incremental backup on; writing a full backup", "dir", filepath.Dir(outputPath))
+				if defaultPath {
+					outputPath = backup.GenerateBackupPath(filepath.Dir(outputPath))
+				}
+				compress = true
+			}
+			if encrypt {
+				compress = true
+			}
+
 			result, err := backup.BackupWithOptions(ctx, graphStore, outputPath, backup.BackupOptions{
 				Compress:     compress,
 				FloopVersion: version,
+				Passphrase:   passphrase,
 			})
 			if err != nil {
 				return fmt.Errorf("backup failed: %w", err)
@@ -82,7 +132,7 @@ Examples:
 			policy := buildRetentionPolicy(&cfg.Backup)
 			dir := filepath.Dir(outputPath)
 			if _, err := backup.ApplyRetention(dir, policy); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to apply retention: %v\n", err)
+				slog.Warn("failed to apply retention", "error", err)
 			}
 
 			if jsonOut {
@@ -91,12 +141,13 @@ Examples:
 				if info != nil {
 					sizeBytes = info.Size()
 				}
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"path":       outputPath,
 					"node_count": len(result.Nodes),
 					"edge_count": len(result.Edges),
 					"version":    result.Version,
 					"compressed": compress,
+					"encrypted":  encrypt,
 					"size_bytes": sizeBytes,
 					"message":    fmt.Sprintf("Backup created: %d nodes, %d edges", len(result.Nodes), len(result.Edges)),
 				})
@@ -105,25 +156,132 @@ Examples:
 			versionLabel := "v2/gzip"
 			if !compress {
 				versionLabel = "v1/json"
+			} else if encrypt {
+				versionLabel = "v2/gzip+aes-256-gcm"
 			}
-			fmt.Printf("Backup created: %d nodes, %d edges (%s)\n", len(result.Nodes), len(result.Edges), versionLabel)
-			fmt.Printf("  Path: %s\n", outputPath)
+			fmt.Fprintf(out, "Backup created: %d nodes, %d edges (%s)\n", len(result.Nodes), len(result.Edges), versionLabel)
+			fmt.Fprintf(out, "  Path: %s\n", outputPath)
 			return nil
 		},
 	}
 
 	cmd.Flags().String("output", "", "Output file path (default: auto-generated in ~/.floop/backups/)")
 	cmd.Flags().Bool("no-compress", false, "Create V1 uncompressed backup instead of V2 compressed")
+	cmd.Flags().Bool("incremental", false, "Create a V3 backup of only the changes since the newest backup")
+	cmd.Flags().Bool("encrypt", false, "Encrypt the backup with a passphrase (from $"+backupPassphraseEnv+" or prompted)")
 
 	// Add subcommands
 	cmd.AddCommand(
 		newBackupListCmd(),
 		newBackupVerifyCmd(),
+		newBackupAutoCmd(),
 	)
 
 	return cmd
 }
 
+// latestBackup returns the newest backup in dir, or "" if there is none.
+func latestBackup(dir string) (string, error) {
+	backups, err := backup.ListBackups(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to list backups: %w", err)
+	}
+	if len(backups) == 0 {
+		return "", nil
+	}
+	return backups[0].Path, nil
+}
+
+// runIncrementalBackup writes a V3 backup of the changes since basePath,
+// encrypted with passphrase if given, and applies the retention policy.
+func runIncrementalBackup(ctx context.Context, cmd *cobra.Command, graphStore store.GraphStore, basePath, outputPath, passphrase string, cfg *config.BackupConfig) error {
+	out := newOutput(cmd)
+	jsonOut, _ := cmd.Flags().GetBool("json")
+
+	// Increments of an encrypted chain are encrypted too.
+	if baseHeader, err := backup.ReadHeader(basePath); err == nil && baseHeader.Encryption != nil && passphrase == "" {
+		if passphrase, err = readBackupPassphrase(false); err != nil {
+			return err
+		}
+	}
+
+	diff, header, err := backup.BackupIncremental(ctx, graphStore, basePath, outputPath, backup.BackupOptions{
+		FloopVersion: version,
+		Passphrase:   passphrase,
+	})
+	if err != nil {
+		return fmt.Errorf("backup failed: %w", err)
+	}
+
+	if _, err := backup.ApplyRetention(filepath.Dir(outputPath), buildRetentionPolicy(cfg)); err != nil {
+		slog.Warn("failed to apply retention", "error", err)
+	}
+
+	message := fmt.Sprintf("Incremental backup created: %d nodes and %d edges changed, %d nodes and %d edges deleted",
+		len(diff.Nodes), len(diff.Edges), len(diff.DeletedNodes), len(diff.DeletedEdges))
+	if jsonOut {
+		var sizeBytes int64
+		if info, err := os.Stat(outputPath); err == nil {
+			sizeBytes = info.Size()
+		}
+		return json.NewEncoder(out).Encode(map[string]interface{}{
+			"path":          outputPath,
+			"version":       backup.FormatV3,
+			"base":          basePath,
+			"revision":      header.Revision,
+			"node_count":    len(diff.Nodes),
+			"edge_count":    len(diff.Edges),
+			"deleted_nodes": len(diff.DeletedNodes),
+			"deleted_edges": len(diff.DeletedEdges),
+			"compressed":    true,
+			"encrypted":     passphrase != "",
+			"size_bytes":    sizeBytes,
+			"message":       message,
+		})
+	}
+
+	fmt.Fprintf(out, "%s (v3/incremental, revision %d)\n", message, header.Revision)
+	fmt.Fprintf(out, "  Path: %s\n", outputPath)
+	fmt.Fprintf(out, "  Base: %s\n", filepath.Base(basePath))
+	return nil
+}
+
+// backupPassphraseEnv supplies the passphrase for encrypted backups
+// without a prompt.
+const backupPassphraseEnv = "FLOOP_BACKUP_PASSPHRASE"
+
+// readBackupPassphrase returns the backup passphrase from the environment
+// or, on a terminal, a prompt; confirm asks for it twice.
+func readBackupPassphrase(confirm bool) (string, error) {
+	if p := os.Getenv(backupPassphraseEnv); p != "" {
+		return p, nil
+	}
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", fmt.Errorf("backup passphrase required: set %s or run in a terminal", backupPassphraseEnv)
+	}
+	fmt.Fprint(os.Stderr, "Backup passphrase: ")
+	p, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("failed to read passphrase: %w", err)
+	}
+	if len(p) == 0 {
+		return "", fmt.Errorf("backup passphrase must not be empty")
+	}
+	if confirm {
+		fmt.Fprint(os.Stderr, "Repeat passphrase: ")
+		again, err := term.ReadPassword(int(os.Stdin.Fd()))
+		fmt.Fprintln(os.Stderr)
+		if err != nil {
+			return "", fmt.Errorf("failed to read passphrase: %w", err)
+		}
+		if string(again) != string(p) {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return string(p), nil
+}
+
 // buildRetentionPolicy constructs a retention policy from config.
 func buildRetentionPolicy(cfg *config.BackupConfig) backup.RetentionPolicy {
 	var policies []backup.RetentionPolicy
@@ -159,22 +317,34 @@ func newRestoreFromBackupCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "restore-backup <file>",
 		Short: "Restore graph state from a backup file",
-		Long: `Restore behavior graph from a backup file (V1 or V2 format).
-Format is auto-detected.
+		Long: `Restore behavior graph from a backup file (V1, V2 or V3 format).
+Format is auto-detected. Encrypted backups need their passphrase, from
+$FLOOP_BACKUP_PASSPHRASE or a prompt.
 
 Modes:
   merge   - Skip existing nodes/edges (default)
-  replace - Clear store first, then restore
+  replace - Clear store first, then restore (asks for confirmation)
+
+Filters restore part of a backup: a behavior must match every filter given,
+and any value of a repeated one. Edges are restored when both ends are
+restored or already in the store. --dry-run lists each node and edge that
+would be added, updated or skipped, and writes nothing.
 
 Examples:
   floop restore-backup ~/.floop/backups/floop-backup-20260206-120000.json.gz
-  floop restore-backup backup.json --mode replace`,
+  floop restore-backup backup.json --mode replace
+  floop restore-backup backup.json --mode replace --yes
+  floop restore-backup backup.json --tag go --kind constraint --dry-run
+  floop restore-backup backup.json --id b-1 --id b-2
+  floop restore-backup backup.json --pack my-org/go-pack --created-after 2026-01-01`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			inputPath := args[0]
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			mode, _ := cmd.Flags().GetString("mode")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
 
 			allowedDirs, err := pathutil.DefaultAllowedBackupDirsWithProjectRoot(root)
 			if err != nil {
@@ -184,8 +354,27 @@ Examples:
 				return fmt.Errorf("restore path rejected: %w", err)
 			}
 
+			filter, err := restoreFilterFromFlags(cmd)
+			if err != nil {
+				return err
+			}
+
 			restoreMode := backup.RestoreMerge
-			if mode == "replace" {
+			if mode == "replace" && dryRun {
+				restoreMode = backup.RestoreReplace
+			} else if mode == "replace" {
+				yes, _ := cmd.Flags().GetBool("yes")
+				confirmed, err := confirmDestructive(config.OpRestoreReplace, yes, jsonOut, func() {
+					fmt.Fprintf(out, "Replace mode clears the store before restoring from %s.\n", inputPath)
+					fmt.Fprintln(out, "All behaviors and edges not in the backup will be lost.")
+				})
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(out, "Cancelled.")
+					return nil
+				}
 				restoreMode = backup.RestoreReplace
 			}
 
@@ -196,29 +385,94 @@ Examples:
 			}
 			defer graphStore.Close()
 
-			result, err := backup.Restore(ctx, graphStore, inputPath, restoreMode)
+			opts := backup.RestoreOptions{Mode: restoreMode, Filter: filter, DryRun: dryRun}
+			result, err := backup.RestoreWithOptions(ctx, graphStore, inputPath, opts)
+			if errors.Is(err, backup.ErrPassphraseRequired) {
+				if opts.Passphrase, err = readBackupPassphrase(false); err != nil {
+					return err
+				}
+				result, err = backup.RestoreWithOptions(ctx, graphStore, inputPath, opts)
+			}
 			if err != nil {
 				return fmt.Errorf("restore failed: %w", err)
 			}
 
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				resp := map[string]interface{}{
 					"nodes_restored": result.NodesRestored,
 					"nodes_skipped":  result.NodesSkipped,
 					"edges_restored": result.EdgesRestored,
 					"edges_skipped":  result.EdgesSkipped,
 					"message":        fmt.Sprintf("Restore complete: %d nodes, %d edges", result.NodesRestored, result.EdgesRestored),
-				})
+				}
+				if !filter.IsZero() {
+					resp["nodes_filtered"] = result.NodesFiltered
+					resp["edges_filtered"] = result.EdgesFiltered
+				}
+				if dryRun {
+					resp["dry_run"] = true
+					resp["changes"] = result.Changes
+					resp["message"] = fmt.Sprintf("Dry run: would restore %d nodes, %d edges", result.NodesRestored, result.EdgesRestored)
+				}
+				return json.NewEncoder(out).Encode(resp)
 			}
 
-			fmt.Printf("Restore complete (mode: %s)\n", mode)
-			fmt.Printf("  Nodes: %d restored, %d skipped\n", result.NodesRestored, result.NodesSkipped)
-			fmt.Printf("  Edges: %d restored, %d skipped\n", result.EdgesRestored, result.EdgesSkipped)
+			if dryRun {
+				fmt.Fprintf(out, "Dry run (mode: %s); nothing was written\n", mode)
+				for _, c := range result.Changes {
+					fmt.Fprintf(out, "  %-6s  %s  %s\n", c.Action, c.Type, c.ID)
+				}
+				fmt.Fprintf(out, "  Nodes: %d would be restored, %d skipped\n", result.NodesRestored, result.NodesSkipped)
+				fmt.Fprintf(out, "  Edges: %d would be restored\n", result.EdgesRestored)
+				if !filter.IsZero() {
+					fmt.Fprintf(out, "  Filtered out: %d nodes, %d edges\n", result.NodesFiltered, result.EdgesFiltered)
+				}
+				return nil
+			}
+
+			fmt.Fprintf(out, "Restore complete (mode: %s)\n", mode)
+			fmt.Fprintf(out, "  Nodes: %d restored, %d skipped\n", result.NodesRestored, result.NodesSkipped)
+			fmt.Fprintf(out, "  Edges: %d restored, %d skipped\n", result.EdgesRestored, result.EdgesSkipped)
+			if !filter.IsZero() {
+				fmt.Fprintf(out, "  Filtered out: %d nodes, %d edges\n", result.NodesFiltered, result.EdgesFiltered)
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().String("mode", "merge", "Restore mode: merge or replace")
+	cmd.Flags().StringSlice("id", nil, "Restore only these behavior IDs (repeatable)")
+	cmd.Flags().StringSlice("kind", nil, "Restore only behaviors of these kinds (repeatable)")
+	cmd.Flags().StringSlice("tag", nil, "Restore only behaviors with any of these tags (repeatable)")
+	cmd.Flags().StringSlice("pack", nil, "Restore only behaviors installed by these packs (repeatable)")
+	cmd.Flags().String("created-after", "", "Restore only behaviors created after a date (YYYY-MM-DD, RFC 3339, or an age like 30d)")
+	cmd.Flags().Bool("dry-run", false, "Show what would be added, updated or skipped without writing")
+	addYesFlag(cmd)
 
 	return cmd
 }
+
+// restoreFilterFromFlags builds a restore filter from restore-backup's
+// filter flags.
+func restoreFilterFromFlags(cmd *cobra.Command) (backup.RestoreFilter, error) {
+	var f backup.RestoreFilter
+	f.IDs, _ = cmd.Flags().GetStringSlice("id")
+	f.Kinds, _ = cmd.Flags().GetStringSlice("kind")
+	f.Tags, _ = cmd.Flags().GetStringSlice("tag")
+	f.Packs, _ = cmd.Flags().GetStringSlice("pack")
+
+	after, _ := cmd.Flags().GetString("created-after")
+	if after == "" {
+		return f, nil
+	}
+	if t, err := time.Parse(time.RFC3339, after); err == nil {
+		f.CreatedAfter = t
+	} else if t, err := time.ParseInLocation("2006-01-02", after, time.Local); err == nil {
+		f.CreatedAfter = t
+	} else if d, err := backup.ParseDuration(after); err == nil {
+		f.CreatedAfter = time.Now().Add(-d)
+	} else {
+		return f, fmt.Errorf("invalid --created-after %q: want YYYY-MM-DD, RFC 3339, or an age like 30d", after)
+	}
+	return f, nil
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newBackupAutoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "auto",
+		Short: "Inspect automatic backups",
+		Long: `Automatic backups are written to ~/.floop/backups/ by 'floop learn' and
+the MCP server when backup.auto_backup is enabled:
+
+  backup.schedule.every_learns  after this many learned corrections (default 1)
+  backup.schedule.daily         on the first learn or server start of each day (default false)
+
+Old backups are rotated by backup.retention.`,
+	}
+
+	cmd.AddCommand(newBackupAutoStatusCmd())
+
+	return cmd
+}
+
+func newBackupAutoStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the automatic backup schedule and last run",
+		Long: `Show whether automatic backups are enabled, their schedule, learns counted
+since the last automatic backup, the last backup and any failure, and
+whether a backup is due.
+
+Examples:
+  floop backup auto status
+  floop backup auto status --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			dir, err := backup.DefaultBackupDir()
+			if err != nil {
+				return fmt.Errorf("failed to get backup directory: %w", err)
+			}
+			state := backup.LoadAutoState(dir)
+			schedule := autoBackupSchedule(&cfg.Backup)
+			due, reason := schedule.Due(state, time.Now())
+			due = due && cfg.Backup.AutoBackup
+
+			if jsonOut {
+				resp := map[string]interface{}{
+					"enabled":      cfg.Backup.AutoBackup,
+					"every_learns": schedule.EveryLearns,
+					"daily":        schedule.Daily,
+					"learns_since": state.LearnsSince,
+					"due":          due,
+					"directory":    dir,
+				}
+				if due {
+					resp["due_reason"] = reason
+				}
+				if !state.LastBackup.IsZero() {
+					resp["last_backup"] = state.LastBackup.Format(time.RFC3339)
+					resp["last_path"] = state.LastPath
+				}
+				if state.LastError != "" {
+					resp["last_error"] = state.LastError
+					resp["last_error_at"] = state.LastErrorAt.Format(time.RFC3339)
+				}
+				return json.NewEncoder(out).Encode(resp)
+			}
+
+			if !cfg.Backup.AutoBackup {
+				fmt.Fprintln(out, "Automatic backups: disabled (backup.auto_backup)")
+			} else {
+				fmt.Fprintln(out, "Automatic backups: enabled")
+			}
+			fmt.Fprintf(out, "  Schedule:     %s\n", describeSchedule(schedule))
+			fmt.Fprintf(out, "  Learns since: %d\n", state.LearnsSince)
+			if state.LastBackup.IsZero() {
+				fmt.Fprintln(out, "  Last backup:  never")
+			} else {
+				fmt.Fprintf(out, "  Last backup:  %s\n", state.LastBackup.Format("2006-01-02 15:04"))
+				fmt.Fprintf(out, "  Path:         %s\n", state.LastPath)
+			}
+			if state.LastError != "" {
+				fmt.Fprintf(out, "  Last failure: %s: %s\n", state.LastErrorAt.Format("2006-01-02 15:04"), state.LastError)
+			}
+			if due {
+				fmt.Fprintf(out, "  Due:          yes (%s)\n", reason)
+			} else {
+				fmt.Fprintln(out, "  Due:          no")
+			}
+			return nil
+		},
+	}
+}
+
+// autoBackupSchedule returns the automatic backup schedule in cfg.
+func autoBackupSchedule(cfg *config.BackupConfig) backup.Schedule {
+	return backup.Schedule{
+		EveryLearns: cfg.Schedule.EveryLearns,
+		Daily:       cfg.Schedule.Daily,
+	}
+}
+
+// describeSchedule renders a schedule for humans.
+func describeSchedule(s backup.Schedule) string {
+	switch {
+	case s.EveryLearns > 0 && s.Daily:
+		return fmt.Sprintf("every %d learn(s) and daily", s.EveryLearns)
+	case s.EveryLearns > 0:
+		return fmt.Sprintf("every %d learn(s)", s.EveryLearns)
+	case s.Daily:
+		return "daily"
+	default:
+		return "none"
+	}
+}
+
+// recordAutoBackupLearn counts a learned correction toward the automatic
+// backup schedule, then backs up graphStore if the schedule says one is
+// due. Failures are warnings: they must not fail the learn.
+func recordAutoBackupLearn(ctx context.Context, graphStore store.GraphStore) {
+	cfg, err := config.Load()
+	if err != nil || !cfg.Backup.AutoBackup {
+		return
+	}
+	dir, err := backup.DefaultBackupDir()
+	if err == nil {
+		err = backup.RecordLearn(ctx, dir)
+	}
+	if err != nil {
+		slog.Warn("failed to count learn for automatic backup", "error", err)
+		return
+	}
+
+	opts := backup.AutoOptions{
+		Schedule:  autoBackupSchedule(&cfg.Backup),
+		Keep:      cfg.Backup.Retention.MaxCount,
+		Retention: buildRetentionPolicy(&cfg.Backup),
+		Backup: backup.BackupOptions{
+			Compress:     cfg.Backup.Compression,
+			FloopVersion: version,
+		},
+	}
+	path, err := backup.RunAuto(ctx, graphStore, dir, opts, time.Now())
+	if err != nil {
+		slog.Warn("automatic backup failed", "error", err)
+		return
+	}
+	if path != "" {
+		slog.Debug("automatic backup complete", "path", path)
+	}
+}
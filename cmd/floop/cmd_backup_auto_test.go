@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestRecordAutoBackupLearn(t *testing.T) {
+	// setupQueryTest learns one behavior with 'floop learn', which is due
+	// for a backup under the default every_learns of 1.
+	tmpDir, _ := setupQueryTest(t)
+	dir, err := backup.DefaultBackupDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	state := backup.LoadAutoState(dir)
+	if state.LastPath == "" || state.LearnsSince != 0 {
+		t.Fatalf("state after learn = %+v, want a backup and the counter reset", state)
+	}
+	if _, err := os.Stat(state.LastPath); err != nil {
+		t.Errorf("automatic backup not written: %v", err)
+	}
+
+	cfgPath, err := config.DefaultPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(cfgPath), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cfgPath, []byte("backup:\n  schedule:\n    every_learns: 3\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer graphStore.Close()
+
+	ctx := context.Background()
+	recordAutoBackupLearn(ctx, graphStore)
+	recordAutoBackupLearn(ctx, graphStore)
+	if got := backup.LoadAutoState(dir); got.LearnsSince != 2 || got.LastPath != state.LastPath {
+		t.Errorf("state after 2 of 3 learns = %+v, want 2 learns counted and no new backup", got)
+	}
+	recordAutoBackupLearn(ctx, graphStore)
+	if got := backup.LoadAutoState(dir); got.LearnsSince != 0 || got.LastBackup.Equal(state.LastBackup) {
+		t.Errorf("state after 3 learns = %+v, want a new backup", got)
+	}
+}
+
+func TestBackupAutoStatus(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"backup", "auto", "status", "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("backup auto status failed: %v", err)
+	}
+
+	var resp map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out.String(), err)
+	}
+	if resp["enabled"] != true || resp["learns_since"] != float64(0) || resp["due"] != false || resp["last_path"] == nil {
+		t.Errorf("status = %v, want enabled, a backup from the learn, not due", resp)
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	out.Reset()
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"backup", "auto", "status", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("backup auto status failed: %v", err)
+	}
+	for _, want := range []string{"enabled", "every 1 learn(s)", "Path:", "Due:          no"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+}
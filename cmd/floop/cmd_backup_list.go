@@ -3,7 +3,6 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 	"path/filepath"
 
 	"github.com/nvandessel/floop/internal/backup"
@@ -15,12 +14,14 @@ func newBackupListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List all backups with metadata",
 		Long: `List all backup files in the default backup directory with version,
-format, size, and node/edge counts.
+format, size, and node/edge counts. For incremental (v3) backups the
+counts are of changed nodes and edges.
 
 Examples:
   floop backup list
   floop backup list --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			jsonOut, _ := cmd.Flags().GetBool("json")
 
 			dir, err := backup.DefaultBackupDir()
@@ -43,6 +44,9 @@ Examples:
 					NodeCount     int               `json:"node_count,omitempty"`
 					EdgeCount     int               `json:"edge_count,omitempty"`
 					Checksum      string            `json:"checksum,omitempty"`
+					Base          string            `json:"base,omitempty"`
+					Revision      int               `json:"revision,omitempty"`
+					Encrypted     bool              `json:"encrypted,omitempty"`
 					Metadata      map[string]string `json:"metadata,omitempty"`
 				}
 				entries := make([]jsonEntry, 0, len(backups))
@@ -53,18 +57,21 @@ Examples:
 						Size:      b.Size,
 						CreatedAt: b.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 					}
-					if b.Version == backup.FormatV2 {
-						if header, err := backup.ReadV2Header(b.Path); err == nil {
+					if b.Version >= backup.FormatV2 {
+						if header, err := backup.ReadHeader(b.Path); err == nil {
 							entry.NodeCount = header.NodeCount
 							entry.EdgeCount = header.EdgeCount
 							entry.Checksum = header.Checksum
 							entry.SchemaVersion = header.SchemaVersion
 							entry.Metadata = header.Metadata
+							entry.Base = header.Base
+							entry.Revision = header.Revision
+							entry.Encrypted = header.Encryption != nil
 						}
 					}
 					entries = append(entries, entry)
 				}
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"backups":     entries,
 					"total_count": len(entries),
 					"directory":   dir,
@@ -72,11 +79,11 @@ Examples:
 			}
 
 			if len(backups) == 0 {
-				fmt.Printf("No backups found in %s\n", dir)
+				fmt.Fprintf(out, "No backups found in %s\n", dir)
 				return nil
 			}
 
-			fmt.Printf("Backups in %s:\n", dir)
+			fmt.Fprintf(out, "Backups in %s:\n", dir)
 			var totalSize int64
 			for _, b := range backups {
 				totalSize += b.Size
@@ -87,13 +94,19 @@ Examples:
 				edgeCount := 0
 				schemaVersion := 0
 
-				if b.Version == backup.FormatV2 {
-					versionStr = "v2"
+				if b.Version >= backup.FormatV2 {
+					versionStr = fmt.Sprintf("v%d", b.Version)
 					formatStr = "gzip"
-					if header, err := backup.ReadV2Header(b.Path); err == nil {
+					if b.Version == backup.FormatV3 {
+						formatStr = "incr"
+					}
+					if header, err := backup.ReadHeader(b.Path); err == nil {
 						nodeCount = header.NodeCount
 						edgeCount = header.EdgeCount
 						schemaVersion = header.SchemaVersion
+						if header.Encryption != nil {
+							formatStr += "+aes"
+						}
 					}
 				}
 
@@ -102,7 +115,7 @@ Examples:
 					schemaStr = fmt.Sprintf("  schema:v%d", schemaVersion)
 				}
 
-				fmt.Printf("  %s  %s  %s  %s  %d nodes  %d edges%s  %s\n",
+				fmt.Fprintf(out, "  %s  %s  %s  %s  %d nodes  %d edges%s  %s\n",
 					b.CreatedAt.Format("2006-01-02 15:04"),
 					versionStr,
 					formatStr,
@@ -113,7 +126,7 @@ Examples:
 					filepath.Base(b.Path),
 				)
 			}
-			fmt.Printf("Total: %d backups, %s\n", len(backups), formatBytes(totalSize))
+			fmt.Fprintf(out, "Total: %d backups, %s\n", len(backups), formatBytes(totalSize))
 			return nil
 		},
 	}
@@ -2,8 +2,10 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -161,3 +163,143 @@ func TestRestoreFromBackupCmdNotFound(t *testing.T) {
 		t.Error("expected error for nonexistent backup file")
 	}
 }
+
+func TestBackupCmdIncremental(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	fullPath := backupOutputPath(t, tmpDir, "floop-backup-20260101-000000.json.gz")
+	incrPath := backupOutputPath(t, tmpDir, "floop-backup-20260101-000100-incr.json.gz")
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"backup", "--output", fullPath, "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"backup", "--incremental", "--output", incrPath, "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("backup --incremental failed: %v", err)
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, out.String())
+	}
+	if resp["version"] != float64(3) || resp["revision"] != float64(1) || resp["base"] != fullPath {
+		t.Errorf("response = %v, want version 3, revision 1, base %s", resp, fullPath)
+	}
+	if resp["node_count"] != float64(0) {
+		t.Errorf("node_count = %v, want 0 for an unchanged store", resp["node_count"])
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"backup", "verify", incrPath})
+	if err := rootCmd.Execute(); err != nil {
+		t.Errorf("backup verify of incremental backup failed: %v", err)
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newRestoreFromBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"restore-backup", incrPath, "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Errorf("restore-backup of incremental backup failed: %v", err)
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"backup", "--incremental", "--no-compress", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "--no-compress") {
+		t.Errorf("backup --incremental --no-compress error = %v, want rejection", err)
+	}
+}
+
+func TestBackupCmdEncrypted(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	outputPath := backupOutputPath(t, tmpDir, "encrypted.json.gz")
+	t.Setenv(backupPassphraseEnv, "correct horse")
+
+	var out bytes.Buffer
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"backup", "--encrypt", "--output", outputPath, "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("backup --encrypt failed: %v", err)
+	}
+	var resp map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, out.String())
+	}
+	if resp["encrypted"] != true {
+		t.Errorf("encrypted = %v, want true", resp["encrypted"])
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newRestoreFromBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"restore-backup", outputPath, "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Errorf("restore-backup with passphrase failed: %v", err)
+	}
+
+	t.Setenv(backupPassphraseEnv, "wrong")
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newRestoreFromBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"restore-backup", outputPath, "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("restore-backup with wrong passphrase error = %v, want wrong passphrase", err)
+	}
+}
+
+func TestRestoreFromBackupCmdDryRunFiltered(t *testing.T) {
+	tmpDir, slogID := setupQueryTest(t)
+	outputPath := backupOutputPath(t, tmpDir, "test-backup.json.gz")
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"backup", "--output", outputPath, "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newRestoreFromBackupCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"restore-backup", outputPath, "--id", slogID, "--dry-run", "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("restore-backup --dry-run failed: %v", err)
+	}
+	var resp struct {
+		DryRun  bool `json:"dry_run"`
+		Changes []struct {
+			Type   string `json:"type"`
+			ID     string `json:"id"`
+			Action string `json:"action"`
+		} `json:"changes"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, out.String())
+	}
+	if !resp.DryRun || len(resp.Changes) != 1 || resp.Changes[0].ID != slogID || resp.Changes[0].Action != "skip" {
+		t.Errorf("dry run = %+v, want only %s, skipped", resp, slogID)
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newRestoreFromBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"restore-backup", outputPath, "--created-after", "yesterday", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "--created-after") {
+		t.Errorf("restore-backup --created-after yesterday error = %v, want invalid flag", err)
+	}
+}
@@ -3,7 +3,6 @@ package main
 import (
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/nvandessel/floop/internal/backup"
 	"github.com/spf13/cobra"
@@ -14,19 +13,22 @@ func newBackupVerifyCmd() *cobra.Command {
 		Use:   "verify <file>",
 		Short: "Verify backup file integrity",
 		Long: `Verify the integrity of a backup file by checking its SHA-256 checksum.
-Only applicable to V2 (compressed) backup files.
+Only applicable to V2 and V3 (compressed) backup files. For a V3
+incremental backup, also checks that the chain of bases it was taken
+against is present and unchanged.
 
 Examples:
   floop backup verify ~/.floop/backups/floop-backup-20260206-120000.json.gz`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			filePath := args[0]
 			jsonOut, _ := cmd.Flags().GetBool("json")
 
 			version, err := backup.DetectFormat(filePath)
 			if err != nil {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"file":    filePath,
 						"valid":   false,
 						"error":   err.Error(),
@@ -39,32 +41,35 @@ Examples:
 
 			if version == backup.FormatV1 {
 				if jsonOut {
-					return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					return json.NewEncoder(out).Encode(map[string]interface{}{
 						"file":    filePath,
 						"version": 1,
 						"valid":   true,
 						"message": "V1 format: no checksum to verify (integrity check N/A)",
 					})
 				}
-				fmt.Printf("V1 format: no checksum to verify (integrity check N/A)\n")
-				fmt.Printf("  File: %s\n", filePath)
+				fmt.Fprintf(out, "V1 format: no checksum to verify (integrity check N/A)\n")
+				fmt.Fprintf(out, "  File: %s\n", filePath)
 				return nil
 			}
 
 			// Read header for schema version info
 			var schemaVersion int
 			var metadata map[string]string
-			if header, err := backup.ReadV2Header(filePath); err == nil {
+			if header, err := backup.ReadHeader(filePath); err == nil {
 				schemaVersion = header.SchemaVersion
 				metadata = header.Metadata
 			}
 
 			err = backup.VerifyChecksum(filePath)
+			if err == nil && version == backup.FormatV3 {
+				err = backup.VerifyChain(filePath)
+			}
 			if err != nil {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"file":           filePath,
-						"version":        2,
+						"version":        version,
 						"schema_version": schemaVersion,
 						"valid":          false,
 						"error":          err.Error(),
@@ -72,15 +77,15 @@ Examples:
 					})
 					return fmt.Errorf("checksum verification failed")
 				}
-				fmt.Printf("FAILED: %v\n", err)
-				fmt.Printf("  File: %s\n", filePath)
+				fmt.Fprintf(out, "FAILED: %v\n", err)
+				fmt.Fprintf(out, "  File: %s\n", filePath)
 				return fmt.Errorf("checksum verification failed")
 			}
 
 			if jsonOut {
 				result := map[string]interface{}{
 					"file":           filePath,
-					"version":        2,
+					"version":        version,
 					"schema_version": schemaVersion,
 					"valid":          true,
 					"message":        "Checksum OK",
@@ -88,13 +93,13 @@ Examples:
 				if len(metadata) > 0 {
 					result["metadata"] = metadata
 				}
-				return json.NewEncoder(os.Stdout).Encode(result)
+				return json.NewEncoder(out).Encode(result)
 			}
 
-			fmt.Printf("OK: checksum verified\n")
-			fmt.Printf("  File: %s\n", filePath)
+			fmt.Fprintf(out, "OK: checksum verified\n")
+			fmt.Fprintf(out, "  File: %s\n", filePath)
 			if schemaVersion > 0 {
-				fmt.Printf("  Schema version: %d\n", schemaVersion)
+				fmt.Fprintf(out, "  Schema version: %d\n", schemaVersion)
 			}
 			return nil
 		},
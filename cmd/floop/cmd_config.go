@@ -3,11 +3,18 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/tracing"
+	"github.com/nvandessel/floop/internal/utils"
 	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 )
@@ -18,13 +25,26 @@ func newConfigCmd() *cobra.Command {
 		Short: "Manage floop configuration",
 		Long: `View and modify floop configuration settings.
 
-Configuration is stored in ~/.floop/config.yaml.
+Global configuration is stored in ~/.floop/config.yaml. A project can
+override settings in its own .floop/config.yaml, which holds only the
+settings it changes. Only ranking, token_budget, activation,
+deduplication, profile, profiles, corrections, and review settings other
+than review.webhook_url can be set per project; the rest, which includes
+secrets, LLM spending, endpoints, and safety guards, is global only.
+
+list and get show the effective configuration: defaults, then the global
+file, then the project file, then environment variables. --global or
+--local shows a single file over the defaults instead. set writes the
+global file unless --local is given, and rejects values that fail
+validation.
 
 Examples:
   floop config list                            # Show all settings
   floop config get llm.provider                # Get a specific setting
   floop config set llm.provider anthropic      # Set a setting
-  floop config set llm.api_key $ANTHROPIC_API_KEY`,
+  floop config set llm.api_key $ANTHROPIC_API_KEY
+  floop config set --local ranking.context_weight 0.4
+  floop config get --global ranking.context_weight`,
 	}
 
 	cmd.AddCommand(
@@ -37,13 +57,16 @@ Examples:
 }
 
 func newConfigListCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "list",
 		Short: "List all configuration settings",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			root, _ := cmd.Flags().GetString("root")
+			scope := configScope(cmd)
 
-			cfg, err := config.Load()
+			cfg, err := loadScopedConfig(root, scope)
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
@@ -52,121 +75,276 @@ func newConfigListCmd() *cobra.Command {
 				// Redact API key before JSON serialization to prevent leakage
 				redacted := *cfg
 				redacted.LLM.APIKey = cfg.LLM.RedactedAPIKey()
-				json.NewEncoder(os.Stdout).Encode(redacted)
+				redacted.Packs.GitHubToken = cfg.Packs.RedactedGitHubToken()
+				redacted.Packs.Registries = make([]config.Registry, len(cfg.Packs.Registries))
+				for i, r := range cfg.Packs.Registries {
+					r.Token = r.RedactedToken()
+					redacted.Packs.Registries[i] = r
+				}
+				json.NewEncoder(out).Encode(redacted)
 			} else {
-				fmt.Println("Configuration (~/.floop/config.yaml):")
-				fmt.Println()
-				fmt.Println("LLM Settings:")
-				fmt.Printf("  llm.provider:          %s\n", valueOrDefault(cfg.LLM.Provider, "(not set)"))
-				fmt.Printf("  llm.enabled:           %v\n", cfg.LLM.Enabled)
+				switch scope {
+				case configScopeGlobal:
+					fmt.Fprintln(out, "Configuration (~/.floop/config.yaml):")
+				case configScopeLocal:
+					fmt.Fprintf(out, "Configuration (%s):\n", config.ProjectPath(root))
+				default:
+					fmt.Fprintln(out, "Configuration (global, project, and environment):")
+				}
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "LLM Settings:")
+				fmt.Fprintf(out, "  llm.provider:          %s\n", valueOrDefault(cfg.LLM.Provider, "(not set)"))
+				fmt.Fprintf(out, "  llm.enabled:           %v\n", cfg.LLM.Enabled)
 				redacted := cfg.LLM.RedactedAPIKey()
 				if redacted != "" {
-					fmt.Printf("  llm.api_key:           %s\n", redacted)
+					fmt.Fprintf(out, "  llm.api_key:           %s\n", redacted)
 				} else {
-					fmt.Printf("  llm.api_key:           (not set)\n")
+					fmt.Fprintf(out, "  llm.api_key:           (not set)\n")
 				}
-				fmt.Printf("  llm.base_url:          %s\n", valueOrDefault(cfg.LLM.BaseURL, "(default)"))
-				fmt.Printf("  llm.comparison_model:  %s\n", valueOrDefault(cfg.LLM.ComparisonModel, "(default)"))
-				fmt.Printf("  llm.merge_model:       %s\n", valueOrDefault(cfg.LLM.MergeModel, "(default)"))
-				fmt.Printf("  llm.timeout:           %v\n", cfg.LLM.Timeout)
-				fmt.Printf("  llm.fallback_to_rules: %v\n", cfg.LLM.FallbackToRules)
-				fmt.Println()
-				fmt.Println("Deduplication Settings:")
-				fmt.Printf("  deduplication.auto_merge:            %v\n", cfg.Deduplication.AutoMerge)
-				fmt.Printf("  deduplication.similarity_threshold:  %.2f\n", cfg.Deduplication.SimilarityThreshold)
+				fmt.Fprintf(out, "  llm.base_url:          %s\n", valueOrDefault(cfg.LLM.BaseURL, "(default)"))
+				fmt.Fprintf(out, "  llm.comparison_model:  %s\n", valueOrDefault(cfg.LLM.ComparisonModel, "(default)"))
+				fmt.Fprintf(out, "  llm.merge_model:       %s\n", valueOrDefault(cfg.LLM.MergeModel, "(default)"))
+				fmt.Fprintf(out, "  llm.timeout:           %v\n", cfg.LLM.Timeout)
+				fmt.Fprintf(out, "  llm.max_retries:       %d\n", cfg.LLM.MaxRetries)
+				fmt.Fprintf(out, "  llm.daily_budget_usd:  %v\n", cfg.LLM.DailyBudgetUSD)
+				fmt.Fprintf(out, "  llm.daily_call_limit:  %d\n", cfg.LLM.DailyCallLimit)
+				fmt.Fprintf(out, "  llm.fallback_to_rules: %v\n", cfg.LLM.FallbackToRules)
+				fmt.Fprintf(out, "  llm.extract_behaviors: %v\n", cfg.LLM.ExtractBehaviors)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Deduplication Settings:")
+				fmt.Fprintf(out, "  deduplication.auto_merge:            %v\n", cfg.Deduplication.AutoMerge)
+				fmt.Fprintf(out, "  deduplication.similarity_threshold:  %.2f\n", cfg.Deduplication.SimilarityThreshold)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Safety Settings:")
+				fmt.Fprintf(out, "  safety.protected_operations:  %s\n", valueOrDefault(strings.Join(cfg.Safety.ProtectedOperations, ","), "(none)"))
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Review Settings:")
+				fmt.Fprintf(out, "  review.sla:              %s\n", valueOrDefault(cfg.Review.SLA, "(disabled)"))
+				fmt.Fprintf(out, "  review.escalate_after:   %s\n", valueOrDefault(cfg.Review.EscalateAfter, "(disabled)"))
+				fmt.Fprintf(out, "  review.escalate_action:  %s\n", valueOrDefault(cfg.Review.EscalateAction, "(none)"))
+				fmt.Fprintf(out, "  review.webhook_url:      %s\n", valueOrDefault(cfg.Review.WebhookURL, "(not set)"))
+				fmt.Fprintf(out, "  review.hold_pending:     %v\n", cfg.Review.HoldPending)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Activation Settings:")
+				fmt.Fprintf(out, "  activation.near_miss_max_failing:    %d\n", cfg.Activation.NearMissMaxFailing)
+				fmt.Fprintf(out, "  activation.near_miss_suggest_after:  %d\n", cfg.Activation.NearMissSuggestAfter)
+				fmt.Fprintf(out, "  activation.time_budget:              %v\n", cfg.Activation.TimeBudget)
+				fmt.Fprintf(out, "  activation.shed_top_n:               %d\n", cfg.Activation.ShedTopN)
+				fmt.Fprintf(out, "  activation.graph_weight:             %v\n", cfg.Activation.GraphWeight)
+				fmt.Fprintf(out, "  activation.git_context:              %v\n", cfg.Activation.GitContext)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Ranking Settings:")
+				fmt.Fprintf(out, "  ranking.context_weight:     %v\n", cfg.Ranking.ContextWeight)
+				fmt.Fprintf(out, "  ranking.base_level_weight:  %v\n", cfg.Ranking.BaseLevelWeight)
+				fmt.Fprintf(out, "  ranking.feedback_weight:    %v\n", cfg.Ranking.FeedbackWeight)
+				fmt.Fprintf(out, "  ranking.priority_weight:    %v\n", cfg.Ranking.PriorityWeight)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Maintenance Settings:")
+				fmt.Fprintf(out, "  maintenance.gc_interval:      %s\n", valueOrDefault(cfg.Maintenance.GCInterval, "(disabled)"))
+				fmt.Fprintf(out, "  maintenance.decay_interval:   %s\n", valueOrDefault(cfg.Maintenance.DecayInterval, "(disabled)"))
+				fmt.Fprintf(out, "  maintenance.decay_window:     %s\n", cfg.Maintenance.DecayWindow)
+				fmt.Fprintf(out, "  maintenance.decay_half_life:  %s\n", cfg.Maintenance.DecayHalfLife)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Metrics Settings:")
+				fmt.Fprintf(out, "  metrics.enabled:  %v\n", cfg.Metrics.Enabled)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Tracing Settings:")
+				fmt.Fprintf(out, "  tracing.enabled:       %v\n", cfg.Tracing.Enabled)
+				fmt.Fprintf(out, "  tracing.endpoint:      %s\n", valueOrDefault(cfg.Tracing.Endpoint, "(OTEL_EXPORTER_OTLP_ENDPOINT)"))
+				fmt.Fprintf(out, "  tracing.sample_ratio:  %v\n", cfg.Tracing.SampleRatio)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Seed Settings:")
+				fmt.Fprintf(out, "  seeds.experimental:  %v\n", cfg.Seeds.Experimental)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Embedding Settings:")
+				fmt.Fprintf(out, "  embedding.backend:     %s\n", valueOrDefault(cfg.Embedding.Backend, "(auto)"))
+				fmt.Fprintf(out, "  embedding.model_path:  %s\n", valueOrDefault(cfg.Embedding.ModelPath, "(not set)"))
+				fmt.Fprintf(out, "  embedding.url:         %s\n", valueOrDefault(cfg.Embedding.URL, "(not set)"))
+				fmt.Fprintf(out, "  embedding.model:       %s\n", valueOrDefault(cfg.Embedding.Model, "(not set)"))
+				fmt.Fprintf(out, "  embedding.dimension:   %d\n", cfg.Embedding.Dimension)
+				fmt.Fprintf(out, "  embedding.batch_size:  %d\n", cfg.Embedding.BatchSize)
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Corrections Settings:")
+				fmt.Fprintf(out, "  corrections.max_age:           %s\n", valueOrDefault(cfg.Corrections.MaxAge, "(no limit)"))
+				fmt.Fprintf(out, "  corrections.max_count:         %d\n", cfg.Corrections.MaxCount)
+				fmt.Fprintf(out, "  corrections.processed_only:    %v\n", cfg.Corrections.ProcessedOnly)
+				fmt.Fprintf(out, "  corrections.compact_interval:  %s\n", valueOrDefault(cfg.Corrections.CompactInterval, "(disabled)"))
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Pack Settings:")
+				fmt.Fprintf(out, "  packs.signature_policy:  %s\n", cfg.Packs.EffectiveSignaturePolicy())
+				fmt.Fprintf(out, "  packs.collision_policy:  %s\n", cfg.Packs.EffectiveCollisionPolicy())
+				fmt.Fprintf(out, "  packs.trusted_keys:      %d\n", len(cfg.Packs.TrustedKeys))
+				fmt.Fprintf(out, "  packs.github_token:      %s\n", valueOrDefault(cfg.Packs.RedactedGitHubToken(), "(not set)"))
+				fmt.Fprintf(out, "  packs.cache_max_age:     %s\n", valueOrDefault(cfg.Packs.CacheMaxAge, "(no limit)"))
+				fmt.Fprintln(out)
+				fmt.Fprintln(out, "Agent Profiles:")
+				fmt.Fprintf(out, "  profile:   %s\n", valueOrDefault(cfg.Profile, "(none)"))
+				fmt.Fprintf(out, "  profiles:  %s\n", strings.Join(slices.Sorted(maps.Keys(cfg.Profiles)), ", "))
 			}
 
 			return nil
 		},
 	}
+	addConfigScopeFlags(cmd, "Show")
+	return cmd
 }
 
 func newConfigGetCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "get <key>",
 		Short: "Get a configuration value",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			root, _ := cmd.Flags().GetString("root")
 			key := args[0]
 
-			cfg, err := config.Load()
+			cfg, err := loadScopedConfig(root, configScope(cmd))
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 
 			value, found := getConfigValue(cfg, key)
 			if !found {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": "key not found",
-						"key":   key,
-					})
-				} else {
-					fmt.Printf("Unknown configuration key: %s\n", key)
-				}
-				return nil
+				return newCLIError(codeNotFound, map[string]interface{}{"key": key}, "unknown configuration key: %s", key)
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"key":   key,
 					"value": value,
 				})
 			} else {
-				fmt.Printf("%s = %v\n", key, value)
+				fmt.Fprintf(out, "%s = %v\n", key, value)
 			}
 
 			return nil
 		},
 	}
+	addConfigScopeFlags(cmd, "Get")
+	return cmd
 }
 
 func newConfigSetCmd() *cobra.Command {
-	return &cobra.Command{
+	cmd := &cobra.Command{
 		Use:   "set <key> <value>",
 		Short: "Set a configuration value",
 		Args:  cobra.ExactArgs(2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			root, _ := cmd.Flags().GetString("root")
 			key := args[0]
 			value := args[1]
 
-			cfg, err := config.Load()
+			scope := configScope(cmd)
+			if scope == configScopeEffective {
+				scope = configScopeGlobal
+			}
+			details := map[string]interface{}{"key": key, "scope": scope}
+			if scope == configScopeLocal {
+				if config.IsGlobalOnly(key) {
+					return newCLIError(codeInvalid, details, "%s can only be set in the global config", key)
+				}
+				if _, err := os.Stat(filepath.Join(root, ".floop")); os.IsNotExist(err) {
+					return errNotInitialized
+				}
+			}
+
+			cfg, err := loadScopedConfig(root, scope)
 			if err != nil {
 				return fmt.Errorf("failed to load config: %w", err)
 			}
 
 			if err := setConfigValue(cfg, key, value); err != nil {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": err.Error(),
-						"key":   key,
-					})
-				} else {
-					fmt.Printf("Error: %v\n", err)
-				}
-				return nil
+				return newCLIError(codeInvalid, details, "%w", err)
+			}
+			if err := cfg.Validate(); err != nil {
+				return newCLIError(codeInvalid, details, "invalid configuration: %w", err)
 			}
 
 			// Save the config
-			if err := saveConfig(cfg); err != nil {
+			if scope == configScopeLocal {
+				err = config.SaveProjectKey(config.ProjectPath(root), cfg, key)
+			} else {
+				err = saveConfig(cfg)
+			}
+			if err != nil {
 				return fmt.Errorf("failed to save config: %w", err)
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"status": "updated",
 					"key":    key,
 					"value":  value,
+					"scope":  scope,
 				})
 			} else {
-				fmt.Printf("Set %s = %s\n", key, value)
+				fmt.Fprintf(out, "Set %s = %s (%s)\n", key, value, scope)
 			}
 
 			return nil
 		},
 	}
+	addConfigScopeFlags(cmd, "Set")
+	return cmd
+}
+
+// Scopes of the config subcommands. The effective scope layers the project
+// config over the global one and applies environment overrides.
+const (
+	configScopeEffective = ""
+	configScopeGlobal    = "global"
+	configScopeLocal     = "local"
+)
+
+// addConfigScopeFlags adds the mutually exclusive --global and --local
+// flags; verb starts their descriptions.
+func addConfigScopeFlags(cmd *cobra.Command, verb string) {
+	cmd.Flags().Bool("global", false, verb+" the global config (~/.floop/config.yaml)")
+	cmd.Flags().Bool("local", false, verb+" the project config (.floop/config.yaml)")
+	cmd.MarkFlagsMutuallyExclusive("global", "local")
+}
+
+// configScope returns the scope selected by --global or --local.
+func configScope(cmd *cobra.Command) string {
+	if local, _ := cmd.Flags().GetBool("local"); local {
+		return configScopeLocal
+	}
+	if global, _ := cmd.Flags().GetBool("global"); global {
+		return configScopeGlobal
+	}
+	return configScopeEffective
+}
+
+// loadScopedConfig loads the configuration of scope for the project at
+// root. The global and local scopes are a single file over the defaults,
+// without environment overrides, so a set never persists them.
+func loadScopedConfig(root, scope string) (*config.FloopConfig, error) {
+	switch scope {
+	case configScopeLocal:
+		return config.LoadProjectFile(config.ProjectPath(root))
+	case configScopeGlobal:
+		path, err := config.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return config.Default(), nil
+		}
+		return config.LoadFromFile(path)
+	default:
+		return config.LoadForProject(root)
+	}
+}
+
+// loadConfig loads the effective configuration for the project named by
+// --root.
+func loadConfig(cmd *cobra.Command) (*config.FloopConfig, error) {
+	root, _ := cmd.Flags().GetString("root")
+	return config.LoadForProject(root)
 }
 
 // getConfigValue retrieves a configuration value by dot-notation key.
@@ -184,14 +362,102 @@ func getConfigValue(cfg *config.FloopConfig, key string) (interface{}, bool) {
 		return cfg.LLM.MergeModel, true
 	case "llm.timeout":
 		return cfg.LLM.Timeout.String(), true
+	case "llm.max_retries":
+		return cfg.LLM.MaxRetries, true
+	case "llm.daily_budget_usd":
+		return cfg.LLM.DailyBudgetUSD, true
+	case "llm.daily_call_limit":
+		return cfg.LLM.DailyCallLimit, true
 	case "llm.enabled":
 		return cfg.LLM.Enabled, true
 	case "llm.fallback_to_rules":
 		return cfg.LLM.FallbackToRules, true
+	case "llm.extract_behaviors":
+		return cfg.LLM.ExtractBehaviors, true
 	case "deduplication.auto_merge":
 		return cfg.Deduplication.AutoMerge, true
 	case "deduplication.similarity_threshold":
 		return cfg.Deduplication.SimilarityThreshold, true
+	case "safety.protected_operations":
+		return strings.Join(cfg.Safety.ProtectedOperations, ","), true
+	case "review.sla":
+		return cfg.Review.SLA, true
+	case "review.escalate_after":
+		return cfg.Review.EscalateAfter, true
+	case "review.escalate_action":
+		return cfg.Review.EscalateAction, true
+	case "review.webhook_url":
+		return cfg.Review.WebhookURL, true
+	case "review.hold_pending":
+		return cfg.Review.HoldPending, true
+	case "activation.near_miss_max_failing":
+		return cfg.Activation.NearMissMaxFailing, true
+	case "activation.near_miss_suggest_after":
+		return cfg.Activation.NearMissSuggestAfter, true
+	case "activation.time_budget":
+		return cfg.Activation.TimeBudget.String(), true
+	case "activation.shed_top_n":
+		return cfg.Activation.ShedTopN, true
+	case "activation.graph_weight":
+		return cfg.Activation.GraphWeight, true
+	case "activation.git_context":
+		return cfg.Activation.GitContext, true
+	case "ranking.context_weight":
+		return cfg.Ranking.ContextWeight, true
+	case "ranking.base_level_weight":
+		return cfg.Ranking.BaseLevelWeight, true
+	case "ranking.feedback_weight":
+		return cfg.Ranking.FeedbackWeight, true
+	case "ranking.priority_weight":
+		return cfg.Ranking.PriorityWeight, true
+	case "maintenance.gc_interval":
+		return cfg.Maintenance.GCInterval, true
+	case "maintenance.decay_interval":
+		return cfg.Maintenance.DecayInterval, true
+	case "maintenance.decay_window":
+		return cfg.Maintenance.DecayWindow, true
+	case "maintenance.decay_half_life":
+		return cfg.Maintenance.DecayHalfLife, true
+	case "metrics.enabled":
+		return cfg.Metrics.Enabled, true
+	case "tracing.enabled":
+		return cfg.Tracing.Enabled, true
+	case "tracing.endpoint":
+		return cfg.Tracing.Endpoint, true
+	case "tracing.sample_ratio":
+		return cfg.Tracing.SampleRatio, true
+	case "seeds.experimental":
+		return cfg.Seeds.Experimental, true
+	case "embedding.backend":
+		return cfg.Embedding.Backend, true
+	case "embedding.model_path":
+		return cfg.Embedding.ModelPath, true
+	case "embedding.url":
+		return cfg.Embedding.URL, true
+	case "embedding.model":
+		return cfg.Embedding.Model, true
+	case "embedding.dimension":
+		return cfg.Embedding.Dimension, true
+	case "embedding.batch_size":
+		return cfg.Embedding.BatchSize, true
+	case "corrections.max_age":
+		return cfg.Corrections.MaxAge, true
+	case "corrections.max_count":
+		return cfg.Corrections.MaxCount, true
+	case "corrections.processed_only":
+		return cfg.Corrections.ProcessedOnly, true
+	case "corrections.compact_interval":
+		return cfg.Corrections.CompactInterval, true
+	case "packs.signature_policy":
+		return cfg.Packs.EffectiveSignaturePolicy(), true
+	case "packs.collision_policy":
+		return cfg.Packs.EffectiveCollisionPolicy(), true
+	case "packs.github_token":
+		return cfg.Packs.RedactedGitHubToken(), true
+	case "packs.cache_max_age":
+		return cfg.Packs.CacheMaxAge, true
+	case "profile":
+		return cfg.Profile, true
 	default:
 		return nil, false
 	}
@@ -201,9 +467,8 @@ func getConfigValue(cfg *config.FloopConfig, key string) (interface{}, bool) {
 func setConfigValue(cfg *config.FloopConfig, key, value string) error {
 	switch key {
 	case "llm.provider":
-		validProviders := map[string]bool{"": true, "anthropic": true, "openai": true, "ollama": true, "subagent": true}
-		if !validProviders[value] {
-			return fmt.Errorf("invalid provider: %s (valid: anthropic, openai, ollama, subagent, or empty)", value)
+		if value != "" && !slices.Contains(config.LLMProviders(), value) {
+			return fmt.Errorf("invalid provider: %s (valid: %s, or empty)", value, strings.Join(config.LLMProviders(), ", "))
 		}
 		cfg.LLM.Provider = value
 	case "llm.api_key":
@@ -220,10 +485,30 @@ func setConfigValue(cfg *config.FloopConfig, key, value string) error {
 			return fmt.Errorf("invalid duration: %s", value)
 		}
 		cfg.LLM.Timeout = d
+	case "llm.max_retries":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid max_retries: %s (must be a non-negative integer, 0 disables)", value)
+		}
+		cfg.LLM.MaxRetries = n
+	case "llm.daily_budget_usd":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 {
+			return fmt.Errorf("invalid daily_budget_usd: %s (must be a non-negative number, 0 is unlimited)", value)
+		}
+		cfg.LLM.DailyBudgetUSD = f
+	case "llm.daily_call_limit":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid daily_call_limit: %s (must be a non-negative integer, 0 is unlimited)", value)
+		}
+		cfg.LLM.DailyCallLimit = n
 	case "llm.enabled":
 		cfg.LLM.Enabled = value == "true" || value == "1"
 	case "llm.fallback_to_rules":
 		cfg.LLM.FallbackToRules = value == "true" || value == "1"
+	case "llm.extract_behaviors":
+		cfg.LLM.ExtractBehaviors = value == "true" || value == "1"
 	case "deduplication.auto_merge":
 		cfg.Deduplication.AutoMerge = value == "true" || value == "1"
 	case "deduplication.similarity_threshold":
@@ -235,6 +520,205 @@ func setConfigValue(cfg *config.FloopConfig, key, value string) error {
 			return fmt.Errorf("threshold must be between 0 and 1, got %f", f)
 		}
 		cfg.Deduplication.SimilarityThreshold = f
+	case "safety.protected_operations":
+		var ops []string
+		for _, op := range strings.Split(value, ",") {
+			op = strings.TrimSpace(op)
+			if op == "" {
+				continue
+			}
+			if !slices.Contains(config.DestructiveOperations, op) {
+				return fmt.Errorf("invalid operation: %s (valid: %s)", op, strings.Join(config.DestructiveOperations, ", "))
+			}
+			ops = append(ops, op)
+		}
+		cfg.Safety.ProtectedOperations = ops
+	case "review.sla":
+		if value != "" {
+			if _, err := utils.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid duration: %s (e.g. 7d, 48h, or empty to disable)", value)
+			}
+		}
+		cfg.Review.SLA = value
+	case "review.escalate_after":
+		if value != "" {
+			if _, err := utils.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid duration: %s (e.g. 30d, 4w, or empty to disable)", value)
+			}
+		}
+		cfg.Review.EscalateAfter = value
+	case "review.escalate_action":
+		if value != "" && value != string(review.EscalateDowngrade) && value != string(review.EscalateQuarantine) {
+			return fmt.Errorf("invalid escalate action: %s (valid: downgrade, quarantine)", value)
+		}
+		cfg.Review.EscalateAction = value
+	case "review.webhook_url":
+		if value != "" && !strings.HasPrefix(value, "https://") && !strings.HasPrefix(value, "http://") {
+			return fmt.Errorf("invalid webhook URL: %s (must start with http:// or https://)", value)
+		}
+		cfg.Review.WebhookURL = value
+	case "review.hold_pending":
+		cfg.Review.HoldPending = value == "true" || value == "1"
+	case "activation.near_miss_max_failing":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid near_miss_max_failing: %s (must be a non-negative integer, 0 disables)", value)
+		}
+		cfg.Activation.NearMissMaxFailing = n
+	case "activation.near_miss_suggest_after":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid near_miss_suggest_after: %s (must be a positive integer)", value)
+		}
+		cfg.Activation.NearMissSuggestAfter = n
+	case "activation.time_budget":
+		d, err := time.ParseDuration(value)
+		if err != nil || d < 0 {
+			return fmt.Errorf("invalid time_budget: %s (e.g. 200ms, or 0 to disable)", value)
+		}
+		cfg.Activation.TimeBudget = d
+	case "activation.shed_top_n":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid shed_top_n: %s (must be a positive integer)", value)
+		}
+		cfg.Activation.ShedTopN = n
+	case "activation.graph_weight":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 || f > 1 {
+			return fmt.Errorf("invalid graph_weight: %s (must be between 0 and 1, 0 disables)", value)
+		}
+		cfg.Activation.GraphWeight = f
+	case "activation.git_context":
+		cfg.Activation.GitContext = value == "true" || value == "1"
+	case "ranking.context_weight", "ranking.base_level_weight", "ranking.feedback_weight", "ranking.priority_weight":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 || f > 1 {
+			return fmt.Errorf("invalid %s: %s (must be between 0 and 1)", strings.TrimPrefix(key, "ranking."), value)
+		}
+		switch key {
+		case "ranking.context_weight":
+			cfg.Ranking.ContextWeight = f
+		case "ranking.base_level_weight":
+			cfg.Ranking.BaseLevelWeight = f
+		case "ranking.feedback_weight":
+			cfg.Ranking.FeedbackWeight = f
+		case "ranking.priority_weight":
+			cfg.Ranking.PriorityWeight = f
+		}
+	case "maintenance.gc_interval":
+		if value != "" {
+			if _, err := utils.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid duration: %s (e.g. 7d, 24h, or empty to disable)", value)
+			}
+		}
+		cfg.Maintenance.GCInterval = value
+	case "maintenance.decay_interval":
+		if value != "" {
+			if _, err := utils.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid duration: %s (e.g. 1d, 12h, or empty to disable)", value)
+			}
+		}
+		cfg.Maintenance.DecayInterval = value
+	case "maintenance.decay_window", "maintenance.decay_half_life":
+		d, err := utils.ParseDuration(value)
+		if err != nil || d <= 0 {
+			return fmt.Errorf("invalid duration: %s (must be positive, e.g. 30d)", value)
+		}
+		if key == "maintenance.decay_window" {
+			cfg.Maintenance.DecayWindow = value
+		} else {
+			cfg.Maintenance.DecayHalfLife = value
+		}
+	case "metrics.enabled":
+		cfg.Metrics.Enabled = value == "true" || value == "1"
+	case "tracing.enabled":
+		cfg.Tracing.Enabled = value == "true" || value == "1"
+	case "tracing.endpoint":
+		if value != "" {
+			if _, err := tracing.EndpointURL(value); err != nil {
+				return err
+			}
+		}
+		cfg.Tracing.Endpoint = value
+	case "tracing.sample_ratio":
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil || f < 0 || f > 1 {
+			return fmt.Errorf("invalid sample_ratio: %s (must be between 0 and 1, 0 samples everything)", value)
+		}
+		cfg.Tracing.SampleRatio = f
+	case "seeds.experimental":
+		cfg.Seeds.Experimental = value == "true" || value == "1"
+	case "embedding.backend":
+		switch value {
+		case "", config.EmbeddingBackendLocal, config.EmbeddingBackendServer:
+			cfg.Embedding.Backend = value
+		default:
+			return fmt.Errorf("invalid embedding backend: %s (valid: local, server, or empty)", value)
+		}
+	case "embedding.model_path":
+		cfg.Embedding.ModelPath = value
+	case "embedding.url":
+		cfg.Embedding.URL = value
+	case "embedding.model":
+		cfg.Embedding.Model = value
+	case "embedding.dimension":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid dimension: %s (must be a non-negative integer, 0 accepts any)", value)
+		}
+		cfg.Embedding.Dimension = n
+	case "embedding.batch_size":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid batch_size: %s (must be a positive integer)", value)
+		}
+		cfg.Embedding.BatchSize = n
+	case "corrections.max_age", "corrections.compact_interval":
+		if value != "" {
+			if _, err := utils.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid duration: %s (e.g. 180d, 7d, or empty to disable)", value)
+			}
+		}
+		if key == "corrections.max_age" {
+			cfg.Corrections.MaxAge = value
+		} else {
+			cfg.Corrections.CompactInterval = value
+		}
+	case "corrections.max_count":
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return fmt.Errorf("invalid max_count: %s (must be a non-negative integer, 0 for unlimited)", value)
+		}
+		cfg.Corrections.MaxCount = n
+	case "corrections.processed_only":
+		cfg.Corrections.ProcessedOnly = value == "true" || value == "1"
+	case "packs.signature_policy":
+		switch value {
+		case config.SignaturePolicyOff, config.SignaturePolicyWarn, config.SignaturePolicyRequire:
+			cfg.Packs.SignaturePolicy = value
+		default:
+			return fmt.Errorf("invalid signature policy: %s (valid: off, warn, require)", value)
+		}
+	case "packs.collision_policy":
+		if !slices.Contains(config.CollisionPolicies, value) {
+			return fmt.Errorf("invalid collision policy: %s (valid: %s)", value, strings.Join(config.CollisionPolicies, ", "))
+		}
+		cfg.Packs.CollisionPolicy = value
+	case "packs.github_token":
+		cfg.Packs.GitHubToken = value
+	case "packs.cache_max_age":
+		if value != "" {
+			if _, err := utils.ParseDuration(value); err != nil {
+				return fmt.Errorf("invalid duration: %s (e.g. 90d, 720h, or empty for no limit)", value)
+			}
+		}
+		cfg.Packs.CacheMaxAge = value
+	case "profile":
+		if _, err := cfg.ResolveProfile(value); err != nil {
+			return err
+		}
+		cfg.Profile = value
 	default:
 		return fmt.Errorf("unknown configuration key: %s", key)
 	}
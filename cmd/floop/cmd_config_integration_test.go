@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/spf13/cobra"
 	"gopkg.in/yaml.v3"
 
 	"github.com/nvandessel/floop/internal/config"
@@ -101,9 +102,7 @@ func TestConfigGetCmdUnknownKey(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"config", "get", "nonexistent.key"})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("config get unknown key failed: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestConfigSetCmd(t *testing.T) {
@@ -140,10 +139,7 @@ func TestConfigSetCmdInvalidValue(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"config", "set", "llm.provider", "invalid_provider"})
 
-	// Should not return error (prints error message instead)
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("config set invalid: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeInvalid)
 }
 
 func TestConfigSetCmdUnknownKey(t *testing.T) {
@@ -154,8 +150,111 @@ func TestConfigSetCmdUnknownKey(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"config", "set", "nonexistent.key", "value"})
 
-	// Should not return error (prints error message instead)
+	wantErrorCode(t, rootCmd.Execute(), codeInvalid)
+}
+
+func TestConfigSetCmdLocal(t *testing.T) {
+	tmpDir := setupConfigTest(t)
+	root := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(filepath.Join(root, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"config", "set", "--local", "--root", root, "ranking.context_weight", "0.4"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config set --local failed: %v", err)
+	}
+
+	local, err := config.LoadProjectFile(config.ProjectPath(root))
+	if err != nil {
+		t.Fatalf("loading project config: %v", err)
+	}
+	if local.Ranking.ContextWeight != 0.4 {
+		t.Errorf("project ranking.context_weight = %v, want 0.4", local.Ranking.ContextWeight)
+	}
+	global, err := config.LoadFromFile(filepath.Join(tmpDir, "home", ".floop", "config.yaml"))
+	if err != nil {
+		t.Fatalf("loading global config: %v", err)
+	}
+	if global.Ranking.ContextWeight == 0.4 {
+		t.Error("config set --local changed the global config")
+	}
+
+	var out bytes.Buffer
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"config", "get", "--root", root, "ranking.context_weight"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("config get failed: %v", err)
+	}
+	if got := out.String(); got != "ranking.context_weight = 0.4\n" {
+		t.Errorf("effective value = %q, want the project override", got)
+	}
+}
+
+func TestConfigSetCmdLocalGlobalOnly(t *testing.T) {
+	tmpDir := setupConfigTest(t)
+	root := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(filepath.Join(root, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"config", "set", "--local", "--root", root, "llm.base_url", "https://example.com"})
+
+	wantErrorCode(t, rootCmd.Execute(), codeInvalid)
+}
+
+func TestConfigSetCmdLocalNotInitialized(t *testing.T) {
+	tmpDir := setupConfigTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newConfigCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"config", "set", "--local", "--root", tmpDir, "token_budget.default", "3000"})
+
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
+}
+
+func TestLoadConfigUsesRoot(t *testing.T) {
+	tmpDir := setupConfigTest(t)
+	root := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(filepath.Join(root, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config.ProjectPath(root), []byte("ranking:\n  context_weight: 0.4\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	var cfg *config.FloopConfig
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(&cobra.Command{
+		Use: "probe",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			cfg, err = loadConfig(cmd)
+			return err
+		},
+	})
+	rootCmd.SetArgs([]string{"probe", "--root", root})
 	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("config set unknown key: %v", err)
+		t.Fatalf("loadConfig: %v", err)
+	}
+	if cfg.Ranking.ContextWeight != 0.4 {
+		t.Errorf("ranking.context_weight = %v, want the override under --root", cfg.Ranking.ContextWeight)
+	}
+
+	global, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	if global.Ranking.ContextWeight == 0.4 {
+		t.Error("config.Load applied a project override")
 	}
 }
@@ -51,13 +51,50 @@ func TestGetConfigValue(t *testing.T) {
 		{"llm.provider", "llm.provider", true},
 		{"llm.enabled", "llm.enabled", true},
 		{"llm.fallback_to_rules", "llm.fallback_to_rules", true},
+		{"llm.extract_behaviors", "llm.extract_behaviors", true},
 		{"llm.timeout", "llm.timeout", true},
+		{"llm.max_retries", "llm.max_retries", true},
+		{"llm.daily_budget_usd", "llm.daily_budget_usd", true},
+		{"llm.daily_call_limit", "llm.daily_call_limit", true},
 		{"llm.api_key", "llm.api_key", true},
 		{"llm.base_url", "llm.base_url", true},
 		{"llm.comparison_model", "llm.comparison_model", true},
 		{"llm.merge_model", "llm.merge_model", true},
 		{"deduplication.auto_merge", "deduplication.auto_merge", true},
 		{"deduplication.similarity_threshold", "deduplication.similarity_threshold", true},
+		{"safety.protected_operations", "safety.protected_operations", true},
+		{"review.sla", "review.sla", true},
+		{"review.escalate_after", "review.escalate_after", true},
+		{"review.escalate_action", "review.escalate_action", true},
+		{"review.webhook_url", "review.webhook_url", true},
+		{"activation.near_miss_max_failing", "activation.near_miss_max_failing", true},
+		{"activation.near_miss_suggest_after", "activation.near_miss_suggest_after", true},
+		{"activation.time_budget", "activation.time_budget", true},
+		{"activation.shed_top_n", "activation.shed_top_n", true},
+		{"activation.graph_weight", "activation.graph_weight", true},
+		{"maintenance.gc_interval", "maintenance.gc_interval", true},
+		{"maintenance.decay_interval", "maintenance.decay_interval", true},
+		{"maintenance.decay_window", "maintenance.decay_window", true},
+		{"maintenance.decay_half_life", "maintenance.decay_half_life", true},
+		{"metrics.enabled", "metrics.enabled", true},
+		{"tracing.enabled", "tracing.enabled", true},
+		{"tracing.endpoint", "tracing.endpoint", true},
+		{"tracing.sample_ratio", "tracing.sample_ratio", true},
+		{"seeds.experimental", "seeds.experimental", true},
+		{"embedding.backend", "embedding.backend", true},
+		{"embedding.model_path", "embedding.model_path", true},
+		{"embedding.url", "embedding.url", true},
+		{"embedding.model", "embedding.model", true},
+		{"embedding.dimension", "embedding.dimension", true},
+		{"embedding.batch_size", "embedding.batch_size", true},
+		{"corrections.max_age", "corrections.max_age", true},
+		{"corrections.max_count", "corrections.max_count", true},
+		{"corrections.processed_only", "corrections.processed_only", true},
+		{"corrections.compact_interval", "corrections.compact_interval", true},
+		{"packs.signature_policy", "packs.signature_policy", true},
+		{"packs.collision_policy", "packs.collision_policy", true},
+		{"packs.github_token", "packs.github_token", true},
+		{"packs.cache_max_age", "packs.cache_max_age", true},
 		{"unknown key", "nonexistent.key", false},
 	}
 
@@ -101,6 +138,16 @@ func TestSetConfigValue(t *testing.T) {
 		{"empty provider", "llm.provider", "", false},
 		{"subagent provider", "llm.provider", "subagent", false},
 		{"invalid provider", "llm.provider", "invalid", true},
+		{"openai-compatible provider", "llm.provider", "openai-compatible", false},
+		{"local provider", "llm.provider", "local", false},
+		{"valid max_retries", "llm.max_retries", "3", false},
+		{"zero max_retries", "llm.max_retries", "0", false},
+		{"negative max_retries", "llm.max_retries", "-1", true},
+		{"valid daily_budget_usd", "llm.daily_budget_usd", "0.50", false},
+		{"negative daily_budget_usd", "llm.daily_budget_usd", "-1", true},
+		{"invalid daily_budget_usd", "llm.daily_budget_usd", "lots", true},
+		{"valid daily_call_limit", "llm.daily_call_limit", "200", false},
+		{"negative daily_call_limit", "llm.daily_call_limit", "-5", true},
 		{"api key", "llm.api_key", "sk-test123", false},
 		{"base url", "llm.base_url", "https://api.example.com", false},
 		{"comparison model", "llm.comparison_model", "claude-3-opus", false},
@@ -115,6 +162,63 @@ func TestSetConfigValue(t *testing.T) {
 		{"threshold too high", "deduplication.similarity_threshold", "1.5", true},
 		{"threshold too low", "deduplication.similarity_threshold", "-0.1", true},
 		{"invalid threshold", "deduplication.similarity_threshold", "abc", true},
+		{"protected operations", "safety.protected_operations", "restore-replace, pack-remove", false},
+		{"clear protected operations", "safety.protected_operations", "", false},
+		{"unknown protected operation", "safety.protected_operations", "store-wipe", true},
+		{"review sla", "review.sla", "3d", false},
+		{"disable review sla", "review.sla", "", false},
+		{"invalid review sla", "review.sla", "soon", true},
+		{"review escalate after", "review.escalate_after", "4w", false},
+		{"invalid review escalate after", "review.escalate_after", "-1h", true},
+		{"review escalate action", "review.escalate_action", "quarantine", false},
+		{"invalid review escalate action", "review.escalate_action", "delete", true},
+		{"review webhook", "review.webhook_url", "https://hooks.example.com/floop", false},
+		{"invalid review webhook", "review.webhook_url", "ftp://example.com", true},
+		{"near miss max failing", "activation.near_miss_max_failing", "2", false},
+		{"disable near misses", "activation.near_miss_max_failing", "0", false},
+		{"negative near miss max failing", "activation.near_miss_max_failing", "-1", true},
+		{"near miss suggest after", "activation.near_miss_suggest_after", "10", false},
+		{"zero near miss suggest after", "activation.near_miss_suggest_after", "0", true},
+		{"time budget", "activation.time_budget", "500ms", false},
+		{"disable time budget", "activation.time_budget", "0", false},
+		{"negative time budget", "activation.time_budget", "-1s", true},
+		{"invalid time budget", "activation.time_budget", "fast", true},
+		{"shed top n", "activation.shed_top_n", "10", false},
+		{"zero shed top n", "activation.shed_top_n", "0", true},
+		{"graph weight", "activation.graph_weight", "0.25", false},
+		{"disable graph weight", "activation.graph_weight", "0", false},
+		{"graph weight above 1", "activation.graph_weight", "1.5", true},
+		{"gc interval", "maintenance.gc_interval", "1d", false},
+		{"disable gc", "maintenance.gc_interval", "", false},
+		{"invalid gc interval", "maintenance.gc_interval", "weekly", true},
+		{"decay interval", "maintenance.decay_interval", "1d", false},
+		{"decay window", "maintenance.decay_window", "14d", false},
+		{"empty decay half-life", "maintenance.decay_half_life", "", true},
+		{"enable metrics", "metrics.enabled", "true", false},
+		{"enable tracing", "tracing.enabled", "true", false},
+		{"tracing endpoint", "tracing.endpoint", "http://localhost:4318", false},
+		{"invalid tracing endpoint", "tracing.endpoint", "localhost:4318", true},
+		{"tracing sample ratio", "tracing.sample_ratio", "0.25", false},
+		{"invalid tracing sample ratio", "tracing.sample_ratio", "2", true},
+		{"experimental seeds", "seeds.experimental", "true", false},
+		{"server embedding backend", "embedding.backend", "server", false},
+		{"invalid embedding backend", "embedding.backend", "onnx", true},
+		{"embedding url", "embedding.url", "http://localhost:8080/v1", false},
+		{"embedding dimension", "embedding.dimension", "384", false},
+		{"corrections max age", "corrections.max_age", "90d", false},
+		{"invalid corrections max age", "corrections.max_age", "soon", true},
+		{"disable corrections compaction", "corrections.compact_interval", "", false},
+		{"negative corrections max count", "corrections.max_count", "-5", true},
+		{"negative embedding dimension", "embedding.dimension", "-1", true},
+		{"embedding batch size", "embedding.batch_size", "64", false},
+		{"zero embedding batch size", "embedding.batch_size", "0", true},
+		{"require signatures", "packs.signature_policy", "require", false},
+		{"invalid signature policy", "packs.signature_policy", "strict", true},
+		{"keep local on collision", "packs.collision_policy", "keep-local", false},
+		{"invalid collision policy", "packs.collision_policy", "merge", true},
+		{"github token env reference", "packs.github_token", "${GH_PACKS_TOKEN}", false},
+		{"valid cache max age", "packs.cache_max_age", "90d", false},
+		{"invalid cache max age", "packs.cache_max_age", "soon", true},
 		{"unknown key", "nonexistent.key", "value", true},
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"time"
@@ -34,6 +35,7 @@ Examples:
   floop connect behavior-abc behavior-xyz similar-to --bidirectional`,
 		Args: cobra.ExactArgs(3),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			source := args[0]
 			target := args[1]
 			kind := args[2]
@@ -62,7 +64,7 @@ Examples:
 			// Check local initialization
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+				return errNotInitialized
 			}
 
 			ctx := context.Background()
@@ -98,7 +100,7 @@ Examples:
 			for _, e := range existing {
 				if e.Target == target {
 					if !jsonOut {
-						fmt.Fprintf(os.Stderr, "warning: edge %s -[%s]-> %s already exists (weight: %.2f)\n", source, kind, target, e.Weight)
+						slog.Warn("edge already exists", "source", source, "kind", kind, "target", target, "weight", e.Weight)
 					}
 				}
 			}
@@ -138,7 +140,7 @@ Examples:
 
 			// Refresh PageRank
 			if _, err := ranking.ComputePageRank(ctx, graphStore, ranking.DefaultPageRankConfig()); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to refresh PageRank: %v\n", err)
+				slog.Warn("failed to refresh PageRank", "error", err)
 			}
 
 			// Output
@@ -152,12 +154,12 @@ Examples:
 			}
 
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(result)
+				return json.NewEncoder(out).Encode(result)
 			}
 
-			fmt.Printf("✓ Edge created: %s -[%s (%.2f)]-> %s\n", source, kind, weight, target)
+			fmt.Fprintf(out, "✓ Edge created: %s -[%s (%.2f)]-> %s\n", source, kind, weight, target)
 			if bidirectional {
-				fmt.Printf("✓ Reverse edge: %s -[%s (%.2f)]-> %s\n", target, kind, weight, source)
+				fmt.Fprintf(out, "✓ Reverse edge: %s -[%s (%.2f)]-> %s\n", target, kind, weight, source)
 			}
 			return nil
 		},
@@ -9,7 +9,6 @@ import (
 	"path/filepath"
 	"time"
 
-	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/consolidation"
 	"github.com/nvandessel/floop/internal/events"
 	"github.com/nvandessel/floop/internal/llm"
@@ -35,12 +34,12 @@ func newConsolidateCmd() *cobra.Command {
 }
 
 func runConsolidate(cmd *cobra.Command, args []string) error {
+	out := newOutput(cmd)
 	session, _ := cmd.Flags().GetString("session")
 	since, _ := cmd.Flags().GetString("since")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	jsonOut, _ := cmd.Flags().GetBool("json")
 	executor, _ := cmd.Flags().GetString("executor")
-	out := cmd.OutOrStdout()
 
 	// Open global DB
 	homeDir, err := os.UserHomeDir()
@@ -111,7 +110,7 @@ func runConsolidate(cmd *cobra.Command, args []string) error {
 	}
 
 	// Resolve executor from flag or config (single config.Load to avoid redundant disk I/O)
-	floopCfg, cfgErr := config.Load()
+	floopCfg, cfgErr := loadConfig(cmd)
 	if cfgErr != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to load config: %v\n", cfgErr)
 	}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/spf13/cobra"
+)
+
+func newCorrectionsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "corrections",
+		Short: "Manage the project's correction log",
+	}
+
+	cmd.AddCommand(newCorrectionsCompactCmd())
+
+	return cmd
+}
+
+func newCorrectionsCompactCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "compact",
+		Short: "Archive old corrections out of corrections.jsonl",
+		Long: `Move corrections past the retention limits from .floop/corrections.jsonl
+into the gzip archive .floop/corrections-archive.jsonl.gz.
+
+Corrections older than corrections.max_age (default 180d) are archived, then
+the oldest ones until at most corrections.max_count (default 10000) remain.
+With corrections.processed_only (the default), corrections that have not
+been turned into behaviors yet are never archived. Archived corrections are
+kept, not deleted: read them back with 'zcat'.
+
+Compaction also runs automatically after learning, at most once every
+corrections.compact_interval (default 7d).
+
+Examples:
+  floop corrections compact --dry-run
+  floop corrections compact --yes
+  floop corrections compact --max-age 30d --max-count 1000`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			if cmd.Flags().Changed("max-age") {
+				cfg.Corrections.MaxAge, _ = cmd.Flags().GetString("max-age")
+			}
+			if cmd.Flags().Changed("max-count") {
+				cfg.Corrections.MaxCount, _ = cmd.Flags().GetInt("max-count")
+				if cfg.Corrections.MaxCount < 0 {
+					return fmt.Errorf("--max-count must be non-negative")
+				}
+			}
+			if cmd.Flags().Changed("processed-only") {
+				cfg.Corrections.ProcessedOnly, _ = cmd.Flags().GetBool("processed-only")
+			}
+			opts, err := corrections.OptionsFromConfig(cfg.Corrections)
+			if err != nil {
+				return err
+			}
+			opts.DryRun = dryRun
+
+			if !dryRun {
+				confirmed, err := confirmDestructive(config.OpCorrectionsCompact, yes, jsonOut, func() {
+					fmt.Fprintln(out, "Move corrections past the retention limits into corrections-archive.jsonl.gz.")
+				})
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(out, "Cancelled.")
+					return nil
+				}
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			report, err := corrections.Compact(floopDir, opts)
+			if err != nil {
+				return err
+			}
+			if !dryRun {
+				if err := corrections.MarkRun(floopDir, report); err != nil {
+					return err
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(report)
+			}
+			verb := "Archived"
+			if dryRun {
+				verb = "Would archive"
+			}
+			fmt.Fprintf(out, "%s %d of %d correction(s); %d kept\n", verb, len(report.Archived), report.Examined, report.Kept)
+			for _, c := range report.Archived {
+				out.Verbosef("  %s %s %s\n", c.Timestamp.Format("2006-01-02"), c.ID, truncatePreview(c.CorrectedAction, 60))
+			}
+			if report.ArchivePath != "" {
+				fmt.Fprintf(out, "Archive: %s\n", report.ArchivePath)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Report what would be archived without changing any file")
+	cmd.Flags().String("max-age", "", "Archive corrections older than this (default corrections.max_age)")
+	cmd.Flags().Int("max-count", 0, "Keep at most this many corrections (default corrections.max_count)")
+	cmd.Flags().Bool("processed-only", true, "Only archive corrections already turned into behaviors (default corrections.processed_only)")
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// autoCompactCorrections compacts the correction log in floopDir when
+// corrections.compact_interval has elapsed. Failures only warn: the
+// correction is already recorded.
+func autoCompactCorrections(floopDir string) {
+	cfg, err := config.LoadForProject(filepath.Dir(floopDir))
+	if err != nil {
+		cfg = config.Default()
+	}
+	if _, err := corrections.AutoCompact(floopDir, cfg.Corrections, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "warning: failed to compact corrections: %v\n", err)
+	}
+}
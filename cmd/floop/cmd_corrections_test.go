@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/corrections"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestCorrectionsCompactCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	floopDir := filepath.Join(tmpDir, ".floop")
+	if err := os.MkdirAll(floopDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	var log bytes.Buffer
+	enc := json.NewEncoder(&log)
+	for i, age := range []time.Duration{400, 300, 2} {
+		c := models.Correction{
+			ID:              string(rune('a' + i)),
+			Timestamp:       time.Now().Add(-age * 24 * time.Hour),
+			CorrectedAction: "use slog",
+			Processed:       i != 1,
+		}
+		if err := enc.Encode(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(floopDir, corrections.File), log.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCmd(t, newCorrectionsCmd(), "compact", "--dry-run", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("compact --dry-run: %v", err)
+	}
+	if !strings.Contains(out, "Would archive 1 of 3 correction(s); 2 kept") {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+
+	// Unprocessed corrections are archived too with --processed-only=false
+	out, err = runCmd(t, newCorrectionsCmd(), "compact", "--processed-only=false", "--json", "--yes", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("compact: %v", err)
+	}
+	var report corrections.Report
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(report.Archived) != 2 || report.Kept != 1 {
+		t.Errorf("report = %+v, want 2 archived and 1 kept", report)
+	}
+	archived, err := corrections.ReadArchive(floopDir)
+	if err != nil || len(archived) != 2 {
+		t.Errorf("archive = %+v, %v; want 2 corrections", archived, err)
+	}
+	if _, err := os.Stat(filepath.Join(floopDir, corrections.StateFile)); err != nil {
+		t.Errorf("compaction not recorded: %v", err)
+	}
+}
@@ -28,7 +28,7 @@ func TestMergeDuplicatePairsEmpty(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewInMemoryGraphStore()
 
-	count := mergeDuplicatePairs(ctx, s, nil, nil, false)
+	count := mergeDuplicatePairs(ctx, os.Stdout, s, nil, nil, false, false, nil)
 	if count != 0 {
 		t.Errorf("mergeDuplicatePairs with nil duplicates = %d, want 0", count)
 	}
@@ -71,7 +71,7 @@ func TestMergeDuplicatePairsBasic(t *testing.T) {
 		{BehaviorA: &b1, BehaviorB: &b2, Similarity: 0.95},
 	}
 
-	count := mergeDuplicatePairs(ctx, s, duplicates, nil, false)
+	count := mergeDuplicatePairs(ctx, os.Stdout, s, duplicates, nil, false, false, nil)
 	if count != 1 {
 		t.Errorf("mergeDuplicatePairs = %d, want 1", count)
 	}
@@ -119,7 +119,7 @@ func TestMergeDuplicatePairsJSON(t *testing.T) {
 	}
 
 	// jsonOut=true should suppress stderr warnings
-	count := mergeDuplicatePairs(ctx, s, duplicates, nil, true)
+	count := mergeDuplicatePairs(ctx, os.Stdout, s, duplicates, nil, true, false, nil)
 	if count != 1 {
 		t.Errorf("mergeDuplicatePairs JSON mode = %d, want 1", count)
 	}
@@ -144,7 +144,7 @@ func TestMergeDuplicatePairsSkipsAlreadyMerged(t *testing.T) {
 		{BehaviorA: &b2, BehaviorB: &b3, Similarity: 0.90},
 	}
 
-	count := mergeDuplicatePairs(ctx, s, duplicates, nil, false)
+	count := mergeDuplicatePairs(ctx, os.Stdout, s, duplicates, nil, false, false, nil)
 	// Only first pair merged; b2 already merged so second pair skipped
 	if count != 1 {
 		t.Errorf("mergeDuplicatePairs with overlapping = %d, want 1", count)
@@ -168,7 +168,7 @@ func TestPrintDeriveResultDryRun(t *testing.T) {
 	}
 
 	// Should not panic
-	printDeriveResult(result, true)
+	printDeriveResult(os.Stdout, result, true)
 }
 
 func TestPrintDeriveResultNotDryRun(t *testing.T) {
@@ -182,7 +182,7 @@ func TestPrintDeriveResultNotDryRun(t *testing.T) {
 		Connectivity:    edges.ConnectivityInfo{TotalNodes: 3, Connected: 2, Islands: 1},
 	}
 
-	printDeriveResult(result, false)
+	printDeriveResult(os.Stdout, result, false)
 }
 
 // --- Connect command full success path (53.2% → higher) ---
@@ -447,7 +447,7 @@ func TestMergeCmdJSONSuccess(t *testing.T) {
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newMergeCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"merge", nodes[0].ID, nodes[1].ID, "--json", "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"merge", nodes[0].ID, nodes[1].ID, "--json", "--yes", "--root", tmpDir})
 
 	if err := rootCmd2.Execute(); err != nil {
 		t.Fatalf("merge --json failed: %v", err)
@@ -560,10 +560,7 @@ func TestForgetCmdNotFoundJSON(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"forget", "nonexistent-id", "--json", "--root", tmpDir})
 
-	// JSON mode should not error, just return JSON with error field
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("forget --json should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestForgetCmdNotBehaviorJSON(t *testing.T) {
@@ -580,11 +577,9 @@ func TestForgetCmdNotBehaviorJSON(t *testing.T) {
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newForgetCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"forget", behaviorID, "--json", "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"forget", behaviorID, "--json", "--yes", "--root", tmpDir})
 
-	if err := rootCmd2.Execute(); err != nil {
-		t.Fatalf("forget (not behavior) --json should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd2.Execute(), codeInvalid)
 }
 
 // --- Deprecate command more coverage (78.6% → higher) ---
@@ -667,10 +662,7 @@ func TestDeprecateCmdNotFoundJSON(t *testing.T) {
 		"--root", tmpDir,
 	})
 
-	// JSON mode: should not error
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("deprecate --json (not found) should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestDeprecateCmdNotBehaviorJSON(t *testing.T) {
@@ -694,9 +686,7 @@ func TestDeprecateCmdNotBehaviorJSON(t *testing.T) {
 		"--root", tmpDir,
 	})
 
-	if err := rootCmd2.Execute(); err != nil {
-		t.Fatalf("deprecate (not behavior) --json should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd2.Execute(), codeInvalid)
 }
 
 func TestDeprecateCmdWithReplacementJSON(t *testing.T) {
@@ -748,9 +738,7 @@ func TestRestoreCmdNotFoundJSON(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"restore", "nonexistent", "--json", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("restore --json (not found) should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestRestoreCmdNotRestorableJSON(t *testing.T) {
@@ -762,9 +750,7 @@ func TestRestoreCmdNotRestorableJSON(t *testing.T) {
 	rootCmd.SetArgs([]string{"restore", behaviorID, "--json", "--root", tmpDir})
 
 	// Active behavior should not be restorable
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("restore --json (not restorable) should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeInvalid)
 }
 
 func TestDeprecateThenRestoreJSON(t *testing.T) {
@@ -1533,7 +1519,7 @@ func TestRunDedupOnStoreNoBehaviors(t *testing.T) {
 	s := store.NewInMemoryGraphStore()
 
 	// Empty store
-	err := runDedupOnStore(ctx, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, false, false)
+	err := runDedupOnStore(ctx, os.Stdout, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, false, false, false, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1543,7 +1529,7 @@ func TestRunDedupOnStoreNoBehaviorsJSON(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewInMemoryGraphStore()
 
-	err := runDedupOnStore(ctx, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, false, true)
+	err := runDedupOnStore(ctx, os.Stdout, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, false, true, false, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1562,7 +1548,7 @@ func TestRunDedupOnStoreNoDuplicates(t *testing.T) {
 		s.AddNode(ctx, node)
 	}
 
-	err := runDedupOnStore(ctx, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.9}, nil, false, false)
+	err := runDedupOnStore(ctx, os.Stdout, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.9}, nil, false, false, false, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1580,7 +1566,7 @@ func TestRunDedupOnStoreNoDuplicatesJSON(t *testing.T) {
 		s.AddNode(ctx, node)
 	}
 
-	err := runDedupOnStore(ctx, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.9}, nil, false, true)
+	err := runDedupOnStore(ctx, os.Stdout, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.9}, nil, false, true, false, nil)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -1610,7 +1596,7 @@ func TestRunDedupOnStoreDryRunWithDuplicates(t *testing.T) {
 	}
 
 	// Dry run — text mode
-	err := runDedupOnStore(ctx, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, true, false)
+	err := runDedupOnStore(ctx, os.Stdout, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, true, false, false, nil)
 	if err != nil {
 		t.Fatalf("dry run text mode failed: %v", err)
 	}
@@ -1639,7 +1625,7 @@ func TestRunDedupOnStoreDryRunWithDuplicatesJSON(t *testing.T) {
 	}
 
 	// Dry run — JSON mode
-	err := runDedupOnStore(ctx, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, true, true)
+	err := runDedupOnStore(ctx, os.Stdout, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, true, true, false, nil)
 	if err != nil {
 		t.Fatalf("dry run JSON mode failed: %v", err)
 	}
@@ -1668,7 +1654,7 @@ func TestRunDedupOnStoreMerge(t *testing.T) {
 	}
 
 	// Actual merge (not dry run) — text mode
-	err := runDedupOnStore(ctx, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, false, false)
+	err := runDedupOnStore(ctx, os.Stdout, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, false, false, false, nil)
 	if err != nil {
 		t.Fatalf("merge text mode failed: %v", err)
 	}
@@ -1697,7 +1683,7 @@ func TestRunDedupOnStoreMergeJSON(t *testing.T) {
 	}
 
 	// Actual merge — JSON mode
-	err := runDedupOnStore(ctx, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, false, true)
+	err := runDedupOnStore(ctx, os.Stdout, s, dedup.DeduplicatorConfig{SimilarityThreshold: 0.5}, nil, false, true, false, nil)
 	if err != nil {
 		t.Fatalf("merge JSON mode failed: %v", err)
 	}
@@ -1836,9 +1822,15 @@ func TestPackRemoveJSON(t *testing.T) {
 	rootCmd3.AddCommand(newPackCmd())
 	rootCmd3.SetOut(&bytes.Buffer{})
 	rootCmd3.SetArgs([]string{"pack", "remove", "test-org/rm-json", "--json", "--root", tmpDir})
+	wantErrorCode(t, rootCmd3.Execute(), codeConfirmationRequired)
 
-	if err := rootCmd3.Execute(); err != nil {
-		t.Fatalf("pack remove --json failed: %v", err)
+	rootCmd4 := newTestRootCmd()
+	rootCmd4.AddCommand(newPackCmd())
+	rootCmd4.SetOut(&bytes.Buffer{})
+	rootCmd4.SetArgs([]string{"pack", "remove", "test-org/rm-json", "--json", "--yes", "--root", tmpDir})
+
+	if err := rootCmd4.Execute(); err != nil {
+		t.Fatalf("pack remove --json --yes failed: %v", err)
 	}
 }
 
@@ -2145,6 +2137,21 @@ func TestCreateLLMClientAnthropicProvider(t *testing.T) {
 	}
 }
 
+func TestCreateLLMClientOpenAICompatibleProvider(t *testing.T) {
+	cfg := &config.FloopConfig{
+		LLM: config.LLMConfig{Enabled: true, Provider: "openai-compatible", BaseURL: "http://localhost:8000/v1", MaxRetries: 2},
+	}
+	client := createLLMClient(cfg)
+	if client == nil || !client.Available() {
+		t.Error("expected available client for openai-compatible provider without API key")
+	}
+
+	cfg.LLM.BaseURL = ""
+	if client := createLLMClient(cfg); client != nil {
+		t.Error("expected nil client for openai-compatible provider without base URL")
+	}
+}
+
 func TestCreateLLMClientWithTimeout(t *testing.T) {
 	cfg := &config.FloopConfig{
 		LLM: config.LLMConfig{Enabled: true, Provider: "openai", APIKey: "test-key"},
@@ -2819,10 +2826,7 @@ func TestConfigSetInvalidKey(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"config", "set", "nonexistent.key", "value"})
 
-	// The command prints its own error and returns nil
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("config set should not return error (prints own): %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeInvalid)
 }
 
 func TestConfigSetInvalidKeyJSON(t *testing.T) {
@@ -2834,9 +2838,7 @@ func TestConfigSetInvalidKeyJSON(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"config", "set", "nonexistent.key", "value", "--json"})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("config set --json should not return error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeInvalid)
 }
 
 // --- hook session-start and first-prompt with behaviors ---
@@ -3069,7 +3071,7 @@ func TestForgetCmdSuccessJSON(t *testing.T) {
 	rootCmd := newTestRootCmd()
 	rootCmd.AddCommand(newForgetCmd())
 	rootCmd.SetOut(&bytes.Buffer{})
-	rootCmd.SetArgs([]string{"forget", behaviorID, "--json", "--root", tmpDir})
+	rootCmd.SetArgs([]string{"forget", behaviorID, "--json", "--yes", "--root", tmpDir})
 
 	if err := rootCmd.Execute(); err != nil {
 		t.Fatalf("forget --json failed: %v", err)
@@ -3203,7 +3205,7 @@ func TestMergeCmdFullFlowJSON(t *testing.T) {
 	rootCmd4 := newTestRootCmd()
 	rootCmd4.AddCommand(newMergeCmd())
 	rootCmd4.SetOut(&bytes.Buffer{})
-	rootCmd4.SetArgs([]string{"merge", srcID, tgtID, "--json", "--root", tmpDir})
+	rootCmd4.SetArgs([]string{"merge", srcID, tgtID, "--json", "--yes", "--root", tmpDir})
 
 	if err := rootCmd4.Execute(); err != nil {
 		t.Fatalf("merge --json failed: %v", err)
@@ -3446,10 +3448,10 @@ func TestConfigGetCmdValidKey(t *testing.T) {
 	rootCmd.AddCommand(newConfigCmd())
 	var out bytes.Buffer
 	rootCmd.SetOut(&out)
-	rootCmd.SetArgs([]string{"config", "get", "token_budget.default"})
+	rootCmd.SetArgs([]string{"config", "get", "llm.provider"})
 
 	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("config get token_budget.default failed: %v", err)
+		t.Fatalf("config get llm.provider failed: %v", err)
 	}
 }
 
@@ -3461,7 +3463,7 @@ func TestConfigGetCmdValidKeyJSON(t *testing.T) {
 	rootCmd.AddCommand(newConfigCmd())
 	var out bytes.Buffer
 	rootCmd.SetOut(&out)
-	rootCmd.SetArgs([]string{"config", "get", "token_budget.default", "--json"})
+	rootCmd.SetArgs([]string{"config", "get", "llm.provider", "--json"})
 
 	if err := rootCmd.Execute(); err != nil {
 		t.Fatalf("config get --json failed: %v", err)
@@ -3595,10 +3597,7 @@ func TestListCmdLocalNotInitializedR2(t *testing.T) {
 	rootCmd.SetOut(&out)
 	rootCmd.SetArgs([]string{"list", "--local", "--root", tmpDir})
 
-	// Should not error, just print message
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("list --local not init should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
 }
 
 func TestListCmdLocalNotInitializedJSONR2(t *testing.T) {
@@ -3611,9 +3610,7 @@ func TestListCmdLocalNotInitializedJSONR2(t *testing.T) {
 	rootCmd.SetOut(&out)
 	rootCmd.SetArgs([]string{"list", "--local", "--json", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("list --local --json not init should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
 }
 
 func TestListCmdGlobalNotInitializedJSON(t *testing.T) {
@@ -3656,9 +3653,7 @@ func TestActiveCmdNotInitializedR2(t *testing.T) {
 	rootCmd.SetOut(&out)
 	rootCmd.SetArgs([]string{"active", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("active not initialized should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
 }
 
 func TestActiveCmdNotInitializedJSONR2(t *testing.T) {
@@ -3671,9 +3666,7 @@ func TestActiveCmdNotInitializedJSONR2(t *testing.T) {
 	rootCmd.SetOut(&out)
 	rootCmd.SetArgs([]string{"active", "--json", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("active --json not initialized should not error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
 }
 
 func TestActiveCmdWithEnv(t *testing.T) {
@@ -3845,6 +3838,20 @@ func TestSummarizeCmdMissingFlag(t *testing.T) {
 	}
 }
 
+func TestSummarizeCmdLLMRequiresClient(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newSummarizeCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"summarize", "--all", "--llm", "--root", tmpDir})
+
+	err := rootCmd.Execute()
+	if err == nil || !strings.Contains(err.Error(), "--llm requires a configured LLM") {
+		t.Errorf("summarize --llm without LLM error = %v", err)
+	}
+}
+
 func TestSummarizeCmdWithID(t *testing.T) {
 	tmpDir, behaviorID := setupQueryTest(t)
 
@@ -4144,10 +4151,7 @@ func TestShowCmdNotFoundR2(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"show", "nonexistent-id", "--root", tmpDir})
 
-	// Show returns nil and prints error itself
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("show should not return error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 // --- writeConfig test (0% → covered) ---
@@ -4174,9 +4178,7 @@ func TestShowCmdNotFoundJSON(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"show", "nonexistent-id", "--json", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("show --json should not return error: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestShowCmdSuccess(t *testing.T) {
@@ -4216,7 +4218,7 @@ func TestOutputValidationResultsValidJSON(t *testing.T) {
 	defer r.Close()
 	os.Stdout = w
 
-	err := outputValidationResults(nil, store.ScopeLocal, true)
+	err := outputValidationResults(os.Stdout, nil, store.ScopeLocal, true)
 
 	w.Close()
 	os.Stdout = old
@@ -4241,7 +4243,7 @@ func TestOutputValidationResultsWithErrorsJSON(t *testing.T) {
 	errors := []store.ValidationError{
 		{BehaviorID: "b1", Field: "requires", RefID: "b999", Issue: "dangling_reference"},
 	}
-	err := outputValidationResults(errors, store.ScopeGlobal, true)
+	err := outputValidationResults(os.Stdout, errors, store.ScopeGlobal, true)
 
 	w.Close()
 	os.Stdout = old
@@ -4264,7 +4266,7 @@ func TestOutputValidationResultsValidTextR3(t *testing.T) {
 	defer r.Close()
 	os.Stdout = w
 
-	err := outputValidationResults(nil, store.ScopeBoth, false)
+	err := outputValidationResults(os.Stdout, nil, store.ScopeBoth, false)
 
 	w.Close()
 	os.Stdout = old
@@ -4286,7 +4288,7 @@ func TestOutputValidationResultsWithErrorsTextR3(t *testing.T) {
 		{BehaviorID: "b1", Field: "requires", RefID: "b999", Issue: "dangling_reference"},
 		{BehaviorID: "b2", Field: "overrides", RefID: "b1", Issue: "self_reference"},
 	}
-	err := outputValidationResults(errors, store.ScopeLocal, false)
+	err := outputValidationResults(os.Stdout, errors, store.ScopeLocal, false)
 
 	w.Close()
 	os.Stdout = old
@@ -4345,7 +4347,7 @@ func TestRunDedupOnStoreNoBehaviorsR3(t *testing.T) {
 	defer r.Close()
 	os.Stdout = w
 
-	err := runDedupOnStore(context.Background(), s, cfg, nil, false, true)
+	err := runDedupOnStore(context.Background(), os.Stdout, s, cfg, nil, false, true, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -4369,7 +4371,7 @@ func TestRunDedupOnStoreNoBehaviorsText(t *testing.T) {
 	defer devNull.Close()
 	os.Stdout = w
 
-	err := runDedupOnStore(context.Background(), s, cfg, nil, false, false)
+	err := runDedupOnStore(context.Background(), os.Stdout, s, cfg, nil, false, false, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -4408,7 +4410,7 @@ func TestRunDedupOnStoreWithBehaviorsDryRunJSON(t *testing.T) {
 	defer r.Close()
 	os.Stdout = w
 
-	err := runDedupOnStore(ctx, s, cfg, nil, true, true)
+	err := runDedupOnStore(ctx, os.Stdout, s, cfg, nil, true, true, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -4451,7 +4453,7 @@ func TestRunDedupOnStoreWithBehaviorsDryRunText(t *testing.T) {
 	defer devNull.Close()
 	os.Stdout = w
 
-	err := runDedupOnStore(ctx, s, cfg, nil, true, false)
+	err := runDedupOnStore(ctx, os.Stdout, s, cfg, nil, true, false, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -4595,7 +4597,7 @@ func TestOutputJSONR3(t *testing.T) {
 		"b1": {ID: "b1", Name: "test behavior", Kind: models.BehaviorKindDirective, Content: models.BehaviorContent{Canonical: "test content"}},
 	}
 
-	err := outputJSON(cmd, results, behaviorMap, "file change to `*.go`")
+	err := outputJSON(cmd, results, behaviorMap, "file change to `*.go`", false)
 	if err != nil {
 		t.Fatalf("outputJSON failed: %v", err)
 	}
@@ -4800,7 +4802,7 @@ func TestForgetAndRestoreFlowJSON(t *testing.T) {
 	rootCmd := newTestRootCmd()
 	rootCmd.AddCommand(newForgetCmd())
 	rootCmd.SetOut(&bytes.Buffer{})
-	rootCmd.SetArgs([]string{"forget", behaviorID, "--json", "--root", tmpDir})
+	rootCmd.SetArgs([]string{"forget", behaviorID, "--json", "--yes", "--root", tmpDir})
 
 	if err := rootCmd.Execute(); err != nil {
 		t.Fatalf("forget --json failed: %v", err)
@@ -5309,8 +5311,13 @@ func TestOpenStoreForGraphInvalidDir(t *testing.T) {
 		t.Fatalf("CreateTemp: %v", err)
 	}
 	f.Close()
-	_, err = openStoreForGraph(f.Name())
-	if err == nil {
+	// Stores open lazily, so the error surfaces on first use.
+	gs, err := openStoreForGraph(f.Name())
+	if err != nil {
+		t.Fatalf("openStoreForGraph: %v", err)
+	}
+	defer gs.Close()
+	if _, err := gs.QueryNodes(context.Background(), map[string]interface{}{}); err == nil {
 		t.Fatal("expected error for invalid directory")
 	}
 }
@@ -5448,7 +5455,7 @@ func TestMergeCmdWithIntoSwap(t *testing.T) {
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newMergeCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"merge", secondID, behaviorID, "--into", secondID, "--json", "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"merge", secondID, behaviorID, "--into", secondID, "--json", "--yes", "--root", tmpDir})
 
 	if err := rootCmd2.Execute(); err != nil {
 		t.Fatalf("merge with --into swap failed: %v", err)
@@ -5517,14 +5524,14 @@ func TestForgetCmdAlreadyForgottenJSON(t *testing.T) {
 	rootCmd1 := newTestRootCmd()
 	rootCmd1.AddCommand(newForgetCmd())
 	rootCmd1.SetOut(&bytes.Buffer{})
-	rootCmd1.SetArgs([]string{"forget", behaviorID, "--json", "--root", tmpDir})
+	rootCmd1.SetArgs([]string{"forget", behaviorID, "--json", "--yes", "--root", tmpDir})
 	rootCmd1.Execute()
 
 	// Try forget again with JSON
 	rootCmd2 := newTestRootCmd()
 	rootCmd2.AddCommand(newForgetCmd())
 	rootCmd2.SetOut(&bytes.Buffer{})
-	rootCmd2.SetArgs([]string{"forget", behaviorID, "--json", "--root", tmpDir})
+	rootCmd2.SetArgs([]string{"forget", behaviorID, "--json", "--yes", "--root", tmpDir})
 
 	// JSON mode should return nil even for not-active
 	_ = rootCmd2.Execute()
@@ -5725,7 +5732,7 @@ func TestRunSingleStoreDedupLocalScope(t *testing.T) {
 	tmpDir, _ := setupQueryTest(t)
 	cfg := dedup.DeduplicatorConfig{SimilarityThreshold: 0.9}
 
-	err := runSingleStoreDedup(context.Background(), tmpDir, store.ScopeLocal, cfg, nil, true, false)
+	err := runSingleStoreDedup(context.Background(), os.Stdout, tmpDir, store.ScopeLocal, cfg, nil, true, false, false, nil)
 	if err != nil {
 		t.Fatalf("runSingleStoreDedup local failed: %v", err)
 	}
@@ -5735,7 +5742,7 @@ func TestRunSingleStoreDedupInvalidScope(t *testing.T) {
 	tmpDir := t.TempDir()
 	cfg := dedup.DeduplicatorConfig{SimilarityThreshold: 0.9}
 
-	err := runSingleStoreDedup(context.Background(), tmpDir, store.StoreScope("bogus"), cfg, nil, true, false)
+	err := runSingleStoreDedup(context.Background(), os.Stdout, tmpDir, store.StoreScope("bogus"), cfg, nil, true, false, false, nil)
 	if err == nil {
 		t.Fatal("expected error for invalid scope")
 	}
@@ -5745,7 +5752,7 @@ func TestRunSingleStoreDedupInvalidScope(t *testing.T) {
 
 func TestRunSingleStoreValidationLocalDirect(t *testing.T) {
 	tmpDir, _ := setupQueryTest(t)
-	err := runSingleStoreValidation(context.Background(), tmpDir, store.ScopeLocal, false)
+	err := runSingleStoreValidation(context.Background(), os.Stdout, tmpDir, store.ScopeLocal, false)
 	if err != nil {
 		t.Fatalf("runSingleStoreValidation local failed: %v", err)
 	}
@@ -5753,7 +5760,7 @@ func TestRunSingleStoreValidationLocalDirect(t *testing.T) {
 
 func TestRunSingleStoreValidationInvalidScope(t *testing.T) {
 	tmpDir := t.TempDir()
-	err := runSingleStoreValidation(context.Background(), tmpDir, store.StoreScope("bogus"), false)
+	err := runSingleStoreValidation(context.Background(), os.Stdout, tmpDir, store.StoreScope("bogus"), false)
 	if err == nil {
 		t.Fatal("expected error for invalid scope")
 	}
@@ -5794,7 +5801,7 @@ func TestMergeDuplicatePairsWithPairs(t *testing.T) {
 	defer devNull.Close()
 	os.Stdout = w
 
-	count := mergeDuplicatePairs(ctx, s, pairs, nil, false)
+	count := mergeDuplicatePairs(ctx, os.Stdout, s, pairs, nil, false, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -5848,7 +5855,7 @@ func TestMergeDuplicatePairsAlreadyMerged(t *testing.T) {
 	defer devNull.Close()
 	os.Stdout = w
 
-	count := mergeDuplicatePairs(ctx, s, pairs, nil, false)
+	count := mergeDuplicatePairs(ctx, os.Stdout, s, pairs, nil, false, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -5889,7 +5896,7 @@ func TestRunDedupOnStoreMergeMode(t *testing.T) {
 	defer devNull.Close()
 	os.Stdout = w
 
-	err := runDedupOnStore(ctx, s, cfg, nil, false, false)
+	err := runDedupOnStore(ctx, os.Stdout, s, cfg, nil, false, false, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -5926,7 +5933,7 @@ func TestRunDedupOnStoreMergeModeJSON(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := runDedupOnStore(ctx, s, cfg, nil, false, true)
+	err := runDedupOnStore(ctx, os.Stdout, s, cfg, nil, false, true, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -5967,7 +5974,7 @@ func TestRunDedupOnStoreDryRunWithDups(t *testing.T) {
 	r, w, _ := os.Pipe()
 	os.Stdout = w
 
-	err := runDedupOnStore(ctx, s, cfg, nil, true, true)
+	err := runDedupOnStore(ctx, os.Stdout, s, cfg, nil, true, true, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -6011,7 +6018,7 @@ func TestRunDedupOnStoreDryRunWithDupsText(t *testing.T) {
 	defer devNull.Close()
 	os.Stdout = w
 
-	err := runDedupOnStore(ctx, s, cfg, nil, true, false)
+	err := runDedupOnStore(ctx, os.Stdout, s, cfg, nil, true, false, false, nil)
 
 	w.Close()
 	os.Stdout = old
@@ -7249,7 +7256,7 @@ func TestConfigSetTextModeR4(t *testing.T) {
 	out := captureStdout(t, func() {
 		rootCmd := newTestRootCmd()
 		rootCmd.AddCommand(newConfigCmd())
-		rootCmd.SetArgs([]string{"config", "set", "auto_merge_threshold", "0.95"})
+		rootCmd.SetArgs([]string{"config", "set", "deduplication.similarity_threshold", "0.95"})
 		rootCmd.Execute()
 	})
 
@@ -7352,7 +7359,7 @@ func TestMergeDuplicatePairsR4(t *testing.T) {
 		},
 	}
 
-	count := mergeDuplicatePairs(ctx, graphStore, pairs, nil, false)
+	count := mergeDuplicatePairs(ctx, os.Stdout, graphStore, pairs, nil, false, false, nil)
 	if count != 1 {
 		t.Errorf("expected 1 merge, got %d", count)
 	}
@@ -8247,16 +8254,16 @@ func TestLearnCmdScopeLocalExplicitR4(t *testing.T) {
 func TestPackShowCmdR4(t *testing.T) {
 	tmpDir, _ := setupQueryTest(t)
 
-	out := captureStdout(t, func() {
-		rootCmd := newTestRootCmd()
-		rootCmd.AddCommand(newPackCmd())
-		// Pack IDs must match namespace/name pattern — use valid format
-		rootCmd.SetArgs([]string{"pack", "info", "test-org/nonexistent-pack", "--root", tmpDir})
-		rootCmd.Execute()
-	})
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newPackCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	// Pack IDs must match namespace/name pattern — use valid format
+	rootCmd.SetArgs([]string{"pack", "info", "test-org/nonexistent-pack", "--root", tmpDir})
 
-	if !strings.Contains(out, "not found") {
-		t.Errorf("output: %s", out)
+	err := rootCmd.Execute()
+	wantErrorCode(t, err, codeNotFound)
+	if !strings.Contains(err.Error(), "not found") {
+		t.Errorf("error = %v", err)
 	}
 }
 
@@ -1,15 +1,16 @@
 package main
 
 import (
-	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/confidence"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -24,20 +25,18 @@ The behavior is not deleted, just marked with kind "forgotten-behavior".
 Use 'floop restore' to undo this action.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			force, _ := cmd.Flags().GetBool("force")
+			yes, _ := cmd.Flags().GetBool("yes")
+			force = force || yes
 			reason, _ := cmd.Flags().GetString("reason")
 			id := args[0]
 
-			// JSON mode implies force (no interactive prompts)
-			if jsonOut {
-				force = true
-			}
-
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+				return errNotInitialized
 			}
 
 			// Open graph store
@@ -55,27 +54,13 @@ Use 'floop restore' to undo this action.`,
 				return fmt.Errorf("failed to get behavior: %w", err)
 			}
 			if node == nil {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": "behavior not found",
-						"id":    id,
-					})
-					return nil
-				}
-				return fmt.Errorf("behavior not found: %s", id)
+				return newCLIError(codeNotFound, map[string]interface{}{"id": id}, "behavior not found: %s", id)
 			}
 
 			// Verify it's an active behavior
 			if node.Kind != store.NodeKindBehavior {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error":        "not an active behavior",
-						"id":           id,
-						"current_kind": node.Kind,
-					})
-					return nil
-				}
-				return fmt.Errorf("not an active behavior (current kind: %s)", node.Kind)
+				return newCLIError(codeInvalid, map[string]interface{}{"id": id, "current_kind": node.Kind},
+					"not an active behavior (current kind: %s)", node.Kind)
 			}
 
 			// Get behavior name for display
@@ -84,34 +69,22 @@ Use 'floop restore' to undo this action.`,
 				name = n
 			}
 
-			// Confirm unless --force
-			if !force {
-				fmt.Printf("Forget behavior: %s\n", name)
+			// Confirm unless --yes/--force
+			confirmed, err := confirmDestructive(config.OpForget, force, jsonOut, func() {
+				fmt.Fprintf(out, "Forget behavior: %s\n", name)
 				if reason != "" {
-					fmt.Printf("Reason: %s\n", reason)
-				}
-				fmt.Print("\nConfirm? [y/N]: ")
-				reader := bufio.NewReader(os.Stdin)
-				response, _ := reader.ReadString('\n')
-				response = strings.TrimSpace(strings.ToLower(response))
-				if response != "y" && response != "yes" {
-					fmt.Println("Cancelled.")
-					return nil
+					fmt.Fprintf(out, "Reason: %s\n", reason)
 				}
+			})
+			if err != nil {
+				return err
 			}
-
-			// Update node to forgotten state
-			now := time.Now()
-			if node.Metadata == nil {
-				node.Metadata = make(map[string]interface{})
-			}
-			node.Metadata["original_kind"] = node.Kind
-			node.Metadata["forgotten_at"] = now.Format(time.RFC3339)
-			node.Metadata["forgotten_by"] = os.Getenv("USER")
-			if reason != "" {
-				node.Metadata["forget_reason"] = reason
+			if !confirmed {
+				fmt.Fprintln(out, "Cancelled.")
+				return nil
 			}
-			node.Kind = store.NodeKindForgotten
+
+			markForgotten(node, os.Getenv("USER"), reason, time.Now())
 
 			if err := graphStore.UpdateNode(ctx, *node); err != nil {
 				return fmt.Errorf("failed to update behavior: %w", err)
@@ -122,7 +95,7 @@ Use 'floop restore' to undo this action.`,
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"status":     "forgotten",
 					"id":         id,
 					"name":       name,
@@ -130,20 +103,36 @@ Use 'floop restore' to undo this action.`,
 					"restorable": true,
 				})
 			} else {
-				fmt.Printf("Behavior '%s' has been forgotten.\n", name)
-				fmt.Println("Use 'floop restore' to undo this action.")
+				fmt.Fprintf(out, "Behavior '%s' has been forgotten.\n", name)
+				fmt.Fprintln(out, "Use 'floop restore' to undo this action.")
 			}
 
 			return nil
 		},
 	}
 
-	cmd.Flags().Bool("force", false, "Skip confirmation prompt")
+	cmd.Flags().Bool("force", false, "Skip confirmation prompt (same as --yes)")
+	addYesFlag(cmd)
 	cmd.Flags().String("reason", "", "Reason for forgetting")
 
 	return cmd
 }
 
+// markForgotten moves node to the forgotten state, recording who forgot it
+// and why so 'floop restore' can undo it.
+func markForgotten(node *store.Node, by, reason string, now time.Time) {
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata["original_kind"] = node.Kind
+	node.Metadata["forgotten_at"] = now.Format(time.RFC3339)
+	node.Metadata["forgotten_by"] = by
+	if reason != "" {
+		node.Metadata["forget_reason"] = reason
+	}
+	node.Kind = store.NodeKindForgotten
+}
+
 func newDeprecateCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "deprecate <behavior-id>",
@@ -154,6 +143,7 @@ Deprecated behaviors are not active but can be restored.
 Use --replacement to link to a newer behavior.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			reason, _ := cmd.Flags().GetString("reason")
@@ -162,7 +152,7 @@ Use --replacement to link to a newer behavior.`,
 
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+				return errNotInitialized
 			}
 
 			// Reason is required
@@ -185,27 +175,13 @@ Use --replacement to link to a newer behavior.`,
 				return fmt.Errorf("failed to get behavior: %w", err)
 			}
 			if node == nil {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": "behavior not found",
-						"id":    id,
-					})
-					return nil
-				}
-				return fmt.Errorf("behavior not found: %s", id)
+				return newCLIError(codeNotFound, map[string]interface{}{"id": id}, "behavior not found: %s", id)
 			}
 
 			// Verify it's an active behavior
 			if node.Kind != store.NodeKindBehavior {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error":        "not an active behavior",
-						"id":           id,
-						"current_kind": node.Kind,
-					})
-					return nil
-				}
-				return fmt.Errorf("not an active behavior (current kind: %s)", node.Kind)
+				return newCLIError(codeInvalid, map[string]interface{}{"id": id, "current_kind": node.Kind},
+					"not an active behavior (current kind: %s)", node.Kind)
 			}
 
 			// Verify replacement exists if specified
@@ -275,14 +251,14 @@ Use --replacement to link to a newer behavior.`,
 				if replacement != "" {
 					result["replacement"] = replacement
 				}
-				json.NewEncoder(os.Stdout).Encode(result)
+				json.NewEncoder(out).Encode(result)
 			} else {
-				fmt.Printf("Behavior '%s' has been deprecated.\n", name)
-				fmt.Printf("Reason: %s\n", reason)
+				fmt.Fprintf(out, "Behavior '%s' has been deprecated.\n", name)
+				fmt.Fprintf(out, "Reason: %s\n", reason)
 				if replacement != "" {
-					fmt.Printf("Replacement: %s\n", replacement)
+					fmt.Fprintf(out, "Replacement: %s\n", replacement)
 				}
-				fmt.Println("Use 'floop restore' to undo this action.")
+				fmt.Fprintln(out, "Use 'floop restore' to undo this action.")
 			}
 
 			return nil
@@ -297,20 +273,26 @@ Use --replacement to link to a newer behavior.`,
 
 func newRestoreCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "restore <behavior-id>",
-		Short: "Restore a deprecated or forgotten behavior",
-		Long: `Restore a behavior that was previously deprecated or forgotten.
-
-This undoes 'floop forget' or 'floop deprecate'.`,
+		Use:     "restore <behavior-id>",
+		Aliases: []string{"restore-behavior"},
+		Short:   "Restore a deprecated, forgotten, or dormant behavior",
+		Long: `Restore a behavior that was previously deprecated, forgotten, or made
+dormant by expiry.
+
+This undoes 'floop forget', 'floop deprecate', or 'floop maintain expire'.
+A restored dormant behavior no longer expires. 'floop restore-behavior'
+is an alias.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			reason, _ := cmd.Flags().GetString("reason")
 			id := args[0]
 
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+				return errNotInitialized
 			}
 
 			// Open graph store
@@ -328,27 +310,13 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 				return fmt.Errorf("failed to get behavior: %w", err)
 			}
 			if node == nil {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": "behavior not found",
-						"id":    id,
-					})
-					return nil
-				}
-				return fmt.Errorf("behavior not found: %s", id)
-			}
-
-			// Verify it's restorable (deprecated or forgotten)
-			if node.Kind != store.NodeKindDeprecated && node.Kind != store.NodeKindForgotten {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error":        "behavior is not deprecated or forgotten",
-						"id":           id,
-						"current_kind": node.Kind,
-					})
-					return nil
-				}
-				return fmt.Errorf("behavior is not deprecated or forgotten (current kind: %s)", node.Kind)
+				return newCLIError(codeNotFound, map[string]interface{}{"id": id}, "behavior not found: %s", id)
+			}
+
+			// Verify it's restorable (deprecated, forgotten, or dormant)
+			if node.Kind != store.NodeKindDeprecated && node.Kind != store.NodeKindForgotten && node.Kind != store.NodeKindDormant {
+				return newCLIError(codeInvalid, map[string]interface{}{"id": id, "current_kind": node.Kind},
+					"behavior is not deprecated or forgotten, nor dormant (current kind: %s)", node.Kind)
 			}
 
 			// Get behavior name for display
@@ -370,6 +338,11 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 			now := time.Now()
 			node.Metadata["restored_at"] = now.Format(time.RFC3339)
 			node.Metadata["restored_by"] = os.Getenv("USER")
+			if reason != "" {
+				node.Metadata["restore_reason"] = reason
+			} else {
+				delete(node.Metadata, "restore_reason")
+			}
 
 			// Clean up curation metadata
 			delete(node.Metadata, "original_kind")
@@ -380,6 +353,12 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 			delete(node.Metadata, "deprecated_by")
 			delete(node.Metadata, "deprecation_reason")
 			delete(node.Metadata, "replacement_id")
+			delete(node.Metadata, "dormant_at")
+			delete(node.Metadata, "dormant_reason")
+			if previousKind == store.NodeKindDormant {
+				// Otherwise the next expiry pass would make it dormant again
+				delete(node.Metadata, "expires_at")
+			}
 
 			if err := graphStore.UpdateNode(ctx, *node); err != nil {
 				return fmt.Errorf("failed to update behavior: %w", err)
@@ -400,48 +379,64 @@ This undoes 'floop forget' or 'floop deprecate'.`,
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"status":        "restored",
 					"id":            id,
 					"name":          name,
 					"previous_kind": previousKind,
 					"current_kind":  originalKind,
+					"reason":        reason,
 				})
 			} else {
-				fmt.Printf("Behavior '%s' has been restored.\n", name)
+				fmt.Fprintf(out, "Behavior '%s' has been restored.\n", name)
 			}
 
 			return nil
 		},
 	}
 
+	cmd.Flags().String("reason", "", "Reason for restoring")
+
 	return cmd
 }
 
 func newMergeCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "merge <source-id> <target-id>",
-		Short: "Merge two behaviors into one",
+		Short: "Merge two behaviors, or another project's store, into one",
 		Long: `Combine two similar behaviors into one.
 
 The source behavior is marked as merged and linked to the target.
 Use --into to specify which behavior survives (default: target).
 
-This action cannot be undone with restore.`,
-		Args: cobra.ExactArgs(2),
+This action cannot be undone with restore.
+
+Given a single directory, import the behaviors of that project's .floop
+store into this project's local store instead. A behavior similar to one
+already here (--threshold) is merged into it; one whose ID is taken by a
+different behavior is added under a new ID, with its edges and references
+rewritten. Edges are then derived between the imported behaviors and the
+rest. The other store is not modified.
+
+Examples:
+  floop merge behavior-abc123 behavior-def456
+  floop merge ~/src/other-project --dry-run
+  floop merge ../api --threshold 0.8`,
+		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
+			if len(args) == 1 {
+				return runStoreMerge(cmd, root, args[0])
+			}
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			force, _ := cmd.Flags().GetBool("force")
+			yes, _ := cmd.Flags().GetBool("yes")
+			force = force || yes
 			into, _ := cmd.Flags().GetString("into")
 			sourceID := args[0]
 			targetID := args[1]
 
-			// JSON mode implies force (no interactive prompts)
-			if jsonOut {
-				force = true
-			}
-
 			// Handle --into flag to swap source/target
 			if into == sourceID {
 				sourceID, targetID = targetID, sourceID
@@ -451,7 +446,7 @@ This action cannot be undone with restore.`,
 
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+				return errNotInitialized
 			}
 
 			// Open graph store
@@ -498,20 +493,19 @@ This action cannot be undone with restore.`,
 				targetName = n
 			}
 
-			// Confirm unless --force
-			if !force {
-				fmt.Printf("Merge behaviors:\n")
-				fmt.Printf("  Source (will be merged): %s\n", sourceName)
-				fmt.Printf("  Target (will survive):   %s\n", targetName)
-				fmt.Println("\nThis action cannot be undone.")
-				fmt.Print("\nConfirm? [y/N]: ")
-				reader := bufio.NewReader(os.Stdin)
-				response, _ := reader.ReadString('\n')
-				response = strings.TrimSpace(strings.ToLower(response))
-				if response != "y" && response != "yes" {
-					fmt.Println("Cancelled.")
-					return nil
-				}
+			// Confirm unless --yes/--force
+			confirmed, err := confirmDestructive(config.OpMerge, force, jsonOut, func() {
+				fmt.Fprintf(out, "Merge behaviors:\n")
+				fmt.Fprintf(out, "  Source (will be merged): %s\n", sourceName)
+				fmt.Fprintf(out, "  Target (will survive):   %s\n", targetName)
+				fmt.Fprintln(out, "\nThis action cannot be undone.")
+			})
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Fprintln(out, "Cancelled.")
+				return nil
 			}
 
 			now := time.Now()
@@ -533,7 +527,7 @@ This action cannot be undone with restore.`,
 			sourceConf, _ := sourceNode.Metadata["confidence"].(float64)
 			targetConf, _ := targetNode.Metadata["confidence"].(float64)
 			if sourceConf > targetConf {
-				targetNode.Metadata["confidence"] = sourceConf
+				confidence.Adjust(targetNode.Metadata, confidence.Recalibration, sourceConf)
 			}
 
 			// Keep higher priority
@@ -609,7 +603,7 @@ This action cannot be undone with restore.`,
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"status":       "merged",
 					"source_id":    sourceID,
 					"source_name":  sourceName,
@@ -618,16 +612,19 @@ This action cannot be undone with restore.`,
 					"surviving_id": targetID,
 				})
 			} else {
-				fmt.Printf("Behaviors merged successfully.\n")
-				fmt.Printf("  '%s' has been merged into '%s'\n", sourceName, targetName)
+				fmt.Fprintf(out, "Behaviors merged successfully.\n")
+				fmt.Fprintf(out, "  '%s' has been merged into '%s'\n", sourceName, targetName)
 			}
 
 			return nil
 		},
 	}
 
-	cmd.Flags().Bool("force", false, "Skip confirmation prompt")
+	cmd.Flags().Bool("force", false, "Skip confirmation prompt (same as --yes)")
+	addYesFlag(cmd)
 	cmd.Flags().String("into", "", "ID of behavior that should survive (default: second argument)")
+	cmd.Flags().Bool("dry-run", false, "With <other-root>: show what would be imported without writing")
+	cmd.Flags().Float64("threshold", constants.DefaultAutoMergeThreshold, "With <other-root>: similarity at which an imported behavior is merged (0.0-1.0)")
 
 	return cmd
 }
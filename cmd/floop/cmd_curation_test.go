@@ -2,8 +2,12 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"strings"
 	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
 )
 
 func TestForgetCmdNotInitialized(t *testing.T) {
@@ -57,13 +61,24 @@ func TestForgetCmdJSON(t *testing.T) {
 	rootCmd := newTestRootCmd()
 	rootCmd.AddCommand(newForgetCmd())
 	rootCmd.SetOut(&bytes.Buffer{})
-	rootCmd.SetArgs([]string{"forget", behaviorID, "--json", "--root", tmpDir})
+	rootCmd.SetArgs([]string{"forget", behaviorID, "--json", "--yes", "--root", tmpDir})
 
 	if err := rootCmd.Execute(); err != nil {
 		t.Fatalf("forget --json failed: %v", err)
 	}
 }
 
+func TestForgetCmdJSONRequiresYes(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newForgetCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"forget", behaviorID, "--json", "--root", tmpDir})
+
+	wantErrorCode(t, rootCmd.Execute(), codeConfirmationRequired)
+}
+
 func TestForgetCmdWithReason(t *testing.T) {
 	tmpDir, behaviorID := setupQueryTest(t)
 
@@ -307,3 +322,72 @@ func TestMergeCmdInvalidInto(t *testing.T) {
 		t.Errorf("expected '--into must be one of' error, got: %v", err)
 	}
 }
+
+func TestForgottenBehaviorNotRelearned(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	learn := func() map[string]interface{} {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newLearnCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs([]string{
+			"learn", "--wrong", "used print statements", "--right", "use the project logger",
+			"--file", "app.py", "--scope", "local", "--json", "--root", tmpDir,
+		})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("learn failed: %v", err)
+		}
+		var resp map[string]interface{}
+		if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid learn JSON %q: %v", out.String(), err)
+		}
+		return resp
+	}
+
+	first := learn()
+	id := first["behavior"].(map[string]interface{})["id"].(string)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newForgetCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"forget", id, "--yes", "--reason", "noisy", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("forget failed: %v", err)
+	}
+
+	second := learn()
+	if second["status"] != "skipped_forgotten" || second["forgotten_id"] != id {
+		t.Fatalf("relearning a forgotten behavior = %v, want skipped_forgotten for %s", second, id)
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newRestoreCmd())
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"restore-behavior", id, "--reason", "useful after all", "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("restore-behavior failed: %v", err)
+	}
+	var restored map[string]interface{}
+	if err := json.Unmarshal(out.Bytes(), &restored); err != nil {
+		t.Fatalf("invalid restore JSON %q: %v", out.String(), err)
+	}
+	if restored["status"] != "restored" || restored["reason"] != "useful after all" {
+		t.Errorf("restore output = %v", restored)
+	}
+
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer graphStore.Close()
+	node, err := graphStore.GetNode(context.Background(), id)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+	}
+	if node.Kind != store.NodeKindBehavior || node.Metadata["restore_reason"] != "useful after all" {
+		t.Errorf("restored node kind = %s, restore_reason = %v", node.Kind, node.Metadata["restore_reason"])
+	}
+}
@@ -1,50 +1,79 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
-	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/dedup"
 	"github.com/nvandessel/floop/internal/edges"
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func newDeduplicateCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "deduplicate",
-		Short: "Find and merge duplicate behaviors",
+		Use:     "deduplicate",
+		Aliases: []string{"dedup"},
+		Short:   "Find and merge duplicate behaviors",
 		Long: `Find duplicate behaviors and optionally merge them.
 
 This command analyzes all behaviors in the store, identifies duplicates based on
 semantic similarity, and can automatically merge them.
 
+When deduplicating a single store from a terminal, each proposed merge is
+confirmed interactively; --auto merges them all without asking. A merged
+behavior's edges move to the survivor, which records it in merged_from.
+Each answer is saved as a similarity label for 'floop tune-similarity':
+a merge as duplicate, a declined merge as related.
+
 Examples:
   floop deduplicate                  # Find duplicates across both stores (default)
   floop deduplicate --dry-run        # Show what would be merged
   floop deduplicate --threshold 0.8  # Use lower similarity threshold
   floop deduplicate --scope global   # Deduplicate global store only
-  floop deduplicate --scope local    # Deduplicate local store only`,
+  floop deduplicate --scope local    # Deduplicate local store only
+  floop dedup --scope local --auto   # Merge every duplicate without asking
+  floop dedup --llm-confirm          # Merge only pairs the LLM confirms
+  floop deduplicate --scope local --edit  # Review and edit each merge in $EDITOR`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			threshold, _ := cmd.Flags().GetFloat64("threshold")
 			embeddingThreshold, _ := cmd.Flags().GetFloat64("embedding-threshold")
 			scope, _ := cmd.Flags().GetString("scope")
+			edit, _ := cmd.Flags().GetBool("edit")
+			auto, _ := cmd.Flags().GetBool("auto")
+			llmConfirm, _ := cmd.Flags().GetBool("llm-confirm")
 
 			// Validate scope
 			storeScope := store.StoreScope(scope)
 			if !storeScope.Valid() {
 				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scope)
 			}
+			if edit {
+				if jsonOut || dryRun {
+					return fmt.Errorf("--edit cannot be combined with --json or --dry-run")
+				}
+				if storeScope == store.ScopeBoth {
+					return fmt.Errorf("--edit requires --scope local or --scope global")
+				}
+			}
 
 			// Check initialization — for ScopeBoth, degrade gracefully if one store is missing
 			hasLocal := true
@@ -55,7 +84,7 @@ Examples:
 				if _, err := os.Stat(floopDir); err != nil {
 					hasLocal = false
 					if storeScope == store.ScopeLocal {
-						return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+						return errNotInitialized
 					}
 				}
 			}
@@ -91,12 +120,15 @@ Examples:
 			ctx := context.Background()
 
 			// Load config and create LLM client once
-			floopCfg, err := config.Load()
+			floopCfg, err := loadConfig(cmd)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to load config: %v\n", err)
 			}
 			useLLM := floopCfg != nil && floopCfg.LLM.Enabled && floopCfg.LLM.Provider != ""
 			llmClient := createLLMClient(floopCfg)
+			if llmConfirm && (llmClient == nil || !llmClient.Available()) {
+				return fmt.Errorf("--llm-confirm requires a configured LLM (see 'floop config set llm.enabled true')")
+			}
 
 			// Configure deduplication
 			dedupConfig := dedup.DeduplicatorConfig{
@@ -104,16 +136,22 @@ Examples:
 				EmbeddingThreshold:  embeddingThreshold,
 				AutoMerge:           !dryRun,
 				UseLLM:              useLLM,
+				ConfirmWithLLM:      llmConfirm,
 				MaxBatchSize:        100,
 			}
 
 			// Handle cross-store deduplication
 			if storeScope == store.ScopeBoth {
-				return runCrossStoreDedup(ctx, root, dedupConfig, llmClient, dryRun, jsonOut)
+				return runCrossStoreDedup(ctx, out, root, dedupConfig, llmClient, dryRun, jsonOut)
 			}
 
-			// Single store deduplication
-			return runSingleStoreDedup(ctx, root, storeScope, dedupConfig, llmClient, dryRun, jsonOut)
+			// Single store deduplication; propose each merge when run
+			// interactively
+			var review *mergeReview
+			if !auto && !jsonOut && !dryRun && term.IsTerminal(int(os.Stdin.Fd())) {
+				review = &mergeReview{answers: os.Stdin}
+			}
+			return runSingleStoreDedup(ctx, out, root, storeScope, dedupConfig, llmClient, dryRun, jsonOut, edit, review)
 		},
 	}
 
@@ -121,6 +159,9 @@ Examples:
 	cmd.Flags().Float64("threshold", constants.DefaultAutoMergeThreshold, "Similarity threshold for duplicate detection (0.0-1.0)")
 	cmd.Flags().Float64("embedding-threshold", constants.DefaultEmbeddingDedupThreshold, "Cosine similarity threshold for embedding-based duplicate detection (0.0-1.0)")
 	cmd.Flags().String("scope", "both", "Store scope: local, global, or both")
+	cmd.Flags().Bool("edit", false, "Edit each proposed merge in $EDITOR before it is saved")
+	cmd.Flags().Bool("auto", false, "Merge every duplicate without asking")
+	cmd.Flags().Bool("llm-confirm", false, "Merge only pairs the configured LLM confirms are the same behavior")
 
 	return cmd
 }
@@ -132,20 +173,43 @@ type duplicatePair struct {
 	Similarity float64
 }
 
+// mergeReview is an interactive review of proposed merges. Answers are read
+// from answers, and each decision is recorded as a similarity label in
+// labelDir, the store's .floop directory, when it is set.
+type mergeReview struct {
+	answers  io.Reader
+	labelDir string
+}
+
+// label records the reviewed pair as a similarity label. Failures only
+// warn: the merge decision itself stands.
+func (r *mergeReview) label(dup duplicatePair, l similarity.PairLabel) {
+	if r.labelDir == "" {
+		return
+	}
+	entry := similarityLabel{A: dup.BehaviorA.ID, B: dup.BehaviorB.ID, Label: l, LabeledAt: time.Now()}
+	if err := appendSimilarityLabel(r.labelDir, entry); err != nil {
+		slog.Warn("failed to record similarity label", "a", entry.A, "b", entry.B, "error", err)
+	}
+}
+
 // runSingleStoreDedup runs deduplication on a single store.
-func runSingleStoreDedup(ctx context.Context, root string, scope store.StoreScope, cfg dedup.DeduplicatorConfig, llmClient llm.Client, dryRun, jsonOut bool) error {
+func runSingleStoreDedup(ctx context.Context, out io.Writer, root string, scope store.StoreScope, cfg dedup.DeduplicatorConfig, llmClient llm.Client, dryRun, jsonOut, edit bool, review *mergeReview) error {
 	// Open the appropriate store
 	var graphStore store.GraphStore
 	var err error
+	var floopDir string
 
 	switch scope {
 	case store.ScopeLocal:
+		floopDir = store.LocalFloopPath(root)
 		graphStore, err = store.NewSQLiteGraphStore(root)
 	case store.ScopeGlobal:
 		globalPath, pathErr := store.GlobalFloopPath()
 		if pathErr != nil {
 			return fmt.Errorf("failed to get global path: %w", pathErr)
 		}
+		floopDir = globalPath
 		graphStore, err = store.NewSQLiteGraphStore(filepath.Dir(globalPath))
 	default:
 		return fmt.Errorf("runSingleStoreDedup requires local or global scope, got %q", scope)
@@ -156,12 +220,17 @@ func runSingleStoreDedup(ctx context.Context, root string, scope store.StoreScop
 	}
 	defer graphStore.Close()
 
-	return runDedupOnStore(ctx, graphStore, cfg, llmClient, dryRun, jsonOut)
+	if review != nil {
+		review.labelDir = floopDir
+	}
+	return runDedupOnStore(ctx, out, graphStore, cfg, llmClient, dryRun, jsonOut, edit, review)
 }
 
 // runDedupOnStore performs deduplication on the given store.
 // Extracted for testability — accepts a GraphStore directly.
-func runDedupOnStore(ctx context.Context, graphStore store.GraphStore, cfg dedup.DeduplicatorConfig, llmClient llm.Client, dryRun, jsonOut bool) error {
+// If review is set, each proposed merge is confirmed through it first; if
+// edit is set, it is then opened in the user's editor.
+func runDedupOnStore(ctx context.Context, out io.Writer, graphStore store.GraphStore, cfg dedup.DeduplicatorConfig, llmClient llm.Client, dryRun, jsonOut, edit bool, review *mergeReview) error {
 	// Load all behaviors
 	behaviors, err := edges.LoadBehaviorsFromStore(ctx, graphStore)
 	if err != nil {
@@ -170,29 +239,32 @@ func runDedupOnStore(ctx context.Context, graphStore store.GraphStore, cfg dedup
 
 	if len(behaviors) == 0 {
 		if jsonOut {
-			json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			json.NewEncoder(out).Encode(map[string]interface{}{
 				"status":           "no_behaviors",
 				"total_behaviors":  0,
 				"duplicates_found": 0,
 			})
 		} else {
-			fmt.Println("No behaviors found to deduplicate.")
+			fmt.Fprintln(out, "No behaviors found to deduplicate.")
 		}
 		return nil
 	}
 
 	// Find duplicate pairs
 	duplicates := findDuplicatePairs(behaviors, cfg, llmClient)
+	if cfg.ConfirmWithLLM {
+		duplicates = confirmDuplicatePairs(ctx, duplicates, llmClient, jsonOut)
+	}
 
 	if len(duplicates) == 0 {
 		if jsonOut {
-			json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			json.NewEncoder(out).Encode(map[string]interface{}{
 				"status":           "no_duplicates",
 				"total_behaviors":  len(behaviors),
 				"duplicates_found": 0,
 			})
 		} else {
-			fmt.Printf("Analyzed %d behaviors. No duplicates found.\n", len(behaviors))
+			fmt.Fprintf(out, "Analyzed %d behaviors. No duplicates found.\n", len(behaviors))
 		}
 		return nil
 	}
@@ -209,40 +281,40 @@ func runDedupOnStore(ctx context.Context, graphStore store.GraphStore, cfg dedup
 					"similarity": dup.Similarity,
 				})
 			}
-			json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+			json.NewEncoder(out).Encode(map[string]interface{}{
 				"status":           "dry_run",
 				"total_behaviors":  len(behaviors),
 				"duplicates_found": len(duplicates),
 				"duplicates":       pairs,
 			})
 		} else {
-			fmt.Printf("Dry run: Found %d duplicate pairs among %d behaviors.\n\n", len(duplicates), len(behaviors))
+			fmt.Fprintf(out, "Dry run: Found %d duplicate pairs among %d behaviors.\n\n", len(duplicates), len(behaviors))
 			for i, dup := range duplicates {
-				fmt.Printf("%d. Similarity: %.2f\n", i+1, dup.Similarity)
-				fmt.Printf("   A: [%s] %s\n", dup.BehaviorA.ID, dup.BehaviorA.Name)
-				fmt.Printf("   B: [%s] %s\n", dup.BehaviorB.ID, dup.BehaviorB.Name)
-				fmt.Println()
+				fmt.Fprintf(out, "%d. Similarity: %.2f\n", i+1, dup.Similarity)
+				fmt.Fprintf(out, "   A: [%s] %s\n", dup.BehaviorA.ID, dup.BehaviorA.Name)
+				fmt.Fprintf(out, "   B: [%s] %s\n", dup.BehaviorB.ID, dup.BehaviorB.Name)
+				fmt.Fprintln(out)
 			}
 		}
 		return nil
 	}
 
 	// Perform merges
-	mergeCount := mergeDuplicatePairs(ctx, graphStore, duplicates, llmClient, jsonOut)
+	mergeCount := mergeDuplicatePairs(ctx, out, graphStore, duplicates, llmClient, jsonOut, edit, review)
 
 	if err := graphStore.Sync(ctx); err != nil {
 		return fmt.Errorf("failed to sync changes: %w", err)
 	}
 
 	if jsonOut {
-		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+		json.NewEncoder(out).Encode(map[string]interface{}{
 			"status":           "completed",
 			"total_behaviors":  len(behaviors),
 			"duplicates_found": len(duplicates),
 			"merges_performed": mergeCount,
 		})
 	} else {
-		fmt.Printf("\nDeduplication complete: %d merges performed.\n", mergeCount)
+		fmt.Fprintf(out, "\nDeduplication complete: %d merges performed.\n", mergeCount)
 	}
 
 	return nil
@@ -275,22 +347,70 @@ func findDuplicatePairs(behaviors []models.Behavior, cfg dedup.DeduplicatorConfi
 	return duplicates
 }
 
-// mergeDuplicatePairs merges each duplicate pair, updating the store.
-// Returns the number of successful merges.
-func mergeDuplicatePairs(ctx context.Context, graphStore store.GraphStore, duplicates []duplicatePair, llmClient llm.Client, jsonOut bool) int {
+// confirmDuplicatePairs keeps the pairs the LLM confirms are the same
+// behavior. Pairs it can't assess are dropped with a warning.
+func confirmDuplicatePairs(ctx context.Context, duplicates []duplicatePair, llmClient llm.Client, jsonOut bool) []duplicatePair {
+	var confirmed []duplicatePair
+	for _, dup := range duplicates {
+		verdict, err := dedup.ConfirmDuplicate(ctx, llmClient, dup.BehaviorA, dup.BehaviorB)
+		if err != nil {
+			if !jsonOut {
+				fmt.Fprintf(os.Stderr, "Warning: LLM could not compare %s and %s, skipping: %v\n",
+					dup.BehaviorA.ID, dup.BehaviorB.ID, err)
+			}
+			continue
+		}
+		if verdict.MergeCandidate {
+			confirmed = append(confirmed, dup)
+		}
+	}
+	return confirmed
+}
+
+// mergeDuplicatePairs merges each duplicate pair, updating the store. The
+// second behavior of a pair is deleted after its edges are redirected to the
+// survivor, which records it in merged_from.
+// If review is set, each merge is proposed and read from it first: y merges,
+// n skips, a merges this and all remaining pairs, q stops. A merge answered
+// y or a is labeled duplicate, and one answered n, or skipped in the editor,
+// related. If edit is set, the user edits each merged behavior before it is
+// saved and may skip the merge. Returns the number of successful merges.
+func mergeDuplicatePairs(ctx context.Context, out io.Writer, graphStore store.GraphStore, duplicates []duplicatePair, llmClient llm.Client, jsonOut, edit bool, review *mergeReview) int {
 	mergeCount := 0
 	merged := make(map[string]bool)
 
+	var answers *bufio.Reader
+	if review != nil {
+		answers = bufio.NewReader(review.answers)
+	}
+
 	merger := dedup.NewBehaviorMerger(dedup.MergerConfig{
 		UseLLM:    llmClient != nil,
 		LLMClient: llmClient,
 	})
 
-	for _, dup := range duplicates {
+	for i, dup := range duplicates {
 		if merged[dup.BehaviorA.ID] || merged[dup.BehaviorB.ID] {
 			continue
 		}
 
+		reviewed := answers != nil
+		if reviewed {
+			switch proposeMerge(out, answers, dup, i+1, len(duplicates)) {
+			case "n":
+				review.label(dup, similarity.LabelRelated)
+				fmt.Fprintf(out, "Skipped: %s + %s\n", dup.BehaviorA.Name, dup.BehaviorB.Name)
+				continue
+			case "":
+				fmt.Fprintf(out, "Skipped: %s + %s\n", dup.BehaviorA.Name, dup.BehaviorB.Name)
+				continue
+			case "a":
+				answers = nil
+			case "q":
+				return mergeCount
+			}
+		}
+
 		mergedBehavior, err := merger.Merge(ctx, []*models.Behavior{dup.BehaviorA, dup.BehaviorB})
 		if err != nil {
 			if !jsonOut {
@@ -300,8 +420,26 @@ func mergeDuplicatePairs(ctx context.Context, graphStore store.GraphStore, dupli
 			continue
 		}
 
+		adjusted := false
+		if edit {
+			mergedBehavior, adjusted, err = editMergedBehavior(out, mergedBehavior, []*models.Behavior{dup.BehaviorA, dup.BehaviorB})
+			if errors.Is(err, errMergeSkipped) {
+				if reviewed {
+					review.label(dup, similarity.LabelRelated)
+				}
+				fmt.Fprintf(out, "Skipped: %s + %s\n", dup.BehaviorA.Name, dup.BehaviorB.Name)
+				continue
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to edit merge of %s and %s: %v\n",
+					dup.BehaviorA.ID, dup.BehaviorB.ID, err)
+				continue
+			}
+		}
+
 		mergedNode := models.BehaviorToNode(mergedBehavior)
 		mergedNode.ID = dup.BehaviorA.ID
+		mergedNode.Metadata["merged_from"] = mergedFromIDs(ctx, graphStore, dup.BehaviorA.ID, dup.BehaviorB.ID)
 		if err := graphStore.UpdateNode(ctx, mergedNode); err != nil {
 			if !jsonOut {
 				fmt.Fprintf(os.Stderr, "Warning: failed to save merged behavior: %v\n", err)
@@ -309,6 +447,13 @@ func mergeDuplicatePairs(ctx context.Context, graphStore store.GraphStore, dupli
 			continue
 		}
 
+		if err := dedup.RedirectEdges(ctx, graphStore, dup.BehaviorB.ID, dup.BehaviorA.ID); err != nil {
+			if !jsonOut {
+				fmt.Fprintf(os.Stderr, "Warning: failed to redirect edges of %s: %v\n",
+					dup.BehaviorB.ID, err)
+			}
+		}
+
 		if err := graphStore.DeleteNode(ctx, dup.BehaviorB.ID); err != nil {
 			if !jsonOut {
 				fmt.Fprintf(os.Stderr, "Warning: failed to delete merged behavior %s: %v\n",
@@ -318,21 +463,66 @@ func mergeDuplicatePairs(ctx context.Context, graphStore store.GraphStore, dupli
 
 		merged[dup.BehaviorB.ID] = true
 		mergeCount++
+		if reviewed {
+			review.label(dup, similarity.LabelDuplicate)
+		}
 
 		if !jsonOut {
-			fmt.Printf("Merged: %s <- %s (similarity: %.2f)\n",
-				mergedBehavior.Name, dup.BehaviorB.Name, dup.Similarity)
+			note := ""
+			if adjusted {
+				note = ", edited"
+			}
+			fmt.Fprintf(out, "Merged: %s <- %s (similarity: %.2f%s)\n",
+				mergedBehavior.Name, dup.BehaviorB.Name, dup.Similarity, note)
 		}
 	}
 	return mergeCount
 }
 
+// proposeMerge shows a duplicate pair and reads whether to merge it:
+// "y", "n", "a" (all remaining), or "q" (quit). Anything else, including
+// end of input, is "": the pair is skipped without a decision.
+func proposeMerge(out io.Writer, answers *bufio.Reader, dup duplicatePair, n, total int) string {
+	fmt.Fprintf(out, "\n[%d/%d] Similarity: %.2f\n", n, total, dup.Similarity)
+	fmt.Fprintf(out, "  A: [%s] %s\n     %s\n", dup.BehaviorA.ID, dup.BehaviorA.Name, dup.BehaviorA.Content.Canonical)
+	fmt.Fprintf(out, "  B: [%s] %s\n     %s\n", dup.BehaviorB.ID, dup.BehaviorB.Name, dup.BehaviorB.Content.Canonical)
+	fmt.Fprint(out, "Merge B into A? [y/N/a/q]: ")
+
+	response, _ := answers.ReadString('\n')
+	switch response = strings.TrimSpace(strings.ToLower(response)); response {
+	case "y", "yes":
+		return "y"
+	case "a", "all":
+		return "a"
+	case "q", "quit":
+		return "q"
+	case "n", "no":
+		return "n"
+	default:
+		return ""
+	}
+}
+
+// mergedFromIDs returns the lineage the survivor of merging mergedID into
+// survivorID records: both behaviors' earlier merged_from entries, then
+// mergedID.
+func mergedFromIDs(ctx context.Context, graphStore store.GraphStore, survivorID, mergedID string) []interface{} {
+	var ids []interface{}
+	for _, id := range []string{survivorID, mergedID} {
+		if node, err := graphStore.GetNode(ctx, id); err == nil && node != nil {
+			prior, _ := node.Metadata["merged_from"].([]interface{})
+			ids = append(ids, prior...)
+		}
+	}
+	return append(ids, mergedID)
+}
+
 // runCrossStoreDedup runs deduplication across local and global stores.
 // Note: the caller's pre-checks verify that both store directories exist, but not
 // that the DB files are healthy. If a store is corrupted or locked, this function
 // returns a hard error rather than falling back to single-store dedup. This is
 // intentional: cross-store dedup requires both stores to produce correct results.
-func runCrossStoreDedup(ctx context.Context, root string, cfg dedup.DeduplicatorConfig, llmClient llm.Client, dryRun, jsonOut bool) error {
+func runCrossStoreDedup(ctx context.Context, out io.Writer, root string, cfg dedup.DeduplicatorConfig, llmClient llm.Client, dryRun, jsonOut bool) error {
 	// Open local store
 	localStore, err := store.NewSQLiteGraphStore(root)
 	if err != nil {
@@ -384,7 +574,7 @@ func runCrossStoreDedup(ctx context.Context, root string, cfg dedup.Deduplicator
 	}
 
 	if jsonOut {
-		json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+		json.NewEncoder(out).Encode(map[string]interface{}{
 			"status":         "completed",
 			"dry_run":        dryRun,
 			"total_compared": len(results),
@@ -395,21 +585,21 @@ func runCrossStoreDedup(ctx context.Context, root string, cfg dedup.Deduplicator
 		})
 	} else {
 		if dryRun {
-			fmt.Printf("Dry run: Cross-store deduplication analysis\n\n")
+			fmt.Fprintf(out, "Dry run: Cross-store deduplication analysis\n\n")
 		} else {
-			fmt.Printf("Cross-store deduplication complete\n\n")
+			fmt.Fprintf(out, "Cross-store deduplication complete\n\n")
 		}
-		fmt.Printf("Total local behaviors compared: %d\n", len(results))
-		fmt.Printf("  Skipped (same ID in global):  %d\n", skipped)
-		fmt.Printf("  Semantic duplicates found:    %d\n", mergedCount)
-		fmt.Printf("  No duplicate found:           %d\n", none)
+		fmt.Fprintf(out, "Total local behaviors compared: %d\n", len(results))
+		fmt.Fprintf(out, "  Skipped (same ID in global):  %d\n", skipped)
+		fmt.Fprintf(out, "  Semantic duplicates found:    %d\n", mergedCount)
+		fmt.Fprintf(out, "  No duplicate found:           %d\n", none)
 
 		// Show details of duplicates
 		if mergedCount > 0 {
-			fmt.Println("\nDuplicate details:")
+			fmt.Fprintln(out, "\nDuplicate details:")
 			for _, r := range results {
 				if r.Action == "merge" {
-					fmt.Printf("  - Local: %s (%.2f similar to global: %s)\n",
+					fmt.Fprintf(out, "  - Local: %s (%.2f similar to global: %s)\n",
 						r.LocalBehavior.Name, r.Similarity, r.GlobalMatch.Name)
 				}
 			}
@@ -2,11 +2,16 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/nvandessel/floop/internal/dedup"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 func TestNewDeduplicateCmd(t *testing.T) {
@@ -15,7 +20,7 @@ func TestNewDeduplicateCmd(t *testing.T) {
 		t.Errorf("Use = %q, want %q", cmd.Use, "deduplicate")
 	}
 
-	for _, flag := range []string{"dry-run", "threshold", "embedding-threshold", "scope"} {
+	for _, flag := range []string{"dry-run", "threshold", "embedding-threshold", "scope", "edit", "auto", "llm-confirm"} {
 		if cmd.Flags().Lookup(flag) == nil {
 			t.Errorf("missing --%s flag", flag)
 		}
@@ -214,3 +219,215 @@ func TestDeduplicateCmdMerge(t *testing.T) {
 		t.Fatalf("deduplicate failed: %v", err)
 	}
 }
+
+// stubEditor replaces runEditor for the duration of a test.
+func stubEditor(t *testing.T, edit func(path string) error) {
+	t.Helper()
+	orig := runEditor
+	runEditor = edit
+	t.Cleanup(func() { runEditor = orig })
+}
+
+// editDuplicates returns a SQLite store holding two duplicate behaviors
+// and the pair to merge.
+func editDuplicates(t *testing.T) (store.GraphStore, []duplicatePair) {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore: %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	a := &models.Behavior{ID: "b-edit-a", Name: "wrap-errors", Kind: models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "Wrap errors with fmt.Errorf"}, Confidence: 0.6}
+	b := &models.Behavior{ID: "b-edit-b", Name: "wrap-errors-context", Kind: models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "Wrap errors with context"}, Confidence: 0.8}
+	ctx := context.Background()
+	for _, beh := range []*models.Behavior{a, b} {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(beh)); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	return s, []duplicatePair{{BehaviorA: a, BehaviorB: b, Similarity: 0.9}}
+}
+
+func TestMergeDuplicatePairsEdit(t *testing.T) {
+	s, pairs := editDuplicates(t)
+	stubEditor(t, func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !strings.Contains(string(data), "[b-edit-b] wrap-errors-context") {
+			t.Errorf("buffer does not list the merge sources:\n%s", data)
+		}
+		var kept []string
+		for _, line := range strings.Split(string(data), "\n") {
+			if strings.HasPrefix(line, "  canonical:") {
+				line = "  canonical: Wrap errors with fmt.Errorf and %w, adding context"
+			}
+			kept = append(kept, line)
+		}
+		return os.WriteFile(path, []byte(strings.Join(kept, "\n")), 0600)
+	})
+
+	var out bytes.Buffer
+	if n := mergeDuplicatePairs(context.Background(), &out, s, pairs, nil, false, true, nil); n != 1 {
+		t.Fatalf("merges = %d, want 1\n%s", n, out.String())
+	}
+	if !strings.Contains(out.String(), "edited") {
+		t.Errorf("output does not mention the edit: %s", out.String())
+	}
+
+	node, err := s.GetNode(context.Background(), "b-edit-a")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode = %v, %v", node, err)
+	}
+	got := models.NodeToBehavior(*node)
+	if got.Content.Canonical != "Wrap errors with fmt.Errorf and %w, adding context" {
+		t.Errorf("canonical = %q", got.Content.Canonical)
+	}
+	if !got.Provenance.HumanAdjusted || got.Provenance.AdjustedAt == nil {
+		t.Errorf("provenance = %+v, want human-adjusted", got.Provenance)
+	}
+	if gone, _ := s.GetNode(context.Background(), "b-edit-b"); gone != nil {
+		t.Error("merged-away behavior still present")
+	}
+}
+
+func TestMergeDuplicatePairsEditSkip(t *testing.T) {
+	for name, edit := range map[string]func(path string) error{
+		"emptied": func(path string) error { return os.WriteFile(path, nil, 0600) },
+		"invalid unchanged": func(path string) error {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if strings.HasPrefix(string(data), "# ERROR") {
+				return nil // saved again without fixing the error
+			}
+			return os.WriteFile(path, []byte("name: x\nkind: bogus\ncontent:\n  canonical: y\n"), 0600)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			s, pairs := editDuplicates(t)
+			stubEditor(t, edit)
+
+			var out bytes.Buffer
+			if n := mergeDuplicatePairs(context.Background(), &out, s, pairs, nil, false, true, nil); n != 0 {
+				t.Fatalf("merges = %d, want 0", n)
+			}
+			if !strings.Contains(out.String(), "Skipped") {
+				t.Errorf("output = %q, want a skip notice", out.String())
+			}
+			for _, id := range []string{"b-edit-a", "b-edit-b"} {
+				if n, _ := s.GetNode(context.Background(), id); n == nil {
+					t.Errorf("%s removed by a skipped merge", id)
+				}
+			}
+		})
+	}
+}
+
+func TestMergeDuplicatePairsRewiresEdges(t *testing.T) {
+	s, pairs := editDuplicates(t)
+	ctx := context.Background()
+	c := &models.Behavior{ID: "b-edit-c", Name: "error-tests", Kind: models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "Test error paths"}, Confidence: 0.6}
+	if _, err := s.AddNode(ctx, models.BehaviorToNode(c)); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	now := time.Now()
+	for _, e := range []store.Edge{
+		{Source: "b-edit-b", Target: "b-edit-c", Kind: store.EdgeKindSimilarTo, Weight: 0.7, CreatedAt: now},
+		{Source: "b-edit-c", Target: "b-edit-b", Kind: store.EdgeKindRequires, Weight: 1.0, CreatedAt: now},
+		{Source: "b-edit-a", Target: "b-edit-b", Kind: store.EdgeKindSimilarTo, Weight: 0.9, CreatedAt: now},
+	} {
+		if err := s.AddEdge(ctx, e); err != nil {
+			t.Fatalf("AddEdge: %v", err)
+		}
+	}
+
+	var out bytes.Buffer
+	if n := mergeDuplicatePairs(ctx, &out, s, pairs, nil, false, false, &mergeReview{answers: strings.NewReader("y\n")}); n != 1 {
+		t.Fatalf("merges = %d, want 1\n%s", n, out.String())
+	}
+	if !strings.Contains(out.String(), "Merge B into A? [y/N/a/q]") {
+		t.Errorf("merge was not proposed:\n%s", out.String())
+	}
+
+	outbound, _ := s.GetEdges(ctx, "b-edit-a", store.DirectionOutbound, "")
+	inbound, _ := s.GetEdges(ctx, "b-edit-a", store.DirectionInbound, "")
+	if len(outbound) != 1 || outbound[0].Target != "b-edit-c" || outbound[0].Kind != store.EdgeKindSimilarTo {
+		t.Errorf("outbound edges of survivor = %+v, want only similar-to b-edit-c", outbound)
+	}
+	if len(inbound) != 1 || inbound[0].Source != "b-edit-c" || inbound[0].Kind != store.EdgeKindRequires {
+		t.Errorf("inbound edges of survivor = %+v, want only requires from b-edit-c", inbound)
+	}
+
+	node, err := s.GetNode(ctx, "b-edit-a")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode = %v, %v", node, err)
+	}
+	if from, _ := node.Metadata["merged_from"].([]interface{}); len(from) != 1 || from[0] != "b-edit-b" {
+		t.Errorf("merged_from = %v, want [b-edit-b]", node.Metadata["merged_from"])
+	}
+}
+
+func TestMergeDuplicatePairsPrompt(t *testing.T) {
+	for answer, want := range map[string]int{"n\n": 0, "q\n": 0, "": 0, "a\n": 1} {
+		t.Run(strings.TrimSpace(answer), func(t *testing.T) {
+			s, pairs := editDuplicates(t)
+			var out bytes.Buffer
+			if n := mergeDuplicatePairs(context.Background(), &out, s, pairs, nil, false, false, &mergeReview{answers: strings.NewReader(answer)}); n != want {
+				t.Fatalf("merges = %d, want %d\n%s", n, want, out.String())
+			}
+			if gone, _ := s.GetNode(context.Background(), "b-edit-b"); (gone == nil) != (want == 1) {
+				t.Errorf("b-edit-b present = %v after answer %q", gone != nil, answer)
+			}
+		})
+	}
+}
+
+func TestMergeDuplicatePairsLabels(t *testing.T) {
+	for answer, want := range map[string]similarity.PairLabel{"y\n": similarity.LabelDuplicate, "n\n": similarity.LabelRelated, "": ""} {
+		t.Run(strings.TrimSpace(answer), func(t *testing.T) {
+			s, pairs := editDuplicates(t)
+			labelDir := t.TempDir()
+			review := &mergeReview{answers: strings.NewReader(answer), labelDir: labelDir}
+			mergeDuplicatePairs(context.Background(), &bytes.Buffer{}, s, pairs, nil, false, false, review)
+
+			labels, err := readSimilarityLabels(labelDir)
+			if err != nil {
+				t.Fatalf("readSimilarityLabels: %v", err)
+			}
+			if want == "" {
+				if len(labels) != 0 {
+					t.Errorf("labels = %+v, want none without an answer", labels)
+				}
+				return
+			}
+			if len(labels) != 1 || labels[0].A != "b-edit-a" || labels[0].B != "b-edit-b" || labels[0].Label != want {
+				t.Errorf("labels = %+v, want b-edit-a/b-edit-b %s", labels, want)
+			}
+		})
+	}
+}
+
+func TestDeduplicateCmdEditFlagConflicts(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	for _, args := range [][]string{
+		{"--edit", "--json", "--scope", "local"},
+		{"--edit", "--dry-run", "--scope", "local"},
+		{"--edit"},
+	} {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newDeduplicateCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"deduplicate", "--root", tmpDir}, args...))
+		if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "--edit") {
+			t.Errorf("%v: error = %v, want --edit conflict", args, err)
+		}
+	}
+}
@@ -0,0 +1,348 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/ctxcache"
+	"github.com/nvandessel/floop/internal/events"
+	"github.com/nvandessel/floop/internal/hooks"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/project"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+	_ "modernc.org/sqlite"
+)
+
+func newDeinitCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "deinit",
+		Short: "Remove floop from the current project",
+		Long: `Undo 'floop init' for a project.
+
+By default the project's .floop/ directory is deactivated by renaming it to
+.floop.disabled-YYYYMMDD-HHMMSS, so it can be restored by renaming it back.
+Use --purge to delete it instead.
+
+Deinit also removes floop hook entries from the project's .claude/settings.json,
+deletes buffered events recorded for the project in the global event store,
+drops 'floop active' results cached for the project in ~/.floop/cache, and
+removes the project store's similarity tuning from ~/.floop/config.yaml.
+Global behaviors are never touched.
+
+Examples:
+  floop deinit                         # Deactivate .floop/ (rename)
+  floop deinit --keep-backup           # Export a final backup first
+  floop deinit --purge --keep-backup   # Back up, then delete .floop/
+  floop deinit --purge --yes           # Delete without confirmation
+  floop deinit --keep-hooks            # Leave .claude/settings.json alone`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			purge, _ := cmd.Flags().GetBool("purge")
+			keepBackup, _ := cmd.Flags().GetBool("keep-backup")
+			keepHooks, _ := cmd.Flags().GetBool("keep-hooks")
+			force, _ := cmd.Flags().GetBool("force")
+			yes, _ := cmd.Flags().GetBool("yes")
+			force = force || yes
+
+			floopDir := store.LocalFloopPath(root)
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return fmt.Errorf(".floop not initialized in %s", root)
+			}
+
+			if isGlobalFloopDir(floopDir) {
+				return fmt.Errorf("refusing to deinit the global store (%s); run deinit from a project root", floopDir)
+			}
+
+			if purge {
+				confirmed, err := confirmDestructive(config.OpDeinitPurge, force, jsonOut, func() {
+					fmt.Fprintf(out, "Permanently delete %s?\n", floopDir)
+					if !keepBackup {
+						fmt.Fprintln(out, "No backup will be made (use --keep-backup to export one first).")
+					}
+				})
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(out, "Cancelled.")
+					return nil
+				}
+			}
+
+			result, err := deinitProject(context.Background(), root, deinitOptions{
+				Purge:      purge,
+				KeepBackup: keepBackup,
+				KeepHooks:  keepHooks,
+			})
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(result)
+			}
+
+			if result.BackupPath != "" {
+				fmt.Fprintf(out, "Backup created: %s\n", result.BackupPath)
+			}
+			if result.HooksRemoved {
+				fmt.Fprintf(out, "Removed floop hooks from %s\n", result.SettingsPath)
+			}
+			if result.EventsDeleted > 0 {
+				fmt.Fprintf(out, "Deleted %d buffered event(s) for project %s\n", result.EventsDeleted, result.ProjectID)
+			}
+			if result.CacheEntriesDeleted > 0 {
+				fmt.Fprintf(out, "Deleted %d cached result(s) for the project from the global cache\n", result.CacheEntriesDeleted)
+			}
+			if result.TuningRemoved {
+				fmt.Fprintln(out, "Removed the project's similarity tuning from the global config")
+			}
+			if result.Purged {
+				fmt.Fprintf(out, "Deleted %s\n", floopDir)
+			} else {
+				fmt.Fprintf(out, "Deactivated %s -> %s\n", floopDir, result.DisabledPath)
+				fmt.Fprintln(out, "Rename it back to .floop to re-enable.")
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("purge", false, "Delete .floop/ instead of renaming it")
+	cmd.Flags().Bool("keep-backup", false, "Export a final backup to ~/.floop/backups/ before removal")
+	cmd.Flags().Bool("keep-hooks", false, "Leave floop hooks in .claude/settings.json")
+	cmd.Flags().Bool("force", false, "Skip confirmation prompt for --purge (same as --yes)")
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// deinitOptions controls what deinitProject removes.
+type deinitOptions struct {
+	Purge      bool
+	KeepBackup bool
+	KeepHooks  bool
+}
+
+// deinitResult reports what deinitProject did.
+type deinitResult struct {
+	Status        string `json:"status"`
+	FloopDir      string `json:"floop_dir"`
+	Purged        bool   `json:"purged"`
+	DisabledPath  string `json:"disabled_path,omitempty"`
+	BackupPath    string `json:"backup_path,omitempty"`
+	HooksRemoved  bool   `json:"hooks_removed"`
+	SettingsPath  string `json:"settings_path,omitempty"`
+	ProjectID     string `json:"project_id,omitempty"`
+	EventsDeleted int    `json:"events_deleted"`
+
+	CacheEntriesDeleted int  `json:"cache_entries_deleted"`
+	TuningRemoved       bool `json:"tuning_removed"`
+}
+
+// deinitProject removes floop from the project at root. Steps run in an order
+// that keeps the project recoverable: backup first, then hooks, buffered
+// events, and cached state, and finally the .floop directory itself.
+func deinitProject(ctx context.Context, root string, opts deinitOptions) (*deinitResult, error) {
+	floopDir := store.LocalFloopPath(root)
+	result := &deinitResult{
+		Status:   "deinitialized",
+		FloopDir: floopDir,
+	}
+
+	// Resolve project identity before the config disappears.
+	projectID, err := project.ResolveProjectID(root)
+	if err != nil {
+		return nil, fmt.Errorf("resolving project ID: %w", err)
+	}
+	result.ProjectID = projectID
+
+	if opts.KeepBackup {
+		backupPath, err := backupLocalStore(ctx, root)
+		if err != nil {
+			return nil, fmt.Errorf("final backup failed: %w", err)
+		}
+		result.BackupPath = backupPath
+	}
+
+	if !opts.KeepHooks {
+		p := hooks.NewClaudePlatform()
+		existing, err := p.ReadConfig(root)
+		if err != nil {
+			return nil, fmt.Errorf("reading hook settings: %w", err)
+		}
+		if updated, changed := p.RemoveHookConfig(existing); changed {
+			if err := p.WriteConfig(root, updated); err != nil {
+				return nil, fmt.Errorf("removing hooks: %w", err)
+			}
+			result.HooksRemoved = true
+			result.SettingsPath = p.ConfigPath(root)
+		}
+	}
+
+	if projectID != "" {
+		n, err := deleteProjectEvents(ctx, projectID)
+		if err != nil {
+			return nil, fmt.Errorf("clearing project events: %w", err)
+		}
+		result.EventsDeleted = n
+	}
+
+	n, err := clearProjectCaches(root, floopDir)
+	if err != nil {
+		return nil, fmt.Errorf("clearing project caches: %w", err)
+	}
+	result.CacheEntriesDeleted = n
+
+	removed, err := removeProjectTuning(floopDir)
+	if err != nil {
+		return nil, fmt.Errorf("removing similarity tuning: %w", err)
+	}
+	result.TuningRemoved = removed
+
+	if opts.Purge {
+		if err := os.RemoveAll(floopDir); err != nil {
+			return nil, fmt.Errorf("removing %s: %w", floopDir, err)
+		}
+		result.Purged = true
+		return result, nil
+	}
+
+	disabled := fmt.Sprintf("%s.disabled-%s", floopDir, time.Now().Format("20060102-150405"))
+	if err := os.Rename(floopDir, disabled); err != nil {
+		return nil, fmt.Errorf("deactivating %s: %w", floopDir, err)
+	}
+	result.DisabledPath = disabled
+	return result, nil
+}
+
+// backupLocalStore writes a compressed backup of the project-local store to
+// the default backup directory and returns its path.
+func backupLocalStore(ctx context.Context, root string) (string, error) {
+	dir, err := backup.DefaultBackupDir()
+	if err != nil {
+		return "", fmt.Errorf("getting backup directory: %w", err)
+	}
+
+	localStore, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		return "", fmt.Errorf("opening local store: %w", err)
+	}
+	defer localStore.Close()
+
+	outputPath := backup.GenerateBackupPath(dir)
+	if _, err := backup.BackupWithOptions(ctx, localStore, outputPath, backup.BackupOptions{
+		Compress:     true,
+		FloopVersion: version,
+	}); err != nil {
+		return "", err
+	}
+	return outputPath, nil
+}
+
+// deleteProjectEvents removes buffered events for projectID from the global
+// event store. A missing global database is not an error.
+func deleteProjectEvents(ctx context.Context, projectID string) (int, error) {
+	globalDir, err := store.GlobalFloopPath()
+	if err != nil {
+		return 0, err
+	}
+	dbPath := filepath.Join(globalDir, "floop.db")
+	if _, err := os.Stat(dbPath); os.IsNotExist(err) {
+		return 0, nil
+	}
+
+	db, err := sql.Open("sqlite", dbPath+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return 0, fmt.Errorf("opening database: %w", err)
+	}
+	defer db.Close()
+
+	es := events.NewSQLiteEventStore(db)
+	if err := es.InitSchema(ctx); err != nil {
+		return 0, fmt.Errorf("initializing events schema: %w", err)
+	}
+	return es.DeleteByProject(ctx, projectID)
+}
+
+// clearProjectCaches deletes the project's own context cache and the entries
+// in the global 'floop active' cache computed for the project, returning how
+// many global entries were removed. Global-scope results are cached in the
+// global store, keyed by the project's context, so they outlive .floop.
+func clearProjectCaches(root, floopDir string) (int, error) {
+	if err := os.RemoveAll(filepath.Join(floopDir, ctxcache.Dir)); err != nil {
+		return 0, err
+	}
+
+	globalDir, err := store.GlobalFloopPath()
+	if err != nil {
+		return 0, err
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return 0, err
+	}
+	absFloopDir, err := filepath.Abs(floopDir)
+	if err != nil {
+		return 0, err
+	}
+	return ctxcache.New(globalDir, "active").RemoveIf(func(key string) bool {
+		return activeCacheKeyForProject(key, absRoot, absFloopDir)
+	})
+}
+
+// activeCacheKeyForProject reports whether an activeCache key was built for
+// the project at root: its context names root as the repo root, or its
+// fingerprint covers the project's store.
+func activeCacheKeyForProject(key, root, floopDir string) bool {
+	if strings.Contains(key, "\n"+floopDir+string(filepath.Separator)) {
+		return true
+	}
+	_, rest, _ := strings.Cut(key, "\n")
+	ctxJSON, _, _ := strings.Cut(rest, "\n")
+	var ctx models.ContextSnapshot
+	if err := json.Unmarshal([]byte(ctxJSON), &ctx); err != nil || ctx.RepoRoot == "" {
+		return false
+	}
+	return filepath.Clean(ctx.RepoRoot) == filepath.Clean(root)
+}
+
+// removeProjectTuning drops the similarity tuning fitted for the project's
+// store from the global config, reporting whether there was one. Its labels
+// stay in .floop, so a re-enabled project can be tuned again.
+func removeProjectTuning(floopDir string) (bool, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return false, fmt.Errorf("loading config: %w", err)
+	}
+	if !cfg.Similarity.RemoveTuning(floopDir) {
+		return false, nil
+	}
+	if err := saveConfig(cfg); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isGlobalFloopDir reports whether dir resolves to the global ~/.floop directory.
+func isGlobalFloopDir(dir string) bool {
+	globalDir, err := store.GlobalFloopPath()
+	if err != nil {
+		return false
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return false
+	}
+	return filepath.Clean(absDir) == filepath.Clean(globalDir)
+}
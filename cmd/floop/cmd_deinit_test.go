@@ -0,0 +1,224 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/ctxcache"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// setupDeinitTest initializes a project in a temp dir with isolated HOME.
+func setupDeinitTest(t *testing.T) string {
+	t.Helper()
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+	return tmpDir
+}
+
+func TestNewDeinitCmd(t *testing.T) {
+	cmd := newDeinitCmd()
+	if cmd.Use != "deinit" {
+		t.Errorf("Use = %q, want %q", cmd.Use, "deinit")
+	}
+	for _, flag := range []string{"purge", "keep-backup", "keep-hooks", "force"} {
+		if cmd.Flags().Lookup(flag) == nil {
+			t.Errorf("missing --%s flag", flag)
+		}
+	}
+}
+
+func TestDeinitRequiresInit(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newDeinitCmd())
+	rootCmd.SetArgs([]string{"deinit", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected error when .floop does not exist")
+	}
+}
+
+func TestDeinitRenamesByDefault(t *testing.T) {
+	tmpDir := setupDeinitTest(t)
+
+	result, err := deinitProject(context.Background(), tmpDir, deinitOptions{})
+	if err != nil {
+		t.Fatalf("deinitProject: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".floop")); !os.IsNotExist(err) {
+		t.Error("expected .floop to be moved away")
+	}
+	if result.DisabledPath == "" {
+		t.Fatal("expected DisabledPath to be set")
+	}
+	if _, err := os.Stat(filepath.Join(result.DisabledPath, "manifest.yaml")); err != nil {
+		t.Errorf("expected manifest in disabled dir: %v", err)
+	}
+	if result.Purged {
+		t.Error("expected Purged = false")
+	}
+
+	if !result.HooksRemoved {
+		t.Error("expected hooks to be removed")
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".claude", "settings.json"))
+	if err != nil {
+		t.Fatalf("reading settings.json: %v", err)
+	}
+	if strings.Contains(string(data), "floop hook") {
+		t.Error("settings.json still references floop hooks")
+	}
+}
+
+func TestDeinitPurgeWithBackup(t *testing.T) {
+	tmpDir := setupDeinitTest(t)
+
+	result, err := deinitProject(context.Background(), tmpDir, deinitOptions{
+		Purge:      true,
+		KeepBackup: true,
+		KeepHooks:  true,
+	})
+	if err != nil {
+		t.Fatalf("deinitProject: %v", err)
+	}
+
+	if !result.Purged {
+		t.Error("expected Purged = true")
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".floop")); !os.IsNotExist(err) {
+		t.Error("expected .floop to be deleted")
+	}
+	if result.BackupPath == "" {
+		t.Fatal("expected a backup path")
+	}
+	if _, err := os.Stat(result.BackupPath); err != nil {
+		t.Errorf("backup file missing: %v", err)
+	}
+	if result.HooksRemoved {
+		t.Error("expected hooks to be kept with KeepHooks")
+	}
+}
+
+func TestDeinitClearsGlobalCacheAndTuning(t *testing.T) {
+	tmpDir := setupDeinitTest(t)
+	floopDir := filepath.Join(tmpDir, ".floop")
+	globalDir, err := store.GlobalFloopPath()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Global-scope results for this project and for another one.
+	cache := ctxcache.New(globalDir, "active")
+	ownCache, ownKey := activeCache(tmpDir, constants.ScopeGlobal, nil, models.ContextSnapshot{RepoRoot: tmpDir})
+	_, otherKey := activeCache(tmpDir, constants.ScopeGlobal, nil, models.ContextSnapshot{RepoRoot: filepath.Join(tmpDir, "other")})
+	if ownCache == nil {
+		t.Fatal("expected an active cache")
+	}
+	for _, key := range []string{ownKey, otherKey} {
+		if err := cache.Put(key, []string{"b-1"}); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	if err := ctxcache.New(floopDir, "active").Put("local", []string{"b-1"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Similarity.SetTuning(floopDir, similarity.DefaultTuning())
+	cfg.Similarity.SetTuning(globalDir, similarity.DefaultTuning())
+	if err := saveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := deinitProject(context.Background(), tmpDir, deinitOptions{})
+	if err != nil {
+		t.Fatalf("deinitProject: %v", err)
+	}
+
+	if result.CacheEntriesDeleted != 1 {
+		t.Errorf("CacheEntriesDeleted = %d, want 1", result.CacheEntriesDeleted)
+	}
+	var got []string
+	if cache.Get(ownKey, &got) {
+		t.Error("project entry survived in the global cache")
+	}
+	if !cache.Get(otherKey, &got) {
+		t.Error("another project's entry was removed from the global cache")
+	}
+	if _, err := os.Stat(filepath.Join(result.DisabledPath, ctxcache.Dir)); !os.IsNotExist(err) {
+		t.Error("expected the project cache to be cleared")
+	}
+
+	if !result.TuningRemoved {
+		t.Error("expected TuningRemoved = true")
+	}
+	cfg, err = config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := cfg.Similarity.Lookup(floopDir); ok {
+		t.Error("project tuning survived deinit")
+	}
+	if _, ok := cfg.Similarity.Lookup(globalDir); !ok {
+		t.Error("global store tuning was removed")
+	}
+}
+
+func TestDeinitCmdPurgeForce(t *testing.T) {
+	tmpDir := setupDeinitTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newDeinitCmd())
+	rootCmd.SetArgs([]string{"deinit", "--purge", "--force", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("deinit failed: %v", err)
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".floop")); !os.IsNotExist(err) {
+		t.Error("expected .floop to be deleted")
+	}
+}
+
+func TestDeinitRefusesGlobalStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	home := filepath.Join(tmpDir, "home")
+	if err := os.MkdirAll(filepath.Join(home, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newDeinitCmd())
+	rootCmd.SetArgs([]string{"deinit", "--purge", "--force", "--root", home})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("expected deinit of global store to fail")
+	}
+	if _, err := os.Stat(filepath.Join(home, ".floop")); err != nil {
+		t.Error("global .floop must not be removed")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -22,6 +23,9 @@ For each pair of behaviors:
   - If similarity is in [0.5, 0.9): create a similar-to edge (weight 0.8)
   - If one behavior's when-conditions are a strict superset: create an overrides edge (weight 1.0)
 
+Thresholds and weights fitted by 'floop tune-similarity' replace the defaults
+for stores that have been tuned.
+
 Existing edges are preserved unless --clear is used.
 
 Examples:
@@ -30,6 +34,7 @@ Examples:
   floop derive-edges --scope global         # Only process global store
   floop derive-edges --clear                # Remove existing derived edges first`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
@@ -53,7 +58,7 @@ Examples:
 				if _, err := os.Stat(floopDir); err != nil {
 					hasLocal = false
 					if storeScope == store.ScopeLocal {
-						return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+						return errNotInitialized
 					}
 				}
 			}
@@ -89,13 +94,19 @@ Examples:
 				}
 			}
 
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
 			if hasLocal && (storeScope == store.ScopeLocal || storeScope == store.ScopeBoth) {
 				graphStore, err := store.NewSQLiteGraphStore(root)
 				if err != nil {
 					return fmt.Errorf("failed to open local store: %w", err)
 				}
 				defer graphStore.Close()
-				result, err := edges.DeriveEdgesForStore(ctx, graphStore, "local", dryRun, clear)
+				tuning := cfg.Similarity.TuningFor(filepath.Join(root, ".floop"))
+				result, err := edges.DeriveEdgesForStoreWithTuning(ctx, graphStore, "local", dryRun, clear, tuning)
 				if err != nil {
 					return fmt.Errorf("local store: %w", err)
 				}
@@ -108,7 +119,8 @@ Examples:
 					return fmt.Errorf("failed to open global store: %w", err)
 				}
 				defer graphStore.Close()
-				result, err := edges.DeriveEdgesForStore(ctx, graphStore, "global", dryRun, clear)
+				tuning := cfg.Similarity.TuningFor(resolvedGlobalPath)
+				result, err := edges.DeriveEdgesForStoreWithTuning(ctx, graphStore, "global", dryRun, clear, tuning)
 				if err != nil {
 					return fmt.Errorf("global store: %w", err)
 				}
@@ -116,7 +128,7 @@ Examples:
 			}
 
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"dry_run": dryRun,
 					"clear":   clear,
 					"stores":  allResults,
@@ -124,7 +136,7 @@ Examples:
 			}
 
 			for _, r := range allResults {
-				printDeriveResult(r, dryRun)
+				printDeriveResult(out, r, dryRun)
 			}
 			return nil
 		},
@@ -137,20 +149,20 @@ Examples:
 	return cmd
 }
 
-func printDeriveResult(r edges.DeriveResult, dryRun bool) {
+func printDeriveResult(out io.Writer, r edges.DeriveResult, dryRun bool) {
 	if dryRun {
-		fmt.Printf("\n=== %s store (dry run) ===\n", r.Scope)
+		fmt.Fprintf(out, "\n=== %s store (dry run) ===\n", r.Scope)
 	} else {
-		fmt.Printf("\n=== %s store ===\n", r.Scope)
+		fmt.Fprintf(out, "\n=== %s store ===\n", r.Scope)
 	}
-	fmt.Printf("Behaviors: %d\n", r.Behaviors)
+	fmt.Fprintf(out, "Behaviors: %d\n", r.Behaviors)
 
 	if r.ClearedEdges > 0 {
-		fmt.Printf("Cleared edges: %d\n", r.ClearedEdges)
+		fmt.Fprintf(out, "Cleared edges: %d\n", r.ClearedEdges)
 	}
 
 	// Score histogram
-	fmt.Println("\nScore distribution:")
+	fmt.Fprintln(out, "\nScore distribution:")
 	bucketLabels := []string{
 		"[0.0-0.1)", "[0.1-0.2)", "[0.2-0.3)", "[0.3-0.4)", "[0.4-0.5)",
 		"[0.5-0.6)", "[0.6-0.7)", "[0.7-0.8)", "[0.8-0.9)", "[0.9-1.0]",
@@ -163,21 +175,21 @@ func printDeriveResult(r edges.DeriveResult, dryRun bool) {
 					bar += "#"
 				}
 			}
-			fmt.Printf("  %s %s (%d)\n", bucketLabels[i], bar, count)
+			fmt.Fprintf(out, "  %s %s (%d)\n", bucketLabels[i], bar, count)
 		}
 	}
 
 	// Edge proposals
-	fmt.Printf("\nProposed edges: %d\n", len(r.ProposedEdges))
-	fmt.Printf("Skipped (already exist): %d\n", r.SkippedExisting)
+	fmt.Fprintf(out, "\nProposed edges: %d\n", len(r.ProposedEdges))
+	fmt.Fprintf(out, "Skipped (already exist): %d\n", r.SkippedExisting)
 
 	if !dryRun {
-		fmt.Printf("Created edges: %d\n", r.CreatedEdges)
+		fmt.Fprintf(out, "Created edges: %d\n", r.CreatedEdges)
 	}
 
 	// Connectivity
-	fmt.Printf("\nConnectivity:\n")
-	fmt.Printf("  Total nodes: %d\n", r.Connectivity.TotalNodes)
-	fmt.Printf("  Connected: %d\n", r.Connectivity.Connected)
-	fmt.Printf("  Islands (0 edges): %d\n", r.Connectivity.Islands)
+	fmt.Fprintf(out, "\nConnectivity:\n")
+	fmt.Fprintf(out, "  Total nodes: %d\n", r.Connectivity.TotalNodes)
+	fmt.Fprintf(out, "  Connected: %d\n", r.Connectivity.Connected)
+	fmt.Fprintf(out, "  Islands (0 edges): %d\n", r.Connectivity.Islands)
 }
@@ -33,6 +33,7 @@ Examples:
   floop detect-correction --prompt "No, don't use print, use logging instead"
   echo '{"prompt":"Actually, prefer pathlib over os.path"}' | floop detect-correction`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			prompt, _ := cmd.Flags().GetString("prompt")
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
@@ -50,7 +51,7 @@ Examples:
 
 			if prompt == "" {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"detected": false,
 						"reason":   "no prompt provided",
 						"captured": false,
@@ -63,7 +64,7 @@ Examples:
 			capture := learning.NewCorrectionCapture()
 			if !capture.MightBeCorrection(prompt) {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"detected": false,
 						"reason":   "no correction patterns found",
 						"captured": false,
@@ -98,7 +99,7 @@ Examples:
 			if !extracted {
 				// Fallback: pattern detected but couldn't extract details
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"detected": true,
 						"reason":   "correction pattern found but could not extract details",
 						"captured": false,
@@ -115,7 +116,7 @@ Examples:
 			// Dry run - just report what would be captured
 			if dryRun {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"detected":   true,
 						"wrong":      wrong,
 						"right":      right,
@@ -124,10 +125,10 @@ Examples:
 						"dry_run":    true,
 					})
 				} else {
-					fmt.Println("Correction detected (dry run):")
-					fmt.Printf("  Wrong: %s\n", wrong)
-					fmt.Printf("  Right: %s\n", right)
-					fmt.Printf("  Confidence: %.2f\n", confidence)
+					fmt.Fprintln(out, "Correction detected (dry run):")
+					fmt.Fprintf(out, "  Wrong: %s\n", wrong)
+					fmt.Fprintf(out, "  Right: %s\n", right)
+					fmt.Fprintf(out, "  Confidence: %.2f\n", confidence)
 				}
 				return nil
 			}
@@ -135,7 +136,7 @@ Examples:
 			// Skip low confidence corrections
 			if confidence < constants.LowConfidenceThreshold {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"detected":   true,
 						"wrong":      wrong,
 						"right":      right,
@@ -151,7 +152,7 @@ Examples:
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"detected": true,
 						"wrong":    wrong,
 						"right":    right,
@@ -181,7 +182,7 @@ Examples:
 			graphStore, err := store.NewMultiGraphStore(root)
 			if err != nil {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"detected": true,
 						"wrong":    wrong,
 						"right":    right,
@@ -194,13 +195,13 @@ Examples:
 			defer graphStore.Close()
 
 			// Process through learning loop
-			loop := learning.NewLearningLoop(graphStore, nil)
+			loop := learning.NewLearningLoop(graphStore, applyReviewHold(root, withOpLog(root, nil)))
 			ctx := context.Background()
 
 			result, err := loop.ProcessCorrection(ctx, correction)
 			if err != nil {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"detected": true,
 						"wrong":    wrong,
 						"right":    right,
@@ -225,16 +226,22 @@ Examples:
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				resp := map[string]interface{}{
 					"detected":    true,
 					"wrong":       wrong,
 					"right":       right,
 					"confidence":  confidence,
 					"captured":    true,
 					"behavior_id": result.CandidateBehavior.ID,
-				})
+				}
+				if result.SkippedForgotten {
+					resp["forgotten_id"] = result.ForgottenBehaviorID
+				}
+				json.NewEncoder(out).Encode(resp)
+			} else if result.SkippedForgotten {
+				fmt.Fprintf(out, "Correction matches forgotten behavior %s; not learned\n", result.ForgottenBehaviorID)
 			} else {
-				fmt.Printf("Correction captured: %s\n", result.CandidateBehavior.ID)
+				fmt.Fprintf(out, "Correction captured: %s\n", result.CandidateBehavior.ID)
 			}
 
 			return nil
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/pathutil"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <from> [to]",
+		Short: "Compare two stores or backups",
+		Long: `Report how the behaviors in <to> differ from those in <from>: behaviors
+added, removed, or changed (kind, name, content, when, tags), confidence
+drift, and edges added, removed, or reweighted. Usage statistics are not
+compared.
+
+Each side is one of:
+  store   the merged local and global stores (the default for <to>)
+  local   the project store in .floop/
+  global  the global store in ~/.floop/
+  <file>  a backup file of any format; encrypted backups need their
+          passphrase, from $FLOOP_BACKUP_PASSPHRASE or a prompt
+
+Examples:
+  floop diff ~/.floop/backups/floop-backup-20260206-120000.json.gz
+  floop diff local global
+  floop diff old.json.gz new.json.gz --json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			to := "store"
+			if len(args) == 2 {
+				to = args[1]
+			}
+
+			var graphStore *store.MultiGraphStore
+			loadSide := func(spec string) (*backup.BackupFormat, error) {
+				var gs store.GraphStore
+				switch spec {
+				case "store", "local", "global":
+					if graphStore == nil {
+						var err error
+						if graphStore, err = store.NewMultiGraphStore(root); err != nil {
+							return nil, fmt.Errorf("failed to open store: %w", err)
+						}
+					}
+					gs = graphStore
+					if spec == "local" {
+						if !floopDirExists(root) {
+							return nil, errNotInitialized
+						}
+						gs = graphStore.LocalStore()
+					} else if spec == "global" {
+						gs = graphStore.GlobalStore()
+					}
+					snapshot, err := backup.Snapshot(cmd.Context(), gs)
+					if err != nil {
+						return nil, fmt.Errorf("failed to read %s store: %w", spec, err)
+					}
+					return snapshot, nil
+				}
+				return readBackupForDiff(root, spec)
+			}
+			defer func() {
+				if graphStore != nil {
+					graphStore.Close()
+				}
+			}()
+
+			from, err := loadSide(args[0])
+			if err != nil {
+				return err
+			}
+			target, err := loadSide(to)
+			if err != nil {
+				return err
+			}
+			c := backup.Compare(from, target)
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"from":       args[0],
+					"to":         to,
+					"comparison": c,
+				})
+			}
+
+			fmt.Fprintf(out, "Comparing %s -> %s\n", args[0], to)
+			if len(c.Added)+len(c.Removed)+len(c.Changed)+len(c.EdgesAdded)+len(c.EdgesRemoved)+len(c.EdgesChanged) == 0 {
+				fmt.Fprintf(out, "No differences (%d behaviors).\n", c.Unchanged)
+				return nil
+			}
+			if len(c.Added) > 0 {
+				fmt.Fprintf(out, "\nAdded (%d):\n", len(c.Added))
+				for _, b := range c.Added {
+					fmt.Fprintf(out, "  + %s  %s  [%s]  confidence %.2f\n", b.ID, b.Name, b.Kind, b.Confidence)
+				}
+			}
+			if len(c.Removed) > 0 {
+				fmt.Fprintf(out, "\nRemoved (%d):\n", len(c.Removed))
+				for _, b := range c.Removed {
+					fmt.Fprintf(out, "  - %s  %s  [%s]  confidence %.2f\n", b.ID, b.Name, b.Kind, b.Confidence)
+				}
+			}
+			if len(c.Changed) > 0 {
+				fmt.Fprintf(out, "\nChanged (%d):\n", len(c.Changed))
+				for _, ch := range c.Changed {
+					fmt.Fprintf(out, "  ~ %s  %s  (%s)", ch.ID, ch.Name, strings.Join(ch.Fields, ", "))
+					if drift := ch.ConfidenceDrift(); drift != 0 {
+						fmt.Fprintf(out, "  confidence %.2f -> %.2f (%+.2f)", ch.ConfidenceFrom, ch.ConfidenceTo, drift)
+					}
+					fmt.Fprintln(out)
+				}
+			}
+			if n := len(c.EdgesAdded) + len(c.EdgesRemoved) + len(c.EdgesChanged); n > 0 {
+				fmt.Fprintf(out, "\nEdges (%d):\n", n)
+				for _, e := range c.EdgesAdded {
+					fmt.Fprintf(out, "  + %s -%s-> %s\n", e.Source, e.Kind, e.Target)
+				}
+				for _, e := range c.EdgesRemoved {
+					fmt.Fprintf(out, "  - %s -%s-> %s\n", e.Source, e.Kind, e.Target)
+				}
+				for _, e := range c.EdgesChanged {
+					fmt.Fprintf(out, "  ~ %s -%s-> %s  weight %.2f -> %.2f\n", e.Source, e.Kind, e.Target, e.WeightFrom, e.WeightTo)
+				}
+			}
+			fmt.Fprintf(out, "\n%d added, %d removed, %d changed, %d unchanged\n",
+				len(c.Added), len(c.Removed), len(c.Changed), c.Unchanged)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+// readBackupForDiff reads the backup at path for 'floop diff', asking for
+// a passphrase if it is encrypted.
+func readBackupForDiff(root, path string) (*backup.BackupFormat, error) {
+	allowedDirs, err := pathutil.DefaultAllowedBackupDirsWithProjectRoot(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine allowed backup dirs: %w", err)
+	}
+	if err := pathutil.ValidatePath(path, allowedDirs); err != nil {
+		return nil, fmt.Errorf("backup path rejected: %w", err)
+	}
+
+	b, err := backup.ReadFile(path, "")
+	if errors.Is(err, backup.ErrPassphraseRequired) {
+		passphrase, perr := readBackupPassphrase(false)
+		if perr != nil {
+			return nil, perr
+		}
+		b, err = backup.ReadFile(path, passphrase)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return b, nil
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestDiffCmd(t *testing.T) {
+	tmpDir, slogID := setupQueryTest(t)
+	backupPath := backupOutputPath(t, tmpDir, "before.json.gz")
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newBackupCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"backup", "--output", backupPath, "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("backup failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"diff", backupPath, "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("diff failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "No differences") {
+		t.Errorf("diff of a fresh backup = %q, want no differences", out.String())
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newLearnCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"learn", "--wrong", "committed secrets", "--right", "keep secrets in the vault", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+
+	out.Reset()
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"diff", backupPath, "store", "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("diff --json failed: %v", err)
+	}
+	var resp struct {
+		Comparison struct {
+			Added []struct {
+				ID string `json:"id"`
+			} `json:"added"`
+			Removed []interface{} `json:"removed"`
+		} `json:"comparison"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, out.String())
+	}
+	if len(resp.Comparison.Added) != 1 || resp.Comparison.Added[0].ID == slogID || len(resp.Comparison.Removed) != 0 {
+		t.Errorf("comparison = %+v, want one new behavior added", resp.Comparison)
+	}
+
+	out.Reset()
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newDiffCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"diff", "global", "local", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("diff global local failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "Comparing global -> local") {
+		t.Errorf("diff global local = %q", out.String())
+	}
+}
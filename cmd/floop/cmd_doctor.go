@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/conflict"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/doctor"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
+	"github.com/spf13/cobra"
+)
+
+// doctorSection groups the checks run against one store, or against the
+// behavior graph across stores.
+type doctorSection struct {
+	Scope  string         `json:"scope"`
+	Dir    string         `json:"dir,omitempty"`
+	Checks []doctor.Check `json:"checks"`
+}
+
+// doctorDetailLimit caps the details printed per check without --verbose.
+const doctorDetailLimit = 5
+
+func newDoctorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check .floop stores for problems and repair them",
+		Long: `Run health checks against the local and global stores:
+
+  integrity        SQLite integrity_check and foreign_key_check
+  schema           schema version is current
+  jsonl-drift      nodes.jsonl and edges.jsonl match the database
+  orphaned-edges   edges point at behaviors that exist
+  invalid-kinds    node and behavior kinds are ones floop knows
+  zero-confidence  active behaviors have positive confidence
+  vector-index     .floop/vectors matches the stored embeddings
+  conflicts        contradictory behaviors are recorded as conflicts
+
+Checks are read-only unless --fix is given. With --fix, the JSONL files
+are re-exported, orphaned edges removed, invalid behavior kinds reset to
+directive, zero confidence reset to the learned default, the vector index
+brought in line with stored embeddings, outdated schemas migrated, and
+conflicts recorded. A corrupt database is never repaired in place; use
+'floop restore-backup'.
+
+Conflict detection is rule-based: a conflict is reported when one
+behavior prescribes what another forbids, unless their when-conditions
+cannot match the same context. With --llm, topically similar pairs the
+rules miss are also compared by the configured LLM. Recorded conflicts
+take part in activation: when both behaviors match, only one stays
+active, chosen by specificity, then priority, then confidence.
+
+Exits non-zero when a check fails.
+
+Examples:
+  floop doctor                  # Check both stores
+  floop doctor --fix            # Repair fixable problems
+  floop doctor --llm --json     # Include LLM conflict comparison, JSON output`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			scopeStr, _ := cmd.Flags().GetString("scope")
+			fix, _ := cmd.Flags().GetBool("fix")
+			useLLM, _ := cmd.Flags().GetBool("llm")
+
+			scope := constants.Scope(scopeStr)
+			if !scope.Valid() {
+				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scopeStr)
+			}
+			localDir := filepath.Join(root, ".floop")
+			if scope != constants.ScopeGlobal {
+				if _, err := os.Stat(localDir); os.IsNotExist(err) {
+					return errNotInitialized
+				}
+			}
+
+			var llmClient llm.Client
+			if useLLM {
+				floopCfg, err := loadConfig(cmd)
+				if err != nil {
+					return fmt.Errorf("loading config: %w", err)
+				}
+				llmClient = createLLMClient(floopCfg)
+				if llmClient == nil || !llmClient.Available() {
+					return fmt.Errorf("--llm requires a configured LLM (see 'floop config set llm.enabled true')")
+				}
+			}
+
+			ctx := context.Background()
+			var sections []doctorSection
+			healthy := true
+
+			if scope != constants.ScopeGlobal {
+				checks, err := doctor.CheckStore(ctx, localDir, doctor.StoreOptions{Fix: fix})
+				if err != nil {
+					return fmt.Errorf("checking local store: %w", err)
+				}
+				sections = append(sections, doctorSection{Scope: "local", Dir: localDir, Checks: checks})
+				healthy = healthy && storeUsable(checks)
+			}
+			if scope != constants.ScopeLocal {
+				globalDir, err := store.GlobalFloopPath()
+				if err != nil {
+					return fmt.Errorf("failed to get global path: %w", err)
+				}
+				var checks []doctor.Check
+				if _, err := os.Stat(globalDir); os.IsNotExist(err) {
+					checks = []doctor.Check{{Name: "store", Status: doctor.StatusSkip, Message: "global store not initialized"}}
+				} else {
+					checks, err = doctor.CheckStore(ctx, globalDir, doctor.StoreOptions{Fix: fix, CrossStore: true})
+					if err != nil {
+						return fmt.Errorf("checking global store: %w", err)
+					}
+					healthy = healthy && storeUsable(checks)
+				}
+				sections = append(sections, doctorSection{Scope: "global", Dir: globalDir, Checks: checks})
+			}
+
+			graph := doctorSection{Scope: "graph"}
+			if !healthy {
+				graph.Checks = append(graph.Checks, doctor.Check{Name: "graph", Status: doctor.StatusSkip, Message: "graph checks skipped until the stores above are healthy"})
+			} else {
+				graphStore, err := openStoreWithScope(root, scope)
+				if err != nil {
+					return err
+				}
+				defer graphStore.Close()
+
+				if scope != constants.ScopeGlobal {
+					graph.Checks = append(graph.Checks, checkDoctorVectorIndex(ctx, graphStore, filepath.Join(localDir, "vectors"), fix))
+				}
+				c, err := doctor.CheckBehaviorConflicts(ctx, graphStore, conflict.NewDetector(llmClient), fix, time.Now())
+				if err != nil {
+					return err
+				}
+				graph.Checks = append(graph.Checks, c)
+			}
+			sections = append(sections, graph)
+
+			counts, fixable := summarizeDoctor(sections)
+
+			if jsonOut {
+				if err := json.NewEncoder(out).Encode(map[string]interface{}{
+					"sections": sections,
+					"ok":       counts[doctor.StatusOK],
+					"warnings": counts[doctor.StatusWarn],
+					"failures": counts[doctor.StatusFail],
+					"fixable":  fixable,
+					"fix":      fix,
+				}); err != nil {
+					return err
+				}
+			} else {
+				printDoctorReport(out, sections)
+				fmt.Fprintf(out, "%d ok, %d warnings, %d failed\n", counts[doctor.StatusOK], counts[doctor.StatusWarn], counts[doctor.StatusFail])
+				if fixable > 0 && !fix {
+					fmt.Fprintf(out, "%d problems can be repaired: run 'floop doctor --fix'\n", fixable)
+				}
+			}
+
+			if counts[doctor.StatusFail] > 0 {
+				return fmt.Errorf("%d checks failed", counts[doctor.StatusFail])
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("scope", "both", "Store scope: local, global, or both")
+	cmd.Flags().Bool("fix", false, "Repair fixable problems")
+	cmd.Flags().Bool("llm", false, "Also compare similar behaviors with the configured LLM")
+
+	return cmd
+}
+
+// storeUsable reports whether every check on a store ran, so the store can
+// be opened for graph-level checks.
+func storeUsable(checks []doctor.Check) bool {
+	for _, c := range checks {
+		if c.Status == doctor.StatusSkip || c.Status == doctor.StatusFail {
+			return false
+		}
+	}
+	return true
+}
+
+// checkDoctorVectorIndex runs the vector index check when the project has
+// an index on disk.
+func checkDoctorVectorIndex(ctx context.Context, s store.GraphStore, vectorDir string, fix bool) doctor.Check {
+	if _, err := os.Stat(vectorDir); err != nil {
+		return doctor.Check{Name: doctor.CheckVectorIndex, Status: doctor.StatusSkip, Message: "no vector index"}
+	}
+	// Dims only matter when creating a table; an existing one is opened as-is.
+	idx, err := vectorindex.NewLanceDBIndex(vectorindex.LanceDBConfig{Dir: vectorDir, Dims: 768})
+	if err != nil {
+		return doctor.Check{Name: doctor.CheckVectorIndex, Status: doctor.StatusSkip, Message: fmt.Sprintf("vector index skipped: %v", err)}
+	}
+	defer idx.Close()
+
+	c, err := doctor.CheckVectorIndexStaleness(ctx, s, idx, fix)
+	if err != nil {
+		return doctor.Check{Name: doctor.CheckVectorIndex, Status: doctor.StatusFail, Message: err.Error()}
+	}
+	return c
+}
+
+// summarizeDoctor counts checks by status, and the problems left to fix.
+func summarizeDoctor(sections []doctorSection) (map[doctor.Status]int, int) {
+	counts := make(map[doctor.Status]int)
+	fixable := 0
+	for _, s := range sections {
+		for _, c := range s.Checks {
+			counts[c.Status]++
+			if c.Fixable && !c.Fixed {
+				fixable++
+			}
+		}
+	}
+	return counts, fixable
+}
+
+func printDoctorReport(out *output, sections []doctorSection) {
+	markers := map[doctor.Status]string{
+		doctor.StatusOK:   "✓",
+		doctor.StatusWarn: "!",
+		doctor.StatusFail: "✗",
+		doctor.StatusSkip: "-",
+	}
+	for _, s := range sections {
+		if s.Dir != "" {
+			fmt.Fprintf(out, "%s store (%s)\n", s.Scope, s.Dir)
+		} else {
+			fmt.Fprintf(out, "%s\n", s.Scope)
+		}
+		for _, c := range s.Checks {
+			suffix := ""
+			if c.Fixed {
+				suffix = " [fixed]"
+			}
+			fmt.Fprintf(out, "  %s %-16s %s%s\n", markers[c.Status], c.Name, c.Message, suffix)
+
+			details := c.Details
+			if !out.Verbose() && len(details) > doctorDetailLimit {
+				details = details[:doctorDetailLimit]
+			}
+			for _, d := range details {
+				fmt.Fprintf(out, "      %s\n", d)
+			}
+			if len(details) < len(c.Details) {
+				fmt.Fprintf(out, "      ... and %d more (use --verbose)\n", len(c.Details)-len(details))
+			}
+		}
+		fmt.Fprintln(out)
+	}
+}
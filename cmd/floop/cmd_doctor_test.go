@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/doctor"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestDoctorCmd(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	ctx := context.Background()
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range []models.Behavior{
+		{ID: "always-pip", Name: "always-pip", Kind: models.BehaviorKindDirective, Confidence: 0.8, Content: models.BehaviorContent{Canonical: "Always use pip"}},
+		{ID: "never-pip", Name: "never-pip", Kind: models.BehaviorKindDirective, Confidence: 0.8, Content: models.BehaviorContent{Canonical: "Never use pip"}},
+	} {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	out, err := runCmd(t, newDoctorCmd(), "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("doctor failed: %v", err)
+	}
+	var result struct {
+		Sections []struct {
+			Scope  string         `json:"scope"`
+			Checks []doctor.Check `json:"checks"`
+		} `json:"sections"`
+		Warnings int `json:"warnings"`
+		Fixable  int `json:"fixable"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(result.Sections) != 3 || result.Sections[2].Scope != "graph" {
+		t.Fatalf("sections = %+v, want local, global, graph", result.Sections)
+	}
+	var conflicts *doctor.Check
+	for i, c := range result.Sections[2].Checks {
+		if c.Name == doctor.CheckConflicts {
+			conflicts = &result.Sections[2].Checks[i]
+		}
+	}
+	if conflicts == nil || conflicts.Status != doctor.StatusWarn || !conflicts.Fixable || len(conflicts.Details) != 1 {
+		t.Fatalf("conflicts check = %+v, want one unrecorded conflict", conflicts)
+	}
+	if result.Fixable != 1 {
+		t.Errorf("fixable = %d, want 1", result.Fixable)
+	}
+
+	out, err = runCmd(t, newDoctorCmd(), "--scope", "local", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("doctor failed: %v", err)
+	}
+	if !strings.Contains(out, "always-pip") || !strings.Contains(out, "run 'floop doctor --fix'") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	out, err = runCmd(t, newDoctorCmd(), "--fix", "--scope", "local", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("doctor --fix failed: %v", err)
+	}
+	if !strings.Contains(out, "[fixed]") {
+		t.Errorf("unexpected --fix output:\n%s", out)
+	}
+
+	s, err = store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := s.GetNode(ctx, "never-pip")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode = %v, %v", node, err)
+	}
+	if got := models.NodeToBehavior(*node).Conflicts; len(got) != 1 || got[0] != "always-pip" {
+		t.Errorf("never-pip conflicts = %v, want [always-pip]", got)
+	}
+	s.Close()
+
+	out, err = runCmd(t, newDoctorCmd(), "--scope", "local", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("second doctor failed: %v", err)
+	}
+	if !strings.Contains(out, "1 conflicts among 2 behaviors, all recorded") || strings.Contains(out, "--fix") {
+		t.Errorf("unexpected output after fix:\n%s", out)
+	}
+}
+
+func TestDoctorCmd_CorruptStore(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	floopDir := filepath.Join(tmpDir, ".floop")
+	if err := os.MkdirAll(floopDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(floopDir, "floop.db"), []byte("not a database, just some text that is long enough"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCmd(t, newDoctorCmd(), "--scope", "local", "--root", tmpDir)
+	if err == nil || !strings.Contains(err.Error(), "1 checks failed") {
+		t.Errorf("expected failed check error, got %v", err)
+	}
+	if !strings.Contains(out, "restore-backup") || !strings.Contains(out, "graph checks skipped") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestDoctorCmd_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runCmd(t, newDoctorCmd(), "--scope", "bogus", "--root", tmpDir); err == nil {
+		t.Error("expected invalid scope error")
+	}
+	if _, err := runCmd(t, newDoctorCmd(), "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "not initialized") {
+		t.Errorf("expected not initialized error, got %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/eval"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/spf13/cobra"
+)
+
+func newEvalCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "eval",
+		Short: "Measure learning quality against labeled corpora",
+	}
+
+	cmd.AddCommand(newEvalExtractionCmd())
+
+	return cmd
+}
+
+func newEvalExtractionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "extraction",
+		Short: "Score behavior extraction against a labeled corpus",
+		Long: `Run the behavior extractor on a corpus of labeled corrections and score
+how often it produces the expected name, kind, when conditions, and tags.
+
+A corpus is a directory of *.jsonl files, one example per line:
+
+  {"id": "no-force-push",
+   "correction": {"agent_action": "...", "corrected_action": "...", "context": {...}},
+   "expected": {"name": "...", "kind": "constraint", "when": {...}, "tags": ["git"]}}
+
+Only the expected fields present are scored. Tags are scored by F1; the
+other fields by exact match.
+
+Baselines live in <corpus>/baselines/<release>.json. Scores are compared
+against the most recent baseline (or --baseline), and the command fails if
+any field drops by more than --tolerance. Use --record-baseline when cutting
+a release.
+
+Examples:
+  floop eval extraction --corpus corpus/
+  floop eval extraction --corpus corpus/ --verbose
+  floop eval extraction --corpus corpus/ --record-baseline --release v0.9.0`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			corpusDir, _ := cmd.Flags().GetString("corpus")
+			baselinePath, _ := cmd.Flags().GetString("baseline")
+			record, _ := cmd.Flags().GetBool("record-baseline")
+			recordVersion, _ := cmd.Flags().GetString("release")
+			tolerance, _ := cmd.Flags().GetFloat64("tolerance")
+
+			if tolerance < 0 || tolerance > 1 {
+				return fmt.Errorf("--tolerance must be between 0 and 1")
+			}
+
+			examples, err := eval.LoadCorpus(corpusDir)
+			if err != nil {
+				return fmt.Errorf("failed to load corpus: %w", err)
+			}
+
+			report, err := eval.Evaluate(learning.NewBehaviorExtractor(), examples)
+			if err != nil {
+				return fmt.Errorf("evaluation failed: %w", err)
+			}
+
+			var baseline *eval.Baseline
+			if baselinePath != "" {
+				baseline, err = eval.LoadBaseline(baselinePath)
+			} else {
+				baseline, err = eval.LatestBaseline(corpusDir)
+			}
+			if err != nil {
+				return fmt.Errorf("failed to load baseline: %w", err)
+			}
+			var regressions []eval.Regression
+			if baseline != nil {
+				regressions = eval.Compare(baseline, report, tolerance)
+			}
+
+			recordedPath := ""
+			if record {
+				if recordVersion == "" {
+					recordVersion = version
+				}
+				recordedPath, err = eval.SaveBaseline(corpusDir, eval.NewBaseline(recordVersion, report, time.Now()))
+				if err != nil {
+					return err
+				}
+			}
+
+			if jsonOut {
+				result := map[string]interface{}{
+					"examples":    report.Examples,
+					"scores":      report.Scores,
+					"failures":    report.Failures,
+					"regressions": regressions,
+				}
+				if baseline != nil {
+					result["baseline_version"] = baseline.Version
+				}
+				if recordedPath != "" {
+					result["recorded_baseline"] = recordedPath
+				}
+				if err := json.NewEncoder(out).Encode(result); err != nil {
+					return err
+				}
+			} else {
+				fmt.Fprintf(out, "Extraction eval: %d examples\n\n", report.Examples)
+				fmt.Fprintf(out, "  %-6s %8s %10s", "FIELD", "SCORED", "ACCURACY")
+				if baseline != nil {
+					fmt.Fprintf(out, " %10s", baseline.Version)
+				}
+				fmt.Fprintln(out)
+				for _, field := range eval.Fields {
+					score := report.Scores[field]
+					fmt.Fprintf(out, "  %-6s %8d %9.1f%%", field, score.Scored, score.Accuracy*100)
+					if baseline != nil {
+						if base, ok := baseline.Scores[field]; ok && base.Scored > 0 {
+							fmt.Fprintf(out, " %9.1f%%", base.Accuracy*100)
+						}
+					}
+					fmt.Fprintln(out)
+				}
+
+				if out.Verbose() && len(report.Failures) > 0 {
+					fmt.Fprintf(out, "\nMismatches (%d):\n", len(report.Failures))
+					for _, f := range report.Failures {
+						fmt.Fprintf(out, "  %s [%s]: expected %v, got %v\n", f.ExampleID, f.Field, f.Expected, f.Got)
+					}
+				}
+
+				if recordedPath != "" {
+					fmt.Fprintf(out, "\nRecorded baseline %s: %s\n", recordVersion, recordedPath)
+				}
+				if len(regressions) > 0 {
+					fmt.Fprintf(out, "\nRegressions vs %s:\n", baseline.Version)
+					for _, r := range regressions {
+						fmt.Fprintf(out, "  %s: %.1f%% -> %.1f%%\n", r.Field, r.Baseline*100, r.Current*100)
+					}
+				}
+			}
+
+			if len(regressions) > 0 {
+				return fmt.Errorf("extraction quality regressed on %d field(s)", len(regressions))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("corpus", "", "Corpus directory of *.jsonl examples (required)")
+	cmd.Flags().String("baseline", "", "Baseline file to compare against (default: most recent in <corpus>/baselines)")
+	cmd.Flags().Bool("record-baseline", false, "Record these scores as the baseline for --release")
+	cmd.Flags().String("release", "", "Release version for --record-baseline (default: floop version)")
+	cmd.Flags().Float64("tolerance", 0.02, "Allowed accuracy drop per field before failing")
+	_ = cmd.MarkFlagRequired("corpus")
+
+	return cmd
+}
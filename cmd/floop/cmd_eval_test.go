@@ -0,0 +1,69 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/eval"
+)
+
+func writeEvalCorpus(t *testing.T, kind string) string {
+	t.Helper()
+	dir := t.TempDir()
+	line := `{"id":"no-force-push","correction":{"agent_action":"ran git push --force","corrected_action":"never force push to shared branches"},"expected":{"kind":"` + kind + `","when":{}}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "corpus.jsonl"), []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestEvalExtractionCmd(t *testing.T) {
+	corpus := writeEvalCorpus(t, "constraint")
+
+	out, err := runCmd(t, newEvalCmd(), "extraction", "--corpus", corpus, "--record-baseline", "--release", "v1.0.0")
+	if err != nil {
+		t.Fatalf("eval extraction failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Recorded baseline v1.0.0") {
+		t.Errorf("missing baseline confirmation:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(corpus, eval.BaselinesDir, "v1.0.0.json")); err != nil {
+		t.Errorf("baseline not written: %v", err)
+	}
+
+	out, err = runCmd(t, newEvalCmd(), "extraction", "--corpus", corpus, "--json")
+	if err != nil {
+		t.Fatalf("eval extraction --json failed: %v", err)
+	}
+	var result struct {
+		Examples        int                        `json:"examples"`
+		Scores          map[string]eval.FieldScore `json:"scores"`
+		BaselineVersion string                     `json:"baseline_version"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Examples != 1 || result.Scores["kind"].Accuracy != 1 || result.BaselineVersion != "v1.0.0" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestEvalExtractionCmd_Regression(t *testing.T) {
+	corpus := writeEvalCorpus(t, "constraint")
+	if _, err := runCmd(t, newEvalCmd(), "extraction", "--corpus", corpus, "--record-baseline", "--release", "v1.0.0"); err != nil {
+		t.Fatalf("record baseline: %v", err)
+	}
+
+	// Relabel so the extractor now scores worse than the baseline.
+	regressed := writeEvalCorpus(t, "procedure")
+	out, err := runCmd(t, newEvalCmd(), "extraction", "--corpus", regressed, "--baseline", filepath.Join(corpus, eval.BaselinesDir, "v1.0.0.json"))
+	if err == nil {
+		t.Fatalf("expected regression error:\n%s", out)
+	}
+	if !strings.Contains(out, "kind: 100.0% -> 0.0%") {
+		t.Errorf("missing regression detail:\n%s", out)
+	}
+}
@@ -28,11 +28,11 @@ func newEventsCmd() *cobra.Command {
 }
 
 func runEvents(cmd *cobra.Command, args []string) error {
+	out := newOutput(cmd)
 	session, _ := cmd.Flags().GetString("session")
 	pruneStr, _ := cmd.Flags().GetString("prune")
 	countOnly, _ := cmd.Flags().GetBool("count")
 	jsonOut, _ := cmd.Flags().GetBool("json")
-	out := cmd.OutOrStdout()
 
 	// Open global DB
 	homeDir, err := os.UserHomeDir()
@@ -0,0 +1,360 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/assembly"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/export"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/sandbox"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export behaviors for use outside floop",
+	}
+
+	cmd.AddCommand(newExportRAGCmd())
+	cmd.AddCommand(newExportRulesCmd())
+
+	return cmd
+}
+
+func newExportRAGCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rag",
+		Short: "Export behaviors as a RAG corpus",
+		Long: `Export active behaviors as a corpus for external retrieval pipelines.
+
+Each JSONL record holds the behavior text, metadata, tags and, when the
+behavior has been embedded, its embedding vector and model name. Record IDs
+are behavior IDs and stay stable across exports; content_hash covers every
+other field and changes whenever the record does, so external vector
+databases can sync incrementally by upserting records whose hash differs.
+
+Forgotten, deprecated, and merged behaviors are not exported. When both
+stores contain the same behavior ID, the local record wins.
+
+--template renders the records through a Go text/template file instead of
+writing JSONL. The template receives .Records and .Count and runs in a
+sandbox: only string helpers (upper, lower, trim, replace, contains,
+hasPrefix, join, indent, truncate, default, toJSON) are available, and
+execution is capped at 2s and 4 MiB of output. The output file is only
+written once the template has rendered.
+
+Examples:
+  floop export rag --format jsonl > corpus.jsonl
+  floop export rag --scope global -o corpus.jsonl
+  floop export rag --no-embeddings
+  floop export rag --template corpus.md.tmpl -o corpus.md`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			format, _ := cmd.Flags().GetString("format")
+			scope, _ := cmd.Flags().GetString("scope")
+			output, _ := cmd.Flags().GetString("output")
+			noEmbeddings, _ := cmd.Flags().GetBool("no-embeddings")
+			templatePath, _ := cmd.Flags().GetString("template")
+
+			if format != "jsonl" {
+				return fmt.Errorf("unsupported format: %s (supported: jsonl)", format)
+			}
+
+			// Parse the template up front so mistakes fail before any store work
+			var tmpl *sandbox.Template
+			if templatePath != "" {
+				text, err := os.ReadFile(templatePath)
+				if err != nil {
+					return fmt.Errorf("failed to read template: %w", err)
+				}
+				tmpl, err = sandbox.Parse(templatePath, string(text), sandbox.DefaultLimits())
+				if err != nil {
+					return fmt.Errorf("invalid template: %w", err)
+				}
+				format = "template"
+			}
+
+			var scopes []string
+			switch constants.Scope(scope) {
+			case constants.ScopeLocal, constants.ScopeGlobal:
+				scopes = []string{scope}
+			case constants.ScopeBoth:
+				scopes = []string{string(constants.ScopeLocal), string(constants.ScopeGlobal)}
+			default:
+				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scope)
+			}
+
+			var records []export.RAGRecord
+			seen := make(map[string]bool)
+			opened := 0
+			for _, sc := range scopes {
+				floopDir, err := similarityStoreDir(root, sc)
+				if err != nil {
+					// With --scope both, export whichever stores exist
+					if len(scopes) > 1 {
+						continue
+					}
+					return err
+				}
+				opened++
+
+				gs, err := store.NewSQLiteGraphStore(filepath.Dir(floopDir))
+				if err != nil {
+					return fmt.Errorf("failed to open %s store: %w", sc, err)
+				}
+				recs, err := export.BuildRAGRecords(cmd.Context(), gs, export.RAGOptions{
+					Scope:             sc,
+					IncludeEmbeddings: !noEmbeddings,
+				})
+				gs.Close()
+				if err != nil {
+					return err
+				}
+
+				for _, r := range recs {
+					if seen[r.ID] {
+						continue
+					}
+					seen[r.ID] = true
+					records = append(records, r)
+				}
+			}
+			if opened == 0 {
+				return fmt.Errorf("no .floop stores initialized. Run 'floop init' first")
+			}
+
+			// Render fully before touching the output file, so a template
+			// error or timeout leaves an existing file intact.
+			var rendered bytes.Buffer
+			if tmpl != nil {
+				text, err := tmpl.Execute(cmd.Context(), ragTemplateData{Records: records, Count: len(records)})
+				if err != nil {
+					return fmt.Errorf("failed to render template: %w", err)
+				}
+				rendered.Write(text)
+			} else if err := export.WriteRAGJSONL(&rendered, records); err != nil {
+				return err
+			}
+
+			if output == "" {
+				if _, err := out.Write(rendered.Bytes()); err != nil {
+					return fmt.Errorf("failed to write output: %w", err)
+				}
+			} else if err := writeFileAtomic(output, rendered.Bytes()); err != nil {
+				return fmt.Errorf("failed to write output file: %w", err)
+			}
+
+			// Records on stdout are the output; only report when writing a file
+			if output == "" {
+				return nil
+			}
+
+			embedded := 0
+			for _, r := range records {
+				if r.Embedding != nil {
+					embedded++
+				}
+			}
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"path":     output,
+					"count":    len(records),
+					"embedded": embedded,
+					"format":   format,
+				})
+			}
+			fmt.Fprintf(out, "Exported %d behaviors (%d with embeddings) to %s\n", len(records), embedded, output)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("format", "jsonl", "Output format (jsonl)")
+	cmd.Flags().String("scope", "both", "Store scope: local, global, or both")
+	cmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
+	cmd.Flags().Bool("no-embeddings", false, "Omit embedding vectors from records")
+	cmd.Flags().String("template", "", "Render records with a sandboxed Go text/template file instead of JSONL")
+
+	return cmd
+}
+
+// ragTemplateData is the data passed to 'floop export rag --template'.
+type ragTemplateData struct {
+	Records []export.RAGRecord
+	Count   int
+}
+
+func newExportRulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules",
+		Short: "Export top behaviors as an agent rules file",
+		Long: `Export the most relevant behaviors for the project as an agent rules file.
+
+Behaviors active at session start (no file or task, language inferred from
+the project) are ranked by relevance, and the top --top are rendered. The
+agent profile (--profile) filters and weights them.
+
+Without --sync, a complete rules file in --format is written to stdout.
+
+With --sync, the behaviors are written into a section of an existing file,
+between floop markers:
+
+  <!-- floop:begin -->
+  ...
+  <!-- floop:end -->
+
+Only the text between the markers is replaced, so human-authored content
+around them is preserved. A file without markers gets the section appended;
+a missing file is created. Files with unbalanced or repeated markers are
+left untouched and reported as an error.
+
+Examples:
+  floop export rules --format agents-md > AGENTS.md
+  floop export rules --sync CLAUDE.md
+  floop export rules --sync AGENTS.md --top 10`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			format, _ := cmd.Flags().GetString("format")
+			syncPath, _ := cmd.Flags().GetString("sync")
+			top, _ := cmd.Flags().GetInt("top")
+
+			rulesFormat, ok := assembly.ParseRulesFormat(format)
+			if !ok {
+				return fmt.Errorf("invalid --format %q: must be one of %s", format, rulesFormatList())
+			}
+			if top <= 0 {
+				return fmt.Errorf("--top must be positive")
+			}
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			profile, err := agentProfile(cmd, cfg)
+			if err != nil {
+				return err
+			}
+
+			behaviors, err := topSessionBehaviors(root, cfg, profile, top)
+			if err != nil {
+				return err
+			}
+			injected := assembly.FullInjections(behaviors)
+
+			if syncPath == "" {
+				fmt.Fprint(out, assembly.CompileRules(rulesFormat, injected))
+				return nil
+			}
+
+			existing, err := os.ReadFile(syncPath)
+			if err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to read %s: %w", syncPath, err)
+			}
+			status := "updated"
+			if os.IsNotExist(err) {
+				status = "created"
+			}
+			updated, err := export.SpliceRules(string(existing), assembly.CompileRulesSection(injected))
+			if err != nil {
+				return fmt.Errorf("cannot sync %s: %w", syncPath, err)
+			}
+			if status == "updated" && updated == string(existing) {
+				status = "unchanged"
+			} else if err := os.WriteFile(syncPath, []byte(updated), 0644); err != nil {
+				return fmt.Errorf("failed to write %s: %w", syncPath, err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"path":   syncPath,
+					"status": status,
+					"count":  len(behaviors),
+				})
+			}
+			switch status {
+			case "unchanged":
+				fmt.Fprintf(out, "%s is up to date (%d behaviors)\n", syncPath, len(behaviors))
+			case "created":
+				fmt.Fprintf(out, "Created %s with %d behaviors\n", syncPath, len(behaviors))
+			default:
+				fmt.Fprintf(out, "Updated floop section of %s (%d behaviors)\n", syncPath, len(behaviors))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("format", string(assembly.RulesClaudeMD), "Rules file format without --sync ("+rulesFormatList()+")")
+	cmd.Flags().String("sync", "", "Update the floop section of this file instead of printing a complete rules file")
+	cmd.Flags().Int("top", 20, "Number of behaviors to export")
+
+	return cmd
+}
+
+// topSessionBehaviors returns up to n behaviors active at session start,
+// most relevant first.
+func topSessionBehaviors(root string, cfg *config.FloopConfig, profile config.ProfileConfig, n int) ([]models.Behavior, error) {
+	behaviors, err := loadBehaviorsWithScope(root, constants.ScopeBoth)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load behaviors: %w", err)
+	}
+
+	ctxBuilder := activation.NewContextBuilder().WithRepoRoot(root)
+	if lang := projectTypeToLanguage(models.InferProjectType(root)); lang != "" {
+		ctxBuilder.WithLanguage(lang)
+	}
+	actCtx := ctxBuilder.Build()
+
+	matches := activation.NewEvaluator().Evaluate(actCtx, profile.Filter(behaviors))
+	resolved := activation.NewResolver().Resolve(matches)
+
+	scored := ranking.NewRelevanceScorer(scorerConfig(root, cfg, profile)).ScoreBatch(resolved.Active, &actCtx)
+	sort.SliceStable(scored, func(i, j int) bool {
+		return scored[i].Score > scored[j].Score
+	})
+	top := make([]models.Behavior, 0, min(n, len(scored)))
+	for _, s := range scored[:min(n, len(scored))] {
+		top = append(top, *s.Behavior)
+	}
+	return top, nil
+}
+
+// writeFileAtomic replaces path with data through a temp file in the same
+// directory, so a failed write never leaves path truncated.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	_, err = tmp.Write(data)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/export"
+)
+
+func decodeRAGRecords(t *testing.T, data []byte) []export.RAGRecord {
+	t.Helper()
+	var records []export.RAGRecord
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var rec export.RAGRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("invalid JSONL line %q: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
+
+func TestExportRAGStdout(t *testing.T) {
+	tmpDir := setupTuneSimilarityTest(t)
+
+	out, err := runCmd(t, newExportCmd(), "rag", "--scope", "local", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("export rag: %v", err)
+	}
+
+	records := decodeRAGRecords(t, []byte(out))
+	if len(records) != 4 {
+		t.Fatalf("got %d records, want 4", len(records))
+	}
+	for _, r := range records {
+		if r.Scope != "local" || r.Text == "" || r.ContentHash == "" {
+			t.Errorf("incomplete record: %+v", r)
+		}
+	}
+
+	// A second export yields identical IDs and hashes for incremental sync.
+	again, err := runCmd(t, newExportCmd(), "rag", "--scope", "local", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("second export: %v", err)
+	}
+	for i, r := range decodeRAGRecords(t, []byte(again)) {
+		if r.ID != records[i].ID || r.ContentHash != records[i].ContentHash {
+			t.Errorf("record %d not stable across exports", i)
+		}
+	}
+}
+
+func TestExportRAGToFile(t *testing.T) {
+	tmpDir := setupTuneSimilarityTest(t)
+	outPath := filepath.Join(tmpDir, "corpus.jsonl")
+
+	out, err := runCmd(t, newExportCmd(), "rag", "-o", outPath, "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("export rag: %v", err)
+	}
+
+	var summary map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &summary); err != nil {
+		t.Fatalf("summary is not JSON: %v (%q)", err, out)
+	}
+	if summary["count"] != float64(4) {
+		t.Errorf("count = %v, want 4", summary["count"])
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if got := len(decodeRAGRecords(t, data)); got != 4 {
+		t.Errorf("file has %d records, want 4", got)
+	}
+}
+
+func TestExportRAGTemplate(t *testing.T) {
+	tmpDir := setupTuneSimilarityTest(t)
+
+	tmplPath := filepath.Join(tmpDir, "corpus.tmpl")
+	if err := os.WriteFile(tmplPath, []byte("{{.Count}} behaviors\n{{range .Records}}- {{upper .Kind}}: {{truncate 20 .Text}}\n{{end}}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	out, err := runCmd(t, newExportCmd(), "rag", "--scope", "local", "--template", tmplPath, "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("export rag --template: %v", err)
+	}
+	if !strings.HasPrefix(out, "4 behaviors\n- ") || strings.Count(out, "\n- ") != 4 {
+		t.Errorf("unexpected template output:\n%s", out)
+	}
+
+	badPath := filepath.Join(tmpDir, "bad.tmpl")
+	if err := os.WriteFile(badPath, []byte("ok\n{{ readFile \"/etc/passwd\" }}"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	_, err = runCmd(t, newExportCmd(), "rag", "--template", badPath, "--root", tmpDir)
+	if err == nil || !strings.Contains(err.Error(), "bad.tmpl:2") {
+		t.Errorf("expected error pointing at line 2, got %v", err)
+	}
+}
+
+func TestExportRAGTemplateErrorKeepsOutput(t *testing.T) {
+	tmpDir := setupTuneSimilarityTest(t)
+	outPath := filepath.Join(tmpDir, "corpus.txt")
+	if err := os.WriteFile(outPath, []byte("previous export"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tmplPath := filepath.Join(tmpDir, "huge.tmpl")
+	if err := os.WriteFile(tmplPath, []byte(`{{indent 2000000000 "x"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCmd(t, newExportCmd(), "rag", "--template", tmplPath, "-o", outPath, "--root", tmpDir); err == nil {
+		t.Fatal("expected the template to exceed the output limit")
+	}
+
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if string(data) != "previous export" {
+		t.Errorf("failed render changed the output file to %q", data)
+	}
+}
+
+func TestExportRAGInvalidFlags(t *testing.T) {
+	tmpDir := setupTuneSimilarityTest(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"unsupported format", []string{"--format", "parquet"}},
+		{"invalid scope", []string{"--scope", "team"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := runCmd(t, newExportCmd(), append(append([]string{"rag"}, tt.args...), "--root", tmpDir)...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestExportRulesSync(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	out, err := runCmd(t, newExportCmd(), "rules", "--format", "agents-md", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("export rules failed: %v", err)
+	}
+	if !strings.HasPrefix(out, "# AGENTS.md") || !strings.Contains(out, "slog structured logging") {
+		t.Errorf("unexpected rules file:\n%s", out)
+	}
+
+	path := filepath.Join(tmpDir, "CLAUDE.md")
+	human := "# Project\n\nRun make before committing.\n"
+	if err := os.WriteFile(path, []byte(human), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = runCmd(t, newExportCmd(), "rules", "--sync", path, "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("export rules --sync failed: %v", err)
+	}
+	if !strings.Contains(out, "Updated floop section") {
+		t.Errorf("unexpected sync output: %s", out)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	synced := string(data)
+	if !strings.HasPrefix(synced, human) || !strings.Contains(synced, export.RulesBeginMarker) ||
+		!strings.Contains(synced, "slog structured logging") {
+		t.Errorf("unexpected synced file:\n%s", synced)
+	}
+
+	out, err = runCmd(t, newExportCmd(), "rules", "--sync", path, "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("second sync failed: %v", err)
+	}
+	var result struct {
+		Status string `json:"status"`
+		Count  int    `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if result.Status != "unchanged" || result.Count != 1 {
+		t.Errorf("second sync = %+v, want unchanged with 1 behavior", result)
+	}
+
+	broken := filepath.Join(tmpDir, "AGENTS.md")
+	if err := os.WriteFile(broken, []byte(export.RulesBeginMarker+"\nno end\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := runCmd(t, newExportCmd(), "rules", "--sync", broken, "--root", tmpDir); err == nil {
+		t.Error("sync into a file with an unbalanced marker should fail")
+	}
+}
@@ -0,0 +1,272 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/session"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// Feedback signals accepted by 'floop feedback'.
+const (
+	feedbackFollowed   = "followed"
+	feedbackConfirmed  = "confirmed"
+	feedbackOverridden = "overridden"
+)
+
+// feedbackLogFile is the file in .floop/ that logs feedback with its notes.
+const feedbackLogFile = "feedback.jsonl"
+
+// feedbackEntry is one feedback signal, as given in --batch input and as
+// logged to feedback.jsonl.
+type feedbackEntry struct {
+	BehaviorID string    `json:"behavior_id"`
+	Signal     string    `json:"signal"`
+	Note       string    `json:"note,omitempty"`
+	Timestamp  time.Time `json:"timestamp,omitempty"`
+}
+
+// feedbackFailure explains why an entry of a batch was not recorded.
+type feedbackFailure struct {
+	Index      int    `json:"index"`
+	BehaviorID string `json:"behavior_id,omitempty"`
+	Error      string `json:"error"`
+}
+
+// feedbackRecorder is implemented by stores that track feedback counters.
+type feedbackRecorder interface {
+	RecordFollowed(ctx context.Context, behaviorID string) error
+	RecordConfirmed(ctx context.Context, behaviorID string) error
+	RecordOverridden(ctx context.Context, behaviorID string) error
+}
+
+func newFeedbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "feedback [behavior-id]",
+		Short: "Record whether a behavior was followed, confirmed, or overridden",
+		Long: `Record feedback on a behavior from the command line.
+
+Each signal increments a counter in the behavior's stats:
+  --followed    the behavior was applied (times_followed)
+  --confirmed   the behavior was explicitly endorsed (times_confirmed)
+  --overridden  the behavior was contradicted (times_overridden)
+
+Relevance scoring and re-injection use these counters: followed and
+confirmed behaviors rank higher, and constraints that are often overridden
+are re-injected sooner. Feedback is also logged, with any --note, to
+.floop/feedback.jsonl and counted against the tracked session if one is
+active.
+
+With --batch, feedback is read from a file (or - for stdin) holding a JSON
+array or JSON Lines of {"behavior_id": ..., "signal": ..., "note": ...}.
+Entries that fail are reported and the rest are still recorded.
+
+Examples:
+  floop feedback b-1a2b3c --followed
+  floop feedback b-1a2b3c --overridden --note "generated code needs panics here"
+  floop feedback --batch review-feedback.json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			batch, _ := cmd.Flags().GetString("batch")
+			note, _ := cmd.Flags().GetString("note")
+
+			var entries []feedbackEntry
+			if batch != "" {
+				if len(args) > 0 {
+					return fmt.Errorf("--batch cannot be combined with a behavior ID")
+				}
+				var err error
+				if entries, err = readFeedbackBatch(cmd, batch); err != nil {
+					return err
+				}
+			} else {
+				if len(args) == 0 {
+					return fmt.Errorf("a behavior ID (or --batch) is required")
+				}
+				signal, err := feedbackSignalFlag(cmd)
+				if err != nil {
+					return err
+				}
+				entries = []feedbackEntry{{BehaviorID: args[0], Signal: signal, Note: note}}
+			}
+
+			graphStore, err := openSessionStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			recorded, failures, err := recordFeedback(cmd.Context(), graphStore, root, entries)
+			if err != nil {
+				return err
+			}
+
+			if batch == "" {
+				if len(failures) > 0 {
+					return errors.New(failures[0].Error)
+				}
+				e := recorded[0]
+				if jsonOut {
+					return json.NewEncoder(out).Encode(map[string]interface{}{
+						"status":      "recorded",
+						"behavior_id": e.BehaviorID,
+						"signal":      e.Signal,
+						"note":        e.Note,
+					})
+				}
+				fmt.Fprintf(out, "Recorded %s feedback for %s\n", e.Signal, e.BehaviorID)
+				return nil
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status":   "recorded",
+					"recorded": len(recorded),
+					"failed":   failures,
+				})
+			}
+			fmt.Fprintf(out, "Recorded %d of %d feedback entries\n", len(recorded), len(entries))
+			for _, f := range failures {
+				fmt.Fprintf(out, "  entry %d (%s): %s\n", f.Index, f.BehaviorID, f.Error)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool(feedbackFollowed, false, "The behavior was applied")
+	cmd.Flags().Bool(feedbackConfirmed, false, "The behavior was explicitly endorsed")
+	cmd.Flags().Bool(feedbackOverridden, false, "The behavior was contradicted")
+	cmd.MarkFlagsMutuallyExclusive(feedbackFollowed, feedbackConfirmed, feedbackOverridden)
+	cmd.Flags().String("note", "", "Why the feedback was given (logged, not scored)")
+	cmd.Flags().String("batch", "", "Read feedback entries from a JSON file (- for stdin)")
+	cmd.MarkFlagsMutuallyExclusive("batch", feedbackFollowed)
+	cmd.MarkFlagsMutuallyExclusive("batch", feedbackConfirmed)
+	cmd.MarkFlagsMutuallyExclusive("batch", feedbackOverridden)
+	cmd.MarkFlagsMutuallyExclusive("batch", "note")
+
+	return cmd
+}
+
+// feedbackSignalFlag returns the signal chosen by --followed, --confirmed,
+// or --overridden.
+func feedbackSignalFlag(cmd *cobra.Command) (string, error) {
+	for _, signal := range []string{feedbackFollowed, feedbackConfirmed, feedbackOverridden} {
+		if set, _ := cmd.Flags().GetBool(signal); set {
+			return signal, nil
+		}
+	}
+	return "", fmt.Errorf("one of --followed, --confirmed, or --overridden is required")
+}
+
+// readFeedbackBatch reads feedback entries from path (- for stdin) as a
+// JSON array or a stream of JSON objects.
+func readFeedbackBatch(cmd *cobra.Command, path string) ([]feedbackEntry, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(cmd.InOrStdin())
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading feedback batch: %w", err)
+	}
+
+	var entries []feedbackEntry
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &entries); err != nil {
+			return nil, fmt.Errorf("parsing feedback batch: %w", err)
+		}
+		return entries, nil
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	for {
+		var e feedbackEntry
+		if err := dec.Decode(&e); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("parsing feedback batch: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, nil
+}
+
+// recordFeedback applies each entry to the store, logs it, and counts it
+// against the tracked session. It returns the entries recorded and those
+// that failed; an error is returned only if the feedback log can't be written.
+func recordFeedback(ctx context.Context, graphStore *store.MultiGraphStore, root string, entries []feedbackEntry) ([]feedbackEntry, []feedbackFailure, error) {
+	var recorded []feedbackEntry
+	var failures []feedbackFailure
+	for i, e := range entries {
+		if err := applyFeedback(ctx, graphStore, e); err != nil {
+			failures = append(failures, feedbackFailure{Index: i + 1, BehaviorID: e.BehaviorID, Error: err.Error()})
+			continue
+		}
+		e.Note = sanitize.SanitizeBehaviorContent(e.Note)
+		e.Timestamp = time.Now()
+		recorded = append(recorded, e)
+	}
+	if len(recorded) == 0 {
+		return nil, failures, nil
+	}
+
+	if err := appendFeedbackLog(filepath.Join(root, ".floop", feedbackLogFile), recorded); err != nil {
+		return recorded, failures, err
+	}
+	if id := session.Current(root); id != "" {
+		for _, e := range recorded {
+			if err := session.Track(ctx, graphStore, id, session.EventFeedback, e.BehaviorID); err != nil {
+				return recorded, failures, err
+			}
+		}
+	}
+	return recorded, failures, nil
+}
+
+// applyFeedback validates e and increments the matching counter.
+func applyFeedback(ctx context.Context, recorder feedbackRecorder, e feedbackEntry) error {
+	if e.BehaviorID == "" {
+		return fmt.Errorf("behavior_id is required")
+	}
+	switch e.Signal {
+	case feedbackFollowed:
+		return recorder.RecordFollowed(ctx, e.BehaviorID)
+	case feedbackConfirmed:
+		return recorder.RecordConfirmed(ctx, e.BehaviorID)
+	case feedbackOverridden:
+		return recorder.RecordOverridden(ctx, e.BehaviorID)
+	default:
+		return fmt.Errorf("signal must be followed, confirmed, or overridden, got %q", e.Signal)
+	}
+}
+
+// appendFeedbackLog appends entries to the feedback log at path.
+func appendFeedbackLog(path string, entries []feedbackEntry) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening feedback log: %w", err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, e := range entries {
+		if err := enc.Encode(e); err != nil {
+			return fmt.Errorf("writing feedback log: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func feedbackStats(t *testing.T, root, id string) models.BehaviorStats {
+	t.Helper()
+	behaviors, err := loadBehaviorsWithScope(root, constants.ScopeBoth)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, b := range behaviors {
+		if b.ID == id {
+			return b.Stats
+		}
+	}
+	t.Fatalf("behavior %s not found", id)
+	return models.BehaviorStats{}
+}
+
+func TestFeedbackCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	out, err := runCmd(t, newFeedbackCmd(), behaviorID, "--followed", "--root", tmpDir)
+	if err != nil || !strings.Contains(out, "Recorded followed feedback") {
+		t.Fatalf("feedback --followed = %q, %v", out, err)
+	}
+	out, err = runCmd(t, newFeedbackCmd(), behaviorID, "--overridden", "--note", "tests need fmt output", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("feedback --overridden failed: %v", err)
+	}
+	var resp map[string]any
+	if err := json.Unmarshal([]byte(out), &resp); err != nil || resp["signal"] != "overridden" {
+		t.Errorf("JSON = %q, %v", out, err)
+	}
+
+	stats := feedbackStats(t, tmpDir, behaviorID)
+	if stats.TimesFollowed != 1 || stats.TimesOverridden != 1 || stats.TimesConfirmed != 0 {
+		t.Errorf("stats = %+v", stats)
+	}
+	log, _ := os.ReadFile(filepath.Join(tmpDir, ".floop", feedbackLogFile))
+	if strings.Count(string(log), "\n") != 2 || !strings.Contains(string(log), "tests need fmt output") {
+		t.Errorf("feedback log = %s", log)
+	}
+}
+
+func TestFeedbackCmdErrors(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr string
+	}{
+		{"no signal", []string{behaviorID}, "one of --followed"},
+		{"two signals", []string{behaviorID, "--followed", "--confirmed"}, "none of the others"},
+		{"no behavior", []string{"--confirmed"}, "behavior ID"},
+		{"unknown behavior", []string{"b-missing", "--confirmed"}, "not found"},
+		{"batch with id", []string{behaviorID, "--batch", "-"}, "cannot be combined"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"--root", tmpDir}, tt.args...)
+			if _, err := runCmd(t, newFeedbackCmd(), args...); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFeedbackCmdBatch(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	batch := `{"behavior_id": "` + behaviorID + `", "signal": "confirmed"}
+{"behavior_id": "` + behaviorID + `", "signal": "followed", "note": "applied in review"}
+{"behavior_id": "b-missing", "signal": "followed"}
+{"behavior_id": "` + behaviorID + `", "signal": "liked"}`
+	out, err := runCmdWithInput(t, batch, newFeedbackCmd(), "--batch", "-", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("feedback --batch failed: %v", err)
+	}
+	var resp struct {
+		Recorded int               `json:"recorded"`
+		Failed   []feedbackFailure `json:"failed"`
+	}
+	if err := json.Unmarshal([]byte(out), &resp); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if resp.Recorded != 2 || len(resp.Failed) != 2 || resp.Failed[0].Index != 3 || resp.Failed[1].Index != 4 {
+		t.Errorf("batch = %+v", resp)
+	}
+
+	// A JSON array from a file works too.
+	path := filepath.Join(tmpDir, "feedback.json")
+	os.WriteFile(path, []byte(`[{"behavior_id": "`+behaviorID+`", "signal": "confirmed"}]`), 0600)
+	if out, err := runCmd(t, newFeedbackCmd(), "--batch", path, "--root", tmpDir); err != nil || !strings.Contains(out, "Recorded 1 of 1") {
+		t.Errorf("array batch = %q, %v", out, err)
+	}
+
+	stats := feedbackStats(t, tmpDir, behaviorID)
+	if stats.TimesConfirmed != 2 || stats.TimesFollowed != 1 {
+		t.Errorf("stats = %+v", stats)
+	}
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/gc"
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
+	"github.com/spf13/cobra"
+)
+
+func newGCCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "gc",
+		Short: "Remove orphaned vector index entries and pack cache files",
+		Long: `Garbage-collect data that outlived what it belonged to.
+
+Vector index entries and stored embeddings for forgotten, deprecated,
+merged, or deleted behaviors are removed from the project's vector index
+(.floop/vectors). Pack download cache files (~/.floop/cache/packs) are
+removed unless an installed pack was installed from them; temp files from
+interrupted downloads are removed once they are an hour old. With
+packs.cache_max_age set, cache files not used for that long are removed
+too, except those installed packs were installed from.
+
+The MCP server also runs this at startup every maintenance.gc_interval
+(default 7d).
+
+Examples:
+  floop gc --dry-run
+  floop gc
+  floop gc --json --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			if !dryRun {
+				confirmed, err := confirmDestructive(config.OpGC, yes, jsonOut, func() {
+					fmt.Fprintln(out, "Remove orphaned vector index entries, stored embeddings of inactive behaviors, and unused pack cache files.")
+				})
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(out, "Cancelled.")
+					return nil
+				}
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+
+			opts := gc.Options{
+				Installed:   pack.ListInstalled(cfg),
+				CacheMaxAge: cfg.Packs.CacheMaxAgeDuration(),
+				DryRun:      dryRun,
+			}
+			if cacheDir, err := pack.DefaultCacheDir(); err == nil {
+				opts.CacheDir = cacheDir
+			}
+
+			var warnings []string
+			floopDir := filepath.Join(root, ".floop")
+			vectorDir := filepath.Join(floopDir, "vectors")
+			if _, err := os.Stat(vectorDir); err == nil {
+				graphStore, err := store.NewMultiGraphStore(root)
+				if err != nil {
+					return fmt.Errorf("failed to open store: %w", err)
+				}
+				defer graphStore.Close()
+
+				// Dims only matter when creating a table; an existing one is opened as-is.
+				idx, err := vectorindex.NewLanceDBIndex(vectorindex.LanceDBConfig{Dir: vectorDir, Dims: 768})
+				if err != nil {
+					warnings = append(warnings, fmt.Sprintf("vector index skipped: %v", err))
+				} else {
+					defer idx.Close()
+					opts.Store = graphStore
+					opts.Index = idx
+					opts.IndexDir = vectorDir
+				}
+			}
+
+			report, err := gc.Run(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			report.Warnings = append(warnings, report.Warnings...)
+			if !dryRun {
+				if _, err := os.Stat(floopDir); err == nil {
+					if err := gc.MarkRun(floopDir, report); err != nil {
+						report.Warnings = append(report.Warnings, err.Error())
+					}
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(report)
+			}
+			printGCReport(out, report)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Report orphans without removing them")
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// printGCReport writes a human-readable summary of a gc run.
+func printGCReport(out *output, r *gc.Report) {
+	verb := "Removed"
+	if r.DryRun {
+		verb = "Would remove"
+	}
+
+	fmt.Fprintf(out, "%s %d orphaned vector(s)", verb, len(r.OrphanedVectors))
+	if r.VectorBytes > 0 {
+		fmt.Fprintf(out, ", reclaimed %s", formatBytes(r.VectorBytes))
+	}
+	fmt.Fprintln(out)
+	for _, id := range r.OrphanedVectors {
+		out.Verbosef("  - %s\n", id)
+	}
+	if r.ClearedEmbeddings > 0 {
+		fmt.Fprintf(out, "Cleared %d stored embedding(s) of inactive behaviors\n", r.ClearedEmbeddings)
+	}
+
+	fmt.Fprintf(out, "%s %d orphaned pack cache file(s) (%s)\n", verb, len(r.OrphanedCacheFiles), formatBytes(r.CacheBytes))
+	for _, f := range r.OrphanedCacheFiles {
+		out.Verbosef("  - %s (%s)\n", f.Path, formatBytes(f.Size))
+	}
+
+	for _, w := range r.Warnings {
+		fmt.Fprintf(out, "Warning: %s\n", w)
+	}
+	if !r.DryRun {
+		fmt.Fprintf(out, "Total reclaimed: %s\n", formatBytes(r.ReclaimedBytes()))
+	}
+}
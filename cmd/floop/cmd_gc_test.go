@@ -0,0 +1,66 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/gc"
+)
+
+func TestGCCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	floopDir := filepath.Join(tmpDir, ".floop")
+	if err := os.MkdirAll(floopDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	orphan := filepath.Join(tmpDir, "home", ".floop", "cache", "packs", "url", "gone.fpack")
+	if err := os.MkdirAll(filepath.Dir(orphan), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(orphan, make([]byte, 2048), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCmd(t, newGCCmd(), "--dry-run", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("gc --dry-run failed: %v", err)
+	}
+	if !strings.Contains(out, "Would remove 1 orphaned pack cache file(s) (2.0KB)") {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+	if _, err := os.Stat(orphan); err != nil {
+		t.Errorf("dry run removed the cache file: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(floopDir, gc.StateFile)); !os.IsNotExist(err) {
+		t.Error("dry run should not record a gc run")
+	}
+
+	_, err = runCmd(t, newGCCmd(), "--json", "--root", tmpDir)
+	wantErrorCode(t, err, codeConfirmationRequired)
+	if _, err := os.Stat(orphan); err != nil {
+		t.Errorf("unconfirmed gc removed the cache file: %v", err)
+	}
+
+	out, err = runCmd(t, newGCCmd(), "--json", "--yes", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("gc failed: %v", err)
+	}
+	var report gc.Report
+	if err := json.Unmarshal([]byte(out), &report); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(report.OrphanedCacheFiles) != 1 || report.CacheBytes != 2048 {
+		t.Errorf("report = %+v, want one 2048-byte cache file", report)
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Error("cache file should be removed")
+	}
+	if _, err := os.Stat(filepath.Join(floopDir, gc.StateFile)); err != nil {
+		t.Errorf("gc run not recorded: %v", err)
+	}
+}
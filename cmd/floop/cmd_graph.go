@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/visualization"
@@ -21,6 +22,7 @@ func newGraphCmd() *cobra.Command {
 		Short: "Visualize the behavior graph",
 		Long:  `Output the behavior graph in DOT (Graphviz), JSON, or interactive HTML format.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			format, _ := cmd.Flags().GetString("format")
 			output, _ := cmd.Flags().GetString("output")
@@ -46,14 +48,14 @@ func newGraphCmd() *cobra.Command {
 				if err != nil {
 					return fmt.Errorf("render DOT: %w", err)
 				}
-				fmt.Fprint(cmd.OutOrStdout(), dot)
+				fmt.Fprint(out, dot)
 
 			case visualization.FormatJSON:
 				result, err := visualization.RenderJSON(ctx, gs)
 				if err != nil {
 					return fmt.Errorf("render JSON: %w", err)
 				}
-				enc := json.NewEncoder(cmd.OutOrStdout())
+				enc := json.NewEncoder(out)
 				enc.SetIndent("", "  ")
 				if err := enc.Encode(result); err != nil {
 					return fmt.Errorf("encode JSON: %w", err)
@@ -93,11 +95,126 @@ func newGraphCmd() *cobra.Command {
 	cmd.Flags().Bool("no-open", false, "Don't open browser after generating HTML")
 	cmd.Flags().Bool("serve", false, "Start a local server with electric mode (spreading activation visualization)")
 
+	cmd.AddCommand(newGraphExportCmd())
+
+	return cmd
+}
+
+func newGraphExportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export behavior nodes and edges for Graphviz or Gephi",
+		Long: `Dump behaviors and the edges between them for inspection in external
+graph tools: DOT for Graphviz, GraphML for Gephi or yEd, or JSON.
+
+--edge-kind keeps only edges of the given kinds. --tag keeps only behaviors
+carrying at least one of the given tags; edges are kept only when both
+endpoints are. With --scope both, a behavior present in both stores is
+exported from the local store.
+
+Examples:
+  floop graph export > graph.dot
+  floop graph export --format graphml -o graph.graphml
+  floop graph export --edge-kind similar-to --edge-kind overrides
+  floop graph export --scope global --tag go --format json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			format, _ := cmd.Flags().GetString("format")
+			scope, _ := cmd.Flags().GetString("scope")
+			edgeKinds, _ := cmd.Flags().GetStringSlice("edge-kind")
+			tags, _ := cmd.Flags().GetStringSlice("tag")
+			output, _ := cmd.Flags().GetString("output")
+
+			switch visualization.Format(format) {
+			case visualization.FormatDOT, visualization.FormatGraphML, visualization.FormatJSON:
+			default:
+				return fmt.Errorf("unsupported format %q (use 'dot', 'graphml', or 'json')", format)
+			}
+
+			var scopes []string
+			switch constants.Scope(scope) {
+			case constants.ScopeLocal, constants.ScopeGlobal:
+				scopes = []string{scope}
+			case constants.ScopeBoth:
+				scopes = []string{string(constants.ScopeLocal), string(constants.ScopeGlobal)}
+			default:
+				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scope)
+			}
+
+			filter := visualization.ExportFilter{Tags: tags}
+			for _, k := range edgeKinds {
+				filter.EdgeKinds = append(filter.EdgeKinds, store.EdgeKind(k))
+			}
+
+			var stores []visualization.ScopedStore
+			for _, sc := range scopes {
+				floopDir, err := similarityStoreDir(root, sc)
+				if err != nil {
+					// With --scope both, export whichever stores exist
+					if len(scopes) > 1 {
+						continue
+					}
+					return err
+				}
+				gs, err := store.NewSQLiteGraphStore(filepath.Dir(floopDir))
+				if err != nil {
+					return fmt.Errorf("failed to open %s store: %w", sc, err)
+				}
+				defer gs.Close()
+				stores = append(stores, visualization.ScopedStore{Scope: sc, Store: gs})
+			}
+			if len(stores) == 0 {
+				return fmt.Errorf("no .floop stores initialized. Run 'floop init' first")
+			}
+
+			graph, err := visualization.BuildExportGraph(cmd.Context(), stores, filter)
+			if err != nil {
+				return fmt.Errorf("build graph: %w", err)
+			}
+
+			var data []byte
+			switch visualization.Format(format) {
+			case visualization.FormatDOT:
+				data = []byte(visualization.RenderExportDOT(graph))
+			case visualization.FormatGraphML:
+				data, err = visualization.RenderExportGraphML(graph)
+				if err != nil {
+					return err
+				}
+			case visualization.FormatJSON:
+				data, err = json.MarshalIndent(graph, "", "  ")
+				if err != nil {
+					return fmt.Errorf("encode JSON: %w", err)
+				}
+				data = append(data, '\n')
+			}
+
+			if output == "" {
+				_, err := out.Write(data)
+				return err
+			}
+			if err := os.WriteFile(output, data, 0644); err != nil {
+				return fmt.Errorf("write output file: %w", err)
+			}
+			fmt.Fprintf(cmd.ErrOrStderr(), "Exported %d behaviors and %d edges to %s\n", graph.NodeCount, graph.EdgeCount, output)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("format", "dot", "Output format: dot, graphml, or json")
+	cmd.Flags().String("scope", "both", "Store scope: local, global, or both")
+	cmd.Flags().StringSlice("edge-kind", nil, "Only include edges of this kind (repeatable)")
+	cmd.Flags().StringSlice("tag", nil, "Only include behaviors with this tag (repeatable)")
+	cmd.Flags().StringP("output", "o", "", "Output file path (default: stdout)")
+
 	return cmd
 }
 
 // writeStaticHTML renders the graph to a self-contained HTML file.
 func writeStaticHTML(cmd *cobra.Command, ctx context.Context, gs store.GraphStore, enrichment *visualization.EnrichmentData, output string, noOpen bool) error {
+	out := newOutput(cmd)
 	htmlBytes, err := visualization.RenderHTML(ctx, gs, enrichment)
 	if err != nil {
 		return fmt.Errorf("render HTML: %w", err)
@@ -112,7 +229,7 @@ func writeStaticHTML(cmd *cobra.Command, ctx context.Context, gs store.GraphStor
 		return fmt.Errorf("write HTML file: %w", err)
 	}
 
-	fmt.Fprintf(cmd.OutOrStdout(), "Graph written to %s\n", outPath)
+	fmt.Fprintf(out, "Graph written to %s\n", outPath)
 
 	if !noOpen {
 		if err := visualization.OpenBrowser(outPath); err != nil {
@@ -124,6 +241,7 @@ func writeStaticHTML(cmd *cobra.Command, ctx context.Context, gs store.GraphStor
 
 // runGraphServer starts a local HTTP server with electric mode and blocks until Ctrl-C.
 func runGraphServer(cmd *cobra.Command, ctx context.Context, gs store.GraphStore, enrichment *visualization.EnrichmentData, noOpen bool) error {
+	out := newOutput(cmd)
 	srv := visualization.NewServer(gs, enrichment)
 
 	srvCtx, srvCancel := context.WithCancel(ctx)
@@ -160,8 +278,8 @@ func runGraphServer(cmd *cobra.Command, ctx context.Context, gs store.GraphStore
 	}
 
 	url := "http://" + addr
-	fmt.Fprintf(cmd.OutOrStdout(), "Graph server running at %s\n", url)
-	fmt.Fprintf(cmd.OutOrStdout(), "Press Ctrl-C to stop.\n")
+	fmt.Fprintf(out, "Graph server running at %s\n", url)
+	fmt.Fprintf(out, "Press Ctrl-C to stop.\n")
 
 	if !noOpen {
 		if err := visualization.OpenBrowser(url); err != nil {
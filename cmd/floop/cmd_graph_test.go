@@ -3,6 +3,8 @@ package main
 import (
 	"bytes"
 	"io"
+	"os"
+	"path/filepath"
 	"runtime"
 	"strings"
 	"testing"
@@ -123,3 +125,58 @@ func TestGraphDefaultFormatIsDOT(t *testing.T) {
 		t.Errorf("expected DOT output containing 'digraph', got: %s", output)
 	}
 }
+
+func TestGraphExportCmdFormats(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"dot", "digraph floop {"},
+		{"graphml", `<node id="` + behaviorID + `">`},
+		{"json", `"id": "` + behaviorID + `"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			out, err := runCmd(t, newGraphCmd(), "export", "--format", tt.format, "--root", tmpDir)
+			if err != nil {
+				t.Fatalf("graph export --format %s failed: %v", tt.format, err)
+			}
+			if !strings.Contains(out, tt.want) || !strings.Contains(out, behaviorID) {
+				t.Errorf("output missing %q:\n%s", tt.want, out)
+			}
+		})
+	}
+
+	if _, err := runCmd(t, newGraphCmd(), "export", "--format", "svg", "--root", tmpDir); err == nil {
+		t.Error("--format svg should fail")
+	}
+	if _, err := runCmd(t, newGraphCmd(), "export", "--scope", "team", "--root", tmpDir); err == nil {
+		t.Error("--scope team should fail")
+	}
+}
+
+func TestGraphExportCmdFilters(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	out, err := runCmd(t, newGraphCmd(), "export", "--format", "json", "--tag", "no-such-tag", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("graph export --tag failed: %v", err)
+	}
+	if strings.Contains(out, behaviorID) || !strings.Contains(out, `"node_count": 0`) {
+		t.Errorf("tag filter kept behavior:\n%s", out)
+	}
+
+	outPath := filepath.Join(tmpDir, "graph.graphml")
+	if _, err := runCmd(t, newGraphCmd(), "export", "--format", "graphml", "--scope", "global", "-o", outPath, "--root", tmpDir); err != nil {
+		t.Fatalf("graph export -o failed: %v", err)
+	}
+	data, err := os.ReadFile(outPath)
+	if err != nil {
+		t.Fatalf("read output: %v", err)
+	}
+	if !strings.Contains(string(data), behaviorID) {
+		t.Errorf("output file missing behavior:\n%s", data)
+	}
+}
@@ -0,0 +1,258 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/spf13/cobra"
+)
+
+// Sources searched by 'floop grep'.
+const (
+	grepSourceBehaviors   = "behaviors"
+	grepSourceCorrections = "corrections"
+	grepSourcePacks       = "packs"
+)
+
+var grepSources = []string{grepSourceBehaviors, grepSourceCorrections, grepSourcePacks}
+
+// grepSnippetRunes is the maximum length of a matched field shown in a hit.
+const grepSnippetRunes = 100
+
+// grepHit is one field matching a 'floop grep' pattern.
+type grepHit struct {
+	Source  string `json:"source"`
+	ID      string `json:"id"`
+	Label   string `json:"label,omitempty"`
+	Field   string `json:"field"`
+	Text    string `json:"text"`
+	Command string `json:"command"`
+}
+
+func newGrepCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "grep <pattern>",
+		Short: "Search behaviors, corrections, and installed packs",
+		Long: `Search behavior content, the correction log, and installed pack manifests
+in one pass — a quick way to check whether anything about a topic has
+already been captured.
+
+The pattern is a regular expression, matched case-insensitively unless
+--case-sensitive is set. Each hit is labeled with its source and the command
+that shows it in full.
+
+Behaviors are searched by name, canonical text, summary, and tags in both
+stores; corrections by the wrong/right actions, human response, and file;
+packs by ID, version, and source.
+
+Examples:
+  floop grep logging
+  floop grep 'fmt\.Print(ln|f)' --source behaviors,corrections
+  floop grep slog --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			sources, _ := cmd.Flags().GetStringSlice("source")
+			caseSensitive, _ := cmd.Flags().GetBool("case-sensitive")
+
+			for _, src := range sources {
+				if !slices.Contains(grepSources, src) {
+					return fmt.Errorf("invalid source: %s (valid: %s)", src, strings.Join(grepSources, ", "))
+				}
+			}
+
+			expr := args[0]
+			if !caseSensitive {
+				expr = "(?i)" + expr
+			}
+			re, err := regexp.Compile(expr)
+			if err != nil {
+				return fmt.Errorf("invalid pattern: %w", err)
+			}
+
+			var hits []grepHit
+			if slices.Contains(sources, grepSourceBehaviors) {
+				if scope, ok := availableScope(root); ok {
+					behaviors, err := loadBehaviorsWithScope(root, scope)
+					if err != nil {
+						return fmt.Errorf("failed to load behaviors: %w", err)
+					}
+					hits = append(hits, grepBehaviors(re, behaviors)...)
+				}
+			}
+			if slices.Contains(sources, grepSourceCorrections) {
+				corrections, err := loadCorrections(root)
+				if err != nil {
+					return fmt.Errorf("failed to read corrections: %w", err)
+				}
+				hits = append(hits, grepCorrections(re, corrections)...)
+			}
+			if slices.Contains(sources, grepSourcePacks) {
+				cfg, err := loadConfig(cmd)
+				if err != nil {
+					cfg = config.Default()
+				}
+				hits = append(hits, grepPacks(re, pack.ListInstalled(cfg))...)
+			}
+
+			if jsonOut {
+				if hits == nil {
+					hits = []grepHit{}
+				}
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"pattern": args[0],
+					"hits":    hits,
+					"count":   len(hits),
+				})
+			}
+
+			if len(hits) == 0 {
+				fmt.Fprintf(out, "No matches for %q.\n", args[0])
+				return nil
+			}
+
+			counts := make(map[string]int)
+			for _, h := range hits {
+				fmt.Fprintf(out, "[%s] %s", h.Source, h.ID)
+				if h.Label != "" {
+					fmt.Fprintf(out, " (%s)", h.Label)
+				}
+				fmt.Fprintln(out)
+				fmt.Fprintf(out, "  %s: %s\n", h.Field, h.Text)
+				fmt.Fprintf(out, "  -> %s\n", h.Command)
+				counts[h.Source]++
+			}
+			fmt.Fprintf(out, "\n%d matches (%d behaviors, %d corrections, %d packs)\n",
+				len(hits), counts[grepSourceBehaviors], counts[grepSourceCorrections], counts[grepSourcePacks])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("source", grepSources, "Sources to search: behaviors, corrections, packs")
+	cmd.Flags().Bool("case-sensitive", false, "Match the pattern case-sensitively")
+
+	return cmd
+}
+
+// grepField pairs a field name with its text for matching.
+type grepField struct {
+	name string
+	text string
+}
+
+// firstMatch returns the first field whose text matches re.
+func firstMatch(re *regexp.Regexp, fields []grepField) (grepField, bool) {
+	for _, f := range fields {
+		if f.text != "" && re.MatchString(f.text) {
+			return f, true
+		}
+	}
+	return grepField{}, false
+}
+
+func grepBehaviors(re *regexp.Regexp, behaviors []models.Behavior) []grepHit {
+	var hits []grepHit
+	for _, b := range behaviors {
+		f, ok := firstMatch(re, []grepField{
+			{"canonical", b.Content.Canonical},
+			{"summary", b.Content.Summary},
+			{"name", b.Name},
+			{"tags", strings.Join(b.Content.Tags, ", ")},
+		})
+		if !ok {
+			continue
+		}
+		hits = append(hits, grepHit{
+			Source:  grepSourceBehaviors,
+			ID:      b.ID,
+			Label:   b.Name,
+			Field:   f.name,
+			Text:    grepSnippet(re, f.text),
+			Command: "floop show " + b.ID,
+		})
+	}
+	return hits
+}
+
+func grepCorrections(re *regexp.Regexp, corrections []models.Correction) []grepHit {
+	var hits []grepHit
+	for _, c := range corrections {
+		f, ok := firstMatch(re, []grepField{
+			{"wrong", c.AgentAction},
+			{"right", c.CorrectedAction},
+			{"human_response", c.HumanResponse},
+			{"file", c.Context.FilePath},
+		})
+		if !ok {
+			continue
+		}
+		hits = append(hits, grepHit{
+			Source:  grepSourceCorrections,
+			ID:      c.ID,
+			Label:   c.Timestamp.Format("2006-01-02"),
+			Field:   f.name,
+			Text:    grepSnippet(re, f.text),
+			Command: "floop list --corrections",
+		})
+	}
+	return hits
+}
+
+func grepPacks(re *regexp.Regexp, installed []config.InstalledPack) []grepHit {
+	var hits []grepHit
+	for _, p := range installed {
+		f, ok := firstMatch(re, []grepField{
+			{"id", p.ID},
+			{"version", p.Version},
+			{"source", p.Source},
+		})
+		if !ok {
+			continue
+		}
+		hits = append(hits, grepHit{
+			Source:  grepSourcePacks,
+			ID:      p.ID,
+			Label:   "v" + p.Version,
+			Field:   f.name,
+			Text:    grepSnippet(re, f.text),
+			Command: "floop pack info " + p.ID,
+		})
+	}
+	return hits
+}
+
+// grepSnippet returns text on one line, trimmed to a window around the first
+// match of re when it is longer than grepSnippetRunes.
+func grepSnippet(re *regexp.Regexp, text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	runes := []rune(text)
+	if len(runes) <= grepSnippetRunes {
+		return text
+	}
+
+	loc := re.FindStringIndex(text)
+	start := 0
+	if loc != nil {
+		matchStart := len([]rune(text[:loc[0]]))
+		start = max(0, matchStart-grepSnippetRunes/4)
+	}
+	end := min(len(runes), start+grepSnippetRunes)
+	start = max(0, end-grepSnippetRunes)
+
+	snippet := string(runes[start:end])
+	if start > 0 {
+		snippet = "..." + snippet
+	}
+	if end < len(runes) {
+		snippet += "..."
+	}
+	return snippet
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+)
+
+func TestGrepCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	cfg := config.Default()
+	cfg.Packs.Installed = []config.InstalledPack{
+		{ID: "acme/logging-pack", Version: "1.2.0", Source: "gh:acme/logging"},
+	}
+	if err := saveConfig(cfg); err != nil {
+		t.Fatalf("saveConfig: %v", err)
+	}
+
+	out, err := runCmd(t, newGrepCmd(), "LOGGING", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("grep failed: %v", err)
+	}
+	for _, want := range []string{
+		"[behaviors] " + behaviorID,
+		"-> floop show " + behaviorID,
+		"[corrections]",
+		"-> floop list --corrections",
+		"[packs] acme/logging-pack (v1.2.0)",
+		"-> floop pack info acme/logging-pack",
+		"3 matches (1 behaviors, 1 corrections, 1 packs)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCmd(t, newGrepCmd(), `fmt\.Println`, "--source", "corrections", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("grep --json failed: %v", err)
+	}
+	var result struct {
+		Hits  []grepHit `json:"hits"`
+		Count int       `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Count != 1 || result.Hits[0].Source != grepSourceCorrections || result.Hits[0].Field != "wrong" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	out, err = runCmd(t, newGrepCmd(), "LOGGING", "--case-sensitive", "--root", tmpDir)
+	if err != nil || !strings.Contains(out, "No matches") {
+		t.Errorf("expected no case-sensitive matches, got %v:\n%s", err, out)
+	}
+}
+
+func TestGrepCmd_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runCmd(t, newGrepCmd(), "x", "--source", "events", "--root", tmpDir); err == nil {
+		t.Error("expected invalid source error")
+	}
+	if _, err := runCmd(t, newGrepCmd(), "(unclosed", "--root", tmpDir); err == nil {
+		t.Error("expected invalid pattern error")
+	}
+	out, err := runCmd(t, newGrepCmd(), "anything", "--root", tmpDir)
+	if err != nil || !strings.Contains(out, "No matches") {
+		t.Errorf("uninitialized grep: %v\n%s", err, out)
+	}
+}
+
+func TestGrepSnippet(t *testing.T) {
+	re := regexp.MustCompile("needle")
+	long := strings.Repeat("a ", 80) + "needle" + strings.Repeat(" b", 80)
+	got := grepSnippet(re, long)
+	if !strings.Contains(got, "needle") || !strings.HasPrefix(got, "...") || !strings.HasSuffix(got, "...") {
+		t.Errorf("snippet = %q", got)
+	}
+	if got := grepSnippet(re, "short\n needle"); got != "short needle" {
+		t.Errorf("snippet = %q, want single line", got)
+	}
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newHistoryCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history <behavior-id>",
+		Short: "Show how a behavior changed over time",
+		Long: `List the revisions of a behavior, oldest first.
+
+A revision is recorded each time a behavior's content, when conditions, or
+kind changes, whether by learning, merging, curation, or a pack update.
+Each revision keeps the content and confidence the behavior had then; use
+'floop revert' to restore one. Revisions live in the store database and
+are not exported to nodes.jsonl.
+
+Examples:
+  floop history b-1a2b3c
+  floop history b-1a2b3c --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			id := args[0]
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			revisions, err := graphStore.BehaviorHistory(cmd.Context(), id)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				if revisions == nil {
+					revisions = []store.Revision{}
+				}
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"behavior_id": id,
+					"revisions":   revisions,
+				})
+			}
+			if len(revisions) == 0 {
+				fmt.Fprintf(out, "No revisions recorded for %s yet.\n", id)
+				return nil
+			}
+			fmt.Fprintf(out, "Revisions of %s (%d):\n", id, len(revisions))
+			for _, r := range revisions {
+				fmt.Fprintf(out, "\n  rev %d  %s  %s  confidence %.2f\n",
+					r.Rev, r.RecordedAt.Local().Format("2006-01-02 15:04"), r.Kind, r.Confidence)
+				if name, _ := r.Content["name"].(string); name != "" {
+					fmt.Fprintf(out, "    name: %s\n", name)
+				}
+				if c, ok := r.Content["content"].(map[string]interface{}); ok {
+					fmt.Fprintf(out, "    %v\n", c["canonical"])
+				}
+				if when, ok := r.Content["when"].(map[string]interface{}); ok && len(when) > 0 {
+					data, _ := json.Marshal(when)
+					fmt.Fprintf(out, "    when: %s\n", data)
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newRevertCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "revert <behavior-id> --to <rev>",
+		Short: "Restore a behavior's content from an earlier revision",
+		Long: `Restore the content, when conditions, and confidence a behavior had at
+an earlier revision (see 'floop history').
+
+The revert is itself recorded as a new revision, so it can be undone by
+reverting again. The behavior's kind is not changed: use 'floop restore'
+to bring back a forgotten or deprecated behavior.
+
+Examples:
+  floop revert b-1a2b3c --to 2
+  floop revert b-1a2b3c --to 2 --yes`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			to, _ := cmd.Flags().GetInt("to")
+			yes, _ := cmd.Flags().GetBool("yes")
+			id := args[0]
+			ctx := cmd.Context()
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			revisions, err := graphStore.BehaviorHistory(ctx, id)
+			if err != nil {
+				return err
+			}
+			var target *store.Revision
+			for i := range revisions {
+				if revisions[i].Rev == to {
+					target = &revisions[i]
+				}
+			}
+			if target == nil {
+				return fmt.Errorf("behavior %s has no revision %d (see 'floop history %s')", id, to, id)
+			}
+			if to == revisions[len(revisions)-1].Rev {
+				return fmt.Errorf("revision %d is already the current revision of %s", to, id)
+			}
+
+			node, err := graphStore.GetNode(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get behavior: %w", err)
+			}
+			if node == nil {
+				return fmt.Errorf("behavior not found: %s", id)
+			}
+
+			confirmed, err := confirmDestructive(config.OpRevert, yes, jsonOut, func() {
+				fmt.Fprintf(out, "Revert %s to revision %d, replacing its current content.\n", id, to)
+			})
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Fprintln(out, "Cancelled.")
+				return nil
+			}
+
+			node.Content = target.Content
+			node.Metadata["confidence"] = target.Confidence
+			if err := graphStore.UpdateNode(ctx, *node); err != nil {
+				return fmt.Errorf("failed to update behavior: %w", err)
+			}
+			if err := graphStore.Sync(ctx); err != nil {
+				return fmt.Errorf("failed to sync changes: %w", err)
+			}
+
+			current := to
+			if revisions, err := graphStore.BehaviorHistory(ctx, id); err == nil && len(revisions) > 0 {
+				current = revisions[len(revisions)-1].Rev
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status":      "reverted",
+					"behavior_id": id,
+					"reverted_to": to,
+					"revision":    current,
+				})
+			}
+			fmt.Fprintf(out, "Reverted %s to revision %d (recorded as revision %d)\n", id, to, current)
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("to", 0, "Revision to restore (required)")
+	cmd.MarkFlagRequired("to")
+	addYesFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func canonicalOf(t *testing.T, root, id string) string {
+	t.Helper()
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer graphStore.Close()
+	node, err := graphStore.GetNode(context.Background(), id)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+	}
+	content, _ := node.Content["content"].(map[string]interface{})
+	canonical, _ := content["canonical"].(string)
+	return canonical
+}
+
+func TestHistoryAndRevertCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	original := canonicalOf(t, tmpDir, behaviorID)
+
+	// Edit the behavior through the store, as curation and merges do.
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	node, err := graphStore.GetNode(ctx, behaviorID)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode = %v, %v", node, err)
+	}
+	node.Content["content"].(map[string]interface{})["canonical"] = "use log/slog with request IDs"
+	if err := graphStore.UpdateNode(ctx, *node); err != nil {
+		t.Fatal(err)
+	}
+	graphStore.Close()
+
+	out, err := runCmd(t, newHistoryCmd(), behaviorID, "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("history failed: %v", err)
+	}
+	var history struct {
+		Revisions []store.Revision `json:"revisions"`
+	}
+	if err := json.Unmarshal([]byte(out), &history); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(history.Revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d:\n%s", len(history.Revisions), out)
+	}
+
+	out, err = runCmd(t, newHistoryCmd(), behaviorID, "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("history failed: %v", err)
+	}
+	if !strings.Contains(out, "rev 1") || !strings.Contains(out, "use log/slog with request IDs") {
+		t.Errorf("unexpected history output:\n%s", out)
+	}
+
+	if _, err := runCmd(t, newRevertCmd(), behaviorID, "--to", "2", "--root", tmpDir); err == nil {
+		t.Error("expected error reverting to the current revision")
+	}
+	if _, err := runCmd(t, newRevertCmd(), behaviorID, "--to", "9", "--root", tmpDir); err == nil {
+		t.Error("expected error reverting to a missing revision")
+	}
+
+	out, err = runCmd(t, newRevertCmd(), behaviorID, "--to", "1", "--yes", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("revert failed: %v", err)
+	}
+	if !strings.Contains(out, "Reverted "+behaviorID+" to revision 1 (recorded as revision 3)") {
+		t.Errorf("unexpected revert output:\n%s", out)
+	}
+	if got := canonicalOf(t, tmpDir, behaviorID); got != original {
+		t.Errorf("canonical after revert = %q, want %q", got, original)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
@@ -132,7 +133,7 @@ func newHookDynamicContextCmd() *cobra.Command {
 			}
 
 			// Load dynamic context token budget from config
-			cfg, err := config.Load()
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				cfg = config.Default()
 			}
@@ -233,6 +234,7 @@ func newHookDetectCorrectionCmd() *cobra.Command {
 // runDetectCorrection contains the core correction detection logic, extracted
 // from the command handler to allow dependency injection of the LLM client for testing.
 func runDetectCorrection(cmd *cobra.Command, root, prompt string, client llm.Client) error {
+	out := newOutput(cmd)
 	// Fast pattern check
 	capture := learning.NewCorrectionCapture()
 	if !capture.MightBeCorrection(prompt) {
@@ -303,7 +305,7 @@ func runDetectCorrection(cmd *cobra.Command, root, prompt string, client llm.Cli
 		Processed:       false,
 	}
 
-	loop := learning.NewLearningLoop(graphStore, nil)
+	loop := learning.NewLearningLoop(graphStore, applyReviewHold(root, withOpLog(root, nil)))
 	_, processErr := loop.ProcessCorrection(ctx, correction)
 	if processErr != nil {
 		hookLog(root, "detect-correction", "process", "process_error", map[string]interface{}{"error": processErr.Error()})
@@ -323,7 +325,7 @@ func runDetectCorrection(cmd *cobra.Command, root, prompt string, client llm.Cli
 	}
 
 	hookLog(root, "detect-correction", "complete", "correction_captured", map[string]interface{}{"correction_id": correction.ID})
-	fmt.Fprint(cmd.OutOrStdout(), formatCorrectionCapturedMessage(correction.ID))
+	fmt.Fprint(out, formatCorrectionCapturedMessage(correction.ID))
 	return nil
 }
 
@@ -344,33 +346,38 @@ This applies to: explicit corrections, preferences, "don't do X", repeated feedb
 // runHookPrompt generates a markdown prompt with all active behaviors.
 // Used by session-start and first-prompt hooks.
 func runHookPrompt(cmd *cobra.Command, root string) error {
+	out := newOutput(cmd)
 	// Check initialization silently
 	if !floopDirExists(root) {
 		return nil
 	}
 
 	// Load config for token budget
-	cfg, err := config.Load()
+	cfg, err := config.LoadForProject(root)
 	if err != nil {
 		cfg = config.Default()
 	}
-	tokenBudget := cfg.TokenBudget.Default
+	// An unknown profile falls back to the global settings; hooks stay silent
+	profile, _ := agentProfile(cmd, cfg)
+	tokenBudget := profile.Budget(cfg.TokenBudget.Default)
 
 	// Load all behaviors from both scopes
 	behaviors, err := loadBehaviorsWithScope(root, constants.ScopeBoth)
 	if err != nil {
 		return nil // silent in hook context
 	}
+	behaviors = profile.Filter(behaviors)
 
 	if len(behaviors) == 0 {
 		// No behaviors to inject, but still output the learn directive
-		fmt.Fprint(cmd.OutOrStdout(), floopLearnDirective())
+		fmt.Fprint(out, floopLearnDirective())
 		return nil
 	}
 
 	// Evaluate which behaviors are active (no specific context for session start)
 	ctxBuilder := activation.NewContextBuilder().
-		WithRepoRoot(root)
+		WithRepoRoot(root).
+		WithGitDetection(cfg.Activation.GitContext)
 
 	// Auto-infer language from project type at session start
 	if lang := projectTypeToLanguage(models.InferProjectType(root)); lang != "" {
@@ -387,13 +394,13 @@ func runHookPrompt(cmd *cobra.Command, root string) error {
 
 	if len(resolved.Active) == 0 {
 		// No active behaviors, but still output the learn directive
-		fmt.Fprint(cmd.OutOrStdout(), floopLearnDirective())
+		fmt.Fprint(out, floopLearnDirective())
 		return nil
 	}
 
 	// Use tiered injection with markdown format
-	results, behaviorMap := tiering.BehaviorsToResults(resolved.Active)
-	mapper := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig())
+	results, behaviorMap := tiering.BehaviorsToResultsWithConfig(resolved.Active, scorerConfig(root, cfg, profile))
+	mapper := tiering.NewActivationTierMapper(profile.TierConfig())
 	plan := mapper.MapResults(results, behaviorMap, tokenBudget)
 
 	compiler := assembly.NewCompiler().
@@ -401,7 +408,7 @@ func runHookPrompt(cmd *cobra.Command, root string) error {
 	compiled := compiler.CompileTiered(plan)
 
 	output := compiled.Text + floopLearnDirective()
-	fmt.Fprint(cmd.OutOrStdout(), output)
+	fmt.Fprint(out, output)
 	return nil
 }
 
@@ -414,9 +421,15 @@ func runHookActivate(cmd *cobra.Command, root, file, task string, tokenBudget in
 		return nil
 	}
 
+	cfg, err := config.LoadForProject(root)
+	if err != nil {
+		cfg = config.Default()
+	}
+
 	// Build context
 	ctxBuilder := activation.NewContextBuilder().
-		WithRepoRoot(root)
+		WithRepoRoot(root).
+		WithGitDetection(cfg.Activation.GitContext)
 	if file != "" {
 		ctxBuilder.WithFile(file)
 	}
@@ -473,7 +486,11 @@ func runHookActivate(cmd *cobra.Command, root, file, task string, tokenBudget in
 		return nil
 	}
 
-	// Apply token budget
+	// Drop behaviors excluded by the agent profile, then apply token budget
+	profile, _ := agentProfile(cmd, cfg)
+	filtered = slices.DeleteFunc(filtered, func(fr session.FilteredResult) bool {
+		return profile.Excludes(behaviorMap[fr.BehaviorID])
+	})
 	budgeted := applyTokenBudget(filtered, tokenBudget)
 	if len(budgeted) == 0 {
 		_ = session.SaveState(sessState, sessionDir)
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/hooks"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/session"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// gitHookMaxFiles caps how many staged files pre-commit evaluates, so a huge
+// commit does not stall the commit.
+const gitHookMaxFiles = 100
+
+func newHooksCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "hooks",
+		Short: "Manage git hooks that feed real usage back into behavior stats",
+		Long: `Install git hooks that record which behaviors applied to each commit.
+
+The pre-commit hook evaluates the behaviors active for each staged file on
+the current branch and records an activation for each one. The post-commit
+hook logs the commit, branch, files, and behaviors to
+.floop/git-commits.jsonl once the commit lands. Behaviors are not confirmed:
+being active for a file does not mean the commit followed them.
+
+Ranking's frequency signal then reflects the code you actually commit, not only explicit 'floop learn' and MCP calls. Activations count
+against the tracked session ('floop session start') when one is active, and
+against the branch otherwise. The hooks never block a commit.
+
+Examples:
+  floop hooks install
+  floop hooks install --force   # replace existing hooks, keeping backups
+  floop hooks uninstall`,
+	}
+
+	cmd.AddCommand(
+		newHooksInstallCmd(),
+		newHooksUninstallCmd(),
+		newHooksRecordCmd(),
+	)
+
+	return cmd
+}
+
+func newHooksInstallCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "install",
+		Short: "Install the pre-commit and post-commit hooks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			force, _ := cmd.Flags().GetBool("force")
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+			hooksDir, err := hooks.GitHooksDir(root)
+			if err != nil {
+				return err
+			}
+			installed, err := hooks.InstallGitHooks(hooksDir, force)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status": "installed",
+					"hooks":  installed,
+				})
+			}
+			for _, path := range installed {
+				fmt.Fprintf(out, "Installed %s\n", path)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("force", false, "Replace existing hooks not written by floop (they are kept as <hook>.floop-backup)")
+
+	return cmd
+}
+
+func newHooksUninstallCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "uninstall",
+		Short: "Remove floop's git hooks, restoring any backed-up hooks",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			hooksDir, err := hooks.GitHooksDir(root)
+			if err != nil {
+				return err
+			}
+			removed, err := hooks.UninstallGitHooks(hooksDir)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status": "uninstalled",
+					"hooks":  removed,
+				})
+			}
+			if len(removed) == 0 {
+				fmt.Fprintln(out, "No floop git hooks installed.")
+			}
+			for _, path := range removed {
+				fmt.Fprintf(out, "Removed %s\n", path)
+			}
+			return nil
+		},
+	}
+}
+
+// newHooksRecordCmd creates the 'hooks record' subcommand run by the
+// installed git hooks. It is silent and never fails the commit.
+func newHooksRecordCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:       "record <pre-commit|post-commit>",
+		Short:     "Record a git hook event (run by the installed hooks)",
+		Hidden:    true,
+		Args:      cobra.ExactArgs(1),
+		ValidArgs: hooks.GitHookNames,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			if !floopDirExists(root) {
+				return nil
+			}
+			var err error
+			switch args[0] {
+			case "pre-commit":
+				err = recordPreCommit(cmd.Context(), root)
+			case "post-commit":
+				err = recordPostCommit(root)
+			default:
+				return fmt.Errorf("unknown git hook %q (valid: %s)", args[0], strings.Join(hooks.GitHookNames, ", "))
+			}
+			if err != nil {
+				hookLog(root, "git-"+args[0], "record", "error", map[string]interface{}{"error": err.Error()})
+			}
+			return nil
+		},
+	}
+}
+
+// recordPreCommit records an activation for each behavior active for the
+// staged files and saves them for post-commit.
+func recordPreCommit(ctx context.Context, root string) error {
+	files, err := hooks.GitStagedFiles(root)
+	if err != nil {
+		return err
+	}
+	if len(files) > gitHookMaxFiles {
+		files = files[:gitHookMaxFiles]
+	}
+	behaviors, err := loadBehaviorsWithScope(root, constants.ScopeBoth)
+	if err != nil {
+		return err
+	}
+
+	// Build the context once; Build shells out to git for the branch.
+	base := activation.NewContextBuilder().WithRepoRoot(root).Build()
+	activeIDs := activeForFiles(base, files, behaviors)
+	rec := hooks.GitCommitRecord{
+		Branch:    base.Branch,
+		Files:     files,
+		Behaviors: activeIDs,
+		Timestamp: time.Now(),
+	}
+
+	if len(activeIDs) > 0 {
+		graphStore, err := store.NewMultiGraphStore(root)
+		if err != nil {
+			return fmt.Errorf("failed to open graph store: %w", err)
+		}
+		defer graphStore.Close()
+
+		trackedSession := session.Current(root)
+		sessionID := trackedSession
+		if sessionID == "" {
+			sessionID = "git:" + base.Branch
+		}
+		for _, id := range activeIDs {
+			if err := graphStore.RecordSessionActivation(ctx, id, sessionID); err != nil {
+				hookLog(root, "git-pre-commit", "activation", "error", map[string]interface{}{"behavior_id": id, "error": err.Error()})
+			}
+		}
+		if trackedSession != "" {
+			if err := session.Track(ctx, graphStore, trackedSession, session.EventActivation, activeIDs...); err != nil {
+				hookLog(root, "git-pre-commit", "session", "error", map[string]interface{}{"error": err.Error()})
+			}
+		}
+	}
+
+	return hooks.SavePendingCommit(filepath.Join(root, ".floop"), rec)
+}
+
+// recordPostCommit appends the commit recorded by pre-commit to the log,
+// now that it has landed.
+func recordPostCommit(root string) error {
+	floopDir := filepath.Join(root, ".floop")
+	rec, err := hooks.TakePendingCommit(floopDir)
+	if err != nil || rec == nil {
+		return err
+	}
+	if rec.Commit, err = hooks.GitHead(root); err != nil {
+		return err
+	}
+
+	return hooks.AppendCommitLog(floopDir, *rec)
+}
+
+// activeForFiles returns the sorted IDs of behaviors active for any of the
+// files in the base context, or for the base context alone when no files
+// are given. Seed behaviors are skipped, as in floop_active.
+func activeForFiles(base models.ContextSnapshot, files []string, behaviors []models.Behavior) []string {
+	contexts := []models.ContextSnapshot{base}
+	if len(files) > 0 {
+		contexts = contexts[:0]
+		for _, f := range files {
+			c := base
+			c.FilePath = f
+			c.FileLanguage = models.InferLanguage(f)
+			c.FileExt = filepath.Ext(f)
+			contexts = append(contexts, c)
+		}
+	}
+
+	evaluator := activation.NewEvaluator()
+	resolver := activation.NewResolver()
+	seen := make(map[string]bool)
+	var ids []string
+	for _, c := range contexts {
+		resolved := resolver.Resolve(evaluator.Evaluate(c, behaviors))
+		for _, b := range resolved.Active {
+			if seen[b.ID] || strings.HasPrefix(b.ID, "seed-") {
+				continue
+			}
+			seen[b.ID] = true
+			ids = append(ids, b.ID)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
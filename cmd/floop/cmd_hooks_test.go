@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/hooks"
+)
+
+func TestHooksCmdInstallAndRecord(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = tmpDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Skipf("git %v: %v %s", args, err, out)
+		}
+	}
+	git("init", "-q")
+
+	run := func(args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newInitCmd(), newLearnCmd(), newHooksCmd())
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		var outBuf bytes.Buffer
+		rootCmd.SetOut(&outBuf)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return outBuf.String()
+	}
+	run("init")
+	run("learn", "--wrong", "used ioutil.ReadFile", "--right", "use os.ReadFile in Go code", "--file", "main.go", "--scope", "local")
+
+	var installed map[string]any
+	if err := json.Unmarshal([]byte(run("hooks", "install", "--json")), &installed); err != nil {
+		t.Fatal(err)
+	}
+	if hookPaths, _ := installed["hooks"].([]any); len(hookPaths) != 2 {
+		t.Errorf("installed = %v", installed)
+	}
+
+	if err := os.WriteFile(filepath.Join(tmpDir, "main.go"), []byte("package main\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	git("add", "main.go")
+	run("hooks", "record", "pre-commit")
+
+	floopDir := filepath.Join(tmpDir, ".floop")
+	data, err := os.ReadFile(filepath.Join(floopDir, hooks.GitPendingFile))
+	if err != nil {
+		t.Fatalf("pre-commit left no pending record: %v", err)
+	}
+	var pending hooks.GitCommitRecord
+	if err := json.Unmarshal(data, &pending); err != nil {
+		t.Fatal(err)
+	}
+	if len(pending.Files) != 1 || pending.Files[0] != "main.go" || len(pending.Behaviors) == 0 {
+		t.Fatalf("pending = %+v", pending)
+	}
+
+	// The installed hooks call 'floop' from PATH, so run post-commit directly.
+	git("commit", "-q", "--no-verify", "-m", "add main")
+	run("hooks", "record", "post-commit")
+
+	log, err := os.ReadFile(filepath.Join(floopDir, hooks.GitCommitLogFile))
+	if err != nil || !strings.Contains(string(log), `"commit":"`) {
+		t.Fatalf("commit log = %s, %v", log, err)
+	}
+	if _, err := os.Stat(filepath.Join(floopDir, hooks.GitPendingFile)); !os.IsNotExist(err) {
+		t.Error("post-commit should clear the pending record")
+	}
+
+	behaviors, err := loadBehaviorsWithScope(tmpDir, constants.ScopeLocal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, b := range behaviors {
+		if b.ID != pending.Behaviors[0] {
+			continue
+		}
+		found = true
+		if b.Stats.TimesActivated != 1 || b.Stats.TimesConfirmed != 0 {
+			t.Errorf("stats = %+v, want 1 activation and no confirmation", b.Stats)
+		}
+	}
+	if !found {
+		t.Errorf("behavior %s not in local store", pending.Behaviors[0])
+	}
+
+	if out := run("hooks", "uninstall"); strings.Count(out, "Removed") != 2 {
+		t.Errorf("uninstall output = %q", out)
+	}
+}
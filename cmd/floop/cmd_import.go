@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/edges"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import behaviors from outside floop",
+	}
+
+	cmd.AddCommand(newImportRulesCmd())
+
+	return cmd
+}
+
+func newImportRulesCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rules <file>",
+		Short: "Import behaviors from an agent rules file",
+		Long: `Bootstrap floop from the conventions already written down in an agent
+instruction file such as CLAUDE.md, AGENTS.md, or .cursorrules.
+
+Each top-level bullet or numbered item becomes a behavior; nested items
+and wrapped lines are folded into their parent. A file without list items
+is read one rule per line. Code blocks, HTML comments, and the section
+written by 'floop export rules --sync' are ignored.
+
+The kind of each behavior is inferred from its wording ("never ..." is a
+constraint, "prefer ..." a preference), and tags are extracted from the
+rule and the heading it sits under. Rules that match an existing behavior,
+or an earlier rule in the file, are reported as duplicates and not added,
+so importing a file twice adds nothing. Rules shorter than three words and
+bare links are skipped.
+
+Examples:
+  floop import rules CLAUDE.md
+  floop import rules AGENTS.md --dry-run
+  floop import rules ~/.claude/CLAUDE.md --scope global`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			scopeVal, _ := cmd.Flags().GetString("scope")
+			threshold, _ := cmd.Flags().GetFloat64("threshold")
+			path := args[0]
+			ctx := cmd.Context()
+
+			scope := constants.Scope(scopeVal)
+			if scope != constants.ScopeLocal && scope != constants.ScopeGlobal {
+				return fmt.Errorf("--scope must be 'local' or 'global'")
+			}
+			if threshold < 0 || threshold > 1 {
+				return fmt.Errorf("--threshold must be between 0.0 and 1.0")
+			}
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("failed to read rules file: %w", err)
+			}
+			rules := learning.ParseRules(string(data))
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			existing, err := edges.LoadBehaviorsFromStore(ctx, graphStore)
+			if err != nil {
+				return fmt.Errorf("failed to load behaviors: %w", err)
+			}
+
+			floopCfg, err := loadConfig(cmd)
+			if err != nil {
+				slog.Warn("failed to load config", "error", err)
+			}
+			llmClient := createLLMClient(floopCfg)
+
+			result := learning.ImportRules(existing, rules, learning.RuleImportOptions{
+				Source:              filepath.Base(path),
+				SimilarityThreshold: threshold,
+				UseLLM:              floopCfg != nil && floopCfg.LLM.Enabled && llmClient != nil,
+				LLMClient:           llmClient,
+				Now:                 time.Now(),
+			})
+
+			derived := 0
+			if !dryRun && len(result.Behaviors) > 0 {
+				var added []string
+				for _, b := range result.Behaviors {
+					if _, err := graphStore.AddNodeToScope(ctx, models.BehaviorToNode(b), scope); err != nil {
+						// Same content as a forgotten or merged behavior
+						var dup *store.DuplicateContentError
+						if !errors.As(err, &dup) {
+							return fmt.Errorf("failed to add %s: %w", b.ID, err)
+						}
+						markRuleDuplicate(result, b.ID, dup.ExistingID)
+						continue
+					}
+					added = append(added, b.ID)
+				}
+
+				all, err := edges.LoadBehaviorsFromStore(ctx, graphStore)
+				if err == nil && len(added) > 0 {
+					var sub *edges.SubsetResult
+					if sub, err = edges.DeriveEdgesForSubsetWithTuning(ctx, graphStore, added, all, scopeSimilarityTuning(root, scope)); err == nil {
+						derived = sub.EdgesCreated
+					}
+				}
+				if err != nil {
+					slog.Warn("edge derivation failed", "error", err)
+				}
+				if err := graphStore.Sync(ctx); err != nil {
+					return fmt.Errorf("failed to sync changes: %w", err)
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"result":        result,
+					"scope":         scope,
+					"dry_run":       dryRun,
+					"derived_edges": derived,
+				})
+			}
+
+			if dryRun {
+				fmt.Fprintf(out, "Dry run: importing rules from %s\n", path)
+			} else {
+				fmt.Fprintf(out, "Imported rules from %s\n", path)
+			}
+			for _, r := range result.Rules {
+				switch r.Action {
+				case learning.RuleAdded:
+					fmt.Fprintf(out, "  %-9s line %-4d %s [%s] %s\n", r.Action, r.Line, r.ID, r.Kind, truncatePreview(r.Text, 60))
+				case learning.RuleDuplicate:
+					fmt.Fprintf(out, "  %-9s line %-4d of %s (similarity %.2f) %s\n", r.Action, r.Line, r.ID, r.Similarity, truncatePreview(r.Text, 40))
+				default:
+					fmt.Fprintf(out, "  %-9s line %-4d %s: %s\n", r.Action, r.Line, r.Reason, truncatePreview(r.Text, 40))
+				}
+			}
+			fmt.Fprintf(out, "\n%d added, %d duplicates, %d skipped\n", result.Added, result.Duplicates, result.Skipped)
+			if !dryRun && result.Added > 0 {
+				fmt.Fprintf(out, "Stored in %s scope; %d edges derived\n", scope, derived)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would be imported without storing anything")
+	cmd.Flags().String("scope", string(constants.ScopeLocal), "Store in local (project) or global (user) scope")
+	cmd.Flags().Float64("threshold", constants.DefaultAutoMergeThreshold, "Similarity at which a rule duplicates an existing behavior (0.0-1.0)")
+
+	return cmd
+}
+
+// markRuleDuplicate records that the behavior for a rule, which ImportRules
+// planned to add as id, turned out to duplicate existingID.
+func markRuleDuplicate(result *learning.RuleImportResult, id, existingID string) {
+	for i := range result.Rules {
+		r := &result.Rules[i]
+		if r.Action == learning.RuleAdded && r.ID == id {
+			r.Action, r.ID, r.Similarity = learning.RuleDuplicate, existingID, 1
+			result.Added--
+			result.Duplicates++
+			return
+		}
+	}
+}
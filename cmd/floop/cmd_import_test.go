@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/learning"
+)
+
+func TestImportRulesCmd(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	rulesPath := filepath.Join(tmpDir, "CLAUDE.md")
+	rules := "# Conventions\n\n## Go\n- Never use panic in library code\n- Prefer errors.Is over comparing error strings\n- ok\n"
+	if err := os.WriteFile(rulesPath, []byte(rules), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newImportCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append(append([]string{"import", "rules"}, args...), "--root", tmpDir))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+	decode := func(out string) learning.RuleImportResult {
+		t.Helper()
+		var resp struct {
+			Result learning.RuleImportResult `json:"result"`
+		}
+		if err := json.Unmarshal([]byte(out), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		return resp.Result
+	}
+
+	if _, err := run(rulesPath, "--scope", "team"); err == nil {
+		t.Error("invalid --scope succeeded, want error")
+	}
+	if _, err := run(filepath.Join(tmpDir, "missing.md")); err == nil {
+		t.Error("missing file succeeded, want error")
+	}
+
+	out, err := run(rulesPath, "--dry-run")
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !strings.Contains(out, "2 added, 0 duplicates, 1 skipped") {
+		t.Errorf("dry run output:\n%s", out)
+	}
+
+	out, err = run(rulesPath, "--json")
+	if err != nil {
+		t.Fatalf("import failed: %v", err)
+	}
+	first := decode(out)
+	if first.Added != 2 || first.Skipped != 1 {
+		t.Fatalf("first import = %+v", first)
+	}
+	if first.Rules[0].Kind != "constraint" || first.Rules[1].Kind != "preference" {
+		t.Errorf("kinds = %s, %s", first.Rules[0].Kind, first.Rules[1].Kind)
+	}
+
+	out, err = run(rulesPath, "--json")
+	if err != nil {
+		t.Fatalf("second import failed: %v", err)
+	}
+	if second := decode(out); second.Added != 0 || second.Duplicates != 2 {
+		t.Errorf("second import = %+v, want everything reported as a duplicate", second)
+	}
+}
@@ -0,0 +1,223 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
+	"github.com/nvandessel/floop/internal/vectorsearch"
+	"github.com/spf13/cobra"
+)
+
+func newIndexCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "index",
+		Short: "Manage the vector index used for behavior retrieval",
+		Long: `Manage the project's vector index (.floop/vectors).
+
+Behaviors are embedded with the model configured under embedding in
+~/.floop/config.yaml, entirely on the local machine: either a GGUF model
+run in-process with llama.cpp (embedding.backend: local, installed with
+'floop init --embeddings') or an OpenAI-compatible embedding server such as
+llama-server or Ollama (embedding.backend: server).
+
+Examples:
+  floop index rebuild
+  floop index rebuild --missing-only`,
+	}
+
+	cmd.AddCommand(newIndexRebuildCmd())
+
+	return cmd
+}
+
+// indexRebuildResult is the outcome of 'floop index rebuild'.
+type indexRebuildResult struct {
+	Model     string   `json:"model"`
+	Dimension int      `json:"dimension"`
+	Behaviors int      `json:"behaviors"`
+	Embedded  int      `json:"embedded"`
+	Indexed   int      `json:"indexed"`
+	IndexDir  string   `json:"index_dir,omitempty"`
+	Warnings  []string `json:"warnings,omitempty"`
+}
+
+func newIndexRebuildCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rebuild",
+		Short: "Re-embed behaviors and rebuild the vector index",
+		Long: `Re-embed every active behavior with the configured embedding model and
+rebuild the vector index from scratch.
+
+Run it after changing embedding models, since vectors from different models
+cannot be compared. With --missing-only, only behaviors without a stored
+embedding are embedded before the index is rebuilt. Stop running MCP
+servers for the project first; they load the index at startup.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			missingOnly, _ := cmd.Flags().GetBool("missing-only")
+			ctx := cmd.Context()
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return err
+			}
+			if err := cfg.Validate(); err != nil {
+				return fmt.Errorf("invalid config: %w", err)
+			}
+			embedder, localClient := vectorsearch.NewEmbedderFromConfig(cfg)
+			if embedder == nil {
+				return fmt.Errorf("no embedding model available: run 'floop init --embeddings' or set embedding.backend")
+			}
+			if localClient != nil {
+				defer localClient.Close()
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return errNotInitialized
+			}
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open store: %w", err)
+			}
+			defer graphStore.Close()
+
+			nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+			if err != nil {
+				return fmt.Errorf("failed to query behaviors: %w", err)
+			}
+			live := make(map[string]bool, len(nodes))
+			for _, n := range nodes {
+				live[n.ID] = true
+			}
+
+			var ids []string
+			if missingOnly {
+				ids, err = graphStore.GetBehaviorIDsWithoutEmbeddings(ctx)
+				if err != nil {
+					return fmt.Errorf("failed to find unembedded behaviors: %w", err)
+				}
+			} else {
+				for id := range live {
+					ids = append(ids, id)
+				}
+			}
+			slices.Sort(ids)
+
+			result := indexRebuildResult{Model: embedder.ModelName(), Behaviors: len(ids)}
+			embedded, err := embedder.EmbedBehaviors(ctx, graphStore, ids)
+			result.Embedded = embedded
+			if err != nil {
+				if embedded == 0 {
+					return err
+				}
+				result.Warnings = append(result.Warnings, err.Error())
+			}
+
+			if err := rebuildVectorIndex(ctx, graphStore, embedder, live, filepath.Join(floopDir, "vectors"), &result); err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(result)
+			}
+			fmt.Fprintf(out, "Embedded %d of %d behavior(s) with %s\n", result.Embedded, result.Behaviors, result.Model)
+			if result.IndexDir != "" {
+				fmt.Fprintf(out, "Rebuilt vector index with %d vector(s) (%d dims) in %s\n", result.Indexed, result.Dimension, result.IndexDir)
+			}
+			for _, w := range result.Warnings {
+				fmt.Fprintf(out, "Warning: %s\n", w)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("missing-only", false, "Only embed behaviors without a stored embedding")
+
+	return cmd
+}
+
+// rebuildVectorIndex recreates the LanceDB index in vectorDir from the
+// stored embeddings of live behaviors. Embeddings whose size differs from
+// the embedder's (from an earlier model) are left out. If LanceDB is
+// unavailable, the embeddings stay in the store and the MCP server
+// searches them by brute force.
+func rebuildVectorIndex(ctx context.Context, gs *store.MultiGraphStore, embedder *vectorsearch.Embedder, live map[string]bool, vectorDir string, result *indexRebuildResult) error {
+	all, err := gs.GetAllEmbeddings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to load embeddings: %w", err)
+	}
+
+	var current []store.BehaviorEmbedding
+	for _, emb := range all {
+		if live[emb.BehaviorID] && len(emb.Embedding) > 0 {
+			current = append(current, emb)
+		}
+	}
+
+	dims := embedder.Dimension()
+	if dims == 0 {
+		// Size the index by this model's vectors, or by the first if none are its own
+		for _, emb := range current {
+			if emb.ModelName == embedder.ModelName() {
+				dims = len(emb.Embedding)
+				break
+			}
+			if dims == 0 {
+				dims = len(emb.Embedding)
+			}
+		}
+	}
+	if dims == 0 {
+		result.Warnings = append(result.Warnings, "no embeddings to index")
+		return nil
+	}
+	result.Dimension = dims
+
+	skipped := 0
+	matching := current[:0]
+	for _, emb := range current {
+		if len(emb.Embedding) != dims {
+			skipped++
+			continue
+		}
+		matching = append(matching, emb)
+	}
+	if skipped > 0 {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("%d embedding(s) from another model left out of the index; run without --missing-only to re-embed them", skipped))
+	}
+
+	if err := os.RemoveAll(vectorDir); err != nil {
+		return fmt.Errorf("failed to remove old vector index: %w", err)
+	}
+	if err := os.MkdirAll(vectorDir, 0o755); err != nil {
+		return fmt.Errorf("failed to create vector directory: %w", err)
+	}
+	idx, err := vectorindex.NewLanceDBIndex(vectorindex.LanceDBConfig{Dir: vectorDir, Dims: dims})
+	if err != nil {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("vector index not built (%v); embeddings are stored and will be searched by brute force", err))
+		return nil
+	}
+	defer idx.Close()
+
+	for _, emb := range matching {
+		if err := idx.Add(ctx, emb.BehaviorID, emb.Embedding); err != nil {
+			return fmt.Errorf("failed to index %s: %w", emb.BehaviorID, err)
+		}
+	}
+	if err := idx.Save(ctx); err != nil {
+		return fmt.Errorf("failed to save vector index: %w", err)
+	}
+	result.Indexed = len(matching)
+	result.IndexDir = vectorDir
+	return nil
+}
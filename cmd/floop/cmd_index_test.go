@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestIndexRebuildCmd(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	if _, err := runCmd(t, newIndexCmd(), "rebuild", "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "floop init --embeddings") {
+		t.Fatalf("rebuild without a model: error = %v, want setup hint", err)
+	}
+
+	var requests int
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var data []string
+		for i := range req.Input {
+			data = append(data, fmt.Sprintf(`{"index":%d,"embedding":[1,0,0]}`, i))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+	defer ts.Close()
+
+	configYAML := fmt.Sprintf("embedding:\n  backend: server\n  url: %s\n  model: all-minilm\n  dimension: 3\n  batch_size: 8\n", ts.URL)
+	if err := os.WriteFile(filepath.Join(tmpDir, "home", ".floop", "config.yaml"), []byte(configYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCmd(t, newIndexCmd(), "rebuild", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("index rebuild: %v", err)
+	}
+	var result indexRebuildResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Model != "all-minilm" || result.Dimension != 3 || result.Behaviors == 0 || result.Embedded != result.Behaviors {
+		t.Errorf("result = %+v, want every behavior embedded with all-minilm", result)
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests, want one batch", requests)
+	}
+
+	gs, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gs.Close()
+	embs, err := gs.GetAllEmbeddings(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(embs) != result.Embedded || embs[0].ModelName != "all-minilm" || len(embs[0].Embedding) != 3 {
+		t.Errorf("stored embeddings = %+v", embs)
+	}
+
+	out, err = runCmd(t, newIndexCmd(), "rebuild", "--missing-only", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("index rebuild --missing-only: %v", err)
+	}
+	if !strings.Contains(out, "Embedded 0 of 0 behavior(s) with all-minilm") {
+		t.Errorf("unexpected --missing-only output:\n%s", out)
+	}
+}
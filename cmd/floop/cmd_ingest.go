@@ -5,11 +5,17 @@ import (
 	"database/sql"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
+	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/events"
+	"github.com/nvandessel/floop/internal/ingest"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 	_ "modernc.org/sqlite"
 )
@@ -19,26 +25,45 @@ func init() {
 	events.RegisterAdapter(&events.MarkdownAdapter{})
 	events.RegisterAdapter(&events.JSONLAdapter{})
 	events.RegisterAdapter(&events.JSONAdapter{})
+	events.RegisterAdapter(&events.CursorAdapter{})
+	events.RegisterAlias("claude", "claude-code-jsonl")
 }
 
 func newIngestCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "ingest [file]",
 		Short: "Import conversation transcript into event buffer",
-		Long:  `Parses a transcript file (or stdin) and stores events for consolidation.`,
-		RunE:  runIngest,
+		Long: `Parses a transcript file (or stdin) and stores events for consolidation.
+
+With --learn, user messages that look like corrections are also run
+through the learning loop, as with 'floop learn --text'. Each correction
+is scoped to the file the agent touched just before it, so learning from
+a session log needs no manual --file or --wrong.
+
+Examples:
+  floop ingest ~/.claude/projects/-home-me-app/8f2c.jsonl --format claude --learn
+  floop ingest cursor-chat.json --format cursor --learn --dry-run`,
+		RunE: runIngest,
 	}
-	cmd.Flags().String("format", "markdown", "Transcript format (markdown, claude-code-jsonl, generic-json)")
+	cmd.Flags().String("format", "markdown", "Transcript format (markdown, claude-code-jsonl or claude, cursor, generic-json)")
 	cmd.Flags().String("source", "", "Agent source identifier (e.g., claude-code, gemini)")
 	cmd.Flags().String("session", "", "Session ID (auto-generated if empty)")
+	cmd.Flags().Bool("learn", false, "Learn behaviors from corrections found in the transcript")
+	cmd.Flags().Bool("dry-run", false, "With --learn, report the corrections found without storing anything")
+	cmd.Flags().String("scope", "", "With --learn, override auto-classification: local (project) or global (user)")
+	cmd.Flags().Bool("auto-merge", true, "With --learn, merge corrections into similar existing behaviors")
 	return cmd
 }
 
 func runIngest(cmd *cobra.Command, args []string) error {
+	out := newOutput(cmd)
 	format, _ := cmd.Flags().GetString("format")
 	source, _ := cmd.Flags().GetString("source")
 	session, _ := cmd.Flags().GetString("session")
 	jsonOut, _ := cmd.Flags().GetBool("json")
+	learn, _ := cmd.Flags().GetBool("learn")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	root, _ := cmd.Flags().GetString("root")
 
 	// Get adapter
 	adapter, ok := events.GetAdapter(format)
@@ -76,6 +101,21 @@ func runIngest(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	if dryRun && !learn {
+		return fmt.Errorf("--dry-run requires --learn")
+	}
+
+	var learned *learning.ImportResult
+	if learn {
+		learned, err = learnFromTranscript(cmd, root, parsed, dryRun)
+		if err != nil {
+			return err
+		}
+	}
+	if dryRun {
+		return printIngestResult(out, jsonOut, format, len(parsed), learned)
+	}
+
 	// Open global store DB
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
@@ -106,16 +146,93 @@ func runIngest(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("adding events: %w", err)
 	}
 
-	out := cmd.OutOrStdout()
+	return printIngestResult(out, jsonOut, format, len(parsed), learned)
+}
+
+// learnFromTranscript scans parsed transcript events for corrections and
+// learns from them like 'learn import' does.
+func learnFromTranscript(cmd *cobra.Command, root string, parsed []events.Event, dryRun bool) (*learning.ImportResult, error) {
+	floopDir := filepath.Join(root, ".floop")
+	if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+		return nil, errNotInitialized
+	}
+	correctionsPath := filepath.Join(floopDir, "corrections.jsonl")
+	seen, err := learnedCorrectionIDs(correctionsPath)
+	if err != nil {
+		return nil, err
+	}
+
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open graph store: %w", err)
+	}
+	defer graphStore.Close()
+
+	loopConfig, err := newLearnLoopConfig(cmd, graphStore, root)
+	if err != nil {
+		return nil, err
+	}
+	loop := learning.NewLearningLoop(graphStore, applyReviewHold(root, withOpLog(root, loopConfig)))
+
+	floopCfg, err := config.LoadForProject(root)
+	if err != nil {
+		floopCfg = config.Default()
+	}
+	client := createLLMClient(floopCfg)
+	if c, ok := client.(llm.Closer); ok {
+		defer c.Close()
+	}
+
+	records := ingest.Scan(parsed, learning.NewCorrectionCapture())
+	result, err := learning.ImportCorrections(context.Background(), loop, records, learning.ImportOptions{
+		Client: client,
+		Seen:   seen,
+		DryRun: dryRun,
+	})
+	if result != nil && len(result.Corrections) > 0 {
+		if logErr := appendCorrections(correctionsPath, result.Corrections); logErr != nil {
+			return nil, logErr
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("learning from transcript: %w", err)
+	}
+	return result, nil
+}
+
+// printIngestResult reports ingested events and, with --learn, what was learned.
+func printIngestResult(out io.Writer, jsonOut bool, format string, count int, learned *learning.ImportResult) error {
 	if jsonOut {
-		json.NewEncoder(out).Encode(map[string]interface{}{
+		resp := map[string]interface{}{
 			"status": "ingested",
-			"count":  len(parsed),
+			"count":  count,
 			"format": format,
-		})
-	} else {
-		fmt.Fprintf(out, "Ingested %d events from %s format.\n", len(parsed), format)
+		}
+		if learned != nil {
+			if learned.DryRun {
+				resp["status"] = "dry_run"
+			}
+			resp["learned"] = learned
+		}
+		return json.NewEncoder(out).Encode(resp)
 	}
 
+	if learned == nil || !learned.DryRun {
+		fmt.Fprintf(out, "Ingested %d events from %s format.\n", count, format)
+	}
+	if learned == nil {
+		return nil
+	}
+	if learned.DryRun {
+		fmt.Fprintf(out, "Found %d corrections in %d events (dry run)\n", learned.Total-learned.Skipped, count)
+	} else {
+		fmt.Fprintf(out, "Learned from %d corrections:\n", learned.Total)
+		fmt.Fprintf(out, "  Added:   %d\n", learned.Added)
+		fmt.Fprintf(out, "  Merged:  %d\n", learned.Merged)
+	}
+	fmt.Fprintf(out, "  Skipped: %d\n", learned.Skipped)
+	for _, issue := range learned.Issues {
+		fmt.Fprintf(out, "    event %d: %s\n", issue.Line, issue.Reason)
+	}
 	return nil
 }
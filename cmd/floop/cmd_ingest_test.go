@@ -132,3 +132,85 @@ func TestIngestCmdNonexistentFile(t *testing.T) {
 		t.Errorf("error = %q, want it to contain 'opening file'", err.Error())
 	}
 }
+
+func TestIngestCmdLearn(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	transcriptPath := filepath.Join(tmpDir, "session.jsonl")
+	transcript := strings.Join([]string{
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","name":"Edit","input":{"file_path":"` + tmpDir + `/internal/db/conn.go"}}]},"cwd":"` + tmpDir + `","gitBranch":"main"}`,
+		`{"type":"user","message":{"role":"user","content":"No, don't open a connection per query, reuse the pool instead."},"cwd":"` + tmpDir + `"}`,
+		`{"type":"user","message":{"role":"user","content":"looks good"}}`,
+	}, "\n")
+	if err := os.WriteFile(transcriptPath, []byte(transcript), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	runIngest := func(extra ...string) map[string]any {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newIngestCmd())
+		rootCmd.SetArgs(append([]string{"ingest", transcriptPath, "--format", "claude", "--learn", "--root", tmpDir, "--json"}, extra...))
+		var outBuf bytes.Buffer
+		rootCmd.SetOut(&outBuf)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("ingest failed: %v", err)
+		}
+		var resp map[string]any
+		if err := json.Unmarshal(outBuf.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, outBuf.String())
+		}
+		return resp
+	}
+
+	resp := runIngest("--dry-run")
+	if resp["status"] != "dry_run" {
+		t.Errorf("dry run status = %v", resp["status"])
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, "home", ".floop", "floop.db")); !os.IsNotExist(err) {
+		t.Errorf("dry run should not store events, stat err = %v", err)
+	}
+
+	learned, _ := runIngest()["learned"].(map[string]any)
+	if learned["total"] != 1.0 || learned["added"] != 1.0 {
+		t.Errorf("learned = %v, want 1 added", learned)
+	}
+
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".floop", "corrections.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"file_path":"internal/db/conn.go"`) {
+		t.Errorf("correction not scoped to the edited file: %s", data)
+	}
+
+	// Ingesting the same transcript again learns nothing new.
+	if learned, _ := runIngest()["learned"].(map[string]any); learned["added"] != 0.0 || learned["skipped"] != 1.0 {
+		t.Errorf("re-ingest learned = %v", learned)
+	}
+}
+
+func TestIngestCmdDryRunRequiresLearn(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	path := filepath.Join(tmpDir, "t.md")
+	os.WriteFile(path, []byte("User: hi\n"), 0600)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newIngestCmd())
+	rootCmd.SetArgs([]string{"ingest", path, "--dry-run"})
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "--learn") {
+		t.Errorf("error = %v, want --dry-run requires --learn", err)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strconv"
@@ -43,6 +44,7 @@ Examples:
   floop init --global --project       # Both scopes
   floop init --global --hooks=all --token-budget 2000  # Explicit everything`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			globalFlag, _ := cmd.Flags().GetBool("global")
 			projectFlag, _ := cmd.Flags().GetBool("project")
 			hooksFlag, _ := cmd.Flags().GetString("hooks")
@@ -89,7 +91,7 @@ Examples:
 			}
 
 			if doGlobal {
-				globalResult, err := initScope(constants.ScopeGlobal, "", hooksFlag, tokenBudget, jsonOut)
+				globalResult, err := initScope(out, constants.ScopeGlobal, "", hooksFlag, tokenBudget, jsonOut)
 				if err != nil {
 					return fmt.Errorf("global init failed: %w", err)
 				}
@@ -97,7 +99,7 @@ Examples:
 			}
 
 			if doProject {
-				projectResult, err := initScope(constants.ScopeLocal, root, hooksFlag, tokenBudget, jsonOut)
+				projectResult, err := initScope(out, constants.ScopeLocal, root, hooksFlag, tokenBudget, jsonOut)
 				if err != nil {
 					return fmt.Errorf("project init failed: %w", err)
 				}
@@ -106,7 +108,7 @@ Examples:
 
 			// Set up local embeddings if requested
 			if doEmbeddings {
-				embResult, err := setupEmbeddings(jsonOut)
+				embResult, err := setupEmbeddings(out, jsonOut)
 				if err != nil {
 					if embeddingsFlag {
 						// Explicitly requested via --embeddings; fail the command
@@ -117,7 +119,7 @@ Examples:
 						result["embeddings_error"] = err.Error()
 					} else {
 						fmt.Fprintf(os.Stderr, "warning: embedding setup failed: %v\n", err)
-						fmt.Println("You can retry later with: floop init --embeddings")
+						fmt.Fprintln(out, "You can retry later with: floop init --embeddings")
 					}
 				} else {
 					result["embeddings"] = embResult
@@ -125,9 +127,9 @@ Examples:
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(result)
+				json.NewEncoder(out).Encode(result)
 			} else {
-				fmt.Println("\nReady! Your AI agents will now load learned behaviors at session start.")
+				fmt.Fprintln(out, "\nReady! Your AI agents will now load learned behaviors at session start.")
 			}
 
 			return nil
@@ -145,7 +147,7 @@ Examples:
 }
 
 // initScope performs initialization for a single scope (global or project).
-func initScope(scope constants.Scope, projectRoot string, hooksMode string, tokenBudget int, jsonOut bool) (map[string]interface{}, error) {
+func initScope(out io.Writer, scope constants.Scope, projectRoot string, hooksMode string, tokenBudget int, jsonOut bool) (map[string]interface{}, error) {
 	var configRoot string // where .claude/settings.json lives
 	var hookScope hooks.HookScope
 
@@ -195,7 +197,7 @@ func initScope(scope constants.Scope, projectRoot string, hooksMode string, toke
 	}
 
 	if !jsonOut {
-		fmt.Printf("Created %s\n", floopDir)
+		fmt.Fprintf(out, "Created %s\n", floopDir)
 	}
 
 	// 2. Configure Claude Code settings.json
@@ -220,7 +222,7 @@ func initScope(scope constants.Scope, projectRoot string, hooksMode string, toke
 		result["settings"] = configResult.ConfigPath
 
 		if !jsonOut {
-			fmt.Printf("%s %s\n", action, configResult.ConfigPath)
+			fmt.Fprintf(out, "%s %s\n", action, configResult.ConfigPath)
 		}
 	}
 
@@ -233,23 +235,32 @@ func initScope(scope constants.Scope, projectRoot string, hooksMode string, toke
 		}
 		defer globalStore.Close()
 
-		seedResult, err := seed.NewSeeder(globalStore).SeedGlobalStore(context.Background())
+		cfg, err := config.Load()
+		if err != nil {
+			cfg = config.Default()
+		}
+		seedResult, err := seed.NewSeeder(globalStore).WithExperimental(cfg.Seeds.Experimental).SeedGlobalStore(context.Background())
 		if err != nil {
 			return nil, fmt.Errorf("seeding global store: %w", err)
 		}
 
 		if !jsonOut {
 			if len(seedResult.Added) > 0 {
-				fmt.Printf("Seeded %d meta-behavior(s)\n", len(seedResult.Added))
+				fmt.Fprintf(out, "Seeded %d meta-behavior(s)\n", len(seedResult.Added))
 			}
 			if len(seedResult.Updated) > 0 {
-				fmt.Printf("Updated %d meta-behavior(s)\n", len(seedResult.Updated))
+				fmt.Fprintf(out, "Updated %d meta-behavior(s)\n", len(seedResult.Updated))
+			}
+			if len(seedResult.Withdrawn) > 0 {
+				fmt.Fprintf(out, "Withdrew %d experimental meta-behavior(s)\n", len(seedResult.Withdrawn))
 			}
 		}
 		result["seeds"] = map[string]interface{}{
-			"added":   len(seedResult.Added),
-			"updated": len(seedResult.Updated),
-			"skipped": len(seedResult.Skipped),
+			"added":     len(seedResult.Added),
+			"updated":   len(seedResult.Updated),
+			"skipped":   len(seedResult.Skipped),
+			"held":      len(seedResult.Held),
+			"withdrawn": len(seedResult.Withdrawn),
 		}
 	}
 
@@ -348,7 +359,7 @@ func runInteractiveInit() (doGlobal, doProject bool, hooksMode string, tokenBudg
 
 // setupEmbeddings downloads llama.cpp libraries and the embedding model,
 // then updates the floop config to enable local embeddings.
-func setupEmbeddings(jsonOut bool) (map[string]interface{}, error) {
+func setupEmbeddings(out io.Writer, jsonOut bool) (map[string]interface{}, error) {
 	floopDir := setup.DefaultFloopDir()
 	if floopDir == "" {
 		return nil, fmt.Errorf("cannot determine home directory")
@@ -363,7 +374,7 @@ func setupEmbeddings(jsonOut bool) (map[string]interface{}, error) {
 	detected := setup.DetectInstalled(floopDir)
 	if detected.Available {
 		if !jsonOut {
-			fmt.Println("Local embeddings already installed.")
+			fmt.Fprintln(out, "Local embeddings already installed.")
 		}
 		result["status"] = "already_installed"
 		result["lib_path"] = detected.LibPath
@@ -374,7 +385,7 @@ func setupEmbeddings(jsonOut bool) (map[string]interface{}, error) {
 	// Download libraries if needed
 	if detected.LibPath == "" {
 		if !jsonOut {
-			fmt.Println("Downloading llama.cpp libraries...")
+			fmt.Fprintln(out, "Downloading llama.cpp libraries...")
 		}
 		if err := setup.DownloadLibraries(context.Background(), libDir); err != nil {
 			return nil, fmt.Errorf("downloading libraries: %w", err)
@@ -387,7 +398,7 @@ func setupEmbeddings(jsonOut bool) (map[string]interface{}, error) {
 	// Download model if needed
 	if detected.ModelPath == "" {
 		if !jsonOut {
-			fmt.Println("Downloading embedding model (nomic-embed-text-v1.5, ~81 MB)...")
+			fmt.Fprintln(out, "Downloading embedding model (nomic-embed-text-v1.5, ~81 MB)...")
 		}
 		if err := setup.DownloadEmbeddingModel(context.Background(), modelsDir); err != nil {
 			return nil, fmt.Errorf("downloading model: %w", err)
@@ -420,7 +431,7 @@ func setupEmbeddings(jsonOut bool) (map[string]interface{}, error) {
 	}
 
 	if !jsonOut {
-		fmt.Println("Local embeddings enabled.")
+		fmt.Fprintln(out, "Local embeddings enabled.")
 	}
 	result["status"] = "installed"
 	result["config_path"] = configPath
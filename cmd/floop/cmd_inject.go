@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/assembly"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/tiering"
+	"github.com/nvandessel/floop/internal/tokens"
+	"github.com/spf13/cobra"
+)
+
+func newInjectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "inject",
+		Short: "Assemble active behaviors into a context block under a token budget",
+		Long: `Assemble the behaviors active for the current context into a block ready
+to inject into an agent's context.
+
+The pipeline:
+  1. select   - evaluate when-conditions and resolve conflicts
+  2. rank     - score behaviors by relevance to the context
+  3. budget   - assign full, summary, and name-only tiers within --budget
+  4. coalesce - group related full-tier behaviors sharing tags, showing one
+                representative and naming the rest
+  5. compile  - render the block as markdown, xml, json, or an agent's
+                rules file
+
+--budget defaults to the agent profile's token budget (see --profile), or
+token_budget.default from config. The profile's kind boosts, minimum
+activation, and excluded tags also apply.
+
+--format claude-md, cursor-rules, copilot-instructions, or agents-md renders
+the full and summarized behaviors as a complete rules file in that agent's
+native format (CLAUDE.md, .cursor/rules/*.mdc, .github/copilot-instructions.md,
+AGENTS.md), so the file can be regenerated from the learned store.
+
+Examples:
+  floop inject --file main.go --task testing
+  floop inject --file main.go --budget 2000
+  floop inject --file main.go --format xml
+  floop inject --file main.go --format json
+  floop inject --format cursor-rules > .cursor/rules/floop.mdc`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			file, _ := cmd.Flags().GetString("file")
+			task, _ := cmd.Flags().GetString("task")
+			env, _ := cmd.Flags().GetString("env")
+			custom, err := contextFlag(cmd)
+			if err != nil {
+				return err
+			}
+			budget, _ := cmd.Flags().GetInt("budget")
+			format, _ := cmd.Flags().GetString("format")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			if jsonOut {
+				format = "json"
+			}
+			rulesFormat, isRules := assembly.ParseRulesFormat(format)
+			var compileFormat assembly.Format
+			switch format {
+			case "markdown":
+				compileFormat = assembly.FormatMarkdown
+			case "xml":
+				compileFormat = assembly.FormatXML
+			case "json":
+				// JSON wraps the markdown rendering with the selection details.
+				compileFormat = assembly.FormatMarkdown
+			default:
+				if !isRules {
+					return fmt.Errorf("invalid --format %q: must be markdown, json, xml, or a rules format (%s)", format, rulesFormatList())
+				}
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			profile, err := agentProfile(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			if !cmd.Flags().Changed("budget") {
+				budget = profile.Budget(cfg.TokenBudget.Default)
+			}
+			if budget <= 0 {
+				return fmt.Errorf("--budget must be positive")
+			}
+
+			if _, err := os.Stat(filepath.Join(root, ".floop")); os.IsNotExist(err) {
+				return errNotInitialized
+			}
+
+			behaviors, err := loadBehaviorsWithScope(root, constants.ScopeBoth)
+			if err != nil {
+				return fmt.Errorf("failed to load behaviors: %w", err)
+			}
+
+			// Select
+			actCtx := activation.NewContextBuilder().
+				WithFile(file).
+				WithTask(task).
+				WithEnvironment(env).
+				WithRepoRoot(root).
+				WithGitDetection(cfg.Activation.GitContext).
+				WithCustomValues(custom).
+				Build()
+			matches := activation.NewEvaluator().Evaluate(actCtx, profile.Filter(behaviors))
+			resolved := activation.NewResolver().Resolve(matches)
+
+			// Rank and budget
+			scorer := ranking.NewRelevanceScorer(scorerConfig(root, cfg, profile))
+			results, behaviorMap := tiering.ScoredBehaviorsToResults(scorer.ScoreBatch(resolved.Active, &actCtx))
+			plan := tiering.NewActivationTierMapper(profile.TierConfig()).
+				MapResults(results, behaviorMap, budget)
+
+			// Coalesce and compile
+			compiled := assembly.NewCompiler().
+				WithFormat(compileFormat).
+				CompileTieredCoalesced(plan, assembly.NewCoalescer(assembly.DefaultCoalesceConfig()))
+
+			if format == "json" {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"context":              actCtx,
+					"prompt":               compiled.Text,
+					"format":               compiled.Format,
+					"total_tokens":         compiled.TotalTokens,
+					"token_budget":         budget,
+					"full_behaviors":       compiled.IncludedBehaviors,
+					"summarized_behaviors": compiled.SummarizedBehaviors,
+					"name_only_behaviors":  compiled.NameOnlyBehaviorIDs,
+					"omitted_behaviors":    compiled.OmittedBehaviors,
+					"coalesced_behaviors":  compiled.CoalescedBehaviors,
+				})
+			}
+
+			if isRules {
+				// A rules file is static, so it carries the full and summary
+				// content; name-only entries would need floop to expand.
+				text := assembly.CompileRules(rulesFormat, slices.Concat(plan.FullBehaviors, plan.SummarizedBehaviors))
+				fmt.Fprint(out, text)
+				fmt.Fprintf(os.Stderr, "Behaviors: %d full, %d summarized; tokens: ~%d / %d budget\n",
+					len(plan.FullBehaviors), len(plan.SummarizedBehaviors), tokens.EstimateTokens(text), budget)
+				return nil
+			}
+
+			if plan.IncludedCount() == 0 {
+				fmt.Fprintln(out, "No active behaviors for this context.")
+				return nil
+			}
+			fmt.Fprintln(out, compiled.Text)
+
+			fmt.Fprintln(os.Stderr)
+			fmt.Fprintf(os.Stderr, "---\n")
+			fmt.Fprintf(os.Stderr, "Behaviors: %d full (%d coalesced), %d summarized, %d name-only, %d omitted\n",
+				len(plan.FullBehaviors), len(compiled.CoalescedBehaviors), len(plan.SummarizedBehaviors),
+				len(plan.NameOnlyBehaviors), len(plan.OmittedBehaviors))
+			fmt.Fprintf(os.Stderr, "Tokens: ~%d / %d budget\n", compiled.TotalTokens, budget)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("file", "", "Current file path")
+	cmd.Flags().String("task", "", "Current task type")
+	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+	addContextFlag(cmd)
+	cmd.Flags().Int("budget", 0, "Token budget for the block (default token_budget.default)")
+	cmd.Flags().String("format", "markdown", "Output format (markdown, json, xml, "+rulesFormatList()+")")
+
+	return cmd
+}
+
+// agentProfile resolves the agent profile named by --profile, or the
+// config's default profile when the flag is not set.
+func agentProfile(cmd *cobra.Command, cfg *config.FloopConfig) (config.ProfileConfig, error) {
+	name, _ := cmd.Flags().GetString("profile")
+	return cfg.ResolveProfile(name)
+}
+
+// scorerConfig returns the scorer configuration for the profile with graph
+// centrality from the stores under root blended in, weighted by
+// activation.graph_weight. PageRank is computed only when the weight is
+// positive; if it can't be, behaviors are ranked without it.
+func scorerConfig(root string, cfg *config.FloopConfig, profile config.ProfileConfig) ranking.ScorerConfig {
+	sc := cfg.ScorerConfig(profile)
+	if sc.GraphWeight <= 0 {
+		return sc
+	}
+	graphStore, err := openStoreWithScope(root, constants.ScopeBoth)
+	if err != nil {
+		return sc
+	}
+	defer graphStore.Close()
+	if pageRank, err := ranking.ComputePageRank(context.Background(), graphStore, ranking.DefaultPageRankConfig()); err == nil {
+		sc.PageRank = pageRank
+	}
+	return sc
+}
+
+// rulesFormatList lists the agent rules-file formats for help and errors.
+func rulesFormatList() string {
+	names := make([]string, len(assembly.RulesFormats))
+	for i, f := range assembly.RulesFormats {
+		names[i] = string(f)
+	}
+	return strings.Join(names, ", ")
+}
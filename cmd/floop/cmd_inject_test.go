@@ -0,0 +1,99 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestInjectCmdFormats(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	out, err := runCmd(t, newInjectCmd(), "--file", "main.go", "--task", "coding", "--budget", "2000", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("inject failed: %v", err)
+	}
+	if !strings.Contains(out, "slog structured logging") {
+		t.Errorf("markdown output missing behavior: %q", out)
+	}
+
+	out, err = runCmd(t, newInjectCmd(), "--file", "main.go", "--task", "coding", "--format", "xml", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("inject --format xml failed: %v", err)
+	}
+	if !strings.HasPrefix(out, "<") || !strings.Contains(out, "slog structured logging") {
+		t.Errorf("xml output = %q", out)
+	}
+
+	out, err = runCmd(t, newInjectCmd(), "--file", "main.go", "--task", "coding", "--format", "json", "--budget", "500", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("inject --format json failed: %v", err)
+	}
+	var result struct {
+		Prompt              string   `json:"prompt"`
+		TokenBudget         int      `json:"token_budget"`
+		FullBehaviors       []string `json:"full_behaviors"`
+		SummarizedBehaviors []string `json:"summarized_behaviors"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if result.TokenBudget != 500 || len(result.FullBehaviors)+len(result.SummarizedBehaviors) != 1 ||
+		!strings.Contains(result.Prompt, "slog structured logging") {
+		t.Errorf("json result = %+v", result)
+	}
+}
+
+func TestInjectCmdRejectsInvalidInput(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	if _, err := runCmd(t, newInjectCmd(), "--format", "yaml", "--root", tmpDir); err == nil {
+		t.Error("--format yaml should fail")
+	}
+	if _, err := runCmd(t, newInjectCmd(), "--budget", "0", "--root", tmpDir); err == nil {
+		t.Error("--budget 0 should fail")
+	}
+}
+
+func TestInjectCmdProfile(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	out, err := runCmd(t, newInjectCmd(), "--file", "main.go", "--task", "coding", "--format", "json", "--profile", "copilot", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("inject --profile copilot failed: %v", err)
+	}
+	var result struct {
+		TokenBudget int `json:"token_budget"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if result.TokenBudget != 1000 {
+		t.Errorf("token_budget = %d, want the copilot profile's 1000", result.TokenBudget)
+	}
+
+	out, err = runCmd(t, newInjectCmd(), "--format", "json", "--profile", "copilot", "--budget", "300", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("inject --profile --budget failed: %v", err)
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil || result.TokenBudget != 300 {
+		t.Errorf("--budget should override the profile budget: %q", out)
+	}
+
+	if _, err := runCmd(t, newInjectCmd(), "--profile", "nonexistent", "--root", tmpDir); err == nil {
+		t.Error("unknown --profile should fail")
+	}
+}
+
+func TestInjectCmdRulesFormat(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	out, err := runCmd(t, newInjectCmd(), "--file", "main.go", "--task", "coding", "--format", "cursor-rules", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("inject --format cursor-rules failed: %v", err)
+	}
+	if !strings.HasPrefix(out, "---\ndescription:") || !strings.Contains(out, "alwaysApply: true") ||
+		!strings.Contains(out, "slog structured logging") {
+		t.Errorf("unexpected cursor-rules output:\n%s", out)
+	}
+}
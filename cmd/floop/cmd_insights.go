@@ -0,0 +1,156 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/metrics"
+	"github.com/spf13/cobra"
+)
+
+// openMetrics returns the recorder for the project's local usage metrics,
+// or nil unless metrics.enabled is set and the project is initialized.
+func openMetrics(cfg *config.FloopConfig, root string) *metrics.Recorder {
+	if cfg == nil || !cfg.Metrics.Enabled {
+		return nil
+	}
+	return metrics.Open(filepath.Join(root, ".floop"), metrics.SourceCLI)
+}
+
+// recordCommand records the command that ran, its latency, and whether it
+// failed in the project's usage metrics. A nil cfg means the command never
+// ran, and nothing is recorded.
+func recordCommand(cmd *cobra.Command, cfg *config.FloopConfig, start time.Time, err error) {
+	if cmd == nil || cfg == nil {
+		return
+	}
+	root, _ := cmd.Flags().GetString("root")
+	if root == "" {
+		root = "."
+	}
+	openMetrics(cfg, root).Record(metrics.Event{
+		Kind:       metrics.KindCommand,
+		Name:       cmd.CommandPath(),
+		DurationMS: metrics.Since(start),
+		Error:      err != nil,
+	})
+}
+
+func newInsightsCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "insights",
+		Short: "Summarize local usage metrics",
+		Long: `Summarize the usage metrics recorded in .floop/metrics.jsonl: the most
+used commands and MCP tools with their latency and error counts, learn
+outcomes, and how many behaviors activations returned.
+
+Metrics are opt-in and never leave the machine. Enable them with:
+  floop config set metrics.enabled true
+
+Events record only names, outcomes, counts, and durations — no behavior
+content, arguments, or file paths. The file keeps the last 90 days once it
+grows past 4MB.
+
+Examples:
+  floop insights
+  floop insights --days 7
+  floop insights --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			root, _ := cmd.Flags().GetString("root")
+			days, _ := cmd.Flags().GetInt("days")
+			if days < 1 {
+				return fmt.Errorf("--days must be at least 1")
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			path := filepath.Join(root, ".floop", metrics.FileName)
+			since := time.Now().AddDate(0, 0, -days)
+			events, err := metrics.Read(path, since)
+			if err != nil {
+				return fmt.Errorf("reading metrics: %w", err)
+			}
+			insights := metrics.Summarize(events, since)
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"enabled":  cfg.Metrics.Enabled,
+					"path":     path,
+					"days":     days,
+					"insights": insights,
+				})
+			}
+
+			if !cfg.Metrics.Enabled {
+				fmt.Fprintln(out, "Usage metrics are disabled. Enable them with: floop config set metrics.enabled true")
+				if insights.Events == 0 {
+					return nil
+				}
+				fmt.Fprintln(out)
+			}
+			if insights.Events == 0 {
+				fmt.Fprintf(out, "No usage recorded in the last %d days.\n", days)
+				return nil
+			}
+
+			fmt.Fprintf(out, "Usage insights, last %d days (%s)\n", days, path)
+
+			if len(insights.Commands) > 0 {
+				fmt.Fprintf(out, "\n%-34s  %6s  %6s  %9s  %9s\n", "Command", "Count", "Errors", "p50", "p95")
+				for _, c := range insights.Commands {
+					name := c.Name
+					if c.Source == metrics.SourceMCP {
+						name = "mcp: " + name
+					}
+					fmt.Fprintf(out, "%-34s  %6d  %6d  %9s  %9s\n", name, c.Count, c.Errors, formatMS(c.P50MS), formatMS(c.P95MS))
+				}
+			}
+
+			if l := insights.Learn; l.Count > 0 {
+				fmt.Fprintf(out, "\nLearns: %d (p50 %s, p95 %s)\n", l.Count, formatMS(l.P50MS), formatMS(l.P95MS))
+				outcomes := make([]string, 0, len(l.Outcomes))
+				for outcome := range l.Outcomes {
+					outcomes = append(outcomes, outcome)
+				}
+				sort.Slice(outcomes, func(i, j int) bool {
+					if l.Outcomes[outcomes[i]] != l.Outcomes[outcomes[j]] {
+						return l.Outcomes[outcomes[i]] > l.Outcomes[outcomes[j]]
+					}
+					return outcomes[i] < outcomes[j]
+				})
+				for _, outcome := range outcomes {
+					fmt.Fprintf(out, "  %-10s %d\n", outcome, l.Outcomes[outcome])
+				}
+			}
+
+			if a := insights.Activation; a.Count > 0 {
+				fmt.Fprintf(out, "\nActivations: %d (p50 %s, p95 %s)\n", a.Count, formatMS(a.P50MS), formatMS(a.P95MS))
+				fmt.Fprintf(out, "  behaviors per activation: %.1f\n", a.AvgBehaviors)
+				fmt.Fprintf(out, "  activated nothing:        %d\n", a.Empty)
+				fmt.Fprintf(out, "  over time budget:         %d\n", a.Degraded)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("days", 30, "Number of days to summarize")
+
+	return cmd
+}
+
+// formatMS formats a latency in milliseconds for display.
+func formatMS(ms float64) string {
+	if ms >= 1000 {
+		return fmt.Sprintf("%.1fs", ms/1000)
+	}
+	return fmt.Sprintf("%.0fms", ms)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/metrics"
+)
+
+func TestInsightsCmd_Disabled(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	out, err := runCmd(t, newInsightsCmd(), "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("insights failed: %v", err)
+	}
+	if !strings.Contains(out, "floop config set metrics.enabled true") {
+		t.Errorf("expected a hint to enable metrics:\n%s", out)
+	}
+}
+
+func TestInsightsCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	if err := os.MkdirAll(filepath.Join(tmpDir, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.Default()
+	cfg.Metrics.Enabled = true
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Commands run through main are recorded by execute
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInsightsCmd())
+	rootCmd.SetArgs([]string{"insights", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if _, err := execute(context.Background(), rootCmd); err != nil {
+		t.Fatalf("insights failed: %v", err)
+	}
+
+	r := metrics.Open(filepath.Join(tmpDir, ".floop"), metrics.SourceMCP)
+	r.Record(metrics.Event{Kind: metrics.KindTool, Name: "floop_learn", DurationMS: 40})
+	r.Record(metrics.Event{Kind: metrics.KindLearn, Outcome: "merged", DurationMS: 35})
+	r.Record(metrics.Event{Kind: metrics.KindActivation, Count: 3, DurationMS: 12})
+
+	out, err := runCmd(t, newInsightsCmd(), "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("insights failed: %v", err)
+	}
+	for _, want := range []string{"floop insights", "mcp: floop_learn", "Learns: 1", "merged", "Activations: 1", "behaviors per activation: 3.0"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCmd(t, newInsightsCmd(), "--root", tmpDir, "--json")
+	if err != nil {
+		t.Fatalf("insights --json failed: %v", err)
+	}
+	var result struct {
+		Enabled  bool              `json:"enabled"`
+		Insights *metrics.Insights `json:"insights"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if !result.Enabled || result.Insights.Events != 4 || result.Insights.Learn.Outcomes["merged"] != 1 {
+		t.Errorf("unexpected JSON result: %+v", result)
+	}
+
+	if _, err := runCmd(t, newInsightsCmd(), "--root", tmpDir, "--days", "0"); err == nil {
+		t.Error("expected error for --days 0")
+	}
+}
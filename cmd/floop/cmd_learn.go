@@ -3,16 +3,22 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"time"
 
+	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/dedup"
 	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/session"
+	"github.com/nvandessel/floop/internal/similarity"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tagging"
 	"github.com/spf13/cobra"
@@ -31,19 +37,64 @@ whether the behavior can be auto-accepted or requires human review.
 The --wrong flag is optional. When omitted, the behavior is created from
 the --right content alone (the "wrong" action is stored as provenance only).
 
+With --text, the correction is derived from free-form text such as a chat
+excerpt or a review comment ("-" reads it from stdin). The text must look
+like a correction; the configured LLM, if any, extracts the wrong/right
+pair and the file, language, and task it applies to, falling back to
+pattern rules. --file, --task, and --language override what is inferred.
+
+With --expires, the behavior is temporary: it stops activating at the
+given date or after the given TTL, and 'floop maintain expire' later marks
+it dormant.
+
 Examples:
   floop learn --right "use pathlib.Path instead"
-  floop learn --wrong "used os.path" --right "use pathlib.Path instead"`,
+  floop learn --wrong "used os.path" --right "use pathlib.Path instead"
+  floop learn --right "pin grpc to v1.58 until the upgrade lands" --expires 30d
+  floop learn --text "no, don't use pip here, use uv instead"
+  gh pr view 42 --comments | floop learn --text -`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			wrong, _ := cmd.Flags().GetString("wrong")
 			right, _ := cmd.Flags().GetString("right")
 			file, _ := cmd.Flags().GetString("file")
 			task, _ := cmd.Flags().GetString("task")
 			language, _ := cmd.Flags().GetString("language")
 			root, _ := cmd.Flags().GetString("root")
+			text, _ := cmd.Flags().GetString("text")
+
+			// Derive the correction from free-form text
+			var extracted *learning.TextCorrection
+			if text != "" {
+				if wrong != "" || right != "" {
+					return fmt.Errorf("--text cannot be combined with --wrong or --right")
+				}
+				if text == "-" {
+					data, err := io.ReadAll(cmd.InOrStdin())
+					if err != nil {
+						return fmt.Errorf("failed to read text from stdin: %w", err)
+					}
+					text = string(data)
+				}
+				var err error
+				if extracted, err = extractTextCorrection(text); err != nil {
+					return err
+				}
+				wrong, right = extracted.Wrong, extracted.Right
+				if file == "" {
+					file = extracted.FilePath
+				}
+				if task == "" {
+					task = extracted.Task
+				}
+				if language == "" {
+					language = extracted.Language
+				}
+			}
+
 			// Validate required parameters
 			if right == "" {
-				return fmt.Errorf("--right is required and cannot be empty")
+				return fmt.Errorf("--right (or --text) is required and cannot be empty")
 			}
 
 			// Sanitize inputs to prevent stored prompt injection
@@ -71,6 +122,14 @@ Examples:
 
 			// Build context snapshot
 			now := time.Now()
+			var expiresAt *time.Time
+			if expires, _ := cmd.Flags().GetString("expires"); expires != "" {
+				t, err := parseExpiry(expires, now)
+				if err != nil {
+					return err
+				}
+				expiresAt = &t
+			}
 			ctxSnapshot := models.ContextSnapshot{
 				Timestamp: now,
 				FilePath:  file,
@@ -92,13 +151,17 @@ Examples:
 				AgentAction:     wrong,
 				CorrectedAction: right,
 				ExtraTags:       tags,
+				ExpiresAt:       expiresAt,
 				Processed:       false,
 			}
+			if extracted != nil {
+				correction.HumanResponse = sanitize.SanitizeBehaviorContent(text)
+			}
 
 			// Ensure .floop exists
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+				return errNotInitialized
 			}
 
 			// Use persistent graph store with MultiGraphStore
@@ -108,34 +171,16 @@ Examples:
 			}
 			defer graphStore.Close()
 
-			// Process through learning loop with auto-merge support
-			autoMerge, _ := cmd.Flags().GetBool("auto-merge")
-			var loopConfig *learning.LearningLoopConfig
-			if autoMerge {
-				cfg := learning.DefaultLearningLoopConfig()
-				cfg.AutoMerge = true
-				merger := dedup.NewBehaviorMerger(dedup.MergerConfig{})
-				cfg.Deduplicator = dedup.NewStoreDeduplicator(graphStore, merger, dedup.DeduplicatorConfig{
-					SimilarityThreshold: constants.DefaultAutoMergeThreshold,
-					AutoMerge:           true,
-				})
-				loopConfig = &cfg
+			loopConfig, err := newLearnLoopConfig(cmd, graphStore, root)
+			if err != nil {
+				return err
 			}
 
-			// Apply --scope override if explicitly set
-			if cmd.Flags().Changed("scope") {
-				scopeVal, _ := cmd.Flags().GetString("scope")
-				s := constants.Scope(scopeVal)
-				if s != constants.ScopeLocal && s != constants.ScopeGlobal {
-					return fmt.Errorf("--scope must be 'local' or 'global'")
-				}
-				if loopConfig == nil {
-					loopConfig = &learning.LearningLoopConfig{}
-				}
-				loopConfig.ScopeOverride = &s
+			client := extractionLLMClient()
+			if c, ok := client.(llm.Closer); ok {
+				defer c.Close()
 			}
-
-			loop := learning.NewLearningLoop(graphStore, loopConfig)
+			loop := learning.NewLearningLoop(graphStore, withLLMExtraction(applyReviewHold(root, withOpLog(root, loopConfig)), client))
 			ctx := context.Background()
 
 			result, err := loop.ProcessCorrection(ctx, correction)
@@ -159,10 +204,30 @@ Examples:
 			if err := json.NewEncoder(f).Encode(correction); err != nil {
 				return fmt.Errorf("failed to write correction: %w", err)
 			}
+			autoCompactCorrections(floopDir)
+
+			// Group the correction under the active tracked session, if any
+			if id := session.Current(root); id != "" {
+				if err := session.Track(ctx, graphStore, id, session.EventCorrection, correction.ID); err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to track session: %v\n", err)
+				}
+			}
+			if !result.SkippedForgotten {
+				recordAutoBackupLearn(ctx, graphStore)
+			}
 
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			failReview, _ := cmd.Flags().GetBool("fail-review")
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				if result.SkippedForgotten {
+					json.NewEncoder(out).Encode(map[string]interface{}{
+						"status":       "skipped_forgotten",
+						"correction":   correction,
+						"forgotten_id": result.ForgottenBehaviorID,
+					})
+					return nil
+				}
+				resp := map[string]interface{}{
 					"status":          "processed",
 					"correction":      correction,
 					"behavior":        result.CandidateBehavior,
@@ -170,48 +235,199 @@ Examples:
 					"auto_accepted":   result.AutoAccepted,
 					"requires_review": result.RequiresReview,
 					"review_reasons":  result.ReviewReasons,
-				})
+					"held_for_review": result.HeldForReview,
+				}
+				if extracted != nil {
+					resp["extracted"] = extracted
+				}
+				json.NewEncoder(out).Encode(resp)
 			} else {
-				fmt.Println("Correction captured and processed:")
+				fmt.Fprintln(out, "Correction captured and processed:")
+				if extracted != nil {
+					fmt.Fprintf(out, "  (extracted from text by %s, confidence %.2f)\n", extracted.Method, extracted.Confidence)
+				}
 				if correction.AgentAction != "" {
-					fmt.Printf("  Wrong: %s\n", correction.AgentAction)
+					fmt.Fprintf(out, "  Wrong: %s\n", correction.AgentAction)
 				}
-				fmt.Printf("  Right: %s\n", correction.CorrectedAction)
+				fmt.Fprintf(out, "  Right: %s\n", correction.CorrectedAction)
 				if correction.Context.FilePath != "" {
-					fmt.Printf("  File:  %s\n", correction.Context.FilePath)
+					fmt.Fprintf(out, "  File:  %s\n", correction.Context.FilePath)
 				}
 				if correction.Context.Task != "" {
-					fmt.Printf("  Task:  %s\n", correction.Context.Task)
-				}
-				fmt.Println()
-				fmt.Println("Extracted behavior:")
-				fmt.Printf("  ID:   %s\n", result.CandidateBehavior.ID)
-				fmt.Printf("  Name: %s\n", result.CandidateBehavior.Name)
-				fmt.Printf("  Kind: %s\n", result.CandidateBehavior.Kind)
-				fmt.Println()
+					fmt.Fprintf(out, "  Task:  %s\n", correction.Context.Task)
+				}
+				fmt.Fprintln(out)
+				if result.SkippedForgotten {
+					fmt.Fprintf(out, "Not learned: matches forgotten behavior %s.\n", result.ForgottenBehaviorID)
+					fmt.Fprintf(out, "Use 'floop restore %s' to revive it.\n", result.ForgottenBehaviorID)
+					return nil
+				}
+				fmt.Fprintln(out, "Extracted behavior:")
+				fmt.Fprintf(out, "  ID:   %s\n", result.CandidateBehavior.ID)
+				fmt.Fprintf(out, "  Name: %s\n", result.CandidateBehavior.Name)
+				fmt.Fprintf(out, "  Kind: %s\n", result.CandidateBehavior.Kind)
+				if b := result.CandidateBehavior; b.ExpiresAt != nil {
+					fmt.Fprintf(out, "  Expires: %s\n", formatExpiry(*b.ExpiresAt, now))
+				}
+				fmt.Fprintln(out)
 				if result.AutoAccepted {
-					fmt.Println("Status: Auto-accepted")
+					fmt.Fprintln(out, "Status: Auto-accepted")
+				} else if result.HeldForReview {
+					fmt.Fprintln(out, "Status: Held for review (inactive until 'floop review approve')")
+					for _, reason := range result.ReviewReasons {
+						fmt.Fprintf(out, "  - %s\n", reason)
+					}
 				} else if result.RequiresReview {
-					fmt.Println("Status: Requires review")
+					fmt.Fprintln(out, "Status: Requires review")
 					for _, reason := range result.ReviewReasons {
-						fmt.Printf("  - %s\n", reason)
+						fmt.Fprintf(out, "  - %s\n", reason)
 					}
 				}
 			}
 
+			if failReview && result.RequiresReview {
+				return errReviewRequired
+			}
 			return nil
 		},
 	}
 
 	cmd.Flags().String("wrong", "", "What the agent did (optional, stored as provenance only)")
-	cmd.Flags().String("right", "", "What should have been done (required)")
+	cmd.Flags().String("right", "", "What should have been done (required unless --text is given)")
+	cmd.Flags().String("text", "", "Derive the correction from free-form text, or '-' to read it from stdin")
 	cmd.Flags().String("file", "", "Current file path")
 	cmd.Flags().String("task", "", "Current task type")
 	cmd.Flags().String("language", "", "Programming language (e.g. 'go', 'python'). Overrides file extension inference")
 	cmd.Flags().String("scope", "", "Override auto-classification: local (project) or global (user)")
 	cmd.Flags().Bool("auto-merge", true, "Automatically merge similar behaviors (matches MCP behavior)")
 	cmd.Flags().StringSlice("tags", nil, "Additional tags to apply, merged with inferred tags (max 5)")
-	cmd.MarkFlagRequired("right")
+	cmd.Flags().String("expires", "", "Stop applying the behavior at a date (2026-01-31), time (RFC3339), or after a TTL (30d)")
+	cmd.Flags().Bool("fail-review", false, "Exit with status 4 when the learned behavior requires review")
+
+	cmd.AddCommand(newLearnImportCmd())
+
+	return cmd
+}
+
+func newLearnImportCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Learn from a batch of historical corrections",
+		Long: `Ingest many historical corrections, such as ones extracted from past
+pull request review comments, from a JSONL or CSV file.
+
+Each record needs a right action, or free-form text to derive one from (as
+with 'learn --text'); wrong, file, task, language, tags, and timestamp are
+optional. JSONL has one object per line; CSV needs a header row naming the
+columns. A corrections.jsonl from another project can be imported as-is.
+
+Records are processed in order through the learning loop. With
+--auto-merge (the default), each is deduplicated against existing
+behaviors, including ones added earlier in the import. Records already
+imported, invalid, or matching a forgotten behavior are skipped.
+
+Examples:
+  floop learn import review-comments.jsonl
+  floop learn import corrections.csv --dry-run
+  floop learn import old-project/.floop/corrections.jsonl --scope global`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			format, _ := cmd.Flags().GetString("format")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			path := args[0]
+			if format == "" {
+				format = learning.DetectImportFormat(path)
+			}
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return errNotInitialized
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return fmt.Errorf("failed to open import file: %w", err)
+			}
+			records, parseIssues, err := learning.ReadImportRecords(f, format)
+			f.Close()
+			if err != nil {
+				return err
+			}
+
+			correctionsPath := filepath.Join(floopDir, "corrections.jsonl")
+			seen, err := learnedCorrectionIDs(correctionsPath)
+			if err != nil {
+				return err
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			loopConfig, err := newLearnLoopConfig(cmd, graphStore, root)
+			if err != nil {
+				return err
+			}
+			floopCfg, err := loadConfig(cmd)
+			if err != nil {
+				floopCfg = config.Default()
+			}
+			client := createLLMClient(floopCfg)
+			if c, ok := client.(llm.Closer); ok {
+				defer c.Close()
+			}
+
+			loopConfig = applyReviewHold(root, withOpLog(root, loopConfig))
+			if floopCfg.LLM.ExtractBehaviors {
+				loopConfig = withLLMExtraction(loopConfig, client)
+			}
+			loop := learning.NewLearningLoop(graphStore, loopConfig)
+
+			result, err := learning.ImportCorrections(context.Background(), loop, records, learning.ImportOptions{
+				Client: client,
+				Seen:   seen,
+				DryRun: dryRun,
+			})
+			if result != nil && len(result.Corrections) > 0 {
+				// Log what was learned even if a later record failed
+				if logErr := appendCorrections(correctionsPath, result.Corrections); logErr != nil {
+					return logErr
+				}
+			}
+			if err != nil {
+				return fmt.Errorf("import stopped: %w", err)
+			}
+			result.Total += len(parseIssues)
+			result.Skipped += len(parseIssues)
+			result.Issues = append(parseIssues, result.Issues...)
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(result)
+			}
+			if dryRun {
+				fmt.Fprintf(out, "Would import %d of %d corrections from %s\n", result.Total-result.Skipped, result.Total, path)
+			} else {
+				fmt.Fprintf(out, "Imported %d corrections from %s\n", result.Total, path)
+				fmt.Fprintf(out, "  Added:   %d\n", result.Added)
+				fmt.Fprintf(out, "  Merged:  %d\n", result.Merged)
+			}
+			fmt.Fprintf(out, "  Skipped: %d\n", result.Skipped)
+			for _, issue := range result.Issues {
+				fmt.Fprintf(out, "    line %d: %s\n", issue.Line, issue.Reason)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("format", "", "Input format: jsonl or csv (default: from the file extension)")
+	cmd.Flags().Bool("dry-run", false, "Validate the records without learning from them")
+	cmd.Flags().String("scope", "", "Override auto-classification: local (project) or global (user)")
+	cmd.Flags().Bool("auto-merge", true, "Merge corrections into similar existing behaviors")
 
 	return cmd
 }
@@ -230,12 +446,13 @@ Example:
   floop reprocess           # Reprocess local corrections
   floop reprocess --dry-run # Preview what would be processed`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+				return errNotInitialized
 			}
 
 			// Read corrections file
@@ -244,13 +461,13 @@ Example:
 			if err != nil {
 				if os.IsNotExist(err) {
 					if jsonOut {
-						json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+						json.NewEncoder(out).Encode(map[string]interface{}{
 							"status":    "no_corrections",
 							"processed": 0,
 							"skipped":   0,
 						})
 					} else {
-						fmt.Println("No corrections file found.")
+						fmt.Fprintln(out, "No corrections file found.")
 					}
 					return nil
 				}
@@ -281,33 +498,33 @@ Example:
 
 			if len(unprocessed) == 0 {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"status":    "all_processed",
 						"processed": 0,
 						"skipped":   len(corrections),
 					})
 				} else {
-					fmt.Printf("All %d corrections have already been processed.\n", len(corrections))
+					fmt.Fprintf(out, "All %d corrections have already been processed.\n", len(corrections))
 				}
 				return nil
 			}
 
 			if dryRun {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"status":            "dry_run",
 						"would_process":     len(unprocessed),
 						"already_processed": len(corrections) - len(unprocessed),
 						"corrections":       unprocessed,
 					})
 				} else {
-					fmt.Printf("Dry run: would process %d unprocessed corrections (out of %d total)\n\n",
+					fmt.Fprintf(out, "Dry run: would process %d unprocessed corrections (out of %d total)\n\n",
 						len(unprocessed), len(corrections))
 					for i, c := range unprocessed {
-						fmt.Printf("%d. [%s]\n", i+1, c.Timestamp.Format(time.RFC3339))
-						fmt.Printf("   Wrong: %s\n", c.AgentAction)
-						fmt.Printf("   Right: %s\n", c.CorrectedAction)
-						fmt.Println()
+						fmt.Fprintf(out, "%d. [%s]\n", i+1, c.Timestamp.Format(time.RFC3339))
+						fmt.Fprintf(out, "   Wrong: %s\n", c.AgentAction)
+						fmt.Fprintf(out, "   Right: %s\n", c.CorrectedAction)
+						fmt.Fprintln(out)
 					}
 				}
 				return nil
@@ -347,7 +564,19 @@ Example:
 				loopConfig.ScopeOverride = &s
 			}
 
-			loop := learning.NewLearningLoop(graphStore, loopConfig)
+			// Use the target store's tuned similarity parameters for placement
+			if tuning := learnSimilarityTuning(root, loopConfig); tuning != nil {
+				if loopConfig == nil {
+					loopConfig = &learning.LearningLoopConfig{}
+				}
+				loopConfig.SimilarityTuning = tuning
+			}
+
+			client := extractionLLMClient()
+			if c, ok := client.(llm.Closer); ok {
+				defer c.Close()
+			}
+			loop := learning.NewLearningLoop(graphStore, withLLMExtraction(applyReviewHold(root, withOpLog(root, loopConfig)), client))
 			ctx := context.Background()
 
 			var processed []models.Correction
@@ -383,6 +612,17 @@ Example:
 				c.ProcessedAt = &now
 				processed = append(processed, *c)
 
+				if result.SkippedForgotten {
+					if jsonOut {
+						results = append(results, map[string]interface{}{
+							"correction_id": c.ID,
+							"forgotten_id":  result.ForgottenBehaviorID,
+						})
+					} else {
+						fmt.Fprintf(out, "Skipped: %s matches forgotten behavior %s\n", c.CorrectedAction[:min(50, len(c.CorrectedAction))], result.ForgottenBehaviorID)
+					}
+					continue
+				}
 				if jsonOut {
 					results = append(results, map[string]interface{}{
 						"correction_id": c.ID,
@@ -391,7 +631,7 @@ Example:
 						"auto_accepted": result.AutoAccepted,
 					})
 				} else {
-					fmt.Printf("Processed: %s -> %s\n", c.CorrectedAction[:min(50, len(c.CorrectedAction))], result.CandidateBehavior.ID)
+					fmt.Fprintf(out, "Processed: %s -> %s\n", c.CorrectedAction[:min(50, len(c.CorrectedAction))], result.CandidateBehavior.ID)
 				}
 			}
 
@@ -417,15 +657,15 @@ Example:
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"status":    "completed",
 					"processed": len(processed),
 					"skipped":   len(corrections) - len(processed),
 					"results":   results,
 				})
 			} else {
-				fmt.Printf("\nReprocessed %d corrections into behaviors.\n", len(processed))
-				fmt.Printf("Skipped %d already-processed corrections.\n", len(corrections)-len(unprocessed))
+				fmt.Fprintf(out, "\nReprocessed %d corrections into behaviors.\n", len(processed))
+				fmt.Fprintf(out, "Skipped %d already-processed corrections.\n", len(corrections)-len(unprocessed))
 			}
 
 			return nil
@@ -438,3 +678,171 @@ Example:
 
 	return cmd
 }
+
+// newLearnLoopConfig builds the learning loop config from the --auto-merge
+// and --scope flags shared by learn and learn import. It returns nil when
+// the defaults apply.
+func newLearnLoopConfig(cmd *cobra.Command, graphStore store.GraphStore, root string) (*learning.LearningLoopConfig, error) {
+	// Merge near-duplicates into existing behaviors
+	autoMerge, _ := cmd.Flags().GetBool("auto-merge")
+	var loopConfig *learning.LearningLoopConfig
+	if autoMerge {
+		cfg := learning.DefaultLearningLoopConfig()
+		cfg.AutoMerge = true
+		merger := dedup.NewBehaviorMerger(dedup.MergerConfig{})
+		cfg.Deduplicator = dedup.NewStoreDeduplicator(graphStore, merger, dedup.DeduplicatorConfig{
+			SimilarityThreshold: constants.DefaultAutoMergeThreshold,
+			AutoMerge:           true,
+		})
+		loopConfig = &cfg
+	}
+
+	// Apply --scope override if explicitly set
+	if cmd.Flags().Changed("scope") {
+		scopeVal, _ := cmd.Flags().GetString("scope")
+		s := constants.Scope(scopeVal)
+		if s != constants.ScopeLocal && s != constants.ScopeGlobal {
+			return nil, fmt.Errorf("--scope must be 'local' or 'global'")
+		}
+		if loopConfig == nil {
+			loopConfig = &learning.LearningLoopConfig{}
+		}
+		loopConfig.ScopeOverride = &s
+	}
+
+	// Use the target store's tuned similarity parameters for placement
+	if tuning := learnSimilarityTuning(root, loopConfig); tuning != nil {
+		if loopConfig == nil {
+			loopConfig = &learning.LearningLoopConfig{}
+		}
+		loopConfig.SimilarityTuning = tuning
+	}
+	return loopConfig, nil
+}
+
+// learnedCorrectionIDs returns the IDs in a corrections log.
+func learnedCorrectionIDs(path string) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corrections: %w", err)
+	}
+	ids := make(map[string]bool)
+	for _, line := range splitLines(string(data)) {
+		var c models.Correction
+		if line != "" && json.Unmarshal([]byte(line), &c) == nil {
+			ids[c.ID] = true
+		}
+	}
+	return ids, nil
+}
+
+// appendCorrections appends corrections to a corrections log.
+func appendCorrections(path string, corrections []models.Correction) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open corrections log: %w", err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, c := range corrections {
+		if err := enc.Encode(c); err != nil {
+			return fmt.Errorf("failed to write correction: %w", err)
+		}
+	}
+	return nil
+}
+
+// extractTextCorrection derives a correction from free-form text for
+// 'learn --text', using the configured LLM client when there is one.
+func extractTextCorrection(text string) (*learning.TextCorrection, error) {
+	floopCfg, err := config.Load()
+	if err != nil {
+		floopCfg = config.Default()
+	}
+	client := createLLMClient(floopCfg)
+	if c, ok := client.(llm.Closer); ok {
+		defer c.Close()
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	extracted, err := learning.ExtractFromText(ctx, learning.NewCorrectionCapture(), client, text)
+	if errors.Is(err, learning.ErrNotCorrection) {
+		return nil, fmt.Errorf("--text does not look like a correction; use --right to record it explicitly")
+	}
+	return extracted, err
+}
+
+// applyReviewHold sets HoldForReview from the review.hold_pending of the
+// project at root, starting from the default loop config when loopConfig is
+// nil.
+func applyReviewHold(root string, loopConfig *learning.LearningLoopConfig) *learning.LearningLoopConfig {
+	cfg, err := config.LoadForProject(root)
+	if err != nil || !cfg.Review.HoldPending {
+		return loopConfig
+	}
+	if loopConfig == nil {
+		defaults := learning.DefaultLearningLoopConfig()
+		loopConfig = &defaults
+	}
+	loopConfig.HoldForReview = true
+	return loopConfig
+}
+
+// withOpLog records the loop's graph changes in the project's operation log
+// so 'floop undo' can reverse them, and the learns in the project's usage
+// metrics when enabled, starting from defaults when nil.
+func withOpLog(root string, loopConfig *learning.LearningLoopConfig) *learning.LearningLoopConfig {
+	if loopConfig == nil {
+		defaults := learning.DefaultLearningLoopConfig()
+		loopConfig = &defaults
+	}
+	loopConfig.OpLogPath = filepath.Join(root, ".floop", learning.OpLogFile)
+	if cfg, err := config.LoadForProject(root); err == nil {
+		loopConfig.Metrics = openMetrics(cfg, root)
+	}
+	return loopConfig
+}
+
+// extractionLLMClient returns the LLM client that refines learned behaviors,
+// or nil unless llm.extract_behaviors is enabled. The caller closes it.
+func extractionLLMClient() llm.Client {
+	cfg, err := config.Load()
+	if err != nil || !cfg.LLM.ExtractBehaviors {
+		return nil
+	}
+	return createLLMClient(cfg)
+}
+
+// withLLMExtraction has the loop refine extracted behaviors with client,
+// starting from defaults when loopConfig is nil. A nil client leaves
+// extraction rule-based.
+func withLLMExtraction(loopConfig *learning.LearningLoopConfig, client llm.Client) *learning.LearningLoopConfig {
+	if client == nil {
+		return loopConfig
+	}
+	if loopConfig == nil {
+		defaults := learning.DefaultLearningLoopConfig()
+		loopConfig = &defaults
+	}
+	loopConfig.LLMClient = client
+	loopConfig.UseLLM = true
+	return loopConfig
+}
+
+// learnSimilarityTuning returns the saved similarity tuning for the store that
+// learned behaviors are placed in: local when the scope is overridden to
+// local, global otherwise. Returns nil when that store has not been tuned.
+func learnSimilarityTuning(root string, loopConfig *learning.LearningLoopConfig) *similarity.Tuning {
+	if loopConfig != nil && loopConfig.ScopeOverride != nil && *loopConfig.ScopeOverride == constants.ScopeLocal {
+		return storeSimilarityTuning(store.LocalFloopPath(root))
+	}
+	globalDir, err := store.GlobalFloopPath()
+	if err != nil {
+		return nil
+	}
+	return storeSimilarityTuning(globalDir)
+}
@@ -617,3 +617,158 @@ func TestReprocessCmdSanitizesCorrections(t *testing.T) {
 		})
 	}
 }
+
+func TestLearnCmdText(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	t.Run("extracts wrong, right, and file from stdin", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newLearnCmd())
+		rootCmd.SetArgs([]string{"learn", "--text", "-", "--root", tmpDir, "--json"})
+		rootCmd.SetIn(strings.NewReader("Review of tools/release.py: no, don't use os.path, use pathlib instead.\n"))
+		var outBuf bytes.Buffer
+		rootCmd.SetOut(&outBuf)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("learn --text failed: %v", err)
+		}
+
+		var resp struct {
+			Correction models.Correction `json:"correction"`
+			Extracted  map[string]any    `json:"extracted"`
+		}
+		if err := json.Unmarshal(outBuf.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, outBuf.String())
+		}
+		c := resp.Correction
+		if c.AgentAction != "use os.path" || c.CorrectedAction != "use pathlib" {
+			t.Errorf("wrong/right = %q / %q", c.AgentAction, c.CorrectedAction)
+		}
+		if c.Context.FilePath != "tools/release.py" || c.Context.FileLanguage != "python" {
+			t.Errorf("context = %+v", c.Context)
+		}
+		if !strings.Contains(c.HumanResponse, "Review of tools/release.py") {
+			t.Errorf("HumanResponse = %q, want the original text", c.HumanResponse)
+		}
+		if resp.Extracted["method"] != "pattern" {
+			t.Errorf("extracted = %v", resp.Extracted)
+		}
+	})
+
+	for _, tc := range []struct {
+		name string
+		args []string
+	}{
+		{"text without a correction", []string{"--text", "thanks, looks good"}},
+		{"text with right", []string{"--text", "don't do X, do Y", "--right", "do Y"}},
+		{"neither text nor right", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rootCmd := newTestRootCmd()
+			rootCmd.AddCommand(newLearnCmd())
+			rootCmd.SetArgs(append([]string{"learn", "--root", tmpDir}, tc.args...))
+			rootCmd.SetOut(&bytes.Buffer{})
+			rootCmd.SetErr(&bytes.Buffer{})
+			if err := rootCmd.Execute(); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestLearnImportCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	importPath := filepath.Join(tmpDir, "reviews.csv")
+	csvData := "wrong,right,text\n" +
+		"used fmt.Println for errors,return wrapped errors with fmt.Errorf,\n" +
+		",,\"No, don't use panics in library code, return an error instead\"\n" +
+		",,thanks!\n"
+	if err := os.WriteFile(importPath, []byte(csvData), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	runImport := func(extra ...string) map[string]any {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newLearnCmd())
+		rootCmd.SetArgs(append([]string{"learn", "import", importPath, "--root", tmpDir, "--json"}, extra...))
+		var outBuf bytes.Buffer
+		rootCmd.SetOut(&outBuf)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("learn import failed: %v", err)
+		}
+		var resp map[string]any
+		if err := json.Unmarshal(outBuf.Bytes(), &resp); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, outBuf.String())
+		}
+		return resp
+	}
+
+	if resp := runImport("--dry-run"); resp["added"] != 0.0 || resp["skipped"] != 1.0 || resp["dry_run"] != true {
+		t.Errorf("dry run = %v", resp)
+	}
+	if _, err := os.Stat(filepath.Join(tmpDir, ".floop", "corrections.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote the corrections log: %v", err)
+	}
+
+	if resp := runImport(); resp["total"] != 3.0 || resp["added"] != 2.0 || resp["skipped"] != 1.0 {
+		t.Errorf("import = %v", resp)
+	}
+	data, err := os.ReadFile(filepath.Join(tmpDir, ".floop", "corrections.jsonl"))
+	if err != nil {
+		t.Fatalf("corrections log: %v", err)
+	}
+	if n := len(splitLines(strings.TrimSpace(string(data)))); n != 2 {
+		t.Errorf("corrections logged = %d, want 2", n)
+	}
+
+	if resp := runImport(); resp["added"] != 0.0 || resp["skipped"] != 3.0 {
+		t.Errorf("re-import = %v", resp)
+	}
+}
+
+func TestLearnCmdFailReview(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newInitCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"init", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("init failed: %v", err)
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newLearnCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{
+		"learn", "--fail-review",
+		"--wrong", "committed the .env file",
+		"--right", "never commit secrets or .env files to the repository",
+		"--root", tmpDir,
+	})
+	err := rootCmd.Execute()
+	wantErrorCode(t, err, codeReviewRequired)
+	if exitCode(err) != exitReviewRequired {
+		t.Errorf("exitCode = %d, want %d", exitCode(err), exitReviewRequired)
+	}
+}
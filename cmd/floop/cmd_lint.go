@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/lint"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+// contentLintResult is the lint outcome for one behavior.
+type contentLintResult struct {
+	BehaviorID string       `json:"behavior_id"`
+	Name       string       `json:"name"`
+	Canonical  string       `json:"canonical"`
+	Issues     []lint.Issue `json:"issues"`
+	Suggestion string       `json:"suggestion,omitempty"`
+}
+
+func newLintCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint",
+		Short: "Check behavior content against style rules",
+		Long: `Check learned behaviors against style rules.
+
+With --content, each behavior's canonical text is checked for:
+  imperative-mood      starts with a verb ("Use...", "Never...")
+  max-length           stays under --max-length characters
+  first-person         avoids I, me, my, we, our
+  unresolved-pronoun   avoids pronouns with nothing to refer to
+
+The same rules run at learn time; issues found there are added to the
+behavior's review reasons. Use --suggest to ask the configured LLM for a
+rewrite of each flagged behavior.
+
+Examples:
+  floop lint --content
+  floop lint --content --scope local --json
+  floop lint --content --suggest`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			content, _ := cmd.Flags().GetBool("content")
+			scopeStr, _ := cmd.Flags().GetString("scope")
+			maxLength, _ := cmd.Flags().GetInt("max-length")
+			suggest, _ := cmd.Flags().GetBool("suggest")
+
+			if !content {
+				return fmt.Errorf("nothing to lint: pass --content")
+			}
+			scope := constants.Scope(scopeStr)
+			if !scope.Valid() {
+				return fmt.Errorf("invalid scope: %s (must be local, global, or both)", scopeStr)
+			}
+			if maxLength <= 0 {
+				return fmt.Errorf("--max-length must be positive")
+			}
+			if scope != constants.ScopeGlobal {
+				if _, err := os.Stat(filepath.Join(root, ".floop")); os.IsNotExist(err) {
+					return errNotInitialized
+				}
+			}
+
+			var llmClient llm.Client
+			if suggest {
+				floopCfg, err := loadConfig(cmd)
+				if err != nil {
+					return fmt.Errorf("loading config: %w", err)
+				}
+				llmClient = createLLMClient(floopCfg)
+				if llmClient == nil || !llmClient.Available() {
+					return fmt.Errorf("--suggest requires a configured LLM (see 'floop config set llm.enabled true')")
+				}
+			}
+
+			behaviors, err := loadBehaviorsWithScope(root, scope)
+			if err != nil {
+				return fmt.Errorf("failed to load behaviors: %w", err)
+			}
+
+			opts := lint.Options{MaxLength: maxLength}
+			ctx := context.Background()
+			results := []contentLintResult{}
+			for _, b := range behaviors {
+				issues := lint.Lint(b.Content.Canonical, opts)
+				if len(issues) == 0 {
+					continue
+				}
+				result := contentLintResult{
+					BehaviorID: b.ID,
+					Name:       b.Name,
+					Canonical:  b.Content.Canonical,
+					Issues:     issues,
+				}
+				if llmClient != nil {
+					suggestion, err := lint.SuggestRewrite(ctx, llmClient, b.Content.Canonical, issues, opts)
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "Warning: rewrite suggestion failed for %s: %v\n", b.ID, err)
+					} else {
+						result.Suggestion = suggestion
+					}
+				}
+				results = append(results, result)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"results": results,
+					"flagged": len(results),
+					"checked": len(behaviors),
+					"scope":   string(scope),
+				})
+			}
+
+			if len(results) == 0 {
+				fmt.Fprintf(out, "All %d behaviors pass content lint.\n", len(behaviors))
+				return nil
+			}
+
+			for _, r := range results {
+				fmt.Fprintf(out, "%s (%s)\n", r.Name, r.BehaviorID)
+				out.Verbosef("  %s\n", r.Canonical)
+				for _, issue := range r.Issues {
+					fmt.Fprintf(out, "  - %s\n", issue)
+				}
+				if r.Suggestion != "" {
+					fmt.Fprintf(out, "  Suggested: %s\n", r.Suggestion)
+				}
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintf(out, "%d of %d behaviors have content lint issues.\n", len(results), len(behaviors))
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("content", false, "Lint behavior canonical content")
+	cmd.Flags().String("scope", "both", "Store scope: local, global, or both")
+	cmd.Flags().Int("max-length", lint.DefaultMaxLength, "Maximum canonical content length in characters")
+	cmd.Flags().Bool("suggest", false, "Ask the configured LLM for rewrite suggestions")
+
+	return cmd
+}
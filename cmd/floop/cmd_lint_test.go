@@ -0,0 +1,64 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestLintCmd_Content(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newLearnCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{
+		"learn",
+		"--wrong", "used tabs",
+		"--right", "I want you to use spaces for it",
+		"--root", tmpDir,
+	})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+
+	out, err := runCmd(t, newLintCmd(), "--content", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("lint --content failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "first-person") || !strings.Contains(out, "1 of 2 behaviors") {
+		t.Errorf("unexpected lint output:\n%s", out)
+	}
+
+	out, err = runCmd(t, newLintCmd(), "--content", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("lint --content --json failed: %v", err)
+	}
+	var result struct {
+		Results []contentLintResult `json:"results"`
+		Flagged int                 `json:"flagged"`
+		Checked int                 `json:"checked"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Flagged != 1 || result.Checked != 2 || len(result.Results[0].Issues) == 0 {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestLintCmd_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runCmd(t, newLintCmd(), "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "--content") {
+		t.Errorf("expected missing --content error, got %v", err)
+	}
+	if _, err := runCmd(t, newLintCmd(), "--content", "--scope", "bogus", "--root", tmpDir); err == nil {
+		t.Error("expected invalid scope error")
+	}
+	if _, err := runCmd(t, newLintCmd(), "--content", "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "not initialized") {
+		t.Errorf("expected not initialized error, got %v", err)
+	}
+}
@@ -3,15 +3,25 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
+	"time"
 
 	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/assembly"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/ctxcache"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/nearmiss"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/spreading"
 	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
 	"github.com/spf13/cobra"
 )
 
@@ -20,15 +30,46 @@ func newListCmd() *cobra.Command {
 		Use:   "list",
 		Short: "List behaviors or corrections",
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			showCorrections, _ := cmd.Flags().GetBool("corrections")
 			globalFlag, _ := cmd.Flags().GetBool("global")
 			localFlag, _ := cmd.Flags().GetBool("local")
 			allFlag, _ := cmd.Flags().GetBool("all")
-			tagFilter, _ := cmd.Flags().GetString("tag")
+			textFilter, _ := cmd.Flags().GetString("filter")
+			scopeFlag, _ := cmd.Flags().GetString("scope")
+			sortBy, _ := cmd.Flags().GetString("sort")
 
 			// Validate flag combinations
+			if scopeFlag != "" && (globalFlag || localFlag || allFlag) {
+				return fmt.Errorf("cannot specify --scope with --global, --local, or --all")
+			}
+			switch constants.Scope(scopeFlag) {
+			case "", constants.ScopeBoth:
+			case constants.ScopeLocal:
+				localFlag = true
+			case constants.ScopeGlobal:
+				globalFlag = true
+			default:
+				return fmt.Errorf("invalid --scope %q: must be local, global, or both", scopeFlag)
+			}
+			if !validListSorts[sortBy] {
+				return fmt.Errorf("invalid --sort %q: must be score, confidence, created, or activations", sortBy)
+			}
+			predicate, err := listPredicate(cmd, time.Now())
+			if err != nil {
+				return err
+			}
+			limit, _ := cmd.Flags().GetInt("limit")
+			cursor, _ := cmd.Flags().GetString("cursor")
+			paged := limit != 0 || cursor != ""
+			if limit < 0 {
+				return fmt.Errorf("--limit must be non-negative")
+			}
+			if paged && (sortBy != "" || textFilter != "") {
+				return fmt.Errorf("--limit and --cursor page in ID order and cannot be combined with --sort or --filter")
+			}
 			if globalFlag && localFlag {
 				return fmt.Errorf("cannot specify both --global and --local")
 			}
@@ -45,7 +86,7 @@ func newListCmd() *cobra.Command {
 				if globalFlag || localFlag || allFlag {
 					fmt.Fprintln(cmd.ErrOrStderr(), "Warning: --corrections reads local corrections only; scope flags are ignored")
 				}
-				return listCorrections(cmd.OutOrStdout(), root, jsonOut)
+				return listCorrections(out, root, jsonOut)
 			}
 
 			// Determine scope
@@ -69,14 +110,7 @@ func newListCmd() *cobra.Command {
 				if _, err := os.Stat(floopDir); err != nil {
 					hasLocal = false
 					if scope == constants.ScopeLocal {
-						if jsonOut {
-							json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
-								"error": "local .floop not initialized",
-							})
-						} else {
-							fmt.Fprintln(cmd.OutOrStdout(), "Local .floop not initialized. Run 'floop init' first.")
-						}
-						return nil
+						return newCLIError(codeNotInitialized, map[string]interface{}{"scope": "local"}, "local .floop not initialized. Run 'floop init' first")
 					}
 				}
 			}
@@ -91,14 +125,7 @@ func newListCmd() *cobra.Command {
 				} else if _, err := os.Stat(globalPath); err != nil {
 					hasGlobal = false
 					if scope == constants.ScopeGlobal {
-						if jsonOut {
-							json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
-								"error": "global .floop not accessible",
-							})
-						} else {
-							fmt.Fprintln(cmd.OutOrStdout(), "Global .floop not initialized. Run 'floop init --global' first.")
-						}
-						return nil
+						return newCLIError(codeNotInitialized, map[string]interface{}{"scope": "global"}, "global .floop not initialized. Run 'floop init --global' first")
 					}
 				}
 			}
@@ -107,11 +134,11 @@ func newListCmd() *cobra.Command {
 			if scope == constants.ScopeBoth {
 				if !hasLocal && !hasGlobal {
 					if jsonOut {
-						json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
+						json.NewEncoder(out).Encode(map[string]interface{}{
 							"error": "no .floop stores initialized",
 						})
 					} else {
-						fmt.Fprintln(cmd.OutOrStdout(), "No .floop stores initialized. Run 'floop init' first.")
+						fmt.Fprintln(out, "No .floop stores initialized. Run 'floop init' first.")
 					}
 					return nil
 				}
@@ -125,24 +152,26 @@ func newListCmd() *cobra.Command {
 			}
 
 			// Load behaviors from appropriate store(s)
-			behaviors, err := loadBehaviorsWithScope(root, scope)
+			var behaviors []models.Behavior
+			nextCursor := ""
+			err = out.Timed("load behaviors", func() error {
+				var loadErr error
+				if paged {
+					behaviors, nextCursor, loadErr = queryBehaviorPage(root, scope, predicate, store.PageOptions{Limit: limit, Cursor: cursor})
+				} else if textFilter != "" {
+					behaviors, loadErr = loadFilteredBehaviors(root, scope, textFilter, predicate)
+				} else {
+					behaviors, loadErr = queryBehaviorsWithScope(root, scope, predicate)
+				}
+				return loadErr
+			})
 			if err != nil {
 				return fmt.Errorf("failed to load behaviors: %w", err)
 			}
-
-			// Filter by tag if specified
-			if tagFilter != "" {
-				var filtered []models.Behavior
-				for _, b := range behaviors {
-					for _, t := range b.Content.Tags {
-						if t == tagFilter {
-							filtered = append(filtered, b)
-							break
-						}
-					}
-				}
-				behaviors = filtered
+			if sortBy == "" && predicate[store.PredicateExpiresBefore] != nil {
+				sortByExpiry(behaviors)
 			}
+			sortBehaviors(behaviors, sortBy)
 
 			if jsonOut {
 				// Note: JSON scope field emits the scope constant value ("local", "global",
@@ -154,7 +183,10 @@ func newListCmd() *cobra.Command {
 					"count":     len(behaviors),
 					"scope":     string(scope),
 				}
-				json.NewEncoder(cmd.OutOrStdout()).Encode(result)
+				if nextCursor != "" {
+					result["next_cursor"] = nextCursor
+				}
+				json.NewEncoder(out).Encode(result)
 			} else {
 				// Show scope in header
 				scopeStr := string(scope)
@@ -162,25 +194,39 @@ func newListCmd() *cobra.Command {
 					scopeStr = "all (local + global)"
 				}
 
+				if len(behaviors) == 0 && (len(predicate) > 1 || textFilter != "") {
+					fmt.Fprintf(out, "No behaviors match the filters (%s scope).\n", scopeStr)
+					return nil
+				}
 				if len(behaviors) == 0 {
-					fmt.Fprintf(cmd.OutOrStdout(), "No behaviors learned yet (%s scope).\n", scopeStr)
-					fmt.Fprintln(cmd.OutOrStdout(), "\nUse 'floop learn --right \"Y\"' to capture corrections.")
+					fmt.Fprintf(out, "No behaviors learned yet (%s scope).\n", scopeStr)
+					fmt.Fprintln(out, "\nUse 'floop learn --right \"Y\"' to capture corrections.")
 					return nil
 				}
 
-				fmt.Fprintf(cmd.OutOrStdout(), "Learned behaviors - %s (%d):\n\n", scopeStr, len(behaviors))
+				fmt.Fprintf(out, "Learned behaviors - %s (%d):\n\n", scopeStr, len(behaviors))
 
 				for i, b := range behaviors {
-					fmt.Fprintf(cmd.OutOrStdout(), "%d. [%s] %s\n", i+1, b.Kind, b.Name)
-					fmt.Fprintf(cmd.OutOrStdout(), "   %s\n", b.Content.Canonical)
+					fmt.Fprintf(out, "%d. [%s] %s\n", i+1, b.Kind, b.Name)
+					fmt.Fprintf(out, "   %s\n", b.Content.Canonical)
 					if len(b.Content.Tags) > 0 {
-						fmt.Fprintf(cmd.OutOrStdout(), "   Tags: %v\n", b.Content.Tags)
+						fmt.Fprintf(out, "   Tags: %v\n", b.Content.Tags)
 					}
 					if len(b.When) > 0 {
-						fmt.Fprintf(cmd.OutOrStdout(), "   When: %v\n", b.When)
+						fmt.Fprintf(out, "   When: %v\n", b.When)
+					}
+					if b.ExpiresAt != nil {
+						fmt.Fprintf(out, "   Expires: %s\n", formatExpiry(*b.ExpiresAt, time.Now()))
+					}
+					if b.Snoozed(time.Now()) {
+						fmt.Fprintf(out, "   Snoozed until: %s\n", b.SnoozedUntil.Local().Format("2006-01-02 15:04"))
 					}
-					fmt.Fprintf(cmd.OutOrStdout(), "   Confidence: %.2f\n", b.Confidence)
-					fmt.Fprintln(cmd.OutOrStdout())
+					fmt.Fprintf(out, "   Confidence: %.2f\n", b.Confidence)
+					out.Verbosef("   ID: %s  Priority: %d  Source: %s\n", b.ID, b.Priority, b.Provenance.SourceType)
+					fmt.Fprintln(out)
+				}
+				if nextCursor != "" {
+					fmt.Fprintf(out, "More behaviors follow; continue with --cursor %s\n", nextCursor)
 				}
 			}
 
@@ -193,45 +239,33 @@ func newListCmd() *cobra.Command {
 	cmd.Flags().Bool("local", false, "Show behaviors from local project store only")
 	cmd.Flags().Bool("all", false, "Show behaviors from both local and global stores")
 	_ = cmd.Flags().MarkDeprecated("all", "both is now the default scope; use --local or --global to narrow")
+	cmd.Flags().String("scope", "", "Store to list: local, global, or both (default both)")
+	cmd.Flags().String("kind", "", "Filter behaviors by kind (directive, constraint, procedure, preference)")
 	cmd.Flags().String("tag", "", "Filter behaviors by tag (exact match)")
+	cmd.Flags().Float64("min-confidence", 0, "Only show behaviors with at least this confidence")
+	cmd.Flags().String("pack", "", "Only show behaviors installed from this skill pack")
+	cmd.Flags().String("created-after", "", "Only show behaviors created since a date (2026-01-31), time (RFC3339), or age (7d)")
+	cmd.Flags().Bool("expiring", false, "Only show behaviors with an expiry, soonest first")
+	cmd.Flags().String("expiring-within", "", "Only show behaviors expiring within a duration (14d), soonest first")
+	cmd.Flags().Bool("snoozed", false, "Only show behaviors that are snoozed")
+	cmd.Flags().String("sort", "", "Sort by score, confidence, created, or activations (highest/newest first)")
+	cmd.Flags().String("filter", "", "Only show behaviors whose name, content, or tags contain every word of the text")
+	cmd.Flags().Int("limit", 0, "Show at most this many behaviors, in ID order (0 = all)")
+	cmd.Flags().String("cursor", "", "Continue a paged listing after this behavior ID (printed as next_cursor)")
 
 	return cmd
 }
 
 func listCorrections(w io.Writer, root string, jsonOut bool) error {
-	correctionsPath := filepath.Join(root, ".floop", "corrections.jsonl")
-
-	data, err := os.ReadFile(correctionsPath)
+	corrections, err := loadCorrections(root)
 	if err != nil {
-		if os.IsNotExist(err) {
-			if jsonOut {
-				json.NewEncoder(w).Encode(map[string]interface{}{
-					"corrections": []models.Correction{},
-					"count":       0,
-				})
-			} else {
-				fmt.Fprintln(w, "No corrections captured yet.")
-			}
-			return nil
-		}
 		return err
 	}
 
-	// Parse JSONL into models.Correction
-	var corrections []models.Correction
-	lines := splitLines(string(data))
-	for _, line := range lines {
-		if line == "" {
-			continue
-		}
-		var c models.Correction
-		if err := json.Unmarshal([]byte(line), &c); err != nil {
-			continue
-		}
-		corrections = append(corrections, c)
-	}
-
 	if jsonOut {
+		if corrections == nil {
+			corrections = []models.Correction{}
+		}
 		json.NewEncoder(w).Encode(map[string]interface{}{
 			"corrections": corrections,
 			"count":       len(corrections),
@@ -256,6 +290,35 @@ func listCorrections(w io.Writer, root string, jsonOut bool) error {
 	return nil
 }
 
+// loadCorrections reads the local correction log. A missing log yields no
+// corrections; malformed lines are skipped.
+func loadCorrections(root string) ([]models.Correction, error) {
+	correctionsPath := filepath.Join(root, ".floop", "corrections.jsonl")
+
+	data, err := os.ReadFile(correctionsPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Parse JSONL into models.Correction
+	var corrections []models.Correction
+	lines := splitLines(string(data))
+	for _, line := range lines {
+		if line == "" {
+			continue
+		}
+		var c models.Correction
+		if err := json.Unmarshal([]byte(line), &c); err != nil {
+			continue
+		}
+		corrections = append(corrections, c)
+	}
+	return corrections, nil
+}
+
 // splitLines splits a string into lines without using strings.Split for efficiency.
 func splitLines(s string) []string {
 	var lines []string
@@ -272,58 +335,380 @@ func splitLines(s string) []string {
 	return lines
 }
 
+// validListSorts are the orders 'floop list --sort' accepts; empty keeps
+// the store's order.
+var validListSorts = map[string]bool{"": true, "score": true, "confidence": true, "created": true, "activations": true}
+
+// listPredicate builds the QueryNodes predicate for list's filter flags.
+func listPredicate(cmd *cobra.Command, now time.Time) (map[string]interface{}, error) {
+	predicate := map[string]interface{}{"kind": string(store.NodeKindBehavior)}
+	if kind, _ := cmd.Flags().GetString("kind"); kind != "" {
+		predicate[store.PredicateBehaviorKind] = kind
+	}
+	if tag, _ := cmd.Flags().GetString("tag"); tag != "" {
+		predicate[store.PredicateTag] = tag
+	}
+	if cmd.Flags().Changed("min-confidence") {
+		minConfidence, _ := cmd.Flags().GetFloat64("min-confidence")
+		if minConfidence < 0 || minConfidence > 1 {
+			return nil, fmt.Errorf("--min-confidence must be between 0 and 1")
+		}
+		predicate[store.PredicateMinConfidence] = minConfidence
+	}
+	if pack, _ := cmd.Flags().GetString("pack"); pack != "" {
+		predicate[store.PredicatePack] = pack
+	}
+	if createdAfter, _ := cmd.Flags().GetString("created-after"); createdAfter != "" {
+		t, err := parseCreatedAfter(createdAfter, now)
+		if err != nil {
+			return nil, err
+		}
+		predicate[store.PredicateCreatedAfter] = t
+	}
+	if within, _ := cmd.Flags().GetString("expiring-within"); within != "" {
+		d, err := utils.ParseDuration(within)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid --expiring-within %q: use a duration such as 14d", within)
+		}
+		predicate[store.PredicateExpiresBefore] = now.Add(d)
+	} else if expiring, _ := cmd.Flags().GetBool("expiring"); expiring {
+		predicate[store.PredicateExpiresBefore] = noExpiry
+	}
+	if snoozed, _ := cmd.Flags().GetBool("snoozed"); snoozed {
+		predicate[store.PredicateSnoozedAfter] = now
+	}
+	return predicate, nil
+}
+
+// parseExpiry parses an --expires value: a date, an RFC3339 time, or a
+// TTL such as 30d counted forward from now.
+func parseExpiry(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if ttl, err := utils.ParseDuration(s); err == nil && ttl > 0 {
+		return now.Add(ttl), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --expires %q: use a date (2026-01-31), an RFC3339 time, or a TTL (30d)", s)
+}
+
+// noExpiry is later than any expiry, so expiring before it matches every
+// behavior that has one.
+var noExpiry = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// sortByExpiry orders behaviors soonest expiry first; behaviors without
+// one go last.
+func sortByExpiry(behaviors []models.Behavior) {
+	expiry := func(b *models.Behavior) time.Time {
+		if b.ExpiresAt == nil {
+			return noExpiry
+		}
+		return *b.ExpiresAt
+	}
+	sort.SliceStable(behaviors, func(i, j int) bool {
+		return expiry(&behaviors[i]).Before(expiry(&behaviors[j]))
+	})
+}
+
+// formatExpiry describes when a behavior expires relative to now.
+func formatExpiry(expiresAt, now time.Time) string {
+	when := expiresAt.Local().Format("2006-01-02 15:04")
+	if !expiresAt.After(now) {
+		return when + " (expired)"
+	}
+	return fmt.Sprintf("%s (in %s)", when, formatReviewAge(expiresAt.Sub(now)))
+}
+
+// parseCreatedAfter parses a --created-after value: a date, an RFC3339
+// time, or an age such as 7d counted back from now.
+func parseCreatedAfter(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if age, err := utils.ParseDuration(s); err == nil && age >= 0 {
+		return now.Add(-age), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --created-after %q: use a date (2026-01-31), an RFC3339 time, or an age (7d)", s)
+}
+
+// sortBehaviors orders behaviors by the --sort key, highest or newest
+// first. Score is the relevance score without a task context.
+func sortBehaviors(behaviors []models.Behavior, by string) {
+	var key func(b *models.Behavior) float64
+	switch by {
+	case "score":
+		scorer := ranking.NewRelevanceScorer(ranking.DefaultScorerConfig())
+		scores := make(map[string]float64, len(behaviors))
+		for i := range behaviors {
+			scores[behaviors[i].ID] = scorer.Score(&behaviors[i], &models.ContextSnapshot{}).Score
+		}
+		key = func(b *models.Behavior) float64 { return scores[b.ID] }
+	case "confidence":
+		key = func(b *models.Behavior) float64 { return b.Confidence }
+	case "created":
+		key = func(b *models.Behavior) float64 { return float64(behaviorCreatedAt(b).UnixNano()) }
+	case "activations":
+		key = func(b *models.Behavior) float64 { return float64(b.Stats.TimesActivated) }
+	default:
+		return
+	}
+	sort.SliceStable(behaviors, func(i, j int) bool {
+		return key(&behaviors[i]) > key(&behaviors[j])
+	})
+}
+
+// behaviorCreatedAt returns when b was created, falling back to when it was
+// stored for behaviors without a provenance time.
+func behaviorCreatedAt(b *models.Behavior) time.Time {
+	if !b.Provenance.CreatedAt.IsZero() {
+		return b.Provenance.CreatedAt
+	}
+	return b.Stats.CreatedAt
+}
+
 // loadBehaviorsWithScope loads behaviors from the specified scope (local, global, or both).
 func loadBehaviorsWithScope(projectRoot string, scope constants.Scope) ([]models.Behavior, error) {
+	return queryBehaviorsWithScope(projectRoot, scope, behaviorPredicate())
+}
+
+// behaviorPredicate selects every behavior node.
+func behaviorPredicate() map[string]interface{} {
+	return map[string]interface{}{"kind": string(store.NodeKindBehavior)}
+}
+
+// behaviorPageSize is how many behavior nodes are fetched from the store
+// at a time when loading behaviors.
+const behaviorPageSize = 500
+
+// queryBehaviorsWithScope loads the behaviors in scope that match
+// predicate in ID order, letting the store do the filtering.
+func queryBehaviorsWithScope(projectRoot string, scope constants.Scope, predicate map[string]interface{}) ([]models.Behavior, error) {
+	return queryBehaviorsFrom(projectRoot, scope, store.BackendSQLite, predicate)
+}
+
+// queryBehaviorsFrom is queryBehaviorsWithScope with the stores opened by
+// backend.
+func queryBehaviorsFrom(projectRoot string, scope constants.Scope, backend store.Backend, predicate map[string]interface{}) ([]models.Behavior, error) {
+	ctx := context.Background()
+	graphStore, err := openStoreForBackend(projectRoot, scope, backend)
+	if err != nil {
+		return nil, err
+	}
+	defer graphStore.Close()
+
+	// Convert nodes to behaviors a page at a time
+	behaviors := make([]models.Behavior, 0)
+	for node, err := range store.Nodes(ctx, graphStore, predicate, behaviorPageSize) {
+		if err != nil {
+			return nil, fmt.Errorf("failed to query behaviors: %w", err)
+		}
+		behaviors = append(behaviors, models.NodeToBehavior(node))
+	}
+
+	return behaviors, nil
+}
+
+// queryBehaviorPage loads one page of the behaviors in scope that match
+// predicate and returns the cursor of the next page, empty on the last.
+func queryBehaviorPage(projectRoot string, scope constants.Scope, predicate map[string]interface{}, opts store.PageOptions) ([]models.Behavior, string, error) {
+	graphStore, err := openStoreWithScope(projectRoot, scope)
+	if err != nil {
+		return nil, "", err
+	}
+	defer graphStore.Close()
+
+	page, err := store.QueryPage(context.Background(), graphStore, predicate, opts)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	behaviors := make([]models.Behavior, 0, len(page.Nodes))
+	for _, node := range page.Nodes {
+		behaviors = append(behaviors, models.NodeToBehavior(node))
+	}
+	return behaviors, page.NextCursor, nil
+}
+
+// loadFilteredBehaviors loads the behaviors in scope that match predicate
+// and every word of filter, best match first. It uses the store's full-text
+// index when it has one, and otherwise scans the matching behaviors for the
+// words as substrings.
+func loadFilteredBehaviors(projectRoot string, scope constants.Scope, filter string, predicate map[string]interface{}) ([]models.Behavior, error) {
 	ctx := context.Background()
-	var graphStore store.GraphStore
-	var err error
+	graphStore, err := openStoreWithScope(projectRoot, scope)
+	if err != nil {
+		return nil, err
+	}
+	defer graphStore.Close()
+
+	nodes, err := graphStore.QueryNodes(ctx, predicate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	ts, ok := graphStore.(store.TextSearcher)
+	if !ok {
+		words := strings.Fields(strings.ToLower(filter))
+		var behaviors []models.Behavior
+		for _, node := range nodes {
+			b := models.NodeToBehavior(node)
+			text := strings.ToLower(strings.Join([]string{b.Name, b.Content.Canonical, b.Content.Summary, strings.Join(b.Content.Tags, " ")}, " "))
+			if containsAll(text, words) {
+				behaviors = append(behaviors, b)
+			}
+		}
+		return behaviors, nil
+	}
+
+	byID := make(map[string]store.Node, len(nodes))
+	for _, node := range nodes {
+		byID[node.ID] = node
+	}
+	matches, err := ts.SearchText(ctx, filter, true, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search behaviors: %w", err)
+	}
+	behaviors := make([]models.Behavior, 0, len(matches))
+	for _, m := range matches {
+		if node, ok := byID[m.BehaviorID]; ok {
+			behaviors = append(behaviors, models.NodeToBehavior(node))
+		}
+	}
+	return behaviors, nil
+}
 
+// containsAll reports whether text contains every word.
+func containsAll(text string, words []string) bool {
+	for _, w := range words {
+		if !strings.Contains(text, w) {
+			return false
+		}
+	}
+	return true
+}
+
+// mergeNestedStores layers the behaviors of the .floop stores in dirs (see
+// store.NestedFloopDirs), opened by backend, over the project's behaviors,
+// the nearest store winning conflicts.
+func mergeNestedStores(root string, dirs []string, backend store.Backend, behaviors []models.Behavior) ([]models.Behavior, error) {
+	layers := []activation.Layer{{Behaviors: behaviors}}
+	for _, dir := range dirs {
+		nested, err := queryBehaviorsFrom(filepath.Join(root, filepath.FromSlash(dir)), constants.ScopeLocal, backend, behaviorPredicate())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load behaviors from %s: %w", dir, err)
+		}
+		layers = append(layers, activation.Layer{Dir: dir, Behaviors: nested})
+	}
+	return activation.MergeLayers(layers), nil
+}
+
+// activeCacheEntry is a plain 'floop active' result in the context cache.
+type activeCacheEntry struct {
+	BehaviorCount int                           `json:"behavior_count"`
+	Matches       []activation.ActivationResult `json:"matches"`
+	Result        activation.ResolveResult      `json:"result"`
+}
+
+// activeCache returns the context cache for 'floop active' and the key of
+// ctx in it. The key covers the context and a fingerprint of every store
+// the result is read from, taken before they are opened: a write racing
+// the evaluation then only causes a miss, never a stale hit.
+func activeCache(root string, scope constants.Scope, nested []string, ctx models.ContextSnapshot) (*ctxcache.Cache, string) {
+	var floopDirs []string
+	if scope != constants.ScopeGlobal {
+		floopDirs = append(floopDirs, filepath.Join(root, ".floop"))
+	}
+	if scope != constants.ScopeLocal {
+		if globalPath, err := store.GlobalFloopPath(); err == nil {
+			floopDirs = append(floopDirs, globalPath)
+		}
+	}
+	for _, dir := range nested {
+		floopDirs = append(floopDirs, filepath.Join(root, filepath.FromSlash(dir), ".floop"))
+	}
+	if len(floopDirs) == 0 {
+		return nil, ""
+	}
+
+	ctx.Timestamp = time.Time{}
+	ctxJSON, err := json.Marshal(ctx)
+	if err != nil {
+		return nil, ""
+	}
+	key := string(scope) + "\n" + string(ctxJSON) + "\n" + ctxcache.Fingerprint(floopDirs...)
+	return ctxcache.New(floopDirs[0], "active"), key
+}
+
+// availableScope returns the widest scope whose stores exist, so read-only
+// commands degrade gracefully when only one store is initialized. ok is
+// false when neither store exists.
+func availableScope(root string) (scope constants.Scope, ok bool) {
+	hasLocal := true
+	if _, err := os.Stat(filepath.Join(root, ".floop")); err != nil {
+		hasLocal = false
+	}
+
+	hasGlobal := true
+	if globalPath, err := store.GlobalFloopPath(); err != nil {
+		hasGlobal = false
+	} else if _, err := os.Stat(globalPath); err != nil {
+		hasGlobal = false
+	}
+
+	switch {
+	case hasLocal && hasGlobal:
+		return constants.ScopeBoth, true
+	case hasLocal:
+		return constants.ScopeLocal, true
+	case hasGlobal:
+		return constants.ScopeGlobal, true
+	default:
+		return "", false
+	}
+}
+
+// openStoreWithScope opens the graph store(s) for the given scope.
+func openStoreWithScope(projectRoot string, scope constants.Scope) (store.GraphStore, error) {
+	return openStoreForBackend(projectRoot, scope, store.BackendSQLite)
+}
+
+// openStoreForBackend opens the graph store(s) for the given scope with
+// backend (see store.OpenGraphStore).
+func openStoreForBackend(projectRoot string, scope constants.Scope, backend store.Backend) (store.GraphStore, error) {
 	switch scope {
 	case constants.ScopeLocal:
-		// Load from local store only
-		graphStore, err = store.NewSQLiteGraphStore(projectRoot)
+		graphStore, err := store.OpenGraphStore(projectRoot, backend)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open local store: %w", err)
 		}
+		return graphStore, nil
 
 	case constants.ScopeGlobal:
-		// Load from global store only
 		globalPath, err := store.GlobalFloopPath()
 		if err != nil {
 			return nil, fmt.Errorf("failed to get global path: %w", err)
 		}
-		graphStore, err = store.NewSQLiteGraphStore(filepath.Dir(globalPath))
+		graphStore, err := store.OpenGraphStore(filepath.Dir(globalPath), backend)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open global store: %w", err)
 		}
+		return graphStore, nil
 
 	case constants.ScopeBoth:
-		// Load from both stores using MultiGraphStore
-		graphStore, err = store.NewMultiGraphStore(projectRoot)
+		graphStore, err := store.NewMultiGraphStoreWithBackend(projectRoot, backend)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open multi-store: %w", err)
 		}
+		return graphStore, nil
 
 	default:
 		return nil, fmt.Errorf("invalid scope: %s", scope)
 	}
-
-	defer graphStore.Close()
-
-	// Query all behavior nodes
-	nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
-	if err != nil {
-		return nil, fmt.Errorf("failed to query behaviors: %w", err)
-	}
-
-	// Convert nodes to behaviors
-	behaviors := make([]models.Behavior, 0, len(nodes))
-	for _, node := range nodes {
-		b := models.NodeToBehavior(node)
-		behaviors = append(behaviors, b)
-	}
-
-	return behaviors, nil
 }
 
 func newActiveCmd() *cobra.Command {
@@ -333,132 +718,447 @@ func newActiveCmd() *cobra.Command {
 		Long: `List all behaviors that are currently active based on the
 current context (file, task, language, etc.).
 
+Scoring is bounded by activation.time_budget. On an oversized store that
+overruns it, activation sheds load: it keeps the matches found so far and
+fills up to activation.shed_top_n behaviors by priority, and JSON output
+reports "degraded": true.
+
+Use --near-misses to also list behaviors that almost matched: they
+confirmed some conditions but were excluded by a few contradicted ones
+(at most activation.near_miss_max_failing). Each near miss is counted on
+the behavior, and conditions that keep blocking it are suggested for
+relaxation once they reach activation.near_miss_suggest_after.
+
+In a monorepo, stores in directories between the root and --file (such as
+services/api/.floop) are layered over the root store. Their path
+conditions are relative to their own directory, and a nested behavior
+overrides outer ones with the same name or that it contradicts.
+
+Use --spread to also surface related behaviors: activation spreads from the
+matched behaviors over graph edges (similar-to, requires, overrides, and
+shared tags), decaying with each hop, and behaviors it reaches are added
+below the direct matches. Spreading is skipped when activation sheds load.
+
+Use --format claude-md, cursor-rules, copilot-instructions, or agents-md to
+render the active behaviors as that agent's rules file instead of a listing.
+
+Results are cached in .floop/cache, keyed by the context (file, task,
+environment, branch) and the modification times of the stores involved, so
+repeated calls in a session skip reopening the stores. Any store write
+invalidates them. Use --no-cache to always re-evaluate.
+
+With --store jsonl, behaviors are read straight from the committed JSONL
+export (.floop/nodes.jsonl and edges.jsonl) without SQLite, as in an
+ephemeral CI container. The default, --store auto, does so for any store
+whose floop.db has not been built yet. Near misses are then reported but
+not counted.
+
+Use --fail-empty to exit with status 5 when no behavior is active, so a
+script can branch on the result without parsing it.
+
 Use --json for machine-readable output suitable for agent consumption.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			file, _ := cmd.Flags().GetString("file")
 			task, _ := cmd.Flags().GetString("task")
 			env, _ := cmd.Flags().GetString("env")
-			jsonOut, _ := cmd.Flags().GetBool("json")
-
-			// Determine effective scope — degrade gracefully if one store is missing
-			activeScope := constants.ScopeBoth
-			floopDir := filepath.Join(root, ".floop")
-			hasLocal := true
-			if _, err := os.Stat(floopDir); err != nil {
-				hasLocal = false
+			custom, err := contextFlag(cmd)
+			if err != nil {
+				return err
 			}
+			showNearMisses, _ := cmd.Flags().GetBool("near-misses")
+			spread, _ := cmd.Flags().GetBool("spread")
+			noCache, _ := cmd.Flags().GetBool("no-cache")
+			format, _ := cmd.Flags().GetString("format")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			storeFlag, _ := cmd.Flags().GetString("store")
+			failEmpty, _ := cmd.Flags().GetBool("fail-empty")
 
-			hasGlobal := true
-			if globalPath, err := store.GlobalFloopPath(); err != nil {
-				hasGlobal = false
-			} else if _, err := os.Stat(globalPath); err != nil {
-				hasGlobal = false
+			backend, err := store.ParseBackend(storeFlag)
+			if err != nil {
+				return fmt.Errorf("invalid --store: %w", err)
 			}
 
-			if !hasLocal && !hasGlobal {
-				if jsonOut {
-					json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]interface{}{
-						"error": "no .floop stores initialized",
-					})
-				} else {
-					fmt.Fprintln(cmd.OutOrStdout(), "Not initialized. Run 'floop init' first.")
+			var rulesFormat assembly.RulesFormat
+			if format != "" {
+				var ok bool
+				if rulesFormat, ok = assembly.ParseRulesFormat(format); !ok {
+					return fmt.Errorf("invalid --format %q: must be one of %s", format, rulesFormatList())
 				}
-				return nil
-			}
-			if !hasLocal {
-				activeScope = constants.ScopeGlobal
-			} else if !hasGlobal {
-				activeScope = constants.ScopeLocal
 			}
 
-			// Load behaviors from available store(s)
-			behaviors, err := loadBehaviorsWithScope(root, activeScope)
+			cfg, err := loadConfig(cmd)
 			if err != nil {
-				return fmt.Errorf("failed to load behaviors: %w", err)
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+
+			// Determine effective scope — degrade gracefully if one store is missing
+			activeScope, ok := availableScope(root)
+			if !ok {
+				return errNotInitialized
 			}
 
+			// Stores of subdirectories between the root and the file are
+			// layered over the project's
+			nested := store.NestedFloopDirs(root, file)
+
 			// Build context
 			ctxBuilder := activation.NewContextBuilder().
 				WithFile(file).
 				WithTask(task).
 				WithEnvironment(env).
-				WithRepoRoot(root)
+				WithRepoRoot(root).
+				WithGitDetection(cfg.Activation.GitContext).
+				WithCustomValues(custom)
 			ctx := ctxBuilder.Build()
 
-			// Evaluate which behaviors are active
-			evaluator := activation.NewEvaluator()
-			matches := evaluator.Evaluate(ctx, behaviors)
+			// Near misses are recorded and spreading reads the graph on every
+			// call, so only plain activation is served from the cache
+			var cache *ctxcache.Cache
+			var cacheKey string
+			if !noCache && !showNearMisses && !spread {
+				cache, cacheKey = activeCache(root, activeScope, nested, ctx)
+			}
 
-			// Resolve conflicts
-			resolver := activation.NewResolver()
-			result := resolver.Resolve(matches)
+			var behaviorCount int
+			var matches []activation.ActivationResult
+			var misses []activation.NearMiss
+			var result activation.ResolveResult
+			var degraded, cached bool
+			var related map[string]spreading.Result
+			var hit activeCacheEntry
+			if cache != nil && cache.Get(cacheKey, &hit) {
+				cached = true
+				behaviorCount, matches, result = hit.BehaviorCount, hit.Matches, hit.Result
+				out.Debugf("served from context cache\n")
+			} else {
+				// Load behaviors from available store(s)
+				start := time.Now()
+				var behaviors []models.Behavior
+				err = out.Timed("load behaviors", func() error {
+					var loadErr error
+					behaviors, loadErr = queryBehaviorsFrom(root, activeScope, backend, behaviorPredicate())
+					return loadErr
+				})
+				if err != nil {
+					return fmt.Errorf("failed to load behaviors: %w", err)
+				}
+				if len(nested) > 0 {
+					behaviors, err = mergeNestedStores(root, nested, backend, behaviors)
+					if err != nil {
+						return err
+					}
+				}
+				behaviorCount = len(behaviors)
+
+				// Evaluate which behaviors are active and resolve conflicts
+				_ = out.Timed("evaluate", func() error {
+					maxFailing := 0
+					if showNearMisses {
+						maxFailing = cfg.Activation.NearMissMaxFailing
+					}
+					budget := activation.NewBudget(start, cfg.Activation.TimeBudget, cfg.Activation.ShedTopN, nil)
+					matches, misses, degraded = activation.NewEvaluator().EvaluateWithinBudget(ctx, behaviors, maxFailing, budget)
+					return nil
+				})
+				if spread && !degraded && len(matches) > 0 {
+					err = out.Timed("spread", func() error {
+						var spreadErr error
+						matches, related, spreadErr = spreadActivation(cmd.Context(), root, activeScope, backend, matches)
+						return spreadErr
+					})
+					if err != nil {
+						fmt.Fprintf(os.Stderr, "warning: spreading activation failed: %v\n", err)
+					}
+				}
+				result = activation.NewResolver().Resolve(matches)
+				if degraded {
+					fmt.Fprintf(os.Stderr, "warning: activation exceeded its %v time budget (%v elapsed, %d behaviors); showing top %d by priority\n",
+						cfg.Activation.TimeBudget, time.Since(start).Round(time.Millisecond), len(behaviors), len(matches))
+				}
+
+				// A degraded result depends on timing, not just the stores
+				if cache != nil && !degraded {
+					entry := activeCacheEntry{BehaviorCount: behaviorCount, Matches: matches, Result: result}
+					if err := cache.Put(cacheKey, entry); err != nil {
+						out.Debugf("context cache: %v\n", err)
+					}
+				}
+			}
+
+			var emptyErr error
+			if failEmpty && len(result.Active) == 0 {
+				emptyErr = errNoActiveBehaviors
+			}
+
+			if rulesFormat != "" {
+				fmt.Fprint(out, assembly.CompileRules(rulesFormat, assembly.FullInjections(result.Active)))
+				return emptyErr
+			}
+
+			var nearMisses []nearMissReport
+			if showNearMisses {
+				var err error
+				nearMisses, err = recordNearMisses(root, activeScope, backend, misses, cfg.Activation.NearMissSuggestAfter)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "warning: failed to record near misses: %v\n", err)
+				}
+			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				resp := map[string]interface{}{
 					"context":    ctx,
 					"active":     result.Active,
 					"overridden": result.Overridden,
 					"excluded":   result.Excluded,
 					"count":      len(result.Active),
-				})
+				}
+				if degraded {
+					resp["degraded"] = true
+				}
+				if cached {
+					resp["cached"] = true
+				}
+				if showNearMisses {
+					resp["near_misses"] = nearMisses
+				}
+				if spread {
+					resp["related"] = relatedReports(result.Active, related)
+				}
+				if len(nested) > 0 {
+					resp["nested_stores"] = nested
+				}
+				json.NewEncoder(out).Encode(resp)
 			} else {
-				fmt.Printf("Context:\n")
+				fmt.Fprintf(out, "Context:\n")
 				if ctx.FilePath != "" {
-					fmt.Printf("  File: %s\n", ctx.FilePath)
+					fmt.Fprintf(out, "  File: %s\n", ctx.FilePath)
 				}
 				if ctx.FileLanguage != "" {
-					fmt.Printf("  Language: %s\n", ctx.FileLanguage)
+					fmt.Fprintf(out, "  Language: %s\n", ctx.FileLanguage)
 				}
 				if ctx.Task != "" {
-					fmt.Printf("  Task: %s\n", ctx.Task)
+					fmt.Fprintf(out, "  Task: %s\n", ctx.Task)
 				}
 				if ctx.Branch != "" {
-					fmt.Printf("  Branch: %s\n", ctx.Branch)
+					fmt.Fprintf(out, "  Branch: %s\n", ctx.Branch)
+				}
+				if len(ctx.Frameworks) > 0 {
+					fmt.Fprintf(out, "  Frameworks: %s\n", strings.Join(ctx.Frameworks, ", "))
+				}
+				printCustomContext(out, ctx.Custom)
+				if len(nested) > 0 {
+					fmt.Fprintf(out, "  Nested stores: %s\n", strings.Join(nested, ", "))
 				}
-				fmt.Println()
+				fmt.Fprintln(out)
 
 				if len(result.Active) == 0 {
-					fmt.Println("No active behaviors for this context.")
-					if len(behaviors) > 0 {
-						fmt.Printf("\n(%d behaviors exist but none match current context)\n", len(behaviors))
+					fmt.Fprintln(out, "No active behaviors for this context.")
+					if behaviorCount > 0 {
+						fmt.Fprintf(out, "\n(%d behaviors exist but none match current context)\n", behaviorCount)
 					}
-					return nil
+					if showNearMisses {
+						fmt.Fprintln(out)
+						printNearMisses(out, nearMisses)
+					}
+					return emptyErr
 				}
 
-				fmt.Printf("Active behaviors (%d):\n\n", len(result.Active))
+				matchByID := make(map[string]activation.ActivationResult, len(matches))
+				for _, m := range matches {
+					matchByID[m.Behavior.ID] = m
+				}
+
+				fmt.Fprintf(out, "Active behaviors (%d):\n\n", len(result.Active))
 				for i, b := range result.Active {
-					fmt.Printf("%d. [%s] %s\n", i+1, b.Kind, b.Name)
-					fmt.Printf("   %s\n", b.Content.Canonical)
+					fmt.Fprintf(out, "%d. [%s] %s\n", i+1, b.Kind, b.Name)
+					fmt.Fprintf(out, "   %s\n", b.Content.Canonical)
 					if len(b.When) > 0 {
-						fmt.Printf("   When: %v\n", b.When)
+						fmt.Fprintf(out, "   When: %v\n", b.When)
+					}
+					if r, ok := related[b.ID]; ok {
+						fmt.Fprintf(out, "   Related: spread from %s (activation %.2f, %d hop(s))\n", r.SeedSource, r.Activation, r.Distance)
+					}
+					if m, ok := matchByID[b.ID]; ok {
+						out.Verbosef("   Score: match=%.2f specificity=%d confidence=%.2f priority=%d\n",
+							m.MatchScore, m.Specificity, b.Confidence, b.Priority)
+						if len(m.MatchedConditions) > 0 {
+							out.Verbosef("   Matched: %v\n", m.MatchedConditions)
+						}
 					}
-					fmt.Println()
+					fmt.Fprintln(out)
 				}
 
 				if len(result.Overridden) > 0 {
-					fmt.Printf("Overridden behaviors (%d):\n", len(result.Overridden))
+					fmt.Fprintf(out, "Overridden behaviors (%d):\n", len(result.Overridden))
 					for _, o := range result.Overridden {
-						fmt.Printf("  - %s (by %s)\n", o.Behavior.Name, o.OverrideBy)
+						fmt.Fprintf(out, "  - %s (by %s)\n", o.Behavior.Name, o.OverrideBy)
 					}
-					fmt.Println()
+					fmt.Fprintln(out)
 				}
 
 				if len(result.Excluded) > 0 {
-					fmt.Printf("Excluded due to conflicts (%d):\n", len(result.Excluded))
+					fmt.Fprintf(out, "Excluded due to conflicts (%d):\n", len(result.Excluded))
 					for _, e := range result.Excluded {
-						fmt.Printf("  - %s (conflicts with %s)\n", e.Behavior.Name, e.ConflictsWith)
+						fmt.Fprintf(out, "  - %s (conflicts with %s)\n", e.Behavior.Name, e.ConflictsWith)
 					}
+					fmt.Fprintln(out)
+				}
+
+				if showNearMisses {
+					printNearMisses(out, nearMisses)
 				}
 			}
 
-			return nil
+			return emptyErr
 		},
 	}
 
 	cmd.Flags().String("file", "", "Current file path")
 	cmd.Flags().String("task", "", "Current task type")
 	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+	addContextFlag(cmd)
+	cmd.Flags().Bool("near-misses", false, "Also show behaviors that almost matched and suggest condition relaxations")
+	cmd.Flags().Bool("spread", false, "Also show related behaviors reached by spreading activation over graph edges")
+	cmd.Flags().String("format", "", "Render active behaviors as an agent rules file ("+rulesFormatList()+")")
+	cmd.Flags().Bool("no-cache", false, "Re-evaluate the stores instead of using the context cache")
+	cmd.Flags().String("store", string(store.BackendAuto), "Store backend: sqlite, jsonl (read nodes.jsonl without SQLite), or auto (jsonl when floop.db is absent)")
+	cmd.Flags().Bool("fail-empty", false, "Exit with status 5 when no behavior is active for the context")
 
 	return cmd
 }
+
+// relatedBehavior is an active behavior surfaced by spreading activation
+// rather than a direct context match.
+type relatedBehavior struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name"`
+	Activation float64 `json:"activation"`
+	Distance   int     `json:"distance"`
+	SeedSource string  `json:"seed_source"`
+}
+
+// spreadActivation seeds the spreading engine with the direct matches and
+// appends the related behaviors it reaches. It returns the merged matches
+// and the spreading result for each behavior reached only by spreading.
+func spreadActivation(ctx context.Context, root string, scope constants.Scope, backend store.Backend, matches []activation.ActivationResult) ([]activation.ActivationResult, map[string]spreading.Result, error) {
+	graphStore, err := openStoreForBackend(root, scope, backend)
+	if err != nil {
+		return matches, nil, err
+	}
+	defer graphStore.Close()
+
+	spreadConfig := spreading.DefaultConfig()
+	affinityConfig := spreading.DefaultAffinityConfig()
+	spreadConfig.Affinity = &affinityConfig
+	spreadConfig.TagProvider = spreading.NewStoreTagProvider(graphStore)
+	results, err := spreading.NewEngine(graphStore, spreadConfig).Activate(ctx, spreading.SeedsFromMatches(matches))
+	if err != nil {
+		return matches, nil, err
+	}
+
+	direct := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		direct[m.Behavior.ID] = true
+	}
+	related := make(map[string]spreading.Result)
+	for _, r := range results {
+		if !direct[r.BehaviorID] {
+			related[r.BehaviorID] = r
+		}
+	}
+	return spreading.MergeMatches(ctx, graphStore, matches, results), related, nil
+}
+
+// relatedReports lists the active behaviors that spreading activation
+// surfaced, strongest first.
+func relatedReports(active []models.Behavior, related map[string]spreading.Result) []relatedBehavior {
+	reports := []relatedBehavior{}
+	for _, b := range active {
+		r, ok := related[b.ID]
+		if !ok {
+			continue
+		}
+		reports = append(reports, relatedBehavior{
+			ID:         b.ID,
+			Name:       b.Name,
+			Activation: r.Activation,
+			Distance:   r.Distance,
+			SeedSource: r.SeedSource,
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].Activation > reports[j].Activation })
+	return reports
+}
+
+// nearMissReport is a near miss with its recorded history and any
+// condition relaxation suggestions.
+type nearMissReport struct {
+	activation.NearMiss
+	Stats       nearmiss.Stats        `json:"stats"`
+	Suggestions []nearmiss.Suggestion `json:"suggestions,omitempty"`
+}
+
+// recordNearMisses counts each near miss on its behavior and returns the
+// updated history with relaxation suggestions. Reports are returned even if
+// recording fails, using the stats loaded with the behaviors. Read-only
+// stores report the history without counting.
+func recordNearMisses(root string, scope constants.Scope, backend store.Backend, misses []activation.NearMiss, suggestAfter int) ([]nearMissReport, error) {
+	reports := make([]nearMissReport, len(misses))
+	for i, miss := range misses {
+		reports[i] = nearMissReport{NearMiss: miss}
+	}
+	if len(misses) == 0 {
+		return reports, nil
+	}
+
+	ctx := context.Background()
+	graphStore, err := openStoreForBackend(root, scope, backend)
+	if err != nil {
+		return reports, err
+	}
+	defer graphStore.Close()
+
+	recordErr := nearmiss.Record(ctx, graphStore, misses, time.Now())
+	if errors.Is(recordErr, store.ErrReadOnly) {
+		recordErr = nil
+	}
+	for i := range reports {
+		id := reports[i].Behavior.ID
+		node, err := graphStore.GetNode(ctx, id)
+		if err != nil || node == nil {
+			continue
+		}
+		reports[i].Stats = nearmiss.StatsFromMetadata(node.Metadata)
+		reports[i].Suggestions = nearmiss.Suggest(id, reports[i].Behavior.When, reports[i].Stats, suggestAfter)
+	}
+	return reports, recordErr
+}
+
+// printNearMisses writes the near-miss section of 'floop active'.
+func printNearMisses(out io.Writer, reports []nearMissReport) {
+	if len(reports) == 0 {
+		fmt.Fprintln(out, "No near misses for this context.")
+		return
+	}
+
+	fmt.Fprintf(out, "Near misses (%d):\n\n", len(reports))
+	for i, r := range reports {
+		fmt.Fprintf(out, "%d. [%s] %s\n", i+1, r.Behavior.Kind, r.Behavior.Name)
+		fmt.Fprintf(out, "   %s\n", r.Behavior.Content.Canonical)
+		for _, key := range r.FailedConditions {
+			fmt.Fprintf(out, "   Failed: %s=%v\n", key, r.Behavior.When[key])
+		}
+		if len(r.MatchedConditions) > 0 {
+			fmt.Fprintf(out, "   Matched: %v\n", r.MatchedConditions)
+		}
+		if r.Stats.Count > 0 {
+			fmt.Fprintf(out, "   Near misses so far: %d\n", r.Stats.Count)
+		}
+		for _, sug := range r.Suggestions {
+			fmt.Fprintf(out, "   Suggestion: %s\n", sug.Message)
+		}
+		fmt.Fprintln(out)
+	}
+}
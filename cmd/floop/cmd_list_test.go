@@ -2,7 +2,9 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,6 +12,7 @@ import (
 	"time"
 
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 func TestListCorrectionsWithData(t *testing.T) {
@@ -158,12 +161,10 @@ func TestListCmdLocalNotInitialized(t *testing.T) {
 	rootCmd.SetOut(&buf)
 	rootCmd.SetArgs([]string{"list", "--local", "--root", tmpDir})
 
-	// Should succeed gracefully with message
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("list --local failed: %v", err)
-	}
-	if !strings.Contains(buf.String(), "not initialized") {
-		t.Errorf("expected 'not initialized' message, got: %s", buf.String())
+	err := rootCmd.Execute()
+	wantErrorCode(t, err, codeNotInitialized)
+	if !strings.Contains(err.Error(), "not initialized") {
+		t.Errorf("expected 'not initialized' error, got: %v", err)
 	}
 }
 
@@ -177,16 +178,17 @@ func TestListCmdLocalNotInitializedJSON(t *testing.T) {
 	rootCmd.SetOut(&buf)
 	rootCmd.SetArgs([]string{"list", "--local", "--json", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("list --local --json failed: %v", err)
-	}
+	err := rootCmd.Execute()
+	wantErrorCode(t, err, codeNotInitialized)
 
+	var envelope bytes.Buffer
+	reportError(rootCmd, &envelope, io.Discard, err)
 	var result map[string]interface{}
-	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+	if err := json.Unmarshal(envelope.Bytes(), &result); err != nil {
 		t.Fatalf("failed to parse JSON: %v", err)
 	}
-	if result["error"] == nil {
-		t.Error("expected error field in JSON output")
+	if details, _ := result["details"].(map[string]interface{}); details["scope"] != "local" {
+		t.Errorf("expected local scope in error details, got: %v", result)
 	}
 }
 
@@ -233,3 +235,189 @@ func TestListCmdWithInitializedLocalStore(t *testing.T) {
 		t.Fatalf("list --local failed: %v", err)
 	}
 }
+
+func TestListCmdFilter(t *testing.T) {
+	tmpDir, slogID := setupQueryTest(t)
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrap := models.Behavior{ID: "wrap-errors", Name: "wrap-errors", Kind: models.BehaviorKindDirective, Confidence: 0.8,
+		Content: models.BehaviorContent{Canonical: "Wrap errors with context using fmt.Errorf", Tags: []string{"errors"}}}
+	if _, err := s.AddNode(context.Background(), models.BehaviorToNode(&wrap)); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	tests := []struct {
+		filter string
+		want   []string
+	}{
+		{"wrapping", []string{"wrap-errors"}}, // stemmed
+		{"structured log", []string{slogID}},  // every word, last as prefix
+		{"errors logging", nil},               // no behavior has both
+		{"SLOG", []string{slogID}},            // case-insensitive
+	}
+	for _, tt := range tests {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newListCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"list", "--json", "--filter", tt.filter, "--root", tmpDir})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("list --filter %q failed: %v", tt.filter, err)
+		}
+		var result struct {
+			Behaviors []models.Behavior `json:"behaviors"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		var got []string
+		for _, b := range result.Behaviors {
+			got = append(got, b.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("list --filter %q = %v, want %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestListCmdQueryFlags(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := models.Behavior{ID: "no-secrets", Name: "no-secrets", Kind: models.BehaviorKindConstraint, Confidence: 0.95,
+		Content:    models.BehaviorContent{Canonical: "Never commit secrets", Tags: []string{"security"}},
+		Provenance: models.Provenance{SourceType: models.SourceTypeImported, Package: "team/core", CreatedAt: time.Now().AddDate(0, -2, 0)}}
+	recent := models.Behavior{ID: "wrap-errors", Name: "wrap-errors", Kind: models.BehaviorKindDirective, Confidence: 0.7,
+		Content:    models.BehaviorContent{Canonical: "Wrap errors with context", Tags: []string{"errors", "go"}},
+		Provenance: models.Provenance{SourceType: models.SourceTypeLearned, CreatedAt: time.Now().AddDate(0, 0, -1)}}
+	for _, b := range []models.Behavior{old, recent} {
+		if _, err := s.AddNode(context.Background(), models.BehaviorToNode(&b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"--kind", "constraint"}, []string{"no-secrets"}},
+		{[]string{"--tag", "go"}, []string{"wrap-errors"}},
+		{[]string{"--min-confidence", "0.9"}, []string{"no-secrets"}},
+		{[]string{"--pack", "team/core"}, []string{"no-secrets"}},
+		{[]string{"--created-after", "7d"}, []string{"wrap-errors"}},
+		{[]string{"--sort", "confidence"}, []string{"no-secrets", "wrap-errors"}},
+		{[]string{"--sort", "created"}, []string{"wrap-errors", "no-secrets"}},
+		{[]string{"--filter", "errors", "--kind", "constraint"}, nil},
+	}
+	for _, tt := range tests {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newListCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append([]string{"list", "--json", "--scope", "local", "--root", tmpDir}, tt.args...))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("list %v failed: %v", tt.args, err)
+		}
+		var result struct {
+			Behaviors []models.Behavior `json:"behaviors"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		var got []string
+		for _, b := range result.Behaviors {
+			got = append(got, b.ID)
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("list %v = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestListCmdQueryFlagsInvalid(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	for _, args := range [][]string{
+		{"--scope", "team"},
+		{"--scope", "local", "--global"},
+		{"--sort", "name"},
+		{"--min-confidence", "2"},
+		{"--created-after", "last week"},
+		{"--expiring-within", "soon"},
+	} {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newListCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append([]string{"list", "--root", tmpDir}, args...))
+		if err := rootCmd.Execute(); err == nil {
+			t.Errorf("list %v succeeded, want error", args)
+		}
+	}
+}
+
+func TestListCmdPaged(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"page-a", "page-b", "page-c"} {
+		b := models.Behavior{ID: id, Name: id, Kind: models.BehaviorKindDirective, Confidence: 0.8,
+			Content: models.BehaviorContent{Canonical: "Paged behavior " + id}}
+		if _, err := s.AddNode(context.Background(), models.BehaviorToNode(&b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	var got []string
+	cursor := ""
+	for pages := 0; pages < 5; pages++ {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newListCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs([]string{"list", "--json", "--scope", "local", "--limit", "2", "--cursor", cursor, "--root", tmpDir})
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("list --limit 2 failed: %v", err)
+		}
+		var result struct {
+			Behaviors  []models.Behavior `json:"behaviors"`
+			NextCursor string            `json:"next_cursor"`
+		}
+		if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+		}
+		if len(result.Behaviors) > 2 {
+			t.Errorf("page of %d behaviors, limit 2", len(result.Behaviors))
+		}
+		for _, b := range result.Behaviors {
+			got = append(got, b.ID)
+		}
+		if result.NextCursor == "" {
+			break
+		}
+		cursor = result.NextCursor
+	}
+	if strings.Join(got, ",") != "page-a,page-b,page-c" {
+		t.Errorf("paged list = %v, want page-a, page-b, page-c", got)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newListCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"list", "--limit", "2", "--sort", "confidence", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("list --limit with --sort succeeded, want error")
+	}
+}
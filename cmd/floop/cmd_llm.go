@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/spf13/cobra"
+)
+
+func newLLMCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "llm",
+		Short: "Inspect LLM provider usage",
+		Long: `Inspect how floop uses the configured LLM provider.
+
+Every call floop makes to an API provider (anthropic, openai, ollama,
+openai-compatible) is recorded in ~/.floop/llm-usage.json with its token
+counts, estimated cost at list prices, and purpose: compare (duplicate and
+conflict checks), merge, extract (learning from corrections and sessions),
+or other. llm.daily_budget_usd and llm.daily_call_limit cap daily use; once
+either is reached, LLM features fall back to rules until the next day.
+
+Examples:
+  floop llm usage
+  floop llm usage --days 30 --json`,
+	}
+
+	cmd.AddCommand(newLLMUsageCmd())
+
+	return cmd
+}
+
+func newLLMUsageCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show LLM calls, tokens, and estimated cost per day",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			days, _ := cmd.Flags().GetInt("days")
+			if days < 1 {
+				return fmt.Errorf("--days must be at least 1")
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			ledger := llmUsageLedger(cfg)
+			if ledger == nil {
+				return fmt.Errorf("cannot locate the global .floop directory")
+			}
+			recorded, err := ledger.Days()
+			if err != nil {
+				return err
+			}
+
+			// Most recent first, limited to the requested window
+			cutoff := time.Now().AddDate(0, 0, -days+1).Format(time.DateOnly)
+			var dates []string
+			for date := range recorded {
+				if date >= cutoff {
+					dates = append(dates, date)
+				}
+			}
+			slices.Sort(dates)
+			slices.Reverse(dates)
+
+			var total llm.UsageTotals
+			byPurpose := make(map[string]llm.UsageTotals)
+			for _, date := range dates {
+				addUsageTotals(&total, recorded[date].UsageTotals)
+				for purpose, t := range recorded[date].ByPurpose {
+					p := byPurpose[purpose]
+					addUsageTotals(&p, t)
+					byPurpose[purpose] = p
+				}
+			}
+			today := recorded[time.Now().Format(time.DateOnly)]
+
+			if jsonOut {
+				window := make(map[string]llm.DayUsage, len(dates))
+				for _, date := range dates {
+					window[date] = recorded[date]
+				}
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"ledger":     ledger.Path(),
+					"budget":     ledger.Budget(),
+					"today":      today,
+					"days":       window,
+					"total":      total,
+					"by_purpose": byPurpose,
+				})
+			}
+
+			fmt.Fprintf(out, "LLM usage (%s)\n\n", ledger.Path())
+			fmt.Fprintf(out, "Today: %d calls, %d input / %d output tokens, ~$%.4f\n",
+				today.Calls, today.InputTokens, today.OutputTokens, today.CostUSD)
+			printLLMBudget(out, ledger.Budget(), today.UsageTotals)
+
+			if len(dates) == 0 {
+				fmt.Fprintf(out, "\nNo LLM calls recorded in the last %d days.\n", days)
+				return nil
+			}
+
+			fmt.Fprintf(out, "\n%-10s  %6s  %10s  %10s  %10s\n", "Date", "Calls", "Input", "Output", "Cost")
+			for _, date := range dates {
+				d := recorded[date]
+				fmt.Fprintf(out, "%-10s  %6d  %10d  %10d  %10s\n", date, d.Calls, d.InputTokens, d.OutputTokens, fmt.Sprintf("$%.4f", d.CostUSD))
+			}
+			fmt.Fprintf(out, "%-10s  %6d  %10d  %10d  %10s\n", "Total", total.Calls, total.InputTokens, total.OutputTokens, fmt.Sprintf("$%.4f", total.CostUSD))
+
+			fmt.Fprintf(out, "\nBy purpose (last %d days):\n", days)
+			purposes := make([]string, 0, len(byPurpose))
+			for purpose := range byPurpose {
+				purposes = append(purposes, purpose)
+			}
+			slices.Sort(purposes)
+			for _, purpose := range purposes {
+				p := byPurpose[purpose]
+				fmt.Fprintf(out, "  %-8s  %6d calls  %10d tokens  $%.4f\n", purpose, p.Calls, p.InputTokens+p.OutputTokens, p.CostUSD)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("days", 7, "Number of days to show, including today")
+
+	return cmd
+}
+
+// addUsageTotals adds t into sum.
+func addUsageTotals(sum *llm.UsageTotals, t llm.UsageTotals) {
+	sum.Calls += t.Calls
+	sum.InputTokens += t.InputTokens
+	sum.OutputTokens += t.OutputTokens
+	sum.CostUSD += t.CostUSD
+}
+
+// printLLMBudget prints today's use against each configured daily limit.
+func printLLMBudget(w io.Writer, budget llm.Budget, today llm.UsageTotals) {
+	if budget == (llm.Budget{}) {
+		fmt.Fprintln(w, "Budget: unlimited (set llm.daily_budget_usd or llm.daily_call_limit)")
+		return
+	}
+	if budget.DailyCostUSD > 0 {
+		fmt.Fprintf(w, "Budget: $%.4f of $%.2f (%.0f%%)\n", today.CostUSD, budget.DailyCostUSD, 100*today.CostUSD/budget.DailyCostUSD)
+	}
+	if budget.DailyCalls > 0 {
+		fmt.Fprintf(w, "Calls:  %d of %d\n", today.Calls, budget.DailyCalls)
+	}
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/llm"
+)
+
+func TestLLMUsageCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	globalDir := filepath.Join(tmpDir, "home", ".floop")
+	if err := os.MkdirAll(globalDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(globalDir, "config.yaml"), []byte("llm:\n  daily_call_limit: 10\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	ledger := llm.NewLedger(filepath.Join(globalDir, llm.UsageFile), llm.Budget{})
+	ledger.Record(llm.PurposeMerge, llm.Usage{Model: "gpt-4o-mini", InputTokens: 400, OutputTokens: 100})
+	ledger.Record(llm.PurposeCompare, llm.Usage{Model: "gpt-4o-mini", InputTokens: 200, OutputTokens: 10})
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newLLMCmd())
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"llm", "usage"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("llm usage: %v", err)
+	}
+	for _, want := range []string{"Today: 2 calls, 600 input / 110 output tokens", "Calls:  2 of 10", "merge", "compare"} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("output missing %q:\n%s", want, out.String())
+		}
+	}
+
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newLLMCmd())
+	out.Reset()
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"llm", "usage", "--json"})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("llm usage --json: %v", err)
+	}
+	var result struct {
+		Budget    llm.Budget                 `json:"budget"`
+		Total     llm.UsageTotals            `json:"total"`
+		ByPurpose map[string]llm.UsageTotals `json:"by_purpose"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &result); err != nil {
+		t.Fatalf("parsing JSON output: %v\n%s", err, out.String())
+	}
+	if result.Total.Calls != 2 || result.Budget.DailyCalls != 10 || result.ByPurpose[llm.PurposeMerge].InputTokens != 400 {
+		t.Errorf("unexpected JSON result: %+v", result)
+	}
+}
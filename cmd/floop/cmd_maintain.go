@@ -0,0 +1,202 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/decay"
+	"github.com/nvandessel/floop/internal/expiry"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newMaintainCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "maintain",
+		Short: "Run periodic maintenance jobs on the behavior stores",
+	}
+
+	cmd.AddCommand(newMaintainDecayCmd())
+	cmd.AddCommand(newMaintainExpireCmd())
+
+	return cmd
+}
+
+func newMaintainDecayCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "decay",
+		Short: "Lower the confidence of behaviors that have not activated recently",
+		Long: `Decay the confidence of stale behaviors.
+
+A behavior that has not activated within maintenance.decay_window (default
+30d) loses confidence exponentially, halving every maintenance.decay_half_life
+(default 90d), down to the reinforcement floor (0.3). Stale behaviors then
+sort behind fresh ones and are the first shed when injection runs out of
+token budget. Activating a behavior stops its decay, and reinforcement
+raises its confidence again.
+
+The local and global stores each record when they were last decayed, so
+running decay again only applies the time since the previous run. The MCP
+server also runs this at startup every maintenance.decay_interval (off by
+default).
+
+Examples:
+  floop maintain decay --dry-run
+  floop maintain decay
+  floop maintain decay --window 14d --half-life 60d`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			window, _ := cmd.Flags().GetString("window")
+			halfLife, _ := cmd.Flags().GetString("half-life")
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			if window != "" {
+				cfg.Maintenance.DecayWindow = window
+			}
+			if halfLife != "" {
+				cfg.Maintenance.DecayHalfLife = halfLife
+			}
+			opts, err := decay.OptionsFromConfig(cfg.Maintenance)
+			if err != nil {
+				return err
+			}
+			opts.DryRun = dryRun
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open store: %w", err)
+			}
+			defer graphStore.Close()
+
+			var reports []*decay.Report
+			for _, scope := range decay.Scopes(graphStore, root) {
+				scopeOpts := opts
+				scopeOpts.Store = scope.Store
+				scopeOpts.LastRun = decay.LastRun(scope.Dir)
+				report, err := decay.Run(cmd.Context(), scopeOpts)
+				if err != nil {
+					return fmt.Errorf("%s store: %w", scope.Name, err)
+				}
+				report.Scope = scope.Name
+				if !dryRun {
+					if err := decay.MarkRun(scope.Dir, report); err != nil {
+						return err
+					}
+				}
+				reports = append(reports, report)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"dry_run": dryRun,
+					"window":  cfg.Maintenance.DecayWindow,
+					"floor":   opts.Floor,
+					"scopes":  reports,
+				})
+			}
+			printDecayReports(out, reports)
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Report the changes without writing them")
+	cmd.Flags().String("window", "", "Inactivity before decay starts (default maintenance.decay_window)")
+	cmd.Flags().String("half-life", "", "Time for a stale behavior to lose half its confidence (default maintenance.decay_half_life)")
+
+	return cmd
+}
+
+// printDecayReports writes a human-readable summary of a decay run.
+func printDecayReports(out *output, reports []*decay.Report) {
+	for _, r := range reports {
+		verb := "Decayed"
+		if r.DryRun {
+			verb = "Would decay"
+		}
+		fmt.Fprintf(out, "%s store: %s %d of %d behavior(s)\n", r.Scope, verb, len(r.Decayed), r.Examined)
+		for _, c := range r.Decayed {
+			fmt.Fprintf(out, "  %s %-40s %.2f -> %.2f (last active %s)\n",
+				c.ID, truncatePreview(c.Name, 40), c.From, c.To, c.LastActivated.Format("2006-01-02"))
+		}
+	}
+}
+
+func newMaintainExpireCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "expire",
+		Short: "Mark behaviors past their expiry as dormant",
+		Long: `Retire temporary behaviors whose expiry has passed.
+
+Behaviors learned with --expires (for example a workaround needed only
+during a migration) stop activating once they expire. This marks them
+dormant, so they also leave listings and stats. 'floop restore <id>'
+brings a dormant behavior back without an expiry.
+
+Examples:
+  floop list --expiring 14d
+  floop maintain expire --dry-run
+  floop maintain expire`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open store: %w", err)
+			}
+			defer graphStore.Close()
+
+			var reports []*expiry.Report
+			for _, scope := range decay.Scopes(graphStore, root) {
+				report, err := expiry.Run(cmd.Context(), expiry.Options{Store: scope.Store, DryRun: dryRun})
+				if err != nil {
+					return fmt.Errorf("%s store: %w", scope.Name, err)
+				}
+				report.Scope = scope.Name
+				reports = append(reports, report)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"dry_run": dryRun,
+					"scopes":  reports,
+				})
+			}
+			for _, r := range reports {
+				verb := "Made"
+				if r.DryRun {
+					verb = "Would make"
+				}
+				fmt.Fprintf(out, "%s store: %s %d expired behavior(s) dormant\n", r.Scope, verb, len(r.Dormant))
+				for _, e := range r.Dormant {
+					fmt.Fprintf(out, "  %s %-40s expired %s\n",
+						e.ID, truncatePreview(e.Name, 40), e.ExpiresAt.Local().Format("2006-01-02 15:04"))
+				}
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Report the changes without writing them")
+
+	return cmd
+}
@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/decay"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestMaintainDecayCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	floopDir := filepath.Join(tmpDir, ".floop")
+	if err := os.MkdirAll(floopDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lastActivated := time.Now().AddDate(0, 0, -200).Format(time.RFC3339)
+	_, err = graphStore.LocalStore().AddNode(ctx, store.Node{
+		ID:   "b-stale",
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "stale-behavior",
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "Use the old build script"},
+		},
+		Metadata: map[string]interface{}{
+			"confidence": 0.8,
+			"stats":      map[string]interface{}{"last_activated": lastActivated},
+		},
+	})
+	graphStore.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCmd(t, newMaintainCmd(), "decay", "--dry-run", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("maintain decay --dry-run failed: %v", err)
+	}
+	if !strings.Contains(out, "local store: Would decay 1 of 1 behavior(s)") {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+	if _, err := os.Stat(filepath.Join(floopDir, decay.StateFile)); !os.IsNotExist(err) {
+		t.Error("dry run should not record a decay run")
+	}
+
+	out, err = runCmd(t, newMaintainCmd(), "decay", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("maintain decay failed: %v", err)
+	}
+	var result struct {
+		Scopes []decay.Report `json:"scopes"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	var local *decay.Report
+	for i := range result.Scopes {
+		if result.Scopes[i].Scope == "local" {
+			local = &result.Scopes[i]
+		}
+	}
+	if local == nil || len(local.Decayed) != 1 {
+		t.Fatalf("scopes = %+v, want one decayed local behavior", result.Scopes)
+	}
+	// 170 days past the 30d window at a 90d half-life leaves 0.8 at 0.22,
+	// below the floor.
+	if c := local.Decayed[0]; c.From != 0.8 || c.To != 0.3 {
+		t.Errorf("change = %+v, want 0.8 decayed to the 0.3 floor", c)
+	}
+	if _, err := os.Stat(filepath.Join(floopDir, decay.StateFile)); err != nil {
+		t.Errorf("decay run not recorded: %v", err)
+	}
+
+	// The behavior is at the floor, so a second run changes nothing.
+	out, err = runCmd(t, newMaintainCmd(), "decay", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("second maintain decay failed: %v", err)
+	}
+	if !strings.Contains(out, "local store: Decayed 0 of 1 behavior(s)") {
+		t.Errorf("unexpected second-run output:\n%s", out)
+	}
+}
+
+func TestMaintainDecayCmdRequiresInit(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runCmd(t, newMaintainCmd(), "decay", "--root", tmpDir); err == nil {
+		t.Error("expected error without .floop")
+	}
+}
+
+func TestMaintainExpireCmd(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	run := func(cmd *cobra.Command, args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(cmd)
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return out.String()
+	}
+	learn := func(right, expires string) string {
+		t.Helper()
+		var result struct {
+			Behavior models.Behavior `json:"behavior"`
+		}
+		out := run(newLearnCmd(), "learn", "--json", "--scope", "local", "--right", right, "--expires", expires)
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		if result.Behavior.ExpiresAt == nil {
+			t.Fatalf("learn --expires %s: behavior has no expiry", expires)
+		}
+		return result.Behavior.ID
+	}
+	list := func(args ...string) []string {
+		t.Helper()
+		var result struct {
+			Behaviors []models.Behavior `json:"behaviors"`
+		}
+		out := run(newListCmd(), append([]string{"list", "--json", "--scope", "local"}, args...)...)
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		var ids []string
+		for _, b := range result.Behaviors {
+			ids = append(ids, b.ID)
+		}
+		return ids
+	}
+
+	later := learn("pin grpc to v1.58 until the upgrade lands", "30d")
+	expired := learn("run the legacy migration script before deploys", "2026-01-01T00:00:00Z")
+
+	if got := list("--expiring"); strings.Join(got, ",") != expired+","+later {
+		t.Errorf("list --expiring = %v, want [%s %s]", got, expired, later)
+	}
+	if got := list("--expiring-within", "1d"); strings.Join(got, ",") != expired {
+		t.Errorf("list --expiring-within 1d = %v, want [%s]", got, expired)
+	}
+
+	out := run(newMaintainCmd(), "maintain", "expire", "--dry-run")
+	if !strings.Contains(out, "local store: Would make 1 expired behavior(s) dormant") {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+	out = run(newMaintainCmd(), "maintain", "expire")
+	if !strings.Contains(out, "local store: Made 1 expired behavior(s) dormant") || !strings.Contains(out, expired) {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if got := list("--expiring"); strings.Join(got, ",") != later {
+		t.Errorf("list --expiring after expire = %v, want [%s]", got, later)
+	}
+
+	// Restoring a dormant behavior clears its expiry.
+	run(newRestoreCmd(), "restore", expired)
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	node, err := s.GetNode(context.Background(), expired)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", expired, node, err)
+	}
+	if node.Kind != store.NodeKindBehavior || node.Metadata["expires_at"] != nil || node.Metadata["dormant_at"] != nil {
+		t.Errorf("restored behavior = %s %v, want active without expiry", node.Kind, node.Metadata)
+	}
+}
@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/edges"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// runStoreMerge implements 'floop merge <other-root>': it imports the
+// behaviors of another project's local store into this one's.
+func runStoreMerge(cmd *cobra.Command, root, otherRoot string) error {
+	out := newOutput(cmd)
+	jsonOut, _ := cmd.Flags().GetBool("json")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	threshold, _ := cmd.Flags().GetFloat64("threshold")
+	ctx := cmd.Context()
+
+	if threshold < 0 || threshold > 1 {
+		return fmt.Errorf("--threshold must be between 0.0 and 1.0")
+	}
+	if !floopDirExists(root) {
+		return errNotInitialized
+	}
+	if !floopDirExists(otherRoot) {
+		return fmt.Errorf("no .floop store in %s", otherRoot)
+	}
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", root, err)
+	}
+	absOther, err := filepath.Abs(otherRoot)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", otherRoot, err)
+	}
+	if absRoot == absOther {
+		return fmt.Errorf("cannot merge a store into itself")
+	}
+
+	src, err := store.NewSQLiteGraphStore(absOther)
+	if err != nil {
+		return fmt.Errorf("failed to open store in %s: %w", otherRoot, err)
+	}
+	defer src.Close()
+	dst, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		return fmt.Errorf("failed to open local store: %w", err)
+	}
+	defer dst.Close()
+
+	floopCfg, err := config.LoadForProject(root)
+	if err != nil {
+		slog.Warn("failed to load config", "error", err)
+	}
+	llmClient := createLLMClient(floopCfg)
+
+	result, err := dedup.ImportStore(ctx, dst, src, dedup.ImportConfig{
+		SimilarityThreshold: threshold,
+		UseLLM:              floopCfg != nil && floopCfg.LLM.Enabled && llmClient != nil,
+		LLMClient:           llmClient,
+		Source:              absOther,
+		DryRun:              dryRun,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to merge %s: %w", otherRoot, err)
+	}
+
+	derived := 0
+	if changed := result.ChangedIDs(); !dryRun && len(changed) > 0 {
+		all, err := edges.LoadBehaviorsFromStore(ctx, dst)
+		if err != nil {
+			return fmt.Errorf("failed to load behaviors: %w", err)
+		}
+		sub, err := edges.DeriveEdgesForSubsetWithTuning(ctx, dst, changed, all, scopeSimilarityTuning(root, constants.ScopeLocal))
+		if err != nil {
+			slog.Warn("edge derivation failed", "error", err)
+		} else {
+			derived = sub.EdgesCreated
+		}
+	}
+
+	if jsonOut {
+		return json.NewEncoder(out).Encode(map[string]interface{}{
+			"from":          absOther,
+			"result":        result,
+			"derived_edges": derived,
+		})
+	}
+
+	if dryRun {
+		fmt.Fprintf(out, "Dry run: merging %s\n", absOther)
+	} else {
+		fmt.Fprintf(out, "Merged %s\n", absOther)
+	}
+	for _, b := range result.Behaviors {
+		switch b.Action {
+		case dedup.ImportPresent:
+			continue
+		case dedup.ImportRename:
+			fmt.Fprintf(out, "  %-6s %s -> %s  %s\n", b.Action, b.SourceID, b.ID, b.Name)
+		case dedup.ImportMerge:
+			fmt.Fprintf(out, "  %-6s %s -> %s  %s (similarity %.2f)\n", b.Action, b.SourceID, b.ID, b.Name, b.Similarity)
+		default:
+			fmt.Fprintf(out, "  %-6s %s  %s\n", b.Action, b.ID, b.Name)
+		}
+	}
+	fmt.Fprintf(out, "\n%d added, %d renamed, %d merged, %d already present\n",
+		result.Count(dedup.ImportAdd), result.Count(dedup.ImportRename),
+		result.Count(dedup.ImportMerge), result.Count(dedup.ImportPresent))
+	if !dryRun {
+		fmt.Fprintf(out, "Edges: %d imported, %d derived\n", result.EdgesImported, derived)
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestMergeCmdStore(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	otherRoot := t.TempDir()
+
+	for _, args := range [][]string{
+		{"init", "--root", otherRoot},
+		{"learn", "--wrong", "committed secrets", "--right", "keep secrets in the vault", "--scope", "local", "--root", otherRoot},
+	} {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newInitCmd(), newLearnCmd())
+		rootCmd.SetOut(&bytes.Buffer{})
+		rootCmd.SetArgs(args)
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%s failed: %v", args[0], err)
+		}
+	}
+
+	var out bytes.Buffer
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newMergeCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"merge", otherRoot, "--dry-run", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("merge --dry-run failed: %v", err)
+	}
+	if !strings.Contains(out.String(), "1 added") {
+		t.Errorf("merge --dry-run output = %q, want 1 added", out.String())
+	}
+
+	out.Reset()
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newMergeCmd())
+	rootCmd.SetOut(&out)
+	rootCmd.SetArgs([]string{"merge", otherRoot, "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("merge failed: %v", err)
+	}
+	var resp struct {
+		Result struct {
+			Behaviors []struct {
+				ID     string `json:"id"`
+				Action string `json:"action"`
+			} `json:"behaviors"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal(out.Bytes(), &resp); err != nil {
+		t.Fatalf("parse JSON: %v\n%s", err, out.String())
+	}
+	if len(resp.Result.Behaviors) != 1 || resp.Result.Behaviors[0].Action != "add" {
+		t.Fatalf("merge result = %+v, want one added behavior", resp.Result.Behaviors)
+	}
+
+	local, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer local.Close()
+	id := resp.Result.Behaviors[0].ID
+	if n, err := local.GetNode(context.Background(), id); err != nil || n == nil {
+		t.Errorf("local store is missing %s after merge (err %v)", id, err)
+	}
+}
+
+func TestMergeCmdStoreIntoItself(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newMergeCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"merge", tmpDir, "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "into itself") {
+		t.Errorf("merge into itself error = %v, want refusal", err)
+	}
+}
@@ -24,9 +24,9 @@ func newMigrateCmd() *cobra.Command {
 }
 
 func runMigrate(cmd *cobra.Command, args []string) error {
+	out := newOutput(cmd)
 	mergeLocal, _ := cmd.Flags().GetBool("merge-local-to-global")
 	jsonOut, _ := cmd.Flags().GetBool("json")
-	out := cmd.OutOrStdout()
 
 	if !mergeLocal {
 		return fmt.Errorf("no migration action specified; use --merge-local-to-global")
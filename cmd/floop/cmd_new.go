@@ -0,0 +1,336 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/edges"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// behaviorTemplate is the scaffold shown when authoring a behavior of a
+// kind: the shape its content should take and an example.
+type behaviorTemplate struct {
+	shape   string
+	example string
+}
+
+var behaviorTemplates = map[models.BehaviorKind]behaviorTemplate{
+	models.BehaviorKindDirective:  {"<do something> [when <situation>].", "Run go vet before committing Go changes."},
+	models.BehaviorKindConstraint: {"Never <do something> [because <reason>].", "Never commit secrets or .env files."},
+	models.BehaviorKindPreference: {"Prefer <X> over <Y> [because <reason>].", "Prefer table-driven tests over repeated test functions."},
+	models.BehaviorKindProcedure:  {"To <goal>: 1. <step> 2. <step> 3. <step>", "To release: 1. update CHANGELOG.md 2. tag vX.Y.Z 3. push the tag."},
+	models.BehaviorKindWorkflow:   {"When <trigger>, <step>; if <condition>, <step>.", "When a migration changes, regenerate the schema; if it drops a column, ask for review."},
+}
+
+func newNewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "new",
+		Short: "Scaffold new floop items by hand",
+	}
+	cmd.AddCommand(newNewBehaviorCmd())
+	return cmd
+}
+
+func newNewBehaviorCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "behavior",
+		Short: "Author a behavior by hand",
+		Long: `Create a behavior written directly by a person, rather than one
+extracted from a correction with 'floop learn'.
+
+The content goes through the same pipeline as learned behaviors: it is
+sanitized, tags are extracted and merged with --tags, and edges to related
+behaviors are derived. Provenance records the behavior as manual.
+
+With --interactive, floop shows a template for the kind and prompts for
+every field not given as a flag, then asks for confirmation. Otherwise
+--content is required.
+
+Examples:
+  floop new behavior --kind constraint --interactive
+  floop new behavior --kind preference --content "Prefer errors.Is over string matching" --when language=go
+  floop new behavior --kind procedure --content "To release: 1. tag 2. push" --scope global`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			interactive, _ := cmd.Flags().GetBool("interactive")
+			kind, _ := cmd.Flags().GetString("kind")
+			name, _ := cmd.Flags().GetString("name")
+			content, _ := cmd.Flags().GetString("content")
+			summary, _ := cmd.Flags().GetString("summary")
+			tags, _ := cmd.Flags().GetStringSlice("tags")
+			priority, _ := cmd.Flags().GetInt("priority")
+			scopeVal, _ := cmd.Flags().GetString("scope")
+			whenPairs, _ := cmd.Flags().GetStringArray("when")
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+			if scopeVal != "" && !constants.Scope(scopeVal).Valid() {
+				return fmt.Errorf("--scope must be 'local' or 'global'")
+			}
+			when, err := parseKeyValues("--when", whenPairs)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			spec := learning.ManualSpec{
+				Kind:     models.BehaviorKind(kind),
+				Name:     name,
+				Content:  content,
+				Summary:  summary,
+				When:     when,
+				Tags:     tags,
+				Priority: priority,
+				Author:   os.Getenv("USER"),
+			}
+			if expires, _ := cmd.Flags().GetString("expires"); expires != "" {
+				t, err := parseExpiry(expires, now)
+				if err != nil {
+					return err
+				}
+				spec.ExpiresAt = &t
+			}
+
+			// Prompts go to stderr when stdout carries JSON
+			p := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: out}
+			if jsonOut {
+				p.out = cmd.ErrOrStderr()
+			}
+			if interactive {
+				if err := p.fillSpec(cmd, &spec); err != nil {
+					return err
+				}
+			} else if strings.TrimSpace(content) == "" {
+				return fmt.Errorf("--content is required (or use --interactive)")
+			}
+
+			behavior, err := learning.NewManualBehavior(spec, now)
+			if err != nil {
+				return err
+			}
+			scope := models.ClassifyScope(behavior)
+			if scopeVal != "" {
+				scope = constants.Scope(scopeVal)
+			}
+
+			if interactive {
+				fmt.Fprintln(p.out)
+				printNewBehavior(p.out, behavior, scope)
+				if ok, err := p.confirm("Create this behavior?"); err != nil || !ok {
+					fmt.Fprintln(p.out, "Cancelled.")
+					return err
+				}
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+			ctx := context.Background()
+
+			existing, err := graphStore.GetNode(ctx, behavior.ID)
+			if err != nil {
+				return fmt.Errorf("failed to check for an existing behavior: %w", err)
+			}
+			if existing != nil {
+				return fmt.Errorf("behavior already exists: %s (%s)", existing.ID, models.NodeToBehavior(*existing).Name)
+			}
+
+			if _, err := graphStore.AddNodeToScope(ctx, models.BehaviorToNode(behavior), scope); err != nil {
+				return fmt.Errorf("failed to add behavior: %w", err)
+			}
+			derived := 0
+			all, err := edges.LoadBehaviorsFromStore(ctx, graphStore)
+			if err == nil {
+				var sub *edges.SubsetResult
+				if sub, err = edges.DeriveEdgesForSubsetWithTuning(ctx, graphStore, []string{behavior.ID}, all, scopeSimilarityTuning(root, scope)); err == nil {
+					derived = sub.EdgesCreated
+				}
+			}
+			if err != nil {
+				slog.Warn("edge derivation failed", "error", err)
+			}
+			if err := graphStore.Sync(ctx); err != nil {
+				return fmt.Errorf("failed to sync changes: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status":        "created",
+					"behavior":      behavior,
+					"scope":         scope,
+					"derived_edges": derived,
+				})
+			}
+			if !interactive {
+				printNewBehavior(out, behavior, scope)
+			}
+			fmt.Fprintf(out, "\nCreated %s (%d edges derived).\n", behavior.ID, derived)
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolP("interactive", "i", false, "Prompt for each field not given as a flag, showing a template for the kind")
+	cmd.Flags().String("kind", string(models.BehaviorKindDirective), "Behavior kind: directive, constraint, preference, procedure, or workflow")
+	cmd.Flags().String("name", "", "Behavior name (default derived from the content)")
+	cmd.Flags().String("content", "", "Behavior content (required unless --interactive)")
+	cmd.Flags().String("summary", "", "One-line summary for tiered injection")
+	cmd.Flags().StringArray("when", nil, "Activation condition as key=value (repeatable, e.g. language=go)")
+	cmd.Flags().StringSlice("tags", nil, "Additional tags, merged with extracted tags (max 5)")
+	cmd.Flags().Int("priority", 0, "Priority for conflict resolution")
+	cmd.Flags().String("scope", "", "Store in local (project) or global (user) scope (default classified from the conditions)")
+	cmd.Flags().String("expires", "", "Stop applying the behavior at a date (2026-01-31), time (RFC3339), or after a TTL (30d)")
+
+	return cmd
+}
+
+// prompter reads answers to interactive prompts.
+type prompter struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask prints question and returns the trimmed answer, or def if the answer
+// is empty.
+func (p *prompter) ask(question, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(p.out, "%s [%s]: ", question, def)
+	} else {
+		fmt.Fprintf(p.out, "%s: ", question)
+	}
+	line, err := p.in.ReadString('\n')
+	if err != nil && err != io.EOF {
+		return "", err
+	}
+	if answer := strings.TrimSpace(line); answer != "" {
+		return answer, nil
+	}
+	if err == io.EOF && def == "" {
+		return "", io.ErrUnexpectedEOF
+	}
+	return def, nil
+}
+
+// confirm asks a yes/no question that defaults to yes.
+func (p *prompter) confirm(question string) (bool, error) {
+	answer, err := p.ask(question+" [Y/n]", "")
+	if err == io.ErrUnexpectedEOF {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	answer = strings.ToLower(answer)
+	return answer == "" || answer == "y" || answer == "yes", nil
+}
+
+// fillSpec prompts for every field of spec whose flag was not given.
+func (p *prompter) fillSpec(cmd *cobra.Command, spec *learning.ManualSpec) error {
+	flags := cmd.Flags()
+	kinds := make([]string, len(learning.ManualKinds))
+	for i, k := range learning.ManualKinds {
+		kinds[i] = string(k)
+	}
+	if !flags.Changed("kind") {
+		answer, err := p.ask("Kind ("+strings.Join(kinds, ", ")+")", string(models.BehaviorKindDirective))
+		if err != nil {
+			return err
+		}
+		spec.Kind = models.BehaviorKind(answer)
+	}
+	tmpl, ok := behaviorTemplates[spec.Kind]
+	if !ok {
+		return fmt.Errorf("invalid kind %q: use one of %s", spec.Kind, strings.Join(kinds, ", "))
+	}
+
+	if !flags.Changed("content") {
+		fmt.Fprintf(p.out, "\nTemplate: %s\nExample:  %s\n", tmpl.shape, tmpl.example)
+		answer, err := p.ask("Content", "")
+		if err != nil || answer == "" {
+			return fmt.Errorf("content is required")
+		}
+		spec.Content = answer
+	}
+	if !flags.Changed("summary") {
+		answer, err := p.ask("Summary (optional)", "")
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		spec.Summary = answer
+	}
+	if !flags.Changed("when") {
+		fmt.Fprintln(p.out, "Conditions as key=value, such as language=go or file_path=**/*_test.go; blank to finish.")
+		var pairs []string
+		for {
+			answer, err := p.ask("  when", "")
+			if err == io.ErrUnexpectedEOF || answer == "" {
+				break
+			}
+			if err != nil {
+				return err
+			}
+			pairs = append(pairs, answer)
+		}
+		when, err := parseKeyValues("condition", pairs)
+		if err != nil {
+			return err
+		}
+		spec.When = when
+	}
+	if !flags.Changed("tags") {
+		answer, err := p.ask("Tags, comma-separated (optional)", "")
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return err
+		}
+		spec.Tags = nil
+		for _, tag := range strings.Split(answer, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				spec.Tags = append(spec.Tags, tag)
+			}
+		}
+	}
+	return nil
+}
+
+// printNewBehavior writes a preview of a behavior about to be created.
+func printNewBehavior(w io.Writer, b *models.Behavior, scope constants.Scope) {
+	fmt.Fprintf(w, "  ID:      %s\n", b.ID)
+	fmt.Fprintf(w, "  Name:    %s\n", b.Name)
+	fmt.Fprintf(w, "  Kind:    %s\n", b.Kind)
+	fmt.Fprintf(w, "  Scope:   %s\n", scope)
+	fmt.Fprintf(w, "  Content: %s\n", b.Content.Canonical)
+	if b.Content.Summary != "" {
+		fmt.Fprintf(w, "  Summary: %s\n", b.Content.Summary)
+	}
+	if len(b.When) > 0 {
+		conditions := make([]string, 0, len(b.When))
+		for key, value := range b.When {
+			conditions = append(conditions, fmt.Sprintf("%s=%v", key, value))
+		}
+		sort.Strings(conditions)
+		fmt.Fprintf(w, "  When:    %s\n", strings.Join(conditions, ", "))
+	}
+	if len(b.Content.Tags) > 0 {
+		fmt.Fprintf(w, "  Tags:    %s\n", strings.Join(b.Content.Tags, ", "))
+	}
+	if b.ExpiresAt != nil {
+		fmt.Fprintf(w, "  Expires: %s\n", formatExpiry(*b.ExpiresAt, time.Now()))
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestNewBehaviorCmd(t *testing.T) {
+	tmpDir, learnedID := setupQueryTest(t)
+
+	run := func(stdin string, args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newNewCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetIn(strings.NewReader(stdin))
+		rootCmd.SetArgs(append(append([]string{"new", "behavior"}, args...), "--root", tmpDir))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+	load := func(id string) models.Behavior {
+		t.Helper()
+		s, err := store.NewMultiGraphStore(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer s.Close()
+		node, err := s.GetNode(context.Background(), id)
+		if err != nil || node == nil {
+			t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+		}
+		return models.NodeToBehavior(*node)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"--content", "x", "--kind", "episodic"},
+		{"--content", "x", "--when", "language"},
+		{"--content", "x", "--scope", "team"},
+	} {
+		if _, err := run("", args...); err == nil {
+			t.Errorf("%v succeeded, want error", args)
+		}
+	}
+
+	out, err := run("", "--json", "--kind", "preference",
+		"--content", "Prefer slog structured logging over fmt.Println",
+		"--when", "language=go", "--tags", "observability")
+	if err != nil {
+		t.Fatalf("new behavior failed: %v", err)
+	}
+	var result struct {
+		Behavior     models.Behavior `json:"behavior"`
+		Scope        string          `json:"scope"`
+		DerivedEdges int             `json:"derived_edges"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	b := load(result.Behavior.ID)
+	if b.Kind != models.BehaviorKindPreference || b.Provenance.SourceType != models.SourceTypeManual ||
+		b.When["language"] != "go" || !strings.HasPrefix(b.Name, "manual/") {
+		t.Errorf("created behavior = %+v", b)
+	}
+	if !containsTag(b.Content.Tags, "observability") {
+		t.Errorf("tags = %v, want observability included", b.Content.Tags)
+	}
+	if result.Scope != "global" {
+		t.Errorf("scope = %s, want global for a language condition", result.Scope)
+	}
+	if result.DerivedEdges == 0 {
+		t.Errorf("no edges derived to the similar learned behavior %s", learnedID)
+	}
+
+	// The same behavior cannot be authored twice.
+	if _, err := run("", "--kind", "preference", "--content", "Prefer slog structured logging over fmt.Println"); err == nil {
+		t.Error("duplicate behavior succeeded, want error")
+	}
+
+	// Interactive: prompts for content, summary, conditions, and tags, then
+	// confirms with the default answer.
+	stdin := "Never commit generated mocks\n\nfile_path=**/mocks/*.go\n\ntesting\n\n"
+	out, err = run(stdin, "--kind", "constraint", "--interactive", "--name", "no-mocks")
+	if err != nil {
+		t.Fatalf("interactive new behavior failed: %v\n%s", err, out)
+	}
+	if !strings.Contains(out, "Template: Never") || !strings.Contains(out, "Created behavior-") {
+		t.Errorf("unexpected interactive output:\n%s", out)
+	}
+	id := out[strings.Index(out, "Created ")+len("Created "):]
+	id = id[:strings.Index(id, " ")]
+	b = load(id)
+	if b.Name != "no-mocks" || b.Kind != models.BehaviorKindConstraint || b.When["file_path"] != "**/mocks/*.go" {
+		t.Errorf("interactive behavior = %+v", b)
+	}
+
+	// Declining the confirmation creates nothing.
+	out, err = run("Never push on Fridays\n\n\n\nn\n", "--kind", "constraint", "--interactive")
+	if err != nil || !strings.Contains(out, "Cancelled.") {
+		t.Errorf("declined creation = %v\n%s", err, out)
+	}
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -1,14 +1,22 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 
+	"github.com/nvandessel/floop/internal/backup"
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/project"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 )
@@ -22,20 +30,29 @@ func newPackCmd() *cobra.Command {
 Examples:
   floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0
   floop pack install my-pack.fpack
+  floop pack search go
   floop pack list
   floop pack info my-org/my-pack
-  floop pack remove my-org/my-pack`,
+  floop pack remove my-org/my-pack
+  floop pack cache list`,
 	}
 
 	cmd.AddCommand(
 		newPackCreateCmd(),
 		newPackInstallCmd(),
+		newPackSearchCmd(),
+		newPackPublishCmd(),
 		newPackListCmd(),
 		newPackInfoCmd(),
 		newPackUpdateCmd(),
+		newPackRollbackCmd(),
+		newPackPinCmd(),
+		newPackUnpinCmd(),
 		newPackRemoveCmd(),
 		newPackAddCmd(),
 		newPackRemoveBehaviorCmd(),
+		newPackDiffCmd(),
+		newPackCacheCmd(),
 	)
 
 	return cmd
@@ -50,9 +67,35 @@ func newPackCreateCmd() *cobra.Command {
 Examples:
   floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0
   floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0 --filter-tags go,testing
-  floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0 --filter-scope global`,
+  floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0 --filter-scope global
+  floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0 --anonymize --anonymize-domains acme.io
+  floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0 --filter-tags go --dry-run
+  floop pack create my-pack.fpack --id my-org/my-pack --version 1.0.0 --filter-tags go --interactive
+
+--dry-run lists exactly which behaviors and edges the filters select, and
+for every behavior or edge left out, the reason: the filter it failed, or
+for an edge, the endpoint that is not in the pack. Nothing is written.
+
+--interactive lists the behaviors that pass the filters and asks which to
+keep, as numbers and ranges (1-3,7) or exclusions (!2,!5), before
+confirming and writing the pack. Combined with --dry-run, it previews the
+curated selection instead.
+
+--anonymize rewrites internal hostnames, usernames, and repo names in
+behavior content to placeholders such as host-1.example, user-1, and repo-1.
+Hostnames on internal suffixes (.internal, .local, .corp, ...) and usernames
+in home directory paths are detected automatically; the current project ID
+is always treated as a repo name. The mapping is stored locally in
+~/.floop/anonymize/<pack-id>.json and reused for later versions, so the
+placeholders stay stable and can be reversed.
+
+--sign-key embeds an ed25519 signature in the pack header. Installs check
+it against packs.trusted_keys in ~/.floop/config.yaml. Generate a key with:
+  openssl genpkey -algorithm ed25519 -out ~/.floop/keys/packs.pem
+  openssl pkey -in ~/.floop/keys/packs.pem -pubout`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			outputPath := args[0]
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
@@ -66,6 +109,13 @@ Examples:
 			filterScope, _ := cmd.Flags().GetString("filter-scope")
 			filterKinds, _ := cmd.Flags().GetString("filter-kinds")
 			fromPack, _ := cmd.Flags().GetString("from-pack")
+			anonymize, _ := cmd.Flags().GetBool("anonymize")
+			anonDomains, _ := cmd.Flags().GetStringSlice("anonymize-domains")
+			anonUsers, _ := cmd.Flags().GetStringSlice("anonymize-users")
+			anonRepos, _ := cmd.Flags().GetStringSlice("anonymize-repos")
+			signKey, _ := cmd.Flags().GetString("sign-key")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			interactive, _ := cmd.Flags().GetBool("interactive")
 
 			manifest := pack.PackManifest{
 				ID:          pack.PackID(id),
@@ -96,28 +146,116 @@ Examples:
 			}
 			defer graphStore.Close()
 
-			result, err := pack.Create(ctx, graphStore, filter, manifest, outputPath, pack.CreateOptions{
-				FloopVersion: version,
-			})
+			if dryRun || interactive {
+				plan, err := pack.PlanCreate(ctx, graphStore, filter)
+				if err != nil {
+					return fmt.Errorf("pack create failed: %w", err)
+				}
+				if interactive {
+					// Prompts go to stderr when stdout carries JSON
+					p := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: out}
+					if jsonOut {
+						p.out = cmd.ErrOrStderr()
+					}
+					if filter.IDs, err = selectPackBehaviors(p, plan); err != nil {
+						return err
+					}
+					if plan, err = pack.PlanCreate(ctx, graphStore, filter); err != nil {
+						return fmt.Errorf("pack create failed: %w", err)
+					}
+					if !dryRun {
+						fmt.Fprintln(p.out)
+						ok, err := p.confirm(fmt.Sprintf("Write %d behaviors and %d edges to %s?", len(plan.Included), len(plan.Edges), outputPath))
+						if err != nil || !ok {
+							fmt.Fprintln(p.out, "Cancelled.")
+							return err
+						}
+					}
+				}
+				if dryRun {
+					if jsonOut {
+						return json.NewEncoder(out).Encode(map[string]interface{}{
+							"dry_run": true,
+							"pack_id": id,
+							"version": ver,
+							"plan":    plan,
+						})
+					}
+					printCreatePlan(out, plan)
+					return nil
+				}
+			}
+
+			createOpts := pack.CreateOptions{FloopVersion: version}
+			if signKey != "" {
+				if createOpts.SigningKey, err = pack.LoadSigningKey(signKey); err != nil {
+					return err
+				}
+			}
+			var mapping *pack.AnonymizeMapping
+			var mappingPath string
+			if anonymize {
+				if err := pack.ValidatePackID(id); err != nil {
+					return err
+				}
+				mappingPath, err = pack.DefaultMappingPath(id)
+				if err != nil {
+					return err
+				}
+				mapping, err = pack.LoadMapping(mappingPath, id)
+				if err != nil {
+					return err
+				}
+				if projectID, err := project.ResolveProjectID(root); err == nil && projectID != "" {
+					anonRepos = append(anonRepos, projectID)
+				}
+				createOpts.Anonymizer = pack.NewAnonymizer(pack.AnonymizeRules{
+					Domains:   anonDomains,
+					Usernames: anonUsers,
+					Repos:     anonRepos,
+				}, mapping)
+			}
+
+			result, err := pack.Create(ctx, graphStore, filter, manifest, outputPath, createOpts)
 			if err != nil {
 				return fmt.Errorf("pack create failed: %w", err)
 			}
 
+			if mapping != nil {
+				if err := mapping.Save(mappingPath); err != nil {
+					return err
+				}
+				result.Anonymize.MappingPath = mappingPath
+			}
+
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				resp := map[string]interface{}{
 					"path":           result.Path,
 					"behavior_count": result.BehaviorCount,
 					"edge_count":     result.EdgeCount,
 					"pack_id":        id,
 					"version":        ver,
 					"message":        fmt.Sprintf("Pack created: %d behaviors, %d edges", result.BehaviorCount, result.EdgeCount),
-				})
+				}
+				if result.Signer != "" {
+					resp["signer"] = result.Signer
+				}
+				if result.Anonymize != nil {
+					resp["anonymize"] = result.Anonymize
+				}
+				return json.NewEncoder(out).Encode(resp)
 			}
 
-			fmt.Printf("Pack created: %d behaviors, %d edges\n", result.BehaviorCount, result.EdgeCount)
-			fmt.Printf("  ID: %s\n", id)
-			fmt.Printf("  Version: %s\n", ver)
-			fmt.Printf("  Path: %s\n", result.Path)
+			fmt.Fprintf(out, "Pack created: %d behaviors, %d edges\n", result.BehaviorCount, result.EdgeCount)
+			fmt.Fprintf(out, "  ID: %s\n", id)
+			fmt.Fprintf(out, "  Version: %s\n", ver)
+			fmt.Fprintf(out, "  Path: %s\n", result.Path)
+			if result.Signer != "" {
+				fmt.Fprintf(out, "  Signed: %s\n", result.Signer)
+			}
+			if result.Anonymize != nil {
+				printAnonymizeReport(out, result.Anonymize)
+			}
 			return nil
 		},
 	}
@@ -132,36 +270,216 @@ Examples:
 	cmd.Flags().String("filter-scope", "", "Filter: only include behaviors from this scope (global/local)")
 	cmd.Flags().String("filter-kinds", "", "Filter: only include behaviors of these kinds (comma-separated)")
 	cmd.Flags().String("from-pack", "", "Filter: only include behaviors belonging to this pack (by provenance)")
+	cmd.Flags().Bool("anonymize", false, "Rewrite internal hostnames, usernames, and repo names in behavior content")
+	cmd.Flags().StringSlice("anonymize-domains", nil, "Internal domains whose hosts are rewritten (with --anonymize)")
+	cmd.Flags().StringSlice("anonymize-users", nil, "Usernames to rewrite (with --anonymize)")
+	cmd.Flags().StringSlice("anonymize-repos", nil, "Repo names to rewrite, besides the project ID (with --anonymize)")
+	cmd.Flags().String("sign-key", "", "Sign the pack with an ed25519 private key file (PEM or base64)")
+	cmd.Flags().Bool("dry-run", false, "List the behaviors and edges that would be included, and why others are not, without writing")
+	cmd.Flags().BoolP("interactive", "i", false, "Choose which of the matching behaviors to include before writing")
 	_ = cmd.MarkFlagRequired("id")
 	_ = cmd.MarkFlagRequired("version")
 
 	return cmd
 }
 
+// printCreatePlan prints what 'pack create --dry-run' would include.
+func printCreatePlan(out io.Writer, plan *pack.CreatePlan) {
+	fmt.Fprintf(out, "Would include %d behaviors, %d edges:\n", len(plan.Included), len(plan.Edges))
+	for _, b := range plan.Included {
+		fmt.Fprintf(out, "  %s  [%s] %s", b.ID, b.Kind, b.Name)
+		if len(b.Tags) > 0 {
+			fmt.Fprintf(out, " (%s)", strings.Join(b.Tags, ", "))
+		}
+		fmt.Fprintln(out)
+	}
+	for _, e := range plan.Edges {
+		fmt.Fprintf(out, "  %s -> %s (%s)\n", e.Source, e.Target, e.Kind)
+	}
+	if len(plan.Excluded) > 0 {
+		fmt.Fprintf(out, "\nExcluded %d behaviors:\n", len(plan.Excluded))
+		for _, b := range plan.Excluded {
+			fmt.Fprintf(out, "  %s  %s: %s\n", b.ID, b.Name, b.Reason)
+		}
+	}
+	if len(plan.ExcludedEdges) > 0 {
+		fmt.Fprintf(out, "\nExcluded %d edges:\n", len(plan.ExcludedEdges))
+		for _, e := range plan.ExcludedEdges {
+			fmt.Fprintf(out, "  %s -> %s (%s): %s\n", e.Source, e.Target, e.Kind, e.Reason)
+		}
+	}
+}
+
+// selectPackBehaviors lists the behaviors in plan and returns the IDs of
+// the ones the author chooses to keep.
+func selectPackBehaviors(p *prompter, plan *pack.CreatePlan) ([]string, error) {
+	if len(plan.Included) == 0 {
+		return nil, fmt.Errorf("no behaviors match the filters")
+	}
+	fmt.Fprintf(p.out, "%d behaviors match the filters:\n", len(plan.Included))
+	for i, b := range plan.Included {
+		fmt.Fprintf(p.out, "  %3d. %s  [%s] %s\n", i+1, b.ID, b.Kind, b.Name)
+	}
+	for {
+		answer, err := p.ask("Include which? Numbers or ranges (1-3,7), !N to drop, empty for all", "")
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+		picked, selErr := parseSelection(answer, len(plan.Included))
+		if selErr == nil && len(picked) == 0 {
+			selErr = fmt.Errorf("nothing selected")
+		}
+		if selErr != nil {
+			if err == io.ErrUnexpectedEOF {
+				return nil, selErr
+			}
+			fmt.Fprintf(p.out, "  %v\n", selErr)
+			continue
+		}
+		ids := make([]string, len(picked))
+		for i, n := range picked {
+			ids[i] = plan.Included[n-1].ID
+		}
+		return ids, nil
+	}
+}
+
+// parseSelection parses a selection of items numbered 1..n, such as
+// "1-3,7" or "!2 !5", and returns the chosen numbers in order. Items are
+// separated by commas or spaces. An empty selection, or one made only of
+// exclusions, starts from every item.
+func parseSelection(input string, n int) ([]int, error) {
+	fields := strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' })
+	chosen := make([]bool, n+1)
+	includesAny := false
+	for _, f := range fields {
+		if !strings.HasPrefix(f, "!") {
+			includesAny = true
+		}
+	}
+	if !includesAny {
+		for i := 1; i <= n; i++ {
+			chosen[i] = true
+		}
+	}
+	for _, f := range fields {
+		exclude := strings.HasPrefix(f, "!")
+		lo, hi, err := parseRange(strings.TrimPrefix(f, "!"), n)
+		if err != nil {
+			return nil, err
+		}
+		for i := lo; i <= hi; i++ {
+			chosen[i] = !exclude
+		}
+	}
+	var picked []int
+	for i := 1; i <= n; i++ {
+		if chosen[i] {
+			picked = append(picked, i)
+		}
+	}
+	return picked, nil
+}
+
+// parseRange parses "4" or "2-5" as a range within 1..n.
+func parseRange(s string, n int) (int, int, error) {
+	loStr, hiStr, isRange := strings.Cut(s, "-")
+	lo, err := strconv.Atoi(loStr)
+	hi := lo
+	if err == nil && isRange {
+		hi, err = strconv.Atoi(hiStr)
+	}
+	if err != nil || lo < 1 || hi > n || lo > hi {
+		return 0, 0, fmt.Errorf("invalid selection %q: use numbers from 1 to %d", s, n)
+	}
+	return lo, hi, nil
+}
+
+// printAnonymizeReport prints what --anonymize rewrote.
+func printAnonymizeReport(out io.Writer, report *pack.AnonymizeReport) {
+	if len(report.Rewrites) == 0 {
+		fmt.Fprintln(out, "\nAnonymize: nothing to rewrite")
+		return
+	}
+	fmt.Fprintf(out, "\nAnonymized %d behaviors:\n", report.BehaviorsChanged)
+	for _, r := range report.Rewrites {
+		fmt.Fprintf(out, "  %-4s  %s -> %s (%d in %d behaviors)\n", r.Kind, r.Original, r.Replacement, r.Count, len(r.BehaviorIDs))
+	}
+	fmt.Fprintf(out, "  Mapping: %s\n", report.MappingPath)
+}
+
 func newPackInstallCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "install <source>",
-		Short: "Install a skill pack from a file, URL, or GitHub repo",
+		Use:   "install [source]",
+		Short: "Install a skill pack from a file, URL, git host, or registry",
 		Long: `Install behaviors from a skill pack into the store.
 
-Supports local files, HTTP URLs, and GitHub shorthand sources.
+Supports local files, HTTP URLs, GitHub (gh:) and GitLab (gl:) releases,
+pack files in any git repository (git+), and packs from the registries
+configured under packs.registries. Registry downloads are verified against
+the index's SHA-256 checksum, and against its ed25519 signature when the
+registry has a public_key.
+
+GitLab sources use gitlab.com, or the instance in GITLAB_HOST, with
+GITLAB_TOKEN for private projects. Git sources are cloned with the git
+command, so its credentials apply; the fragment names the tag and the
+pack file within the repository, which defaults to the .fpack file at its
+root.
+
 Follows the seeder pattern: forgotten behaviors are not re-added,
 existing behaviors are version-gated for updates, and provenance
 is stamped on each installed behavior.
 
+Each install records the exact version and checksum in .floop/packs.lock.
+With --frozen, as in CI, the lockfile is not changed: without a source,
+every locked pack is installed at its locked version; with one, the pack
+must match its locked version and checksum. Any drift fails the install
+before anything is written.
+
+A pack behavior collides with a local one when it has the same ID but
+different content, or near-identical content under another ID. The
+packs.collision_policy setting, or --on-collision, decides what happens:
+prefer-pack (the default) installs the pack's version, keep-local skips
+it, rename installs it alongside under a new ID, and prompt asks each time.
+
 Examples:
   floop pack install my-pack.fpack
   floop pack install https://example.com/pack.fpack
   floop pack install gh:owner/repo
   floop pack install gh:owner/repo@v1.0.0
-  floop pack install gh:owner/repo --all-assets`,
-		Args: cobra.ExactArgs(1),
+  floop pack install gh:owner/repo --all-assets
+  floop pack install gl:group/project@v1.0.0
+  floop pack install "git+https://git.example.com/packs.git#tag=v1.0.0;path=go.fpack"
+  floop pack install registry:my-org/go-style
+  floop pack install registry:my-org/go-style@1.2.0
+  floop pack install --frozen
+  floop pack install gh:owner/repo --on-collision keep-local`,
+		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
-			source := args[0]
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			deriveEdges, _ := cmd.Flags().GetBool("derive-edges")
 			allAssets, _ := cmd.Flags().GetBool("all-assets")
+			frozen, _ := cmd.Flags().GetBool("frozen")
+			policy, resolver, err := collisionOptions(cmd, out, jsonOut)
+			if err != nil {
+				return err
+			}
+
+			if len(args) == 0 && !frozen {
+				return fmt.Errorf("provide a source, or use --frozen to install from %s", pack.LockFileName)
+			}
+			var lock *pack.Lockfile
+			if frozen {
+				lockPath := pack.LockPath(root)
+				if _, err := os.Stat(lockPath); err != nil {
+					return fmt.Errorf("--frozen needs %s: %w", lockPath, err)
+				}
+				if lock, err = pack.ReadLock(lockPath); err != nil {
+					return err
+				}
+			}
 
 			cfg, err := config.Load()
 			if err != nil {
@@ -175,17 +493,32 @@ Examples:
 			}
 			defer graphStore.Close()
 
-			results, err := pack.InstallFromSource(ctx, graphStore, source, cfg, pack.InstallFromSourceOptions{
-				DeriveEdges: deriveEdges,
-				AllAssets:   allAssets,
-			})
+			var results []*pack.InstallResult
+			if len(args) == 0 {
+				results, err = pack.InstallLocked(ctx, graphStore, lock, root, cfg, pack.InstallOptions{
+					DeriveEdges:      deriveEdges,
+					CollisionPolicy:  policy,
+					ResolveCollision: resolver,
+				})
+			} else {
+				results, err = pack.InstallFromSource(ctx, graphStore, args[0], cfg, pack.InstallFromSourceOptions{
+					DeriveEdges:      deriveEdges,
+					AllAssets:        allAssets,
+					Lock:             lock,
+					CollisionPolicy:  policy,
+					ResolveCollision: resolver,
+				})
+			}
 			if err != nil {
 				return fmt.Errorf("pack install failed: %w", err)
 			}
 
 			// Save config with updated pack list
 			if saveErr := cfg.Save(); saveErr != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to save config: %v\n", saveErr)
+				slog.Warn("failed to save config", "error", saveErr)
+			}
+			if !frozen {
+				lockPacks(root, results)
 			}
 
 			if jsonOut {
@@ -200,23 +533,27 @@ Examples:
 						"edges_added":   result.EdgesAdded,
 						"edges_skipped": result.EdgesSkipped,
 						"derived_edges": result.DerivedEdges,
+						"collisions":    result.Collisions,
+						"signature":     result.Signature,
 						"message":       fmt.Sprintf("Installed %s v%s: %d added, %d updated, %d skipped", result.PackID, result.Version, len(result.Added), len(result.Updated), len(result.Skipped)),
 					})
 				}
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"results": jsonResults,
 				})
 			}
 
 			for _, result := range results {
-				fmt.Printf("Installed %s v%s\n", result.PackID, result.Version)
-				fmt.Printf("  Added: %d behaviors\n", len(result.Added))
-				fmt.Printf("  Updated: %d behaviors\n", len(result.Updated))
-				fmt.Printf("  Skipped: %d behaviors\n", len(result.Skipped))
-				fmt.Printf("  Edges: %d added, %d skipped\n", result.EdgesAdded, result.EdgesSkipped)
+				fmt.Fprintf(out, "Installed %s v%s\n", result.PackID, result.Version)
+				fmt.Fprintf(out, "  Added: %d behaviors\n", len(result.Added))
+				fmt.Fprintf(out, "  Updated: %d behaviors\n", len(result.Updated))
+				fmt.Fprintf(out, "  Skipped: %d behaviors\n", len(result.Skipped))
+				fmt.Fprintf(out, "  Edges: %d added, %d skipped\n", result.EdgesAdded, result.EdgesSkipped)
 				if result.DerivedEdges > 0 {
-					fmt.Printf("  Derived edges: %d\n", result.DerivedEdges)
+					fmt.Fprintf(out, "  Derived edges: %d\n", result.DerivedEdges)
 				}
+				printCollisions(out, result.Collisions)
+				printSignatureStatus(out, result.Signature)
 			}
 			return nil
 		},
@@ -224,6 +561,184 @@ Examples:
 
 	cmd.Flags().Bool("derive-edges", false, "Automatically derive edges between pack behaviors and existing behaviors")
 	cmd.Flags().Bool("all-assets", false, "Install all .fpack assets from a multi-asset release")
+	cmd.Flags().Bool("frozen", false, "Install exactly the versions in .floop/packs.lock and fail on drift")
+	addCollisionFlag(cmd)
+
+	return cmd
+}
+
+func newPackSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search [query]",
+		Short: "Search configured registries for skill packs",
+		Long: `Search the pack registries configured under packs.registries in
+~/.floop/config.yaml. Packs match when every word of the query appears in
+their ID, description, or tags; with no query, every pack is listed.
+
+Install a result with 'floop pack install registry:<pack-id>'.
+
+Examples:
+  floop pack search go
+  floop pack search "go testing"
+  floop pack search --json`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			query := ""
+			if len(args) == 1 {
+				query = args[0]
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			if len(cfg.Packs.Registries) == 0 {
+				return fmt.Errorf("no pack registries configured; add one under packs.registries in ~/.floop/config.yaml")
+			}
+
+			hits, err := pack.NewRegistryClient().Search(context.Background(), cfg.Packs.Registries, query)
+			if err != nil {
+				return fmt.Errorf("pack search failed: %w", err)
+			}
+
+			installed := make(map[string]string)
+			for _, p := range cfg.Packs.Installed {
+				installed[p.ID] = p.Version
+			}
+
+			if jsonOut {
+				results := make([]map[string]interface{}, 0, len(hits))
+				for _, h := range hits {
+					latest := ""
+					if v := h.Pack.LatestVersion(); v != nil {
+						latest = v.Version
+					}
+					results = append(results, map[string]interface{}{
+						"id":                h.Pack.ID,
+						"registry":          h.Registry,
+						"description":       h.Pack.Description,
+						"tags":              h.Pack.Tags,
+						"latest":            latest,
+						"installed_version": installed[h.Pack.ID],
+					})
+				}
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"results": results,
+					"count":   len(results),
+				})
+			}
+
+			if len(hits) == 0 {
+				fmt.Fprintf(out, "No packs match %q.\n", query)
+				return nil
+			}
+
+			fmt.Fprintf(out, "Packs (%d):\n", len(hits))
+			for _, h := range hits {
+				line := "  " + h.Pack.ID
+				if v := h.Pack.LatestVersion(); v != nil {
+					line += " v" + strings.TrimPrefix(v.Version, "v")
+				}
+				line += " [" + h.Registry + "]"
+				if version, ok := installed[h.Pack.ID]; ok {
+					line += fmt.Sprintf(" (installed v%s)", strings.TrimPrefix(version, "v"))
+				}
+				fmt.Fprintln(out, line)
+				if h.Pack.Description != "" {
+					fmt.Fprintf(out, "    %s\n", h.Pack.Description)
+				}
+			}
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newPackPublishCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "publish <file.fpack>",
+		Short: "Lint a skill pack and upload it to a registry",
+		Long: `Publish a pack file to a registry configured under packs.registries.
+
+Before uploading, the pack is linted: the manifest needs a valid ID, a
+version, and a description; forgotten behaviors, unsanitized names or
+content, and edges with unknown kinds, bad weights, or endpoints outside
+the pack all block publishing. The upload carries the file's SHA-256
+checksum and, with --sign-key, an ed25519 signature, and authenticates
+with the registry's token (token: ${ENV_VAR} is supported).
+
+Examples:
+  floop pack publish go-style.fpack --registry main
+  floop pack publish go-style.fpack --sign-key ~/.floop/keys/packs.key
+  floop pack publish go-style.fpack --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			registryName, _ := cmd.Flags().GetString("registry")
+			signKey, _ := cmd.Flags().GetString("sign-key")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			reg, err := pack.FindRegistry(cfg.Packs.Registries, registryName)
+			if err != nil {
+				return err
+			}
+
+			opts := pack.PublishOptions{DryRun: dryRun}
+			if signKey != "" {
+				if opts.SigningKey, err = pack.LoadSigningKey(signKey); err != nil {
+					return err
+				}
+			}
+
+			result, err := pack.Publish(context.Background(), args[0], reg, opts)
+			var lintErr *pack.LintError
+			if errors.As(err, &lintErr) {
+				if jsonOut {
+					json.NewEncoder(out).Encode(map[string]interface{}{
+						"issues": lintErr.Issues,
+					})
+				} else {
+					for _, issue := range lintErr.Issues {
+						fmt.Fprintf(out, "  %s\n", issue)
+					}
+				}
+				return err
+			}
+			if err != nil {
+				return fmt.Errorf("pack publish failed: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(result)
+			}
+			verb := "Published"
+			if dryRun {
+				verb = "Would publish"
+			}
+			fmt.Fprintf(out, "%s %s v%s to %s\n", verb, result.PackID, strings.TrimPrefix(result.Version, "v"), result.Registry)
+			fmt.Fprintf(out, "  SHA-256: %s\n", result.SHA256)
+			fmt.Fprintf(out, "  Size: %d bytes\n", result.Size)
+			if result.Signed {
+				fmt.Fprintln(out, "  Signed: yes")
+			}
+			if result.URL != "" {
+				fmt.Fprintf(out, "  URL: %s\n", result.URL)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("registry", "", "Registry to publish to (default: the only configured registry)")
+	cmd.Flags().String("sign-key", "", "Sign the upload with an ed25519 private key file (PEM or base64)")
+	cmd.Flags().Bool("dry-run", false, "Lint and checksum the pack without uploading")
 
 	return cmd
 }
@@ -238,32 +753,48 @@ Examples:
   floop pack list
   floop pack list --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 
-			cfg, err := config.Load()
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				cfg = config.Default()
 			}
 
 			installed := pack.ListInstalled(cfg)
 
+			pinned := []string{}
+			if lock, err := pack.ReadLock(pack.LockPath(root)); err == nil {
+				for _, p := range lock.Packs {
+					if p.Pinned {
+						pinned = append(pinned, p.ID)
+					}
+				}
+			}
+
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"installed": installed,
 					"count":     len(installed),
+					"pinned":    pinned,
 				})
 			}
 
 			if len(installed) == 0 {
-				fmt.Println("No skill packs installed.")
+				fmt.Fprintln(out, "No skill packs installed.")
 				return nil
 			}
 
-			fmt.Printf("Installed packs (%d):\n", len(installed))
+			fmt.Fprintf(out, "Installed packs (%d):\n", len(installed))
 			for _, p := range installed {
-				fmt.Printf("  %s v%s (%d behaviors, %d edges)\n", p.ID, p.Version, p.BehaviorCount, p.EdgeCount)
+				pin := ""
+				if slices.Contains(pinned, p.ID) {
+					pin = " [pinned]"
+				}
+				fmt.Fprintf(out, "  %s v%s (%d behaviors, %d edges)%s\n", p.ID, p.Version, p.BehaviorCount, p.EdgeCount, pin)
 				if !p.InstalledAt.IsZero() {
-					fmt.Printf("    Installed: %s\n", p.InstalledAt.Format("2006-01-02 15:04:05"))
+					fmt.Fprintf(out, "    Installed: %s\n", p.InstalledAt.Format("2006-01-02 15:04:05"))
 				}
 			}
 			return nil
@@ -284,11 +815,12 @@ Examples:
   floop pack info my-org/my-pack --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			packID := args[0]
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 
-			cfg, err := config.Load()
+			cfg, err := loadConfig(cmd)
 			if err != nil {
 				cfg = config.Default()
 			}
@@ -314,40 +846,46 @@ Examples:
 			if err != nil {
 				return fmt.Errorf("querying pack behaviors: %w", err)
 			}
+			if installed == nil && len(behaviors) == 0 {
+				return newCLIError(codeNotFound, map[string]interface{}{"pack": packID}, "pack %q not found", packID)
+			}
 
 			if jsonOut {
-				out := map[string]interface{}{
+				info := map[string]interface{}{
 					"pack_id":        packID,
 					"behavior_count": len(behaviors),
 				}
 				if installed != nil {
-					out["version"] = installed.Version
-					out["installed_at"] = installed.InstalledAt
-					out["edge_count"] = installed.EdgeCount
+					info["version"] = installed.Version
+					info["installed_at"] = installed.InstalledAt
+					info["edge_count"] = installed.EdgeCount
+					info["history"] = installed.History
 				}
-				return json.NewEncoder(os.Stdout).Encode(out)
+				return json.NewEncoder(out).Encode(info)
 			}
 
-			if installed == nil && len(behaviors) == 0 {
-				fmt.Printf("Pack %q not found.\n", packID)
-				return nil
-			}
-
-			fmt.Printf("Pack: %s\n", packID)
+			fmt.Fprintf(out, "Pack: %s\n", packID)
 			if installed != nil {
-				fmt.Printf("  Version: %s\n", installed.Version)
+				fmt.Fprintf(out, "  Version: %s\n", installed.Version)
 				if !installed.InstalledAt.IsZero() {
-					fmt.Printf("  Installed: %s\n", installed.InstalledAt.Format("2006-01-02 15:04:05"))
+					fmt.Fprintf(out, "  Installed: %s\n", installed.InstalledAt.Format("2006-01-02 15:04:05"))
+				}
+				fmt.Fprintf(out, "  Config edges: %d\n", installed.EdgeCount)
+				if len(installed.History) > 0 {
+					previous := make([]string, 0, len(installed.History))
+					for i := len(installed.History) - 1; i >= 0; i-- {
+						previous = append(previous, installed.History[i].Version)
+					}
+					fmt.Fprintf(out, "  Previous versions: %s\n", strings.Join(previous, ", "))
 				}
-				fmt.Printf("  Config edges: %d\n", installed.EdgeCount)
 			}
-			fmt.Printf("  Behaviors in store: %d\n", len(behaviors))
+			fmt.Fprintf(out, "  Behaviors in store: %d\n", len(behaviors))
 			for _, b := range behaviors {
 				name := ""
 				if content, ok := b.Content["name"].(string); ok {
 					name = content
 				}
-				fmt.Printf("    - %s (%s)\n", b.ID, name)
+				fmt.Fprintf(out, "    - %s (%s)\n", b.ID, name)
 			}
 			return nil
 		},
@@ -364,11 +902,15 @@ func newPackUpdateCmd() *cobra.Command {
 all packs that have remote sources.
 
 When given a pack ID, looks up the installed pack's source and re-fetches it.
-When given a source string (file path, URL, or gh: shorthand), installs directly.
+When given a source string (file path, URL, or gh:, gl:, or git+ source), installs directly.
 When used with --all, updates every installed pack that has a recorded source.
 
-For GitHub sources, the remote release version is checked first; if the
-installed version already matches, the download is skipped.
+For GitHub, GitLab, registry, and tagged git sources, the remote version
+is checked first; if the installed version already matches, the download
+is skipped.
+
+Packs pinned with 'floop pack pin' are skipped by --all, and updating one
+by ID fails until it is unpinned.
 
 Examples:
   floop pack update my-org/my-pack
@@ -377,10 +919,15 @@ Examples:
   floop pack update --all`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			deriveEdges, _ := cmd.Flags().GetBool("derive-edges")
 			allPacks, _ := cmd.Flags().GetBool("all")
+			policy, resolver, err := collisionOptions(cmd, out, jsonOut)
+			if err != nil {
+				return err
+			}
 
 			cfg, err := config.Load()
 			if err != nil {
@@ -402,7 +949,9 @@ Examples:
 			defer graphStore.Close()
 
 			opts := pack.InstallFromSourceOptions{
-				DeriveEdges: deriveEdges,
+				DeriveEdges:      deriveEdges,
+				CollisionPolicy:  policy,
+				ResolveCollision: resolver,
 			}
 
 			// Collect (source, packID) pairs to update
@@ -413,10 +962,19 @@ Examples:
 			}
 			var targets []updateTarget
 
+			lock, err := pack.ReadLock(pack.LockPath(root))
+			if err != nil {
+				return err
+			}
+
 			if allPacks {
 				for _, p := range cfg.Packs.Installed {
 					if p.Source == "" {
-						fmt.Fprintf(os.Stderr, "skipping %s: no recorded source\n", p.ID)
+						slog.Info("skipping pack with no recorded source", "pack", p.ID)
+						continue
+					}
+					if locked := lock.Get(p.ID); locked != nil && locked.Pinned {
+						slog.Info("skipping pinned pack", "pack", p.ID, "version", locked.Version)
 						continue
 					}
 					targets = append(targets, updateTarget{
@@ -426,7 +984,7 @@ Examples:
 					})
 				}
 				if len(targets) == 0 {
-					fmt.Println("No packs with remote sources to update.")
+					fmt.Fprintln(out, "No packs with remote sources to update.")
 					return nil
 				}
 			} else {
@@ -436,6 +994,9 @@ Examples:
 				// Check if arg is an installed pack ID
 				for _, p := range cfg.Packs.Installed {
 					if p.ID == arg {
+						if locked := lock.Get(p.ID); locked != nil && locked.Pinned {
+							return fmt.Errorf("pack %s is pinned to v%s; run 'floop pack unpin %s' to update it", p.ID, locked.Version, p.ID)
+						}
 						if p.Source == "" {
 							return fmt.Errorf("pack %q has no recorded source; reinstall from a remote source or provide one directly", arg)
 						}
@@ -460,26 +1021,45 @@ Examples:
 			var allResults []*pack.InstallResult
 
 			for _, t := range targets {
-				// Version check for GitHub sources: skip if already up-to-date
+				// Version check for GitHub and registry sources: skip if already up-to-date
 				resolved, err := pack.ResolveSource(t.source)
 				if err != nil {
 					return fmt.Errorf("resolving source %q: %w", t.source, err)
 				}
 
-				if resolved.Kind == pack.SourceGitHub && t.installedVersion != "" {
-					gh := pack.NewGitHubClient()
+				remoteVersion := ""
+				switch {
+				case t.installedVersion == "":
+				case resolved.Kind == pack.SourceGitHub:
+					gh := pack.NewGitHubClient(cfg)
 					release, err := gh.ResolveRelease(ctx, resolved.Owner, resolved.Repo, resolved.Version)
 					if err != nil {
 						return fmt.Errorf("checking release for %s: %w", t.source, err)
 					}
-					remoteVersion := strings.TrimPrefix(release.TagName, "v")
+					remoteVersion = strings.TrimPrefix(release.TagName, "v")
+				case resolved.Kind == pack.SourceRegistry:
+					_, v, err := pack.NewRegistryClient().Resolve(ctx, cfg.Packs.Registries, resolved.PackID, resolved.Version)
+					if err != nil {
+						return fmt.Errorf("checking registry for %s: %w", t.source, err)
+					}
+					remoteVersion = strings.TrimPrefix(v.Version, "v")
+				case resolved.Kind == pack.SourceGitLab:
+					release, err := pack.NewGitLabClient().ResolveRelease(ctx, resolved.Owner+"/"+resolved.Repo, resolved.Version)
+					if err != nil {
+						return fmt.Errorf("checking release for %s: %w", t.source, err)
+					}
+					remoteVersion = strings.TrimPrefix(release.TagName, "v")
+				case resolved.Kind == pack.SourceGit:
+					remoteVersion = strings.TrimPrefix(resolved.Version, "v")
+				}
+				if remoteVersion != "" {
 					installedVersion := strings.TrimPrefix(t.installedVersion, "v")
 					if remoteVersion == installedVersion {
 						label := t.packID
 						if label == "" {
 							label = t.source
 						}
-						fmt.Printf("%s is already up-to-date (v%s)\n", label, remoteVersion)
+						fmt.Fprintf(out, "%s is already up-to-date (v%s)\n", label, remoteVersion)
 						continue
 					}
 				}
@@ -487,7 +1067,7 @@ Examples:
 				results, err := pack.InstallFromSource(ctx, graphStore, t.source, cfg, opts)
 				if err != nil {
 					if allPacks {
-						fmt.Fprintf(os.Stderr, "warning: failed to update %s: %v\n", t.packID, err)
+						slog.Warn("failed to update pack", "pack", t.packID, "error", err)
 						continue
 					}
 					return fmt.Errorf("pack update failed: %w", err)
@@ -496,8 +1076,9 @@ Examples:
 			}
 
 			if saveErr := cfg.Save(); saveErr != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to save config: %v\n", saveErr)
+				slog.Warn("failed to save config", "error", saveErr)
 			}
+			lockPacks(root, allResults)
 
 			if jsonOut {
 				jsonResults := make([]map[string]interface{}, 0, len(allResults))
@@ -511,23 +1092,27 @@ Examples:
 						"edges_added":   result.EdgesAdded,
 						"edges_skipped": result.EdgesSkipped,
 						"derived_edges": result.DerivedEdges,
+						"collisions":    result.Collisions,
+						"signature":     result.Signature,
 						"message":       fmt.Sprintf("Updated %s to v%s: %d added, %d updated, %d skipped", result.PackID, result.Version, len(result.Added), len(result.Updated), len(result.Skipped)),
 					})
 				}
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"results": jsonResults,
 				})
 			}
 
 			for _, result := range allResults {
-				fmt.Printf("Updated %s to v%s\n", result.PackID, result.Version)
-				fmt.Printf("  Added: %d behaviors\n", len(result.Added))
-				fmt.Printf("  Updated: %d behaviors\n", len(result.Updated))
-				fmt.Printf("  Skipped: %d behaviors\n", len(result.Skipped))
-				fmt.Printf("  Edges: %d added, %d skipped\n", result.EdgesAdded, result.EdgesSkipped)
+				fmt.Fprintf(out, "Updated %s to v%s\n", result.PackID, result.Version)
+				fmt.Fprintf(out, "  Added: %d behaviors\n", len(result.Added))
+				fmt.Fprintf(out, "  Updated: %d behaviors\n", len(result.Updated))
+				fmt.Fprintf(out, "  Skipped: %d behaviors\n", len(result.Skipped))
+				fmt.Fprintf(out, "  Edges: %d added, %d skipped\n", result.EdgesAdded, result.EdgesSkipped)
 				if result.DerivedEdges > 0 {
-					fmt.Printf("  Derived edges: %d\n", result.DerivedEdges)
+					fmt.Fprintf(out, "  Derived edges: %d\n", result.DerivedEdges)
 				}
+				printCollisions(out, result.Collisions)
+				printSignatureStatus(out, result.Signature)
 			}
 			return nil
 		},
@@ -535,10 +1120,228 @@ Examples:
 
 	cmd.Flags().Bool("derive-edges", false, "Automatically derive edges between pack behaviors and existing behaviors")
 	cmd.Flags().Bool("all", false, "Update all installed packs that have remote sources")
+	addCollisionFlag(cmd)
+
+	return cmd
+}
+
+func newPackRollbackCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "rollback <pack-id>",
+		Short: "Restore the previously installed version of a pack",
+		Long: `Undo a pack update by restoring the version it replaced.
+
+Each install or update of a new version records the version it replaced
+in config. Rollback reinstalls that version from its cached .fpack file,
+downloading it again if the cache was cleaned, which reverts the behaviors
+the update changed. Behaviors that only the newer version had are deleted,
+along with its new edges. Behaviors you have forgotten are left alone.
+
+Rolling back again goes to the version before that, as far back as the
+last 5 versions.
+
+Examples:
+  floop pack rollback my-org/my-pack
+  floop pack rollback my-org/my-pack --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			packID := args[0]
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			cfg, err := config.Load()
+			if err != nil {
+				cfg = config.Default()
+			}
+
+			ctx := context.Background()
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open store: %w", err)
+			}
+			defer graphStore.Close()
+
+			result, err := pack.Rollback(ctx, graphStore, packID, cfg)
+			if err != nil {
+				return fmt.Errorf("pack rollback failed: %w", err)
+			}
+
+			if saveErr := cfg.Save(); saveErr != nil {
+				slog.Warn("failed to save config", "error", saveErr)
+			}
+			lockPacks(root, []*pack.InstallResult{result.Installed})
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"pack_id":       result.PackID,
+					"from_version":  result.FromVersion,
+					"to_version":    result.ToVersion,
+					"reverted":      result.Reverted,
+					"restored":      result.Restored,
+					"removed":       result.Removed,
+					"edges_removed": result.EdgesRemoved,
+					"message":       fmt.Sprintf("Rolled back %s from v%s to v%s", result.PackID, result.FromVersion, result.ToVersion),
+				})
+			}
+
+			fmt.Fprintf(out, "Rolled back %s from v%s to v%s\n", result.PackID, result.FromVersion, result.ToVersion)
+			fmt.Fprintf(out, "  Reverted: %d behaviors\n", len(result.Reverted))
+			fmt.Fprintf(out, "  Restored: %d behaviors\n", len(result.Restored))
+			fmt.Fprintf(out, "  Removed: %d behaviors\n", len(result.Removed))
+			fmt.Fprintf(out, "  Edges removed: %d\n", result.EdgesRemoved)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newPackPinCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin <pack-id>[@version]",
+		Short: "Pin a pack to a version",
+		Long: `Pin an installed pack so 'floop pack update' leaves it alone.
+
+The pin is recorded in .floop/packs.lock. Without a version, the pack is
+pinned at its installed version; with one, that version is installed
+first from the pack's GitHub, GitLab, git, or registry source.
+
+Examples:
+  floop pack pin my-org/my-pack
+  floop pack pin my-org/my-pack@1.2.0`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			packID, version, _ := strings.Cut(args[0], "@")
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+			cfg, err := config.Load()
+			if err != nil {
+				cfg = config.Default()
+			}
+			var installed *config.InstalledPack
+			for i := range cfg.Packs.Installed {
+				if cfg.Packs.Installed[i].ID == packID {
+					installed = &cfg.Packs.Installed[i]
+				}
+			}
+			if installed == nil {
+				return fmt.Errorf("pack %s is not installed", packID)
+			}
+
+			// The installed version as a lock entry, for packs installed
+			// before the lockfile existed.
+			result := &pack.InstallResult{PackID: packID, Version: installed.Version, Source: installed.Source, File: installed.Path}
+			if sum, err := pack.FileSHA256(installed.Path); err == nil {
+				result.SHA256 = sum
+			}
+
+			if version != "" && strings.TrimPrefix(version, "v") != strings.TrimPrefix(installed.Version, "v") {
+				source, err := withSourceVersion(installed.Source, version)
+				if err != nil {
+					return fmt.Errorf("installing %s@%s: %w", packID, version, err)
+				}
+				graphStore, err := store.NewMultiGraphStore(root)
+				if err != nil {
+					return fmt.Errorf("failed to open store: %w", err)
+				}
+				defer graphStore.Close()
+
+				results, err := pack.InstallFromSource(context.Background(), graphStore, source, cfg, pack.InstallFromSourceOptions{})
+				if err != nil {
+					return fmt.Errorf("pack pin failed: %w", err)
+				}
+				if saveErr := cfg.Save(); saveErr != nil {
+					slog.Warn("failed to save config", "error", saveErr)
+				}
+				result = results[0]
+			}
+
+			var locked pack.LockedPack
+			err = pack.UpdateLock(root, func(lock *pack.Lockfile) {
+				if entry := lock.Get(packID); entry == nil || entry.Version != result.Version {
+					lock.Record(result, root)
+				}
+				entry := lock.Get(packID)
+				entry.Pinned = true
+				locked = *entry
+			})
+			if err != nil {
+				return fmt.Errorf("pack pin failed: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"pack_id": locked.ID,
+					"version": locked.Version,
+					"pinned":  true,
+				})
+			}
+			fmt.Fprintf(out, "Pinned %s to v%s\n", locked.ID, locked.Version)
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newPackUnpinCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "unpin <pack-id>",
+		Short: "Let 'floop pack update' update a pinned pack again",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			packID := args[0]
+
+			var wasPinned bool
+			err := pack.UpdateLock(root, func(lock *pack.Lockfile) {
+				if entry := lock.Get(packID); entry != nil {
+					wasPinned = entry.Pinned
+					entry.Pinned = false
+				}
+			})
+			if err != nil {
+				return fmt.Errorf("pack unpin failed: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"pack_id":    packID,
+					"was_pinned": wasPinned,
+				})
+			}
+			if !wasPinned {
+				fmt.Fprintf(out, "%s is not pinned.\n", packID)
+				return nil
+			}
+			fmt.Fprintf(out, "Unpinned %s\n", packID)
+			return nil
+		},
+	}
 
 	return cmd
 }
 
+// lockPacks records installed packs in the project's packs.lock.
+func lockPacks(root string, results []*pack.InstallResult) {
+	err := pack.UpdateLock(root, func(lock *pack.Lockfile) {
+		for _, result := range results {
+			lock.Record(result, root)
+		}
+	})
+	if err != nil {
+		slog.Warn("failed to update lock file", "file", pack.LockFileName, "error", err)
+	}
+}
+
 func newPackRemoveCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "remove <pack-id>",
@@ -546,14 +1349,34 @@ func newPackRemoveCmd() *cobra.Command {
 		Long: `Remove a pack by marking its behaviors as forgotten and removing
 the pack from the installed packs list.
 
+Asks for confirmation unless --yes or --json is given.
+
 Examples:
   floop pack remove my-org/my-pack
+  floop pack remove my-org/my-pack --yes
   floop pack remove my-org/my-pack --json`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			packID := args[0]
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			if err := pack.ValidatePackID(packID); err != nil {
+				return fmt.Errorf("pack remove failed: invalid pack ID: %w", err)
+			}
+
+			confirmed, err := confirmDestructive(config.OpPackRemove, yes, jsonOut, func() {
+				fmt.Fprintf(out, "Remove pack %s and forget all of its behaviors?\n", packID)
+			})
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Fprintln(out, "Cancelled.")
+				return nil
+			}
 
 			cfg, err := config.Load()
 			if err != nil {
@@ -573,23 +1396,28 @@ Examples:
 			}
 
 			if saveErr := cfg.Save(); saveErr != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to save config: %v\n", saveErr)
+				slog.Warn("failed to save config", "error", saveErr)
+			}
+			if err := pack.UpdateLock(root, func(lock *pack.Lockfile) { lock.Remove(packID) }); err != nil {
+				slog.Warn("failed to update lock file", "file", pack.LockFileName, "error", err)
 			}
 
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"pack_id":           result.PackID,
 					"behaviors_removed": result.BehaviorsRemoved,
 					"message":           fmt.Sprintf("Removed %s: %d behaviors marked as forgotten", result.PackID, result.BehaviorsRemoved),
 				})
 			}
 
-			fmt.Printf("Removed %s\n", result.PackID)
-			fmt.Printf("  Behaviors marked as forgotten: %d\n", result.BehaviorsRemoved)
+			fmt.Fprintf(out, "Removed %s\n", result.PackID)
+			fmt.Fprintf(out, "  Behaviors marked as forgotten: %d\n", result.BehaviorsRemoved)
 			return nil
 		},
 	}
 
+	addYesFlag(cmd)
+
 	return cmd
 }
 
@@ -607,6 +1435,7 @@ Examples:
   floop pack add behavior-abc123 --to my-org/my-pack --force`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			behaviorID := args[0]
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
@@ -625,14 +1454,14 @@ Examples:
 			}
 
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"behavior_id": behaviorID,
 					"pack_id":     packID,
 					"message":     fmt.Sprintf("Added %s to %s", behaviorID, packID),
 				})
 			}
 
-			fmt.Printf("Added %s to %s\n", behaviorID, packID)
+			fmt.Fprintf(out, "Added %s to %s\n", behaviorID, packID)
 			return nil
 		},
 	}
@@ -658,6 +1487,7 @@ Examples:
   floop pack remove-behavior behavior-abc123 --forget`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			behaviorID := args[0]
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
@@ -685,14 +1515,14 @@ Examples:
 			}
 
 			if jsonOut {
-				return json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				return json.NewEncoder(out).Encode(map[string]interface{}{
 					"behavior_id": behaviorID,
 					"action":      string(mode),
 					"message":     fmt.Sprintf("Behavior %s %s", behaviorID, action),
 				})
 			}
 
-			fmt.Printf("Behavior %s %s\n", behaviorID, action)
+			fmt.Fprintf(out, "Behavior %s %s\n", behaviorID, action)
 			return nil
 		},
 	}
@@ -701,3 +1531,212 @@ Examples:
 
 	return cmd
 }
+
+func newPackDiffCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <old> <new>",
+		Short: "Compare two versions of a pack",
+		Long: `Report the behaviors added, changed, and removed between two versions of a
+pack, as a changelog for its release.
+
+Each side is a pack source, as for 'pack install': a .fpack file, an
+HTTP(S) URL, gh:owner/repo@version, gl:group/project@version, a git+
+source, or registry:namespace/name@version. Remote packs are downloaded
+to the pack cache. <new> may be just @version to compare another release
+or tag of the same gh:, gl:, git+, or registry: source.
+
+--format markdown prints a release notes section to paste into the
+release; --json prints the same changelog for tooling.
+
+Examples:
+  floop pack diff my-pack-1.0.0.fpack my-pack-1.1.0.fpack
+  floop pack diff gh:my-org/go-pack@v1.0.0 @v1.1.0
+  floop pack diff registry:my-org/go-pack@1.0.0 my-pack.fpack --format markdown`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			format, _ := cmd.Flags().GetString("format")
+			if format != "text" && format != "markdown" {
+				return fmt.Errorf("invalid --format %q: must be text or markdown", format)
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			oldSource, newSource := args[0], args[1]
+			if strings.HasPrefix(newSource, "@") {
+				if newSource, err = withSourceVersion(oldSource, strings.TrimPrefix(newSource, "@")); err != nil {
+					return err
+				}
+			}
+
+			ctx := context.Background()
+			oldData, oldManifest, err := readPackSource(ctx, oldSource, cfg)
+			if err != nil {
+				return err
+			}
+			newData, newManifest, err := readPackSource(ctx, newSource, cfg)
+			if err != nil {
+				return err
+			}
+			if oldManifest.ID != newManifest.ID {
+				fmt.Fprintf(cmd.ErrOrStderr(), "Warning: comparing different packs: %s and %s\n", oldManifest.ID, newManifest.ID)
+			}
+
+			changelog := pack.Diff(oldData, oldManifest, newData, newManifest)
+			if jsonOut {
+				return json.NewEncoder(out).Encode(changelog)
+			}
+			if format == "markdown" {
+				fmt.Fprint(out, changelog.Markdown())
+				return nil
+			}
+
+			fmt.Fprintf(out, "%s %s -> %s\n", changelog.PackID, changelog.FromVersion, changelog.ToVersion)
+			if changelog.Empty() {
+				fmt.Fprintf(out, "No differences (%d behaviors).\n", changelog.Unchanged)
+				return nil
+			}
+			if len(changelog.Added) > 0 {
+				fmt.Fprintf(out, "\nAdded (%d):\n", len(changelog.Added))
+				for _, e := range changelog.Added {
+					fmt.Fprintf(out, "  + %s  %s  [%s]\n", e.ID, e.Name, e.Kind)
+				}
+			}
+			if len(changelog.Changed) > 0 {
+				fmt.Fprintf(out, "\nChanged (%d):\n", len(changelog.Changed))
+				for _, e := range changelog.Changed {
+					fmt.Fprintf(out, "  ~ %s  %s  (%s)\n", e.ID, e.Name, strings.Join(e.Fields, ", "))
+				}
+			}
+			if len(changelog.Removed) > 0 {
+				fmt.Fprintf(out, "\nRemoved (%d):\n", len(changelog.Removed))
+				for _, e := range changelog.Removed {
+					fmt.Fprintf(out, "  - %s  %s  [%s]\n", e.ID, e.Name, e.Kind)
+				}
+			}
+			if changelog.EdgesAdded+changelog.EdgesRemoved > 0 {
+				fmt.Fprintf(out, "\nEdges: %d added, %d removed\n", changelog.EdgesAdded, changelog.EdgesRemoved)
+			}
+			fmt.Fprintf(out, "\n%d added, %d changed, %d removed, %d unchanged\n",
+				len(changelog.Added), len(changelog.Changed), len(changelog.Removed), changelog.Unchanged)
+			return nil
+		},
+	}
+
+	cmd.Flags().String("format", "text", "Output format: text or markdown (release notes)")
+
+	return cmd
+}
+
+// withSourceVersion returns the gh:, gl:, git+, or registry: source at
+// version.
+func withSourceVersion(source, version string) (string, error) {
+	resolved, err := pack.ResolveSource(source)
+	if err != nil {
+		return "", err
+	}
+	if version == "" {
+		return "", fmt.Errorf("version after @ is empty")
+	}
+	versioned, ok := resolved.AtVersion(version)
+	if !ok {
+		return "", fmt.Errorf("@%s needs a gh:, gl:, git+, or registry: source, got %s", version, source)
+	}
+	return versioned, nil
+}
+
+// readPackSource fetches the pack at source and reads it.
+func readPackSource(ctx context.Context, source string, cfg *config.FloopConfig) (*backup.BackupFormat, *pack.PackManifest, error) {
+	_, paths, err := pack.FetchSource(ctx, source, cfg, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, manifest, err := pack.ReadPackFile(paths[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading %s: %w", source, err)
+	}
+	return data, manifest, nil
+}
+
+// printSignatureStatus prints an install's signature check, if one ran.
+// addCollisionFlag adds --on-collision to a command that installs packs.
+func addCollisionFlag(cmd *cobra.Command) {
+	cmd.Flags().String("on-collision", "", "How to resolve pack behaviors that collide with local ones: "+
+		strings.Join(config.CollisionPolicies, ", ")+" (default: packs.collision_policy)")
+}
+
+// collisionOptions returns the --on-collision policy, and a resolver that
+// asks about each collision under the prompt policy. JSON output never
+// prompts; there, prompt keeps the local behavior.
+func collisionOptions(cmd *cobra.Command, out io.Writer, jsonOut bool) (string, pack.CollisionResolver, error) {
+	policy, _ := cmd.Flags().GetString("on-collision")
+	if policy != "" && !slices.Contains(config.CollisionPolicies, policy) {
+		return "", nil, fmt.Errorf("--on-collision must be one of: %s", strings.Join(config.CollisionPolicies, ", "))
+	}
+	if jsonOut {
+		return policy, nil, nil
+	}
+
+	p := &prompter{in: bufio.NewReader(cmd.InOrStdin()), out: out}
+	actions := map[string]string{
+		"k": config.CollisionKeepLocal,
+		"p": config.CollisionPreferPack,
+		"r": config.CollisionRename,
+	}
+	return policy, func(c pack.Collision) (string, error) {
+		if c.Kind == pack.CollisionSameID {
+			fmt.Fprintf(out, "\nPack behavior %s has the same ID as a local behavior:\n", c.PackBehavior)
+		} else {
+			fmt.Fprintf(out, "\nPack behavior %s is %.0f%% similar to local behavior %s:\n", c.PackBehavior, c.Similarity*100, c.LocalBehavior)
+		}
+		fmt.Fprintf(out, "  local: %s\n  pack:  %s\n", truncatePreview(c.LocalContent, 70), truncatePreview(c.PackContent, 70))
+		for {
+			answer, err := p.ask("Keep local, prefer pack, or rename? (k/p/r)", "k")
+			if err != nil {
+				return "", err
+			}
+			if action, ok := actions[strings.ToLower(answer)]; ok {
+				return action, nil
+			}
+			if slices.Contains(config.CollisionPolicies, answer) && answer != config.CollisionPrompt {
+				return answer, nil
+			}
+		}
+	}, nil
+}
+
+// printCollisions lists the collisions of an install and how each was
+// resolved.
+func printCollisions(out io.Writer, collisions []pack.Collision) {
+	if len(collisions) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "  Collisions: %d\n", len(collisions))
+	for _, c := range collisions {
+		line := fmt.Sprintf("    %s: %s with local %s", c.Action, c.PackBehavior, c.LocalBehavior)
+		if c.Kind == pack.CollisionSameContent {
+			line += fmt.Sprintf(" (%.0f%% similar)", c.Similarity*100)
+		} else {
+			line += " (same ID)"
+		}
+		if c.InstalledAs != "" {
+			line += ", installed as " + c.InstalledAs
+		}
+		fmt.Fprintln(out, line)
+	}
+}
+
+func printSignatureStatus(out io.Writer, status *pack.SignatureStatus) {
+	switch {
+	case status == nil:
+	case status.Trusted:
+		fmt.Fprintf(out, "  Signature: trusted (%s)\n", status.KeyName)
+	case status.Signed:
+		fmt.Fprintf(out, "  Signature: untrusted key %s\n", status.Fingerprint)
+	default:
+		fmt.Fprintln(out, "  Signature: unsigned")
+	}
+}
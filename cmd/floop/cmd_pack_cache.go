@@ -0,0 +1,271 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func newPackCacheCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "cache",
+		Short: "Manage the pack download cache",
+		Long: `Inspect and clean the pack download cache (~/.floop/cache/packs).
+
+Remote packs are downloaded into the cache when installed, updated, or
+rolled back. Installed packs keep working without their cache files;
+a missing file is downloaded again when it is next needed.
+
+Examples:
+  floop pack cache list
+  floop pack cache clean --older-than 90d
+  floop pack cache purge --yes`,
+	}
+
+	cmd.AddCommand(
+		newPackCacheListCmd(),
+		newPackCacheCleanCmd(),
+		newPackCachePurgeCmd(),
+	)
+
+	return cmd
+}
+
+func newPackCacheListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List cached pack downloads",
+		Long: `List the files in the pack download cache with their size, when they
+were last used, and their status:
+
+  installed  an installed pack was installed from it
+  kept       a rollback version, or another release of an installed source
+  download   a temp or partial file from an interrupted download
+  orphaned   no installed pack refers to it; removed by clean and gc
+
+Examples:
+  floop pack cache list
+  floop pack cache list --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			cacheDir, err := pack.DefaultCacheDir()
+			if err != nil {
+				return err
+			}
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+
+			entries, err := pack.ListCache(cacheDir, pack.ListInstalled(cfg))
+			if err != nil {
+				return err
+			}
+			var total int64
+			byStatus := make(map[string]int)
+			for _, e := range entries {
+				total += e.Size
+				byStatus[e.Status]++
+			}
+
+			if jsonOut {
+				if entries == nil {
+					entries = []pack.CacheEntry{}
+				}
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"cache_dir":   cacheDir,
+					"files":       entries,
+					"count":       len(entries),
+					"total_bytes": total,
+				})
+			}
+
+			if len(entries) == 0 {
+				fmt.Fprintf(out, "Pack cache is empty (%s).\n", cacheDir)
+				return nil
+			}
+
+			fmt.Fprintf(out, "Pack cache: %s\n\n", cacheDir)
+			fmt.Fprintf(out, "  %-9s %-16s %-10s %s\n", "SIZE", "LAST USED", "STATUS", "FILE")
+			for _, e := range entries {
+				rel, err := filepath.Rel(cacheDir, e.Path)
+				if err != nil {
+					rel = e.Path
+				}
+				fmt.Fprintf(out, "  %-9s %-16s %-10s %s\n",
+					formatBytes(e.Size), e.LastUsed.Format("2006-01-02 15:04"), e.Status, filepath.ToSlash(rel))
+			}
+			fmt.Fprintf(out, "\n%d file(s), %s", len(entries), formatBytes(total))
+			sep := " ("
+			for _, status := range []string{pack.CacheInstalled, pack.CacheKept, pack.CacheDownload, pack.CacheOrphaned} {
+				if n := byStatus[status]; n > 0 {
+					fmt.Fprintf(out, "%s%d %s", sep, n, status)
+					sep = ", "
+				}
+			}
+			fmt.Fprintln(out, ")")
+			return nil
+		},
+	}
+
+	return cmd
+}
+
+func newPackCacheCleanCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clean",
+		Short: "Remove orphaned and unused pack downloads",
+		Long: `Remove cache files that no installed pack refers to, and temp files
+from downloads interrupted more than an hour ago.
+
+With --older-than, or packs.cache_max_age in config, files not used for
+that long are removed too, except those installed packs were installed
+from. Rollback versions removed this way are downloaded again if a
+rollback needs them. floop gc applies packs.cache_max_age as well.
+
+Examples:
+  floop pack cache clean --dry-run
+  floop pack cache clean
+  floop pack cache clean --older-than 90d
+  floop pack cache clean --json`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			olderThan, _ := cmd.Flags().GetString("older-than")
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			maxAge := cfg.Packs.CacheMaxAgeDuration()
+			if olderThan != "" {
+				if maxAge, err = utils.ParseDuration(olderThan); err != nil || maxAge <= 0 {
+					return fmt.Errorf("invalid --older-than: %s (e.g. 90d, 720h)", olderThan)
+				}
+			}
+
+			cacheDir, err := pack.DefaultCacheDir()
+			if err != nil {
+				return err
+			}
+			files, err := pack.FindEvictableCache(cacheDir, pack.ListInstalled(cfg), time.Now(), maxAge)
+			if err != nil {
+				return err
+			}
+			return removeCacheFiles(out, cacheDir, files, dryRun, jsonOut)
+		},
+	}
+
+	cmd.Flags().String("older-than", "", "Also remove files not used for this long (e.g. 90d); default packs.cache_max_age")
+	cmd.Flags().Bool("dry-run", false, "Report what would be removed without removing it")
+
+	return cmd
+}
+
+func newPackCachePurgeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "purge",
+		Short: "Remove every file in the pack download cache",
+		Long: `Empty the pack download cache. Installed packs are not affected; their
+files are downloaded again when an update, rollback, or frozen install
+needs them.
+
+Asks for confirmation unless --yes or --json is given.
+
+Examples:
+  floop pack cache purge
+  floop pack cache purge --yes
+  floop pack cache purge --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			cacheDir, err := pack.DefaultCacheDir()
+			if err != nil {
+				return err
+			}
+			entries, err := pack.ListCache(cacheDir, nil)
+			if err != nil {
+				return err
+			}
+			files := make([]pack.CacheFile, len(entries))
+			var total int64
+			for i, e := range entries {
+				files[i] = e.CacheFile
+				total += e.Size
+			}
+
+			if !dryRun && len(files) > 0 {
+				confirmed, err := confirmDestructive(config.OpPackCachePurge, yes, jsonOut, func() {
+					fmt.Fprintf(out, "Remove all %d file(s) (%s) from %s?\n", len(files), formatBytes(total), cacheDir)
+				})
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(out, "Cancelled.")
+					return nil
+				}
+			}
+			return removeCacheFiles(out, cacheDir, files, dryRun, jsonOut)
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Report what would be removed without removing it")
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// removeCacheFiles removes files from the pack cache, unless dryRun is
+// set, and reports what was (or would be) removed.
+func removeCacheFiles(out *output, cacheDir string, files []pack.CacheFile, dryRun, jsonOut bool) error {
+	var reclaimed int64
+	if dryRun {
+		for _, f := range files {
+			reclaimed += f.Size
+		}
+	} else {
+		n, err := pack.RemoveCacheFiles(cacheDir, files)
+		reclaimed = n
+		if err != nil {
+			return fmt.Errorf("cleaning pack cache: %w", err)
+		}
+	}
+
+	if jsonOut {
+		if files == nil {
+			files = []pack.CacheFile{}
+		}
+		return json.NewEncoder(out).Encode(map[string]interface{}{
+			"dry_run":         dryRun,
+			"removed":         files,
+			"count":           len(files),
+			"bytes_reclaimed": reclaimed,
+		})
+	}
+
+	verb := "Removed"
+	if dryRun {
+		verb = "Would remove"
+	}
+	fmt.Fprintf(out, "%s %d pack cache file(s) (%s)\n", verb, len(files), formatBytes(reclaimed))
+	for _, f := range files {
+		out.Verbosef("  - %s (%s)\n", f.Path, formatBytes(f.Size))
+	}
+	return nil
+}
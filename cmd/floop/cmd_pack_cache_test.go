@@ -0,0 +1,167 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/pack"
+)
+
+// setupPackCache isolates HOME and fills the pack cache with an installed
+// pack's download, another release of its repo last used 100 days ago,
+// and an orphan. It returns their paths.
+func setupPackCache(t *testing.T) (installed, old, orphan string) {
+	t.Helper()
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	cacheDir, err := pack.DefaultCacheDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	installed = pack.GitHubCachePath(cacheDir, "acme", "packs", "1.0.0", "go.fpack")
+	old = pack.GitHubCachePath(cacheDir, "acme", "packs", "0.9.0", "go.fpack")
+	orphan = pack.HTTPCachePath(cacheDir, "https://example.com/gone.fpack")
+	for path, size := range map[string]int{installed: 1024, old: 2048, orphan: 512} {
+		if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, make([]byte, size), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	lastUsed := time.Now().Add(-100 * 24 * time.Hour)
+	if err := os.Chtimes(old, lastUsed, lastUsed); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Packs.Installed = []config.InstalledPack{{ID: "acme/go", Version: "1.0.0", Source: "gh:acme/packs", Path: installed}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	return installed, old, orphan
+}
+
+func TestPackCacheList(t *testing.T) {
+	installed, old, orphan := setupPackCache(t)
+
+	out, err := runCmd(t, newPackCmd(), "cache", "list")
+	if err != nil {
+		t.Fatalf("pack cache list failed: %v", err)
+	}
+	for _, want := range []string{"acme/packs/1.0.0/go.fpack", "installed", "kept", "orphaned", "3 file(s), 3.5KB (1 installed, 1 kept, 1 orphaned)"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCmd(t, newPackCmd(), "cache", "list", "--json")
+	if err != nil {
+		t.Fatalf("pack cache list --json failed: %v", err)
+	}
+	var result struct {
+		Files      []pack.CacheEntry `json:"files"`
+		TotalBytes int64             `json:"total_bytes"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	status := make(map[string]string)
+	for _, f := range result.Files {
+		status[f.Path] = f.Status
+	}
+	want := map[string]string{installed: pack.CacheInstalled, old: pack.CacheKept, orphan: pack.CacheOrphaned}
+	for path, s := range want {
+		if status[path] != s {
+			t.Errorf("status of %s = %q, want %q", filepath.Base(filepath.Dir(path)), status[path], s)
+		}
+	}
+	if result.TotalBytes != 3584 {
+		t.Errorf("total_bytes = %d, want 3584", result.TotalBytes)
+	}
+}
+
+func TestPackCacheClean(t *testing.T) {
+	installed, old, orphan := setupPackCache(t)
+
+	out, err := runCmd(t, newPackCmd(), "cache", "clean", "--dry-run")
+	if err != nil {
+		t.Fatalf("pack cache clean --dry-run failed: %v", err)
+	}
+	if !strings.Contains(out, "Would remove 1 pack cache file(s) (512B)") {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+	if _, err := os.Stat(orphan); err != nil {
+		t.Errorf("dry run removed the orphan: %v", err)
+	}
+
+	if _, err := runCmd(t, newPackCmd(), "cache", "clean", "--older-than", "90d"); err != nil {
+		t.Fatalf("pack cache clean --older-than failed: %v", err)
+	}
+	for path, wantKept := range map[string]bool{installed: true, old: false, orphan: false} {
+		if _, err := os.Stat(path); (err == nil) != wantKept {
+			t.Errorf("%s kept = %v, want %v", path, err == nil, wantKept)
+		}
+	}
+
+	if _, err := runCmd(t, newPackCmd(), "cache", "clean", "--older-than", "soon"); err == nil {
+		t.Error("expected error for an invalid --older-than")
+	}
+}
+
+func TestPackCacheCleanMaxAgeConfig(t *testing.T) {
+	_, old, _ := setupPackCache(t)
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Packs.CacheMaxAge = "30d"
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := runCmd(t, newPackCmd(), "cache", "clean", "--json")
+	if err != nil {
+		t.Fatalf("pack cache clean failed: %v", err)
+	}
+	var result struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Count != 2 {
+		t.Errorf("count = %d, want 2", result.Count)
+	}
+	if _, err := os.Stat(old); !os.IsNotExist(err) {
+		t.Error("packs.cache_max_age should evict the unused release")
+	}
+}
+
+func TestPackCachePurge(t *testing.T) {
+	installed, _, _ := setupPackCache(t)
+
+	out, err := runCmd(t, newPackCmd(), "cache", "purge", "--yes")
+	if err != nil {
+		t.Fatalf("pack cache purge failed: %v", err)
+	}
+	if !strings.Contains(out, "Removed 3 pack cache file(s) (3.5KB)") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if _, err := os.Stat(installed); !os.IsNotExist(err) {
+		t.Error("purge should remove installed packs' downloads too")
+	}
+
+	out, err = runCmd(t, newPackCmd(), "cache", "list")
+	if err != nil {
+		t.Fatalf("pack cache list failed: %v", err)
+	}
+	if !strings.Contains(out, "Pack cache is empty") {
+		t.Errorf("unexpected output after purge:\n%s", out)
+	}
+}
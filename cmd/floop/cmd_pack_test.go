@@ -2,9 +2,22 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
 )
 
 func TestNewPackCmd(t *testing.T) {
@@ -16,12 +29,15 @@ func TestNewPackCmd(t *testing.T) {
 
 	// Verify subcommands exist
 	subcommands := map[string]bool{
-		"create":  false,
-		"install": false,
-		"list":    false,
-		"info":    false,
-		"update":  false,
-		"remove":  false,
+		"create":   false,
+		"install":  false,
+		"search":   false,
+		"list":     false,
+		"info":     false,
+		"update":   false,
+		"remove":   false,
+		"rollback": false,
+		"diff":     false,
 	}
 
 	for _, sub := range cmd.Commands() {
@@ -64,8 +80,8 @@ func TestNewPackCreateCmd_Flags(t *testing.T) {
 func TestNewPackInstallCmd_Args(t *testing.T) {
 	cmd := newPackInstallCmd()
 
-	if cmd.Use != "install <source>" {
-		t.Errorf("Use = %q, want %q", cmd.Use, "install <source>")
+	if cmd.Use != "install [source]" {
+		t.Errorf("Use = %q, want %q", cmd.Use, "install [source]")
 	}
 
 	if f := cmd.Flags().Lookup("derive-edges"); f == nil {
@@ -75,6 +91,10 @@ func TestNewPackInstallCmd_Args(t *testing.T) {
 	if f := cmd.Flags().Lookup("all-assets"); f == nil {
 		t.Error("missing --all-assets flag")
 	}
+
+	if f := cmd.Flags().Lookup("frozen"); f == nil {
+		t.Error("missing --frozen flag")
+	}
 }
 
 func TestNewPackListCmd(t *testing.T) {
@@ -219,10 +239,7 @@ func TestPackInfoNotFound(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"pack", "info", "nonexistent/pack", "--root", tmpDir})
 
-	// Should not error — just prints not found message
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("pack info failed: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestPackInfoJSON(t *testing.T) {
@@ -234,9 +251,7 @@ func TestPackInfoJSON(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"pack", "info", "nonexistent/pack", "--json", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("pack info --json failed: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestPackInstallFromFile(t *testing.T) {
@@ -302,7 +317,7 @@ func TestPackRemoveNotInstalled(t *testing.T) {
 	rootCmd := newTestRootCmd()
 	rootCmd.AddCommand(newPackCmd())
 	rootCmd.SetOut(&bytes.Buffer{})
-	rootCmd.SetArgs([]string{"pack", "remove", "nonexistent/pack", "--root", tmpDir})
+	rootCmd.SetArgs([]string{"pack", "remove", "nonexistent/pack", "--yes", "--root", tmpDir})
 
 	// Remove succeeds even for nonexistent packs (reports 0 behaviors forgotten)
 	if err := rootCmd.Execute(); err != nil {
@@ -325,6 +340,114 @@ func TestPackUpdateAllNotInstalled(t *testing.T) {
 	}
 }
 
+func TestPackSearch(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	search := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newPackCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append([]string{"pack", "search", "--root", tmpDir}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := search("go"); err == nil || !strings.Contains(err.Error(), "no pack registries") {
+		t.Fatalf("expected missing registry error, got %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(pack.RegistryIndex{Packs: []pack.RegistryPack{
+			{ID: "acme/go-style", Description: "Go conventions", Versions: []pack.RegistryVersion{{Version: "1.2.0"}}},
+			{ID: "acme/python", Description: "Python tooling"},
+		}})
+	}))
+	defer srv.Close()
+	cfg := config.Default()
+	cfg.Packs.Registries = []config.Registry{{Name: "main", URL: srv.URL}}
+	cfg.Packs.Installed = []config.InstalledPack{{ID: "acme/go-style", Version: "1.1.0"}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := search("go")
+	if err != nil {
+		t.Fatalf("pack search failed: %v", err)
+	}
+	if !strings.Contains(out, "acme/go-style v1.2.0 [main] (installed v1.1.0)") || strings.Contains(out, "acme/python") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+
+	out, err = search("--json")
+	if err != nil {
+		t.Fatalf("pack search --json failed: %v", err)
+	}
+	var result struct {
+		Count int `json:"count"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil || result.Count != 2 {
+		t.Errorf("pack search --json = %s (%v)", out, err)
+	}
+}
+
+func TestPackPublish(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newPackCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	packPath := filepath.Join(tmpDir, "publish-test.fpack")
+	if _, err := run("pack", "create", packPath, "--id", "test-org/publish-test", "--version", "1.0.0"); err != nil {
+		t.Fatalf("pack create failed: %v", err)
+	}
+
+	if _, err := run("pack", "publish", packPath); err == nil || !strings.Contains(err.Error(), "no pack registries") {
+		t.Fatalf("expected missing registry error, got %v", err)
+	}
+
+	var uploaded bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		uploaded = true
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+	cfg := config.Default()
+	cfg.Packs.Registries = []config.Registry{{Name: "main", URL: srv.URL, Token: "s3cret"}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	// The pack has no description, so lint blocks the upload.
+	out, err := run("pack", "publish", packPath, "--registry", "main")
+	if err == nil || !strings.Contains(out, "[manifest] description is required") {
+		t.Fatalf("expected lint failure, got %v\n%s", err, out)
+	}
+	if uploaded {
+		t.Fatal("a pack that fails lint was uploaded")
+	}
+
+	if _, err := run("pack", "create", packPath, "--id", "test-org/publish-test", "--version", "1.0.0", "--description", "Logging conventions"); err != nil {
+		t.Fatalf("pack create failed: %v", err)
+	}
+	out, err = run("pack", "publish", packPath, "--dry-run")
+	if err != nil || !strings.Contains(out, "Would publish test-org/publish-test v1.0.0 to main") || uploaded {
+		t.Fatalf("dry run = %v\n%s", err, out)
+	}
+	out, err = run("pack", "publish", packPath)
+	if err != nil || !strings.Contains(out, "Published test-org/publish-test v1.0.0 to main") || !uploaded {
+		t.Fatalf("publish = %v\n%s", err, out)
+	}
+}
+
 func TestPackCreateThenInfo(t *testing.T) {
 	tmpDir, _ := setupQueryTest(t)
 
@@ -521,8 +644,417 @@ func TestPackCreateInstallRemove(t *testing.T) {
 	rootCmd3 := newTestRootCmd()
 	rootCmd3.AddCommand(newPackCmd())
 	rootCmd3.SetOut(&bytes.Buffer{})
-	rootCmd3.SetArgs([]string{"pack", "remove", "test-org/remove-test", "--root", tmpDir})
+	rootCmd3.SetArgs([]string{"pack", "remove", "test-org/remove-test", "--yes", "--root", tmpDir})
 	if err := rootCmd3.Execute(); err != nil {
 		t.Fatalf("pack remove failed: %v", err)
 	}
 }
+
+func TestPackCreateAnonymize(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	outputPath := filepath.Join(tmpDir, "anon.fpack")
+
+	var buf bytes.Buffer
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newPackCmd())
+	rootCmd.SetOut(&buf)
+	rootCmd.SetArgs([]string{
+		"pack", "create", outputPath,
+		"--id", "test-org/anon-pack",
+		"--version", "1.0.0",
+		"--anonymize",
+		"--anonymize-users", "slog",
+		"--json",
+		"--root", tmpDir,
+	})
+
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("pack create --anonymize failed: %v", err)
+	}
+
+	var resp struct {
+		Anonymize *pack.AnonymizeReport `json:"anonymize"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &resp); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if resp.Anonymize == nil || resp.Anonymize.BehaviorsChanged != 1 {
+		t.Fatalf("anonymize report = %+v, want 1 behavior changed", resp.Anonymize)
+	}
+
+	mappingPath := filepath.Join(tmpDir, "home", ".floop", "anonymize", "test-org", "anon-pack.json")
+	if resp.Anonymize.MappingPath != mappingPath {
+		t.Errorf("mapping_path = %q, want %q", resp.Anonymize.MappingPath, mappingPath)
+	}
+	if _, err := os.Stat(mappingPath); err != nil {
+		t.Errorf("mapping not saved: %v", err)
+	}
+}
+
+func TestPackCreateSigned(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyPath := filepath.Join(tmpDir, "packs.key")
+	if err := os.WriteFile(keyPath, []byte(base64.StdEncoding.EncodeToString(priv)), 0600); err != nil {
+		t.Fatal(err)
+	}
+	packPath := filepath.Join(tmpDir, "signed.fpack")
+
+	run := func(args ...string) (string, error) {
+		var buf bytes.Buffer
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newPackCmd())
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		err := rootCmd.Execute()
+		return buf.String(), err
+	}
+
+	out, err := run("pack", "create", packPath, "--id", "test-org/signed", "--version", "1.0.0", "--sign-key", keyPath)
+	if err != nil {
+		t.Fatalf("pack create --sign-key failed: %v", err)
+	}
+	if !strings.Contains(out, "Signed: "+pack.KeyFingerprint(pub)) {
+		t.Errorf("create output missing signer:\n%s", out)
+	}
+
+	cfg := config.Default()
+	cfg.Packs.SignaturePolicy = config.SignaturePolicyRequire
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run("pack", "install", packPath); err == nil {
+		t.Fatal("install of an untrusted pack should fail under the require policy")
+	}
+
+	cfg.Packs.TrustedKeys = []config.TrustedKey{{Name: "test-org", PublicKey: base64.StdEncoding.EncodeToString(pub)}}
+	if err := cfg.Save(); err != nil {
+		t.Fatal(err)
+	}
+	out, err = run("pack", "install", packPath)
+	if err != nil {
+		t.Fatalf("pack install failed: %v", err)
+	}
+	if !strings.Contains(out, "Signature: trusted (test-org)") {
+		t.Errorf("install output missing signature status:\n%s", out)
+	}
+}
+
+func TestPackCreateDryRunAndInteractive(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	outputPath := filepath.Join(tmpDir, "curated.fpack")
+
+	run := func(stdin string, args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newPackCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetIn(strings.NewReader(stdin))
+		rootCmd.SetArgs(append([]string{"pack", "create", outputPath,
+			"--id", "test-org/curated", "--version", "1.0.0", "--root", tmpDir}, args...))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	out, err := run("", "--dry-run", "--filter-kinds", "constraint")
+	if err != nil {
+		t.Fatalf("dry run failed: %v", err)
+	}
+	if !strings.Contains(out, "Would include 0 behaviors") || !strings.Contains(out, behaviorID) || !strings.Contains(out, "not in constraint") {
+		t.Errorf("dry run output missing the exclusion:\n%s", out)
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Fatal("dry run wrote the pack file")
+	}
+
+	if _, err := run("", "--interactive", "--filter-kinds", "constraint"); err == nil {
+		t.Error("interactive with no matches succeeded, want error")
+	}
+
+	out, err = run("5\n1\nn\n", "--interactive")
+	if err != nil {
+		t.Fatalf("interactive create failed: %v", err)
+	}
+	if !strings.Contains(out, "invalid selection") || !strings.Contains(out, "Cancelled.") {
+		t.Errorf("interactive output:\n%s", out)
+	}
+	if _, err := os.Stat(outputPath); !os.IsNotExist(err) {
+		t.Fatal("cancelled create wrote the pack file")
+	}
+
+	if out, err = run("1\n\n", "--interactive"); err != nil {
+		t.Fatalf("interactive create failed: %v", err)
+	}
+	if !strings.Contains(out, "Pack created: 1 behaviors") {
+		t.Errorf("interactive output:\n%s", out)
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{"", []int{1, 2, 3, 4, 5}, false},
+		{"1-3,5", []int{1, 2, 3, 5}, false},
+		{"4 2", []int{2, 4}, false},
+		{"!2, !5", []int{1, 3, 4}, false},
+		{"1-4 !2", []int{1, 3, 4}, false},
+		{"!1-5", nil, false},
+		{"6", nil, true},
+		{"3-1", nil, true},
+		{"a", nil, true},
+	}
+	for _, tt := range tests {
+		got, err := parseSelection(tt.input, 5)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseSelection(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseSelection(%q) = %v, want %v", tt.input, got, tt.want)
+		}
+	}
+}
+
+func TestPackDiffCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	oldPath := filepath.Join(tmpDir, "v1.fpack")
+	newPath := filepath.Join(tmpDir, "v2.fpack")
+
+	run := func(cmd *cobra.Command, args ...string) string {
+		t.Helper()
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(cmd)
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+		return out.String()
+	}
+
+	run(newPackCmd(), "pack", "create", oldPath, "--id", "test-org/diff", "--version", "1.0.0")
+	run(newLearnCmd(), "learn",
+		"--wrong", "ignored the returned error",
+		"--right", "always check returned errors",
+		"--file", "main.go")
+	run(newPackCmd(), "pack", "create", newPath, "--id", "test-org/diff", "--version", "1.1.0")
+
+	out := run(newPackCmd(), "pack", "diff", oldPath, newPath)
+	if !strings.Contains(out, "test-org/diff 1.0.0 -> 1.1.0") || !strings.Contains(out, "1 added, 0 changed, 0 removed, 1 unchanged") {
+		t.Errorf("text output:\n%s", out)
+	}
+
+	out = run(newPackCmd(), "pack", "diff", oldPath, newPath, "--format", "markdown")
+	if !strings.Contains(out, "## test-org/diff 1.1.0") || !strings.Contains(out, "### Added") {
+		t.Errorf("markdown output:\n%s", out)
+	}
+
+	var changelog pack.Changelog
+	if err := json.Unmarshal([]byte(run(newPackCmd(), "pack", "diff", newPath, oldPath, "--json")), &changelog); err != nil {
+		t.Fatalf("parsing JSON output: %v", err)
+	}
+	if len(changelog.Removed) != 1 || changelog.Unchanged != 1 || changelog.Removed[0].ID == behaviorID {
+		t.Errorf("reverse diff = %+v, want the new behavior removed", changelog)
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newPackCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetErr(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"pack", "diff", oldPath, "@1.1.0"})
+	if err := rootCmd.Execute(); err == nil || !strings.Contains(err.Error(), "needs a gh:, gl:, git+, or registry: source") {
+		t.Errorf("@version against a file: err = %v", err)
+	}
+}
+
+func TestPackRollbackCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newPackCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	for _, version := range []string{"1.0.0", "2.0.0"} {
+		packPath := filepath.Join(tmpDir, "rollback-"+version+".fpack")
+		if _, err := run("pack", "create", packPath, "--id", "test-org/rollback", "--version", version); err != nil {
+			t.Fatalf("pack create %s failed: %v", version, err)
+		}
+		if _, err := run("pack", "install", packPath); err != nil {
+			t.Fatalf("pack install %s failed: %v", version, err)
+		}
+	}
+
+	out, err := run("pack", "info", "test-org/rollback")
+	if err != nil || !strings.Contains(out, "Previous versions: 1.0.0") {
+		t.Errorf("pack info: err = %v\n%s", err, out)
+	}
+
+	out, err = run("pack", "rollback", "test-org/rollback", "--json")
+	if err != nil {
+		t.Fatalf("pack rollback failed: %v", err)
+	}
+	var result struct {
+		FromVersion string   `json:"from_version"`
+		ToVersion   string   `json:"to_version"`
+		Reverted    []string `json:"reverted"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("parsing JSON output: %v\n%s", err, out)
+	}
+	if result.FromVersion != "2.0.0" || result.ToVersion != "1.0.0" || len(result.Reverted) != 1 || result.Reverted[0] != behaviorID {
+		t.Errorf("rollback = %+v", result)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("loading config: %v", err)
+	}
+	if p := cfg.Packs.Installed[0]; p.Version != "1.0.0" || len(p.History) != 0 {
+		t.Errorf("installed after rollback = %s with history %+v", p.Version, p.History)
+	}
+
+	if _, err := run("pack", "rollback", "test-org/rollback"); err == nil || !strings.Contains(err.Error(), "no previous version") {
+		t.Errorf("second rollback: err = %v", err)
+	}
+}
+
+func TestPackLockAndPin(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+	packPath := filepath.Join(tmpDir, "lock.fpack")
+
+	run := func(args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newPackCmd())
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run("pack", "install", "--frozen"); err == nil {
+		t.Error("frozen install without a lockfile succeeded")
+	}
+	if _, err := run("pack", "create", packPath, "--id", "test-org/lock", "--version", "1.0.0"); err != nil {
+		t.Fatalf("pack create failed: %v", err)
+	}
+	if _, err := run("pack", "install", packPath); err != nil {
+		t.Fatalf("pack install failed: %v", err)
+	}
+
+	lock, err := pack.ReadLock(pack.LockPath(tmpDir))
+	if err != nil {
+		t.Fatalf("reading lockfile: %v", err)
+	}
+	locked := lock.Get("test-org/lock")
+	if locked == nil || locked.Version != "1.0.0" || locked.Source != "lock.fpack" || locked.SHA256 == "" {
+		t.Fatalf("locked = %+v, want 1.0.0 from lock.fpack with a checksum", locked)
+	}
+
+	if out, err := run("pack", "pin", "test-org/lock"); err != nil || !strings.Contains(out, "Pinned test-org/lock to v1.0.0") {
+		t.Fatalf("pack pin: err = %v\n%s", err, out)
+	}
+	if _, err := run("pack", "update", "test-org/lock"); err == nil || !strings.Contains(err.Error(), "pinned") {
+		t.Errorf("update of a pinned pack: err = %v", err)
+	}
+	if out, err := run("pack", "update", "--all"); err != nil || strings.Contains(out, "Updated") {
+		t.Errorf("update --all touched a pinned pack: err = %v\n%s", err, out)
+	}
+	if _, err := run("pack", "pin", "test-org/lock@2.0.0"); err == nil {
+		t.Error("pinning a local pack to another version succeeded")
+	}
+	if out, err := run("pack", "unpin", "test-org/lock"); err != nil || !strings.Contains(out, "Unpinned") {
+		t.Errorf("pack unpin: err = %v\n%s", err, out)
+	}
+
+	if out, err := run("pack", "install", "--frozen"); err != nil || !strings.Contains(out, "Installed test-org/lock v1.0.0") {
+		t.Errorf("frozen install: err = %v\n%s", err, out)
+	}
+	if _, err := run("pack", "create", packPath, "--id", "test-org/lock", "--version", "1.1.0"); err != nil {
+		t.Fatalf("pack create failed: %v", err)
+	}
+	if _, err := run("pack", "install", "--frozen"); err == nil || !strings.Contains(err.Error(), "drifted") {
+		t.Errorf("frozen install after the pack changed: err = %v", err)
+	}
+
+	if _, err := run("pack", "remove", "test-org/lock", "--yes"); err != nil {
+		t.Fatalf("pack remove failed: %v", err)
+	}
+	if lock, _ = pack.ReadLock(pack.LockPath(tmpDir)); lock.Get("test-org/lock") != nil {
+		t.Error("removed pack still in the lockfile")
+	}
+}
+
+func TestPackInstallCollision(t *testing.T) {
+	tmpDir, learnedID := setupQueryTest(t)
+	packPath := filepath.Join(tmpDir, "collide.fpack")
+
+	run := func(stdin string, args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newPackCmd())
+		var out bytes.Buffer
+		rootCmd.SetIn(strings.NewReader(stdin))
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+
+	if _, err := run("", "pack", "create", packPath, "--id", "test-org/collide", "--version", "1.0.0"); err != nil {
+		t.Fatalf("pack create failed: %v", err)
+	}
+
+	// Relearn the behavior locally so the pack's copy collides by ID.
+	ctx := context.Background()
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("opening store: %v", err)
+	}
+	node, err := graphStore.GetNode(ctx, learnedID)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", learnedID, node, err)
+	}
+	node.Content["content"] = map[string]interface{}{"canonical": "Use structured logging with slog"}
+	if err := graphStore.UpdateNode(ctx, *node); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	graphStore.Close()
+
+	if _, err := run("", "pack", "install", packPath, "--on-collision", "merge"); err == nil {
+		t.Error("install with an invalid --on-collision succeeded")
+	}
+
+	out, err := run("r\n", "pack", "install", packPath, "--on-collision", "prompt")
+	if err != nil {
+		t.Fatalf("pack install failed: %v\n%s", err, out)
+	}
+	renamed := pack.RenamedID(learnedID, "test-org/collide")
+	for _, want := range []string{
+		"has the same ID as a local behavior",
+		"local: Use structured logging with slog",
+		"Collisions: 1",
+		"rename: " + learnedID + " with local " + learnedID + " (same ID), installed as " + renamed,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/assembly"
+	"github.com/nvandessel/floop/internal/confidence"
+	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tiering"
@@ -21,20 +23,14 @@ func newShowCmd() *cobra.Command {
 		Short: "Show details of a behavior",
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			id := args[0]
 
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": ".floop not initialized",
-					})
-				} else {
-					fmt.Println("Not initialized. Run 'floop init' first.")
-				}
-				return nil
+				return errNotInitialized
 			}
 
 			// Load all behaviors from both local and global stores
@@ -53,58 +49,51 @@ func newShowCmd() *cobra.Command {
 			}
 
 			if found == nil {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": "behavior not found",
-						"id":    id,
-					})
-				} else {
-					fmt.Printf("Behavior not found: %s\n", id)
-				}
-				return nil
+				return newCLIError(codeNotFound, map[string]interface{}{"id": id}, "behavior not found: %s", id)
 			}
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(found)
+				json.NewEncoder(out).Encode(found)
 			} else {
-				fmt.Printf("Behavior: %s\n", found.ID)
-				fmt.Printf("Name: %s\n", found.Name)
-				fmt.Printf("Kind: %s\n", found.Kind)
-				fmt.Printf("Confidence: %.2f\n", found.Confidence)
-				fmt.Printf("Priority: %d\n", found.Priority)
-				fmt.Println()
-
-				fmt.Println("Content:")
-				fmt.Printf("  Canonical: %s\n", found.Content.Canonical)
+				fmt.Fprintf(out, "Behavior: %s\n", found.ID)
+				fmt.Fprintf(out, "Name: %s\n", found.Name)
+				fmt.Fprintf(out, "Kind: %s\n", found.Kind)
+				fmt.Fprintf(out, "Confidence: %.2f\n", found.Confidence)
+				printConfidenceSources(out, found)
+				fmt.Fprintf(out, "Priority: %d\n", found.Priority)
+				fmt.Fprintln(out)
+
+				fmt.Fprintln(out, "Content:")
+				fmt.Fprintf(out, "  Canonical: %s\n", found.Content.Canonical)
 				if len(found.Content.Structured) > 0 {
-					fmt.Printf("  Structured: %v\n", found.Content.Structured)
+					fmt.Fprintf(out, "  Structured: %v\n", found.Content.Structured)
 				}
-				fmt.Println()
+				fmt.Fprintln(out)
 
 				if len(found.When) > 0 {
-					fmt.Println("Activation conditions:")
+					fmt.Fprintln(out, "Activation conditions:")
 					for k, v := range found.When {
-						fmt.Printf("  %s: %v\n", k, v)
+						fmt.Fprintf(out, "  %s: %v\n", k, v)
 					}
-					fmt.Println()
+					fmt.Fprintln(out)
 				}
 
-				fmt.Println("Provenance:")
-				fmt.Printf("  Source: %s\n", found.Provenance.SourceType)
-				fmt.Printf("  Created: %s\n", found.Provenance.CreatedAt.Format(time.RFC3339))
+				fmt.Fprintln(out, "Provenance:")
+				fmt.Fprintf(out, "  Source: %s\n", found.Provenance.SourceType)
+				fmt.Fprintf(out, "  Created: %s\n", found.Provenance.CreatedAt.Format(time.RFC3339))
 				if found.Provenance.CorrectionID != "" {
-					fmt.Printf("  Correction: %s\n", found.Provenance.CorrectionID)
+					fmt.Fprintf(out, "  Correction: %s\n", found.Provenance.CorrectionID)
 				}
-				fmt.Println()
+				fmt.Fprintln(out)
 
 				if len(found.Requires) > 0 {
-					fmt.Printf("Requires: %v\n", found.Requires)
+					fmt.Fprintf(out, "Requires: %v\n", found.Requires)
 				}
 				if len(found.Overrides) > 0 {
-					fmt.Printf("Overrides: %v\n", found.Overrides)
+					fmt.Fprintf(out, "Overrides: %v\n", found.Overrides)
 				}
 				if len(found.Conflicts) > 0 {
-					fmt.Printf("Conflicts: %v\n", found.Conflicts)
+					fmt.Fprintf(out, "Conflicts: %v\n", found.Conflicts)
 				}
 			}
 
@@ -115,6 +104,28 @@ func newShowCmd() *cobra.Command {
 	return cmd
 }
 
+// printConfidenceSources writes the contributions that make up b's
+// confidence. Behaviors created before contributions were tracked show their
+// whole confidence as an untracked base.
+func printConfidenceSources(out *output, b *models.Behavior) {
+	sources := confidence.Sources{Base: b.Confidence, Origin: confidence.OriginUntracked}
+	if b.ConfidenceSources != nil {
+		sources = *b.ConfidenceSources
+	}
+	origin := sources.Origin
+	if origin == "" {
+		origin = confidence.OriginUntracked
+	}
+
+	fmt.Fprintf(out, "  Base (%s): %.2f\n", origin, sources.Base)
+	fmt.Fprintf(out, "  Approval: %+.2f\n", sources.Approval)
+	fmt.Fprintf(out, "  Reinforcement: %+.2f\n", sources.Reinforcement)
+	fmt.Fprintf(out, "  Recalibration: %+.2f\n", sources.Recalibration)
+	if other := sources.Unattributed(b.Confidence); other != 0 {
+		fmt.Fprintf(out, "  Untracked changes: %+.2f\n", other)
+	}
+}
+
 func newWhyCmd() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "why [behavior-id]",
@@ -124,23 +135,21 @@ func newWhyCmd() *cobra.Command {
 This helps debug when a behavior isn't being applied as expected.`,
 		Args: cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			file, _ := cmd.Flags().GetString("file")
 			task, _ := cmd.Flags().GetString("task")
 			env, _ := cmd.Flags().GetString("env")
+			custom, err := contextFlag(cmd)
+			if err != nil {
+				return err
+			}
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			id := args[0]
 
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": ".floop not initialized",
-					})
-				} else {
-					fmt.Println("Not initialized. Run 'floop init' first.")
-				}
-				return nil
+				return errNotInitialized
 			}
 
 			// Load all behaviors from both local and global stores
@@ -159,15 +168,12 @@ This helps debug when a behavior isn't being applied as expected.`,
 			}
 
 			if found == nil {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": "behavior not found",
-						"id":    id,
-					})
-				} else {
-					fmt.Printf("Behavior not found: %s\n", id)
-				}
-				return nil
+				return newCLIError(codeNotFound, map[string]interface{}{"id": id}, "behavior not found: %s", id)
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
 			}
 
 			// Build context
@@ -175,7 +181,9 @@ This helps debug when a behavior isn't being applied as expected.`,
 				WithFile(file).
 				WithTask(task).
 				WithEnvironment(env).
-				WithRepoRoot(root)
+				WithRepoRoot(root).
+				WithGitDetection(cfg.Activation.GitContext).
+				WithCustomValues(custom)
 			ctx := ctxBuilder.Build()
 
 			// Get explanation
@@ -183,54 +191,58 @@ This helps debug when a behavior isn't being applied as expected.`,
 			explanation := evaluator.WhyActive(ctx, *found)
 
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"behavior":    found,
 					"context":     ctx,
 					"explanation": explanation,
 					"scope":       "local",
 				})
 			} else {
-				fmt.Printf("Behavior: %s\n", found.Name)
-				fmt.Printf("ID: %s\n", found.ID)
-				fmt.Println()
+				fmt.Fprintf(out, "Behavior: %s\n", found.Name)
+				fmt.Fprintf(out, "ID: %s\n", found.ID)
+				fmt.Fprintln(out)
 
 				if explanation.IsActive {
-					fmt.Println("Status: ACTIVE")
+					fmt.Fprintln(out, "Status: ACTIVE")
 				} else {
-					fmt.Println("Status: NOT ACTIVE")
+					fmt.Fprintln(out, "Status: NOT ACTIVE")
 				}
-				fmt.Printf("Reason: %s\n", explanation.Reason)
-				fmt.Println()
+				fmt.Fprintf(out, "Reason: %s\n", explanation.Reason)
+				fmt.Fprintln(out)
 
 				if len(explanation.Conditions) > 0 {
-					fmt.Println("Condition evaluation:")
+					fmt.Fprintln(out, "Condition evaluation:")
 					for _, c := range explanation.Conditions {
 						status := "✓"
 						if !c.Matched {
 							status = "✗"
 						}
-						fmt.Printf("  %s %s: required=%v, actual=%v\n",
+						fmt.Fprintf(out, "  %s %s: required=%v, actual=%v\n",
 							status, c.Field, c.Required, c.Actual)
+						if c.Detail != "" {
+							fmt.Fprintf(out, "      %s\n", c.Detail)
+						}
 					}
-					fmt.Println()
+					fmt.Fprintln(out)
 				}
 
-				fmt.Println("Current context:")
+				fmt.Fprintln(out, "Current context:")
 				if ctx.FilePath != "" {
-					fmt.Printf("  file_path: %s\n", ctx.FilePath)
+					fmt.Fprintf(out, "  file_path: %s\n", ctx.FilePath)
 				}
 				if ctx.FileLanguage != "" {
-					fmt.Printf("  language: %s\n", ctx.FileLanguage)
+					fmt.Fprintf(out, "  language: %s\n", ctx.FileLanguage)
 				}
 				if ctx.Task != "" {
-					fmt.Printf("  task: %s\n", ctx.Task)
+					fmt.Fprintf(out, "  task: %s\n", ctx.Task)
 				}
 				if ctx.Branch != "" {
-					fmt.Printf("  branch: %s\n", ctx.Branch)
+					fmt.Fprintf(out, "  branch: %s\n", ctx.Branch)
 				}
 				if ctx.Environment != "" {
-					fmt.Printf("  environment: %s\n", ctx.Environment)
+					fmt.Fprintf(out, "  environment: %s\n", ctx.Environment)
 				}
+				printCustomContext(out, ctx.Custom)
 			}
 
 			return nil
@@ -240,6 +252,7 @@ This helps debug when a behavior isn't being applied as expected.`,
 	cmd.Flags().String("file", "", "Current file path")
 	cmd.Flags().String("task", "", "Current task type")
 	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+	addContextFlag(cmd)
 
 	return cmd
 }
@@ -252,6 +265,8 @@ func newPromptCmd() *cobra.Command {
 
 This command compiles active behaviors into a format suitable for injection into
 agent system prompts. Use --token-budget to limit output size with intelligent tiering.
+With --profile, the agent profile's excluded tags apply, its token budget is used
+when none is given, and tiered output uses its kind boosts and minimum activation.
 
 Examples:
   floop prompt --file main.go
@@ -259,10 +274,15 @@ Examples:
   floop prompt --file main.go --tiered --token-budget 2000
   floop prompt --file main.go --json`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			file, _ := cmd.Flags().GetString("file")
 			task, _ := cmd.Flags().GetString("task")
 			env, _ := cmd.Flags().GetString("env")
+			custom, err := contextFlag(cmd)
+			if err != nil {
+				return err
+			}
 			format, _ := cmd.Flags().GetString("format")
 			maxTokens, _ := cmd.Flags().GetInt("max-tokens")
 			tokenBudget, _ := cmd.Flags().GetInt("token-budget")
@@ -276,14 +296,19 @@ Examples:
 
 			floopDir := filepath.Join(root, ".floop")
 			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
-				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
-						"error": ".floop not initialized",
-					})
-				} else {
-					fmt.Println("Not initialized. Run 'floop init' first.")
-				}
-				return nil
+				return errNotInitialized
+			}
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				cfg = config.Default()
+			}
+			profile, err := agentProfile(cmd, cfg)
+			if err != nil {
+				return err
+			}
+			if maxTokens == 0 {
+				maxTokens = profile.TokenBudget
 			}
 
 			// Load all behaviors from both local and global stores
@@ -291,13 +316,16 @@ Examples:
 			if err != nil {
 				return fmt.Errorf("failed to load behaviors: %w", err)
 			}
+			behaviors = profile.Filter(behaviors)
 
 			// Build context
 			ctxBuilder := activation.NewContextBuilder().
 				WithFile(file).
 				WithTask(task).
 				WithEnvironment(env).
-				WithRepoRoot(root)
+				WithRepoRoot(root).
+				WithGitDetection(cfg.Activation.GitContext).
+				WithCustomValues(custom)
 			ctx := ctxBuilder.Build()
 
 			// Evaluate which behaviors are active
@@ -325,13 +353,13 @@ Examples:
 			// Use tiered injection if requested
 			if tiered && maxTokens > 0 {
 				// Create tiered injection plan via bridge → ActivationTierMapper
-				results, behaviorMap := tiering.BehaviorsToResults(resolved.Active)
-				mapper := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig())
+				results, behaviorMap := tiering.BehaviorsToResultsWithConfig(resolved.Active, scorerConfig(root, cfg, profile))
+				mapper := tiering.NewActivationTierMapper(profile.TierConfig())
 				plan := mapper.MapResults(results, behaviorMap, maxTokens)
 				tieredCompiled := compiler.CompileTiered(plan)
 
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"context":              ctx,
 						"prompt":               tieredCompiled.Text,
 						"format":               tieredCompiled.Format,
@@ -345,11 +373,11 @@ Examples:
 					})
 				} else {
 					if plan.IncludedCount() == 0 {
-						fmt.Println("No active behaviors for this context.")
+						fmt.Fprintln(out, "No active behaviors for this context.")
 						return nil
 					}
 
-					fmt.Println(tieredCompiled.Text)
+					fmt.Fprintln(out, tieredCompiled.Text)
 
 					fmt.Fprintln(os.Stderr)
 					fmt.Fprintf(os.Stderr, "---\n")
@@ -378,7 +406,7 @@ Examples:
 				}
 
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"context":            ctx,
 						"prompt":             compiled.Text,
 						"format":             compiled.Format,
@@ -390,11 +418,11 @@ Examples:
 					})
 				} else {
 					if len(activeBehaviors) == 0 {
-						fmt.Println("No active behaviors for this context.")
+						fmt.Fprintln(out, "No active behaviors for this context.")
 						return nil
 					}
 
-					fmt.Println(compiled.Text)
+					fmt.Fprintln(out, compiled.Text)
 
 					fmt.Fprintln(os.Stderr)
 					fmt.Fprintf(os.Stderr, "---\n")
@@ -414,6 +442,7 @@ Examples:
 	cmd.Flags().String("file", "", "Current file path")
 	cmd.Flags().String("task", "", "Current task type")
 	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+	addContextFlag(cmd)
 	cmd.Flags().String("format", "markdown", "Output format (markdown, xml, plain)")
 	cmd.Flags().Int("max-tokens", 0, "Maximum tokens (0 = unlimited, deprecated: use --token-budget)")
 	cmd.Flags().Int("token-budget", 0, "Token budget for behavior injection (enables intelligent tiering)")
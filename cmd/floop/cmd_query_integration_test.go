@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/store"
@@ -64,12 +65,23 @@ func TestShowCmdWithBehavior(t *testing.T) {
 
 	rootCmd := newTestRootCmd()
 	rootCmd.AddCommand(newShowCmd())
-	rootCmd.SetOut(&bytes.Buffer{})
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
 	rootCmd.SetArgs([]string{"show", behaviorID, "--root", tmpDir})
 
 	if err := rootCmd.Execute(); err != nil {
 		t.Fatalf("show failed: %v", err)
 	}
+	for _, want := range []string{
+		"Base (extraction): 0.60",
+		"Approval: +0.00",
+		"Reinforcement: +0.00",
+		"Recalibration: +0.00",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("show output missing %q:\n%s", want, out.String())
+		}
+	}
 }
 
 func TestShowCmdNotFound(t *testing.T) {
@@ -80,10 +92,7 @@ func TestShowCmdNotFound(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"show", "nonexistent-id", "--root", tmpDir})
 
-	// Should not error (prints "not found" message)
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("show nonexistent failed: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestShowCmdJSON(t *testing.T) {
@@ -108,10 +117,7 @@ func TestShowCmdNotInitialized(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"show", "any-id", "--root", tmpDir})
 
-	// Should not error (prints "not initialized" message)
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("show not initialized failed: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
 }
 
 func TestWhyCmdWithBehavior(t *testing.T) {
@@ -148,9 +154,7 @@ func TestWhyCmdNotFound(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"why", "nonexistent-id", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("why nonexistent failed: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotFound)
 }
 
 func TestWhyCmdNotInitialized(t *testing.T) {
@@ -162,9 +166,7 @@ func TestWhyCmdNotInitialized(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"why", "any-id", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("why not initialized failed: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
 }
 
 func TestPromptCmdWithBehaviors(t *testing.T) {
@@ -241,9 +243,7 @@ func TestPromptCmdNotInitialized(t *testing.T) {
 	rootCmd.SetOut(&bytes.Buffer{})
 	rootCmd.SetArgs([]string{"prompt", "--file", "main.go", "--root", tmpDir})
 
-	if err := rootCmd.Execute(); err != nil {
-		t.Fatalf("prompt not initialized failed: %v", err)
-	}
+	wantErrorCode(t, rootCmd.Execute(), codeNotInitialized)
 }
 
 func TestPromptCmdXMLFormat(t *testing.T) {
@@ -0,0 +1,497 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// reviewWebhookTimeout bounds how long 'floop review remind' waits on the webhook.
+const reviewWebhookTimeout = 10 * time.Second
+
+func newReviewCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "review",
+		Short: "Track behaviors awaiting review",
+		Long: `Track behaviors the learning loop flagged for human review.
+
+Each flagged behavior records when review was requested. 'review list' shows
+how long each has waited; items older than review.sla are overdue.
+'review remind' reports overdue items (and posts them to review.webhook_url
+if set), and 'review escalate' applies review.escalate_action to items
+waiting longer than review.escalate_after.
+
+'review approve' accepts items and 'review reject' forgets them. With
+review.hold_pending set, flagged behaviors are held as pending and do not
+activate until approved.`,
+	}
+
+	cmd.AddCommand(
+		newReviewListCmd(),
+		newReviewRemindCmd(),
+		newReviewEscalateCmd(),
+		newReviewApproveCmd(),
+		newReviewRejectCmd(),
+	)
+
+	return cmd
+}
+
+func newReviewListCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List behaviors awaiting review with their age",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			overdueOnly, _ := cmd.Flags().GetBool("overdue")
+
+			cfg, items, err := loadReviewItems(cmd.Context(), root)
+			if err != nil {
+				return err
+			}
+			if overdueOnly {
+				items = overdueReviewItems(items)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"items":   items,
+					"count":   len(items),
+					"overdue": len(overdueReviewItems(items)),
+					"sla":     cfg.Review.SLA,
+				})
+			}
+
+			if len(items) == 0 {
+				fmt.Fprintln(out, "No behaviors awaiting review.")
+				return nil
+			}
+
+			fmt.Fprintf(out, "Behaviors awaiting review (%d):\n\n", len(items))
+			for i, item := range items {
+				status := ""
+				if item.EscalatedAt != nil {
+					status = "[ESCALATED] "
+				} else if item.Overdue {
+					status = "[OVERDUE] "
+				}
+				fmt.Fprintf(out, "%d. %s%s (%s)\n", i+1, status, item.Name, item.BehaviorID)
+				fmt.Fprintf(out, "   Waiting: %s\n", formatReviewAge(item.Age))
+				if item.Canonical != "" {
+					fmt.Fprintf(out, "   %s\n", item.Canonical)
+				}
+				for _, reason := range item.Reasons {
+					fmt.Fprintf(out, "   - %s\n", reason)
+				}
+				fmt.Fprintln(out)
+			}
+			if n := len(overdueReviewItems(items)); n > 0 {
+				fmt.Fprintf(out, "%d overdue (SLA %s).\n", n, cfg.Review.SLA)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("overdue", false, "Show only items past the review SLA")
+
+	return cmd
+}
+
+func newReviewRemindCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "remind",
+		Short: "Report overdue review items and notify the review webhook",
+		Long: `Report behaviors that have awaited review longer than review.sla.
+
+If review.webhook_url is set, overdue items are also POSTed to it as JSON.
+Intended to run from cron or a session hook.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			cfg, items, err := loadReviewItems(cmd.Context(), root)
+			if err != nil {
+				return err
+			}
+			overdue := overdueReviewItems(items)
+
+			webhookSent := false
+			if len(overdue) > 0 && cfg.Review.WebhookURL != "" {
+				ctx, cancel := context.WithTimeout(cmd.Context(), reviewWebhookTimeout)
+				defer cancel()
+				if err := review.Notify(ctx, http.DefaultClient, cfg.Review.WebhookURL, overdue, time.Now()); err != nil {
+					return fmt.Errorf("failed to send review reminder: %w", err)
+				}
+				webhookSent = true
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"overdue":      overdue,
+					"count":        len(overdue),
+					"webhook_sent": webhookSent,
+				})
+			}
+
+			if len(overdue) == 0 {
+				fmt.Fprintln(out, "No overdue reviews.")
+				return nil
+			}
+			fmt.Fprintf(out, "Reminder: %d behavior(s) awaiting review longer than %s:\n", len(overdue), cfg.Review.SLA)
+			for _, item := range overdue {
+				fmt.Fprintf(out, "  %s (%s) - waiting %s\n", item.Name, item.BehaviorID, formatReviewAge(item.Age))
+			}
+			if webhookSent {
+				fmt.Fprintln(out, "Webhook notified.")
+			}
+			return nil
+		},
+	}
+}
+
+func newReviewEscalateCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "escalate",
+		Short: "Escalate review items ignored past review.escalate_after",
+		Long: `Apply review.escalate_action to behaviors that have awaited review longer
+than review.escalate_after. Each item is escalated at most once.
+
+Actions:
+  downgrade   Halve the behavior's confidence; it stays in the review list
+  quarantine  Deprecate the behavior so it stops activating ('floop restore' undoes this)`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			policy, err := cfg.Review.Policy()
+			if err != nil {
+				return err
+			}
+			if policy.EscalateAfter == 0 || policy.Action == "" {
+				return fmt.Errorf("escalation disabled: set review.escalate_after and review.escalate_action")
+			}
+
+			graphStore, err := openReviewStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			ctx := cmd.Context()
+			now := time.Now()
+			items, err := review.Pending(ctx, graphStore, policy, now)
+			if err != nil {
+				return err
+			}
+
+			var escalated []review.Item
+			for _, item := range items {
+				if !item.EscalationDue {
+					continue
+				}
+				if !dryRun {
+					if err := review.Escalate(ctx, graphStore, item.BehaviorID, policy.Action, now); err != nil {
+						return fmt.Errorf("failed to escalate %s: %w", item.BehaviorID, err)
+					}
+				}
+				escalated = append(escalated, item)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"escalated": escalated,
+					"count":     len(escalated),
+					"action":    policy.Action,
+					"dry_run":   dryRun,
+				})
+			}
+
+			if len(escalated) == 0 {
+				fmt.Fprintln(out, "No review items due for escalation.")
+				return nil
+			}
+			verb := "Escalated"
+			if dryRun {
+				verb = "Would escalate"
+			}
+			fmt.Fprintf(out, "%s %d item(s) (%s):\n", verb, len(escalated), policy.Action)
+			for _, item := range escalated {
+				fmt.Fprintf(out, "  %s (%s) - waiting %s\n", item.Name, item.BehaviorID, formatReviewAge(item.Age))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would be escalated without changing anything")
+
+	return cmd
+}
+
+func newReviewApproveCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "approve [behavior-id...]",
+		Short: "Approve behaviors awaiting review",
+		Long: `Approve behaviors awaiting review. Pending behaviors become active, and
+the review request is cleared so the item leaves the review list.
+
+Examples:
+  floop review approve b-abc123
+  floop review approve b-abc123 b-def456
+  floop review approve --all --json`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			all, _ := cmd.Flags().GetBool("all")
+
+			graphStore, err := openReviewStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			ctx := cmd.Context()
+			items, err := selectReviewItems(ctx, graphStore, args, all)
+			if err != nil {
+				return err
+			}
+
+			loop := learning.NewLearningLoop(graphStore, nil)
+			for _, item := range items {
+				if err := loop.ApprovePending(ctx, item.BehaviorID); err != nil {
+					return fmt.Errorf("failed to approve %s: %w", item.BehaviorID, err)
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"approved": items,
+					"count":    len(items),
+				})
+			}
+
+			if len(items) == 0 {
+				fmt.Fprintln(out, "No behaviors awaiting review.")
+				return nil
+			}
+			fmt.Fprintf(out, "Approved %d behavior(s):\n", len(items))
+			for _, item := range items {
+				fmt.Fprintf(out, "  %s (%s)\n", item.Name, item.BehaviorID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "Approve every behavior awaiting review")
+
+	return cmd
+}
+
+func newReviewRejectCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "reject [behavior-id...]",
+		Short: "Reject behaviors awaiting review",
+		Long: `Reject behaviors awaiting review. Rejected behaviors are forgotten, so
+similar corrections will not recreate them; 'floop restore' undoes this.
+
+Examples:
+  floop review reject b-abc123 --reason "too specific"
+  floop review reject --all --yes`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			all, _ := cmd.Flags().GetBool("all")
+			reason, _ := cmd.Flags().GetString("reason")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			graphStore, err := openReviewStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			ctx := cmd.Context()
+			items, err := selectReviewItems(ctx, graphStore, args, all)
+			if err != nil {
+				return err
+			}
+			if len(items) == 0 {
+				if jsonOut {
+					return json.NewEncoder(out).Encode(map[string]interface{}{
+						"rejected": items,
+						"count":    0,
+					})
+				}
+				fmt.Fprintln(out, "No behaviors awaiting review.")
+				return nil
+			}
+
+			confirmed, err := confirmDestructive(config.OpForget, yes, jsonOut, func() {
+				fmt.Fprintf(out, "Reject and forget %d behavior(s):\n", len(items))
+				for _, item := range items {
+					fmt.Fprintf(out, "  %s (%s)\n", item.Name, item.BehaviorID)
+				}
+				if reason != "" {
+					fmt.Fprintf(out, "Reason: %s\n", reason)
+				}
+			})
+			if err != nil {
+				return err
+			}
+			if !confirmed {
+				fmt.Fprintln(out, "Cancelled.")
+				return nil
+			}
+
+			loop := learning.NewLearningLoop(graphStore, nil)
+			for _, item := range items {
+				if err := loop.RejectPending(ctx, item.BehaviorID, reason); err != nil {
+					return fmt.Errorf("failed to reject %s: %w", item.BehaviorID, err)
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"rejected": items,
+					"count":    len(items),
+					"reason":   reason,
+				})
+			}
+
+			fmt.Fprintf(out, "Rejected %d behavior(s):\n", len(items))
+			for _, item := range items {
+				fmt.Fprintf(out, "  %s (%s)\n", item.Name, item.BehaviorID)
+			}
+			fmt.Fprintln(out, "Use 'floop restore <id>' to undo.")
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("all", false, "Reject every behavior awaiting review")
+	cmd.Flags().String("reason", "", "Reason for rejecting (recorded as the forget reason)")
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// selectReviewItems returns the pending items named by ids, or all pending
+// items when all is set. Every id must be awaiting review.
+func selectReviewItems(ctx context.Context, s store.GraphStore, ids []string, all bool) ([]review.Item, error) {
+	if all && len(ids) > 0 {
+		return nil, fmt.Errorf("specify behavior IDs or --all, not both")
+	}
+	if !all && len(ids) == 0 {
+		return nil, fmt.Errorf("specify behavior IDs or --all")
+	}
+
+	items, err := review.Pending(ctx, s, review.Policy{}, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	if all {
+		if items == nil {
+			items = []review.Item{}
+		}
+		return items, nil
+	}
+
+	byID := make(map[string]review.Item, len(items))
+	for _, item := range items {
+		byID[item.BehaviorID] = item
+	}
+	selected := make([]review.Item, 0, len(ids))
+	for _, id := range ids {
+		item, ok := byID[id]
+		if !ok {
+			return nil, fmt.Errorf("behavior %s is not awaiting review", id)
+		}
+		selected = append(selected, item)
+	}
+	return selected, nil
+}
+
+// openReviewStore opens the local and global stores for review commands.
+func openReviewStore(root string) (*store.MultiGraphStore, error) {
+	if _, err := os.Stat(filepath.Join(root, ".floop")); os.IsNotExist(err) {
+		return nil, errNotInitialized
+	}
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open graph store: %w", err)
+	}
+	return graphStore, nil
+}
+
+// loadReviewItems loads the config and all behaviors awaiting review.
+func loadReviewItems(ctx context.Context, root string) (*config.FloopConfig, []review.Item, error) {
+	cfg, err := config.LoadForProject(root)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	policy, err := cfg.Review.Policy()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	graphStore, err := openReviewStore(root)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer graphStore.Close()
+
+	items, err := review.Pending(ctx, graphStore, policy, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	if items == nil {
+		items = []review.Item{}
+	}
+	return cfg, items, nil
+}
+
+// overdueReviewItems returns the items past the review SLA.
+func overdueReviewItems(items []review.Item) []review.Item {
+	overdue := []review.Item{}
+	for _, item := range items {
+		if item.Overdue {
+			overdue = append(overdue, item)
+		}
+	}
+	return overdue
+}
+
+// formatReviewAge renders a wait time as days and hours, e.g. "3d 4h".
+func formatReviewAge(d time.Duration) string {
+	if d < time.Hour {
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+	days := int(d.Hours()) / 24
+	hours := int(d.Hours()) % 24
+	if days == 0 {
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dd %dh", days, hours)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// setupReviewTest inits a project with one behavior that has awaited review
+// for ten days.
+func setupReviewTest(t *testing.T) string {
+	t.Helper()
+	tmpDir := setupDeinitTest(t)
+
+	gs, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer gs.Close()
+
+	_, err = gs.AddNodeToScope(context.Background(), store.Node{
+		ID:   "b-pending",
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "never-push-main",
+			"kind":    "constraint",
+			"content": map[string]interface{}{"canonical": "never push directly to main"},
+		},
+		Metadata: map[string]interface{}{
+			"confidence":           0.8,
+			review.MetaRequestedAt: time.Now().Add(-10 * 24 * time.Hour).Format(time.RFC3339),
+			review.MetaReasons:     []string{"Constraints require human review"},
+		},
+	}, store.ScopeLocal)
+	if err != nil {
+		t.Fatalf("failed to add behavior: %v", err)
+	}
+	return tmpDir
+}
+
+func TestReviewListCmd(t *testing.T) {
+	tmpDir := setupReviewTest(t)
+
+	out, err := runCmd(t, newReviewCmd(), "list", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("review list failed: %v", err)
+	}
+	for _, want := range []string{"[OVERDUE] never-push-main (b-pending)", "Waiting: 10d", "Constraints require human review"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	out, err = runCmd(t, newReviewCmd(), "list", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("review list --json failed: %v", err)
+	}
+	var result struct {
+		Count   int           `json:"count"`
+		Overdue int           `json:"overdue"`
+		Items   []review.Item `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Count != 1 || result.Overdue != 1 || result.Items[0].BehaviorID != "b-pending" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestReviewEscalateCmd(t *testing.T) {
+	tmpDir := setupReviewTest(t)
+
+	if _, err := runCmd(t, newReviewCmd(), "escalate", "--root", tmpDir); err == nil {
+		t.Fatal("expected error when review.escalate_after is unset")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	if err := setConfigValue(cfg, "review.escalate_after", "9d"); err != nil {
+		t.Fatalf("set escalate_after: %v", err)
+	}
+	if err := setConfigValue(cfg, "review.escalate_action", "quarantine"); err != nil {
+		t.Fatalf("set escalate_action: %v", err)
+	}
+	if err := saveConfig(cfg); err != nil {
+		t.Fatalf("save config: %v", err)
+	}
+
+	out, err := runCmd(t, newReviewCmd(), "escalate", "--dry-run", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("review escalate --dry-run failed: %v", err)
+	}
+	if !strings.Contains(out, "Would escalate 1 item(s) (quarantine)") {
+		t.Errorf("unexpected dry-run output:\n%s", out)
+	}
+
+	if _, err := runCmd(t, newReviewCmd(), "escalate", "--root", tmpDir); err != nil {
+		t.Fatalf("review escalate failed: %v", err)
+	}
+
+	gs, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer gs.Close()
+	node, err := gs.GetNode(context.Background(), "b-pending")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode: %v, %v", node, err)
+	}
+	if node.Kind != store.NodeKindDeprecated {
+		t.Errorf("Kind = %s, want deprecated", node.Kind)
+	}
+}
+
+func TestReviewApproveRejectCmd(t *testing.T) {
+	tmpDir := setupReviewTest(t)
+
+	gs, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	_, err = gs.AddNodeToScope(context.Background(), store.Node{
+		ID:      "b-held",
+		Kind:    store.NodeKindPending,
+		Content: map[string]interface{}{"name": "held-behavior", "kind": "directive"},
+		Metadata: map[string]interface{}{
+			"confidence":           0.5,
+			review.MetaRequestedAt: time.Now().Format(time.RFC3339),
+		},
+	}, store.ScopeLocal)
+	gs.Close()
+	if err != nil {
+		t.Fatalf("failed to add pending behavior: %v", err)
+	}
+
+	if _, err := runCmd(t, newReviewCmd(), "approve", "--root", tmpDir); err == nil {
+		t.Error("approve without IDs or --all should fail")
+	}
+	if _, err := runCmd(t, newReviewCmd(), "approve", "b-missing", "--root", tmpDir); err == nil {
+		t.Error("approve of a behavior not awaiting review should fail")
+	}
+
+	out, err := runCmd(t, newReviewCmd(), "approve", "b-held", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("review approve failed: %v", err)
+	}
+	var approved struct {
+		Count    int           `json:"count"`
+		Approved []review.Item `json:"approved"`
+	}
+	if err := json.Unmarshal([]byte(out), &approved); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if approved.Count != 1 || !approved.Approved[0].Held {
+		t.Errorf("unexpected approve result: %+v", approved)
+	}
+
+	out, err = runCmd(t, newReviewCmd(), "reject", "--all", "--reason", "too broad", "--yes", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("review reject failed: %v", err)
+	}
+	if !strings.Contains(out, "Rejected 1 behavior(s)") || !strings.Contains(out, "b-pending") {
+		t.Errorf("unexpected reject output:\n%s", out)
+	}
+
+	gs, err = store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("failed to open store: %v", err)
+	}
+	defer gs.Close()
+	ctx := context.Background()
+	held, err := gs.GetNode(ctx, "b-held")
+	if err != nil || held == nil {
+		t.Fatalf("GetNode(b-held): %v, %v", held, err)
+	}
+	if held.Kind != store.NodeKindBehavior {
+		t.Errorf("approved Kind = %s, want behavior", held.Kind)
+	}
+	if _, ok := held.Metadata[review.MetaRequestedAt]; ok {
+		t.Error("approved behavior should no longer await review")
+	}
+	rejected, err := gs.GetNode(ctx, "b-pending")
+	if err != nil || rejected == nil {
+		t.Fatalf("GetNode(b-pending): %v, %v", rejected, err)
+	}
+	if rejected.Kind != store.NodeKindForgotten || rejected.Metadata["forget_reason"] != "too broad" {
+		t.Errorf("rejected Kind = %s, reason = %v", rejected.Kind, rejected.Metadata["forget_reason"])
+	}
+
+	out, err = runCmd(t, newReviewCmd(), "list", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("review list failed: %v", err)
+	}
+	if !strings.Contains(out, "No behaviors awaiting review.") {
+		t.Errorf("review list after approve/reject:\n%s", out)
+	}
+}
+
+func TestFormatReviewAge(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{12 * time.Minute, "12m"},
+		{5 * time.Hour, "5h"},
+		{76 * time.Hour, "3d 4h"},
+	}
+	for _, tt := range tests {
+		if got := formatReviewAge(tt.d); got != tt.want {
+			t.Errorf("formatReviewAge(%v) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/nvandessel/floop/internal/schema"
+	"github.com/spf13/cobra"
+)
+
+func newSchemaCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "schema",
+		Short: "Publish JSON Schemas for integration payloads",
+		Long: `Publish JSON Schemas for the payloads third-party agents exchange with
+floop: the activation context snapshot and the floop_active and floop_learn
+request and response bodies.
+
+The schemas are generated from floop's own types, so they always match the
+running version.`,
+	}
+
+	cmd.AddCommand(newSchemaDumpCmd())
+
+	return cmd
+}
+
+func newSchemaDumpCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "dump [name...]",
+		Short: "Print or write JSON Schemas",
+		Long: fmt.Sprintf(`Print or write JSON Schemas (draft 2020-12).
+
+Available schemas: %s.
+
+With one name, that schema is printed. With several names or none, an object
+mapping each name to its schema is printed. With --dir, each schema is
+written to <dir>/<name>.schema.json instead.
+
+Examples:
+  floop schema dump context-snapshot
+  floop schema dump
+  floop schema dump --dir ./schemas`, strings.Join(schema.Names(), ", ")),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			dir, _ := cmd.Flags().GetString("dir")
+
+			names := args
+			if len(names) == 0 {
+				names = schema.Names()
+			}
+			schemas := make([]*jsonschema.Schema, len(names))
+			for i, name := range names {
+				def, ok := schema.Lookup(name)
+				if !ok {
+					return fmt.Errorf("unknown schema: %s (valid: %s)", name, strings.Join(schema.Names(), ", "))
+				}
+				s, err := schema.Generate(def)
+				if err != nil {
+					return err
+				}
+				schemas[i] = s
+			}
+
+			if dir != "" {
+				if err := os.MkdirAll(dir, 0755); err != nil {
+					return fmt.Errorf("failed to create %s: %w", dir, err)
+				}
+				for i, name := range names {
+					data, err := schema.Marshal(schemas[i])
+					if err != nil {
+						return err
+					}
+					path := filepath.Join(dir, name+".schema.json")
+					if err := os.WriteFile(path, data, 0644); err != nil {
+						return fmt.Errorf("failed to write %s: %w", path, err)
+					}
+					fmt.Fprintf(out, "Wrote %s\n", path)
+				}
+				return nil
+			}
+
+			// Schemas are JSON, so they are written even with --quiet.
+			if len(schemas) == 1 {
+				data, err := schema.Marshal(schemas[0])
+				if err != nil {
+					return err
+				}
+				_, err = cmd.OutOrStdout().Write(data)
+				return err
+			}
+
+			bundle := &jsonschema.Schema{Schema: schema.Draft, Defs: make(map[string]*jsonschema.Schema, len(names))}
+			for i, name := range names {
+				bundle.Defs[name] = schemas[i]
+			}
+			data, err := schema.Marshal(bundle)
+			if err != nil {
+				return err
+			}
+			_, err = cmd.OutOrStdout().Write(data)
+			return err
+		},
+	}
+
+	cmd.Flags().String("dir", "", "Write each schema to <dir>/<name>.schema.json")
+
+	return cmd
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/schema"
+)
+
+func TestSchemaDumpCmd(t *testing.T) {
+	out, err := runCmd(t, newSchemaCmd(), "dump", "context-snapshot")
+	if err != nil {
+		t.Fatalf("schema dump failed: %v", err)
+	}
+	var single map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &single); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if single["title"] != "ContextSnapshot" {
+		t.Errorf("title = %v, want ContextSnapshot", single["title"])
+	}
+
+	out, err = runCmd(t, newSchemaCmd(), "dump")
+	if err != nil {
+		t.Fatalf("schema dump (all) failed: %v", err)
+	}
+	var bundle struct {
+		Defs map[string]interface{} `json:"$defs"`
+	}
+	if err := json.Unmarshal([]byte(out), &bundle); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(bundle.Defs) != len(schema.Definitions) {
+		t.Errorf("bundle has %d schemas, want %d", len(bundle.Defs), len(schema.Definitions))
+	}
+
+	dir := filepath.Join(t.TempDir(), "schemas")
+	if _, err := runCmd(t, newSchemaCmd(), "dump", "--dir", dir); err != nil {
+		t.Fatalf("schema dump --dir failed: %v", err)
+	}
+	for _, name := range schema.Names() {
+		if _, err := os.Stat(filepath.Join(dir, name+".schema.json")); err != nil {
+			t.Errorf("missing %s: %v", name, err)
+		}
+	}
+
+	_, err = runCmd(t, newSchemaCmd(), "dump", "nope")
+	if err == nil || !strings.Contains(err.Error(), "unknown schema") {
+		t.Errorf("expected unknown schema error, got %v", err)
+	}
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newPromoteCmd() *cobra.Command {
+	return newRelocateCmd("promote", constants.ScopeGlobal,
+		"Move a behavior from the local store to the global store",
+		`Move a behavior from this project's store (./.floop) to the global
+store (~/.floop) so it applies in every project.`)
+}
+
+func newDemoteCmd() *cobra.Command {
+	return newRelocateCmd("demote", constants.ScopeLocal,
+		"Move a behavior from the global store to the local store",
+		`Move a behavior from the global store (~/.floop) to this project's
+store (./.floop) so it only applies here.`)
+}
+
+// newRelocateCmd builds promote and demote, which differ only in direction.
+func newRelocateCmd(use string, to constants.Scope, short, long string) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   use + " <behavior-id>",
+		Short: short,
+		Long: long + `
+
+The behavior keeps its content, confidence, stats, provenance, and
+embedding. Its edges follow it: edges between two behaviors in the same
+store live in that store, and all others in the global store.
+
+If the destination already has a different behavior with the same ID,
+the move is refused; pass --as to move it under a new ID, which also
+rewrites edges and requires/overrides/conflicts references. If the
+destination already has the same behavior (same content), the two are
+merged and only the destination copy is kept.
+
+Examples:
+  floop ` + use + ` behavior-3f2a9c1d4e5b
+  floop ` + use + ` behavior-3f2a9c1d4e5b --as behavior-3f2a9c1d4e5b-` + string(to) + `
+  floop ` + use + ` behavior-3f2a9c1d4e5b --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			newID, _ := cmd.Flags().GetString("as")
+			id := args[0]
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return errNotInitialized
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			ctx := context.Background()
+			result, err := graphStore.Relocate(ctx, id, to, store.RelocateOptions{NewID: newID})
+			if err != nil {
+				var collision *store.IDCollisionError
+				if errors.As(err, &collision) {
+					return fmt.Errorf("%w; use --as <new-id> to %s it under another ID", err, use)
+				}
+				return err
+			}
+
+			if err := graphStore.Sync(ctx); err != nil {
+				return fmt.Errorf("failed to sync changes: %w", err)
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(result)
+			}
+
+			switch {
+			case result.Merged:
+				fmt.Fprintf(out, "Merged %s into %s in the %s store.\n", result.PreviousID, result.ID, result.To)
+			case result.ID != result.PreviousID:
+				fmt.Fprintf(out, "Moved %s to the %s store as %s.\n", result.PreviousID, result.To, result.ID)
+			default:
+				fmt.Fprintf(out, "Moved %s to the %s store.\n", result.ID, result.To)
+			}
+			if result.Edges > 0 {
+				fmt.Fprintf(out, "  %d edges re-homed\n", result.Edges)
+			}
+			if result.References > 0 {
+				fmt.Fprintf(out, "  %d behaviors updated to reference %s\n", result.References, result.ID)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("as", "", "Move the behavior under this ID (for ID collisions)")
+
+	return cmd
+}
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestPromoteDemoteCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	ctx := context.Background()
+
+	out, err := runCmd(t, newDemoteCmd(), behaviorID, "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("demote failed: %v", err)
+	}
+	if !strings.Contains(out, "Moved "+behaviorID+" to the local store.") {
+		t.Errorf("unexpected demote output:\n%s", out)
+	}
+
+	local, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, _ := local.GetNode(ctx, behaviorID)
+	local.Close()
+	if node == nil || node.Metadata["scope"] != "local" {
+		t.Fatalf("local node = %+v, want demoted behavior", node)
+	}
+
+	if _, err := runCmd(t, newDemoteCmd(), behaviorID, "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "already in the local store") {
+		t.Errorf("expected already-local error, got %v", err)
+	}
+
+	out, err = runCmd(t, newPromoteCmd(), behaviorID, "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("promote failed: %v", err)
+	}
+	var result store.RelocateResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.ID != behaviorID || result.From != "local" || result.To != "global" {
+		t.Errorf("promote result = %+v", result)
+	}
+
+	global, err := store.NewSQLiteGraphStore(filepath.Join(tmpDir, "home"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer global.Close()
+	if node, _ := global.GetNode(ctx, behaviorID); node == nil {
+		t.Error("behavior not back in the global store")
+	}
+}
+
+func TestPromoteCmd_Collision(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	ctx := context.Background()
+
+	local, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := local.AddNode(ctx, store.Node{
+		ID:   behaviorID,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "local-twin",
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "Prefer zerolog in this project"},
+		},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	local.Close()
+
+	if _, err := runCmd(t, newPromoteCmd(), behaviorID, "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "--as") {
+		t.Fatalf("expected collision error suggesting --as, got %v", err)
+	}
+
+	out, err := runCmd(t, newPromoteCmd(), behaviorID, "--as", behaviorID+"-zerolog", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("promote --as failed: %v", err)
+	}
+	if !strings.Contains(out, "to the global store as "+behaviorID+"-zerolog") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+}
+
+func TestPromoteCmd_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runCmd(t, newPromoteCmd(), "x", "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "not initialized") {
+		t.Errorf("expected not initialized error, got %v", err)
+	}
+	if _, err := runCmd(t, newPromoteCmd(), "--root", tmpDir); err == nil {
+		t.Error("expected error without a behavior ID")
+	}
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
+	"github.com/nvandessel/floop/internal/vectorsearch"
+	"github.com/spf13/cobra"
+)
+
+// Search modes reported by 'floop search'.
+const (
+	searchModeSemantic = "semantic"
+	searchModeKeyword  = "keyword"
+)
+
+// searchResult is one behavior ranked by 'floop search'.
+type searchResult struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Kind    string  `json:"kind"`
+	Score   float64 `json:"score"`
+	Snippet string  `json:"snippet"`
+	Command string  `json:"command"`
+}
+
+func newSearchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "search <query>",
+		Short: "Find behaviors by meaning",
+		Long: `Rank active behaviors by how closely they match a natural-language query.
+
+When an embedding model is available (see 'floop index rebuild') and
+behaviors have been embedded, the query is embedded and matched against the
+vector index by cosine similarity. Otherwise, or with --keyword, behaviors
+are ranked by a full-text search (BM25) of their name, text, and tags, where
+words match their variants ("wrapping" finds "wrap") and the last word
+matches as a prefix. Keyword scores are relative to the best match.
+
+Examples:
+  floop search "error wrapping conventions"
+  floop search "how should I log" --limit 3
+  floop search testing --keyword --json`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			limit, _ := cmd.Flags().GetInt("limit")
+			keywordOnly, _ := cmd.Flags().GetBool("keyword")
+			query := args[0]
+			ctx := cmd.Context()
+
+			if limit < 1 {
+				return fmt.Errorf("--limit must be at least 1")
+			}
+			if strings.TrimSpace(query) == "" {
+				return fmt.Errorf("query must not be empty")
+			}
+
+			var behaviors map[string]models.Behavior
+			var results []searchResult
+			mode := searchModeKeyword
+			fallback := ""
+
+			scope, ok := availableScope(root)
+			if ok {
+				graphStore, err := openStoreWithScope(root, scope)
+				if err != nil {
+					return err
+				}
+				defer graphStore.Close()
+
+				nodes, err := graphStore.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+				if err != nil {
+					return fmt.Errorf("failed to query behaviors: %w", err)
+				}
+				behaviors = make(map[string]models.Behavior, len(nodes))
+				for _, node := range nodes {
+					behaviors[node.ID] = models.NodeToBehavior(node)
+				}
+
+				if keywordOnly {
+					fallback = "--keyword"
+				} else {
+					var hits []vectorindex.SearchResult
+					hits, fallback = semanticSearch(ctx, graphStore, root, scope, query, behaviors)
+					if fallback == "" {
+						mode = searchModeSemantic
+						for _, h := range hits {
+							results = append(results, newSearchResult(behaviors[h.BehaviorID], h.Score, query))
+						}
+					}
+				}
+				if mode == searchModeKeyword {
+					results, err = keywordSearch(ctx, graphStore, query, behaviors)
+					if err != nil {
+						return err
+					}
+				}
+			}
+			if len(results) > limit {
+				results = results[:limit]
+			}
+
+			if jsonOut {
+				if results == nil {
+					results = []searchResult{}
+				}
+				resp := map[string]interface{}{
+					"query":   query,
+					"mode":    mode,
+					"results": results,
+					"count":   len(results),
+				}
+				if fallback != "" {
+					resp["fallback_reason"] = fallback
+				}
+				return json.NewEncoder(out).Encode(resp)
+			}
+
+			if fallback != "" && fallback != "--keyword" {
+				out.Verbosef("Keyword search (%s)\n", fallback)
+			}
+			if len(results) == 0 {
+				fmt.Fprintf(out, "No behaviors match %q.\n", query)
+				return nil
+			}
+			fmt.Fprintf(out, "%d %s match(es) for %q:\n\n", len(results), mode, query)
+			for i, r := range results {
+				fmt.Fprintf(out, "%2d. %.3f  %s", i+1, r.Score, r.Name)
+				if r.Kind != "" {
+					fmt.Fprintf(out, " [%s]", r.Kind)
+				}
+				fmt.Fprintln(out)
+				fmt.Fprintf(out, "    %s\n", r.Snippet)
+				fmt.Fprintf(out, "    -> %s\n", r.Command)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Int("limit", 10, "Maximum number of results")
+	cmd.Flags().Bool("keyword", false, "Use full-text keyword search even when embeddings are available")
+
+	return cmd
+}
+
+// semanticSearch ranks behaviors by cosine similarity between the embedded
+// query and their stored embeddings, using the project's vector index when
+// one is on disk. It returns a reason instead of results when semantic
+// search is unavailable, so the caller can fall back to keywords.
+func semanticSearch(ctx context.Context, gs store.GraphStore, root string, scope constants.Scope, query string, behaviors map[string]models.Behavior) ([]vectorindex.SearchResult, string) {
+	es, ok := gs.(store.EmbeddingStore)
+	if !ok {
+		return nil, "store has no embeddings"
+	}
+	cfg, err := config.LoadForProject(root)
+	if err != nil {
+		cfg = config.Default()
+	}
+	embedder, localClient := vectorsearch.NewEmbedderFromConfig(cfg)
+	if embedder == nil {
+		return nil, "no embedding model available"
+	}
+	if localClient != nil {
+		defer localClient.Close()
+	}
+
+	embeddings, err := es.GetAllEmbeddings(ctx)
+	if err != nil {
+		return nil, fmt.Sprintf("loading embeddings: %v", err)
+	}
+	if len(embeddings) == 0 {
+		return nil, "no behaviors have embeddings yet (run 'floop index rebuild')"
+	}
+
+	queryVec, err := embedder.EmbedQuery(ctx, query)
+	if err != nil {
+		return nil, fmt.Sprintf("embedding query: %v", err)
+	}
+
+	var idx vectorindex.VectorIndex
+	vectorDir := filepath.Join(root, ".floop", "vectors")
+	if _, err := os.Stat(vectorDir); err == nil && scope != constants.ScopeGlobal {
+		if lance, err := vectorindex.NewLanceDBIndex(vectorindex.LanceDBConfig{Dir: vectorDir, Dims: len(queryVec)}); err == nil {
+			defer lance.Close()
+			// A stale index (behaviors learned since the MCP server last synced it) is skipped
+			if lance.Len() >= len(embeddings) {
+				idx = lance
+			}
+		}
+	}
+	if idx == nil {
+		bf := vectorindex.NewBruteForceIndex()
+		for _, emb := range embeddings {
+			if len(emb.Embedding) == len(queryVec) {
+				_ = bf.Add(ctx, emb.BehaviorID, emb.Embedding)
+			}
+		}
+		idx = bf
+	}
+
+	// Search everything: the index may hold vectors of inactive behaviors.
+	hits, err := idx.Search(ctx, queryVec, idx.Len())
+	if err != nil {
+		return nil, fmt.Sprintf("searching vector index: %v", err)
+	}
+	var live []vectorindex.SearchResult
+	for _, h := range hits {
+		if _, ok := behaviors[h.BehaviorID]; ok {
+			live = append(live, h)
+		}
+	}
+	if len(live) == 0 {
+		return nil, "no embeddings match the model's dimension (run 'floop index rebuild')"
+	}
+	return live, ""
+}
+
+// keywordSearch ranks behaviors with the store's full-text index. Stores
+// without one fall back to the Jaccard similarity between the query's words
+// and those of each behavior's name, text, and tags.
+func keywordSearch(ctx context.Context, gs store.GraphStore, query string, behaviors map[string]models.Behavior) ([]searchResult, error) {
+	if ts, ok := gs.(store.TextSearcher); ok {
+		matches, err := ts.SearchText(ctx, query, false, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search behaviors: %w", err)
+		}
+		// BM25 scores depend on the corpus, so report them relative to the best match
+		var results []searchResult
+		for _, m := range matches {
+			if b, ok := behaviors[m.BehaviorID]; ok {
+				results = append(results, newSearchResult(b, m.Score/matches[0].Score, query))
+			}
+		}
+		return results, nil
+	}
+
+	var results []searchResult
+	for _, b := range behaviors {
+		text := strings.Join([]string{b.Name, b.Content.Canonical, b.Content.Summary, strings.Join(b.Content.Tags, " ")}, " ")
+		if score := similarity.ComputeContentSimilarity(query, text); score > 0 {
+			results = append(results, newSearchResult(b, score, query))
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Score != results[j].Score {
+			return results[i].Score > results[j].Score
+		}
+		return results[i].ID < results[j].ID
+	})
+	return results, nil
+}
+
+// newSearchResult builds the result for b, with a snippet of its text
+// around the first query word it contains.
+func newSearchResult(b models.Behavior, score float64, query string) searchResult {
+	var words []string
+	for _, w := range similarity.Tokenize(query) {
+		words = append(words, regexp.QuoteMeta(w))
+	}
+	re := regexp.MustCompile(`(?i)\b(` + strings.Join(words, "|") + `)\b`)
+
+	text := b.Content.Canonical
+	if text == "" {
+		text = b.Content.Summary
+	}
+	return searchResult{
+		ID:      b.ID,
+		Name:    b.Name,
+		Kind:    string(b.Kind),
+		Score:   score,
+		Snippet: grepSnippet(re, text),
+		Command: "floop show " + b.ID,
+	}
+}
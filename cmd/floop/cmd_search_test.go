@@ -0,0 +1,111 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+type searchOutput struct {
+	Mode           string         `json:"mode"`
+	Results        []searchResult `json:"results"`
+	Count          int            `json:"count"`
+	FallbackReason string         `json:"fallback_reason"`
+}
+
+func TestSearchCmd(t *testing.T) {
+	tmpDir, slogID := setupQueryTest(t)
+
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrap := models.Behavior{ID: "wrap-errors", Name: "wrap-errors", Kind: models.BehaviorKindDirective, Confidence: 0.8,
+		Content: models.BehaviorContent{Canonical: "Wrap errors with context using fmt.Errorf and %w"}}
+	if _, err := s.AddNode(context.Background(), models.BehaviorToNode(&wrap)); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+
+	// Without an embedding model, search falls back to keywords
+	out, err := runCmd(t, newSearchCmd(), "how to wrap errors", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	var result searchOutput
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Mode != searchModeKeyword || result.FallbackReason == "" {
+		t.Errorf("mode = %q (%q), want keyword fallback", result.Mode, result.FallbackReason)
+	}
+	if result.Count != 1 || result.Results[0].ID != "wrap-errors" || result.Results[0].Command != "floop show wrap-errors" {
+		t.Errorf("results = %+v, want wrap-errors only", result.Results)
+	}
+
+	// With an embedding server, the query is matched by meaning:
+	// "diagnostics" shares no word with the slog behavior but embeds close to it
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Input []string `json:"input"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var data []string
+		for i, text := range req.Input {
+			vec := "[0,1]"
+			if strings.Contains(text, "log") || strings.Contains(text, "diagnostics") {
+				vec = "[1,0.1]"
+			}
+			data = append(data, fmt.Sprintf(`{"index":%d,"embedding":%s}`, i, vec))
+		}
+		fmt.Fprintf(w, `{"data":[%s]}`, strings.Join(data, ","))
+	}))
+	defer ts.Close()
+	configYAML := fmt.Sprintf("embedding:\n  backend: server\n  url: %s\n", ts.URL)
+	if err := os.WriteFile(filepath.Join(tmpDir, "home", ".floop", "config.yaml"), []byte(configYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = runCmd(t, newSearchCmd(), "where should diagnostics be written", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("search before rebuild: %v", err)
+	}
+	if !strings.Contains(out, "No behaviors match") {
+		t.Errorf("expected no keyword matches before embedding:\n%s", out)
+	}
+
+	if _, err := runCmd(t, newIndexCmd(), "rebuild", "--root", tmpDir); err != nil {
+		t.Fatalf("index rebuild: %v", err)
+	}
+	out, err = runCmd(t, newSearchCmd(), "where should diagnostics be written", "--json", "--limit", "1", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("search: %v", err)
+	}
+	result = searchOutput{}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Mode != searchModeSemantic || result.Count != 1 || result.Results[0].ID != slogID || result.Results[0].Score < 0.99 {
+		t.Errorf("result = %+v, want the slog behavior by semantic match", result)
+	}
+
+	out, err = runCmd(t, newSearchCmd(), "where should diagnostics be written", "--keyword", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("search --keyword: %v", err)
+	}
+	if !strings.Contains(out, "No behaviors match") {
+		t.Errorf("--keyword should not use embeddings:\n%s", out)
+	}
+}
@@ -0,0 +1,193 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/session"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newSessionCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "session",
+		Short: "Group corrections, activations, and feedback into a work session",
+		Long: `Track a work session in the project's graph.
+
+'session start' creates a session node and marks it active in .floop/session.
+Until 'session end', corrections captured with 'floop learn' or floop_learn,
+behaviors activated through floop_active, and floop_feedback signals are
+counted against the session node.
+
+Behavior stats also record how many distinct sessions activated each
+behavior. Ranking uses that count instead of raw activations, so a behavior
+activated many times within one session is not boosted over and over. When
+no session is active, each MCP server process counts as its own session.
+
+Examples:
+  floop session start --label "auth refactor"
+  floop session status
+  floop session end`,
+	}
+
+	cmd.AddCommand(
+		newSessionStartCmd(),
+		newSessionEndCmd(),
+		newSessionStatusCmd(),
+	)
+
+	return cmd
+}
+
+func newSessionStartCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "Start a tracked session",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			label, _ := cmd.Flags().GetString("label")
+
+			graphStore, err := openSessionStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			rec, err := session.Start(cmd.Context(), graphStore, root, label, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status":  "started",
+					"session": rec,
+				})
+			}
+			fmt.Fprintf(out, "Started session %s\n", rec.ID)
+			if rec.Label != "" {
+				fmt.Fprintf(out, "  Label: %s\n", rec.Label)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("label", "", "Short description of the session")
+
+	return cmd
+}
+
+func newSessionEndCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "end",
+		Short: "End the active session and print its summary",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			graphStore, err := openSessionStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			rec, err := session.End(cmd.Context(), graphStore, root, time.Now())
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status":  "ended",
+					"session": rec,
+				})
+			}
+			fmt.Fprintf(out, "Ended session %s\n", rec.ID)
+			printSessionSummary(out, rec)
+			return nil
+		},
+	}
+}
+
+func newSessionStatusCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "status",
+		Short: "Show the active session",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+
+			id := session.Current(root)
+			if id == "" {
+				if jsonOut {
+					return json.NewEncoder(out).Encode(map[string]interface{}{
+						"active": false,
+					})
+				}
+				fmt.Fprintln(out, "No active session.")
+				return nil
+			}
+
+			graphStore, err := openSessionStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			rec, err := session.Get(cmd.Context(), graphStore, id)
+			if err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"active":  true,
+					"session": rec,
+				})
+			}
+			fmt.Fprintf(out, "Active session %s\n", rec.ID)
+			printSessionSummary(out, rec)
+			return nil
+		},
+	}
+}
+
+// openSessionStore opens the project's graph store, which holds session nodes.
+func openSessionStore(root string) (*store.MultiGraphStore, error) {
+	if _, err := os.Stat(filepath.Join(root, ".floop")); os.IsNotExist(err) {
+		return nil, errNotInitialized
+	}
+	graphStore, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open graph store: %w", err)
+	}
+	return graphStore, nil
+}
+
+// printSessionSummary writes the session's label, duration, and counters.
+func printSessionSummary(out io.Writer, rec *session.Record) {
+	if rec.Label != "" {
+		fmt.Fprintf(out, "  Label:       %s\n", rec.Label)
+	}
+	end := time.Now()
+	if rec.EndedAt != nil {
+		end = *rec.EndedAt
+	}
+	fmt.Fprintf(out, "  Duration:    %s\n", end.Sub(rec.StartedAt).Round(time.Second))
+	fmt.Fprintf(out, "  Corrections: %d\n", rec.Corrections)
+	fmt.Fprintf(out, "  Activations: %d\n", rec.Activations)
+	fmt.Fprintf(out, "  Feedback:    %d\n", rec.Feedback)
+	fmt.Fprintf(out, "  Behaviors:   %d\n", len(rec.BehaviorIDs))
+}
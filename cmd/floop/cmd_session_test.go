@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestSessionCmdLifecycle(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	out, err := runCmd(t, newSessionCmd(), "status", "--root", tmpDir)
+	if err != nil || !strings.Contains(out, "No active session") {
+		t.Fatalf("status before start = %q, %v", out, err)
+	}
+
+	out, err = runCmd(t, newSessionCmd(), "start", "--label", "refactor", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("session start failed: %v", err)
+	}
+	if !strings.Contains(out, "Started session session-") {
+		t.Errorf("start output = %q", out)
+	}
+	if _, err := runCmd(t, newSessionCmd(), "start", "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "already active") {
+		t.Errorf("second start error = %v, want already active", err)
+	}
+
+	// Corrections captured while the session is open are grouped under it.
+	if _, err := runCmd(t, newLearnCmd(), "--right", "run go vet before committing", "--root", tmpDir); err != nil {
+		t.Fatalf("learn failed: %v", err)
+	}
+
+	out, err = runCmd(t, newSessionCmd(), "end", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("session end failed: %v", err)
+	}
+	var ended struct {
+		Status  string `json:"status"`
+		Session struct {
+			Label         string   `json:"label"`
+			EndedAt       string   `json:"ended_at"`
+			Corrections   int      `json:"corrections"`
+			CorrectionIDs []string `json:"correction_ids"`
+		} `json:"session"`
+	}
+	if err := json.Unmarshal([]byte(out), &ended); err != nil {
+		t.Fatalf("invalid JSON %q: %v", out, err)
+	}
+	if ended.Status != "ended" || ended.Session.Label != "refactor" || ended.Session.EndedAt == "" {
+		t.Errorf("end = %+v", ended)
+	}
+	if ended.Session.Corrections != 1 || len(ended.Session.CorrectionIDs) != 1 {
+		t.Errorf("corrections = %d %v, want 1", ended.Session.Corrections, ended.Session.CorrectionIDs)
+	}
+
+	if _, err := runCmd(t, newSessionCmd(), "end", "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "no active session") {
+		t.Errorf("end without session error = %v", err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
+	"github.com/spf13/cobra"
+)
+
+func newSnoozeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snooze <behavior-id>",
+		Short: "Temporarily stop a behavior from activating",
+		Long: `Snooze a behavior so it does not activate for a while.
+
+Unlike 'floop forget' or 'floop deprecate', the behavior stays active in
+the store and wakes up on its own when the snooze ends. Use --off to wake
+it early, and 'floop list --snoozed' to review snoozed behaviors.
+
+Examples:
+  floop snooze b-1706000000000000000 --for 7d
+  floop snooze b-1706000000000000000 --until 2026-03-01 --reason "freeze week"
+  floop snooze b-1706000000000000000 --off`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			forFlag, _ := cmd.Flags().GetString("for")
+			untilFlag, _ := cmd.Flags().GetString("until")
+			off, _ := cmd.Flags().GetBool("off")
+			reason, _ := cmd.Flags().GetString("reason")
+			id := args[0]
+
+			set := 0
+			for _, given := range []bool{forFlag != "", untilFlag != "", off} {
+				if given {
+					set++
+				}
+			}
+			if set != 1 {
+				return fmt.Errorf("specify exactly one of --for, --until, or --off")
+			}
+
+			now := time.Now()
+			var until time.Time
+			switch {
+			case forFlag != "":
+				d, err := utils.ParseDuration(forFlag)
+				if err != nil || d <= 0 {
+					return fmt.Errorf("invalid --for %q: use a duration such as 7d", forFlag)
+				}
+				until = now.Add(d)
+			case untilFlag != "":
+				t, err := parseExpiry(untilFlag, now)
+				if err != nil {
+					return fmt.Errorf("invalid --until %q: use a date (2026-01-31) or an RFC3339 time", untilFlag)
+				}
+				if !t.After(now) {
+					return fmt.Errorf("--until %s is not in the future", untilFlag)
+				}
+				until = t
+			}
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			ctx := context.Background()
+
+			node, err := graphStore.GetNode(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get behavior: %w", err)
+			}
+			if node == nil {
+				return fmt.Errorf("behavior not found: %s", id)
+			}
+			if node.Kind != store.NodeKindBehavior {
+				return fmt.Errorf("not an active behavior (current kind: %s)", node.Kind)
+			}
+			name := models.NodeToBehavior(*node).Name
+
+			if off {
+				unsnooze(node)
+			} else {
+				snooze(node, until, os.Getenv("USER"), reason, now)
+			}
+			if err := graphStore.UpdateNode(ctx, *node); err != nil {
+				return fmt.Errorf("failed to update behavior: %w", err)
+			}
+			if err := graphStore.Sync(ctx); err != nil {
+				return fmt.Errorf("failed to sync changes: %w", err)
+			}
+
+			if jsonOut {
+				result := map[string]interface{}{
+					"status": "snoozed",
+					"id":     id,
+					"name":   name,
+				}
+				if off {
+					result["status"] = "awake"
+				} else {
+					result["snoozed_until"] = until.UTC().Format(time.RFC3339)
+					result["reason"] = reason
+				}
+				return json.NewEncoder(out).Encode(result)
+			}
+			if off {
+				fmt.Fprintf(out, "Behavior '%s' is no longer snoozed.\n", name)
+			} else {
+				fmt.Fprintf(out, "Behavior '%s' snoozed until %s.\n", name, until.Local().Format("2006-01-02 15:04"))
+				fmt.Fprintf(out, "Use 'floop snooze %s --off' to wake it early.\n", id)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().String("for", "", "How long to snooze (e.g. 7d, 12h)")
+	cmd.Flags().String("until", "", "Snooze until a date (2026-01-31) or RFC3339 time")
+	cmd.Flags().Bool("off", false, "End the snooze now")
+	cmd.Flags().String("reason", "", "Reason for snoozing")
+
+	return cmd
+}
+
+// snooze records on node that it is snoozed until the given time.
+func snooze(node *store.Node, until time.Time, by, reason string, now time.Time) {
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	node.Metadata[models.SnoozedUntilKey] = until.UTC().Format(time.RFC3339)
+	node.Metadata["snoozed_at"] = now.Format(time.RFC3339)
+	node.Metadata["snoozed_by"] = by
+	if reason != "" {
+		node.Metadata["snooze_reason"] = reason
+	} else {
+		delete(node.Metadata, "snooze_reason")
+	}
+}
+
+// unsnooze clears any snooze recorded on node.
+func unsnooze(node *store.Node) {
+	delete(node.Metadata, models.SnoozedUntilKey)
+	delete(node.Metadata, "snoozed_at")
+	delete(node.Metadata, "snoozed_by")
+	delete(node.Metadata, "snooze_reason")
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func TestSnoozeCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	run := func(cmd *cobra.Command, args ...string) (string, error) {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(cmd)
+		var out bytes.Buffer
+		rootCmd.SetOut(&out)
+		rootCmd.SetErr(&bytes.Buffer{})
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		err := rootCmd.Execute()
+		return out.String(), err
+	}
+	snoozed := func() []string {
+		t.Helper()
+		out, err := run(newListCmd(), "list", "--json", "--snoozed")
+		if err != nil {
+			t.Fatalf("list --snoozed failed: %v", err)
+		}
+		var result struct {
+			Behaviors []models.Behavior `json:"behaviors"`
+		}
+		if err := json.Unmarshal([]byte(out), &result); err != nil {
+			t.Fatalf("invalid JSON: %v\n%s", err, out)
+		}
+		var ids []string
+		for _, b := range result.Behaviors {
+			ids = append(ids, b.ID)
+		}
+		return ids
+	}
+
+	for _, args := range [][]string{
+		{"snooze", behaviorID},
+		{"snooze", behaviorID, "--for", "7d", "--off"},
+		{"snooze", behaviorID, "--for", "soon"},
+		{"snooze", behaviorID, "--until", "2020-01-01"},
+		{"snooze", "missing", "--for", "7d"},
+	} {
+		if _, err := run(newSnoozeCmd(), args...); err == nil {
+			t.Errorf("%v succeeded, want error", args)
+		}
+	}
+	if got := snoozed(); len(got) != 0 {
+		t.Fatalf("list --snoozed before snoozing = %v", got)
+	}
+
+	out, err := run(newSnoozeCmd(), "snooze", behaviorID, "--for", "7d", "--reason", "freeze week")
+	if err != nil {
+		t.Fatalf("snooze failed: %v", err)
+	}
+	if !strings.Contains(out, "snoozed until") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	if got := snoozed(); len(got) != 1 || got[0] != behaviorID {
+		t.Errorf("list --snoozed = %v, want [%s]", got, behaviorID)
+	}
+
+	s, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := s.GetNode(context.Background(), behaviorID)
+	s.Close()
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", behaviorID, node, err)
+	}
+	b := models.NodeToBehavior(*node)
+	if node.Kind != store.NodeKindBehavior || !b.Snoozed(time.Now().Add(6*24*time.Hour)) || b.Snoozed(time.Now().Add(8*24*time.Hour)) {
+		t.Errorf("snoozed behavior = %s until %v, want active kind snoozed for 7d", node.Kind, b.SnoozedUntil)
+	}
+	if node.Metadata["snooze_reason"] != "freeze week" {
+		t.Errorf("snooze_reason = %v", node.Metadata["snooze_reason"])
+	}
+
+	if _, err := run(newSnoozeCmd(), "snooze", behaviorID, "--off"); err != nil {
+		t.Fatalf("snooze --off failed: %v", err)
+	}
+	if got := snoozed(); len(got) != 0 {
+		t.Errorf("list --snoozed after --off = %v", got)
+	}
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/team"
+	"github.com/spf13/cobra"
+)
+
+func newSyncCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "sync",
+		Short: "Share behaviors with your team through .floop/team.jsonl",
+		Long: `Reconcile the project's team behaviors with .floop/team.jsonl.
+
+Team behaviors are local behaviors with scope "team". Commit
+.floop/team.jsonl to share them: each sync imports behaviors teammates
+shared or changed, and appends your edits and newly shared behaviors to
+the log as new revisions.
+
+The log is append-only, and each behavior resolves to its highest
+revision (then latest timestamp), so the result does not depend on merge
+order. Sync adds "team.jsonl merge=union" to .floop/.gitattributes so git
+merges concurrent appends without conflicts. When a behavior was edited
+both locally and by a teammate, the teammate's revision wins and the
+local edit is reported.
+
+Examples:
+  floop sync                          # Pull team changes, publish yours
+  floop sync --share behavior-3f2a9c  # Start sharing a local behavior
+  floop sync --unshare behavior-3f2a9c
+  floop sync --dry-run --json
+  floop sync --compact                # Drop superseded revisions`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			share, _ := cmd.Flags().GetStringSlice("share")
+			unshare, _ := cmd.Flags().GetStringSlice("unshare")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			compact, _ := cmd.Flags().GetBool("compact")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			floopDir := filepath.Join(root, ".floop")
+			if _, err := os.Stat(floopDir); os.IsNotExist(err) {
+				return errNotInitialized
+			}
+			if dryRun && (len(share) > 0 || len(unshare) > 0 || compact) {
+				return fmt.Errorf("--dry-run cannot be combined with --share, --unshare, or --compact")
+			}
+			if compact {
+				confirmed, err := confirmDestructive(config.OpSyncCompact, yes, jsonOut, func() {
+					fmt.Fprintf(out, "Rewrite %s keeping only the latest revision of each behavior.\n", team.FileName)
+				})
+				if err != nil {
+					return err
+				}
+				if !confirmed {
+					fmt.Fprintln(out, "Cancelled.")
+					return nil
+				}
+			}
+
+			localStore, err := store.NewSQLiteGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open local store: %w", err)
+			}
+			defer localStore.Close()
+
+			ctx := context.Background()
+			logPath := team.Path(floopDir)
+			opts := team.Options{Author: os.Getenv("USER"), DryRun: dryRun}
+
+			for _, id := range share {
+				if err := team.Share(ctx, localStore, id); err != nil {
+					return fmt.Errorf("%w (only local behaviors can be shared; use 'floop demote %s' for a global one)", err, id)
+				}
+			}
+			for _, id := range unshare {
+				if err := team.Unshare(ctx, localStore, logPath, id, opts); err != nil {
+					return err
+				}
+			}
+
+			result, err := team.Sync(ctx, localStore, logPath, opts)
+			if err != nil {
+				return err
+			}
+
+			compacted := 0
+			if !dryRun {
+				if err := team.EnsureGitattributes(floopDir); err != nil {
+					return err
+				}
+				if compact {
+					if compacted, err = team.Compact(logPath); err != nil {
+						return err
+					}
+				}
+				if err := localStore.Sync(ctx); err != nil {
+					return fmt.Errorf("failed to sync store: %w", err)
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"imported":  nonNil(result.Imported),
+					"updated":   nonNil(result.Updated),
+					"removed":   nonNil(result.Removed),
+					"published": nonNil(result.Published),
+					"conflicts": nonNil(result.Conflicts),
+					"skipped":   result.Skipped,
+					"shared":    nonNil(share),
+					"unshared":  nonNil(unshare),
+					"compacted": compacted,
+					"dry_run":   dryRun,
+				})
+			}
+
+			if dryRun {
+				fmt.Fprintln(out, "Dry run: nothing written.")
+			}
+			for _, line := range []struct {
+				label string
+				ids   []string
+			}{
+				{"Imported", result.Imported},
+				{"Updated", result.Updated},
+				{"Removed", result.Removed},
+				{"Published", result.Published},
+			} {
+				if len(line.ids) > 0 {
+					fmt.Fprintf(out, "%s %d: %s\n", line.label, len(line.ids), strings.Join(line.ids, ", "))
+				}
+			}
+			for _, c := range result.Conflicts {
+				fmt.Fprintf(out, "Conflict: %s\n", c)
+			}
+			if result.Skipped > 0 {
+				fmt.Fprintf(out, "Skipped %d malformed lines in %s\n", result.Skipped, team.FileName)
+			}
+			if len(result.Imported)+len(result.Updated)+len(result.Removed)+len(result.Published)+len(result.Conflicts) == 0 {
+				fmt.Fprintln(out, "Team behaviors are up to date.")
+			}
+			if compacted > 0 {
+				fmt.Fprintf(out, "Compacted %s to %d records.\n", team.FileName, compacted)
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().StringSlice("share", nil, "Start sharing a local behavior with the team (repeatable)")
+	cmd.Flags().StringSlice("unshare", nil, "Stop sharing a team behavior; teammates' copies are removed (repeatable)")
+	cmd.Flags().Bool("dry-run", false, "Show what would change without writing")
+	cmd.Flags().Bool("compact", false, "Rewrite team.jsonl keeping only the latest revision of each behavior")
+	addYesFlag(cmd)
+
+	return cmd
+}
+
+// nonNil returns an empty slice for nil, so JSON output has [] not null.
+func nonNil(s []string) []string {
+	if s == nil {
+		return []string{}
+	}
+	return s
+}
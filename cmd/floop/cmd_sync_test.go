@@ -0,0 +1,120 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// initTeamProject creates a project with a local store holding one behavior.
+func initTeamProject(t *testing.T, dir, id, canonical string) {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if id == "" {
+		return
+	}
+	if _, err := s.AddNode(context.Background(), store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": canonical},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.7},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSyncCmd(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	alice, bob := filepath.Join(tmpDir, "alice"), filepath.Join(tmpDir, "bob")
+	initTeamProject(t, alice, "uv-installs", "Use uv for Python installs")
+	initTeamProject(t, bob, "", "")
+
+	out, err := runCmd(t, newSyncCmd(), "--share", "uv-installs", "--root", alice)
+	if err != nil {
+		t.Fatalf("sync --share failed: %v", err)
+	}
+	if !strings.Contains(out, "Published 1: uv-installs") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	attrs, _ := os.ReadFile(filepath.Join(alice, ".floop", ".gitattributes"))
+	if !strings.Contains(string(attrs), "team.jsonl merge=union") {
+		t.Errorf(".gitattributes = %q", attrs)
+	}
+
+	// Stand in for git: Bob pulls Alice's team log.
+	data, err := os.ReadFile(filepath.Join(alice, ".floop", "team.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(bob, ".floop", "team.jsonl"), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err = runCmd(t, newSyncCmd(), "--dry-run", "--json", "--root", bob)
+	if err != nil {
+		t.Fatalf("sync --dry-run failed: %v", err)
+	}
+	var result struct {
+		Imported []string `json:"imported"`
+		DryRun   bool     `json:"dry_run"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if len(result.Imported) != 1 || !result.DryRun {
+		t.Errorf("dry run result = %+v", result)
+	}
+
+	out, err = runCmd(t, newSyncCmd(), "--root", bob)
+	if err != nil {
+		t.Fatalf("sync failed: %v", err)
+	}
+	if !strings.Contains(out, "Imported 1: uv-installs") {
+		t.Errorf("unexpected output:\n%s", out)
+	}
+	s, err := store.NewSQLiteGraphStore(bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, _ := s.GetNode(context.Background(), "uv-installs")
+	s.Close()
+	if node == nil || node.Metadata["scope"] != "team" {
+		t.Fatalf("bob's copy = %+v, want team behavior", node)
+	}
+
+	out, err = runCmd(t, newSyncCmd(), "--root", bob)
+	if err != nil || !strings.Contains(out, "up to date") {
+		t.Errorf("second sync = %q, %v", out, err)
+	}
+}
+
+func TestSyncCmd_Errors(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	if _, err := runCmd(t, newSyncCmd(), "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "not initialized") {
+		t.Errorf("expected not initialized error, got %v", err)
+	}
+
+	initTeamProject(t, tmpDir, "", "")
+	if _, err := runCmd(t, newSyncCmd(), "--share", "missing", "--root", tmpDir); err == nil || !strings.Contains(err.Error(), "floop demote") {
+		t.Errorf("expected share error pointing at demote, got %v", err)
+	}
+	if _, err := runCmd(t, newSyncCmd(), "--dry-run", "--compact", "--root", tmpDir); err == nil {
+		t.Error("expected error combining --dry-run and --compact")
+	}
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
+	"io"
 
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
@@ -33,6 +33,7 @@ extraction that new behaviors get automatically.
 
 Use --dry-run to preview changes without modifying the store.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			dryRun, _ := cmd.Flags().GetBool("dry-run")
 			jsonOut, _ := cmd.Flags().GetBool("json")
@@ -42,7 +43,7 @@ Use --dry-run to preview changes without modifying the store.`,
 			}
 			defer graphStore.Close()
 
-			return runTagsBackfill(graphStore, dryRun, jsonOut)
+			return runTagsBackfill(out, graphStore, dryRun, jsonOut)
 		},
 	}
 
@@ -64,7 +65,7 @@ type backfillOutput struct {
 	DryRun  bool             `json:"dry_run"`
 }
 
-func runTagsBackfill(graphStore store.GraphStore, dryRun, jsonOut bool) error {
+func runTagsBackfill(out io.Writer, graphStore store.GraphStore, dryRun, jsonOut bool) error {
 	ctx := context.Background()
 	dict := tagging.NewDictionary()
 
@@ -112,18 +113,18 @@ func runTagsBackfill(graphStore store.GraphStore, dryRun, jsonOut bool) error {
 	}
 
 	if jsonOut {
-		return json.NewEncoder(os.Stdout).Encode(output)
+		return json.NewEncoder(out).Encode(output)
 	}
 
 	if dryRun {
-		fmt.Println("DRY RUN — no changes made")
-		fmt.Println()
+		fmt.Fprintln(out, "DRY RUN — no changes made")
+		fmt.Fprintln(out)
 	}
-	fmt.Printf("Total: %d behaviors, %d would be tagged, %d skipped\n",
+	fmt.Fprintf(out, "Total: %d behaviors, %d would be tagged, %d skipped\n",
 		output.Total, len(output.Updated), output.Skipped)
 
 	for _, r := range output.Updated {
-		fmt.Printf("  %s -> %v\n", r.Name, r.Tags)
+		fmt.Fprintf(out, "  %s -> %v\n", r.Name, r.Tags)
 	}
 
 	return nil
@@ -3,6 +3,7 @@ package main
 import (
 	"bytes"
 	"context"
+	"io"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/models"
@@ -75,7 +76,7 @@ func TestRunTagsBackfillDryRun(t *testing.T) {
 	}
 
 	// Dry run should not modify the store
-	err := runTagsBackfill(s, true, false)
+	err := runTagsBackfill(io.Discard, s, true, false)
 	if err != nil {
 		t.Fatalf("runTagsBackfill dry-run failed: %v", err)
 	}
@@ -113,7 +114,7 @@ func TestRunTagsBackfillAppliesTags(t *testing.T) {
 	}
 
 	// Run for real (not dry run)
-	err := runTagsBackfill(s, false, false)
+	err := runTagsBackfill(io.Discard, s, false, false)
 	if err != nil {
 		t.Fatalf("runTagsBackfill failed: %v", err)
 	}
@@ -123,7 +124,7 @@ func TestRunTagsBackfillJSON(t *testing.T) {
 	s := store.NewInMemoryGraphStore()
 
 	// Run with JSON output on empty store
-	err := runTagsBackfill(s, true, true)
+	err := runTagsBackfill(io.Discard, s, true, true)
 	if err != nil {
 		t.Fatalf("runTagsBackfill JSON failed: %v", err)
 	}
@@ -132,7 +133,7 @@ func TestRunTagsBackfillJSON(t *testing.T) {
 func TestRunTagsBackfillEmptyStore(t *testing.T) {
 	s := store.NewInMemoryGraphStore()
 
-	err := runTagsBackfill(s, false, false)
+	err := runTagsBackfill(io.Discard, s, false, false)
 	if err != nil {
 		t.Fatalf("runTagsBackfill on empty store failed: %v", err)
 	}
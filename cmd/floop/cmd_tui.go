@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+func newTUICmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tui",
+		Short: "Browse and curate behaviors in an interactive terminal UI",
+		Long: `Browse learned behaviors in a full-screen terminal UI.
+
+Each behavior shows its activation status for a synthetic context built
+from --file, --task, and --env:
+
+  ●  active      matches the context and survives conflict resolution
+  ◐  overridden  matches but is overridden by a more specific behavior
+  ◌  excluded    matches but loses a conflict
+  ○  inactive    when-conditions do not match
+  ?  pending     held for review; never activates
+
+A "!" after the marker flags behaviors awaiting review. The detail pane
+shows the selected behavior and its graph neighborhood (edges in and out).
+
+Keys:
+  j/k, arrows   move           a      approve (behaviors awaiting review)
+  g/G           first/last     f      forget (asks to confirm)
+  PgUp/PgDn     page           e, ⏎   edit canonical content in $EDITOR
+  r             reload         q      quit
+
+Examples:
+  floop tui
+  floop tui --file internal/store/sqlite.go --task testing`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			file, _ := cmd.Flags().GetString("file")
+			task, _ := cmd.Flags().GetString("task")
+			env, _ := cmd.Flags().GetString("env")
+
+			graphStore, err := openReviewStore(root)
+			if err != nil {
+				return err
+			}
+			defer graphStore.Close()
+
+			b := &tuiBackend{
+				store: graphStore,
+				actCtx: activation.NewContextBuilder().
+					WithFile(file).
+					WithTask(task).
+					WithEnvironment(env).
+					WithRepoRoot(root).
+					Build(),
+			}
+			return tui.Run(cmd.Context(), os.Stdin, os.Stdout, b, tuiContextLabel(file, task, env))
+		},
+	}
+
+	cmd.Flags().String("file", "", "File path for the synthetic activation context")
+	cmd.Flags().String("task", "", "Task type for the synthetic activation context")
+	cmd.Flags().String("env", "", "Environment for the synthetic activation context")
+
+	return cmd
+}
+
+// tuiContextLabel describes the synthetic context for the TUI header.
+func tuiContextLabel(file, task, env string) string {
+	var parts []string
+	for _, p := range [][2]string{{"file", file}, {"task", task}, {"env", env}} {
+		if p[1] != "" {
+			parts = append(parts, p[0]+"="+p[1])
+		}
+	}
+	if len(parts) == 0 {
+		return "(empty)"
+	}
+	return strings.Join(parts, " ")
+}
+
+// tuiBackend implements tui.Backend over the project and global stores.
+type tuiBackend struct {
+	store  *store.MultiGraphStore
+	actCtx models.ContextSnapshot
+}
+
+// tuiStatusRank orders statuses in the list.
+var tuiStatusRank = map[tui.Status]int{
+	tui.StatusPending:    0,
+	tui.StatusActive:     1,
+	tui.StatusOverridden: 2,
+	tui.StatusExcluded:   3,
+	tui.StatusInactive:   4,
+}
+
+// Items implements tui.Backend. Behaviors awaiting review sort first, then
+// by activation status and name.
+func (b *tuiBackend) Items(ctx context.Context) ([]tui.Item, error) {
+	var behaviors []models.Behavior
+	var items []tui.Item
+	for _, kind := range []store.NodeKind{store.NodeKindBehavior, store.NodeKindPending} {
+		nodes, err := b.store.QueryNodes(ctx, map[string]interface{}{"kind": string(kind)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query behaviors: %w", err)
+		}
+		for _, node := range nodes {
+			behavior := models.NodeToBehavior(node)
+			_, requested := node.Metadata[review.MetaRequestedAt]
+			item := tui.Item{
+				ID:             behavior.ID,
+				Name:           behavior.Name,
+				Kind:           string(behavior.Kind),
+				Canonical:      behavior.Content.Canonical,
+				Tags:           behavior.Content.Tags,
+				Confidence:     behavior.Confidence,
+				TimesActivated: behavior.Stats.TimesActivated,
+				Status:         tui.StatusInactive,
+				AwaitingReview: kind == store.NodeKindPending || requested,
+			}
+			if kind == store.NodeKindPending {
+				item.Status = tui.StatusPending
+			} else {
+				behaviors = append(behaviors, behavior)
+			}
+			items = append(items, item)
+		}
+	}
+
+	resolved := activation.NewResolver().Resolve(activation.NewEvaluator().Evaluate(b.actCtx, behaviors))
+	status := make(map[string]tui.Status)
+	for _, o := range resolved.Overridden {
+		status[o.Behavior.ID] = tui.StatusOverridden
+	}
+	for _, c := range resolved.Excluded {
+		status[c.Behavior.ID] = tui.StatusExcluded
+	}
+	for _, a := range resolved.Active {
+		status[a.ID] = tui.StatusActive
+	}
+	for i := range items {
+		if s, ok := status[items[i].ID]; ok {
+			items[i].Status = s
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		if items[i].AwaitingReview != items[j].AwaitingReview {
+			return items[i].AwaitingReview
+		}
+		if ri, rj := tuiStatusRank[items[i].Status], tuiStatusRank[items[j].Status]; ri != rj {
+			return ri < rj
+		}
+		return items[i].Name < items[j].Name
+	})
+	return items, nil
+}
+
+// Neighbors implements tui.Backend.
+func (b *tuiBackend) Neighbors(ctx context.Context, id string) ([]tui.Neighbor, error) {
+	edges, err := b.store.GetEdges(ctx, id, store.DirectionBoth, "")
+	if err != nil {
+		return nil, err
+	}
+	neighbors := make([]tui.Neighbor, 0, len(edges))
+	for _, e := range edges {
+		n := tui.Neighbor{ID: e.Target, Kind: e.Kind, Weight: e.Weight, Outbound: e.Source == id}
+		if !n.Outbound {
+			n.ID = e.Source
+		}
+		if node, err := b.store.GetNode(ctx, n.ID); err == nil && node != nil {
+			n.Name, _ = node.Content["name"].(string)
+		}
+		neighbors = append(neighbors, n)
+	}
+	sort.SliceStable(neighbors, func(i, j int) bool { return neighbors[i].Weight > neighbors[j].Weight })
+	return neighbors, nil
+}
+
+// Approve implements tui.Backend.
+func (b *tuiBackend) Approve(ctx context.Context, id string) error {
+	return learning.NewLearningLoop(b.store, nil).ApprovePending(ctx, id)
+}
+
+// Forget implements tui.Backend.
+func (b *tuiBackend) Forget(ctx context.Context, id string) error {
+	node, err := b.store.GetNode(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get behavior: %w", err)
+	}
+	if node == nil {
+		return fmt.Errorf("behavior not found: %s", id)
+	}
+	if node.Kind != store.NodeKindBehavior && node.Kind != store.NodeKindPending {
+		return fmt.Errorf("not an active behavior (current kind: %s)", node.Kind)
+	}
+	markForgotten(node, os.Getenv("USER"), "forgotten in floop tui", time.Now())
+	if err := b.store.UpdateNode(ctx, *node); err != nil {
+		return fmt.Errorf("failed to update behavior: %w", err)
+	}
+	return b.store.Sync(ctx)
+}
+
+// Edit implements tui.Backend. The canonical content is opened in the
+// user's editor; saving it unchanged or empty leaves the behavior as is.
+// A changed canonical drops the stored summary so it is regenerated.
+func (b *tuiBackend) Edit(ctx context.Context, id string) error {
+	node, err := b.store.GetNode(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get behavior: %w", err)
+	}
+	if node == nil {
+		return fmt.Errorf("behavior not found: %s", id)
+	}
+	behavior := models.NodeToBehavior(*node)
+
+	edited, err := editText(fmt.Sprintf("# Editing %s (%s)\n# Lines starting with # are ignored. Save empty to cancel.\n\n%s\n",
+		behavior.Name, behavior.ID, behavior.Content.Canonical))
+	if err != nil {
+		return err
+	}
+	if edited == "" || edited == behavior.Content.Canonical {
+		return nil
+	}
+
+	content, ok := node.Content["content"].(map[string]interface{})
+	if !ok {
+		content = make(map[string]interface{})
+		node.Content["content"] = content
+	}
+	content["canonical"] = edited
+	delete(content, "summary")
+	if err := b.store.UpdateNode(ctx, *node); err != nil {
+		return fmt.Errorf("failed to update behavior: %w", err)
+	}
+	return b.store.Sync(ctx)
+}
+
+// editText opens text in the user's editor and returns the saved result
+// with comment lines and surrounding whitespace removed.
+func editText(text string) (string, error) {
+	f, err := os.CreateTemp("", "floop-edit-*.md")
+	if err != nil {
+		return "", fmt.Errorf("creating edit buffer: %w", err)
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	if err := os.WriteFile(path, []byte(text), 0600); err != nil {
+		return "", fmt.Errorf("writing edit buffer: %w", err)
+	}
+	if err := runEditor(path); err != nil {
+		return "", err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading edit buffer: %w", err)
+	}
+	return strings.TrimSpace(string(stripComments(data))), nil
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tui"
+)
+
+func newTestTUIBackend(t *testing.T, root, file, task string) *tuiBackend {
+	t.Helper()
+	gs, err := store.NewMultiGraphStore(root)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	t.Cleanup(func() { gs.Close() })
+	return &tuiBackend{
+		store:  gs,
+		actCtx: activation.NewContextBuilder().WithFile(file).WithTask(task).WithRepoRoot(root).Build(),
+	}
+}
+
+func TestTUIBackendItemsAndNeighbors(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	ctx := context.Background()
+	b := newTestTUIBackend(t, tmpDir, "main.go", "coding")
+
+	pending := models.Behavior{
+		ID:      "b-pending",
+		Name:    "pending-review",
+		Kind:    models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "Prefer table-driven tests"},
+	}
+	node := models.BehaviorToNode(&pending)
+	node.Kind = store.NodeKindPending
+	if _, err := b.store.AddNode(ctx, node); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.store.AddEdge(ctx, store.Edge{Source: behaviorID, Target: "b-pending", Kind: store.EdgeKindSimilarTo, Weight: 0.7, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := b.Items(ctx)
+	if err != nil {
+		t.Fatalf("Items() error = %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("Items() returned %d, want 2", len(items))
+	}
+	if items[0].ID != "b-pending" || items[0].Status != tui.StatusPending || !items[0].AwaitingReview {
+		t.Errorf("items[0] = %+v, want pending behavior awaiting review first", items[0])
+	}
+	if items[1].ID != behaviorID || items[1].Status != tui.StatusActive {
+		t.Errorf("items[1] = %+v, want learned behavior active for main.go", items[1])
+	}
+
+	neighbors, err := b.Neighbors(ctx, "b-pending")
+	if err != nil {
+		t.Fatalf("Neighbors() error = %v", err)
+	}
+	if len(neighbors) != 1 || neighbors[0].ID != behaviorID || neighbors[0].Outbound || neighbors[0].Name == "" {
+		t.Errorf("Neighbors() = %+v, want one inbound edge from %s", neighbors, behaviorID)
+	}
+
+	if err := b.Approve(ctx, "b-pending"); err != nil {
+		t.Fatalf("Approve() error = %v", err)
+	}
+	if err := b.Approve(ctx, behaviorID); err == nil {
+		t.Error("Approve() on a behavior not awaiting review should fail")
+	}
+	approved, _ := b.store.GetNode(ctx, "b-pending")
+	if approved.Kind != store.NodeKindBehavior {
+		t.Errorf("approved kind = %s, want behavior", approved.Kind)
+	}
+}
+
+func TestTUIBackendEditAndForget(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	ctx := context.Background()
+	b := newTestTUIBackend(t, tmpDir, "", "")
+
+	stubEditor(t, func(path string) error {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Contains(data, []byte("use slog structured logging")) {
+			t.Errorf("edit buffer missing canonical: %q", data)
+		}
+		return os.WriteFile(path, []byte("# comment\nuse slog with JSON handler\n"), 0600)
+	})
+	if err := b.Edit(ctx, behaviorID); err != nil {
+		t.Fatalf("Edit() error = %v", err)
+	}
+	node, _ := b.store.GetNode(ctx, behaviorID)
+	if got := models.NodeToBehavior(*node).Content.Canonical; got != "use slog with JSON handler" {
+		t.Errorf("canonical after edit = %q", got)
+	}
+
+	// Saving an empty buffer leaves the behavior unchanged.
+	stubEditor(t, func(path string) error { return os.WriteFile(path, nil, 0600) })
+	if err := b.Edit(ctx, behaviorID); err != nil {
+		t.Fatalf("Edit() empty error = %v", err)
+	}
+	node, _ = b.store.GetNode(ctx, behaviorID)
+	if got := models.NodeToBehavior(*node).Content.Canonical; got != "use slog with JSON handler" {
+		t.Errorf("canonical after empty edit = %q", got)
+	}
+
+	if err := b.Forget(ctx, behaviorID); err != nil {
+		t.Fatalf("Forget() error = %v", err)
+	}
+	node, _ = b.store.GetNode(ctx, behaviorID)
+	if node.Kind != store.NodeKindForgotten || node.Metadata["forget_reason"] != "forgotten in floop tui" {
+		t.Errorf("node after forget = %s %v", node.Kind, node.Metadata)
+	}
+	if err := b.Forget(ctx, behaviorID); err == nil {
+		t.Error("Forget() on a forgotten behavior should fail")
+	}
+}
+
+func TestTUIContextLabel(t *testing.T) {
+	if got := tuiContextLabel("", "", ""); got != "(empty)" {
+		t.Errorf("empty label = %q", got)
+	}
+	if got := tuiContextLabel("main.go", "", "prod"); got != "file=main.go env=prod" {
+		t.Errorf("label = %q", got)
+	}
+}
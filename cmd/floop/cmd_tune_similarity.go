@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// similarityLabelsFile is the per-store file holding labeled behavior pairs.
+const similarityLabelsFile = "similarity-labels.jsonl"
+
+// similarityLabel is one labeled behavior pair as stored on disk.
+type similarityLabel struct {
+	A         string               `json:"a"`
+	B         string               `json:"b"`
+	Label     similarity.PairLabel `json:"label"`
+	LabeledAt time.Time            `json:"labeled_at"`
+}
+
+func newTuneSimilarityCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tune-similarity",
+		Short: "Fit similarity thresholds and weights from labeled behavior pairs",
+		Long: `Fit per-store similarity thresholds and signal weights from labeled pairs.
+
+The built-in thresholds (similar-to at 0.5, duplicate at 0.9) are global
+guesses. Label behavior pairs as duplicate, related, or unrelated with
+'floop tune-similarity label', then run this command to fit weights and
+thresholds that best reproduce your labels. The result is saved to
+~/.floop/config.yaml and used by derive-edges and learn placement for that store.
+At least 10 duplicate and 10 related or unrelated labels are required.
+
+Pair IDs can be taken from 'floop deduplicate --dry-run' output.
+
+Examples:
+  floop tune-similarity label b-1 b-2 duplicate   # Label a pair
+  floop tune-similarity                           # Fit and save for global store
+  floop tune-similarity --scope local --dry-run   # Preview fit for local store`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			scope, _ := cmd.Flags().GetString("scope")
+
+			floopDir, err := similarityStoreDir(root, scope)
+			if err != nil {
+				return err
+			}
+
+			labels, err := readSimilarityLabels(floopDir)
+			if err != nil {
+				return err
+			}
+			if len(labels) == 0 {
+				return fmt.Errorf("no labeled pairs in %s. Use 'floop tune-similarity label' first", filepath.Join(floopDir, similarityLabelsFile))
+			}
+
+			ctx := context.Background()
+			pairs, skipped, err := labeledPairsFromStore(ctx, floopDir, labels)
+			if err != nil {
+				return err
+			}
+
+			result, err := similarity.Tune(pairs)
+			if err != nil {
+				return err
+			}
+
+			if !dryRun {
+				cfg, err := config.Load()
+				if err != nil {
+					return fmt.Errorf("failed to load config: %w", err)
+				}
+				cfg.Similarity.SetTuning(floopDir, result.Tuning)
+				if err := saveConfig(cfg); err != nil {
+					return err
+				}
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"scope":     scope,
+					"floop_dir": floopDir,
+					"dry_run":   dryRun,
+					"skipped":   skipped,
+					"result":    result,
+				})
+			}
+
+			fmt.Fprintf(out, "Labeled pairs: %d", result.Pairs)
+			if skipped > 0 {
+				fmt.Fprintf(out, " (%d skipped: behavior no longer exists)", skipped)
+			}
+			fmt.Fprintln(out)
+			fmt.Fprintf(out, "Accuracy: %.0f%% (defaults: %.0f%%)\n", result.Accuracy*100, result.BaselineAccuracy*100)
+			w := result.Tuning.Weights
+			fmt.Fprintf(out, "\nWeights:    when=%.2f content=%.2f tags=%.2f\n", w.When, w.Content, w.Tags)
+			th := result.Tuning.Thresholds
+			fmt.Fprintf(out, "Thresholds: similar_to=%.3f specialize=%.3f upper_bound=%.3f\n", th.SimilarTo, th.Specialize, th.UpperBound)
+			if dryRun {
+				fmt.Fprintln(out, "\nDry run: tuning not saved.")
+			} else {
+				fmt.Fprintf(out, "\nSaved tuning for %s\n", floopDir)
+			}
+			return nil
+		},
+	}
+
+	cmd.PersistentFlags().String("scope", "global", "Store scope: local or global")
+	cmd.Flags().Bool("dry-run", false, "Show the fitted tuning without saving it")
+
+	cmd.AddCommand(newTuneSimilarityLabelCmd())
+
+	return cmd
+}
+
+func newTuneSimilarityLabelCmd() *cobra.Command {
+	return &cobra.Command{
+		Use:   "label <behavior-id> <behavior-id> <duplicate|related|unrelated>",
+		Short: "Label a behavior pair for similarity tuning",
+		Args:  cobra.ExactArgs(3),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			scope, _ := cmd.Flags().GetString("scope")
+
+			label := similarity.PairLabel(args[2])
+			if !label.Valid() {
+				return fmt.Errorf("invalid label: %s (must be duplicate, related, or unrelated)", args[2])
+			}
+			if args[0] == args[1] {
+				return fmt.Errorf("cannot label a behavior against itself")
+			}
+
+			floopDir, err := similarityStoreDir(root, scope)
+			if err != nil {
+				return err
+			}
+
+			ctx := context.Background()
+			graphStore, err := store.NewSQLiteGraphStore(filepath.Dir(floopDir))
+			if err != nil {
+				return fmt.Errorf("failed to open %s store: %w", scope, err)
+			}
+			defer graphStore.Close()
+
+			for _, id := range args[:2] {
+				node, err := graphStore.GetNode(ctx, id)
+				if err != nil {
+					return fmt.Errorf("failed to get behavior %s: %w", id, err)
+				}
+				if node == nil {
+					return fmt.Errorf("behavior not found in %s store: %s", scope, id)
+				}
+			}
+
+			entry := similarityLabel{
+				A:         args[0],
+				B:         args[1],
+				Label:     label,
+				LabeledAt: time.Now(),
+			}
+			if err := appendSimilarityLabel(floopDir, entry); err != nil {
+				return err
+			}
+
+			if jsonOut {
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status": "labeled",
+					"scope":  scope,
+					"pair":   entry,
+				})
+			}
+			fmt.Fprintf(out, "Labeled %s <-> %s as %s\n", entry.A, entry.B, entry.Label)
+			return nil
+		},
+	}
+}
+
+// similarityStoreDir resolves the .floop directory for a local or global scope
+// and verifies it has been initialized.
+func similarityStoreDir(root, scope string) (string, error) {
+	switch constants.Scope(scope) {
+	case constants.ScopeLocal:
+		floopDir := store.LocalFloopPath(root)
+		if _, err := os.Stat(floopDir); err != nil {
+			return "", errNotInitialized
+		}
+		return floopDir, nil
+	case constants.ScopeGlobal:
+		floopDir, err := store.GlobalFloopPath()
+		if err != nil {
+			return "", fmt.Errorf("failed to get global path: %w", err)
+		}
+		if _, err := os.Stat(floopDir); err != nil {
+			return "", fmt.Errorf("global .floop not accessible: %w", err)
+		}
+		return floopDir, nil
+	default:
+		return "", fmt.Errorf("invalid scope: %s (must be local or global)", scope)
+	}
+}
+
+// readSimilarityLabels reads labeled pairs from floopDir. Later labels for the
+// same pair replace earlier ones. A missing file yields no labels.
+func readSimilarityLabels(floopDir string) ([]similarityLabel, error) {
+	path := filepath.Join(floopDir, similarityLabelsFile)
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open labels: %w", err)
+	}
+	defer f.Close()
+
+	type pairKey struct{ a, b string }
+	index := make(map[pairKey]int)
+	var labels []similarityLabel
+
+	scanner := bufio.NewScanner(f)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var l similarityLabel
+		if err := json.Unmarshal(scanner.Bytes(), &l); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		key := pairKey{l.A, l.B}
+		if l.B < l.A {
+			key = pairKey{l.B, l.A}
+		}
+		if i, ok := index[key]; ok {
+			labels[i] = l
+			continue
+		}
+		index[key] = len(labels)
+		labels = append(labels, l)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read labels: %w", err)
+	}
+	return labels, nil
+}
+
+// appendSimilarityLabel appends a labeled pair to floopDir's labels file.
+func appendSimilarityLabel(floopDir string, l similarityLabel) error {
+	data, err := json.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("failed to encode label: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(floopDir, similarityLabelsFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open labels: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write label: %w", err)
+	}
+	return nil
+}
+
+// labeledPairsFromStore computes similarity signals for each labeled pair
+// using the behaviors in floopDir's store. Pairs referencing behaviors that no
+// longer exist are skipped and counted.
+func labeledPairsFromStore(ctx context.Context, floopDir string, labels []similarityLabel) ([]similarity.LabeledPair, int, error) {
+	graphStore, err := store.NewSQLiteGraphStore(filepath.Dir(floopDir))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open store: %w", err)
+	}
+	defer graphStore.Close()
+
+	cache := make(map[string]*models.Behavior)
+	get := func(id string) (*models.Behavior, error) {
+		if b, ok := cache[id]; ok {
+			return b, nil
+		}
+		node, err := graphStore.GetNode(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		var b *models.Behavior
+		if node != nil {
+			nb := models.NodeToBehavior(*node)
+			b = &nb
+		}
+		cache[id] = b
+		return b, nil
+	}
+
+	pairs := make([]similarity.LabeledPair, 0, len(labels))
+	skipped := 0
+	for _, l := range labels {
+		a, err := get(l.A)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get behavior %s: %w", l.A, err)
+		}
+		b, err := get(l.B)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to get behavior %s: %w", l.B, err)
+		}
+		if a == nil || b == nil {
+			skipped++
+			continue
+		}
+		pairs = append(pairs, similarity.LabeledPair{
+			WhenOverlap:       similarity.ComputeWhenOverlap(a.When, b.When),
+			ContentSimilarity: similarity.ComputeContentSimilarity(a.Content.Canonical, b.Content.Canonical),
+			TagSimilarity:     similarity.ComputeTagSimilarity(a.Content.Tags, b.Content.Tags),
+			Label:             l.Label,
+		})
+	}
+	return pairs, skipped, nil
+}
+
+// scopeSimilarityTuning returns the saved similarity tuning for the local or
+// global store, or the defaults if that store has not been tuned.
+func scopeSimilarityTuning(root string, scope constants.Scope) similarity.Tuning {
+	floopDir := store.LocalFloopPath(root)
+	if scope != constants.ScopeLocal {
+		globalDir, err := store.GlobalFloopPath()
+		if err != nil {
+			return similarity.DefaultTuning()
+		}
+		floopDir = globalDir
+	}
+	if t := storeSimilarityTuning(floopDir); t != nil {
+		return *t
+	}
+	return similarity.DefaultTuning()
+}
+
+// storeSimilarityTuning returns the saved similarity tuning for floopDir, or
+// nil if the store has not been tuned or the config cannot be loaded.
+func storeSimilarityTuning(floopDir string) *similarity.Tuning {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil
+	}
+	t, ok := cfg.Similarity.Lookup(floopDir)
+	if !ok {
+		return nil
+	}
+	return &t
+}
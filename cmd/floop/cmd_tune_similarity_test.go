@@ -0,0 +1,173 @@
+package main
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// setupTuneSimilarityTest initializes a local store with a few behaviors.
+func setupTuneSimilarityTest(t *testing.T) string {
+	t.Helper()
+	tmpDir := setupDeinitTest(t)
+	addTuneSimilarityBehaviors(t, tmpDir, map[string]string{
+		"b-go-tests":  "Use table-driven tests in Go",
+		"b-go-tests2": "Use table-driven tests for Go code",
+		"b-go-errs":   "Wrap errors with context in Go",
+		"b-docker":    "Pin base image versions in Dockerfiles",
+	})
+	return tmpDir
+}
+
+// addTuneSimilarityBehaviors adds directive behaviors to the local store.
+func addTuneSimilarityBehaviors(t *testing.T, tmpDir string, behaviors map[string]string) {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("open store: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	for id, canonical := range behaviors {
+		if _, err := s.AddNode(ctx, store.Node{
+			ID:   id,
+			Kind: "behavior",
+			Content: map[string]interface{}{
+				"kind":    "directive",
+				"name":    id,
+				"content": map[string]interface{}{"canonical": canonical},
+			},
+		}); err != nil {
+			t.Fatalf("add node %s: %v", id, err)
+		}
+	}
+}
+
+func TestTuneSimilarityLabelValidation(t *testing.T) {
+	tmpDir := setupTuneSimilarityTest(t)
+
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"invalid label", []string{"b-go-tests", "b-go-errs", "maybe"}},
+		{"same behavior", []string{"b-go-tests", "b-go-tests", "duplicate"}},
+		{"unknown behavior", []string{"b-go-tests", "b-missing", "related"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			args := append([]string{"label"}, tt.args...)
+			args = append(args, "--scope", "local", "--root", tmpDir)
+			if _, err := runCmd(t, newTuneSimilarityCmd(), args...); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(tmpDir, ".floop", similarityLabelsFile)); !os.IsNotExist(err) {
+		t.Error("rejected labels should not be written")
+	}
+}
+
+func TestTuneSimilarityFitsAndSaves(t *testing.T) {
+	tmpDir := setupTuneSimilarityTest(t)
+
+	labels := [][]string{
+		{"b-go-tests", "b-go-tests2", "duplicate"},
+		{"b-go-tests", "b-go-errs", "related"},
+		{"b-go-tests", "b-docker", "unrelated"},
+		{"b-go-errs", "b-docker", "related"},
+		// Relabeling a pair replaces the earlier label.
+		{"b-docker", "b-go-errs", "unrelated"},
+	}
+	for _, l := range labels {
+		args := append([]string{"label"}, l...)
+		args = append(args, "--scope", "local", "--root", tmpDir)
+		if _, err := runCmd(t, newTuneSimilarityCmd(), args...); err != nil {
+			t.Fatalf("label %v: %v", l, err)
+		}
+	}
+
+	floopDir := filepath.Join(tmpDir, ".floop")
+	got, err := readSimilarityLabels(floopDir)
+	if err != nil {
+		t.Fatalf("readSimilarityLabels: %v", err)
+	}
+	if len(got) != 4 {
+		t.Fatalf("expected 4 distinct pairs, got %d", len(got))
+	}
+	if got[3].Label != similarity.LabelUnrelated {
+		t.Errorf("relabeled pair = %q, want unrelated", got[3].Label)
+	}
+
+	// Too few labels per class to fit.
+	if _, err := runCmd(t, newTuneSimilarityCmd(), "--scope", "local", "--dry-run", "--root", tmpDir); err == nil {
+		t.Fatal("expected error with too few labels")
+	}
+
+	// Label every pair, alternating duplicates with related and unrelated.
+	addTuneSimilarityBehaviors(t, tmpDir, map[string]string{
+		"b-go-errs2": "Add context when wrapping Go errors",
+		"b-docker2":  "Pin Docker base images to a version",
+		"b-git":      "Write commit subjects in the imperative mood",
+		"b-sql":      "Use parameterized SQL queries",
+	})
+	ids := []string{"b-docker", "b-docker2", "b-git", "b-go-errs", "b-go-errs2", "b-go-tests", "b-go-tests2", "b-sql"}
+	n := 0
+	for i := range ids {
+		for j := i + 1; j < len(ids); j++ {
+			label := similarity.LabelDuplicate
+			switch n % 4 {
+			case 1:
+				label = similarity.LabelRelated
+			case 3:
+				label = similarity.LabelUnrelated
+			}
+			n++
+			if err := appendSimilarityLabel(floopDir, similarityLabel{A: ids[i], B: ids[j], Label: label, LabeledAt: time.Now()}); err != nil {
+				t.Fatalf("appendSimilarityLabel: %v", err)
+			}
+		}
+	}
+
+	// Dry run does not persist.
+	if _, err := runCmd(t, newTuneSimilarityCmd(), "--scope", "local", "--dry-run", "--root", tmpDir); err != nil {
+		t.Fatalf("tune-similarity --dry-run: %v", err)
+	}
+	if storeSimilarityTuning(floopDir) != nil {
+		t.Error("dry run should not save tuning")
+	}
+
+	if _, err := runCmd(t, newTuneSimilarityCmd(), "--scope", "local", "--root", tmpDir); err != nil {
+		t.Fatalf("tune-similarity: %v", err)
+	}
+	tuning := storeSimilarityTuning(floopDir)
+	if tuning == nil {
+		t.Fatal("expected tuning to be saved")
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		t.Fatalf("config.Load: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("saved config invalid: %v", err)
+	}
+	if cfg.Similarity.TuningFor(floopDir) != *tuning {
+		t.Error("TuningFor() does not match saved tuning")
+	}
+}
+
+func TestTuneSimilarityNoLabels(t *testing.T) {
+	tmpDir := setupTuneSimilarityTest(t)
+	if _, err := runCmd(t, newTuneSimilarityCmd(), "--scope", "local", "--root", tmpDir); err == nil {
+		t.Error("expected error without labels")
+	}
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+func newUndoCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undo",
+		Short: "Reverse the most recent learn",
+		Long: `Reverse the graph changes made by the most recent learn.
+
+A learn that created a behavior is undone by deleting the behavior and the
+edges placed with it, and restoring any behavior it marked as conflicting.
+A learn that auto-merged into an existing behavior is undone by restoring
+the merged behavior and the duplicate it replaced. Run undo again to step
+further back.
+
+Learns are recorded in .floop/oplog.jsonl by 'floop learn', 'floop ingest',
+correction hooks, and the MCP floop_learn tool. The corrections log is left
+as is; stale vector index entries are removed by 'floop gc'.
+
+Examples:
+  floop undo --dry-run
+  floop undo
+  floop undo --json --yes`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
+			root, _ := cmd.Flags().GetString("root")
+			jsonOut, _ := cmd.Flags().GetBool("json")
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			yes, _ := cmd.Flags().GetBool("yes")
+
+			if !floopDirExists(root) {
+				return errNotInitialized
+			}
+			graphStore, err := store.NewMultiGraphStore(root)
+			if err != nil {
+				return fmt.Errorf("failed to open graph store: %w", err)
+			}
+			defer graphStore.Close()
+
+			opLog := filepath.Join(root, ".floop", learning.OpLogFile)
+			op, err := learning.UndoLast(cmd.Context(), graphStore, opLog, true)
+			if err == nil && !dryRun {
+				confirmed, cerr := confirmDestructive(config.OpUndo, yes, jsonOut, func() {
+					fmt.Fprintf(out, "Undo %s of %s (%s)\n", op.Action, op.BehaviorID, op.Timestamp.Local().Format("2006-01-02 15:04"))
+				})
+				if cerr != nil {
+					return cerr
+				}
+				if !confirmed {
+					fmt.Fprintln(out, "Cancelled.")
+					return nil
+				}
+				op, err = learning.UndoLast(cmd.Context(), graphStore, opLog, false)
+			}
+			if errors.Is(err, learning.ErrNothingToUndo) {
+				if jsonOut {
+					return json.NewEncoder(out).Encode(map[string]interface{}{
+						"status": "nothing_to_undo",
+					})
+				}
+				fmt.Fprintln(out, "Nothing to undo.")
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to undo %s: %w", op.Action, err)
+			}
+
+			if jsonOut {
+				status := "undone"
+				if dryRun {
+					status = "dry_run"
+				}
+				return json.NewEncoder(out).Encode(map[string]interface{}{
+					"status":    status,
+					"operation": op,
+				})
+			}
+
+			verb := "Undid"
+			if dryRun {
+				verb = "Would undo"
+			}
+			fmt.Fprintf(out, "%s %s of %s (%s)\n", verb, op.Action, op.BehaviorID, op.Timestamp.Local().Format("2006-01-02 15:04"))
+			if len(op.Created) > 0 {
+				fmt.Fprintf(out, "  deleted:  %d behavior(s)\n", len(op.Created))
+			}
+			if len(op.Edges) > 0 {
+				fmt.Fprintf(out, "  removed:  %d edge(s)\n", len(op.Edges))
+			}
+			if len(op.Before) > 0 {
+				fmt.Fprintf(out, "  restored: %d behavior(s)\n", len(op.Before))
+			}
+			return nil
+		},
+	}
+
+	cmd.Flags().Bool("dry-run", false, "Show what would be undone without changing anything")
+	addYesFlag(cmd)
+
+	return cmd
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestUndoCmd(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	out, err := runCmd(t, newUndoCmd(), "--dry-run", "--json", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("undo --dry-run failed: %v", err)
+	}
+	var result struct {
+		Status    string `json:"status"`
+		Operation struct {
+			Action     string `json:"action"`
+			BehaviorID string `json:"behavior_id"`
+		} `json:"operation"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, out)
+	}
+	if result.Status != "dry_run" || result.Operation.Action != "learn" || result.Operation.BehaviorID != behaviorID {
+		t.Errorf("unexpected dry run result:\n%s", out)
+	}
+
+	out, err = runCmd(t, newUndoCmd(), "--yes", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("undo failed: %v", err)
+	}
+	if !strings.Contains(out, "Undid learn of "+behaviorID) {
+		t.Errorf("unexpected undo output:\n%s", out)
+	}
+
+	graphStore, err := store.NewMultiGraphStore(tmpDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := graphStore.GetNode(context.Background(), behaviorID)
+	graphStore.Close()
+	if err != nil || node != nil {
+		t.Errorf("behavior still present after undo: %v, %v", node, err)
+	}
+
+	out, err = runCmd(t, newUndoCmd(), "--yes", "--root", tmpDir)
+	if err != nil {
+		t.Fatalf("second undo failed: %v", err)
+	}
+	if !strings.Contains(out, "Nothing to undo") {
+		t.Errorf("unexpected second undo output:\n%s", out)
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -24,6 +25,7 @@ Examples:
   floop upgrade           # Migrate .sh scripts to native commands
   floop upgrade --force   # Re-configure even if already native`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			force, _ := cmd.Flags().GetBool("force")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 
@@ -32,7 +34,7 @@ Examples:
 			// Check global installation
 			homeDir, err := os.UserHomeDir()
 			if err == nil {
-				globalResult, err := upgradeScope("global", homeDir, hooks.ScopeGlobal, force, jsonOut)
+				globalResult, err := upgradeScope(out, "global", homeDir, hooks.ScopeGlobal, force, jsonOut)
 				if err != nil {
 					if !jsonOut {
 						fmt.Fprintf(os.Stderr, "Warning: global upgrade failed: %v\n", err)
@@ -44,7 +46,7 @@ Examples:
 
 			// Check project installation
 			root, _ := cmd.Flags().GetString("root")
-			projectResult, err := upgradeScope("project", root, hooks.ScopeProject, force, jsonOut)
+			projectResult, err := upgradeScope(out, "project", root, hooks.ScopeProject, force, jsonOut)
 			if err != nil {
 				if !jsonOut {
 					fmt.Fprintf(os.Stderr, "Warning: project upgrade failed: %v\n", err)
@@ -55,20 +57,20 @@ Examples:
 
 			if len(results) == 0 {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"status":  "no_installations",
 						"version": version,
 					})
 				} else {
-					fmt.Println("No floop hook installations found.")
-					fmt.Println("Run 'floop init' to set up hooks.")
+					fmt.Fprintln(out, "No floop hook installations found.")
+					fmt.Fprintln(out, "Run 'floop init' to set up hooks.")
 				}
 				return nil
 			}
 
 			if jsonOut {
 				results["version"] = version
-				json.NewEncoder(os.Stdout).Encode(results)
+				json.NewEncoder(out).Encode(results)
 			}
 
 			return nil
@@ -82,7 +84,7 @@ Examples:
 
 // upgradeScope checks and upgrades hooks in a single scope.
 // Returns nil result if no installation found.
-func upgradeScope(scopeName, configRoot string, scope hooks.HookScope, force bool, jsonOut bool) (map[string]interface{}, error) {
+func upgradeScope(out io.Writer, scopeName, configRoot string, scope hooks.HookScope, force bool, jsonOut bool) (map[string]interface{}, error) {
 	p := hooks.NewClaudePlatform()
 
 	// Check if native hooks are already configured
@@ -123,7 +125,7 @@ func upgradeScope(scopeName, configRoot string, scope hooks.HookScope, force boo
 		result["scripts_removed"] = len(oldScripts)
 
 		if !jsonOut {
-			fmt.Printf("%s: migrated %d shell script(s) to native Go commands\n",
+			fmt.Fprintf(out, "%s: migrated %d shell script(s) to native Go commands\n",
 				scopeName, len(oldScripts))
 		}
 
@@ -134,7 +136,7 @@ func upgradeScope(scopeName, configRoot string, scope hooks.HookScope, force boo
 	if !force {
 		result["status"] = "up_to_date"
 		if !jsonOut {
-			fmt.Printf("%s: hooks are up to date (native commands)\n", scopeName)
+			fmt.Fprintf(out, "%s: hooks are up to date (native commands)\n", scopeName)
 		}
 		return result, nil
 	}
@@ -147,7 +149,7 @@ func upgradeScope(scopeName, configRoot string, scope hooks.HookScope, force boo
 
 	result["status"] = "reconfigured"
 	if !jsonOut {
-		fmt.Printf("%s: reconfigured hooks (forced)\n", scopeName)
+		fmt.Fprintf(out, "%s: reconfigured hooks (forced)\n", scopeName)
 	}
 
 	return result, nil
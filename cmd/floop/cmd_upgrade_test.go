@@ -13,7 +13,7 @@ import (
 func TestUpgradeScopeNoInstallation(t *testing.T) {
 	dir := t.TempDir()
 
-	result, err := upgradeScope("test", dir, hooks.ScopeProject, false, true)
+	result, err := upgradeScope(os.Stdout, "test", dir, hooks.ScopeProject, false, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -37,7 +37,7 @@ func TestUpgradeScopeNativeUpToDate(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	result, err := upgradeScope("test", dir, hooks.ScopeProject, false, true)
+	result, err := upgradeScope(os.Stdout, "test", dir, hooks.ScopeProject, false, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -65,7 +65,7 @@ func TestUpgradeScopeMigratesOldScripts(t *testing.T) {
 	}
 
 	// Upgrade should migrate
-	result, err := upgradeScope("test", dir, hooks.ScopeProject, false, true)
+	result, err := upgradeScope(os.Stdout, "test", dir, hooks.ScopeProject, false, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -163,7 +163,7 @@ func TestUpgradeScopeForce(t *testing.T) {
 	}
 
 	// Force should reconfigure even when native hooks exist
-	result, err := upgradeScope("test", dir, hooks.ScopeProject, true, true)
+	result, err := upgradeScope(os.Stdout, "test", dir, hooks.ScopeProject, true, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 
@@ -28,6 +29,7 @@ Examples:
   floop validate --scope global   # Validate global store only
   floop validate --scope local    # Validate local store only`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			scope, _ := cmd.Flags().GetString("scope")
@@ -47,7 +49,7 @@ Examples:
 				if _, err := os.Stat(floopDir); err != nil {
 					hasLocal = false
 					if storeScope == store.ScopeLocal {
-						return fmt.Errorf(".floop not initialized. Run 'floop init' first")
+						return errNotInitialized
 					}
 				}
 			}
@@ -84,10 +86,10 @@ Examples:
 
 			// Handle validation based on scope
 			if storeScope == store.ScopeBoth {
-				return runMultiStoreValidation(ctx, root, jsonOut)
+				return runMultiStoreValidation(ctx, out, root, jsonOut)
 			}
 
-			return runSingleStoreValidation(ctx, root, storeScope, jsonOut)
+			return runSingleStoreValidation(ctx, out, root, storeScope, jsonOut)
 		},
 	}
 
@@ -97,7 +99,7 @@ Examples:
 }
 
 // runSingleStoreValidation validates a single store.
-func runSingleStoreValidation(ctx context.Context, root string, scope store.StoreScope, jsonOut bool) error {
+func runSingleStoreValidation(ctx context.Context, out io.Writer, root string, scope store.StoreScope, jsonOut bool) error {
 	// Open the appropriate store
 	var graphStore *store.SQLiteGraphStore
 	var err error
@@ -126,11 +128,11 @@ func runSingleStoreValidation(ctx context.Context, root string, scope store.Stor
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	return outputValidationResults(validationErrors, scope, jsonOut)
+	return outputValidationResults(out, validationErrors, scope, jsonOut)
 }
 
 // runMultiStoreValidation validates both local and global stores.
-func runMultiStoreValidation(ctx context.Context, root string, jsonOut bool) error {
+func runMultiStoreValidation(ctx context.Context, out io.Writer, root string, jsonOut bool) error {
 	multiStore, err := store.NewMultiGraphStore(root)
 	if err != nil {
 		return fmt.Errorf("failed to open stores: %w", err)
@@ -143,11 +145,11 @@ func runMultiStoreValidation(ctx context.Context, root string, jsonOut bool) err
 		return fmt.Errorf("validation failed: %w", err)
 	}
 
-	return outputValidationResults(validationErrors, store.ScopeBoth, jsonOut)
+	return outputValidationResults(out, validationErrors, store.ScopeBoth, jsonOut)
 }
 
 // outputValidationResults formats and outputs validation results.
-func outputValidationResults(validationErrors []store.ValidationError, scope store.StoreScope, jsonOut bool) error {
+func outputValidationResults(out io.Writer, validationErrors []store.ValidationError, scope store.StoreScope, jsonOut bool) error {
 	valid := len(validationErrors) == 0
 
 	if jsonOut {
@@ -176,23 +178,23 @@ func outputValidationResults(validationErrors []store.ValidationError, scope sto
 			output["message"] = fmt.Sprintf("Found %d validation error(s)", len(validationErrors))
 		}
 
-		return json.NewEncoder(os.Stdout).Encode(output)
+		return json.NewEncoder(out).Encode(output)
 	}
 
 	// Human-readable output
-	fmt.Printf("Validating %s store(s)...\n\n", scope)
+	fmt.Fprintf(out, "Validating %s store(s)...\n\n", scope)
 
 	if valid {
-		fmt.Println("✓ Behavior graph is valid - no issues found.")
+		fmt.Fprintln(out, "✓ Behavior graph is valid - no issues found.")
 		return nil
 	}
 
-	fmt.Printf("✗ Found %d validation error(s):\n\n", len(validationErrors))
+	fmt.Fprintf(out, "✗ Found %d validation error(s):\n\n", len(validationErrors))
 
 	for i, ve := range validationErrors {
-		fmt.Printf("%d. [%s] %s\n", i+1, ve.Issue, ve.BehaviorID)
-		fmt.Printf("   Field: %s\n", ve.Field)
-		fmt.Printf("   References: %s\n\n", ve.RefID)
+		fmt.Fprintf(out, "%d. [%s] %s\n", i+1, ve.Issue, ve.BehaviorID)
+		fmt.Fprintf(out, "   Field: %s\n", ve.Field)
+		fmt.Fprintf(out, "   References: %s\n\n", ve.RefID)
 	}
 
 	return nil
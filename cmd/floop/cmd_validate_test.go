@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"io"
 	"strings"
 	"testing"
 
@@ -62,7 +63,7 @@ func TestValidateCmdLocalNotInitialized(t *testing.T) {
 func TestOutputValidationResultsValid(t *testing.T) {
 	// Valid graph — no errors
 	var errs []store.ValidationError
-	err := outputValidationResults(errs, store.ScopeLocal, true)
+	err := outputValidationResults(io.Discard, errs, store.ScopeLocal, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -78,7 +79,7 @@ func TestOutputValidationResultsWithErrors(t *testing.T) {
 		},
 	}
 
-	err := outputValidationResults(errs, store.ScopeLocal, true)
+	err := outputValidationResults(io.Discard, errs, store.ScopeLocal, true)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -87,7 +88,7 @@ func TestOutputValidationResultsWithErrors(t *testing.T) {
 func TestOutputValidationResultsValidText(t *testing.T) {
 	var errs []store.ValidationError
 	// Non-JSON mode — writes to stdout
-	err := outputValidationResults(errs, store.ScopeLocal, false)
+	err := outputValidationResults(io.Discard, errs, store.ScopeLocal, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -109,7 +110,7 @@ func TestOutputValidationResultsWithErrorsText(t *testing.T) {
 		},
 	}
 
-	err := outputValidationResults(errs, store.ScopeBoth, false)
+	err := outputValidationResults(io.Discard, errs, store.ScopeBoth, false)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
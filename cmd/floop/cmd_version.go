@@ -13,15 +13,16 @@ func newVersionCmd() *cobra.Command {
 		Short:  "Print version information",
 		Hidden: true,
 		Run: func(cmd *cobra.Command, args []string) {
+			out := newOutput(cmd)
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			if jsonOut {
-				json.NewEncoder(cmd.OutOrStdout()).Encode(map[string]string{
+				json.NewEncoder(out).Encode(map[string]string{
 					"version": version,
 					"commit":  commit,
 					"date":    date,
 				})
 			} else {
-				fmt.Fprintf(cmd.OutOrStdout(), "floop version %s (commit: %s, built: %s)\n", version, commit, date)
+				fmt.Fprintf(out, "floop version %s (commit: %s, built: %s)\n", version, commit, date)
 			}
 		},
 	}
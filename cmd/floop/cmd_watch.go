@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/watch"
+	"github.com/spf13/cobra"
+)
+
+// watchEvent is one line of the floop watch stream.
+type watchEvent struct {
+	Timestamp time.Time         `json:"timestamp"`
+	Context   watch.State       `json:"context"`
+	Active    []watchedBehavior `json:"active"`
+	Added     []string          `json:"added,omitempty"`
+	Removed   []string          `json:"removed,omitempty"`
+	Degraded  bool              `json:"degraded,omitempty"`
+}
+
+// watchedBehavior is an active behavior in a watch event.
+type watchedBehavior struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Kind      string `json:"kind"`
+	Canonical string `json:"canonical"`
+}
+
+func newWatchCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "watch",
+		Short: "Stream active behaviors as the working context changes",
+		Long: `Watch the repository for context changes and emit the updated set of
+active behaviors, so agents don't need to poll 'floop active' on every edit.
+
+The watcher polls for:
+  - the most recently modified file (dot-directories, node_modules, and
+    vendor are ignored)
+  - the checked-out git branch
+  - the task named on the first line of .floop/task
+
+Whenever the context changes and the active set differs from the last one
+emitted, a JSON line is written with the context, the active behaviors, and
+the IDs added and removed. The first line reports the initial state.
+
+Events go to stdout, or with --socket to every client connected to a unix
+socket (clients receive the latest event on connect). Stop with Ctrl-C.
+
+Examples:
+  floop watch
+  floop watch --interval 500ms
+  floop watch --socket /tmp/floop.sock
+  echo refactoring > .floop/task   # switch task while watching`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root, _ := cmd.Flags().GetString("root")
+			interval, _ := cmd.Flags().GetDuration("interval")
+			socketPath, _ := cmd.Flags().GetString("socket")
+			task, _ := cmd.Flags().GetString("task")
+			env, _ := cmd.Flags().GetString("env")
+
+			if interval <= 0 {
+				return fmt.Errorf("--interval must be positive")
+			}
+			cfg, err := loadConfig(cmd)
+			if err != nil {
+				return fmt.Errorf("failed to load config: %w", err)
+			}
+			scope, ok := availableScope(root)
+			if !ok {
+				return fmt.Errorf("no .floop stores initialized; run 'floop init' first")
+			}
+
+			var sink io.Writer = cmd.OutOrStdout()
+			if socketPath != "" {
+				b, err := watch.Listen(socketPath)
+				if err != nil {
+					return err
+				}
+				defer b.Close()
+				sink = b
+				fmt.Fprintf(cmd.ErrOrStderr(), "floop watch streaming to %s\n", socketPath)
+			}
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+
+			w := &contextWatcher{
+				root:     root,
+				scope:    scope,
+				cfg:      cfg,
+				task:     task,
+				env:      env,
+				interval: interval,
+				enc:      json.NewEncoder(sink),
+				errOut:   cmd.ErrOrStderr(),
+			}
+			return w.run(ctx)
+		},
+	}
+
+	cmd.Flags().Duration("interval", watch.DefaultInterval, "Polling interval")
+	cmd.Flags().String("socket", "", "Stream events to clients of this unix socket instead of stdout")
+	cmd.Flags().String("task", "", "Task type (overrides .floop/task)")
+	cmd.Flags().String("env", "", "Environment (dev, staging, prod)")
+
+	return cmd
+}
+
+// contextWatcher polls the repository and emits active-behavior updates.
+type contextWatcher struct {
+	root     string
+	scope    constants.Scope
+	cfg      *config.FloopConfig
+	task     string
+	env      string
+	interval time.Duration
+	enc      *json.Encoder
+	errOut   io.Writer
+
+	active []string // IDs in the last emitted event; nil before the first
+}
+
+// run polls until ctx is cancelled.
+func (w *contextWatcher) run(ctx context.Context) error {
+	poller := watch.NewPoller(w.root)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if state, changed := poller.Poll(); changed {
+			if err := w.emit(state); err != nil {
+				fmt.Fprintf(w.errOut, "warning: %v\n", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// emit evaluates the behaviors active in state and writes an event if the
+// active set differs from the last one written.
+func (w *contextWatcher) emit(state watch.State) error {
+	start := time.Now()
+	if w.task != "" {
+		state.Task = w.task
+	}
+
+	behaviors, err := loadBehaviorsWithScope(w.root, w.scope)
+	if err != nil {
+		return fmt.Errorf("failed to load behaviors: %w", err)
+	}
+
+	ctxBuilder := activation.NewContextBuilder().
+		WithTask(state.Task).
+		WithEnvironment(w.env).
+		WithRepoRoot(w.root)
+	if state.File != "" {
+		ctxBuilder.WithFile(filepath.Join(w.root, filepath.FromSlash(state.File)))
+	}
+	actCtx := ctxBuilder.Build()
+
+	budget := activation.NewBudget(start, w.cfg.Activation.TimeBudget, w.cfg.Activation.ShedTopN, nil)
+	matches, _, degraded := activation.NewEvaluator().EvaluateWithinBudget(actCtx, behaviors, 0, budget)
+	result := activation.NewResolver().Resolve(matches)
+
+	event := watchEvent{
+		Timestamp: time.Now(),
+		Context:   state,
+		Active:    make([]watchedBehavior, 0, len(result.Active)),
+		Degraded:  degraded,
+	}
+	ids := make([]string, 0, len(result.Active))
+	for _, b := range result.Active {
+		ids = append(ids, b.ID)
+		event.Active = append(event.Active, watchedBehavior{
+			ID:        b.ID,
+			Name:      b.Name,
+			Kind:      string(b.Kind),
+			Canonical: b.Content.Canonical,
+		})
+	}
+	slices.Sort(ids)
+
+	if w.active != nil && slices.Equal(ids, w.active) {
+		return nil
+	}
+	if w.active != nil {
+		for _, id := range ids {
+			if _, found := slices.BinarySearch(w.active, id); !found {
+				event.Added = append(event.Added, id)
+			}
+		}
+		for _, id := range w.active {
+			if _, found := slices.BinarySearch(ids, id); !found {
+				event.Removed = append(event.Removed, id)
+			}
+		}
+	}
+	w.active = ids
+
+	return w.enc.Encode(event)
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/watch"
+)
+
+func decodeWatchEvents(t *testing.T, data string) []watchEvent {
+	t.Helper()
+	var events []watchEvent
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		var ev watchEvent
+		if err := json.Unmarshal(scanner.Bytes(), &ev); err != nil {
+			t.Fatalf("invalid event %q: %v", scanner.Text(), err)
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+func TestContextWatcherEmitsChanges(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	var buf bytes.Buffer
+	w := &contextWatcher{
+		root:   tmpDir,
+		scope:  constants.ScopeBoth,
+		cfg:    config.Default(),
+		enc:    json.NewEncoder(&buf),
+		errOut: io.Discard,
+	}
+
+	goState := watch.State{File: "main.go", Task: "coding"}
+	for _, state := range []watch.State{goState, goState, {File: "app.py", Task: "coding"}} {
+		if err := w.emit(state); err != nil {
+			t.Fatalf("emit(%+v) error = %v", state, err)
+		}
+	}
+
+	events := decodeWatchEvents(t, buf.String())
+	if len(events) != 2 {
+		t.Fatalf("got %d events, want 2 (unchanged active set is not re-emitted):\n%s", len(events), buf.String())
+	}
+	if len(events[0].Active) == 0 || events[0].Added != nil {
+		t.Errorf("initial event = %+v, want active behaviors and no delta", events[0])
+	}
+	if len(events[1].Removed) == 0 {
+		t.Errorf("switching to a python file should remove the go behavior, got %+v", events[1])
+	}
+}
+
+func TestWatchCmdStreamsTaskChanges(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newWatchCmd())
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetArgs([]string{"watch", "--interval", "20ms", "--root", tmpDir})
+
+	done := make(chan error, 1)
+	go func() { done <- rootCmd.ExecuteContext(ctx) }()
+
+	time.Sleep(100 * time.Millisecond)
+	if err := os.WriteFile(filepath.Join(tmpDir, ".floop", watch.TaskFile), []byte("coding\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(200 * time.Millisecond)
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Fatalf("watch failed: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("watch did not stop on cancel")
+	}
+
+	events := decodeWatchEvents(t, buf.String())
+	if len(events) == 0 {
+		t.Fatal("expected at least the initial event")
+	}
+	if events[0].Context.Task != "" {
+		t.Errorf("initial task = %q, want empty", events[0].Context.Task)
+	}
+}
+
+func TestWatchCmdRejectsBadInterval(t *testing.T) {
+	tmpDir, _ := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newWatchCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"watch", "--interval", "0s", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil {
+		t.Error("watch --interval 0s should fail")
+	}
+}
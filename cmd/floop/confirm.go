@@ -0,0 +1,49 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// addYesFlag registers the --yes/-y flag used to skip destructive-operation
+// confirmation prompts.
+func addYesFlag(cmd *cobra.Command) {
+	cmd.Flags().BoolP("yes", "y", false, "Skip confirmation prompt (for scripts)")
+}
+
+// confirmDestructive guards a destructive operation.
+//
+// Operations listed in safety.protected_operations are refused unless
+// FLOOP_ALLOW_PROTECTED names them, even when skipConfirm is set. Otherwise,
+// unless skipConfirm is set, describe prints what is about to happen and the
+// user is asked to confirm on stdin. Returns false if the user declines. In
+// JSON mode there is no one to ask, so the operation fails with
+// confirmation_required instead.
+func confirmDestructive(op string, skipConfirm, jsonOut bool, describe func()) (bool, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return false, fmt.Errorf("failed to load config: %w", err)
+	}
+	if err := cfg.Safety.CheckAllowed(op); err != nil {
+		return false, err
+	}
+	if skipConfirm {
+		return true, nil
+	}
+	if jsonOut {
+		return false, newCLIError(codeConfirmationRequired, map[string]interface{}{"operation": op},
+			"%s needs confirmation; pass --yes to proceed", op)
+	}
+
+	describe()
+	fmt.Print("\nConfirm? [y/N]: ")
+	reader := bufio.NewReader(os.Stdin)
+	response, _ := reader.ReadString('\n')
+	response = strings.TrimSpace(strings.ToLower(response))
+	return response == "y" || response == "yes", nil
+}
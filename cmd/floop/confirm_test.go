@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+)
+
+// protectOperations writes a config protecting ops to the isolated home.
+func protectOperations(t *testing.T, ops ...string) {
+	t.Helper()
+	cfg := config.Default()
+	cfg.Safety.ProtectedOperations = ops
+	if err := cfg.Save(); err != nil {
+		t.Fatalf("saving config: %v", err)
+	}
+}
+
+func TestConfirmDestructive(t *testing.T) {
+	tests := []struct {
+		name          string
+		protected     []string
+		env           string
+		skipConfirm   bool
+		jsonOut       bool
+		wantConfirmed bool
+		wantErr       bool
+	}{
+		{"unprotected with yes", nil, "", true, false, true, false},
+		// Stdin is empty under go test, so the prompt reads no answer.
+		{"unprotected without yes declines", nil, "", false, false, false, false},
+		{"json without yes needs confirmation", nil, "", false, true, false, true},
+		{"json with yes", nil, "", true, true, true, false},
+		{"protected with yes still refused", []string{config.OpPackRemove}, "", true, false, false, true},
+		{"protected unlocked", []string{config.OpPackRemove}, config.OpPackRemove, true, false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			isolateHome(t, t.TempDir())
+			t.Setenv(config.EnvAllowProtected, tt.env)
+			protectOperations(t, tt.protected...)
+
+			confirmed, err := confirmDestructive(config.OpPackRemove, tt.skipConfirm, tt.jsonOut, func() {})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("confirmDestructive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if confirmed != tt.wantConfirmed {
+				t.Errorf("confirmDestructive() = %v, want %v", confirmed, tt.wantConfirmed)
+			}
+		})
+	}
+}
+
+func TestForgetRefusedWhenProtected(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+	t.Setenv(config.EnvAllowProtected, "")
+	protectOperations(t, config.OpForget)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newForgetCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"forget", behaviorID, "--yes", "--root", tmpDir})
+	if err := rootCmd.Execute(); err == nil {
+		t.Fatal("expected protected forget to be refused")
+	}
+
+	t.Setenv(config.EnvAllowProtected, config.OpForget)
+	rootCmd = newTestRootCmd()
+	rootCmd.AddCommand(newForgetCmd())
+	rootCmd.SetOut(&bytes.Buffer{})
+	rootCmd.SetArgs([]string{"forget", behaviorID, "--yes", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("forget with token failed: %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// addContextFlag registers the repeatable --context key=value flag.
+func addContextFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("context", nil, "Custom context value as key=value (repeatable, e.g. team=payments)")
+}
+
+// contextFlag parses the --context flags into custom context values. A key
+// given more than once becomes a list, which a when condition matches if
+// any value does. Built-in keys such as task or file_path are rejected;
+// they have their own flags.
+func contextFlag(cmd *cobra.Command) (map[string]interface{}, error) {
+	pairs, _ := cmd.Flags().GetStringArray("context")
+	custom, err := parseKeyValues("--context", pairs)
+	if err != nil {
+		return nil, err
+	}
+	for key := range custom {
+		if models.IsBuiltinField(key) {
+			return nil, fmt.Errorf("invalid --context %s: a built-in context key", key)
+		}
+	}
+	return custom, nil
+}
+
+// parseKeyValues parses key=value pairs given with flag. A key given more
+// than once becomes a list of its values.
+func parseKeyValues(flag string, pairs []string) (map[string]interface{}, error) {
+	values := make(map[string]interface{}, len(pairs))
+	for _, pair := range pairs {
+		key, value, ok := strings.Cut(pair, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || key == "" || value == "" {
+			return nil, fmt.Errorf("invalid %s %q: use key=value", flag, pair)
+		}
+		switch prev := values[key].(type) {
+		case nil:
+			values[key] = value
+		case string:
+			values[key] = []string{prev, value}
+		case []string:
+			values[key] = append(prev, value)
+		}
+	}
+	return values, nil
+}
+
+// printCustomContext writes the custom context values, sorted by key, one
+// per indented line.
+func printCustomContext(w io.Writer, custom map[string]interface{}) {
+	keys := make([]string, 0, len(custom))
+	for key := range custom {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := fmt.Sprint(custom[key])
+		if list, ok := custom[key].([]string); ok {
+			value = strings.Join(list, ", ")
+		}
+		fmt.Fprintf(w, "  %s: %s\n", key, value)
+	}
+}
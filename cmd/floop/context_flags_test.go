@@ -0,0 +1,58 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestContextFlag(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		want    map[string]interface{}
+		wantErr bool
+	}{
+		{
+			name: "none",
+			want: map[string]interface{}{},
+		},
+		{
+			name: "single values",
+			args: []string{"--context", "team=payments", "--context", "stage = canary"},
+			want: map[string]interface{}{"team": "payments", "stage": "canary"},
+		},
+		{
+			name: "repeated key becomes a list",
+			args: []string{"--context", "team=payments", "--context", "team=billing"},
+			want: map[string]interface{}{"team": []string{"payments", "billing"}},
+		},
+		{
+			name: "value may contain equals",
+			args: []string{"--context", "query=a=b"},
+			want: map[string]interface{}{"query": "a=b"},
+		},
+		{name: "missing value", args: []string{"--context", "team"}, wantErr: true},
+		{name: "empty key", args: []string{"--context", "=payments"}, wantErr: true},
+		{name: "built-in key", args: []string{"--context", "task=testing"}, wantErr: true},
+		{name: "built-in alias", args: []string{"--context", "env=prod"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := &cobra.Command{}
+			addContextFlag(cmd)
+			if err := cmd.ParseFlags(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			got, err := contextFlag(cmd)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("contextFlag() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("contextFlag() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
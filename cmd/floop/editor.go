@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// errMergeSkipped is returned by editMergedBehavior when the user declines
+// the proposed merge.
+var errMergeSkipped = errors.New("merge skipped")
+
+// runEditor opens path in the user's editor ($VISUAL, then $EDITOR, then vi)
+// and waits for it to exit. Replaced in tests.
+var runEditor = func(path string) error {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := strings.Fields(editor)
+	c := exec.Command(args[0], append(args[1:], path)...)
+	c.Stdin = os.Stdin
+	c.Stdout = os.Stdout
+	c.Stderr = os.Stderr
+	if err := c.Run(); err != nil {
+		return fmt.Errorf("running editor %q: %w", editor, err)
+	}
+	return nil
+}
+
+// editMergedBehavior lets the user edit a proposed merge in their editor
+// before it is committed. The buffer is pre-filled with the merged behavior
+// as YAML. An invalid edit reopens the editor with the error shown; saving
+// an empty buffer, or an invalid one unchanged, skips the merge
+// (errMergeSkipped). Returns the behavior to save and whether the user
+// changed it.
+func editMergedBehavior(out io.Writer, merged *models.Behavior, sources []*models.Behavior) (*models.Behavior, bool, error) {
+	draft, err := dedup.EncodeMergeDraft(merged)
+	if err != nil {
+		return nil, false, err
+	}
+
+	f, err := os.CreateTemp("", "floop-merge-*.yaml")
+	if err != nil {
+		return nil, false, fmt.Errorf("creating merge buffer: %w", err)
+	}
+	path := f.Name()
+	f.Close()
+	defer os.Remove(path)
+
+	var problem error
+	for {
+		var buf bytes.Buffer
+		if problem != nil {
+			fmt.Fprintf(&buf, "# ERROR: %v\n#\n", problem)
+		}
+		buf.WriteString("# Proposed merge of:\n")
+		for _, s := range sources {
+			fmt.Fprintf(&buf, "#   [%s] %s\n", s.ID, s.Name)
+		}
+		buf.WriteString("#\n# Edit the merged behavior, then save and quit to apply it.\n")
+		buf.WriteString("# Delete everything to skip this merge.\n\n")
+		buf.Write(draft)
+		written := buf.Bytes()
+
+		if err := os.WriteFile(path, written, 0600); err != nil {
+			return nil, false, fmt.Errorf("writing merge buffer: %w", err)
+		}
+		if err := runEditor(path); err != nil {
+			return nil, false, err
+		}
+		edited, err := os.ReadFile(path)
+		if err != nil {
+			return nil, false, fmt.Errorf("reading merge buffer: %w", err)
+		}
+
+		result, adjusted, err := dedup.ApplyMergeDraft(merged, edited)
+		switch {
+		case errors.Is(err, dedup.ErrEmptyMergeDraft):
+			return nil, false, errMergeSkipped
+		case err == nil:
+			return result, adjusted, nil
+		case problem != nil && bytes.Equal(edited, written):
+			// Saved an invalid buffer without fixing it: give up on this merge.
+			return nil, false, errMergeSkipped
+		}
+
+		fmt.Fprintf(out, "Invalid merge: %v\n", err)
+		problem = err
+		draft = stripComments(edited)
+	}
+}
+
+// stripComments removes full-line YAML comments so a re-opened buffer does
+// not accumulate stale headers.
+func stripComments(data []byte) []byte {
+	var buf bytes.Buffer
+	for _, line := range strings.SplitAfter(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		buf.WriteString(line)
+	}
+	return bytes.TrimLeft(buf.Bytes(), "\n")
+}
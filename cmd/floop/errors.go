@@ -0,0 +1,182 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// Error codes reported in the JSON error envelope. Agents branch on the
+// code; the message is for people.
+const (
+	codeError          = "error"           // any failure without a more specific code
+	codeUsage          = "usage"           // unknown command, bad flag, or wrong arguments
+	codeInvalid        = "invalid"         // an argument or setting value was rejected
+	codeNotInitialized = "not_initialized" // the project or global .floop is missing
+	codeNotFound       = "not_found"       // a behavior, key, or other named item does not exist
+	codeLocked         = "locked"          // another floop process holds the store
+	codeReviewRequired = "review_required" // learn --fail-review: the behavior needs review
+	codeEmpty          = "empty"           // active --fail-empty: no behavior matched
+
+	codeConfirmationRequired = "confirmation_required" // a destructive operation in JSON mode without --yes
+)
+
+// Exit codes, part of the CLI's contract with scripts and agent harnesses.
+// Existing codes never change meaning.
+const (
+	exitOK             = 0
+	exitError          = 1
+	exitNotInitialized = 2
+	exitNotFound       = 3
+	exitReviewRequired = 4
+	exitEmpty          = 5
+	exitUsage          = 6 // also rejected values (codeInvalid)
+	exitLocked         = 7
+)
+
+// exitCode returns the process exit code for a command's error.
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
+	switch errorCode(err) {
+	case codeNotInitialized:
+		return exitNotInitialized
+	case codeNotFound:
+		return exitNotFound
+	case codeReviewRequired:
+		return exitReviewRequired
+	case codeEmpty:
+		return exitEmpty
+	case codeUsage, codeInvalid, codeConfirmationRequired:
+		return exitUsage
+	case codeLocked:
+		return exitLocked
+	default:
+		return exitError
+	}
+}
+
+// cliError is a command failure carrying an error code and structured
+// details for the JSON error envelope. A silent error only sets the exit
+// code: the command has already written its result.
+type cliError struct {
+	code    string
+	msg     string
+	details map[string]interface{}
+	err     error
+	silent  bool
+}
+
+// Error implements error.
+func (e *cliError) Error() string {
+	return e.msg
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *cliError) Unwrap() error {
+	return e.err
+}
+
+// newCLIError returns a failure with code, a message formatted from format
+// and args, and optional details. A %w verb in format wraps the error.
+func newCLIError(code string, details map[string]interface{}, format string, args ...interface{}) error {
+	err := fmt.Errorf(format, args...)
+	return &cliError{code: code, msg: err.Error(), details: details, err: errors.Unwrap(err)}
+}
+
+// errNotInitialized reports a project without a .floop directory.
+var errNotInitialized = &cliError{code: codeNotInitialized, msg: ".floop not initialized. Run 'floop init' first"}
+
+// errNoActiveBehaviors sets the exit code of 'floop active --fail-empty'
+// when nothing matched.
+var errNoActiveBehaviors = &cliError{code: codeEmpty, msg: "no active behaviors for this context", silent: true}
+
+// errReviewRequired sets the exit code of 'floop learn --fail-review' when
+// the learned behavior needs review.
+var errReviewRequired = &cliError{code: codeReviewRequired, msg: "learned behavior requires review", silent: true}
+
+// errorEnvelope is the JSON written for a failed command with --json.
+type errorEnvelope struct {
+	Error   string                 `json:"error"`
+	Code    string                 `json:"code"`
+	Details map[string]interface{} `json:"details,omitempty"`
+}
+
+// errorCode returns the envelope code for err.
+func errorCode(err error) string {
+	var ce *cliError
+	switch {
+	case errors.As(err, &ce):
+		return ce.code
+	case errors.Is(err, store.ErrLocked):
+		return codeLocked
+	case strings.HasPrefix(err.Error(), "unknown command "):
+		return codeUsage
+	default:
+		return codeError
+	}
+}
+
+// newErrorEnvelope builds the JSON envelope for err.
+func newErrorEnvelope(err error) errorEnvelope {
+	env := errorEnvelope{Error: err.Error(), Code: errorCode(err)}
+	var ce *cliError
+	if errors.As(err, &ce) {
+		env.Details = ce.details
+	}
+	return env
+}
+
+// reportError writes a failed command's error: as a JSON envelope on
+// stdout with --json, so agents parse failures from the same stream as
+// results, otherwise as text on stderr. Silent errors are not written.
+func reportError(cmd *cobra.Command, stdout, stderr io.Writer, err error) {
+	var ce *cliError
+	if errors.As(err, &ce) && ce.silent {
+		return
+	}
+	jsonOut := false
+	if cmd != nil {
+		jsonOut, _ = cmd.Flags().GetBool("json")
+	}
+	if !jsonOut && (cmd == nil || !cmd.Flags().Parsed()) {
+		// Flags are not parsed for an unknown command.
+		jsonOut = slices.Contains(os.Args[1:], "--json")
+	}
+	if jsonOut {
+		json.NewEncoder(stdout).Encode(newErrorEnvelope(err))
+		return
+	}
+	fmt.Fprintf(stderr, "Error: %v\n", err)
+}
+
+// markUsageErrors gives flag parsing and argument validation failures
+// anywhere under root the usage code.
+func markUsageErrors(root *cobra.Command) {
+	root.SetFlagErrorFunc(func(_ *cobra.Command, err error) error {
+		return &cliError{code: codeUsage, msg: err.Error(), err: err}
+	})
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		if args := cmd.Args; args != nil {
+			cmd.Args = func(cmd *cobra.Command, a []string) error {
+				if err := args(cmd, a); err != nil {
+					return &cliError{code: codeUsage, msg: err.Error(), err: err}
+				}
+				return nil
+			}
+		}
+		for _, sub := range cmd.Commands() {
+			walk(sub)
+		}
+	}
+	walk(root)
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/spf13/cobra"
+)
+
+// wantErrorCode fails the test unless err is non-nil with the given
+// envelope code.
+func wantErrorCode(t *testing.T, err error, code string) {
+	t.Helper()
+	if err == nil {
+		t.Fatalf("expected %s error, got nil", code)
+	}
+	if got := errorCode(err); got != code {
+		t.Fatalf("error code = %q, want %q (error: %v)", got, code, err)
+	}
+}
+
+func TestErrorCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"plain", errors.New("boom"), codeError},
+		{"not initialized", errNotInitialized, codeNotInitialized},
+		{"wrapped cli error", fmt.Errorf("loading: %w", newCLIError(codeNotFound, nil, "behavior not found: b-1")), codeNotFound},
+		{"locked store", fmt.Errorf("opening: %w", store.ErrLocked), codeLocked},
+		{"unknown command", errors.New(`unknown command "bogus" for "floop"`), codeUsage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errorCode(tt.err); got != tt.want {
+				t.Errorf("errorCode(%v) = %q, want %q", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewCLIErrorWraps(t *testing.T) {
+	err := newCLIError(codeInvalid, nil, "%w", store.ErrReadOnly)
+	if !errors.Is(err, store.ErrReadOnly) {
+		t.Error("newCLIError should wrap the %w operand")
+	}
+	if err.Error() != store.ErrReadOnly.Error() {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
+
+func TestReportError(t *testing.T) {
+	err := newCLIError(codeNotFound, map[string]interface{}{"id": "b-1"}, "behavior not found: %s", "b-1")
+
+	t.Run("json", func(t *testing.T) {
+		rootCmd := newTestRootCmd()
+		if perr := rootCmd.ParseFlags([]string{"--json"}); perr != nil {
+			t.Fatal(perr)
+		}
+		var stdout, stderr bytes.Buffer
+		reportError(rootCmd, &stdout, &stderr, err)
+
+		if stderr.Len() != 0 {
+			t.Errorf("stderr = %q, want empty", stderr.String())
+		}
+		var env map[string]interface{}
+		if jerr := json.Unmarshal(stdout.Bytes(), &env); jerr != nil {
+			t.Fatalf("stdout is not JSON: %v (%q)", jerr, stdout.String())
+		}
+		if env["error"] != "behavior not found: b-1" || env["code"] != codeNotFound {
+			t.Errorf("envelope = %v", env)
+		}
+		if details, _ := env["details"].(map[string]interface{}); details["id"] != "b-1" {
+			t.Errorf("details = %v", env["details"])
+		}
+	})
+
+	t.Run("text", func(t *testing.T) {
+		var stdout, stderr bytes.Buffer
+		reportError(newTestRootCmd(), &stdout, &stderr, err)
+		if stdout.Len() != 0 {
+			t.Errorf("stdout = %q, want empty", stdout.String())
+		}
+		if got := stderr.String(); got != "Error: behavior not found: b-1\n" {
+			t.Errorf("stderr = %q", got)
+		}
+	})
+}
+
+func TestMarkUsageErrors(t *testing.T) {
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(&cobra.Command{
+		Use:  "one",
+		Args: cobra.ExactArgs(1),
+		RunE: func(*cobra.Command, []string) error { return nil },
+	})
+	markUsageErrors(rootCmd)
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+
+	rootCmd.SetArgs([]string{"one"})
+	wantErrorCode(t, rootCmd.Execute(), codeUsage)
+
+	rootCmd.SetArgs([]string{"one", "x", "--bogus"})
+	err := rootCmd.Execute()
+	wantErrorCode(t, err, codeUsage)
+	if !strings.Contains(err.Error(), "unknown flag") {
+		t.Errorf("error = %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{errors.New("boom"), 1},
+		{errNotInitialized, 2},
+		{newCLIError(codeNotFound, nil, "behavior not found"), 3},
+		{errReviewRequired, 4},
+		{errNoActiveBehaviors, 5},
+		{newCLIError(codeUsage, nil, "accepts 1 arg(s), received 0"), 6},
+		{newCLIError(codeInvalid, nil, "invalid scope"), 6},
+		{fmt.Errorf("opening: %w", store.ErrLocked), 7},
+	}
+	for _, tt := range tests {
+		if got := exitCode(tt.err); got != tt.want {
+			t.Errorf("exitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
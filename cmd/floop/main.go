@@ -1,19 +1,23 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"path/filepath"
 	"runtime/debug"
 	"time"
 
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/store"
 	"github.com/spf13/cobra"
 )
 
 // createLLMClient creates an LLM client based on config settings.
 // Returns nil if LLM is not enabled or configured.
-// Supports providers: anthropic, openai, ollama, subagent, local.
+// Supports the llm package's registered providers plus subagent and local.
+// API provider calls are recorded in the usage ledger (see llmUsageLedger).
 // When provider is "subagent" or not specified with LLM enabled, attempts auto-detection.
 // An optional timeout overrides the default for subagent and API clients.
 func createLLMClient(cfg *config.FloopConfig, timeout ...time.Duration) llm.Client {
@@ -47,19 +51,7 @@ func createLLMClient(cfg *config.FloopConfig, timeout ...time.Duration) llm.Clie
 		return nil
 	}
 
-	clientCfg := llm.ClientConfig{
-		Provider: cfg.LLM.Provider,
-		APIKey:   cfg.LLM.APIKey,
-		BaseURL:  cfg.LLM.BaseURL,
-		Model:    cfg.LLM.ComparisonModel,
-		Timeout:  resolveTimeout(cfg.LLM.Timeout),
-	}
-
 	switch cfg.LLM.Provider {
-	case "ollama", "openai":
-		return llm.NewOpenAIClient(clientCfg)
-	case "anthropic":
-		return llm.NewAnthropicClient(clientCfg)
 	case "local":
 		return llm.NewLocalClient(llm.LocalConfig{
 			LibPath:            cfg.LLM.LocalLibPath,
@@ -76,9 +68,36 @@ func createLLMClient(cfg *config.FloopConfig, timeout ...time.Duration) llm.Clie
 			return client
 		}
 		return nil
-	default:
+	}
+
+	// Everything else is an API provider from the llm registry
+	client, err := llm.NewClient(llm.ClientConfig{
+		Provider:   cfg.LLM.Provider,
+		APIKey:     cfg.LLM.APIKey,
+		BaseURL:    cfg.LLM.BaseURL,
+		Model:      cfg.LLM.ComparisonModel,
+		Timeout:    resolveTimeout(cfg.LLM.Timeout),
+		MaxRetries: cfg.LLM.MaxRetries,
+		Ledger:     llmUsageLedger(cfg),
+	})
+	if err != nil {
 		return nil
 	}
+	return client
+}
+
+// llmUsageLedger returns the ledger in the global .floop directory that
+// records LLM usage and enforces the configured daily budget, or nil if the
+// home directory cannot be determined.
+func llmUsageLedger(cfg *config.FloopConfig) *llm.Ledger {
+	globalDir, err := store.GlobalFloopPath()
+	if err != nil {
+		return nil
+	}
+	return llm.NewLedger(filepath.Join(globalDir, llm.UsageFile), llm.Budget{
+		DailyCostUSD: cfg.LLM.DailyBudgetUSD,
+		DailyCalls:   cfg.LLM.DailyCallLimit,
+	})
 }
 
 var (
@@ -123,9 +142,33 @@ func versionString() string {
 	return fmt.Sprintf("%s (commit: %s, built: %s)", version, commit, date)
 }
 
-func main() {
-	resolveVersion()
+// applyChaosFlag exports --chaos as FLOOP_CHAOS so every store opened by the
+// command injects the requested faults.
+func applyChaosFlag(cmd *cobra.Command, args []string) error {
+	spec, _ := cmd.Flags().GetString("chaos")
+	if spec == "" {
+		return nil
+	}
+	if _, err := store.ParseChaosSpec(spec); err != nil {
+		return fmt.Errorf("invalid --chaos: %w", err)
+	}
+	return os.Setenv(store.EnvChaos, spec)
+}
+
+// applyNoWaitFlag exports --no-wait as FLOOP_LOCK_WAIT=0 so every store
+// opened by the command fails at once when another floop process holds it.
+func applyNoWaitFlag(cmd *cobra.Command) error {
+	if noWait, _ := cmd.Flags().GetBool("no-wait"); !noWait {
+		return nil
+	}
+	return os.Setenv(store.EnvLockWait, "0")
+}
 
+// newRootCmd builds the command tree. Construction only defines commands and
+// flags; config, stores, and other resources are loaded once a command runs,
+// and stores open lazily on first use, so cheap commands like version and
+// config get never touch SQLite.
+func newRootCmd() *cobra.Command {
 	rootCmd := &cobra.Command{
 		Use:     "floop",
 		Short:   "Feedback loop - behavior learning for AI agents",
@@ -141,57 +184,151 @@ context-aware behavior activation for consistent agent operation.`,
 	// Global flags
 	rootCmd.PersistentFlags().Bool("json", false, "Output as JSON (for agent consumption)")
 	rootCmd.PersistentFlags().String("root", ".", "Project root directory")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress non-error output")
+	rootCmd.PersistentFlags().CountP("verbose", "v", "Increase output detail (-v score breakdowns, -vv store timings)")
+	rootCmd.PersistentFlags().Bool("log-json", false, "Write diagnostics to stderr as JSON lines")
+	rootCmd.PersistentFlags().String("profile", "", "Agent profile for activation settings (e.g. claude, copilot; default: config profile)")
+	rootCmd.PersistentFlags().Bool("no-wait", false, "Fail at once if another floop process is writing the store (default: wait up to FLOOP_LOCK_WAIT, 10s)")
+
+	// Hidden: store fault injection for resilience testing
+	rootCmd.PersistentFlags().String("chaos", "", "Inject store faults (e.g. write=0.2,sync=0.1,partial=0.1,latency=50ms,seed=1)")
+	_ = rootCmd.PersistentFlags().MarkHidden("chaos")
+	// main reports errors, as a JSON envelope with --json. Usage is shown
+	// for bad flags and arguments, which fail before PersistentPreRunE,
+	// but not for failures while the command runs.
+	rootCmd.SilenceErrors = true
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		cmd.SilenceUsage = true
+		if err := validateVerbosityFlags(cmd); err != nil {
+			return err
+		}
+		configureLogging(cmd)
+		if err := applyNoWaitFlag(cmd); err != nil {
+			return err
+		}
+		return applyChaosFlag(cmd, args)
+	}
 
 	// Add subcommands
 	rootCmd.AddCommand(
 		newVersionCmd(),
 		newInitCmd(),
+		newDeinitCmd(),
 		newLearnCmd(),
+		newNewCmd(),
 		newReprocessCmd(),
+		newCorrectionsCmd(),
 		newListCmd(),
 		newActiveCmd(),
+		newWatchCmd(),
 		newGraphCmd(),
 		newShowCmd(),
 		newWhyCmd(),
+		newGrepCmd(),
+		newSearchCmd(),
 		newPromptCmd(),
+		newInjectCmd(),
 		newMCPServerCmd(),
 		// Curation commands
 		newForgetCmd(),
 		newDeprecateCmd(),
 		newRestoreCmd(),
+		newSnoozeCmd(),
+		newHistoryCmd(),
+		newRevertCmd(),
+		newUndoCmd(),
 		newMergeCmd(),
+		newPromoteCmd(),
+		newDemoteCmd(),
+		newReviewCmd(),
+		newTUICmd(),
 		// Management commands
 		newDeduplicateCmd(),
 		newValidateCmd(),
+		newDoctorCmd(),
+		newLintCmd(),
 		newConfigCmd(),
+		newLLMCmd(),
+		newIndexCmd(),
 		newPackCmd(),
+		newSchemaCmd(),
+		newGCCmd(),
+		newMaintainCmd(),
+		newSyncCmd(),
 		// Token optimization commands
 		newSummarizeCmd(),
 		newStatsCmd(),
+		newInsightsCmd(),
 		// Hook support commands
 		newDetectCorrectionCmd(),
 		newActivateCmd(),
+		newSessionCmd(),
+		newFeedbackCmd(),
 		// Graph management commands
 		newConnectCmd(),
 		newDeriveEdgesCmd(),
+		newTuneSimilarityCmd(),
 		// Backup/restore commands
 		newBackupCmd(),
 		newRestoreFromBackupCmd(),
+		newDiffCmd(),
+		newArchiveCmd(),
+		newExportCmd(),
+		newImportCmd(),
+		newEvalCmd(),
 		// Hook management commands
 		newUpgradeCmd(),
 		// Tag management commands
 		newTagsCmd(),
 		// Native hook commands (replacing shell scripts)
 		newHookCmd(),
+		newHooksCmd(),
 		// Memory consolidation commands
 		newIngestCmd(),
 		newConsolidateCmd(),
 		newEventsCmd(),
 		newMigrateCmd(),
 	)
+	markUsageErrors(rootCmd)
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
+	return rootCmd
+}
+
+func main() {
+	resolveVersion()
+
+	cmd, err := execute(context.Background(), newRootCmd())
+	if err != nil {
+		reportError(cmd, os.Stdout, os.Stderr, err)
+		os.Exit(exitCode(err))
+	}
+}
+
+// execute runs the command tree under rootCmd with tracing and usage
+// metrics. The config they need is loaded once, when the command that runs
+// and its --root are known, so --help, --version, and flag errors never load
+// it.
+func execute(ctx context.Context, rootCmd *cobra.Command) (*cobra.Command, error) {
+	start := time.Now()
+	var cfg *config.FloopConfig
+	endTracing := func(*cobra.Command, error) {}
+
+	preRun := rootCmd.PersistentPreRunE
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if loaded, err := loadConfig(cmd); err == nil {
+			cfg = loaded
+			var tracingCtx context.Context
+			tracingCtx, endTracing = startTracing(cmd.Context(), cfg)
+			cmd.SetContext(tracingCtx)
+		}
+		if preRun == nil {
+			return nil
+		}
+		return preRun(cmd, args)
 	}
+
+	cmd, err := rootCmd.ExecuteContextC(ctx)
+	endTracing(cmd, err)
+	recordCommand(cmd, cfg, start, err)
+	return cmd, err
 }
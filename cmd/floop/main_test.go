@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -21,9 +22,33 @@ func newTestRootCmd() *cobra.Command {
 	}
 	rootCmd.PersistentFlags().Bool("json", false, "Output as JSON")
 	rootCmd.PersistentFlags().String("root", ".", "Project root directory")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "Suppress non-error output")
+	rootCmd.PersistentFlags().CountP("verbose", "v", "Increase output detail")
+	rootCmd.PersistentFlags().Bool("log-json", false, "Write diagnostics to stderr as JSON lines")
+	rootCmd.PersistentFlags().String("profile", "", "Agent profile for activation settings")
 	return rootCmd
 }
 
+// runCmd executes cmd under a test root command, passing args after the
+// command's name, and returns what it wrote to stdout.
+func runCmd(t *testing.T, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	return runCmdWithInput(t, "", cmd, args...)
+}
+
+// runCmdWithInput is runCmd with stdin read from input.
+func runCmdWithInput(t *testing.T, input string, cmd *cobra.Command, args ...string) (string, error) {
+	t.Helper()
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(cmd)
+	rootCmd.SetArgs(append([]string{cmd.Name()}, args...))
+	rootCmd.SetIn(strings.NewReader(input))
+	var out bytes.Buffer
+	rootCmd.SetOut(&out)
+	err := rootCmd.Execute()
+	return out.String(), err
+}
+
 // isolateHome sets HOME to a temp directory to avoid touching real ~/.floop/
 // MUST be called for any test that creates stores.
 // Uses t.Setenv for thread-safe env var handling and automatic cleanup.
@@ -1044,3 +1069,39 @@ func TestDeprecateWithReplacement(t *testing.T) {
 	}
 	graphStore.Close()
 }
+
+// TestLearnNoWait checks that --no-wait makes a learn fail at once while
+// another process holds the store's lock.
+func TestLearnNoWait(t *testing.T) {
+	tmpDir := setupDeinitTest(t)
+	t.Setenv(store.EnvLockWait, "")
+
+	ctx := context.Background()
+	homeDir, _ := os.UserHomeDir()
+	for _, root := range []string{tmpDir, homeDir} {
+		if err := os.MkdirAll(filepath.Join(root, ".floop"), 0700); err != nil {
+			t.Fatal(err)
+		}
+		lock, err := store.AcquireLock(ctx, filepath.Join(root, ".floop", store.LockFile), 0)
+		if err != nil {
+			t.Fatalf("AcquireLock: %v", err)
+		}
+		defer lock.Release()
+	}
+
+	rootCmd := newTestRootCmd()
+	rootCmd.PersistentFlags().Bool("no-wait", false, "")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		return applyNoWaitFlag(cmd)
+	}
+	rootCmd.AddCommand(newLearnCmd())
+	rootCmd.SetArgs([]string{"learn", "--right", "use uv for python packages", "--no-wait", "--root", tmpDir})
+	rootCmd.SetOut(&bytes.Buffer{})
+	err := rootCmd.Execute()
+	if !errors.Is(err, store.ErrLocked) {
+		t.Fatalf("learn error = %v, want store locked", err)
+	}
+	if got := os.Getenv(store.EnvLockWait); got != "0" {
+		t.Errorf("%s = %q, want 0", store.EnvLockWait, got)
+	}
+}
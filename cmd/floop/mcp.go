@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"github.com/nvandessel/floop/internal/mcp"
 	"github.com/spf13/cobra"
@@ -10,19 +11,29 @@ import (
 
 func newMCPServerCmd() *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "mcp-server",
-		Short: "Run floop as an MCP (Model Context Protocol) server",
+		Use:     "mcp-server",
+		Aliases: []string{"serve"},
+		Short:   "Run floop as an MCP (Model Context Protocol) server",
 		Long: `Start an MCP server that exposes floop functionality over stdio.
 
 The MCP server allows AI tools (Continue.dev, Cursor, Cline, Windsurf, GitHub Copilot)
 to invoke floop tools directly:
 
   • floop_active  - Get active behaviors for current context
+  • floop_context - Report a context change and get back the behavior delta
   • floop_learn   - Capture corrections and extract behaviors
   • floop_list    - List all behaviors or corrections
+  • floop_show    - Show full details of a behavior
+  • floop_why     - Explain why a behavior is or isn't active
 
 The server communicates via JSON-RPC 2.0 over stdin/stdout, following the
-Model Context Protocol specification.
+Model Context Protocol specification. With --http, it instead serves the MCP
+streamable HTTP transport on the given address, so long-running agents can keep
+one connection open and receive change notifications over SSE. With --grpc,
+it serves the floop gRPC API (api/floop/v1) instead: ProcessCorrection,
+GetActive, Feedback, Search, and a WatchActive stream of active-set changes.
+
+'floop serve' is an alias for 'floop mcp-server'.
 
 Configuration examples for each AI tool can be found in:
   docs/integrations/mcp-server.md
@@ -38,20 +49,60 @@ Example usage in Continue.dev config.json:
       }
     }
   }
+
+Serve over HTTP on localhost:
+
+  floop mcp-server --http 127.0.0.1:7345
+
+The HTTP transport only listens on loopback and only accepts requests whose
+Host names loopback, unless FLOOP_SERVER_TOKEN is set. With a token it may
+listen on any address and every request must send
+"Authorization: Bearer <token>". Browser requests from other origins are
+refused either way. --grpc follows the same rule for TCP addresses, or
+takes unix:<path> for a Unix socket only the current user can open.
+
+Serve gRPC on localhost:
+
+  floop mcp-server --grpc 127.0.0.1:7346
+
+The server reloads ~/.floop/config.yaml when it changes, on SIGHUP, or on
+POST /admin/reload (HTTP mode, loopback only). Invalid configs are rejected
+and the running config is kept. llm.* changes take effect after a restart.
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			root, _ := cmd.Flags().GetString("root")
+			httpAddr, _ := cmd.Flags().GetString("http")
+			grpcAddr, _ := cmd.Flags().GetString("grpc")
+			profile, _ := cmd.Flags().GetString("profile")
 
 			// Create MCP server
 			server, err := mcp.NewServer(&mcp.Config{
 				Name:    "floop",
 				Version: version,
 				Root:    root,
+				Profile: profile,
+				Token:   os.Getenv(mcp.TokenEnv),
 			})
 			if err != nil {
 				return fmt.Errorf("failed to create MCP server: %w", err)
 			}
 
+			if httpAddr != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "floop MCP server listening on http://%s\n", httpAddr)
+				if err := server.RunHTTP(context.Background(), httpAddr); err != nil {
+					return fmt.Errorf("MCP server error: %w", err)
+				}
+				return nil
+			}
+
+			if grpcAddr != "" {
+				fmt.Fprintf(cmd.ErrOrStderr(), "floop gRPC server listening on %s\n", grpcAddr)
+				if err := server.RunGRPC(context.Background(), grpcAddr); err != nil {
+					return fmt.Errorf("gRPC server error: %w", err)
+				}
+				return nil
+			}
+
 			// Run server (blocks until client disconnects or SIGTERM/SIGINT)
 			if err := server.Run(context.Background()); err != nil {
 				return fmt.Errorf("MCP server error: %w", err)
@@ -61,5 +112,9 @@ Example usage in Continue.dev config.json:
 		},
 	}
 
+	cmd.Flags().String("http", "", "Serve the streamable HTTP transport on this address (e.g. 127.0.0.1:7345) instead of stdio")
+	cmd.Flags().String("grpc", "", "Serve the gRPC API on this address (e.g. 127.0.0.1:7346 or unix:/path/floop.sock) instead of stdio")
+	cmd.MarkFlagsMutuallyExclusive("http", "grpc")
+
 	return cmd
 }
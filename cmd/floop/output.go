@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"github.com/nvandessel/floop/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+// Verbosity levels selected by the global -q and -v flags.
+const (
+	verbosityQuiet   = -1 // -q: errors only
+	verbosityNormal  = 0
+	verbosityVerbose = 1 // -v: score breakdowns and extra detail
+	verbosityDebug   = 2 // -vv: adds store timings
+)
+
+// output is the shared writer for command output. Text written through it
+// honors the global -q/--quiet and -v/--verbose flags. JSON output is always
+// written: the caller asked for it explicitly with --json.
+type output struct {
+	w     io.Writer
+	level int
+	json  bool
+}
+
+// newOutput returns the output writer for cmd's stdout.
+func newOutput(cmd *cobra.Command) *output {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	verbose, _ := cmd.Flags().GetCount("verbose")
+	jsonOut, _ := cmd.Flags().GetBool("json")
+
+	level := verbose
+	if quiet {
+		level = verbosityQuiet
+	}
+	return &output{w: cmd.OutOrStdout(), level: level, json: jsonOut}
+}
+
+// Write implements io.Writer. Output is discarded in quiet mode unless
+// --json is set.
+func (o *output) Write(p []byte) (int, error) {
+	if o.level <= verbosityQuiet && !o.json {
+		return len(p), nil
+	}
+	return o.w.Write(p)
+}
+
+// Verbose reports whether -v or higher is set for text output.
+func (o *output) Verbose() bool {
+	return o.level >= verbosityVerbose && !o.json
+}
+
+// Verbosef writes detail shown with -v or higher. Suppressed with --json.
+func (o *output) Verbosef(format string, args ...interface{}) {
+	if o.Verbose() {
+		fmt.Fprintf(o.w, format, args...)
+	}
+}
+
+// Debugf writes detail shown with -vv. Suppressed with --json.
+func (o *output) Debugf(format string, args ...interface{}) {
+	if o.level >= verbosityDebug && !o.json {
+		fmt.Fprintf(o.w, format, args...)
+	}
+}
+
+// Timed runs fn and, with -vv, reports how long it took.
+func (o *output) Timed(label string, fn func() error) error {
+	start := time.Now()
+	err := fn()
+	o.Debugf("[timing] %s: %s\n", label, time.Since(start).Round(time.Microsecond))
+	return err
+}
+
+// validateVerbosityFlags rejects combining -q with -v.
+func validateVerbosityFlags(cmd *cobra.Command) error {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	verbose, _ := cmd.Flags().GetCount("verbose")
+	if quiet && verbose > 0 {
+		return fmt.Errorf("--quiet and --verbose cannot be used together")
+	}
+	return nil
+}
+
+// logLevel maps the global -q and -v flags to the level of diagnostics
+// logged to stderr: -q keeps only errors, -v adds debug records, and -vv
+// adds trace records.
+func logLevel(cmd *cobra.Command) slog.Level {
+	quiet, _ := cmd.Flags().GetBool("quiet")
+	verbose, _ := cmd.Flags().GetCount("verbose")
+	switch {
+	case quiet:
+		return slog.LevelError
+	case verbose >= verbosityDebug:
+		return logging.LevelTrace
+	case verbose >= verbosityVerbose:
+		return slog.LevelDebug
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// configureLogging installs the default slog logger for the command, so
+// warnings from floop's packages go to stderr at the level the global flags
+// select, as JSON lines with --log-json.
+func configureLogging(cmd *cobra.Command) {
+	logJSON, _ := cmd.Flags().GetBool("log-json")
+	slog.SetDefault(logging.NewCLILogger(cmd.ErrOrStderr(), logLevel(cmd), logJSON))
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/logging"
+)
+
+// newOutputForArgs parses args on a test root command and returns its output
+// writer along with the buffer it writes to.
+func newOutputForArgs(t *testing.T, args ...string) (*output, *bytes.Buffer) {
+	t.Helper()
+	rootCmd := newTestRootCmd()
+	if err := rootCmd.ParseFlags(args); err != nil {
+		t.Fatalf("ParseFlags(%v): %v", args, err)
+	}
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	return newOutput(rootCmd), &buf
+}
+
+func TestOutputLevels(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []string
+		wantNormal  bool
+		wantVerbose bool
+		wantDebug   bool
+	}{
+		{"default", nil, true, false, false},
+		{"quiet", []string{"-q"}, false, false, false},
+		{"verbose", []string{"-v"}, true, true, false},
+		{"debug", []string{"-vv"}, true, true, true},
+		{"json ignores verbose", []string{"--json", "-vv"}, true, false, false},
+		{"json ignores quiet", []string{"--json", "-q"}, true, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, buf := newOutputForArgs(t, tt.args...)
+			fmt.Fprintln(out, "normal")
+			out.Verbosef("verbose\n")
+			out.Debugf("debug\n")
+
+			got := buf.String()
+			if strings.Contains(got, "normal") != tt.wantNormal {
+				t.Errorf("normal output present = %v, want %v", !tt.wantNormal, tt.wantNormal)
+			}
+			if strings.Contains(got, "verbose") != tt.wantVerbose {
+				t.Errorf("verbose output present = %v, want %v", !tt.wantVerbose, tt.wantVerbose)
+			}
+			if strings.Contains(got, "debug") != tt.wantDebug {
+				t.Errorf("debug output present = %v, want %v", !tt.wantDebug, tt.wantDebug)
+			}
+		})
+	}
+}
+
+func TestOutputTimed(t *testing.T) {
+	out, buf := newOutputForArgs(t, "-vv")
+	wantErr := fmt.Errorf("boom")
+	if err := out.Timed("load", func() error { return wantErr }); err != wantErr {
+		t.Errorf("Timed() error = %v, want %v", err, wantErr)
+	}
+	if !strings.HasPrefix(buf.String(), "[timing] load: ") {
+		t.Errorf("missing timing line, got %q", buf.String())
+	}
+
+	out, buf = newOutputForArgs(t, "-v")
+	_ = out.Timed("load", func() error { return nil })
+	if buf.Len() != 0 {
+		t.Errorf("timing printed below -vv: %q", buf.String())
+	}
+}
+
+func TestValidateVerbosityFlags(t *testing.T) {
+	tests := []struct {
+		args    []string
+		wantErr bool
+	}{
+		{nil, false},
+		{[]string{"-q"}, false},
+		{[]string{"-vv"}, false},
+		{[]string{"-q", "-v"}, true},
+	}
+	for _, tt := range tests {
+		rootCmd := newTestRootCmd()
+		if err := rootCmd.ParseFlags(tt.args); err != nil {
+			t.Fatalf("ParseFlags(%v): %v", tt.args, err)
+		}
+		if err := validateVerbosityFlags(rootCmd); (err != nil) != tt.wantErr {
+			t.Errorf("validateVerbosityFlags(%v) error = %v, wantErr %v", tt.args, err, tt.wantErr)
+		}
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		args []string
+		want slog.Level
+	}{
+		{nil, slog.LevelInfo},
+		{[]string{"-q"}, slog.LevelError},
+		{[]string{"-v"}, slog.LevelDebug},
+		{[]string{"-vv"}, logging.LevelTrace},
+	}
+	for _, tt := range tests {
+		rootCmd := newTestRootCmd()
+		if err := rootCmd.ParseFlags(tt.args); err != nil {
+			t.Fatalf("ParseFlags(%v): %v", tt.args, err)
+		}
+		if got := logLevel(rootCmd); got != tt.want {
+			t.Errorf("logLevel(%v) = %v, want %v", tt.args, got, tt.want)
+		}
+	}
+}
+
+func TestConfigureLogging(t *testing.T) {
+	prev := slog.Default()
+	t.Cleanup(func() { slog.SetDefault(prev) })
+
+	rootCmd := newTestRootCmd()
+	if err := rootCmd.ParseFlags([]string{"--log-json"}); err != nil {
+		t.Fatalf("ParseFlags: %v", err)
+	}
+	var stdout, stderr bytes.Buffer
+	rootCmd.SetOut(&stdout)
+	rootCmd.SetErr(&stderr)
+	configureLogging(rootCmd)
+
+	slog.Debug("hidden")
+	slog.Warn("failed to save config", "error", "disk full")
+
+	if stdout.Len() != 0 {
+		t.Errorf("diagnostics written to stdout: %q", stdout.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal(stderr.Bytes(), &record); err != nil {
+		t.Fatalf("stderr is not a single JSON record: %v (%q)", err, stderr.String())
+	}
+	if record["level"] != "warning" || record["msg"] != "failed to save config" || record["error"] != "disk full" {
+		t.Errorf("record = %v", record)
+	}
+}
+
+func TestListCmdQuiet(t *testing.T) {
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+
+	run := func(args ...string) string {
+		rootCmd := newTestRootCmd()
+		rootCmd.AddCommand(newInitCmd(), newListCmd())
+		var buf bytes.Buffer
+		rootCmd.SetOut(&buf)
+		rootCmd.SetArgs(append(args, "--root", tmpDir))
+		if err := rootCmd.Execute(); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+		return buf.String()
+	}
+
+	run("init")
+	if got := run("list", "-q"); got != "" {
+		t.Errorf("list -q printed output: %q", got)
+	}
+	if got := run("list", "-q", "--json"); !strings.Contains(got, `"count"`) {
+		t.Errorf("list -q --json missing JSON output: %q", got)
+	}
+}
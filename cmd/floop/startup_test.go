@@ -0,0 +1,101 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// startupBudget is the latency budget for commands that should not touch the
+// behavior stores, measured in-process with a large global store present.
+const startupBudget = 250 * time.Millisecond
+
+// largeStoreSize is the number of behaviors seeded for the budget test.
+const largeStoreSize = 2000
+
+// seedLargeGlobalStore fills the global store under home with behaviors.
+func seedLargeGlobalStore(t *testing.T, home string) {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(home)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore: %v", err)
+	}
+	ctx := context.Background()
+	for i := 0; i < largeStoreSize; i++ {
+		_, err := s.AddNode(ctx, store.Node{
+			ID:   fmt.Sprintf("b-%05d", i),
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    fmt.Sprintf("behavior-%d", i),
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": fmt.Sprintf("Behavior number %d", i)},
+			},
+			Metadata: map[string]interface{}{"confidence": 0.7},
+		})
+		if err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestStartupBudget(t *testing.T) {
+	if testing.Short() {
+		t.Skip("seeds a large store")
+	}
+	tmpDir := t.TempDir()
+	isolateHome(t, tmpDir)
+	seedLargeGlobalStore(t, filepath.Join(tmpDir, "home"))
+
+	projectDir := filepath.Join(tmpDir, "project")
+	if err := os.MkdirAll(projectDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	commands := [][]string{
+		{"version"},
+		{"--version"},
+		{"config", "get", "llm.enabled"},
+		{"config", "list", "--json"},
+		{"schema", "dump", "active-request"},
+		{"--help"},
+	}
+	for _, args := range commands {
+		t.Run(fmt.Sprint(args), func(t *testing.T) {
+			cmd := newRootCmd()
+			cmd.SetOut(&bytes.Buffer{})
+			cmd.SetErr(&bytes.Buffer{})
+			cmd.SetArgs(append([]string{"--root", projectDir}, args...))
+
+			start := time.Now()
+			if _, err := execute(context.Background(), cmd); err != nil {
+				t.Fatalf("%v: %v", args, err)
+			}
+			if elapsed := time.Since(start); elapsed > startupBudget {
+				t.Errorf("%v took %s, budget %s", args, elapsed, startupBudget)
+			}
+
+			// Opening the local store would create .floop in the project.
+			if _, err := os.Stat(filepath.Join(projectDir, ".floop")); err == nil {
+				t.Errorf("%v opened the local store", args)
+			}
+		})
+	}
+}
+
+func TestNewRootCmdRegistersCommands(t *testing.T) {
+	cmd := newRootCmd()
+	for _, name := range []string{"version", "config", "active", "learn", "pack", "gc", "schema"} {
+		if c, _, err := cmd.Find([]string{name}); err != nil || c.Name() != name {
+			t.Errorf("command %q not registered", name)
+		}
+	}
+}
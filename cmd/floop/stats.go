@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"sort"
+	"time"
 
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
@@ -23,11 +24,17 @@ func newStatsCmd() *cobra.Command {
 Shows activation counts, follow rates, and ranking scores to help
 understand which behaviors are most valuable and which may need review.
 
+Analytics cover the most and least activated behaviors, follow vs
+override ratios, activated behaviors whose ACT-R base-level score is
+decaying toward the floor, weekly correction volume, and graph
+connectivity (edges, isolated behaviors, average degree).
+
 Examples:
   floop stats              # Show all stats
   floop stats --top 10     # Show top 10 by usage
   floop stats --sort score # Sort by ranking score`,
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			topN, _ := cmd.Flags().GetInt("top")
@@ -51,12 +58,12 @@ Examples:
 
 			if len(nodes) == 0 {
 				if jsonOut {
-					json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+					json.NewEncoder(out).Encode(map[string]interface{}{
 						"behaviors": []interface{}{},
 						"summary":   map[string]int{},
 					})
 				} else {
-					fmt.Println("No behaviors found.")
+					fmt.Fprintln(out, "No behaviors found.")
 				}
 				return nil
 			}
@@ -213,40 +220,52 @@ Examples:
 				"behaviors":         tokenBudgetBehaviors,
 			}
 
+			corrections, err := loadCorrections(root)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "warning: failed to read corrections: %v\n", err)
+			}
+			analytics, err := computeStatsAnalytics(ctx, graphStore, behaviors, corrections, time.Now())
+			if err != nil {
+				return fmt.Errorf("failed to compute analytics: %w", err)
+			}
+
 			// Output
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"behaviors":    stats,
 					"summary":      summary,
 					"token_budget": tokenBudgetInfo,
+					"analytics":    analytics,
 				})
 			} else {
-				fmt.Printf("Behavior Statistics\n")
-				fmt.Printf("===================\n\n")
-
-				fmt.Printf("Summary:\n")
-				fmt.Printf("  Total behaviors:   %d\n", len(nodes))
-				fmt.Printf("  With summaries:    %d\n", withSummary)
-				fmt.Printf("  Total activations: %d\n", totalActivations)
-				fmt.Printf("  Total followed:    %d\n", totalFollowed)
-				fmt.Printf("  Total confirmed:   %d\n", totalConfirmed)
-				fmt.Printf("  Total overridden:  %d\n", totalOverridden)
-				fmt.Printf("\n")
-
-				fmt.Printf("By kind:\n")
+				fmt.Fprintf(out, "Behavior Statistics\n")
+				fmt.Fprintf(out, "===================\n\n")
+
+				fmt.Fprintf(out, "Summary:\n")
+				fmt.Fprintf(out, "  Total behaviors:   %d\n", len(nodes))
+				fmt.Fprintf(out, "  With summaries:    %d\n", withSummary)
+				fmt.Fprintf(out, "  Total activations: %d\n", totalActivations)
+				fmt.Fprintf(out, "  Total followed:    %d\n", totalFollowed)
+				fmt.Fprintf(out, "  Total confirmed:   %d\n", totalConfirmed)
+				fmt.Fprintf(out, "  Total overridden:  %d\n", totalOverridden)
+				fmt.Fprintf(out, "\n")
+
+				fmt.Fprintf(out, "By kind:\n")
 				for kind, count := range kindCounts {
-					fmt.Printf("  %s: %d\n", kind, count)
+					fmt.Fprintf(out, "  %s: %d\n", kind, count)
 				}
-				fmt.Printf("\n")
+				fmt.Fprintf(out, "\n")
+
+				printStatsAnalytics(out, analytics)
 
 				// Token budget section
-				fmt.Printf("Token Budget:\n")
-				fmt.Printf("  Budget:       %d tokens\n", budget)
-				fmt.Printf("  Used:         %d tokens (%d%%)\n", plan.TotalTokens, int(utilization*100))
-				fmt.Printf("  Full:         %d behaviors (%d tokens)\n", len(plan.FullBehaviors), fullTokens)
-				fmt.Printf("  Summarized:   %d behaviors (%d tokens)\n", len(plan.SummarizedBehaviors), summaryTokens)
-				fmt.Printf("  Omitted:      %d behaviors\n", len(plan.OmittedBehaviors))
-				fmt.Printf("\n")
+				fmt.Fprintf(out, "Token Budget:\n")
+				fmt.Fprintf(out, "  Budget:       %d tokens\n", budget)
+				fmt.Fprintf(out, "  Used:         %d tokens (%d%%)\n", plan.TotalTokens, int(utilization*100))
+				fmt.Fprintf(out, "  Full:         %d behaviors (%d tokens)\n", len(plan.FullBehaviors), fullTokens)
+				fmt.Fprintf(out, "  Summarized:   %d behaviors (%d tokens)\n", len(plan.SummarizedBehaviors), summaryTokens)
+				fmt.Fprintf(out, "  Omitted:      %d behaviors\n", len(plan.OmittedBehaviors))
+				fmt.Fprintf(out, "\n")
 
 				// Top 5 by token cost
 				if len(stats) > 0 {
@@ -261,7 +280,7 @@ Examples:
 						topCount = len(topByTokens)
 					}
 
-					fmt.Printf("Top %d by token cost:\n", topCount)
+					fmt.Fprintf(out, "Top %d by token cost:\n", topCount)
 					for _, s := range topByTokens[:topCount] {
 						shortID := s.ID
 						if len(shortID) > 8 {
@@ -271,16 +290,16 @@ Examples:
 						if preview == "" {
 							preview = truncatePreview(s.Kind, 40)
 						}
-						fmt.Printf("  %-8s  %4d tokens  %q\n", shortID, s.TokenCost, preview)
+						fmt.Fprintf(out, "  %-8s  %4d tokens  %q\n", shortID, s.TokenCost, preview)
 					}
-					fmt.Printf("\n")
+					fmt.Fprintf(out, "\n")
 				}
 
 				if len(stats) > 0 {
-					fmt.Printf("Behaviors (sorted by %s):\n\n", sortBy)
-					fmt.Printf("%-8s %-30s %-12s %6s %6s %6s %8s\n",
+					fmt.Fprintf(out, "Behaviors (sorted by %s):\n\n", sortBy)
+					fmt.Fprintf(out, "%-8s %-30s %-12s %6s %6s %6s %8s\n",
 						"ID", "Name", "Kind", "Act", "Fol", "Conf", "Rate")
-					fmt.Println(repeatChar('-', 86))
+					fmt.Fprintln(out, repeatChar('-', 86))
 
 					for _, s := range stats {
 						shortID := s.ID
@@ -296,7 +315,7 @@ Examples:
 							kind = kind[:12]
 						}
 
-						fmt.Printf("%-8s %-30s %-12s %6d %6d %6d %7.0f%%\n",
+						fmt.Fprintf(out, "%-8s %-30s %-12s %6d %6d %6d %7.0f%%\n",
 							shortID, name, kind,
 							s.TimesActivated, s.TimesFollowed, s.TimesConfirmed,
 							s.FollowRate*100)
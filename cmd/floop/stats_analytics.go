@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+const (
+	// statsRankedLimit is how many behaviors the most/least activated lists hold.
+	statsRankedLimit = 5
+
+	// decayThreshold is the ACT-R base-level score below which an activated
+	// behavior is reported as decaying toward the activation floor. A behavior
+	// activated once drops below it roughly three weeks after creation.
+	decayThreshold = 0.2
+
+	// correctionWeeksShown is how many recent weeks the table view lists.
+	correctionWeeksShown = 8
+)
+
+// statsAnalytics aggregates behavior stats across the store.
+type statsAnalytics struct {
+	MostActivated    []activationRef    `json:"most_activated"`
+	LeastActivated   []activationRef    `json:"least_activated"`
+	Feedback         feedbackRatios     `json:"feedback"`
+	Decaying         []decayingBehavior `json:"decaying"`
+	CorrectionVolume []correctionWeek   `json:"correction_volume"`
+	Connectivity     graphConnectivity  `json:"connectivity"`
+}
+
+type activationRef struct {
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	TimesActivated int    `json:"times_activated"`
+}
+
+// feedbackRatios compares positive signals (followed + confirmed) with overrides.
+type feedbackRatios struct {
+	Followed      int     `json:"followed"`
+	Confirmed     int     `json:"confirmed"`
+	Overridden    int     `json:"overridden"`
+	FollowRatio   float64 `json:"follow_ratio"`
+	OverrideRatio float64 `json:"override_ratio"`
+}
+
+type decayingBehavior struct {
+	ID             string  `json:"id"`
+	Name           string  `json:"name"`
+	BaseLevel      float64 `json:"base_level"`
+	TimesActivated int     `json:"times_activated"`
+	AgeDays        int     `json:"age_days"`
+}
+
+// correctionWeek counts corrections captured in the week starting on Week (a Monday).
+type correctionWeek struct {
+	Week  string `json:"week"`
+	Count int    `json:"count"`
+}
+
+type graphConnectivity struct {
+	Edges     int            `json:"edges"`
+	ByKind    map[string]int `json:"by_kind"`
+	Isolated  int            `json:"isolated"`
+	AvgDegree float64        `json:"avg_degree"`
+}
+
+// computeStatsAnalytics builds the analytics section of floop stats.
+func computeStatsAnalytics(ctx context.Context, gs store.GraphStore, behaviors []models.Behavior, corrections []models.Correction, now time.Time) (statsAnalytics, error) {
+	a := statsAnalytics{
+		MostActivated:  []activationRef{},
+		LeastActivated: []activationRef{},
+		Decaying:       []decayingBehavior{},
+	}
+
+	byActivation := make([]models.Behavior, len(behaviors))
+	copy(byActivation, behaviors)
+	sort.SliceStable(byActivation, func(i, j int) bool {
+		if byActivation[i].Stats.TimesActivated != byActivation[j].Stats.TimesActivated {
+			return byActivation[i].Stats.TimesActivated > byActivation[j].Stats.TimesActivated
+		}
+		return byActivation[i].ID < byActivation[j].ID
+	})
+	limit := statsRankedLimit
+	if limit > len(byActivation) {
+		limit = len(byActivation)
+	}
+	for _, b := range byActivation[:limit] {
+		a.MostActivated = append(a.MostActivated, newActivationRef(b))
+	}
+	for i := len(byActivation) - 1; i >= len(byActivation)-limit; i-- {
+		a.LeastActivated = append(a.LeastActivated, newActivationRef(byActivation[i]))
+	}
+
+	actr := ranking.DefaultACTRConfig()
+	for _, b := range behaviors {
+		a.Feedback.Followed += b.Stats.TimesFollowed
+		a.Feedback.Confirmed += b.Stats.TimesConfirmed
+		a.Feedback.Overridden += b.Stats.TimesOverridden
+
+		// Same practice count the relevance scorer uses.
+		n := b.Stats.TimesActivated
+		if b.Stats.SessionsActivated > 0 {
+			n = b.Stats.SessionsActivated
+		}
+		age := now.Sub(b.Stats.CreatedAt)
+		if n <= 0 || age <= 0 || b.Stats.CreatedAt.IsZero() {
+			continue
+		}
+		if score := ranking.BaseLevelScore(n, age, actr); score < decayThreshold {
+			a.Decaying = append(a.Decaying, decayingBehavior{
+				ID:             b.ID,
+				Name:           b.Name,
+				BaseLevel:      score,
+				TimesActivated: b.Stats.TimesActivated,
+				AgeDays:        int(age.Hours() / 24),
+			})
+		}
+	}
+	sort.Slice(a.Decaying, func(i, j int) bool {
+		return a.Decaying[i].BaseLevel < a.Decaying[j].BaseLevel
+	})
+	positive := a.Feedback.Followed + a.Feedback.Confirmed
+	if total := positive + a.Feedback.Overridden; total > 0 {
+		a.Feedback.FollowRatio = float64(positive) / float64(total)
+		a.Feedback.OverrideRatio = float64(a.Feedback.Overridden) / float64(total)
+	}
+
+	a.CorrectionVolume = correctionVolumeByWeek(corrections)
+
+	conn, err := behaviorConnectivity(ctx, gs, behaviors)
+	if err != nil {
+		return a, err
+	}
+	a.Connectivity = conn
+
+	return a, nil
+}
+
+func newActivationRef(b models.Behavior) activationRef {
+	return activationRef{ID: b.ID, Name: b.Name, TimesActivated: b.Stats.TimesActivated}
+}
+
+// correctionVolumeByWeek counts corrections per week, oldest first. Weeks
+// without corrections are left out.
+func correctionVolumeByWeek(corrections []models.Correction) []correctionWeek {
+	counts := make(map[string]int)
+	for _, c := range corrections {
+		if c.Timestamp.IsZero() {
+			continue
+		}
+		counts[weekStart(c.Timestamp).Format("2006-01-02")]++
+	}
+	weeks := make([]correctionWeek, 0, len(counts))
+	for week, count := range counts {
+		weeks = append(weeks, correctionWeek{Week: week, Count: count})
+	}
+	sort.Slice(weeks, func(i, j int) bool { return weeks[i].Week < weeks[j].Week })
+	return weeks
+}
+
+// weekStart returns midnight UTC on the Monday of t's week.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	offset := (int(t.Weekday()) + 6) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
+
+// behaviorConnectivity summarizes the edges touching behavior nodes.
+func behaviorConnectivity(ctx context.Context, gs store.GraphStore, behaviors []models.Behavior) (graphConnectivity, error) {
+	conn := graphConnectivity{ByKind: make(map[string]int)}
+	seen := make(map[string]bool)
+	degrees := 0
+	for _, b := range behaviors {
+		edges, err := gs.GetEdges(ctx, b.ID, store.DirectionBoth, "")
+		if err != nil {
+			return conn, fmt.Errorf("failed to get edges for %s: %w", b.ID, err)
+		}
+		if len(edges) == 0 {
+			conn.Isolated++
+		}
+		degrees += len(edges)
+		for _, e := range edges {
+			key := e.Source + "\x00" + e.Target + "\x00" + string(e.Kind)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			conn.Edges++
+			conn.ByKind[string(e.Kind)]++
+		}
+	}
+	if len(behaviors) > 0 {
+		conn.AvgDegree = float64(degrees) / float64(len(behaviors))
+	}
+	return conn, nil
+}
+
+// printStatsAnalytics renders the analytics section as tables.
+func printStatsAnalytics(w io.Writer, a statsAnalytics) {
+	printActivationTable(w, "Most activated:", a.MostActivated)
+	printActivationTable(w, "Least activated:", a.LeastActivated)
+
+	fmt.Fprintf(w, "Follow vs override:\n")
+	fmt.Fprintf(w, "  Followed + confirmed: %d (%.0f%%)\n", a.Feedback.Followed+a.Feedback.Confirmed, a.Feedback.FollowRatio*100)
+	fmt.Fprintf(w, "  Overridden:           %d (%.0f%%)\n", a.Feedback.Overridden, a.Feedback.OverrideRatio*100)
+	fmt.Fprintf(w, "\n")
+
+	fmt.Fprintf(w, "Decaying (base-level < %.2f):\n", decayThreshold)
+	if len(a.Decaying) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	for _, d := range a.Decaying {
+		fmt.Fprintf(w, "  %-22s %-30s %6.2f %4d act %5d days\n",
+			d.ID, truncatePreview(d.Name, 27), d.BaseLevel, d.TimesActivated, d.AgeDays)
+	}
+	fmt.Fprintf(w, "\n")
+
+	fmt.Fprintf(w, "Corrections per week:\n")
+	if len(a.CorrectionVolume) == 0 {
+		fmt.Fprintf(w, "  none\n")
+	}
+	weeks := a.CorrectionVolume
+	if len(weeks) > correctionWeeksShown {
+		weeks = weeks[len(weeks)-correctionWeeksShown:]
+	}
+	for _, wk := range weeks {
+		fmt.Fprintf(w, "  %s  %4d  %s\n", wk.Week, wk.Count, repeatChar('#', min(wk.Count, 40)))
+	}
+	fmt.Fprintf(w, "\n")
+
+	fmt.Fprintf(w, "Graph connectivity:\n")
+	fmt.Fprintf(w, "  Edges:       %d\n", a.Connectivity.Edges)
+	kinds := make([]string, 0, len(a.Connectivity.ByKind))
+	for kind := range a.Connectivity.ByKind {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		fmt.Fprintf(w, "    %-12s %d\n", kind, a.Connectivity.ByKind[kind])
+	}
+	fmt.Fprintf(w, "  Isolated:    %d behaviors\n", a.Connectivity.Isolated)
+	fmt.Fprintf(w, "  Avg degree:  %.2f\n", a.Connectivity.AvgDegree)
+	fmt.Fprintf(w, "\n")
+}
+
+func printActivationTable(w io.Writer, title string, refs []activationRef) {
+	fmt.Fprintln(w, title)
+	for _, r := range refs {
+		fmt.Fprintf(w, "  %-22s %-30s %6d\n", r.ID, truncatePreview(r.Name, 27), r.TimesActivated)
+	}
+	fmt.Fprintf(w, "\n")
+}
@@ -2,10 +2,13 @@ package main
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tiering"
 	"github.com/nvandessel/floop/internal/tokens"
 )
@@ -412,3 +415,90 @@ func TestTruncatePreview(t *testing.T) {
 		})
 	}
 }
+
+func TestComputeStatsAnalytics(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 4, 12, 0, 0, 0, time.UTC) // a Wednesday
+	behaviors := []models.Behavior{
+		{ID: "b-busy", Name: "busy", Stats: models.BehaviorStats{
+			TimesActivated: 40, TimesFollowed: 6, TimesConfirmed: 2, TimesOverridden: 2, CreatedAt: now.Add(-48 * time.Hour)}},
+		{ID: "b-stale", Name: "stale", Stats: models.BehaviorStats{
+			TimesActivated: 1, CreatedAt: now.Add(-365 * 24 * time.Hour)}},
+		{ID: "b-new", Name: "new", Stats: models.BehaviorStats{CreatedAt: now.Add(-time.Hour)}},
+	}
+
+	s := store.NewInMemoryGraphStore()
+	for i := range behaviors {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&behaviors[i])); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddEdge(ctx, store.Edge{Source: "b-busy", Target: "b-stale", Kind: store.EdgeKindSimilarTo, Weight: 1, CreatedAt: now}); err != nil {
+		t.Fatal(err)
+	}
+
+	corrections := []models.Correction{
+		{ID: "c1", Timestamp: now},
+		{ID: "c2", Timestamp: now.Add(-48 * time.Hour)}, // Monday, same week
+		{ID: "c3", Timestamp: now.Add(-7 * 24 * time.Hour)},
+	}
+
+	a, err := computeStatsAnalytics(ctx, s, behaviors, corrections, now)
+	if err != nil {
+		t.Fatalf("computeStatsAnalytics() error = %v", err)
+	}
+
+	if a.MostActivated[0].ID != "b-busy" || a.LeastActivated[0].ID != "b-new" {
+		t.Errorf("most/least = %v / %v", a.MostActivated, a.LeastActivated)
+	}
+	if a.Feedback.FollowRatio != 0.8 || a.Feedback.OverrideRatio != 0.2 {
+		t.Errorf("feedback = %+v, want 0.8/0.2", a.Feedback)
+	}
+	if len(a.Decaying) != 1 || a.Decaying[0].ID != "b-stale" || a.Decaying[0].AgeDays != 365 {
+		t.Errorf("decaying = %+v, want only b-stale", a.Decaying)
+	}
+	wantWeeks := []correctionWeek{{Week: "2026-02-23", Count: 1}, {Week: "2026-03-02", Count: 2}}
+	if len(a.CorrectionVolume) != len(wantWeeks) || a.CorrectionVolume[0] != wantWeeks[0] || a.CorrectionVolume[1] != wantWeeks[1] {
+		t.Errorf("correction volume = %v, want %v", a.CorrectionVolume, wantWeeks)
+	}
+	c := a.Connectivity
+	if c.Edges != 1 || c.ByKind["similar-to"] != 1 || c.Isolated != 1 {
+		t.Errorf("connectivity = %+v", c)
+	}
+	if want := 2.0 / 3.0; c.AvgDegree != want {
+		t.Errorf("avg degree = %v, want %v", c.AvgDegree, want)
+	}
+}
+
+func TestStatsCmdAnalytics(t *testing.T) {
+	tmpDir, behaviorID := setupQueryTest(t)
+
+	rootCmd := newTestRootCmd()
+	rootCmd.AddCommand(newStatsCmd())
+	var buf bytes.Buffer
+	rootCmd.SetOut(&buf)
+	rootCmd.SetArgs([]string{"stats", "--json", "--root", tmpDir})
+	if err := rootCmd.Execute(); err != nil {
+		t.Fatalf("stats --json failed: %v", err)
+	}
+
+	var result struct {
+		Analytics struct {
+			MostActivated    []activationRef   `json:"most_activated"`
+			CorrectionVolume []correctionWeek  `json:"correction_volume"`
+			Connectivity     graphConnectivity `json:"connectivity"`
+		} `json:"analytics"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if len(result.Analytics.MostActivated) != 1 || result.Analytics.MostActivated[0].ID != behaviorID {
+		t.Errorf("most_activated = %v, want %s", result.Analytics.MostActivated, behaviorID)
+	}
+	if len(result.Analytics.CorrectionVolume) != 1 || result.Analytics.CorrectionVolume[0].Count != 1 {
+		t.Errorf("correction_volume = %v, want one correction", result.Analytics.CorrectionVolume)
+	}
+	if result.Analytics.Connectivity.Isolated != 1 {
+		t.Errorf("connectivity = %+v, want 1 isolated", result.Analytics.Connectivity)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"os"
 
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
@@ -21,22 +20,43 @@ func newSummarizeCmd() *cobra.Command {
 Summaries are used in tiered injection when the full behavior content
 would exceed the token budget. Each summary is ~60 characters.
 
+Summaries are generated from canonical content by compression rules. With
+--llm, behaviors the rules can only truncate are summarized by the
+configured LLM instead.
+
 Examples:
-  floop summarize abc123      # Generate summary for specific behavior
-  floop summarize --all       # Generate summaries for all behaviors
-  floop summarize --missing   # Only generate for behaviors without summaries`,
+  floop summarize abc123          # Generate summary for specific behavior
+  floop summarize --all           # Generate summaries for all behaviors
+  floop summarize --missing       # Only generate for behaviors without summaries
+  floop summarize --missing --llm # Use the LLM for long behaviors`,
 		Args: cobra.MaximumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
+			out := newOutput(cmd)
 			root, _ := cmd.Flags().GetString("root")
 			jsonOut, _ := cmd.Flags().GetBool("json")
 			allBehaviors, _ := cmd.Flags().GetBool("all")
 			missingOnly, _ := cmd.Flags().GetBool("missing")
+			useLLM, _ := cmd.Flags().GetBool("llm")
 
 			// Validate flags
 			if len(args) == 0 && !allBehaviors && !missingOnly {
 				return fmt.Errorf("specify a behavior ID or use --all/--missing")
 			}
 
+			// Create summarizer
+			var summarizer summarization.Summarizer = summarization.NewRuleSummarizer(summarization.DefaultConfig())
+			if useLLM {
+				floopCfg, err := loadConfig(cmd)
+				if err != nil {
+					return fmt.Errorf("loading config: %w", err)
+				}
+				llmClient := createLLMClient(floopCfg)
+				if llmClient == nil || !llmClient.Available() {
+					return fmt.Errorf("--llm requires a configured LLM (see 'floop config set llm.enabled true')")
+				}
+				summarizer = summarization.NewLLMSummarizer(llmClient, summarization.DefaultConfig())
+			}
+
 			// Open graph store
 			graphStore, err := store.NewMultiGraphStore(root)
 			if err != nil {
@@ -46,9 +66,6 @@ Examples:
 
 			ctx := context.Background()
 
-			// Create summarizer
-			summarizer := summarization.NewRuleSummarizer(summarization.DefaultConfig())
-
 			// Results tracking
 			var results []summaryResult
 
@@ -94,29 +111,29 @@ Examples:
 
 			// Output results
 			if jsonOut {
-				json.NewEncoder(os.Stdout).Encode(map[string]interface{}{
+				json.NewEncoder(out).Encode(map[string]interface{}{
 					"results": results,
 					"count":   len(results),
 					"updated": countUpdated(results),
 				})
 			} else {
 				if len(results) == 0 {
-					fmt.Println("No behaviors to summarize.")
+					fmt.Fprintln(out, "No behaviors to summarize.")
 					return nil
 				}
 
-				fmt.Printf("Summarized %d behavior(s):\n\n", len(results))
+				fmt.Fprintf(out, "Summarized %d behavior(s):\n\n", len(results))
 				for _, r := range results {
 					shortID := r.BehaviorID
 					if len(shortID) > 8 {
 						shortID = shortID[:8]
 					}
 					if r.Error != "" {
-						fmt.Printf("  %s: ERROR - %s\n", shortID, r.Error)
+						fmt.Fprintf(out, "  %s: ERROR - %s\n", shortID, r.Error)
 					} else if r.Updated {
-						fmt.Printf("  %s: %s\n", shortID, r.Summary)
+						fmt.Fprintf(out, "  %s: %s\n", shortID, r.Summary)
 					} else {
-						fmt.Printf("  %s: (unchanged) %s\n", shortID, r.Summary)
+						fmt.Fprintf(out, "  %s: (unchanged) %s\n", shortID, r.Summary)
 					}
 				}
 			}
@@ -127,6 +144,7 @@ Examples:
 
 	cmd.Flags().Bool("all", false, "Generate summaries for all behaviors")
 	cmd.Flags().Bool("missing", false, "Only generate for behaviors without summaries")
+	cmd.Flags().Bool("llm", false, "Summarize long behaviors with the configured LLM")
 
 	return cmd
 }
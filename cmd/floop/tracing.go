@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/tracing"
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracingShutdownTimeout bounds how long exit waits to flush spans to an
+// unreachable collector.
+const tracingShutdownTimeout = 5 * time.Second
+
+// startTracing installs the OTLP exporter when tracing.enabled is set in cfg
+// and starts the span covering the command. The returned function ends the
+// span and flushes pending spans; it is a no-op when tracing is off. A
+// collector that cannot be set up is reported but never fails the command.
+func startTracing(ctx context.Context, cfg *config.FloopConfig) (context.Context, func(cmd *cobra.Command, err error)) {
+	if !cfg.Tracing.Enabled {
+		return ctx, func(*cobra.Command, error) {}
+	}
+	shutdown, err := tracing.Setup(ctx, tracing.Options{
+		Endpoint:       cfg.Tracing.Endpoint,
+		SampleRatio:    cfg.Tracing.SampleRatio,
+		ServiceVersion: version,
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "warning: tracing disabled: %v\n", err)
+		return ctx, func(*cobra.Command, error) {}
+	}
+
+	ctx, span := tracing.Start(ctx, "floop")
+	return ctx, func(cmd *cobra.Command, err error) {
+		endCommandSpan(span, cmd, err)
+		flushCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdown(flushCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+		}
+	}
+}
+
+// endCommandSpan names the command span after the command that ran and ends it.
+func endCommandSpan(span trace.Span, cmd *cobra.Command, err error) {
+	if cmd != nil {
+		span.SetName(cmd.CommandPath())
+	}
+	tracing.End(span, err)
+}
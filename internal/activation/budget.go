@@ -0,0 +1,144 @@
+package activation
+
+import (
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// DefaultTimeBudget is the default time activation may spend before it sheds
+// load. Activation runs on interactive agent turns and must not block them.
+const DefaultTimeBudget = 200 * time.Millisecond
+
+// DefaultShedTopN is the default number of behaviors kept when activation
+// sheds load.
+const DefaultShedTopN = 20
+
+// deadlineCheckInterval is how many behaviors are evaluated between deadline
+// checks, so checking the clock stays cheap relative to matching.
+const deadlineCheckInterval = 64
+
+// Budget bounds how long evaluation may run and says what to keep when it
+// overruns.
+type Budget struct {
+	// Deadline is when evaluation stops. Zero means no limit.
+	Deadline time.Time
+
+	// TopN is how many behaviors are kept when shedding load.
+	// 0 uses DefaultShedTopN.
+	TopN int
+
+	// Rank holds cached per-behavior scores (e.g. PageRank) used to choose
+	// which behaviors to keep. Behaviors without a score rank by priority,
+	// then confidence. May be nil.
+	Rank map[string]float64
+}
+
+// NewBudget returns a budget that expires timeBudget after start.
+// A non-positive timeBudget returns an unlimited budget.
+func NewBudget(start time.Time, timeBudget time.Duration, topN int, rank map[string]float64) Budget {
+	b := Budget{TopN: topN, Rank: rank}
+	if timeBudget > 0 {
+		b.Deadline = start.Add(timeBudget)
+	}
+	return b
+}
+
+// Expired reports whether the budget's deadline has passed.
+func (b Budget) Expired() bool {
+	return !b.Deadline.IsZero() && time.Now().After(b.Deadline)
+}
+
+// EvaluateWithinBudget evaluates behaviors like EvaluateWithNearMisses, but
+// stops once budget.Deadline passes and sheds load instead of finishing.
+// When shedding, it returns the matches found so far followed by
+// not-yet-evaluated behaviors, ranked by budget.Rank, priority, and
+// confidence, truncated to budget.TopN. Behaviors already ruled out are
+// never returned. degraded reports whether load was shed.
+func (e *Evaluator) EvaluateWithinBudget(ctx models.ContextSnapshot, behaviors []models.Behavior, maxFailing int, budget Budget) (results []ActivationResult, nearMisses []NearMiss, degraded bool) {
+	for i := range behaviors {
+		if i%deadlineCheckInterval == 0 && budget.Expired() {
+			results = shed(results, activatable(behaviors[i:], evaluationTime(ctx)), budget)
+			degraded = true
+			break
+		}
+		mr := e.evaluateMatch(ctx, behaviors[i])
+		if mr.Matched {
+			results = append(results, ActivationResult{
+				Behavior:          behaviors[i],
+				MatchedConditions: mr.Confirmed,
+				Specificity:       mr.Specificity(),
+				MatchScore:        mr.Score,
+			})
+			continue
+		}
+		if n := len(mr.Contradicted); n > 0 && n <= maxFailing && len(mr.Confirmed) > 0 {
+			failed := append([]string(nil), mr.Contradicted...)
+			sort.Strings(failed)
+			nearMisses = append(nearMisses, NearMiss{
+				Behavior:          behaviors[i],
+				FailedConditions:  failed,
+				MatchedConditions: mr.Confirmed,
+			})
+		}
+	}
+
+	if !degraded {
+		sortBySpecificityAndPriority(results) // shed results keep their rank order
+	}
+	sort.SliceStable(nearMisses, func(i, j int) bool {
+		if len(nearMisses[i].FailedConditions) != len(nearMisses[j].FailedConditions) {
+			return len(nearMisses[i].FailedConditions) < len(nearMisses[j].FailedConditions)
+		}
+		return len(nearMisses[i].MatchedConditions) > len(nearMisses[j].MatchedConditions)
+	})
+
+	return results, nearMisses, degraded
+}
+
+// activatable returns the behaviors that are neither expired nor snoozed
+// at now.
+func activatable(behaviors []models.Behavior, now time.Time) []models.Behavior {
+	kept := make([]models.Behavior, 0, len(behaviors))
+	for _, b := range behaviors {
+		if !b.Expired(now) && !b.Snoozed(now) {
+			kept = append(kept, b)
+		}
+	}
+	return kept
+}
+
+// shed keeps the top budget.TopN of the confirmed matches plus the
+// unevaluated behaviors. Confirmed matches come first.
+func shed(matched []ActivationResult, unevaluated []models.Behavior, budget Budget) []ActivationResult {
+	topN := budget.TopN
+	if topN <= 0 {
+		topN = DefaultShedTopN
+	}
+
+	rest := make([]ActivationResult, len(unevaluated))
+	for i, b := range unevaluated {
+		rest[i] = ActivationResult{Behavior: b}
+	}
+	byRank := func(rs []ActivationResult) {
+		sort.SliceStable(rs, func(i, j int) bool {
+			a, b := rs[i].Behavior, rs[j].Behavior
+			if ra, rb := budget.Rank[a.ID], budget.Rank[b.ID]; ra != rb {
+				return ra > rb
+			}
+			if a.Priority != b.Priority {
+				return a.Priority > b.Priority
+			}
+			return a.Confidence > b.Confidence
+		})
+	}
+	byRank(matched)
+	byRank(rest)
+
+	kept := append(matched, rest...)
+	if len(kept) > topN {
+		kept = kept[:topN]
+	}
+	return kept
+}
@@ -0,0 +1,72 @@
+package activation
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestEvaluator_EvaluateWithinBudget_NotExpired(t *testing.T) {
+	ctx := models.ContextSnapshot{FileLanguage: "go"}
+	behaviors := []models.Behavior{
+		{ID: "go", When: map[string]interface{}{"language": "go"}},
+		{ID: "python", When: map[string]interface{}{"language": "python"}},
+	}
+
+	budget := NewBudget(time.Now(), time.Hour, 1, nil)
+	results, _, degraded := NewEvaluator().EvaluateWithinBudget(ctx, behaviors, 0, budget)
+	if degraded {
+		t.Error("degraded within budget")
+	}
+	if len(results) != 1 || results[0].Behavior.ID != "go" {
+		t.Errorf("results = %+v, want only go", results)
+	}
+}
+
+func TestEvaluator_EvaluateWithinBudget_Sheds(t *testing.T) {
+	ctx := models.ContextSnapshot{FileLanguage: "go"}
+	var behaviors []models.Behavior
+	for i := 0; i < 10; i++ {
+		behaviors = append(behaviors, models.Behavior{ID: fmt.Sprintf("b-%d", i), Priority: i})
+	}
+
+	budget := Budget{
+		Deadline: time.Now().Add(-time.Second),
+		TopN:     3,
+		Rank:     map[string]float64{"b-2": 0.9},
+	}
+	results, _, degraded := NewEvaluator().EvaluateWithinBudget(ctx, behaviors, 0, budget)
+	if !degraded {
+		t.Fatal("expired budget not reported as degraded")
+	}
+	var got []string
+	for _, r := range results {
+		got = append(got, r.Behavior.ID)
+	}
+	// Ranked first by cached rank, then priority.
+	if fmt.Sprint(got) != "[b-2 b-9 b-8]" {
+		t.Errorf("kept %v, want [b-2 b-9 b-8]", got)
+	}
+}
+
+func TestNewBudget_Unlimited(t *testing.T) {
+	b := NewBudget(time.Now().Add(-time.Hour), 0, 0, nil)
+	if !b.Deadline.IsZero() || b.Expired() {
+		t.Errorf("zero time budget should be unlimited, got %+v", b)
+	}
+}
+
+func TestShed_KeepsMatchesFirst(t *testing.T) {
+	matched := []ActivationResult{{Behavior: models.Behavior{ID: "matched", Priority: 0}, MatchScore: 1}}
+	unevaluated := []models.Behavior{{ID: "high", Priority: 9}, {ID: "low", Priority: 1}}
+
+	kept := shed(matched, unevaluated, Budget{TopN: 2})
+	if len(kept) != 2 || kept[0].Behavior.ID != "matched" || kept[1].Behavior.ID != "high" {
+		t.Errorf("kept = %+v, want matched then high", kept)
+	}
+	if kept[0].MatchScore != 1 {
+		t.Error("matched result lost its match score")
+	}
+}
@@ -5,9 +5,11 @@ import (
 	"os/exec"
 	"os/user"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/contextdetect"
 	"github.com/nvandessel/floop/internal/models"
 )
 
@@ -20,6 +22,10 @@ type ContextBuilder struct {
 	Language    string
 	RepoRoot    string
 
+	// DetectGit fills in the working tree state (changed files, staged
+	// languages, base branch) by running git in RepoRoot
+	DetectGit bool
+
 	// Additional custom values
 	Custom map[string]interface{}
 }
@@ -61,12 +67,28 @@ func (b *ContextBuilder) WithRepoRoot(root string) *ContextBuilder {
 	return b
 }
 
+// WithGitDetection enables or disables detecting the working tree state
+// from git
+func (b *ContextBuilder) WithGitDetection(enabled bool) *ContextBuilder {
+	b.DetectGit = enabled
+	return b
+}
+
 // WithCustom adds a custom context field
 func (b *ContextBuilder) WithCustom(key string, value interface{}) *ContextBuilder {
 	b.Custom[key] = value
 	return b
 }
 
+// WithCustomValues adds custom context fields, such as those given with
+// --context key=value
+func (b *ContextBuilder) WithCustomValues(values map[string]interface{}) *ContextBuilder {
+	for key, value := range values {
+		b.Custom[key] = value
+	}
+	return b
+}
+
 // Build creates a ContextSnapshot from the current environment
 func (b *ContextBuilder) Build() models.ContextSnapshot {
 	ctx := models.ContextSnapshot{
@@ -108,9 +130,14 @@ func (b *ContextBuilder) Build() models.ContextSnapshot {
 	ctx.RepoRoot = repoRoot
 	ctx.Repo = getGitRemote(repoRoot)
 	ctx.Branch = getGitBranch(repoRoot)
+	if b.DetectGit {
+		ctx.ChangedFiles, ctx.StagedLanguages = getGitChanges(repoRoot)
+		ctx.BaseBranch = getBaseBranch(repoRoot)
+	}
 
-	// Infer project type from repo root
+	// Infer project type and fingerprint from repo root
 	ctx.ProjectType = models.InferProjectType(repoRoot)
+	contextdetect.Detect(repoRoot).Apply(&ctx)
 
 	// Get user info
 	if u, err := user.Current(); err == nil {
@@ -169,3 +196,63 @@ func getGitBranch(repoRoot string) string {
 	}
 	return strings.TrimSpace(string(out))
 }
+
+// getGitChanges returns the files with uncommitted changes (modified,
+// staged, or untracked, relative to the repository root) and the languages
+// of the staged ones
+func getGitChanges(repoRoot string) (changed, stagedLanguages []string) {
+	cmd := exec.Command("git", "status", "--porcelain", "-z")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	entries := strings.Split(string(out), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, path := entry[:2], entry[3:]
+		if status[0] == 'R' || status[0] == 'C' {
+			i++ // the next entry is the original path
+		}
+		changed = append(changed, path)
+		if status[0] != ' ' && status[0] != '?' {
+			if lang := models.InferLanguage(path); lang != "" && !seen[lang] {
+				seen[lang] = true
+				stagedLanguages = append(stagedLanguages, lang)
+			}
+		}
+	}
+	sort.Strings(stagedLanguages)
+	return changed, stagedLanguages
+}
+
+// baseBranchEnvVars name the pull request target branch in CI providers
+var baseBranchEnvVars = []string{
+	"FLOOP_BASE_BRANCH",
+	"GITHUB_BASE_REF",
+	"CI_MERGE_REQUEST_TARGET_BRANCH_NAME",
+	"BITBUCKET_PR_DESTINATION_BRANCH",
+	"CHANGE_TARGET",
+}
+
+// getBaseBranch returns the branch a pull request targets: the one CI
+// names, else the remote's default branch
+func getBaseBranch(repoRoot string) string {
+	for _, name := range baseBranchEnvVars {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "origin/HEAD")
+	cmd.Dir = repoRoot
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "origin/")
+}
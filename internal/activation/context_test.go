@@ -2,7 +2,10 @@ package activation
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/models"
@@ -355,3 +358,65 @@ func TestContextBuilder_WithLanguage(t *testing.T) {
 		})
 	}
 }
+
+func TestContextBuilder_Build_GitDetection(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	for _, name := range baseBranchEnvVars {
+		t.Setenv(name, "")
+	}
+
+	dir := t.TempDir()
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	write := func(name, content string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git("init", "-q")
+	write("main.go", "package main\n")
+	write("old.sql", "select 1;\n")
+	git("add", ".")
+	git("commit", "-q", "-m", "init")
+
+	write("main.go", "package main\n\nfunc main() {}\n") // modified, unstaged
+	write("deploy.py", "print('hi')\n")                  // staged
+	git("add", "deploy.py")
+	git("mv", "old.sql", "new.sql") // staged rename
+	write("notes.md", "todo\n")     // untracked
+
+	ctx := NewContextBuilder().WithRepoRoot(dir).Build()
+	if ctx.ChangedFiles != nil || ctx.StagedLanguages != nil {
+		t.Errorf("git state detected without WithGitDetection: %+v", ctx)
+	}
+
+	t.Setenv("GITHUB_BASE_REF", "release")
+	ctx = NewContextBuilder().WithRepoRoot(dir).WithGitDetection(true).Build()
+	sort.Strings(ctx.ChangedFiles)
+	if got, want := strings.Join(ctx.ChangedFiles, ","), "deploy.py,main.go,new.sql,notes.md"; got != want {
+		t.Errorf("ChangedFiles = %s, want %s", got, want)
+	}
+	if got := strings.Join(ctx.StagedLanguages, ","); got != "python" {
+		t.Errorf("StagedLanguages = %s, want python", got)
+	}
+	if ctx.BaseBranch != "release" {
+		t.Errorf("BaseBranch = %q, want release", ctx.BaseBranch)
+	}
+
+	// Conditions on the detected state match any changed file.
+	if !ctx.Matches(map[string]interface{}{"changed_files": "*.sql", "staged_languages": "python", "target_branch": "release"}) {
+		t.Error("context does not match conditions on its git state")
+	}
+	if ctx.Matches(map[string]interface{}{"changed_files": "migrations/**"}) {
+		t.Error("changed_files matched a pattern no changed file fits")
+	}
+}
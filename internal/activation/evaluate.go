@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/nvandessel/floop/internal/models"
 )
@@ -15,7 +16,8 @@ type ActivationResult struct {
 	// MatchedConditions shows which 'when' conditions were confirmed
 	MatchedConditions map[string]interface{}
 
-	// Specificity indicates how specific the match is (number of confirmed conditions)
+	// Specificity indicates how specific the match is: the number of confirmed
+	// conditions, with a confirmed path_prefix counting once per directory level.
 	Specificity int
 
 	// MatchScore is the ratio of confirmed conditions to total conditions (0.0-1.0).
@@ -30,6 +32,21 @@ type MatchResult struct {
 	Confirmed    map[string]interface{} // conditions that matched
 	Absent       []string               // conditions where context had no value
 	Contradicted []string               // conditions where context value differed
+	PathDepth    int                    // directory depth of a confirmed path_prefix, 0 if none
+	Expired      bool                   // true if the behavior's expiry has passed
+	Snoozed      bool                   // true if the behavior is snoozed
+}
+
+// Specificity returns the specificity of a match: one per confirmed
+// condition, plus one per directory level beyond the first for a confirmed
+// path_prefix. A behavior scoped to internal/store is therefore more specific
+// than one it inherits from internal.
+func (mr MatchResult) Specificity() int {
+	specificity := len(mr.Confirmed)
+	if mr.PathDepth > 1 {
+		specificity += mr.PathDepth - 1
+	}
+	return specificity
 }
 
 // Evaluator determines which behaviors are active for a given context
@@ -45,23 +62,7 @@ func NewEvaluator() *Evaluator {
 // Absent conditions (context has no value for the key) are neutral.
 // Returns behaviors that match, sorted by specificity (most specific first).
 func (e *Evaluator) Evaluate(ctx models.ContextSnapshot, behaviors []models.Behavior) []ActivationResult {
-	var results []ActivationResult
-
-	for _, b := range behaviors {
-		mr := e.evaluateMatch(ctx, b)
-		if mr.Matched {
-			results = append(results, ActivationResult{
-				Behavior:          b,
-				MatchedConditions: mr.Confirmed,
-				Specificity:       len(mr.Confirmed),
-				MatchScore:        mr.Score,
-			})
-		}
-	}
-
-	// Sort by specificity (higher first), then by priority
-	sortBySpecificityAndPriority(results)
-
+	results, _ := e.EvaluateWithNearMisses(ctx, behaviors, 0)
 	return results
 }
 
@@ -70,7 +71,16 @@ func (e *Evaluator) Evaluate(ctx models.ContextSnapshot, behaviors []models.Beha
 //   - Confirmed: context has the key and values match
 //   - Contradicted: context has the key but values differ (excludes behavior)
 //   - Absent: context doesn't have the key (neutral)
+//
+// A behavior whose expiry has passed, or that is snoozed, never matches.
 func (e *Evaluator) evaluateMatch(ctx models.ContextSnapshot, b models.Behavior) MatchResult {
+	now := evaluationTime(ctx)
+	if b.Expired(now) {
+		return MatchResult{Expired: true}
+	}
+	if b.Snoozed(now) {
+		return MatchResult{Snoozed: true}
+	}
 	if len(b.When) == 0 {
 		return MatchResult{Matched: true, Score: 0.0, Confirmed: nil}
 	}
@@ -78,6 +88,7 @@ func (e *Evaluator) evaluateMatch(ctx models.ContextSnapshot, b models.Behavior)
 	confirmed := make(map[string]interface{})
 	var absent []string
 	var contradicted []string
+	var pathDepth int
 
 	for key, required := range b.When {
 		matched, hasValue := ctx.MatchField(key, required)
@@ -85,6 +96,9 @@ func (e *Evaluator) evaluateMatch(ctx models.ContextSnapshot, b models.Behavior)
 			contradicted = append(contradicted, key)
 		} else if hasValue && matched {
 			confirmed[key] = required
+			if key == models.PathPrefixKey {
+				pathDepth, _ = ctx.PathPrefixMatch(required)
+			}
 		} else {
 			absent = append(absent, key)
 		}
@@ -106,7 +120,17 @@ func (e *Evaluator) evaluateMatch(ctx models.ContextSnapshot, b models.Behavior)
 		Score:     score,
 		Confirmed: confirmed,
 		Absent:    absent,
+		PathDepth: pathDepth,
+	}
+}
+
+// evaluationTime is the time expiries are checked against: the context's
+// timestamp, or now if it has none.
+func evaluationTime(ctx models.ContextSnapshot) time.Time {
+	if ctx.Timestamp.IsZero() {
+		return time.Now()
 	}
+	return ctx.Timestamp
 }
 
 // sortBySpecificityAndPriority sorts results by specificity desc, then priority desc
@@ -133,6 +157,16 @@ func (e *Evaluator) WhyActive(ctx models.ContextSnapshot, b models.Behavior) Act
 		IsActive:   false,
 	}
 
+	now := evaluationTime(ctx)
+	if b.Expired(now) {
+		explanation.Reason = fmt.Sprintf("Expired at %s", b.ExpiresAt.Format(time.RFC3339))
+		return explanation
+	}
+	if b.Snoozed(now) {
+		explanation.Reason = fmt.Sprintf("Snoozed until %s", b.SnoozedUntil.Format(time.RFC3339))
+		return explanation
+	}
+
 	if len(b.When) == 0 {
 		explanation.IsActive = true
 		explanation.Reason = "No activation conditions - always active"
@@ -160,6 +194,9 @@ func (e *Evaluator) WhyActive(ctx models.ContextSnapshot, b models.Behavior) Act
 			conditionResult.Status = "absent"
 			conditionResult.Matched = false
 		}
+		if key != models.PathPrefixKey && conditionResult.Status != "absent" {
+			conditionResult.Pattern, conditionResult.Detail = explainPattern(conditionResult.Actual, required)
+		}
 
 		explanation.Conditions = append(explanation.Conditions, conditionResult)
 	}
@@ -179,6 +216,22 @@ func (e *Evaluator) WhyActive(ctx models.ContextSnapshot, b models.Behavior) Act
 	return explanation
 }
 
+// explainPattern describes how a confirmed or contradicted condition was
+// matched: the pattern or list option that matched, or which failed.
+func explainPattern(actual, required interface{}) (pattern, detail string) {
+	if p, ok := models.MatchedPattern(actual, required); ok {
+		return p, fmt.Sprintf("matched %s %q", models.PatternKind(p), p)
+	}
+	switch req := required.(type) {
+	case string:
+		return "", fmt.Sprintf("%s %q did not match", models.PatternKind(req), req)
+	case []string, []interface{}:
+		return "", "no listed value or pattern matched"
+	default:
+		return "", ""
+	}
+}
+
 // sliceContains checks if a string slice contains a value.
 func sliceContains(s []string, v string) bool {
 	for _, item := range s {
@@ -204,4 +257,10 @@ type ConditionResult struct {
 	Actual   interface{} `json:"actual"`
 	Matched  bool        `json:"matched"`
 	Status   string      `json:"status"` // "confirmed", "contradicted", "absent"
+
+	// Pattern is the value or list option that matched, for confirmed
+	// string conditions.
+	Pattern string `json:"pattern,omitempty"`
+	// Detail explains the match or failure, e.g. `matched glob "**/*_test.go"`.
+	Detail string `json:"detail,omitempty"`
 }
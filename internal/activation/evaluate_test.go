@@ -142,6 +142,50 @@ func TestEvaluator_Specificity(t *testing.T) {
 	}
 }
 
+func TestEvaluator_PathPrefixInheritance(t *testing.T) {
+	evaluator := NewEvaluator()
+
+	ctx := models.ContextSnapshot{
+		FilePath:     "internal/store/sqlite/graph.go",
+		FileLanguage: "go",
+	}
+
+	behaviors := []models.Behavior{
+		{ID: "root", When: map[string]interface{}{"path_prefix": "internal"}},
+		{ID: "store", When: map[string]interface{}{"path_prefix": "internal/store"}},
+		{ID: "sibling", When: map[string]interface{}{"path_prefix": "internal/storage"}},
+		{ID: "go", When: map[string]interface{}{"language": "go"}},
+	}
+
+	results := evaluator.Evaluate(ctx, behaviors)
+
+	got := make(map[string]int)
+	for _, r := range results {
+		got[r.Behavior.ID] = r.Specificity
+	}
+	if _, ok := got["sibling"]; ok {
+		t.Error("sibling directory behavior should not activate")
+	}
+	want := map[string]int{"root": 1, "store": 2, "go": 1}
+	for id, spec := range want {
+		if got[id] != spec {
+			t.Errorf("%s specificity = %d, want %d (results: %v)", id, got[id], spec, got)
+		}
+	}
+	if results[0].Behavior.ID != "store" {
+		t.Errorf("deepest directory match should rank first, got %s", results[0].Behavior.ID)
+	}
+
+	// A subdirectory behavior that overrides its parent replaces it.
+	behaviors[1].Overrides = []string{"root"}
+	resolved := NewResolver().Resolve(evaluator.Evaluate(ctx, behaviors))
+	for _, b := range resolved.Active {
+		if b.ID == "root" {
+			t.Error("parent directory behavior should be overridden")
+		}
+	}
+}
+
 func TestEvaluator_WhyActive(t *testing.T) {
 	evaluator := NewEvaluator()
 
@@ -200,6 +244,68 @@ func TestEvaluator_WhyActive(t *testing.T) {
 	}
 }
 
+func TestEvaluator_WhyActive_Patterns(t *testing.T) {
+	evaluator := NewEvaluator()
+
+	behavior := models.Behavior{
+		ID:   "b1",
+		Name: "test-files",
+		When: map[string]interface{}{
+			"file_path": "**/*_test.go",
+			"language":  []interface{}{"go", "re:^type"},
+		},
+	}
+
+	tests := []struct {
+		name        string
+		ctx         models.ContextSnapshot
+		wantActive  bool
+		wantDetails map[string]string
+	}{
+		{
+			name:       "glob and list match",
+			ctx:        models.ContextSnapshot{FilePath: "internal/store/sqlite_test.go", FileLanguage: "go"},
+			wantActive: true,
+			wantDetails: map[string]string{
+				"file_path": `matched glob "**/*_test.go"`,
+				"language":  `matched value "go"`,
+			},
+		},
+		{
+			name:       "regex option matches",
+			ctx:        models.ContextSnapshot{FilePath: "main_test.go", FileLanguage: "typescript"},
+			wantActive: true,
+			wantDetails: map[string]string{
+				"file_path": `matched glob "**/*_test.go"`,
+				"language":  `matched regex "re:^type"`,
+			},
+		},
+		{
+			name:       "glob and list fail",
+			ctx:        models.ContextSnapshot{FilePath: "internal/store/sqlite.go", FileLanguage: "python"},
+			wantActive: false,
+			wantDetails: map[string]string{
+				"file_path": `glob "**/*_test.go" did not match`,
+				"language":  "no listed value or pattern matched",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanation := evaluator.WhyActive(tt.ctx, behavior)
+			if explanation.IsActive != tt.wantActive {
+				t.Errorf("WhyActive() IsActive = %v, want %v", explanation.IsActive, tt.wantActive)
+			}
+			for _, c := range explanation.Conditions {
+				if c.Detail != tt.wantDetails[c.Field] {
+					t.Errorf("%s detail = %q, want %q", c.Field, c.Detail, tt.wantDetails[c.Field])
+				}
+			}
+		})
+	}
+}
+
 func TestEvaluator_PartialMatching(t *testing.T) {
 	evaluator := NewEvaluator()
 
@@ -297,3 +403,62 @@ func TestEvaluator_NoConditions(t *testing.T) {
 		t.Errorf("Unexpected reason: %s", explanation.Reason)
 	}
 }
+
+func TestEvaluator_Expired(t *testing.T) {
+	evaluator := NewEvaluator()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	behaviors := []models.Behavior{
+		{ID: "expired", Name: "old-workaround", ExpiresAt: &past},
+		{ID: "expiring", Name: "current-workaround", ExpiresAt: &future},
+		{ID: "permanent", Name: "always-active"},
+	}
+	ctx := models.ContextSnapshot{Timestamp: now}
+
+	results := evaluator.Evaluate(ctx, behaviors)
+	if len(results) != 2 {
+		t.Fatalf("Evaluate() returned %d results, want 2", len(results))
+	}
+	for _, r := range results {
+		if r.Behavior.ID == "expired" {
+			t.Error("expired behavior was activated")
+		}
+	}
+
+	explanation := evaluator.WhyActive(ctx, behaviors[0])
+	if explanation.IsActive {
+		t.Error("WhyActive() reports expired behavior as active")
+	}
+	if explanation.Reason != "Expired at 2026-03-01T11:00:00Z" {
+		t.Errorf("Unexpected reason: %s", explanation.Reason)
+	}
+
+	// Behaviors skipped when shedding load are not revived once expired.
+	shedResults, _, degraded := evaluator.EvaluateWithinBudget(ctx, behaviors, 0, Budget{Deadline: past})
+	if !degraded || len(shedResults) != 2 {
+		t.Errorf("EvaluateWithinBudget() = %d results (degraded %v), want 2 (degraded)", len(shedResults), degraded)
+	}
+}
+
+func TestEvaluator_Snoozed(t *testing.T) {
+	evaluator := NewEvaluator()
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+
+	behaviors := []models.Behavior{
+		{ID: "snoozed", Name: "noisy-reminder", SnoozedUntil: &future},
+		{ID: "awake", Name: "woken-reminder", SnoozedUntil: &past},
+	}
+	ctx := models.ContextSnapshot{Timestamp: now}
+
+	results := evaluator.Evaluate(ctx, behaviors)
+	if len(results) != 1 || results[0].Behavior.ID != "awake" {
+		t.Fatalf("Evaluate() = %+v, want only the behavior whose snooze ended", results)
+	}
+
+	explanation := evaluator.WhyActive(ctx, behaviors[0])
+	if explanation.IsActive || explanation.Reason != "Snoozed until 2026-03-01T13:00:00Z" {
+		t.Errorf("WhyActive() = %+v, want snoozed", explanation)
+	}
+}
@@ -0,0 +1,108 @@
+package activation
+
+import (
+	"context"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/conflict"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// Layer is the behaviors of one store in a nested store hierarchy, such as
+// a monorepo with a .floop at the root and another in services/api.
+type Layer struct {
+	// Dir is the store's directory relative to the project root,
+	// slash-separated; empty for the root store.
+	Dir       string
+	Behaviors []models.Behavior
+}
+
+// MergeLayers flattens layers ordered outermost first into one behavior
+// list, with the nearest store winning conflicts.
+//
+// Behaviors of a nested layer were learned with paths relative to that
+// layer's directory, so their file_path and path_prefix conditions are
+// rebased onto the project root. Each nested behavior overrides the outer
+// behaviors it replaces: those with the same name, and those it contradicts
+// by the conflict rules. The resolver then reports the outer ones as
+// overridden rather than active. An outer behavior with the same ID as a
+// nested one is dropped.
+func MergeLayers(layers []Layer) []models.Behavior {
+	var merged []models.Behavior
+	detector := conflict.NewDetector(nil)
+	for _, layer := range layers {
+		outer := len(merged)
+		for _, b := range layer.Behaviors {
+			if layer.Dir != "" {
+				b.When = rebaseWhen(b.When, layer.Dir)
+			}
+			for i := 0; i < outer; i++ {
+				o := &merged[i]
+				if o.ID == b.ID {
+					continue
+				}
+				if (o.Name != "" && o.Name == b.Name) || detector.Check(context.Background(), &b, o) != nil {
+					b.Overrides = append(slices.Clip(b.Overrides), o.ID)
+				}
+			}
+			merged = append(merged, b)
+		}
+	}
+
+	// The resolver tracks behaviors by ID, so keep only the nearest copy.
+	seen := make(map[string]bool, len(merged))
+	kept := make([]models.Behavior, 0, len(merged))
+	for i := len(merged) - 1; i >= 0; i-- {
+		if seen[merged[i].ID] {
+			continue
+		}
+		seen[merged[i].ID] = true
+		kept = append(kept, merged[i])
+	}
+	slices.Reverse(kept)
+	return kept
+}
+
+// rebaseWhen prefixes the path conditions of when with dir. Regex
+// conditions are left as they are: they match the project-relative path.
+func rebaseWhen(when map[string]interface{}, dir string) map[string]interface{} {
+	if len(when) == 0 {
+		return when
+	}
+	rebased := make(map[string]interface{}, len(when))
+	for key, value := range when {
+		switch key {
+		case "file_path", "file.path", models.PathPrefixKey:
+			rebased[key] = rebaseValue(value, dir)
+		default:
+			rebased[key] = value
+		}
+	}
+	return rebased
+}
+
+func rebaseValue(value interface{}, dir string) interface{} {
+	switch v := value.(type) {
+	case string:
+		if strings.HasPrefix(v, models.RegexPrefix) || path.IsAbs(v) {
+			return v
+		}
+		return path.Join(dir, v)
+	case []string:
+		out := make([]string, len(v))
+		for i, s := range v {
+			out[i] = rebaseValue(s, dir).(string)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(v))
+		for i, option := range v {
+			out[i] = rebaseValue(option, dir)
+		}
+		return out
+	default:
+		return value
+	}
+}
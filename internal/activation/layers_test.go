@@ -0,0 +1,84 @@
+package activation
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestMergeLayers(t *testing.T) {
+	behavior := func(id, name, canonical string, when map[string]interface{}) models.Behavior {
+		return models.Behavior{
+			ID:      id,
+			Name:    name,
+			When:    when,
+			Content: models.BehaviorContent{Canonical: canonical},
+		}
+	}
+
+	layers := []Layer{
+		{Behaviors: []models.Behavior{
+			behavior("root-pip", "package-manager", "Always use pip for Python packages", nil),
+			behavior("root-tabs", "indent", "Never use tabs for indentation", nil),
+			behavior("root-logging", "logging", "Use structured logging", nil),
+			behavior("shared", "shared", "Shared root copy", nil),
+		}},
+		{Dir: "services/api", Behaviors: []models.Behavior{
+			behavior("api-uv", "package-manager", "Always use uv for Python packages", nil),
+			behavior("api-tabs", "api-indent", "Always use tabs for indentation", nil),
+			behavior("api-handlers", "handlers", "Return typed errors from handlers",
+				map[string]interface{}{"path_prefix": "handlers", "file_path": []interface{}{"**/*.go", "re:_test\\.go$"}}),
+			behavior("shared", "shared", "Shared service copy", nil),
+		}},
+	}
+
+	merged := MergeLayers(layers)
+	byID := make(map[string]models.Behavior, len(merged))
+	for _, b := range merged {
+		byID[b.ID] = b
+	}
+	if len(merged) != 7 {
+		t.Fatalf("merged %d behaviors, want 7 (duplicate ID dropped)", len(merged))
+	}
+	if byID["shared"].Content.Canonical != "Shared service copy" {
+		t.Errorf("shared = %q, want the nearest copy", byID["shared"].Content.Canonical)
+	}
+	if !slices.Contains(byID["api-uv"].Overrides, "root-pip") {
+		t.Errorf("same-name behavior does not override the root one: %v", byID["api-uv"].Overrides)
+	}
+	if !slices.Contains(byID["api-tabs"].Overrides, "root-tabs") {
+		t.Errorf("conflicting behavior does not override the root one: %v", byID["api-tabs"].Overrides)
+	}
+	if len(byID["api-handlers"].Overrides) != 0 {
+		t.Errorf("unrelated behavior overrides %v", byID["api-handlers"].Overrides)
+	}
+
+	when := byID["api-handlers"].When
+	if when["path_prefix"] != "services/api/handlers" {
+		t.Errorf("path_prefix = %v, want rebased onto services/api", when["path_prefix"])
+	}
+	if got := when["file_path"].([]interface{}); got[0] != "services/api/**/*.go" || got[1] != "re:_test\\.go$" {
+		t.Errorf("file_path = %v, want glob rebased and regex unchanged", got)
+	}
+	if layers[1].Behaviors[2].When["path_prefix"] != "handlers" {
+		t.Error("MergeLayers modified the input behaviors")
+	}
+
+	ctx := models.ContextSnapshot{FilePath: "services/api/handlers/user.go"}
+	result := NewResolver().Resolve(NewEvaluator().Evaluate(ctx, merged))
+	var active []string
+	for _, b := range result.Active {
+		active = append(active, b.ID)
+	}
+	for _, id := range []string{"root-pip", "root-tabs"} {
+		if slices.Contains(active, id) {
+			t.Errorf("outer behavior %s is active, want overridden by the nearer store", id)
+		}
+	}
+	for _, id := range []string{"api-uv", "api-tabs", "api-handlers", "root-logging"} {
+		if !slices.Contains(active, id) {
+			t.Errorf("behavior %s is not active: %v", id, active)
+		}
+	}
+}
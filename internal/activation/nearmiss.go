@@ -0,0 +1,33 @@
+package activation
+
+import (
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// DefaultNearMissMaxFailing is the default number of contradicted conditions
+// a behavior may have and still be reported as a near miss.
+const DefaultNearMissMaxFailing = 1
+
+// NearMiss is a behavior that almost matched the context: at least one of its
+// conditions was confirmed, but a few were contradicted.
+type NearMiss struct {
+	Behavior models.Behavior `json:"behavior"`
+
+	// FailedConditions are the contradicted condition keys, sorted.
+	FailedConditions []string `json:"failed_conditions"`
+
+	// MatchedConditions are the conditions the context confirmed.
+	MatchedConditions map[string]interface{} `json:"matched_conditions"`
+}
+
+// EvaluateWithNearMisses evaluates behaviors like Evaluate and also returns
+// near misses: behaviors with between 1 and maxFailing contradicted conditions
+// and at least one confirmed condition. A behavior that confirms nothing is
+// unrelated to the context rather than nearly matching it. maxFailing <= 0
+// disables near-miss collection.
+//
+// Near misses are sorted by fewest failed conditions, then most confirmed.
+func (e *Evaluator) EvaluateWithNearMisses(ctx models.ContextSnapshot, behaviors []models.Behavior, maxFailing int) ([]ActivationResult, []NearMiss) {
+	results, nearMisses, _ := e.EvaluateWithinBudget(ctx, behaviors, maxFailing, Budget{})
+	return results, nearMisses
+}
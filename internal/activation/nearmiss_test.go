@@ -0,0 +1,47 @@
+package activation
+
+import (
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestEvaluator_EvaluateWithNearMisses(t *testing.T) {
+	evaluator := NewEvaluator()
+	ctx := models.ContextSnapshot{FileLanguage: "go", Task: "debug", Environment: "local"}
+
+	behaviors := []models.Behavior{
+		{ID: "active", When: map[string]interface{}{"language": "go"}},
+		{ID: "one-off", When: map[string]interface{}{"language": "go", "task": "refactor"}},
+		{ID: "one-off-richer", When: map[string]interface{}{"language": "go", "environment": "local", "task": "refactor"}},
+		{ID: "two-off", When: map[string]interface{}{"language": "go", "task": "refactor", "environment": "ci"}},
+		{ID: "unrelated", When: map[string]interface{}{"language": "python"}},
+	}
+
+	results, misses := evaluator.EvaluateWithNearMisses(ctx, behaviors, 1)
+	if len(results) != 1 || results[0].Behavior.ID != "active" {
+		t.Fatalf("results = %+v, want only active", results)
+	}
+	if len(misses) != 2 {
+		t.Fatalf("len(misses) = %d, want 2: %+v", len(misses), misses)
+	}
+	if misses[0].Behavior.ID != "one-off-richer" || misses[1].Behavior.ID != "one-off" {
+		t.Errorf("order = %s, %s; want one-off-richer, one-off", misses[0].Behavior.ID, misses[1].Behavior.ID)
+	}
+	if len(misses[1].FailedConditions) != 1 || misses[1].FailedConditions[0] != "task" {
+		t.Errorf("FailedConditions = %v, want [task]", misses[1].FailedConditions)
+	}
+	if misses[1].MatchedConditions["language"] != "go" {
+		t.Errorf("MatchedConditions = %v", misses[1].MatchedConditions)
+	}
+
+	_, misses = evaluator.EvaluateWithNearMisses(ctx, behaviors, 2)
+	if len(misses) != 3 || misses[2].Behavior.ID != "two-off" {
+		t.Errorf("maxFailing=2: misses = %+v", misses)
+	}
+
+	_, misses = evaluator.EvaluateWithNearMisses(ctx, behaviors, 0)
+	if len(misses) != 0 {
+		t.Errorf("maxFailing=0: expected no near misses, got %d", len(misses))
+	}
+}
@@ -0,0 +1,515 @@
+// Package archive bundles the complete state of one or more .floop
+// directories — graph database, corrections, config, vector index, and any
+// other files — into a single portable tarball for moving between machines.
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite" // SQLite driver for database snapshots
+)
+
+// FormatVersion is the archive format version written to the manifest.
+const FormatVersion = 1
+
+// manifestName is the name of the first tar entry, holding the Manifest.
+const manifestName = "manifest.json"
+
+// maxManifestSize caps the manifest read from an archive.
+const maxManifestSize = 16 * 1024 * 1024
+
+// Scopes name the .floop directory a file belongs to.
+const (
+	ScopeLocal  = "local"
+	ScopeGlobal = "global"
+)
+
+// Sections group archived files for selective extraction.
+const (
+	SectionGraph       = "graph"       // floop.db, nodes.jsonl, edges.jsonl
+	SectionCorrections = "corrections" // corrections.jsonl and its compaction archive
+	SectionConfig      = "config"      // config.yaml, manifest.yaml, .gitignore, packs.lock
+	SectionVectors     = "vectors"     // vectors/
+	SectionOther       = "other"       // everything else (tags, templates, labels, packs)
+)
+
+// Sections lists every section in extraction order.
+var Sections = []string{SectionGraph, SectionCorrections, SectionConfig, SectionVectors, SectionOther}
+
+// excludedDirs are top-level .floop directories never archived: backups
+// have their own mechanism and sessions are per-machine, transient state.
+var excludedDirs = map[string]bool{"backups": true, "sessions": true}
+
+// Manifest describes an archive's contents.
+type Manifest struct {
+	FormatVersion int         `json:"format_version"`
+	FloopVersion  string      `json:"floop_version,omitempty"`
+	CreatedAt     time.Time   `json:"created_at"`
+	Scopes        []string    `json:"scopes"`
+	Files         []FileEntry `json:"files"`
+}
+
+// FileEntry is one archived file. Path is slash-separated and relative to
+// the scope's .floop directory.
+type FileEntry struct {
+	Scope   string `json:"scope"`
+	Path    string `json:"path"`
+	Section string `json:"section"`
+	Size    int64  `json:"size"`
+	SHA256  string `json:"sha256"`
+}
+
+// name returns the tar entry name of the file.
+func (f FileEntry) name() string {
+	return f.Scope + "/" + f.Path
+}
+
+// Source is a .floop directory to archive.
+type Source struct {
+	Scope string // ScopeLocal or ScopeGlobal
+	Dir   string // path to the .floop directory
+}
+
+// CreateOptions configures Create.
+type CreateOptions struct {
+	FloopVersion string
+}
+
+// SectionOf classifies a file by its slash-separated path relative to a
+// .floop directory.
+func SectionOf(rel string) string {
+	switch {
+	case rel == "floop.db" || rel == "nodes.jsonl" || rel == "edges.jsonl":
+		return SectionGraph
+	case rel == "corrections.jsonl" || rel == "corrections-archive.jsonl.gz":
+		return SectionCorrections
+	case rel == "config.yaml" || rel == "manifest.yaml" || rel == ".gitignore" || rel == "packs.lock":
+		return SectionConfig
+	case rel == "vectors" || strings.HasPrefix(rel, "vectors/"):
+		return SectionVectors
+	default:
+		return SectionOther
+	}
+}
+
+// skipFile reports whether a file is transient SQLite or lock state that
+// must not be archived. The pack lockfile is project state, not a lock.
+func skipFile(rel string) bool {
+	base := path.Base(rel)
+	return strings.HasSuffix(base, "-wal") || strings.HasSuffix(base, "-shm") ||
+		strings.HasSuffix(base, "-journal") || (strings.HasSuffix(base, ".lock") && base != "packs.lock")
+}
+
+// staged is a file ready to be written into the archive.
+type staged struct {
+	entry FileEntry
+	src   string // file to copy from (a snapshot for databases)
+}
+
+// Create writes a gzip-compressed tarball of the given .floop directories
+// to outputPath. SQLite databases are snapshotted with VACUUM INTO so the
+// archive is consistent even while floop is running. Sources whose
+// directory does not exist are skipped.
+func Create(ctx context.Context, outputPath string, sources []Source, opts CreateOptions) (*Manifest, error) {
+	tmpDir, err := os.MkdirTemp("", "floop-archive-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating staging dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest := &Manifest{
+		FormatVersion: FormatVersion,
+		FloopVersion:  opts.FloopVersion,
+		CreatedAt:     time.Now().UTC(),
+	}
+
+	var files []staged
+	for _, src := range sources {
+		if _, err := os.Stat(src.Dir); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		scopeFiles, err := stageSource(ctx, src, tmpDir)
+		if err != nil {
+			return nil, err
+		}
+		manifest.Scopes = append(manifest.Scopes, src.Scope)
+		files = append(files, scopeFiles...)
+	}
+	if len(manifest.Scopes) == 0 {
+		return nil, fmt.Errorf("no .floop directories to archive")
+	}
+	for _, f := range files {
+		manifest.Files = append(manifest.Files, f.entry)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0700); err != nil {
+		return nil, fmt.Errorf("creating output dir: %w", err)
+	}
+	tmpOut := outputPath + ".tmp"
+	if err := writeArchive(tmpOut, manifest, files); err != nil {
+		os.Remove(tmpOut)
+		return nil, err
+	}
+	if err := os.Rename(tmpOut, outputPath); err != nil {
+		os.Remove(tmpOut)
+		return nil, fmt.Errorf("finalizing archive: %w", err)
+	}
+	return manifest, nil
+}
+
+// stageSource collects the archivable files of one .floop directory,
+// snapshotting databases into tmpDir.
+func stageSource(ctx context.Context, src Source, tmpDir string) ([]staged, error) {
+	var files []staged
+	err := filepath.WalkDir(src.Dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src.Dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == "." {
+			return nil
+		}
+		if d.IsDir() {
+			if excludedDirs[rel] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() || skipFile(rel) {
+			return nil
+		}
+
+		from := p
+		if strings.HasSuffix(rel, ".db") {
+			from = filepath.Join(tmpDir, src.Scope+"-"+strings.ReplaceAll(rel, "/", "_"))
+			if err := snapshotDB(ctx, p, from); err != nil {
+				return fmt.Errorf("snapshotting %s: %w", rel, err)
+			}
+		}
+		size, sum, err := hashFile(from)
+		if err != nil {
+			return err
+		}
+		files = append(files, staged{
+			entry: FileEntry{Scope: src.Scope, Path: rel, Section: SectionOf(rel), Size: size, SHA256: sum},
+			src:   from,
+		})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", src.Dir, err)
+	}
+	return files, nil
+}
+
+// snapshotDB writes a consistent copy of the SQLite database at src to dst.
+func snapshotDB(ctx context.Context, src, dst string) error {
+	db, err := sql.Open("sqlite", src)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	_, err = db.ExecContext(ctx, "VACUUM INTO ?", dst)
+	return err
+}
+
+// hashFile returns the size and hex SHA-256 of a file.
+func hashFile(p string) (int64, string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeArchive writes the manifest followed by every staged file.
+func writeArchive(outputPath string, manifest *Manifest, files []staged) error {
+	out, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("creating archive: %w", err)
+	}
+	defer out.Close()
+
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding manifest: %w", err)
+	}
+	hdr := &tar.Header{Name: manifestName, Mode: 0600, Size: int64(len(data)), ModTime: manifest.CreatedAt}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+	if _, err := tw.Write(data); err != nil {
+		return fmt.Errorf("writing manifest: %w", err)
+	}
+
+	for _, f := range files {
+		if err := addFile(tw, f, manifest.CreatedAt); err != nil {
+			return fmt.Errorf("adding %s: %w", f.entry.name(), err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("closing archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return fmt.Errorf("closing archive: %w", err)
+	}
+	return out.Close()
+}
+
+// addFile copies one staged file into the tar stream.
+func addFile(tw *tar.Writer, f staged, modTime time.Time) error {
+	in, err := os.Open(f.src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	hdr := &tar.Header{Name: f.entry.name(), Mode: 0600, Size: f.entry.Size, ModTime: modTime}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	_, err = io.CopyN(tw, in, f.entry.Size)
+	return err
+}
+
+// ReadManifest returns the manifest of the archive at archivePath.
+func ReadManifest(archivePath string) (*Manifest, error) {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+	_, m, err := openArchive(f)
+	return m, err
+}
+
+// openArchive reads the manifest from the start of an archive stream and
+// returns the tar reader positioned after it.
+func openArchive(r io.Reader) (*tar.Reader, *Manifest, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, nil, fmt.Errorf("not a floop archive: %w", err)
+	}
+	tr := tar.NewReader(gz)
+	hdr, err := tr.Next()
+	if err != nil || hdr.Name != manifestName {
+		return nil, nil, fmt.Errorf("not a floop archive: missing %s", manifestName)
+	}
+	data, err := io.ReadAll(io.LimitReader(tr, maxManifestSize+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	if len(data) > maxManifestSize {
+		return nil, nil, fmt.Errorf("manifest exceeds %d bytes", maxManifestSize)
+	}
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, nil, fmt.Errorf("parsing manifest: %w", err)
+	}
+	if m.FormatVersion < 1 || m.FormatVersion > FormatVersion {
+		return nil, nil, fmt.Errorf("unsupported archive format version %d (this floop reads up to %d)", m.FormatVersion, FormatVersion)
+	}
+	for _, e := range m.Files {
+		if err := validateEntry(e); err != nil {
+			return nil, nil, err
+		}
+	}
+	return tr, &m, nil
+}
+
+// validateEntry rejects manifest entries that could escape the target
+// directory.
+func validateEntry(e FileEntry) error {
+	if e.Scope != ScopeLocal && e.Scope != ScopeGlobal {
+		return fmt.Errorf("invalid scope %q in manifest", e.Scope)
+	}
+	clean := path.Clean(e.Path)
+	if e.Path == "" || clean != e.Path || path.IsAbs(e.Path) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return fmt.Errorf("unsafe path %q in manifest", e.Path)
+	}
+	if !slices.Contains(Sections, e.Section) {
+		return fmt.Errorf("invalid section %q in manifest", e.Section)
+	}
+	return nil
+}
+
+// ExtractOptions configures Extract.
+type ExtractOptions struct {
+	// Targets maps each scope to extract to its destination .floop
+	// directory. Scopes without a target are not extracted.
+	Targets map[string]string
+
+	// Sections limits extraction to these sections. Empty means all.
+	Sections []string
+
+	// Overwrite allows replacing existing files. Without it, Extract fails
+	// before writing anything if any file already exists.
+	Overwrite bool
+
+	// DryRun reports what would be extracted without writing.
+	DryRun bool
+}
+
+// ExtractResult reports what Extract did.
+type ExtractResult struct {
+	Manifest    *Manifest   `json:"manifest"`
+	Extracted   []FileEntry `json:"extracted"`
+	Overwritten []string    `json:"overwritten,omitempty"` // destination paths replaced
+}
+
+// ConflictError is returned when extraction would overwrite existing files
+// and ExtractOptions.Overwrite is not set.
+type ConflictError struct {
+	Paths []string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("%d file(s) already exist (first: %s)", len(e.Paths), e.Paths[0])
+}
+
+// Plan returns the manifest entries Extract would write and the existing
+// destination files it would replace, without writing anything.
+func Plan(manifest *Manifest, opts ExtractOptions) (selected []FileEntry, existing []string) {
+	for _, e := range manifest.Files {
+		dir, ok := opts.Targets[e.Scope]
+		if !ok {
+			continue
+		}
+		if len(opts.Sections) > 0 && !slices.Contains(opts.Sections, e.Section) {
+			continue
+		}
+		selected = append(selected, e)
+		dst := filepath.Join(dir, filepath.FromSlash(e.Path))
+		if _, err := os.Stat(dst); err == nil {
+			existing = append(existing, dst)
+		}
+	}
+	sort.Strings(existing)
+	return selected, existing
+}
+
+// Extract restores files from the archive at archivePath into the target
+// directories. Every file is verified against its manifest checksum before
+// it replaces anything on disk.
+func Extract(archivePath string, opts ExtractOptions) (*ExtractResult, error) {
+	for _, s := range opts.Sections {
+		if !slices.Contains(Sections, s) {
+			return nil, fmt.Errorf("unknown section %q (valid: %s)", s, strings.Join(Sections, ", "))
+		}
+	}
+
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return nil, fmt.Errorf("opening archive: %w", err)
+	}
+	defer f.Close()
+	tr, manifest, err := openArchive(f)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, existing := Plan(manifest, opts)
+	result := &ExtractResult{Manifest: manifest, Extracted: selected}
+	if len(existing) > 0 && !opts.Overwrite {
+		return nil, &ConflictError{Paths: existing}
+	}
+	result.Overwritten = existing
+	if opts.DryRun {
+		return result, nil
+	}
+
+	wanted := make(map[string]FileEntry, len(selected))
+	for _, e := range selected {
+		wanted[e.name()] = e
+	}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading archive: %w", err)
+		}
+		e, ok := wanted[hdr.Name]
+		if !ok {
+			continue
+		}
+		dst := filepath.Join(opts.Targets[e.Scope], filepath.FromSlash(e.Path))
+		if err := extractFile(tr, e, dst); err != nil {
+			return nil, fmt.Errorf("extracting %s: %w", e.name(), err)
+		}
+		delete(wanted, hdr.Name)
+	}
+	if len(wanted) > 0 {
+		return nil, fmt.Errorf("archive is missing %d file(s) listed in its manifest", len(wanted))
+	}
+	return result, nil
+}
+
+// extractFile writes one entry to dst via a temp file, verifying its size
+// and checksum first. Replacing a SQLite database also removes its stale
+// WAL and shared-memory files.
+func extractFile(r io.Reader, e FileEntry, dst string) error {
+	if err := os.MkdirAll(filepath.Dir(dst), 0700); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(dst), ".archive-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(tmp, h), io.LimitReader(r, e.Size+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	if n != e.Size || hex.EncodeToString(h.Sum(nil)) != e.SHA256 {
+		return fmt.Errorf("checksum mismatch")
+	}
+
+	if strings.HasSuffix(e.Path, ".db") {
+		for _, suffix := range []string{"-wal", "-shm"} {
+			if err := os.Remove(dst + suffix); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	if err := os.Chmod(tmp.Name(), 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), dst)
+}
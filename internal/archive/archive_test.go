@@ -0,0 +1,286 @@
+package archive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeFile creates a file (and its parent dirs) under dir.
+func writeFile(t *testing.T, dir, rel, content string) {
+	t.Helper()
+	p := filepath.Join(dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// makeFloopDir builds a .floop directory with one file per section plus
+// files that must be excluded.
+func makeFloopDir(t *testing.T) string {
+	t.Helper()
+	dir := filepath.Join(t.TempDir(), ".floop")
+	writeFile(t, dir, "nodes.jsonl", `{"id":"b1"}`+"\n")
+	writeFile(t, dir, "corrections.jsonl", `{"id":"c1"}`+"\n")
+	writeFile(t, dir, "config.yaml", "llm:\n  provider: none\n")
+	writeFile(t, dir, "vectors/index.bin", "vec")
+	writeFile(t, dir, "similarity-labels.jsonl", "{}\n")
+	writeFile(t, dir, "backups/old.json.gz", "skip")
+	writeFile(t, dir, "sessions/s1.json", "skip")
+	writeFile(t, dir, "floop.db-wal", "skip")
+
+	db, err := sql.Open("sqlite", filepath.Join(dir, "floop.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if _, err := db.Exec(`CREATE TABLE t (v TEXT); INSERT INTO t VALUES ('hello')`); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func createArchive(t *testing.T, sources []Source) (string, *Manifest) {
+	t.Helper()
+	out := filepath.Join(t.TempDir(), "a.tar.gz")
+	m, err := Create(context.Background(), out, sources, CreateOptions{FloopVersion: "test"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	return out, m
+}
+
+func TestCreateManifest(t *testing.T) {
+	src := makeFloopDir(t)
+	path, m := createArchive(t, []Source{{Scope: ScopeGlobal, Dir: src}, {Scope: ScopeLocal, Dir: filepath.Join(t.TempDir(), "missing")}})
+
+	if m.FormatVersion != FormatVersion || m.FloopVersion != "test" {
+		t.Errorf("manifest versions = %d/%q", m.FormatVersion, m.FloopVersion)
+	}
+	if len(m.Scopes) != 1 || m.Scopes[0] != ScopeGlobal {
+		t.Errorf("Scopes = %v, want [global] (missing dirs skipped)", m.Scopes)
+	}
+
+	sections := map[string]string{}
+	for _, f := range m.Files {
+		sections[f.Path] = f.Section
+	}
+	want := map[string]string{
+		"floop.db":                SectionGraph,
+		"nodes.jsonl":             SectionGraph,
+		"corrections.jsonl":       SectionCorrections,
+		"config.yaml":             SectionConfig,
+		"vectors/index.bin":       SectionVectors,
+		"similarity-labels.jsonl": SectionOther,
+	}
+	if len(sections) != len(want) {
+		t.Errorf("archived files = %v, want %v", sections, want)
+	}
+	for p, s := range want {
+		if sections[p] != s {
+			t.Errorf("section of %s = %q, want %q", p, sections[p], s)
+		}
+	}
+
+	read, err := ReadManifest(path)
+	if err != nil {
+		t.Fatalf("ReadManifest() error = %v", err)
+	}
+	if len(read.Files) != len(m.Files) {
+		t.Errorf("ReadManifest() files = %d, want %d", len(read.Files), len(m.Files))
+	}
+}
+
+func TestCreateNoSources(t *testing.T) {
+	out := filepath.Join(t.TempDir(), "a.tar.gz")
+	if _, err := Create(context.Background(), out, []Source{{Scope: ScopeLocal, Dir: filepath.Join(t.TempDir(), "missing")}}, CreateOptions{}); err == nil {
+		t.Error("Create() with no existing sources should fail")
+	}
+}
+
+func TestExtractRoundTrip(t *testing.T) {
+	src := makeFloopDir(t)
+	path, _ := createArchive(t, []Source{{Scope: ScopeLocal, Dir: src}})
+
+	dst := filepath.Join(t.TempDir(), ".floop")
+	res, err := Extract(path, ExtractOptions{Targets: map[string]string{ScopeLocal: dst}})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(res.Extracted) != 6 || len(res.Overwritten) != 0 {
+		t.Errorf("Extract() extracted %d, overwritten %d", len(res.Extracted), len(res.Overwritten))
+	}
+
+	got, err := os.ReadFile(filepath.Join(dst, "corrections.jsonl"))
+	if err != nil || string(got) != `{"id":"c1"}`+"\n" {
+		t.Errorf("corrections.jsonl = %q, %v", got, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "sessions")); !os.IsNotExist(err) {
+		t.Error("sessions/ should not be archived")
+	}
+
+	db, err := sql.Open("sqlite", filepath.Join(dst, "floop.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	var v string
+	if err := db.QueryRow(`SELECT v FROM t`).Scan(&v); err != nil || v != "hello" {
+		t.Errorf("restored database row = %q, %v", v, err)
+	}
+}
+
+func TestExtractSelectiveAndScopes(t *testing.T) {
+	src := makeFloopDir(t)
+	path, _ := createArchive(t, []Source{{Scope: ScopeLocal, Dir: src}, {Scope: ScopeGlobal, Dir: src}})
+
+	dst := filepath.Join(t.TempDir(), ".floop")
+	res, err := Extract(path, ExtractOptions{
+		Targets:  map[string]string{ScopeGlobal: dst},
+		Sections: []string{SectionCorrections, SectionConfig},
+	})
+	if err != nil {
+		t.Fatalf("Extract() error = %v", err)
+	}
+	if len(res.Extracted) != 2 {
+		t.Errorf("Extract() extracted %d files, want 2", len(res.Extracted))
+	}
+	if _, err := os.Stat(filepath.Join(dst, "nodes.jsonl")); !os.IsNotExist(err) {
+		t.Error("graph section should not be extracted")
+	}
+
+	if _, err := Extract(path, ExtractOptions{Targets: map[string]string{ScopeGlobal: dst}, Sections: []string{"bogus"}}); err == nil {
+		t.Error("Extract() with unknown section should fail")
+	}
+}
+
+func TestExtractConflicts(t *testing.T) {
+	src := makeFloopDir(t)
+	path, _ := createArchive(t, []Source{{Scope: ScopeLocal, Dir: src}})
+
+	dst := filepath.Join(t.TempDir(), ".floop")
+	writeFile(t, dst, "corrections.jsonl", "mine\n")
+	writeFile(t, dst, "floop.db-wal", "stale")
+	targets := map[string]string{ScopeLocal: dst}
+
+	_, err := Extract(path, ExtractOptions{Targets: targets})
+	var conflict *ConflictError
+	if !errors.As(err, &conflict) || len(conflict.Paths) != 1 {
+		t.Fatalf("Extract() error = %v, want ConflictError on 1 file", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "nodes.jsonl")); !os.IsNotExist(err) {
+		t.Error("conflicting extract should write nothing")
+	}
+
+	res, err := Extract(path, ExtractOptions{Targets: targets, Overwrite: true, DryRun: true})
+	if err != nil || len(res.Overwritten) != 1 {
+		t.Fatalf("dry run = %+v, %v", res, err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dst, "corrections.jsonl")); string(got) != "mine\n" {
+		t.Error("dry run should not write")
+	}
+
+	if _, err := Extract(path, ExtractOptions{Targets: targets, Overwrite: true}); err != nil {
+		t.Fatalf("Extract(Overwrite) error = %v", err)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dst, "corrections.jsonl")); string(got) != `{"id":"c1"}`+"\n" {
+		t.Errorf("corrections.jsonl not overwritten: %q", got)
+	}
+	if _, err := os.Stat(filepath.Join(dst, "floop.db-wal")); !os.IsNotExist(err) {
+		t.Error("stale WAL should be removed when floop.db is replaced")
+	}
+}
+
+// writeRawArchive writes an archive with the given manifest and entries.
+func writeRawArchive(t *testing.T, m Manifest, entries map[string]string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "raw.tar.gz")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+	data, _ := json.Marshal(m)
+	tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0600, Size: int64(len(data))})
+	tw.Write(data)
+	for name, content := range entries {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0600, Size: int64(len(content))})
+		tw.Write([]byte(content))
+	}
+	tw.Close()
+	gz.Close()
+	return path
+}
+
+func TestExtractRejectsBadArchives(t *testing.T) {
+	targets := map[string]string{ScopeLocal: t.TempDir()}
+
+	tests := []struct {
+		name     string
+		manifest Manifest
+		entries  map[string]string
+	}{
+		{
+			name:     "future version",
+			manifest: Manifest{FormatVersion: FormatVersion + 1},
+		},
+		{
+			name: "path traversal",
+			manifest: Manifest{FormatVersion: 1, Files: []FileEntry{
+				{Scope: ScopeLocal, Path: "../evil", Section: SectionOther, Size: 1},
+			}},
+		},
+		{
+			name: "checksum mismatch",
+			manifest: Manifest{FormatVersion: 1, Files: []FileEntry{
+				{Scope: ScopeLocal, Path: "corrections.jsonl", Section: SectionCorrections, Size: 3, SHA256: "00"},
+			}},
+			entries: map[string]string{"local/corrections.jsonl": "abc"},
+		},
+		{
+			name: "missing entry",
+			manifest: Manifest{FormatVersion: 1, Files: []FileEntry{
+				{Scope: ScopeLocal, Path: "corrections.jsonl", Section: SectionCorrections, Size: 3},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeRawArchive(t, tt.manifest, tt.entries)
+			if _, err := Extract(path, ExtractOptions{Targets: targets}); err == nil {
+				t.Error("Extract() should fail")
+			}
+		})
+	}
+
+	if _, err := os.Stat(filepath.Join(filepath.Dir(targets[ScopeLocal]), "evil")); !os.IsNotExist(err) {
+		t.Error("traversal path was written")
+	}
+}
+
+func TestSectionOf(t *testing.T) {
+	for rel, want := range map[string]string{
+		"floop.db":                     SectionGraph,
+		"corrections.jsonl":            SectionCorrections,
+		"corrections-archive.jsonl.gz": SectionCorrections,
+		"manifest.yaml":                SectionConfig,
+		"packs.lock":                   SectionConfig,
+		"vectors/index.bin":            SectionVectors,
+		"gc-state.json":                SectionOther,
+	} {
+		if got := SectionOf(rel); got != want {
+			t.Errorf("SectionOf(%q) = %q, want %q", rel, got, want)
+		}
+	}
+}
@@ -216,3 +216,43 @@ func TestCompileCoalesced_Output(t *testing.T) {
 		t.Error("expected output to contain floop show hint")
 	}
 }
+
+func TestCompileTieredCoalesced(t *testing.T) {
+	compiler := NewCompiler()
+	summB := models.Behavior{ID: "summ1", Kind: models.BehaviorKindPreference}
+
+	plan := &models.InjectionPlan{
+		FullBehaviors: []models.InjectedBehavior{
+			makeInjectedBehavior("rep1", models.BehaviorKindDirective, []string{"python", "filesystem"}, 0.9, "Use pathlib.Path"),
+			makeInjectedBehaviorNamed("m1", "prefer-context-managers", models.BehaviorKindDirective, []string{"python", "filesystem"}, 0.5, "Use context managers for file handles"),
+			makeInjectedBehaviorNamed("m2", "avoid-os-walk", models.BehaviorKindDirective, []string{"python", "filesystem"}, 0.3, "Avoid os.walk"),
+			makeInjectedBehavior("ind1", models.BehaviorKindConstraint, []string{"go"}, 0.8, "Never commit secrets"),
+		},
+		SummarizedBehaviors: []models.InjectedBehavior{
+			{Behavior: &summB, Tier: models.TierSummary, Content: "Prefer tabs"},
+		},
+		TokenBudget: 500,
+	}
+
+	result := compiler.CompileTieredCoalesced(plan, NewCoalescer(DefaultCoalesceConfig()))
+
+	if len(result.CoalescedBehaviors) != 2 {
+		t.Errorf("CoalescedBehaviors = %v, want the 2 non-representative members", result.CoalescedBehaviors)
+	}
+	if strings.Contains(result.Text, "Use context managers for file handles") {
+		t.Error("coalesced member content should not be rendered in full")
+	}
+	for _, want := range []string{"Use pathlib.Path", "avoid-os-walk", "Never commit secrets", "Prefer tabs"} {
+		if !strings.Contains(result.Text, want) {
+			t.Errorf("expected output to contain %q, got:\n%s", want, result.Text)
+		}
+	}
+	if len(result.IncludedBehaviors) != 4 {
+		t.Errorf("IncludedBehaviors = %v, want all 4 full-tier behaviors", result.IncludedBehaviors)
+	}
+
+	// Without clusters the output matches CompileTiered.
+	if got, want := compiler.CompileTieredCoalesced(plan, nil).Text, compiler.CompileTiered(plan).Text; got != want {
+		t.Errorf("nil coalescer text = %q, want %q", got, want)
+	}
+}
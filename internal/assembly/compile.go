@@ -279,6 +279,10 @@ type TieredCompiledPrompt struct {
 
 	// NameOnlySection contains name-only behaviors
 	NameOnlySection string `json:"name_only_section,omitempty"`
+
+	// CoalescedBehaviors are full-tier behaviors folded into a cluster and
+	// listed by name under its representative
+	CoalescedBehaviors []string `json:"coalesced_behaviors,omitempty"`
 }
 
 // CompileTiered transforms an injection plan into a tiered prompt
@@ -344,6 +348,34 @@ func (c *Compiler) CompileTiered(plan *models.InjectionPlan) *TieredCompiledProm
 	return result
 }
 
+// CompileTieredCoalesced is CompileTiered with the full-tier behaviors
+// grouped by the coalescer: each cluster of related behaviors is rendered as
+// its representative followed by the names of the other members.
+func (c *Compiler) CompileTieredCoalesced(plan *models.InjectionPlan, coalescer *Coalescer) *TieredCompiledPrompt {
+	result := c.CompileTiered(plan)
+	if plan == nil || coalescer == nil {
+		return result
+	}
+
+	individuals, clusters := coalescer.Coalesce(plan.FullBehaviors)
+	if len(clusters) == 0 {
+		return result
+	}
+	for _, cluster := range clusters {
+		for _, m := range cluster.Members {
+			if m.Behavior != nil {
+				result.CoalescedBehaviors = append(result.CoalescedBehaviors, m.Behavior.ID)
+			}
+		}
+	}
+
+	fullText := c.CompileCoalesced(individuals, clusters)
+	result.Text = c.assembleTieredText(fullText, result.QuickReferenceSection, result.NameOnlySection, plan.OmittedBehaviors)
+	result.TotalTokens = estimateTokens(result.Text)
+
+	return result
+}
+
 // buildQuickReferenceSection creates the summarized behaviors section
 func (c *Compiler) buildQuickReferenceSection(summarized []models.InjectedBehavior) string {
 	if len(summarized) == 0 {
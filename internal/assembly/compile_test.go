@@ -605,3 +605,58 @@ func TestCompiler_CompileTiered_WithOmitted(t *testing.T) {
 		t.Errorf("expected 2 omitted behaviors, got %d", len(result.OmittedBehaviors))
 	}
 }
+
+func TestCompiler_CompileTiered_TierRendering(t *testing.T) {
+	full := models.Behavior{ID: "full-behavior", Kind: models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "Use Go modules for dependency management"}}
+	summ := models.Behavior{ID: "summary-behavior", Kind: models.BehaviorKindPreference,
+		Content: models.BehaviorContent{Canonical: "Prefer interfaces over concrete types everywhere"}}
+	name := models.Behavior{ID: "name-behavior", Name: "table-tests", Kind: models.BehaviorKindDirective}
+	omit := models.Behavior{ID: "omitted-behavior", Kind: models.BehaviorKindPreference}
+
+	plan := &models.InjectionPlan{
+		FullBehaviors:       []models.InjectedBehavior{{Behavior: &full, Tier: models.TierFull, Content: full.Content.Canonical}},
+		SummarizedBehaviors: []models.InjectedBehavior{{Behavior: &summ, Tier: models.TierSummary, Content: "interfaces > concrete types"}},
+		NameOnlyBehaviors:   []models.InjectedBehavior{{Behavior: &name, Tier: models.TierNameOnly, Content: "`table-tests` [directive]"}},
+		OmittedBehaviors:    []models.InjectedBehavior{{Behavior: &omit, Tier: models.TierOmitted}},
+		TokenBudget:         500,
+	}
+
+	tests := []struct {
+		format Format
+		want   map[models.InjectionTier]string
+	}{
+		{FormatMarkdown, map[models.InjectionTier]string{
+			models.TierFull:     "- Use Go modules for dependency management",
+			models.TierSummary:  "### Quick Reference (ask for details if needed)\n- [summary-] interfaces > concrete types",
+			models.TierNameOnly: "### Also Available (activate with floop show <id>)\n- `table-tests` [directive]",
+			models.TierOmitted:  "*1 additional behaviors available: floop show omitted-...*",
+		}},
+		{FormatXML, map[models.InjectionTier]string{
+			models.TierFull:     "Use Go modules for dependency management",
+			models.TierSummary:  "<quick-reference>\n- [summary-] interfaces &gt; concrete types\n</quick-reference>",
+			models.TierNameOnly: "<also-available>\n- `table-tests` [directive]\n</also-available>",
+			models.TierOmitted:  `<omitted count="1"/>`,
+		}},
+		{FormatPlain, map[models.InjectionTier]string{
+			models.TierFull:     "Use Go modules for dependency management",
+			models.TierSummary:  "Quick Reference (ask for details if needed):\n- [summary-] interfaces > concrete types",
+			models.TierNameOnly: "Also Available (activate with floop show <id>):\n- `table-tests` [directive]",
+			models.TierOmitted:  "*1 additional behaviors available: floop show omitted-...*",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			result := NewCompiler().WithFormat(tt.format).CompileTiered(plan)
+			for tier, want := range tt.want {
+				if !strings.Contains(result.Text, want) {
+					t.Errorf("%s tier: expected %q in:\n%s", tier, want, result.Text)
+				}
+			}
+			if strings.Contains(result.Text, summ.Content.Canonical) {
+				t.Error("summary tier should not render canonical content")
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package assembly
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// RulesFormat is an agent's native rules-file format. Rendering behaviors
+// into one keeps files such as CLAUDE.md or .cursor/rules in sync with the
+// learned store.
+type RulesFormat string
+
+const (
+	// RulesClaudeMD renders a CLAUDE.md memory file.
+	RulesClaudeMD RulesFormat = "claude-md"
+	// RulesCursor renders a Cursor rule (.cursor/rules/*.mdc) with
+	// frontmatter that applies it to every request.
+	RulesCursor RulesFormat = "cursor-rules"
+	// RulesCopilot renders .github/copilot-instructions.md.
+	RulesCopilot RulesFormat = "copilot-instructions"
+	// RulesAgentsMD renders an AGENTS.md file.
+	RulesAgentsMD RulesFormat = "agents-md"
+)
+
+// RulesFormats lists the supported rules-file formats.
+var RulesFormats = []RulesFormat{RulesClaudeMD, RulesCursor, RulesCopilot, RulesAgentsMD}
+
+// ParseRulesFormat returns the rules-file format named s.
+func ParseRulesFormat(s string) (RulesFormat, bool) {
+	if f := RulesFormat(s); slices.Contains(RulesFormats, f) {
+		return f, true
+	}
+	return "", false
+}
+
+// rulesKindOrder is the section order of a rules file; kinds not listed
+// follow under a generic heading.
+var rulesKindOrder = []models.BehaviorKind{
+	models.BehaviorKindConstraint,
+	models.BehaviorKindDirective,
+	models.BehaviorKindPreference,
+	models.BehaviorKindProcedure,
+}
+
+// FullInjections wraps behaviors as full-tier injections of their canonical
+// content, for rendering a selection that was not budgeted.
+func FullInjections(behaviors []models.Behavior) []models.InjectedBehavior {
+	injected := make([]models.InjectedBehavior, len(behaviors))
+	for i := range behaviors {
+		injected[i] = models.InjectedBehavior{
+			Behavior: &behaviors[i],
+			Tier:     models.TierFull,
+			Content:  behaviors[i].Content.Canonical,
+		}
+	}
+	return injected
+}
+
+// CompileRules renders behaviors as a complete rules file in the given
+// format, grouped by kind with constraints first. Each behavior contributes
+// its injected content, so summarized behaviors appear as their summary.
+// The file is marked as generated, since regenerating it replaces any edits.
+func CompileRules(format RulesFormat, behaviors []models.InjectedBehavior) string {
+	var sb strings.Builder
+
+	title := "Learned Behaviors"
+	switch format {
+	case RulesCursor:
+		sb.WriteString("---\n")
+		sb.WriteString("description: Behaviors learned by floop from corrections in this project\n")
+		sb.WriteString("globs:\n")
+		sb.WriteString("alwaysApply: true\n")
+		sb.WriteString("---\n\n")
+	case RulesCopilot:
+		title = "Copilot Instructions"
+	case RulesAgentsMD:
+		title = "AGENTS.md"
+	}
+	fmt.Fprintf(&sb, "# %s\n\n", title)
+	sb.WriteString("<!-- Generated by floop from learned behaviors; edits will be overwritten on regeneration. -->\n")
+
+	if len(behaviors) == 0 {
+		sb.WriteString("\nNo learned behaviors apply yet.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(compileRulesSections(behaviors, "##"))
+	return sb.String()
+}
+
+// CompileRulesSection renders behaviors as a section to embed in a
+// human-authored rules file: a "Learned Behaviors" heading with a
+// subsection per kind.
+func CompileRulesSection(behaviors []models.InjectedBehavior) string {
+	if len(behaviors) == 0 {
+		return "## Learned Behaviors\n\nNo learned behaviors apply yet.\n"
+	}
+	return "## Learned Behaviors\n" + compileRulesSections(behaviors, "###")
+}
+
+// compileRulesSections renders one section per kind, headed at the given
+// markdown level, with constraints first.
+func compileRulesSections(behaviors []models.InjectedBehavior, heading string) string {
+	// Kinds without a section of their own share a generic one, keyed "".
+	grouped := make(map[models.BehaviorKind][]models.InjectedBehavior)
+	for _, ib := range behaviors {
+		if ib.Behavior == nil {
+			continue
+		}
+		kind := ib.Behavior.Kind
+		if !slices.Contains(rulesKindOrder, kind) {
+			kind = ""
+		}
+		grouped[kind] = append(grouped[kind], ib)
+	}
+
+	var sb strings.Builder
+	compiler := NewCompiler()
+	for _, kind := range append(slices.Clip(rulesKindOrder), "") {
+		group := grouped[kind]
+		if len(group) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n%s %s\n\n", heading, compiler.kindTitle(kind))
+		for _, ib := range group {
+			fmt.Fprintf(&sb, "- %s\n", rulesLine(ib.Content))
+		}
+	}
+	return sb.String()
+}
+
+// rulesLine fits content onto a markdown list item, indenting continuation
+// lines so multi-line content stays inside the item.
+func rulesLine(content string) string {
+	return strings.ReplaceAll(strings.TrimSpace(content), "\n", "\n  ")
+}
@@ -0,0 +1,62 @@
+package assembly
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestCompileRules(t *testing.T) {
+	behaviors := []models.Behavior{
+		{ID: "b1", Kind: models.BehaviorKindDirective, Content: models.BehaviorContent{Canonical: "Use slog for logging"}},
+		{ID: "b2", Kind: models.BehaviorKindConstraint, Content: models.BehaviorContent{Canonical: "Never commit secrets"}},
+		{ID: "b3", Kind: models.BehaviorKindWorkflow, Content: models.BehaviorContent{Canonical: "Run tests\nthen lint"}},
+	}
+	injected := FullInjections(behaviors)
+
+	tests := []struct {
+		format RulesFormat
+		prefix string
+	}{
+		{RulesClaudeMD, "# Learned Behaviors\n"},
+		{RulesCursor, "---\ndescription: "},
+		{RulesCopilot, "# Copilot Instructions\n"},
+		{RulesAgentsMD, "# AGENTS.md\n"},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			text := CompileRules(tt.format, injected)
+			if !strings.HasPrefix(text, tt.prefix) {
+				t.Errorf("expected prefix %q, got:\n%s", tt.prefix, text)
+			}
+			constraints := strings.Index(text, "## Constraints\n\n- Never commit secrets")
+			directives := strings.Index(text, "## Directives\n\n- Use slog for logging")
+			if constraints < 0 || directives < 0 || constraints > directives {
+				t.Errorf("expected constraints section before directives, got:\n%s", text)
+			}
+			if !strings.Contains(text, "## Behaviors\n\n- Run tests\n  then lint") {
+				t.Errorf("expected other kinds under a generic section with indented continuation, got:\n%s", text)
+			}
+			if !strings.Contains(text, "<!-- Generated by floop") {
+				t.Errorf("expected generated-file marker, got:\n%s", text)
+			}
+		})
+	}
+
+	if text := CompileRules(RulesCursor, injected); !strings.Contains(text, "alwaysApply: true\n---\n") {
+		t.Errorf("cursor rule frontmatter missing alwaysApply:\n%s", text)
+	}
+	if text := CompileRules(RulesClaudeMD, nil); !strings.Contains(text, "No learned behaviors apply yet.") {
+		t.Errorf("expected empty notice, got:\n%s", text)
+	}
+}
+
+func TestParseRulesFormat(t *testing.T) {
+	if f, ok := ParseRulesFormat("cursor-rules"); !ok || f != RulesCursor {
+		t.Errorf("ParseRulesFormat(cursor-rules) = %q, %v", f, ok)
+	}
+	if _, ok := ParseRulesFormat("markdown"); ok {
+		t.Error("markdown is not a rules format")
+	}
+}
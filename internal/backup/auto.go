@@ -0,0 +1,177 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+const (
+	// AutoStateFile records automatic backup progress in the backup directory.
+	AutoStateFile = "auto-backup.json"
+
+	// autoLockFile serializes automatic backup state updates across floop
+	// processes sharing a backup directory.
+	autoLockFile = "auto-backup.lock"
+)
+
+// Schedule decides when automatic backups run.
+type Schedule struct {
+	// EveryLearns backs up after this many learned corrections; 0 disables.
+	EveryLearns int
+
+	// Daily backs up on the first check of each local calendar day.
+	Daily bool
+}
+
+// AutoOptions configures RunAuto.
+type AutoOptions struct {
+	Schedule Schedule
+
+	// Keep is how many backups RotateBackups keeps; 0 keeps all.
+	Keep int
+
+	// Retention, when set, is applied after rotation.
+	Retention RetentionPolicy
+
+	Backup BackupOptions
+}
+
+// AutoState is the persisted progress of automatic backups.
+type AutoState struct {
+	LastBackup  time.Time `json:"last_backup"`
+	LastPath    string    `json:"last_path,omitempty"`
+	LastError   string    `json:"last_error,omitempty"`
+	LastErrorAt time.Time `json:"last_error_at"`
+	LearnsSince int       `json:"learns_since"`
+}
+
+// Due reports whether an automatic backup is due given state, and why.
+func (s Schedule) Due(state AutoState, now time.Time) (bool, string) {
+	if s.EveryLearns > 0 && state.LearnsSince >= s.EveryLearns {
+		return true, fmt.Sprintf("%d learn(s) since the last backup", state.LearnsSince)
+	}
+	if s.Daily {
+		if state.LastBackup.IsZero() {
+			return true, "no automatic backup yet"
+		}
+		ly, lm, ld := state.LastBackup.Local().Date()
+		ny, nm, nd := now.Local().Date()
+		if ly != ny || lm != nm || ld != nd {
+			return true, "first check today"
+		}
+	}
+	return false, ""
+}
+
+// LoadAutoState reads the automatic backup state in dir. A missing or
+// unreadable state is the zero state.
+func LoadAutoState(dir string) AutoState {
+	var state AutoState
+	data, err := os.ReadFile(filepath.Join(dir, AutoStateFile))
+	if err != nil {
+		return state
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return AutoState{}
+	}
+	return state
+}
+
+// saveAutoState writes state to dir atomically.
+func saveAutoState(dir string, state AutoState) error {
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, AutoStateFile+".tmp")
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("failed to write auto-backup state: %w", err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, AutoStateFile)); err != nil {
+		return fmt.Errorf("failed to write auto-backup state: %w", err)
+	}
+	return nil
+}
+
+// updateAutoState applies fn to the state in dir under the auto-backup lock.
+func updateAutoState(ctx context.Context, dir string, fn func(*AutoState) error) error {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	lock, err := store.AcquireLock(ctx, filepath.Join(dir, autoLockFile), store.DefaultLockWait)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+
+	state := LoadAutoState(dir)
+	if err := fn(&state); err != nil {
+		return err
+	}
+	return saveAutoState(dir, state)
+}
+
+// RecordLearn counts a learned correction toward the schedule in dir.
+func RecordLearn(ctx context.Context, dir string) error {
+	return updateAutoState(ctx, dir, func(state *AutoState) error {
+		state.LearnsSince++
+		return nil
+	})
+}
+
+// RunAuto backs up graphStore into dir if opts.Schedule says one is due,
+// then rotates old backups. It returns the new backup's path, or "" when
+// none was due. Failures are recorded in the state for 'floop backup auto
+// status'.
+func RunAuto(ctx context.Context, graphStore store.GraphStore, dir string, opts AutoOptions, now time.Time) (string, error) {
+	var path string
+	var backupErr error
+	err := updateAutoState(ctx, dir, func(state *AutoState) error {
+		if due, _ := opts.Schedule.Due(*state, now); !due {
+			return nil
+		}
+		if opts.Backup.Compress {
+			path = GenerateBackupPath(dir)
+		} else {
+			path = GenerateBackupPathV1(dir)
+		}
+		if _, backupErr = BackupWithOptions(ctx, graphStore, path, opts.Backup); backupErr != nil {
+			state.LastError = backupErr.Error()
+			state.LastErrorAt = now
+			return nil
+		}
+		state.LastBackup = now
+		state.LastPath = path
+		state.LastError = ""
+		state.LastErrorAt = time.Time{}
+		state.LearnsSince = 0
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if backupErr != nil {
+		return "", backupErr
+	}
+	if path == "" {
+		return "", nil
+	}
+
+	if opts.Keep > 0 {
+		if err := RotateBackups(dir, opts.Keep); err != nil {
+			return path, err
+		}
+	}
+	if opts.Retention != nil {
+		if _, err := ApplyRetention(dir, opts.Retention); err != nil {
+			return path, err
+		}
+	}
+	return path, nil
+}
@@ -0,0 +1,107 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestScheduleDue(t *testing.T) {
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.Local)
+	tests := []struct {
+		name     string
+		schedule Schedule
+		state    AutoState
+		want     bool
+	}{
+		{"disabled", Schedule{}, AutoState{LearnsSince: 5}, false},
+		{"learns below threshold", Schedule{EveryLearns: 3}, AutoState{LearnsSince: 2}, false},
+		{"learns at threshold", Schedule{EveryLearns: 3}, AutoState{LearnsSince: 3}, true},
+		{"daily never run", Schedule{Daily: true}, AutoState{}, true},
+		{"daily ran today", Schedule{Daily: true}, AutoState{LastBackup: now.Add(-time.Hour)}, false},
+		{"daily ran yesterday", Schedule{Daily: true}, AutoState{LastBackup: now.Add(-12 * time.Hour)}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := tt.schedule.Due(tt.state, now)
+			if got != tt.want {
+				t.Errorf("Due() = %v (%q), want %v", got, reason, tt.want)
+			}
+			if got && reason == "" {
+				t.Error("Due() gave no reason")
+			}
+		})
+	}
+}
+
+func TestRunAuto(t *testing.T) {
+	s := createTestStore(t)
+	defer s.Close()
+	addTestData(t, s)
+	ctx := context.Background()
+	dir := t.TempDir()
+	opts := AutoOptions{
+		Schedule: Schedule{EveryLearns: 2},
+		Keep:     2,
+		Backup:   BackupOptions{Compress: true},
+	}
+
+	// Older backups beyond Keep are rotated away
+	for _, name := range []string{"floop-backup-20200101-000000.json.gz", "floop-backup-20200102-000000.json.gz"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := RecordLearn(ctx, dir); err != nil {
+		t.Fatalf("RecordLearn: %v", err)
+	}
+	path, err := RunAuto(ctx, s, dir, opts, time.Now())
+	if err != nil || path != "" {
+		t.Fatalf("RunAuto after 1 learn = %q, %v; want nothing due", path, err)
+	}
+
+	if err := RecordLearn(ctx, dir); err != nil {
+		t.Fatalf("RecordLearn: %v", err)
+	}
+	path, err = RunAuto(ctx, s, dir, opts, time.Now())
+	if err != nil || path == "" {
+		t.Fatalf("RunAuto after 2 learns = %q, %v; want a backup", path, err)
+	}
+	if _, err := ReadV2(path); err != nil {
+		t.Errorf("ReadV2(%s): %v", path, err)
+	}
+
+	state := LoadAutoState(dir)
+	if state.LearnsSince != 0 || state.LastPath != path || state.LastBackup.IsZero() {
+		t.Errorf("state after backup = %+v, want learns reset and last backup recorded", state)
+	}
+	backups, err := ListBackups(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(backups) != 2 || backups[0].Path != path {
+		t.Errorf("backups after rotation = %v, want the new one and one older", backups)
+	}
+}
+
+func TestRunAuto_RecordsFailure(t *testing.T) {
+	s := createTestStore(t)
+	s.Close() // a closed store cannot be read
+	dir := t.TempDir()
+	now := time.Now()
+
+	opts := AutoOptions{Schedule: Schedule{Daily: true}, Backup: BackupOptions{Compress: true}}
+	if _, err := RunAuto(context.Background(), s, dir, opts, now); err == nil {
+		t.Fatal("RunAuto with a closed store succeeded")
+	}
+	state := LoadAutoState(dir)
+	if state.LastError == "" || !state.LastErrorAt.Equal(now) {
+		t.Errorf("state = %+v, want the failure recorded", state)
+	}
+	if !state.LastBackup.IsZero() {
+		t.Error("failed backup recorded as last backup")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"sort"
@@ -84,6 +85,7 @@ type BackupOptions struct {
 	FloopVersion string            // floop binary version from ldflags
 	AllowedDirs  []string          // nil = skip path validation
 	Metadata     map[string]string // additional metadata for the backup header
+	Passphrase   string            // encrypt the payload (AES-256-GCM, scrypt); requires Compress
 }
 
 // Backup exports all nodes and edges from the store to a V2 compressed backup file.
@@ -105,6 +107,10 @@ func BackupWithOptions(ctx context.Context, graphStore store.GraphStore, outputP
 		}
 	}
 
+	if opts.Passphrase != "" && !opts.Compress {
+		return nil, fmt.Errorf("encrypted backups must be compressed (V2)")
+	}
+
 	bf, err := collectGraph(ctx, graphStore)
 	if err != nil {
 		return nil, err
@@ -114,6 +120,7 @@ func BackupWithOptions(ctx context.Context, graphStore store.GraphStore, outputP
 		writeOpts := &WriteOptions{
 			FloopVersion: opts.FloopVersion,
 			Metadata:     opts.Metadata,
+			Passphrase:   opts.Passphrase,
 		}
 		if err := WriteV2(outputPath, bf, writeOpts); err != nil {
 			return nil, fmt.Errorf("failed to write V2 backup: %w", err)
@@ -166,25 +173,60 @@ type RestoreResult struct {
 	NodesSkipped  int `json:"nodes_skipped"`
 	EdgesRestored int `json:"edges_restored"`
 	EdgesSkipped  int `json:"edges_skipped"`
+
+	// Set when RestoreOptions.Filter left nodes or edges out.
+	NodesFiltered int `json:"nodes_filtered,omitempty"`
+	EdgesFiltered int `json:"edges_filtered,omitempty"`
+
+	// DryRun results describe what a restore would do; Changes lists it
+	// node by node and edge by edge.
+	DryRun  bool            `json:"dry_run,omitempty"`
+	Changes []RestoreChange `json:"changes,omitempty"`
+}
+
+// RestoreOptions controls restore behavior.
+type RestoreOptions struct {
+	Mode        RestoreMode
+	Passphrase  string   // decrypts encrypted backups
+	AllowedDirs []string // nil = skip path validation
+
+	// Filter restores only the nodes it selects, and the edges between
+	// them and nodes already in the store.
+	Filter RestoreFilter
+
+	// DryRun reports what the restore would do without writing.
+	DryRun bool
 }
 
 // Restore imports nodes and edges from a backup file into the store.
-// Automatically detects V1 and V2 format.
+// Automatically detects V1, V2 and V3 format; a V3 backup is restored with
+// the chain of bases it was taken against.
 // If allowedDirs is non-empty, the inputPath is validated against them.
 // Pass nil to skip validation (for internal/default paths only).
 //
-// Schema version checks (V2 only):
+// Schema version checks (V2 and V3 only):
 //   - SchemaVersion > store.SchemaVersion: returns error (backup too new)
 //   - SchemaVersion < store.SchemaVersion: prints warning to stderr
 //   - SchemaVersion == 0: silent (old format, no schema version)
+//
+// Encrypted backups fail with ErrPassphraseRequired; see RestoreWithOptions.
 func Restore(ctx context.Context, graphStore store.GraphStore, inputPath string, mode RestoreMode, allowedDirs ...string) (*RestoreResult, error) {
-	if len(allowedDirs) > 0 {
-		if err := pathutil.ValidatePath(inputPath, allowedDirs); err != nil {
+	return RestoreWithOptions(ctx, graphStore, inputPath, RestoreOptions{
+		Mode:        mode,
+		AllowedDirs: allowedDirs,
+	})
+}
+
+// RestoreWithOptions is Restore with explicit options. The backup is read,
+// decrypted and filtered before the store is touched.
+func RestoreWithOptions(ctx context.Context, graphStore store.GraphStore, inputPath string, opts RestoreOptions) (*RestoreResult, error) {
+	if len(opts.AllowedDirs) > 0 {
+		if err := pathutil.ValidatePath(inputPath, opts.AllowedDirs); err != nil {
 			return nil, fmt.Errorf("restore path rejected: %w", err)
 		}
 	}
 
-	backup, err := readBackupAuto(inputPath)
+	backup, err := readBackupAuto(inputPath, opts.Passphrase)
 	if err != nil {
 		return nil, err
 	}
@@ -194,17 +236,33 @@ func Restore(ctx context.Context, graphStore store.GraphStore, inputPath string,
 		return nil, err
 	}
 
-	return restoreFromBackup(ctx, graphStore, backup, mode)
+	backup, nodesFiltered, edgesFiltered, err := filterBackup(ctx, graphStore, backup, opts.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *RestoreResult
+	if opts.DryRun {
+		result, err = planRestore(ctx, graphStore, backup, opts.Mode)
+	} else {
+		result, err = restoreFromBackup(ctx, graphStore, backup, opts.Mode)
+	}
+	if err != nil {
+		return nil, err
+	}
+	result.NodesFiltered = nodesFiltered
+	result.EdgesFiltered = edgesFiltered
+	return result, nil
 }
 
-// checkSchemaVersion reads the V2 header and validates schema version compatibility.
+// checkSchemaVersion reads the V2 or V3 header and validates schema version compatibility.
 // Returns an error if the backup's schema version is newer than the current store schema.
 // Prints a warning to stderr if the backup's schema version is older.
 // Returns nil silently for V1 backups or V2 backups with schema_version=0.
 func checkSchemaVersion(inputPath string) error {
-	header, err := ReadV2Header(inputPath)
+	header, err := ReadHeader(inputPath)
 	if err != nil {
-		return nil // V1, unreadable, or non-V2 — skip check
+		return nil // V1 or unreadable — skip check
 	}
 
 	if header.SchemaVersion == 0 {
@@ -217,23 +275,26 @@ func checkSchemaVersion(inputPath string) error {
 	}
 
 	if header.SchemaVersion < store.SchemaVersion {
-		fmt.Fprintf(os.Stderr, "warning: backup schema version %d is older than current schema version %d; data will be migrated on restore\n",
-			header.SchemaVersion, store.SchemaVersion)
+		slog.Warn("backup schema is older than the current schema; data will be migrated on restore",
+			"backup_schema", header.SchemaVersion, "schema", store.SchemaVersion)
 	}
 
 	return nil
 }
 
-// readBackupAuto detects the format and reads the backup file.
-func readBackupAuto(inputPath string) (*BackupFormat, error) {
+// readBackupAuto detects the format and reads the backup file, decrypting
+// it with passphrase if needed.
+func readBackupAuto(inputPath, passphrase string) (*BackupFormat, error) {
 	version, err := DetectFormat(inputPath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to detect backup format: %w", err)
 	}
 
 	switch version {
+	case FormatV3:
+		return ReadChain(inputPath, passphrase)
 	case FormatV2:
-		return ReadV2(inputPath)
+		return ReadV2Encrypted(inputPath, passphrase)
 	case FormatV1:
 		return readV1(inputPath)
 	default:
@@ -272,6 +333,63 @@ func readV1(inputPath string) (*BackupFormat, error) {
 
 // restoreFromBackup applies a parsed BackupFormat to the store.
 func restoreFromBackup(ctx context.Context, graphStore store.GraphStore, backup *BackupFormat, mode RestoreMode) (*RestoreResult, error) {
+	result, err := restoreBatch(ctx, graphStore, backup, mode)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil {
+		result, err = restoreEach(ctx, graphStore, backup, mode)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if err := graphStore.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("failed to sync after restore: %w", err)
+	}
+
+	return result, nil
+}
+
+// restoreBatch restores the backup in one batch when the store supports
+// it. In merge mode, nodes already in the store are skipped; if the batch
+// then fails (say, on a conflicting node), it returns a nil result so the
+// caller can retry one at a time and skip just the conflicts.
+func restoreBatch(ctx context.Context, graphStore store.GraphStore, backup *BackupFormat, mode RestoreMode) (*RestoreResult, error) {
+	bw, ok := graphStore.(store.BatchWriter)
+	if !ok {
+		return nil, nil
+	}
+
+	result := &RestoreResult{}
+	nodes := make([]store.Node, 0, len(backup.Nodes))
+	for _, bn := range backup.Nodes {
+		if mode == RestoreMerge {
+			existing, err := graphStore.GetNode(ctx, bn.ID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check existing node %s: %w", bn.ID, err)
+			}
+			if existing != nil {
+				result.NodesSkipped++
+				continue
+			}
+		}
+		nodes = append(nodes, bn.Node)
+	}
+
+	if err := bw.BatchAdd(ctx, nodes, backup.Edges); err != nil {
+		if mode == RestoreMerge {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to restore: %w", err)
+	}
+	result.NodesRestored = len(nodes)
+	result.EdgesRestored = len(backup.Edges)
+	return result, nil
+}
+
+// restoreEach restores the backup one node and edge at a time.
+func restoreEach(ctx context.Context, graphStore store.GraphStore, backup *BackupFormat, mode RestoreMode) (*RestoreResult, error) {
 	result := &RestoreResult{}
 
 	for _, bn := range backup.Nodes {
@@ -307,10 +425,6 @@ func restoreFromBackup(ctx context.Context, graphStore store.GraphStore, backup
 		result.EdgesRestored++
 	}
 
-	if err := graphStore.Sync(ctx); err != nil {
-		return nil, fmt.Errorf("failed to sync after restore: %w", err)
-	}
-
 	return result, nil
 }
 
@@ -334,7 +448,8 @@ func isBackupFile(name string) bool {
 }
 
 // RotateBackups keeps only the most recent N backups, deleting older ones.
-// Matches both .json (V1) and .json.gz (V2) backup files.
+// Matches both .json (V1) and .json.gz (V2, V3) backup files. Bases of kept
+// incremental backups are kept too.
 func RotateBackups(dir string, keepN int) error {
 	entries, err := os.ReadDir(dir)
 	if err != nil {
@@ -357,8 +472,15 @@ func RotateBackups(dir string, keepN int) error {
 	})
 
 	if len(backups) > keepN {
+		kept := make(map[string]bool)
+		for _, b := range backups[:keepN] {
+			keepWithBases(kept, filepath.Join(dir, b.Name()))
+		}
 		for _, b := range backups[keepN:] {
 			path := filepath.Join(dir, b.Name())
+			if kept[path] {
+				continue
+			}
 			if err := os.Remove(path); err != nil {
 				return fmt.Errorf("failed to remove old backup %s: %w", b.Name(), err)
 			}
@@ -367,3 +489,12 @@ func RotateBackups(dir string, keepN int) error {
 
 	return nil
 }
+
+// keepWithBases marks path, and the bases it depends on if it is an
+// incremental backup, as kept.
+func keepWithBases(kept map[string]bool, path string) {
+	kept[path] = true
+	for _, base := range chainBases(path) {
+		kept[base] = true
+	}
+}
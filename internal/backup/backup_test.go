@@ -184,6 +184,43 @@ func TestRestore_MergeMode(t *testing.T) {
 	}
 }
 
+func TestRestore_MergeModeSkipsConflicts(t *testing.T) {
+	srcStore := createTestStore(t)
+	defer srcStore.Close()
+	addTestData(t, srcStore)
+
+	ctx := context.Background()
+	backupPath := filepath.Join(t.TempDir(), "test-backup.json")
+	if _, err := Backup(ctx, srcStore, backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// node-b's content already exists under another ID, so the batch fails
+	// and the restore retries one at a time
+	dstStore := createTestStore(t)
+	defer dstStore.Close()
+	if _, err := dstStore.AddNode(ctx, store.Node{
+		ID:      "other-b",
+		Kind:    "behavior",
+		Content: map[string]interface{}{"name": "other-b", "kind": "directive", "content": map[string]interface{}{"canonical": "Content for node-b"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := Restore(ctx, dstStore, backupPath, RestoreMerge)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if result.NodesRestored != 2 || result.NodesSkipped != 1 {
+		t.Errorf("NodesRestored = %d, NodesSkipped = %d; want 2, 1", result.NodesRestored, result.NodesSkipped)
+	}
+	for _, id := range []string{"node-a", "node-c"} {
+		if node, _ := dstStore.GetNode(ctx, id); node == nil {
+			t.Errorf("node %s not restored", id)
+		}
+	}
+}
+
 func TestRotateBackups(t *testing.T) {
 	dir := t.TempDir()
 
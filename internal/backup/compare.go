@@ -0,0 +1,213 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"reflect"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Comparison is the behavior-level difference between two graphs, as
+// reported by 'floop diff'.
+type Comparison struct {
+	Added        []BehaviorSummary `json:"added"`
+	Removed      []BehaviorSummary `json:"removed"`
+	Changed      []BehaviorChange  `json:"changed"`
+	Unchanged    int               `json:"unchanged"`
+	EdgesAdded   []EdgeRef         `json:"edges_added"`
+	EdgesRemoved []EdgeRef         `json:"edges_removed"`
+	EdgesChanged []EdgeChange      `json:"edges_changed"`
+}
+
+// BehaviorSummary identifies a behavior in a comparison.
+type BehaviorSummary struct {
+	ID         string  `json:"id"`
+	Name       string  `json:"name,omitempty"`
+	Kind       string  `json:"kind,omitempty"`
+	Confidence float64 `json:"confidence"`
+}
+
+// BehaviorChange is a behavior present on both sides that differs.
+// Fields names what changed: "kind", "name", "content", "when", "tags" or
+// "confidence". Usage statistics are not compared.
+type BehaviorChange struct {
+	ID             string   `json:"id"`
+	Name           string   `json:"name,omitempty"`
+	Fields         []string `json:"fields"`
+	ConfidenceFrom float64  `json:"confidence_from"`
+	ConfidenceTo   float64  `json:"confidence_to"`
+}
+
+// ConfidenceDrift is how much the behavior's confidence moved.
+func (c BehaviorChange) ConfidenceDrift() float64 {
+	return c.ConfidenceTo - c.ConfidenceFrom
+}
+
+// EdgeChange is an edge present on both sides whose weight differs.
+type EdgeChange struct {
+	EdgeRef
+	WeightFrom float64 `json:"weight_from"`
+	WeightTo   float64 `json:"weight_to"`
+}
+
+// Snapshot returns every node and edge in graphStore, as a full backup
+// would hold them.
+func Snapshot(ctx context.Context, graphStore store.GraphStore) (*BackupFormat, error) {
+	return collectGraph(ctx, graphStore)
+}
+
+// ReadFile reads a backup file of any format, decrypting it with
+// passphrase if needed, and returns the graph it holds. An incremental
+// backup is read with its chain.
+func ReadFile(path, passphrase string) (*BackupFormat, error) {
+	return readBackupAuto(path, passphrase)
+}
+
+// Compare reports how to differs from from. Results are sorted by ID.
+func Compare(from, to *BackupFormat) *Comparison {
+	c := &Comparison{
+		Added:        []BehaviorSummary{},
+		Removed:      []BehaviorSummary{},
+		Changed:      []BehaviorChange{},
+		EdgesAdded:   []EdgeRef{},
+		EdgesRemoved: []EdgeRef{},
+		EdgesChanged: []EdgeChange{},
+	}
+
+	// Round-trip both sides through JSON so a live store and a backup file
+	// compare alike.
+	fromNodes := normalizedNodes(from)
+	toNodes := normalizedNodes(to)
+
+	for id, n := range toNodes {
+		old, ok := fromNodes[id]
+		if !ok {
+			c.Added = append(c.Added, summarize(n))
+			continue
+		}
+		if fields := changedFields(old, n); len(fields) > 0 {
+			c.Changed = append(c.Changed, BehaviorChange{
+				ID:             id,
+				Name:           models.NodeToBehavior(n).Name,
+				Fields:         fields,
+				ConfidenceFrom: models.NodeToBehavior(old).Confidence,
+				ConfidenceTo:   models.NodeToBehavior(n).Confidence,
+			})
+		} else {
+			c.Unchanged++
+		}
+	}
+	for id, n := range fromNodes {
+		if _, ok := toNodes[id]; !ok {
+			c.Removed = append(c.Removed, summarize(n))
+		}
+	}
+
+	fromEdges := make(map[EdgeRef]store.Edge, len(from.Edges))
+	for _, e := range from.Edges {
+		fromEdges[edgeRef(e)] = e
+	}
+	toEdges := make(map[EdgeRef]store.Edge, len(to.Edges))
+	for _, e := range to.Edges {
+		toEdges[edgeRef(e)] = e
+		old, ok := fromEdges[edgeRef(e)]
+		switch {
+		case !ok:
+			c.EdgesAdded = append(c.EdgesAdded, edgeRef(e))
+		case math.Abs(old.Weight-e.Weight) > 1e-9:
+			c.EdgesChanged = append(c.EdgesChanged, EdgeChange{EdgeRef: edgeRef(e), WeightFrom: old.Weight, WeightTo: e.Weight})
+		}
+	}
+	for _, e := range from.Edges {
+		if _, ok := toEdges[edgeRef(e)]; !ok {
+			c.EdgesRemoved = append(c.EdgesRemoved, edgeRef(e))
+		}
+	}
+
+	sort.Slice(c.Added, func(i, j int) bool { return c.Added[i].ID < c.Added[j].ID })
+	sort.Slice(c.Removed, func(i, j int) bool { return c.Removed[i].ID < c.Removed[j].ID })
+	sort.Slice(c.Changed, func(i, j int) bool { return c.Changed[i].ID < c.Changed[j].ID })
+	for _, edges := range [][]EdgeRef{c.EdgesAdded, c.EdgesRemoved} {
+		sort.Slice(edges, func(i, j int) bool { return edgeLess(edges[i], edges[j]) })
+	}
+	sort.Slice(c.EdgesChanged, func(i, j int) bool { return edgeLess(c.EdgesChanged[i].EdgeRef, c.EdgesChanged[j].EdgeRef) })
+	return c
+}
+
+// normalizedNodes returns b's nodes by ID after a JSON round trip.
+func normalizedNodes(b *BackupFormat) map[string]store.Node {
+	nodes := make(map[string]store.Node, len(b.Nodes))
+	for _, bn := range b.Nodes {
+		n := bn.Node
+		if data, err := json.Marshal(bn); err == nil {
+			var rt BackupNode
+			if json.Unmarshal(data, &rt) == nil {
+				n = rt.Node
+			}
+		}
+		nodes[n.ID] = n
+	}
+	return nodes
+}
+
+// changedFields names the behavior fields that differ between a and b.
+func changedFields(a, b store.Node) []string {
+	ba, bb := models.NodeToBehavior(a), models.NodeToBehavior(b)
+	var fields []string
+	if a.Kind != b.Kind || ba.Kind != bb.Kind {
+		fields = append(fields, "kind")
+	}
+	if ba.Name != bb.Name {
+		fields = append(fields, "name")
+	}
+	if ba.Content.Canonical != bb.Content.Canonical || ba.Content.Summary != bb.Content.Summary ||
+		!reflect.DeepEqual(ba.Content.Structured, bb.Content.Structured) {
+		fields = append(fields, "content")
+	}
+	if !reflect.DeepEqual(ba.When, bb.When) {
+		fields = append(fields, "when")
+	}
+	if !sameStrings(ba.Content.Tags, bb.Content.Tags) {
+		fields = append(fields, "tags")
+	}
+	if math.Abs(ba.Confidence-bb.Confidence) > 1e-9 {
+		fields = append(fields, "confidence")
+	}
+	return fields
+}
+
+func summarize(n store.Node) BehaviorSummary {
+	b := models.NodeToBehavior(n)
+	return BehaviorSummary{ID: n.ID, Name: b.Name, Kind: string(b.Kind), Confidence: b.Confidence}
+}
+
+func sameStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	seen := make(map[string]int, len(a))
+	for _, s := range a {
+		seen[s]++
+	}
+	for _, s := range b {
+		if seen[s] == 0 {
+			return false
+		}
+		seen[s]--
+	}
+	return true
+}
+
+func edgeLess(a, b EdgeRef) bool {
+	if a.Source != b.Source {
+		return a.Source < b.Source
+	}
+	if a.Target != b.Target {
+		return a.Target < b.Target
+	}
+	return a.Kind < b.Kind
+}
@@ -0,0 +1,63 @@
+package backup
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	s := createTestStore(t)
+	defer s.Close()
+	addTestData(t, s)
+
+	ctx := context.Background()
+	before, err := Snapshot(ctx, s)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+
+	if c := Compare(before, before); len(c.Added)+len(c.Removed)+len(c.Changed)+len(c.EdgesAdded)+len(c.EdgesRemoved)+len(c.EdgesChanged) != 0 || c.Unchanged != 3 {
+		t.Errorf("Compare(before, before) = %+v, want no differences", c)
+	}
+
+	nodeA, _ := s.GetNode(ctx, "node-a")
+	nodeA.Metadata["confidence"] = 0.5
+	if err := s.UpdateNode(ctx, *nodeA); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	nodeB, _ := s.GetNode(ctx, "node-b")
+	nodeB.Content["content"] = map[string]interface{}{"canonical": "Rewritten node-b"}
+	if err := s.UpdateNode(ctx, *nodeB); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	if err := s.DeleteNode(ctx, "node-c"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+
+	after, err := Snapshot(ctx, s)
+	if err != nil {
+		t.Fatalf("Snapshot() error = %v", err)
+	}
+	c := Compare(before, after)
+
+	if len(c.Removed) != 1 || c.Removed[0].ID != "node-c" {
+		t.Errorf("Removed = %v, want node-c", c.Removed)
+	}
+	if len(c.Changed) != 2 {
+		t.Fatalf("Changed = %v, want node-a and node-b", c.Changed)
+	}
+	if a := c.Changed[0]; a.ID != "node-a" || len(a.Fields) != 1 || a.Fields[0] != "confidence" || a.ConfidenceDrift() > -0.29 {
+		t.Errorf("Changed[0] = %+v, want node-a confidence 0.8 -> 0.5", a)
+	}
+	if b := c.Changed[1]; b.ID != "node-b" || len(b.Fields) != 1 || b.Fields[0] != "content" {
+		t.Errorf("Changed[1] = %+v, want node-b content", b)
+	}
+	if len(c.EdgesRemoved) != 1 || c.EdgesRemoved[0].Target != "node-c" {
+		t.Errorf("EdgesRemoved = %v, want node-b -> node-c", c.EdgesRemoved)
+	}
+
+	reverse := Compare(after, before)
+	if len(reverse.Added) != 1 || reverse.Added[0].ID != "node-c" || len(reverse.EdgesAdded) != 1 {
+		t.Errorf("Compare(after, before) added = %v / %v, want node-c and its edge", reverse.Added, reverse.EdgesAdded)
+	}
+}
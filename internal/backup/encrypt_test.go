@@ -0,0 +1,137 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestBackupEncrypted_RoundTrip(t *testing.T) {
+	srcStore := createTestStore(t)
+	defer srcStore.Close()
+	addTestData(t, srcStore)
+
+	ctx := context.Background()
+	backupPath := filepath.Join(t.TempDir(), "encrypted.json.gz")
+	if _, err := BackupWithOptions(ctx, srcStore, backupPath, BackupOptions{Compress: true, Passphrase: "correct horse"}); err != nil {
+		t.Fatalf("BackupWithOptions() error = %v", err)
+	}
+
+	header, err := ReadV2Header(backupPath)
+	if err != nil {
+		t.Fatalf("ReadV2Header() error = %v", err)
+	}
+	enc := header.Encryption
+	if enc == nil || enc.Cipher != "aes-256-gcm" || enc.KDF != "scrypt" || len(enc.Salt) == 0 || len(enc.Nonce) == 0 || enc.N == 0 {
+		t.Fatalf("header.Encryption = %+v, want aes-256-gcm with scrypt parameters", enc)
+	}
+	if err := VerifyChecksum(backupPath); err != nil {
+		t.Errorf("VerifyChecksum() without passphrase error = %v", err)
+	}
+
+	if _, err := ReadV2(backupPath); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("ReadV2() error = %v, want ErrPassphraseRequired", err)
+	}
+	if _, err := ReadV2Encrypted(backupPath, "wrong"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("ReadV2Encrypted(wrong) error = %v, want ErrWrongPassphrase", err)
+	}
+
+	dstStore := createTestStore(t)
+	defer dstStore.Close()
+	if _, err := Restore(ctx, dstStore, backupPath, RestoreMerge); !errors.Is(err, ErrPassphraseRequired) {
+		t.Errorf("Restore() error = %v, want ErrPassphraseRequired", err)
+	}
+	result, err := RestoreWithOptions(ctx, dstStore, backupPath, RestoreOptions{Mode: RestoreMerge, Passphrase: "correct horse"})
+	if err != nil {
+		t.Fatalf("RestoreWithOptions() error = %v", err)
+	}
+	if result.NodesRestored != 3 || result.EdgesRestored != 2 {
+		t.Errorf("restored %d nodes, %d edges, want 3, 2", result.NodesRestored, result.EdgesRestored)
+	}
+}
+
+func TestDecryptPayload_RejectsCostlyParameters(t *testing.T) {
+	info, data, err := encryptPayload([]byte("payload"), "secret", 2)
+	if err != nil {
+		t.Fatalf("encryptPayload() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		tamper func(*EncryptionInfo)
+	}{
+		{"huge N", func(i *EncryptionInfo) { i.N = 1 << 20 }},
+		{"huge r", func(i *EncryptionInfo) { i.R = 1 << 20 }},
+		{"huge p", func(i *EncryptionInfo) { i.P = 1 << 20 }},
+		{"small r", func(i *EncryptionInfo) { i.R = 1 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crafted := *info
+			tt.tamper(&crafted)
+			_, err := decryptPayload(&crafted, data, "secret", 2)
+			if err == nil || errors.Is(err, ErrWrongPassphrase) {
+				t.Errorf("decryptPayload() error = %v, want a parameter error", err)
+			}
+		})
+	}
+
+	if _, err := decryptPayload(info, data, "secret", 2); err != nil {
+		t.Errorf("decryptPayload() of untampered payload error = %v", err)
+	}
+}
+
+func TestBackupEncrypted_RequiresCompression(t *testing.T) {
+	s := createTestStore(t)
+	defer s.Close()
+
+	_, err := BackupWithOptions(context.Background(), s, filepath.Join(t.TempDir(), "b.json"), BackupOptions{Passphrase: "secret"})
+	if err == nil {
+		t.Error("BackupWithOptions() of an encrypted V1 backup succeeded")
+	}
+}
+
+func TestBackupEncrypted_IncrementalChain(t *testing.T) {
+	s := createTestStore(t)
+	defer s.Close()
+	addTestData(t, s)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, "floop-backup-20260101-000000.json.gz")
+	if _, err := BackupWithOptions(ctx, s, fullPath, BackupOptions{Compress: true, Passphrase: "secret"}); err != nil {
+		t.Fatalf("BackupWithOptions() error = %v", err)
+	}
+	if err := s.DeleteNode(ctx, "node-c"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+	incr := filepath.Join(dir, "floop-backup-20260101-000100-incr.json.gz")
+	if _, _, err := BackupIncremental(ctx, s, fullPath, incr, BackupOptions{}); !errors.Is(err, ErrPassphraseRequired) {
+		t.Fatalf("BackupIncremental() without passphrase error = %v, want ErrPassphraseRequired", err)
+	}
+	if _, _, err := BackupIncremental(ctx, s, fullPath, incr, BackupOptions{Passphrase: "secret"}); err != nil {
+		t.Fatalf("BackupIncremental() error = %v", err)
+	}
+
+	data, err := os.ReadFile(incr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if bytes.Contains(data, []byte("node-c")) {
+		t.Error("encrypted increment contains plaintext node IDs")
+	}
+	if err := VerifyChain(incr); err != nil {
+		t.Errorf("VerifyChain() without passphrase error = %v", err)
+	}
+
+	full, err := ReadChain(incr, "secret")
+	if err != nil {
+		t.Fatalf("ReadChain() error = %v", err)
+	}
+	if len(full.Nodes) != 2 {
+		t.Errorf("ReadChain() returned %d nodes, want 2", len(full.Nodes))
+	}
+}
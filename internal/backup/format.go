@@ -22,12 +22,13 @@ import (
 const (
 	FormatV1 = 1
 	FormatV2 = 2
+	FormatV3 = 3 // differential: changes since a base backup
 )
 
 // MaxDecompressedSize is the maximum allowed size of decompressed backup data (200MB).
 const MaxDecompressedSize = 200 * 1024 * 1024
 
-// BackupHeader is the plain-text first line of a V2 backup file.
+// BackupHeader is the plain-text first line of a V2 or V3 backup file.
 type BackupHeader struct {
 	Version       int               `json:"version"`
 	SchemaVersion int               `json:"schema_version,omitempty"`
@@ -37,16 +38,33 @@ type BackupHeader struct {
 	EdgeCount     int               `json:"edge_count"`
 	Compressed    bool              `json:"compressed"`
 	Metadata      map[string]string `json:"metadata,omitempty"`
+
+	// Encryption is set when the payload is encrypted with a passphrase.
+	// The checksum covers the encrypted bytes.
+	Encryption *EncryptionInfo `json:"encryption,omitempty"`
+
+	// V3 only: the base backup's file name, in the same directory, the
+	// SHA-256 of the whole base file, and this increment's position in its
+	// chain (the full backup at its root is revision 0).
+	Base         string `json:"base,omitempty"`
+	BaseChecksum string `json:"base_checksum,omitempty"`
+	Revision     int    `json:"revision,omitempty"`
 }
 
 // WriteOptions controls metadata population when writing V2 backups.
 type WriteOptions struct {
 	FloopVersion string            // floop binary version (from ldflags)
 	Metadata     map[string]string // additional user-supplied metadata
+	Passphrase   string            // encrypt the payload with this passphrase; empty = plaintext
+
+	// Finalize, if set, is called with the complete header just before it
+	// is written, e.g. to sign it.
+	Finalize func(header *BackupHeader) error
 }
 
-// DetectFormat reads the first bytes of a file to determine V1 vs V2.
-// V2 files have a header line with "version":2. V1 files are plain JSON starting with '{'.
+// DetectFormat reads the first bytes of a file to determine its format.
+// V2 and V3 files have a header line with "version":2 or 3. V1 files are
+// plain JSON starting with '{'.
 func DetectFormat(path string) (int, error) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -70,8 +88,8 @@ func DetectFormat(path string) (int, error) {
 	// Try to parse as a header
 	var header BackupHeader
 	if err := json.Unmarshal([]byte(firstLine), &header); err == nil {
-		if header.Version == FormatV2 {
-			return FormatV2, nil
+		if header.Version == FormatV2 || header.Version == FormatV3 {
+			return header.Version, nil
 		}
 	}
 
@@ -86,74 +104,92 @@ func DetectFormat(path string) (int, error) {
 // WriteV2 writes a BackupFormat as a V2 file: header line + gzip-compressed payload.
 // opts may be nil for backward compatibility (no metadata population).
 func WriteV2(path string, b *BackupFormat, opts *WriteOptions) error {
+	_, err := writeHeadered(path, BackupHeader{
+		Version:   FormatV2,
+		CreatedAt: b.CreatedAt,
+		NodeCount: len(b.Nodes),
+		EdgeCount: len(b.Edges),
+	}, b, opts)
+	return err
+}
+
+// writeHeadered writes header and payload in the layout shared by V2 and
+// V3 files, filling in the header's checksum, schema version and metadata.
+func writeHeadered(path string, header BackupHeader, payload interface{}, opts *WriteOptions) (*BackupHeader, error) {
 	// Marshal the payload
-	payload, err := json.Marshal(b)
+	data, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("marshaling payload: %w", err)
+		return nil, fmt.Errorf("marshaling payload: %w", err)
 	}
 
 	// Compress the payload
 	var compressed bytes.Buffer
 	gzw, err := gzip.NewWriterLevel(&compressed, gzip.DefaultCompression)
 	if err != nil {
-		return fmt.Errorf("creating gzip writer: %w", err)
+		return nil, fmt.Errorf("creating gzip writer: %w", err)
 	}
-	if _, err := gzw.Write(payload); err != nil {
-		return fmt.Errorf("compressing payload: %w", err)
+	if _, err := gzw.Write(data); err != nil {
+		return nil, fmt.Errorf("compressing payload: %w", err)
 	}
 	if err := gzw.Close(); err != nil {
-		return fmt.Errorf("closing gzip writer: %w", err)
+		return nil, fmt.Errorf("closing gzip writer: %w", err)
 	}
 
-	// Compute SHA-256 of the compressed data
-	hash := sha256.Sum256(compressed.Bytes())
-	checksum := "sha256:" + hex.EncodeToString(hash[:])
-
-	// Build header
-	header := BackupHeader{
-		Version:       FormatV2,
-		SchemaVersion: store.SchemaVersion,
-		CreatedAt:     b.CreatedAt,
-		Checksum:      checksum,
-		NodeCount:     len(b.Nodes),
-		EdgeCount:     len(b.Edges),
-		Compressed:    true,
+	stored := compressed.Bytes()
+	if opts != nil && opts.Passphrase != "" {
+		info, encrypted, err := encryptPayload(stored, opts.Passphrase, header.Version)
+		if err != nil {
+			return nil, fmt.Errorf("encrypting payload: %w", err)
+		}
+		header.Encryption = info
+		stored = encrypted
 	}
 
+	// Compute SHA-256 of the stored (compressed, maybe encrypted) data
+	hash := sha256.Sum256(stored)
+	header.Checksum = "sha256:" + hex.EncodeToString(hash[:])
+	header.SchemaVersion = store.SchemaVersion
+	header.Compressed = true
+
 	// Populate metadata
 	header.Metadata = buildHeaderMetadata(opts)
+	if opts != nil && opts.Finalize != nil {
+		if err := opts.Finalize(&header); err != nil {
+			return nil, fmt.Errorf("finalizing header: %w", err)
+		}
+	}
 
 	headerBytes, err := json.Marshal(header)
 	if err != nil {
-		return fmt.Errorf("marshaling header: %w", err)
+		return nil, fmt.Errorf("marshaling header: %w", err)
 	}
 
 	// Ensure directory exists
 	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
-		return fmt.Errorf("creating directory: %w", err)
+		return nil, fmt.Errorf("creating directory: %w", err)
 	}
 
 	// Write file: header line + newline + compressed payload
 	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
 	if err != nil {
-		return fmt.Errorf("creating file: %w", err)
+		return nil, fmt.Errorf("creating file: %w", err)
 	}
 	defer f.Close()
 
 	// Write header line
 	if _, err := f.Write(headerBytes); err != nil {
-		return fmt.Errorf("writing header: %w", err)
+		return nil, fmt.Errorf("writing header: %w", err)
 	}
 	if _, err := f.Write([]byte("\n")); err != nil {
-		return fmt.Errorf("writing header newline: %w", err)
+		return nil, fmt.Errorf("writing header newline: %w", err)
 	}
 
 	// Write compressed payload
-	if _, err := f.Write(compressed.Bytes()); err != nil {
-		return fmt.Errorf("writing compressed payload: %w", err)
+	if _, err := f.Write(stored); err != nil {
+		return nil, fmt.Errorf("writing compressed payload: %w", err)
 	}
 
-	return nil
+	return &header, nil
 }
 
 // buildHeaderMetadata constructs the metadata map for the backup header.
@@ -183,70 +219,44 @@ func buildHeaderMetadata(opts *WriteOptions) map[string]string {
 }
 
 // ReadV2 reads a V2 backup file, verifies the checksum, and decompresses the payload.
+// It returns ErrPassphraseRequired for an encrypted backup; see ReadV2Encrypted.
 func ReadV2(path string) (*BackupFormat, error) {
-	f, err := os.Open(path)
-	if err != nil {
-		return nil, fmt.Errorf("opening file: %w", err)
-	}
-	defer f.Close()
-
-	// Read header line
-	reader := bufio.NewReader(f)
-	headerLine, err := reader.ReadBytes('\n')
-	if err != nil {
-		return nil, fmt.Errorf("reading header line: %w", err)
-	}
-
-	var header BackupHeader
-	if err := json.Unmarshal(bytes.TrimSpace(headerLine), &header); err != nil {
-		return nil, fmt.Errorf("parsing header: %w", err)
-	}
-
-	if header.Version != FormatV2 {
-		return nil, fmt.Errorf("expected V2 format, got version %d", header.Version)
-	}
-
-	// Read the rest (compressed payload)
-	compressedData, err := io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("reading compressed payload: %w", err)
-	}
+	backup, _, err := ReadV2WithHeader(path)
+	return backup, err
+}
 
-	// Verify checksum
-	hash := sha256.Sum256(compressedData)
-	actualChecksum := "sha256:" + hex.EncodeToString(hash[:])
-	if actualChecksum != header.Checksum {
-		return nil, fmt.Errorf("checksum mismatch: expected %s, got %s", header.Checksum, actualChecksum)
-	}
+// ReadV2Encrypted is ReadV2 for a backup that may be encrypted with passphrase.
+func ReadV2Encrypted(path, passphrase string) (*BackupFormat, error) {
+	backup, _, err := readV2(path, passphrase)
+	return backup, err
+}
 
-	// Decompress
-	gzr, err := gzip.NewReader(bytes.NewReader(compressedData))
-	if err != nil {
-		return nil, fmt.Errorf("creating gzip reader: %w", err)
-	}
-	defer gzr.Close()
+// ReadV2WithHeader reads a V2 backup file and returns both the data and header in a
+// single file open, avoiding the TOCTOU race of separate ReadV2Header + ReadV2 calls.
+func ReadV2WithHeader(path string) (*BackupFormat, *BackupHeader, error) {
+	return readV2(path, "")
+}
 
-	// Limit decompressed size
-	limitedReader := io.LimitReader(gzr, MaxDecompressedSize+1)
-	decompressed, err := io.ReadAll(limitedReader)
+// readV2 reads a V2 backup file, decrypting it with passphrase if needed.
+func readV2(path, passphrase string) (*BackupFormat, *BackupHeader, error) {
+	header, decompressed, err := readHeadered(path, FormatV2, passphrase)
 	if err != nil {
-		return nil, fmt.Errorf("decompressing payload: %w", err)
-	}
-	if int64(len(decompressed)) > MaxDecompressedSize {
-		return nil, fmt.Errorf("decompressed payload exceeds maximum size of %d bytes", MaxDecompressedSize)
+		return nil, nil, err
 	}
 
 	var backup BackupFormat
 	if err := json.Unmarshal(decompressed, &backup); err != nil {
-		return nil, fmt.Errorf("parsing backup data: %w", err)
+		return nil, nil, fmt.Errorf("parsing backup data: %w", err)
 	}
 
-	return &backup, nil
+	return &backup, header, nil
 }
 
-// ReadV2WithHeader reads a V2 backup file and returns both the data and header in a
-// single file open, avoiding the TOCTOU race of separate ReadV2Header + ReadV2 calls.
-func ReadV2WithHeader(path string) (*BackupFormat, *BackupHeader, error) {
+// readHeadered reads a file in the layout shared by V2 and V3, checks that
+// it is the given version, verifies the checksum, decrypts the payload with
+// passphrase if it is encrypted, and returns the header and the
+// decompressed payload.
+func readHeadered(path string, version int, passphrase string) (*BackupHeader, []byte, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, nil, fmt.Errorf("opening file: %w", err)
@@ -265,8 +275,8 @@ func ReadV2WithHeader(path string) (*BackupFormat, *BackupHeader, error) {
 		return nil, nil, fmt.Errorf("parsing header: %w", err)
 	}
 
-	if header.Version != FormatV2 {
-		return nil, nil, fmt.Errorf("expected V2 format, got version %d", header.Version)
+	if header.Version != version {
+		return nil, nil, fmt.Errorf("expected V%d format, got version %d", version, header.Version)
 	}
 
 	// Read the rest (compressed payload)
@@ -282,6 +292,13 @@ func ReadV2WithHeader(path string) (*BackupFormat, *BackupHeader, error) {
 		return nil, nil, fmt.Errorf("checksum mismatch: expected %s, got %s", header.Checksum, actualChecksum)
 	}
 
+	if header.Encryption != nil {
+		compressedData, err = decryptPayload(header.Encryption, compressedData, passphrase, header.Version)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
 	// Decompress
 	gzr, err := gzip.NewReader(bytes.NewReader(compressedData))
 	if err != nil {
@@ -289,6 +306,7 @@ func ReadV2WithHeader(path string) (*BackupFormat, *BackupHeader, error) {
 	}
 	defer gzr.Close()
 
+	// Limit decompressed size
 	limitedReader := io.LimitReader(gzr, MaxDecompressedSize+1)
 	decompressed, err := io.ReadAll(limitedReader)
 	if err != nil {
@@ -298,16 +316,26 @@ func ReadV2WithHeader(path string) (*BackupFormat, *BackupHeader, error) {
 		return nil, nil, fmt.Errorf("decompressed payload exceeds maximum size of %d bytes", MaxDecompressedSize)
 	}
 
-	var backup BackupFormat
-	if err := json.Unmarshal(decompressed, &backup); err != nil {
-		return nil, nil, fmt.Errorf("parsing backup data: %w", err)
-	}
-
-	return &backup, &header, nil
+	return &header, decompressed, nil
 }
 
 // ReadV2Header reads only the header line from a V2 backup file without decompressing.
 func ReadV2Header(path string) (*BackupHeader, error) {
+	header, err := ReadHeader(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if header.Version != FormatV2 {
+		return nil, fmt.Errorf("expected V2 format, got version %d", header.Version)
+	}
+
+	return header, nil
+}
+
+// ReadHeader reads only the header line from a V2 or V3 backup file
+// without decompressing.
+func ReadHeader(path string) (*BackupHeader, error) {
 	f, err := os.Open(path)
 	if err != nil {
 		return nil, fmt.Errorf("opening file: %w", err)
@@ -325,14 +353,14 @@ func ReadV2Header(path string) (*BackupHeader, error) {
 		return nil, fmt.Errorf("parsing header: %w", err)
 	}
 
-	if header.Version != FormatV2 {
-		return nil, fmt.Errorf("expected V2 format, got version %d", header.Version)
+	if header.Version != FormatV2 && header.Version != FormatV3 {
+		return nil, fmt.Errorf("expected V2 or V3 format, got version %d", header.Version)
 	}
 
 	return &header, nil
 }
 
-// VerifyChecksum checks the integrity of a V2 backup file without full decompression.
+// VerifyChecksum checks the integrity of a V2 or V3 backup file without full decompression.
 func VerifyChecksum(path string) error {
 	f, err := os.Open(path)
 	if err != nil {
@@ -351,8 +379,8 @@ func VerifyChecksum(path string) error {
 		return fmt.Errorf("parsing header: %w", err)
 	}
 
-	if header.Version != FormatV2 {
-		return fmt.Errorf("checksum verification only supported for V2 format (got version %d)", header.Version)
+	if header.Version != FormatV2 && header.Version != FormatV3 {
+		return fmt.Errorf("checksum verification only supported for V2 and V3 formats (got version %d)", header.Version)
 	}
 
 	// Read compressed payload
@@ -0,0 +1,393 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/pathutil"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// DiffFormat is the payload of a V3 differential backup: what changed
+// between its base backup and the graph when it was written.
+type DiffFormat struct {
+	Version   int          `json:"version"`
+	CreatedAt time.Time    `json:"created_at"`
+	Nodes     []BackupNode `json:"nodes"` // added or changed
+	Edges     []store.Edge `json:"edges"` // added or changed
+
+	DeletedNodes []string  `json:"deleted_nodes,omitempty"`
+	DeletedEdges []EdgeRef `json:"deleted_edges,omitempty"`
+}
+
+// EdgeRef identifies an edge.
+type EdgeRef struct {
+	Source string         `json:"source"`
+	Target string         `json:"target"`
+	Kind   store.EdgeKind `json:"kind"`
+}
+
+func edgeRef(e store.Edge) EdgeRef {
+	return EdgeRef{Source: e.Source, Target: e.Target, Kind: e.Kind}
+}
+
+// GenerateIncrementalPath creates a timestamped filename for a V3
+// incremental backup in the given directory.
+func GenerateIncrementalPath(dir string) string {
+	ts := time.Now().Format("20060102-150405")
+	return filepath.Join(dir, fmt.Sprintf("floop-backup-%s-incr.json.gz", ts))
+}
+
+// BackupIncremental writes a V3 backup to outputPath holding only the
+// nodes and edges that changed since the backup at basePath, which must be
+// in the same directory. The base may itself be incremental; restoring the
+// result replays the whole chain. opts.Compress is ignored: V3 is always
+// compressed, and opts.Passphrase both decrypts the chain and encrypts the
+// result.
+func BackupIncremental(ctx context.Context, graphStore store.GraphStore, basePath, outputPath string, opts BackupOptions) (*DiffFormat, *BackupHeader, error) {
+	if len(opts.AllowedDirs) > 0 {
+		if err := pathutil.ValidatePath(outputPath, opts.AllowedDirs); err != nil {
+			return nil, nil, fmt.Errorf("backup path rejected: %w", err)
+		}
+	}
+	absBase, err := filepath.Abs(basePath)
+	if err != nil {
+		return nil, nil, err
+	}
+	absOutput, err := filepath.Abs(outputPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if filepath.Dir(absBase) != filepath.Dir(absOutput) {
+		return nil, nil, fmt.Errorf("incremental backup %s must be in the same directory as its base %s", outputPath, basePath)
+	}
+	if absBase == absOutput {
+		return nil, nil, fmt.Errorf("incremental backup %s would overwrite its base", outputPath)
+	}
+
+	base, err := readBackupAuto(basePath, opts.Passphrase)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read base backup: %w", err)
+	}
+	baseRevision := 0
+	if header, err := ReadHeader(basePath); err == nil {
+		baseRevision = header.Revision
+	}
+	baseChecksum, err := fileChecksum(basePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read base backup: %w", err)
+	}
+
+	current, err := collectGraph(ctx, graphStore)
+	if err != nil {
+		return nil, nil, err
+	}
+	diff, err := diffGraphs(base, current)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header, err := writeHeadered(outputPath, BackupHeader{
+		Version:      FormatV3,
+		CreatedAt:    diff.CreatedAt,
+		NodeCount:    len(diff.Nodes),
+		EdgeCount:    len(diff.Edges),
+		Base:         filepath.Base(basePath),
+		BaseChecksum: baseChecksum,
+		Revision:     baseRevision + 1,
+	}, diff, &WriteOptions{
+		FloopVersion: opts.FloopVersion,
+		Metadata:     opts.Metadata,
+		Passphrase:   opts.Passphrase,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to write V3 backup: %w", err)
+	}
+	return diff, header, nil
+}
+
+// diffGraphs returns the changes that turn base into current. Nodes and
+// edges are compared by their JSON encoding, which is what a backup keeps.
+func diffGraphs(base, current *BackupFormat) (*DiffFormat, error) {
+	diff := &DiffFormat{
+		Version:   FormatV3,
+		CreatedAt: current.CreatedAt,
+		Nodes:     []BackupNode{},
+		Edges:     []store.Edge{},
+	}
+
+	baseNodes := make(map[string][]byte, len(base.Nodes))
+	for _, n := range base.Nodes {
+		data, err := json.Marshal(n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode node %s: %w", n.ID, err)
+		}
+		baseNodes[n.ID] = data
+	}
+	seenNodes := make(map[string]bool, len(current.Nodes))
+	for _, n := range current.Nodes {
+		seenNodes[n.ID] = true
+		data, err := json.Marshal(n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode node %s: %w", n.ID, err)
+		}
+		if old, ok := baseNodes[n.ID]; !ok || !bytes.Equal(old, data) {
+			diff.Nodes = append(diff.Nodes, n)
+		}
+	}
+	for _, n := range base.Nodes {
+		if !seenNodes[n.ID] {
+			diff.DeletedNodes = append(diff.DeletedNodes, n.ID)
+		}
+	}
+
+	baseEdges := make(map[EdgeRef][]byte, len(base.Edges))
+	for _, e := range base.Edges {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode edge %s->%s: %w", e.Source, e.Target, err)
+		}
+		baseEdges[edgeRef(e)] = data
+	}
+	seenEdges := make(map[EdgeRef]bool, len(current.Edges))
+	for _, e := range current.Edges {
+		seenEdges[edgeRef(e)] = true
+		data, err := json.Marshal(e)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode edge %s->%s: %w", e.Source, e.Target, err)
+		}
+		if old, ok := baseEdges[edgeRef(e)]; !ok || !bytes.Equal(old, data) {
+			diff.Edges = append(diff.Edges, e)
+		}
+	}
+	for _, e := range base.Edges {
+		if !seenEdges[edgeRef(e)] {
+			diff.DeletedEdges = append(diff.DeletedEdges, edgeRef(e))
+		}
+	}
+
+	return diff, nil
+}
+
+// applyDiff returns base with diff applied.
+func applyDiff(base *BackupFormat, diff *DiffFormat) *BackupFormat {
+	changedNodes := make(map[string]BackupNode, len(diff.Nodes))
+	for _, n := range diff.Nodes {
+		changedNodes[n.ID] = n
+	}
+	deletedNodes := make(map[string]bool, len(diff.DeletedNodes))
+	for _, id := range diff.DeletedNodes {
+		deletedNodes[id] = true
+	}
+	changedEdges := make(map[EdgeRef]store.Edge, len(diff.Edges))
+	for _, e := range diff.Edges {
+		changedEdges[edgeRef(e)] = e
+	}
+	deletedEdges := make(map[EdgeRef]bool, len(diff.DeletedEdges))
+	for _, ref := range diff.DeletedEdges {
+		deletedEdges[ref] = true
+	}
+
+	result := &BackupFormat{
+		Version:   FormatV3,
+		CreatedAt: diff.CreatedAt,
+		Nodes:     make([]BackupNode, 0, len(base.Nodes)+len(diff.Nodes)),
+		Edges:     make([]store.Edge, 0, len(base.Edges)+len(diff.Edges)),
+	}
+	for _, n := range base.Nodes {
+		if deletedNodes[n.ID] {
+			continue
+		}
+		if changed, ok := changedNodes[n.ID]; ok {
+			n = changed
+			delete(changedNodes, n.ID)
+		}
+		result.Nodes = append(result.Nodes, n)
+	}
+	for _, n := range diff.Nodes {
+		if _, added := changedNodes[n.ID]; added {
+			result.Nodes = append(result.Nodes, n)
+		}
+	}
+	for _, e := range base.Edges {
+		ref := edgeRef(e)
+		if deletedEdges[ref] {
+			continue
+		}
+		if changed, ok := changedEdges[ref]; ok {
+			e = changed
+			delete(changedEdges, ref)
+		}
+		result.Edges = append(result.Edges, e)
+	}
+	for _, e := range diff.Edges {
+		if _, added := changedEdges[edgeRef(e)]; added {
+			result.Edges = append(result.Edges, e)
+		}
+	}
+	return result
+}
+
+// ReadV3 reads a V3 backup file, verifies the checksum, decrypts the
+// payload with passphrase if it is encrypted, and decompresses it. It does
+// not read the base; see ReadChain.
+func ReadV3(path, passphrase string) (*DiffFormat, *BackupHeader, error) {
+	header, decompressed, err := readHeadered(path, FormatV3, passphrase)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var diff DiffFormat
+	if err := json.Unmarshal(decompressed, &diff); err != nil {
+		return nil, nil, fmt.Errorf("parsing backup data: %w", err)
+	}
+
+	return &diff, header, nil
+}
+
+// ReadChain reads the V3 backup at path and the chain of bases behind it,
+// down to a full V1 or V2 backup, and returns the graph it represents.
+// Encrypted links are decrypted with passphrase. It fails if a base is
+// missing or has changed since its increment was written.
+func ReadChain(path, passphrase string) (*BackupFormat, error) {
+	var diffs []*DiffFormat
+	seen := make(map[string]bool)
+	for {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if seen[abs] {
+			return nil, fmt.Errorf("backup chain loops at %s", path)
+		}
+		seen[abs] = true
+
+		diff, header, err := ReadV3(path, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", filepath.Base(path), err)
+		}
+		if header.Base == "" || filepath.Base(header.Base) != header.Base {
+			return nil, fmt.Errorf("%s has an invalid base %q", filepath.Base(path), header.Base)
+		}
+		diffs = append(diffs, diff)
+
+		basePath := filepath.Join(filepath.Dir(path), header.Base)
+		checksum, err := fileChecksum(basePath)
+		if err != nil {
+			return nil, fmt.Errorf("base backup of %s: %w", filepath.Base(path), err)
+		}
+		if checksum != header.BaseChecksum {
+			return nil, fmt.Errorf("base backup %s has changed since %s was written", header.Base, filepath.Base(path))
+		}
+
+		version, err := DetectFormat(basePath)
+		if err != nil {
+			return nil, fmt.Errorf("base backup %s: %w", header.Base, err)
+		}
+		if version == FormatV3 {
+			baseHeader, err := ReadHeader(basePath)
+			if err != nil {
+				return nil, fmt.Errorf("base backup %s: %w", header.Base, err)
+			}
+			if baseHeader.Revision != header.Revision-1 {
+				return nil, fmt.Errorf("%s is revision %d but its base %s is revision %d",
+					filepath.Base(path), header.Revision, header.Base, baseHeader.Revision)
+			}
+			path = basePath
+			continue
+		}
+		if header.Revision != 1 {
+			return nil, fmt.Errorf("%s is revision %d but its base %s is a full backup",
+				filepath.Base(path), header.Revision, header.Base)
+		}
+
+		full, err := readBackupAuto(basePath, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("base backup %s: %w", header.Base, err)
+		}
+		for i := len(diffs) - 1; i >= 0; i-- {
+			full = applyDiff(full, diffs[i])
+		}
+		return full, nil
+	}
+}
+
+// VerifyChain checks the checksums of the V3 backup at path and of every
+// base behind it, and that each base is the file its increment was taken
+// against. It needs no passphrase: checksums cover the stored bytes.
+func VerifyChain(path string) error {
+	if err := VerifyChecksum(path); err != nil {
+		return err
+	}
+	bases := chainBases(path)
+	for i, base := range bases {
+		link := path
+		if i > 0 {
+			link = bases[i-1]
+		}
+		header, err := ReadHeader(link)
+		if err != nil {
+			return err
+		}
+		checksum, err := fileChecksum(base)
+		if err != nil {
+			return fmt.Errorf("base backup of %s: %w", filepath.Base(link), err)
+		}
+		if checksum != header.BaseChecksum {
+			return fmt.Errorf("base backup %s has changed since %s was written", header.Base, filepath.Base(link))
+		}
+		version, err := DetectFormat(base)
+		if err != nil {
+			return fmt.Errorf("base backup %s: %w", header.Base, err)
+		}
+		if version != FormatV1 {
+			if err := VerifyChecksum(base); err != nil {
+				return fmt.Errorf("base backup %s: %w", header.Base, err)
+			}
+		}
+	}
+	return nil
+}
+
+// chainBases returns the paths of the backups the V3 backup at path
+// depends on, nearest first. It stops quietly at the first unreadable
+// link; ReadChain reports those.
+func chainBases(path string) []string {
+	var bases []string
+	seen := map[string]bool{path: true}
+	for {
+		header, err := ReadHeader(path)
+		if err != nil || header.Version != FormatV3 || header.Base == "" {
+			return bases
+		}
+		path = filepath.Join(filepath.Dir(path), filepath.Base(header.Base))
+		if seen[path] {
+			return bases
+		}
+		seen[path] = true
+		bases = append(bases, path)
+	}
+}
+
+// fileChecksum returns the SHA-256 of the whole file at path.
+func fileChecksum(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
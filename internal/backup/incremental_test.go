@@ -0,0 +1,221 @@
+package backup
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestBackupIncremental_RestoreChain(t *testing.T) {
+	srcStore := createTestStore(t)
+	defer srcStore.Close()
+	addTestData(t, srcStore)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, "floop-backup-20260101-000000.json.gz")
+	if _, err := Backup(ctx, srcStore, fullPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	// Change node-a, delete node-c (and its edge), add node-d.
+	nodeA, err := srcStore.GetNode(ctx, "node-a")
+	if err != nil || nodeA == nil {
+		t.Fatalf("GetNode(node-a) = %v, %v", nodeA, err)
+	}
+	nodeA.Metadata["confidence"] = 0.95
+	if err := srcStore.UpdateNode(ctx, *nodeA); err != nil {
+		t.Fatalf("UpdateNode() error = %v", err)
+	}
+	if err := srcStore.DeleteNode(ctx, "node-c"); err != nil {
+		t.Fatalf("DeleteNode() error = %v", err)
+	}
+	if _, err := srcStore.AddNode(ctx, store.Node{ID: "node-d", Kind: "behavior", Content: map[string]interface{}{"name": "node-d", "content": map[string]interface{}{"canonical": "Content for node-d"}}}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+
+	incr1 := filepath.Join(dir, "floop-backup-20260101-000100-incr.json.gz")
+	diff, header, err := BackupIncremental(ctx, srcStore, fullPath, incr1, BackupOptions{})
+	if err != nil {
+		t.Fatalf("BackupIncremental() error = %v", err)
+	}
+	if header.Revision != 1 || header.Base != filepath.Base(fullPath) {
+		t.Errorf("header revision/base = %d/%q, want 1/%q", header.Revision, header.Base, filepath.Base(fullPath))
+	}
+	changed := map[string]bool{}
+	for _, n := range diff.Nodes {
+		changed[n.ID] = true
+	}
+	if len(changed) != 2 || !changed["node-a"] || !changed["node-d"] {
+		t.Errorf("changed nodes = %v, want node-a and node-d", changed)
+	}
+	if len(diff.DeletedNodes) != 1 || diff.DeletedNodes[0] != "node-c" {
+		t.Errorf("DeletedNodes = %v, want [node-c]", diff.DeletedNodes)
+	}
+	if len(diff.Edges) != 0 || len(diff.DeletedEdges) != 1 || diff.DeletedEdges[0].Target != "node-c" {
+		t.Errorf("edges changed/deleted = %v/%v, want none/node-b->node-c", diff.Edges, diff.DeletedEdges)
+	}
+
+	// A second increment chains on the first.
+	if _, err := srcStore.AddNode(ctx, store.Node{ID: "node-e", Kind: "behavior", Content: map[string]interface{}{"name": "node-e", "content": map[string]interface{}{"canonical": "Content for node-e"}}}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	incr2 := filepath.Join(dir, "floop-backup-20260101-000200-incr.json.gz")
+	diff, header, err = BackupIncremental(ctx, srcStore, incr1, incr2, BackupOptions{})
+	if err != nil {
+		t.Fatalf("BackupIncremental() error = %v", err)
+	}
+	if header.Revision != 2 || len(diff.Nodes) != 1 || diff.Nodes[0].ID != "node-e" {
+		t.Errorf("second increment revision/nodes = %d/%v, want 2/[node-e]", header.Revision, diff.Nodes)
+	}
+
+	dstStore := createTestStore(t)
+	defer dstStore.Close()
+	result, err := Restore(ctx, dstStore, incr2, RestoreMerge)
+	if err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if result.NodesRestored != 4 || result.EdgesRestored != 1 {
+		t.Errorf("restored %d nodes, %d edges, want 4, 1", result.NodesRestored, result.EdgesRestored)
+	}
+	for _, id := range []string{"node-a", "node-b", "node-d", "node-e"} {
+		if n, _ := dstStore.GetNode(ctx, id); n == nil {
+			t.Errorf("node %s not restored", id)
+		}
+	}
+	if n, _ := dstStore.GetNode(ctx, "node-c"); n != nil {
+		t.Error("deleted node-c was restored")
+	}
+	restoredA, _ := dstStore.GetNode(ctx, "node-a")
+	if restoredA == nil || restoredA.Metadata["confidence"] != 0.95 {
+		t.Errorf("node-a = %v, want confidence 0.95", restoredA)
+	}
+}
+
+func TestBackupIncremental_NoChanges(t *testing.T) {
+	s := createTestStore(t)
+	defer s.Close()
+	addTestData(t, s)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, "floop-backup-20260101-000000.json.gz")
+	if _, err := Backup(ctx, s, fullPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	diff, _, err := BackupIncremental(ctx, s, fullPath, filepath.Join(dir, "floop-backup-20260101-000100-incr.json.gz"), BackupOptions{})
+	if err != nil {
+		t.Fatalf("BackupIncremental() error = %v", err)
+	}
+	if len(diff.Nodes)+len(diff.Edges)+len(diff.DeletedNodes)+len(diff.DeletedEdges) != 0 {
+		t.Errorf("diff of unchanged store = %+v, want empty", diff)
+	}
+}
+
+func TestBackupIncremental_RejectsOtherDirectory(t *testing.T) {
+	s := createTestStore(t)
+	defer s.Close()
+
+	ctx := context.Background()
+	fullPath := filepath.Join(t.TempDir(), "floop-backup-20260101-000000.json.gz")
+	if _, err := Backup(ctx, s, fullPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	_, _, err := BackupIncremental(ctx, s, fullPath, filepath.Join(t.TempDir(), "incr.json.gz"), BackupOptions{})
+	if err == nil || !strings.Contains(err.Error(), "same directory") {
+		t.Errorf("BackupIncremental() error = %v, want same-directory error", err)
+	}
+}
+
+func TestReadChain_BaseChanged(t *testing.T) {
+	s := createTestStore(t)
+	defer s.Close()
+	addTestData(t, s)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	fullPath := filepath.Join(dir, "floop-backup-20260101-000000.json.gz")
+	if _, err := Backup(ctx, s, fullPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	incr := filepath.Join(dir, "floop-backup-20260101-000100-incr.json.gz")
+	if _, _, err := BackupIncremental(ctx, s, fullPath, incr, BackupOptions{}); err != nil {
+		t.Fatalf("BackupIncremental() error = %v", err)
+	}
+
+	if err := VerifyChecksum(incr); err != nil {
+		t.Errorf("VerifyChecksum(incr) error = %v", err)
+	}
+	if version, err := DetectFormat(incr); err != nil || version != FormatV3 {
+		t.Errorf("DetectFormat(incr) = %d, %v, want %d", version, err, FormatV3)
+	}
+
+	// Replacing the base with a different backup breaks the chain.
+	other := srcStoreWithNode(t, "other")
+	defer other.Close()
+	if _, err := Backup(ctx, other, fullPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+	if _, err := ReadChain(incr, ""); err == nil || !strings.Contains(err.Error(), "has changed") {
+		t.Errorf("ReadChain() with replaced base error = %v, want changed-base error", err)
+	}
+	if err := VerifyChain(incr); err == nil || !strings.Contains(err.Error(), "has changed") {
+		t.Errorf("VerifyChain() with replaced base error = %v, want changed-base error", err)
+	}
+
+	if err := os.Remove(fullPath); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadChain(incr, ""); err == nil {
+		t.Error("ReadChain() with missing base succeeded")
+	}
+}
+
+func TestRetention_KeepsIncrementalBases(t *testing.T) {
+	s := createTestStore(t)
+	defer s.Close()
+	addTestData(t, s)
+
+	ctx := context.Background()
+	dir := t.TempDir()
+	older := filepath.Join(dir, "floop-backup-20260101-000000.json.gz")
+	base := filepath.Join(dir, "floop-backup-20260102-000000.json.gz")
+	incr := filepath.Join(dir, "floop-backup-20260103-000000-incr.json.gz")
+	for _, p := range []string{older, base} {
+		if _, err := Backup(ctx, s, p); err != nil {
+			t.Fatalf("Backup() error = %v", err)
+		}
+	}
+	if _, _, err := BackupIncremental(ctx, s, base, incr, BackupOptions{}); err != nil {
+		t.Fatalf("BackupIncremental() error = %v", err)
+	}
+
+	deleted, err := ApplyRetention(dir, &CountPolicy{MaxCount: 1})
+	if err != nil {
+		t.Fatalf("ApplyRetention() error = %v", err)
+	}
+	if len(deleted) != 1 || deleted[0] != older {
+		t.Errorf("ApplyRetention() deleted %v, want only %s", deleted, older)
+	}
+
+	if err := RotateBackups(dir, 1); err != nil {
+		t.Fatalf("RotateBackups() error = %v", err)
+	}
+	if _, err := os.Stat(base); err != nil {
+		t.Errorf("RotateBackups() removed the base of a kept increment: %v", err)
+	}
+}
+
+func srcStoreWithNode(t *testing.T, id string) *store.SQLiteGraphStore {
+	t.Helper()
+	s := createTestStore(t)
+	if _, err := s.AddNode(context.Background(), store.Node{ID: id, Kind: "behavior", Content: map[string]interface{}{"name": id}}); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	return s
+}
@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// RestoreFilter selects which nodes of a backup to restore. A node must
+// match every set field; within a field, any value matches. The zero
+// filter selects everything.
+type RestoreFilter struct {
+	IDs          []string  // node IDs
+	Kinds        []string  // behavior kinds, e.g. "directive"
+	Tags         []string  // behavior tags
+	Packs        []string  // IDs of the packs that installed the behavior
+	CreatedAfter time.Time // behaviors created after this time
+}
+
+// IsZero reports whether f selects everything.
+func (f RestoreFilter) IsZero() bool {
+	return len(f.IDs) == 0 && len(f.Kinds) == 0 && len(f.Tags) == 0 &&
+		len(f.Packs) == 0 && f.CreatedAfter.IsZero()
+}
+
+// Match reports whether f selects node.
+func (f RestoreFilter) Match(node store.Node) bool {
+	if len(f.IDs) > 0 && !contains(f.IDs, node.ID) {
+		return false
+	}
+	if len(f.Kinds) == 0 && len(f.Tags) == 0 && len(f.Packs) == 0 && f.CreatedAfter.IsZero() {
+		return true
+	}
+
+	b := models.NodeToBehavior(node)
+	if len(f.Kinds) > 0 && !contains(f.Kinds, string(b.Kind)) {
+		return false
+	}
+	if len(f.Tags) > 0 {
+		tagged := false
+		for _, tag := range b.Content.Tags {
+			if contains(f.Tags, tag) {
+				tagged = true
+				break
+			}
+		}
+		if !tagged {
+			return false
+		}
+	}
+	if len(f.Packs) > 0 && !contains(f.Packs, models.ExtractPackageName(node.Metadata)) {
+		return false
+	}
+	if !f.CreatedAfter.IsZero() {
+		created := b.Provenance.CreatedAt
+		if created.IsZero() {
+			created = b.Stats.CreatedAt
+		}
+		if !created.After(f.CreatedAfter) {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if s == v {
+			return true
+		}
+	}
+	return false
+}
+
+// Restore actions reported by a dry run.
+const (
+	ActionAdd    = "add"
+	ActionUpdate = "update"
+	ActionSkip   = "skip"
+)
+
+// RestoreChange is what a restore would do with one node or edge.
+type RestoreChange struct {
+	Type   string `json:"type"` // "node" or "edge"
+	ID     string `json:"id"`   // node ID, or "source -kind-> target"
+	Action string `json:"action"`
+}
+
+// filterBackup returns the part of backup that filter selects, with counts
+// of the nodes and edges left out. An edge is kept when both its ends are
+// selected or already in the store, so a filtered restore never leaves an
+// edge dangling.
+func filterBackup(ctx context.Context, graphStore store.GraphStore, backup *BackupFormat, filter RestoreFilter) (*BackupFormat, int, int, error) {
+	if filter.IsZero() {
+		return backup, 0, 0, nil
+	}
+
+	selected := &BackupFormat{Version: backup.Version, CreatedAt: backup.CreatedAt}
+	ids := make(map[string]bool)
+	for _, n := range backup.Nodes {
+		if filter.Match(n.Node) {
+			selected.Nodes = append(selected.Nodes, n)
+			ids[n.ID] = true
+		}
+	}
+
+	present := func(id string) (bool, error) {
+		if ids[id] {
+			return true, nil
+		}
+		existing, err := graphStore.GetNode(ctx, id)
+		if err != nil {
+			return false, fmt.Errorf("failed to check existing node %s: %w", id, err)
+		}
+		return existing != nil, nil
+	}
+	for _, e := range backup.Edges {
+		source, err := present(e.Source)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		target, err := present(e.Target)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		if source && target {
+			selected.Edges = append(selected.Edges, e)
+		}
+	}
+
+	return selected, len(backup.Nodes) - len(selected.Nodes), len(backup.Edges) - len(selected.Edges), nil
+}
+
+// planRestore reports what restoring backup in mode would do, without
+// writing. Existing nodes are skipped in merge mode and overwritten in
+// replace mode; existing edges are overwritten in both.
+func planRestore(ctx context.Context, graphStore store.GraphStore, backup *BackupFormat, mode RestoreMode) (*RestoreResult, error) {
+	result := &RestoreResult{DryRun: true, Changes: []RestoreChange{}}
+	for _, bn := range backup.Nodes {
+		existing, err := graphStore.GetNode(ctx, bn.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing node %s: %w", bn.ID, err)
+		}
+		action := ActionAdd
+		switch {
+		case existing != nil && mode == RestoreMerge:
+			action = ActionSkip
+			result.NodesSkipped++
+		case existing != nil:
+			action = ActionUpdate
+			result.NodesRestored++
+		default:
+			result.NodesRestored++
+		}
+		result.Changes = append(result.Changes, RestoreChange{Type: "node", ID: bn.ID, Action: action})
+	}
+
+	for _, e := range backup.Edges {
+		existing, err := graphStore.GetEdges(ctx, e.Source, store.DirectionOutbound, e.Kind)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check existing edges of %s: %w", e.Source, err)
+		}
+		action := ActionAdd
+		for _, x := range existing {
+			if x.Target == e.Target {
+				action = ActionUpdate
+				break
+			}
+		}
+		result.EdgesRestored++
+		result.Changes = append(result.Changes, RestoreChange{
+			Type:   "edge",
+			ID:     fmt.Sprintf("%s -%s-> %s", e.Source, e.Kind, e.Target),
+			Action: action,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,181 @@
+package backup
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// addFilterTestData adds behaviors with varied kinds, tags, packs and
+// creation times, linked a -> b -> c.
+func addFilterTestData(t *testing.T, s store.GraphStore) {
+	t.Helper()
+	ctx := context.Background()
+	behaviors := []struct {
+		id, kind, pack string
+		tags           []interface{}
+		created        time.Time
+	}{
+		{"a", "directive", "", []interface{}{"go", "errors"}, time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC)},
+		{"b", "constraint", "org/go-pack", []interface{}{"go"}, time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{"c", "directive", "org/go-pack", []interface{}{"python"}, time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, b := range behaviors {
+		provenance := map[string]interface{}{"created_at": b.created.Format(time.RFC3339)}
+		if b.pack != "" {
+			provenance["package"] = b.pack
+		}
+		if _, err := s.AddNode(ctx, store.Node{
+			ID:   b.id,
+			Kind: "behavior",
+			Content: map[string]interface{}{
+				"name": b.id,
+				"kind": b.kind,
+				"content": map[string]interface{}{
+					"canonical": "Content for " + b.id,
+					"tags":      b.tags,
+				},
+			},
+			Metadata: map[string]interface{}{"provenance": provenance},
+		}); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", b.id, err)
+		}
+	}
+	now := time.Now()
+	for _, e := range [][2]string{{"a", "b"}, {"b", "c"}} {
+		if err := s.AddEdge(ctx, store.Edge{Source: e[0], Target: e[1], Kind: store.EdgeKindRequires, Weight: 0.5, CreatedAt: now}); err != nil {
+			t.Fatalf("AddEdge() error = %v", err)
+		}
+	}
+}
+
+func TestRestoreWithOptions_Filters(t *testing.T) {
+	srcStore := createTestStore(t)
+	defer srcStore.Close()
+	addFilterTestData(t, srcStore)
+
+	ctx := context.Background()
+	backupPath := filepath.Join(t.TempDir(), "backup.json.gz")
+	if _, err := Backup(ctx, srcStore, backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		filter    RestoreFilter
+		wantNodes []string
+		wantEdges int
+	}{
+		{"ids", RestoreFilter{IDs: []string{"a", "c"}}, []string{"a", "c"}, 0},
+		{"kind", RestoreFilter{Kinds: []string{"directive"}}, []string{"a", "c"}, 0},
+		{"tag", RestoreFilter{Tags: []string{"go"}}, []string{"a", "b"}, 1},
+		{"pack", RestoreFilter{Packs: []string{"org/go-pack"}}, []string{"b", "c"}, 1},
+		{"created after", RestoreFilter{CreatedAfter: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)}, []string{"b", "c"}, 1},
+		{"combined", RestoreFilter{Kinds: []string{"directive"}, Packs: []string{"org/go-pack"}}, []string{"c"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := createTestStore(t)
+			defer dst.Close()
+
+			result, err := RestoreWithOptions(ctx, dst, backupPath, RestoreOptions{Mode: RestoreMerge, Filter: tt.filter})
+			if err != nil {
+				t.Fatalf("RestoreWithOptions() error = %v", err)
+			}
+			if result.NodesRestored != len(tt.wantNodes) || result.NodesFiltered != 3-len(tt.wantNodes) {
+				t.Errorf("restored/filtered nodes = %d/%d, want %d/%d", result.NodesRestored, result.NodesFiltered, len(tt.wantNodes), 3-len(tt.wantNodes))
+			}
+			if result.EdgesRestored != tt.wantEdges {
+				t.Errorf("EdgesRestored = %d, want %d", result.EdgesRestored, tt.wantEdges)
+			}
+			for _, id := range tt.wantNodes {
+				if n, _ := dst.GetNode(ctx, id); n == nil {
+					t.Errorf("node %s not restored", id)
+				}
+			}
+		})
+	}
+}
+
+func TestRestoreWithOptions_FilterKeepsEdgesToExistingNodes(t *testing.T) {
+	srcStore := createTestStore(t)
+	defer srcStore.Close()
+	addFilterTestData(t, srcStore)
+
+	ctx := context.Background()
+	backupPath := filepath.Join(t.TempDir(), "backup.json.gz")
+	if _, err := Backup(ctx, srcStore, backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	dst := createTestStore(t)
+	defer dst.Close()
+	if _, err := RestoreWithOptions(ctx, dst, backupPath, RestoreOptions{Mode: RestoreMerge, Filter: RestoreFilter{IDs: []string{"a"}}}); err != nil {
+		t.Fatalf("RestoreWithOptions() error = %v", err)
+	}
+	result, err := RestoreWithOptions(ctx, dst, backupPath, RestoreOptions{Mode: RestoreMerge, Filter: RestoreFilter{IDs: []string{"b"}}})
+	if err != nil {
+		t.Fatalf("RestoreWithOptions() error = %v", err)
+	}
+	if result.EdgesRestored != 1 || result.EdgesFiltered != 1 {
+		t.Errorf("restored/filtered edges = %d/%d, want 1/1 (a->b kept, b->c dropped)", result.EdgesRestored, result.EdgesFiltered)
+	}
+}
+
+func TestRestoreWithOptions_DryRun(t *testing.T) {
+	srcStore := createTestStore(t)
+	defer srcStore.Close()
+	addFilterTestData(t, srcStore)
+
+	ctx := context.Background()
+	backupPath := filepath.Join(t.TempDir(), "backup.json.gz")
+	if _, err := Backup(ctx, srcStore, backupPath); err != nil {
+		t.Fatalf("Backup() error = %v", err)
+	}
+
+	dst := createTestStore(t)
+	defer dst.Close()
+	if _, err := RestoreWithOptions(ctx, dst, backupPath, RestoreOptions{Mode: RestoreMerge, Filter: RestoreFilter{IDs: []string{"a", "b"}}}); err != nil {
+		t.Fatalf("RestoreWithOptions() error = %v", err)
+	}
+
+	actions := func(mode RestoreMode) map[string]string {
+		t.Helper()
+		result, err := RestoreWithOptions(ctx, dst, backupPath, RestoreOptions{Mode: mode, DryRun: true})
+		if err != nil {
+			t.Fatalf("RestoreWithOptions(dry run) error = %v", err)
+		}
+		if !result.DryRun {
+			t.Error("DryRun = false, want true")
+		}
+		got := make(map[string]string)
+		for _, c := range result.Changes {
+			got[c.Type+" "+c.ID] = c.Action
+		}
+		return got
+	}
+
+	merge := actions(RestoreMerge)
+	want := map[string]string{
+		"node a":               ActionSkip,
+		"node b":               ActionSkip,
+		"node c":               ActionAdd,
+		"edge a -requires-> b": ActionUpdate,
+		"edge b -requires-> c": ActionAdd,
+	}
+	for k, v := range want {
+		if merge[k] != v {
+			t.Errorf("merge dry run %s = %q, want %q", k, merge[k], v)
+		}
+	}
+	if replace := actions(RestoreReplace); replace["node a"] != ActionUpdate {
+		t.Errorf("replace dry run node a = %q, want %q", replace["node a"], ActionUpdate)
+	}
+
+	if n, _ := dst.GetNode(ctx, "c"); n != nil {
+		t.Error("dry run wrote node c")
+	}
+}
@@ -139,7 +139,8 @@ func ListBackups(dir string) ([]BackupInfo, error) {
 	return backups, nil
 }
 
-// ApplyRetention deletes backups not kept by the policy.
+// ApplyRetention deletes backups not kept by the policy. Bases of kept
+// incremental backups are never deleted.
 func ApplyRetention(dir string, policy RetentionPolicy) (deleted []string, err error) {
 	backups, err := ListBackups(dir)
 	if err != nil {
@@ -149,7 +150,7 @@ func ApplyRetention(dir string, policy RetentionPolicy) (deleted []string, err e
 	keep := policy.Apply(backups)
 	keepSet := make(map[string]bool, len(keep))
 	for _, b := range keep {
-		keepSet[b.Path] = true
+		keepWithBases(keepSet, b.Path)
 	}
 
 	for _, b := range backups {
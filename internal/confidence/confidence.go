@@ -0,0 +1,131 @@
+// Package confidence records where a behavior's confidence came from, so the
+// single stored value can be explained as a base plus per-source
+// adjustments.
+package confidence
+
+import (
+	"math"
+
+	"github.com/nvandessel/floop/internal/utils"
+)
+
+// MetadataKey is the node metadata key holding a behavior's Sources.
+const MetadataKey = "confidence_sources"
+
+// Source identifies what changed a behavior's confidence after its base
+// value was set.
+type Source string
+
+const (
+	// Approval covers human review outcomes, such as an escalation
+	// downgrade.
+	Approval Source = "approval"
+
+	// Reinforcement covers automatic boosts and decay from usage.
+	Reinforcement Source = "reinforcement"
+
+	// Recalibration covers re-estimates when behaviors are merged or
+	// consolidated into an existing one.
+	Recalibration Source = "recalibration"
+)
+
+// Origins of a behavior's base confidence.
+const (
+	OriginExtraction    = "extraction"
+	OriginPack          = "pack"
+	OriginMerge         = "merge"
+	OriginConsolidation = "consolidation"
+	OriginUntracked     = "untracked"
+	OriginManual        = "manual"
+	OriginRules         = "rules"
+)
+
+// Sources breaks a behavior's confidence down by contribution. Base plus
+// the three adjustments equals the stored confidence whenever every change
+// went through Adjust.
+type Sources struct {
+	Base          float64 `json:"base" yaml:"base"`
+	Origin        string  `json:"origin,omitempty" yaml:"origin,omitempty"`
+	Approval      float64 `json:"approval" yaml:"approval"`
+	Reinforcement float64 `json:"reinforcement" yaml:"reinforcement"`
+	Recalibration float64 `json:"recalibration" yaml:"recalibration"`
+}
+
+// Total returns the confidence the contributions add up to.
+func (s Sources) Total() float64 {
+	return s.Base + s.Approval + s.Reinforcement + s.Recalibration
+}
+
+// Unattributed returns the part of confidence not explained by s, from
+// changes made outside Adjust. It is zero when the two agree to within
+// rounding.
+func (s Sources) Unattributed(confidence float64) float64 {
+	d := confidence - s.Total()
+	if math.Abs(d) < 1e-9 {
+		return 0
+	}
+	return d
+}
+
+// Map returns s in the form stored in node metadata.
+func (s Sources) Map() map[string]interface{} {
+	m := map[string]interface{}{
+		"base":          s.Base,
+		"approval":      s.Approval,
+		"reinforcement": s.Reinforcement,
+		"recalibration": s.Recalibration,
+	}
+	if s.Origin != "" {
+		m["origin"] = s.Origin
+	}
+	return m
+}
+
+// FromMetadata reads Sources from node metadata. ok is false for behaviors
+// created before contributions were tracked.
+func FromMetadata(metadata map[string]interface{}) (Sources, bool) {
+	switch v := metadata[MetadataKey].(type) {
+	case Sources:
+		return v, true
+	case map[string]interface{}:
+		return Sources{
+			Base:          utils.GetFloat64(v, "base", 0),
+			Origin:        utils.GetString(v, "origin", ""),
+			Approval:      utils.GetFloat64(v, "approval", 0),
+			Reinforcement: utils.GetFloat64(v, "reinforcement", 0),
+			Recalibration: utils.GetFloat64(v, "recalibration", 0),
+		}, true
+	}
+	return Sources{}, false
+}
+
+// SetBase sets metadata's confidence to value and records it as the base,
+// discarding earlier contributions.
+func SetBase(metadata map[string]interface{}, value float64, origin string) {
+	metadata["confidence"] = value
+	metadata[MetadataKey] = Sources{Base: value, Origin: origin}.Map()
+}
+
+// Adjust sets metadata's confidence to value and attributes the change to
+// src. A behavior without recorded sources starts tracking with its current
+// confidence as an untracked base.
+func Adjust(metadata map[string]interface{}, src Source, value float64) {
+	current := utils.GetFloat64(metadata, "confidence", 0)
+	s, ok := FromMetadata(metadata)
+	if !ok {
+		s = Sources{Base: current, Origin: OriginUntracked}
+	}
+
+	delta := value - current
+	switch src {
+	case Approval:
+		s.Approval += delta
+	case Reinforcement:
+		s.Reinforcement += delta
+	case Recalibration:
+		s.Recalibration += delta
+	}
+
+	metadata["confidence"] = value
+	metadata[MetadataKey] = s.Map()
+}
@@ -0,0 +1,91 @@
+package confidence
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+)
+
+func approxEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestSetBaseAndAdjust(t *testing.T) {
+	meta := map[string]interface{}{}
+	SetBase(meta, 0.6, OriginExtraction)
+	Adjust(meta, Reinforcement, 0.64)
+	Adjust(meta, Reinforcement, 0.66)
+	Adjust(meta, Approval, 0.33)
+	Adjust(meta, Recalibration, 0.5)
+
+	if got := meta["confidence"]; got != 0.5 {
+		t.Errorf("confidence = %v, want 0.5", got)
+	}
+	s, ok := FromMetadata(meta)
+	if !ok {
+		t.Fatal("FromMetadata() ok = false, want true")
+	}
+	if s.Base != 0.6 || s.Origin != OriginExtraction {
+		t.Errorf("base = %v (%s), want 0.6 (extraction)", s.Base, s.Origin)
+	}
+	if !approxEqual(s.Reinforcement, 0.06) {
+		t.Errorf("Reinforcement = %v, want 0.06", s.Reinforcement)
+	}
+	if !approxEqual(s.Approval, -0.33) {
+		t.Errorf("Approval = %v, want -0.33", s.Approval)
+	}
+	if !approxEqual(s.Recalibration, 0.17) {
+		t.Errorf("Recalibration = %v, want 0.17", s.Recalibration)
+	}
+	if u := s.Unattributed(0.5); u != 0 {
+		t.Errorf("Unattributed() = %v, want 0", u)
+	}
+}
+
+func TestAdjust_Untracked(t *testing.T) {
+	meta := map[string]interface{}{"confidence": 0.8}
+	Adjust(meta, Approval, 0.4)
+
+	s, ok := FromMetadata(meta)
+	if !ok {
+		t.Fatal("FromMetadata() ok = false, want true")
+	}
+	if s.Base != 0.8 || s.Origin != OriginUntracked {
+		t.Errorf("base = %v (%s), want 0.8 (untracked)", s.Base, s.Origin)
+	}
+	if !approxEqual(s.Approval, -0.4) {
+		t.Errorf("Approval = %v, want -0.4", s.Approval)
+	}
+}
+
+func TestFromMetadata_JSONRoundTrip(t *testing.T) {
+	meta := map[string]interface{}{}
+	SetBase(meta, 0.7, OriginPack)
+	Adjust(meta, Reinforcement, 0.75)
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded map[string]interface{}
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+
+	s, ok := FromMetadata(decoded)
+	if !ok {
+		t.Fatal("FromMetadata() ok = false after round trip")
+	}
+	if s.Origin != OriginPack || !approxEqual(s.Total(), 0.75) {
+		t.Errorf("sources = %+v, want pack origin totalling 0.75", s)
+	}
+	if u := s.Unattributed(0.9); !approxEqual(u, 0.15) {
+		t.Errorf("Unattributed(0.9) = %v, want 0.15", u)
+	}
+}
+
+func TestFromMetadata_Missing(t *testing.T) {
+	if _, ok := FromMetadata(map[string]interface{}{"confidence": 0.6}); ok {
+		t.Error("FromMetadata() ok = true for untracked behavior, want false")
+	}
+}
@@ -6,11 +6,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/nearmiss"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/tracing"
 	"github.com/nvandessel/floop/internal/utils"
 	"gopkg.in/yaml.v3"
 )
@@ -40,6 +48,46 @@ type FloopConfig struct {
 
 	// Events contains settings for the raw event buffer.
 	Events EventsConfig `json:"events" yaml:"events"`
+
+	// Similarity contains per-store similarity tuning.
+	Similarity SimilarityConfig `json:"similarity,omitempty" yaml:"similarity,omitempty"`
+
+	// Safety contains guards for destructive operations.
+	Safety SafetyConfig `json:"safety" yaml:"safety"`
+
+	// Review contains SLA settings for behaviors awaiting review.
+	Review ReviewConfig `json:"review" yaml:"review"`
+
+	// Activation contains settings for behavior activation diagnostics.
+	Activation ActivationConfig `json:"activation" yaml:"activation"`
+
+	// Ranking contains the weights of the relevance score.
+	Ranking RankingConfig `json:"ranking" yaml:"ranking"`
+
+	// Maintenance contains settings for scheduled housekeeping.
+	Maintenance MaintenanceConfig `json:"maintenance" yaml:"maintenance"`
+
+	// Metrics contains settings for local usage metrics.
+	Metrics MetricsConfig `json:"metrics" yaml:"metrics"`
+
+	// Tracing contains settings for OpenTelemetry span export.
+	Tracing TracingConfig `json:"tracing" yaml:"tracing"`
+
+	// Seeds contains settings for the built-in core behaviors.
+	Seeds SeedsConfig `json:"seeds" yaml:"seeds"`
+
+	// Embedding contains settings for the embedding model behind vector retrieval.
+	Embedding EmbeddingConfig `json:"embedding" yaml:"embedding"`
+
+	// Corrections contains retention settings for the correction log.
+	Corrections CorrectionsConfig `json:"corrections" yaml:"corrections"`
+
+	// Profile names the agent profile used when --profile is not given.
+	// Empty uses no profile.
+	Profile string `json:"profile" yaml:"profile"`
+
+	// Profiles are per-agent activation settings, selected by name.
+	Profiles map[string]ProfileConfig `json:"profiles,omitempty" yaml:"profiles,omitempty"`
 }
 
 // TokenBudgetConfig configures token budget limits for behavior injection.
@@ -56,13 +104,26 @@ type BackupConfig struct {
 	// Compression enables gzip compression for backups (V2 format).
 	Compression bool `json:"compression" yaml:"compression"`
 
-	// AutoBackup enables automatic backups after learn operations.
+	// AutoBackup enables automatic backups, run by long-running floop
+	// processes (the MCP server) on Schedule.
 	AutoBackup bool `json:"auto_backup" yaml:"auto_backup"`
 
+	// Schedule configures when automatic backups run.
+	Schedule BackupScheduleConfig `json:"schedule" yaml:"schedule"`
+
 	// Retention configures backup retention policies.
 	Retention RetentionConfig `json:"retention" yaml:"retention"`
 }
 
+// BackupScheduleConfig configures when automatic backups run.
+type BackupScheduleConfig struct {
+	// EveryLearns backs up after this many learned corrections (0 = disabled).
+	EveryLearns int `json:"every_learns" yaml:"every_learns"`
+
+	// Daily backs up on the first open of each day.
+	Daily bool `json:"daily" yaml:"daily"`
+}
+
 // RetentionConfig configures backup retention policies.
 type RetentionConfig struct {
 	// MaxCount is the maximum number of backups to keep (0 = unlimited).
@@ -79,6 +140,94 @@ type RetentionConfig struct {
 type PacksConfig struct {
 	Installed  []InstalledPack `json:"installed,omitempty" yaml:"installed,omitempty"`
 	Registries []Registry      `json:"registries,omitempty" yaml:"registries,omitempty"`
+
+	// TrustedKeys are the ed25519 keys whose pack signatures are trusted.
+	TrustedKeys []TrustedKey `json:"trusted_keys,omitempty" yaml:"trusted_keys,omitempty"`
+
+	// SignaturePolicy decides what happens when a pack is unsigned or signed
+	// by an untrusted key: "warn" (default), "require", or "off".
+	SignaturePolicy string `json:"signature_policy,omitempty" yaml:"signature_policy,omitempty"`
+
+	// CollisionPolicy decides what happens when a pack behavior collides
+	// with a local one of the same ID or near-identical content:
+	// "prefer-pack" (default), "keep-local", "rename", or "prompt".
+	CollisionPolicy string `json:"collision_policy,omitempty" yaml:"collision_policy,omitempty"`
+
+	// GitHubToken authenticates gh: sources, for private repositories and
+	// higher rate limits. Supports ${VAR} syntax for env vars, expanded
+	// when used. The GITHUB_TOKEN env var takes precedence.
+	GitHubToken string `json:"github_token,omitempty" yaml:"github_token,omitempty"`
+
+	// CacheMaxAge evicts pack download cache files not used for this long
+	// (e.g. "90d") when gc or `floop pack cache clean` runs. Empty keeps
+	// them until their packs are removed.
+	CacheMaxAge string `json:"cache_max_age,omitempty" yaml:"cache_max_age,omitempty"`
+}
+
+// GitHubAuthToken returns the GitHub token with ${VAR} references expanded.
+func (c PacksConfig) GitHubAuthToken() string {
+	return expandEnvVars(c.GitHubToken)
+}
+
+// RedactedGitHubToken returns the GitHub token with most characters
+// masked. Env var references are shown as-is.
+func (c PacksConfig) RedactedGitHubToken() string {
+	return redactToken(c.GitHubToken)
+}
+
+// Pack signature policies.
+const (
+	SignaturePolicyWarn    = "warn"
+	SignaturePolicyRequire = "require"
+	SignaturePolicyOff     = "off"
+)
+
+// EffectiveSignaturePolicy returns the signature policy, defaulting to warn.
+func (c PacksConfig) EffectiveSignaturePolicy() string {
+	if c.SignaturePolicy == "" {
+		return SignaturePolicyWarn
+	}
+	return c.SignaturePolicy
+}
+
+// Pack collision policies.
+const (
+	CollisionPreferPack = "prefer-pack"
+	CollisionKeepLocal  = "keep-local"
+	CollisionRename     = "rename"
+	CollisionPrompt     = "prompt"
+)
+
+// CollisionPolicies lists the valid collision policies.
+var CollisionPolicies = []string{CollisionPreferPack, CollisionKeepLocal, CollisionRename, CollisionPrompt}
+
+// EffectiveCollisionPolicy returns the collision policy, defaulting to
+// prefer-pack.
+func (c PacksConfig) EffectiveCollisionPolicy() string {
+	if c.CollisionPolicy == "" {
+		return CollisionPreferPack
+	}
+	return c.CollisionPolicy
+}
+
+// CacheMaxAgeDuration returns packs.cache_max_age as a duration, or 0 if
+// it is unset or invalid.
+func (c PacksConfig) CacheMaxAgeDuration() time.Duration {
+	if c.CacheMaxAge == "" {
+		return 0
+	}
+	d, err := utils.ParseDuration(c.CacheMaxAge)
+	if err != nil {
+		return 0
+	}
+	return d
+}
+
+// TrustedKey is a pack signing key trusted at install time.
+type TrustedKey struct {
+	Name string `json:"name" yaml:"name"`
+	// PublicKey is a PEM public key or a base64 raw ed25519 public key.
+	PublicKey string `json:"public_key" yaml:"public_key"`
 }
 
 // InstalledPack records a skill pack that has been installed.
@@ -89,12 +238,57 @@ type InstalledPack struct {
 	Source        string    `json:"source,omitempty" yaml:"source,omitempty"`
 	BehaviorCount int       `json:"behavior_count" yaml:"behavior_count"`
 	EdgeCount     int       `json:"edge_count" yaml:"edge_count"`
+
+	// Path is the pack file the installed version was read from: the
+	// download cache for remote sources.
+	Path string `json:"path,omitempty" yaml:"path,omitempty"`
+
+	// History holds the versions this one replaced, oldest first, for
+	// 'floop pack rollback'.
+	History []PackVersion `json:"history,omitempty" yaml:"history,omitempty"`
+}
+
+// PackVersion records a previously installed version of a pack.
+type PackVersion struct {
+	Version     string    `json:"version" yaml:"version"`
+	InstalledAt time.Time `json:"installed_at" yaml:"installed_at"`
+	Source      string    `json:"source,omitempty" yaml:"source,omitempty"`
+	Path        string    `json:"path,omitempty" yaml:"path,omitempty"`
 }
 
 // Registry is a URL for discovering skill packs.
 type Registry struct {
 	Name string `json:"name" yaml:"name"`
 	URL  string `json:"url" yaml:"url"`
+	// PublicKey is a base64 ed25519 public key. When set, every pack
+	// downloaded from the registry must carry a valid signature.
+	PublicKey string `json:"public_key,omitempty" yaml:"public_key,omitempty"`
+	// Token authenticates pack uploads. Supports ${VAR} syntax for env vars;
+	// it is expanded when used, so the reference is what gets saved.
+	Token string `json:"token,omitempty" yaml:"token,omitempty"`
+}
+
+// AuthToken returns the registry token with ${VAR} references expanded.
+func (r Registry) AuthToken() string {
+	return expandEnvVars(r.Token)
+}
+
+// RedactedToken returns the token with most characters masked, like
+// LLMConfig.RedactedAPIKey. Env var references are shown as-is.
+func (r Registry) RedactedToken() string {
+	return redactToken(r.Token)
+}
+
+// redactToken masks most characters of a token, leaving env var
+// references as-is.
+func redactToken(token string) string {
+	if token == "" || strings.HasPrefix(token, "${") {
+		return token
+	}
+	if len(token) < 12 {
+		return "(set)"
+	}
+	return token[:4] + "..." + token[len(token)-4:]
 }
 
 // LoggingConfig configures floop's logging behavior.
@@ -107,14 +301,16 @@ type LoggingConfig struct {
 
 // LLMConfig configures LLM-based behavior comparison and merging.
 type LLMConfig struct {
-	// Provider identifies the LLM backend: "anthropic", "openai", "ollama", "subagent", "local", or "" for disabled.
+	// Provider identifies the LLM backend: "anthropic", "openai", "ollama",
+	// "openai-compatible", "subagent", "local", or "" for disabled.
 	Provider string `json:"provider" yaml:"provider"`
 
 	// APIKey is the API key for the provider. Supports ${VAR} syntax for env vars.
 	// Not required for ollama.
 	APIKey string `json:"api_key,omitempty" yaml:"api_key,omitempty"`
 
-	// BaseURL is the API endpoint URL. Used for ollama or custom OpenAI-compatible endpoints.
+	// BaseURL is the API endpoint URL. Used for ollama or custom OpenAI-compatible endpoints,
+	// and required for openai-compatible.
 	// Defaults: ollama=http://localhost:11434/v1, openai=https://api.openai.com/v1
 	BaseURL string `json:"base_url,omitempty" yaml:"base_url,omitempty"`
 
@@ -127,6 +323,18 @@ type LLMConfig struct {
 	// Timeout is the maximum duration to wait for LLM responses.
 	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
 
+	// MaxRetries is how many times a request that failed with a network
+	// error, rate limit, or server error is retried. Zero disables retries.
+	MaxRetries int `json:"max_retries" yaml:"max_retries"`
+
+	// DailyBudgetUSD caps the estimated cost of LLM calls per day; once it
+	// is spent, LLM features fall back to rules until the next day. Zero is
+	// unlimited.
+	DailyBudgetUSD float64 `json:"daily_budget_usd,omitempty" yaml:"daily_budget_usd,omitempty"`
+
+	// DailyCallLimit caps the number of LLM calls per day. Zero is unlimited.
+	DailyCallLimit int `json:"daily_call_limit,omitempty" yaml:"daily_call_limit,omitempty"`
+
 	// Enabled indicates whether LLM features are enabled.
 	Enabled bool `json:"enabled" yaml:"enabled"`
 
@@ -134,6 +342,11 @@ type LLMConfig struct {
 	// when LLM is unavailable or fails.
 	FallbackToRules bool `json:"fallback_to_rules" yaml:"fallback_to_rules"`
 
+	// ExtractBehaviors refines behaviors learned from corrections with the
+	// LLM: a cleaner canonical statement, kind, tags, and when-conditions.
+	// The rule-based extraction is kept when the LLM fails.
+	ExtractBehaviors bool `json:"extract_behaviors" yaml:"extract_behaviors"`
+
 	// LocalLibPath is the directory containing yzma shared libraries (.so/.dylib).
 	// Falls back to YZMA_LIB env var at runtime. Only used when provider is "local".
 	LocalLibPath string `json:"local_lib_path,omitempty" yaml:"local_lib_path,omitempty"`
@@ -202,8 +415,346 @@ type EventsConfig struct {
 	RetentionDays int `json:"retention_days" yaml:"retention_days"`
 }
 
+// SimilarityConfig holds similarity weights and thresholds fitted by
+// 'floop tune-similarity'. Stores without an entry use the built-in defaults.
+type SimilarityConfig struct {
+	// Stores maps an absolute .floop directory path to its tuned parameters.
+	Stores map[string]similarity.Tuning `json:"stores,omitempty" yaml:"stores,omitempty"`
+}
+
+// TuningFor returns the similarity tuning for the store at floopDir,
+// falling back to the defaults when the store has not been tuned.
+func (c SimilarityConfig) TuningFor(floopDir string) similarity.Tuning {
+	if t, ok := c.Lookup(floopDir); ok {
+		return t
+	}
+	return similarity.DefaultTuning()
+}
+
+// Lookup returns the similarity tuning recorded for the store at floopDir
+// and whether one exists.
+func (c SimilarityConfig) Lookup(floopDir string) (similarity.Tuning, bool) {
+	t, ok := c.Stores[similarityStoreKey(floopDir)]
+	return t, ok
+}
+
+// SetTuning records the similarity tuning for the store at floopDir.
+func (c *SimilarityConfig) SetTuning(floopDir string, t similarity.Tuning) {
+	if c.Stores == nil {
+		c.Stores = make(map[string]similarity.Tuning)
+	}
+	c.Stores[similarityStoreKey(floopDir)] = t
+}
+
+// RemoveTuning drops the similarity tuning recorded for the store at
+// floopDir, reporting whether there was one.
+func (c *SimilarityConfig) RemoveTuning(floopDir string) bool {
+	key := similarityStoreKey(floopDir)
+	if _, ok := c.Stores[key]; !ok {
+		return false
+	}
+	delete(c.Stores, key)
+	return true
+}
+
+// similarityStoreKey normalizes a store directory to an absolute, clean path.
+func similarityStoreKey(floopDir string) string {
+	if abs, err := filepath.Abs(floopDir); err == nil {
+		return filepath.Clean(abs)
+	}
+	return filepath.Clean(floopDir)
+}
+
+// Destructive operation names accepted in safety.protected_operations.
+const (
+	OpForget             = "forget"
+	OpMerge              = "merge"
+	OpRestoreReplace     = "restore-replace"
+	OpPackRemove         = "pack-remove"
+	OpDeinitPurge        = "deinit-purge"
+	OpArchiveOverwrite   = "archive-overwrite"
+	OpPackCachePurge     = "pack-cache-purge"
+	OpCorrectionsCompact = "corrections-compact"
+	OpGC                 = "gc"
+	OpUndo               = "undo"
+	OpRevert             = "revert"
+	OpSyncCompact        = "sync-compact"
+)
+
+// DestructiveOperations lists every operation that can be protected.
+var DestructiveOperations = []string{
+	OpForget, OpMerge, OpRestoreReplace, OpPackRemove, OpDeinitPurge, OpArchiveOverwrite, OpPackCachePurge,
+	OpCorrectionsCompact, OpGC, OpUndo, OpRevert, OpSyncCompact,
+}
+
+// EnvAllowProtected is the environment variable that unlocks protected
+// operations. It holds a comma-separated list of operation names, or "*".
+const EnvAllowProtected = "FLOOP_ALLOW_PROTECTED"
+
+// SafetyConfig configures guards for destructive operations.
+type SafetyConfig struct {
+	// ProtectedOperations lists operations that, in addition to confirmation,
+	// require FLOOP_ALLOW_PROTECTED to name them in the environment.
+	ProtectedOperations []string `json:"protected_operations" yaml:"protected_operations"`
+}
+
+// IsProtected reports whether op is in the protected list.
+func (c SafetyConfig) IsProtected(op string) bool {
+	for _, p := range c.ProtectedOperations {
+		if p == op {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckAllowed returns an error if op is protected and not unlocked by
+// FLOOP_ALLOW_PROTECTED. Unprotected operations are always allowed.
+func (c SafetyConfig) CheckAllowed(op string) error {
+	if !c.IsProtected(op) {
+		return nil
+	}
+	for _, v := range strings.Split(os.Getenv(EnvAllowProtected), ",") {
+		v = strings.TrimSpace(v)
+		if v == "*" || v == op {
+			return nil
+		}
+	}
+	return fmt.Errorf("operation %q is protected by safety.protected_operations; set %s=%s to allow it", op, EnvAllowProtected, op)
+}
+
+// ReviewConfig configures review SLA reminders and escalation.
+type ReviewConfig struct {
+	// SLA is how long a behavior may await review before reminders fire
+	// (e.g., "7d", "48h"). Empty = no reminders.
+	SLA string `json:"sla" yaml:"sla"`
+
+	// EscalateAfter is how long a behavior may await review before it is
+	// escalated. Empty = never escalate.
+	EscalateAfter string `json:"escalate_after" yaml:"escalate_after"`
+
+	// EscalateAction is "downgrade" (halve confidence) or "quarantine"
+	// (deprecate until restored).
+	EscalateAction string `json:"escalate_action" yaml:"escalate_action"`
+
+	// WebhookURL receives a JSON POST listing overdue items from
+	// 'floop review remind'. Empty = CLI reminders only.
+	WebhookURL string `json:"webhook_url,omitempty" yaml:"webhook_url,omitempty"`
+
+	// HoldPending stores behaviors that need review as pending behaviors,
+	// which do not activate until approved with 'floop review approve'.
+	HoldPending bool `json:"hold_pending" yaml:"hold_pending"`
+}
+
+// Policy converts the config into a review.Policy.
+func (c ReviewConfig) Policy() (review.Policy, error) {
+	var p review.Policy
+	var err error
+	if c.SLA != "" {
+		if p.SLA, err = utils.ParseDuration(c.SLA); err != nil {
+			return review.Policy{}, fmt.Errorf("review.sla: %w", err)
+		}
+	}
+	if c.EscalateAfter != "" {
+		if p.EscalateAfter, err = utils.ParseDuration(c.EscalateAfter); err != nil {
+			return review.Policy{}, fmt.Errorf("review.escalate_after: %w", err)
+		}
+	}
+	p.Action = review.EscalationAction(c.EscalateAction)
+	if p.Action != "" && !slices.Contains(review.ValidEscalationActions, p.Action) {
+		return review.Policy{}, fmt.Errorf("review.escalate_action: invalid action %q (valid: downgrade, quarantine)", c.EscalateAction)
+	}
+	return p, nil
+}
+
+// ActivationConfig configures near-miss reporting and load shedding for
+// behavior activation.
+type ActivationConfig struct {
+	// NearMissMaxFailing is the most contradicted conditions a behavior may
+	// have and still be reported as a near miss. 0 disables near misses.
+	NearMissMaxFailing int `json:"near_miss_max_failing" yaml:"near_miss_max_failing"`
+
+	// NearMissSuggestAfter is how many near misses on the same condition
+	// are needed before relaxing that condition is suggested. 0 uses the
+	// default.
+	NearMissSuggestAfter int `json:"near_miss_suggest_after" yaml:"near_miss_suggest_after"`
+
+	// TimeBudget is how long activation may spend scoring behaviors before
+	// it sheds load and returns the top ShedTopN by cached rank/priority.
+	// 0 disables the budget.
+	TimeBudget time.Duration `json:"time_budget" yaml:"time_budget"`
+
+	// ShedTopN is how many behaviors are kept when activation sheds load.
+	ShedTopN int `json:"shed_top_n" yaml:"shed_top_n"`
+
+	// GraphWeight is how much graph centrality (PageRank) contributes to a
+	// behavior's relevance score, from 0 to 1. 0 disables it.
+	GraphWeight float64 `json:"graph_weight" yaml:"graph_weight"`
+
+	// GitContext adds the working tree state (changed files, staged
+	// languages, base branch) to the activation context by running git,
+	// so behaviors can match on it without the agent passing it.
+	GitContext bool `json:"git_context" yaml:"git_context"`
+}
+
+// RankingConfig weights the signals behind a behavior's relevance score.
+// Each weight is from 0 to 1; the defaults sum to 1.
+type RankingConfig struct {
+	// ContextWeight is the weight of how specifically a behavior's
+	// conditions match the current context.
+	ContextWeight float64 `json:"context_weight" yaml:"context_weight"`
+
+	// BaseLevelWeight is the weight of ACT-R base-level activation: how
+	// often and how recently the behavior was activated.
+	BaseLevelWeight float64 `json:"base_level_weight" yaml:"base_level_weight"`
+
+	// FeedbackWeight is the weight of the behavior's confirmed to
+	// overridden ratio.
+	FeedbackWeight float64 `json:"feedback_weight" yaml:"feedback_weight"`
+
+	// PriorityWeight is the weight of the behavior's priority and kind.
+	PriorityWeight float64 `json:"priority_weight" yaml:"priority_weight"`
+}
+
+// ScorerConfig returns the relevance scorer configuration for profile p,
+// with the configured ranking weights and activation.graph_weight applied.
+func (c *FloopConfig) ScorerConfig(p ProfileConfig) ranking.ScorerConfig {
+	sc := p.ScorerConfig()
+	sc.ContextWeight = c.Ranking.ContextWeight
+	sc.BaseLevelWeight = c.Ranking.BaseLevelWeight
+	sc.FeedbackWeight = c.Ranking.FeedbackWeight
+	sc.PriorityWeight = c.Ranking.PriorityWeight
+	sc.GraphWeight = c.Activation.GraphWeight
+	return sc
+}
+
+// MaintenanceConfig configures scheduled housekeeping run by the MCP server.
+type MaintenanceConfig struct {
+	// GCInterval is how often the MCP server garbage-collects orphaned vector
+	// index entries and pack cache files at startup (e.g., "7d").
+	// Empty = never; run 'floop gc' manually.
+	GCInterval string `json:"gc_interval" yaml:"gc_interval"`
+
+	// DecayInterval is how often the MCP server decays the confidence of
+	// stale behaviors at startup (e.g., "1d"). Empty = never; run
+	// 'floop maintain decay' manually.
+	DecayInterval string `json:"decay_interval" yaml:"decay_interval"`
+
+	// DecayWindow is how long a behavior may go without activating before
+	// its confidence starts to decay (e.g., "30d").
+	DecayWindow string `json:"decay_window" yaml:"decay_window"`
+
+	// DecayHalfLife is how long a stale behavior takes to lose half of its
+	// confidence, down to the reinforcement floor (e.g., "90d").
+	DecayHalfLife string `json:"decay_half_life" yaml:"decay_half_life"`
+}
+
+// MetricsConfig configures local usage metrics.
+type MetricsConfig struct {
+	// Enabled records command and MCP tool invocations, learn outcomes,
+	// and activations, with their latency, to .floop/metrics.jsonl for
+	// 'floop insights'. Metrics never leave the machine. Off by default.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+}
+
+// TracingConfig configures OpenTelemetry tracing.
+type TracingConfig struct {
+	// Enabled exports spans for store operations, learning pipeline stages,
+	// LLM calls, and pack fetches over OTLP/HTTP. Off by default.
+	Enabled bool `json:"enabled" yaml:"enabled"`
+
+	// Endpoint is the OTLP/HTTP collector URL (e.g., http://localhost:4318).
+	// Empty uses the standard OTEL_EXPORTER_OTLP_ENDPOINT variables.
+	Endpoint string `json:"endpoint,omitempty" yaml:"endpoint,omitempty"`
+
+	// SampleRatio is the fraction of traces exported, from 0 to 1. Zero
+	// exports every trace.
+	SampleRatio float64 `json:"sample_ratio,omitempty" yaml:"sample_ratio,omitempty"`
+}
+
+// SeedsConfig configures which built-in core behaviors are seeded.
+type SeedsConfig struct {
+	// Experimental installs seeds still being trialed in addition to stable
+	// ones. Turning it off withdraws experimental seeds on the next seeding.
+	Experimental bool `json:"experimental" yaml:"experimental"`
+}
+
+// Embedding backends.
+const (
+	// EmbeddingBackendLocal runs a GGUF embedding model in-process with llama.cpp.
+	EmbeddingBackendLocal = "local"
+
+	// EmbeddingBackendServer calls an OpenAI-compatible /embeddings endpoint,
+	// such as llama.cpp's llama-server or Ollama.
+	EmbeddingBackendServer = "server"
+)
+
+// DefaultEmbeddingBatchSize is how many behaviors are embedded per request
+// when rebuilding the vector index.
+const DefaultEmbeddingBatchSize = 32
+
+// EmbeddingConfig configures the embedding model used for vector retrieval.
+// Embeddings are computed on the local machine; no cloud API is involved.
+type EmbeddingConfig struct {
+	// Backend is "local" or "server". Empty uses the local backend when a
+	// model is configured or installed with 'floop init --embeddings'.
+	Backend string `json:"backend" yaml:"backend"`
+
+	// ModelPath is the GGUF embedding model for the local backend. Empty
+	// falls back to llm.local_embedding_model_path, then the installed model.
+	ModelPath string `json:"model_path,omitempty" yaml:"model_path,omitempty"`
+
+	// URL is the base URL of the server backend (e.g., "http://localhost:8080/v1").
+	URL string `json:"url,omitempty" yaml:"url,omitempty"`
+
+	// Model is the model name sent to the server backend. Servers hosting a
+	// single model may ignore it.
+	Model string `json:"model,omitempty" yaml:"model,omitempty"`
+
+	// Dimension is the expected vector size. Embeddings of another size are
+	// rejected. 0 accepts whatever the model produces.
+	Dimension int `json:"dimension" yaml:"dimension"`
+
+	// BatchSize is how many behaviors are embedded per request when
+	// rebuilding the vector index.
+	BatchSize int `json:"batch_size" yaml:"batch_size"`
+}
+
+// CorrectionsConfig configures retention of the correction log
+// (.floop/corrections.jsonl). Corrections past the limits are moved to a
+// gzip archive beside it, not deleted.
+type CorrectionsConfig struct {
+	// MaxAge archives corrections older than this (e.g., "180d").
+	// Empty = no age limit.
+	MaxAge string `json:"max_age" yaml:"max_age"`
+
+	// MaxCount archives the oldest corrections beyond this many (0 = unlimited).
+	MaxCount int `json:"max_count" yaml:"max_count"`
+
+	// ProcessedOnly never archives corrections not yet turned into behaviors.
+	ProcessedOnly bool `json:"processed_only" yaml:"processed_only"`
+
+	// CompactInterval is how often the log is compacted automatically after
+	// learning (e.g., "7d"). Empty = never; run 'floop corrections compact'.
+	CompactInterval string `json:"compact_interval" yaml:"compact_interval"`
+}
+
+// DefaultLLMMaxRetries is the default number of retries for transient LLM
+// request failures.
+const DefaultLLMMaxRetries = 2
+
+// LLMProviders returns the valid values of llm.provider other than "": the
+// API providers registered with the llm package plus the in-process
+// subagent and local backends.
+func LLMProviders() []string {
+	providers := append(llm.Providers(), "subagent", "local")
+	slices.Sort(providers)
+	return providers
+}
+
 // Default returns a FloopConfig with sensible defaults.
 func Default() *FloopConfig {
+	scorer := ranking.DefaultScorerConfig()
 	return &FloopConfig{
 		LLM: LLMConfig{
 			Provider:        "",
@@ -211,6 +762,7 @@ func Default() *FloopConfig {
 			ComparisonModel: "claude-3-haiku-20240307",
 			MergeModel:      "claude-3-haiku-20240307",
 			Timeout:         5 * time.Second,
+			MaxRetries:      DefaultLLMMaxRetries,
 			Enabled:         false,
 			FallbackToRules: true,
 		},
@@ -228,6 +780,9 @@ func Default() *FloopConfig {
 		Backup: BackupConfig{
 			Compression: true,
 			AutoBackup:  true,
+			Schedule: BackupScheduleConfig{
+				EveryLearns: 1,
+			},
 			Retention: RetentionConfig{
 				MaxCount: constants.MaxBackupRotation,
 			},
@@ -239,18 +794,72 @@ func Default() *FloopConfig {
 		Events: EventsConfig{
 			RetentionDays: 90,
 		},
+		Review: ReviewConfig{
+			SLA:            "7d",
+			EscalateAction: string(review.EscalateDowngrade),
+		},
+		Activation: ActivationConfig{
+			NearMissMaxFailing:   activation.DefaultNearMissMaxFailing,
+			NearMissSuggestAfter: nearmiss.DefaultSuggestAfter,
+			TimeBudget:           activation.DefaultTimeBudget,
+			ShedTopN:             activation.DefaultShedTopN,
+			GraphWeight:          ranking.DefaultGraphWeight,
+		},
+		Ranking: RankingConfig{
+			ContextWeight:   scorer.ContextWeight,
+			BaseLevelWeight: scorer.BaseLevelWeight,
+			FeedbackWeight:  scorer.FeedbackWeight,
+			PriorityWeight:  scorer.PriorityWeight,
+		},
+		Maintenance: MaintenanceConfig{
+			GCInterval:    "7d",
+			DecayWindow:   "30d",
+			DecayHalfLife: "90d",
+		},
+		Embedding: EmbeddingConfig{
+			BatchSize: DefaultEmbeddingBatchSize,
+		},
+		Corrections: CorrectionsConfig{
+			MaxAge:          "180d",
+			MaxCount:        10000,
+			ProcessedOnly:   true,
+			CompactInterval: "7d",
+		},
+		Profiles: defaultProfiles(),
 	}
 }
 
-// Load loads configuration from the default locations and environment variables.
+// DefaultPath returns the path of the user config file, ~/.floop/config.yaml.
+func DefaultPath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".floop", "config.yaml"), nil
+}
+
+// Load loads the global configuration from the default location and
+// environment variables, without any project config. Commands that save the
+// global config start from it.
 // Order: defaults -> ~/.floop/config.yaml -> environment variables
 func Load() (*FloopConfig, error) {
+	return load("")
+}
+
+// LoadForProject is Load with the project config under root overlaid.
+// Order: defaults -> ~/.floop/config.yaml -> <root>/.floop/config.yaml -> environment variables
+func LoadForProject(root string) (*FloopConfig, error) {
+	return load(root)
+}
+
+// load builds the configuration, overlaying the project config under root
+// unless root is empty.
+func load(root string) (*FloopConfig, error) {
 	config := Default()
 
 	// Try to load from default config file
-	homeDir, err := os.UserHomeDir()
+	configPath, err := DefaultPath()
 	if err == nil {
-		configPath := filepath.Join(homeDir, ".floop", "config.yaml")
 		if _, statErr := os.Stat(configPath); statErr == nil {
 			fileConfig, loadErr := LoadFromFile(configPath)
 			if loadErr != nil {
@@ -260,6 +869,16 @@ func Load() (*FloopConfig, error) {
 		}
 	}
 
+	// Overlay the project config, unless root is the home directory
+	if root != "" {
+		projectPath := ProjectPath(root)
+		if !samePath(projectPath, configPath) {
+			if err := config.applyProjectFile(projectPath); err != nil {
+				return nil, fmt.Errorf("loading project config file: %w", err)
+			}
+		}
+	}
+
 	// Apply environment variable overrides
 	applyEnvOverrides(config)
 
@@ -294,9 +913,20 @@ func (c *FloopConfig) Validate() error {
 		return fmt.Errorf("timeout must be non-negative, got %v", c.LLM.Timeout)
 	}
 
-	validProviders := map[string]bool{"": true, "anthropic": true, "openai": true, "ollama": true, "subagent": true, "local": true}
-	if !validProviders[c.LLM.Provider] {
-		return fmt.Errorf("invalid provider: %s (valid: anthropic, openai, ollama, subagent, local, or empty)", c.LLM.Provider)
+	if c.LLM.MaxRetries < 0 {
+		return fmt.Errorf("max_retries must be non-negative, got %d", c.LLM.MaxRetries)
+	}
+
+	if c.LLM.DailyBudgetUSD < 0 {
+		return fmt.Errorf("daily_budget_usd must be non-negative, got %f", c.LLM.DailyBudgetUSD)
+	}
+
+	if c.LLM.DailyCallLimit < 0 {
+		return fmt.Errorf("daily_call_limit must be non-negative, got %d", c.LLM.DailyCallLimit)
+	}
+
+	if c.LLM.Provider != "" && !slices.Contains(LLMProviders(), c.LLM.Provider) {
+		return fmt.Errorf("invalid provider: %s (valid: %s, or empty)", c.LLM.Provider, strings.Join(LLMProviders(), ", "))
 	}
 
 	validLevels := map[string]bool{"info": true, "debug": true, "trace": true}
@@ -312,6 +942,9 @@ func (c *FloopConfig) Validate() error {
 	}
 
 	// Backup validation
+	if c.Backup.Schedule.EveryLearns < 0 {
+		return fmt.Errorf("backup.schedule.every_learns must be >= 0, got %d", c.Backup.Schedule.EveryLearns)
+	}
 	if c.Backup.Retention.MaxCount < 0 {
 		return fmt.Errorf("backup.retention.max_count must be >= 0, got %d", c.Backup.Retention.MaxCount)
 	}
@@ -339,6 +972,120 @@ func (c *FloopConfig) Validate() error {
 		return fmt.Errorf("events.retention_days must be non-negative, got %d", c.Events.RetentionDays)
 	}
 
+	// Safety validation
+	for _, op := range c.Safety.ProtectedOperations {
+		if !slices.Contains(DestructiveOperations, op) {
+			return fmt.Errorf("safety.protected_operations: unknown operation %q (valid: %s)", op, strings.Join(DestructiveOperations, ", "))
+		}
+	}
+
+	// Review validation
+	if _, err := c.Review.Policy(); err != nil {
+		return err
+	}
+	if c.Review.WebhookURL != "" && !strings.HasPrefix(c.Review.WebhookURL, "https://") && !strings.HasPrefix(c.Review.WebhookURL, "http://") {
+		return fmt.Errorf("review.webhook_url must be an http(s) URL")
+	}
+
+	// Activation validation
+	if c.Activation.NearMissMaxFailing < 0 {
+		return fmt.Errorf("activation.near_miss_max_failing must be non-negative, got %d", c.Activation.NearMissMaxFailing)
+	}
+	if c.Activation.NearMissSuggestAfter < 0 {
+		return fmt.Errorf("activation.near_miss_suggest_after must be non-negative, got %d", c.Activation.NearMissSuggestAfter)
+	}
+	if c.Activation.TimeBudget < 0 {
+		return fmt.Errorf("activation.time_budget must be non-negative, got %v", c.Activation.TimeBudget)
+	}
+	if c.Activation.ShedTopN < 0 {
+		return fmt.Errorf("activation.shed_top_n must be non-negative, got %d", c.Activation.ShedTopN)
+	}
+	if c.Activation.GraphWeight < 0 || c.Activation.GraphWeight > 1 {
+		return fmt.Errorf("activation.graph_weight must be between 0 and 1, got %v", c.Activation.GraphWeight)
+	}
+
+	for key, w := range map[string]float64{
+		"ranking.context_weight":    c.Ranking.ContextWeight,
+		"ranking.base_level_weight": c.Ranking.BaseLevelWeight,
+		"ranking.feedback_weight":   c.Ranking.FeedbackWeight,
+		"ranking.priority_weight":   c.Ranking.PriorityWeight,
+	} {
+		if w < 0 || w > 1 {
+			return fmt.Errorf("%s must be between 0 and 1, got %v", key, w)
+		}
+	}
+
+	// Maintenance and corrections validation
+	for key, value := range map[string]string{
+		"maintenance.gc_interval":      c.Maintenance.GCInterval,
+		"maintenance.decay_interval":   c.Maintenance.DecayInterval,
+		"maintenance.decay_window":     c.Maintenance.DecayWindow,
+		"maintenance.decay_half_life":  c.Maintenance.DecayHalfLife,
+		"corrections.max_age":          c.Corrections.MaxAge,
+		"corrections.compact_interval": c.Corrections.CompactInterval,
+		"packs.cache_max_age":          c.Packs.CacheMaxAge,
+	} {
+		if value != "" {
+			if _, err := utils.ParseDuration(value); err != nil {
+				return fmt.Errorf("%s: %w", key, err)
+			}
+		}
+	}
+
+	// Tracing validation
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		return fmt.Errorf("tracing.sample_ratio must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+	}
+	if c.Tracing.Endpoint != "" {
+		if _, err := tracing.EndpointURL(c.Tracing.Endpoint); err != nil {
+			return fmt.Errorf("tracing.endpoint: %w", err)
+		}
+	}
+
+	if c.Corrections.MaxCount < 0 {
+		return fmt.Errorf("corrections.max_count must be non-negative, got %d", c.Corrections.MaxCount)
+	}
+
+	// Embedding validation
+	switch c.Embedding.Backend {
+	case "", EmbeddingBackendLocal:
+	case EmbeddingBackendServer:
+		if c.Embedding.URL == "" {
+			return fmt.Errorf("embedding.url is required for the server backend")
+		}
+	default:
+		return fmt.Errorf("invalid embedding.backend: %s (valid: %s, %s, or empty)", c.Embedding.Backend, EmbeddingBackendLocal, EmbeddingBackendServer)
+	}
+	if c.Embedding.Dimension < 0 {
+		return fmt.Errorf("embedding.dimension must be non-negative, got %d", c.Embedding.Dimension)
+	}
+	if c.Embedding.BatchSize < 1 {
+		return fmt.Errorf("embedding.batch_size must be at least 1, got %d", c.Embedding.BatchSize)
+	}
+
+	// Profile validation
+	for name, p := range c.Profiles {
+		if err := p.validate(name); err != nil {
+			return err
+		}
+	}
+	if _, err := c.ResolveProfile(""); err != nil {
+		return fmt.Errorf("profile: %w", err)
+	}
+
+	// Similarity tuning validation
+	for dir, t := range c.Similarity.Stores {
+		th := t.Thresholds
+		// A fitted upper_bound may sit just above 1 (similarity.NeverDuplicate).
+		if th.SimilarTo < 0 || th.UpperBound > similarity.NeverDuplicate || th.SimilarTo > th.Specialize || th.Specialize > th.UpperBound {
+			return fmt.Errorf("similarity.stores[%s]: thresholds must satisfy 0 <= similar_to <= specialize <= upper_bound <= 1", dir)
+		}
+		w := t.Weights
+		if w.When < 0 || w.Content < 0 || w.Tags < 0 || w.When+w.Content+w.Tags == 0 {
+			return fmt.Errorf("similarity.stores[%s]: weights must be non-negative and not all zero", dir)
+		}
+	}
+
 	return nil
 }
 
@@ -441,6 +1188,9 @@ func applyEnvOverrides(config *FloopConfig) {
 			config.TokenBudget.DynamicContext = n
 		}
 	}
+	if v := os.Getenv("FLOOP_PROFILE"); v != "" {
+		config.Profile = v
+	}
 
 	// Backup config overrides
 	if v := os.Getenv("FLOOP_BACKUP_COMPRESSION"); v != "" {
@@ -449,6 +1199,14 @@ func applyEnvOverrides(config *FloopConfig) {
 	if v := os.Getenv("FLOOP_BACKUP_AUTO"); v != "" {
 		config.Backup.AutoBackup = v == "true" || v == "1"
 	}
+	if v := os.Getenv("FLOOP_BACKUP_EVERY_LEARNS"); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			config.Backup.Schedule.EveryLearns = n
+		}
+	}
+	if v := os.Getenv("FLOOP_BACKUP_DAILY"); v != "" {
+		config.Backup.Schedule.Daily = v == "true" || v == "1"
+	}
 	if v := os.Getenv("FLOOP_BACKUP_MAX_COUNT"); v != "" {
 		if n, err := strconv.Atoi(v); err == nil {
 			config.Backup.Retention.MaxCount = n
@@ -457,15 +1215,17 @@ func applyEnvOverrides(config *FloopConfig) {
 	if v := os.Getenv("FLOOP_BACKUP_MAX_AGE"); v != "" {
 		config.Backup.Retention.MaxAge = v
 	}
+	if v := os.Getenv("FLOOP_TRACING"); v != "" {
+		config.Tracing.Enabled = v == "true" || v == "1"
+	}
 }
 
 // Save writes the config to the default config file with atomic write.
 func (c *FloopConfig) Save() error {
-	homeDir, err := os.UserHomeDir()
+	configPath, err := DefaultPath()
 	if err != nil {
-		return fmt.Errorf("getting home directory: %w", err)
+		return err
 	}
-	configPath := filepath.Join(homeDir, ".floop", "config.yaml")
 	return c.SaveTo(configPath)
 }
 
@@ -475,7 +1235,12 @@ func (c *FloopConfig) SaveTo(path string) error {
 	if err != nil {
 		return fmt.Errorf("marshaling config: %w", err)
 	}
+	return writeAtomic(path, data)
+}
 
+// writeAtomic writes data to path through a temp file and rename, creating
+// the directory if needed.
+func writeAtomic(path string, data []byte) error {
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0700); err != nil {
 		return fmt.Errorf("creating config directory: %w", err)
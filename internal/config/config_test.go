@@ -7,6 +7,8 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/nvandessel/floop/internal/similarity"
 )
 
 func TestDefault(t *testing.T) {
@@ -461,6 +463,58 @@ func TestValidate_TokenBudget(t *testing.T) {
 	}
 }
 
+func TestValidate_Embedding(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*EmbeddingConfig)
+		wantErr bool
+	}{
+		{"defaults", func(e *EmbeddingConfig) {}, false},
+		{"local backend", func(e *EmbeddingConfig) { e.Backend = EmbeddingBackendLocal; e.Dimension = 384 }, false},
+		{"server backend", func(e *EmbeddingConfig) { e.Backend = EmbeddingBackendServer; e.URL = "http://localhost:8080/v1" }, false},
+		{"server without url", func(e *EmbeddingConfig) { e.Backend = EmbeddingBackendServer }, true},
+		{"unknown backend", func(e *EmbeddingConfig) { e.Backend = "onnx" }, true},
+		{"negative dimension", func(e *EmbeddingConfig) { e.Dimension = -1 }, true},
+		{"zero batch size", func(e *EmbeddingConfig) { e.BatchSize = 0 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			tt.modify(&config.Embedding)
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_Corrections(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*CorrectionsConfig)
+		wantErr bool
+	}{
+		{"defaults", func(c *CorrectionsConfig) {}, false},
+		{"disabled", func(c *CorrectionsConfig) { c.MaxAge = ""; c.MaxCount = 0; c.CompactInterval = "" }, false},
+		{"bad max age", func(c *CorrectionsConfig) { c.MaxAge = "half a year" }, true},
+		{"bad interval", func(c *CorrectionsConfig) { c.CompactInterval = "weekly" }, true},
+		{"negative max count", func(c *CorrectionsConfig) { c.MaxCount = -1 }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			tt.modify(&config.Corrections)
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestLoadFromFile_NotFound(t *testing.T) {
 	_, err := LoadFromFile("/nonexistent/path/config.yaml")
 	if err == nil {
@@ -697,3 +751,121 @@ func TestSaveTo_CreatesDirectory(t *testing.T) {
 		t.Fatalf("config file should exist: %v", err)
 	}
 }
+
+func TestSimilarityConfig_TuningFor(t *testing.T) {
+	tmpDir := t.TempDir()
+	storeDir := filepath.Join(tmpDir, ".floop")
+
+	var cfg SimilarityConfig
+	if got := cfg.TuningFor(storeDir); got != similarity.DefaultTuning() {
+		t.Errorf("expected default tuning for untuned store, got %+v", got)
+	}
+	if _, ok := cfg.Lookup(storeDir); ok {
+		t.Error("Lookup() ok = true for untuned store")
+	}
+
+	tuned := similarity.DefaultTuning()
+	tuned.Thresholds.SimilarTo = 0.3
+	cfg.SetTuning(storeDir, tuned)
+
+	// Lookup normalizes the path
+	if got := cfg.TuningFor(storeDir + string(filepath.Separator)); got != tuned {
+		t.Errorf("expected tuned thresholds, got %+v", got)
+	}
+
+	if !cfg.RemoveTuning(storeDir) {
+		t.Error("RemoveTuning() = false for tuned store")
+	}
+	if _, ok := cfg.Lookup(storeDir); ok {
+		t.Error("tuning survived RemoveTuning")
+	}
+	if cfg.RemoveTuning(storeDir) {
+		t.Error("RemoveTuning() = true for untuned store")
+	}
+}
+
+func TestSaveTo_SimilarityRoundTrip(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+	storeDir := filepath.Join(tmpDir, ".floop")
+
+	tuned := similarity.Tuning{
+		Weights:    similarity.Weights{When: 0.2, Content: 0.7, Tags: 0.1},
+		Thresholds: similarity.Thresholds{SimilarTo: 0.35, Specialize: 0.5, UpperBound: 0.65},
+	}
+	cfg := Default()
+	cfg.Similarity.SetTuning(storeDir, tuned)
+
+	if err := cfg.SaveTo(configPath); err != nil {
+		t.Fatalf("SaveTo failed: %v", err)
+	}
+	loaded, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if got := loaded.Similarity.TuningFor(storeDir); got != tuned {
+		t.Errorf("round-tripped tuning = %+v, want %+v", got, tuned)
+	}
+	if err := loaded.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
+
+func TestValidate_SimilarityTuning(t *testing.T) {
+	cfg := Default()
+	bad := similarity.DefaultTuning()
+	bad.Thresholds.SimilarTo = 0.95 // above UpperBound
+	cfg.Similarity.SetTuning("/tmp/.floop", bad)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for misordered thresholds")
+	}
+
+	cfg = Default()
+	zero := similarity.DefaultTuning()
+	zero.Weights = similarity.Weights{}
+	cfg.Similarity.SetTuning("/tmp/.floop", zero)
+	if err := cfg.Validate(); err == nil {
+		t.Error("expected error for all-zero weights")
+	}
+}
+
+func TestSafetyConfig_CheckAllowed(t *testing.T) {
+	cfg := SafetyConfig{ProtectedOperations: []string{OpRestoreReplace, OpPackRemove}}
+
+	tests := []struct {
+		name    string
+		op      string
+		env     string
+		wantErr bool
+	}{
+		{"unprotected", OpForget, "", false},
+		{"protected without token", OpRestoreReplace, "", true},
+		{"protected with matching token", OpRestoreReplace, OpRestoreReplace, false},
+		{"protected with token list", OpPackRemove, "restore-replace, pack-remove", false},
+		{"protected with wildcard", OpPackRemove, "*", false},
+		{"protected with other token", OpPackRemove, OpRestoreReplace, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvAllowProtected, tt.env)
+			err := cfg.CheckAllowed(tt.op)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckAllowed(%q) error = %v, wantErr %v", tt.op, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_SafetyProtectedOperations(t *testing.T) {
+	cfg := Default()
+	cfg.Safety.ProtectedOperations = []string{OpDeinitPurge}
+	if err := cfg.Validate(); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	cfg.Safety.ProtectedOperations = []string{"store-wipe"}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Validate() should reject unknown operations")
+	}
+}
@@ -0,0 +1,135 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces secret values in a Change.
+const redacted = "<redacted>"
+
+// secretKeys are settings whose values are never reported by Diff. "[]"
+// stands for every item of a list, so a field of list items is redacted
+// inside the list's value.
+var secretKeys = map[string]bool{
+	"llm.api_key":              true,
+	"review.webhook_url":       true,
+	"packs.github_token":       true,
+	"packs.registries[].token": true,
+}
+
+// Change is a setting whose value differs between two configs.
+type Change struct {
+	Key string `json:"key"`
+	Old string `json:"old"`
+	New string `json:"new"`
+}
+
+// String formats the change for logs, e.g. "token_budget.default: 2000 -> 3000".
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s -> %s", c.Key, c.Old, c.New)
+}
+
+// Diff returns the settings that differ between old and new, keyed by their
+// dotted YAML path and sorted by key. Secret values are redacted.
+func Diff(old, new *FloopConfig) ([]Change, error) {
+	oldFlat, err := flattenConfig(old, false)
+	if err != nil {
+		return nil, err
+	}
+	newFlat, err := flattenConfig(new, false)
+	if err != nil {
+		return nil, err
+	}
+	oldShown, err := flattenConfig(old, true)
+	if err != nil {
+		return nil, err
+	}
+	newShown, err := flattenConfig(new, true)
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]bool, len(oldFlat)+len(newFlat))
+	for k := range oldFlat {
+		keys[k] = true
+	}
+	for k := range newFlat {
+		keys[k] = true
+	}
+
+	var changes []Change
+	for k := range keys {
+		if oldFlat[k] == newFlat[k] {
+			continue
+		}
+		changes = append(changes, Change{Key: k, Old: oldShown[k], New: newShown[k]})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Key < changes[j].Key })
+	return changes, nil
+}
+
+// redactSecrets replaces the non-empty values of secretKeys in the YAML
+// tree v, whose path is prefix, with the redacted marker.
+func redactSecrets(prefix string, v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, child := range v {
+			key := k
+			if prefix != "" {
+				key = prefix + "." + k
+			}
+			if secretKeys[key] {
+				if child != nil && child != "" {
+					v[k] = redacted
+				}
+				continue
+			}
+			v[k] = redactSecrets(key, child)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactSecrets(prefix+"[]", item)
+		}
+	}
+	return v
+}
+
+// flattenConfig renders c as dotted YAML keys mapped to formatted values,
+// with secret values redacted if redact is set.
+func flattenConfig(c *FloopConfig, redact bool) (map[string]string, error) {
+	flat := make(map[string]string)
+	if c == nil {
+		return flat, nil
+	}
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling config: %w", err)
+	}
+	var tree map[string]interface{}
+	if err := yaml.Unmarshal(data, &tree); err != nil {
+		return nil, fmt.Errorf("unmarshaling config: %w", err)
+	}
+	if redact {
+		redactSecrets("", tree)
+	}
+	flattenInto(flat, "", tree)
+	return flat, nil
+}
+
+func flattenInto(flat map[string]string, prefix string, v interface{}) {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		flat[prefix] = fmt.Sprint(v)
+		return
+	}
+	for k, child := range m {
+		key := k
+		if prefix != "" {
+			key = prefix + "." + k
+		}
+		flattenInto(flat, key, child)
+	}
+}
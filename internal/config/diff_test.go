@@ -0,0 +1,74 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	old := Default()
+	updated := Default()
+	updated.TokenBudget.Default = old.TokenBudget.Default + 500
+	updated.Safety.ProtectedOperations = []string{OpForget}
+	updated.LLM.APIKey = "sk-secret"
+	updated.Packs.GitHubToken = "ghp_secret"
+
+	changes, err := Diff(old, updated)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+
+	got := make(map[string]Change)
+	for _, c := range changes {
+		got[c.Key] = c
+	}
+	if len(got) != 4 {
+		t.Fatalf("Diff returned %d changes, want 4: %v", len(got), changes)
+	}
+	if c := got["token_budget.default"]; c.New == c.Old {
+		t.Errorf("token_budget.default change = %+v", c)
+	}
+	if _, ok := got["safety.protected_operations"]; !ok {
+		t.Errorf("missing safety.protected_operations change: %v", changes)
+	}
+	if c := got["llm.api_key"]; c.Old != "" || c.New != redacted {
+		t.Errorf("llm.api_key change = %+v, want redacted", c)
+	}
+	if c := got["packs.github_token"]; c.Old != "" || c.New != redacted {
+		t.Errorf("packs.github_token change = %+v, want redacted", c)
+	}
+
+	for i := 1; i < len(changes); i++ {
+		if changes[i-1].Key > changes[i].Key {
+			t.Errorf("changes not sorted: %v", changes)
+		}
+	}
+}
+
+func TestDiffIdentical(t *testing.T) {
+	changes, err := Diff(Default(), Default())
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(changes) != 0 {
+		t.Errorf("identical configs produced changes: %v", changes)
+	}
+}
+
+func TestDiffRedactsListItemSecrets(t *testing.T) {
+	old := Default()
+	old.Packs.Registries = []Registry{{Name: "corp", URL: "https://packs.example.com", Token: "regtoken-secret-1"}}
+	updated := Default()
+	updated.Packs.Registries = []Registry{{Name: "corp", URL: "https://packs.example.com", Token: "regtoken-secret-2"}}
+
+	changes, err := Diff(old, updated)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(changes) != 1 || changes[0].Key != "packs.registries" {
+		t.Fatalf("Diff = %v, want one packs.registries change", changes)
+	}
+	if s := changes[0].String(); strings.Contains(s, "regtoken-secret") || !strings.Contains(s, "corp") {
+		t.Errorf("registry change = %q, want the token redacted and the rest shown", s)
+	}
+}
@@ -0,0 +1,146 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/tiering"
+)
+
+// ProfileConfig tunes activation for one agent, such as a smaller token
+// budget for an agent with a short context window. Zero fields keep the
+// global settings.
+type ProfileConfig struct {
+	// TokenBudget replaces token_budget.default for this agent.
+	TokenBudget int `json:"token_budget,omitempty" yaml:"token_budget,omitempty"`
+
+	// MinActivation is the relevance score (0.0-1.0) a behavior needs to be
+	// injected at all. Behaviors below it are omitted rather than listed by
+	// name. Constraints still keep their minimum tier.
+	MinActivation float64 `json:"min_activation,omitempty" yaml:"min_activation,omitempty"`
+
+	// KindBoosts replace the relevance multipliers of the given behavior
+	// kinds (directive, constraint, procedure, preference, ...).
+	KindBoosts map[string]float64 `json:"kind_boosts,omitempty" yaml:"kind_boosts,omitempty"`
+
+	// ExcludeTags drops behaviors carrying any of these tags.
+	ExcludeTags []string `json:"exclude_tags,omitempty" yaml:"exclude_tags,omitempty"`
+}
+
+// Built-in profile names. Both can be redefined in config.yaml, and other
+// names added alongside them.
+const (
+	ProfileClaude  = "claude"
+	ProfileCopilot = "copilot"
+)
+
+// defaultProfiles returns the built-in agent profiles. Copilot's custom
+// instructions share a smaller context, so it gets a tighter budget and
+// only clearly relevant behaviors.
+func defaultProfiles() map[string]ProfileConfig {
+	return map[string]ProfileConfig{
+		ProfileClaude:  {},
+		ProfileCopilot: {TokenBudget: 1000, MinActivation: 0.3},
+	}
+}
+
+// ResolveProfile returns the profile with the given name, or the configured
+// default profile when name is empty. With neither set it returns the zero
+// profile, which changes nothing.
+func (c *FloopConfig) ResolveProfile(name string) (ProfileConfig, error) {
+	if name == "" {
+		name = c.Profile
+	}
+	if name == "" {
+		return ProfileConfig{}, nil
+	}
+	p, ok := c.Profiles[name]
+	if !ok {
+		return ProfileConfig{}, fmt.Errorf("unknown profile %q (configured: %s)", name, strings.Join(slices.Sorted(maps.Keys(c.Profiles)), ", "))
+	}
+	return p, nil
+}
+
+// profileKinds are the behavior kinds a profile can boost.
+var profileKinds = []models.BehaviorKind{
+	models.BehaviorKindDirective,
+	models.BehaviorKindConstraint,
+	models.BehaviorKindProcedure,
+	models.BehaviorKindPreference,
+	models.BehaviorKindEpisodic,
+	models.BehaviorKindWorkflow,
+}
+
+// validate checks a profile's values; name is used in error messages.
+func (p ProfileConfig) validate(name string) error {
+	if p.TokenBudget < 0 {
+		return fmt.Errorf("profiles.%s.token_budget must be non-negative, got %d", name, p.TokenBudget)
+	}
+	if p.MinActivation < 0 || p.MinActivation > 1 {
+		return fmt.Errorf("profiles.%s.min_activation must be between 0 and 1, got %v", name, p.MinActivation)
+	}
+	for kind, boost := range p.KindBoosts {
+		if !slices.Contains(profileKinds, models.BehaviorKind(kind)) {
+			return fmt.Errorf("profiles.%s.kind_boosts: unknown behavior kind %q", name, kind)
+		}
+		if boost < 0 {
+			return fmt.Errorf("profiles.%s.kind_boosts.%s must be non-negative, got %v", name, kind, boost)
+		}
+	}
+	return nil
+}
+
+// Budget returns the profile's token budget, or fallback when it sets none.
+func (p ProfileConfig) Budget(fallback int) int {
+	if p.TokenBudget > 0 {
+		return p.TokenBudget
+	}
+	return fallback
+}
+
+// ScorerConfig returns the relevance scorer configuration with the
+// profile's kind boosts applied.
+func (p ProfileConfig) ScorerConfig() ranking.ScorerConfig {
+	cfg := ranking.DefaultScorerConfig()
+	for kind, boost := range p.KindBoosts {
+		cfg.KindBoosts[models.BehaviorKind(kind)] = boost
+	}
+	return cfg
+}
+
+// TierConfig returns the tier thresholds with the profile's minimum
+// activation applied.
+func (p ProfileConfig) TierConfig() tiering.ActivationTierConfig {
+	cfg := tiering.DefaultActivationTierConfig()
+	if p.MinActivation > cfg.NameOnlyThreshold {
+		cfg.NameOnlyThreshold = p.MinActivation
+		cfg.SummaryThreshold = max(cfg.SummaryThreshold, p.MinActivation)
+		cfg.FullThreshold = max(cfg.FullThreshold, p.MinActivation)
+	}
+	return cfg
+}
+
+// Excludes reports whether b carries one of the profile's excluded tags.
+func (p ProfileConfig) Excludes(b models.Behavior) bool {
+	return slices.ContainsFunc(b.Content.Tags, func(tag string) bool {
+		return slices.Contains(p.ExcludeTags, tag)
+	})
+}
+
+// Filter returns the behaviors the profile does not exclude.
+func (p ProfileConfig) Filter(behaviors []models.Behavior) []models.Behavior {
+	if len(p.ExcludeTags) == 0 {
+		return behaviors
+	}
+	kept := make([]models.Behavior, 0, len(behaviors))
+	for _, b := range behaviors {
+		if !p.Excludes(b) {
+			kept = append(kept, b)
+		}
+	}
+	return kept
+}
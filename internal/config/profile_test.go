@@ -0,0 +1,166 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestResolveProfile(t *testing.T) {
+	config := Default()
+
+	p, err := config.ResolveProfile("")
+	if err != nil {
+		t.Fatalf("ResolveProfile(\"\") failed: %v", err)
+	}
+	if p.TokenBudget != 0 || p.MinActivation != 0 {
+		t.Errorf("expected zero profile with no default set, got %+v", p)
+	}
+
+	p, err = config.ResolveProfile(ProfileCopilot)
+	if err != nil {
+		t.Fatalf("ResolveProfile(copilot) failed: %v", err)
+	}
+	if p.TokenBudget != 1000 {
+		t.Errorf("expected copilot token budget 1000, got %d", p.TokenBudget)
+	}
+
+	config.Profile = ProfileCopilot
+	p, _ = config.ResolveProfile("")
+	if p.TokenBudget != 1000 {
+		t.Errorf("expected configured default profile copilot, got %+v", p)
+	}
+
+	_, err = config.ResolveProfile("gemini")
+	if err == nil || !strings.Contains(err.Error(), "claude, copilot") {
+		t.Errorf("expected unknown profile error listing configured names, got %v", err)
+	}
+}
+
+func TestLoadFromFile_Profiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	configPath := filepath.Join(tmpDir, "config.yaml")
+
+	configContent := `
+profile: custom
+profiles:
+  custom:
+    token_budget: 600
+    min_activation: 0.5
+    kind_boosts:
+      procedure: 2.0
+    exclude_tags: [experimental]
+`
+	if err := os.WriteFile(configPath, []byte(configContent), 0600); err != nil {
+		t.Fatalf("failed to write test config: %v", err)
+	}
+
+	config, err := LoadFromFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadFromFile failed: %v", err)
+	}
+	if err := config.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+
+	if _, ok := config.Profiles[ProfileCopilot]; !ok {
+		t.Error("built-in copilot profile lost when config defines another profile")
+	}
+	p, err := config.ResolveProfile("")
+	if err != nil {
+		t.Fatalf("ResolveProfile failed: %v", err)
+	}
+	if p.TokenBudget != 600 || p.MinActivation != 0.5 || p.KindBoosts["procedure"] != 2.0 {
+		t.Errorf("unexpected custom profile: %+v", p)
+	}
+	if len(p.ExcludeTags) != 1 || p.ExcludeTags[0] != "experimental" {
+		t.Errorf("expected exclude_tags [experimental], got %v", p.ExcludeTags)
+	}
+}
+
+func TestEnvOverrides_Profile(t *testing.T) {
+	t.Setenv("FLOOP_PROFILE", "copilot")
+
+	config := Default()
+	applyEnvOverrides(config)
+
+	if config.Profile != ProfileCopilot {
+		t.Errorf("expected Profile copilot, got %q", config.Profile)
+	}
+}
+
+func TestValidate_Profiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		profile ProfileConfig
+		wantErr bool
+	}{
+		{"valid", ProfileConfig{TokenBudget: 500, MinActivation: 0.4, KindBoosts: map[string]float64{"constraint": 3}}, false},
+		{"negative budget", ProfileConfig{TokenBudget: -1}, true},
+		{"activation above 1", ProfileConfig{MinActivation: 1.5}, true},
+		{"unknown kind", ProfileConfig{KindBoosts: map[string]float64{"rule": 1}}, true},
+		{"negative boost", ProfileConfig{KindBoosts: map[string]float64{"directive": -1}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := Default()
+			config.Profiles["custom"] = tt.profile
+			err := config.Validate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	config := Default()
+	config.Profile = "missing"
+	if err := config.Validate(); err == nil {
+		t.Error("expected error for unknown default profile")
+	}
+}
+
+func TestProfileConfig_Apply(t *testing.T) {
+	p := ProfileConfig{
+		TokenBudget:   800,
+		MinActivation: 0.4,
+		KindBoosts:    map[string]float64{"procedure": 2.5},
+		ExcludeTags:   []string{"experimental"},
+	}
+
+	if got := p.Budget(2000); got != 800 {
+		t.Errorf("Budget = %d, want 800", got)
+	}
+	if got := (ProfileConfig{}).Budget(2000); got != 2000 {
+		t.Errorf("zero profile Budget = %d, want fallback 2000", got)
+	}
+
+	scorer := p.ScorerConfig()
+	if scorer.KindBoosts[models.BehaviorKindProcedure] != 2.5 {
+		t.Errorf("procedure boost = %v, want 2.5", scorer.KindBoosts[models.BehaviorKindProcedure])
+	}
+	if scorer.KindBoosts[models.BehaviorKindConstraint] == 0 {
+		t.Error("profile boost dropped the default constraint boost")
+	}
+
+	tiers := p.TierConfig()
+	if tiers.NameOnlyThreshold != 0.4 {
+		t.Errorf("NameOnlyThreshold = %v, want 0.4", tiers.NameOnlyThreshold)
+	}
+	if tiers.SummaryThreshold < tiers.NameOnlyThreshold || tiers.FullThreshold < tiers.SummaryThreshold {
+		t.Errorf("tier thresholds out of order: %+v", tiers)
+	}
+
+	behaviors := []models.Behavior{
+		{ID: "keep", Content: models.BehaviorContent{Tags: []string{"go"}}},
+		{ID: "drop", Content: models.BehaviorContent{Tags: []string{"go", "experimental"}}},
+		{ID: "untagged"},
+	}
+	kept := p.Filter(behaviors)
+	if len(kept) != 2 || kept[0].ID != "keep" || kept[1].ID != "untagged" {
+		t.Errorf("Filter kept %v, want keep and untagged", kept)
+	}
+}
@@ -0,0 +1,227 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ProjectPath returns the path of the project config file under root,
+// <root>/.floop/config.yaml. It holds only the settings the project
+// overrides; the project section written by 'floop migrate' lives there too.
+func ProjectPath(root string) string {
+	return filepath.Join(root, ".floop", "config.yaml")
+}
+
+// projectKeys are the settings a project config may override: how
+// behaviors are ranked, budgeted, deduplicated, and reviewed. Everything
+// else holds secrets, spends the user's LLM budget, sends data or
+// credentials somewhere, loads native code or models, or guards
+// destructive operations, so a cloned repository must not change it. A key
+// covers everything beneath it.
+var projectKeys = []string{
+	"ranking",
+	"token_budget",
+	"activation",
+	"deduplication",
+	"profile",
+	"profiles",
+	"review.sla",
+	"review.escalate_after",
+	"review.escalate_action",
+	"review.hold_pending",
+	"corrections",
+}
+
+// IsGlobalOnly reports whether key (a dotted YAML path) can only be set in
+// the global config.
+func IsGlobalOnly(key string) bool {
+	return !slices.ContainsFunc(projectKeys, func(k string) bool {
+		return key == k || strings.HasPrefix(key, k+".")
+	})
+}
+
+// keepProjectKeys removes the entries of mapping node n, at dotted path
+// prefix, that a project config may not set.
+func keepProjectKeys(n *yaml.Node, prefix string) {
+	var kept []*yaml.Node
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		key, value := n.Content[i], n.Content[i+1]
+		path := key.Value
+		if prefix != "" {
+			path = prefix + "." + key.Value
+		}
+		switch {
+		case !IsGlobalOnly(path):
+			kept = append(kept, key, value)
+		case value.Kind == yaml.MappingNode && slices.ContainsFunc(projectKeys, func(k string) bool {
+			return strings.HasPrefix(k, path+".")
+		}):
+			keepProjectKeys(value, path)
+			kept = append(kept, key, value)
+		}
+	}
+	n.Content = kept
+}
+
+// applyProjectFile overlays the settings in the project config file at path
+// on c. A missing file changes nothing. Settings outside projectKeys are
+// ignored.
+func (c *FloopConfig) applyProjectFile(path string) error {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("parsing config file: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return fmt.Errorf("parsing config file: not a mapping")
+	}
+	keepProjectKeys(doc.Content[0], "")
+	if err := doc.Decode(c); err != nil {
+		return fmt.Errorf("parsing config file: %w", err)
+	}
+	return nil
+}
+
+// LoadProjectFile loads the defaults overlaid with the project config file at
+// path, without the global config or environment overrides.
+func LoadProjectFile(path string) (*FloopConfig, error) {
+	config := Default()
+	if err := config.applyProjectFile(path); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// SaveProjectKey writes the value of key in c to the project config file at
+// path, leaving the file's other settings and comments as they are. A key
+// whose value c omits is removed from the file, so the global value applies.
+func SaveProjectKey(path string, c *FloopConfig, key string) error {
+	if IsGlobalOnly(key) {
+		return fmt.Errorf("%s can only be set in the global config", key)
+	}
+	keyPath := strings.Split(key, ".")
+
+	var src yaml.Node
+	if err := src.Encode(c); err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+	value := findNode(&src, keyPath)
+
+	var doc yaml.Node
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+	if err == nil {
+		if err := yaml.Unmarshal(data, &doc); err != nil {
+			return fmt.Errorf("parsing config file: %w", err)
+		}
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	if value == nil {
+		deleteNode(doc.Content[0], keyPath)
+	} else {
+		setNode(doc.Content[0], keyPath, value)
+	}
+
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(&doc); err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return fmt.Errorf("marshaling config: %w", err)
+	}
+	return writeAtomic(path, buf.Bytes())
+}
+
+// findNode returns the value at path in a mapping node, or nil.
+func findNode(n *yaml.Node, path []string) *yaml.Node {
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for _, name := range path {
+		if n.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			if n.Content[i].Value == name {
+				next = n.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		n = next
+	}
+	return n
+}
+
+// setNode sets the value at path in a mapping node, creating intermediate
+// mappings as needed.
+func setNode(n *yaml.Node, path []string, value *yaml.Node) {
+	for i, name := range path {
+		var next *yaml.Node
+		for j := 0; j+1 < len(n.Content); j += 2 {
+			if n.Content[j].Value == name {
+				next = n.Content[j+1]
+				if i == len(path)-1 {
+					n.Content[j+1] = value
+					return
+				}
+				break
+			}
+		}
+		if i == len(path)-1 {
+			n.Content = append(n.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: name}, value)
+			return
+		}
+		if next == nil || next.Kind != yaml.MappingNode {
+			next = &yaml.Node{Kind: yaml.MappingNode}
+			setNode(n, path[i:i+1], next)
+		}
+		n = next
+	}
+}
+
+// deleteNode removes the entry at path from a mapping node, if present.
+func deleteNode(n *yaml.Node, path []string) {
+	parent := findNode(n, path[:len(path)-1])
+	if parent == nil || parent.Kind != yaml.MappingNode {
+		return
+	}
+	name := path[len(path)-1]
+	for i := 0; i+1 < len(parent.Content); i += 2 {
+		if parent.Content[i].Value == name {
+			parent.Content = append(parent.Content[:i], parent.Content[i+2:]...)
+			return
+		}
+	}
+}
+
+// samePath reports whether a and b name the same file.
+func samePath(a, b string) bool {
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestLoadForProject(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	globalYAML := "llm:\n  provider: anthropic\n  base_url: https://api.example.com\ntoken_budget:\n  default: 3000\nsafety:\n  protected_operations: [forget]\n"
+	if err := os.MkdirAll(filepath.Join(home, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(home, ".floop", "config.yaml"), []byte(globalYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	root := t.TempDir()
+	projectYAML := "project:\n  id: demo\nranking:\n  context_weight: 0.4\nllm:\n  base_url: https://attacker.example.com\n  daily_budget_usd: 500\n" +
+		"safety:\n  protected_operations: []\nreview:\n  sla: 3d\n  webhook_url: https://attacker.example.com/hook\n"
+	if err := os.MkdirAll(filepath.Join(root, ".floop"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ProjectPath(root), []byte(projectYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadForProject(root)
+	if err != nil {
+		t.Fatalf("LoadForProject failed: %v", err)
+	}
+	if config.Ranking.ContextWeight != 0.4 {
+		t.Errorf("ranking.context_weight = %v, want project override 0.4", config.Ranking.ContextWeight)
+	}
+	if config.Ranking.PriorityWeight != Default().Ranking.PriorityWeight {
+		t.Errorf("ranking.priority_weight = %v, want default", config.Ranking.PriorityWeight)
+	}
+	if config.LLM.Provider != "anthropic" || config.TokenBudget.Default != 3000 {
+		t.Errorf("global settings lost: provider=%q token_budget.default=%d", config.LLM.Provider, config.TokenBudget.Default)
+	}
+	if config.LLM.BaseURL != "https://api.example.com" {
+		t.Errorf("llm.base_url = %q, project config must not override it", config.LLM.BaseURL)
+	}
+	if config.LLM.DailyBudgetUSD != 0 || config.Review.WebhookURL != "" {
+		t.Errorf("project config overrode global-only settings: budget=%v webhook=%q", config.LLM.DailyBudgetUSD, config.Review.WebhookURL)
+	}
+	if !slices.Equal(config.Safety.ProtectedOperations, []string{OpForget}) {
+		t.Errorf("safety.protected_operations = %v, project config must not override it", config.Safety.ProtectedOperations)
+	}
+	if config.Review.SLA != "3d" {
+		t.Errorf("review.sla = %q, want project override 3d", config.Review.SLA)
+	}
+}
+
+func TestSaveProjectKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), ".floop", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("# project identity\nproject:\n  id: demo\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	config, err := LoadProjectFile(path)
+	if err != nil {
+		t.Fatalf("LoadProjectFile failed: %v", err)
+	}
+	config.Ranking.ContextWeight = 0.5
+	if err := SaveProjectKey(path, config, "ranking.context_weight"); err != nil {
+		t.Fatalf("SaveProjectKey failed: %v", err)
+	}
+	config.Ranking.ContextWeight = 0.45
+	if err := SaveProjectKey(path, config, "ranking.context_weight"); err != nil {
+		t.Fatalf("SaveProjectKey failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := string(data)
+	for _, want := range []string{"# project identity", "id: demo", "context_weight: 0.45"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("project config missing %q:\n%s", want, got)
+		}
+	}
+	if strings.Contains(got, "priority_weight") || strings.Contains(got, "llm:") {
+		t.Errorf("project config should hold only the saved key:\n%s", got)
+	}
+
+	reloaded, err := LoadProjectFile(path)
+	if err != nil {
+		t.Fatalf("LoadProjectFile failed: %v", err)
+	}
+	if reloaded.Ranking.ContextWeight != 0.45 {
+		t.Errorf("reloaded ranking.context_weight = %v, want 0.45", reloaded.Ranking.ContextWeight)
+	}
+
+	if err := SaveProjectKey(path, config, "llm.api_key"); err == nil {
+		t.Error("expected SaveProjectKey to reject a global-only key")
+	}
+}
+
+func TestIsGlobalOnly(t *testing.T) {
+	tests := map[string]bool{
+		"llm.api_key":                   true,
+		"llm.provider":                  true,
+		"llm.daily_budget_usd":          true,
+		"safety.protected_operations":   true,
+		"review.webhook_url":            true,
+		"review.sla":                    false,
+		"profiles.copilot.token_budget": false,
+		"packs.github_token":            true,
+		"backup.retention.max_age":      true,
+		"ranking.context_weight":        false,
+	}
+	for key, want := range tests {
+		if got := IsGlobalOnly(key); got != want {
+			t.Errorf("IsGlobalOnly(%q) = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestScorerConfig(t *testing.T) {
+	config := Default()
+	config.Ranking.ContextWeight = 0.6
+	config.Activation.GraphWeight = 0.2
+
+	sc := config.ScorerConfig(config.Profiles[ProfileCopilot])
+	if sc.ContextWeight != 0.6 || sc.GraphWeight != 0.2 {
+		t.Errorf("ScorerConfig = context %v graph %v, want 0.6 and 0.2", sc.ContextWeight, sc.GraphWeight)
+	}
+
+	config.Ranking.FeedbackWeight = 1.5
+	if err := config.Validate(); err == nil || !strings.Contains(err.Error(), "ranking.feedback_weight") {
+		t.Errorf("expected ranking.feedback_weight validation error, got %v", err)
+	}
+}
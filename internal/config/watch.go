@@ -0,0 +1,45 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Watch polls the file at path every interval and calls onChange when its
+// modification time or size changes, including when it is created or removed.
+// It blocks until ctx is cancelled.
+func Watch(ctx context.Context, path string, interval time.Duration, onChange func()) {
+	last := statFingerprint(path)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			current := statFingerprint(path)
+			if current != last {
+				last = current
+				onChange()
+			}
+		}
+	}
+}
+
+// fileFingerprint identifies a version of a file by size and modification time.
+type fileFingerprint struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+func statFingerprint(path string) fileFingerprint {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fileFingerprint{}
+	}
+	return fileFingerprint{exists: true, size: info.Size(), modTime: info.ModTime()}
+}
@@ -0,0 +1,48 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changed := make(chan struct{}, 4)
+	done := make(chan struct{})
+	go func() {
+		Watch(ctx, path, 10*time.Millisecond, func() { changed <- struct{}{} })
+		close(done)
+	}()
+
+	// Keep growing the file until a change is seen; the watcher may take its
+	// first snapshot after an early write.
+	contents := "logging:\n  level: debug\n"
+	deadline := time.After(2 * time.Second)
+	for seen := false; !seen; {
+		contents += "#\n"
+		if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case <-changed:
+			seen = true
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("no change reported after writing the file")
+		}
+	}
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Watch did not return after cancel")
+	}
+}
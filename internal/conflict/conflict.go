@@ -0,0 +1,199 @@
+// Package conflict detects contradictions between behaviors, such as
+// "always use pip" and "never use pip", and records them in the graph as
+// conflicts edges.
+//
+// Detection is rule-based by default: each behavior's canonical content is
+// split into clauses, each clause gets a polarity (do or don't), and a
+// conflict is reported when one behavior prescribes what the other forbids.
+// An optional LLM client compares topically similar pairs the rules do not
+// flag, catching contradictions that are phrased differently.
+package conflict
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+)
+
+// Method identifies how a conflict was detected.
+type Method string
+
+const (
+	MethodRule Method = "rule"
+	MethodLLM  Method = "llm"
+)
+
+const (
+	// RuleThreshold is the minimum overlap between a prescribing clause and
+	// a forbidding clause for the rules to report a conflict.
+	RuleThreshold = 0.75
+
+	// TopicThreshold is the minimum content similarity for a pair to be
+	// sent to the LLM. Unrelated behaviors cannot contradict each other.
+	TopicThreshold = 0.2
+)
+
+// Conflict is a contradiction between behaviors A and B.
+type Conflict struct {
+	A      string  `json:"a"`
+	B      string  `json:"b"`
+	Reason string  `json:"reason"`
+	Method Method  `json:"method"`
+	Score  float64 `json:"score"`
+}
+
+// Detector finds conflicts between behaviors.
+type Detector struct {
+	client llm.Client
+}
+
+// NewDetector creates a detector. client may be nil, in which case only
+// the rules are used.
+func NewDetector(client llm.Client) *Detector {
+	return &Detector{client: client}
+}
+
+// Check compares two behaviors and returns their conflict, or nil if they
+// do not contradict each other. Behaviors whose when-conditions cannot hold
+// at the same time never conflict. LLM failures are treated as no conflict.
+func (d *Detector) Check(ctx context.Context, a, b *models.Behavior) *Conflict {
+	if a.ID == b.ID || !whenCompatible(a.When, b.When) {
+		return nil
+	}
+	if c := checkRules(a, b); c != nil {
+		return c
+	}
+	if d.client == nil || !d.client.Available() {
+		return nil
+	}
+	if similarity.ComputeContentSimilarity(a.Content.Canonical, b.Content.Canonical) < TopicThreshold {
+		return nil
+	}
+	return d.checkLLM(ctx, a, b)
+}
+
+// Find returns the conflicts between candidate and each existing behavior.
+func (d *Detector) Find(ctx context.Context, candidate *models.Behavior, existing []models.Behavior) []Conflict {
+	var found []Conflict
+	for i := range existing {
+		if c := d.Check(ctx, candidate, &existing[i]); c != nil {
+			found = append(found, *c)
+		}
+	}
+	return found
+}
+
+// Scan returns the conflicts among all pairs of behaviors, ordered by
+// score. It stops early with the conflicts found so far when ctx is done.
+func (d *Detector) Scan(ctx context.Context, behaviors []models.Behavior) []Conflict {
+	var found []Conflict
+	for i := range behaviors {
+		if ctx.Err() != nil {
+			break
+		}
+		for j := i + 1; j < len(behaviors); j++ {
+			if c := d.Check(ctx, &behaviors[i], &behaviors[j]); c != nil {
+				found = append(found, *c)
+			}
+		}
+	}
+	sort.SliceStable(found, func(i, j int) bool { return found[i].Score > found[j].Score })
+	return found
+}
+
+// whenCompatible reports whether two when-predicates can match the same
+// context: every key they share must have a value in common.
+func whenCompatible(a, b map[string]interface{}) bool {
+	for key, va := range a {
+		vb, ok := b[key]
+		if !ok {
+			continue
+		}
+		if !similarity.ValuesEqual(asList(va), asList(vb)) {
+			return false
+		}
+	}
+	return true
+}
+
+// asList wraps scalar values so ValuesEqual treats "go" and ["go", "rust"]
+// as overlapping.
+func asList(v interface{}) interface{} {
+	switch v.(type) {
+	case []interface{}, []string:
+		return v
+	}
+	return []interface{}{v}
+}
+
+// checkLLM asks the LLM whether a and b contradict each other.
+func (d *Detector) checkLLM(ctx context.Context, a, b *models.Behavior) *Conflict {
+	response, err := d.client.Complete(llm.WithPurpose(ctx, llm.PurposeCompare), []llm.Message{{Role: "user", Content: ComparisonPrompt(a, b)}})
+	if err != nil {
+		return nil
+	}
+	result, err := ParseComparisonResponse(response)
+	if err != nil || !result.Conflict {
+		return nil
+	}
+	reason := result.Reason
+	if reason == "" {
+		reason = "LLM judged the behaviors contradictory"
+	}
+	return &Conflict{A: a.ID, B: b.ID, Reason: reason, Method: MethodLLM, Score: 1.0}
+}
+
+// ComparisonResult is the LLM's verdict on a pair of behaviors.
+type ComparisonResult struct {
+	Conflict bool   `json:"conflict"`
+	Reason   string `json:"reason,omitempty"`
+}
+
+// ComparisonPrompt builds the prompt asking whether two behaviors
+// contradict each other.
+//
+// Behavior content is concatenated via strings.Builder rather than
+// interpolated alongside the JSON template, so quotes in the content
+// cannot break the prompt structure.
+func ComparisonPrompt(a, b *models.Behavior) string {
+	var p strings.Builder
+	p.WriteString("You are checking two AI agent behaviors for contradictions.\n\n")
+	fmt.Fprintf(&p, "## Behavior A\nName: %s\nContent: ", a.Name)
+	p.WriteString(a.Content.Canonical)
+	fmt.Fprintf(&p, "\n\n## Behavior B\nName: %s\nContent: ", b.Name)
+	p.WriteString(b.Content.Canonical)
+	p.WriteString(`
+
+## Task
+Decide whether an agent could follow both behaviors in the same situation.
+They conflict only if following one means violating the other. Behaviors
+that are merely different, or where one refines the other, do not conflict.
+
+## Response Format
+Respond with ONLY a JSON object (no markdown code blocks, no additional text):
+{
+  "conflict": <boolean>,
+  "reason": "<one sentence naming the contradiction, or empty>"
+}`)
+	return p.String()
+}
+
+// ParseComparisonResponse parses the LLM's verdict, handling JSON wrapped
+// in markdown code blocks.
+func ParseComparisonResponse(response string) (*ComparisonResult, error) {
+	jsonStr := llm.ExtractJSON(response)
+	if jsonStr == "" {
+		return nil, fmt.Errorf("no JSON found in response")
+	}
+	var result ComparisonResult
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return nil, fmt.Errorf("parsing conflict result: %w", err)
+	}
+	return &result, nil
+}
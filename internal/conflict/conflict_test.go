@@ -0,0 +1,150 @@
+package conflict
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func behavior(id, canonical string, when map[string]interface{}) models.Behavior {
+	return models.Behavior{
+		ID:      id,
+		Name:    id,
+		Kind:    models.BehaviorKindDirective,
+		When:    when,
+		Content: models.BehaviorContent{Canonical: canonical},
+	}
+}
+
+func TestCheckRules(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want bool
+	}{
+		{"always vs never", "Always use pip", "Never use pip", true},
+		{"contraction", "Use pip for installs", "Don't use pip", true},
+		{"avoid", "Prefer fmt.Errorf with %w", "Avoid fmt.Errorf", true},
+		{"instead of", "Use uv instead of pip", "Always use pip", true},
+		{"instead of, both preferred", "Use uv instead of pip", "Use uv for installs", false},
+		{"agreeing negatives", "Never use pip", "Avoid pip", false},
+		{"unrelated", "Always use pip", "Never commit secrets", false},
+		{"separate clauses", "Never use pip, use uv", "Use uv", false},
+		{"partial overlap", "Never use fmt.Println", "Use fmt.Errorf to wrap errors", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := behavior("a", tt.a, nil)
+			b := behavior("b", tt.b, nil)
+			got := checkRules(&a, &b)
+			if (got != nil) != tt.want {
+				t.Fatalf("checkRules(%q, %q) = %+v, want conflict=%v", tt.a, tt.b, got, tt.want)
+			}
+			if got != nil && (got.Method != MethodRule || got.A != "a" || got.B != "b") {
+				t.Errorf("conflict = %+v", got)
+			}
+		})
+	}
+}
+
+func TestCheck_WhenConditions(t *testing.T) {
+	ctx := context.Background()
+	d := NewDetector(nil)
+	tests := []struct {
+		name   string
+		wa, wb map[string]interface{}
+		want   bool
+	}{
+		{"no conditions", nil, nil, true},
+		{"same language", map[string]interface{}{"language": "python"}, map[string]interface{}{"language": "python"}, true},
+		{"different language", map[string]interface{}{"language": "python"}, map[string]interface{}{"language": "go"}, false},
+		{"list overlap", map[string]interface{}{"language": "python"}, map[string]interface{}{"language": []interface{}{"go", "python"}}, true},
+		{"orthogonal keys", map[string]interface{}{"language": "python"}, map[string]interface{}{"task": "testing"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := behavior("a", "Always use pip", tt.wa)
+			b := behavior("b", "Never use pip", tt.wb)
+			if got := d.Check(ctx, &a, &b); (got != nil) != tt.want {
+				t.Errorf("Check() = %+v, want conflict=%v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_LLM(t *testing.T) {
+	ctx := context.Background()
+	a := behavior("a", "Write table-driven tests for parser functions", nil)
+	b := behavior("b", "Write a separate test function for each parser case", nil)
+	unrelated := behavior("c", "Sign commits with GPG", nil)
+
+	client := llm.NewMockClient().WithCompleteResponse("```json\n{\"conflict\": true, \"reason\": \"table-driven vs one function per case\"}\n```")
+	d := NewDetector(client)
+
+	got := d.Check(ctx, &a, &b)
+	if got == nil || got.Method != MethodLLM || got.Reason != "table-driven vs one function per case" {
+		t.Fatalf("Check() = %+v, want LLM conflict", got)
+	}
+	if !strings.Contains(client.CompleteCalls[0].Messages[0].Content, "separate test function for each parser case") {
+		t.Error("prompt does not include behavior content")
+	}
+
+	if got := d.Check(ctx, &a, &unrelated); got != nil {
+		t.Errorf("Check() on unrelated pair = %+v, want nil", got)
+	}
+	if len(client.CompleteCalls) != 1 {
+		t.Errorf("LLM called %d times, want 1 (unrelated pairs are skipped)", len(client.CompleteCalls))
+	}
+
+	failing := NewDetector(llm.NewMockClient().WithError(errors.New("boom")))
+	if got := failing.Check(ctx, &a, &b); got != nil {
+		t.Errorf("Check() with failing LLM = %+v, want nil", got)
+	}
+}
+
+func TestScanAndRecord(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	behaviors := []models.Behavior{
+		behavior("pip", "Always use pip", nil),
+		behavior("no-pip", "Never use pip", nil),
+		behavior("secrets", "Never commit secrets", nil),
+	}
+	for i := range behaviors {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&behaviors[i])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	found := NewDetector(nil).Scan(ctx, behaviors)
+	if len(found) != 1 || found[0].A != "pip" || found[0].B != "no-pip" {
+		t.Fatalf("Scan() = %+v, want pip <-> no-pip", found)
+	}
+
+	n, err := Record(ctx, s, found, time.Now())
+	if err != nil || n != 1 {
+		t.Fatalf("Record() = %d, %v; want 1", n, err)
+	}
+	edges, _ := s.GetEdges(ctx, "pip", store.DirectionOutbound, store.EdgeKindConflicts)
+	if len(edges) != 1 || edges[0].Target != "no-pip" {
+		t.Errorf("conflicts edges = %+v", edges)
+	}
+	for id, want := range map[string]string{"pip": "no-pip", "no-pip": "pip"} {
+		node, _ := s.GetNode(ctx, id)
+		if got := models.NodeToBehavior(*node).Conflicts; len(got) != 1 || got[0] != want {
+			t.Errorf("%s conflicts = %v, want [%s]", id, got, want)
+		}
+	}
+
+	// Recording again, or in the reverse direction, is a no-op.
+	reversed := []Conflict{{A: "no-pip", B: "pip", Method: MethodRule, Score: 1}}
+	if n, err := Record(ctx, s, append(found, reversed...), time.Now()); err != nil || n != 0 {
+		t.Errorf("second Record() = %d, %v; want 0", n, err)
+	}
+}
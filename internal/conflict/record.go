@@ -0,0 +1,83 @@
+package conflict
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Record writes conflicts to the graph: a conflicts edge from A to B, and
+// each ID in the other behavior's conflicts list so conflict resolution
+// sees it. Pairs already joined by a conflicts edge in either direction are
+// skipped. Returns the number of conflicts newly recorded.
+func Record(ctx context.Context, s store.GraphStore, conflicts []Conflict, now time.Time) (int, error) {
+	recorded := 0
+	for _, c := range conflicts {
+		exists, err := Linked(ctx, s, c.A, c.B)
+		if err != nil {
+			return recorded, err
+		}
+		if exists {
+			continue
+		}
+		if err := s.AddEdge(ctx, store.Edge{
+			Source:    c.A,
+			Target:    c.B,
+			Kind:      store.EdgeKindConflicts,
+			Weight:    c.Score,
+			CreatedAt: now,
+			Metadata: map[string]interface{}{
+				"method": string(c.Method),
+				"reason": c.Reason,
+			},
+		}); err != nil {
+			return recorded, fmt.Errorf("adding conflicts edge %s -> %s: %w", c.A, c.B, err)
+		}
+		if err := addConflictRef(ctx, s, c.A, c.B); err != nil {
+			return recorded, err
+		}
+		if err := addConflictRef(ctx, s, c.B, c.A); err != nil {
+			return recorded, err
+		}
+		recorded++
+	}
+	return recorded, nil
+}
+
+// Linked reports whether a conflicts edge joins a and b in either direction.
+func Linked(ctx context.Context, s store.GraphStore, a, b string) (bool, error) {
+	edges, err := s.GetEdges(ctx, a, store.DirectionBoth, store.EdgeKindConflicts)
+	if err != nil {
+		return false, fmt.Errorf("getting conflicts edges for %s: %w", a, err)
+	}
+	for _, e := range edges {
+		if e.Source == b || e.Target == b {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// addConflictRef appends other to the conflicts list of behavior id.
+func addConflictRef(ctx context.Context, s store.GraphStore, id, other string) error {
+	node, err := s.GetNode(ctx, id)
+	if err != nil {
+		return fmt.Errorf("getting behavior %s: %w", id, err)
+	}
+	if node == nil {
+		return nil
+	}
+	existing := models.NodeToBehavior(*node).Conflicts
+	if slices.Contains(existing, other) {
+		return nil
+	}
+	node.Content["conflicts"] = append(existing, other)
+	if err := s.UpdateNode(ctx, *node); err != nil {
+		return fmt.Errorf("updating behavior %s: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package conflict
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
+)
+
+// clause is a fragment of a behavior that prescribes (positive) or forbids
+// (negative) the actions named by its terms.
+type clause struct {
+	text     string
+	positive bool
+	terms    map[string]bool
+}
+
+// negators flip a clause to forbidding. Contractions are expanded to
+// "not" before matching.
+var negators = map[string]bool{
+	"never": true, "not": true, "no": true, "avoid": true,
+	"stop": true, "without": true, "forbid": true, "forbidden": true,
+	"prohibited": true,
+}
+
+// fillers carry polarity or grammar but not the action itself, so they are
+// ignored when comparing clauses.
+var fillers = map[string]bool{
+	"always": true, "use": true, "uses": true, "using": true, "do": true,
+	"does": true, "must": true, "should": true, "prefer": true, "please": true,
+	"instead": true, "make": true, "sure": true, "ever": true,
+	"the": true, "a": true, "an": true, "to": true, "for": true, "in": true,
+	"of": true, "on": true, "with": true, "and": true, "or": true, "when": true,
+	"it": true, "this": true, "that": true, "be": true, "is": true, "are": true,
+	"you": true, "your": true, "we": true, "all": true, "any": true,
+}
+
+// clauseSplit separates independent statements within a behavior. Periods
+// only end a sentence when followed by whitespace, so "fmt.Println" stays
+// whole.
+var clauseSplit = regexp.MustCompile(`[.!?]+(?:\s+|$)|[;:,\n]+|\bbut\b|\band then\b`)
+
+// contrast splits "X instead of Y": X and Y take opposite polarities.
+var contrast = regexp.MustCompile(`\b(?:instead of|rather than)\b`)
+
+// contraction expands "don't", "shouldn't", etc. so "not" is its own token.
+var contraction = regexp.MustCompile(`n['’]t\b`)
+
+// clauses breaks a behavior's canonical content into polarized clauses.
+// Clauses with no remaining terms are dropped.
+func clauses(text string) []clause {
+	text = contraction.ReplaceAllString(strings.ToLower(text), " not")
+	var out []clause
+	for _, part := range clauseSplit.Split(text, -1) {
+		sides := contrast.Split(part, 2)
+		first := newClause(sides[0])
+		if len(first.terms) > 0 {
+			out = append(out, first)
+		}
+		if len(sides) == 2 {
+			// The rejected alternative reads as a plain noun phrase
+			// ("os.path"), so its polarity comes from the first side.
+			second := newClause(sides[1])
+			second.positive = !first.positive
+			if len(second.terms) > 0 {
+				out = append(out, second)
+			}
+		}
+	}
+	return out
+}
+
+func newClause(text string) clause {
+	c := clause{text: strings.TrimSpace(text), positive: true, terms: make(map[string]bool)}
+	for _, tok := range similarity.Tokenize(text) {
+		switch {
+		case negators[tok]:
+			c.positive = false
+		case fillers[tok]:
+		default:
+			c.terms[tok] = true
+		}
+	}
+	return c
+}
+
+// overlap is the overlap coefficient of two term sets: shared terms over
+// the size of the smaller set. "use pip" and "never use pip for installs"
+// overlap fully.
+func overlap(a, b map[string]bool) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if b[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(min(len(a), len(b)))
+}
+
+// checkRules reports a conflict when a clause of one behavior prescribes
+// what a clause of the other forbids.
+func checkRules(a, b *models.Behavior) *Conflict {
+	ca, cb := clauses(a.Content.Canonical), clauses(b.Content.Canonical)
+	var best *Conflict
+	for _, x := range ca {
+		for _, y := range cb {
+			if x.positive == y.positive {
+				continue
+			}
+			score := overlap(x.terms, y.terms)
+			if score < RuleThreshold || (best != nil && score <= best.Score) {
+				continue
+			}
+			best = &Conflict{
+				A:      a.ID,
+				B:      b.ID,
+				Reason: fmt.Sprintf("%q contradicts %q", x.text, y.text),
+				Method: MethodRule,
+				Score:  score,
+			}
+		}
+	}
+	return best
+}
@@ -67,6 +67,7 @@ func (c *LLMConsolidator) Extract(ctx context.Context, evts []events.Event) ([]C
 	if len(evts) == 0 {
 		return nil, nil
 	}
+	ctx = llm.WithPurpose(ctx, llm.PurposeExtract)
 
 	chunkSize := c.config.ChunkSize
 	if chunkSize <= 0 {
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/confidence"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
 )
@@ -189,7 +190,7 @@ func (c *LLMConsolidator) executeAbsorb(ctx context.Context, merge MergeProposal
 	maxConf := oldConf
 	if newConf > oldConf {
 		maxConf = newConf
-		node.Metadata["confidence"] = newConf
+		confidence.Adjust(node.Metadata, confidence.Recalibration, newConf)
 	}
 
 	prov, _ := node.Metadata["provenance"].(map[string]interface{})
@@ -356,6 +357,7 @@ func (c *LLMConsolidator) buildPromoteNode(mem ClassifiedMemory, runID string, b
 		"provenance":        prov,
 		"consolidation_run": runID,
 	}
+	confidence.SetBase(metadata, mem.Confidence, confidence.OriginConsolidation)
 
 	return store.Node{
 		ID:   fmt.Sprintf("consolidated-%d-%d", baseTS, index),
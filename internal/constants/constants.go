@@ -19,6 +19,10 @@ const (
 	// Learned behaviors start with lower confidence than manually defined ones.
 	DefaultLearnedConfidence = 0.6
 
+	// DefaultManualConfidence is the starting confidence for hand-authored
+	// behaviors, which a person stated deliberately.
+	DefaultManualConfidence = 0.8
+
 	// DefaultSimilarityThreshold is the threshold for considering two behaviors duplicates.
 	// Values above this threshold indicate high semantic similarity.
 	DefaultSimilarityThreshold = 0.95
@@ -14,6 +14,11 @@ const (
 	// Used for read/query operations (dedup --scope both, validate --scope both).
 	// Not a valid write scope — each behavior belongs to exactly one store.
 	ScopeBoth Scope = "both"
+
+	// ScopeTeam marks a behavior in the local store that is shared with
+	// teammates through .floop/team.jsonl (see 'floop sync'). It is a
+	// behavior's scope, not a store: Valid does not accept it.
+	ScopeTeam Scope = "team"
 )
 
 // Valid returns true if the scope is a recognized value.
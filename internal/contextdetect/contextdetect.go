@@ -0,0 +1,323 @@
+// Package contextdetect fingerprints a project from the manifests, build
+// files, container files, and CI configuration at its root, so behaviors
+// can be scoped to a framework or toolchain rather than a file type.
+package contextdetect
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// Fingerprint describes the project-level context found at a root.
+// Every list is sorted and free of duplicates.
+type Fingerprint struct {
+	Languages       []string `json:"languages,omitempty"`
+	Frameworks      []string `json:"frameworks,omitempty"`
+	Build           []string `json:"build,omitempty"`
+	PackageManagers []string `json:"package_managers,omitempty"`
+	CI              []string `json:"ci,omitempty"`
+	Containers      []string `json:"containers,omitempty"`
+}
+
+// dependency maps a dependency name in a manifest to the framework it
+// indicates.
+type dependency struct {
+	name      string
+	framework string
+}
+
+var (
+	goDependencies = []dependency{
+		{"github.com/gin-gonic/gin", "gin"},
+		{"github.com/labstack/echo", "echo"},
+		{"github.com/gofiber/fiber", "fiber"},
+		{"github.com/go-chi/chi", "chi"},
+		{"github.com/gorilla/mux", "gorilla"},
+		{"github.com/spf13/cobra", "cobra"},
+		{"google.golang.org/grpc", "grpc"},
+	}
+	nodeDependencies = []dependency{
+		{"react", "react"},
+		{"next", "nextjs"},
+		{"vue", "vue"},
+		{"nuxt", "nuxt"},
+		{"svelte", "svelte"},
+		{"@angular/core", "angular"},
+		{"express", "express"},
+		{"@nestjs/core", "nestjs"},
+		{"electron", "electron"},
+		{"jest", "jest"},
+		{"vitest", "vitest"},
+	}
+	nodeBuildTools = []dependency{
+		{"vite", "vite"},
+		{"webpack", "webpack"},
+		{"esbuild", "esbuild"},
+		{"turbo", "turborepo"},
+	}
+	pythonDependencies = []dependency{
+		{"django", "django"},
+		{"flask", "flask"},
+		{"fastapi", "fastapi"},
+		{"pytest", "pytest"},
+		{"pandas", "pandas"},
+		{"torch", "pytorch"},
+	}
+	rustDependencies = []dependency{
+		{"actix-web", "actix"},
+		{"axum", "axum"},
+		{"rocket", "rocket"},
+		{"tokio", "tokio"},
+	}
+	jvmDependencies = []dependency{
+		{"spring-boot", "spring"},
+		{"quarkus", "quarkus"},
+		{"micronaut", "micronaut"},
+	}
+)
+
+// markers maps files (or directories, with a trailing slash) at the root
+// to the fingerprint entries they imply.
+var markers = []struct {
+	path string
+	add  func(*Fingerprint)
+}{
+	{"Makefile", build("make")},
+	{"makefile", build("make")},
+	{"GNUmakefile", build("make")},
+	{"justfile", build("just")},
+	{"Taskfile.yml", build("task")},
+	{"CMakeLists.txt", build("cmake")},
+	{"WORKSPACE", build("bazel")},
+	{"MODULE.bazel", build("bazel")},
+	{"pom.xml", func(f *Fingerprint) {
+		f.Languages = append(f.Languages, "java")
+		f.Build = append(f.Build, "maven")
+	}},
+	{"build.gradle", func(f *Fingerprint) {
+		f.Languages = append(f.Languages, "java")
+		f.Build = append(f.Build, "gradle")
+	}},
+	{"build.gradle.kts", func(f *Fingerprint) {
+		f.Languages = append(f.Languages, "kotlin")
+		f.Build = append(f.Build, "gradle")
+	}},
+	{"Gemfile", func(f *Fingerprint) {
+		f.Languages = append(f.Languages, "ruby")
+		f.PackageManagers = append(f.PackageManagers, "bundler")
+	}},
+	{"Dockerfile", container("docker")},
+	{"Containerfile", container("docker")},
+	{"docker-compose.yml", container("compose")},
+	{"docker-compose.yaml", container("compose")},
+	{"compose.yml", container("compose")},
+	{"compose.yaml", container("compose")},
+	{"Chart.yaml", container("helm")},
+	{".github/workflows/", ci("github-actions")},
+	{".gitlab-ci.yml", ci("gitlab-ci")},
+	{".circleci/", ci("circleci")},
+	{"Jenkinsfile", ci("jenkins")},
+	{"azure-pipelines.yml", ci("azure-pipelines")},
+	{".travis.yml", ci("travis")},
+	{"bitbucket-pipelines.yml", ci("bitbucket-pipelines")},
+	{".buildkite/", ci("buildkite")},
+}
+
+func build(name string) func(*Fingerprint) {
+	return func(f *Fingerprint) { f.Build = append(f.Build, name) }
+}
+
+func container(name string) func(*Fingerprint) {
+	return func(f *Fingerprint) { f.Containers = append(f.Containers, name) }
+}
+
+func ci(name string) func(*Fingerprint) {
+	return func(f *Fingerprint) { f.CI = append(f.CI, name) }
+}
+
+// Detect fingerprints the project at root. Missing or unreadable files are
+// skipped, so Detect never fails; an empty Fingerprint means nothing was
+// recognized.
+func Detect(root string) Fingerprint {
+	var f Fingerprint
+
+	if data, ok := readFile(root, "go.mod"); ok {
+		f.Languages = append(f.Languages, "go")
+		f.Build = append(f.Build, "go")
+		f.Frameworks = append(f.Frameworks, goFrameworks(data)...)
+	}
+	if data, ok := readFile(root, "package.json"); ok {
+		detectNode(root, data, &f)
+	}
+	detectPython(root, &f)
+	if data, ok := readFile(root, "Cargo.toml"); ok {
+		f.Languages = append(f.Languages, "rust")
+		f.Build = append(f.Build, "cargo")
+		f.Frameworks = append(f.Frameworks, matchDependencies(data, rustDependencies)...)
+	}
+	for _, name := range []string{"pom.xml", "build.gradle", "build.gradle.kts"} {
+		if data, ok := readFile(root, name); ok {
+			// JVM artifacts carry suffixes (spring-boot-starter-web), so
+			// match on the name alone.
+			for _, dep := range jvmDependencies {
+				if strings.Contains(data, dep.name) {
+					f.Frameworks = append(f.Frameworks, dep.framework)
+				}
+			}
+		}
+	}
+	if data, ok := readFile(root, "Gemfile"); ok && containsWord(data, "rails") {
+		f.Frameworks = append(f.Frameworks, "rails")
+	}
+
+	for _, m := range markers {
+		if exists(root, m.path) {
+			m.add(&f)
+		}
+	}
+
+	for _, list := range []*[]string{&f.Languages, &f.Frameworks, &f.Build, &f.PackageManagers, &f.CI, &f.Containers} {
+		*list = normalize(*list)
+	}
+	return f
+}
+
+// Apply copies the fingerprint into ctx.
+func (f Fingerprint) Apply(ctx *models.ContextSnapshot) {
+	ctx.ProjectLanguages = f.Languages
+	ctx.Frameworks = f.Frameworks
+	ctx.BuildTools = f.Build
+	ctx.PackageManagers = f.PackageManagers
+	ctx.CISystems = f.CI
+	ctx.Containers = f.Containers
+}
+
+// goRequire matches a module path in a go.mod require line or block.
+var goRequire = regexp.MustCompile(`(?m)^\s*(?:require\s+)?([\w.\-]+(?:/[\w.\-]+)+)\s+v.*$`)
+
+// goFrameworks returns the frameworks among the direct requirements of a
+// go.mod file.
+func goFrameworks(data string) []string {
+	var found []string
+	for _, m := range goRequire.FindAllStringSubmatch(data, -1) {
+		if strings.HasSuffix(m[0], "// indirect") {
+			continue
+		}
+		for _, dep := range goDependencies {
+			if m[1] == dep.name || strings.HasPrefix(m[1], dep.name+"/") {
+				found = append(found, dep.framework)
+			}
+		}
+	}
+	return found
+}
+
+func detectNode(root, data string, f *Fingerprint) {
+	f.Languages = append(f.Languages, "javascript")
+	if exists(root, "tsconfig.json") || strings.Contains(data, `"typescript"`) {
+		f.Languages = append(f.Languages, "typescript")
+	}
+	for _, dep := range nodeDependencies {
+		if strings.Contains(data, `"`+dep.name+`"`) {
+			f.Frameworks = append(f.Frameworks, dep.framework)
+		}
+	}
+	for _, dep := range nodeBuildTools {
+		if strings.Contains(data, `"`+dep.name+`"`) {
+			f.Build = append(f.Build, dep.framework)
+		}
+	}
+
+	switch {
+	case exists(root, "pnpm-lock.yaml"):
+		f.PackageManagers = append(f.PackageManagers, "pnpm")
+	case exists(root, "yarn.lock"):
+		f.PackageManagers = append(f.PackageManagers, "yarn")
+	case exists(root, "bun.lockb"), exists(root, "bun.lock"):
+		f.PackageManagers = append(f.PackageManagers, "bun")
+	default:
+		f.PackageManagers = append(f.PackageManagers, "npm")
+	}
+}
+
+func detectPython(root string, f *Fingerprint) {
+	var manifests []string
+	for _, name := range []string{"pyproject.toml", "requirements.txt", "setup.py", "Pipfile"} {
+		if data, ok := readFile(root, name); ok {
+			manifests = append(manifests, data)
+		}
+	}
+	if len(manifests) == 0 {
+		return
+	}
+	f.Languages = append(f.Languages, "python")
+	f.Frameworks = append(f.Frameworks, matchDependencies(strings.Join(manifests, "\n"), pythonDependencies)...)
+
+	switch {
+	case exists(root, "uv.lock"):
+		f.PackageManagers = append(f.PackageManagers, "uv")
+	case exists(root, "poetry.lock"), strings.Contains(manifests[0], "[tool.poetry"):
+		f.PackageManagers = append(f.PackageManagers, "poetry")
+	case exists(root, "Pipfile"):
+		f.PackageManagers = append(f.PackageManagers, "pipenv")
+	default:
+		f.PackageManagers = append(f.PackageManagers, "pip")
+	}
+}
+
+// matchDependencies returns the frameworks whose dependency name appears
+// as a whole word in a manifest.
+func matchDependencies(data string, deps []dependency) []string {
+	var found []string
+	for _, dep := range deps {
+		if containsWord(data, dep.name) {
+			found = append(found, dep.framework)
+		}
+	}
+	return found
+}
+
+// containsWord reports whether word appears in data, case-insensitively,
+// not as part of a longer identifier. Hyphens and underscores count as
+// identifier characters, so "flask" does not match "flask-cors".
+func containsWord(data, word string) bool {
+	re := regexp.MustCompile(`(?i)(^|[^\w\-])` + regexp.QuoteMeta(word) + `($|[^\w\-])`)
+	return re.MatchString(data)
+}
+
+func readFile(root, name string) (string, bool) {
+	data, err := os.ReadFile(filepath.Join(root, name))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// exists reports whether the file, or with a trailing slash the
+// directory, exists under root.
+func exists(root, path string) bool {
+	info, err := os.Stat(filepath.Join(root, filepath.FromSlash(path)))
+	if err != nil {
+		return false
+	}
+	return info.IsDir() == strings.HasSuffix(path, "/")
+}
+
+func normalize(list []string) []string {
+	if len(list) == 0 {
+		return nil
+	}
+	sort.Strings(list)
+	out := list[:1]
+	for _, s := range list[1:] {
+		if s != out[len(out)-1] {
+			out = append(out, s)
+		}
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package contextdetect
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  Fingerprint
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name: "go service",
+			files: map[string]string{
+				"go.mod":                   "module example.com/svc\n\ngo 1.22\n\nrequire (\n\tgithub.com/go-chi/chi/v5 v5.0.0\n\tgithub.com/spf13/cobra v1.8.0\n\tgoogle.golang.org/grpc v1.60.0 // indirect\n)\n",
+				"Makefile":                 "build:\n\tgo build ./...\n",
+				"Dockerfile":               "FROM golang\n",
+				".github/workflows/ci.yml": "on: push\n",
+			},
+			want: Fingerprint{
+				Languages:  []string{"go"},
+				Frameworks: []string{"chi", "cobra"},
+				Build:      []string{"go", "make"},
+				CI:         []string{"github-actions"},
+				Containers: []string{"docker"},
+			},
+		},
+		{
+			name: "react app",
+			files: map[string]string{
+				"package.json":   `{"dependencies": {"react": "^18.0.0"}, "devDependencies": {"typescript": "^5", "vite": "^5"}}`,
+				"pnpm-lock.yaml": "",
+				".gitlab-ci.yml": "test:\n  script: pnpm test\n",
+			},
+			want: Fingerprint{
+				Languages:       []string{"javascript", "typescript"},
+				Frameworks:      []string{"react"},
+				Build:           []string{"vite"},
+				PackageManagers: []string{"pnpm"},
+				CI:              []string{"gitlab-ci"},
+			},
+		},
+		{
+			name: "python with poetry",
+			files: map[string]string{
+				"pyproject.toml":     "[tool.poetry.dependencies]\npython = \"^3.12\"\nfastapi = \"^0.110\"\nflask-cors = \"*\"\n",
+				"docker-compose.yml": "services: {}\n",
+			},
+			want: Fingerprint{
+				Languages:       []string{"python"},
+				Frameworks:      []string{"fastapi"},
+				PackageManagers: []string{"poetry"},
+				Containers:      []string{"compose"},
+			},
+		},
+		{
+			name: "spring with maven",
+			files: map[string]string{
+				"pom.xml": "<artifactId>spring-boot-starter-web</artifactId>",
+			},
+			want: Fingerprint{
+				Languages:  []string{"java"},
+				Frameworks: []string{"spring"},
+				Build:      []string{"maven"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(root, filepath.FromSlash(name))
+				if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+					t.Fatal(err)
+				}
+				if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if got := Detect(root); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Detect() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFingerprint_Apply(t *testing.T) {
+	var ctx models.ContextSnapshot
+	Fingerprint{Frameworks: []string{"react"}, Build: []string{"make"}}.Apply(&ctx)
+
+	if matched, _ := ctx.MatchField("framework", "react"); !matched {
+		t.Error("framework: react did not match")
+	}
+	if matched, hasValue := ctx.MatchField("build", "bazel"); matched || !hasValue {
+		t.Errorf("build: bazel = (%v, %v), want contradicted", matched, hasValue)
+	}
+	if _, hasValue := ctx.MatchField("ci", "github-actions"); hasValue {
+		t.Error("ci reported a value, want absent")
+	}
+}
@@ -0,0 +1,344 @@
+// Package corrections keeps a project's correction log (corrections.jsonl)
+// from growing forever: compaction moves corrections past the retention
+// limits into a gzip archive next to it.
+package corrections
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/utils"
+)
+
+const (
+	// File is the name of the correction log in .floop/.
+	File = "corrections.jsonl"
+
+	// ArchiveFile is the name of the gzip archive in .floop/ that compacted
+	// corrections are appended to. Each compaction adds a gzip member, so
+	// the archive reads back as one JSONL stream (zcat, gzip.Reader).
+	ArchiveFile = "corrections-archive.jsonl.gz"
+
+	// StateFile is the name of the file in .floop/ recording the last
+	// compaction.
+	StateFile = "corrections-state.json"
+)
+
+// compactMu serializes compactions within the process, so concurrent
+// automatic runs cannot archive the same corrections twice.
+var compactMu sync.Mutex
+
+// Options configures a compaction.
+type Options struct {
+	// MaxAge archives corrections older than this. Zero keeps all ages.
+	MaxAge time.Duration
+
+	// MaxCount archives the oldest corrections beyond this many. Zero keeps
+	// any number.
+	MaxCount int
+
+	// ProcessedOnly never archives corrections that have not been turned
+	// into behaviors yet, even past the limits.
+	ProcessedOnly bool
+
+	// DryRun reports what would be archived without changing any file.
+	DryRun bool
+
+	// Now is the reference time; zero uses time.Now.
+	Now time.Time
+}
+
+// Report summarizes a compaction.
+type Report struct {
+	DryRun      bool                `json:"dry_run"`
+	Examined    int                 `json:"examined"`
+	Kept        int                 `json:"kept"`
+	Archived    []models.Correction `json:"archived"`
+	ArchivePath string              `json:"archive_path,omitempty"`
+	RanAt       time.Time           `json:"ran_at"`
+}
+
+// OptionsFromConfig returns compaction options for the corrections settings.
+func OptionsFromConfig(cfg config.CorrectionsConfig) (Options, error) {
+	opts := Options{MaxCount: cfg.MaxCount, ProcessedOnly: cfg.ProcessedOnly}
+	if cfg.MaxAge != "" {
+		maxAge, err := utils.ParseDuration(cfg.MaxAge)
+		if err != nil {
+			return Options{}, fmt.Errorf("corrections.max_age: %w", err)
+		}
+		opts.MaxAge = maxAge
+	}
+	return opts, nil
+}
+
+// entry is one line of the correction log. Lines that do not parse are
+// kept as they are and never archived.
+type entry struct {
+	line       []byte
+	correction models.Correction
+	valid      bool
+}
+
+// Compact archives the corrections in floopDir's log that fall outside the
+// retention limits, oldest first, and rewrites the log with the rest. The
+// archive is written before the log is replaced, so a failure can at worst
+// leave a correction in both. Corrections appended while compaction runs
+// are kept.
+func Compact(floopDir string, opts Options) (*Report, error) {
+	compactMu.Lock()
+	defer compactMu.Unlock()
+
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	report := &Report{DryRun: opts.DryRun, RanAt: now}
+
+	logPath := filepath.Join(floopDir, File)
+	data, err := os.ReadFile(logPath)
+	if os.IsNotExist(err) {
+		return report, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corrections: %w", err)
+	}
+
+	entries := parseLog(data)
+	archive := selectArchived(entries, opts, now)
+	for _, e := range entries {
+		if e.valid {
+			report.Examined++
+		}
+	}
+	report.Kept = report.Examined - len(archive)
+	if len(archive) == 0 {
+		return report, nil
+	}
+
+	var archived, kept bytes.Buffer
+	for i, e := range entries {
+		if archive[i] {
+			archived.Write(e.line)
+			archived.WriteByte('\n')
+			report.Archived = append(report.Archived, e.correction)
+		} else {
+			kept.Write(e.line)
+			kept.WriteByte('\n')
+		}
+	}
+	if opts.DryRun {
+		return report, nil
+	}
+
+	archivePath := filepath.Join(floopDir, ArchiveFile)
+	if err := appendArchive(archivePath, archived.Bytes()); err != nil {
+		return nil, err
+	}
+	report.ArchivePath = archivePath
+
+	// Keep whatever was appended to the log since it was read
+	if current, err := os.ReadFile(logPath); err == nil && len(current) > len(data) && bytes.HasPrefix(current, data) {
+		kept.Write(current[len(data):])
+	}
+	if err := replaceFile(logPath, kept.Bytes()); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// parseLog splits the correction log into entries, skipping blank lines.
+func parseLog(data []byte) []entry {
+	var entries []entry
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		e := entry{line: line}
+		e.valid = json.Unmarshal(line, &e.correction) == nil
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// selectArchived returns the indexes of the entries to archive: eligible
+// corrections older than MaxAge, then the oldest eligible ones until at
+// most MaxCount corrections remain.
+func selectArchived(entries []entry, opts Options, now time.Time) map[int]bool {
+	var eligible []int
+	for i, e := range entries {
+		if e.valid && (e.correction.Processed || !opts.ProcessedOnly) {
+			eligible = append(eligible, i)
+		}
+	}
+	sort.SliceStable(eligible, func(a, b int) bool {
+		return entries[eligible[a]].correction.Timestamp.Before(entries[eligible[b]].correction.Timestamp)
+	})
+
+	archive := make(map[int]bool)
+	if opts.MaxAge > 0 {
+		cutoff := now.Add(-opts.MaxAge)
+		for _, i := range eligible {
+			if entries[i].correction.Timestamp.Before(cutoff) {
+				archive[i] = true
+			}
+		}
+	}
+	if opts.MaxCount > 0 {
+		remaining := 0
+		for _, e := range entries {
+			if e.valid {
+				remaining++
+			}
+		}
+		remaining -= len(archive)
+		for _, i := range eligible {
+			if remaining <= opts.MaxCount {
+				break
+			}
+			if !archive[i] {
+				archive[i] = true
+				remaining--
+			}
+		}
+	}
+	return archive
+}
+
+// appendArchive appends lines to the archive at path as a new gzip member.
+func appendArchive(path string, lines []byte) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open corrections archive: %w", err)
+	}
+	gz := gzip.NewWriter(f)
+	if _, err := gz.Write(lines); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write corrections archive: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write corrections archive: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to sync corrections archive: %w", err)
+	}
+	return f.Close()
+}
+
+// replaceFile atomically replaces the file at path with data.
+func replaceFile(path string, data []byte) error {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0600); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write corrections: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to update corrections: %w", err)
+	}
+	return nil
+}
+
+// ReadArchive returns the corrections archived in floopDir, oldest
+// compaction first. A missing archive yields none.
+func ReadArchive(floopDir string) ([]models.Correction, error) {
+	f, err := os.Open(filepath.Join(floopDir, ArchiveFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open corrections archive: %w", err)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read corrections archive: %w", err)
+	}
+	defer gz.Close()
+
+	var out []models.Correction
+	dec := json.NewDecoder(gz)
+	for dec.More() {
+		var c models.Correction
+		if err := dec.Decode(&c); err != nil {
+			return nil, fmt.Errorf("failed to read corrections archive: %w", err)
+		}
+		out = append(out, c)
+	}
+	return out, nil
+}
+
+type state struct {
+	LastRun  time.Time `json:"last_run"`
+	Archived int       `json:"archived"`
+}
+
+// Due reports whether automatic compaction should run: interval has elapsed
+// since the last compaction recorded in floopDir, or it has never run. A
+// non-positive interval disables automatic compaction.
+func Due(floopDir string, interval time.Duration, now time.Time) bool {
+	if interval <= 0 {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(floopDir, StateFile))
+	if err != nil {
+		return true
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return true
+	}
+	return now.Sub(st.LastRun) >= interval
+}
+
+// MarkRun records a completed compaction in floopDir.
+func MarkRun(floopDir string, report *Report) error {
+	data, err := json.Marshal(state{LastRun: report.RanAt, Archived: len(report.Archived)})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(floopDir, StateFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to record corrections compaction: %w", err)
+	}
+	return nil
+}
+
+// autoMu makes checking and recording a due automatic compaction atomic.
+var autoMu sync.Mutex
+
+// AutoCompact compacts floopDir's correction log with the configured
+// retention if corrections.compact_interval has elapsed since the last
+// compaction. It returns nil when compaction was not due.
+func AutoCompact(floopDir string, cfg config.CorrectionsConfig, now time.Time) (*Report, error) {
+	autoMu.Lock()
+	defer autoMu.Unlock()
+
+	interval, err := utils.ParseDuration(cfg.CompactInterval)
+	if err != nil || !Due(floopDir, interval, now) {
+		return nil, nil
+	}
+	opts, err := OptionsFromConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	opts.Now = now
+	report, err := Compact(floopDir, opts)
+	if err != nil {
+		return nil, err
+	}
+	if err := MarkRun(floopDir, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
@@ -0,0 +1,174 @@
+package corrections
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+var testNow = time.Date(2026, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// writeLog writes a correction log to a temp .floop dir: one correction per
+// age in days, processed unless its age is negative (then unprocessed, |age|
+// days old), plus a line that does not parse.
+func writeLog(t *testing.T, ages ...int) string {
+	t.Helper()
+	dir := t.TempDir()
+	var lines []string
+	for i, age := range ages {
+		c := models.Correction{ID: "c" + string(rune('a'+i)), Processed: age >= 0}
+		if age < 0 {
+			age = -age
+		}
+		c.Timestamp = testNow.AddDate(0, 0, -age)
+		data, err := json.Marshal(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		lines = append(lines, string(data))
+	}
+	lines = append(lines, "not json")
+	if err := os.WriteFile(filepath.Join(dir, File), []byte(strings.Join(lines, "\n")+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func logIDs(t *testing.T, dir string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(dir, File))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, e := range parseLog(data) {
+		if e.valid {
+			ids = append(ids, e.correction.ID)
+		} else {
+			ids = append(ids, string(e.line))
+		}
+	}
+	return ids
+}
+
+func TestCompact(t *testing.T) {
+	tests := []struct {
+		name     string
+		ages     []int
+		opts     Options
+		wantKept []string
+	}{
+		{"max age", []int{400, 10, 200, 1}, Options{MaxAge: 180 * 24 * time.Hour, ProcessedOnly: true},
+			[]string{"cb", "cd", "not json"}},
+		{"max count keeps newest", []int{3, 1, 2, 4}, Options{MaxCount: 2, ProcessedOnly: true},
+			[]string{"cb", "cc", "not json"}},
+		{"processed only", []int{-400, 400, 1}, Options{MaxAge: 24 * time.Hour, ProcessedOnly: true},
+			[]string{"ca", "cc", "not json"}},
+		{"unprocessed too", []int{-400, 400, 0}, Options{MaxAge: 24 * time.Hour},
+			[]string{"cc", "not json"}},
+		{"no limits", []int{400, 1}, Options{ProcessedOnly: true},
+			[]string{"ca", "cb", "not json"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := writeLog(t, tt.ages...)
+			opts := tt.opts
+			opts.Now = testNow
+			report, err := Compact(dir, opts)
+			if err != nil {
+				t.Fatalf("Compact() error = %v", err)
+			}
+			got := logIDs(t, dir)
+			if strings.Join(got, ",") != strings.Join(tt.wantKept, ",") {
+				t.Errorf("kept %v, want %v", got, tt.wantKept)
+			}
+			if report.Examined != len(tt.ages) || report.Kept != len(tt.wantKept)-1 || len(report.Archived) != report.Examined-report.Kept {
+				t.Errorf("report = examined %d, kept %d, archived %d", report.Examined, report.Kept, len(report.Archived))
+			}
+
+			archived, err := ReadArchive(dir)
+			if err != nil {
+				t.Fatalf("ReadArchive() error = %v", err)
+			}
+			if len(archived) != len(report.Archived) {
+				t.Errorf("archive holds %d corrections, want %d", len(archived), len(report.Archived))
+			}
+		})
+	}
+}
+
+func TestCompact_AppendsToArchive(t *testing.T) {
+	dir := writeLog(t, 300, 200, 1)
+	for _, maxAge := range []time.Duration{250 * 24 * time.Hour, 100 * 24 * time.Hour} {
+		if _, err := Compact(dir, Options{MaxAge: maxAge, Now: testNow}); err != nil {
+			t.Fatalf("Compact() error = %v", err)
+		}
+	}
+
+	archived, err := ReadArchive(dir)
+	if err != nil {
+		t.Fatalf("ReadArchive() error = %v", err)
+	}
+	if len(archived) != 2 || archived[0].ID != "ca" || archived[1].ID != "cb" {
+		t.Errorf("archive = %+v, want ca then cb", archived)
+	}
+}
+
+func TestCompact_DryRun(t *testing.T) {
+	dir := writeLog(t, 400, 1)
+	before, _ := os.ReadFile(filepath.Join(dir, File))
+
+	report, err := Compact(dir, Options{MaxAge: 24 * time.Hour, DryRun: true, Now: testNow})
+	if err != nil {
+		t.Fatalf("Compact() error = %v", err)
+	}
+	if len(report.Archived) != 1 || report.ArchivePath != "" {
+		t.Errorf("report = %+v, want one correction that would be archived", report)
+	}
+	after, _ := os.ReadFile(filepath.Join(dir, File))
+	if string(before) != string(after) {
+		t.Error("dry run changed the log")
+	}
+	if _, err := os.Stat(filepath.Join(dir, ArchiveFile)); !os.IsNotExist(err) {
+		t.Error("dry run created the archive")
+	}
+}
+
+func TestCompact_NoLog(t *testing.T) {
+	report, err := Compact(t.TempDir(), Options{MaxCount: 1})
+	if err != nil || report.Examined != 0 {
+		t.Errorf("Compact() = %+v, %v; want empty report", report, err)
+	}
+}
+
+func TestAutoCompact(t *testing.T) {
+	dir := writeLog(t, 400, 1)
+	cfg := config.Default().Corrections
+
+	report, err := AutoCompact(dir, cfg, testNow)
+	if err != nil {
+		t.Fatalf("AutoCompact() error = %v", err)
+	}
+	if report == nil || len(report.Archived) != 1 {
+		t.Fatalf("AutoCompact() = %+v, want one correction archived", report)
+	}
+
+	// Not due again until the interval elapses
+	if report, err := AutoCompact(dir, cfg, testNow.Add(time.Hour)); err != nil || report != nil {
+		t.Errorf("AutoCompact() within interval = %+v, %v; want not due", report, err)
+	}
+	if !Due(dir, 7*24*time.Hour, testNow.AddDate(0, 0, 7)) {
+		t.Error("Due() = false after the interval")
+	}
+
+	cfg.CompactInterval = ""
+	if report, err := AutoCompact(dir, cfg, testNow.AddDate(1, 0, 0)); err != nil || report != nil {
+		t.Errorf("AutoCompact() disabled = %+v, %v; want nil", report, err)
+	}
+}
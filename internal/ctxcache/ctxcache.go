@@ -0,0 +1,185 @@
+// Package ctxcache persists compiled activation results in .floop/cache, so
+// repeated agent calls with the same context skip reopening and
+// re-evaluating the store.
+//
+// Entries are keyed by the caller's context and a fingerprint of the stores
+// involved (size and modification time of their database and JSONL files).
+// Any write to a store changes its fingerprint, so stale entries are never
+// served; they are pruned once the cache grows past MaxEntries.
+package ctxcache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Dir is the cache directory inside a .floop directory.
+const Dir = "cache"
+
+// MaxEntries bounds the number of cached results kept per cache.
+const MaxEntries = 64
+
+// storeFiles are the files of a .floop directory whose changes invalidate
+// cached results. The WAL holds writes of long-running processes such as the
+// MCP server until they are checkpointed into floop.db.
+var storeFiles = []string{"floop.db", "floop.db-wal", "nodes.jsonl", "edges.jsonl"}
+
+// Cache is a directory of cached results for one kind of computation.
+type Cache struct {
+	dir string
+}
+
+// New returns the cache named name in floopDir, such as "active" for
+// .floop/cache/active. The directory is created on the first Put.
+func New(floopDir, name string) *Cache {
+	return &Cache{dir: filepath.Join(floopDir, Dir, name)}
+}
+
+// Fingerprint summarizes the state of the given .floop directories. It
+// changes whenever any of their store files is written, created, or removed.
+func Fingerprint(floopDirs ...string) string {
+	var sb strings.Builder
+	for _, dir := range floopDirs {
+		for _, name := range storeFiles {
+			info, err := os.Stat(filepath.Join(dir, name))
+			if err != nil {
+				fmt.Fprintf(&sb, "%s/%s:-\n", dir, name)
+				continue
+			}
+			fmt.Fprintf(&sb, "%s/%s:%d:%d\n", dir, name, info.Size(), info.ModTime().UnixNano())
+		}
+	}
+	return sb.String()
+}
+
+// entry is the on-disk form of a cached result. Key is the full key the
+// file name was hashed from, checked on read.
+type entry struct {
+	Key       string          `json:"key"`
+	CreatedAt time.Time       `json:"created_at"`
+	Value     json.RawMessage `json:"value"`
+}
+
+// Get decodes the result cached under key into v, reporting whether there
+// was one. A missing, unreadable, or mismatched entry is a miss.
+func (c *Cache) Get(key string, v interface{}) bool {
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		return false
+	}
+	var e entry
+	if err := json.Unmarshal(data, &e); err != nil || e.Key != key {
+		return false
+	}
+	return json.Unmarshal(e.Value, v) == nil
+}
+
+// Put caches v under key, replacing any previous entry, and prunes the
+// oldest entries beyond MaxEntries.
+func (c *Cache) Put(key string, v interface{}) error {
+	value, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+	data, err := json.Marshal(entry{Key: key, CreatedAt: time.Now(), Value: value})
+	if err != nil {
+		return fmt.Errorf("encoding cache entry: %w", err)
+	}
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+
+	// Write then rename, so concurrent readers never see a partial entry
+	tmp, err := os.CreateTemp(c.dir, ".entry-*")
+	if err != nil {
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("writing cache entry: %w", err)
+	}
+
+	c.prune()
+	return nil
+}
+
+// Clear removes every cached entry.
+func (c *Cache) Clear() error {
+	return os.RemoveAll(c.dir)
+}
+
+// RemoveIf removes the cached entries whose key satisfies match and returns
+// how many were removed. Unreadable entries are left for prune.
+func (c *Cache) RemoveIf(match func(key string) bool) (int, error) {
+	entries, err := os.ReadDir(c.dir)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("reading cache directory: %w", err)
+	}
+	removed := 0
+	for _, de := range entries {
+		if filepath.Ext(de.Name()) != ".json" {
+			continue
+		}
+		path := filepath.Join(c.dir, de.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		var e entry
+		if json.Unmarshal(data, &e) != nil || !match(e.Key) {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return removed, fmt.Errorf("removing cache entry: %w", err)
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+// prune removes the oldest entries beyond MaxEntries. Errors are ignored:
+// an entry left behind is only disk space.
+func (c *Cache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil || len(entries) <= MaxEntries {
+		return
+	}
+	type file struct {
+		name    string
+		modTime time.Time
+	}
+	var files []file
+	for _, e := range entries {
+		if info, err := e.Info(); err == nil && filepath.Ext(e.Name()) == ".json" {
+			files = append(files, file{e.Name(), info.ModTime()})
+		}
+	}
+	slices.SortFunc(files, func(a, b file) int { return b.modTime.Compare(a.modTime) })
+	for _, f := range files[min(MaxEntries, len(files)):] {
+		os.Remove(filepath.Join(c.dir, f.name))
+	}
+}
@@ -0,0 +1,103 @@
+package ctxcache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCacheGetPut(t *testing.T) {
+	floopDir := t.TempDir()
+	c := New(floopDir, "active")
+
+	var got []string
+	if c.Get("k1", &got) {
+		t.Fatal("empty cache reported a hit")
+	}
+	if err := c.Put("k1", []string{"a", "b"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if !c.Get("k1", &got) || len(got) != 2 || got[1] != "b" {
+		t.Errorf("Get(k1) = %v", got)
+	}
+	if c.Get("k2", &got) {
+		t.Error("Get(k2) hit an entry stored under another key")
+	}
+
+	if err := c.Clear(); err != nil {
+		t.Fatalf("Clear: %v", err)
+	}
+	if c.Get("k1", &got) {
+		t.Error("entry survived Clear")
+	}
+}
+
+func TestCachePrune(t *testing.T) {
+	c := New(t.TempDir(), "active")
+	for i := 0; i < MaxEntries+5; i++ {
+		if err := c.Put(string(rune('a'+i)), i); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != MaxEntries {
+		t.Errorf("cache holds %d entries, want %d", len(entries), MaxEntries)
+	}
+}
+
+func TestCacheRemoveIf(t *testing.T) {
+	c := New(t.TempDir(), "active")
+	if n, err := c.RemoveIf(func(string) bool { return true }); err != nil || n != 0 {
+		t.Errorf("RemoveIf on missing cache = %d, %v", n, err)
+	}
+	for _, key := range []string{"project-a/1", "project-a/2", "project-b/1"} {
+		if err := c.Put(key, key); err != nil {
+			t.Fatalf("Put: %v", err)
+		}
+	}
+
+	n, err := c.RemoveIf(func(key string) bool { return strings.HasPrefix(key, "project-a/") })
+	if err != nil {
+		t.Fatalf("RemoveIf: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("removed %d entries, want 2", n)
+	}
+	var got string
+	if c.Get("project-a/1", &got) {
+		t.Error("matching entry survived RemoveIf")
+	}
+	if !c.Get("project-b/1", &got) {
+		t.Error("RemoveIf removed an entry that did not match")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	floopDir := t.TempDir()
+	db := filepath.Join(floopDir, "floop.db")
+
+	empty := Fingerprint(floopDir)
+	if err := os.WriteFile(db, []byte("v1"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	created := Fingerprint(floopDir)
+	if created == empty {
+		t.Error("fingerprint unchanged after creating floop.db")
+	}
+	if Fingerprint(floopDir) != created {
+		t.Error("fingerprint changed without a write")
+	}
+
+	later := time.Now().Add(time.Second)
+	if err := os.Chtimes(db, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if Fingerprint(floopDir) == created {
+		t.Error("fingerprint unchanged after floop.db was modified")
+	}
+}
@@ -0,0 +1,233 @@
+// Package decay lowers the confidence of behaviors that have not activated
+// in a while, so stale behaviors sort behind fresh ones and are the first
+// shed when injection runs out of token budget.
+package decay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ranking"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
+)
+
+// StateFile is the name of the file in .floop/ recording the last decay run.
+const StateFile = "decay-state.json"
+
+// minChange is the smallest confidence drop worth writing.
+const minChange = 0.001
+
+// Options configures a decay run.
+type Options struct {
+	// Store holds the behaviors. It must implement ranking.ConfidenceUpdater
+	// unless DryRun is set.
+	Store store.GraphStore
+
+	// Window is how long a behavior may go without activating before it
+	// starts to decay.
+	Window time.Duration
+
+	// HalfLife is how long a stale behavior takes to lose half of its
+	// confidence above zero.
+	HalfLife time.Duration
+
+	// Floor is the confidence decay stops at; behaviors already at or
+	// below it are left alone.
+	Floor float64
+
+	// LastRun is when decay last ran. Time before it was already decayed,
+	// so repeated runs do not compound. Zero decays from the end of the
+	// window.
+	LastRun time.Time
+
+	// DryRun reports the changes without writing them.
+	DryRun bool
+
+	// Now is the reference time; zero uses time.Now.
+	Now time.Time
+}
+
+// Change is one behavior's confidence drop.
+type Change struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	From          float64   `json:"from"`
+	To            float64   `json:"to"`
+	LastActivated time.Time `json:"last_activated"`
+}
+
+// Report summarizes a decay run.
+type Report struct {
+	Scope    string    `json:"scope,omitempty"`
+	DryRun   bool      `json:"dry_run"`
+	Examined int       `json:"examined"`
+	Decayed  []Change  `json:"decayed"`
+	RanAt    time.Time `json:"ran_at"`
+}
+
+// Run applies ranking.ExponentialDecay to the confidence of every behavior
+// not activated within opts.Window, stopping at opts.Floor. A behavior that
+// never activated is measured from its creation. Built-in seed behaviors
+// are skipped.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Window <= 0 || opts.HalfLife <= 0 {
+		return nil, fmt.Errorf("decay: window and half-life must be positive")
+	}
+	updater, ok := opts.Store.(ranking.ConfidenceUpdater)
+	if !ok && !opts.DryRun {
+		return nil, fmt.Errorf("decay: store does not support confidence updates")
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	report := &Report{DryRun: opts.DryRun, Decayed: []Change{}, RanAt: now}
+
+	nodes, err := opts.Store.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+		if strings.HasPrefix(b.ID, "seed-") {
+			continue
+		}
+		report.Examined++
+		if b.Confidence <= opts.Floor {
+			continue
+		}
+
+		lastActive := b.Stats.CreatedAt
+		if b.Stats.LastActivated != nil {
+			lastActive = *b.Stats.LastActivated
+		}
+		if lastActive.IsZero() {
+			continue
+		}
+		start := lastActive.Add(opts.Window)
+		if opts.LastRun.After(start) {
+			start = opts.LastRun
+		}
+		if !now.After(start) {
+			continue
+		}
+
+		to := b.Confidence * ranking.ExponentialDecayAt(start, now, opts.HalfLife)
+		if to < opts.Floor {
+			to = opts.Floor
+		}
+		if b.Confidence-to < minChange {
+			continue
+		}
+		if !opts.DryRun {
+			if err := updater.UpdateConfidence(ctx, b.ID, to); err != nil {
+				return report, fmt.Errorf("failed to decay %s: %w", b.ID, err)
+			}
+		}
+		report.Decayed = append(report.Decayed, Change{
+			ID:            b.ID,
+			Name:          b.Name,
+			From:          b.Confidence,
+			To:            to,
+			LastActivated: lastActive,
+		})
+	}
+
+	sort.Slice(report.Decayed, func(i, j int) bool {
+		return report.Decayed[i].LastActivated.Before(report.Decayed[j].LastActivated)
+	})
+	return report, nil
+}
+
+// OptionsFromConfig returns decay options for the maintenance settings, with
+// the reinforcement floor.
+func OptionsFromConfig(cfg config.MaintenanceConfig) (Options, error) {
+	window, err := utils.ParseDuration(cfg.DecayWindow)
+	if err != nil {
+		return Options{}, fmt.Errorf("maintenance.decay_window: %w", err)
+	}
+	halfLife, err := utils.ParseDuration(cfg.DecayHalfLife)
+	if err != nil {
+		return Options{}, fmt.Errorf("maintenance.decay_half_life: %w", err)
+	}
+	return Options{
+		Window:   window,
+		HalfLife: halfLife,
+		Floor:    ranking.DefaultReinforcementConfig().Floor,
+	}, nil
+}
+
+// Scope is a store decayed on its own schedule, with its state in Dir.
+type Scope struct {
+	Name  string
+	Store store.GraphStore
+	Dir   string
+}
+
+// Scopes returns the initialized local and global stores of m. Each keeps
+// its own state, so the global store, shared by every project, decays once
+// per interval however many projects run decay.
+func Scopes(m *store.MultiGraphStore, root string) []Scope {
+	var scopes []Scope
+	localDir := store.LocalFloopPath(root)
+	globalDir, err := store.GlobalFloopPath()
+	if _, statErr := os.Stat(localDir); statErr == nil && (err != nil || filepath.Clean(localDir) != filepath.Clean(globalDir)) {
+		scopes = append(scopes, Scope{Name: string(store.ScopeLocal), Store: m.LocalStore(), Dir: localDir})
+	}
+	if err == nil {
+		if _, statErr := os.Stat(globalDir); statErr == nil {
+			scopes = append(scopes, Scope{Name: string(store.ScopeGlobal), Store: m.GlobalStore(), Dir: globalDir})
+		}
+	}
+	return scopes
+}
+
+type state struct {
+	LastRun time.Time `json:"last_run"`
+	Decayed int       `json:"decayed"`
+}
+
+// LastRun returns when decay last ran in floopDir, or the zero time if it
+// never has.
+func LastRun(floopDir string) time.Time {
+	data, err := os.ReadFile(filepath.Join(floopDir, StateFile))
+	if err != nil {
+		return time.Time{}
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return time.Time{}
+	}
+	return st.LastRun
+}
+
+// Due reports whether decay should run: interval has elapsed since the last
+// run recorded in floopDir, or it has never run. A non-positive interval
+// disables scheduled runs.
+func Due(floopDir string, interval time.Duration, now time.Time) bool {
+	if interval <= 0 {
+		return false
+	}
+	return now.Sub(LastRun(floopDir)) >= interval
+}
+
+// MarkRun records a completed run in floopDir.
+func MarkRun(floopDir string, report *Report) error {
+	data, err := json.Marshal(state{LastRun: report.RanAt, Decayed: len(report.Decayed)})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(floopDir, StateFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to record decay run: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package decay
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func newTestStore(t *testing.T, now time.Time) *store.SQLiteGraphStore {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	ctx := context.Background()
+	for _, b := range []struct {
+		id            string
+		confidence    float64
+		lastActivated time.Time
+	}{
+		{"stale", 0.8, time.Time{}},
+		{"fresh", 0.8, now.Add(-24 * time.Hour)},
+		{"floored", 0.3, time.Time{}},
+		{"seed-core", 0.9, time.Time{}},
+	} {
+		stats := map[string]interface{}{}
+		if !b.lastActivated.IsZero() {
+			stats["last_activated"] = b.lastActivated.Format(time.RFC3339)
+		}
+		_, err := s.AddNode(ctx, store.Node{
+			ID:   b.id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    b.id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": b.id},
+			},
+			Metadata: map[string]interface{}{"confidence": b.confidence, "stats": stats},
+		})
+		if err != nil {
+			t.Fatalf("AddNode(%s) error = %v", b.id, err)
+		}
+	}
+	return s
+}
+
+func confidenceOf(t *testing.T, s store.GraphStore, id string) float64 {
+	t.Helper()
+	node, err := s.GetNode(context.Background(), id)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+	}
+	c, _ := node.Metadata["confidence"].(float64)
+	return c
+}
+
+func TestRun(t *testing.T) {
+	// Behaviors are created now; look at them 30+30 days later so "stale"
+	// has spent one 30-day half-life past its 30-day window.
+	now := time.Now().Add(60 * 24 * time.Hour)
+	s := newTestStore(t, now)
+	opts := Options{Store: s, Window: 30 * 24 * time.Hour, HalfLife: 30 * 24 * time.Hour, Floor: 0.3, Now: now}
+
+	dry := opts
+	dry.DryRun = true
+	report, err := Run(context.Background(), dry)
+	if err != nil {
+		t.Fatalf("Run(dry) error = %v", err)
+	}
+	if report.Examined != 3 || len(report.Decayed) != 1 || report.Decayed[0].ID != "stale" {
+		t.Fatalf("dry run report = %+v", report)
+	}
+	if got := confidenceOf(t, s, "stale"); got != 0.8 {
+		t.Errorf("dry run changed confidence to %v", got)
+	}
+
+	report, err = Run(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got := confidenceOf(t, s, "stale"); math.Abs(got-0.4) > 0.01 {
+		t.Errorf("stale confidence = %v, want ~0.4 after one half-life", got)
+	}
+	for _, id := range []string{"fresh", "floored", "seed-core"} {
+		if got, want := confidenceOf(t, s, id), map[string]float64{"fresh": 0.8, "floored": 0.3, "seed-core": 0.9}[id]; got != want {
+			t.Errorf("%s confidence = %v, want unchanged %v", id, got, want)
+		}
+	}
+
+	// A run right after the last one decays nothing more, and a long gap
+	// stops at the floor.
+	opts.LastRun = report.RanAt
+	if again, _ := Run(context.Background(), opts); len(again.Decayed) != 0 {
+		t.Errorf("immediate re-run decayed %+v", again.Decayed)
+	}
+	opts.Now = now.Add(365 * 24 * time.Hour)
+	if _, err := Run(context.Background(), opts); err != nil {
+		t.Fatal(err)
+	}
+	if got := confidenceOf(t, s, "stale"); got != 0.3 {
+		t.Errorf("stale confidence after a year = %v, want floor 0.3", got)
+	}
+}
+
+func TestRun_InvalidOptions(t *testing.T) {
+	s := newTestStore(t, time.Now())
+	if _, err := Run(context.Background(), Options{Store: s, HalfLife: time.Hour}); err == nil {
+		t.Error("expected error for zero window")
+	}
+}
+
+func TestDueAndMarkRun(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	if !Due(dir, time.Hour, now) {
+		t.Error("never-run decay should be due")
+	}
+	if Due(dir, 0, now) {
+		t.Error("zero interval should disable scheduled runs")
+	}
+	if err := MarkRun(dir, &Report{RanAt: now}); err != nil {
+		t.Fatal(err)
+	}
+	if LastRun(dir).Sub(now).Abs() > time.Second {
+		t.Errorf("LastRun() = %v, want %v", LastRun(dir), now)
+	}
+	if Due(dir, time.Hour, now.Add(30*time.Minute)) {
+		t.Error("decay should not be due within the interval")
+	}
+	if !Due(dir, time.Hour, now.Add(2*time.Hour)) {
+		t.Error("decay should be due after the interval")
+	}
+}
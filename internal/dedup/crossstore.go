@@ -4,7 +4,6 @@ package dedup
 import (
 	"context"
 	"fmt"
-	"time"
 
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
@@ -166,6 +165,14 @@ func (d *CrossStoreDeduplicator) deduplicateBehavior(
 		}
 	}
 
+	// Let the LLM veto matches it doesn't consider the same behavior
+	if bestMatch != nil && bestSimilarity >= d.config.SimilarityThreshold && d.config.ConfirmWithLLM {
+		verdict, err := ConfirmDuplicate(ctx, d.llmClient, local, bestMatch)
+		if err != nil || !verdict.MergeCandidate {
+			return result
+		}
+	}
+
 	// If similarity is above threshold, merge
 	if bestSimilarity >= d.config.SimilarityThreshold && bestMatch != nil {
 		result.Action = "merge"
@@ -225,86 +232,14 @@ func (d *CrossStoreDeduplicator) computeSimilarity(a, b *models.Behavior) float6
 // point to the merged behavior instead.
 func (d *CrossStoreDeduplicator) updateEdges(ctx context.Context, localID, globalID, mergedID string) error {
 	// Update edges in the local store
-	if err := d.redirectEdges(ctx, d.localStore, localID, mergedID); err != nil {
+	if err := RedirectEdges(ctx, d.localStore, localID, mergedID); err != nil {
 		return fmt.Errorf("failed to update local edges: %w", err)
 	}
 
 	// Update edges in the global store
-	if err := d.redirectEdges(ctx, d.globalStore, globalID, mergedID); err != nil {
+	if err := RedirectEdges(ctx, d.globalStore, globalID, mergedID); err != nil {
 		return fmt.Errorf("failed to update global edges: %w", err)
 	}
 
 	return nil
 }
-
-// redirectEdges redirects all edges referencing oldID to point to newID instead.
-func (d *CrossStoreDeduplicator) redirectEdges(ctx context.Context, s store.GraphStore, oldID, newID string) error {
-	// Get all edges connected to the old node (both directions)
-	inbound, err := s.GetEdges(ctx, oldID, store.DirectionInbound, "")
-	if err != nil {
-		return fmt.Errorf("failed to get inbound edges: %w", err)
-	}
-
-	outbound, err := s.GetEdges(ctx, oldID, store.DirectionOutbound, "")
-	if err != nil {
-		return fmt.Errorf("failed to get outbound edges: %w", err)
-	}
-
-	// Redirect inbound edges (where oldID is the target)
-	for _, edge := range inbound {
-		// Remove old edge
-		if err := s.RemoveEdge(ctx, edge.Source, oldID, edge.Kind); err != nil {
-			return fmt.Errorf("failed to remove inbound edge: %w", err)
-		}
-		// Add new edge pointing to newID
-		newEdge := store.Edge{
-			Source:        edge.Source,
-			Target:        newID,
-			Kind:          edge.Kind,
-			Weight:        edge.Weight,
-			CreatedAt:     edge.CreatedAt,
-			LastActivated: edge.LastActivated,
-			Metadata:      edge.Metadata,
-		}
-		// Defensive fallback for legacy edges missing Weight/CreatedAt
-		if newEdge.Weight <= 0 {
-			newEdge.Weight = 1.0
-		}
-		if newEdge.CreatedAt.IsZero() {
-			newEdge.CreatedAt = time.Now()
-		}
-		if err := s.AddEdge(ctx, newEdge); err != nil {
-			return fmt.Errorf("failed to add redirected inbound edge: %w", err)
-		}
-	}
-
-	// Redirect outbound edges (where oldID is the source)
-	for _, edge := range outbound {
-		// Remove old edge
-		if err := s.RemoveEdge(ctx, oldID, edge.Target, edge.Kind); err != nil {
-			return fmt.Errorf("failed to remove outbound edge: %w", err)
-		}
-		// Add new edge from newID
-		newEdge := store.Edge{
-			Source:        newID,
-			Target:        edge.Target,
-			Kind:          edge.Kind,
-			Weight:        edge.Weight,
-			CreatedAt:     edge.CreatedAt,
-			LastActivated: edge.LastActivated,
-			Metadata:      edge.Metadata,
-		}
-		// Defensive fallback for legacy edges missing Weight/CreatedAt
-		if newEdge.Weight <= 0 {
-			newEdge.Weight = 1.0
-		}
-		if newEdge.CreatedAt.IsZero() {
-			newEdge.CreatedAt = time.Now()
-		}
-		if err := s.AddEdge(ctx, newEdge); err != nil {
-			return fmt.Errorf("failed to add redirected outbound edge: %w", err)
-		}
-	}
-
-	return nil
-}
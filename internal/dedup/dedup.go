@@ -69,6 +69,11 @@ type DeduplicatorConfig struct {
 	// When false, only Jaccard word overlap is used.
 	UseLLM bool `json:"use_llm,omitempty" yaml:"use_llm,omitempty"`
 
+	// ConfirmWithLLM asks the LLM whether each pair above the threshold is
+	// really the same behavior (see ConfirmDuplicate), and drops the pairs
+	// it rejects or can't assess.
+	ConfirmWithLLM bool `json:"confirm_with_llm,omitempty" yaml:"confirm_with_llm,omitempty"`
+
 	// MaxBatchSize limits the number of behaviors to process at once.
 	// Use 0 for no limit.
 	MaxBatchSize int `json:"max_batch_size,omitempty" yaml:"max_batch_size,omitempty"`
@@ -0,0 +1,90 @@
+package dedup
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// RedirectEdges redirects all edges referencing oldID in s to point to newID
+// instead, so the survivor of a merge inherits the merged behavior's place in
+// the graph. Edges between oldID and newID are dropped rather than turned
+// into self-edges.
+func RedirectEdges(ctx context.Context, s store.GraphStore, oldID, newID string) error {
+	// Get all edges connected to the old node (both directions)
+	inbound, err := s.GetEdges(ctx, oldID, store.DirectionInbound, "")
+	if err != nil {
+		return fmt.Errorf("failed to get inbound edges: %w", err)
+	}
+
+	outbound, err := s.GetEdges(ctx, oldID, store.DirectionOutbound, "")
+	if err != nil {
+		return fmt.Errorf("failed to get outbound edges: %w", err)
+	}
+
+	// Redirect inbound edges (where oldID is the target)
+	for _, edge := range inbound {
+		// Remove old edge
+		if err := s.RemoveEdge(ctx, edge.Source, oldID, edge.Kind); err != nil {
+			return fmt.Errorf("failed to remove inbound edge: %w", err)
+		}
+		if edge.Source == newID {
+			continue
+		}
+		// Add new edge pointing to newID
+		newEdge := store.Edge{
+			Source:        edge.Source,
+			Target:        newID,
+			Kind:          edge.Kind,
+			Weight:        edge.Weight,
+			CreatedAt:     edge.CreatedAt,
+			LastActivated: edge.LastActivated,
+			Metadata:      edge.Metadata,
+		}
+		// Defensive fallback for legacy edges missing Weight/CreatedAt
+		if newEdge.Weight <= 0 {
+			newEdge.Weight = 1.0
+		}
+		if newEdge.CreatedAt.IsZero() {
+			newEdge.CreatedAt = time.Now()
+		}
+		if err := s.AddEdge(ctx, newEdge); err != nil {
+			return fmt.Errorf("failed to add redirected inbound edge: %w", err)
+		}
+	}
+
+	// Redirect outbound edges (where oldID is the source)
+	for _, edge := range outbound {
+		// Remove old edge
+		if err := s.RemoveEdge(ctx, oldID, edge.Target, edge.Kind); err != nil {
+			return fmt.Errorf("failed to remove outbound edge: %w", err)
+		}
+		if edge.Target == newID {
+			continue
+		}
+		// Add new edge from newID
+		newEdge := store.Edge{
+			Source:        newID,
+			Target:        edge.Target,
+			Kind:          edge.Kind,
+			Weight:        edge.Weight,
+			CreatedAt:     edge.CreatedAt,
+			LastActivated: edge.LastActivated,
+			Metadata:      edge.Metadata,
+		}
+		// Defensive fallback for legacy edges missing Weight/CreatedAt
+		if newEdge.Weight <= 0 {
+			newEdge.Weight = 1.0
+		}
+		if newEdge.CreatedAt.IsZero() {
+			newEdge.CreatedAt = time.Now()
+		}
+		if err := s.AddEdge(ctx, newEdge); err != nil {
+			return fmt.Errorf("failed to add redirected outbound edge: %w", err)
+		}
+	}
+
+	return nil
+}
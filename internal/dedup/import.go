@@ -0,0 +1,355 @@
+package dedup
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Import actions, one per behavior of the source store.
+const (
+	ImportAdd     = "add"     // added under its own ID
+	ImportRename  = "rename"  // added under a new ID; its ID was taken by a different behavior
+	ImportMerge   = "merge"   // merged into a similar behavior already in the destination
+	ImportPresent = "present" // the destination already has it, or it was merged by an earlier import
+)
+
+// ImportConfig configures ImportStore.
+type ImportConfig struct {
+	// SimilarityThreshold is the score at or above which a source behavior
+	// is merged into a destination behavior rather than added beside it.
+	SimilarityThreshold float64
+
+	// UseLLM and LLMClient enable semantic comparison and merging, as for
+	// deduplication.
+	UseLLM    bool
+	LLMClient llm.Client
+
+	// Source is recorded in each imported behavior's imported_from
+	// metadata, e.g. the other project's root.
+	Source string
+
+	// DryRun plans the import without writing.
+	DryRun bool
+}
+
+// ImportedBehavior is what ImportStore did with one source behavior.
+type ImportedBehavior struct {
+	SourceID   string  `json:"source_id"`
+	ID         string  `json:"id"` // the behavior's ID in the destination
+	Name       string  `json:"name,omitempty"`
+	Action     string  `json:"action"`
+	Similarity float64 `json:"similarity,omitempty"` // for merges
+}
+
+// ImportResult summarizes an ImportStore.
+type ImportResult struct {
+	Behaviors     []ImportedBehavior `json:"behaviors"`
+	EdgesImported int                `json:"edges_imported"`
+	EdgesSkipped  int                `json:"edges_skipped"` // already present, or to nodes not imported
+	DryRun        bool               `json:"dry_run"`
+}
+
+// Count returns the number of behaviors that had the given action.
+func (r *ImportResult) Count(action string) int {
+	n := 0
+	for _, b := range r.Behaviors {
+		if b.Action == action {
+			n++
+		}
+	}
+	return n
+}
+
+// ChangedIDs returns the destination IDs of the behaviors added or merged
+// into, whose edges should be re-derived.
+func (r *ImportResult) ChangedIDs() []string {
+	seen := make(map[string]bool)
+	var ids []string
+	for _, b := range r.Behaviors {
+		if b.Action != ImportPresent && !seen[b.ID] {
+			seen[b.ID] = true
+			ids = append(ids, b.ID)
+		}
+	}
+	return ids
+}
+
+// ImportStore copies the active behaviors of src into dst, and the edges
+// between them. A behavior similar to one already in dst is merged into it,
+// unless it has the same ID, in which case dst's copy is kept; one whose ID
+// dst uses for a different behavior is added under a new ID,
+// and edges and requires/overrides/conflicts references are rewritten to
+// match. src is only read.
+func ImportStore(ctx context.Context, dst, src store.GraphStore, cfg ImportConfig) (*ImportResult, error) {
+	srcNodes, err := src.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query source behaviors: %w", err)
+	}
+	sort.Slice(srcNodes, func(i, j int) bool { return srcNodes[i].ID < srcNodes[j].ID })
+
+	dstNodes, err := dst.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query destination behaviors: %w", err)
+	}
+	existing := make([]models.Behavior, 0, len(dstNodes))
+	for _, n := range dstNodes {
+		existing = append(existing, models.NodeToBehavior(n))
+	}
+
+	// Plan every behavior first, so references can be rewritten to IDs
+	// chosen later in the loop.
+	result := &ImportResult{Behaviors: []ImportedBehavior{}, DryRun: cfg.DryRun}
+	idMap := make(map[string]string, len(srcNodes))
+	taken := make(map[string]bool)
+	simCfg := SimilarityConfig{
+		UseLLM:              cfg.UseLLM,
+		LLMClient:           cfg.LLMClient,
+		SimilarityThreshold: cfg.SimilarityThreshold,
+	}
+	for _, n := range srcNodes {
+		b := models.NodeToBehavior(n)
+		plan := ImportedBehavior{SourceID: n.ID, ID: n.ID, Name: b.Name, Action: ImportAdd}
+
+		same, err := dst.GetNode(ctx, n.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check %s: %w", n.ID, err)
+		}
+		// A behavior with the same ID is the same behavior, perhaps edited
+		// in one project, unless its content has diverged; the destination's
+		// copy wins.
+		if same != nil && sameBehavior(&b, *same, simCfg) {
+			plan.Action = ImportPresent
+		} else if id := alreadyImported(n.ID, &b, dstNodes); id != "" {
+			plan.Action, plan.ID = ImportPresent, id
+		} else if match, score := bestMatch(&b, existing, simCfg); match != nil {
+			plan.Action, plan.ID, plan.Similarity = ImportMerge, match.ID, score
+		} else if same != nil || taken[n.ID] {
+			plan.Action = ImportRename
+			if plan.ID, err = freeID(ctx, dst, n.ID, b.Content.Canonical, taken); err != nil {
+				return nil, err
+			}
+		}
+		taken[plan.ID] = true
+		idMap[n.ID] = plan.ID
+		result.Behaviors = append(result.Behaviors, plan)
+	}
+
+	if cfg.DryRun {
+		return result, nil
+	}
+
+	merger := NewBehaviorMerger(MergerConfig{UseLLM: cfg.UseLLM, LLMClient: cfg.LLMClient})
+	for i, n := range srcNodes {
+		plan := &result.Behaviors[i]
+		switch plan.Action {
+		case ImportPresent:
+			continue
+		case ImportMerge:
+			if err := mergeImported(ctx, dst, merger, n, plan.ID); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		node, err := copyNode(n)
+		if err != nil {
+			return nil, fmt.Errorf("failed to copy %s: %w", n.ID, err)
+		}
+		node.ID = plan.ID
+		rewriteReferences(node, idMap)
+		if cfg.Source != "" {
+			node.Metadata["imported_from"] = cfg.Source
+		}
+		if _, err := dst.AddNode(ctx, node); err != nil {
+			// The destination has the same content under another ID,
+			// perhaps as a forgotten or merged behavior: leave it alone.
+			var dup *store.DuplicateContentError
+			if !errors.As(err, &dup) {
+				return nil, fmt.Errorf("failed to add %s: %w", plan.ID, err)
+			}
+			plan.Action, plan.ID = ImportPresent, dup.ExistingID
+			idMap[n.ID] = dup.ExistingID
+		}
+	}
+
+	for _, n := range srcNodes {
+		edges, err := src.GetEdges(ctx, n.ID, store.DirectionOutbound, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get edges of %s: %w", n.ID, err)
+		}
+		for _, e := range edges {
+			source, target := idMap[e.Source], idMap[e.Target]
+			if source == "" || target == "" || source == target {
+				result.EdgesSkipped++
+				continue
+			}
+			present, err := dst.GetEdges(ctx, source, store.DirectionOutbound, e.Kind)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get edges of %s: %w", source, err)
+			}
+			if hasTarget(present, target) {
+				result.EdgesSkipped++
+				continue
+			}
+			e.Source, e.Target = source, target
+			// Defensive fallback for legacy edges missing Weight/CreatedAt
+			if e.Weight <= 0 {
+				e.Weight = 1.0
+			}
+			if e.CreatedAt.IsZero() {
+				e.CreatedAt = time.Now()
+			}
+			if err := dst.AddEdge(ctx, e); err != nil {
+				return nil, fmt.Errorf("failed to add edge %s -> %s: %w", source, target, err)
+			}
+			result.EdgesImported++
+		}
+	}
+
+	if err := dst.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("failed to sync: %w", err)
+	}
+	return result, nil
+}
+
+// bestMatch returns the behavior in candidates most similar to b, if it
+// scores at least the threshold.
+func bestMatch(b *models.Behavior, candidates []models.Behavior, cfg SimilarityConfig) (*models.Behavior, float64) {
+	var best *models.Behavior
+	var bestScore float64
+	for i := range candidates {
+		if score := ComputeSimilarity(b, &candidates[i], cfg).Score; score > bestScore {
+			best, bestScore = &candidates[i], score
+		}
+	}
+	if best == nil || bestScore < cfg.SimilarityThreshold {
+		return nil, 0
+	}
+	return best, bestScore
+}
+
+// sameBehavior reports whether b and n, which share an ID, have the same
+// or similar content.
+func sameBehavior(b *models.Behavior, n store.Node, cfg SimilarityConfig) bool {
+	other := models.NodeToBehavior(n)
+	if other.Content.Canonical == b.Content.Canonical {
+		return true
+	}
+	return ComputeSimilarity(b, &other, cfg).Score >= cfg.SimilarityThreshold
+}
+
+// alreadyImported returns the ID of the node in nodes that has b's content,
+// or that b (source ID id) was merged into by an earlier import.
+func alreadyImported(id string, b *models.Behavior, nodes []store.Node) string {
+	for _, n := range nodes {
+		if models.NodeToBehavior(n).Content.Canonical == b.Content.Canonical {
+			return n.ID
+		}
+		mergedFrom, _ := n.Metadata["merged_from"].([]interface{})
+		for _, from := range mergedFrom {
+			if from == id {
+				return n.ID
+			}
+		}
+	}
+	return ""
+}
+
+// mergeImported merges the source behavior n into the destination
+// behavior id, which records n's ID in merged_from.
+func mergeImported(ctx context.Context, dst store.GraphStore, merger *BehaviorMerger, n store.Node, id string) error {
+	target, err := dst.GetNode(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", id, err)
+	}
+	if target == nil {
+		return fmt.Errorf("behavior not found: %s", id)
+	}
+	into, from := models.NodeToBehavior(*target), models.NodeToBehavior(n)
+	merged, err := merger.Merge(ctx, []*models.Behavior{&into, &from})
+	if err != nil {
+		return fmt.Errorf("failed to merge %s into %s: %w", n.ID, id, err)
+	}
+
+	node := models.BehaviorToNode(merged)
+	node.ID = id
+	mergedFrom, _ := target.Metadata["merged_from"].([]interface{})
+	node.Metadata["merged_from"] = append(mergedFrom, n.ID)
+	if err := dst.UpdateNode(ctx, node); err != nil {
+		return fmt.Errorf("failed to save merge of %s into %s: %w", n.ID, id, err)
+	}
+	return nil
+}
+
+// freeID derives an ID for a behavior whose own ID is taken, from the ID
+// and a hash of its content.
+func freeID(ctx context.Context, dst store.GraphStore, id, canonical string, taken map[string]bool) (string, error) {
+	hash := sha256.Sum256([]byte(canonical))
+	base := id + "-" + hex.EncodeToString(hash[:])[:6]
+	candidate := base
+	for i := 2; ; i++ {
+		existing, err := dst.GetNode(ctx, candidate)
+		if err != nil {
+			return "", fmt.Errorf("failed to check %s: %w", candidate, err)
+		}
+		if existing == nil && !taken[candidate] {
+			return candidate, nil
+		}
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+}
+
+// rewriteReferences maps the IDs in a node's requires, overrides, and
+// conflicts lists through idMap.
+func rewriteReferences(n store.Node, idMap map[string]string) {
+	for _, field := range []string{"requires", "overrides", "conflicts"} {
+		list, ok := n.Content[field].([]interface{})
+		if !ok {
+			continue
+		}
+		for i, v := range list {
+			if id, ok := v.(string); ok && idMap[id] != "" {
+				list[i] = idMap[id]
+			}
+		}
+	}
+}
+
+// copyNode returns a deep copy of n in the shape AddNode reads back
+// without loss.
+func copyNode(n store.Node) (store.Node, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return store.Node{}, err
+	}
+	var out store.Node
+	if err := json.Unmarshal(data, &out); err != nil {
+		return store.Node{}, err
+	}
+	if out.Content == nil {
+		out.Content = make(map[string]interface{})
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]interface{})
+	}
+	return out, nil
+}
+
+func hasTarget(edges []store.Edge, target string) bool {
+	for _, e := range edges {
+		if e.Target == target {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,121 @@
+package dedup
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestImportStore(t *testing.T) {
+	ctx := context.Background()
+	python := map[string]interface{}{"language": "python"}
+
+	dst := createTestStore([]models.Behavior{
+		{ID: "b1", Name: "Use pathlib", Kind: models.BehaviorKindDirective, When: python,
+			Content: models.BehaviorContent{Canonical: "always use pathlib for file operations"}},
+		{ID: "b2", Name: "Tabs", Kind: models.BehaviorKindDirective,
+			Content: models.BehaviorContent{Canonical: "indent go code with tabs"}},
+	})
+	src := createTestStore([]models.Behavior{
+		// Same ID and content: already present.
+		{ID: "b1", Name: "Use pathlib", Kind: models.BehaviorKindDirective, When: python,
+			Content: models.BehaviorContent{Canonical: "always use pathlib for file operations"}},
+		// Same ID, different behavior: renamed.
+		{ID: "b2", Name: "Commit style", Kind: models.BehaviorKindDirective,
+			Content: models.BehaviorContent{Canonical: "write commit subjects in the imperative mood"}},
+		// Different ID, same behavior: merged.
+		{ID: "s1", Name: "Use pathlib", Kind: models.BehaviorKindDirective, When: python,
+			Content: models.BehaviorContent{Canonical: "always use pathlib for all file operations"}},
+	})
+	// New, and requires the renamed behavior.
+	s2 := models.BehaviorToNode(&models.Behavior{ID: "s2", Name: "Changelog", Kind: models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "update the changelog before tagging a release"}})
+	s2.Content["requires"] = []interface{}{"b2"}
+	if _, err := src.AddNode(ctx, s2); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []store.Edge{
+		{Source: "s2", Target: "b2", Kind: store.EdgeKindRequires, Weight: 1, CreatedAt: time.Now()},
+		{Source: "s1", Target: "s2", Kind: store.EdgeKindSimilarTo, Weight: 0.5, CreatedAt: time.Now()},
+	} {
+		if err := src.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg := ImportConfig{SimilarityThreshold: 0.8, Source: "/other"}
+	plan, err := ImportStore(ctx, dst, src, ImportConfig{SimilarityThreshold: 0.8, DryRun: true})
+	if err != nil {
+		t.Fatalf("ImportStore(dry run) error = %v", err)
+	}
+	if n, _ := dst.QueryNodes(ctx, map[string]interface{}{}); len(n) != 2 {
+		t.Fatalf("dry run wrote to the destination: %d nodes", len(n))
+	}
+
+	result, err := ImportStore(ctx, dst, src, cfg)
+	if err != nil {
+		t.Fatalf("ImportStore() error = %v", err)
+	}
+	if len(plan.Behaviors) != len(result.Behaviors) {
+		t.Fatalf("dry run planned %d behaviors, import did %d", len(plan.Behaviors), len(result.Behaviors))
+	}
+
+	got := make(map[string]ImportedBehavior)
+	for i, b := range result.Behaviors {
+		got[b.SourceID] = b
+		if plan.Behaviors[i] != b {
+			t.Errorf("dry run planned %+v, import did %+v", plan.Behaviors[i], b)
+		}
+	}
+	if got["b1"].Action != ImportPresent {
+		t.Errorf("b1 action = %s, want %s", got["b1"].Action, ImportPresent)
+	}
+	if got["s1"].Action != ImportMerge || got["s1"].ID != "b1" {
+		t.Errorf("s1 = %+v, want merged into b1", got["s1"])
+	}
+	if got["s2"].Action != ImportAdd || got["s2"].ID != "s2" {
+		t.Errorf("s2 = %+v, want added as s2", got["s2"])
+	}
+	renamed := got["b2"].ID
+	if got["b2"].Action != ImportRename || renamed == "b2" {
+		t.Fatalf("b2 = %+v, want renamed", got["b2"])
+	}
+
+	// The destination's own b2 is untouched; the import got a new ID.
+	if n, _ := dst.GetNode(ctx, "b2"); models.NodeToBehavior(*n).Name != "Tabs" {
+		t.Errorf("destination b2 was overwritten: %+v", n)
+	}
+	n, _ := dst.GetNode(ctx, renamed)
+	if n == nil || n.Metadata["imported_from"] != "/other" {
+		t.Fatalf("renamed behavior %s = %+v, want imported_from /other", renamed, n)
+	}
+
+	// References and edges follow the rename; the merged behavior's edge
+	// moves to its survivor.
+	imported, _ := dst.GetNode(ctx, "s2")
+	if req, _ := imported.Content["requires"].([]interface{}); len(req) != 1 || req[0] != renamed {
+		t.Errorf("s2 requires = %v, want [%s]", req, renamed)
+	}
+	if e, _ := dst.GetEdges(ctx, "s2", store.DirectionOutbound, store.EdgeKindRequires); len(e) != 1 || e[0].Target != renamed {
+		t.Errorf("s2 requires edges = %+v, want one to %s", e, renamed)
+	}
+	if e, _ := dst.GetEdges(ctx, "b1", store.DirectionOutbound, store.EdgeKindSimilarTo); len(e) != 1 || e[0].Target != "s2" {
+		t.Errorf("b1 similar-to edges = %+v, want one to s2", e)
+	}
+	b1, _ := dst.GetNode(ctx, "b1")
+	if from, _ := b1.Metadata["merged_from"].([]interface{}); len(from) != 1 || from[0] != "s1" {
+		t.Errorf("b1 merged_from = %v, want [s1]", b1.Metadata["merged_from"])
+	}
+
+	// Importing again changes nothing.
+	again, err := ImportStore(ctx, dst, src, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Count(ImportPresent) != len(again.Behaviors) || again.EdgesImported != 0 {
+		t.Errorf("second import = %+v, want nothing new", again)
+	}
+}
@@ -0,0 +1,169 @@
+package dedup
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"gopkg.in/yaml.v3"
+)
+
+// ErrEmptyMergeDraft is returned by ApplyMergeDraft when the edited draft
+// has no content, which callers treat as "skip this merge".
+var ErrEmptyMergeDraft = errors.New("merge draft is empty")
+
+// MergeDraft is the human-editable form of a proposed merged behavior.
+type MergeDraft struct {
+	Name       string                 `yaml:"name"`
+	Kind       string                 `yaml:"kind"`
+	When       map[string]interface{} `yaml:"when,omitempty"`
+	Content    models.BehaviorContent `yaml:"content"`
+	Priority   int                    `yaml:"priority"`
+	Confidence float64                `yaml:"confidence"`
+}
+
+// editableKinds are the behavior kinds a merge draft may use.
+var editableKinds = map[models.BehaviorKind]bool{
+	models.BehaviorKindDirective:  true,
+	models.BehaviorKindConstraint: true,
+	models.BehaviorKindProcedure:  true,
+	models.BehaviorKindPreference: true,
+	models.BehaviorKindEpisodic:   true,
+	models.BehaviorKindWorkflow:   true,
+}
+
+// draftOf returns the editable fields of a behavior.
+func draftOf(b *models.Behavior) MergeDraft {
+	d := MergeDraft{
+		Name:       strings.TrimSpace(b.Name),
+		Kind:       string(b.Kind),
+		When:       b.When,
+		Content:    b.Content,
+		Priority:   b.Priority,
+		Confidence: b.Confidence,
+	}
+	d.Content.Canonical = strings.TrimSpace(d.Content.Canonical)
+	d.Content.Summary = strings.TrimSpace(d.Content.Summary)
+	return d
+}
+
+// EncodeMergeDraft renders the editable fields of a merged behavior as YAML.
+func EncodeMergeDraft(merged *models.Behavior) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(draftOf(merged)); err != nil {
+		return nil, fmt.Errorf("encoding merge draft: %w", err)
+	}
+	if err := enc.Close(); err != nil {
+		return nil, fmt.Errorf("encoding merge draft: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// ApplyMergeDraft validates an edited merge draft and returns a copy of
+// merged with the edits applied. If the draft differs from merged, the
+// result's provenance records that the merge was human-adjusted.
+// A draft containing only comments and whitespace returns ErrEmptyMergeDraft.
+func ApplyMergeDraft(merged *models.Behavior, data []byte) (*models.Behavior, bool, error) {
+	if isBlankYAML(data) {
+		return nil, false, ErrEmptyMergeDraft
+	}
+
+	var draft MergeDraft
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&draft); err != nil {
+		return nil, false, fmt.Errorf("parsing merge draft: %w", err)
+	}
+
+	draft.Name = strings.TrimSpace(draft.Name)
+	draft.Content.Canonical = strings.TrimSpace(draft.Content.Canonical)
+	draft.Content.Summary = strings.TrimSpace(draft.Content.Summary)
+	if err := validateMergeDraft(draft); err != nil {
+		return nil, false, err
+	}
+
+	adjusted, err := draftChanged(draftOf(merged), draft)
+	if err != nil {
+		return nil, false, err
+	}
+
+	result := *merged
+	if !adjusted {
+		return &result, false, nil
+	}
+
+	result.Name = sanitize.SanitizeBehaviorName(draft.Name)
+	result.Kind = models.BehaviorKind(draft.Kind)
+	result.MemoryType = models.MemoryTypeForKind(result.Kind)
+	result.When = draft.When
+	result.Content = draft.Content
+	result.Content.Canonical = sanitize.SanitizeBehaviorContent(draft.Content.Canonical)
+	result.Content.Summary = sanitize.SanitizeBehaviorContent(draft.Content.Summary)
+	result.Content.Tags = make([]string, len(draft.Content.Tags))
+	for i, tag := range draft.Content.Tags {
+		result.Content.Tags[i] = sanitize.SanitizeBehaviorName(tag)
+	}
+	result.Priority = draft.Priority
+	result.Confidence = draft.Confidence
+	if result.ConfidenceSources != nil {
+		sources := *result.ConfidenceSources
+		sources.Base = draft.Confidence
+		result.ConfidenceSources = &sources
+	}
+
+	now := time.Now()
+	result.Provenance.HumanAdjusted = true
+	result.Provenance.AdjustedAt = &now
+	return &result, true, nil
+}
+
+// validateMergeDraft checks the fields a merged behavior must have.
+func validateMergeDraft(d MergeDraft) error {
+	if d.Name == "" {
+		return fmt.Errorf("name is required")
+	}
+	if d.Content.Canonical == "" {
+		return fmt.Errorf("content.canonical is required")
+	}
+	if !editableKinds[models.BehaviorKind(d.Kind)] {
+		return fmt.Errorf("invalid behavior kind %q", d.Kind)
+	}
+	if d.Confidence < 0 || d.Confidence > 1 {
+		return fmt.Errorf("confidence must be between 0.0 and 1.0, got %v", d.Confidence)
+	}
+	if d.Priority < 0 {
+		return fmt.Errorf("priority must not be negative, got %d", d.Priority)
+	}
+	return nil
+}
+
+// draftChanged reports whether two drafts differ once normalized through YAML,
+// so that edits which only reformat the document do not count.
+func draftChanged(before, after MergeDraft) (bool, error) {
+	a, err := yaml.Marshal(before)
+	if err != nil {
+		return false, fmt.Errorf("encoding merge draft: %w", err)
+	}
+	b, err := yaml.Marshal(after)
+	if err != nil {
+		return false, fmt.Errorf("encoding merge draft: %w", err)
+	}
+	return !bytes.Equal(a, b), nil
+}
+
+// isBlankYAML reports whether data holds only comments and whitespace.
+func isBlankYAML(data []byte) bool {
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "#") {
+			return false
+		}
+	}
+	return true
+}
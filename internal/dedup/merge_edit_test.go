@@ -0,0 +1,87 @@
+package dedup
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func mergeEditBehavior() *models.Behavior {
+	return &models.Behavior{
+		ID:         "merged-1",
+		Name:       "wrap-errors",
+		Kind:       models.BehaviorKindDirective,
+		Content:    models.BehaviorContent{Canonical: "Wrap errors with context", Tags: []string{"go"}},
+		Priority:   2,
+		Confidence: 0.7,
+		Provenance: models.Provenance{SourceType: models.SourceTypeLearned, Author: "merge"},
+	}
+}
+
+func TestApplyMergeDraft_Unchanged(t *testing.T) {
+	merged := mergeEditBehavior()
+	draft, err := EncodeMergeDraft(merged)
+	if err != nil {
+		t.Fatalf("EncodeMergeDraft() error = %v", err)
+	}
+
+	got, adjusted, err := ApplyMergeDraft(merged, append([]byte("# header\n"), draft...))
+	if err != nil {
+		t.Fatalf("ApplyMergeDraft() error = %v", err)
+	}
+	if adjusted || got.Provenance.HumanAdjusted {
+		t.Errorf("unchanged draft marked as adjusted: %+v", got.Provenance)
+	}
+	if got.Content.Canonical != merged.Content.Canonical {
+		t.Errorf("canonical = %q", got.Content.Canonical)
+	}
+}
+
+func TestApplyMergeDraft_Edited(t *testing.T) {
+	merged := mergeEditBehavior()
+	draft, _ := EncodeMergeDraft(merged)
+	edited := strings.Replace(string(draft), "kind: directive", "kind: constraint", 1)
+	edited = strings.Replace(edited, "Wrap errors with context", "Never return bare errors", 1)
+
+	got, adjusted, err := ApplyMergeDraft(merged, []byte(edited))
+	if err != nil {
+		t.Fatalf("ApplyMergeDraft() error = %v", err)
+	}
+	if !adjusted {
+		t.Fatal("edited draft not reported as adjusted")
+	}
+	if got.Kind != models.BehaviorKindConstraint || got.Content.Canonical != "Never return bare errors" {
+		t.Errorf("got kind %q canonical %q", got.Kind, got.Content.Canonical)
+	}
+	if !got.Provenance.HumanAdjusted || got.Provenance.AdjustedAt == nil || got.Provenance.Author != "merge" {
+		t.Errorf("provenance = %+v", got.Provenance)
+	}
+	if merged.Content.Canonical != "Wrap errors with context" || merged.Provenance.HumanAdjusted {
+		t.Error("input behavior was modified")
+	}
+}
+
+func TestApplyMergeDraft_Invalid(t *testing.T) {
+	merged := mergeEditBehavior()
+	tests := map[string]string{
+		"missing name":      "kind: directive\ncontent:\n  canonical: x\n",
+		"missing canonical": "name: x\nkind: directive\n",
+		"bad kind":          "name: x\nkind: rule\ncontent:\n  canonical: x\n",
+		"bad confidence":    "name: x\nkind: directive\ncontent:\n  canonical: x\nconfidence: 1.5\n",
+		"unknown field":     "name: x\nkind: directive\ncontent:\n  canonical: x\nscope: global\n",
+		"not yaml":          "name: [x\n",
+	}
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, _, err := ApplyMergeDraft(merged, []byte(data)); err == nil {
+				t.Error("expected validation error")
+			}
+		})
+	}
+
+	if _, _, err := ApplyMergeDraft(merged, []byte("# only comments\n\n")); !errors.Is(err, ErrEmptyMergeDraft) {
+		t.Errorf("blank draft error = %v, want ErrEmptyMergeDraft", err)
+	}
+}
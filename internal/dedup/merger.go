@@ -8,6 +8,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/confidence"
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/logging"
 	"github.com/nvandessel/floop/internal/models"
@@ -132,7 +133,7 @@ func (m *BehaviorMerger) shouldUseLLM() bool {
 func (m *BehaviorMerger) llmMerge(ctx context.Context, behaviors []*models.Behavior) (*models.Behavior, error) {
 	prompt := MergePrompt(behaviors)
 	msgs := []llm.Message{{Role: "user", Content: prompt}}
-	response, err := m.llmClient.Complete(ctx, msgs)
+	response, err := m.llmClient.Complete(llm.WithPurpose(ctx, llm.PurposeMerge), msgs)
 	if err != nil {
 		return nil, fmt.Errorf("llm merge failed: %w", err)
 	}
@@ -156,6 +157,7 @@ func (m *BehaviorMerger) llmMerge(ctx context.Context, behaviors []*models.Behav
 	// Ensure the merged behavior has proper metadata
 	merged.ID = generateMergedID(behaviors)
 	merged.Provenance = createMergeProvenance(behaviors)
+	merged.ConfidenceSources = &confidence.Sources{Base: merged.Confidence, Origin: confidence.OriginMerge}
 
 	// Merge when conditions from all sources
 	merged.When = mergeWhenConditions(behaviors)
@@ -190,6 +192,7 @@ func (m *BehaviorMerger) ruleMerge(behaviors []*models.Behavior) *models.Behavio
 		Confidence: averageConfidence(behaviors),
 		Priority:   maxPriority(behaviors),
 	}
+	merged.ConfidenceSources = &confidence.Sources{Base: merged.Confidence, Origin: confidence.OriginMerge}
 
 	// Sanitize merged content to prevent stored prompt injection
 	merged.Content.Canonical = sanitize.SanitizeBehaviorContent(merged.Content.Canonical)
@@ -2,6 +2,7 @@ package dedup
 
 import (
 	"context"
+	"fmt"
 	"log/slog"
 	"sync"
 	"time"
@@ -51,7 +52,7 @@ func ComputeSimilarity(a, b *models.Behavior, cfg SimilarityConfig) SimilarityRe
 		// Try full LLM comparison
 		prompt := ComparisonPrompt(a, b)
 		msgs := []llm.Message{{Role: "user", Content: prompt}}
-		response, completeErr := cfg.LLMClient.Complete(ctx, msgs)
+		response, completeErr := cfg.LLMClient.Complete(llm.WithPurpose(ctx, llm.PurposeCompare), msgs)
 		if completeErr == nil {
 			result, parseErr := ParseComparisonResponse(response)
 			if parseErr == nil && result != nil {
@@ -75,6 +76,22 @@ func ComputeSimilarity(a, b *models.Behavior, cfg SimilarityConfig) SimilarityRe
 	return SimilarityResult{Score: score, Method: "jaccard"}
 }
 
+// ConfirmDuplicate asks the LLM whether a and b are the same behavior,
+// regardless of how they scored. The result's MergeCandidate is the verdict.
+func ConfirmDuplicate(ctx context.Context, client llm.Client, a, b *models.Behavior) (*ComparisonResult, error) {
+	if client == nil || !client.Available() {
+		return nil, fmt.Errorf("no LLM available")
+	}
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	response, err := client.Complete(llm.WithPurpose(ctx, llm.PurposeCompare), []llm.Message{{Role: "user", Content: ComparisonPrompt(a, b)}})
+	if err != nil {
+		return nil, fmt.Errorf("comparing behaviors: %w", err)
+	}
+	return ParseComparisonResponse(response)
+}
+
 // computeEmbeddingSimilarity uses the EmbeddingCache if available, otherwise
 // delegates to CompareEmbeddings directly.
 func computeEmbeddingSimilarity(ctx context.Context, ec llm.EmbeddingComparer, a, b *models.Behavior, cache *EmbeddingCache) (float64, error) {
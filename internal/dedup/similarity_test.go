@@ -260,6 +260,25 @@ func TestComputeSimilarity_EmbeddingCache_EmbedOnce(t *testing.T) {
 	}
 }
 
+func TestConfirmDuplicate(t *testing.T) {
+	a := &models.Behavior{ID: "a", Content: models.BehaviorContent{Canonical: "use pathlib"}}
+	b := &models.Behavior{ID: "b", Content: models.BehaviorContent{Canonical: "prefer pathlib"}}
+
+	mock := llm.NewMockClient().
+		WithCompleteResponse(`{"semantic_similarity": 0.95, "intent_match": true, "merge_candidate": true}`)
+	verdict, err := ConfirmDuplicate(context.Background(), mock, a, b)
+	if err != nil || !verdict.MergeCandidate {
+		t.Errorf("ConfirmDuplicate = %+v, %v, want a merge candidate", verdict, err)
+	}
+
+	if _, err := ConfirmDuplicate(context.Background(), llm.NewMockClient().WithError(errors.New("down")), a, b); err == nil {
+		t.Error("expected error when the LLM fails")
+	}
+	if _, err := ConfirmDuplicate(context.Background(), nil, a, b); err == nil {
+		t.Error("expected error without an LLM")
+	}
+}
+
 func TestNewEmbeddingCache(t *testing.T) {
 	cache := NewEmbeddingCache()
 	if cache == nil {
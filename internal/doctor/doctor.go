@@ -0,0 +1,346 @@
+// Package doctor checks the health of floop stores and repairs common
+// problems: database corruption, schema version, drift between the SQLite
+// database and its JSONL export, orphaned edges, invalid behavior kinds,
+// zero-confidence behaviors, stale vector index entries, and unrecorded
+// conflicts between behaviors.
+//
+// Each check returns a Check. Checks that find a repairable problem mark it
+// Fixable, and repair it when run with fix set.
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Status is the outcome of a check.
+type Status string
+
+const (
+	StatusOK   Status = "ok"
+	StatusWarn Status = "warn"
+	StatusFail Status = "fail"
+	StatusSkip Status = "skip"
+)
+
+// Check names.
+const (
+	CheckIntegrity      = "integrity"
+	CheckSchema         = "schema"
+	CheckJSONLDrift     = "jsonl-drift"
+	CheckOrphanedEdges  = "orphaned-edges"
+	CheckInvalidKinds   = "invalid-kinds"
+	CheckZeroConfidence = "zero-confidence"
+	CheckVectorIndex    = "vector-index"
+	CheckConflicts      = "conflicts"
+)
+
+// Check is the result of one health check.
+type Check struct {
+	Name    string   `json:"name"`
+	Status  Status   `json:"status"`
+	Message string   `json:"message"`
+	Details []string `json:"details,omitempty"`
+	// Fixable is set when --fix can repair the problem.
+	Fixable bool `json:"fixable,omitempty"`
+	// Fixed is set when the problem was repaired.
+	Fixed bool `json:"fixed,omitempty"`
+}
+
+// ok, warn, and fail build checks with a formatted message.
+func ok(name, format string, args ...any) Check {
+	return Check{Name: name, Status: StatusOK, Message: fmt.Sprintf(format, args...)}
+}
+
+func warn(name, format string, args ...any) Check {
+	return Check{Name: name, Status: StatusWarn, Message: fmt.Sprintf(format, args...)}
+}
+
+func fail(name, format string, args ...any) Check {
+	return Check{Name: name, Status: StatusFail, Message: fmt.Sprintf(format, args...)}
+}
+
+// StoreOptions configures CheckStore.
+type StoreOptions struct {
+	// Fix repairs fixable problems.
+	Fix bool
+
+	// CrossStore marks the store that holds edges between stores (the
+	// global store). Its edges may point at behaviors in any project's
+	// local store, so an edge is only orphaned when neither endpoint is in
+	// the store.
+	CrossStore bool
+}
+
+// CheckStore runs the per-database checks on the store in floopDir. The
+// database is inspected read-only first; when it is corrupt, created by a
+// newer floop, or (without Fix) needs migrating, the checks that require
+// opening it are skipped.
+func CheckStore(ctx context.Context, floopDir string, opts StoreOptions) ([]Check, error) {
+	dbPath := filepath.Join(floopDir, "floop.db")
+	var checks []Check
+
+	info, err := store.InspectDatabase(ctx, dbPath)
+	switch {
+	case os.IsNotExist(err):
+		c := warn(CheckIntegrity, "floop.db not found; it is rebuilt from the JSONL files when the store is opened")
+		c.Fixable = true
+		if !opts.Fix {
+			return append(checks, c, skipRest("floop.db is missing")), nil
+		}
+		c.Fixed = true
+		checks = append(checks, c)
+	case err != nil:
+		return nil, err
+	case len(info.Problems) > 0:
+		c := fail(CheckIntegrity, "database is corrupt; restore it with 'floop restore-backup'")
+		c.Details = info.Problems
+		return append(checks, c, skipRest("the database is corrupt")), nil
+	default:
+		checks = append(checks, ok(CheckIntegrity, "integrity_check and foreign_key_check passed"))
+
+		switch v := info.SchemaVersion; {
+		case v > store.SchemaVersion:
+			return append(checks,
+				fail(CheckSchema, "schema v%d is newer than this floop supports (v%d); upgrade floop", v, store.SchemaVersion),
+				skipRest("the schema is newer than this floop")), nil
+		case v < store.SchemaVersion:
+			c := warn(CheckSchema, "schema v%d is behind v%d; migrations run when the store is opened", v, store.SchemaVersion)
+			c.Fixable = true
+			if !opts.Fix {
+				return append(checks, c, skipRest("the schema needs migrating")), nil
+			}
+			c.Fixed = true
+			checks = append(checks, c)
+		default:
+			checks = append(checks, ok(CheckSchema, "schema v%d is current", v))
+		}
+	}
+
+	s, err := store.NewSQLiteGraphStore(filepath.Dir(floopDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open store: %w", err)
+	}
+	defer s.Close()
+
+	for _, check := range []func(context.Context, *store.SQLiteGraphStore, StoreOptions) (Check, error){
+		checkJSONLDrift,
+		checkOrphanedEdges,
+		checkInvalidKinds,
+		checkZeroConfidence,
+	} {
+		c, err := check(ctx, s, opts)
+		if err != nil {
+			return nil, err
+		}
+		checks = append(checks, c)
+	}
+	return checks, nil
+}
+
+// skipRest records that the checks needing an open store did not run.
+func skipRest(reason string) Check {
+	return Check{Name: "store", Status: StatusSkip, Message: "remaining checks skipped: " + reason}
+}
+
+func checkJSONLDrift(ctx context.Context, s *store.SQLiteGraphStore, opts StoreOptions) (Check, error) {
+	drift, err := s.CheckJSONLDrift(ctx)
+	if err != nil {
+		return Check{}, err
+	}
+	if drift.Empty() {
+		return ok(CheckJSONLDrift, "nodes.jsonl and edges.jsonl match the database"), nil
+	}
+
+	c := warn(CheckJSONLDrift, "JSONL export differs from the database: %d nodes missing, %d stale, %d edges vs %d in the database",
+		len(drift.MissingFromJSONL), len(drift.StaleInJSONL), drift.JSONLEdges, drift.DBEdges)
+	c.Fixable = true
+	for _, id := range drift.MissingFromJSONL {
+		c.Details = append(c.Details, "missing from nodes.jsonl: "+id)
+	}
+	for _, id := range drift.StaleInJSONL {
+		c.Details = append(c.Details, "not in the database: "+id)
+	}
+	if opts.Fix {
+		if err := s.ExportJSONL(ctx); err != nil {
+			return Check{}, err
+		}
+		c.Fixed = true
+	}
+	return c, nil
+}
+
+func checkOrphanedEdges(ctx context.Context, s *store.SQLiteGraphStore, opts StoreOptions) (Check, error) {
+	ids, err := s.AllBehaviorIDs(ctx)
+	if err != nil {
+		return Check{}, err
+	}
+	edges, err := s.AllEdges(ctx)
+	if err != nil {
+		return Check{}, err
+	}
+
+	var orphans []store.Edge
+	for _, e := range edges {
+		missing := !ids[e.Source] || !ids[e.Target]
+		if opts.CrossStore {
+			missing = !ids[e.Source] && !ids[e.Target]
+		}
+		if missing {
+			orphans = append(orphans, e)
+		}
+	}
+	if len(orphans) == 0 {
+		return ok(CheckOrphanedEdges, "all %d edges connect existing behaviors", len(edges)), nil
+	}
+
+	c := warn(CheckOrphanedEdges, "%d of %d edges point at behaviors that do not exist", len(orphans), len(edges))
+	c.Fixable = true
+	for _, e := range orphans {
+		c.Details = append(c.Details, fmt.Sprintf("%s -[%s]-> %s", e.Source, e.Kind, e.Target))
+	}
+	if opts.Fix {
+		for _, e := range orphans {
+			if err := s.RemoveEdge(ctx, e.Source, e.Target, e.Kind); err != nil {
+				return Check{}, err
+			}
+		}
+		c.Fixed = true
+	}
+	return c, nil
+}
+
+// knownNodeKinds are the node kinds floop writes to a store.
+var knownNodeKinds = map[store.NodeKind]bool{
+	store.NodeKindBehavior:        true,
+	store.NodeKindCorrection:      true,
+	store.NodeKindContextSnapshot: true,
+	store.NodeKindForgotten:       true,
+	store.NodeKindDeprecated:      true,
+	store.NodeKindMerged:          true,
+	store.NodeKindPending:         true,
+	store.NodeKindDormant:         true,
+	store.NodeKindSession:         true,
+}
+
+// behaviorNodeKinds are the node kinds that hold a behavior, and so need a
+// valid behavior kind.
+var behaviorNodeKinds = map[store.NodeKind]bool{
+	store.NodeKindBehavior:   true,
+	store.NodeKindForgotten:  true,
+	store.NodeKindDeprecated: true,
+	store.NodeKindMerged:     true,
+	store.NodeKindPending:    true,
+	store.NodeKindDormant:    true,
+}
+
+// validBehaviorKinds are the behavior kinds the extractor and packs produce.
+var validBehaviorKinds = map[models.BehaviorKind]bool{
+	models.BehaviorKindDirective:  true,
+	models.BehaviorKindConstraint: true,
+	models.BehaviorKindProcedure:  true,
+	models.BehaviorKindPreference: true,
+	models.BehaviorKindEpisodic:   true,
+	models.BehaviorKindWorkflow:   true,
+}
+
+// checkInvalidKinds flags nodes with an unknown node kind, which cannot be
+// repaired automatically, and behaviors with an unknown behavior kind,
+// which are repaired by resetting them to directive.
+func checkInvalidKinds(ctx context.Context, s *store.SQLiteGraphStore, opts StoreOptions) (Check, error) {
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{})
+	if err != nil {
+		return Check{}, err
+	}
+
+	var unknownNode, invalidBehavior []store.Node
+	for _, n := range nodes {
+		if !knownNodeKinds[n.Kind] {
+			unknownNode = append(unknownNode, n)
+			continue
+		}
+		if !behaviorNodeKinds[n.Kind] {
+			continue
+		}
+		kind, _ := n.Content["kind"].(string)
+		if !validBehaviorKinds[models.BehaviorKind(kind)] {
+			invalidBehavior = append(invalidBehavior, n)
+		}
+	}
+	if len(unknownNode) == 0 && len(invalidBehavior) == 0 {
+		return ok(CheckInvalidKinds, "all %d nodes have valid kinds", len(nodes)), nil
+	}
+
+	c := warn(CheckInvalidKinds, "%d nodes with an unknown node kind, %d behaviors with an invalid behavior kind", len(unknownNode), len(invalidBehavior))
+	for _, n := range unknownNode {
+		c.Details = append(c.Details, fmt.Sprintf("%s: unknown node kind %q (not fixable)", n.ID, n.Kind))
+	}
+	for _, n := range invalidBehavior {
+		c.Details = append(c.Details, fmt.Sprintf("%s: invalid behavior kind %q", n.ID, n.Content["kind"]))
+	}
+	if len(invalidBehavior) == 0 {
+		return c, nil
+	}
+
+	c.Fixable = true
+	if opts.Fix {
+		for _, n := range invalidBehavior {
+			n.Content["kind"] = string(models.BehaviorKindDirective)
+			if err := s.UpdateNode(ctx, n); err != nil {
+				return Check{}, err
+			}
+		}
+		c.Fixed = len(unknownNode) == 0
+	}
+	return c, nil
+}
+
+// checkZeroConfidence flags active and pending behaviors whose confidence
+// is zero or below, which rank last everywhere. They are repaired by
+// resetting confidence to the default for learned behaviors.
+func checkZeroConfidence(ctx context.Context, s *store.SQLiteGraphStore, opts StoreOptions) (Check, error) {
+	var zero []store.Node
+	total := 0
+	for _, kind := range []store.NodeKind{store.NodeKindBehavior, store.NodeKindPending} {
+		nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(kind)})
+		if err != nil {
+			return Check{}, err
+		}
+		total += len(nodes)
+		for _, n := range nodes {
+			if c, _ := n.Metadata["confidence"].(float64); c <= 0 {
+				zero = append(zero, n)
+			}
+		}
+	}
+	if len(zero) == 0 {
+		return ok(CheckZeroConfidence, "all %d behaviors have positive confidence", total), nil
+	}
+
+	sort.Slice(zero, func(i, j int) bool { return zero[i].ID < zero[j].ID })
+	c := warn(CheckZeroConfidence, "%d behaviors have zero confidence", len(zero))
+	c.Fixable = true
+	for _, n := range zero {
+		c.Details = append(c.Details, n.ID)
+	}
+	if opts.Fix {
+		for _, n := range zero {
+			if n.Metadata == nil {
+				n.Metadata = make(map[string]interface{})
+			}
+			n.Metadata["confidence"] = constants.DefaultLearnedConfidence
+			if err := s.UpdateNode(ctx, n); err != nil {
+				return Check{}, err
+			}
+		}
+		c.Fixed = true
+	}
+	return c, nil
+}
@@ -0,0 +1,241 @@
+package doctor
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/conflict"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
+)
+
+func behaviorNode(id, kind string, confidence float64) store.Node {
+	return store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    kind,
+			"content": map[string]interface{}{"canonical": id},
+		},
+		Metadata: map[string]interface{}{"confidence": confidence},
+	}
+}
+
+func byName(checks []Check) map[string]Check {
+	m := make(map[string]Check, len(checks))
+	for _, c := range checks {
+		m[c.Name] = c
+	}
+	return m
+}
+
+func TestCheckStore(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	floopDir := filepath.Join(root, ".floop")
+
+	s, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, n := range []store.Node{
+		behaviorNode("good", "directive", 0.8),
+		behaviorNode("zero", "constraint", 0),
+		behaviorNode("bad-kind", "banana", 0.5),
+	} {
+		if _, err := s.AddNode(ctx, n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.AddEdge(ctx, store.Edge{Source: "good", Target: "gone", Kind: store.EdgeKindSimilarTo, Weight: 0.9, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+	s.Close()
+	// Losing nodes.jsonl leaves the database ahead of its export.
+	if err := os.Remove(filepath.Join(floopDir, "nodes.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err := CheckStore(ctx, floopDir, StoreOptions{})
+	if err != nil {
+		t.Fatalf("CheckStore() error = %v", err)
+	}
+	got := byName(checks)
+	for name, want := range map[string]Status{
+		CheckIntegrity:      StatusOK,
+		CheckSchema:         StatusOK,
+		CheckJSONLDrift:     StatusWarn,
+		CheckOrphanedEdges:  StatusWarn,
+		CheckInvalidKinds:   StatusWarn,
+		CheckZeroConfidence: StatusWarn,
+	} {
+		c := got[name]
+		if c.Status != want {
+			t.Errorf("%s status = %q (%s), want %q", name, c.Status, c.Message, want)
+		}
+		if want == StatusWarn && (!c.Fixable || c.Fixed) {
+			t.Errorf("%s fixable=%v fixed=%v, want fixable and not fixed", name, c.Fixable, c.Fixed)
+		}
+	}
+	if d := got[CheckOrphanedEdges].Details; len(d) != 1 || d[0] != "good -[similar-to]-> gone" {
+		t.Errorf("orphaned-edges details = %v", d)
+	}
+
+	checks, err = CheckStore(ctx, floopDir, StoreOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("CheckStore(Fix) error = %v", err)
+	}
+	for _, c := range checks {
+		if c.Status == StatusWarn && !c.Fixed {
+			t.Errorf("%s not fixed: %s", c.Name, c.Message)
+		}
+	}
+
+	checks, err = CheckStore(ctx, floopDir, StoreOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, c := range checks {
+		if c.Status != StatusOK {
+			t.Errorf("after fix, %s = %q: %s %v", c.Name, c.Status, c.Message, c.Details)
+		}
+	}
+
+	s, err = store.NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	if n, _ := s.GetNode(ctx, "bad-kind"); n == nil || n.Content["kind"] != "directive" {
+		t.Errorf("bad-kind node = %+v, want kind reset to directive", n)
+	}
+	if n, _ := s.GetNode(ctx, "zero"); n == nil || models.NodeToBehavior(*n).Confidence <= 0 {
+		t.Errorf("zero node = %+v, want positive confidence", n)
+	}
+}
+
+func TestCheckStore_CrossStoreEdges(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+
+	s, err := store.NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddNode(ctx, behaviorNode("global", "directive", 0.8)); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range []store.Edge{
+		{Source: "global", Target: "project-local", Kind: store.EdgeKindSimilarTo, Weight: 0.9, CreatedAt: time.Now()},
+		{Source: "gone", Target: "also-gone", Kind: store.EdgeKindSimilarTo, Weight: 0.9, CreatedAt: time.Now()},
+	} {
+		if err := s.AddEdge(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.Close()
+
+	checks, err := CheckStore(ctx, filepath.Join(root, ".floop"), StoreOptions{CrossStore: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := byName(checks)[CheckOrphanedEdges]
+	if len(c.Details) != 1 || c.Details[0] != "gone -[similar-to]-> also-gone" {
+		t.Errorf("cross-store orphaned edges = %v, want only the edge with no local endpoint", c.Details)
+	}
+}
+
+func TestCheckStore_Corrupt(t *testing.T) {
+	floopDir := filepath.Join(t.TempDir(), ".floop")
+	if err := os.MkdirAll(floopDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(floopDir, "floop.db"), []byte("not a database, just some text that is long enough"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	checks, err := CheckStore(context.Background(), floopDir, StoreOptions{Fix: true})
+	if err != nil {
+		t.Fatalf("CheckStore() error = %v", err)
+	}
+	if len(checks) != 2 || checks[0].Name != CheckIntegrity || checks[0].Status != StatusFail || checks[1].Status != StatusSkip {
+		t.Fatalf("checks = %+v, want integrity failure and the rest skipped", checks)
+	}
+	if checks[0].Fixed {
+		t.Error("corruption must not be reported as fixed")
+	}
+}
+
+func TestCheckVectorIndexStaleness(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+	for _, id := range []string{"indexed", "unindexed"} {
+		if _, err := s.AddNode(ctx, behaviorNode(id, "directive", 0.8)); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.StoreEmbedding(ctx, id, []float32{1, 0, 0}, "test-model"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	idx := vectorindex.NewBruteForceIndex()
+	_ = idx.Add(ctx, "indexed", []float32{1, 0, 0})
+	_ = idx.Add(ctx, "deleted", []float32{0, 1, 0})
+
+	c, err := CheckVectorIndexStaleness(ctx, s, idx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Status != StatusWarn || len(c.Details) != 2 || idx.Len() != 2 {
+		t.Fatalf("check = %+v (index len %d), want one stale and one missing entry", c, idx.Len())
+	}
+
+	c, err = CheckVectorIndexStaleness(ctx, s, idx, true)
+	if err != nil || !c.Fixed {
+		t.Fatalf("fix = %+v, %v", c, err)
+	}
+	ids, _ := idx.IDs(ctx)
+	if len(ids) != 2 {
+		t.Errorf("index IDs after fix = %v, want indexed and unindexed", ids)
+	}
+	if c, _ := CheckVectorIndexStaleness(ctx, s, idx, false); c.Status != StatusOK {
+		t.Errorf("after fix = %+v, want ok", c)
+	}
+}
+
+func TestCheckBehaviorConflicts(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	for _, b := range []models.Behavior{
+		{ID: "pip", Name: "pip", Kind: models.BehaviorKindDirective, Content: models.BehaviorContent{Canonical: "Always use pip"}},
+		{ID: "no-pip", Name: "no-pip", Kind: models.BehaviorKindDirective, Content: models.BehaviorContent{Canonical: "Never use pip"}},
+	} {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&b)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	d := conflict.NewDetector(nil)
+
+	c, err := CheckBehaviorConflicts(ctx, s, d, false, time.Now())
+	if err != nil || c.Status != StatusWarn || len(c.Details) != 1 {
+		t.Fatalf("check = %+v, %v; want one unrecorded conflict", c, err)
+	}
+	if linked, _ := conflict.Linked(ctx, s, "pip", "no-pip"); linked {
+		t.Fatal("conflict recorded without fix")
+	}
+
+	if c, err := CheckBehaviorConflicts(ctx, s, d, true, time.Now()); err != nil || !c.Fixed {
+		t.Fatalf("fix = %+v, %v", c, err)
+	}
+	if c, _ := CheckBehaviorConflicts(ctx, s, d, false, time.Now()); c.Status != StatusOK {
+		t.Errorf("after fix = %+v, want ok", c)
+	}
+}
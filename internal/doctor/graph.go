@@ -0,0 +1,127 @@
+package doctor
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/conflict"
+	"github.com/nvandessel/floop/internal/gc"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
+)
+
+// CheckVectorIndexStaleness compares the vector index with the behaviors
+// and embeddings in s. Entries for behaviors that no longer exist are stale;
+// active behaviors with a stored embedding but no index entry are missing.
+// The fix removes stale entries, adds missing ones, and saves the index.
+func CheckVectorIndexStaleness(ctx context.Context, s store.GraphStore, idx vectorindex.VectorIndex, fix bool) (Check, error) {
+	lister, isLister := idx.(vectorindex.IDLister)
+	if !isLister {
+		return Check{Name: CheckVectorIndex, Status: StatusSkip, Message: fmt.Sprintf("vector index %T cannot list its entries", idx)}, nil
+	}
+	live, err := gc.LiveBehaviorIDs(ctx, s)
+	if err != nil {
+		return Check{}, err
+	}
+	stale, err := vectorindex.FindOrphans(ctx, idx, live)
+	if err != nil {
+		return Check{}, err
+	}
+
+	indexed, err := lister.IDs(ctx)
+	if err != nil {
+		return Check{}, fmt.Errorf("failed to list vector index entries: %w", err)
+	}
+	inIndex := make(map[string]bool, len(indexed))
+	for _, id := range indexed {
+		inIndex[id] = true
+	}
+	var missing []store.BehaviorEmbedding
+	if es, ok := s.(store.EmbeddingStore); ok {
+		embeddings, err := es.GetAllEmbeddings(ctx)
+		if err != nil {
+			return Check{}, err
+		}
+		for _, e := range embeddings {
+			if live[e.BehaviorID] && !inIndex[e.BehaviorID] {
+				missing = append(missing, e)
+			}
+		}
+	}
+
+	if len(stale) == 0 && len(missing) == 0 {
+		return ok(CheckVectorIndex, "%d index entries match the stored embeddings", len(indexed)), nil
+	}
+
+	c := warn(CheckVectorIndex, "vector index has %d stale entries and is missing %d embeddings", len(stale), len(missing))
+	c.Fixable = true
+	for _, id := range stale {
+		c.Details = append(c.Details, "stale: "+id)
+	}
+	for _, e := range missing {
+		c.Details = append(c.Details, "missing: "+e.BehaviorID)
+	}
+	if !fix {
+		return c, nil
+	}
+	if _, err := vectorindex.RemoveOrphans(ctx, idx, stale); err != nil {
+		return Check{}, err
+	}
+	for _, e := range missing {
+		if err := idx.Add(ctx, e.BehaviorID, e.Embedding); err != nil {
+			return Check{}, fmt.Errorf("failed to index %s: %w", e.BehaviorID, err)
+		}
+	}
+	if err := idx.Save(ctx); err != nil {
+		return Check{}, fmt.Errorf("failed to save vector index: %w", err)
+	}
+	c.Fixed = true
+	return c, nil
+}
+
+// CheckBehaviorConflicts scans active behaviors in s for contradictions.
+// Conflicts that are not yet recorded as conflicts edges are reported; the
+// fix records them.
+func CheckBehaviorConflicts(ctx context.Context, s store.GraphStore, d *conflict.Detector, fix bool, now time.Time) (Check, error) {
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return Check{}, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	behaviors := make([]models.Behavior, 0, len(nodes))
+	for _, n := range nodes {
+		behaviors = append(behaviors, models.NodeToBehavior(n))
+	}
+
+	var unrecorded []conflict.Conflict
+	found := d.Scan(ctx, behaviors)
+	for _, f := range found {
+		linked, err := conflict.Linked(ctx, s, f.A, f.B)
+		if err != nil {
+			return Check{}, err
+		}
+		if !linked {
+			unrecorded = append(unrecorded, f)
+		}
+	}
+	if len(unrecorded) == 0 {
+		if len(found) == 0 {
+			return ok(CheckConflicts, "no conflicts among %d behaviors", len(behaviors)), nil
+		}
+		return ok(CheckConflicts, "%d conflicts among %d behaviors, all recorded", len(found), len(behaviors)), nil
+	}
+
+	c := warn(CheckConflicts, "%d unrecorded conflicts among %d behaviors", len(unrecorded), len(behaviors))
+	c.Fixable = true
+	for _, f := range unrecorded {
+		c.Details = append(c.Details, fmt.Sprintf("%s <-> %s [%s, %.2f]: %s", f.A, f.B, f.Method, f.Score, f.Reason))
+	}
+	if fix {
+		if _, err := conflict.Record(ctx, s, unrecorded, now); err != nil {
+			return Check{}, err
+		}
+		c.Fixed = true
+	}
+	return c, nil
+}
@@ -3,10 +3,9 @@ package edges
 import (
 	"context"
 	"fmt"
-	"os"
+	"log/slog"
 	"time"
 
-	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/similarity"
@@ -62,6 +61,12 @@ type SubsetResult struct {
 // DeriveEdgesForStore runs the all-pairs edge derivation algorithm on a single store.
 // Extracted from cmd/floop/cmd_derive_edges.go:deriveEdgesForStore.
 func DeriveEdgesForStore(ctx context.Context, graphStore store.GraphStore, scope string, dryRun, clear bool) (DeriveResult, error) {
+	return DeriveEdgesForStoreWithTuning(ctx, graphStore, scope, dryRun, clear, similarity.DefaultTuning())
+}
+
+// DeriveEdgesForStoreWithTuning is DeriveEdgesForStore with store-specific
+// similarity weights and thresholds (see 'floop tune-similarity').
+func DeriveEdgesForStoreWithTuning(ctx context.Context, graphStore store.GraphStore, scope string, dryRun, clear bool, tuning similarity.Tuning) (DeriveResult, error) {
 	result := DeriveResult{Scope: scope}
 
 	// Load all non-forgotten behaviors
@@ -91,7 +96,7 @@ func DeriveEdgesForStore(ctx context.Context, graphStore store.GraphStore, scope
 			a := &behaviors[i]
 			b := &behaviors[j]
 
-			score := ComputeBehaviorSimilarity(a, b, nil, false, nil)
+			score := ComputeTunedSimilarity(a, b, tuning)
 
 			// Record in histogram (10 buckets: [0.0,0.1), [0.1,0.2), ..., [0.9,1.0])
 			bucket := int(score * 10)
@@ -100,7 +105,7 @@ func DeriveEdgesForStore(ctx context.Context, graphStore store.GraphStore, scope
 			}
 			result.Histogram[bucket]++
 
-			proposed, skipped := proposeEdgesForPair(a, b, score, existingEdges)
+			proposed, skipped := proposeEdgesForPair(a, b, score, tuning.Thresholds, existingEdges)
 			result.ProposedEdges = append(result.ProposedEdges, proposed...)
 			result.SkippedExisting += skipped
 		}
@@ -117,7 +122,7 @@ func DeriveEdgesForStore(ctx context.Context, graphStore store.GraphStore, scope
 				CreatedAt: now,
 			}
 			if err := graphStore.AddEdge(ctx, edge); err != nil {
-				fmt.Fprintf(os.Stderr, "warning: failed to add edge %s -> %s: %v\n", pe.Source, pe.Target, err)
+				slog.Warn("failed to add edge", "source", pe.Source, "target", pe.Target, "error", err)
 				continue
 			}
 			result.CreatedEdges++
@@ -129,7 +134,7 @@ func DeriveEdgesForStore(ctx context.Context, graphStore store.GraphStore, scope
 
 		// Refresh PageRank
 		if _, err := ranking.ComputePageRank(ctx, graphStore, ranking.DefaultPageRankConfig()); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to refresh PageRank: %v\n", err)
+			slog.Warn("failed to refresh PageRank", "error", err)
 		}
 	}
 
@@ -143,6 +148,12 @@ func DeriveEdgesForStore(ctx context.Context, graphStore store.GraphStore, scope
 // Only computes pairs where at least one behavior is in newIDs.
 // This is O(new * all) not O(all * all).
 func DeriveEdgesForSubset(ctx context.Context, graphStore store.GraphStore, newIDs []string, allBehaviors []models.Behavior) (*SubsetResult, error) {
+	return DeriveEdgesForSubsetWithTuning(ctx, graphStore, newIDs, allBehaviors, similarity.DefaultTuning())
+}
+
+// DeriveEdgesForSubsetWithTuning is DeriveEdgesForSubset with store-specific
+// similarity weights and thresholds (see 'floop tune-similarity').
+func DeriveEdgesForSubsetWithTuning(ctx context.Context, graphStore store.GraphStore, newIDs []string, allBehaviors []models.Behavior, tuning similarity.Tuning) (*SubsetResult, error) {
 	// Performance guard
 	newSet := make(map[string]bool, len(newIDs))
 	for _, id := range newIDs {
@@ -154,8 +165,8 @@ func DeriveEdgesForSubset(ctx context.Context, graphStore store.GraphStore, newI
 		existingCount = 0
 	}
 	if len(newIDs)*existingCount > 10000 {
-		fmt.Fprintf(os.Stderr, "warning: large comparison set (%d new x %d existing = %d pairs)\n",
-			len(newIDs), existingCount, len(newIDs)*existingCount)
+		slog.Warn("large comparison set",
+			"new", len(newIDs), "existing", existingCount, "pairs", len(newIDs)*existingCount)
 	}
 
 	// Build existing edge set
@@ -176,9 +187,9 @@ func DeriveEdgesForSubset(ctx context.Context, graphStore store.GraphStore, newI
 				continue
 			}
 
-			score := ComputeBehaviorSimilarity(a, b, nil, false, nil)
+			score := ComputeTunedSimilarity(a, b, tuning)
 
-			pairProposed, pairSkipped := proposeEdgesForPair(a, b, score, existingEdges)
+			pairProposed, pairSkipped := proposeEdgesForPair(a, b, score, tuning.Thresholds, existingEdges)
 			proposed = append(proposed, pairProposed...)
 			skipped += pairSkipped
 		}
@@ -198,7 +209,7 @@ func DeriveEdgesForSubset(ctx context.Context, graphStore store.GraphStore, newI
 
 	if created > 0 {
 		if err := graphStore.Sync(ctx); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to sync after subset derivation: %v\n", err)
+			slog.Warn("failed to sync after subset derivation", "error", err)
 		}
 	}
 
@@ -241,14 +252,14 @@ func buildExistingEdgeSet(ctx context.Context, graphStore store.GraphStore, beha
 // proposeEdgesForPair evaluates a single behavior pair and returns any proposed edges.
 // It checks for similar-to edges (score-based and tag-based) and overrides edges
 // (specificity-based). Returns proposed edges and the number of skipped duplicates.
-func proposeEdgesForPair(a, b *models.Behavior, score float64, existingEdges map[string]bool) ([]ProposedEdge, int) {
+func proposeEdgesForPair(a, b *models.Behavior, score float64, th similarity.Thresholds, existingEdges map[string]bool) ([]ProposedEdge, int) {
 	var proposed []ProposedEdge
 	skipped := 0
 
 	// Similar-to edges:
-	// 1. Score-based: similarity in [0.5, 0.9)
+	// 1. Score-based: similarity in [SimilarTo, UpperBound), default [0.5, 0.9)
 	// 2. Tag-based: behaviors sharing >= 2 tags are conceptually related
-	shouldConnect := (score >= constants.SimilarToThreshold && score < constants.SimilarToUpperBound) ||
+	shouldConnect := (score >= th.SimilarTo && score < th.UpperBound) ||
 		similarity.CountSharedTags(a.Content.Tags, b.Content.Tags) >= MinSharedTagsForEdge
 	if shouldConnect {
 		key := a.ID + ":" + b.ID + ":" + string(store.EdgeKindSimilarTo)
@@ -296,7 +307,7 @@ func ClearDerivedEdges(ctx context.Context, graphStore store.GraphStore, behavio
 			}
 			for _, e := range edges {
 				if err := graphStore.RemoveEdge(ctx, e.Source, e.Target, e.Kind); err != nil {
-					fmt.Fprintf(os.Stderr, "warning: failed to remove edge %s -> %s (%s): %v\n", e.Source, e.Target, e.Kind, err)
+					slog.Warn("failed to remove edge", "source", e.Source, "target", e.Target, "kind", e.Kind, "error", err)
 					continue
 				}
 				cleared++
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"fmt"
-	"os"
+	"log/slog"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/logging"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -188,6 +191,46 @@ func TestDeriveEdgesForSubset_CreatesNewExisting(t *testing.T) {
 	}
 }
 
+func TestDeriveEdgesForSubsetWithTuning(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+
+	a := models.Behavior{
+		ID:      "b-tuned-a",
+		Name:    "Test before push",
+		Content: models.BehaviorContent{Canonical: "run tests before pushing branches", Tags: []string{"git"}},
+	}
+	b := models.Behavior{
+		ID:      "b-tuned-b",
+		Name:    "Lint before push",
+		Content: models.BehaviorContent{Canonical: "run linters before pushing commits", Tags: []string{"lint"}},
+	}
+	for _, beh := range []models.Behavior{a, b} {
+		addBehaviorToStore(t, ctx, s, beh)
+	}
+	all := []models.Behavior{a, b}
+	newIDs := []string{"b-tuned-a", "b-tuned-b"}
+
+	result, err := DeriveEdgesForSubset(ctx, s, newIDs, all)
+	if err != nil {
+		t.Fatalf("DeriveEdgesForSubset() error = %v", err)
+	}
+	if result.EdgesCreated != 0 {
+		t.Fatalf("EdgesCreated = %d with default thresholds, want 0", result.EdgesCreated)
+	}
+
+	// A store tuned to connect loosely related behaviors links the pair.
+	tuning := similarity.DefaultTuning()
+	tuning.Thresholds.SimilarTo = 0.3
+	result, err = DeriveEdgesForSubsetWithTuning(ctx, s, newIDs, all, tuning)
+	if err != nil {
+		t.Fatalf("DeriveEdgesForSubsetWithTuning() error = %v", err)
+	}
+	if result.EdgesCreated != 1 {
+		t.Errorf("EdgesCreated = %d under the tuned thresholds, want 1: %+v", result.EdgesCreated, result.ProposedEdges)
+	}
+}
+
 func TestDeriveEdgesForSubset_CreatesNewNew(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewInMemoryGraphStore()
@@ -244,10 +287,11 @@ func TestDeriveEdgesForSubset_CreatesNewNew(t *testing.T) {
 }
 
 func TestDeriveEdgesForSubset_PerformanceGuard(t *testing.T) {
-	// Capture stderr to verify the warning is printed
-	oldStderr := os.Stderr
-	r, w, _ := os.Pipe()
-	os.Stderr = w
+	// Capture the default logger to verify the warning is logged
+	var buf bytes.Buffer
+	prev := slog.Default()
+	slog.SetDefault(logging.NewCLILogger(&buf, slog.LevelInfo, false))
+	t.Cleanup(func() { slog.SetDefault(prev) })
 
 	ctx := context.Background()
 	s := store.NewInMemoryGraphStore()
@@ -288,13 +332,8 @@ func TestDeriveEdgesForSubset_PerformanceGuard(t *testing.T) {
 		t.Fatalf("DeriveEdgesForSubset() error = %v", err)
 	}
 
-	w.Close()
-	var buf bytes.Buffer
-	buf.ReadFrom(r)
-	os.Stderr = oldStderr
-
 	output := buf.String()
-	if !bytes.Contains([]byte(output), []byte("warning: large comparison set")) {
+	if !strings.Contains(output, "warning: large comparison set new=2 existing=5001 pairs=10002") {
 		t.Errorf("expected performance guard warning, got: %q", output)
 	}
 }
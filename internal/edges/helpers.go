@@ -9,6 +9,7 @@ import (
 	"github.com/nvandessel/floop/internal/dedup"
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -41,3 +42,13 @@ func ComputeBehaviorSimilarity(a, b *models.Behavior, llmClient llm.Client, useL
 	})
 	return result.Score
 }
+
+// ComputeTunedSimilarity calculates rule-based similarity between two
+// behaviors using the weights from a store's similarity tuning.
+func ComputeTunedSimilarity(a, b *models.Behavior, tuning similarity.Tuning) float64 {
+	return tuning.Score(
+		similarity.ComputeWhenOverlap(a.When, b.When),
+		similarity.ComputeContentSimilarity(a.Content.Canonical, b.Content.Canonical),
+		similarity.ComputeTagSimilarity(a.Content.Tags, b.Content.Tags),
+	)
+}
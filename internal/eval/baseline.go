@@ -0,0 +1,112 @@
+package eval
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// BaselinesDir is the corpus subdirectory holding recorded baselines.
+const BaselinesDir = "baselines"
+
+// Baseline is a report's scores recorded for a release.
+type Baseline struct {
+	Version    string                `json:"version"`
+	RecordedAt time.Time             `json:"recorded_at"`
+	Examples   int                   `json:"examples"`
+	Scores     map[string]FieldScore `json:"scores"`
+}
+
+// Regression is a field whose accuracy dropped below its baseline by more
+// than the tolerance.
+type Regression struct {
+	Field    string  `json:"field"`
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+}
+
+// NewBaseline captures a report's scores for version.
+func NewBaseline(version string, report *Report, now time.Time) Baseline {
+	return Baseline{
+		Version:    version,
+		RecordedAt: now.UTC(),
+		Examples:   report.Examples,
+		Scores:     report.Scores,
+	}
+}
+
+// SaveBaseline writes b to <corpusDir>/baselines/<version>.json, replacing any
+// baseline already recorded for that version.
+func SaveBaseline(corpusDir string, b Baseline) (string, error) {
+	if b.Version == "" || strings.ContainsAny(b.Version, `/\`) {
+		return "", fmt.Errorf("invalid baseline version: %q", b.Version)
+	}
+	dir := filepath.Join(corpusDir, BaselinesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create baselines directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode baseline: %w", err)
+	}
+	path := filepath.Join(dir, b.Version+".json")
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write baseline: %w", err)
+	}
+	return path, nil
+}
+
+// LoadBaseline reads a baseline file.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var b Baseline
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("failed to parse baseline %s: %w", path, err)
+	}
+	return &b, nil
+}
+
+// LatestBaseline returns the most recently recorded baseline in the corpus,
+// or nil if none has been recorded.
+func LatestBaseline(corpusDir string) (*Baseline, error) {
+	paths, err := filepath.Glob(filepath.Join(corpusDir, BaselinesDir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	var latest *Baseline
+	for _, path := range paths {
+		b, err := LoadBaseline(path)
+		if err != nil {
+			return nil, err
+		}
+		if latest == nil || b.RecordedAt.After(latest.RecordedAt) {
+			latest = b
+		}
+	}
+	return latest, nil
+}
+
+// Compare returns the fields whose accuracy in report fell more than
+// tolerance below the baseline. Fields the baseline did not score are skipped.
+func Compare(b *Baseline, report *Report, tolerance float64) []Regression {
+	var regressions []Regression
+	for _, field := range Fields {
+		base, ok := b.Scores[field]
+		if !ok || base.Scored == 0 {
+			continue
+		}
+		current := report.Scores[field]
+		if current.Accuracy < base.Accuracy-tolerance {
+			regressions = append(regressions, Regression{Field: field, Baseline: base.Accuracy, Current: current.Accuracy})
+		}
+	}
+	return regressions
+}
@@ -0,0 +1,260 @@
+// Package eval measures the quality of floop's learning pipeline against
+// labeled corpora.
+//
+// An extraction corpus is a directory of JSONL files. Each line pairs a
+// correction with the behavior fields the extractor is expected to produce.
+// Evaluate scores an extractor on the corpus field by field; baselines record
+// those scores per release so extractor changes can be compared against them.
+package eval
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/learning"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// Example is one corpus entry: a correction and the behavior fields it should
+// produce.
+type Example struct {
+	ID         string            `json:"id"`
+	Correction models.Correction `json:"correction"`
+	Expected   Expected          `json:"expected"`
+}
+
+// Expected holds the behavior fields an example is scored on. Omitted fields
+// are not scored; an explicit empty when ({}) or tags ([]) expects none.
+type Expected struct {
+	Name string                 `json:"name,omitempty"`
+	Kind models.BehaviorKind    `json:"kind,omitempty"`
+	When map[string]interface{} `json:"when,omitempty"`
+	Tags []string               `json:"tags,omitempty"`
+}
+
+// UnmarshalJSON keeps explicit empty when/tags distinct from omitted ones.
+func (e *Expected) UnmarshalJSON(data []byte) error {
+	type plain Expected
+	var p plain
+	if err := json.Unmarshal(data, &p); err != nil {
+		return err
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(data, &keys); err != nil {
+		return err
+	}
+
+	*e = Expected(p)
+	if _, ok := keys["when"]; ok && e.When == nil {
+		e.When = map[string]interface{}{}
+	}
+	if _, ok := keys["tags"]; ok && e.Tags == nil {
+		e.Tags = []string{}
+	}
+	return nil
+}
+
+// Field names reported in scores and failures.
+const (
+	FieldName = "name"
+	FieldKind = "kind"
+	FieldWhen = "when"
+	FieldTags = "tags"
+)
+
+// Fields lists the scored fields in report order.
+var Fields = []string{FieldName, FieldKind, FieldWhen, FieldTags}
+
+// FieldScore is the accuracy of one field across the examples that specify it.
+// For tags, each example contributes its F1 score rather than 0 or 1.
+type FieldScore struct {
+	Scored   int     `json:"scored"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// Failure records an example whose extracted field did not match.
+type Failure struct {
+	ExampleID string      `json:"example_id"`
+	Field     string      `json:"field"`
+	Expected  interface{} `json:"expected"`
+	Got       interface{} `json:"got"`
+}
+
+// Report is the result of evaluating an extractor on a corpus.
+type Report struct {
+	Examples int                   `json:"examples"`
+	Scores   map[string]FieldScore `json:"scores"`
+	Failures []Failure             `json:"failures"`
+}
+
+// LoadCorpus reads every *.jsonl file in dir. Blank lines and lines starting
+// with # are skipped. Example IDs must be unique across the corpus.
+func LoadCorpus(dir string) ([]Example, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no *.jsonl files in %s", dir)
+	}
+	sort.Strings(paths)
+
+	var examples []Example
+	seen := make(map[string]string)
+	for _, path := range paths {
+		fileExamples, err := readCorpusFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, ex := range fileExamples {
+			if prev, dup := seen[ex.ID]; dup {
+				return nil, fmt.Errorf("duplicate example id %q in %s (first seen in %s)", ex.ID, filepath.Base(path), prev)
+			}
+			seen[ex.ID] = filepath.Base(path)
+			examples = append(examples, ex)
+		}
+	}
+	return examples, nil
+}
+
+func readCorpusFile(path string) ([]Example, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var examples []Example
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		var ex Example
+		if err := json.Unmarshal([]byte(line), &ex); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", filepath.Base(path), lineNum, err)
+		}
+		if ex.ID == "" {
+			return nil, fmt.Errorf("%s:%d: example is missing an id", filepath.Base(path), lineNum)
+		}
+		if ex.Correction.CorrectedAction == "" {
+			return nil, fmt.Errorf("%s:%d: example %q has no corrected_action", filepath.Base(path), lineNum, ex.ID)
+		}
+		examples = append(examples, ex)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return examples, nil
+}
+
+// Evaluate runs the extractor on each example and scores the fields the
+// example specifies.
+func Evaluate(extractor learning.BehaviorExtractor, examples []Example) (*Report, error) {
+	type tally struct {
+		scored int
+		total  float64
+	}
+	tallies := make(map[string]*tally, len(Fields))
+	for _, f := range Fields {
+		tallies[f] = &tally{}
+	}
+
+	report := &Report{Examples: len(examples), Failures: []Failure{}}
+	record := func(ex Example, field string, score float64, expected, got interface{}) {
+		t := tallies[field]
+		t.scored++
+		t.total += score
+		if score < 1 {
+			report.Failures = append(report.Failures, Failure{ExampleID: ex.ID, Field: field, Expected: expected, Got: got})
+		}
+	}
+
+	for _, ex := range examples {
+		behavior, err := extractor.Extract(ex.Correction)
+		if err != nil {
+			return nil, fmt.Errorf("example %s: %w", ex.ID, err)
+		}
+
+		if ex.Expected.Name != "" {
+			record(ex, FieldName, boolScore(behavior.Name == ex.Expected.Name), ex.Expected.Name, behavior.Name)
+		}
+		if ex.Expected.Kind != "" {
+			record(ex, FieldKind, boolScore(behavior.Kind == ex.Expected.Kind), ex.Expected.Kind, behavior.Kind)
+		}
+		if ex.Expected.When != nil {
+			record(ex, FieldWhen, boolScore(whenEqual(behavior.When, ex.Expected.When)), ex.Expected.When, behavior.When)
+		}
+		if ex.Expected.Tags != nil {
+			record(ex, FieldTags, tagF1(behavior.Content.Tags, ex.Expected.Tags), ex.Expected.Tags, behavior.Content.Tags)
+		}
+	}
+
+	report.Scores = make(map[string]FieldScore, len(Fields))
+	for _, f := range Fields {
+		t := tallies[f]
+		score := FieldScore{Scored: t.scored}
+		if t.scored > 0 {
+			score.Accuracy = t.total / float64(t.scored)
+		}
+		report.Scores[f] = score
+	}
+	return report, nil
+}
+
+func boolScore(ok bool) float64 {
+	if ok {
+		return 1
+	}
+	return 0
+}
+
+// whenEqual compares when predicates by key and stringified value.
+func whenEqual(got, want map[string]interface{}) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	for k, w := range want {
+		g, ok := got[k]
+		if !ok || fmt.Sprint(g) != fmt.Sprint(w) {
+			return false
+		}
+	}
+	return true
+}
+
+// tagF1 is the F1 score of got against want. Two empty sets score 1.
+func tagF1(got, want []string) float64 {
+	if len(got) == 0 && len(want) == 0 {
+		return 1
+	}
+	wantSet := make(map[string]bool, len(want))
+	for _, t := range want {
+		wantSet[t] = true
+	}
+	gotSet := make(map[string]bool, len(got))
+	for _, t := range got {
+		gotSet[t] = true
+	}
+	hits := 0
+	for t := range gotSet {
+		if wantSet[t] {
+			hits++
+		}
+	}
+	if hits == 0 {
+		return 0
+	}
+	precision := float64(hits) / float64(len(gotSet))
+	recall := float64(hits) / float64(len(wantSet))
+	return 2 * precision * recall / (precision + recall)
+}
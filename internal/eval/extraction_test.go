@@ -0,0 +1,172 @@
+package eval
+
+import (
+	"encoding/json"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/learning"
+)
+
+// seedCorpus is the corpus checked into the repo. Its recorded baselines
+// guard the extractor against quality regressions.
+const seedCorpus = "testdata/extraction"
+
+// TestExtractionRegression fails when the built-in extractor scores below the
+// latest recorded baseline on the seed corpus. After an intentional
+// improvement, re-record with:
+//
+//	go run ./cmd/floop eval extraction --corpus internal/eval/testdata/extraction --record-baseline --release dev
+func TestExtractionRegression(t *testing.T) {
+	examples, err := LoadCorpus(seedCorpus)
+	if err != nil {
+		t.Fatalf("LoadCorpus: %v", err)
+	}
+	report, err := Evaluate(learning.NewBehaviorExtractor(), examples)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	baseline, err := LatestBaseline(seedCorpus)
+	if err != nil {
+		t.Fatalf("LatestBaseline: %v", err)
+	}
+	if baseline == nil {
+		t.Fatal("seed corpus has no recorded baseline")
+	}
+	if baseline.Examples != report.Examples {
+		t.Errorf("baseline %s covers %d examples, corpus has %d; re-record the baseline", baseline.Version, baseline.Examples, report.Examples)
+	}
+	for _, r := range Compare(baseline, report, 0.001) {
+		t.Errorf("%s accuracy regressed: %.3f -> %.3f (baseline %s)", r.Field, r.Baseline, r.Current, baseline.Version)
+	}
+}
+
+func TestExpectedUnmarshal(t *testing.T) {
+	var omitted, empty Expected
+	if err := json.Unmarshal([]byte(`{"kind":"directive"}`), &omitted); err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(`{"when":{},"tags":[]}`), &empty); err != nil {
+		t.Fatal(err)
+	}
+
+	if omitted.When != nil || omitted.Tags != nil {
+		t.Errorf("omitted fields should be nil, got when=%v tags=%v", omitted.When, omitted.Tags)
+	}
+	if empty.When == nil || empty.Tags == nil {
+		t.Errorf("explicit empty fields should be non-nil, got when=%v tags=%v", empty.When, empty.Tags)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	examples := []Example{
+		{
+			ID:       "constraint",
+			Expected: Expected{Kind: "constraint", When: map[string]interface{}{"language": "go"}, Tags: []string{"git"}},
+		},
+		{
+			ID:       "wrong-kind",
+			Expected: Expected{Kind: "procedure"},
+		},
+	}
+	examples[0].Correction.CorrectedAction = "never force push with git"
+	examples[0].Correction.Context.FileLanguage = "go"
+	examples[1].Correction.CorrectedAction = "use tabs"
+
+	report, err := Evaluate(learning.NewBehaviorExtractor(), examples)
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+
+	if got := report.Scores[FieldKind]; got.Scored != 2 || got.Accuracy != 0.5 {
+		t.Errorf("kind score = %+v, want 2 scored at 0.5", got)
+	}
+	if got := report.Scores[FieldWhen]; got.Scored != 1 || got.Accuracy != 1 {
+		t.Errorf("when score = %+v, want 1 scored at 1", got)
+	}
+	if got := report.Scores[FieldName]; got.Scored != 0 {
+		t.Errorf("name should not be scored, got %+v", got)
+	}
+	if len(report.Failures) != 1 || report.Failures[0].ExampleID != "wrong-kind" {
+		t.Errorf("failures = %+v", report.Failures)
+	}
+}
+
+func TestTagF1(t *testing.T) {
+	tests := []struct {
+		name      string
+		got, want []string
+		score     float64
+	}{
+		{"both empty", nil, []string{}, 1},
+		{"exact", []string{"go", "git"}, []string{"git", "go"}, 1},
+		{"none found", nil, []string{"go"}, 0},
+		{"half recall", []string{"go"}, []string{"go", "git"}, 2.0 / 3.0},
+		{"extra tag", []string{"go", "git"}, []string{"go"}, 2.0 / 3.0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tagF1(tt.got, tt.want); math.Abs(got-tt.score) > 1e-9 {
+				t.Errorf("tagF1(%v, %v) = %v, want %v", tt.got, tt.want, got, tt.score)
+			}
+		})
+	}
+}
+
+func TestLoadCorpusErrors(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := LoadCorpus(dir); err == nil {
+		t.Error("expected error for empty corpus directory")
+	}
+
+	line := `{"id":"a","correction":{"corrected_action":"use uv"},"expected":{"kind":"preference"}}` + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "one.jsonl"), []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "two.jsonl"), []byte(line), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadCorpus(dir); err == nil {
+		t.Error("expected error for duplicate example id")
+	}
+}
+
+func TestBaselineRoundTrip(t *testing.T) {
+	dir := t.TempDir()
+	report := &Report{Examples: 2, Scores: map[string]FieldScore{
+		FieldKind: {Scored: 2, Accuracy: 1},
+		FieldTags: {Scored: 2, Accuracy: 0.5},
+	}}
+
+	old := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	if _, err := SaveBaseline(dir, NewBaseline("v0.1.0", report, old)); err != nil {
+		t.Fatalf("SaveBaseline: %v", err)
+	}
+	if _, err := SaveBaseline(dir, NewBaseline("v0.2.0", report, old.Add(24*time.Hour))); err != nil {
+		t.Fatalf("SaveBaseline: %v", err)
+	}
+	if _, err := SaveBaseline(dir, NewBaseline("../evil", report, old)); err == nil {
+		t.Error("expected error for version containing a path separator")
+	}
+
+	latest, err := LatestBaseline(dir)
+	if err != nil {
+		t.Fatalf("LatestBaseline: %v", err)
+	}
+	if latest == nil || latest.Version != "v0.2.0" {
+		t.Fatalf("latest = %+v, want v0.2.0", latest)
+	}
+
+	worse := &Report{Examples: 2, Scores: map[string]FieldScore{
+		FieldKind: {Scored: 2, Accuracy: 0.5},
+		FieldTags: {Scored: 2, Accuracy: 0.49},
+	}}
+	regressions := Compare(latest, worse, 0.02)
+	if len(regressions) != 1 || regressions[0].Field != FieldKind {
+		t.Errorf("regressions = %+v, want only kind", regressions)
+	}
+}
@@ -14,13 +14,24 @@ type TranscriptAdapter interface {
 // adapters is the global adapter registry. Registration must happen during init() — concurrent registration is not supported.
 var adapters = map[string]TranscriptAdapter{}
 
+// aliases maps short format names to registered format names.
+var aliases = map[string]string{}
+
 // RegisterAdapter registers a TranscriptAdapter by its format name.
 func RegisterAdapter(a TranscriptAdapter) {
 	adapters[a.Format()] = a
 }
 
-// GetAdapter returns the adapter registered for the given format, if any.
+// RegisterAlias lets a short name (e.g. "claude") select a registered format.
+func RegisterAlias(alias, format string) {
+	aliases[alias] = format
+}
+
+// GetAdapter returns the adapter registered for the given format or alias, if any.
 func GetAdapter(format string) (TranscriptAdapter, bool) {
+	if target, ok := aliases[format]; ok {
+		format = target
+	}
 	a, ok := adapters[format]
 	return a, ok
 }
@@ -0,0 +1,178 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// MetaKeyFiles is the event metadata key listing files a Cursor message
+// attached or referenced.
+const MetaKeyFiles = "files"
+
+// CursorAdapter parses exported Cursor chat history into Events. It accepts
+// the chat panel layout ({"tabs": [{"bubbles": [...]}]}, bubbles typed
+// "user" or "ai") and the composer layout ({"conversation": [...]}, entries
+// typed 1 for user and 2 for AI), as a single object or an array of them.
+type CursorAdapter struct {
+	Source string
+}
+
+// Format returns the adapter format name.
+func (a *CursorAdapter) Format() string { return "cursor" }
+
+// Parse reads a Cursor chat export and maps each message to an Event.
+func (a *CursorAdapter) Parse(reader io.Reader) ([]Event, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("reading Cursor export: %w", err)
+	}
+
+	var chats []map[string]any
+	if err := json.Unmarshal(data, &chats); err != nil {
+		var single map[string]any
+		if err := json.Unmarshal(data, &single); err != nil {
+			return nil, fmt.Errorf("parsing Cursor export: %w", err)
+		}
+		chats = []map[string]any{single}
+	}
+
+	now := time.Now()
+	var events []Event
+	add := func(sessionID string, msg map[string]any) {
+		actor, ok := cursorActor(msg["type"])
+		if !ok {
+			return
+		}
+		content, _ := msg["text"].(string)
+		if content == "" {
+			content, _ = msg["rawText"].(string)
+		}
+		ts := now.Add(time.Duration(len(events)) * time.Millisecond)
+		for _, key := range []string{"timestamp", "createdAt"} {
+			if ms, ok := msg[key].(float64); ok && ms > 0 {
+				ts = time.UnixMilli(int64(ms))
+				break
+			}
+		}
+		event := Event{
+			ID:        generateEventID(),
+			SessionID: sessionID,
+			Timestamp: ts,
+			Source:    a.Source,
+			Actor:     actor,
+			Kind:      KindMessage,
+			Content:   content,
+			CreatedAt: now,
+		}
+		if files := cursorFiles(msg); len(files) > 0 {
+			event.Metadata = map[string]any{MetaKeyFiles: files}
+		}
+		events = append(events, event)
+	}
+
+	for _, chat := range chats {
+		if tabs, ok := chat["tabs"].([]any); ok {
+			for _, t := range tabs {
+				tab, ok := t.(map[string]any)
+				if !ok {
+					continue
+				}
+				sessionID := stringOr(tab["tabId"], generateSessionID())
+				for _, b := range asMaps(tab["bubbles"]) {
+					add(sessionID, b)
+				}
+			}
+			continue
+		}
+		if conversation, ok := chat["conversation"].([]any); ok {
+			sessionID := stringOr(chat["composerId"], generateSessionID())
+			for _, m := range asMaps(conversation) {
+				add(sessionID, m)
+			}
+			continue
+		}
+		return nil, fmt.Errorf("parsing Cursor export: expected \"tabs\" or \"conversation\"")
+	}
+	return events, nil
+}
+
+// cursorActor maps a Cursor message type to an EventActor.
+func cursorActor(typ any) (EventActor, bool) {
+	switch t := typ.(type) {
+	case string:
+		switch strings.ToLower(t) {
+		case "user":
+			return ActorUser, true
+		case "ai", "assistant":
+			return ActorAgent, true
+		}
+	case float64:
+		switch t {
+		case 1:
+			return ActorUser, true
+		case 2:
+			return ActorAgent, true
+		}
+	}
+	return "", false
+}
+
+// cursorFileKeys are message fields that hold attached or referenced files.
+var cursorFileKeys = []string{"fileSelections", "selections", "relevantFiles", "context"}
+
+// cursorFiles collects the file paths a Cursor message references.
+func cursorFiles(msg map[string]any) []string {
+	seen := make(map[string]bool)
+	var files []string
+	var walk func(v any)
+	walk = func(v any) {
+		switch x := v.(type) {
+		case string:
+			if x != "" && !seen[x] {
+				seen[x] = true
+				files = append(files, x)
+			}
+		case []any:
+			for _, item := range x {
+				walk(item)
+			}
+		case map[string]any:
+			for _, key := range []string{"relativeWorkspacePath", "fsPath", "path"} {
+				if p, ok := x[key].(string); ok {
+					walk(p)
+					return
+				}
+			}
+			for _, key := range append([]string{"uri"}, cursorFileKeys...) {
+				if inner, ok := x[key]; ok {
+					walk(inner)
+				}
+			}
+		}
+	}
+	for _, key := range cursorFileKeys {
+		walk(msg[key])
+	}
+	return files
+}
+
+func asMaps(v any) []map[string]any {
+	items, _ := v.([]any)
+	maps := make([]map[string]any, 0, len(items))
+	for _, item := range items {
+		if m, ok := item.(map[string]any); ok {
+			maps = append(maps, m)
+		}
+	}
+	return maps
+}
+
+func stringOr(v any, fallback string) string {
+	if s, ok := v.(string); ok && s != "" {
+		return s
+	}
+	return fallback
+}
@@ -0,0 +1,86 @@
+package events
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCursorAdapter_Format(t *testing.T) {
+	a := &CursorAdapter{}
+	if got := a.Format(); got != "cursor" {
+		t.Errorf("Format() = %q, want %q", got, "cursor")
+	}
+}
+
+func TestCursorAdapter_Parse(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		wantActors []EventActor
+		wantTexts  []string
+		wantFiles  [][]string
+		wantSess   string
+	}{
+		{
+			name: "chat panel tabs",
+			input: `{"tabs": [{"tabId": "tab-1", "bubbles": [
+				{"type": "user", "text": "add retries", "selections": [{"uri": {"fsPath": "/repo/client.go"}, "text": "func Get()"}]},
+				{"type": "ai", "rawText": "Added a retry loop."},
+				{"type": "system", "text": "ignored"}
+			]}]}`,
+			wantActors: []EventActor{ActorUser, ActorAgent},
+			wantTexts:  []string{"add retries", "Added a retry loop."},
+			wantFiles:  [][]string{{"/repo/client.go"}, nil},
+			wantSess:   "tab-1",
+		},
+		{
+			name: "composer conversation array",
+			input: `[{"composerId": "c-9", "conversation": [
+				{"type": 1, "text": "no, use errgroup", "context": {"fileSelections": [{"uri": {"fsPath": "/repo/sync.go"}}]}, "relevantFiles": ["sync.go"]},
+				{"type": 2, "text": "Switched to errgroup.", "timestamp": 1700000000000}
+			]}]`,
+			wantActors: []EventActor{ActorUser, ActorAgent},
+			wantTexts:  []string{"no, use errgroup", "Switched to errgroup."},
+			wantFiles:  [][]string{{"sync.go", "/repo/sync.go"}, nil},
+			wantSess:   "c-9",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := (&CursorAdapter{Source: "cursor"}).Parse(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if len(got) != len(tt.wantActors) {
+				t.Fatalf("got %d events, want %d", len(got), len(tt.wantActors))
+			}
+			for i, e := range got {
+				if e.Actor != tt.wantActors[i] || e.Content != tt.wantTexts[i] || e.SessionID != tt.wantSess {
+					t.Errorf("event %d = %s %q session %q", i, e.Actor, e.Content, e.SessionID)
+				}
+				files, _ := e.Metadata[MetaKeyFiles].([]string)
+				if !reflect.DeepEqual(files, tt.wantFiles[i]) {
+					t.Errorf("event %d files = %v, want %v", i, files, tt.wantFiles[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCursorAdapter_ParseErrors(t *testing.T) {
+	for _, input := range []string{"not json", `{"messages": []}`} {
+		if _, err := (&CursorAdapter{}).Parse(strings.NewReader(input)); err == nil {
+			t.Errorf("Parse(%q) expected error", input)
+		}
+	}
+}
+
+func TestGetAdapter_Alias(t *testing.T) {
+	RegisterAdapter(&JSONLAdapter{})
+	RegisterAlias("claude", "claude-code-jsonl")
+	a, ok := GetAdapter("claude")
+	if !ok || a.Format() != "claude-code-jsonl" {
+		t.Errorf("GetAdapter(claude) = %v, %v", a, ok)
+	}
+}
@@ -235,6 +235,27 @@ func (s *SQLiteEventStore) Prune(ctx context.Context, olderThan time.Duration) (
 	return int(n), nil
 }
 
+// DeleteByProject deletes all events recorded for the given project ID and
+// returns the count deleted. Used when a project is deinitialized.
+func (s *SQLiteEventStore) DeleteByProject(ctx context.Context, projectID string) (int, error) {
+	if projectID == "" {
+		return 0, nil
+	}
+	result, err := s.db.ExecContext(ctx, `
+		DELETE FROM events
+		WHERE project_id = ?
+	`, projectID)
+	if err != nil {
+		return 0, fmt.Errorf("deleting project events: %w", err)
+	}
+
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("getting rows affected: %w", err)
+	}
+	return int(n), nil
+}
+
 // Count returns the total number of events in the store.
 func (s *SQLiteEventStore) Count(ctx context.Context) (int, error) {
 	var count int
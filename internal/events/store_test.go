@@ -266,6 +266,46 @@ func TestPrune(t *testing.T) {
 	}
 }
 
+func TestDeleteByProject(t *testing.T) {
+	store := newTestStore(t)
+	ctx := context.Background()
+
+	now := time.Now().Truncate(time.Microsecond)
+	events := []Event{
+		{ID: "evt-a1", SessionID: "s1", Timestamp: now, Source: "test", Actor: ActorUser, Kind: KindMessage, Content: "a1", ProjectID: "proj-a", CreatedAt: now},
+		{ID: "evt-a2", SessionID: "s1", Timestamp: now, Source: "test", Actor: ActorAgent, Kind: KindMessage, Content: "a2", ProjectID: "proj-a", CreatedAt: now},
+		{ID: "evt-b1", SessionID: "s2", Timestamp: now, Source: "test", Actor: ActorUser, Kind: KindMessage, Content: "b1", ProjectID: "proj-b", CreatedAt: now},
+	}
+	if err := store.AddBatch(ctx, events); err != nil {
+		t.Fatalf("AddBatch: %v", err)
+	}
+
+	deleted, err := store.DeleteByProject(ctx, "proj-a")
+	if err != nil {
+		t.Fatalf("DeleteByProject: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("deleted = %d, want 2", deleted)
+	}
+
+	count, err := store.Count(ctx)
+	if err != nil {
+		t.Fatalf("Count: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+
+	// Empty project ID is a no-op
+	deleted, err = store.DeleteByProject(ctx, "")
+	if err != nil {
+		t.Fatalf("DeleteByProject empty: %v", err)
+	}
+	if deleted != 0 {
+		t.Errorf("deleted = %d, want 0 for empty project ID", deleted)
+	}
+}
+
 func TestCount(t *testing.T) {
 	store := newTestStore(t)
 	ctx := context.Background()
@@ -0,0 +1,100 @@
+// Package expiry retires temporary behaviors whose expiry has passed.
+// Activation already skips expired behaviors; Run marks them dormant so
+// they also drop out of listings, stats, and injection candidates, while
+// staying restorable with 'floop restore'.
+package expiry
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Reason is recorded as dormant_reason on behaviors made dormant by Run.
+const Reason = "expired"
+
+// Options configures an expiry run.
+type Options struct {
+	// Store holds the behaviors.
+	Store store.GraphStore
+
+	// DryRun reports the changes without writing them.
+	DryRun bool
+
+	// Now is the reference time; zero uses time.Now.
+	Now time.Time
+}
+
+// Expired is one behavior made dormant.
+type Expired struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Report summarizes an expiry run.
+type Report struct {
+	Scope   string    `json:"scope,omitempty"`
+	DryRun  bool      `json:"dry_run"`
+	Dormant []Expired `json:"dormant"`
+	RanAt   time.Time `json:"ran_at"`
+}
+
+// Run marks every active behavior whose expiry is at or before opts.Now as
+// dormant. The original kind is kept in original_kind so 'floop restore'
+// can bring it back.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Store == nil {
+		return nil, fmt.Errorf("expiry: no store")
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	nodes, err := opts.Store.QueryNodes(ctx, map[string]interface{}{
+		"kind":                       string(store.NodeKindBehavior),
+		store.PredicateExpiresBefore: now,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("querying expired behaviors: %w", err)
+	}
+
+	report := &Report{DryRun: opts.DryRun, Dormant: []Expired{}, RanAt: now}
+	for _, node := range nodes {
+		expiresAt, ok := models.ParseExpiresAt(node.Metadata)
+		if !ok {
+			continue
+		}
+		report.Dormant = append(report.Dormant, Expired{
+			ID:        node.ID,
+			Name:      models.NodeToBehavior(node).Name,
+			ExpiresAt: expiresAt,
+		})
+		if opts.DryRun {
+			continue
+		}
+
+		node.Metadata["original_kind"] = node.Kind
+		node.Metadata["dormant_at"] = now.Format(time.RFC3339)
+		node.Metadata["dormant_reason"] = Reason
+		node.Kind = store.NodeKindDormant
+		if err := opts.Store.UpdateNode(ctx, node); err != nil {
+			return nil, fmt.Errorf("updating %s: %w", node.ID, err)
+		}
+	}
+
+	if !opts.DryRun && len(report.Dormant) > 0 {
+		if err := opts.Store.Sync(ctx); err != nil {
+			return nil, fmt.Errorf("syncing store: %w", err)
+		}
+	}
+	sort.Slice(report.Dormant, func(i, j int) bool {
+		return report.Dormant[i].ExpiresAt.Before(report.Dormant[j].ExpiresAt)
+	})
+	return report, nil
+}
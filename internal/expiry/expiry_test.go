@@ -0,0 +1,62 @@
+package expiry
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestRun(t *testing.T) {
+	ctx := context.Background()
+	s, err := store.NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	past, future := now.Add(-time.Hour), now.Add(24*time.Hour)
+	for _, b := range []models.Behavior{
+		{ID: "expired", Name: "expired", Kind: models.BehaviorKindDirective, ExpiresAt: &past},
+		{ID: "expiring", Name: "expiring", Kind: models.BehaviorKindDirective, ExpiresAt: &future},
+		{ID: "permanent", Name: "permanent", Kind: models.BehaviorKindDirective},
+	} {
+		b.Content.Canonical = b.Name + " behavior"
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&b)); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", b.ID, err)
+		}
+	}
+
+	report, err := Run(ctx, Options{Store: s, DryRun: true, Now: now})
+	if err != nil {
+		t.Fatalf("Run(dry) error = %v", err)
+	}
+	if len(report.Dormant) != 1 || report.Dormant[0].ID != "expired" || !report.Dormant[0].ExpiresAt.Equal(past) {
+		t.Fatalf("dry run report = %+v", report)
+	}
+	if n, _ := s.GetNode(ctx, "expired"); n.Kind != store.NodeKindBehavior {
+		t.Errorf("dry run changed kind to %s", n.Kind)
+	}
+
+	if _, err := Run(ctx, Options{Store: s, Now: now}); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	n, _ := s.GetNode(ctx, "expired")
+	if n.Kind != store.NodeKindDormant || n.Metadata["original_kind"] != string(store.NodeKindBehavior) ||
+		n.Metadata["dormant_reason"] != Reason {
+		t.Errorf("expired behavior = %s %v, want dormant", n.Kind, n.Metadata)
+	}
+	for _, id := range []string{"expiring", "permanent"} {
+		if n, _ := s.GetNode(ctx, id); n.Kind != store.NodeKindBehavior {
+			t.Errorf("%s kind = %s, want unchanged", id, n.Kind)
+		}
+	}
+
+	// Dormant behaviors are not picked up again.
+	if again, _ := Run(ctx, Options{Store: s, Now: now}); len(again.Dormant) != 0 {
+		t.Errorf("second run made %+v dormant", again.Dormant)
+	}
+}
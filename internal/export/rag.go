@@ -0,0 +1,128 @@
+// Package export converts the behavior graph into formats consumed by
+// external tools.
+package export
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// RAGRecord is one behavior in a RAG corpus export.
+//
+// ID is the behavior ID and is stable across exports, so it can be used as the
+// primary key when syncing into an external vector database. ContentHash
+// covers every other field, so it changes whenever the exported record
+// does; consumers can skip records whose hash they already hold.
+type RAGRecord struct {
+	ID             string                 `json:"id"`
+	ContentHash    string                 `json:"content_hash"`
+	Text           string                 `json:"text"`
+	Summary        string                 `json:"summary,omitempty"`
+	Name           string                 `json:"name"`
+	Kind           string                 `json:"kind"`
+	MemoryType     string                 `json:"memory_type,omitempty"`
+	Scope          string                 `json:"scope,omitempty"`
+	Tags           []string               `json:"tags"`
+	When           map[string]interface{} `json:"when,omitempty"`
+	Confidence     float64                `json:"confidence"`
+	Priority       int                    `json:"priority"`
+	Pack           string                 `json:"pack,omitempty"`
+	CreatedAt      time.Time              `json:"created_at"`
+	UpdatedAt      time.Time              `json:"updated_at"`
+	Embedding      []float32              `json:"embedding,omitempty"`
+	EmbeddingModel string                 `json:"embedding_model,omitempty"`
+}
+
+// RAGOptions controls which data is included in a RAG export.
+type RAGOptions struct {
+	// Scope labels every record with the store it came from (e.g. "local").
+	Scope string
+
+	// IncludeEmbeddings attaches stored embedding vectors when the store
+	// supports them. Behaviors without an embedding are still exported.
+	IncludeEmbeddings bool
+}
+
+// BuildRAGRecords returns one record per active behavior in s, sorted by ID.
+// Forgotten, deprecated, and merged behaviors are excluded.
+func BuildRAGRecords(ctx context.Context, s store.GraphStore, opts RAGOptions) ([]RAGRecord, error) {
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+
+	embeddings := make(map[string]store.BehaviorEmbedding)
+	if opts.IncludeEmbeddings {
+		if es, ok := s.(store.EmbeddingStore); ok {
+			all, err := es.GetAllEmbeddings(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load embeddings: %w", err)
+			}
+			for _, e := range all {
+				embeddings[e.BehaviorID] = e
+			}
+		}
+	}
+
+	records := make([]RAGRecord, 0, len(nodes))
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+		rec := RAGRecord{
+			ID:         b.ID,
+			Text:       b.Content.Canonical,
+			Summary:    b.Content.Summary,
+			Name:       b.Name,
+			Kind:       string(b.Kind),
+			MemoryType: string(b.MemoryType),
+			Scope:      opts.Scope,
+			Tags:       b.Content.Tags,
+			When:       b.When,
+			Confidence: b.Confidence,
+			Priority:   b.Priority,
+			Pack:       b.Provenance.Package,
+			CreatedAt:  b.Stats.CreatedAt,
+			UpdatedAt:  b.Stats.UpdatedAt,
+		}
+		if rec.Tags == nil {
+			rec.Tags = []string{}
+		}
+		if e, ok := embeddings[b.ID]; ok {
+			rec.Embedding = e.Embedding
+			rec.EmbeddingModel = e.ModelName
+		}
+		rec.ContentHash = ragContentHash(rec)
+		records = append(records, rec)
+	}
+
+	sort.Slice(records, func(i, j int) bool { return records[i].ID < records[j].ID })
+	return records, nil
+}
+
+// WriteRAGJSONL writes records to w, one JSON object per line.
+func WriteRAGJSONL(w io.Writer, records []RAGRecord) error {
+	enc := json.NewEncoder(w)
+	for _, rec := range records {
+		if err := enc.Encode(rec); err != nil {
+			return fmt.Errorf("failed to write record %s: %w", rec.ID, err)
+		}
+	}
+	return nil
+}
+
+// ragContentHash hashes the serialized record without its ContentHash, so
+// any change to what is exported, metadata included, changes the hash.
+func ragContentHash(rec RAGRecord) string {
+	rec.ContentHash = ""
+	data, _ := json.Marshal(rec)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func addBehavior(t *testing.T, s store.GraphStore, id, kind, canonical string, tags []interface{}) {
+	t.Helper()
+	if _, err := s.AddNode(context.Background(), store.Node{
+		ID:   id,
+		Kind: store.NodeKind(kind),
+		Content: map[string]interface{}{
+			"name": id,
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": canonical,
+				"tags":      tags,
+			},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.8},
+	}); err != nil {
+		t.Fatalf("AddNode(%s): %v", id, err)
+	}
+}
+
+func TestBuildRAGRecords(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+
+	addBehavior(t, s, "b-2", "behavior", "Wrap errors with context", []interface{}{"go", "errors"})
+	addBehavior(t, s, "b-1", "behavior", "Use table-driven tests", nil)
+	addBehavior(t, s, "b-gone", string(store.NodeKindForgotten), "Old advice", nil)
+	if err := s.StoreEmbedding(ctx, "b-2", []float32{0.1, 0.2, 0.3}, "test-model"); err != nil {
+		t.Fatalf("StoreEmbedding: %v", err)
+	}
+
+	records, err := BuildRAGRecords(ctx, s, RAGOptions{Scope: "local", IncludeEmbeddings: true})
+	if err != nil {
+		t.Fatalf("BuildRAGRecords: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("got %d records, want 2 (forgotten excluded)", len(records))
+	}
+	if records[0].ID != "b-1" || records[1].ID != "b-2" {
+		t.Errorf("records not sorted by ID: %s, %s", records[0].ID, records[1].ID)
+	}
+
+	r1, r2 := records[0], records[1]
+	if r1.Text != "Use table-driven tests" || r1.Scope != "local" {
+		t.Errorf("unexpected record: %+v", r1)
+	}
+	if r1.Tags == nil || r1.Embedding != nil {
+		t.Errorf("b-1 should have empty tags and no embedding: %+v", r1)
+	}
+	if len(r2.Embedding) != 3 || r2.EmbeddingModel != "test-model" {
+		t.Errorf("b-2 embedding = %v (%q)", r2.Embedding, r2.EmbeddingModel)
+	}
+	if r1.ContentHash == "" || r1.ContentHash == r2.ContentHash {
+		t.Errorf("content hashes should be set and distinct: %q %q", r1.ContentHash, r2.ContentHash)
+	}
+
+	without, err := BuildRAGRecords(ctx, s, RAGOptions{})
+	if err != nil {
+		t.Fatalf("BuildRAGRecords without embeddings: %v", err)
+	}
+	if without[1].Embedding != nil {
+		t.Error("embeddings should be omitted when IncludeEmbeddings is false")
+	}
+}
+
+func TestRAGContentHash_Stable(t *testing.T) {
+	base := RAGRecord{ID: "b-1", Text: "Use gofmt", Tags: []string{"go"}, Confidence: 0.5}
+	h := ragContentHash(base)
+
+	withHash := base
+	withHash.ContentHash = h
+	if ragContentHash(withHash) != h {
+		t.Error("the hash should not cover itself")
+	}
+
+	changes := map[string]func(*RAGRecord){
+		"text":        func(r *RAGRecord) { r.Text = "Use gofumpt" },
+		"confidence":  func(r *RAGRecord) { r.Confidence = 0.9 },
+		"priority":    func(r *RAGRecord) { r.Priority = 3 },
+		"pack":        func(r *RAGRecord) { r.Pack = "org/pack" },
+		"memory_type": func(r *RAGRecord) { r.MemoryType = "semantic" },
+		"scope":       func(r *RAGRecord) { r.Scope = "global" },
+	}
+	for field, change := range changes {
+		rec := base
+		change(&rec)
+		if ragContentHash(rec) == h {
+			t.Errorf("%s change should alter content hash", field)
+		}
+	}
+}
+
+func TestWriteRAGJSONL(t *testing.T) {
+	records := []RAGRecord{
+		{ID: "b-1", Text: "one", Tags: []string{}},
+		{ID: "b-2", Text: "two", Tags: []string{"x"}},
+	}
+
+	var buf bytes.Buffer
+	if err := WriteRAGJSONL(&buf, records); err != nil {
+		t.Fatalf("WriteRAGJSONL: %v", err)
+	}
+
+	var ids []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var rec RAGRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("line is not valid JSON: %v", err)
+		}
+		ids = append(ids, rec.ID)
+	}
+	if len(ids) != 2 || ids[0] != "b-1" || ids[1] != "b-2" {
+		t.Errorf("ids = %v", ids)
+	}
+}
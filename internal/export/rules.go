@@ -0,0 +1,45 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Markers delimiting the floop-managed section of a rules file such as
+// CLAUDE.md or AGENTS.md. Everything outside them is left untouched.
+const (
+	RulesBeginMarker = "<!-- floop:begin -->"
+	RulesEndMarker   = "<!-- floop:end -->"
+)
+
+// rulesNotice follows the begin marker to warn editors that the section is
+// regenerated.
+const rulesNotice = "<!-- Managed by 'floop export rules --sync'; edits between these markers are overwritten. -->"
+
+// SpliceRules returns doc with the floop-managed section replaced by
+// section. A doc without markers keeps its content and gets the section
+// appended after a blank line; an empty doc becomes just the section.
+// Unbalanced or repeated markers are an error rather than a guess, so
+// human-authored text is never overwritten.
+func SpliceRules(doc, section string) (string, error) {
+	block := RulesBeginMarker + "\n" + rulesNotice + "\n" + strings.TrimRight(section, "\n") + "\n" + RulesEndMarker
+
+	begins := strings.Count(doc, RulesBeginMarker)
+	ends := strings.Count(doc, RulesEndMarker)
+	switch {
+	case begins == 0 && ends == 0:
+		if strings.TrimSpace(doc) == "" {
+			return block + "\n", nil
+		}
+		return strings.TrimRight(doc, "\n") + "\n\n" + block + "\n", nil
+	case begins != 1 || ends != 1:
+		return "", fmt.Errorf("expected one %s and one %s marker, found %d and %d", RulesBeginMarker, RulesEndMarker, begins, ends)
+	}
+
+	start := strings.Index(doc, RulesBeginMarker)
+	end := strings.Index(doc, RulesEndMarker)
+	if end < start {
+		return "", fmt.Errorf("%s appears before %s", RulesEndMarker, RulesBeginMarker)
+	}
+	return doc[:start] + block + doc[end+len(RulesEndMarker):], nil
+}
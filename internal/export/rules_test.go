@@ -0,0 +1,54 @@
+package export
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSpliceRules(t *testing.T) {
+	section := "## Learned Behaviors\n\n- Use slog\n"
+
+	got, err := SpliceRules("", section)
+	if err != nil {
+		t.Fatalf("SpliceRules(empty): %v", err)
+	}
+	if !strings.HasPrefix(got, RulesBeginMarker+"\n") || !strings.HasSuffix(got, RulesEndMarker+"\n") {
+		t.Errorf("empty doc should become just the section, got:\n%s", got)
+	}
+
+	doc := "# Project\n\nHand-written notes.\n"
+	appended, err := SpliceRules(doc, section)
+	if err != nil {
+		t.Fatalf("SpliceRules(no markers): %v", err)
+	}
+	if !strings.HasPrefix(appended, doc+"\n"+RulesBeginMarker) {
+		t.Errorf("section should be appended after existing content, got:\n%s", appended)
+	}
+
+	// Replacing keeps the text around the markers and is idempotent.
+	withFooter := appended + "\n## Footer\n"
+	updated, err := SpliceRules(withFooter, "## Learned Behaviors\n\n- Use zap\n")
+	if err != nil {
+		t.Fatalf("SpliceRules(markers): %v", err)
+	}
+	if strings.Contains(updated, "Use slog") || !strings.Contains(updated, "Use zap") {
+		t.Errorf("section not replaced:\n%s", updated)
+	}
+	if !strings.HasPrefix(updated, doc) || !strings.HasSuffix(updated, "\n## Footer\n") {
+		t.Errorf("content outside the markers changed:\n%s", updated)
+	}
+	again, _ := SpliceRules(updated, "## Learned Behaviors\n\n- Use zap\n")
+	if again != updated {
+		t.Errorf("splicing the same section twice changed the doc:\n%s\n---\n%s", updated, again)
+	}
+
+	for name, bad := range map[string]string{
+		"missing end":  "a\n" + RulesBeginMarker + "\nb\n",
+		"end first":    RulesEndMarker + "\n" + RulesBeginMarker + "\n",
+		"two sections": appended + appended,
+	} {
+		if _, err := SpliceRules(bad, section); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
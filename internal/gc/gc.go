@@ -0,0 +1,231 @@
+// Package gc removes data that outlived the behaviors and packs it belonged
+// to: vector index entries and stored embeddings for behaviors that were
+// forgotten, deprecated, merged, or deleted, and pack download cache files
+// for packs that are no longer installed.
+package gc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/pack"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
+)
+
+// StateFile is the name of the file in .floop/ recording the last GC run.
+const StateFile = "gc-state.json"
+
+// Options configures a garbage collection run.
+type Options struct {
+	// Store holds the live behaviors. Required when Index is set.
+	Store store.GraphStore
+
+	// Index is the vector index to clean. Nil skips vector collection.
+	Index vectorindex.VectorIndex
+
+	// IndexDir is the index's on-disk directory, used to measure reclaimed
+	// space. Empty reports no vector bytes.
+	IndexDir string
+
+	// CacheDir is the pack download cache. Empty skips cache collection.
+	CacheDir string
+
+	// Installed lists the installed packs whose cache files are kept.
+	Installed []config.InstalledPack
+
+	// CacheMaxAge also evicts cache files not used for this long, except
+	// those installed packs were installed from. Zero disables it.
+	CacheMaxAge time.Duration
+
+	// DryRun reports orphans without removing anything.
+	DryRun bool
+
+	// Now is the reference time; zero uses time.Now.
+	Now time.Time
+}
+
+// Report summarizes a garbage collection run. In a dry run the counts and
+// byte totals describe what would be removed; vector bytes are only known
+// after removal and are zero.
+type Report struct {
+	DryRun             bool             `json:"dry_run"`
+	OrphanedVectors    []string         `json:"orphaned_vectors"`
+	VectorBytes        int64            `json:"vector_bytes_reclaimed"`
+	ClearedEmbeddings  int              `json:"cleared_embeddings"`
+	OrphanedCacheFiles []pack.CacheFile `json:"orphaned_cache_files"`
+	CacheBytes         int64            `json:"cache_bytes_reclaimed"`
+	Warnings           []string         `json:"warnings,omitempty"`
+	RanAt              time.Time        `json:"ran_at"`
+}
+
+// ReclaimedBytes returns the total space reclaimed (or reclaimable).
+func (r *Report) ReclaimedBytes() int64 {
+	return r.VectorBytes + r.CacheBytes
+}
+
+// embeddingClearer is implemented by stores that can drop embeddings of
+// inactive behaviors.
+type embeddingClearer interface {
+	ClearOrphanedEmbeddings(ctx context.Context) (int, error)
+}
+
+// Run cross-references the vector index and pack cache against the store
+// and installed packs and removes orphans. Problems with one target are
+// recorded as warnings so the other is still collected.
+func Run(ctx context.Context, opts Options) (*Report, error) {
+	if opts.Index != nil && opts.Store == nil {
+		return nil, fmt.Errorf("gc: a store is required to collect vectors")
+	}
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	report := &Report{DryRun: opts.DryRun, RanAt: now}
+
+	if opts.Index != nil {
+		if err := collectVectors(ctx, opts, report); err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+		}
+	}
+
+	if opts.CacheDir != "" {
+		orphans, err := pack.FindEvictableCache(opts.CacheDir, opts.Installed, now, opts.CacheMaxAge)
+		if err != nil {
+			report.Warnings = append(report.Warnings, err.Error())
+		} else {
+			report.OrphanedCacheFiles = orphans
+			if opts.DryRun {
+				for _, f := range orphans {
+					report.CacheBytes += f.Size
+				}
+			} else {
+				n, err := pack.RemoveCacheFiles(opts.CacheDir, orphans)
+				report.CacheBytes = n
+				if err != nil {
+					report.Warnings = append(report.Warnings, err.Error())
+				}
+			}
+		}
+	}
+
+	return report, nil
+}
+
+func collectVectors(ctx context.Context, opts Options, report *Report) error {
+	live, err := LiveBehaviorIDs(ctx, opts.Store)
+	if err != nil {
+		return err
+	}
+	orphans, err := vectorindex.FindOrphans(ctx, opts.Index, live)
+	if err != nil {
+		return err
+	}
+	report.OrphanedVectors = orphans
+	if opts.DryRun {
+		return nil
+	}
+
+	// Clear stored embeddings first so the next index sync does not
+	// re-add the vectors removed below.
+	if c, ok := opts.Store.(embeddingClearer); ok {
+		n, err := c.ClearOrphanedEmbeddings(ctx)
+		if err != nil {
+			return err
+		}
+		report.ClearedEmbeddings = n
+	}
+
+	before := dirSize(opts.IndexDir)
+	if _, err := vectorindex.RemoveOrphans(ctx, opts.Index, orphans); err != nil {
+		return err
+	}
+	if after := dirSize(opts.IndexDir); before > after {
+		report.VectorBytes = before - after
+	}
+	return nil
+}
+
+// liveKinds are the node kinds whose vectors GC keeps: active behaviors
+// plus pending and dormant ones, which may still become active.
+var liveKinds = []store.NodeKind{
+	store.NodeKindBehavior,
+	store.NodeKindPending,
+	store.NodeKindDormant,
+}
+
+// LiveBehaviorIDs returns the IDs of active, pending, and dormant behaviors in s.
+func LiveBehaviorIDs(ctx context.Context, s store.GraphStore) (map[string]bool, error) {
+	live := make(map[string]bool)
+	for _, kind := range liveKinds {
+		nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(kind)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query %s nodes: %w", kind, err)
+		}
+		for _, n := range nodes {
+			live[n.ID] = true
+		}
+	}
+	return live, nil
+}
+
+// dirSize returns the total size of regular files under dir, or 0 if dir
+// is empty or unreadable.
+func dirSize(dir string) int64 {
+	if dir == "" {
+		return 0
+	}
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
+
+// state is the persisted record of the last GC run.
+type state struct {
+	LastRun        time.Time `json:"last_run"`
+	ReclaimedBytes int64     `json:"reclaimed_bytes"`
+}
+
+// Due reports whether GC should run: interval has elapsed since the last
+// run recorded in floopDir, or it has never run. A non-positive interval
+// disables scheduled runs.
+func Due(floopDir string, interval time.Duration, now time.Time) bool {
+	if interval <= 0 {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(floopDir, StateFile))
+	if err != nil {
+		return true
+	}
+	var st state
+	if err := json.Unmarshal(data, &st); err != nil {
+		return true
+	}
+	return now.Sub(st.LastRun) >= interval
+}
+
+// MarkRun records a completed run in floopDir.
+func MarkRun(floopDir string, report *Report) error {
+	data, err := json.Marshal(state{LastRun: report.RanAt, ReclaimedBytes: report.ReclaimedBytes()})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(floopDir, StateFile), data, 0600); err != nil {
+		return fmt.Errorf("failed to record gc run: %w", err)
+	}
+	return nil
+}
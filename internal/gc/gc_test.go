@@ -0,0 +1,146 @@
+package gc
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/vectorindex"
+)
+
+func newTestStore(t *testing.T) *store.SQLiteGraphStore {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	t.Cleanup(func() { s.Close() })
+
+	ctx := context.Background()
+	for _, n := range []store.Node{
+		{ID: "live", Kind: store.NodeKindBehavior},
+		{ID: "forgotten", Kind: store.NodeKindForgotten},
+		{ID: "pending", Kind: store.NodeKindPending},
+		{ID: "dormant", Kind: store.NodeKindDormant},
+	} {
+		n.Content = map[string]interface{}{
+			"name":    n.ID,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": n.ID},
+		}
+		if _, err := s.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", n.ID, err)
+		}
+		if err := s.StoreEmbedding(ctx, n.ID, []float32{1, 0, 0}, "test-model"); err != nil {
+			t.Fatalf("StoreEmbedding(%s) error = %v", n.ID, err)
+		}
+	}
+	return s
+}
+
+func newTestIndex(t *testing.T) *vectorindex.BruteForceIndex {
+	t.Helper()
+	idx := vectorindex.NewBruteForceIndex()
+	ctx := context.Background()
+	for _, id := range []string{"live", "forgotten", "pending", "dormant", "deleted"} {
+		if err := idx.Add(ctx, id, []float32{1, 0, 0}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return idx
+}
+
+func TestRun(t *testing.T) {
+	s := newTestStore(t)
+	idx := newTestIndex(t)
+	cacheDir := t.TempDir()
+	orphan := filepath.Join(cacheDir, "url", "gone.fpack")
+	if err := os.MkdirAll(filepath.Dir(orphan), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(orphan, []byte("12345"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := Run(context.Background(), Options{Store: s, Index: idx, CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+
+	if len(report.OrphanedVectors) != 2 {
+		t.Errorf("OrphanedVectors = %v, want [deleted forgotten]", report.OrphanedVectors)
+	}
+	if idx.Len() != 3 {
+		t.Errorf("index Len() = %d, want 3 (live, pending, dormant)", idx.Len())
+	}
+	if report.ClearedEmbeddings != 1 {
+		t.Errorf("ClearedEmbeddings = %d, want 1", report.ClearedEmbeddings)
+	}
+	if len(report.OrphanedCacheFiles) != 1 || report.CacheBytes != 5 {
+		t.Errorf("cache orphans = %v (%d bytes), want 1 file of 5 bytes", report.OrphanedCacheFiles, report.CacheBytes)
+	}
+	if report.ReclaimedBytes() != 5 {
+		t.Errorf("ReclaimedBytes() = %d, want 5", report.ReclaimedBytes())
+	}
+	if _, err := os.Stat(orphan); !os.IsNotExist(err) {
+		t.Error("orphaned cache file should be removed")
+	}
+	if len(report.Warnings) != 0 {
+		t.Errorf("unexpected warnings: %v", report.Warnings)
+	}
+}
+
+func TestRun_DryRun(t *testing.T) {
+	s := newTestStore(t)
+	idx := newTestIndex(t)
+
+	report, err := Run(context.Background(), Options{Store: s, Index: idx, DryRun: true})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(report.OrphanedVectors) != 2 {
+		t.Errorf("OrphanedVectors = %v, want 2", report.OrphanedVectors)
+	}
+	if idx.Len() != 5 {
+		t.Errorf("dry run removed vectors: Len() = %d, want 5", idx.Len())
+	}
+	embeddings, err := s.GetAllEmbeddings(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(embeddings) != 4 {
+		t.Errorf("dry run cleared embeddings: got %d, want 4", len(embeddings))
+	}
+}
+
+func TestRun_IndexWithoutStore(t *testing.T) {
+	if _, err := Run(context.Background(), Options{Index: vectorindex.NewBruteForceIndex()}); err == nil {
+		t.Error("expected error when Index is set without Store")
+	}
+}
+
+func TestDueAndMarkRun(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	interval := 7 * 24 * time.Hour
+
+	if !Due(dir, interval, now) {
+		t.Error("Due() = false before any run, want true")
+	}
+	if Due(dir, 0, now) {
+		t.Error("Due() = true with interval 0, want false")
+	}
+
+	if err := MarkRun(dir, &Report{RanAt: now}); err != nil {
+		t.Fatalf("MarkRun() error = %v", err)
+	}
+	if Due(dir, interval, now.Add(24*time.Hour)) {
+		t.Error("Due() = true one day after a run, want false")
+	}
+	if !Due(dir, interval, now.Add(interval)) {
+		t.Error("Due() = false after the interval elapsed, want true")
+	}
+}
@@ -148,6 +148,38 @@ func (c *ClaudePlatform) GenerateHookConfig(existingConfig map[string]interface{
 	return config, nil
 }
 
+// RemoveHookConfig strips all floop hook entries from an existing config.
+// Non-floop hooks are preserved; the hooks section is dropped entirely when
+// nothing else remains. Returns the updated config and whether anything changed.
+func (c *ClaudePlatform) RemoveHookConfig(existingConfig map[string]interface{}) (map[string]interface{}, bool) {
+	if existingConfig == nil {
+		return nil, false
+	}
+
+	hooksSection, ok := existingConfig["hooks"].(map[string]interface{})
+	if !ok {
+		return existingConfig, false
+	}
+
+	changed := false
+	for _, eventType := range []string{"SessionStart", "UserPromptSubmit", "PreToolUse"} {
+		if containsFloopCommand(hooksSection, eventType) {
+			changed = true
+		}
+	}
+	if !changed {
+		return existingConfig, false
+	}
+
+	hooksSection = removeFloopEntries(hooksSection)
+	if len(hooksSection) == 0 {
+		delete(existingConfig, "hooks")
+	} else {
+		existingConfig["hooks"] = hooksSection
+	}
+	return existingConfig, true
+}
+
 // WriteConfig writes the configuration to settings.json.
 func (c *ClaudePlatform) WriteConfig(projectRoot string, config map[string]interface{}) error {
 	configPath := c.ConfigPath(projectRoot)
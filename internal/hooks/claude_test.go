@@ -312,6 +312,70 @@ func TestClaudePlatformGenerateHookConfigPreservesNonFloop(t *testing.T) {
 	}
 }
 
+func TestClaudePlatformRemoveHookConfig(t *testing.T) {
+	p := NewClaudePlatform()
+
+	existing := map[string]interface{}{
+		"hooks": map[string]interface{}{
+			"PreToolUse": []interface{}{
+				map[string]interface{}{
+					"matcher": "Read",
+					"hooks": []interface{}{
+						map[string]interface{}{
+							"type":    "command",
+							"command": "other-tool check",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	config, err := p.GenerateHookConfig(existing, ScopeProject, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config, changed := p.RemoveHookConfig(config)
+	if !changed {
+		t.Fatal("expected RemoveHookConfig to report a change")
+	}
+
+	hooks := config["hooks"].(map[string]interface{})
+	if _, ok := hooks["SessionStart"]; ok {
+		t.Error("expected SessionStart floop entries to be removed")
+	}
+	preToolUse := hooks["PreToolUse"].([]interface{})
+	if len(preToolUse) != 1 {
+		t.Errorf("expected 1 preserved PreToolUse entry, got %d", len(preToolUse))
+	}
+
+	// Second removal is a no-op
+	if _, changed := p.RemoveHookConfig(config); changed {
+		t.Error("expected second RemoveHookConfig to report no change")
+	}
+}
+
+func TestClaudePlatformRemoveHookConfigDropsEmptySection(t *testing.T) {
+	p := NewClaudePlatform()
+
+	config, err := p.GenerateHookConfig(map[string]interface{}{"model": "opus"}, ScopeProject, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	config, changed := p.RemoveHookConfig(config)
+	if !changed {
+		t.Fatal("expected RemoveHookConfig to report a change")
+	}
+	if _, ok := config["hooks"]; ok {
+		t.Error("expected empty hooks section to be dropped")
+	}
+	if config["model"] != "opus" {
+		t.Error("expected unrelated settings to be preserved")
+	}
+}
+
 func TestClaudePlatformWriteConfig(t *testing.T) {
 	tmpDir := t.TempDir()
 	p := NewClaudePlatform()
@@ -0,0 +1,214 @@
+package hooks
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GitHookMarker identifies git hook scripts written by floop, so install and
+// uninstall never touch hooks the user wrote.
+const GitHookMarker = "# floop git hook"
+
+// GitHookNames are the git hooks floop installs.
+var GitHookNames = []string{"pre-commit", "post-commit"}
+
+// gitHookBackupSuffix is appended to a user's hook when --force replaces it.
+const gitHookBackupSuffix = ".floop-backup"
+
+// Files in .floop/ used by the git hooks.
+const (
+	// GitPendingFile holds the pre-commit record until post-commit sees the
+	// commit land.
+	GitPendingFile = "git-pending.json"
+	// GitCommitLogFile is the JSONL log of commits recorded by the hooks.
+	GitCommitLogFile = "git-commits.jsonl"
+)
+
+// GitCommitRecord is what the git hooks record about a commit: where it was
+// made, what it changed, and which behaviors were active for those files.
+type GitCommitRecord struct {
+	Commit    string    `json:"commit,omitempty"`
+	Branch    string    `json:"branch,omitempty"`
+	Files     []string  `json:"files"`
+	Behaviors []string  `json:"behaviors"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// GitHookScript returns the script installed for the named hook. It runs
+// 'floop hooks record' when floop is on PATH and never blocks the commit.
+func GitHookScript(name string) string {
+	return fmt.Sprintf(`#!/bin/sh
+%s: records behavior activations for this commit.
+# Installed by 'floop hooks install'; remove with 'floop hooks uninstall'.
+if command -v floop >/dev/null 2>&1; then
+	floop hooks record %s >/dev/null 2>&1 || true
+fi
+exit 0
+`, GitHookMarker, name)
+}
+
+// GitHooksDir returns the hooks directory of the git repository at repoRoot,
+// honoring core.hooksPath.
+func GitHooksDir(repoRoot string) (string, error) {
+	out, err := gitOutput(repoRoot, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("not a git repository: %w", err)
+	}
+	if !filepath.IsAbs(out) {
+		out = filepath.Join(repoRoot, out)
+	}
+	return out, nil
+}
+
+// IsFloopGitHook reports whether the hook at path was written by floop.
+func IsFloopGitHook(path string) bool {
+	data, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(data), GitHookMarker)
+}
+
+// InstallGitHooks writes floop's hooks into hooksDir and returns their paths.
+// An existing hook that floop did not write is an error unless force is set,
+// in which case it is kept beside the new hook with a .floop-backup suffix.
+// A forced install never overwrites an earlier backup.
+func InstallGitHooks(hooksDir string, force bool) ([]string, error) {
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating hooks directory: %w", err)
+	}
+	for _, name := range GitHookNames {
+		path := filepath.Join(hooksDir, name)
+		if _, err := os.Stat(path); err != nil || IsFloopGitHook(path) {
+			continue
+		}
+		if !force {
+			return nil, fmt.Errorf("%s already exists; rerun with --force to replace it (the original is kept as %s%s)", path, name, gitHookBackupSuffix)
+		}
+		if _, err := os.Stat(path + gitHookBackupSuffix); err == nil {
+			return nil, fmt.Errorf("%s already exists and %s%s holds an earlier backup; move one of them away first", path, name, gitHookBackupSuffix)
+		}
+	}
+
+	var installed []string
+	for _, name := range GitHookNames {
+		path := filepath.Join(hooksDir, name)
+		if _, err := os.Stat(path); err == nil && !IsFloopGitHook(path) {
+			if err := os.Rename(path, path+gitHookBackupSuffix); err != nil {
+				return installed, fmt.Errorf("backing up %s: %w", path, err)
+			}
+		}
+		if err := os.WriteFile(path, []byte(GitHookScript(name)), 0755); err != nil {
+			return installed, fmt.Errorf("writing %s: %w", path, err)
+		}
+		installed = append(installed, path)
+	}
+	return installed, nil
+}
+
+// UninstallGitHooks removes floop's hooks from hooksDir, restoring any hook
+// that install backed up, and returns the paths removed.
+func UninstallGitHooks(hooksDir string) ([]string, error) {
+	var removed []string
+	for _, name := range GitHookNames {
+		path := filepath.Join(hooksDir, name)
+		if !IsFloopGitHook(path) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, fmt.Errorf("removing %s: %w", path, err)
+		}
+		removed = append(removed, path)
+		if _, err := os.Stat(path + gitHookBackupSuffix); err == nil {
+			if err := os.Rename(path+gitHookBackupSuffix, path); err != nil {
+				return removed, fmt.Errorf("restoring %s: %w", path, err)
+			}
+		}
+	}
+	return removed, nil
+}
+
+// GitStagedFiles returns the files added, copied, modified, or renamed in
+// the index of the repository at repoRoot.
+func GitStagedFiles(repoRoot string) ([]string, error) {
+	out, err := gitOutput(repoRoot, "diff", "--cached", "--name-only", "--diff-filter=ACMR")
+	if err != nil {
+		return nil, fmt.Errorf("listing staged files: %w", err)
+	}
+	var files []string
+	for _, line := range strings.Split(out, "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files, nil
+}
+
+// GitHead returns the commit hash of HEAD in the repository at repoRoot.
+func GitHead(repoRoot string) (string, error) {
+	return gitOutput(repoRoot, "rev-parse", "HEAD")
+}
+
+// SavePendingCommit stores the pre-commit record in floopDir.
+func SavePendingCommit(floopDir string, rec GitCommitRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding pending commit: %w", err)
+	}
+	return os.WriteFile(filepath.Join(floopDir, GitPendingFile), data, 0600)
+}
+
+// TakePendingCommit returns and removes the pre-commit record in floopDir,
+// or nil if there is none.
+func TakePendingCommit(floopDir string) (*GitCommitRecord, error) {
+	path := filepath.Join(floopDir, GitPendingFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading pending commit: %w", err)
+	}
+	if err := os.Remove(path); err != nil {
+		return nil, fmt.Errorf("clearing pending commit: %w", err)
+	}
+	var rec GitCommitRecord
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("decoding pending commit: %w", err)
+	}
+	return &rec, nil
+}
+
+// AppendCommitLog appends rec to the commit log in floopDir.
+func AppendCommitLog(floopDir string, rec GitCommitRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("encoding commit record: %w", err)
+	}
+	f, err := os.OpenFile(filepath.Join(floopDir, GitCommitLogFile), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening commit log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing commit log: %w", err)
+	}
+	return nil
+}
+
+func gitOutput(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	out, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("git %s: %s", args[0], strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
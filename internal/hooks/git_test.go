@@ -0,0 +1,124 @@
+package hooks
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func initGitRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	if out, err := exec.Command("git", "init", "-q", dir).CombinedOutput(); err != nil {
+		t.Skipf("git unavailable: %v %s", err, out)
+	}
+	return dir
+}
+
+func TestInstallGitHooks(t *testing.T) {
+	repo := initGitRepo(t)
+	hooksDir, err := GitHooksDir(repo)
+	if err != nil {
+		t.Fatalf("GitHooksDir() error = %v", err)
+	}
+	if hooksDir != filepath.Join(repo, ".git", "hooks") {
+		t.Errorf("GitHooksDir() = %q", hooksDir)
+	}
+
+	// A user's hook blocks install unless forced.
+	userHook := filepath.Join(hooksDir, "pre-commit")
+	if err := os.WriteFile(userHook, []byte("#!/bin/sh\nmake lint\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InstallGitHooks(hooksDir, false); err == nil || !strings.Contains(err.Error(), "--force") {
+		t.Fatalf("InstallGitHooks() over user hook error = %v", err)
+	}
+	if IsFloopGitHook(filepath.Join(hooksDir, "post-commit")) {
+		t.Error("a refused install should write nothing")
+	}
+
+	installed, err := InstallGitHooks(hooksDir, true)
+	if err != nil || len(installed) != len(GitHookNames) {
+		t.Fatalf("InstallGitHooks(force) = %v, %v", installed, err)
+	}
+	for _, path := range installed {
+		info, err := os.Stat(path)
+		if err != nil || info.Mode()&0100 == 0 || !IsFloopGitHook(path) {
+			t.Errorf("%s not an executable floop hook (%v)", path, err)
+		}
+	}
+	// Reinstalling over floop's own hooks needs no force.
+	if _, err := InstallGitHooks(hooksDir, false); err != nil {
+		t.Errorf("reinstall error = %v", err)
+	}
+
+	// A second user hook never overwrites the backup of the first.
+	if err := os.WriteFile(userHook, []byte("#!/bin/sh\nmake test\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InstallGitHooks(hooksDir, true); err == nil || !strings.Contains(err.Error(), "earlier backup") {
+		t.Fatalf("InstallGitHooks(force) over an existing backup error = %v", err)
+	}
+	if data, _ := os.ReadFile(userHook + gitHookBackupSuffix); string(data) != "#!/bin/sh\nmake lint\n" {
+		t.Errorf("backup overwritten: %q", data)
+	}
+	if _, err := InstallGitHooks(hooksDir, true); err == nil {
+		t.Fatal("retrying the forced install should still refuse")
+	}
+	if err := os.Remove(userHook); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := InstallGitHooks(hooksDir, false); err != nil {
+		t.Fatalf("reinstall error = %v", err)
+	}
+
+	removed, err := UninstallGitHooks(hooksDir)
+	if err != nil || len(removed) != len(GitHookNames) {
+		t.Fatalf("UninstallGitHooks() = %v, %v", removed, err)
+	}
+	if data, _ := os.ReadFile(userHook); string(data) != "#!/bin/sh\nmake lint\n" {
+		t.Errorf("user hook not restored: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(hooksDir, "post-commit")); !os.IsNotExist(err) {
+		t.Errorf("post-commit should be removed, stat err = %v", err)
+	}
+}
+
+func TestGitHooksDir_NotARepo(t *testing.T) {
+	if _, err := GitHooksDir(t.TempDir()); err == nil {
+		t.Error("expected error outside a git repository")
+	}
+}
+
+func TestPendingCommit(t *testing.T) {
+	dir := t.TempDir()
+	if rec, err := TakePendingCommit(dir); rec != nil || err != nil {
+		t.Fatalf("TakePendingCommit(empty) = %v, %v", rec, err)
+	}
+
+	want := GitCommitRecord{Branch: "main", Files: []string{"a.go"}, Behaviors: []string{"b1"}, Timestamp: time.Now().UTC().Truncate(time.Second)}
+	if err := SavePendingCommit(dir, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := TakePendingCommit(dir)
+	if err != nil || got == nil || got.Branch != "main" || got.Behaviors[0] != "b1" || !got.Timestamp.Equal(want.Timestamp) {
+		t.Fatalf("TakePendingCommit() = %+v, %v", got, err)
+	}
+	if rec, _ := TakePendingCommit(dir); rec != nil {
+		t.Error("pending commit should be cleared after it is taken")
+	}
+
+	got.Commit = "abc123"
+	for i := 0; i < 2; i++ {
+		if err := AppendCommitLog(dir, *got); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, GitCommitLogFile))
+	if lines := strings.Count(string(data), "\n"); lines != 2 || !strings.Contains(string(data), `"commit":"abc123"`) {
+		t.Errorf("commit log = %s", data)
+	}
+}
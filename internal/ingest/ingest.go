@@ -0,0 +1,114 @@
+// Package ingest finds corrections in agent session transcripts so they can
+// be learned like any other correction.
+package ingest
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/events"
+	"github.com/nvandessel/floop/internal/learning"
+)
+
+// fileInputKeys are tool input fields that name the file a tool touched.
+var fileInputKeys = []string{"file_path", "notebook_path", "path"}
+
+// Scan walks transcript events in order and returns an import record for
+// each user message that looks like a correction. The record's file is the
+// file the agent touched most recently before the message, so the learned
+// behavior is scoped to where the mistake happened; the branch comes from
+// the agent's provenance. Record lines are 1-based event positions.
+func Scan(evts []events.Event, capture learning.CorrectionCapture) []learning.ImportRecord {
+	var records []learning.ImportRecord
+	var lastFile, branch string
+	for i, e := range evts {
+		if e.Provenance != nil && e.Provenance.Branch != "" {
+			branch = e.Provenance.Branch
+		}
+		files := ReferencedFiles(e)
+		if e.Actor != events.ActorUser || e.Kind != events.KindMessage || isMeta(e) {
+			if len(files) > 0 {
+				lastFile = files[len(files)-1]
+			}
+			continue
+		}
+
+		text := strings.TrimSpace(e.Content)
+		if text == "" || !capture.MightBeCorrection(text) {
+			continue
+		}
+		file := lastFile
+		if len(files) > 0 {
+			// Files attached to the correction itself are the most specific.
+			file = files[0]
+		}
+		records = append(records, learning.ImportRecord{
+			Line:      i + 1,
+			Text:      text,
+			File:      file,
+			Branch:    branch,
+			Timestamp: e.Timestamp,
+		})
+	}
+	return records
+}
+
+// ReferencedFiles returns the files an event references: paths passed to
+// tools in Claude Code records, and attached files in Cursor messages.
+// Absolute paths under the session's working directory are made relative.
+func ReferencedFiles(e events.Event) []string {
+	var files []string
+	switch v := e.Metadata[events.MetaKeyFiles].(type) {
+	case []string:
+		files = append(files, v...)
+	case []any:
+		for _, f := range v {
+			if s, ok := f.(string); ok {
+				files = append(files, s)
+			}
+		}
+	}
+
+	if msg, ok := e.Metadata["message"].(map[string]any); ok {
+		blocks, _ := msg["content"].([]any)
+		for _, b := range blocks {
+			block, ok := b.(map[string]any)
+			if !ok || block["type"] != "tool_use" {
+				continue
+			}
+			input, _ := block["input"].(map[string]any)
+			for _, key := range fileInputKeys {
+				if p, ok := input[key].(string); ok && p != "" {
+					files = append(files, p)
+					break
+				}
+			}
+		}
+	}
+
+	cwd, _ := e.Metadata["cwd"].(string)
+	for i, f := range files {
+		files[i] = relativeTo(cwd, f)
+	}
+	return files
+}
+
+// isMeta reports whether a user event was injected by the agent rather than
+// typed by the user (Claude Code marks these isMeta).
+func isMeta(e events.Event) bool {
+	meta, _ := e.Metadata["isMeta"].(bool)
+	return meta
+}
+
+// relativeTo makes an absolute path relative to dir when it is inside it.
+func relativeTo(dir, path string) string {
+	path = strings.TrimPrefix(path, "file://")
+	if dir == "" || !filepath.IsAbs(path) {
+		return path
+	}
+	rel, err := filepath.Rel(dir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
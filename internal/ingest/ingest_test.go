@@ -0,0 +1,66 @@
+package ingest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/events"
+	"github.com/nvandessel/floop/internal/learning"
+)
+
+func TestScan_ClaudeCode(t *testing.T) {
+	transcript := strings.Join([]string{
+		`{"type":"user","message":{"role":"user","content":"add a config loader"},"cwd":"/home/me/app","sessionId":"s1"}`,
+		`{"type":"assistant","message":{"role":"assistant","content":[{"type":"tool_use","name":"Edit","input":{"file_path":"/home/me/app/config/load.py"}}]},"cwd":"/home/me/app","gitBranch":"feat/config","sessionId":"s1"}`,
+		`{"type":"user","message":{"role":"user","content":[{"type":"tool_result","content":"ok"}]},"cwd":"/home/me/app","sessionId":"s1"}`,
+		`{"type":"user","message":{"role":"user","content":"<command-name>/clear</command-name> don't"},"isMeta":true,"sessionId":"s1"}`,
+		`{"type":"user","message":{"role":"user","content":"No, don't use os.path here, use pathlib instead."},"cwd":"/home/me/app","sessionId":"s1"}`,
+		`{"type":"user","message":{"role":"user","content":"thanks!"},"sessionId":"s1"}`,
+	}, "\n")
+	evts, err := (&events.JSONLAdapter{}).Parse(strings.NewReader(transcript))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	records := Scan(evts, learning.NewCorrectionCapture())
+	if len(records) != 1 {
+		t.Fatalf("Scan() = %+v, want 1 record", records)
+	}
+	rec := records[0]
+	if rec.Line != 5 || rec.File != "config/load.py" || rec.Branch != "feat/config" {
+		t.Errorf("record = %+v", rec)
+	}
+	if !strings.Contains(rec.Text, "use pathlib") {
+		t.Errorf("text = %q", rec.Text)
+	}
+}
+
+func TestScan_CursorAttachedFile(t *testing.T) {
+	evts := []events.Event{
+		{Actor: events.ActorAgent, Kind: events.KindMessage, Content: "done", Metadata: map[string]any{events.MetaKeyFiles: []string{"old.go"}}},
+		{Actor: events.ActorUser, Kind: events.KindMessage, Content: "never return nil errors wrapped", Metadata: map[string]any{events.MetaKeyFiles: []string{"errs.go"}}},
+		{Actor: events.ActorUser, Kind: events.KindMessage, Content: "you should add a test"},
+	}
+	records := Scan(evts, learning.NewCorrectionCapture())
+	if len(records) != 2 {
+		t.Fatalf("Scan() = %+v, want 2 records", records)
+	}
+	if records[0].File != "errs.go" || records[1].File != "old.go" {
+		t.Errorf("files = %q, %q; want errs.go, old.go", records[0].File, records[1].File)
+	}
+}
+
+func TestReferencedFiles_OutsideCwd(t *testing.T) {
+	e := events.Event{Metadata: map[string]any{
+		"cwd": "/home/me/app",
+		"message": map[string]any{"content": []any{
+			map[string]any{"type": "tool_use", "input": map[string]any{"file_path": "/etc/hosts"}},
+			map[string]any{"type": "tool_use", "input": map[string]any{"notebook_path": "/home/me/app/nb/a.ipynb"}},
+			map[string]any{"type": "text", "text": "hi"},
+		}},
+	}}
+	got := ReferencedFiles(e)
+	if len(got) != 2 || got[0] != "/etc/hosts" || got[1] != "nb/a.ipynb" {
+		t.Errorf("ReferencedFiles() = %v", got)
+	}
+}
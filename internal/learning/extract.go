@@ -1,12 +1,15 @@
 package learning
 
 import (
+	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/sanitize"
 	"github.com/nvandessel/floop/internal/tagging"
@@ -93,6 +96,7 @@ func (e *behaviorExtractor) Extract(correction models.Correction) (*models.Behav
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		},
+		ExpiresAt: correction.ExpiresAt,
 	}, nil
 }
 
@@ -231,8 +235,13 @@ func (e *behaviorExtractor) buildContent(correction models.Correction) models.Be
 // generateName creates a human-readable name for the behavior.
 // The name is a slug-ified version of the corrected action.
 func (e *behaviorExtractor) generateName(correction models.Correction) string {
-	// Start with the corrected action
-	name := correction.CorrectedAction
+	return slugName("learned", correction.CorrectedAction)
+}
+
+// slugName builds a behavior name from text: a lowercase, hyphenated slug
+// of its start, prefixed with origin (e.g. "learned/").
+func slugName(origin, text string) string {
+	name := text
 
 	// Truncate to reasonable length
 	if len(name) > constants.MaxBehaviorNameLen {
@@ -282,8 +291,8 @@ func (e *behaviorExtractor) generateName(correction models.Correction) string {
 	// Trim leading/trailing hyphens
 	name = strings.Trim(name, "-")
 
-	// Prefix with learned/ to indicate origin
-	name = "learned/" + name
+	// Prefix with the origin, e.g. learned/
+	name = origin + "/" + name
 
 	// Apply SanitizeBehaviorName as a final safety pass to ensure the name
 	// only contains allowed characters after all transformations.
@@ -291,3 +300,72 @@ func (e *behaviorExtractor) generateName(correction models.Correction) string {
 
 	return name
 }
+
+// llmExtractionTimeout bounds the LLM refinement of one extracted behavior.
+const llmExtractionTimeout = 30 * time.Second
+
+// RefineWithLLM asks client to improve a behavior the rule-based extractor
+// produced from correction: a cleaner canonical statement, its kind, topic
+// tags, and when-conditions the correction implies. Conditions observed in
+// the correction's context are kept; the LLM only fills in missing ones.
+// The behavior's ID and provenance never change. On any error behavior is
+// left untouched, so callers can fall back to the rule-based result.
+func RefineWithLLM(ctx context.Context, client llm.Client, behavior *models.Behavior, correction models.Correction) error {
+	if client == nil || !client.Available() {
+		return fmt.Errorf("LLM client not available")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, llmExtractionTimeout)
+	defer cancel()
+	response, err := client.Complete(llm.WithPurpose(ctx, llm.PurposeExtract), []llm.Message{{Role: "user", Content: BehaviorExtractionPrompt(correction)}})
+	if err != nil {
+		return fmt.Errorf("LLM extraction failed: %w", err)
+	}
+	result, err := ParseBehaviorExtractionResponse(response)
+	if err != nil {
+		return err
+	}
+	canonical := sanitize.SanitizeBehaviorContent(strings.TrimSpace(result.Canonical))
+	if canonical == "" {
+		return fmt.Errorf("LLM canonical statement is empty after sanitization")
+	}
+
+	behavior.Content.Canonical = canonical
+	if behavior.Content.Structured == nil {
+		behavior.Content.Structured = make(map[string]interface{})
+	}
+	behavior.Content.Structured["prefer"] = canonical
+
+	switch kind := models.BehaviorKind(strings.ToLower(strings.TrimSpace(result.Kind))); kind {
+	case models.BehaviorKindDirective, models.BehaviorKindConstraint, models.BehaviorKindPreference, models.BehaviorKindProcedure:
+		behavior.Kind = kind
+	}
+
+	// LLM tags outrank inferred ones; the user's own tags outrank both
+	dict := tagging.NewDictionary()
+	tags := tagging.MergeTags(tagging.ExtractTags(canonical, dict), result.Tags, dict)
+	behavior.Content.Tags = tagging.MergeTags(tags, correction.ExtraTags, dict)
+
+	if behavior.When == nil {
+		behavior.When = make(map[string]interface{})
+	}
+	for key, value := range result.When {
+		value = strings.TrimSpace(value)
+		if value == "" || behavior.When[key] != nil {
+			continue
+		}
+		switch key {
+		case "file_path":
+			if p := sanitize.SanitizeFilePath(value); p != "" {
+				behavior.When[key] = p
+			}
+		case "language":
+			behavior.When[key] = sanitize.SanitizeBehaviorName(strings.ToLower(value))
+		case "task":
+			if constants.KnownTasks[value] {
+				behavior.When[key] = value
+			}
+		}
+	}
+	return nil
+}
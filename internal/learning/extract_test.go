@@ -1,10 +1,14 @@
 package learning
 
 import (
+	"context"
+	"errors"
+	"slices"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
 )
 
@@ -786,3 +790,73 @@ func TestBehaviorExtractor_Extract_Stats(t *testing.T) {
 		t.Errorf("Stats.TimesOverridden = %d, want 0", behavior.Stats.TimesOverridden)
 	}
 }
+
+func TestRefineWithLLM(t *testing.T) {
+	correction := models.Correction{
+		ID:              "c-1",
+		AgentAction:     "ran pip install requests",
+		CorrectedAction: "no, we use uv here, do uv add requests",
+		ExtraTags:       []string{"tooling"},
+		Context:         models.ContextSnapshot{FileLanguage: "python"},
+	}
+
+	t.Run("applies refinement", func(t *testing.T) {
+		behavior, _ := NewBehaviorExtractor().Extract(correction)
+		id := behavior.ID
+		client := llm.NewMockClient().WithCompleteResponse(`{
+			"canonical": "Use uv instead of pip to manage Python dependencies",
+			"kind": "preference",
+			"tags": ["uv", "packaging"],
+			"when": {"language": "go", "file_path": "**/pyproject.toml", "task": "gardening"}
+		}`)
+
+		if err := RefineWithLLM(context.Background(), client, behavior, correction); err != nil {
+			t.Fatalf("RefineWithLLM() error = %v", err)
+		}
+		if behavior.Content.Canonical != "Use uv instead of pip to manage Python dependencies" {
+			t.Errorf("Canonical = %q", behavior.Content.Canonical)
+		}
+		if behavior.Content.Structured["prefer"] != behavior.Content.Canonical {
+			t.Errorf("prefer = %v, want canonical", behavior.Content.Structured["prefer"])
+		}
+		if behavior.Kind != models.BehaviorKindPreference {
+			t.Errorf("Kind = %q, want preference", behavior.Kind)
+		}
+		for _, tag := range []string{"uv", "packaging", "tooling"} {
+			if !slices.Contains(behavior.Content.Tags, tag) {
+				t.Errorf("Tags = %v, missing %q", behavior.Content.Tags, tag)
+			}
+		}
+		// Observed context wins; unknown tasks are dropped
+		if behavior.When["language"] != "python" {
+			t.Errorf("when.language = %v, want observed python", behavior.When["language"])
+		}
+		if behavior.When["file_path"] != "**/pyproject.toml" {
+			t.Errorf("when.file_path = %v, want LLM suggestion", behavior.When["file_path"])
+		}
+		if _, ok := behavior.When["task"]; ok {
+			t.Errorf("when.task = %v, want unknown task dropped", behavior.When["task"])
+		}
+		if behavior.ID != id {
+			t.Errorf("ID changed from %s to %s", id, behavior.ID)
+		}
+	})
+
+	t.Run("leaves behavior untouched on failure", func(t *testing.T) {
+		for name, client := range map[string]llm.Client{
+			"nil client":   nil,
+			"unavailable":  llm.NewMockClient().WithAvailable(false),
+			"error":        llm.NewMockClient().WithError(errors.New("boom")),
+			"bad response": llm.NewMockClient().WithCompleteResponse("I cannot help with that"),
+		} {
+			behavior, _ := NewBehaviorExtractor().Extract(correction)
+			want := *behavior
+			if err := RefineWithLLM(context.Background(), client, behavior, correction); err == nil {
+				t.Errorf("%s: expected error", name)
+			}
+			if behavior.Content.Canonical != want.Content.Canonical || behavior.Kind != want.Kind {
+				t.Errorf("%s: behavior changed: %+v", name, behavior)
+			}
+		}
+	})
+}
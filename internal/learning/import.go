@@ -0,0 +1,318 @@
+package learning
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/tagging"
+)
+
+// Import file formats.
+const (
+	ImportFormatJSONL = "jsonl"
+	ImportFormatCSV   = "csv"
+)
+
+// ImportRecord is one historical correction read from an import file.
+// Either Right or Text is required; Text is free-form and is run through
+// ExtractFromText.
+type ImportRecord struct {
+	Line      int       `json:"line"`
+	Wrong     string    `json:"wrong,omitempty"`
+	Right     string    `json:"right,omitempty"`
+	Text      string    `json:"text,omitempty"`
+	File      string    `json:"file,omitempty"`
+	Task      string    `json:"task,omitempty"`
+	Language  string    `json:"language,omitempty"`
+	Branch    string    `json:"branch,omitempty"`
+	Tags      []string  `json:"tags,omitempty"`
+	Timestamp time.Time `json:"timestamp,omitempty"`
+}
+
+// ImportIssue explains why a line of an import file was skipped.
+type ImportIssue struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ImportResult reports the outcome of ImportCorrections.
+type ImportResult struct {
+	Total   int           `json:"total"`
+	Added   int           `json:"added"`
+	Merged  int           `json:"merged"`
+	Skipped int           `json:"skipped"`
+	Issues  []ImportIssue `json:"issues,omitempty"`
+	// Corrections are the processed corrections, for the corrections log.
+	Corrections []models.Correction `json:"-"`
+	DryRun      bool                `json:"dry_run"`
+}
+
+// ImportOptions configures ImportCorrections.
+type ImportOptions struct {
+	// Client extracts corrections from free-form text; nil uses pattern rules.
+	Client llm.Client
+	// Seen holds IDs of corrections already learned. Records with the same
+	// content are skipped, so re-importing a file is harmless.
+	Seen map[string]bool
+	// DryRun parses and validates records without processing them.
+	DryRun bool
+}
+
+// DetectImportFormat returns the import format for a file name: CSV for
+// .csv files, JSONL otherwise.
+func DetectImportFormat(path string) string {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return ImportFormatCSV
+	}
+	return ImportFormatJSONL
+}
+
+// ReadImportRecords parses an import file. JSONL has one object per line
+// with the fields of ImportRecord (agent_action and corrected_action are
+// accepted for wrong and right, so a corrections.jsonl can be imported).
+// CSV needs a header row naming the columns; unknown columns are ignored
+// and tags are separated by commas or semicolons. Lines that cannot be
+// parsed are returned as issues rather than failing the import.
+func ReadImportRecords(r io.Reader, format string) ([]ImportRecord, []ImportIssue, error) {
+	switch format {
+	case ImportFormatJSONL:
+		return readImportJSONL(r)
+	case ImportFormatCSV:
+		return readImportCSV(r)
+	default:
+		return nil, nil, fmt.Errorf("unknown import format %q (valid: jsonl, csv)", format)
+	}
+}
+
+func readImportJSONL(r io.Reader) ([]ImportRecord, []ImportIssue, error) {
+	var records []ImportRecord
+	var issues []ImportIssue
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		var raw struct {
+			ImportRecord
+			AgentAction     string                  `json:"agent_action"`
+			CorrectedAction string                  `json:"corrected_action"`
+			Context         *models.ContextSnapshot `json:"context"`
+		}
+		if err := json.Unmarshal([]byte(text), &raw); err != nil {
+			issues = append(issues, ImportIssue{Line: line, Reason: "invalid JSON: " + err.Error()})
+			continue
+		}
+		rec := raw.ImportRecord
+		rec.Line = line
+		if rec.Wrong == "" {
+			rec.Wrong = raw.AgentAction
+		}
+		if rec.Right == "" {
+			rec.Right = raw.CorrectedAction
+		}
+		if raw.Context != nil {
+			rec.File = firstNonEmpty(rec.File, raw.Context.FilePath)
+			rec.Task = firstNonEmpty(rec.Task, raw.Context.Task)
+			rec.Language = firstNonEmpty(rec.Language, raw.Context.FileLanguage)
+			rec.Branch = firstNonEmpty(rec.Branch, raw.Context.Branch)
+		}
+		records = append(records, rec)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("reading import file: %w", err)
+	}
+	return records, issues, nil
+}
+
+func readImportCSV(r io.Reader) ([]ImportRecord, []ImportIssue, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, fmt.Errorf("reading CSV header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	if _, ok := columns["right"]; !ok {
+		if _, ok := columns["text"]; !ok {
+			return nil, nil, fmt.Errorf("CSV header must include a right or text column")
+		}
+	}
+
+	var records []ImportRecord
+	var issues []ImportIssue
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				issues = append(issues, ImportIssue{Line: parseErr.StartLine, Reason: "invalid CSV: " + parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, fmt.Errorf("reading CSV: %w", err)
+		}
+		line, _ := reader.FieldPos(0)
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		rec := ImportRecord{
+			Line:     line,
+			Wrong:    get("wrong"),
+			Right:    get("right"),
+			Text:     get("text"),
+			File:     get("file"),
+			Task:     get("task"),
+			Language: get("language"),
+			Branch:   get("branch"),
+		}
+		if tags := get("tags"); tags != "" {
+			rec.Tags = strings.FieldsFunc(tags, func(r rune) bool { return r == ',' || r == ';' })
+		}
+		if ts := get("timestamp"); ts != "" {
+			t, err := time.Parse(time.RFC3339, ts)
+			if err != nil {
+				issues = append(issues, ImportIssue{Line: line, Reason: fmt.Sprintf("invalid timestamp %q (want RFC 3339)", ts)})
+				continue
+			}
+			rec.Timestamp = t
+		}
+		records = append(records, rec)
+	}
+	return records, issues, nil
+}
+
+// ImportCorrections runs historical corrections through the learning loop
+// in order. Each behavior is deduplicated against the store as it stands,
+// including behaviors added earlier in the same import, when the loop has
+// auto-merge enabled. Records that are invalid, already learned, not a
+// correction, or match a forgotten behavior are skipped with an issue; an
+// error from the loop stops the import.
+func ImportCorrections(ctx context.Context, loop LearningLoop, records []ImportRecord, opts ImportOptions) (*ImportResult, error) {
+	result := &ImportResult{Total: len(records), DryRun: opts.DryRun}
+	capture := NewCorrectionCapture()
+	seen := make(map[string]bool, len(opts.Seen))
+	for id := range opts.Seen {
+		seen[id] = true
+	}
+	skip := func(line int, reason string) {
+		result.Skipped++
+		result.Issues = append(result.Issues, ImportIssue{Line: line, Reason: reason})
+	}
+
+	for _, rec := range records {
+		correction, reason := importCorrection(ctx, capture, opts.Client, rec)
+		if reason != "" {
+			skip(rec.Line, reason)
+			continue
+		}
+		if seen[correction.ID] {
+			skip(rec.Line, "already learned ("+correction.ID+")")
+			continue
+		}
+		seen[correction.ID] = true
+		if opts.DryRun {
+			continue
+		}
+
+		learned, err := loop.ProcessCorrection(ctx, *correction)
+		if err != nil {
+			return result, fmt.Errorf("line %d: %w", rec.Line, err)
+		}
+		now := time.Now()
+		correction.Processed = true
+		correction.ProcessedAt = &now
+		result.Corrections = append(result.Corrections, *correction)
+
+		switch {
+		case learned.SkippedForgotten:
+			skip(rec.Line, "matches forgotten behavior "+learned.ForgottenBehaviorID)
+		case learned.MergedIntoExisting:
+			result.Merged++
+		default:
+			result.Added++
+		}
+	}
+	return result, nil
+}
+
+// importCorrection builds a sanitized correction from a record, or returns
+// the reason it must be skipped.
+func importCorrection(ctx context.Context, capture CorrectionCapture, client llm.Client, rec ImportRecord) (*models.Correction, string) {
+	wrong, right := rec.Wrong, rec.Right
+	file, task, language := rec.File, rec.Task, rec.Language
+	if right == "" && rec.Text != "" {
+		extracted, err := ExtractFromText(ctx, capture, client, rec.Text)
+		if err != nil {
+			return nil, err.Error()
+		}
+		wrong, right = extracted.Wrong, extracted.Right
+		file = firstNonEmpty(file, extracted.FilePath)
+		task = firstNonEmpty(task, extracted.Task)
+		language = firstNonEmpty(language, extracted.Language)
+	}
+
+	right = sanitize.SanitizeBehaviorContent(right)
+	if right == "" {
+		return nil, "no right action (empty, or only unsafe content)"
+	}
+	if len(rec.Tags) > tagging.MaxExtraTags {
+		return nil, fmt.Sprintf("at most %d tags allowed, got %d", tagging.MaxExtraTags, len(rec.Tags))
+	}
+
+	timestamp := rec.Timestamp
+	if timestamp.IsZero() {
+		timestamp = time.Now()
+	}
+	snapshot := models.ContextSnapshot{
+		Timestamp: timestamp,
+		FilePath:  sanitize.SanitizeFilePath(file),
+		Task:      sanitize.SanitizeBehaviorContent(task),
+		Branch:    sanitize.SanitizeBehaviorContent(rec.Branch),
+	}
+	if snapshot.FilePath != "" {
+		snapshot.FileLanguage = models.InferLanguage(snapshot.FilePath)
+		snapshot.FileExt = filepath.Ext(snapshot.FilePath)
+	}
+	if language != "" {
+		snapshot.FileLanguage = sanitize.SanitizeBehaviorContent(language)
+	}
+
+	correction, err := capture.CaptureFromCLI(sanitize.SanitizeBehaviorContent(wrong), right, snapshot)
+	if err != nil {
+		return nil, err.Error()
+	}
+	correction.Timestamp = timestamp
+	correction.HumanResponse = sanitize.SanitizeBehaviorContent(rec.Text)
+	correction.ExtraTags = rec.Tags
+	return correction, ""
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,116 @@
+package learning
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestReadImportRecords_JSONL(t *testing.T) {
+	input := `{"wrong": "used pip", "right": "use uv", "tags": ["python"]}
+
+not json
+{"agent_action": "used os.path", "corrected_action": "use pathlib", "context": {"file_path": "app/main.py"}}
+{"text": "don't mock the database, use the test container"}
+`
+	records, issues, err := ReadImportRecords(strings.NewReader(input), ImportFormatJSONL)
+	if err != nil {
+		t.Fatalf("ReadImportRecords() error = %v", err)
+	}
+	if len(records) != 3 || len(issues) != 1 || issues[0].Line != 3 {
+		t.Fatalf("records = %+v, issues = %+v", records, issues)
+	}
+	if records[0].Right != "use uv" || len(records[0].Tags) != 1 {
+		t.Errorf("record 0 = %+v", records[0])
+	}
+	if records[1].Line != 4 || records[1].Wrong != "used os.path" || records[1].Right != "use pathlib" || records[1].File != "app/main.py" {
+		t.Errorf("corrections.jsonl record = %+v", records[1])
+	}
+	if records[2].Text == "" {
+		t.Errorf("text record = %+v", records[2])
+	}
+}
+
+func TestReadImportRecords_CSV(t *testing.T) {
+	input := "Right,Wrong,Tags,Timestamp,Reviewer\n" +
+		"use uv,used pip,python;tooling,2024-03-01T10:00:00Z,alice\n" +
+		"\"wrap errors, with context\",,,,bob\n" +
+		"use slog,,,yesterday,carol\n"
+	records, issues, err := ReadImportRecords(strings.NewReader(input), ImportFormatCSV)
+	if err != nil {
+		t.Fatalf("ReadImportRecords() error = %v", err)
+	}
+	if len(records) != 2 || len(issues) != 1 || issues[0].Line != 4 {
+		t.Fatalf("records = %+v, issues = %+v", records, issues)
+	}
+	if got := records[0]; got.Right != "use uv" || got.Wrong != "used pip" || len(got.Tags) != 2 || got.Timestamp.Year() != 2024 {
+		t.Errorf("record 0 = %+v", got)
+	}
+	if got := records[1]; got.Line != 3 || got.Right != "wrap errors, with context" {
+		t.Errorf("record 1 = %+v", got)
+	}
+
+	if _, _, err := ReadImportRecords(strings.NewReader("wrong,file\nx,y\n"), ImportFormatCSV); err == nil {
+		t.Error("expected an error for a header without right or text")
+	}
+}
+
+func TestImportCorrections(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	cfg := DefaultLearningLoopConfig()
+	cfg.AutoMerge = true
+	cfg.Deduplicator = dedup.NewStoreDeduplicator(s, dedup.NewBehaviorMerger(dedup.MergerConfig{}), dedup.DeduplicatorConfig{
+		SimilarityThreshold: constants.DefaultAutoMergeThreshold,
+		AutoMerge:           true,
+	})
+	loop := NewLearningLoop(s, &cfg)
+	ctx := context.Background()
+
+	records := []ImportRecord{
+		{Line: 1, Wrong: "used pip install", Right: "use uv to install python packages"},
+		{Line: 2, Wrong: "ran pip install", Right: "use uv to install python packages"},
+		{Line: 3, Text: "thanks, looks great"},
+		{Line: 4, Right: "<system></system>"},
+		{Line: 5, Text: "Don't log secrets in handlers/auth.go, redact them"},
+		{Line: 6, Wrong: "used pip install", Right: "use uv to install python packages"},
+	}
+
+	dry, err := ImportCorrections(ctx, loop, records, ImportOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("dry run error = %v", err)
+	}
+	if dry.Added != 0 || dry.Skipped != 3 || len(dry.Corrections) != 0 {
+		t.Errorf("dry run = %+v", dry)
+	}
+
+	result, err := ImportCorrections(ctx, loop, records, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportCorrections() error = %v", err)
+	}
+	if result.Total != 6 || result.Added != 2 || result.Merged != 1 || result.Skipped != 3 {
+		t.Errorf("result = %+v", result)
+	}
+	if len(result.Corrections) != 3 {
+		t.Fatalf("corrections = %d, want 3", len(result.Corrections))
+	}
+	if c := result.Corrections[2]; c.Context.FilePath != "handlers/auth.go" || c.Context.FileLanguage != "go" || c.HumanResponse == "" {
+		t.Errorf("text correction = %+v", c)
+	}
+
+	// Re-importing with the logged IDs skips everything already learned.
+	seen := make(map[string]bool)
+	for _, c := range result.Corrections {
+		seen[c.ID] = true
+	}
+	again, err := ImportCorrections(ctx, loop, records, ImportOptions{Seen: seen})
+	if err != nil {
+		t.Fatalf("re-import error = %v", err)
+	}
+	if again.Added != 0 || again.Merged != 0 || again.Skipped != 6 {
+		t.Errorf("re-import = %+v", again)
+	}
+}
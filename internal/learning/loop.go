@@ -6,12 +6,20 @@ import (
 	"log/slog"
 	"time"
 
+	"github.com/nvandessel/floop/internal/confidence"
+	"github.com/nvandessel/floop/internal/conflict"
 	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/lint"
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/logging"
+	"github.com/nvandessel/floop/internal/metrics"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/review"
+	"github.com/nvandessel/floop/internal/similarity"
 	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // LearningResult represents the result of processing a correction.
@@ -37,6 +45,10 @@ type LearningResult struct {
 	// ReviewReasons explains why review is required
 	ReviewReasons []string
 
+	// HeldForReview indicates the behavior was stored as pending and will
+	// not activate until approved
+	HeldForReview bool
+
 	// MergedIntoExisting indicates whether the behavior was merged into an existing one
 	MergedIntoExisting bool
 
@@ -45,6 +57,38 @@ type LearningResult struct {
 
 	// MergeSimilarity is the similarity score with the merged behavior
 	MergeSimilarity float64
+
+	// SkippedForgotten indicates the candidate matched a behavior the user
+	// forgot, so nothing was written to the graph
+	SkippedForgotten bool
+
+	// ForgottenBehaviorID is the ID of the forgotten behavior that matched
+	ForgottenBehaviorID string
+}
+
+// Learn outcomes, as reported by LearningResult.Outcome.
+const (
+	OutcomeLearned   = "learned"   // stored as a new active behavior
+	OutcomeFlagged   = "flagged"   // stored active, but flagged for review
+	OutcomeHeld      = "held"      // stored pending review
+	OutcomeMerged    = "merged"    // merged into an existing behavior
+	OutcomeForgotten = "forgotten" // skipped: matched a forgotten behavior
+)
+
+// Outcome summarizes what the learn did to the graph.
+func (r *LearningResult) Outcome() string {
+	switch {
+	case r.SkippedForgotten:
+		return OutcomeForgotten
+	case r.MergedIntoExisting:
+		return OutcomeMerged
+	case r.HeldForReview:
+		return OutcomeHeld
+	case r.RequiresReview:
+		return OutcomeFlagged
+	default:
+		return OutcomeLearned
+	}
 }
 
 // LearningLoop orchestrates the correction -> behavior pipeline.
@@ -55,6 +99,14 @@ type LearningLoop interface {
 	// It extracts a behavior, determines graph placement, and optionally
 	// auto-accepts the behavior if confidence is high enough.
 	ProcessCorrection(ctx context.Context, correction models.Correction) (*LearningResult, error)
+
+	// ApprovePending accepts a behavior awaiting review, activating it if it
+	// was held as pending.
+	ApprovePending(ctx context.Context, behaviorID string) error
+
+	// RejectPending declines a behavior awaiting review. The behavior is
+	// forgotten so similar corrections do not recreate it.
+	RejectPending(ctx context.Context, behaviorID, reason string) error
 }
 
 // LearningLoopConfig holds configuration for the learning loop.
@@ -77,6 +129,11 @@ type LearningLoopConfig struct {
 	// LLMClient is the optional LLM client for semantic comparison and merging.
 	LLMClient llm.Client
 
+	// UseLLM enables LLM-assisted extraction with LLMClient: each behavior
+	// the rule-based extractor produces is refined by RefineWithLLM, keeping
+	// the rule-based result when the LLM is unavailable or fails.
+	UseLLM bool
+
 	// Deduplicator is the optional deduplicator for finding duplicates.
 	// If nil, auto-merge is disabled regardless of AutoMerge setting.
 	Deduplicator dedup.Deduplicator
@@ -90,6 +147,22 @@ type LearningLoopConfig struct {
 
 	// DecisionLogger is the optional decision event logger.
 	DecisionLogger *logging.DecisionLogger
+
+	// SimilarityTuning overrides the default similarity weights and thresholds
+	// used for graph placement. nil uses the built-in defaults.
+	SimilarityTuning *similarity.Tuning
+
+	// HoldForReview stores behaviors that require review as pending
+	// behaviors, which do not activate until approved. When false they are
+	// stored active and only flagged for review.
+	HoldForReview bool
+
+	// OpLogPath is the operation log each learn is appended to so it can be
+	// undone (see UndoLast). Empty disables the log.
+	OpLogPath string
+
+	// Metrics records each learn's outcome and latency. Nil records nothing.
+	Metrics *metrics.Recorder
 }
 
 // DefaultLearningLoopConfig returns sensible defaults for the learning loop.
@@ -116,6 +189,11 @@ func NewLearningLoop(s store.GraphStore, config *LearningLoopConfig) LearningLoo
 			LLMClient:              cfg.LLMClient,
 			UseLLMForSimilarity:    true,
 			LLMSimilarityThreshold: 0.5,
+			Tuning:                 cfg.SimilarityTuning,
+		})
+	} else if cfg.SimilarityTuning != nil {
+		placer = NewGraphPlacerWithConfig(s, &GraphPlacerConfig{
+			Tuning: cfg.SimilarityTuning,
 		})
 	} else {
 		placer = NewGraphPlacer(s)
@@ -125,6 +203,8 @@ func NewLearningLoop(s store.GraphStore, config *LearningLoopConfig) LearningLoo
 		store:               s,
 		capturer:            NewCorrectionCapture(),
 		extractor:           NewBehaviorExtractor(),
+		llmClient:           cfg.LLMClient,
+		useLLM:              cfg.UseLLM,
 		placer:              placer,
 		autoAcceptThreshold: cfg.AutoAcceptThreshold,
 		autoMerge:           cfg.AutoMerge,
@@ -133,6 +213,11 @@ func NewLearningLoop(s store.GraphStore, config *LearningLoopConfig) LearningLoo
 		scopeOverride:       cfg.ScopeOverride,
 		logger:              cfg.Logger,
 		decisions:           cfg.DecisionLogger,
+		tuning:              cfg.SimilarityTuning,
+		holdForReview:       cfg.HoldForReview,
+		conflicts:           conflict.NewDetector(cfg.LLMClient),
+		opLogPath:           cfg.OpLogPath,
+		metrics:             cfg.Metrics,
 	}
 }
 
@@ -141,6 +226,8 @@ type learningLoop struct {
 	store               store.GraphStore
 	capturer            CorrectionCapture
 	extractor           BehaviorExtractor
+	llmClient           llm.Client
+	useLLM              bool
 	placer              GraphPlacer
 	autoAcceptThreshold float64
 	autoMerge           bool
@@ -149,31 +236,94 @@ type learningLoop struct {
 	scopeOverride       *constants.Scope
 	logger              *slog.Logger
 	decisions           *logging.DecisionLogger
+	tuning              *similarity.Tuning
+	holdForReview       bool
+	conflicts           *conflict.Detector
+	opLogPath           string
+	metrics             *metrics.Recorder
 }
 
 // ProcessCorrection implements LearningLoop.
 func (l *learningLoop) ProcessCorrection(ctx context.Context, correction models.Correction) (*LearningResult, error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "learning.ProcessCorrection", attribute.String("floop.correction_id", correction.ID))
+	result, err := l.processCorrection(ctx, correction)
+	event := metrics.Event{Kind: metrics.KindLearn, DurationMS: metrics.Since(start), Error: err != nil}
+	if err == nil {
+		event.Outcome = result.Outcome()
+		span.SetAttributes(attribute.String("floop.learn.outcome", event.Outcome))
+	}
+	tracing.End(span, err)
+	l.metrics.Record(event)
+	return result, err
+}
+
+// processCorrection runs the learning pipeline, tracing each stage in a
+// learning.<stage> span.
+func (l *learningLoop) processCorrection(ctx context.Context, correction models.Correction) (*LearningResult, error) {
 	// Step 1: Extract candidate behavior
+	stageCtx, span := tracing.Start(ctx, "learning.extract")
 	candidate, err := l.extractor.Extract(correction)
 	if err != nil {
+		tracing.End(span, err)
 		return nil, fmt.Errorf("extraction failed: %w", err)
 	}
+	if l.useLLM && l.llmClient != nil {
+		if err := RefineWithLLM(stageCtx, l.llmClient, candidate, correction); err != nil && l.logger != nil {
+			l.logger.Debug("LLM extraction failed, using rule-based behavior", "correction_id", correction.ID, "error", err)
+		}
+	}
+	tracing.End(span, nil)
 
 	if l.logger != nil {
 		l.logger.Debug("behavior extracted", "behavior_id", candidate.ID, "kind", candidate.Kind, "correction_id", correction.ID)
 	}
 
+	// Respect user curation: never recreate a behavior the user forgot
+	stageCtx, span = tracing.Start(ctx, "learning.check_forgotten")
+	forgottenID, err := l.findForgotten(stageCtx, candidate)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("checking forgotten behaviors: %w", err)
+	}
+	if forgottenID != "" {
+		if l.logger != nil {
+			l.logger.Debug("candidate matches forgotten behavior", "behavior_id", candidate.ID, "forgotten_id", forgottenID)
+		}
+		if l.decisions != nil {
+			l.decisions.Log(map[string]any{
+				"event":        "forgotten_skip",
+				"behavior_id":  candidate.ID,
+				"forgotten_id": forgottenID,
+			})
+		}
+		return &LearningResult{
+			Correction:          correction,
+			CandidateBehavior:   *candidate,
+			Scope:               ClassifyScope(candidate),
+			SkippedForgotten:    true,
+			ForgottenBehaviorID: forgottenID,
+		}, nil
+	}
+
 	// Step 2: Check for duplicates and auto-merge if enabled
 	if l.autoMerge && l.deduplicator != nil {
-		mergeResult, err := l.tryAutoMerge(ctx, candidate)
+		rec := l.newOp(OpMerge, correction.ID)
+		stageCtx, span = tracing.Start(ctx, "learning.auto_merge")
+		mergeResult, err := l.tryAutoMerge(stageCtx, candidate, rec)
+		span.SetAttributes(attribute.Bool("floop.learn.merged", err == nil && mergeResult != nil))
+		tracing.End(span, err)
 		if err == nil && mergeResult != nil {
+			l.logOp(rec, mergeResult.MergedBehaviorID)
 			return mergeResult, nil
 		}
 		// Continue with normal flow if auto-merge didn't happen
 	}
 
 	// Step 3: Determine graph placement
-	placement, err := l.placer.Place(ctx, candidate)
+	stageCtx, span = tracing.Start(ctx, "learning.place")
+	placement, err := l.placer.Place(stageCtx, candidate)
+	tracing.End(span, err)
 	if err != nil {
 		return nil, fmt.Errorf("placement failed: %w", err)
 	}
@@ -182,16 +332,32 @@ func (l *learningLoop) ProcessCorrection(ctx context.Context, correction models.
 		l.logger.Debug("placement decided", "behavior_id", candidate.ID, "action", placement.Action, "confidence", placement.Confidence)
 	}
 
+	// Contradictions with existing behaviors are recorded and force review
+	stageCtx, span = tracing.Start(ctx, "learning.find_conflicts")
+	conflicts, err := l.findConflicts(stageCtx, candidate)
+	tracing.End(span, err)
+	if err != nil {
+		return nil, fmt.Errorf("conflict detection failed: %w", err)
+	}
+	for _, c := range conflicts {
+		candidate.Conflicts = append(candidate.Conflicts, c.B)
+	}
+
 	// Step 4: Decide if auto-accept or needs review
 	requiresReview, reasons := l.needsReview(candidate, placement)
 	autoAccepted := !requiresReview && placement.Confidence >= l.autoAcceptThreshold
 
-	// Step 5: Commit to graph
-	scope, err := l.commitBehavior(ctx, candidate, placement)
+	// Step 5: Commit to graph, noting what it changes so it can be undone
+	rec := l.newOp(OpLearn, correction.ID)
+	stageCtx, span = tracing.Start(ctx, "learning.commit")
+	scope, err := l.commit(stageCtx, candidate, placement, reasons, conflicts, rec)
+	tracing.End(span, err)
 	if err != nil {
-		return nil, fmt.Errorf("commit failed: %w", err)
+		return nil, err
 	}
 
+	l.logOp(rec, candidate.ID)
+
 	return &LearningResult{
 		Correction:        correction,
 		CandidateBehavior: *candidate,
@@ -200,12 +366,71 @@ func (l *learningLoop) ProcessCorrection(ctx context.Context, correction models.
 		AutoAccepted:      autoAccepted,
 		RequiresReview:    requiresReview,
 		ReviewReasons:     reasons,
+		HeldForReview:     requiresReview && l.holdForReview,
 	}, nil
 }
 
+// commit snapshots what the candidate changes into rec, writes the
+// candidate to the graph, and records its conflicts.
+func (l *learningLoop) commit(ctx context.Context, candidate *models.Behavior, placement *PlacementDecision, reasons []string, conflicts []conflict.Conflict, rec *opRecorder) (constants.Scope, error) {
+	if err := rec.snapshot(ctx, candidate.ID); err != nil {
+		return "", err
+	}
+	for _, c := range conflicts {
+		if err := rec.snapshot(ctx, c.B); err != nil {
+			return "", err
+		}
+		rec.edge(store.Edge{Source: c.A, Target: c.B, Kind: store.EdgeKindConflicts})
+	}
+	scope, err := l.commitBehavior(ctx, candidate, placement, reasons, rec)
+	if err != nil {
+		return "", fmt.Errorf("commit failed: %w", err)
+	}
+	if len(conflicts) > 0 {
+		if _, err := conflict.Record(ctx, l.store, conflicts, time.Now()); err != nil {
+			return "", fmt.Errorf("recording conflicts: %w", err)
+		}
+		if err := l.store.Sync(ctx); err != nil {
+			return "", fmt.Errorf("recording conflicts: %w", err)
+		}
+	}
+	return scope, nil
+}
+
+// ApprovePending implements LearningLoop.
+func (l *learningLoop) ApprovePending(ctx context.Context, behaviorID string) error {
+	node, err := review.Approve(ctx, l.store, behaviorID, time.Now())
+	if err != nil {
+		return err
+	}
+	if l.decisions != nil {
+		l.decisions.Log(map[string]any{
+			"event":       "review_approved",
+			"behavior_id": node.ID,
+		})
+	}
+	return l.store.Sync(ctx)
+}
+
+// RejectPending implements LearningLoop.
+func (l *learningLoop) RejectPending(ctx context.Context, behaviorID, reason string) error {
+	node, err := review.Reject(ctx, l.store, behaviorID, reason, time.Now())
+	if err != nil {
+		return err
+	}
+	if l.decisions != nil {
+		l.decisions.Log(map[string]any{
+			"event":       "review_rejected",
+			"behavior_id": node.ID,
+			"reason":      node.Metadata["forget_reason"],
+		})
+	}
+	return l.store.Sync(ctx)
+}
+
 // tryAutoMerge attempts to merge the candidate with existing duplicates.
 // Returns a LearningResult if merge occurred, nil otherwise.
-func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behavior) (*LearningResult, error) {
+func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behavior, rec *opRecorder) (*LearningResult, error) {
 	// Find duplicates
 	duplicates, err := l.deduplicator.FindDuplicates(ctx, candidate)
 	if err != nil {
@@ -251,7 +476,10 @@ func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behav
 		})
 	}
 
-	// Perform the merge
+	// Perform the merge, which deletes the duplicate
+	if err := rec.snapshot(ctx, bestMatch.Behavior.ID); err != nil {
+		return nil, err
+	}
 	merged, err := l.deduplicator.MergeDuplicates(ctx, []dedup.DuplicateMatch{*bestMatch}, candidate)
 	if err != nil {
 		return nil, fmt.Errorf("merge failed: %w", err)
@@ -277,6 +505,75 @@ func (l *learningLoop) tryAutoMerge(ctx context.Context, candidate *models.Behav
 	}, nil
 }
 
+// findForgotten returns the ID of a forgotten behavior the candidate would
+// recreate: one with the same ID, or one scoring at or above the duplicate
+// threshold. Returns "" if there is none.
+func (l *learningLoop) findForgotten(ctx context.Context, candidate *models.Behavior) (string, error) {
+	existing, err := l.store.GetNode(ctx, candidate.ID)
+	if err != nil {
+		return "", err
+	}
+	if existing != nil && existing.Kind == store.NodeKindForgotten {
+		return existing.ID, nil
+	}
+
+	nodes, err := l.store.QueryNodes(ctx, map[string]interface{}{
+		"kind": string(store.NodeKindForgotten),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	tuning := similarity.DefaultTuning()
+	if l.tuning != nil {
+		tuning = *l.tuning
+	}
+	for _, node := range nodes {
+		forgotten := models.NodeToBehavior(node)
+		score := tuning.Score(
+			similarity.ComputeWhenOverlap(candidate.When, forgotten.When),
+			similarity.ComputeContentSimilarity(candidate.Content.Canonical, forgotten.Content.Canonical),
+			similarity.ComputeTagSimilarity(candidate.Content.Tags, forgotten.Content.Tags),
+		)
+		if score >= tuning.Thresholds.UpperBound {
+			return node.ID, nil
+		}
+	}
+	return "", nil
+}
+
+// findConflicts returns contradictions between the candidate and existing
+// active behaviors.
+func (l *learningLoop) findConflicts(ctx context.Context, candidate *models.Behavior) ([]conflict.Conflict, error) {
+	nodes, err := l.store.QueryNodes(ctx, map[string]interface{}{
+		"kind": string(store.NodeKindBehavior),
+	})
+	if err != nil {
+		return nil, err
+	}
+	existing := make([]models.Behavior, 0, len(nodes))
+	for _, node := range nodes {
+		existing = append(existing, models.NodeToBehavior(node))
+	}
+	conflicts := l.conflicts.Find(ctx, candidate, existing)
+
+	for _, c := range conflicts {
+		if l.logger != nil {
+			l.logger.Debug("conflict detected", "behavior_id", candidate.ID, "conflicts_with", c.B, "method", c.Method)
+		}
+		if l.decisions != nil {
+			l.decisions.Log(map[string]any{
+				"event":          "conflict_detected",
+				"behavior_id":    candidate.ID,
+				"conflicts_with": c.B,
+				"method":         string(c.Method),
+				"reason":         c.Reason,
+			})
+		}
+	}
+	return conflicts, nil
+}
+
 // needsReview determines if human review is required.
 func (l *learningLoop) needsReview(candidate *models.Behavior, placement *PlacementDecision) (bool, []string) {
 	var reasons []string
@@ -308,6 +605,11 @@ func (l *learningLoop) needsReview(candidate *models.Behavior, placement *Placem
 		}
 	}
 
+	// Content that breaks style rules needs review
+	for _, issue := range lint.Lint(candidate.Content.Canonical, lint.DefaultOptions()) {
+		reasons = append(reasons, "Content lint: "+issue.String())
+	}
+
 	needsRev := len(reasons) > 0
 
 	if needsRev {
@@ -347,9 +649,11 @@ type ScopedNodeAdder interface {
 	AddNodeToScope(ctx context.Context, node store.Node, scope constants.Scope) (string, error)
 }
 
-// commitBehavior saves the behavior to the graph.
+// commitBehavior saves the behavior to the graph. Non-empty reviewReasons mark
+// the behavior as awaiting review so its review age can be tracked, and hold
+// it as pending when holdForReview is set.
 // Returns the scope the behavior was written to.
-func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Behavior, placement *PlacementDecision) (constants.Scope, error) {
+func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Behavior, placement *PlacementDecision, reviewReasons []string, rec *opRecorder) (constants.Scope, error) {
 	// Convert behavior to node
 	node := store.Node{
 		ID:   behavior.ID,
@@ -370,6 +674,17 @@ func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Beha
 			"stats":      behavior.Stats,
 		},
 	}
+	confidence.SetBase(node.Metadata, behavior.Confidence, confidence.OriginExtraction)
+	if behavior.ExpiresAt != nil {
+		node.Metadata[models.ExpiresAtKey] = behavior.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if len(reviewReasons) > 0 {
+		node.Metadata[review.MetaRequestedAt] = time.Now().Format(time.RFC3339)
+		node.Metadata[review.MetaReasons] = reviewReasons
+		if l.holdForReview {
+			node.Kind = store.NodeKindPending
+		}
+	}
 
 	// Classify scope based on behavior's When conditions, with optional override
 	scope := ClassifyScope(behavior)
@@ -397,6 +712,7 @@ func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Beha
 			Weight:    1.0,
 			CreatedAt: time.Now(),
 		}
+		rec.edge(edge)
 		if err := l.store.AddEdge(ctx, edge); err != nil {
 			return scope, err
 		}
@@ -404,3 +720,24 @@ func (l *learningLoop) commitBehavior(ctx context.Context, behavior *models.Beha
 
 	return scope, l.store.Sync(ctx)
 }
+
+// newOp starts recording an operation, or returns nil when the operation
+// log is disabled.
+func (l *learningLoop) newOp(action, correctionID string) *opRecorder {
+	if l.opLogPath == "" {
+		return nil
+	}
+	return newOpRecorder(l.store, action, correctionID)
+}
+
+// logOp appends a recorded operation to the operation log. The learn has
+// already been committed, so a failure is logged rather than returned.
+func (l *learningLoop) logOp(rec *opRecorder, behaviorID string) {
+	if rec == nil {
+		return
+	}
+	rec.op.BehaviorID = behaviorID
+	if err := AppendOperation(l.opLogPath, rec.op); err != nil && l.logger != nil {
+		l.logger.Warn("failed to record learn operation", "behavior_id", behaviorID, "error", err)
+	}
+}
@@ -3,6 +3,7 @@ package learning
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -10,8 +11,11 @@ import (
 	"time"
 
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/logging"
+	"github.com/nvandessel/floop/internal/metrics"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/review"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -116,6 +120,15 @@ func TestLearningLoop_ProcessCorrection_ConstraintRequiresReview(t *testing.T) {
 	if !found {
 		t.Errorf("expected constraint review reason, got: %v", result.ReviewReasons)
 	}
+
+	// The stored behavior should record when review was requested
+	node, err := s.GetNode(ctx, result.CandidateBehavior.ID)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", result.CandidateBehavior.ID, node, err)
+	}
+	if _, ok := node.Metadata[review.MetaRequestedAt].(string); !ok {
+		t.Errorf("expected %s metadata, got: %v", review.MetaRequestedAt, node.Metadata)
+	}
 }
 
 func TestLearningLoop_ProcessCorrection_AutoAccept(t *testing.T) {
@@ -252,6 +265,37 @@ func TestLearningLoop_NeedsReview_MergeAction(t *testing.T) {
 	}
 }
 
+func TestLearningLoop_NeedsReview_ContentLint(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	loop := NewLearningLoop(s, nil).(*learningLoop)
+
+	candidate := &models.Behavior{
+		ID:      "test-behavior",
+		Kind:    models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "I think you should avoid it"},
+	}
+	placement := &PlacementDecision{
+		Action:     PlacementActionCreate,
+		Confidence: 0.9,
+	}
+
+	needsReview, reasons := loop.needsReview(candidate, placement)
+	if !needsReview {
+		t.Error("expected lint issues to require review")
+	}
+
+	found := false
+	for _, r := range reasons {
+		if strings.HasPrefix(r, "Content lint: first-person:") {
+			found = true
+			break
+		}
+	}
+	if !found {
+		t.Errorf("expected first-person lint reason, got: %v", reasons)
+	}
+}
+
 func TestLearningLoop_ProcessCorrection_LogsAutoAccept(t *testing.T) {
 	dir := t.TempDir()
 	dl := logging.NewDecisionLogger(dir, "debug")
@@ -547,3 +591,267 @@ func TestLearningLoop_ScopeOverrideLocal(t *testing.T) {
 		t.Errorf("expected scope %q with override, got %q", constants.ScopeLocal, result.Scope)
 	}
 }
+
+func TestLearningLoop_ProcessCorrection_SkipsForgotten(t *testing.T) {
+	ctx := context.Background()
+	correction := models.Correction{
+		ID:              "forgotten-correction",
+		Timestamp:       time.Now(),
+		AgentAction:     "used pip install",
+		CorrectedAction: "use uv instead of pip for package management",
+		Context: models.ContextSnapshot{
+			Timestamp:    time.Now(),
+			FileLanguage: "python",
+			FilePath:     "requirements.txt",
+		},
+	}
+
+	tests := []struct {
+		name   string
+		forget func(t *testing.T, s store.GraphStore, learned string)
+	}{
+		{
+			name: "same ID",
+			forget: func(t *testing.T, s store.GraphStore, learned string) {
+				node, err := s.GetNode(ctx, learned)
+				if err != nil || node == nil {
+					t.Fatalf("GetNode() = %v, %v", node, err)
+				}
+				node.Kind = store.NodeKindForgotten
+				if err := s.UpdateNode(ctx, *node); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+		{
+			name: "near-identical content",
+			forget: func(t *testing.T, s store.GraphStore, learned string) {
+				node, err := s.GetNode(ctx, learned)
+				if err != nil || node == nil {
+					t.Fatalf("GetNode() = %v, %v", node, err)
+				}
+				if err := s.DeleteNode(ctx, learned); err != nil {
+					t.Fatal(err)
+				}
+				node.ID = "behavior-forgotten-copy"
+				node.Kind = store.NodeKindForgotten
+				if _, err := s.AddNode(ctx, *node); err != nil {
+					t.Fatal(err)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := store.NewInMemoryGraphStore()
+			loop := NewLearningLoop(s, nil)
+
+			first, err := loop.ProcessCorrection(ctx, correction)
+			if err != nil {
+				t.Fatalf("ProcessCorrection failed: %v", err)
+			}
+			tt.forget(t, s, first.CandidateBehavior.ID)
+
+			second, err := loop.ProcessCorrection(ctx, correction)
+			if err != nil {
+				t.Fatalf("ProcessCorrection failed: %v", err)
+			}
+			if !second.SkippedForgotten || second.ForgottenBehaviorID == "" {
+				t.Fatalf("expected forgotten skip, got %+v", second)
+			}
+
+			behaviors, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(behaviors) != 0 {
+				t.Errorf("forgotten behavior was recreated: %v", behaviors)
+			}
+		})
+	}
+}
+
+func TestLearningLoop_HoldForReview(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	loop := NewLearningLoop(s, &LearningLoopConfig{
+		AutoAcceptThreshold: constants.DefaultAutoAcceptThreshold,
+		HoldForReview:       true,
+	})
+	ctx := context.Background()
+
+	correction := models.Correction{
+		ID:              "test-correction-held",
+		Timestamp:       time.Now(),
+		AgentAction:     "committed directly to main",
+		CorrectedAction: "never commit directly to main branch",
+		Context:         models.ContextSnapshot{Timestamp: time.Now()},
+	}
+	result, err := loop.ProcessCorrection(ctx, correction)
+	if err != nil {
+		t.Fatalf("ProcessCorrection failed: %v", err)
+	}
+	if !result.RequiresReview || !result.HeldForReview {
+		t.Fatalf("RequiresReview=%v HeldForReview=%v, want both true", result.RequiresReview, result.HeldForReview)
+	}
+
+	id := result.CandidateBehavior.ID
+	node, err := s.GetNode(ctx, id)
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(%s) = %v, %v", id, node, err)
+	}
+	if node.Kind != store.NodeKindPending {
+		t.Errorf("Kind = %s, want %s", node.Kind, store.NodeKindPending)
+	}
+
+	if err := loop.ApprovePending(ctx, id); err != nil {
+		t.Fatalf("ApprovePending: %v", err)
+	}
+	node, _ = s.GetNode(ctx, id)
+	if node.Kind != store.NodeKindBehavior {
+		t.Errorf("Kind after approve = %s, want behavior", node.Kind)
+	}
+	if err := loop.RejectPending(ctx, id, "already approved"); err == nil {
+		t.Error("RejectPending should fail once the behavior is approved")
+	}
+}
+
+func TestLearningLoop_RecordsMetrics(t *testing.T) {
+	dir := t.TempDir()
+	s := store.NewInMemoryGraphStore()
+	loop := NewLearningLoop(s, &LearningLoopConfig{
+		AutoAcceptThreshold: constants.DefaultAutoAcceptThreshold,
+		HoldForReview:       true,
+		Metrics:             metrics.Open(dir, metrics.SourceCLI),
+	})
+
+	correction := models.Correction{
+		ID:              "test-correction-metrics",
+		Timestamp:       time.Now(),
+		AgentAction:     "committed directly to main",
+		CorrectedAction: "never commit directly to main branch",
+		Context:         models.ContextSnapshot{Timestamp: time.Now()},
+	}
+	if _, err := loop.ProcessCorrection(context.Background(), correction); err != nil {
+		t.Fatalf("ProcessCorrection failed: %v", err)
+	}
+
+	events, err := metrics.Read(filepath.Join(dir, metrics.FileName), time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Kind != metrics.KindLearn || events[0].Outcome != OutcomeHeld {
+		t.Errorf("recorded %+v, want one held learn", events)
+	}
+}
+
+func TestLearningResult_Outcome(t *testing.T) {
+	tests := []struct {
+		result LearningResult
+		want   string
+	}{
+		{LearningResult{}, OutcomeLearned},
+		{LearningResult{RequiresReview: true}, OutcomeFlagged},
+		{LearningResult{RequiresReview: true, HeldForReview: true}, OutcomeHeld},
+		{LearningResult{MergedIntoExisting: true}, OutcomeMerged},
+		{LearningResult{SkippedForgotten: true}, OutcomeForgotten},
+	}
+	for _, tt := range tests {
+		if got := tt.result.Outcome(); got != tt.want {
+			t.Errorf("Outcome() of %+v = %q, want %q", tt.result, got, tt.want)
+		}
+	}
+}
+
+func TestLearningLoop_UseLLM(t *testing.T) {
+	correction := models.Correction{
+		ID:              "test-correction-llm",
+		Timestamp:       time.Now(),
+		AgentAction:     "ran pip install requests",
+		CorrectedAction: "no, do uv add requests",
+		Context:         models.ContextSnapshot{Timestamp: time.Now()},
+	}
+	const refined = "Use uv to add Python dependencies"
+
+	for _, useLLM := range []bool{true, false} {
+		client := llm.NewMockClient().WithCompleteResponse(`{"canonical": "` + refined + `", "kind": "preference"}`)
+		loop := NewLearningLoop(store.NewInMemoryGraphStore(), &LearningLoopConfig{
+			AutoAcceptThreshold: constants.DefaultAutoAcceptThreshold,
+			LLMClient:           client,
+			UseLLM:              useLLM,
+		})
+		result, err := loop.ProcessCorrection(context.Background(), correction)
+		if err != nil {
+			t.Fatalf("UseLLM=%v: ProcessCorrection failed: %v", useLLM, err)
+		}
+		if got := result.CandidateBehavior.Content.Canonical == refined; got != useLLM {
+			t.Errorf("UseLLM=%v: canonical = %q", useLLM, result.CandidateBehavior.Content.Canonical)
+		}
+	}
+
+	// A failing LLM falls back to the rule-based behavior
+	loop := NewLearningLoop(store.NewInMemoryGraphStore(), &LearningLoopConfig{
+		LLMClient: llm.NewMockClient().WithError(errors.New("unavailable")),
+		UseLLM:    true,
+	})
+	result, err := loop.ProcessCorrection(context.Background(), correction)
+	if err != nil {
+		t.Fatalf("ProcessCorrection with failing LLM: %v", err)
+	}
+	if result.CandidateBehavior.Content.Canonical != correction.CorrectedAction {
+		t.Errorf("canonical = %q, want rule-based %q", result.CandidateBehavior.Content.Canonical, correction.CorrectedAction)
+	}
+}
+
+func TestLearningLoop_ProcessCorrection_DetectsConflicts(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+	existing := models.Behavior{
+		ID:      "behavior-always-pip",
+		Name:    "always-use-pip",
+		Kind:    models.BehaviorKindDirective,
+		When:    map[string]interface{}{"language": "python"},
+		Content: models.BehaviorContent{Canonical: "Always use pip for installing packages"},
+	}
+	if _, err := s.AddNode(ctx, models.BehaviorToNode(&existing)); err != nil {
+		t.Fatal(err)
+	}
+
+	loop := NewLearningLoop(s, nil)
+	result, err := loop.ProcessCorrection(ctx, models.Correction{
+		ID:              "test-correction-conflict",
+		Timestamp:       time.Now(),
+		AgentAction:     "ran pip install",
+		CorrectedAction: "use uv instead of pip for installing packages",
+		Context: models.ContextSnapshot{
+			Timestamp:    time.Now(),
+			FileLanguage: "python",
+			FilePath:     "requirements.txt",
+		},
+	})
+	if err != nil {
+		t.Fatalf("ProcessCorrection failed: %v", err)
+	}
+
+	if !result.RequiresReview || result.AutoAccepted {
+		t.Errorf("RequiresReview=%v AutoAccepted=%v, want review", result.RequiresReview, result.AutoAccepted)
+	}
+	found := false
+	for _, r := range result.ReviewReasons {
+		if strings.Contains(r, "Conflicts with") && strings.Contains(r, existing.ID) {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("ReviewReasons = %v, want a conflict reason", result.ReviewReasons)
+	}
+
+	id := result.CandidateBehavior.ID
+	edges, err := s.GetEdges(ctx, id, store.DirectionOutbound, store.EdgeKindConflicts)
+	if err != nil || len(edges) != 1 || edges[0].Target != existing.ID {
+		t.Errorf("conflicts edges = %+v, %v", edges, err)
+	}
+	node, _ := s.GetNode(ctx, existing.ID)
+	if got := models.NodeToBehavior(*node).Conflicts; len(got) != 1 || got[0] != id {
+		t.Errorf("existing behavior conflicts = %v, want [%s]", got, id)
+	}
+}
@@ -0,0 +1,123 @@
+package learning
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/confidence"
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/tagging"
+)
+
+// ManualKinds are the behavior kinds a person can author directly.
+var ManualKinds = []models.BehaviorKind{
+	models.BehaviorKindDirective,
+	models.BehaviorKindConstraint,
+	models.BehaviorKindPreference,
+	models.BehaviorKindProcedure,
+	models.BehaviorKindWorkflow,
+}
+
+// ManualSpec describes a hand-authored behavior: one a person writes
+// directly rather than one extracted from a correction.
+type ManualSpec struct {
+	Kind models.BehaviorKind
+
+	// Name is optional; when empty it is derived from Content.
+	Name string
+
+	// Content is the behavior's canonical text.
+	Content string
+
+	// Summary is an optional one-line reminder for tiered injection.
+	Summary string
+
+	// When holds the activation conditions.
+	When map[string]interface{}
+
+	// Tags are merged with the tags extracted from Content.
+	Tags []string
+
+	Priority  int
+	Author    string
+	ExpiresAt *time.Time
+}
+
+// NewManualBehavior builds a behavior from spec. Text is sanitized and tags
+// are extracted as for learned behaviors; provenance records the behavior
+// as manual. The ID is derived from the kind and content, so authoring the
+// same behavior twice yields the same ID.
+func NewManualBehavior(spec ManualSpec, now time.Time) (*models.Behavior, error) {
+	if !isManualKind(spec.Kind) {
+		return nil, fmt.Errorf("invalid kind %q: use one of %v", spec.Kind, ManualKinds)
+	}
+	canonical := sanitize.SanitizeBehaviorContent(spec.Content)
+	if canonical == "" {
+		return nil, fmt.Errorf("behavior content is empty")
+	}
+	if len(spec.Tags) > tagging.MaxExtraTags {
+		return nil, fmt.Errorf("at most %d tags can be given, got %d", tagging.MaxExtraTags, len(spec.Tags))
+	}
+
+	name := sanitize.SanitizeBehaviorName(spec.Name)
+	if name == "" {
+		name = slugName("manual", canonical)
+	}
+
+	when := make(map[string]interface{}, len(spec.When))
+	for key, value := range spec.When {
+		key = sanitize.SanitizeBehaviorContent(key)
+		switch v := value.(type) {
+		case string:
+			when[key] = sanitize.SanitizeBehaviorContent(v)
+		case []string:
+			values := make([]string, len(v))
+			for i, s := range v {
+				values[i] = sanitize.SanitizeBehaviorContent(s)
+			}
+			when[key] = values
+		default:
+			when[key] = v
+		}
+	}
+
+	dict := tagging.NewDictionary()
+	hash := sha256.Sum256([]byte(string(spec.Kind) + "\n" + canonical))
+	return &models.Behavior{
+		ID:   "behavior-" + hex.EncodeToString(hash[:])[:12],
+		Name: name,
+		Kind: spec.Kind,
+		When: when,
+		Content: models.BehaviorContent{
+			Canonical: canonical,
+			Summary:   sanitize.SanitizeBehaviorContent(spec.Summary),
+			Tags:      tagging.MergeTags(tagging.ExtractTags(canonical, dict), spec.Tags, dict),
+		},
+		Provenance: models.Provenance{
+			SourceType: models.SourceTypeManual,
+			CreatedAt:  now,
+			Author:     spec.Author,
+		},
+		Confidence:        constants.DefaultManualConfidence,
+		ConfidenceSources: &confidence.Sources{Base: constants.DefaultManualConfidence, Origin: confidence.OriginManual},
+		Priority:          spec.Priority,
+		Stats: models.BehaviorStats{
+			CreatedAt: now,
+			UpdatedAt: now,
+		},
+		ExpiresAt: spec.ExpiresAt,
+	}, nil
+}
+
+func isManualKind(kind models.BehaviorKind) bool {
+	for _, k := range ManualKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
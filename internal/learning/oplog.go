@@ -0,0 +1,260 @@
+package learning
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// OpLogFile is the file in .floop/ recording what each learn wrote to the
+// graph, so the most recent learn can be undone with 'floop undo'.
+const OpLogFile = "oplog.jsonl"
+
+// Operation actions.
+const (
+	OpLearn = "learn"
+	OpMerge = "merge"
+	OpUndo  = "undo"
+)
+
+// ErrNothingToUndo is returned by UndoLast when every logged operation has
+// already been undone.
+var ErrNothingToUndo = errors.New("no learn operation to undo")
+
+// NodeSnapshot is a node and its edges as they were before an operation
+// changed or deleted it.
+type NodeSnapshot struct {
+	Node  store.Node   `json:"node"`
+	Edges []store.Edge `json:"edges,omitempty"`
+}
+
+// Operation records the graph changes made by one ProcessCorrection.
+type Operation struct {
+	ID           string    `json:"id"`
+	Action       string    `json:"action"`
+	Timestamp    time.Time `json:"timestamp"`
+	CorrectionID string    `json:"correction_id,omitempty"`
+	BehaviorID   string    `json:"behavior_id,omitempty"`
+
+	// Created are nodes the operation added; undo deletes them.
+	Created []string `json:"created,omitempty"`
+	// Edges are edges the operation added between existing nodes; undo
+	// removes them. Edges of created nodes go with the nodes.
+	Edges []store.Edge `json:"edges,omitempty"`
+	// Before holds nodes the operation changed or deleted; undo restores them.
+	Before []NodeSnapshot `json:"before,omitempty"`
+
+	// Undoes is the ID of the operation an undo entry reverses.
+	Undoes string `json:"undoes,omitempty"`
+}
+
+// opRecorder accumulates the changes of one operation as the loop makes
+// them. A nil recorder records nothing.
+type opRecorder struct {
+	store   store.GraphStore
+	op      Operation
+	touched map[string]bool
+}
+
+func newOpRecorder(s store.GraphStore, action, correctionID string) *opRecorder {
+	now := time.Now()
+	return &opRecorder{
+		store: s,
+		op: Operation{
+			ID:           strconv.FormatInt(now.UnixNano(), 36),
+			Action:       action,
+			Timestamp:    now,
+			CorrectionID: correctionID,
+		},
+		touched: make(map[string]bool),
+	}
+}
+
+// snapshot records the node with the given ID before it is written or
+// deleted, or marks it created if it does not exist yet. Only the first
+// call for an ID counts.
+func (r *opRecorder) snapshot(ctx context.Context, id string) error {
+	if r == nil || r.touched[id] {
+		return nil
+	}
+	r.touched[id] = true
+	node, err := r.store.GetNode(ctx, id)
+	if err != nil {
+		return fmt.Errorf("snapshot %s: %w", id, err)
+	}
+	if node == nil {
+		r.op.Created = append(r.op.Created, id)
+		return nil
+	}
+	edges, err := r.store.GetEdges(ctx, id, store.DirectionBoth, "")
+	if err != nil {
+		return fmt.Errorf("snapshot edges of %s: %w", id, err)
+	}
+	r.op.Before = append(r.op.Before, NodeSnapshot{Node: *node, Edges: edges})
+	return nil
+}
+
+// edge records an edge about to be added, unless it already exists or
+// belongs to a created node.
+func (r *opRecorder) edge(e store.Edge) {
+	if r == nil || slices.Contains(r.op.Created, e.Source) || slices.Contains(r.op.Created, e.Target) {
+		return
+	}
+	for _, snap := range r.op.Before {
+		for _, existing := range snap.Edges {
+			if existing.Source == e.Source && existing.Target == e.Target && existing.Kind == e.Kind {
+				return
+			}
+		}
+	}
+	r.op.Edges = append(r.op.Edges, e)
+}
+
+// AppendOperation appends op to the operation log at path.
+func AppendOperation(path string, op Operation) error {
+	data, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("encoding operation: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening operation log: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing operation log: %w", err)
+	}
+	return nil
+}
+
+// LastOperation returns the most recent operation in the log at path that
+// has not been undone, or ErrNothingToUndo.
+func LastOperation(path string) (*Operation, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNothingToUndo
+	}
+	if err != nil {
+		return nil, fmt.Errorf("opening operation log: %w", err)
+	}
+	defer f.Close()
+
+	var ops []Operation
+	undone := make(map[string]bool)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		var op Operation
+		if err := json.Unmarshal(scanner.Bytes(), &op); err != nil {
+			continue // a torn final line from an interrupted write
+		}
+		if op.Action == OpUndo {
+			undone[op.Undoes] = true
+			continue
+		}
+		ops = append(ops, op)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading operation log: %w", err)
+	}
+	for i := len(ops) - 1; i >= 0; i-- {
+		if !undone[ops[i].ID] {
+			return &ops[i], nil
+		}
+	}
+	return nil, ErrNothingToUndo
+}
+
+// UndoLast reverses the most recent operation in the log at path: created
+// nodes are deleted, added edges removed, and changed or deleted nodes
+// restored with their edges. The undo is logged so the next call reverses
+// the operation before it. With dryRun the operation is returned unchanged.
+func UndoLast(ctx context.Context, s store.GraphStore, path string, dryRun bool) (*Operation, error) {
+	op, err := LastOperation(path)
+	if err != nil || dryRun {
+		return op, err
+	}
+
+	for _, id := range op.Created {
+		if err := s.DeleteNode(ctx, id); err != nil {
+			return op, fmt.Errorf("deleting %s: %w", id, err)
+		}
+	}
+	for _, e := range op.Edges {
+		if err := s.RemoveEdge(ctx, e.Source, e.Target, e.Kind); err != nil {
+			return op, fmt.Errorf("removing edge %s -> %s: %w", e.Source, e.Target, err)
+		}
+	}
+	for _, snap := range op.Before {
+		if err := restoreSnapshot(ctx, s, snap, op.Created); err != nil {
+			return op, err
+		}
+	}
+	if err := s.Sync(ctx); err != nil {
+		return op, fmt.Errorf("syncing store: %w", err)
+	}
+
+	undo := Operation{
+		ID:        strconv.FormatInt(time.Now().UnixNano(), 36),
+		Action:    OpUndo,
+		Timestamp: time.Now(),
+		Undoes:    op.ID,
+	}
+	return op, AppendOperation(path, undo)
+}
+
+// restoreSnapshot writes a node back as it was. A node that still exists is
+// updated in place; a deleted one is re-added to its scope with its edges,
+// except edges to nodes that no longer exist.
+func restoreSnapshot(ctx context.Context, s store.GraphStore, snap NodeSnapshot, created []string) error {
+	node := snap.Node
+	existing, err := s.GetNode(ctx, node.ID)
+	if err != nil {
+		return fmt.Errorf("getting %s: %w", node.ID, err)
+	}
+	if existing != nil {
+		if err := s.UpdateNode(ctx, node); err != nil {
+			return fmt.Errorf("restoring %s: %w", node.ID, err)
+		}
+		return nil
+	}
+
+	scope := constants.ScopeLocal
+	if sc, _ := node.Metadata["scope"].(string); sc == string(constants.ScopeGlobal) {
+		scope = constants.ScopeGlobal
+	}
+	if scoped, ok := s.(ScopedNodeAdder); ok {
+		_, err = scoped.AddNodeToScope(ctx, node, scope)
+	} else {
+		_, err = s.AddNode(ctx, node)
+	}
+	if err != nil {
+		return fmt.Errorf("restoring %s: %w", node.ID, err)
+	}
+
+	for _, e := range snap.Edges {
+		other := e.Target
+		if other == node.ID {
+			other = e.Source
+		}
+		if slices.Contains(created, other) {
+			continue
+		}
+		if n, err := s.GetNode(ctx, other); err != nil || n == nil {
+			continue
+		}
+		if err := s.AddEdge(ctx, e); err != nil {
+			return fmt.Errorf("restoring edge %s -> %s: %w", e.Source, e.Target, err)
+		}
+	}
+	return nil
+}
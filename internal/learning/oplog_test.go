@@ -0,0 +1,127 @@
+package learning
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestUndoLast_Learn(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	opLog := filepath.Join(t.TempDir(), OpLogFile)
+	loop := NewLearningLoop(s, &LearningLoopConfig{AutoAcceptThreshold: 0.8, OpLogPath: opLog})
+
+	var ids []string
+	for i, right := range []string{
+		"use uv instead of pip for package management",
+		"use pathlib.Path instead of os.path",
+	} {
+		result, err := loop.ProcessCorrection(ctx, models.Correction{
+			ID:              "c" + string(rune('1'+i)),
+			Timestamp:       time.Now(),
+			AgentAction:     "did it the old way",
+			CorrectedAction: right,
+			Context:         models.ContextSnapshot{FilePath: "main.py", FileLanguage: "python"},
+		})
+		if err != nil {
+			t.Fatalf("ProcessCorrection failed: %v", err)
+		}
+		ids = append(ids, result.CandidateBehavior.ID)
+	}
+
+	// Undo steps back through the learns, newest first.
+	for i := len(ids) - 1; i >= 0; i-- {
+		op, err := UndoLast(ctx, s, opLog, false)
+		if err != nil {
+			t.Fatalf("UndoLast failed: %v", err)
+		}
+		if op.Action != OpLearn || op.BehaviorID != ids[i] {
+			t.Errorf("undid %s %s, want learn %s", op.Action, op.BehaviorID, ids[i])
+		}
+		if node, _ := s.GetNode(ctx, ids[i]); node != nil {
+			t.Errorf("behavior %s still exists after undo", ids[i])
+		}
+		if i > 0 {
+			if node, _ := s.GetNode(ctx, ids[0]); node == nil {
+				t.Errorf("undo removed the earlier behavior %s", ids[0])
+			}
+		}
+	}
+
+	if _, err := UndoLast(ctx, s, opLog, false); !errors.Is(err, ErrNothingToUndo) {
+		t.Errorf("UndoLast with nothing left = %v, want ErrNothingToUndo", err)
+	}
+}
+
+func TestUndoLast_RestoresDeletedAndChanged(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	opLog := filepath.Join(t.TempDir(), OpLogFile)
+
+	behavior := func(id, canonical string) store.Node {
+		return store.Node{
+			ID:   id,
+			Kind: store.NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    id,
+				"content": map[string]interface{}{"canonical": canonical},
+			},
+			Metadata: map[string]interface{}{"confidence": 0.7},
+		}
+	}
+	if _, err := s.AddNode(ctx, behavior("kept", "original")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.AddNode(ctx, behavior("dup", "duplicate")); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.AddEdge(ctx, store.Edge{Source: "dup", Target: "kept", Kind: store.EdgeKindSimilarTo, Weight: 0.9, CreatedAt: time.Now()}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Record an operation that deletes dup and rewrites kept, as a merge does.
+	rec := newOpRecorder(s, OpMerge, "c1")
+	for _, id := range []string{"dup", "kept"} {
+		if err := rec.snapshot(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := s.DeleteNode(ctx, "dup"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.UpdateNode(ctx, behavior("kept", "merged")); err != nil {
+		t.Fatal(err)
+	}
+	if err := AppendOperation(opLog, rec.op); err != nil {
+		t.Fatal(err)
+	}
+
+	op, err := UndoLast(ctx, s, opLog, true)
+	if err != nil || op.Action != OpMerge {
+		t.Fatalf("dry run = %+v, %v", op, err)
+	}
+	if node, _ := s.GetNode(ctx, "dup"); node != nil {
+		t.Fatal("dry run restored the deleted behavior")
+	}
+
+	if _, err := UndoLast(ctx, s, opLog, false); err != nil {
+		t.Fatalf("UndoLast failed: %v", err)
+	}
+	if node, _ := s.GetNode(ctx, "dup"); node == nil {
+		t.Error("deleted behavior not restored")
+	}
+	kept, _ := s.GetNode(ctx, "kept")
+	if got := kept.Content["content"].(map[string]interface{})["canonical"]; got != "original" {
+		t.Errorf("kept canonical = %v, want original", got)
+	}
+	edges, err := s.GetEdges(ctx, "dup", store.DirectionOutbound, store.EdgeKindSimilarTo)
+	if err != nil || len(edges) != 1 {
+		t.Errorf("restored edges = %v, %v; want the similar-to edge", edges, err)
+	}
+}
@@ -3,7 +3,6 @@ package learning
 import (
 	"context"
 
-	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/dedup"
 	"github.com/nvandessel/floop/internal/llm"
 	"github.com/nvandessel/floop/internal/models"
@@ -22,6 +21,10 @@ type GraphPlacerConfig struct {
 	// LLMSimilarityThreshold is the minimum rule-based score required
 	// before invoking LLM for semantic comparison. Default: 0.5
 	LLMSimilarityThreshold float64
+
+	// Tuning overrides the default similarity weights and thresholds.
+	// nil uses similarity.DefaultTuning().
+	Tuning *similarity.Tuning
 }
 
 // DefaultGraphPlacerConfig returns a GraphPlacerConfig with sensible defaults.
@@ -120,6 +123,8 @@ func (p *graphPlacer) Place(ctx context.Context, behavior *models.Behavior) (*Pl
 		return decision, nil
 	}
 
+	th := p.tuning().Thresholds
+
 	// Track the most similar behavior for potential merge
 	var mostSimilar *models.Behavior
 	var highestSimilarity float64
@@ -129,7 +134,7 @@ func (p *graphPlacer) Place(ctx context.Context, behavior *models.Behavior) (*Pl
 		existing := &existingBehaviors[i]
 		similarity := p.computeSimilarity(ctx, behavior, existing)
 
-		if similarity > constants.SimilarToThreshold {
+		if similarity > th.SimilarTo {
 			decision.SimilarBehaviors = append(decision.SimilarBehaviors, SimilarityMatch{
 				ID:    existing.ID,
 				Score: similarity,
@@ -143,12 +148,12 @@ func (p *graphPlacer) Place(ctx context.Context, behavior *models.Behavior) (*Pl
 	}
 
 	// Decide action based on similarity
-	if highestSimilarity > constants.SimilarToUpperBound && mostSimilar != nil {
+	if highestSimilarity > th.UpperBound && mostSimilar != nil {
 		// Very high similarity - suggest merge
 		decision.Action = PlacementActionMerge
 		decision.TargetID = mostSimilar.ID
 		decision.Confidence = 0.5 // Lower confidence for merges (needs review)
-	} else if highestSimilarity > constants.SpecializeThreshold && mostSimilar != nil {
+	} else if highestSimilarity > th.Specialize && mostSimilar != nil {
 		// High similarity but not duplicate - check if we should specialize
 		if p.isMoreSpecific(behavior.When, mostSimilar.When) {
 			decision.Action = PlacementActionSpecialize
@@ -204,7 +209,7 @@ func (p *graphPlacer) computeSimilarity(ctx context.Context, a, b *models.Behavi
 	// Try LLM-based semantic comparison
 	prompt := dedup.ComparisonPrompt(a, b)
 	msgs := []llm.Message{{Role: "user", Content: prompt}}
-	response, err := p.config.LLMClient.Complete(ctx, msgs)
+	response, err := p.config.LLMClient.Complete(llm.WithPurpose(ctx, llm.PurposeCompare), msgs)
 	if err != nil {
 		// Fallback to rule-based on error
 		return ruleScore
@@ -241,7 +246,15 @@ func (p *graphPlacer) computeRuleBasedSimilarity(a, b *models.Behavior) float64
 	whenOverlap := similarity.ComputeWhenOverlap(a.When, b.When)
 	contentSim := similarity.ComputeContentSimilarity(a.Content.Canonical, b.Content.Canonical)
 	tagSim := similarity.ComputeTagSimilarity(a.Content.Tags, b.Content.Tags)
-	return similarity.WeightedScoreWithTags(whenOverlap, contentSim, tagSim)
+	return p.tuning().Score(whenOverlap, contentSim, tagSim)
+}
+
+// tuning returns the configured similarity tuning, or the defaults.
+func (p *graphPlacer) tuning() similarity.Tuning {
+	if p.config != nil && p.config.Tuning != nil {
+		return *p.config.Tuning
+	}
+	return similarity.DefaultTuning()
 }
 
 // hasOverlappingConditions checks if a behavior's when conditions overlap with node content.
@@ -274,6 +287,7 @@ func (p *graphPlacer) hasOverlappingConditions(when map[string]interface{}, cont
 // determineEdges proposes edges for the new behavior based on relationships with existing behaviors.
 func (p *graphPlacer) determineEdges(ctx context.Context, behavior *models.Behavior, existing []models.Behavior) []ProposedEdge {
 	edges := make([]ProposedEdge, 0)
+	th := p.tuning().Thresholds
 
 	for _, e := range existing {
 		// If new behavior has more specific 'when' conditions, it overrides the existing one
@@ -297,7 +311,7 @@ func (p *graphPlacer) determineEdges(ctx context.Context, behavior *models.Behav
 
 		// Add similar-to edges for behaviors with moderate similarity
 		similarity := p.computeSimilarity(ctx, behavior, &e)
-		if similarity >= constants.SimilarToThreshold && similarity < constants.SimilarToUpperBound {
+		if similarity >= th.SimilarTo && similarity < th.UpperBound {
 			edges = append(edges, ProposedEdge{
 				From: behavior.ID,
 				To:   e.ID,
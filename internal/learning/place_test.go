@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -929,3 +930,49 @@ func TestGraphPlacer_Place_MultipleSimilarBehaviors(t *testing.T) {
 		t.Errorf("Place() Action = %v, want merge", decision.Action)
 	}
 }
+
+func TestGraphPlacer_Place_TunedThresholds(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+
+	s.AddNode(ctx, store.Node{
+		ID:   "existing-1",
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"kind": "directive",
+			"name": "use-table-tests",
+			"when": map[string]interface{}{
+				"language": "go",
+			},
+			"content": map[string]interface{}{
+				"canonical": "Use table-driven tests in Go",
+			},
+		},
+	})
+
+	newBehavior := &models.Behavior{
+		ID:   "behavior-new",
+		Name: "use-table-tests-new",
+		Kind: models.BehaviorKindDirective,
+		When: map[string]interface{}{
+			"language": "go",
+		},
+		Content: models.BehaviorContent{
+			Canonical: "Use table-driven tests in Go",
+		},
+	}
+
+	// A store tuned so that nothing counts as similar places a new behavior
+	// even when the default thresholds would suggest a merge.
+	tuning := similarity.DefaultTuning()
+	tuning.Thresholds = similarity.Thresholds{SimilarTo: 1.0, Specialize: 1.0, UpperBound: 1.0}
+	placer := NewGraphPlacerWithConfig(s, &GraphPlacerConfig{Tuning: &tuning})
+
+	decision, err := placer.Place(ctx, newBehavior)
+	if err != nil {
+		t.Fatalf("Place() error = %v", err)
+	}
+	if decision.Action != PlacementActionCreate {
+		t.Errorf("Place() Action = %v, want create with tuned thresholds", decision.Action)
+	}
+}
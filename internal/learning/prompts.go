@@ -3,9 +3,13 @@ package learning
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
+	"slices"
 	"strings"
 
+	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
 )
 
 // CorrectionExtractionResult contains the result of extracting a correction from user text.
@@ -24,6 +28,12 @@ type CorrectionExtractionResult struct {
 
 	// Reasoning explains why this is or isn't a correction
 	Reasoning string `json:"reasoning,omitempty"`
+
+	// File, Language, and Task are the conditions under which the
+	// correction applies, when the text names them
+	File     string `json:"file,omitempty"`
+	Language string `json:"language,omitempty"`
+	Task     string `json:"task,omitempty"`
 }
 
 // CorrectionExtractionPrompt generates a prompt for extracting correction details from user text.
@@ -60,7 +70,10 @@ Respond with ONLY a JSON object (no markdown code blocks, no additional text):
   "wrong": "<what the agent did wrong, if this is a correction>",
   "right": "<what should be done instead, if this is a correction>",
   "confidence": <float between 0.0 and 1.0>,
-  "reasoning": "<brief explanation>"
+  "reasoning": "<brief explanation>",
+  "file": "<file path the correction is about, if the message names one>",
+  "language": "<programming language the correction applies to, if any>",
+  "task": "<kind of task, e.g. testing or refactoring, if the message says>"
 }`)
 	return prompt.String()
 }
@@ -84,3 +97,84 @@ func ParseCorrectionExtractionResponse(response string) (*CorrectionExtractionRe
 
 	return &result, nil
 }
+
+// BehaviorExtractionResult is an LLM's refinement of a behavior extracted
+// from a correction.
+type BehaviorExtractionResult struct {
+	// Canonical is the behavior restated as one clear, general instruction
+	Canonical string `json:"canonical"`
+
+	// Kind is directive, constraint, preference, or procedure
+	Kind string `json:"kind"`
+
+	// Tags are short topic keywords for the behavior
+	Tags []string `json:"tags,omitempty"`
+
+	// When holds the conditions under which the behavior applies, keyed by
+	// file_path, language, and task
+	When map[string]string `json:"when,omitempty"`
+}
+
+// BehaviorExtractionPrompt generates a prompt asking an LLM to turn a
+// correction into a clean behavior. Like CorrectionExtractionPrompt, user
+// text is concatenated rather than formatted into the template (CWE-94).
+func BehaviorExtractionPrompt(correction models.Correction) string {
+	var prompt strings.Builder
+
+	prompt.WriteString("You are turning a correction given to an AI coding agent into a reusable behavior for future sessions.\n\n## What the agent did\n")
+	prompt.WriteString(correction.AgentAction)
+	prompt.WriteString("\n\n## What it should have done\n")
+	prompt.WriteString(correction.CorrectedAction)
+	prompt.WriteString("\n\n## Context\n")
+	if correction.Context.FilePath != "" {
+		prompt.WriteString("File: ")
+		prompt.WriteString(correction.Context.FilePath)
+		prompt.WriteString("\n")
+	}
+	if correction.Context.FileLanguage != "" {
+		prompt.WriteString("Language: ")
+		prompt.WriteString(correction.Context.FileLanguage)
+		prompt.WriteString("\n")
+	}
+	if correction.Context.Task != "" {
+		prompt.WriteString("Task: ")
+		prompt.WriteString(correction.Context.Task)
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString(`
+## Task
+1. Restate what the agent should do as one concise, imperative instruction that generalizes beyond this instance
+2. Classify it: "constraint" (never do X), "preference" (prefer X over Y), "procedure" (ordered steps), or "directive" (do X)
+3. Suggest up to 5 short lowercase topic tags
+4. Give the conditions under which it applies, only if the correction implies them: a file_path glob, a language, and a task (one of: `)
+	prompt.WriteString(strings.Join(slices.Sorted(maps.Keys(constants.KnownTasks)), ", "))
+	prompt.WriteString(`)
+
+## Response Format
+Respond with ONLY a JSON object (no markdown code blocks, no additional text):
+{
+  "canonical": "<the instruction>",
+  "kind": "<directive|constraint|preference|procedure>",
+  "tags": ["<tag>"],
+  "when": {"file_path": "<glob>", "language": "<language>", "task": "<task>"}
+}`)
+	return prompt.String()
+}
+
+// ParseBehaviorExtractionResponse parses an LLM response into a
+// BehaviorExtractionResult.
+func ParseBehaviorExtractionResponse(response string) (*BehaviorExtractionResult, error) {
+	jsonStr := llm.ExtractJSON(response)
+	if jsonStr == "" {
+		return nil, fmt.Errorf("no JSON found in response")
+	}
+
+	var result BehaviorExtractionResult
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return nil, fmt.Errorf("parsing behavior extraction result: %w", err)
+	}
+	if strings.TrimSpace(result.Canonical) == "" {
+		return nil, fmt.Errorf("behavior extraction result has no canonical statement")
+	}
+	return &result, nil
+}
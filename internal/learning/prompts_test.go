@@ -3,6 +3,8 @@ package learning
 import (
 	"strings"
 	"testing"
+
+	"github.com/nvandessel/floop/internal/models"
 )
 
 func TestCorrectionExtractionPrompt(t *testing.T) {
@@ -74,3 +76,32 @@ func TestParseCorrectionExtractionResponse(t *testing.T) {
 		}
 	})
 }
+
+func TestBehaviorExtractionPrompt(t *testing.T) {
+	prompt := BehaviorExtractionPrompt(models.Correction{
+		AgentAction:     "used pip install",
+		CorrectedAction: "use uv",
+		Context:         models.ContextSnapshot{FilePath: "pyproject.toml", FileLanguage: "python"},
+	})
+	for _, want := range []string{"used pip install", "use uv", "pyproject.toml", "python", `"canonical"`, "testing"} {
+		if !strings.Contains(prompt, want) {
+			t.Errorf("prompt should contain %q", want)
+		}
+	}
+}
+
+func TestParseBehaviorExtractionResponse(t *testing.T) {
+	result, err := ParseBehaviorExtractionResponse("```json\n" + `{"canonical": "Use uv for Python packages", "kind": "preference", "tags": ["python", "uv"], "when": {"language": "python"}}` + "\n```")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Canonical != "Use uv for Python packages" || result.Kind != "preference" || len(result.Tags) != 2 || result.When["language"] != "python" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+
+	for _, bad := range []string{"no json here", `{"kind": "directive"}`, `{"canonical": 3}`} {
+		if _, err := ParseBehaviorExtractionResponse(bad); err == nil {
+			t.Errorf("ParseBehaviorExtractionResponse(%q) expected error", bad)
+		}
+	}
+}
@@ -0,0 +1,281 @@
+package learning
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/confidence"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/export"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/tagging"
+)
+
+// Rule is one instruction read from an agent rules file such as CLAUDE.md,
+// AGENTS.md, or .cursorrules.
+type Rule struct {
+	Line    int    `json:"line"`
+	Section string `json:"section,omitempty"`
+	Text    string `json:"text"`
+}
+
+var (
+	ruleHeading = regexp.MustCompile(`^#{1,6}\s+(.*?)\s*#*\s*$`)
+	ruleBullet  = regexp.MustCompile(`^(\s*)(?:[-*+]|\d+[.)])\s+(.*)$`)
+	ruleCheck   = regexp.MustCompile(`^\[[ xX]\]\s+`)
+	ruleLink    = regexp.MustCompile(`^\[[^\]]*\]\([^)]*\)$`)
+)
+
+// ParseRules extracts the rules from a markdown or plain-text rules file.
+// Each top-level bullet or numbered item is a rule; nested items and
+// continuation lines are folded into their parent. Headings become the
+// Section of the rules under them. Code blocks, HTML comments, and the
+// section maintained by 'floop export rules --sync' are ignored.
+//
+// A file with no list items, as .cursorrules often is, yields one rule per
+// line of text instead.
+func ParseRules(doc string) []Rule {
+	var (
+		rules, lines    []Rule
+		section         string
+		open            *Rule
+		openIndent      int
+		continuation    bool
+		inCode, inFloop bool
+	)
+	closeRule := func() {
+		if open != nil {
+			rules = append(rules, *open)
+			open = nil
+		}
+		continuation = false
+	}
+
+	for i, raw := range strings.Split(strings.ReplaceAll(doc, "\r\n", "\n"), "\n") {
+		lineNo := i + 1
+		trimmed := strings.TrimSpace(raw)
+		switch {
+		case inFloop:
+			inFloop = !strings.Contains(trimmed, export.RulesEndMarker)
+			continue
+		case strings.Contains(trimmed, export.RulesBeginMarker):
+			closeRule()
+			inFloop = !strings.Contains(trimmed, export.RulesEndMarker)
+			continue
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			closeRule()
+			inCode = !inCode
+			continue
+		case inCode, strings.HasPrefix(trimmed, "<!--"):
+			continue
+		case trimmed == "":
+			continuation = false
+			continue
+		}
+
+		if m := ruleHeading.FindStringSubmatch(trimmed); m != nil {
+			closeRule()
+			section = cleanRuleText(m[1])
+			continue
+		}
+
+		if m := ruleBullet.FindStringSubmatch(raw); m != nil {
+			indent := len(strings.ReplaceAll(m[1], "\t", "    "))
+			text := cleanRuleText(m[2])
+			if open != nil && indent > openIndent {
+				if strings.HasSuffix(open.Text, ":") {
+					open.Text += " " + text
+				} else {
+					open.Text += "; " + text
+				}
+			} else {
+				closeRule()
+				open = &Rule{Line: lineNo, Section: section, Text: text}
+				openIndent = indent
+			}
+			continuation = true
+			continue
+		}
+
+		if open != nil && continuation {
+			open.Text += " " + cleanRuleText(trimmed)
+			continue
+		}
+		closeRule()
+		lines = append(lines, Rule{Line: lineNo, Section: section, Text: cleanRuleText(trimmed)})
+	}
+	closeRule()
+
+	if len(rules) == 0 {
+		return lines
+	}
+	return rules
+}
+
+// cleanRuleText strips checkboxes and emphasis markers from a line of
+// markdown and collapses its whitespace. Code spans are kept.
+func cleanRuleText(s string) string {
+	s = ruleCheck.ReplaceAllString(strings.TrimSpace(s), "")
+	s = strings.NewReplacer("**", "", "__", "").Replace(s)
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// ruleSkipReason returns why text is not worth importing as a behavior, or
+// "" if it is.
+func ruleSkipReason(text string) string {
+	switch {
+	case ruleLink.MatchString(text):
+		return "a link, not a rule"
+	case len(strings.Fields(text)) < 3:
+		return "too short to be a rule"
+	}
+	return ""
+}
+
+// RuleBehavior converts a rule into a behavior. The kind is inferred from
+// the rule's wording as for corrections, and tags are extracted from both
+// the rule and its section heading. Provenance records the behavior as
+// imported from source.
+func RuleBehavior(rule Rule, source string, now time.Time) (*models.Behavior, error) {
+	extractor := NewBehaviorExtractor().(*behaviorExtractor)
+	kind := extractor.inferKind(models.Correction{CorrectedAction: rule.Text})
+
+	sectionTags := tagging.ExtractTags(rule.Section, extractor.tagDict)
+	if len(sectionTags) > tagging.MaxExtraTags {
+		sectionTags = sectionTags[:tagging.MaxExtraTags]
+	}
+	b, err := NewManualBehavior(ManualSpec{
+		Kind:    kind,
+		Content: rule.Text,
+		Tags:    sectionTags,
+	}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Name = slugName("imported", b.Content.Canonical)
+	b.Provenance = models.Provenance{
+		SourceType: models.SourceTypeImported,
+		CreatedAt:  now,
+		Package:    source,
+	}
+	b.ConfidenceSources.Origin = confidence.OriginRules
+	return b, nil
+}
+
+// Rule import outcomes.
+const (
+	RuleAdded     = "added"
+	RuleDuplicate = "duplicate"
+	RuleSkipped   = "skipped"
+)
+
+// ImportedRule is what ImportRules decided for one rule.
+type ImportedRule struct {
+	Line   int                 `json:"line"`
+	Text   string              `json:"text"`
+	Action string              `json:"action"`
+	ID     string              `json:"id,omitempty"` // the new behavior, or the one it duplicates
+	Kind   models.BehaviorKind `json:"kind,omitempty"`
+	Tags   []string            `json:"tags,omitempty"`
+	// Similarity to the existing behavior, for duplicates.
+	Similarity float64 `json:"similarity,omitempty"`
+	Reason     string  `json:"reason,omitempty"`
+}
+
+// RuleImportResult reports the outcome of ImportRules.
+type RuleImportResult struct {
+	Source     string         `json:"source"`
+	Rules      []ImportedRule `json:"rules"`
+	Added      int            `json:"added"`
+	Duplicates int            `json:"duplicates"`
+	Skipped    int            `json:"skipped"`
+	// Behaviors are the behaviors to add, in rule order.
+	Behaviors []*models.Behavior `json:"-"`
+}
+
+// RuleImportOptions configures ImportRules.
+type RuleImportOptions struct {
+	// Source names the rules file, recorded in each behavior's provenance.
+	Source string
+
+	// SimilarityThreshold is the score at or above which a rule is a
+	// duplicate of an existing behavior.
+	SimilarityThreshold float64
+
+	// UseLLM and LLMClient enable semantic comparison, as for deduplication.
+	UseLLM    bool
+	LLMClient llm.Client
+
+	Now time.Time
+}
+
+// ImportRules converts rules into behaviors and deduplicates them against
+// existing and against each other. It writes nothing: the behaviors to add
+// are returned in the result's Behaviors.
+func ImportRules(existing []models.Behavior, rules []Rule, opts RuleImportOptions) *RuleImportResult {
+	now := opts.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	simCfg := dedup.SimilarityConfig{
+		UseLLM:              opts.UseLLM,
+		LLMClient:           opts.LLMClient,
+		SimilarityThreshold: opts.SimilarityThreshold,
+		EmbeddingCache:      dedup.NewEmbeddingCache(),
+	}
+	candidates := append([]models.Behavior(nil), existing...)
+
+	result := &RuleImportResult{Source: opts.Source, Rules: []ImportedRule{}}
+	for _, rule := range rules {
+		entry := ImportedRule{Line: rule.Line, Text: rule.Text, Action: RuleSkipped}
+		if entry.Reason = ruleSkipReason(rule.Text); entry.Reason != "" {
+			result.Skipped++
+			result.Rules = append(result.Rules, entry)
+			continue
+		}
+		b, err := RuleBehavior(rule, opts.Source, now)
+		if err != nil {
+			entry.Reason = err.Error()
+			result.Skipped++
+			result.Rules = append(result.Rules, entry)
+			continue
+		}
+		entry.Kind, entry.Tags = b.Kind, b.Content.Tags
+
+		if match, score := closestRule(b, candidates, simCfg); match != nil {
+			entry.Action, entry.ID, entry.Similarity = RuleDuplicate, match.ID, score
+			result.Duplicates++
+		} else {
+			entry.Action, entry.ID = RuleAdded, b.ID
+			result.Added++
+			result.Behaviors = append(result.Behaviors, b)
+			candidates = append(candidates, *b)
+		}
+		result.Rules = append(result.Rules, entry)
+	}
+	return result
+}
+
+// closestRule returns the candidate b duplicates: one with the same ID or
+// content, or else the most similar at or above the threshold.
+func closestRule(b *models.Behavior, candidates []models.Behavior, cfg dedup.SimilarityConfig) (*models.Behavior, float64) {
+	for i := range candidates {
+		if candidates[i].ID == b.ID || candidates[i].Content.Canonical == b.Content.Canonical {
+			return &candidates[i], 1
+		}
+	}
+	var best *models.Behavior
+	var bestScore float64
+	for i := range candidates {
+		if score := dedup.ComputeSimilarity(b, &candidates[i], cfg).Score; score > bestScore {
+			best, bestScore = &candidates[i], score
+		}
+	}
+	if best == nil || bestScore < cfg.SimilarityThreshold {
+		return nil, 0
+	}
+	return best, bestScore
+}
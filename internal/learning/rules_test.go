@@ -0,0 +1,107 @@
+package learning
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/models"
+)
+
+func TestParseRules(t *testing.T) {
+	tests := []struct {
+		name string
+		doc  string
+		want []Rule
+	}{
+		{
+			name: "bullets under headings",
+			doc:  "# Project\n\nSome intro text.\n\n## Testing\n\n- Use **table-driven** tests\n* [x] Run `go test -race` before pushing\n\n## Style\n1. Never use panic in library code\n",
+			want: []Rule{
+				{Line: 7, Section: "Testing", Text: "Use table-driven tests"},
+				{Line: 8, Section: "Testing", Text: "Run `go test -race` before pushing"},
+				{Line: 11, Section: "Style", Text: "Never use panic in library code"},
+			},
+		},
+		{
+			name: "nested items and continuations fold into the parent",
+			doc:  "- Commit messages:\n  - start with a verb\n  - stay under 72 characters\n- Prefer small PRs that\n  do one thing\n",
+			want: []Rule{
+				{Line: 1, Text: "Commit messages: start with a verb; stay under 72 characters"},
+				{Line: 4, Text: "Prefer small PRs that do one thing"},
+			},
+		},
+		{
+			name: "code blocks, comments, and the floop section are ignored",
+			doc:  "- Keep handlers thin\n```bash\n- not a rule\n```\n<!-- - also not a rule -->\n<!-- floop:begin -->\n- Learned by floop already\n<!-- floop:end -->\n- Wrap errors with context\n",
+			want: []Rule{
+				{Line: 1, Text: "Keep handlers thin"},
+				{Line: 9, Text: "Wrap errors with context"},
+			},
+		},
+		{
+			name: "plain text is one rule per line",
+			doc:  "You are an expert Go developer.\r\nAlways handle errors explicitly.\r\n\r\nNever ignore context cancellation.\r\n",
+			want: []Rule{
+				{Line: 1, Text: "You are an expert Go developer."},
+				{Line: 2, Text: "Always handle errors explicitly."},
+				{Line: 4, Text: "Never ignore context cancellation."},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ParseRules(tt.doc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseRules() =\n%+v\nwant\n%+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportRules(t *testing.T) {
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	existing := []models.Behavior{{
+		ID:      "behavior-existing",
+		Kind:    models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "Run go vet before committing"},
+	}}
+	rules := []Rule{
+		{Line: 1, Section: "Git workflow", Text: "Never force push to main"},
+		{Line: 2, Text: "Run go vet before committing"},
+		{Line: 3, Text: "Never force push to main"},
+		{Line: 4, Text: "[docs](https://example.com)"},
+		{Line: 5, Text: "Be nice"},
+	}
+
+	result := ImportRules(existing, rules, RuleImportOptions{Source: "CLAUDE.md", SimilarityThreshold: 0.9, Now: now})
+
+	var actions []string
+	for _, r := range result.Rules {
+		actions = append(actions, r.Action)
+	}
+	want := []string{RuleAdded, RuleDuplicate, RuleDuplicate, RuleSkipped, RuleSkipped}
+	if !reflect.DeepEqual(actions, want) {
+		t.Fatalf("actions = %v, want %v", actions, want)
+	}
+	if result.Added != 1 || result.Duplicates != 2 || result.Skipped != 2 || len(result.Behaviors) != 1 {
+		t.Errorf("counts = %d added, %d duplicates, %d skipped", result.Added, result.Duplicates, result.Skipped)
+	}
+	if result.Rules[1].ID != "behavior-existing" || result.Rules[2].ID != result.Rules[0].ID {
+		t.Errorf("duplicates point at %s and %s", result.Rules[1].ID, result.Rules[2].ID)
+	}
+
+	b := result.Behaviors[0]
+	if b.Kind != models.BehaviorKindConstraint {
+		t.Errorf("Kind = %s, want constraint", b.Kind)
+	}
+	if b.Provenance.SourceType != models.SourceTypeImported || b.Provenance.Package != "CLAUDE.md" {
+		t.Errorf("Provenance = %+v", b.Provenance)
+	}
+	if b.Name != "imported/never-force-push-to-main" {
+		t.Errorf("Name = %q", b.Name)
+	}
+	if !reflect.DeepEqual(b.Content.Tags, result.Rules[0].Tags) || len(b.Content.Tags) == 0 {
+		t.Errorf("Tags = %v, reported %v", b.Content.Tags, result.Rules[0].Tags)
+	}
+}
@@ -0,0 +1,172 @@
+package learning
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// ErrNotCorrection is returned by ExtractFromText when the text does not
+// read as a correction.
+var ErrNotCorrection = errors.New("text does not look like a correction")
+
+// Extraction methods reported in TextCorrection.Method.
+const (
+	ExtractMethodLLM     = "llm"
+	ExtractMethodPattern = "pattern"
+)
+
+// TextCorrection is a correction derived from free-form text such as a chat
+// excerpt or a code review comment.
+type TextCorrection struct {
+	Wrong      string  `json:"wrong,omitempty"`
+	Right      string  `json:"right"`
+	FilePath   string  `json:"file,omitempty"`
+	Language   string  `json:"language,omitempty"`
+	Task       string  `json:"task,omitempty"`
+	Confidence float64 `json:"confidence"`
+	Method     string  `json:"method"`
+}
+
+// correctionPatterns split a correction sentence into its wrong and right
+// halves. Each has named groups "wrong" and "right".
+var correctionPatterns = []*regexp.Regexp{
+	// "don't X, Y instead", "never X; always Y"
+	regexp.MustCompile(`(?i)\b(?:don't|do not|never|stop|quit)\s+(?P<wrong>.+?)\s*[,;.]\s+(?:but\s+|instead\s*,?\s*)?(?P<right>.+)$`),
+	// "prefer X over Y"
+	regexp.MustCompile(`(?i)\b(?P<right>(?:prefer|favou?r|choose)\s+.+?)\s+over\s+(?P<wrong>.+)$`),
+	// "use X instead of Y", "X rather than Y"
+	regexp.MustCompile(`(?i)^(?P<right>.+?)\s+(?:instead of|rather than)\s+(?P<wrong>.+)$`),
+	// "X, not Y"
+	regexp.MustCompile(`(?i)^(?P<right>.+?)\s*,\s*not\s+(?P<wrong>.+)$`),
+}
+
+// leadingFiller matches discourse markers that open a correction but carry
+// no content ("No,", "Actually,").
+var leadingFiller = regexp.MustCompile(`(?i)^(?:(?:no|nope|actually|hmm|well|please)\b[,.!]?\s*)+`)
+
+// filePathToken matches words that look like file paths.
+var filePathToken = regexp.MustCompile("[\\w./-]+\\.[A-Za-z]{1,4}\\b")
+
+// ExtractFromText derives a correction from free-form text. Text without
+// correction signals is rejected with ErrNotCorrection. When client is
+// available it is asked to extract the wrong/right pair and conditions;
+// if it is nil, unavailable, or fails, the pair is split with pattern
+// rules and the conditions are inferred from file paths in the text.
+func ExtractFromText(ctx context.Context, capture CorrectionCapture, client llm.Client, text string) (*TextCorrection, error) {
+	text = strings.TrimSpace(text)
+	if text == "" || !capture.MightBeCorrection(text) {
+		return nil, ErrNotCorrection
+	}
+
+	result := extractWithLLM(ctx, client, text)
+	if result == nil {
+		result = extractWithPatterns(text)
+	}
+	if result.FilePath == "" {
+		result.FilePath = findFilePath(text)
+	}
+	if result.Language == "" && result.FilePath != "" {
+		result.Language = models.InferLanguage(result.FilePath)
+	}
+	return result, nil
+}
+
+// extractWithLLM returns the client's extraction, or nil if the client is
+// unavailable, fails, or finds no wrong/right pair. The caller has already
+// asked to learn from the text, so a nil result falls back to patterns
+// rather than discarding it.
+func extractWithLLM(ctx context.Context, client llm.Client, text string) *TextCorrection {
+	if client == nil || !client.Available() {
+		return nil
+	}
+	response, err := client.Complete(llm.WithPurpose(ctx, llm.PurposeExtract), []llm.Message{{Role: "user", Content: CorrectionExtractionPrompt(text)}})
+	if err != nil {
+		return nil
+	}
+	parsed, err := ParseCorrectionExtractionResponse(response)
+	if err != nil || !parsed.IsCorrection || strings.TrimSpace(parsed.Right) == "" {
+		return nil
+	}
+	return &TextCorrection{
+		Wrong:      strings.TrimSpace(parsed.Wrong),
+		Right:      strings.TrimSpace(parsed.Right),
+		FilePath:   parsed.File,
+		Language:   strings.ToLower(parsed.Language),
+		Task:       parsed.Task,
+		Confidence: parsed.Confidence,
+		Method:     ExtractMethodLLM,
+	}
+}
+
+// extractWithPatterns splits the first sentence that carries a correction
+// signal into wrong and right. Without a match, the whole text becomes the
+// right action at lower confidence.
+func extractWithPatterns(text string) *TextCorrection {
+	capture := NewCorrectionCapture()
+	sentence := text
+	for _, s := range splitSentences(text) {
+		if capture.MightBeCorrection(s) {
+			sentence = s
+			break
+		}
+	}
+	sentence = leadingFiller.ReplaceAllString(sentence, "")
+
+	for _, re := range correctionPatterns {
+		m := re.FindStringSubmatch(sentence)
+		if m == nil {
+			continue
+		}
+		wrong := trimClause(m[re.SubexpIndex("wrong")])
+		right := trimClause(m[re.SubexpIndex("right")])
+		if wrong != "" && right != "" {
+			return &TextCorrection{Wrong: wrong, Right: right, Confidence: 0.7, Method: ExtractMethodPattern}
+		}
+	}
+	return &TextCorrection{Right: trimClause(sentence), Confidence: 0.5, Method: ExtractMethodPattern}
+}
+
+// splitSentences splits text on sentence-ending punctuation and newlines.
+func splitSentences(text string) []string {
+	var sentences []string
+	for _, line := range strings.Split(text, "\n") {
+		start := 0
+		for i := 0; i < len(line); i++ {
+			if (line[i] == '.' || line[i] == '!' || line[i] == '?') && (i+1 == len(line) || line[i+1] == ' ') {
+				if s := strings.TrimSpace(line[start : i+1]); s != "" {
+					sentences = append(sentences, s)
+				}
+				start = i + 1
+			}
+		}
+		if s := strings.TrimSpace(line[start:]); s != "" {
+			sentences = append(sentences, s)
+		}
+	}
+	return sentences
+}
+
+// trimClause strips surrounding whitespace, trailing punctuation, and a
+// trailing "instead" from a clause.
+func trimClause(s string) string {
+	s = strings.TrimSpace(s)
+	s = strings.TrimRight(s, ".!?,; ")
+	s = strings.TrimSpace(strings.TrimSuffix(s, " instead"))
+	return s
+}
+
+// findFilePath returns the first word in text that names a file in a known
+// language.
+func findFilePath(text string) string {
+	for _, tok := range filePathToken.FindAllString(text, -1) {
+		if models.InferLanguage(tok) != "" {
+			return tok
+		}
+	}
+	return ""
+}
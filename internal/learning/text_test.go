@@ -0,0 +1,106 @@
+package learning
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/llm"
+)
+
+func TestExtractFromText_Patterns(t *testing.T) {
+	tests := []struct {
+		name      string
+		text      string
+		wantWrong string
+		wantRight string
+		wantFile  string
+		wantLang  string
+	}{
+		{
+			name:      "don't X, Y instead",
+			text:      "No, don't use pip, use uv instead.",
+			wantWrong: "use pip",
+			wantRight: "use uv",
+		},
+		{
+			name:      "instead of",
+			text:      "Actually, use pathlib.Path instead of os.path in scripts/build.py",
+			wantWrong: "os.path in scripts/build.py",
+			wantRight: "use pathlib.Path",
+			wantFile:  "scripts/build.py",
+			wantLang:  "python",
+		},
+		{
+			name:      "prefer over",
+			text:      "Looks fine overall. I'd prefer table-driven tests over copy-pasted cases.",
+			wantWrong: "copy-pasted cases",
+			wantRight: "prefer table-driven tests",
+		},
+		{
+			name:      "review comment on a file",
+			text:      "internal/store/sqlite.go: never ignore the error from Close; wrap it",
+			wantWrong: "ignore the error from Close",
+			wantRight: "wrap it",
+			wantFile:  "internal/store/sqlite.go",
+			wantLang:  "go",
+		},
+		{
+			name:      "directive without a wrong half",
+			text:      "Always run go vet before committing.",
+			wantRight: "Always run go vet before committing",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ExtractFromText(context.Background(), NewCorrectionCapture(), nil, tt.text)
+			if err != nil {
+				t.Fatalf("ExtractFromText() error = %v", err)
+			}
+			if got.Method != ExtractMethodPattern {
+				t.Errorf("Method = %q, want %q", got.Method, ExtractMethodPattern)
+			}
+			if got.Wrong != tt.wantWrong || got.Right != tt.wantRight {
+				t.Errorf("wrong/right = %q / %q, want %q / %q", got.Wrong, got.Right, tt.wantWrong, tt.wantRight)
+			}
+			if got.FilePath != tt.wantFile || got.Language != tt.wantLang {
+				t.Errorf("file/language = %q / %q, want %q / %q", got.FilePath, got.Language, tt.wantFile, tt.wantLang)
+			}
+		})
+	}
+}
+
+func TestExtractFromText_NotCorrection(t *testing.T) {
+	for _, text := range []string{"", "Thanks, that looks great!"} {
+		if _, err := ExtractFromText(context.Background(), NewCorrectionCapture(), nil, text); !errors.Is(err, ErrNotCorrection) {
+			t.Errorf("ExtractFromText(%q) error = %v, want ErrNotCorrection", text, err)
+		}
+	}
+}
+
+func TestExtractFromText_LLM(t *testing.T) {
+	text := "don't shell out to git here, use go-git"
+	client := llm.NewMockClient().WithCompleteResponse(`{"is_correction": true, "wrong": "shelled out to git",
+		"right": "use go-git for repository access", "confidence": 0.9, "language": "Go", "task": "refactoring"}`)
+
+	got, err := ExtractFromText(context.Background(), NewCorrectionCapture(), client, text)
+	if err != nil {
+		t.Fatalf("ExtractFromText() error = %v", err)
+	}
+	if got.Method != ExtractMethodLLM || got.Right != "use go-git for repository access" || got.Wrong != "shelled out to git" {
+		t.Errorf("got %+v", got)
+	}
+	if got.Language != "go" || got.Task != "refactoring" || got.Confidence != 0.9 {
+		t.Errorf("conditions = %+v", got)
+	}
+
+	// A failing client falls back to the pattern rules.
+	failing := llm.NewMockClient().WithError(errors.New("timeout"))
+	got, err = ExtractFromText(context.Background(), NewCorrectionCapture(), failing, text)
+	if err != nil {
+		t.Fatalf("ExtractFromText() error = %v", err)
+	}
+	if got.Method != ExtractMethodPattern || got.Wrong != "shell out to git here" || got.Right != "use go-git" {
+		t.Errorf("fallback = %+v", got)
+	}
+}
@@ -0,0 +1,179 @@
+// Package lint checks behavior canonical content against style rules so that
+// learned behaviors read as short, standalone instructions.
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Rule names reported in Issue.Rule.
+const (
+	RuleImperativeMood    = "imperative-mood"
+	RuleMaxLength         = "max-length"
+	RuleFirstPerson       = "first-person"
+	RuleUnresolvedPronoun = "unresolved-pronoun"
+)
+
+// DefaultMaxLength is the default style limit for canonical content, in
+// characters. It is well below sanitize.MaxContentLength, which is a hard cap.
+const DefaultMaxLength = 280
+
+// Issue is a single style problem found in a piece of content.
+type Issue struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// String formats the issue as "rule: message".
+func (i Issue) String() string {
+	return fmt.Sprintf("%s: %s", i.Rule, i.Message)
+}
+
+// Options configures the linter.
+type Options struct {
+	// MaxLength is the maximum canonical content length in characters.
+	// Zero or negative uses DefaultMaxLength.
+	MaxLength int
+}
+
+// DefaultOptions returns the default lint options.
+func DefaultOptions() Options {
+	return Options{MaxLength: DefaultMaxLength}
+}
+
+var (
+	reWord = regexp.MustCompile(`[A-Za-z']+`)
+
+	// reSentenceEnd splits text into sentences.
+	reSentenceEnd = regexp.MustCompile(`[.!?;]+(\s+|$)`)
+)
+
+// nonImperativeStarts are opening words that signal a statement, suggestion
+// or question rather than an instruction ("You should...", "The agent...").
+var nonImperativeStarts = map[string]bool{
+	"you": true, "we": true, "i": true, "it": true, "they": true, "he": true, "she": true,
+	"this": true, "that": true, "these": true, "those": true,
+	"the": true, "a": true, "an": true, "agent": true, "claude": true,
+	"should": true, "must": true, "need": true, "needs": true, "maybe": true, "probably": true,
+	"can": true, "could": true, "would": true, "why": true, "what": true, "how": true,
+}
+
+// firstPersonWords are pronouns that tie the behavior to a speaker.
+var firstPersonWords = map[string]bool{
+	"i": true, "i'm": true, "i've": true, "i'd": true, "i'll": true,
+	"me": true, "my": true, "mine": true, "myself": true,
+	"we": true, "we're": true, "we've": true, "we'd": true, "we'll": true,
+	"us": true, "our": true, "ours": true, "ourselves": true,
+}
+
+// personalPronouns are pronouns that need an antecedent. Behaviors are read
+// out of context, so one in the opening words has nothing to refer to.
+var personalPronouns = map[string]bool{"it": true, "its": true, "they": true, "them": true}
+
+// demonstratives dangle when they end a sentence ("Never do that.").
+var demonstratives = map[string]bool{"this": true, "that": true, "these": true, "those": true}
+
+// pronounWindow is how many opening words of the content are checked for
+// personal pronouns without an antecedent.
+const pronounWindow = 3
+
+// Lint checks text against all style rules and returns the issues found,
+// in rule order. Empty text returns no issues.
+func Lint(text string, opts Options) []Issue {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil
+	}
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = DefaultMaxLength
+	}
+
+	words := lowerWords(text)
+	var issues []Issue
+
+	if len(words) > 0 && nonImperativeStarts[words[0]] {
+		issues = append(issues, Issue{
+			Rule:    RuleImperativeMood,
+			Message: fmt.Sprintf("starts with %q; start with a verb, e.g. \"Use...\" or \"Never...\"", words[0]),
+		})
+	}
+
+	if n := utf8.RuneCountInString(text); n > opts.MaxLength {
+		issues = append(issues, Issue{
+			Rule:    RuleMaxLength,
+			Message: fmt.Sprintf("%d characters; keep it under %d", n, opts.MaxLength),
+		})
+	}
+
+	if found := matching(words, firstPersonWords); len(found) > 0 {
+		issues = append(issues, Issue{
+			Rule:    RuleFirstPerson,
+			Message: fmt.Sprintf("uses first person (%s); state the rule without a speaker", strings.Join(found, ", ")),
+		})
+	}
+
+	if found := unresolvedPronouns(text, words); len(found) > 0 {
+		issues = append(issues, Issue{
+			Rule:    RuleUnresolvedPronoun,
+			Message: fmt.Sprintf("%s has nothing to refer to; name the thing instead", strings.Join(found, ", ")),
+		})
+	}
+
+	return issues
+}
+
+// lowerWords returns the words in text, lowercased, with surrounding
+// apostrophes trimmed.
+func lowerWords(text string) []string {
+	raw := reWord.FindAllString(text, -1)
+	words := make([]string, 0, len(raw))
+	for _, w := range raw {
+		if w = strings.Trim(strings.ToLower(w), "'"); w != "" {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// matching returns the distinct words that are in set, in order of first use.
+func matching(words []string, set map[string]bool) []string {
+	seen := make(map[string]bool)
+	var found []string
+	for _, w := range words {
+		if set[w] && !seen[w] {
+			seen[w] = true
+			found = append(found, fmt.Sprintf("%q", w))
+		}
+	}
+	return found
+}
+
+// unresolvedPronouns returns pronouns in the opening words and demonstratives
+// that end a sentence.
+func unresolvedPronouns(text string, words []string) []string {
+	opening := words
+	if len(opening) > pronounWindow {
+		opening = opening[:pronounWindow]
+	}
+	found := matching(opening, personalPronouns)
+
+	seen := make(map[string]bool)
+	for _, s := range found {
+		seen[s] = true
+	}
+	for _, sentence := range reSentenceEnd.Split(text, -1) {
+		sw := lowerWords(sentence)
+		if len(sw) == 0 {
+			continue
+		}
+		last := sw[len(sw)-1]
+		if q := fmt.Sprintf("%q", last); demonstratives[last] && !seen[q] {
+			seen[q] = true
+			found = append(found, q)
+		}
+	}
+	return found
+}
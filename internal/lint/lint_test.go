@@ -0,0 +1,90 @@
+package lint
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/llm"
+)
+
+func rules(issues []Issue) []string {
+	var names []string
+	for _, i := range issues {
+		names = append(names, i.Rule)
+	}
+	return names
+}
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"clean", "Never force push to shared branches", nil},
+		{"clean multi-sentence", "Use pathlib for file paths. Prefer it over os.path in new code.", nil},
+		{"empty", "   ", nil},
+		{"you should", "You should run tests before pushing", []string{RuleImperativeMood}},
+		{"modal start", "Should use tabs", []string{RuleImperativeMood}},
+		{"first person", "Use tabs because I prefer them", []string{RuleFirstPerson}},
+		{"we", "We always use goimports", []string{RuleImperativeMood, RuleFirstPerson}},
+		{"pronoun at start", "Use it instead of the old API", []string{RuleUnresolvedPronoun}},
+		{"dangling demonstrative", "Never do that.", []string{RuleUnresolvedPronoun}},
+		{"dangling demonstrative mid-text", "Don't do this; run make lint", []string{RuleUnresolvedPronoun}},
+		{"demonstrative with noun", "Use this pattern for errors", nil},
+		{"too long", "Use " + strings.Repeat("x", DefaultMaxLength), []string{RuleMaxLength}},
+		{"everything", "I think it is bad. Don't do that", []string{RuleImperativeMood, RuleFirstPerson, RuleUnresolvedPronoun}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := rules(Lint(tt.text, DefaultOptions()))
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("Lint(%q) rules = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLint_MaxLengthOption(t *testing.T) {
+	text := "Run go vet before committing"
+	if issues := Lint(text, Options{MaxLength: 10}); len(issues) != 1 || issues[0].Rule != RuleMaxLength {
+		t.Errorf("expected max-length issue, got %v", issues)
+	}
+	if issues := Lint(text, Options{}); len(issues) != 0 {
+		t.Errorf("zero MaxLength should use the default, got %v", issues)
+	}
+}
+
+func TestSuggestRewrite(t *testing.T) {
+	issues := Lint("dont use fmt.Println pls, I hate it", DefaultOptions())
+	client := llm.NewMockClient().
+		WithAvailable(true).
+		WithCompleteResponse("```json\n{\"rewrite\": \"Use the structured logger instead of fmt.Println\"}\n```")
+
+	got, err := SuggestRewrite(context.Background(), client, "dont use fmt.Println pls, I hate it", issues, DefaultOptions())
+	if err != nil {
+		t.Fatalf("SuggestRewrite: %v", err)
+	}
+	if got != "Use the structured logger instead of fmt.Println" {
+		t.Errorf("rewrite = %q", got)
+	}
+	prompt := client.CompleteCalls[0].Messages[0].Content
+	if !strings.Contains(prompt, "dont use fmt.Println pls") || !strings.Contains(prompt, RuleFirstPerson) {
+		t.Errorf("prompt missing content or issues:\n%s", prompt)
+	}
+}
+
+func TestSuggestRewrite_Errors(t *testing.T) {
+	ctx := context.Background()
+	if _, err := SuggestRewrite(ctx, nil, "x", nil, DefaultOptions()); err == nil {
+		t.Error("expected error for nil client")
+	}
+	if _, err := SuggestRewrite(ctx, llm.NewMockClient().WithAvailable(false), "x", nil, DefaultOptions()); err == nil {
+		t.Error("expected error for unavailable client")
+	}
+	client := llm.NewMockClient().WithAvailable(true).WithCompleteResponse(`{"rewrite": ""}`)
+	if _, err := SuggestRewrite(ctx, client, "x", nil, DefaultOptions()); err == nil {
+		t.Error("expected error for empty rewrite")
+	}
+}
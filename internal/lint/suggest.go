@@ -0,0 +1,79 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/llm"
+)
+
+// RewritePrompt generates a prompt asking an LLM to rewrite content so it
+// passes the given lint issues.
+//
+// User-provided text is concatenated via strings.Builder rather than
+// interpolated through fmt.Sprintf, to prevent quote-breaking if the text
+// contains special characters (CWE-94).
+func RewritePrompt(text string, issues []Issue, opts Options) string {
+	if opts.MaxLength <= 0 {
+		opts.MaxLength = DefaultMaxLength
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString("You are editing a behavior rule that will be shown to an AI coding agent.\n\n## Rule\n")
+	prompt.WriteString(text)
+	prompt.WriteString("\n\n## Problems\n")
+	for _, issue := range issues {
+		prompt.WriteString("- ")
+		prompt.WriteString(issue.String())
+		prompt.WriteString("\n")
+	}
+	prompt.WriteString(fmt.Sprintf(`
+## Task
+Rewrite the rule so it fixes the problems while keeping its meaning:
+- Use the imperative mood (start with a verb such as "Use", "Run", "Never", "Prefer")
+- Keep it under %d characters
+- Do not use first person (I, me, my, we, our)
+- Do not use pronouns that refer to something outside the rule
+
+## Response Format
+Respond with ONLY a JSON object (no markdown code blocks, no additional text):
+{"rewrite": "<the rewritten rule>"}`, opts.MaxLength))
+	return prompt.String()
+}
+
+// ParseRewriteResponse extracts the rewritten content from an LLM response.
+func ParseRewriteResponse(response string) (string, error) {
+	jsonStr := llm.ExtractJSON(response)
+	if jsonStr == "" {
+		return "", fmt.Errorf("no JSON found in response")
+	}
+
+	var result struct {
+		Rewrite string `json:"rewrite"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return "", fmt.Errorf("parsing rewrite response: %w", err)
+	}
+	rewrite := strings.TrimSpace(result.Rewrite)
+	if rewrite == "" {
+		return "", fmt.Errorf("empty rewrite in response")
+	}
+	return rewrite, nil
+}
+
+// SuggestRewrite asks client for a rewrite of text that fixes issues.
+// It returns an error if the client is nil or unavailable.
+func SuggestRewrite(ctx context.Context, client llm.Client, text string, issues []Issue, opts Options) (string, error) {
+	if client == nil || !client.Available() {
+		return "", fmt.Errorf("no LLM client available")
+	}
+	response, err := client.Complete(ctx, []llm.Message{
+		{Role: "user", Content: RewritePrompt(text, issues, opts)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("requesting rewrite: %w", err)
+	}
+	return ParseRewriteResponse(response)
+}
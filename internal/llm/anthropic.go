@@ -79,7 +79,11 @@ type anthropicResponse struct {
 		Text string `json:"text"`
 	} `json:"content"`
 	StopReason string `json:"stop_reason"`
-	Error      *struct {
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+	Error *struct {
 		Type    string `json:"type"`
 		Message string `json:"message"`
 	} `json:"error,omitempty"`
@@ -87,8 +91,15 @@ type anthropicResponse struct {
 
 // Complete sends messages to the Anthropic API and returns the response text.
 func (c *AnthropicClient) Complete(ctx context.Context, messages []Message) (string, error) {
+	response, _, err := c.CompleteWithUsage(ctx, messages)
+	return response, err
+}
+
+// CompleteWithUsage is Complete that also reports the tokens the API
+// counted for the request.
+func (c *AnthropicClient) CompleteWithUsage(ctx context.Context, messages []Message) (string, Usage, error) {
 	if !c.Available() {
-		return "", fmt.Errorf("anthropic client not available: missing API key")
+		return "", Usage{}, fmt.Errorf("anthropic client not available: missing API key")
 	}
 
 	// Separate system messages from user/assistant messages.
@@ -101,7 +112,7 @@ func (c *AnthropicClient) Complete(ctx context.Context, messages []Message) (str
 		if m.Role == "system" {
 			systemCount++
 			if systemCount > 1 {
-				return "", fmt.Errorf("anthropic API supports a single system message, got %d", systemCount)
+				return "", Usage{}, fmt.Errorf("anthropic API supports a single system message, got %d", systemCount)
 			}
 			system = m.Content
 		} else {
@@ -113,7 +124,7 @@ func (c *AnthropicClient) Complete(ctx context.Context, messages []Message) (str
 	}
 
 	if len(apiMsgs) == 0 {
-		return "", fmt.Errorf("anthropic API requires at least one non-system message")
+		return "", Usage{}, fmt.Errorf("anthropic API requires at least one non-system message")
 	}
 
 	reqBody := anthropicRequest{
@@ -125,12 +136,12 @@ func (c *AnthropicClient) Complete(ctx context.Context, messages []Message) (str
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("marshaling request: %w", err)
+		return "", Usage{}, fmt.Errorf("marshaling request: %w", err)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, anthropicAPIURL, bytes.NewReader(jsonBody))
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", Usage{}, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -139,40 +150,40 @@ func (c *AnthropicClient) Complete(ctx context.Context, messages []Message) (str
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("sending request: %w", err)
+		return "", Usage{}, fmt.Errorf("sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("reading response body: %w", err)
+		return "", Usage{}, fmt.Errorf("reading response body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(body))
+		return "", Usage{}, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var apiResp anthropicResponse
 	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return "", fmt.Errorf("parsing API response: %w", err)
+		return "", Usage{}, fmt.Errorf("parsing API response: %w", err)
 	}
 
 	if apiResp.Error != nil {
-		return "", fmt.Errorf("API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
+		return "", Usage{}, fmt.Errorf("API error: %s - %s", apiResp.Error.Type, apiResp.Error.Message)
 	}
 
 	if len(apiResp.Content) == 0 {
-		return "", fmt.Errorf("empty response from API")
+		return "", Usage{}, fmt.Errorf("empty response from API")
 	}
 
 	// Extract text from the first content block
 	for _, content := range apiResp.Content {
 		if content.Type == "text" {
-			return content.Text, nil
+			return content.Text, Usage{Model: c.model, InputTokens: apiResp.Usage.InputTokens, OutputTokens: apiResp.Usage.OutputTokens}, nil
 		}
 	}
 
-	return "", fmt.Errorf("no text content in API response")
+	return "", Usage{}, fmt.Errorf("no text content in API response")
 }
 
 // Available returns true if the API key is present.
@@ -16,7 +16,8 @@ type Message struct {
 
 // ClientConfig configures an LLM client.
 type ClientConfig struct {
-	// Provider identifies the LLM backend: "anthropic", "openai", "ollama", "subagent"
+	// Provider identifies the LLM backend: "anthropic", "openai", "ollama",
+	// "openai-compatible", or "subagent".
 	Provider string `json:"provider" yaml:"provider"`
 
 	// APIKey is the API key for the provider (not used for subagent or ollama).
@@ -30,6 +31,18 @@ type ClientConfig struct {
 
 	// Timeout is the maximum duration to wait for a response.
 	Timeout time.Duration `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+
+	// MaxRetries is how many times a request failing with a network error,
+	// rate limit, or server error is retried. Zero disables retries.
+	MaxRetries int `json:"max_retries,omitempty" yaml:"max_retries,omitempty"`
+
+	// RetryBackoff is the delay before the first retry, doubled for each
+	// further one. Zero uses DefaultRetryBackoff.
+	RetryBackoff time.Duration `json:"retry_backoff,omitempty" yaml:"retry_backoff,omitempty"`
+
+	// Ledger, if set, records each request's usage and enforces its daily
+	// budget. Retried requests are recorded once per attempt.
+	Ledger *Ledger `json:"-" yaml:"-"`
 }
 
 // DefaultConfig returns a ClientConfig with sensible defaults.
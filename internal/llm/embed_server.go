@@ -0,0 +1,153 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/vecmath"
+)
+
+// BatchEmbedder is an optional interface for embedding backends that can
+// embed several texts in one request.
+type BatchEmbedder interface {
+	// EmbedBatch returns one embedding per text, in order.
+	EmbedBatch(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// ServerEmbedder embeds text through an OpenAI-compatible /embeddings
+// endpoint, such as llama.cpp's llama-server or Ollama, so embeddings can
+// be computed on the local machine without a cloud API.
+type ServerEmbedder struct {
+	baseURL string
+	model   string
+	apiKey  string
+	client  *http.Client
+}
+
+// NewServerEmbedder creates a ServerEmbedder for config.BaseURL, for
+// example "http://localhost:8080/v1". Model may be empty for servers that
+// serve a single model. If config.Timeout is zero, 60 seconds is used.
+func NewServerEmbedder(config ClientConfig) *ServerEmbedder {
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 60 * time.Second
+	}
+	return &ServerEmbedder{
+		baseURL: strings.TrimSuffix(config.BaseURL, "/"),
+		model:   config.Model,
+		apiKey:  config.APIKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+// openAIEmbeddingRequest represents a request to the OpenAI embeddings API.
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model,omitempty"`
+	Input []string `json:"input"`
+}
+
+// openAIEmbeddingResponse represents a response from the OpenAI embeddings API.
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// Available returns true if the embedder has an endpoint configured.
+func (e *ServerEmbedder) Available() bool {
+	return e != nil && e.baseURL != ""
+}
+
+// Embed returns the normalized embedding of text.
+func (e *ServerEmbedder) Embed(ctx context.Context, text string) ([]float32, error) {
+	vecs, err := e.EmbedBatch(ctx, []string{text})
+	if err != nil {
+		return nil, err
+	}
+	return vecs[0], nil
+}
+
+// EmbedBatch returns the normalized embeddings of texts in one request.
+func (e *ServerEmbedder) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	if !e.Available() {
+		return nil, fmt.Errorf("embedding server not configured: missing URL")
+	}
+	if len(texts) == 0 {
+		return nil, nil
+	}
+
+	jsonBody, err := json.Marshal(openAIEmbeddingRequest{Model: e.model, Input: texts})
+	if err != nil {
+		return nil, fmt.Errorf("marshaling request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", e.baseURL+"/embeddings", bytes.NewReader(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if e.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+e.apiKey)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var embResp openAIEmbeddingResponse
+	if err := json.Unmarshal(body, &embResp); err != nil {
+		return nil, fmt.Errorf("parsing API response: %w", err)
+	}
+	if embResp.Error != nil {
+		return nil, fmt.Errorf("API error: %s", embResp.Error.Message)
+	}
+	if len(embResp.Data) != len(texts) {
+		return nil, fmt.Errorf("API returned %d embeddings for %d inputs", len(embResp.Data), len(texts))
+	}
+
+	vecs := make([][]float32, len(texts))
+	for _, d := range embResp.Data {
+		if d.Index < 0 || d.Index >= len(texts) || len(d.Embedding) == 0 {
+			return nil, fmt.Errorf("API returned an invalid embedding at index %d", d.Index)
+		}
+		vecmath.Normalize(d.Embedding)
+		vecs[d.Index] = d.Embedding
+	}
+	for i, v := range vecs {
+		if v == nil {
+			return nil, fmt.Errorf("API returned no embedding for input %d", i)
+		}
+	}
+	return vecs, nil
+}
+
+// CompareEmbeddings embeds both texts in one request and returns their
+// cosine similarity.
+func (e *ServerEmbedder) CompareEmbeddings(ctx context.Context, a, b string) (float64, error) {
+	vecs, err := e.EmbedBatch(ctx, []string{a, b})
+	if err != nil {
+		return 0, err
+	}
+	return vecmath.CosineSimilarity(vecs[0], vecs[1]), nil
+}
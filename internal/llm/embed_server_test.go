@@ -0,0 +1,64 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestServerEmbedder(t *testing.T) {
+	var gotReq openAIEmbeddingRequest
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/embeddings" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&gotReq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		// Reply out of order to check results are placed by index
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,2]},{"index":0,"embedding":[3,4]}]}`))
+	}))
+	defer ts.Close()
+
+	e := NewServerEmbedder(ClientConfig{BaseURL: ts.URL + "/v1/", Model: "all-minilm"})
+	vecs, err := e.EmbedBatch(context.Background(), []string{"a", "b"})
+	if err != nil {
+		t.Fatalf("EmbedBatch: %v", err)
+	}
+	if gotReq.Model != "all-minilm" || len(gotReq.Input) != 2 {
+		t.Errorf("request = %+v", gotReq)
+	}
+	if len(vecs) != 2 || vecs[0][0] != 0.6 || vecs[0][1] != 0.8 || vecs[1][1] != 1 {
+		t.Errorf("EmbedBatch() = %v, want normalized vectors in input order", vecs)
+	}
+
+	sim, err := e.CompareEmbeddings(context.Background(), "a", "b")
+	if err != nil {
+		t.Fatalf("CompareEmbeddings: %v", err)
+	}
+	if math.Abs(sim-0.8) > 1e-6 {
+		t.Errorf("CompareEmbeddings() = %v, want 0.8", sim)
+	}
+}
+
+func TestServerEmbedder_Errors(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "model not loaded", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	var statusErr *StatusError
+	if _, err := NewServerEmbedder(ClientConfig{BaseURL: ts.URL}).Embed(context.Background(), "a"); !errors.As(err, &statusErr) {
+		t.Errorf("Embed() error = %v, want StatusError", err)
+	}
+	if e := NewServerEmbedder(ClientConfig{}); e.Available() {
+		t.Error("Available() = true without a URL")
+	}
+}
@@ -87,6 +87,10 @@ type openAIChatResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+	} `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
@@ -95,12 +99,19 @@ type openAIChatResponse struct {
 
 // Complete sends messages to the OpenAI API and returns the response text.
 func (c *OpenAIClient) Complete(ctx context.Context, messages []Message) (string, error) {
+	response, _, err := c.CompleteWithUsage(ctx, messages)
+	return response, err
+}
+
+// CompleteWithUsage is Complete that also reports the tokens the API
+// counted for the request.
+func (c *OpenAIClient) CompleteWithUsage(ctx context.Context, messages []Message) (string, Usage, error) {
 	if !c.Available() {
-		return "", fmt.Errorf("openai client not available: missing API key")
+		return "", Usage{}, fmt.Errorf("openai client not available: missing API key")
 	}
 
 	if len(messages) == 0 {
-		return "", fmt.Errorf("at least one message is required")
+		return "", Usage{}, fmt.Errorf("at least one message is required")
 	}
 
 	var apiMsgs []openAIChatMessage
@@ -118,13 +129,13 @@ func (c *OpenAIClient) Complete(ctx context.Context, messages []Message) (string
 
 	jsonBody, err := json.Marshal(reqBody)
 	if err != nil {
-		return "", fmt.Errorf("marshaling request: %w", err)
+		return "", Usage{}, fmt.Errorf("marshaling request: %w", err)
 	}
 
 	endpoint := c.baseURL + "/chat/completions"
 	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(jsonBody))
 	if err != nil {
-		return "", fmt.Errorf("creating request: %w", err)
+		return "", Usage{}, fmt.Errorf("creating request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -134,40 +145,42 @@ func (c *OpenAIClient) Complete(ctx context.Context, messages []Message) (string
 
 	resp, err := c.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("sending request: %w", err)
+		return "", Usage{}, fmt.Errorf("sending request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("reading response body: %w", err)
+		return "", Usage{}, fmt.Errorf("reading response body: %w", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+		return "", Usage{}, &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var chatResp openAIChatResponse
 	if err := json.Unmarshal(body, &chatResp); err != nil {
-		return "", fmt.Errorf("parsing API response: %w", err)
+		return "", Usage{}, fmt.Errorf("parsing API response: %w", err)
 	}
 
 	if chatResp.Error != nil {
-		return "", fmt.Errorf("API error: %s", chatResp.Error.Message)
+		return "", Usage{}, fmt.Errorf("API error: %s", chatResp.Error.Message)
 	}
 
 	if len(chatResp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in API response")
+		return "", Usage{}, fmt.Errorf("no choices in API response")
 	}
 
-	return chatResp.Choices[0].Message.Content, nil
+	usage := Usage{Model: c.model, InputTokens: chatResp.Usage.PromptTokens, OutputTokens: chatResp.Usage.CompletionTokens}
+	return chatResp.Choices[0].Message.Content, usage, nil
 }
 
 // Available returns true if the client is ready to make requests.
-// For OpenAI, this requires an API key. For Ollama, no key is needed.
+// For OpenAI, this requires an API key. Ollama and other OpenAI-compatible
+// endpoints may not need one.
 func (c *OpenAIClient) Available() bool {
-	if c.provider == "ollama" {
-		return true // Ollama doesn't require API key
+	if c.provider == ProviderOllama || c.provider == ProviderOpenAICompatible {
+		return true
 	}
 	return c.apiKey != ""
 }
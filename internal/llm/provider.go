@@ -0,0 +1,172 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nvandessel/floop/internal/tracing"
+)
+
+// Names of the built-in API providers.
+const (
+	ProviderAnthropic        = "anthropic"
+	ProviderOpenAI           = "openai"
+	ProviderOllama           = "ollama"
+	ProviderOpenAICompatible = "openai-compatible"
+)
+
+// ollamaDefaultEndpoint is Ollama's OpenAI-compatible API on its default port.
+const ollamaDefaultEndpoint = "http://localhost:11434/v1"
+
+// DefaultRetryBackoff is the delay before the first retry of a failed
+// request; it doubles with each further attempt.
+const DefaultRetryBackoff = time.Second
+
+// Provider builds clients for one LLM backend.
+type Provider interface {
+	// NewClient returns a client for the backend configured by cfg.
+	NewClient(cfg ClientConfig) (Client, error)
+}
+
+// ProviderFunc adapts an ordinary function to the Provider interface.
+type ProviderFunc func(cfg ClientConfig) (Client, error)
+
+// NewClient calls f(cfg).
+func (f ProviderFunc) NewClient(cfg ClientConfig) (Client, error) {
+	return f(cfg)
+}
+
+var (
+	providersMu sync.RWMutex
+	providers   = map[string]Provider{
+		ProviderAnthropic: ProviderFunc(func(cfg ClientConfig) (Client, error) {
+			return NewAnthropicClient(cfg), nil
+		}),
+		ProviderOpenAI: ProviderFunc(func(cfg ClientConfig) (Client, error) {
+			return NewOpenAIClient(cfg), nil
+		}),
+		ProviderOllama: ProviderFunc(func(cfg ClientConfig) (Client, error) {
+			if cfg.BaseURL == "" {
+				cfg.BaseURL = ollamaDefaultEndpoint
+			}
+			return NewOpenAIClient(cfg), nil
+		}),
+		ProviderOpenAICompatible: ProviderFunc(func(cfg ClientConfig) (Client, error) {
+			if cfg.BaseURL == "" {
+				return nil, fmt.Errorf("%s provider requires a base URL", ProviderOpenAICompatible)
+			}
+			return NewOpenAIClient(cfg), nil
+		}),
+	}
+)
+
+// RegisterProvider makes a backend available to NewClient under name,
+// replacing any provider already registered under it.
+func RegisterProvider(name string, p Provider) {
+	providersMu.Lock()
+	defer providersMu.Unlock()
+	providers[name] = p
+}
+
+// Providers returns the names of the registered providers, sorted.
+func Providers() []string {
+	providersMu.RLock()
+	defer providersMu.RUnlock()
+	return slices.Sorted(maps.Keys(providers))
+}
+
+// NewClient returns a client for cfg.Provider. With cfg.Ledger set, usage is
+// recorded and budgeted; with cfg.MaxRetries set, failed requests that may
+// succeed on a second try are retried. When tracing is enabled, each
+// request is traced.
+func NewClient(cfg ClientConfig) (Client, error) {
+	providersMu.RLock()
+	p, ok := providers[cfg.Provider]
+	providersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown LLM provider %q (available: %s)", cfg.Provider, strings.Join(Providers(), ", "))
+	}
+
+	client, err := p.NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+	if tracing.Enabled() {
+		client = WithTracing(client, cfg.Provider, cfg.Model)
+	}
+	if cfg.Ledger != nil {
+		client = WithLedger(client, cfg.Ledger)
+	}
+	if cfg.MaxRetries > 0 {
+		client = WithRetry(client, cfg.MaxRetries, cfg.RetryBackoff)
+	}
+	return client, nil
+}
+
+// StatusError is returned when an LLM API responds with a non-success HTTP
+// status.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("API returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// retryClient retries completions that failed transiently.
+type retryClient struct {
+	Client
+	maxRetries int
+	backoff    time.Duration
+}
+
+// WithRetry wraps c so that completions failing with a network error, a rate
+// limit (429), or a server error (5xx) are retried up to maxRetries times,
+// waiting backoff before the first retry and twice as long before each
+// further one. A non-positive backoff uses DefaultRetryBackoff.
+func WithRetry(c Client, maxRetries int, backoff time.Duration) Client {
+	if backoff <= 0 {
+		backoff = DefaultRetryBackoff
+	}
+	return &retryClient{Client: c, maxRetries: maxRetries, backoff: backoff}
+}
+
+// Complete calls the wrapped client, retrying transient failures.
+func (r *retryClient) Complete(ctx context.Context, messages []Message) (string, error) {
+	delay := r.backoff
+	for attempt := 0; ; attempt++ {
+		response, err := r.Client.Complete(ctx, messages)
+		if err == nil || attempt >= r.maxRetries || !retryable(err) {
+			return response, err
+		}
+		select {
+		case <-ctx.Done():
+			return "", err
+		case <-time.After(delay):
+		}
+		delay *= 2
+	}
+}
+
+// retryable reports whether a request that failed with err may succeed if
+// sent again.
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.StatusCode == http.StatusTooManyRequests || statusErr.StatusCode >= 500
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
@@ -0,0 +1,152 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestProviders(t *testing.T) {
+	got := Providers()
+	for _, want := range []string{ProviderAnthropic, ProviderOllama, ProviderOpenAI, ProviderOpenAICompatible} {
+		if !slices.Contains(got, want) {
+			t.Errorf("Providers() = %v, missing %q", got, want)
+		}
+	}
+	if !slices.IsSorted(got) {
+		t.Errorf("Providers() = %v, want sorted", got)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	tests := []struct {
+		name      string
+		cfg       ClientConfig
+		wantErr   bool
+		available bool
+	}{
+		{"anthropic", ClientConfig{Provider: ProviderAnthropic, APIKey: "k"}, false, true},
+		{"openai without key", ClientConfig{Provider: ProviderOpenAI}, false, false},
+		{"ollama default endpoint", ClientConfig{Provider: ProviderOllama}, false, true},
+		{"openai-compatible", ClientConfig{Provider: ProviderOpenAICompatible, BaseURL: "http://localhost:8000/v1"}, false, true},
+		{"openai-compatible without base URL", ClientConfig{Provider: ProviderOpenAICompatible}, true, false},
+		{"unknown", ClientConfig{Provider: "nope"}, true, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, err := NewClient(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewClient() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && client.Available() != tt.available {
+				t.Errorf("Available() = %v, want %v", client.Available(), tt.available)
+			}
+		})
+	}
+
+	client, _ := NewClient(ClientConfig{Provider: ProviderOllama})
+	if got := client.(*OpenAIClient).baseURL; got != ollamaDefaultEndpoint {
+		t.Errorf("ollama baseURL = %q, want %q", got, ollamaDefaultEndpoint)
+	}
+	client, _ = NewClient(ClientConfig{Provider: ProviderOpenAI, APIKey: "k", MaxRetries: 2})
+	if _, ok := client.(*retryClient); !ok {
+		t.Errorf("NewClient with MaxRetries returned %T, want retrying client", client)
+	}
+}
+
+func TestRegisterProvider(t *testing.T) {
+	mock := NewMockClient()
+	RegisterProvider("test-mock", ProviderFunc(func(cfg ClientConfig) (Client, error) {
+		return mock, nil
+	}))
+	defer func() {
+		providersMu.Lock()
+		delete(providers, "test-mock")
+		providersMu.Unlock()
+	}()
+
+	client, err := NewClient(ClientConfig{Provider: "test-mock"})
+	if err != nil {
+		t.Fatalf("NewClient() error: %v", err)
+	}
+	if client != Client(mock) {
+		t.Errorf("NewClient() = %v, want registered mock", client)
+	}
+}
+
+func TestWithRetry(t *testing.T) {
+	tests := []struct {
+		name      string
+		status    int
+		failures  int32
+		wantCalls int32
+		wantErr   bool
+	}{
+		{"recovers from server error", http.StatusServiceUnavailable, 2, 3, false},
+		{"recovers from rate limit", http.StatusTooManyRequests, 1, 2, false},
+		{"gives up after max retries", http.StatusInternalServerError, 5, 3, true},
+		{"does not retry client error", http.StatusBadRequest, 1, 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls atomic.Int32
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if calls.Add(1) <= tt.failures {
+					http.Error(w, "try again", tt.status)
+					return
+				}
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprint(w, `{"choices":[{"message":{"content":"ok"}}]}`)
+			}))
+			defer ts.Close()
+
+			client, err := NewClient(ClientConfig{
+				Provider:     ProviderOpenAICompatible,
+				BaseURL:      ts.URL,
+				MaxRetries:   2,
+				RetryBackoff: time.Millisecond,
+			})
+			if err != nil {
+				t.Fatalf("NewClient() error: %v", err)
+			}
+			resp, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "hi"}})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Complete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && resp != "ok" {
+				t.Errorf("Complete() = %q, want ok", resp)
+			}
+			var statusErr *StatusError
+			if err != nil && (!errors.As(err, &statusErr) || statusErr.StatusCode != tt.status) {
+				t.Errorf("Complete() error = %v, want StatusError %d", err, tt.status)
+			}
+			if got := calls.Load(); got != tt.wantCalls {
+				t.Errorf("server called %d times, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestWithRetry_ContextCancelled(t *testing.T) {
+	mock := NewMockClient().WithError(&StatusError{StatusCode: http.StatusBadGateway})
+	client := WithRetry(mock, 5, time.Hour)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := client.Complete(ctx, nil); err == nil {
+		t.Fatal("expected error")
+	}
+	if time.Since(start) > 5*time.Second {
+		t.Error("retry ignored context cancellation")
+	}
+	if got := mock.CompleteCallCount(); got != 1 {
+		t.Errorf("Complete called %d times, want 1", got)
+	}
+}
@@ -0,0 +1,54 @@
+package llm
+
+import (
+	"context"
+
+	"github.com/nvandessel/floop/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// tracingClient records a span for every completion.
+type tracingClient struct {
+	Client
+	provider string
+	model    string
+}
+
+// WithTracing wraps c so that each completion is recorded in an
+// llm.Complete span tagged with the provider, model, and purpose, and with
+// token counts when the API reports them.
+func WithTracing(c Client, provider, model string) Client {
+	return &tracingClient{Client: c, provider: provider, model: model}
+}
+
+// Complete calls the wrapped client in a span.
+func (c *tracingClient) Complete(ctx context.Context, messages []Message) (string, error) {
+	response, _, err := c.CompleteWithUsage(ctx, messages)
+	return response, err
+}
+
+// CompleteWithUsage calls the wrapped client in a span, passing on the
+// usage it reports so a ledger wrapping this client still records it.
+func (c *tracingClient) CompleteWithUsage(ctx context.Context, messages []Message) (response string, usage Usage, err error) {
+	ctx, span := tracing.Start(ctx, "llm.Complete",
+		attribute.String("gen_ai.system", c.provider),
+		attribute.String("gen_ai.request.model", c.model),
+		attribute.String("floop.llm.purpose", PurposeFrom(ctx)),
+		attribute.Int("floop.llm.messages", len(messages)),
+	)
+	defer func() {
+		if usage.InputTokens > 0 || usage.OutputTokens > 0 {
+			span.SetAttributes(
+				attribute.Int("gen_ai.usage.input_tokens", usage.InputTokens),
+				attribute.Int("gen_ai.usage.output_tokens", usage.OutputTokens),
+			)
+		}
+		tracing.End(span, err)
+	}()
+
+	if uc, ok := c.Client.(UsageCompleter); ok {
+		return uc.CompleteWithUsage(ctx, messages)
+	}
+	response, err = c.Client.Complete(ctx, messages)
+	return response, Usage{}, err
+}
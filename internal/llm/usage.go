@@ -0,0 +1,306 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UsageFile is the name of the usage ledger in a .floop directory.
+const UsageFile = "llm-usage.json"
+
+// UsageRetentionDays is how many days of usage the ledger keeps.
+const UsageRetentionDays = 90
+
+// Purposes recorded in the usage ledger. Calls made without one are
+// recorded as PurposeOther.
+const (
+	PurposeCompare = "compare"
+	PurposeMerge   = "merge"
+	PurposeExtract = "extract"
+	PurposeOther   = "other"
+)
+
+// ErrBudgetExceeded is returned instead of making a call once the day's
+// LLM budget is spent. Callers fall back as they would for any LLM failure.
+var ErrBudgetExceeded = errors.New("daily LLM budget exceeded")
+
+// Usage is the token count of one completion.
+type Usage struct {
+	Model        string
+	InputTokens  int
+	OutputTokens int
+}
+
+// UsageCompleter is implemented by clients whose API reports token counts.
+type UsageCompleter interface {
+	CompleteWithUsage(ctx context.Context, messages []Message) (string, Usage, error)
+}
+
+type purposeKey struct{}
+
+// WithPurpose returns a context whose LLM calls are recorded under purpose.
+func WithPurpose(ctx context.Context, purpose string) context.Context {
+	return context.WithValue(ctx, purposeKey{}, purpose)
+}
+
+// PurposeFrom returns the purpose set by WithPurpose, or PurposeOther.
+func PurposeFrom(ctx context.Context) string {
+	if p, ok := ctx.Value(purposeKey{}).(string); ok && p != "" {
+		return p
+	}
+	return PurposeOther
+}
+
+// EstimateTokens approximates the token count of text at four characters
+// per token, for APIs that do not report usage.
+func EstimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// modelPrices are USD per million input and output tokens, matched by the
+// longest model name prefix. Models not listed, such as local Ollama
+// models, are treated as free.
+var modelPrices = map[string][2]float64{
+	"claude-3-haiku":    {0.25, 1.25},
+	"claude-3-5-haiku":  {0.80, 4},
+	"claude-haiku-4":    {1, 5},
+	"claude-3-5-sonnet": {3, 15},
+	"claude-3-7-sonnet": {3, 15},
+	"claude-sonnet-4":   {3, 15},
+	"claude-opus-4":     {15, 75},
+	"claude-opus-4-5":   {5, 25},
+	"gpt-4o":            {2.50, 10},
+	"gpt-4o-mini":       {0.15, 0.60},
+	"gpt-4.1":           {2, 8},
+	"gpt-4.1-mini":      {0.40, 1.60},
+	"gpt-4.1-nano":      {0.10, 0.40},
+}
+
+// EstimateCost returns the approximate USD cost of u at list prices.
+func EstimateCost(u Usage) float64 {
+	var match string
+	for prefix := range modelPrices {
+		if strings.HasPrefix(u.Model, prefix) && len(prefix) > len(match) {
+			match = prefix
+		}
+	}
+	if match == "" {
+		return 0
+	}
+	price := modelPrices[match]
+	return (float64(u.InputTokens)*price[0] + float64(u.OutputTokens)*price[1]) / 1e6
+}
+
+// UsageTotals sums the LLM calls recorded for a period.
+type UsageTotals struct {
+	Calls        int     `json:"calls"`
+	InputTokens  int     `json:"input_tokens"`
+	OutputTokens int     `json:"output_tokens"`
+	CostUSD      float64 `json:"cost_usd"`
+}
+
+func (t *UsageTotals) add(u Usage, cost float64) {
+	t.Calls++
+	t.InputTokens += u.InputTokens
+	t.OutputTokens += u.OutputTokens
+	t.CostUSD += cost
+}
+
+// DayUsage is the usage recorded on one day, overall and per purpose.
+type DayUsage struct {
+	UsageTotals
+	ByPurpose map[string]UsageTotals `json:"by_purpose,omitempty"`
+}
+
+// Budget limits LLM use per calendar day. Zero fields are unlimited.
+type Budget struct {
+	DailyCostUSD float64 `json:"daily_cost_usd,omitempty"`
+	DailyCalls   int     `json:"daily_calls,omitempty"`
+}
+
+// Ledger records LLM usage per day in a JSON file and enforces a Budget.
+// Processes sharing the file may occasionally lose a concurrent update, so
+// totals are approximate.
+type Ledger struct {
+	path   string
+	budget Budget
+	now    func() time.Time
+
+	mu sync.Mutex
+}
+
+// ledgerFile is the on-disk form of a Ledger, keyed by local date.
+type ledgerFile struct {
+	Days map[string]DayUsage `json:"days"`
+}
+
+// NewLedger returns the ledger stored at path, typically UsageFile in the
+// global .floop directory. The file is created on the first recorded call.
+func NewLedger(path string, budget Budget) *Ledger {
+	return &Ledger{path: path, budget: budget, now: time.Now}
+}
+
+// Path returns the ledger's file path.
+func (l *Ledger) Path() string { return l.path }
+
+// Budget returns the ledger's daily budget.
+func (l *Ledger) Budget() Budget { return l.budget }
+
+// Days returns the recorded usage keyed by date (YYYY-MM-DD).
+func (l *Ledger) Days() (map[string]DayUsage, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := l.load()
+	if err != nil {
+		return nil, err
+	}
+	return f.Days, nil
+}
+
+// Today returns the usage recorded today.
+func (l *Ledger) Today() (DayUsage, error) {
+	days, err := l.Days()
+	if err != nil {
+		return DayUsage{}, err
+	}
+	return days[l.today()], nil
+}
+
+// Check returns an error wrapping ErrBudgetExceeded if today's usage has
+// reached the budget.
+func (l *Ledger) Check() error {
+	if l.budget == (Budget{}) {
+		return nil
+	}
+	today, err := l.Today()
+	if err != nil {
+		return err
+	}
+	if l.budget.DailyCostUSD > 0 && today.CostUSD >= l.budget.DailyCostUSD {
+		return fmt.Errorf("%w: $%.4f of $%.2f spent today", ErrBudgetExceeded, today.CostUSD, l.budget.DailyCostUSD)
+	}
+	if l.budget.DailyCalls > 0 && today.Calls >= l.budget.DailyCalls {
+		return fmt.Errorf("%w: %d of %d calls made today", ErrBudgetExceeded, today.Calls, l.budget.DailyCalls)
+	}
+	return nil
+}
+
+// Record adds one call made for purpose to today's totals, dropping days
+// older than UsageRetentionDays.
+func (l *Ledger) Record(purpose string, u Usage) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	f, err := l.load()
+	if err != nil {
+		return err
+	}
+
+	cost := EstimateCost(u)
+	day := f.Days[l.today()]
+	day.add(u, cost)
+	if day.ByPurpose == nil {
+		day.ByPurpose = make(map[string]UsageTotals)
+	}
+	p := day.ByPurpose[purpose]
+	p.add(u, cost)
+	day.ByPurpose[purpose] = p
+	f.Days[l.today()] = day
+
+	cutoff := l.now().AddDate(0, 0, -UsageRetentionDays).Format(time.DateOnly)
+	for date := range f.Days {
+		if date < cutoff {
+			delete(f.Days, date)
+		}
+	}
+	return l.save(f)
+}
+
+func (l *Ledger) today() string {
+	return l.now().Format(time.DateOnly)
+}
+
+func (l *Ledger) load() (*ledgerFile, error) {
+	f := &ledgerFile{Days: make(map[string]DayUsage)}
+	data, err := os.ReadFile(l.path)
+	if os.IsNotExist(err) {
+		return f, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading usage ledger: %w", err)
+	}
+	if err := json.Unmarshal(data, f); err != nil {
+		return nil, fmt.Errorf("parsing usage ledger: %w", err)
+	}
+	if f.Days == nil {
+		f.Days = make(map[string]DayUsage)
+	}
+	return f, nil
+}
+
+// save writes then renames, so concurrent readers never see a partial file.
+func (l *Ledger) save(f *ledgerFile) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encoding usage ledger: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(l.path), 0700); err != nil {
+		return fmt.Errorf("writing usage ledger: %w", err)
+	}
+	tmp := l.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("writing usage ledger: %w", err)
+	}
+	if err := os.Rename(tmp, l.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("writing usage ledger: %w", err)
+	}
+	return nil
+}
+
+// ledgerClient records every completion in a Ledger.
+type ledgerClient struct {
+	Client
+	ledger *Ledger
+}
+
+// WithLedger wraps c so that each completion is recorded in ledger under
+// the purpose set with WithPurpose, and refused with ErrBudgetExceeded once
+// the day's budget is spent. Token counts the API does not report are
+// estimated from the text.
+func WithLedger(c Client, ledger *Ledger) Client {
+	return &ledgerClient{Client: c, ledger: ledger}
+}
+
+// Complete checks the budget, calls the wrapped client, and records the call.
+func (c *ledgerClient) Complete(ctx context.Context, messages []Message) (string, error) {
+	if err := c.ledger.Check(); err != nil {
+		return "", err
+	}
+
+	var response string
+	var usage Usage
+	var err error
+	if uc, ok := c.Client.(UsageCompleter); ok {
+		response, usage, err = uc.CompleteWithUsage(ctx, messages)
+	} else {
+		response, err = c.Client.Complete(ctx, messages)
+	}
+	if err == nil && usage.InputTokens == 0 && usage.OutputTokens == 0 {
+		for _, m := range messages {
+			usage.InputTokens += EstimateTokens(m.Content)
+		}
+		usage.OutputTokens = EstimateTokens(response)
+	}
+
+	// A failed call still counts against the call budget. Ledger write
+	// errors are ignored: losing a record must not fail the completion.
+	_ = c.ledger.Record(PurposeFrom(ctx), usage)
+	return response, err
+}
@@ -0,0 +1,123 @@
+package llm
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestEstimateCost(t *testing.T) {
+	tests := []struct {
+		model string
+		want  float64
+	}{
+		{"claude-3-haiku-20240307", 0.25 + 1.25},
+		{"gpt-4o-mini", 0.15 + 0.60},
+		{"gpt-4o", 2.50 + 10},
+		{"claude-opus-4-5-20251101", 5 + 25},
+		{"llama3.2", 0},
+	}
+	for _, tt := range tests {
+		got := EstimateCost(Usage{Model: tt.model, InputTokens: 1_000_000, OutputTokens: 1_000_000})
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("EstimateCost(%s) = %v, want %v", tt.model, got, tt.want)
+		}
+	}
+}
+
+func TestLedger(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.Local)
+	l := NewLedger(filepath.Join(t.TempDir(), UsageFile), Budget{DailyCalls: 2})
+	l.now = func() time.Time { return now }
+
+	if err := l.Check(); err != nil {
+		t.Fatalf("Check() on empty ledger: %v", err)
+	}
+	if err := l.Record(PurposeMerge, Usage{Model: "gpt-4o-mini", InputTokens: 100, OutputTokens: 10}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	if err := l.Record(PurposeCompare, Usage{Model: "gpt-4o-mini", InputTokens: 50, OutputTokens: 5}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	// A fresh ledger on the same file sees the persisted totals
+	reopened := NewLedger(l.Path(), l.Budget())
+	reopened.now = l.now
+	today, err := reopened.Today()
+	if err != nil {
+		t.Fatalf("Today: %v", err)
+	}
+	if today.Calls != 2 || today.InputTokens != 150 || today.OutputTokens != 15 || today.CostUSD <= 0 {
+		t.Errorf("Today() = %+v", today.UsageTotals)
+	}
+	if today.ByPurpose[PurposeMerge].Calls != 1 || today.ByPurpose[PurposeCompare].InputTokens != 50 {
+		t.Errorf("ByPurpose = %+v", today.ByPurpose)
+	}
+	if err := reopened.Check(); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("Check() = %v, want ErrBudgetExceeded", err)
+	}
+
+	// The budget resets the next day, and old days are pruned
+	now = now.AddDate(0, 0, 1)
+	if err := l.Check(); err != nil {
+		t.Errorf("Check() next day: %v", err)
+	}
+	now = now.AddDate(0, 0, UsageRetentionDays+1)
+	if err := l.Record(PurposeOther, Usage{}); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+	days, _ := l.Days()
+	if len(days) != 1 {
+		t.Errorf("ledger kept %d days, want 1", len(days))
+	}
+}
+
+func TestWithLedger(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"choices":[{"message":{"content":"ok"}}],"usage":{"prompt_tokens":1000,"completion_tokens":200}}`)
+	}))
+	defer ts.Close()
+
+	ledger := NewLedger(filepath.Join(t.TempDir(), UsageFile), Budget{DailyCostUSD: 0.0003})
+	client, err := NewClient(ClientConfig{Provider: ProviderOpenAI, APIKey: "k", BaseURL: ts.URL, Model: "gpt-4o-mini", Ledger: ledger})
+	if err != nil {
+		t.Fatalf("NewClient: %v", err)
+	}
+
+	ctx := WithPurpose(context.Background(), PurposeExtract)
+	if _, err := client.Complete(ctx, []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("first Complete: %v", err)
+	}
+	today, _ := ledger.Today()
+	if got := today.ByPurpose[PurposeExtract]; got.InputTokens != 1000 || got.OutputTokens != 200 {
+		t.Errorf("recorded %+v, want API-reported usage", got)
+	}
+
+	// 1000*0.15/1e6 + 200*0.60/1e6 = $0.00027; the second call crosses the budget
+	if _, err := client.Complete(ctx, []Message{{Role: "user", Content: "hi"}}); err != nil {
+		t.Fatalf("second Complete: %v", err)
+	}
+	if _, err := client.Complete(ctx, []Message{{Role: "user", Content: "hi"}}); !errors.Is(err, ErrBudgetExceeded) {
+		t.Errorf("third Complete error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestWithLedger_EstimatesUsage(t *testing.T) {
+	ledger := NewLedger(filepath.Join(t.TempDir(), UsageFile), Budget{})
+	client := WithLedger(NewMockClient().WithCompleteResponse("12345678"), ledger)
+
+	if _, err := client.Complete(context.Background(), []Message{{Role: "user", Content: "1234"}}); err != nil {
+		t.Fatalf("Complete: %v", err)
+	}
+	today, _ := ledger.Today()
+	if got := today.ByPurpose[PurposeOther]; got.Calls != 1 || got.InputTokens != 1 || got.OutputTokens != 2 {
+		t.Errorf("recorded %+v, want estimated 1 in / 2 out", got)
+	}
+}
@@ -0,0 +1,128 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// NewCLILogger creates the logger for command diagnostics, written to w
+// (stderr) so they never mix with a command's payload on stdout. Records
+// below level are dropped. Text records read "warning: <msg> key=value";
+// with jsonOut each record is a JSON object on its own line.
+func NewCLILogger(w io.Writer, level slog.Level, jsonOut bool) *slog.Logger {
+	if jsonOut {
+		return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{
+			Level: level,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				if a.Key == slog.LevelKey {
+					a.Value = slog.StringValue(levelName(a.Value.Any().(slog.Level)))
+				}
+				return a
+			},
+		}))
+	}
+	return slog.New(&cliHandler{w: w, mu: &sync.Mutex{}, level: level})
+}
+
+// levelName returns the lowercase name used for level in CLI output.
+func levelName(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return "error"
+	case level >= slog.LevelWarn:
+		return "warning"
+	case level >= slog.LevelInfo:
+		return "info"
+	case level >= slog.LevelDebug:
+		return "debug"
+	default:
+		return "trace"
+	}
+}
+
+// cliHandler formats records as single human-readable lines. Info records
+// carry no prefix since they read as ordinary progress notes.
+type cliHandler struct {
+	w      io.Writer
+	mu     *sync.Mutex
+	level  slog.Level
+	attrs  []slog.Attr
+	prefix string // group prefix for attribute keys
+}
+
+// Enabled implements slog.Handler.
+func (h *cliHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level
+}
+
+// Handle implements slog.Handler.
+func (h *cliHandler) Handle(_ context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	if r.Level != slog.LevelInfo {
+		buf.WriteString(levelName(r.Level))
+		buf.WriteString(": ")
+	}
+	buf.WriteString(r.Message)
+	for _, a := range h.attrs {
+		writeAttr(&buf, "", a)
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		writeAttr(&buf, h.prefix, a)
+		return true
+	})
+	buf.WriteByte('\n')
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := h.w.Write(buf.Bytes())
+	return err
+}
+
+// WithAttrs implements slog.Handler.
+func (h *cliHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.attrs = append([]slog.Attr{}, h.attrs...)
+	for _, a := range attrs {
+		a.Key = h.prefix + a.Key
+		h2.attrs = append(h2.attrs, a)
+	}
+	return &h2
+}
+
+// WithGroup implements slog.Handler.
+func (h *cliHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+	return &h2
+}
+
+// writeAttr appends " key=value", quoting values that contain spaces.
+func writeAttr(buf *bytes.Buffer, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		if a.Key != "" {
+			prefix += a.Key + "."
+		}
+		for _, ga := range a.Value.Group() {
+			writeAttr(buf, prefix, ga)
+		}
+		return
+	}
+	value := fmt.Sprint(a.Value.Any())
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(buf, " %s%s=%s", prefix, a.Key, value)
+}
@@ -2,7 +2,9 @@ package logging
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"os"
@@ -310,3 +312,43 @@ func TestDecisionLogger_FilePermissions(t *testing.T) {
 		t.Errorf("file permissions = %o, want 0600", perm)
 	}
 }
+
+func TestNewCLILogger_Text(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewCLILogger(&buf, slog.LevelInfo, false)
+
+	logger.Debug("dropped")
+	logger.Info("writing a full backup", "dir", "/tmp/backups")
+	logger.Warn("failed to add edge", "source", "b-1", "target", "b-2", "error", errors.New("not found"))
+	logger.With("pack", "acme/go").WithGroup("edge").Error("install failed", "kind", "similar-to")
+
+	want := "writing a full backup dir=/tmp/backups\n" +
+		"warning: failed to add edge source=b-1 target=b-2 error=\"not found\"\n" +
+		"error: install failed pack=acme/go edge.kind=similar-to\n"
+	if got := buf.String(); got != want {
+		t.Errorf("output =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestNewCLILogger_JSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := NewCLILogger(&buf, LevelTrace, true)
+
+	logger.Log(context.Background(), LevelTrace, "prompt", "tokens", 12)
+	logger.Warn("large comparison set", "pairs", 10002)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("got %d lines, want 2: %q", len(lines), buf.String())
+	}
+	wantLevels := []string{"trace", "warning"}
+	for i, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d is not JSON: %v", i, err)
+		}
+		if record["level"] != wantLevels[i] {
+			t.Errorf("line %d level = %v, want %s", i, record["level"], wantLevels[i])
+		}
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"path/filepath"
 	"sync"
 	"time"
+
+	"github.com/nvandessel/floop/internal/metrics"
 )
 
 // AuditEntry represents a single audit log entry for an MCP tool invocation.
@@ -170,6 +172,7 @@ func sanitizeToolParams(toolName string, params map[string]interface{}) map[stri
 		"corrections":   true,
 		"signal":        true,
 		"language":      true,
+		"limit":         true,
 	}
 
 	// Parameters whose existence is safe to log but whose values may contain
@@ -186,6 +189,8 @@ func sanitizeToolParams(toolName string, params map[string]interface{}) map[stri
 		"weight":      true,
 		"auto_merge":  true,
 		"behavior_id": true,
+		"cursor":      true,
+		"query":       true,
 	}
 
 	for key, val := range params {
@@ -227,4 +232,11 @@ func (s *Server) auditTool(toolName string, start time.Time, err error, params m
 		Error:      errMsg,
 		Params:     params,
 	})
+
+	s.metrics().Record(metrics.Event{
+		Kind:       metrics.KindTool,
+		Name:       toolName,
+		DurationMS: metrics.Since(start),
+		Error:      err != nil,
+	})
 }
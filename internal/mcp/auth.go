@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// TokenEnv names the environment variable holding the bearer token that
+// network clients must present. Without a token the HTTP and gRPC
+// transports only listen on loopback.
+const TokenEnv = "FLOOP_SERVER_TOKEN"
+
+// checkListenAddr refuses a TCP listen address that is not loopback unless
+// a token is configured. An empty host (":7345") listens on every interface.
+func checkListenAddr(addr, token string) error {
+	if token != "" {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return fmt.Errorf("invalid listen address %q: %w", addr, err)
+	}
+	if !isLoopbackHost(host) {
+		return fmt.Errorf("refusing to listen on non-loopback address %s without a token; set %s", addr, TokenEnv)
+	}
+	return nil
+}
+
+// isLoopbackHost reports whether host, without a port, names the loopback
+// interface.
+func isLoopbackHost(host string) bool {
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "["), "]")
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// tokenMatches reports whether the Authorization value carries want as a
+// bearer token, comparing in constant time.
+func tokenMatches(authorization, want string) bool {
+	got, ok := strings.CutPrefix(authorization, "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(got), []byte(want)) == 1
+}
+
+// requireAuth wraps the HTTP handler with the checks that keep browsers and
+// network peers away from the tools. With a token every request must carry
+// it. Without one the Host header must name loopback, which defeats DNS
+// rebinding. A browser Origin must be loopback or the requested host.
+func (s *Server) requireAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if s.token != "" {
+			if !tokenMatches(r.Header.Get("Authorization"), s.token) {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "unauthorized", http.StatusUnauthorized)
+				return
+			}
+		} else if !isLoopbackHost(hostOnly(r.Host)) {
+			http.Error(w, "forbidden host", http.StatusForbidden)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			u, err := url.Parse(origin)
+			if err != nil || (!isLoopbackHost(u.Hostname()) && u.Host != r.Host) {
+				http.Error(w, "forbidden origin", http.StatusForbidden)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// hostOnly strips the port from a Host header value.
+func hostOnly(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return hostport
+}
@@ -0,0 +1,71 @@
+package mcp
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckListenAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		token   string
+		wantErr bool
+	}{
+		{"127.0.0.1:7345", "", false},
+		{"localhost:7345", "", false},
+		{"[::1]:7345", "", false},
+		{":7345", "", true},
+		{"0.0.0.0:7345", "", true},
+		{"192.168.1.10:7345", "", true},
+		{"0.0.0.0:7345", "secret", false},
+		{"no-port", "", true},
+	}
+	for _, tt := range tests {
+		err := checkListenAddr(tt.addr, tt.token)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkListenAddr(%q, %q) = %v, wantErr %v", tt.addr, tt.token, err, tt.wantErr)
+		}
+	}
+}
+
+func TestRequireAuth(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+
+	tests := []struct {
+		name   string
+		token  string
+		host   string
+		origin string
+		auth   string
+		want   int
+	}{
+		{"loopback host", "", "127.0.0.1:7345", "", "", http.StatusOK},
+		{"localhost host", "", "localhost:7345", "", "", http.StatusOK},
+		{"rebound host", "", "attacker.example.com:7345", "", "", http.StatusForbidden},
+		{"foreign origin", "", "127.0.0.1:7345", "http://attacker.example.com", "", http.StatusForbidden},
+		{"loopback origin", "", "127.0.0.1:7345", "http://localhost:3000", "", http.StatusOK},
+		{"missing token", "secret", "floop.internal:7345", "", "", http.StatusUnauthorized},
+		{"wrong token", "secret", "floop.internal:7345", "", "Bearer nope", http.StatusUnauthorized},
+		{"token", "secret", "floop.internal:7345", "", "Bearer secret", http.StatusOK},
+		{"token with foreign origin", "secret", "floop.internal:7345", "http://attacker.example.com", "Bearer secret", http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &Server{token: tt.token}
+			req := httptest.NewRequest(http.MethodPost, "/", nil)
+			req.Host = tt.host
+			if tt.origin != "" {
+				req.Header.Set("Origin", tt.origin)
+			}
+			if tt.auth != "" {
+				req.Header.Set("Authorization", tt.auth)
+			}
+			rec := httptest.NewRecorder()
+			s.requireAuth(ok).ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
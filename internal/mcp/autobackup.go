@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+)
+
+// autoBackupCheckInterval is how often the server checks the automatic
+// backup schedule between learns, so daily backups still run in sessions
+// that span midnight.
+const autoBackupCheckInterval = time.Hour
+
+// autoBackupLoop runs automatic backups on the backup.schedule config for
+// as long as the server runs: once at startup (the first open of the day),
+// after each learn, and every autoBackupCheckInterval.
+func (s *Server) autoBackupLoop(ctx context.Context) {
+	ticker := time.NewTicker(autoBackupCheckInterval)
+	defer ticker.Stop()
+	for {
+		s.runAutoBackup()
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.done:
+			return
+		case <-s.autoBackupWake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// runAutoBackup backs up the store in the background if one is due.
+func (s *Server) runAutoBackup() {
+	cfg, retentionPolicy := s.backupSettings()
+	if cfg == nil || !cfg.AutoBackup {
+		return
+	}
+	dir, err := backup.DefaultBackupDir()
+	if err != nil {
+		s.logger.Warn("auto-backup failed (dir)", "error", err)
+		return
+	}
+	opts := backup.AutoOptions{
+		Schedule: backup.Schedule{
+			EveryLearns: cfg.Schedule.EveryLearns,
+			Daily:       cfg.Schedule.Daily,
+		},
+		Keep:      cfg.Retention.MaxCount,
+		Retention: retentionPolicy,
+		Backup: backup.BackupOptions{
+			Compress:     cfg.Compression,
+			FloopVersion: s.floopVersion,
+		},
+	}
+	s.runBackground("auto-backup", func() {
+		path, err := backup.RunAuto(context.Background(), s.store, dir, opts, time.Now())
+		if err != nil {
+			s.logger.Warn("auto-backup failed", "error", err)
+			return
+		}
+		if path != "" {
+			s.logger.Info("auto-backup complete", "path", path)
+		}
+	})
+}
+
+// recordAutoBackupLearn counts a learn toward the automatic backup schedule
+// and wakes autoBackupLoop to check it.
+func (s *Server) recordAutoBackupLearn() {
+	cfg, _ := s.backupSettings()
+	if cfg == nil || !cfg.AutoBackup {
+		return
+	}
+	s.runBackground("auto-backup-count", func() {
+		dir, err := backup.DefaultBackupDir()
+		if err != nil {
+			s.logger.Warn("auto-backup failed (dir)", "error", err)
+			return
+		}
+		if err := backup.RecordLearn(context.Background(), dir); err != nil {
+			s.logger.Warn("auto-backup failed to count learn", "error", err)
+			return
+		}
+		select {
+		case s.autoBackupWake <- struct{}{}:
+		default:
+		}
+	})
+}
@@ -0,0 +1,466 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	floopv1 "github.com/nvandessel/floop/api/floop/v1"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/similarity"
+	"github.com/nvandessel/floop/internal/store"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// grpcWatchInterval is how often WatchActive streams re-run activation to
+// pick up changes made outside this server, such as 'floop learn' from the
+// CLI. Changes made through this server are streamed immediately.
+const grpcWatchInterval = 5 * time.Second
+
+// defaultSearchLimit is the Search result limit when the request sets none.
+const defaultSearchLimit = 10
+
+// grpcService implements floopv1.FloopServiceServer on top of the MCP tool
+// handlers, so both transports share validation, rate limits, and auditing.
+type grpcService struct {
+	floopv1.UnimplementedFloopServiceServer
+	s *Server
+}
+
+// RunGRPC serves the floop gRPC API (see api/floop/v1) on addr: a TCP
+// address, which must be loopback unless a token is configured, or
+// "unix:<path>" for a Unix socket only the current user can open.
+// This blocks until the context is cancelled or a termination signal arrives.
+func (s *Server) RunGRPC(ctx context.Context, addr string) error {
+	lis, err := listenGRPC(addr, s.token)
+	if err != nil {
+		return err
+	}
+	return s.serveGRPC(ctx, lis)
+}
+
+// listenGRPC opens the listener for a RunGRPC address.
+func listenGRPC(addr, token string) (net.Listener, error) {
+	if path, ok := strings.CutPrefix(addr, "unix:"); ok {
+		path = strings.TrimPrefix(path, "//")
+		lis, err := net.Listen("unix", path)
+		if err != nil {
+			return nil, err
+		}
+		if err := os.Chmod(path, 0600); err != nil {
+			lis.Close()
+			return nil, fmt.Errorf("restricting socket permissions: %w", err)
+		}
+		return lis, nil
+	}
+	if err := checkListenAddr(addr, token); err != nil {
+		return nil, err
+	}
+	return net.Listen("tcp", addr)
+}
+
+// grpcAuth returns the interceptors requiring token as a bearer token in
+// the "authorization" metadata of every call, or none without a token.
+func grpcAuth(token string) []grpc.ServerOption {
+	if token == "" {
+		return nil
+	}
+	check := func(ctx context.Context) error {
+		md, _ := metadata.FromIncomingContext(ctx)
+		for _, v := range md.Get("authorization") {
+			if tokenMatches(v, token) {
+				return nil
+			}
+		}
+		return status.Error(codes.Unauthenticated, "missing or invalid bearer token")
+	}
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+			if err := check(ctx); err != nil {
+				return nil, err
+			}
+			return handler(ctx, req)
+		}),
+		grpc.StreamInterceptor(func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+			if err := check(ss.Context()); err != nil {
+				return err
+			}
+			return handler(srv, ss)
+		}),
+	}
+}
+
+// serveGRPC serves the gRPC API on lis until the context is cancelled or a
+// termination signal arrives.
+func (s *Server) serveGRPC(ctx context.Context, lis net.Listener) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	notifySignals(sigChan)
+
+	go s.watchConfig(ctx)
+	go s.autoBackupLoop(ctx)
+
+	grpcServer := grpc.NewServer(grpcAuth(s.token)...)
+	floopv1.RegisterFloopServiceServer(grpcServer, &grpcService{s: s})
+
+	go func() {
+		select {
+		case <-sigChan:
+		case <-ctx.Done():
+		}
+		stopped := make(chan struct{})
+		go func() {
+			grpcServer.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(5 * time.Second):
+			grpcServer.Stop()
+		}
+	}()
+
+	err := grpcServer.Serve(lis)
+	if errors.Is(err, grpc.ErrServerStopped) {
+		err = nil
+	}
+
+	s.Close()
+
+	return err
+}
+
+// ProcessCorrection implements floop_learn.
+func (g *grpcService) ProcessCorrection(ctx context.Context, req *floopv1.ProcessCorrectionRequest) (*floopv1.ProcessCorrectionResponse, error) {
+	actx := req.GetContext()
+	_, out, err := g.s.handleFloopLearn(ctx, nil, FloopLearnInput{
+		Wrong:     req.GetWrong(),
+		Right:     req.GetRight(),
+		File:      actx.GetFile(),
+		Task:      actx.GetTask(),
+		Language:  actx.GetLanguage(),
+		AutoMerge: req.GetAutoMerge(),
+		Tags:      req.GetTags(),
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &floopv1.ProcessCorrectionResponse{
+		CorrectionId:    out.CorrectionID,
+		BehaviorId:      out.BehaviorID,
+		Scope:           out.Scope,
+		AutoAccepted:    out.AutoAccepted,
+		Confidence:      out.Confidence,
+		RequiresReview:  out.RequiresReview,
+		ReviewReasons:   out.ReviewReasons,
+		HeldForReview:   out.HeldForReview,
+		MergedIntoId:    out.MergedIntoID,
+		MergeSimilarity: out.MergeSimilarity,
+		ForgottenId:     out.ForgottenID,
+		Message:         out.Message,
+	}, nil
+}
+
+// GetActive implements floop_active.
+func (g *grpcService) GetActive(ctx context.Context, req *floopv1.GetActiveRequest) (*floopv1.GetActiveResponse, error) {
+	_, out, err := g.s.handleFloopActive(ctx, nil, activeInput(req.GetContext()))
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	resp := &floopv1.GetActiveResponse{Degraded: out.Degraded}
+	if resp.Context, err = toStruct(out.Context); err != nil {
+		return nil, grpcError(err)
+	}
+	if resp.Active, err = toProtoBehaviors(out.Active); err != nil {
+		return nil, grpcError(err)
+	}
+	if ts := out.TokenStats; ts != nil {
+		resp.TokenStats = &floopv1.TokenStats{
+			TotalCanonicalTokens: int32(ts.TotalCanonicalTokens),
+			BudgetDefault:        int32(ts.BudgetDefault),
+			BehaviorCount:        int32(ts.BehaviorCount),
+			FullCount:            int32(ts.FullCount),
+			SummaryCount:         int32(ts.SummaryCount),
+			NameOnlyCount:        int32(ts.NameOnlyCount),
+			OmittedCount:         int32(ts.OmittedCount),
+		}
+	}
+	return resp, nil
+}
+
+// Feedback implements floop_feedback.
+func (g *grpcService) Feedback(ctx context.Context, req *floopv1.FeedbackRequest) (*floopv1.FeedbackResponse, error) {
+	var signal string
+	switch req.GetSignal() {
+	case floopv1.FeedbackSignal_FEEDBACK_SIGNAL_CONFIRMED:
+		signal = "confirmed"
+	case floopv1.FeedbackSignal_FEEDBACK_SIGNAL_OVERRIDDEN:
+		signal = "overridden"
+	default:
+		return nil, status.Error(codes.InvalidArgument, "signal must be CONFIRMED or OVERRIDDEN")
+	}
+	_, out, err := g.s.handleFloopFeedback(ctx, nil, FloopFeedbackInput{
+		BehaviorID: req.GetBehaviorId(),
+		Signal:     signal,
+	})
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &floopv1.FeedbackResponse{
+		BehaviorId: out.BehaviorID,
+		Signal:     req.GetSignal(),
+		Message:    out.Message,
+	}, nil
+}
+
+// Search ranks behaviors by full-text match, as 'floop search --keyword'
+// does. Scores are relative to the best match.
+func (g *grpcService) Search(ctx context.Context, req *floopv1.SearchRequest) (_ *floopv1.SearchResponse, retErr error) {
+	start := time.Now()
+	defer func() {
+		g.s.auditTool("floop_search", start, retErr, sanitizeToolParams("floop_search", map[string]interface{}{
+			"query": req.GetQuery(), "limit": req.GetLimit(),
+		}), "local")
+	}()
+
+	if err := ratelimit.CheckLimit(g.s.toolLimiters, "floop_search"); err != nil {
+		return nil, grpcError(err)
+	}
+	query := strings.TrimSpace(req.GetQuery())
+	if query == "" {
+		return nil, status.Error(codes.InvalidArgument, "query must not be empty")
+	}
+	limit := int(req.GetLimit())
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+
+	results, err := g.s.searchBehaviors(ctx, query, limit)
+	if err != nil {
+		return nil, grpcError(err)
+	}
+	return &floopv1.SearchResponse{Results: results}, nil
+}
+
+// searchBehaviors ranks behaviors against query using the store's full-text
+// index, falling back to content similarity when the store has none.
+func (s *Server) searchBehaviors(ctx context.Context, query string, limit int) ([]*floopv1.SearchResult, error) {
+	nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	behaviors := make(map[string]models.Behavior, len(nodes))
+	for _, node := range nodes {
+		behaviors[node.ID] = models.NodeToBehavior(node)
+	}
+
+	var results []*floopv1.SearchResult
+	add := func(b models.Behavior, score float64) {
+		results = append(results, &floopv1.SearchResult{
+			Id:        b.ID,
+			Name:      b.Name,
+			Kind:      string(b.Kind),
+			Canonical: b.Content.Canonical,
+			Score:     score,
+		})
+	}
+
+	if ts, ok := s.store.(store.TextSearcher); ok {
+		matches, err := ts.SearchText(ctx, query, false, 0)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search behaviors: %w", err)
+		}
+		for _, m := range matches {
+			if b, ok := behaviors[m.BehaviorID]; ok {
+				add(b, m.Score/matches[0].Score)
+			}
+		}
+	} else {
+		for _, b := range behaviors {
+			text := strings.Join([]string{b.Name, b.Content.Canonical, b.Content.Summary, strings.Join(b.Content.Tags, " ")}, " ")
+			if score := similarity.ComputeContentSimilarity(query, text); score > 0 {
+				add(b, score)
+			}
+		}
+		sort.Slice(results, func(i, j int) bool {
+			if results[i].Score != results[j].Score {
+				return results[i].Score > results[j].Score
+			}
+			return results[i].Id < results[j].Id
+		})
+	}
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	return results, nil
+}
+
+// WatchActive streams changes to the active set of a context. Activation is
+// re-run whenever a correction is learned through this server and every
+// grpcWatchInterval; a change is sent only when the active set differs.
+func (g *grpcService) WatchActive(req *floopv1.WatchActiveRequest, stream floopv1.FloopService_WatchActiveServer) error {
+	ctx := stream.Context()
+	args := activeInput(req.GetContext())
+
+	if err := ratelimit.CheckLimit(g.s.toolLimiters, "floop_context"); err != nil {
+		return grpcError(err)
+	}
+
+	changed := g.s.addActiveWatcher()
+	defer g.s.removeActiveWatcher(changed)
+
+	ticker := time.NewTicker(grpcWatchInterval)
+	defer ticker.Stop()
+
+	var previous map[string]BehaviorSummary
+	for {
+		out, err := g.s.activate(ctx, args)
+		if err != nil {
+			return grpcError(err)
+		}
+		current := make(map[string]BehaviorSummary, len(out.Active))
+		for _, b := range out.Active {
+			current[b.ID] = b
+		}
+		added, removed, unchanged := diffActiveSets(previous, current)
+		if previous == nil || len(added) > 0 || len(removed) > 0 {
+			change := &floopv1.ActiveChange{
+				Removed:   removed,
+				Unchanged: int32(unchanged),
+				Count:     int32(len(current)),
+				Degraded:  out.Degraded,
+			}
+			if change.Added, err = toProtoBehaviors(added); err != nil {
+				return grpcError(err)
+			}
+			if err := stream.Send(change); err != nil {
+				return err
+			}
+		}
+		previous = current
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-changed:
+		case <-ticker.C:
+		}
+	}
+}
+
+// addActiveWatcher registers a WatchActive stream. The returned channel
+// receives a value when the behavior graph changes.
+func (s *Server) addActiveWatcher() chan struct{} {
+	ch := make(chan struct{}, 1)
+	s.activeWatchersMu.Lock()
+	s.activeWatchers[ch] = struct{}{}
+	s.activeWatchersMu.Unlock()
+	return ch
+}
+
+// removeActiveWatcher unregisters a WatchActive stream.
+func (s *Server) removeActiveWatcher(ch chan struct{}) {
+	s.activeWatchersMu.Lock()
+	delete(s.activeWatchers, ch)
+	s.activeWatchersMu.Unlock()
+}
+
+// notifyActiveWatchers wakes every WatchActive stream without blocking; a
+// stream that has not yet consumed its last wake-up keeps just the one.
+func (s *Server) notifyActiveWatchers() {
+	s.activeWatchersMu.Lock()
+	defer s.activeWatchersMu.Unlock()
+	for ch := range s.activeWatchers {
+		select {
+		case ch <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// activeInput converts an activation context to floop_active input.
+func activeInput(actx *floopv1.ActivationContext) FloopActiveInput {
+	return FloopActiveInput{
+		File:     actx.GetFile(),
+		Task:     actx.GetTask(),
+		Language: actx.GetLanguage(),
+	}
+}
+
+// toProtoBehaviors converts behavior summaries to their gRPC form.
+func toProtoBehaviors(summaries []BehaviorSummary) ([]*floopv1.Behavior, error) {
+	behaviors := make([]*floopv1.Behavior, 0, len(summaries))
+	for _, b := range summaries {
+		content, err := toStruct(b.Content)
+		if err != nil {
+			return nil, fmt.Errorf("behavior %s content: %w", b.ID, err)
+		}
+		when, err := toStruct(b.When)
+		if err != nil {
+			return nil, fmt.Errorf("behavior %s when: %w", b.ID, err)
+		}
+		behaviors = append(behaviors, &floopv1.Behavior{
+			Id:         b.ID,
+			Name:       b.Name,
+			Kind:       b.Kind,
+			Tier:       b.Tier,
+			Content:    content,
+			Confidence: b.Confidence,
+			When:       when,
+			Tags:       b.Tags,
+			Activation: b.Activation,
+			Distance:   int32(b.Distance),
+			SeedSource: b.SeedSource,
+		})
+	}
+	return behaviors, nil
+}
+
+// toStruct converts a JSON-like map to a protobuf Struct. It round-trips
+// through JSON because structpb.NewStruct rejects typed slices such as
+// []string, which behavior content and conditions contain.
+func toStruct(m map[string]interface{}) (*structpb.Struct, error) {
+	if m == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+	s := &structpb.Struct{}
+	if err := s.UnmarshalJSON(data); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// grpcError maps a handler error to a gRPC status.
+func grpcError(err error) error {
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case strings.HasPrefix(err.Error(), "rate limit exceeded"):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	case strings.Contains(err.Error(), "parameter is required"), strings.Contains(err.Error(), "must be"):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case strings.HasPrefix(err.Error(), "behavior not found"):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
@@ -0,0 +1,189 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	floopv1 "github.com/nvandessel/floop/api/floop/v1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// setupGRPCClient serves server's gRPC API over an in-memory listener and
+// returns a client for it, dialed with the extra opts.
+func setupGRPCClient(t *testing.T, server *Server, opts ...grpc.DialOption) *floopv1.Client {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- server.serveGRPC(ctx, lis) }()
+
+	client, err := floopv1.Dial("passthrough:///bufconn", append([]grpc.DialOption{
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	}, opts...)...)
+	if err != nil {
+		t.Fatalf("Dial: %v", err)
+	}
+	t.Cleanup(func() {
+		client.Close()
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("serveGRPC: %v", err)
+		}
+	})
+	return client
+}
+
+func TestGRPC_GetActiveSearchFeedback(t *testing.T) {
+	server, _ := setupTestServer(t)
+	addTestBehavior(t, server, "grpc-1")
+	client := setupGRPCClient(t, server)
+	ctx := context.Background()
+
+	active, err := client.Active(ctx, &floopv1.ActivationContext{File: "main.go"})
+	if err != nil {
+		t.Fatalf("Active: %v", err)
+	}
+	b := findProtoBehavior(active, "grpc-1")
+	if b == nil {
+		t.Fatalf("Active = %v, want grpc-1 included", active)
+	}
+	if got := b.GetContent().GetFields()["canonical"].GetStringValue(); got != "Test behavior grpc-1" {
+		t.Errorf("canonical = %q, want %q", got, "Test behavior grpc-1")
+	}
+
+	search, err := client.Search(ctx, &floopv1.SearchRequest{Query: "test behavior"})
+	if err != nil {
+		t.Fatalf("Search: %v", err)
+	}
+	if len(search.GetResults()) == 0 || search.GetResults()[0].GetId() != "grpc-1" {
+		t.Errorf("Search results = %v, want grpc-1 first", search.GetResults())
+	}
+	if _, err := client.Search(ctx, &floopv1.SearchRequest{Query: "  "}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Search with empty query = %v, want InvalidArgument", err)
+	}
+
+	if err := client.Confirm(ctx, "grpc-1"); err != nil {
+		t.Errorf("Confirm: %v", err)
+	}
+	if err := client.Override(ctx, "missing"); status.Code(err) != codes.NotFound {
+		t.Errorf("Override(missing) = %v, want NotFound", err)
+	}
+	if _, err := client.Feedback(ctx, &floopv1.FeedbackRequest{BehaviorId: "grpc-1"}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Feedback without signal = %v, want InvalidArgument", err)
+	}
+}
+
+func TestGRPC_WatchActive(t *testing.T) {
+	server, _ := setupTestServer(t)
+	addTestBehavior(t, server, "grpc-1")
+	client := setupGRPCClient(t, server)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	changes := make(chan *floopv1.ActiveChange)
+	watchDone := make(chan error, 1)
+	go func() {
+		watchDone <- client.Watch(ctx, nil, func(change *floopv1.ActiveChange) error {
+			select {
+			case changes <- change:
+			case <-ctx.Done():
+			}
+			return nil
+		})
+	}()
+
+	first := <-changes
+	if int(first.GetCount()) != len(first.GetAdded()) || findProtoBehavior(first.GetAdded(), "grpc-1") == nil {
+		t.Fatalf("first change = %v, want the whole active set, including grpc-1", first)
+	}
+
+	learned, err := client.Learn(ctx, "used fmt.Println for errors", "Log errors with slog at the error level", nil)
+	if err != nil {
+		t.Fatalf("Learn: %v", err)
+	}
+	if learned.GetBehaviorId() == "" {
+		t.Fatalf("Learn returned no behavior ID: %v", learned)
+	}
+
+	select {
+	case change := <-changes:
+		if change.GetUnchanged() != first.GetCount() || findProtoBehavior(change.GetAdded(), learned.GetBehaviorId()) == nil {
+			t.Errorf("change after learn = %v, want %s added", change, learned.GetBehaviorId())
+		}
+	case <-ctx.Done():
+		t.Fatal("no change streamed after learn")
+	}
+
+	cancel()
+	if err := <-watchDone; status.Code(err) != codes.Canceled {
+		t.Errorf("Watch after cancel = %v, want Canceled", err)
+	}
+}
+
+func findProtoBehavior(behaviors []*floopv1.Behavior, id string) *floopv1.Behavior {
+	for _, b := range behaviors {
+		if b.GetId() == id {
+			return b
+		}
+	}
+	return nil
+}
+
+func TestGRPC_Token(t *testing.T) {
+	server, _ := setupTestServer(t)
+	server.token = "grpc-secret"
+	ctx := context.Background()
+
+	anonymous := setupGRPCClient(t, server)
+	_, err := anonymous.Learn(ctx, "wrong", "right", nil)
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("Learn without token: err = %v, want Unauthenticated", err)
+	}
+
+	authed := setupGRPCClient(t, server, floopv1.WithToken("grpc-secret"))
+	if _, err := authed.Active(ctx, &floopv1.ActivationContext{File: "main.go"}); err != nil {
+		t.Errorf("Active with token: %v", err)
+	}
+}
+
+func TestListenGRPC(t *testing.T) {
+	if _, err := listenGRPC("0.0.0.0:0", ""); err == nil {
+		t.Error("expected a non-loopback address without a token to be refused")
+	}
+
+	lis, err := listenGRPC("127.0.0.1:0", "")
+	if err != nil {
+		t.Fatalf("loopback listen: %v", err)
+	}
+	lis.Close()
+
+	dir, err := os.MkdirTemp("", "floop-grpc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	sock := filepath.Join(dir, "floop.sock")
+	lis, err = listenGRPC("unix:"+sock, "")
+	if err != nil {
+		t.Fatalf("unix listen: %v", err)
+	}
+	defer lis.Close()
+	info, err := os.Stat(sock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0600 {
+		t.Errorf("socket mode = %o, want 600", perm)
+	}
+}
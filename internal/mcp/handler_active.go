@@ -9,9 +9,12 @@ import (
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/metrics"
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/nearmiss"
 	"github.com/nvandessel/floop/internal/ratelimit"
 	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/session"
 	"github.com/nvandessel/floop/internal/spreading"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tiering"
@@ -30,35 +33,68 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 		return nil, FloopActiveOutput{}, err
 	}
 
-	// Build context from parameters
+	out, err := s.activate(ctx, args)
+	if err != nil {
+		return nil, FloopActiveOutput{}, err
+	}
+	return nil, out, nil
+}
+
+// buildContext builds an activation context from tool parameters. A relative
+// file path is resolved against the project root. Custom values may not use
+// built-in context keys.
+func (s *Server) buildContext(file, task, language string, custom map[string]string) (models.ContextSnapshot, error) {
 	ctxBuilder := activation.NewContextBuilder()
+	if s.floopConfig != nil {
+		ctxBuilder.WithGitDetection(s.floopConfig.Activation.GitContext)
+	}
 
-	if args.File != "" {
-		// Resolve file path relative to project root
-		filePath := args.File
+	if file != "" {
+		filePath := file
 		if !filepath.IsAbs(filePath) {
 			filePath = filepath.Join(s.root, filePath)
 		}
 		ctxBuilder.WithFile(filePath)
 	}
 
-	if args.Task != "" {
-		ctxBuilder.WithTask(args.Task)
+	if task != "" {
+		ctxBuilder.WithTask(task)
+	}
+
+	if language != "" {
+		ctxBuilder.WithLanguage(sanitize.SanitizeBehaviorContent(language))
 	}
 
-	if args.Language != "" {
-		ctxBuilder.WithLanguage(sanitize.SanitizeBehaviorContent(args.Language))
+	for key, value := range custom {
+		if models.IsBuiltinField(key) {
+			return models.ContextSnapshot{}, fmt.Errorf("invalid context key %q: built-in context keys have their own parameters", key)
+		}
+		ctxBuilder.WithCustom(key, sanitize.SanitizeBehaviorContent(value))
 	}
 
 	ctxBuilder.WithRepoRoot(s.root)
 
-	actCtx := ctxBuilder.Build()
+	return ctxBuilder.Build(), nil
+}
+
+// activate runs the activation pipeline for the given context: predicate
+// matching, spreading activation, conflict resolution, and token budget
+// tiering. Shared by floop_active and floop_context.
+//
+// Matching and spreading are bounded by activation.time_budget. On overrun
+// the pipeline sheds load — keeping the top behaviors by cached PageRank and
+// priority and skipping spreading — and marks the output degraded, so a slow
+// store never blocks an agent turn.
+func (s *Server) activate(ctx context.Context, args FloopActiveInput) (FloopActiveOutput, error) {
+	start := time.Now()
+
+	actCtx, err := s.buildContext(args.File, args.Task, args.Language, args.Context)
+	if err != nil {
+		return FloopActiveOutput{}, err
+	}
 
 	// Load behaviors — vector pre-filter when embedder is available, else load all
-	var (
-		nodes []store.Node
-		err   error
-	)
+	var nodes []store.Node
 	if s.embedder != nil && s.embedder.Available() {
 		nodes, err = vectorRetrieve(ctx, s.embedder, s.vectorIndex, s.store, actCtx, vectorRetrieveTopK)
 		if err != nil {
@@ -69,32 +105,60 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 	if nodes == nil {
 		nodes, err = s.store.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
 		if err != nil {
-			return nil, FloopActiveOutput{}, fmt.Errorf("failed to query behaviors: %w", err)
+			return FloopActiveOutput{}, fmt.Errorf("failed to query behaviors: %w", err)
 		}
 	}
 
-	// Convert nodes to behaviors
+	// Convert nodes to behaviors, dropping those the agent profile excludes
 	behaviors := make([]models.Behavior, 0, len(nodes))
 	for _, node := range nodes {
 		behavior := models.NodeToBehavior(node)
 		behaviors = append(behaviors, behavior)
 	}
+	profile := s.profile()
+	behaviors = profile.Filter(behaviors)
+
+	s.pageRankMu.RLock()
+	prScores := s.pageRankCache
+	s.pageRankMu.RUnlock()
 
 	// Evaluate which behaviors are active
 	evaluator := activation.NewEvaluator()
-	matches := evaluator.Evaluate(actCtx, behaviors)
+	maxFailing := 0
+	budget := activation.Budget{Rank: prScores}
+	if cfg := s.config(); cfg != nil {
+		maxFailing = cfg.Activation.NearMissMaxFailing
+		budget = activation.NewBudget(start, cfg.Activation.TimeBudget, cfg.Activation.ShedTopN, prScores)
+	}
+	matches, nearMisses, degraded := evaluator.EvaluateWithinBudget(actCtx, behaviors, maxFailing, budget)
+	if !degraded && budget.Expired() {
+		// Matching finished but left no time to spread activation.
+		degraded = true
+	}
+	if degraded {
+		s.logger.Warn("activation exceeded time budget, shedding load",
+			"budget", budget.Deadline.Sub(start), "elapsed", time.Since(start),
+			"behaviors", len(behaviors), "kept", len(matches))
+	}
+
+	// Background: count near misses so 'floop active --near-misses' can
+	// suggest relaxing conditions that keep blocking a behavior.
+	if len(nearMisses) > 0 {
+		s.runBackground("near-miss-recording", func() {
+			if err := nearmiss.Record(context.Background(), s.store, nearMisses, time.Now()); err != nil {
+				s.logger.Warn("near-miss recording failed", "error", err)
+			}
+		})
+	}
 
 	// Spread activation through graph edges
-	seeds := matchesToSeeds(matches)
+	seeds := spreading.SeedsFromMatches(matches)
 
 	// Boost seeds with PageRank scores (15% blend — tiebreaker, not dominator)
-	s.pageRankMu.RLock()
-	prScores := s.pageRankCache
-	s.pageRankMu.RUnlock()
 	seeds = boostSeedsWithPageRank(seeds, prScores, 0.15)
 
 	var spreadResults []spreading.Result
-	if len(seeds) > 0 {
+	if len(seeds) > 0 && !degraded {
 		spreadConfig := spreading.DefaultConfig()
 		affinityConfig := spreading.DefaultAffinityConfig()
 		spreadConfig.Affinity = &affinityConfig
@@ -105,7 +169,7 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 		if err != nil {
 			s.logger.Warn("spreading activation failed", "error", err)
 		} else {
-			matches = mergeSpreadResults(ctx, s.store, matches, spreadResults)
+			matches = spreading.MergeMatches(ctx, s.store, matches, spreadResults)
 		}
 
 		// Background: stamp LastActivated on edges touching seed behaviors
@@ -176,7 +240,7 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 
 	// Apply token budget enforcement: tier and demote behaviors to fit budget.
 	mapper := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig())
-	plan := mapper.MapResults(tierResults, behaviorMap, s.floopConfig.TokenBudget.Default)
+	plan := mapper.MapResults(tierResults, behaviorMap, profile.Budget(s.config().TokenBudget.Default))
 
 	// Build summaries from the injection plan (included behaviors only).
 	included := plan.IncludedBehaviors()
@@ -248,19 +312,37 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 	// Record activation hits + implicit confirmations in background.
 	// Note: confidence reinforcement has been replaced by ACT-R base-level activation
 	// (see ranking/actr.go), which derives frequency+recency from existing data.
+	trackedSession := session.Current(s.root)
 	s.runBackground("activation-recording", func() {
 		type activationRecorder interface {
 			RecordActivationHit(ctx context.Context, behaviorID string) error
 		}
-		if recorder, ok := s.store.(activationRecorder); ok {
-			for _, b := range activeBehaviors {
-				if strings.HasPrefix(b.ID, "seed-") {
-					continue
-				}
-				if err := recorder.RecordActivationHit(context.Background(), b.ID); err != nil {
-					s.logger.Warn("activation hit recording failed", "behavior_id", b.ID, "error", err)
-				}
+		type sessionActivationRecorder interface {
+			RecordSessionActivation(ctx context.Context, behaviorID, sessionID string) error
+		}
+		sessionID := trackedSession
+		if sessionID == "" {
+			sessionID = s.processSessionID
+		}
+		var activatedIDs []string
+		for _, b := range activeBehaviors {
+			if strings.HasPrefix(b.ID, "seed-") {
+				continue
+			}
+			activatedIDs = append(activatedIDs, b.ID)
+			var err error
+			if recorder, ok := s.store.(sessionActivationRecorder); ok {
+				err = recorder.RecordSessionActivation(context.Background(), b.ID, sessionID)
+			} else if recorder, ok := s.store.(activationRecorder); ok {
+				err = recorder.RecordActivationHit(context.Background(), b.ID)
 			}
+			if err != nil {
+				s.logger.Warn("activation hit recording failed", "behavior_id", b.ID, "error", err)
+			}
+		}
+
+		if trackedSession != "" && len(activatedIDs) > 0 {
+			s.trackSession(context.Background(), session.EventActivation, activatedIDs...)
 		}
 
 		// Record session-scoped implicit confirmations.
@@ -276,13 +358,21 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 		}
 	})
 
-	return nil, FloopActiveOutput{
-		Context: ctxMap,
-		Active:  summaries,
-		Count:   len(summaries),
+	s.metrics().Record(metrics.Event{
+		Kind:       metrics.KindActivation,
+		DurationMS: metrics.Since(start),
+		Count:      len(summaries),
+		Degraded:   degraded,
+	})
+
+	return FloopActiveOutput{
+		Context:  ctxMap,
+		Active:   summaries,
+		Count:    len(summaries),
+		Degraded: degraded,
 		TokenStats: &TokenStats{
 			TotalCanonicalTokens: plan.TotalTokens,
-			BudgetDefault:        s.floopConfig.TokenBudget.Default,
+			BudgetDefault:        s.config().TokenBudget.Default,
 			BehaviorCount:        plan.BehaviorCount(),
 			FullCount:            len(plan.FullBehaviors),
 			SummaryCount:         len(plan.SummarizedBehaviors),
@@ -292,53 +382,6 @@ func (s *Server) handleFloopActive(ctx context.Context, req *sdk.CallToolRequest
 	}, nil
 }
 
-// matchesToSeeds converts activation results to spreading seeds.
-func matchesToSeeds(matches []activation.ActivationResult) []spreading.Seed {
-	seeds := make([]spreading.Seed, len(matches))
-	for i, m := range matches {
-		seeds[i] = spreading.Seed{
-			BehaviorID: m.Behavior.ID,
-			Activation: spreading.MatchScoreToActivation(len(m.Behavior.When), m.MatchScore),
-			Source:     spreading.BuildSourceLabel(m.MatchedConditions),
-		}
-	}
-	return seeds
-}
-
-// mergeSpreadResults merges spreading engine results back into the activation
-// matches slice. Behaviors already present via direct match are kept as-is;
-// spread-only behaviors are loaded from the store and appended with Specificity 0
-// so the Resolver ranks them below direct matches.
-func mergeSpreadResults(ctx context.Context, gs store.GraphStore, matches []activation.ActivationResult, spread []spreading.Result) []activation.ActivationResult {
-	// Index existing matches by ID.
-	seen := make(map[string]bool, len(matches))
-	for _, m := range matches {
-		seen[m.Behavior.ID] = true
-	}
-
-	for _, sr := range spread {
-		if seen[sr.BehaviorID] {
-			continue
-		}
-		// Load the full behavior node for spread-only results.
-		node, err := gs.GetNode(ctx, sr.BehaviorID)
-		if err != nil || node == nil {
-			continue
-		}
-		if node.Kind != "behavior" {
-			continue
-		}
-		behavior := models.NodeToBehavior(*node)
-		matches = append(matches, activation.ActivationResult{
-			Behavior:    behavior,
-			Specificity: 0, // Spread-only: always lower than direct matches in Resolver
-		})
-		seen[sr.BehaviorID] = true
-	}
-
-	return matches
-}
-
 // spreadMeta holds spreading activation metadata for a single behavior.
 type spreadMeta struct {
 	activation float64
@@ -9,6 +9,7 @@ import (
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/pathutil"
 	"github.com/nvandessel/floop/internal/ratelimit"
 )
@@ -55,7 +56,8 @@ func (s *Server) handleFloopBackup(ctx context.Context, req *sdk.CallToolRequest
 
 	// Apply retention policy
 	backupDir := filepath.Dir(outputPath)
-	if _, err := backup.ApplyRetention(backupDir, s.retentionPolicy); err != nil {
+	_, retentionPolicy := s.backupSettings()
+	if _, err := backup.ApplyRetention(backupDir, retentionPolicy); err != nil {
 		s.logger.Warn("failed to apply retention", "error", err)
 	}
 
@@ -116,6 +118,9 @@ func (s *Server) handleFloopRestore(ctx context.Context, req *sdk.CallToolReques
 
 	mode := backup.RestoreMerge
 	if args.Mode == "replace" {
+		if err := s.config().Safety.CheckAllowed(config.OpRestoreReplace); err != nil {
+			return nil, FloopRestoreOutput{}, err
+		}
 		mode = backup.RestoreReplace
 	}
 
@@ -91,9 +91,10 @@ func (s *Server) handleFloopConsolidate(ctx context.Context, req *sdk.CallToolRe
 	}
 
 	// Resolve executor from config
+	floopCfg := s.config()
 	executor := ""
-	if s.floopConfig != nil && s.floopConfig.Consolidation.Executor != "" {
-		executor = s.floopConfig.Consolidation.Executor
+	if floopCfg != nil && floopCfg.Consolidation.Executor != "" {
+		executor = floopCfg.Consolidation.Executor
 	}
 
 	// Warn if LLM executor is requested but no client is available
@@ -103,9 +104,9 @@ func (s *Server) handleFloopConsolidate(ctx context.Context, req *sdk.CallToolRe
 
 	// Run consolidation pipeline
 	var model, logLevel string
-	if s.floopConfig != nil {
-		model = s.floopConfig.LLM.ComparisonModel
-		logLevel = s.floopConfig.Logging.Level
+	if floopCfg != nil {
+		model = floopCfg.LLM.ComparisonModel
+		logLevel = floopCfg.Logging.Level
 	}
 	// Only create decision logger when actually using the LLM executor to
 	// avoid empty JSONL files from heuristic fallback runs.
@@ -0,0 +1,226 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/ratelimit"
+)
+
+// maxContextSessions bounds the number of agent context sessions tracked at
+// once. When full, the least recently updated session is evicted.
+const maxContextSessions = 64
+
+// contextSessionURIPrefix is the resource URI prefix for per-session active sets.
+const contextSessionURIPrefix = "floop://sessions/"
+
+// contextSessionIDPattern restricts agent-supplied session IDs to characters
+// that are safe inside a resource URI.
+var contextSessionIDPattern = regexp.MustCompile(`^[A-Za-z0-9._-]{1,128}$`)
+
+// contextSession is the last activation result delivered to an agent session.
+type contextSession struct {
+	context   map[string]interface{}
+	active    map[string]BehaviorSummary
+	updatedAt time.Time
+}
+
+// contextSessionURI returns the resource URI clients subscribe to for
+// change notifications on a context session.
+func contextSessionURI(id string) string {
+	return contextSessionURIPrefix + id + "/active"
+}
+
+// handleFloopContext implements the floop_context tool.
+//
+// Agents call it whenever their working context changes (file switched, task
+// changed). The first call registers a session; each call re-runs activation
+// and returns only the behaviors that entered or left the active set since the
+// previous call for that session.
+func (s *Server) handleFloopContext(ctx context.Context, req *sdk.CallToolRequest, args FloopContextInput) (_ *sdk.CallToolResult, _ FloopContextOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_context", start, retErr, sanitizeToolParams("floop_context", map[string]interface{}{
+			"file": args.File, "task": args.Task, "language": args.Language,
+		}), "local")
+	}()
+
+	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_context"); err != nil {
+		return nil, FloopContextOutput{}, err
+	}
+
+	sessionID := args.SessionID
+	if sessionID == "" {
+		sessionID = fmt.Sprintf("ctx-%d", time.Now().UnixNano())
+	} else if !contextSessionIDPattern.MatchString(sessionID) {
+		return nil, FloopContextOutput{}, fmt.Errorf("invalid session_id: must match %s", contextSessionIDPattern.String())
+	}
+
+	active, err := s.activate(ctx, FloopActiveInput{
+		File:     args.File,
+		Task:     args.Task,
+		Language: args.Language,
+		Context:  args.Context,
+	})
+	if err != nil {
+		return nil, FloopContextOutput{}, err
+	}
+
+	current := make(map[string]BehaviorSummary, len(active.Active))
+	for _, b := range active.Active {
+		current[b.ID] = b
+	}
+
+	s.contextSessionsMu.Lock()
+	prev, registered := s.contextSessions[sessionID]
+	if !registered && len(s.contextSessions) >= maxContextSessions {
+		s.evictOldestContextSessionLocked()
+	}
+	s.contextSessions[sessionID] = &contextSession{
+		context:   active.Context,
+		active:    current,
+		updatedAt: time.Now(),
+	}
+	s.contextSessionsMu.Unlock()
+
+	out := FloopContextOutput{
+		SessionID:   sessionID,
+		ResourceURI: contextSessionURI(sessionID),
+		Registered:  !registered,
+		Context:     active.Context,
+		Count:       len(current),
+		Degraded:    active.Degraded,
+	}
+
+	var previous map[string]BehaviorSummary
+	if prev != nil {
+		previous = prev.active
+	}
+	out.Added, out.Removed, out.Unchanged = diffActiveSets(previous, current)
+
+	return nil, out, nil
+}
+
+// diffActiveSets compares two active sets keyed by behavior ID. Added and
+// removed are sorted by ID for stable output.
+func diffActiveSets(previous, current map[string]BehaviorSummary) (added []BehaviorSummary, removed []string, unchanged int) {
+	added = []BehaviorSummary{}
+	removed = []string{}
+	for id, b := range current {
+		if _, ok := previous[id]; ok {
+			unchanged++
+			continue
+		}
+		added = append(added, b)
+	}
+	for id := range previous {
+		if _, ok := current[id]; !ok {
+			removed = append(removed, id)
+		}
+	}
+	sort.Slice(added, func(i, j int) bool { return added[i].ID < added[j].ID })
+	sort.Strings(removed)
+	return added, removed, unchanged
+}
+
+// evictOldestContextSessionLocked removes the least recently updated session.
+// Caller must hold contextSessionsMu.
+func (s *Server) evictOldestContextSessionLocked() {
+	var oldestID string
+	var oldest time.Time
+	for id, cs := range s.contextSessions {
+		if oldestID == "" || cs.updatedAt.Before(oldest) {
+			oldestID, oldest = id, cs.updatedAt
+		}
+	}
+	delete(s.contextSessions, oldestID)
+}
+
+// notifyContextChanged tells subscribed clients that the behavior graph has
+// changed, so their active sets may be stale. Clients respond by calling
+// floop_context again to receive the delta; gRPC WatchActive streams re-run
+// activation themselves.
+func (s *Server) notifyContextChanged() {
+	s.notifyActiveWatchers()
+
+	s.contextSessionsMu.Lock()
+	uris := make([]string, 0, len(s.contextSessions)+1)
+	uris = append(uris, "floop://behaviors/active")
+	for id := range s.contextSessions {
+		uris = append(uris, contextSessionURI(id))
+	}
+	s.contextSessionsMu.Unlock()
+
+	s.runBackground("context-notify", func() {
+		for _, uri := range uris {
+			if err := s.server.ResourceUpdated(context.Background(), &sdk.ResourceUpdatedNotificationParams{URI: uri}); err != nil {
+				s.logger.Warn("resource update notification failed", "uri", uri, "error", err)
+			}
+		}
+	})
+}
+
+// handleContextSessionResource returns the last active set delivered to a
+// context session as JSON.
+func (s *Server) handleContextSessionResource(ctx context.Context, req *sdk.ReadResourceRequest) (*sdk.ReadResourceResult, error) {
+	// URI format: floop://sessions/{id}/active
+	uri := req.Params.URI
+	if !strings.HasPrefix(uri, contextSessionURIPrefix) || !strings.HasSuffix(uri, "/active") {
+		return nil, fmt.Errorf("invalid URI format: %s", uri)
+	}
+	sessionID := strings.TrimSuffix(strings.TrimPrefix(uri, contextSessionURIPrefix), "/active")
+
+	s.contextSessionsMu.Lock()
+	cs, ok := s.contextSessions[sessionID]
+	var snapshot FloopActiveOutput
+	if ok {
+		snapshot.Context = cs.context
+		snapshot.Active = make([]BehaviorSummary, 0, len(cs.active))
+		for _, b := range cs.active {
+			snapshot.Active = append(snapshot.Active, b)
+		}
+	}
+	s.contextSessionsMu.Unlock()
+
+	if !ok {
+		return nil, sdk.ResourceNotFoundError(uri)
+	}
+
+	sort.Slice(snapshot.Active, func(i, j int) bool { return snapshot.Active[i].ID < snapshot.Active[j].ID })
+	snapshot.Count = len(snapshot.Active)
+
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session: %w", err)
+	}
+
+	return &sdk.ReadResourceResult{
+		Contents: []*sdk.ResourceContents{
+			{
+				URI:      uri,
+				MIMEType: "application/json",
+				Text:     string(data),
+			},
+		},
+	}, nil
+}
+
+// subscribeHandler accepts subscriptions to floop resources. The SDK tracks
+// subscribers; notifyContextChanged delivers the notifications.
+func subscribeHandler(ctx context.Context, req *sdk.SubscribeRequest) error {
+	if !strings.HasPrefix(req.Params.URI, "floop://") {
+		return fmt.Errorf("unknown resource: %s", req.Params.URI)
+	}
+	return nil
+}
+
+// unsubscribeHandler accepts unsubscriptions from floop resources.
+func unsubscribeHandler(ctx context.Context, req *sdk.UnsubscribeRequest) error {
+	return nil
+}
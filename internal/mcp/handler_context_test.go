@@ -0,0 +1,243 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestHandleFloopContext_Delta(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	req := &sdk.CallToolRequest{}
+
+	// Go-only behavior enters and leaves the active set with the language.
+	if _, err := server.store.AddNode(ctx, store.Node{
+		ID:   "b-go-only",
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name":    "go-only",
+			"kind":    "directive",
+			"when":    map[string]interface{}{"language": "go"},
+			"content": map[string]interface{}{"canonical": "Use gofmt"},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.8},
+	}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+
+	_, first, err := server.handleFloopContext(ctx, req, FloopContextInput{Language: "python"})
+	if err != nil {
+		t.Fatalf("register: %v", err)
+	}
+	if first.SessionID == "" || !first.Registered {
+		t.Fatalf("expected a newly registered session, got %+v", first)
+	}
+	if first.ResourceURI != contextSessionURI(first.SessionID) {
+		t.Errorf("ResourceURI = %q", first.ResourceURI)
+	}
+	if len(first.Added) != first.Count || first.Unchanged != 0 {
+		t.Errorf("first call should add everything: added=%d count=%d unchanged=%d",
+			len(first.Added), first.Count, first.Unchanged)
+	}
+
+	// Same context: nothing changes.
+	_, same, err := server.handleFloopContext(ctx, req, FloopContextInput{SessionID: first.SessionID, Language: "python"})
+	if err != nil {
+		t.Fatalf("same context: %v", err)
+	}
+	if same.Registered || len(same.Added) != 0 || len(same.Removed) != 0 || same.Unchanged != first.Count {
+		t.Errorf("expected empty delta, got %+v", same)
+	}
+
+	// Switch to Go: the Go behavior is added.
+	_, toGo, err := server.handleFloopContext(ctx, req, FloopContextInput{SessionID: first.SessionID, Language: "go"})
+	if err != nil {
+		t.Fatalf("switch to go: %v", err)
+	}
+	if !containsSummary(toGo.Added, "b-go-only") {
+		t.Errorf("expected b-go-only in Added, got %+v", toGo.Added)
+	}
+
+	// Switch back: it is removed.
+	_, back, err := server.handleFloopContext(ctx, req, FloopContextInput{SessionID: first.SessionID, Language: "python"})
+	if err != nil {
+		t.Fatalf("switch back: %v", err)
+	}
+	if len(back.Removed) != 1 || back.Removed[0] != "b-go-only" {
+		t.Errorf("Removed = %v, want [b-go-only]", back.Removed)
+	}
+}
+
+func TestHandleFloopContext_InvalidSessionID(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	_, _, err := server.handleFloopContext(context.Background(), &sdk.CallToolRequest{}, FloopContextInput{SessionID: "bad/id"})
+	if err == nil {
+		t.Error("expected error for invalid session_id")
+	}
+}
+
+func TestHandleFloopContext_EvictsOldest(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	now := time.Now()
+	for i := 0; i < maxContextSessions; i++ {
+		server.contextSessions[fmt.Sprintf("s-%d", i)] = &contextSession{updatedAt: now.Add(time.Duration(i) * time.Second)}
+	}
+
+	if _, _, err := server.handleFloopContext(context.Background(), &sdk.CallToolRequest{}, FloopContextInput{SessionID: "new"}); err != nil {
+		t.Fatalf("handleFloopContext: %v", err)
+	}
+	if len(server.contextSessions) != maxContextSessions {
+		t.Errorf("sessions = %d, want %d", len(server.contextSessions), maxContextSessions)
+	}
+	if _, ok := server.contextSessions["s-0"]; ok {
+		t.Error("expected oldest session to be evicted")
+	}
+	if _, ok := server.contextSessions["new"]; !ok {
+		t.Error("expected new session to be registered")
+	}
+}
+
+func TestHandleContextSessionResource(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	_, out, err := server.handleFloopContext(ctx, &sdk.CallToolRequest{}, FloopContextInput{SessionID: "agent-1"})
+	if err != nil {
+		t.Fatalf("handleFloopContext: %v", err)
+	}
+
+	res, err := server.handleContextSessionResource(ctx, &sdk.ReadResourceRequest{
+		Params: &sdk.ReadResourceParams{URI: out.ResourceURI},
+	})
+	if err != nil {
+		t.Fatalf("read resource: %v", err)
+	}
+	var snapshot FloopActiveOutput
+	if err := json.Unmarshal([]byte(res.Contents[0].Text), &snapshot); err != nil {
+		t.Fatalf("decode resource: %v", err)
+	}
+	if snapshot.Count != out.Count {
+		t.Errorf("resource Count = %d, want %d", snapshot.Count, out.Count)
+	}
+
+	if _, err := server.handleContextSessionResource(ctx, &sdk.ReadResourceRequest{
+		Params: &sdk.ReadResourceParams{URI: contextSessionURI("unknown")},
+	}); err == nil {
+		t.Error("expected error for unknown session")
+	}
+}
+
+func TestDiffActiveSets(t *testing.T) {
+	set := func(ids ...string) map[string]BehaviorSummary {
+		m := make(map[string]BehaviorSummary, len(ids))
+		for _, id := range ids {
+			m[id] = BehaviorSummary{ID: id}
+		}
+		return m
+	}
+
+	tests := []struct {
+		name          string
+		previous      map[string]BehaviorSummary
+		current       map[string]BehaviorSummary
+		wantAdded     []string
+		wantRemoved   []string
+		wantUnchanged int
+	}{
+		{"first call", nil, set("a", "b"), []string{"a", "b"}, nil, 0},
+		{"no change", set("a"), set("a"), nil, nil, 1},
+		{"swap", set("a", "b"), set("b", "c"), []string{"c"}, []string{"a"}, 1},
+		{"all removed", set("a", "b"), set(), nil, []string{"a", "b"}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, removed, unchanged := diffActiveSets(tt.previous, tt.current)
+			if len(added) != len(tt.wantAdded) {
+				t.Fatalf("added = %v, want %v", added, tt.wantAdded)
+			}
+			for i, id := range tt.wantAdded {
+				if added[i].ID != id {
+					t.Errorf("added[%d] = %s, want %s", i, added[i].ID, id)
+				}
+			}
+			if fmt.Sprint(removed) != fmt.Sprint(append([]string{}, tt.wantRemoved...)) {
+				t.Errorf("removed = %v, want %v", removed, tt.wantRemoved)
+			}
+			if unchanged != tt.wantUnchanged {
+				t.Errorf("unchanged = %d, want %d", unchanged, tt.wantUnchanged)
+			}
+		})
+	}
+}
+
+func containsSummary(list []BehaviorSummary, id string) bool {
+	for _, b := range list {
+		if b.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+func TestContextSessionNotificationOverHTTP(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ts := httptest.NewServer(server.httpHandler())
+	defer ts.Close()
+
+	updated := make(chan string, 8)
+	client := sdk.NewClient(&sdk.Implementation{Name: "test-agent", Version: "0.0.0"}, &sdk.ClientOptions{
+		ResourceUpdatedHandler: func(ctx context.Context, req *sdk.ResourceUpdatedNotificationRequest) {
+			updated <- req.Params.URI
+		},
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	cs, err := client.Connect(ctx, &sdk.StreamableClientTransport{Endpoint: ts.URL}, nil)
+	if err != nil {
+		t.Fatalf("connect: %v", err)
+	}
+	defer cs.Close()
+
+	res, err := cs.CallTool(ctx, &sdk.CallToolParams{
+		Name:      "floop_context",
+		Arguments: map[string]interface{}{"session_id": "agent-1", "task": "development"},
+	})
+	if err != nil || res.IsError {
+		t.Fatalf("floop_context: err=%v result=%+v", err, res)
+	}
+
+	uri := contextSessionURI("agent-1")
+	if err := cs.Subscribe(ctx, &sdk.SubscribeParams{URI: uri}); err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+
+	server.notifyContextChanged()
+
+	select {
+	case got := <-updated:
+		if got != uri {
+			t.Errorf("notified URI = %q, want %q", got, uri)
+		}
+	case <-ctx.Done():
+		t.Fatal("timed out waiting for resource update notification")
+	}
+}
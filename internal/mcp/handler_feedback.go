@@ -7,6 +7,7 @@ import (
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/session"
 )
 
 // handleFloopFeedback implements the floop_feedback tool.
@@ -65,6 +66,8 @@ func (s *Server) handleFloopFeedback(ctx context.Context, req *sdk.CallToolReque
 		}
 	}
 
+	s.trackSession(ctx, session.EventFeedback, args.BehaviorID)
+
 	message := fmt.Sprintf("Feedback recorded: behavior %s marked as %s", args.BehaviorID, args.Signal)
 
 	return nil, FloopFeedbackOutput{
@@ -11,13 +11,14 @@ import (
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/activation"
-	"github.com/nvandessel/floop/internal/backup"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/corrections"
 	"github.com/nvandessel/floop/internal/dedup"
 	"github.com/nvandessel/floop/internal/learning"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/ratelimit"
 	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/session"
 	"github.com/nvandessel/floop/internal/store"
 	"github.com/nvandessel/floop/internal/tagging"
 )
@@ -116,6 +117,22 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 	}
 	loopConfig.Deduplicator = dedup.NewStoreDeduplicator(s.store, merger, dedupConfig)
 
+	// Place using the global store's tuned similarity parameters, if any
+	if floopCfg := s.config(); floopCfg != nil {
+		if globalDir, err := store.GlobalFloopPath(); err == nil {
+			if tuning, ok := floopCfg.Similarity.Lookup(globalDir); ok {
+				loopConfig.SimilarityTuning = &tuning
+			}
+		}
+		loopConfig.HoldForReview = floopCfg.Review.HoldPending
+		if floopCfg.LLM.ExtractBehaviors && s.llmClient != nil {
+			loopConfig.LLMClient = s.llmClient
+			loopConfig.UseLLM = true
+		}
+	}
+	loopConfig.OpLogPath = filepath.Join(s.root, ".floop", learning.OpLogFile)
+	loopConfig.Metrics = s.metrics()
+
 	// Process correction through learning loop
 	loop := learning.NewLearningLoop(s.store, loopConfig)
 
@@ -130,24 +147,8 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 		return nil, FloopLearnOutput{}, fmt.Errorf("failed to sync store: %w", err)
 	}
 
-	// Auto-backup after successful learn (bounded background worker)
-	if s.backupConfig == nil || s.backupConfig.AutoBackup {
-		s.runBackground("auto-backup", func() {
-			backupDir, err := backup.DefaultBackupDir()
-			if err != nil {
-				s.logger.Warn("auto-backup failed (dir)", "error", err)
-				return
-			}
-			backupPath := backup.GenerateBackupPath(backupDir)
-			if _, err := backup.Backup(context.Background(), s.store, backupPath); err != nil {
-				s.logger.Warn("auto-backup failed", "error", err)
-				return
-			}
-			if _, err := backup.ApplyRetention(backupDir, s.retentionPolicy); err != nil {
-				s.logger.Warn("auto-backup retention failed", "error", err)
-			}
-		})
-	}
+	// Count the learn toward the automatic backup schedule (see autoBackupLoop)
+	s.recordAutoBackupLearn()
 
 	// Remove the displaced behavior's vector from the index when auto-merge
 	// deletes the existing behavior from the store. Without this, LanceDB's
@@ -160,7 +161,7 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 	}
 
 	// Background: embed the new/merged behavior for vector retrieval
-	if s.embedder != nil && s.embedder.Available() && learningResult.CandidateBehavior.ID != "" {
+	if s.embedder != nil && s.embedder.Available() && learningResult.CandidateBehavior.ID != "" && !learningResult.SkippedForgotten {
 		bid := learningResult.CandidateBehavior.ID
 		text := learningResult.CandidateBehavior.Content.Canonical
 		if text != "" {
@@ -182,6 +183,9 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 	// Debounced PageRank refresh after graph mutation
 	s.debouncedRefreshPageRank()
 
+	// Let context-session subscribers know their active sets may have changed
+	s.notifyContextChanged()
+
 	// Mark correction as processed and write to corrections log for audit trail
 	correction.Processed = true
 	processedAt := time.Now()
@@ -193,13 +197,26 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 		f.Close()
 	}
 	// Note: We don't fail if corrections.jsonl write fails - the behavior is already saved
+	s.runBackground("corrections-compact", func() {
+		if _, err := corrections.AutoCompact(filepath.Join(s.root, ".floop"), s.config().Corrections, time.Now()); err != nil {
+			s.logger.Warn("corrections compaction failed", "error", err)
+		}
+	})
+	s.trackSession(ctx, session.EventCorrection, correction.ID)
 
 	// Build result message with scope info
 	scope := string(learningResult.Scope)
 	message := fmt.Sprintf("Learned behavior (%s): %s", scope, learningResult.CandidateBehavior.Name)
-	if learningResult.MergedIntoExisting {
+	if learningResult.SkippedForgotten {
+		message = fmt.Sprintf("Not learned: matches forgotten behavior %s (restore it with 'floop restore %s')",
+			learningResult.ForgottenBehaviorID, learningResult.ForgottenBehaviorID)
+	} else if learningResult.MergedIntoExisting {
 		message = fmt.Sprintf("Merged into existing behavior (%s): %s (similarity: %.2f)",
 			scope, learningResult.MergedBehaviorID, learningResult.MergeSimilarity)
+	} else if learningResult.HeldForReview {
+		message = fmt.Sprintf("Behavior held for review (%s): %s (%s); it will not activate until approved with 'floop review approve %s'",
+			scope, learningResult.CandidateBehavior.Name,
+			strings.Join(learningResult.ReviewReasons, ", "), learningResult.CandidateBehavior.ID)
 	} else if learningResult.RequiresReview {
 		message = fmt.Sprintf("Behavior requires review (%s): %s (%s)",
 			scope, learningResult.CandidateBehavior.Name,
@@ -214,8 +231,10 @@ func (s *Server) handleFloopLearn(ctx context.Context, req *sdk.CallToolRequest,
 		Confidence:      learningResult.Placement.Confidence,
 		RequiresReview:  learningResult.RequiresReview,
 		ReviewReasons:   learningResult.ReviewReasons,
+		HeldForReview:   learningResult.HeldForReview,
 		MergedIntoID:    learningResult.MergedBehaviorID,
 		MergeSimilarity: learningResult.MergeSimilarity,
+		ForgottenID:     learningResult.ForgottenBehaviorID,
 		Message:         message,
 	}, nil
 }
@@ -12,6 +12,7 @@ import (
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/store"
 )
 
 // handleFloopList implements the floop_list tool.
@@ -19,7 +20,7 @@ func (s *Server) handleFloopList(ctx context.Context, req *sdk.CallToolRequest,
 	start := time.Now()
 	defer func() {
 		s.auditTool("floop_list", start, retErr, sanitizeToolParams("floop_list", map[string]interface{}{
-			"corrections": args.Corrections, "tag": args.Tag,
+			"corrections": args.Corrections, "tag": args.Tag, "limit": args.Limit, "cursor": args.Cursor,
 		}), "local")
 	}()
 
@@ -68,30 +69,23 @@ func (s *Server) handleFloopList(ctx context.Context, req *sdk.CallToolRequest,
 		}, nil
 	}
 
-	// List behaviors
-	nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": "behavior"})
+	// List behaviors, one page at a time when a limit is given
+	if args.Limit < 0 {
+		return nil, FloopListOutput{}, fmt.Errorf("limit must be non-negative")
+	}
+	predicate := map[string]interface{}{"kind": "behavior"}
+	if args.Tag != "" {
+		predicate[store.PredicateTag] = args.Tag
+	}
+	page, err := store.QueryPage(ctx, s.store, predicate, store.PageOptions{Limit: args.Limit, Cursor: args.Cursor})
 	if err != nil {
 		return nil, FloopListOutput{}, fmt.Errorf("failed to query behaviors: %w", err)
 	}
 
-	behaviors := make([]BehaviorListItem, 0, len(nodes))
-	for _, node := range nodes {
+	behaviors := make([]BehaviorListItem, 0, len(page.Nodes))
+	for _, node := range page.Nodes {
 		behavior := models.NodeToBehavior(node)
 
-		// Filter by tag if specified
-		if args.Tag != "" {
-			found := false
-			for _, t := range behavior.Content.Tags {
-				if t == args.Tag {
-					found = true
-					break
-				}
-			}
-			if !found {
-				continue
-			}
-		}
-
 		// Determine source
 		source := "unknown"
 		if behavior.Provenance.SourceType != "" {
@@ -110,7 +104,8 @@ func (s *Server) handleFloopList(ctx context.Context, req *sdk.CallToolRequest,
 	}
 
 	return nil, FloopListOutput{
-		Behaviors: behaviors,
-		Count:     len(behaviors),
+		Behaviors:  behaviors,
+		Count:      len(behaviors),
+		NextCursor: page.NextCursor,
 	}, nil
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
@@ -36,7 +37,7 @@ func (s *Server) handleFloopPackInstall(ctx context.Context, req *sdk.CallToolRe
 		return nil, FloopPackInstallOutput{}, fmt.Errorf("source is required (or use deprecated file_path)")
 	}
 
-	cfg := s.floopConfig
+	cfg := s.config()
 	if cfg == nil {
 		return nil, FloopPackInstallOutput{}, fmt.Errorf("config not available")
 	}
@@ -68,8 +69,12 @@ func (s *Server) handleFloopPackInstall(ctx context.Context, req *sdk.CallToolRe
 			return nil, FloopPackInstallOutput{}, fmt.Errorf("pack install failed: %w", err)
 		}
 
-	case pack.SourceHTTP, pack.SourceGitHub:
-		// Remote sources bypass path validation, go through InstallFromSource
+	case pack.SourceHTTP, pack.SourceGitHub, pack.SourceGitLab, pack.SourceGit, pack.SourceRegistry:
+		// Remote sources bypass path validation, go through InstallFromSource.
+		// A file:// git repository is local, so it is not one of them.
+		if resolved.Kind == pack.SourceGit && strings.HasPrefix(resolved.URL, "file://") {
+			return nil, FloopPackInstallOutput{}, fmt.Errorf("pack install source rejected: local git repositories are not allowed")
+		}
 		results, err := pack.InstallFromSource(ctx, s.store, source, cfg, pack.InstallFromSourceOptions{
 			DeriveEdges: true,
 		})
@@ -86,6 +91,21 @@ func (s *Server) handleFloopPackInstall(ctx context.Context, req *sdk.CallToolRe
 	if saveErr := cfg.Save(); saveErr != nil {
 		s.logger.Warn("failed to save config", "error", saveErr)
 	}
+	if lockErr := pack.UpdateLock(s.root, func(lock *pack.Lockfile) { lock.Record(result, s.root) }); lockErr != nil {
+		s.logger.Warn("failed to update pack lockfile", "error", lockErr)
+	}
+
+	collisions := make([]PackCollision, len(result.Collisions))
+	for i, c := range result.Collisions {
+		collisions[i] = PackCollision{
+			Kind:          c.Kind,
+			PackBehavior:  c.PackBehavior,
+			LocalBehavior: c.LocalBehavior,
+			Similarity:    c.Similarity,
+			Action:        c.Action,
+			InstalledAs:   c.InstalledAs,
+		}
+	}
 
 	return nil, FloopPackInstallOutput{
 		PackID:       result.PackID,
@@ -96,6 +116,7 @@ func (s *Server) handleFloopPackInstall(ctx context.Context, req *sdk.CallToolRe
 		EdgesAdded:   result.EdgesAdded,
 		EdgesSkipped: result.EdgesSkipped,
 		DerivedEdges: result.DerivedEdges,
+		Collisions:   collisions,
 		Message:      fmt.Sprintf("Installed %s v%s: %d added, %d updated, %d skipped", result.PackID, result.Version, len(result.Added), len(result.Updated), len(result.Skipped)),
 	}, nil
 }
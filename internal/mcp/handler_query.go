@@ -0,0 +1,124 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/ratelimit"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// handleFloopShow implements the floop_show tool.
+func (s *Server) handleFloopShow(ctx context.Context, req *sdk.CallToolRequest, args FloopShowInput) (_ *sdk.CallToolResult, _ FloopShowOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_show", start, retErr, sanitizeToolParams("floop_show", map[string]interface{}{
+			"behavior_id": args.BehaviorID,
+		}), "local")
+	}()
+
+	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_show"); err != nil {
+		return nil, FloopShowOutput{}, err
+	}
+
+	b, err := s.findBehavior(ctx, args.BehaviorID)
+	if err != nil {
+		return nil, FloopShowOutput{}, err
+	}
+
+	return nil, FloopShowOutput{
+		ID:           b.ID,
+		Name:         b.Name,
+		Kind:         string(b.Kind),
+		Confidence:   b.Confidence,
+		Priority:     b.Priority,
+		Canonical:    b.Content.Canonical,
+		Summary:      b.Content.Summary,
+		Tags:         b.Content.Tags,
+		When:         b.When,
+		Source:       string(b.Provenance.SourceType),
+		CreatedAt:    b.Provenance.CreatedAt,
+		CorrectionID: b.Provenance.CorrectionID,
+		Requires:     b.Requires,
+		Overrides:    b.Overrides,
+		Conflicts:    b.Conflicts,
+	}, nil
+}
+
+// handleFloopWhy implements the floop_why tool.
+func (s *Server) handleFloopWhy(ctx context.Context, req *sdk.CallToolRequest, args FloopWhyInput) (_ *sdk.CallToolResult, _ FloopWhyOutput, retErr error) {
+	start := time.Now()
+	defer func() {
+		s.auditTool("floop_why", start, retErr, sanitizeToolParams("floop_why", map[string]interface{}{
+			"behavior_id": args.BehaviorID, "file": args.File, "task": args.Task, "language": args.Language,
+		}), "local")
+	}()
+
+	if err := ratelimit.CheckLimit(s.toolLimiters, "floop_why"); err != nil {
+		return nil, FloopWhyOutput{}, err
+	}
+
+	b, err := s.findBehavior(ctx, args.BehaviorID)
+	if err != nil {
+		return nil, FloopWhyOutput{}, err
+	}
+
+	actCtx, err := s.buildContext(args.File, args.Task, args.Language, args.Context)
+	if err != nil {
+		return nil, FloopWhyOutput{}, err
+	}
+	explanation := activation.NewEvaluator().WhyActive(actCtx, *b)
+
+	conditions := make([]WhyCondition, 0, len(explanation.Conditions))
+	for _, c := range explanation.Conditions {
+		conditions = append(conditions, WhyCondition{
+			Field:    c.Field,
+			Required: c.Required,
+			Actual:   c.Actual,
+			Status:   c.Status,
+			Detail:   c.Detail,
+		})
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i].Field < conditions[j].Field })
+
+	return nil, FloopWhyOutput{
+		BehaviorID: b.ID,
+		Name:       b.Name,
+		Active:     explanation.IsActive,
+		Reason:     explanation.Reason,
+		Conditions: conditions,
+	}, nil
+}
+
+// findBehavior looks up a behavior by ID, falling back to an exact name match.
+func (s *Server) findBehavior(ctx context.Context, idOrName string) (*models.Behavior, error) {
+	if idOrName == "" {
+		return nil, fmt.Errorf("'behavior_id' parameter is required")
+	}
+
+	node, err := s.store.GetNode(ctx, idOrName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up behavior: %w", err)
+	}
+	if node != nil && node.Kind == store.NodeKindBehavior {
+		b := models.NodeToBehavior(*node)
+		return &b, nil
+	}
+
+	nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	for _, n := range nodes {
+		b := models.NodeToBehavior(n)
+		if b.Name == idOrName {
+			return &b, nil
+		}
+	}
+	return nil, fmt.Errorf("behavior not found: %s", idOrName)
+}
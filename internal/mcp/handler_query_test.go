@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestHandleFloopShow(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	addTestBehavior(t, server, "show-test-1")
+
+	ctx := context.Background()
+	for _, ref := range []string{"show-test-1", "test-behavior-show-test-1"} {
+		_, out, err := server.handleFloopShow(ctx, &sdk.CallToolRequest{}, FloopShowInput{BehaviorID: ref})
+		if err != nil {
+			t.Fatalf("handleFloopShow(%q) failed: %v", ref, err)
+		}
+		if out.ID != "show-test-1" || out.Canonical != "Test behavior show-test-1" {
+			t.Errorf("handleFloopShow(%q) = %+v", ref, out)
+		}
+	}
+
+	if _, _, err := server.handleFloopShow(ctx, &sdk.CallToolRequest{}, FloopShowInput{BehaviorID: "missing"}); err == nil {
+		t.Error("expected error for unknown behavior")
+	}
+	if _, _, err := server.handleFloopShow(ctx, &sdk.CallToolRequest{}, FloopShowInput{}); err == nil {
+		t.Error("expected error for empty behavior_id")
+	}
+}
+
+func TestHandleFloopWhy(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	node := store.Node{
+		ID:   "why-go",
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "go-only",
+			"kind":    string(models.BehaviorKindDirective),
+			"content": map[string]interface{}{"canonical": "Use gofmt"},
+			"when":    map[string]interface{}{"language": "go"},
+			"provenance": models.Provenance{
+				SourceType: models.SourceTypeLearned,
+				CreatedAt:  time.Now(),
+			},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.8},
+	}
+	if _, err := server.store.AddNode(ctx, node); err != nil {
+		t.Fatalf("AddNode failed: %v", err)
+	}
+
+	tests := []struct {
+		language   string
+		wantActive bool
+		wantStatus string
+	}{
+		{"go", true, "confirmed"},
+		{"python", false, "contradicted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.language, func(t *testing.T) {
+			_, out, err := server.handleFloopWhy(ctx, &sdk.CallToolRequest{}, FloopWhyInput{BehaviorID: "why-go", Language: tt.language})
+			if err != nil {
+				t.Fatalf("handleFloopWhy failed: %v", err)
+			}
+			if out.Active != tt.wantActive {
+				t.Errorf("Active = %v, want %v (reason: %s)", out.Active, tt.wantActive, out.Reason)
+			}
+			if len(out.Conditions) != 1 || out.Conditions[0].Status != tt.wantStatus {
+				t.Errorf("Conditions = %+v, want one %s condition", out.Conditions, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestHandleFloopWhy_CustomContext(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	node := store.Node{
+		ID:   "why-team",
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "payments-only",
+			"kind":    string(models.BehaviorKindDirective),
+			"content": map[string]interface{}{"canonical": "Log every ledger write"},
+			"when":    map[string]interface{}{"team": "payments"},
+		},
+	}
+	if _, err := server.store.AddNode(ctx, node); err != nil {
+		t.Fatalf("AddNode failed: %v", err)
+	}
+
+	tests := []struct {
+		context    map[string]string
+		wantStatus string
+	}{
+		{map[string]string{"team": "payments"}, "confirmed"},
+		{map[string]string{"team": "billing"}, "contradicted"},
+		{nil, "absent"},
+	}
+	for _, tt := range tests {
+		_, out, err := server.handleFloopWhy(ctx, &sdk.CallToolRequest{}, FloopWhyInput{BehaviorID: "why-team", Context: tt.context})
+		if err != nil {
+			t.Fatalf("handleFloopWhy(%v) failed: %v", tt.context, err)
+		}
+		if len(out.Conditions) != 1 || out.Conditions[0].Status != tt.wantStatus {
+			t.Errorf("context %v: Conditions = %+v, want one %s condition", tt.context, out.Conditions, tt.wantStatus)
+		}
+	}
+
+	if _, _, err := server.handleFloopWhy(ctx, &sdk.CallToolRequest{}, FloopWhyInput{BehaviorID: "why-team", Context: map[string]string{"task": "testing"}}); err == nil {
+		t.Error("expected error for built-in context key")
+	}
+}
@@ -35,8 +35,9 @@ func (s *Server) handleBehaviorsResource(ctx context.Context, req *sdk.ReadResou
 	}
 
 	// Evaluate which behaviors are active
+	profile := s.profile()
 	evaluator := activation.NewEvaluator()
-	matches := evaluator.Evaluate(actCtx, behaviors)
+	matches := evaluator.Evaluate(actCtx, profile.Filter(behaviors))
 
 	// Resolve conflicts and get final active set
 	resolver := activation.NewResolver()
@@ -54,10 +55,16 @@ func (s *Server) handleBehaviorsResource(ctx context.Context, req *sdk.ReadResou
 		}, nil
 	}
 
+	// Rank with graph centrality from the cached PageRank
+	scorerConfig := s.config().ScorerConfig(profile)
+	s.pageRankMu.RLock()
+	scorerConfig.PageRank = s.pageRankCache
+	s.pageRankMu.RUnlock()
+
 	// Create tiered injection plan via bridge → ActivationTierMapper
-	results, behaviorMap := tiering.BehaviorsToResults(result.Active)
-	mapper := tiering.NewActivationTierMapper(tiering.DefaultActivationTierConfig())
-	plan := mapper.MapResults(results, behaviorMap, s.floopConfig.TokenBudget.Default)
+	results, behaviorMap := tiering.BehaviorsToResultsWithConfig(result.Active, scorerConfig)
+	mapper := tiering.NewActivationTierMapper(profile.TierConfig())
+	plan := mapper.MapResults(results, behaviorMap, profile.Budget(s.config().TokenBudget.Default))
 
 	// Compile tiered prompt
 	compiler := assembly.NewCompiler()
@@ -12,12 +12,30 @@ func (s *Server) registerTools() error {
 		Description: "Get active behaviors for the current context (file, task, environment)",
 	}, s.handleFloopActive)
 
+	// Register floop_context tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_context",
+		Description: "Report a context change (file switched, task changed) for an agent session and get back only the behaviors that became active or inactive since the last call",
+	}, s.handleFloopContext)
+
 	// Register floop_learn tool
 	sdk.AddTool(s.server, &sdk.Tool{
 		Name:        "floop_learn",
 		Description: "Capture a correction and extract a reusable behavior",
 	}, s.handleFloopLearn)
 
+	// Register floop_show tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_show",
+		Description: "Show full details of a behavior by ID or name",
+	}, s.handleFloopShow)
+
+	// Register floop_why tool
+	sdk.AddTool(s.server, &sdk.Tool{
+		Name:        "floop_why",
+		Description: "Explain why a behavior is or isn't active for a context (file, task, language)",
+	}, s.handleFloopWhy)
+
 	// Register floop_list tool
 	sdk.AddTool(s.server, &sdk.Tool{
 		Name:        "floop_list",
@@ -69,7 +87,7 @@ func (s *Server) registerTools() error {
 	// Register floop_pack_install tool
 	sdk.AddTool(s.server, &sdk.Tool{
 		Name:        "floop_pack_install",
-		Description: "Install a skill pack from a local path, URL, or GitHub shorthand (gh:owner/repo)",
+		Description: "Install a skill pack from a local path, URL, GitHub or GitLab shorthand (gh:owner/repo, gl:group/project), git repository (git+https://...), or registry",
 	}, s.handleFloopPackInstall)
 
 	// Register floop_observe tool
@@ -106,5 +124,13 @@ func (s *Server) registerResources() error {
 		MIMEType:    "text/markdown",
 	}, s.handleBehaviorExpandResource)
 
+	// Register per-session active set for context change subscriptions
+	s.server.AddResourceTemplate(&sdk.ResourceTemplate{
+		URITemplate: "floop://sessions/{id}/active",
+		Name:        "floop-session-active",
+		Description: "Active behaviors last delivered to a floop_context session. Subscribe to be notified when the behavior graph changes.",
+		MIMEType:    "application/json",
+	}, s.handleContextSessionResource)
+
 	return nil
 }
@@ -11,6 +11,7 @@ import (
 
 	sdk "github.com/modelcontextprotocol/go-sdk/mcp"
 	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/spreading"
 	"github.com/nvandessel/floop/internal/store"
@@ -366,6 +367,59 @@ func TestHandleFloopList_Behaviors(t *testing.T) {
 	// so we just verify the behavior was found
 }
 
+func TestHandleFloopList_Paged(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, id := range []string{"page-a", "page-b", "page-c"} {
+		node := store.Node{
+			ID:   id,
+			Kind: "behavior",
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": "Paged behavior " + id},
+			},
+			Metadata: map[string]interface{}{"confidence": 0.8},
+		}
+		if _, err := server.store.AddNode(ctx, node); err != nil {
+			t.Fatalf("Failed to add test behavior: %v", err)
+		}
+	}
+
+	seen := make(map[string]int)
+	args := FloopListInput{Limit: 2}
+	for pages := 0; ; pages++ {
+		if pages > 10 {
+			t.Fatal("paging did not terminate")
+		}
+		_, output, err := server.handleFloopList(ctx, &sdk.CallToolRequest{}, args)
+		if err != nil {
+			t.Fatalf("handleFloopList failed: %v", err)
+		}
+		if output.Count > 2 {
+			t.Errorf("page of %d behaviors, limit 2", output.Count)
+		}
+		for _, b := range output.Behaviors {
+			seen[b.ID]++
+		}
+		if output.NextCursor == "" {
+			break
+		}
+		args.Cursor = output.NextCursor
+	}
+	for _, id := range []string{"page-a", "page-b", "page-c"} {
+		if seen[id] != 1 {
+			t.Errorf("behavior %s listed %d times across pages, want 1", id, seen[id])
+		}
+	}
+
+	if _, _, err := server.handleFloopList(ctx, &sdk.CallToolRequest{}, FloopListInput{Limit: -1}); err == nil {
+		t.Error("handleFloopList(limit -1) succeeded, want error")
+	}
+}
+
 func TestHandleFloopList_Corrections(t *testing.T) {
 	server, tmpDir := setupTestServer(t)
 	defer server.Close()
@@ -429,6 +483,58 @@ func TestHandleFloopList_Corrections(t *testing.T) {
 	}
 }
 
+func TestHandleFloopActive_ShedsLoadOverBudget(t *testing.T) {
+	server, _ := setupTestServer(t)
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, id := range []string{"shed-a", "shed-b", "shed-c"} {
+		node := store.Node{
+			ID:   id,
+			Kind: "behavior",
+			Content: map[string]interface{}{
+				"name":    id,
+				"kind":    "directive",
+				"content": map[string]interface{}{"canonical": "Behavior " + id},
+			},
+			Metadata: map[string]interface{}{"confidence": 0.8},
+		}
+		if _, err := server.store.AddNode(ctx, node); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+
+	server.configMu.Lock()
+	server.floopConfig.Activation.TimeBudget = time.Nanosecond
+	server.floopConfig.Activation.ShedTopN = 1
+	server.configMu.Unlock()
+	server.pageRankMu.Lock()
+	server.pageRankCache = map[string]float64{"shed-b": 1.0}
+	server.pageRankMu.Unlock()
+
+	_, output, err := server.handleFloopActive(ctx, &sdk.CallToolRequest{}, FloopActiveInput{Task: "development"})
+	if err != nil {
+		t.Fatalf("handleFloopActive failed: %v", err)
+	}
+	if !output.Degraded {
+		t.Error("Degraded = false, want true when over the time budget")
+	}
+	if output.Count != 1 || output.Active[0].ID != "shed-b" {
+		t.Errorf("active = %+v, want only shed-b (top by PageRank)", output.Active)
+	}
+
+	server.configMu.Lock()
+	server.floopConfig.Activation.TimeBudget = time.Hour
+	server.configMu.Unlock()
+	_, output, err = server.handleFloopActive(ctx, &sdk.CallToolRequest{}, FloopActiveInput{Task: "development"})
+	if err != nil {
+		t.Fatalf("handleFloopActive failed: %v", err)
+	}
+	if output.Degraded || output.Count < 3 {
+		t.Errorf("within budget: degraded=%v count=%d, want full activation", output.Degraded, output.Count)
+	}
+}
+
 func TestHandleFloopActive_SpreadingActivation(t *testing.T) {
 	server, tmpDir := setupTestServer(t)
 	defer server.Close()
@@ -1262,3 +1368,28 @@ func TestHandleBehaviorsResource_EmptyStoreFraming(t *testing.T) {
 		})
 	}
 }
+
+func TestHandleFloopRestore_ProtectedReplace(t *testing.T) {
+	server, tmpDir := setupTestServer(t)
+	defer server.Close()
+
+	backupDir := filepath.Join(tmpDir, ".floop", "backups")
+	if err := os.MkdirAll(backupDir, 0700); err != nil {
+		t.Fatalf("Failed to create backup dir: %v", err)
+	}
+	backupPath := filepath.Join(backupDir, "restore.json")
+	if err := os.WriteFile(backupPath, []byte("{}"), 0600); err != nil {
+		t.Fatalf("Failed to write backup: %v", err)
+	}
+
+	t.Setenv(config.EnvAllowProtected, "")
+	server.floopConfig.Safety.ProtectedOperations = []string{config.OpRestoreReplace}
+
+	_, _, err := server.handleFloopRestore(context.Background(), nil, FloopRestoreInput{
+		InputPath: backupPath,
+		Mode:      "replace",
+	})
+	if err == nil || !strings.Contains(err.Error(), "protected") {
+		t.Errorf("expected protected-operation error, got %v", err)
+	}
+}
@@ -0,0 +1,196 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/metrics"
+)
+
+// configPollInterval is how often the config file is checked for changes.
+const configPollInterval = 2 * time.Second
+
+// adminReloadPath is the HTTP endpoint that triggers a config reload.
+const adminReloadPath = "/admin/reload"
+
+// restartRequiredPrefixes are config sections read only at startup. Changes
+// to them are applied to the stored config but take effect after a restart.
+var restartRequiredPrefixes = []string{"llm."}
+
+// config returns the current floop configuration. Reloads replace the
+// pointer rather than mutating it, so callers may keep the returned value.
+func (s *Server) config() *config.FloopConfig {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.floopConfig
+}
+
+// metrics returns the recorder for local usage metrics, or nil unless
+// metrics.enabled is set in the current config.
+func (s *Server) metrics() *metrics.Recorder {
+	if cfg := s.config(); cfg == nil || !cfg.Metrics.Enabled {
+		return nil
+	}
+	return s.metricsRecorder
+}
+
+// profile returns the agent profile the server was started with, or the
+// configured default, resolved against the current config. A profile
+// removed by a reload falls back to no profile.
+func (s *Server) profile() config.ProfileConfig {
+	cfg := s.config()
+	if cfg == nil {
+		return config.ProfileConfig{}
+	}
+	p, err := cfg.ResolveProfile(s.profileName)
+	if err != nil {
+		s.logger.Warn("agent profile unavailable, using defaults", "error", err)
+	}
+	return p
+}
+
+// backupSettings returns the current backup configuration and retention policy.
+func (s *Server) backupSettings() (*config.BackupConfig, backup.RetentionPolicy) {
+	s.configMu.RLock()
+	defer s.configMu.RUnlock()
+	return s.backupConfig, s.retentionPolicy
+}
+
+// ReloadConfig loads and validates the config file and, if valid, swaps it
+// in for the running server. On error the current config is kept. The
+// returned changes list which settings differ from the previous config.
+func (s *Server) ReloadConfig() ([]config.Change, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	cfg, err := config.LoadForProject(s.root)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
+	changes, err := config.Diff(s.config(), cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	s.configMu.Lock()
+	s.floopConfig = cfg
+	s.backupConfig = &cfg.Backup
+	s.retentionPolicy = buildRetentionPolicy(&cfg.Backup)
+	s.configMu.Unlock()
+
+	logConfigChanges(os.Stderr, changes)
+	return changes, nil
+}
+
+// logConfigChanges writes a changelog entry for a reload to w, the server log.
+func logConfigChanges(w io.Writer, changes []config.Change) {
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "floop: config reloaded (no changes)")
+		return
+	}
+	fmt.Fprintf(w, "floop: config reloaded, %d setting(s) changed:\n", len(changes))
+	for _, c := range changes {
+		note := ""
+		if requiresRestart(c.Key) {
+			note = " (takes effect after restart)"
+		}
+		fmt.Fprintf(w, "floop:   %s%s\n", c, note)
+	}
+}
+
+func requiresRestart(key string) bool {
+	for _, prefix := range restartRequiredPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchConfig reloads the config when the global or project config file
+// changes or, on Unix, when the process receives SIGHUP. It returns when ctx
+// is cancelled.
+func (s *Server) watchConfig(ctx context.Context) {
+	reload := func(trigger string) {
+		if _, err := s.ReloadConfig(); err != nil {
+			s.logger.Warn("config reload rejected, keeping current config", "trigger", trigger, "error", err)
+		}
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	notifyReloadSignals(sigChan)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sigChan:
+				reload("signal")
+			}
+		}
+	}()
+
+	go config.Watch(ctx, config.ProjectPath(s.root), configPollInterval, func() { reload("project file") })
+
+	path, err := config.DefaultPath()
+	if err != nil {
+		s.logger.Warn("config file watching disabled", "error", err)
+		return
+	}
+	config.Watch(ctx, path, configPollInterval, func() { reload("file") })
+}
+
+// handleAdminReload serves POST /admin/reload. Only loopback clients may
+// trigger a reload.
+func (s *Server) handleAdminReload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !isLoopback(r.RemoteAddr) {
+		http.Error(w, "forbidden", http.StatusForbidden)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	changes, err := s.ReloadConfig()
+	if err != nil {
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": "rejected",
+			"error":  err.Error(),
+		})
+		return
+	}
+	if changes == nil {
+		changes = []config.Change{}
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":  "reloaded",
+		"changes": changes,
+	})
+}
+
+// isLoopback reports whether remoteAddr is a loopback address.
+func isLoopback(remoteAddr string) bool {
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		host = remoteAddr
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
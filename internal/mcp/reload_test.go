@@ -0,0 +1,157 @@
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+)
+
+// writeUserConfig writes ~/.floop/config.yaml under the test server's HOME.
+func writeUserConfig(t *testing.T, contents string) {
+	t.Helper()
+	path, err := config.DefaultPath()
+	if err != nil {
+		t.Fatalf("DefaultPath: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReloadConfig(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	writeUserConfig(t, "token_budget:\n  default: 4321\nbackup:\n  auto_backup: false\n")
+	changes, err := server.ReloadConfig()
+	if err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	if got := server.config().TokenBudget.Default; got != 4321 {
+		t.Errorf("TokenBudget.Default = %d, want 4321", got)
+	}
+	if backupCfg, _ := server.backupSettings(); backupCfg.AutoBackup {
+		t.Error("backup settings were not swapped")
+	}
+
+	found := false
+	for _, c := range changes {
+		if c.Key == "token_budget.default" && c.New == "4321" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("changes missing token_budget.default: %v", changes)
+	}
+}
+
+func TestReloadConfig_RedactsSecrets(t *testing.T) {
+	server, _ := setupTestServer(t)
+
+	const token = "ghp_reloadsecret123456"
+	writeUserConfig(t, "packs:\n  github_token: "+token+"\n")
+	changes, err := server.ReloadConfig()
+	if err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	if len(changes) == 0 {
+		t.Fatal("expected the token change to be reported")
+	}
+
+	var out bytes.Buffer
+	logConfigChanges(&out, changes)
+	if strings.Contains(out.String(), token) {
+		t.Errorf("reload log leaks the token:\n%s", out.String())
+	}
+}
+
+func TestReloadConfig_ProjectConfig(t *testing.T) {
+	server, root := setupTestServer(t)
+
+	if err := os.WriteFile(config.ProjectPath(root), []byte("token_budget:\n  default: 2468\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.ReloadConfig(); err != nil {
+		t.Fatalf("ReloadConfig: %v", err)
+	}
+	if got := server.config().TokenBudget.Default; got != 2468 {
+		t.Errorf("TokenBudget.Default = %d, want the project override 2468", got)
+	}
+}
+
+func TestReloadConfig_InvalidKeepsCurrent(t *testing.T) {
+	server, _ := setupTestServer(t)
+	before := server.config()
+
+	writeUserConfig(t, "token_budget:\n  default: -1\n")
+	if _, err := server.ReloadConfig(); err == nil {
+		t.Fatal("expected validation error")
+	}
+	if server.config() != before {
+		t.Error("invalid config replaced the running config")
+	}
+
+	writeUserConfig(t, "token_budget: [not, a, map\n")
+	if _, err := server.ReloadConfig(); err == nil {
+		t.Fatal("expected parse error")
+	}
+	if server.config() != before {
+		t.Error("unparseable config replaced the running config")
+	}
+}
+
+func TestHandleAdminReload(t *testing.T) {
+	server, _ := setupTestServer(t)
+	ts := httptest.NewServer(server.httpHandler())
+	defer ts.Close()
+
+	writeUserConfig(t, "token_budget:\n  default: 1234\n")
+	resp, err := http.Post(ts.URL+adminReloadPath, "application/json", nil)
+	if err != nil {
+		t.Fatalf("POST %s: %v", adminReloadPath, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want 200", resp.StatusCode)
+	}
+	var body struct {
+		Status  string          `json:"status"`
+		Changes []config.Change `json:"changes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if body.Status != "reloaded" || len(body.Changes) == 0 {
+		t.Errorf("unexpected response: %+v", body)
+	}
+	if got := server.config().TokenBudget.Default; got != 1234 {
+		t.Errorf("TokenBudget.Default = %d, want 1234", got)
+	}
+
+	getResp, err := http.Get(ts.URL + adminReloadPath)
+	if err != nil {
+		t.Fatalf("GET %s: %v", adminReloadPath, err)
+	}
+	getResp.Body.Close()
+	if getResp.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("GET status = %d, want 405", getResp.StatusCode)
+	}
+
+	// Remote clients are refused.
+	req := httptest.NewRequest(http.MethodPost, adminReloadPath, nil)
+	req.RemoteAddr = "203.0.113.7:5555"
+	rec := httptest.NewRecorder()
+	server.handleAdminReload(rec, req)
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("remote status = %d, want 403", rec.Code)
+	}
+}
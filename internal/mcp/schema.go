@@ -7,9 +7,10 @@ import (
 
 // FloopActiveInput defines the input for floop_active tool.
 type FloopActiveInput struct {
-	File     string `json:"file,omitempty" jsonschema:"Current file path (relative to project root)"`
-	Task     string `json:"task,omitempty" jsonschema:"Current task type (e.g. 'development', 'testing', 'refactoring')"`
-	Language string `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+	File     string            `json:"file,omitempty" jsonschema:"Current file path (relative to project root)"`
+	Task     string            `json:"task,omitempty" jsonschema:"Current task type (e.g. 'development', 'testing', 'refactoring')"`
+	Language string            `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+	Context  map[string]string `json:"context,omitempty" jsonschema:"Custom context values for organization-defined when conditions (e.g. {\"team\": \"payments\"})"`
 }
 
 // TokenStats provides token budget awareness for active behaviors.
@@ -29,6 +30,29 @@ type FloopActiveOutput struct {
 	Active     []BehaviorSummary      `json:"active" jsonschema:"List of active behaviors"`
 	Count      int                    `json:"count" jsonschema:"Number of active behaviors"`
 	TokenStats *TokenStats            `json:"token_stats,omitempty"`
+	Degraded   bool                   `json:"degraded,omitempty" jsonschema:"True if activation exceeded its time budget and shed load: the active set is the top behaviors by cached PageRank and priority"`
+}
+
+// FloopContextInput defines the input for floop_context tool.
+type FloopContextInput struct {
+	SessionID string            `json:"session_id,omitempty" jsonschema:"Session ID from a previous floop_context call. Omit to register a new session"`
+	File      string            `json:"file,omitempty" jsonschema:"Current file path (relative to project root)"`
+	Task      string            `json:"task,omitempty" jsonschema:"Current task type (e.g. 'development', 'testing', 'refactoring')"`
+	Language  string            `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+	Context   map[string]string `json:"context,omitempty" jsonschema:"Custom context values for organization-defined when conditions (e.g. {\"team\": \"payments\"})"`
+}
+
+// FloopContextOutput defines the output for floop_context tool.
+type FloopContextOutput struct {
+	SessionID   string                 `json:"session_id" jsonschema:"Session ID to pass on subsequent calls"`
+	ResourceURI string                 `json:"resource_uri" jsonschema:"Resource to subscribe to for notifications when the active set may have changed"`
+	Registered  bool                   `json:"registered" jsonschema:"True if this call registered a new session"`
+	Context     map[string]interface{} `json:"context" jsonschema:"Context used for activation"`
+	Added       []BehaviorSummary      `json:"added" jsonschema:"Behaviors that became active since the previous call"`
+	Removed     []string               `json:"removed" jsonschema:"IDs of behaviors that are no longer active"`
+	Unchanged   int                    `json:"unchanged" jsonschema:"Number of behaviors still active from the previous call"`
+	Count       int                    `json:"count" jsonschema:"Total number of active behaviors"`
+	Degraded    bool                   `json:"degraded,omitempty" jsonschema:"True if activation exceeded its time budget and shed load"`
 }
 
 // BehaviorSummary provides a simplified view of a behavior.
@@ -49,7 +73,7 @@ type BehaviorSummary struct {
 // FloopLearnInput defines the input for floop_learn tool.
 type FloopLearnInput struct {
 	Wrong     string   `json:"wrong,omitempty" jsonschema:"What the agent did (optional, stored as provenance only)"`
-	Right     string   `json:"right" jsonschema:"What should have been done instead,required"`
+	Right     string   `json:"right" jsonschema:"What should have been done instead"`
 	File      string   `json:"file,omitempty" jsonschema:"Relevant file path for context"`
 	Task      string   `json:"task,omitempty" jsonschema:"Current task type for context"`
 	Language  string   `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
@@ -66,8 +90,10 @@ type FloopLearnOutput struct {
 	Confidence      float64  `json:"confidence" jsonschema:"Placement confidence (0.0-1.0)"`
 	RequiresReview  bool     `json:"requires_review" jsonschema:"Whether behavior requires manual review"`
 	ReviewReasons   []string `json:"review_reasons,omitempty" jsonschema:"Reasons why review is needed"`
+	HeldForReview   bool     `json:"held_for_review,omitempty" jsonschema:"Whether the behavior is held as pending and inactive until approved"`
 	MergedIntoID    string   `json:"merged_into_id,omitempty" jsonschema:"ID of behavior this was merged into (if auto-merged)"`
 	MergeSimilarity float64  `json:"merge_similarity,omitempty" jsonschema:"Similarity score with merged behavior (0.0-1.0)"`
+	ForgottenID     string   `json:"forgotten_id,omitempty" jsonschema:"ID of the forgotten behavior this correction matched; nothing was learned"`
 	Message         string   `json:"message" jsonschema:"Human-readable result message"`
 }
 
@@ -75,6 +101,8 @@ type FloopLearnOutput struct {
 type FloopListInput struct {
 	Corrections bool   `json:"corrections,omitempty" jsonschema:"List corrections instead of behaviors (default: false)"`
 	Tag         string `json:"tag,omitempty" jsonschema:"Filter behaviors by tag (exact match)"`
+	Limit       int    `json:"limit,omitempty" jsonschema:"Maximum number of behaviors to return (default: all)"`
+	Cursor      string `json:"cursor,omitempty" jsonschema:"Resume after a previous page (its next_cursor)"`
 }
 
 // FloopListOutput defines the output for floop_list tool.
@@ -82,6 +110,7 @@ type FloopListOutput struct {
 	Behaviors   []BehaviorListItem   `json:"behaviors,omitempty" jsonschema:"List of behaviors"`
 	Corrections []CorrectionListItem `json:"corrections,omitempty" jsonschema:"List of corrections"`
 	Count       int                  `json:"count" jsonschema:"Number of items"`
+	NextCursor  string               `json:"next_cursor,omitempty" jsonschema:"Pass as cursor to fetch the next page of behaviors; absent on the last page"`
 }
 
 // BehaviorListItem provides a list view of a behavior.
@@ -230,21 +259,83 @@ type FloopFeedbackOutput struct {
 	Message    string `json:"message" jsonschema:"Human-readable result message"`
 }
 
+// FloopShowInput defines the input for floop_show tool.
+type FloopShowInput struct {
+	BehaviorID string `json:"behavior_id" jsonschema:"ID or name of the behavior to show,required"`
+}
+
+// FloopShowOutput defines the output for floop_show tool.
+type FloopShowOutput struct {
+	ID           string                 `json:"id" jsonschema:"Behavior ID"`
+	Name         string                 `json:"name" jsonschema:"Behavior name"`
+	Kind         string                 `json:"kind" jsonschema:"Behavior kind"`
+	Confidence   float64                `json:"confidence" jsonschema:"Confidence score (0.0-1.0)"`
+	Priority     int                    `json:"priority" jsonschema:"Priority"`
+	Canonical    string                 `json:"canonical" jsonschema:"Full behavior content"`
+	Summary      string                 `json:"summary,omitempty" jsonschema:"Short summary of the behavior"`
+	Tags         []string               `json:"tags,omitempty" jsonschema:"Behavior tags"`
+	When         map[string]interface{} `json:"when,omitempty" jsonschema:"Activation conditions"`
+	Source       string                 `json:"source" jsonschema:"How the behavior was created"`
+	CreatedAt    time.Time              `json:"created_at" jsonschema:"When the behavior was created"`
+	CorrectionID string                 `json:"correction_id,omitempty" jsonschema:"Correction the behavior was learned from"`
+	Requires     []string               `json:"requires,omitempty" jsonschema:"Behaviors this behavior requires"`
+	Overrides    []string               `json:"overrides,omitempty" jsonschema:"Behaviors this behavior overrides"`
+	Conflicts    []string               `json:"conflicts,omitempty" jsonschema:"Behaviors this behavior conflicts with"`
+}
+
+// FloopWhyInput defines the input for floop_why tool.
+type FloopWhyInput struct {
+	BehaviorID string            `json:"behavior_id" jsonschema:"ID or name of the behavior to explain,required"`
+	File       string            `json:"file,omitempty" jsonschema:"Current file path (relative to project root)"`
+	Task       string            `json:"task,omitempty" jsonschema:"Current task type (e.g. 'development', 'testing', 'refactoring')"`
+	Language   string            `json:"language,omitempty" jsonschema:"Programming language (e.g. 'go', 'python'). Overrides file extension inference"`
+	Context    map[string]string `json:"context,omitempty" jsonschema:"Custom context values for organization-defined when conditions (e.g. {\"team\": \"payments\"})"`
+}
+
+// FloopWhyOutput defines the output for floop_why tool.
+type FloopWhyOutput struct {
+	BehaviorID string         `json:"behavior_id" jsonschema:"Behavior ID"`
+	Name       string         `json:"name" jsonschema:"Behavior name"`
+	Active     bool           `json:"active" jsonschema:"Whether the behavior is active in the given context"`
+	Reason     string         `json:"reason" jsonschema:"Why the behavior is or isn't active"`
+	Conditions []WhyCondition `json:"conditions,omitempty" jsonschema:"Evaluation of each activation condition"`
+}
+
+// WhyCondition explains how one activation condition evaluated.
+type WhyCondition struct {
+	Field    string      `json:"field" jsonschema:"Context field the condition checks"`
+	Required interface{} `json:"required" jsonschema:"Value the condition requires"`
+	Actual   interface{} `json:"actual,omitempty" jsonschema:"Value in the current context"`
+	Status   string      `json:"status" jsonschema:"confirmed, contradicted, or absent"`
+	Detail   string      `json:"detail,omitempty" jsonschema:"Which value or pattern matched, or which failed"`
+}
+
 // FloopPackInstallInput defines the input for floop_pack_install tool.
 type FloopPackInstallInput struct {
-	Source   string `json:"source" jsonschema:"Pack source: local path, URL (https://...), or GitHub shorthand (gh:owner/repo[@version]),required"`
+	Source   string `json:"source" jsonschema:"Pack source: local path, URL (https://...), GitHub shorthand (gh:owner/repo[@version]), GitLab shorthand (gl:group/project[@version]), git repository (git+https://host/repo.git#tag=...;path=...), or registry:namespace/name[@version],required"`
 	FilePath string `json:"file_path,omitempty" jsonschema:"Deprecated: use source instead. Path to .fpack file to install"`
 }
 
 // FloopPackInstallOutput defines the output for floop_pack_install tool.
 type FloopPackInstallOutput struct {
-	PackID       string   `json:"pack_id" jsonschema:"Installed pack ID"`
-	Version      string   `json:"version" jsonschema:"Installed pack version"`
-	Added        []string `json:"added" jsonschema:"IDs of newly added behaviors"`
-	Updated      []string `json:"updated" jsonschema:"IDs of upgraded behaviors"`
-	Skipped      []string `json:"skipped" jsonschema:"IDs of skipped behaviors"`
-	EdgesAdded   int      `json:"edges_added" jsonschema:"Number of edges added"`
-	EdgesSkipped int      `json:"edges_skipped" jsonschema:"Number of edges skipped"`
-	DerivedEdges int      `json:"derived_edges" jsonschema:"Number of edges automatically derived between pack and existing behaviors"`
-	Message      string   `json:"message" jsonschema:"Human-readable result message"`
+	PackID       string          `json:"pack_id" jsonschema:"Installed pack ID"`
+	Version      string          `json:"version" jsonschema:"Installed pack version"`
+	Added        []string        `json:"added" jsonschema:"IDs of newly added behaviors"`
+	Updated      []string        `json:"updated" jsonschema:"IDs of upgraded behaviors"`
+	Skipped      []string        `json:"skipped" jsonschema:"IDs of skipped behaviors"`
+	EdgesAdded   int             `json:"edges_added" jsonschema:"Number of edges added"`
+	EdgesSkipped int             `json:"edges_skipped" jsonschema:"Number of edges skipped"`
+	DerivedEdges int             `json:"derived_edges" jsonschema:"Number of edges automatically derived between pack and existing behaviors"`
+	Collisions   []PackCollision `json:"collisions,omitempty" jsonschema:"Pack behaviors that collided with local ones, and how each was resolved"`
+	Message      string          `json:"message" jsonschema:"Human-readable result message"`
+}
+
+// PackCollision is a pack behavior that collided with a local behavior on install.
+type PackCollision struct {
+	Kind          string  `json:"kind" jsonschema:"Collision kind: 'id' (same ID, different content) or 'content' (near-identical content)"`
+	PackBehavior  string  `json:"pack_behavior" jsonschema:"ID of the behavior in the pack"`
+	LocalBehavior string  `json:"local_behavior" jsonschema:"ID of the local behavior it collided with"`
+	Similarity    float64 `json:"similarity,omitempty" jsonschema:"Content similarity, for content collisions"`
+	Action        string  `json:"action" jsonschema:"How it was resolved: keep-local, prefer-pack, or rename"`
+	InstalledAs   string  `json:"installed_as,omitempty" jsonschema:"ID a renamed pack behavior was installed under"`
 }
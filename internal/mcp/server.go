@@ -4,8 +4,10 @@ package mcp
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 	"log/slog"
+	"net/http"
 	"os"
 	"path/filepath"
 	"sync"
@@ -15,16 +17,20 @@ import (
 	"github.com/nvandessel/floop/internal/backup"
 	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/decay"
 	"github.com/nvandessel/floop/internal/events"
+	"github.com/nvandessel/floop/internal/gc"
 	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/metrics"
+	"github.com/nvandessel/floop/internal/pack"
 	"github.com/nvandessel/floop/internal/project"
 	"github.com/nvandessel/floop/internal/ranking"
 	"github.com/nvandessel/floop/internal/ratelimit"
 	"github.com/nvandessel/floop/internal/seed"
 	"github.com/nvandessel/floop/internal/session"
-	"github.com/nvandessel/floop/internal/setup"
 	"github.com/nvandessel/floop/internal/spreading"
 	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
 	"github.com/nvandessel/floop/internal/vectorindex"
 	"github.com/nvandessel/floop/internal/vectorsearch"
 )
@@ -37,7 +43,6 @@ type Server struct {
 	server        *sdk.Server
 	store         store.GraphStore
 	root          string
-	floopConfig   *config.FloopConfig
 	session       *session.State
 	pageRankMu    sync.RWMutex
 	pageRankCache map[string]float64
@@ -45,6 +50,9 @@ type Server struct {
 	// Audit logging
 	auditLogger *AuditLogger
 
+	// Local usage metrics; recorded only while metrics.enabled (see metrics()).
+	metricsRecorder *metrics.Recorder
+
 	// Rate limiting
 	toolLimiters ratelimit.ToolLimiters
 
@@ -52,10 +60,18 @@ type Server struct {
 	pageRankDebounce   *time.Timer
 	pageRankDebounceMu sync.Mutex
 
-	// Backup configuration and retention policy
+	// Floop configuration, backup settings, and retention policy. Guarded by
+	// configMu; ReloadConfig swaps them together (see reload.go).
+	configMu        sync.RWMutex
+	reloadMu        sync.Mutex
+	floopConfig     *config.FloopConfig
+	profileName     string // --profile; resolved against the current config
 	backupConfig    *config.BackupConfig
 	retentionPolicy backup.RetentionPolicy
 
+	// Wakes autoBackupLoop after a learn.
+	autoBackupWake chan struct{}
+
 	// Bounded worker pool for background goroutines
 	workerPool chan struct{}
 	workerWg   sync.WaitGroup
@@ -67,6 +83,18 @@ type Server struct {
 	confirmedSessionMu   sync.Mutex
 	confirmedThisSession map[string]struct{}
 
+	// processSessionID identifies this server process for per-session
+	// activation stats when no 'floop session' is active (see activationSession).
+	processSessionID string
+
+	// Agent context sessions registered via floop_context, keyed by session ID.
+	contextSessionsMu sync.Mutex
+	contextSessions   map[string]*contextSession
+
+	// gRPC WatchActive streams, woken when the behavior graph changes.
+	activeWatchersMu sync.Mutex
+	activeWatchers   map[chan struct{}]struct{}
+
 	// Hebbian co-activation learning
 	coActivationTracker *coActivationTracker
 	hebbianConfig       spreading.HebbianConfig
@@ -78,6 +106,10 @@ type Server struct {
 	// Version info (from ldflags)
 	floopVersion string
 
+	// Bearer token required by the network transports; empty restricts
+	// them to loopback (see auth.go).
+	token string
+
 	// Vector index for fast ANN search over behavior embeddings
 	vectorIndex vectorindex.VectorIndex
 
@@ -99,10 +131,17 @@ type Config struct {
 	Name    string // Server name (e.g., "floop")
 	Version string // Server version
 	Root    string // Project root directory
+	Profile string // Agent profile name; empty uses the config's profile
+	Token   string // Bearer token for the HTTP and gRPC transports; see TokenEnv
 }
 
 // NewServer creates a new MCP server with floop tools.
 func NewServer(cfg *Config) (*Server, error) {
+	processSessionID, err := session.NewID(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
 	// Create multi-graph store (local + global)
 	graphStore, err := store.NewMultiGraphStore(cfg.Root)
 	if err != nil {
@@ -117,6 +156,8 @@ func NewServer(cfg *Config) (*Server, error) {
 		InitializedHandler: func(ctx context.Context, req *sdk.InitializedRequest) {
 			// Client initialized, ready to serve
 		},
+		SubscribeHandler:   subscribeHandler,
+		UnsubscribeHandler: unsubscribeHandler,
 	})
 
 	// Determine home directory for global audit log
@@ -127,12 +168,16 @@ func NewServer(cfg *Config) (*Server, error) {
 	}
 
 	// Load floop config (non-fatal: use defaults on error)
-	floopCfg, err := config.Load()
+	floopCfg, err := config.LoadForProject(cfg.Root)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to load config, using defaults: %v\n", err)
 		floopCfg = config.Default()
 	}
 	retPolicy := buildRetentionPolicy(&floopCfg.Backup)
+	if _, err := floopCfg.ResolveProfile(cfg.Profile); err != nil {
+		graphStore.Close()
+		return nil, err
+	}
 
 	// Initialize shared event store for consolidation MCP tools.
 	// Uses the global DB (~/.floop/floop.db) so events are available across projects.
@@ -167,14 +212,21 @@ func NewServer(cfg *Config) (*Server, error) {
 		root:                 cfg.Root,
 		floopVersion:         cfg.Version,
 		floopConfig:          floopCfg,
+		profileName:          cfg.Profile,
+		token:                cfg.Token,
 		session:              session.NewState(session.DefaultConfig()),
 		auditLogger:          NewAuditLogger(cfg.Root, homeDir),
+		metricsRecorder:      metrics.Open(filepath.Join(cfg.Root, ".floop"), metrics.SourceMCP),
 		pageRankCache:        make(map[string]float64),
 		toolLimiters:         ratelimit.NewToolLimiters(),
 		backupConfig:         &floopCfg.Backup,
 		retentionPolicy:      retPolicy,
+		autoBackupWake:       make(chan struct{}, 1),
 		workerPool:           make(chan struct{}, maxBackgroundWorkers),
 		confirmedThisSession: make(map[string]struct{}),
+		processSessionID:     processSessionID,
+		contextSessions:      make(map[string]*contextSession),
+		activeWatchers:       make(map[chan struct{}]struct{}),
 		coActivationTracker:  initCoActivationTracker(graphStore),
 		hebbianConfig:        spreading.DefaultHebbianConfig(),
 		eventStore:           eventStore,
@@ -184,40 +236,12 @@ func NewServer(cfg *Config) (*Server, error) {
 		done:                 make(chan struct{}),
 	}
 
-	// Initialize local embedding client.
-	// Priority: explicit config > auto-detect from ~/.floop/
-	if floopCfg.LLM.Provider == "local" {
-		embModelPath := floopCfg.LLM.LocalEmbeddingModelPath
-		if embModelPath == "" {
-			embModelPath = floopCfg.LLM.LocalModelPath
-		}
-		if embModelPath != "" {
-			localClient := llm.NewLocalClient(llm.LocalConfig{
-				LibPath:            floopCfg.LLM.LocalLibPath,
-				EmbeddingModelPath: embModelPath,
-				GPULayers:          floopCfg.LLM.LocalGPULayers,
-				ContextSize:        floopCfg.LLM.LocalContextSize,
-			})
-			if localClient.Available() {
-				s.llmClient = localClient
-				modelName := filepath.Base(embModelPath)
-				s.embedder = vectorsearch.NewEmbedder(localClient.Embed, modelName)
-			}
-		}
-	}
-	// Auto-detect: if no embedder from config, check ~/.floop/ for installed deps
-	if s.embedder == nil {
-		detected := setup.DetectInstalled(setup.DefaultFloopDir())
-		if detected.Available {
-			localClient := llm.NewLocalClient(llm.LocalConfig{
-				LibPath:            detected.LibPath,
-				EmbeddingModelPath: detected.ModelPath,
-			})
-			if localClient.Available() {
-				s.llmClient = localClient
-				modelName := filepath.Base(detected.ModelPath)
-				s.embedder = vectorsearch.NewEmbedder(localClient.Embed, modelName)
-			}
+	// Initialize the embedding backend.
+	// Priority: embedding config > local LLM config > auto-detect from ~/.floop/
+	if embedder, localClient := vectorsearch.NewEmbedderFromConfig(floopCfg); embedder != nil {
+		s.embedder = embedder
+		if localClient != nil {
+			s.llmClient = localClient
 		}
 	}
 
@@ -240,7 +264,7 @@ func NewServer(cfg *Config) (*Server, error) {
 	}
 
 	// Auto-seed meta-behaviors into global store (non-fatal)
-	autoSeedGlobalStore(graphStore)
+	autoSeedGlobalStore(graphStore, floopCfg.Seeds.Experimental)
 
 	// Register tools
 	if err := s.registerTools(); err != nil {
@@ -274,9 +298,92 @@ func NewServer(cfg *Config) (*Server, error) {
 		}
 	}
 
+	s.scheduleGC(floopCfg)
+	s.scheduleDecay(graphStore, floopCfg)
+
 	return s, nil
 }
 
+// scheduleGC runs garbage collection in the background when
+// maintenance.gc_interval has elapsed since the last run.
+func (s *Server) scheduleGC(cfg *config.FloopConfig) {
+	floopDir := filepath.Join(s.root, ".floop")
+	if _, err := os.Stat(floopDir); err != nil {
+		return
+	}
+	interval, err := utils.ParseDuration(cfg.Maintenance.GCInterval)
+	if err != nil || !gc.Due(floopDir, interval, time.Now()) {
+		return
+	}
+
+	opts := gc.Options{
+		Store:       s.store,
+		Installed:   pack.ListInstalled(cfg),
+		CacheMaxAge: cfg.Packs.CacheMaxAgeDuration(),
+	}
+	if s.vectorIndex != nil {
+		opts.Index = s.vectorIndex
+		opts.IndexDir = filepath.Join(floopDir, "vectors")
+	}
+	if cacheDir, err := pack.DefaultCacheDir(); err == nil {
+		opts.CacheDir = cacheDir
+	}
+
+	s.runBackground("gc", func() {
+		report, err := gc.Run(context.Background(), opts)
+		if err != nil {
+			s.logger.Warn("garbage collection failed", "error", err)
+			return
+		}
+		for _, w := range report.Warnings {
+			s.logger.Warn("garbage collection", "warning", w)
+		}
+		if err := gc.MarkRun(floopDir, report); err != nil {
+			s.logger.Warn("failed to record garbage collection", "error", err)
+		}
+		s.logger.Info("garbage collection complete",
+			"vectors", len(report.OrphanedVectors),
+			"cache_files", len(report.OrphanedCacheFiles),
+			"reclaimed_bytes", report.ReclaimedBytes())
+	})
+}
+
+// scheduleDecay decays the confidence of stale behaviors in the background,
+// for each of the local and global stores whose maintenance.decay_interval
+// has elapsed since its last run.
+func (s *Server) scheduleDecay(graphStore *store.MultiGraphStore, cfg *config.FloopConfig) {
+	interval, err := utils.ParseDuration(cfg.Maintenance.DecayInterval)
+	if err != nil || interval <= 0 {
+		return
+	}
+	opts, err := decay.OptionsFromConfig(cfg.Maintenance)
+	if err != nil {
+		s.logger.Warn("confidence decay skipped", "error", err)
+		return
+	}
+
+	now := time.Now()
+	for _, scope := range decay.Scopes(graphStore, s.root) {
+		if !decay.Due(scope.Dir, interval, now) {
+			continue
+		}
+		scopeOpts := opts
+		scopeOpts.Store = scope.Store
+		scopeOpts.LastRun = decay.LastRun(scope.Dir)
+		s.runBackground("decay-"+scope.Name, func() {
+			report, err := decay.Run(context.Background(), scopeOpts)
+			if err != nil {
+				s.logger.Warn("confidence decay failed", "scope", scope.Name, "error", err)
+				return
+			}
+			if err := decay.MarkRun(scope.Dir, report); err != nil {
+				s.logger.Warn("failed to record confidence decay", "error", err)
+			}
+			s.logger.Info("confidence decay complete", "scope", scope.Name, "decayed", len(report.Decayed))
+		})
+	}
+}
+
 // initVectorIndex creates the vector index (LanceDB or BruteForce fallback)
 // and populates it from stored embeddings.
 func (s *Server) initVectorIndex(graphStore *store.MultiGraphStore, vectorDir string) vectorindex.VectorIndex {
@@ -285,12 +392,16 @@ func (s *Server) initVectorIndex(graphStore *store.MultiGraphStore, vectorDir st
 		s.logger.Warn("failed to load embeddings for index", "error", loadErr)
 	}
 
-	// Default matches nomic-embed-text-v1.5 (768-dim), the only supported model.
-	// On fresh installs (no embeddings yet), this creates the table with 768 dims.
+	// Default matches nomic-embed-text-v1.5 (768-dim), the model installed by
+	// 'floop init --embeddings', unless embedding.dimension says otherwise.
+	// On fresh installs (no embeddings yet), this creates the table with those dims.
 	// If a future model has different dims, the first Add will fail with a clear
 	// dimension mismatch error, and on restart the schema validation will catch the
 	// mismatch and fall back to BruteForce until the user deletes .floop/vectors/.
 	dims := 768
+	if s.embedder.Dimension() > 0 {
+		dims = s.embedder.Dimension()
+	}
 	for _, emb := range allEmb {
 		if len(emb.Embedding) > 0 {
 			dims = len(emb.Embedding)
@@ -411,6 +522,19 @@ func (s *Server) runBackground(name string, fn func()) {
 	}
 }
 
+// trackSession counts an event against the tracked 'floop session' active in
+// the project, if any. Failures are logged, never returned: session grouping
+// must not break the tool call that triggered it.
+func (s *Server) trackSession(ctx context.Context, event session.Event, refs ...string) {
+	id := session.Current(s.root)
+	if id == "" {
+		return
+	}
+	if err := session.Track(ctx, s.store, id, event, refs...); err != nil {
+		s.logger.Warn("session tracking failed", "session_id", id, "event", string(event), "error", err)
+	}
+}
+
 // Run starts the MCP server over stdio transport.
 // This blocks until the client disconnects or the context is cancelled.
 func (s *Server) Run(ctx context.Context) error {
@@ -427,6 +551,9 @@ func (s *Server) Run(ctx context.Context) error {
 		cancel()
 	}()
 
+	go s.watchConfig(ctx)
+	go s.autoBackupLoop(ctx)
+
 	// Run server (blocks)
 	err := s.server.Run(ctx, &sdk.StdioTransport{})
 
@@ -436,17 +563,73 @@ func (s *Server) Run(ctx context.Context) error {
 	return err
 }
 
+// RunHTTP starts the MCP server over the streamable HTTP transport on addr.
+// Server-to-client notifications (such as resource updates for floop_context
+// sessions) are delivered over each client's SSE stream.
+// This blocks until the context is cancelled or a termination signal arrives.
+func (s *Server) RunHTTP(ctx context.Context, addr string) error {
+	if err := checkListenAddr(addr, s.token); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	notifySignals(sigChan)
+
+	go s.watchConfig(ctx)
+	go s.autoBackupLoop(ctx)
+
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           s.httpHandler(),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		select {
+		case <-sigChan:
+		case <-ctx.Done():
+		}
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer shutdownCancel()
+		_ = httpServer.Shutdown(shutdownCtx)
+	}()
+
+	err := httpServer.ListenAndServe()
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	s.Close()
+
+	return err
+}
+
+// httpHandler returns the HTTP handler serving this MCP server over the
+// streamable transport, plus the admin reload endpoint. Every request
+// passes requireAuth.
+func (s *Server) httpHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(adminReloadPath, s.handleAdminReload)
+	mux.Handle("/", sdk.NewStreamableHTTPHandler(func(*http.Request) *sdk.Server {
+		return s.server
+	}, nil))
+	return s.requireAuth(mux)
+}
+
 // autoSeedGlobalStore seeds meta-behaviors into the global store.
+// Experimental seeds are included only when experimental is set.
 // This is non-fatal: errors are logged to stderr but do not block startup.
-func autoSeedGlobalStore(graphStore *store.MultiGraphStore) {
+func autoSeedGlobalStore(graphStore *store.MultiGraphStore, experimental bool) {
 	globalStore := graphStore.GlobalStore()
-	result, err := seed.NewSeeder(globalStore).SeedGlobalStore(context.Background())
+	result, err := seed.NewSeeder(globalStore).WithExperimental(experimental).SeedGlobalStore(context.Background())
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "warning: failed to auto-seed global store: %v\n", err)
 		return
 	}
-	if len(result.Added) > 0 || len(result.Updated) > 0 {
-		fmt.Fprintf(os.Stderr, "floop: seeded %d, updated %d meta-behavior(s)\n", len(result.Added), len(result.Updated))
+	if len(result.Added) > 0 || len(result.Updated) > 0 || len(result.Withdrawn) > 0 {
+		fmt.Fprintf(os.Stderr, "floop: seeded %d, updated %d, withdrew %d meta-behavior(s)\n", len(result.Added), len(result.Updated), len(result.Withdrawn))
 	}
 }
 
@@ -13,3 +13,8 @@ import (
 func notifySignals(ch chan<- os.Signal) {
 	signal.Notify(ch, os.Interrupt, syscall.SIGTERM)
 }
+
+// notifyReloadSignals registers the SIGHUP handler that triggers a config reload.
+func notifyReloadSignals(ch chan<- os.Signal) {
+	signal.Notify(ch, syscall.SIGHUP)
+}
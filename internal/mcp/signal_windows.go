@@ -12,3 +12,7 @@ import (
 func notifySignals(ch chan<- os.Signal) {
 	signal.Notify(ch, os.Interrupt)
 }
+
+// notifyReloadSignals is a no-op on Windows, which has no SIGHUP. Config
+// reloads are triggered by file changes or the admin endpoint instead.
+func notifyReloadSignals(ch chan<- os.Signal) {}
@@ -0,0 +1,140 @@
+package metrics
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// Latency summarizes durations in milliseconds.
+type Latency struct {
+	AvgMS float64 `json:"avg_ms"`
+	P50MS float64 `json:"p50_ms"`
+	P95MS float64 `json:"p95_ms"`
+	MaxMS float64 `json:"max_ms"`
+}
+
+// CommandStats summarizes the invocations of one CLI command or MCP tool.
+type CommandStats struct {
+	Source string `json:"source"`
+	Name   string `json:"name"`
+	Count  int    `json:"count"`
+	Errors int    `json:"errors"`
+	Latency
+}
+
+// LearnStats summarizes learns by outcome.
+type LearnStats struct {
+	Count    int            `json:"count"`
+	Outcomes map[string]int `json:"outcomes"`
+	Latency
+}
+
+// ActivationStats summarizes activations.
+type ActivationStats struct {
+	Count        int     `json:"count"`
+	Empty        int     `json:"empty"`    // activations that activated nothing
+	Degraded     int     `json:"degraded"` // activations that exceeded their time budget
+	AvgBehaviors float64 `json:"avg_behaviors"`
+	Latency
+}
+
+// Insights summarizes the events recorded since a point in time.
+type Insights struct {
+	Since      time.Time       `json:"since"`
+	Events     int             `json:"events"`
+	Commands   []CommandStats  `json:"commands"` // most used first
+	Learn      LearnStats      `json:"learn"`
+	Activation ActivationStats `json:"activation"`
+}
+
+// Summarize aggregates events into Insights.
+func Summarize(events []Event, since time.Time) *Insights {
+	in := &Insights{
+		Since:    since,
+		Events:   len(events),
+		Commands: []CommandStats{},
+		Learn:    LearnStats{Outcomes: map[string]int{}},
+	}
+
+	type commandKey struct{ source, name string }
+	commands := make(map[commandKey]*CommandStats)
+	commandDurations := make(map[commandKey][]float64)
+	var learnDurations, activationDurations []float64
+	var behaviors int
+	for _, e := range events {
+		switch e.Kind {
+		case KindCommand, KindTool:
+			key := commandKey{e.Source, e.Name}
+			c, ok := commands[key]
+			if !ok {
+				c = &CommandStats{Source: e.Source, Name: e.Name}
+				commands[key] = c
+			}
+			c.Count++
+			if e.Error {
+				c.Errors++
+			}
+			commandDurations[key] = append(commandDurations[key], e.DurationMS)
+		case KindLearn:
+			in.Learn.Count++
+			in.Learn.Outcomes[e.Outcome]++
+			learnDurations = append(learnDurations, e.DurationMS)
+		case KindActivation:
+			in.Activation.Count++
+			behaviors += e.Count
+			if e.Count == 0 {
+				in.Activation.Empty++
+			}
+			if e.Degraded {
+				in.Activation.Degraded++
+			}
+			activationDurations = append(activationDurations, e.DurationMS)
+		}
+	}
+
+	for key, c := range commands {
+		c.Latency = summarizeLatency(commandDurations[key])
+		in.Commands = append(in.Commands, *c)
+	}
+	sort.Slice(in.Commands, func(i, j int) bool {
+		a, b := in.Commands[i], in.Commands[j]
+		if a.Count != b.Count {
+			return a.Count > b.Count
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Name < b.Name
+	})
+	in.Learn.Latency = summarizeLatency(learnDurations)
+	in.Activation.Latency = summarizeLatency(activationDurations)
+	if in.Activation.Count > 0 {
+		in.Activation.AvgBehaviors = float64(behaviors) / float64(in.Activation.Count)
+	}
+	return in
+}
+
+// summarizeLatency computes the average, nearest-rank percentiles, and
+// maximum of durations.
+func summarizeLatency(durations []float64) Latency {
+	if len(durations) == 0 {
+		return Latency{}
+	}
+	sorted := append([]float64(nil), durations...)
+	sort.Float64s(sorted)
+	var sum float64
+	for _, d := range sorted {
+		sum += d
+	}
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p*float64(len(sorted)))) - 1
+		return sorted[max(rank, 0)]
+	}
+	return Latency{
+		AvgMS: sum / float64(len(sorted)),
+		P50MS: percentile(0.50),
+		P95MS: percentile(0.95),
+		MaxMS: sorted[len(sorted)-1],
+	}
+}
@@ -0,0 +1,174 @@
+// Package metrics records opt-in, local-only usage metrics — command and
+// MCP tool invocations, learn outcomes, and activations, with their
+// latency — to .floop/metrics.jsonl, and summarizes them for
+// 'floop insights'. Nothing is sent anywhere; events carry no behavior
+// content, arguments, or file paths.
+package metrics
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileName is the name of the metrics file in a .floop directory.
+const FileName = "metrics.jsonl"
+
+// RetentionDays is how many days of events the file keeps once it grows
+// past maxFileSize.
+const RetentionDays = 90
+
+// maxFileSize is the size past which the file is trimmed to RetentionDays
+// and, if still too large, to its newest half.
+const maxFileSize = 4 << 20
+
+// Event kinds.
+const (
+	KindCommand    = "command"    // a CLI command
+	KindTool       = "tool"       // an MCP tool call
+	KindLearn      = "learn"      // a correction processed by the learning loop
+	KindActivation = "activation" // behaviors activated for a context
+)
+
+// Sources of events.
+const (
+	SourceCLI = "cli"
+	SourceMCP = "mcp"
+)
+
+// Event is one recorded occurrence.
+type Event struct {
+	Time       time.Time `json:"time"`
+	Kind       string    `json:"kind"`
+	Source     string    `json:"source,omitempty"`
+	Name       string    `json:"name,omitempty"` // command path or tool name
+	DurationMS float64   `json:"duration_ms"`
+	Error      bool      `json:"error,omitempty"`
+	Outcome    string    `json:"outcome,omitempty"`  // learn: what the learn did
+	Count      int       `json:"count,omitempty"`    // activation: behaviors activated
+	Degraded   bool      `json:"degraded,omitempty"` // activation: time budget exceeded
+}
+
+// Recorder appends events to a metrics file. A nil Recorder is safe to
+// use; Record is a no-op.
+type Recorder struct {
+	path   string
+	source string
+
+	mu sync.Mutex
+}
+
+// Open returns a Recorder writing to floopDir/metrics.jsonl, stamping
+// events with source, or nil if floopDir does not exist: metrics are only
+// kept for initialized projects.
+func Open(floopDir, source string) *Recorder {
+	if info, err := os.Stat(floopDir); err != nil || !info.IsDir() {
+		return nil
+	}
+	return &Recorder{path: filepath.Join(floopDir, FileName), source: source}
+}
+
+// Record appends e, filling in its time and source if unset. Metrics are
+// best effort: errors are ignored.
+func (r *Recorder) Record(e Event) {
+	if r == nil {
+		return
+	}
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	e.Time = e.Time.UTC()
+	if e.Source == "" {
+		e.Source = r.source
+	}
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return
+	}
+	_, _ = f.Write(append(data, '\n'))
+	info, statErr := f.Stat()
+	f.Close()
+	if statErr == nil && info.Size() > maxFileSize {
+		trim(r.path, time.Now())
+	}
+}
+
+// Since returns the duration from start in milliseconds, for DurationMS.
+func Since(start time.Time) float64 {
+	return float64(time.Since(start).Microseconds()) / 1000
+}
+
+// Read returns the events in the metrics file at path recorded at or after
+// since. A missing file has no events; malformed lines are skipped.
+func Read(path string, since time.Time) ([]Event, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []Event
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64<<10), 1<<20)
+	for scanner.Scan() {
+		var e Event
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if !e.Time.Before(since) {
+			events = append(events, e)
+		}
+	}
+	return events, scanner.Err()
+}
+
+// trim rewrites the file at path without events older than RetentionDays,
+// keeping only the newest half if that is still over maxFileSize.
+func trim(path string, now time.Time) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+	cutoff := now.AddDate(0, 0, -RetentionDays)
+	var kept [][]byte
+	var size int
+	for _, line := range bytes.Split(data, []byte("\n")) {
+		var e Event
+		if len(line) == 0 || json.Unmarshal(line, &e) != nil || e.Time.Before(cutoff) {
+			continue
+		}
+		kept = append(kept, line)
+		size += len(line) + 1
+	}
+	for size > maxFileSize/2 && len(kept) > 0 {
+		size -= len(kept[0]) + 1
+		kept = kept[1:]
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), FileName+".*.tmp")
+	if err != nil {
+		return
+	}
+	defer os.Remove(tmp.Name()) // no-op after a successful rename
+	for _, line := range kept {
+		tmp.Write(append(line, '\n'))
+	}
+	if err := tmp.Close(); err != nil {
+		return
+	}
+	_ = os.Rename(tmp.Name(), path)
+}
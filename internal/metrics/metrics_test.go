@@ -0,0 +1,139 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOpen_Uninitialized(t *testing.T) {
+	r := Open(filepath.Join(t.TempDir(), ".floop"), SourceCLI)
+	if r != nil {
+		t.Fatal("Open() should return nil for a missing .floop directory")
+	}
+	r.Record(Event{Kind: KindCommand}) // nil recorder is a no-op
+}
+
+func TestRecordAndRead(t *testing.T) {
+	dir := t.TempDir()
+	r := Open(dir, SourceMCP)
+	now := time.Now()
+	r.Record(Event{Time: now.Add(-48 * time.Hour), Kind: KindTool, Name: "floop_learn"})
+	r.Record(Event{Kind: KindTool, Name: "floop_active", DurationMS: 12.5})
+	r.Record(Event{Kind: KindCommand, Source: SourceCLI, Name: "floop list", Error: true})
+
+	path := filepath.Join(dir, FileName)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("metrics file mode = %v, want 0600", info.Mode().Perm())
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("not json\n")
+	f.Close()
+
+	events, err := Read(path, now.Add(-time.Hour))
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if len(events) != 2 {
+		t.Fatalf("Read() returned %d events, want 2", len(events))
+	}
+	if events[0].Source != SourceMCP || events[0].Name != "floop_active" || events[0].DurationMS != 12.5 {
+		t.Errorf("events[0] = %+v", events[0])
+	}
+	if events[1].Source != SourceCLI || !events[1].Error {
+		t.Errorf("events[1] = %+v, want the explicit source kept", events[1])
+	}
+
+	if events, err := Read(filepath.Join(dir, "missing.jsonl"), now); err != nil || events != nil {
+		t.Errorf("Read(missing) = %v, %v; want no events", events, err)
+	}
+}
+
+func TestTrim(t *testing.T) {
+	dir := t.TempDir()
+	r := Open(dir, SourceCLI)
+	now := time.Now()
+	r.Record(Event{Time: now.AddDate(0, 0, -RetentionDays-1), Kind: KindCommand, Name: "old"})
+	padding := strings.Repeat("x", 1024)
+	for range maxFileSize / 1024 {
+		r.Record(Event{Time: now, Kind: KindCommand, Name: padding})
+	}
+
+	path := filepath.Join(dir, FileName)
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() > maxFileSize {
+		t.Errorf("file size = %d, want at most %d after trimming", info.Size(), maxFileSize)
+	}
+	events, err := Read(path, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range events {
+		if e.Name == "old" {
+			t.Fatal("trim kept an event older than the retention period")
+		}
+	}
+	if len(events) == 0 {
+		t.Fatal("trim removed every event")
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	events := []Event{
+		{Kind: KindCommand, Source: SourceCLI, Name: "floop list", DurationMS: 10},
+		{Kind: KindCommand, Source: SourceCLI, Name: "floop list", DurationMS: 30, Error: true},
+		{Kind: KindCommand, Source: SourceCLI, Name: "floop show", DurationMS: 5},
+		{Kind: KindTool, Source: SourceMCP, Name: "floop_active", DurationMS: 20},
+		{Kind: KindTool, Source: SourceMCP, Name: "floop_active", DurationMS: 40},
+		{Kind: KindTool, Source: SourceMCP, Name: "floop_active", DurationMS: 60},
+		{Kind: KindLearn, Outcome: "learned", DurationMS: 100},
+		{Kind: KindLearn, Outcome: "merged", DurationMS: 200},
+		{Kind: KindLearn, Outcome: "learned", DurationMS: 300},
+		{Kind: KindActivation, Count: 4, DurationMS: 8},
+		{Kind: KindActivation, Count: 0, Degraded: true, DurationMS: 50},
+	}
+	in := Summarize(events, time.Time{})
+
+	if in.Events != len(events) {
+		t.Errorf("Events = %d, want %d", in.Events, len(events))
+	}
+	var names []string
+	for _, c := range in.Commands {
+		names = append(names, c.Name)
+	}
+	if got := strings.Join(names, ","); got != "floop_active,floop list,floop show" {
+		t.Errorf("commands = %s, want most used first", got)
+	}
+	list := in.Commands[1]
+	if list.Count != 2 || list.Errors != 1 || list.AvgMS != 20 || list.MaxMS != 30 {
+		t.Errorf("floop list stats = %+v", list)
+	}
+	active := in.Commands[0]
+	if active.P50MS != 40 || active.P95MS != 60 {
+		t.Errorf("floop_active p50/p95 = %v/%v, want 40/60", active.P50MS, active.P95MS)
+	}
+
+	if in.Learn.Count != 3 || in.Learn.Outcomes["learned"] != 2 || in.Learn.Outcomes["merged"] != 1 {
+		t.Errorf("learn stats = %+v", in.Learn)
+	}
+	if in.Learn.P50MS != 200 {
+		t.Errorf("learn p50 = %v, want 200", in.Learn.P50MS)
+	}
+
+	a := in.Activation
+	if a.Count != 2 || a.Empty != 1 || a.Degraded != 1 || a.AvgBehaviors != 2 {
+		t.Errorf("activation stats = %+v", a)
+	}
+}
@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/nvandessel/floop/internal/confidence"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -24,6 +25,8 @@ const (
 	BehaviorKindForgotten  BehaviorKind = BehaviorKind(store.NodeKindForgotten)
 	BehaviorKindDeprecated BehaviorKind = BehaviorKind(store.NodeKindDeprecated)
 	BehaviorKindMerged     BehaviorKind = BehaviorKind(store.NodeKindMerged)
+	BehaviorKindPending    BehaviorKind = BehaviorKind(store.NodeKindPending)
+	BehaviorKindDormant    BehaviorKind = BehaviorKind(store.NodeKindDormant)
 )
 
 // MemoryType classifies behaviors by cognitive category.
@@ -114,6 +117,10 @@ type Behavior struct {
 	// Learned behaviors start lower, increase with successful application
 	Confidence float64 `json:"confidence" yaml:"confidence"`
 
+	// ConfidenceSources explains Confidence as a base plus per-source
+	// adjustments; nil for behaviors created before these were tracked
+	ConfidenceSources *confidence.Sources `json:"confidence_sources,omitempty" yaml:"confidence_sources,omitempty"`
+
 	// Priority for conflict resolution (higher wins)
 	Priority int `json:"priority" yaml:"priority"`
 
@@ -125,6 +132,25 @@ type Behavior struct {
 
 	// Statistics (updated over time)
 	Stats BehaviorStats `json:"stats" yaml:"stats"`
+
+	// ExpiresAt is when a temporary behavior stops applying; nil means never.
+	// Expired behaviors are skipped by activation until 'floop maintain
+	// expire' marks them dormant.
+	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+
+	// SnoozedUntil, set by 'floop snooze', keeps the behavior from
+	// activating until then without forgetting it; nil means not snoozed.
+	SnoozedUntil *time.Time `json:"snoozed_until,omitempty" yaml:"snoozed_until,omitempty"`
+}
+
+// Expired reports whether the behavior has an expiry at or before now.
+func (b *Behavior) Expired(now time.Time) bool {
+	return b.ExpiresAt != nil && !b.ExpiresAt.After(now)
+}
+
+// Snoozed reports whether the behavior is snoozed past now.
+func (b *Behavior) Snoozed(now time.Time) bool {
+	return b.SnoozedUntil != nil && b.SnoozedUntil.After(now)
 }
 
 // SimilarityLink represents a similarity relationship with a score
@@ -143,4 +169,9 @@ type BehaviorStats struct {
 	LastConfirmed   *time.Time `json:"last_confirmed,omitempty" yaml:"last_confirmed,omitempty"` // Last time behavior was positively confirmed
 	CreatedAt       time.Time  `json:"created_at" yaml:"created_at"`
 	UpdatedAt       time.Time  `json:"updated_at" yaml:"updated_at"`
+
+	// SessionsActivated counts distinct sessions the behavior activated in,
+	// so repeated activations within one session count once.
+	SessionsActivated int    `json:"sessions_activated,omitempty" yaml:"sessions_activated,omitempty"`
+	LastSessionID     string `json:"last_session_id,omitempty" yaml:"last_session_id,omitempty"`
 }
@@ -1,6 +1,9 @@
 package models
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestNewBehaviorKinds(t *testing.T) {
 	tests := []struct {
@@ -23,3 +26,44 @@ func TestNewBehaviorKinds(t *testing.T) {
 		})
 	}
 }
+
+func TestBehaviorExpiresAt(t *testing.T) {
+	expires := time.Date(2026, 4, 1, 0, 0, 0, 0, time.UTC)
+	b := Behavior{ID: "b1", Name: "migration-workaround", ExpiresAt: &expires}
+
+	if b.Expired(expires.Add(-time.Second)) {
+		t.Error("Expired() before the expiry = true, want false")
+	}
+	if !b.Expired(expires) {
+		t.Error("Expired() at the expiry = false, want true")
+	}
+	if (&Behavior{}).Expired(expires) {
+		t.Error("Expired() without an expiry = true, want false")
+	}
+
+	node := BehaviorToNode(&b)
+	if node.Metadata[ExpiresAtKey] != "2026-04-01T00:00:00Z" {
+		t.Errorf("BehaviorToNode() expires_at = %v", node.Metadata[ExpiresAtKey])
+	}
+	got := NodeToBehavior(node)
+	if got.ExpiresAt == nil || !got.ExpiresAt.Equal(expires) {
+		t.Errorf("NodeToBehavior() ExpiresAt = %v, want %v", got.ExpiresAt, expires)
+	}
+}
+
+func TestBehaviorSnoozedUntil(t *testing.T) {
+	until := time.Date(2026, 4, 8, 0, 0, 0, 0, time.UTC)
+	b := Behavior{ID: "b1", Name: "noisy-reminder", SnoozedUntil: &until}
+
+	if !b.Snoozed(until.Add(-time.Second)) {
+		t.Error("Snoozed() before the snooze ends = false, want true")
+	}
+	if b.Snoozed(until) {
+		t.Error("Snoozed() when the snooze ends = true, want false")
+	}
+
+	got := NodeToBehavior(BehaviorToNode(&b))
+	if got.SnoozedUntil == nil || !got.SnoozedUntil.Equal(until) {
+		t.Errorf("round trip SnoozedUntil = %v, want %v", got.SnoozedUntil, until)
+	}
+}
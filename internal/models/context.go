@@ -28,6 +28,20 @@ type ContextSnapshot struct {
 	Branch      string      `json:"branch,omitempty" yaml:"branch,omitempty"`
 	ProjectType ProjectType `json:"project_type,omitempty" yaml:"project_type,omitempty"`
 
+	// Working tree state, filled in when git detection is enabled
+	BaseBranch      string   `json:"base_branch,omitempty" yaml:"base_branch,omitempty"`           // branch a pull request targets
+	ChangedFiles    []string `json:"changed_files,omitempty" yaml:"changed_files,omitempty"`       // modified, staged, or untracked files
+	StagedLanguages []string `json:"staged_languages,omitempty" yaml:"staged_languages,omitempty"` // languages of staged files
+
+	// Project fingerprint, detected from manifests, build files, and CI
+	// configuration at the repo root
+	ProjectLanguages []string `json:"project_languages,omitempty" yaml:"project_languages,omitempty"`
+	Frameworks       []string `json:"frameworks,omitempty" yaml:"frameworks,omitempty"`
+	BuildTools       []string `json:"build_tools,omitempty" yaml:"build_tools,omitempty"`
+	PackageManagers  []string `json:"package_managers,omitempty" yaml:"package_managers,omitempty"`
+	CISystems        []string `json:"ci,omitempty" yaml:"ci,omitempty"`
+	Containers       []string `json:"containers,omitempty" yaml:"containers,omitempty"`
+
 	// File info
 	FilePath     string `json:"file_path,omitempty" yaml:"file_path,omitempty"`
 	FileLanguage string `json:"file_language,omitempty" yaml:"file_language,omitempty"`
@@ -50,6 +64,12 @@ type ContextSnapshot struct {
 // Matches checks if this context matches a 'when' predicate
 func (c *ContextSnapshot) Matches(predicate map[string]interface{}) bool {
 	for key, required := range predicate {
+		if key == PathPrefixKey {
+			if _, ok := c.PathPrefixMatch(required); !ok {
+				return false
+			}
+			continue
+		}
 		actual := c.GetField(key)
 		if !matchValue(actual, required) {
 			return false
@@ -68,6 +88,13 @@ func (c *ContextSnapshot) MatchField(key string, required interface{}) (matched,
 	if actual == nil || actual == "" {
 		return false, false // absent
 	}
+	if list, ok := actual.([]string); ok && len(list) == 0 {
+		return false, false // absent
+	}
+	if key == PathPrefixKey {
+		_, ok := c.PathPrefixMatch(required)
+		return ok, true
+	}
 	return matchValue(actual, required), true
 }
 
@@ -78,9 +105,27 @@ func (c *ContextSnapshot) GetField(key string) interface{} {
 		return c.Repo
 	case "branch":
 		return c.Branch
+	case "base_branch", "target_branch":
+		return c.BaseBranch
+	case "changed_files":
+		return c.ChangedFiles
+	case "staged_languages":
+		return c.StagedLanguages
 	case "project_type":
 		return string(c.ProjectType)
-	case "file_path", "file.path":
+	case "project_language":
+		return c.ProjectLanguages
+	case "framework":
+		return c.Frameworks
+	case "build":
+		return c.BuildTools
+	case "package_manager":
+		return c.PackageManagers
+	case "ci":
+		return c.CISystems
+	case "container":
+		return c.Containers
+	case "file_path", "file.path", PathPrefixKey:
 		return c.FilePath
 	case "file_language", "file.language", "language":
 		return c.FileLanguage
@@ -100,50 +145,29 @@ func (c *ContextSnapshot) GetField(key string) interface{} {
 	}
 }
 
-// matchValue checks if an actual value matches a required value
-// Supports: exact match, array membership, glob patterns
+// IsBuiltinField reports whether key names a built-in context field (or
+// one of its aliases). Any other key is looked up in Custom.
+func IsBuiltinField(key string) bool {
+	probe := ContextSnapshot{Custom: map[string]interface{}{key: customProbe{}}}
+	_, custom := probe.GetField(key).(customProbe)
+	return !custom
+}
+
+// customProbe marks the value IsBuiltinField plants in Custom.
+type customProbe struct{}
+
+// matchValue checks if an actual value matches a required value.
+// Supports: exact match, list membership, glob and regex patterns (see
+// MatchPattern), in single values and list options alike.
 func matchValue(actual interface{}, required interface{}) bool {
 	if actual == nil {
 		return false
 	}
 
-	actualStr, actualIsStr := actual.(string)
-
-	switch req := required.(type) {
-	case string:
-		if !actualIsStr {
-			return false
-		}
-		// Support glob patterns
-		if strings.Contains(req, "*") {
-			// Normalize path separators for cross-platform glob matching
-			matched, _ := filepath.Match(filepath.FromSlash(req), filepath.FromSlash(actualStr))
-			return matched
-		}
-		return actualStr == req
-
-	case []interface{}:
-		// Value must be one of the options
-		for _, option := range req {
-			if optStr, ok := option.(string); ok && actualIsStr {
-				if actualStr == optStr {
-					return true
-				}
-			}
-		}
-		return false
-
-	case []string:
-		if !actualIsStr {
-			return false
-		}
-		for _, option := range req {
-			if actualStr == option {
-				return true
-			}
-		}
-		return false
-
+	switch required.(type) {
+	case string, []string, []interface{}:
+		_, ok := MatchedPattern(actual, required)
+		return ok
 	default:
 		return actual == required
 	}
@@ -236,6 +236,38 @@ func TestContextSnapshot_MatchField(t *testing.T) {
 			wantMatched:  false,
 			wantHasValue: false,
 		},
+		{
+			name:         "confirmed - any changed file matches",
+			ctx:          ContextSnapshot{ChangedFiles: []string{"README.md", "db/001_init.sql"}},
+			key:          "changed_files",
+			required:     "**/*.sql",
+			wantMatched:  true,
+			wantHasValue: true,
+		},
+		{
+			name:         "contradicted - no changed file matches",
+			ctx:          ContextSnapshot{ChangedFiles: []string{"README.md"}},
+			key:          "changed_files",
+			required:     "**/*.sql",
+			wantMatched:  false,
+			wantHasValue: true,
+		},
+		{
+			name:         "absent - no changed files",
+			ctx:          ContextSnapshot{ChangedFiles: []string{}},
+			key:          "changed_files",
+			required:     "**/*.sql",
+			wantMatched:  false,
+			wantHasValue: false,
+		},
+		{
+			name:         "confirmed - target branch alias",
+			ctx:          ContextSnapshot{BaseBranch: "release/2.x"},
+			key:          "target_branch",
+			required:     "release/*",
+			wantMatched:  true,
+			wantHasValue: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -300,6 +332,17 @@ func TestMatchValue(t *testing.T) {
 		{"string mismatch", "hello", "world", false},
 		{"glob match star", "test.go", "*.go", true},
 		{"glob no match", "test.go", "*.py", false},
+		{"double star glob match", "internal/store/sqlite_test.go", "**/*_test.go", true},
+		{"double star glob matches root", "main_test.go", "**/*_test.go", true},
+		{"double star glob in middle", "cmd/floop/main.go", "cmd/**/main.go", true},
+		{"double star glob no match", "internal/store/sqlite.go", "**/*_test.go", false},
+		{"question mark glob", "v1.go", "v?.go", true},
+		{"literal brackets match exactly", "app/[id]/page.tsx", "app/[id]/page.tsx", true},
+		{"regex match", "cmd/floop/main.go", "re:^cmd/.*\\.go$", true},
+		{"regex no match", "internal/main.go", "re:^cmd/", false},
+		{"invalid regex never matches", "cmd/main.go", "re:(", false},
+		{"glob in list", "internal/x_test.go", []interface{}{"*.md", "**/*_test.go"}, true},
+		{"regex in string list", "typescript", []string{"go", "re:^type"}, true},
 		{"non-string actual with string required", 123, "123", false},
 		{"interface array match", "b", []interface{}{"a", "b", "c"}, true},
 		{"interface array no match", "d", []interface{}{"a", "b", "c"}, false},
@@ -527,3 +570,16 @@ func TestProjectType_String(t *testing.T) {
 		})
 	}
 }
+
+func TestIsBuiltinField(t *testing.T) {
+	for _, key := range []string{"task", "env", "language", "file.path", PathPrefixKey, "framework", "target_branch"} {
+		if !IsBuiltinField(key) {
+			t.Errorf("IsBuiltinField(%q) = false, want true", key)
+		}
+	}
+	for _, key := range []string{"team", "stage", "custom"} {
+		if IsBuiltinField(key) {
+			t.Errorf("IsBuiltinField(%q) = true, want false", key)
+		}
+	}
+}
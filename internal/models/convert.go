@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/nvandessel/floop/internal/confidence"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -56,11 +57,27 @@ func NodeToBehavior(node store.Node) Behavior {
 		b.Content = content
 	}
 
+	// Extract conflicts ([]string in memory, []interface{} from SQLite)
+	switch conflicts := node.Content["conflicts"].(type) {
+	case []string:
+		b.Conflicts = append(b.Conflicts, conflicts...)
+	case []interface{}:
+		for _, c := range conflicts {
+			if s, ok := c.(string); ok {
+				b.Conflicts = append(b.Conflicts, s)
+			}
+		}
+	}
+
 	// Extract confidence from metadata
 	if confidence, ok := node.Metadata["confidence"].(float64); ok {
 		b.Confidence = confidence
 	}
 
+	if sources, ok := confidence.FromMetadata(node.Metadata); ok {
+		b.ConfidenceSources = &sources
+	}
+
 	// Extract priority from metadata
 	if priority, ok := node.Metadata["priority"].(int); ok {
 		b.Priority = priority
@@ -81,6 +98,21 @@ func NodeToBehavior(node store.Node) Behavior {
 		if author, ok := provenance["author"].(string); ok {
 			b.Provenance.Author = author
 		}
+		if adjusted, ok := provenance["human_adjusted"].(bool); ok {
+			b.Provenance.HumanAdjusted = adjusted
+		}
+		if adjustedAt, ok := provenance["adjusted_at"].(string); ok {
+			if t, err := time.Parse(time.RFC3339, adjustedAt); err == nil {
+				b.Provenance.AdjustedAt = &t
+			}
+		}
+	}
+
+	if expiresAt, ok := ParseExpiresAt(node.Metadata); ok {
+		b.ExpiresAt = &expiresAt
+	}
+	if snoozedUntil, ok := metadataTime(node.Metadata, SnoozedUntilKey); ok {
+		b.SnoozedUntil = &snoozedUntil
 	}
 
 	// Extract stats from metadata
@@ -97,6 +129,12 @@ func NodeToBehavior(node store.Node) Behavior {
 		if overridden, ok := stats["times_overridden"].(int); ok {
 			b.Stats.TimesOverridden = overridden
 		}
+		if sessions, ok := stats["sessions_activated"].(int); ok {
+			b.Stats.SessionsActivated = sessions
+		}
+		if sessionID, ok := stats["last_session_id"].(string); ok {
+			b.Stats.LastSessionID = sessionID
+		}
 
 		// Extract time fields (stored as RFC3339 strings by SQLite store)
 		if ca, ok := stats["created_at"].(string); ok {
@@ -126,7 +164,7 @@ func NodeToBehavior(node store.Node) Behavior {
 
 // BehaviorToNode converts a Behavior to a store.Node.
 func BehaviorToNode(b *Behavior) store.Node {
-	return store.Node{
+	node := store.Node{
 		ID:   b.ID,
 		Kind: store.NodeKindBehavior,
 		Content: map[string]interface{}{
@@ -142,4 +180,41 @@ func BehaviorToNode(b *Behavior) store.Node {
 			"provenance": b.Provenance,
 		},
 	}
+	if len(b.Conflicts) > 0 {
+		node.Content["conflicts"] = b.Conflicts
+	}
+	if b.ConfidenceSources != nil {
+		node.Metadata[confidence.MetadataKey] = b.ConfidenceSources.Map()
+	}
+	if b.ExpiresAt != nil {
+		node.Metadata[ExpiresAtKey] = b.ExpiresAt.UTC().Format(time.RFC3339)
+	}
+	if b.SnoozedUntil != nil {
+		node.Metadata[SnoozedUntilKey] = b.SnoozedUntil.UTC().Format(time.RFC3339)
+	}
+	return node
+}
+
+// Node metadata keys holding behavior times as RFC3339 strings.
+const (
+	ExpiresAtKey    = "expires_at"
+	SnoozedUntilKey = "snoozed_until"
+)
+
+// ParseExpiresAt returns the expiry recorded in node metadata, if any.
+func ParseExpiresAt(metadata map[string]interface{}) (time.Time, bool) {
+	return metadataTime(metadata, ExpiresAtKey)
+}
+
+// metadataTime parses the time stored under key in node metadata.
+func metadataTime(metadata map[string]interface{}, key string) (time.Time, bool) {
+	switch v := metadata[key].(type) {
+	case time.Time:
+		return v, true
+	case string:
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
 }
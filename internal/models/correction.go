@@ -34,6 +34,10 @@ type Correction struct {
 	// Extra tags provided by the user (merged with inferred tags during extraction)
 	ExtraTags []string `json:"extra_tags,omitempty" yaml:"extra_tags,omitempty"`
 
+	// ExpiresAt, if set, is given to the extracted behavior for temporary
+	// guidance such as a workaround during a migration
+	ExpiresAt *time.Time `json:"expires_at,omitempty" yaml:"expires_at,omitempty"`
+
 	// Processing state
 	Processed   bool       `json:"processed" yaml:"processed"`
 	ProcessedAt *time.Time `json:"processed_at,omitempty" yaml:"processed_at,omitempty"`
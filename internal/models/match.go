@@ -0,0 +1,143 @@
+package models
+
+import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// RegexPrefix marks a when-condition value as a regular expression, e.g.
+// "re:^cmd/.*\.go$". Other values containing *, ? or [ are glob patterns,
+// where ** matches any number of directories.
+const RegexPrefix = "re:"
+
+// Pattern kinds reported by PatternKind.
+const (
+	PatternValue = "value"
+	PatternGlob  = "glob"
+	PatternRegex = "regex"
+)
+
+// regexCache holds compiled regex conditions; a nil entry marks an invalid
+// expression, which never matches.
+var regexCache sync.Map // string -> *regexp.Regexp
+
+// PatternKind reports how a when-condition value is matched: as a regular
+// expression, a glob pattern, or an exact value.
+func PatternKind(pattern string) string {
+	switch {
+	case strings.HasPrefix(pattern, RegexPrefix):
+		return PatternRegex
+	case strings.ContainsAny(pattern, "*?["):
+		return PatternGlob
+	default:
+		return PatternValue
+	}
+}
+
+// MatchPattern reports whether actual matches a single when-condition value.
+// An exact match always counts, so literal values containing glob
+// characters (such as app/[id]/page.tsx) still match themselves.
+func MatchPattern(pattern, actual string) bool {
+	if pattern == actual {
+		return true
+	}
+	switch PatternKind(pattern) {
+	case PatternRegex:
+		re := compileRegex(strings.TrimPrefix(pattern, RegexPrefix))
+		return re != nil && re.MatchString(actual)
+	case PatternGlob:
+		return globMatch(filepath.ToSlash(pattern), filepath.ToSlash(actual))
+	default:
+		return false
+	}
+}
+
+// MatchedPattern returns the value in required that actual matched: the
+// pattern itself for a single value, or the first matching option of a
+// list. A list of actual values, such as changed files, matches if any of
+// them does. ok is false when nothing matched or the values are not
+// strings.
+func MatchedPattern(actual, required interface{}) (pattern string, ok bool) {
+	if list, isList := actual.([]string); isList {
+		for _, a := range list {
+			if pattern, ok := MatchedPattern(a, required); ok {
+				return pattern, true
+			}
+		}
+		return "", false
+	}
+	actualStr, isStr := actual.(string)
+	if !isStr {
+		return "", false
+	}
+	for _, option := range patternOptions(required) {
+		if MatchPattern(option, actualStr) {
+			return option, true
+		}
+	}
+	return "", false
+}
+
+// patternOptions returns the string values of a single or list condition.
+func patternOptions(required interface{}) []string {
+	switch req := required.(type) {
+	case string:
+		return []string{req}
+	case []string:
+		return req
+	case []interface{}:
+		options := make([]string, 0, len(req))
+		for _, option := range req {
+			if s, ok := option.(string); ok {
+				options = append(options, s)
+			}
+		}
+		return options
+	default:
+		return nil
+	}
+}
+
+func compileRegex(expr string) *regexp.Regexp {
+	if cached, ok := regexCache.Load(expr); ok {
+		return cached.(*regexp.Regexp)
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		re = nil
+	}
+	regexCache.Store(expr, re)
+	return re
+}
+
+// globMatch matches a slash-separated name against a glob pattern segment by
+// segment. A ** segment matches zero or more whole segments, so
+// "**/*_test.go" matches test files at any depth, including the root.
+func globMatch(pattern, name string) bool {
+	return matchSegments(strings.Split(pattern, "/"), strings.Split(name, "/"))
+}
+
+func matchSegments(pattern, name []string) bool {
+	for len(pattern) > 0 {
+		if pattern[0] == "**" {
+			rest := pattern[1:]
+			for i := 0; i <= len(name); i++ {
+				if matchSegments(rest, name[i:]) {
+					return true
+				}
+			}
+			return false
+		}
+		if len(name) == 0 {
+			return false
+		}
+		if ok, err := path.Match(pattern[0], name[0]); err != nil || !ok {
+			return false
+		}
+		pattern, name = pattern[1:], name[1:]
+	}
+	return len(name) == 0
+}
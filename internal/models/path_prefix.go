@@ -0,0 +1,96 @@
+package models
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// PathPrefixKey is the when-condition key for directory-scoped behaviors.
+// A behavior with "path_prefix: internal/store" activates for files in
+// internal/store and, by inheritance, every subdirectory below it.
+const PathPrefixKey = "path_prefix"
+
+// normalizeDir cleans a directory or file path into slash-separated form
+// relative to the repo root. The repo root itself normalizes to "".
+func normalizeDir(p string) string {
+	p = path.Clean(filepath.ToSlash(strings.TrimSpace(p)))
+	p = strings.TrimPrefix(p, "/")
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// PathPrefixDepth returns how many directory levels prefix names, e.g. 2 for
+// "internal/store". The repo root has depth 0.
+func PathPrefixDepth(prefix string) int {
+	dir := normalizeDir(prefix)
+	if dir == "" {
+		return 0
+	}
+	return strings.Count(dir, "/") + 1
+}
+
+// PathWithin reports whether filePath is inside the directory prefix.
+// Both are interpreted relative to the repo root; matching is by whole path
+// components, so "internal/store" does not contain "internal/storage".
+func PathWithin(filePath, prefix string) bool {
+	dir := normalizeDir(prefix)
+	if dir == "" {
+		return true
+	}
+	f := normalizeDir(filePath)
+	return f == dir || strings.HasPrefix(f, dir+"/")
+}
+
+// RepoRelativePath returns the context's file path relative to the repo
+// root. Absolute paths outside the repo root are returned unchanged.
+func (c *ContextSnapshot) RepoRelativePath() string {
+	if c.FilePath == "" || !filepath.IsAbs(c.FilePath) || c.RepoRoot == "" {
+		return c.FilePath
+	}
+	root, err := filepath.Abs(c.RepoRoot)
+	if err != nil {
+		return c.FilePath
+	}
+	rel, err := filepath.Rel(root, c.FilePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return c.FilePath
+	}
+	return rel
+}
+
+// PathPrefixMatch checks a path_prefix condition against the context's file.
+// required may be a single prefix or a list of prefixes. It returns the depth
+// of the deepest matching prefix, or ok=false if none match.
+func (c *ContextSnapshot) PathPrefixMatch(required interface{}) (depth int, ok bool) {
+	filePath := c.RepoRelativePath()
+
+	var prefixes []string
+	switch req := required.(type) {
+	case string:
+		prefixes = []string{req}
+	case []string:
+		prefixes = req
+	case []interface{}:
+		for _, v := range req {
+			if s, isStr := v.(string); isStr {
+				prefixes = append(prefixes, s)
+			}
+		}
+	}
+
+	depth = -1
+	for _, prefix := range prefixes {
+		if PathWithin(filePath, prefix) {
+			if d := PathPrefixDepth(prefix); d > depth {
+				depth = d
+			}
+		}
+	}
+	if depth < 0 {
+		return 0, false
+	}
+	return depth, true
+}
@@ -0,0 +1,71 @@
+package models
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestPathWithin(t *testing.T) {
+	tests := []struct {
+		file, prefix string
+		want         bool
+	}{
+		{"internal/store/sqlite.go", "internal/store", true},
+		{"internal/store/sqlite/graph.go", "internal", true},
+		{"internal/store", "internal/store", true},
+		{"./internal/store/x.go", "internal/store/", true},
+		{"internal/storage/x.go", "internal/store", false},
+		{"cmd/floop/main.go", "internal", false},
+		{"main.go", "", true},
+		{"main.go", ".", true},
+	}
+	for _, tt := range tests {
+		if got := PathWithin(tt.file, tt.prefix); got != tt.want {
+			t.Errorf("PathWithin(%q, %q) = %v, want %v", tt.file, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestPathPrefixDepth(t *testing.T) {
+	tests := map[string]int{"": 0, ".": 0, "internal": 1, "internal/store/": 2, "./a/b/c": 3}
+	for prefix, want := range tests {
+		if got := PathPrefixDepth(prefix); got != want {
+			t.Errorf("PathPrefixDepth(%q) = %d, want %d", prefix, got, want)
+		}
+	}
+}
+
+func TestContextSnapshot_PathPrefixMatch(t *testing.T) {
+	root := t.TempDir()
+	ctx := ContextSnapshot{
+		RepoRoot: root,
+		FilePath: filepath.Join(root, "internal", "store", "sqlite.go"),
+	}
+
+	depth, ok := ctx.PathPrefixMatch("internal/store")
+	if !ok || depth != 2 {
+		t.Errorf("absolute path: got (%d, %v), want (2, true)", depth, ok)
+	}
+
+	depth, ok = ctx.PathPrefixMatch([]interface{}{"internal", "internal/store", "cmd"})
+	if !ok || depth != 2 {
+		t.Errorf("list: got (%d, %v), want deepest match (2, true)", depth, ok)
+	}
+
+	if _, ok := ctx.PathPrefixMatch("cmd"); ok {
+		t.Error("cmd should not match")
+	}
+
+	matched, hasValue := ctx.MatchField(PathPrefixKey, "internal")
+	if !matched || !hasValue {
+		t.Errorf("MatchField(path_prefix) = (%v, %v), want (true, true)", matched, hasValue)
+	}
+	if !ctx.Matches(map[string]interface{}{PathPrefixKey: "internal/store"}) {
+		t.Error("Matches should honor path_prefix inheritance")
+	}
+
+	empty := ContextSnapshot{}
+	if _, hasValue := empty.MatchField(PathPrefixKey, "internal"); hasValue {
+		t.Error("path_prefix should be absent without a file path")
+	}
+}
@@ -12,6 +12,7 @@ const (
 	SourceTypeLearned      SourceType = "learned"      // Extracted from a correction
 	SourceTypeImported     SourceType = "imported"     // From an external package
 	SourceTypeConsolidated SourceType = "consolidated" // Consolidated from multiple events
+	SourceTypeManual       SourceType = "manual"       // Hand-authored with 'floop new'
 )
 
 // Provenance tracks where a behavior came from
@@ -42,4 +43,8 @@ type Provenance struct {
 	SourceAgent   string `json:"source_agent,omitempty" yaml:"source_agent,omitempty"`
 	SourceProject string `json:"source_project,omitempty" yaml:"source_project,omitempty"`
 	SourceBranch  string `json:"source_branch,omitempty" yaml:"source_branch,omitempty"`
+
+	// Human review of automated output (e.g. an edited dedup merge)
+	HumanAdjusted bool       `json:"human_adjusted,omitempty" yaml:"human_adjusted,omitempty"`
+	AdjustedAt    *time.Time `json:"adjusted_at,omitempty" yaml:"adjusted_at,omitempty"`
 }
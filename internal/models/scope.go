@@ -5,12 +5,12 @@ import (
 )
 
 // localScopeKeys are When condition keys that indicate project-specific behaviors.
-// file_path implies project directory structure.
-var localScopeKeys = []string{"file_path"}
+// file_path and path_prefix imply project directory structure.
+var localScopeKeys = []string{"file_path", PathPrefixKey}
 
 // ClassifyScope determines whether a behavior should be stored locally or globally
-// based on its When conditions. Behaviors with project-specific conditions (file_path)
-// are local; everything else (language-only, task-only, empty) is global.
+// based on its When conditions. Behaviors with project-specific conditions (file_path,
+// path_prefix) are local; everything else (language-only, task-only, empty) is global.
 func ClassifyScope(behavior *Behavior) constants.Scope {
 	if behavior.When == nil {
 		return constants.ScopeGlobal
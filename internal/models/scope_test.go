@@ -22,6 +22,13 @@ func TestClassifyScope(t *testing.T) {
 			when: map[string]interface{}{},
 			want: constants.ScopeGlobal,
 		},
+		{
+			name: "path_prefix is local",
+			when: map[string]interface{}{
+				"path_prefix": "internal/store",
+			},
+			want: constants.ScopeLocal,
+		},
 		{
 			name: "language only is global",
 			when: map[string]interface{}{
@@ -0,0 +1,156 @@
+// Package nearmiss tracks how often behaviors nearly activate and suggests
+// condition relaxations for the ones that keep missing.
+//
+// The activation evaluator reports a near miss when a behavior confirmed some
+// of its when-conditions but was excluded by a few contradicted ones. Each
+// near miss is counted on the behavior's metadata, per failing condition, so
+// a condition that repeatedly blocks an otherwise relevant behavior can be
+// surfaced for refinement.
+package nearmiss
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
+)
+
+// Metadata keys recorded on behaviors that nearly activated.
+const (
+	// MetaCount is the total number of near misses.
+	MetaCount = "near_miss_count"
+
+	// MetaConditions maps each failing condition key to its near-miss count.
+	MetaConditions = "near_miss_conditions"
+
+	// MetaLastAt is the RFC 3339 time of the most recent near miss.
+	MetaLastAt = "near_miss_last_at"
+)
+
+// DefaultSuggestAfter is the default number of near misses on one condition
+// before relaxing it is suggested.
+const DefaultSuggestAfter = 5
+
+// Stats is the recorded near-miss history of a behavior.
+type Stats struct {
+	Count      int            `json:"count"`
+	Conditions map[string]int `json:"conditions,omitempty"`
+	LastAt     *time.Time     `json:"last_at,omitempty"`
+}
+
+// Suggestion proposes relaxing a condition that repeatedly blocks a behavior.
+type Suggestion struct {
+	BehaviorID string      `json:"behavior_id"`
+	Condition  string      `json:"condition"`
+	Required   interface{} `json:"required"`
+	Count      int         `json:"count"`
+	Message    string      `json:"message"`
+}
+
+// Record counts each near miss on its behavior's metadata. Behaviors that no
+// longer exist are skipped. It returns the first write error encountered
+// after attempting every near miss.
+func Record(ctx context.Context, s store.GraphStore, misses []activation.NearMiss, now time.Time) error {
+	var firstErr error
+	for _, miss := range misses {
+		if err := recordOne(ctx, s, miss, now); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func recordOne(ctx context.Context, s store.GraphStore, miss activation.NearMiss, now time.Time) error {
+	node, err := s.GetNode(ctx, miss.Behavior.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get behavior %s: %w", miss.Behavior.ID, err)
+	}
+	if node == nil {
+		return nil
+	}
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+
+	stats := StatsFromMetadata(node.Metadata)
+	stats.Count++
+	for _, key := range miss.FailedConditions {
+		stats.Conditions[key]++
+	}
+
+	conditions := make(map[string]interface{}, len(stats.Conditions))
+	for k, v := range stats.Conditions {
+		conditions[k] = v
+	}
+	node.Metadata[MetaCount] = stats.Count
+	node.Metadata[MetaConditions] = conditions
+	node.Metadata[MetaLastAt] = now.Format(time.RFC3339)
+
+	if err := s.UpdateNode(ctx, *node); err != nil {
+		return fmt.Errorf("failed to record near miss for %s: %w", miss.Behavior.ID, err)
+	}
+	return nil
+}
+
+// StatsFromMetadata reads the near-miss history from node metadata.
+// Conditions is never nil.
+func StatsFromMetadata(m map[string]interface{}) Stats {
+	stats := Stats{
+		Count:      utils.GetInt(m, MetaCount, 0),
+		Conditions: make(map[string]int),
+	}
+	for k, v := range utils.GetMap(m, MetaConditions) {
+		switch n := v.(type) {
+		case int:
+			stats.Conditions[k] = n
+		case int64:
+			stats.Conditions[k] = int(n)
+		case float64:
+			stats.Conditions[k] = int(n)
+		}
+	}
+	if s, ok := m[MetaLastAt].(string); ok {
+		if t, err := time.Parse(time.RFC3339, s); err == nil {
+			stats.LastAt = &t
+		}
+	}
+	return stats
+}
+
+// Suggest returns relaxation suggestions for conditions of when that have
+// blocked the behavior at least suggestAfter times, most frequent first.
+func Suggest(behaviorID string, when map[string]interface{}, stats Stats, suggestAfter int) []Suggestion {
+	if suggestAfter <= 0 {
+		suggestAfter = DefaultSuggestAfter
+	}
+
+	var suggestions []Suggestion
+	for key, count := range stats.Conditions {
+		if count < suggestAfter {
+			continue
+		}
+		required, ok := when[key]
+		if !ok {
+			continue // condition was already changed
+		}
+		suggestions = append(suggestions, Suggestion{
+			BehaviorID: behaviorID,
+			Condition:  key,
+			Required:   required,
+			Count:      count,
+			Message:    fmt.Sprintf("%s=%v blocked activation %d times; consider broadening or removing it", key, required, count),
+		})
+	}
+
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		return suggestions[i].Condition < suggestions[j].Condition
+	})
+	return suggestions
+}
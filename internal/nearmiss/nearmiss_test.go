@@ -0,0 +1,89 @@
+package nearmiss
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestRecord(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	if _, err := s.AddNode(ctx, store.Node{
+		ID:      "b1",
+		Kind:    store.NodeKindBehavior,
+		Content: map[string]interface{}{"name": "b1"},
+	}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+
+	misses := []activation.NearMiss{
+		{Behavior: models.Behavior{ID: "b1"}, FailedConditions: []string{"task"}},
+		{Behavior: models.Behavior{ID: "missing"}, FailedConditions: []string{"task"}},
+	}
+	for i := 0; i < 3; i++ {
+		if err := Record(ctx, s, misses, now); err != nil {
+			t.Fatalf("Record: %v", err)
+		}
+	}
+	if err := Record(ctx, s, []activation.NearMiss{
+		{Behavior: models.Behavior{ID: "b1"}, FailedConditions: []string{"language"}},
+	}, now.Add(time.Hour)); err != nil {
+		t.Fatalf("Record: %v", err)
+	}
+
+	node, err := s.GetNode(ctx, "b1")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode: %v", err)
+	}
+	stats := StatsFromMetadata(node.Metadata)
+	if stats.Count != 4 {
+		t.Errorf("Count = %d, want 4", stats.Count)
+	}
+	if stats.Conditions["task"] != 3 || stats.Conditions["language"] != 1 {
+		t.Errorf("Conditions = %v", stats.Conditions)
+	}
+	if stats.LastAt == nil || !stats.LastAt.Equal(now.Add(time.Hour)) {
+		t.Errorf("LastAt = %v", stats.LastAt)
+	}
+}
+
+func TestStatsFromMetadata_JSONNumbers(t *testing.T) {
+	stats := StatsFromMetadata(map[string]interface{}{
+		MetaCount:      float64(7),
+		MetaConditions: map[string]interface{}{"task": float64(5)},
+		MetaLastAt:     "not a time",
+	})
+	if stats.Count != 7 || stats.Conditions["task"] != 5 || stats.LastAt != nil {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+	if empty := StatsFromMetadata(nil); empty.Count != 0 || empty.Conditions == nil {
+		t.Errorf("unexpected empty stats: %+v", empty)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	when := map[string]interface{}{"task": "refactor", "language": "go", "environment": "ci"}
+	stats := Stats{Conditions: map[string]int{"task": 6, "language": 9, "environment": 2, "removed": 20}}
+
+	got := Suggest("b1", when, stats, 5)
+	if len(got) != 2 {
+		t.Fatalf("len = %d, want 2: %+v", len(got), got)
+	}
+	if got[0].Condition != "language" || got[1].Condition != "task" {
+		t.Errorf("order = %s, %s; want language, task", got[0].Condition, got[1].Condition)
+	}
+	if got[1].Required != "refactor" || got[1].Count != 6 || got[1].Message == "" {
+		t.Errorf("unexpected suggestion: %+v", got[1])
+	}
+
+	if got := Suggest("b1", when, stats, 0); len(got) != 2 {
+		t.Errorf("default threshold: len = %d, want 2", len(got))
+	}
+}
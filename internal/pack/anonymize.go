@@ -0,0 +1,332 @@
+package pack
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Kinds of internal names rewritten by anonymization.
+const (
+	AnonymizeHost = "host"
+	AnonymizeUser = "user"
+	AnonymizeRepo = "repo"
+)
+
+// internalHostSuffixes are DNS suffixes that only resolve inside private
+// networks; hostnames ending in one are always rewritten.
+var internalHostSuffixes = []string{".internal", ".local", ".corp", ".lan", ".intranet", ".intra", ".private", ".home.arpa"}
+
+var (
+	hostnamePattern = regexp.MustCompile(`(?i)\b(?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+[a-z][a-z0-9-]*[a-z0-9]\b`)
+	homeDirPattern  = regexp.MustCompile(`(/home/|/Users/|[A-Za-z]:\\Users\\)([A-Za-z0-9._-]+)`)
+)
+
+// AnonymizeRules names the internal names to rewrite in addition to
+// hostnames with internal suffixes and usernames in home directory paths,
+// which are detected automatically.
+type AnonymizeRules struct {
+	// Domains are internal domains; the domain and its subdomains are
+	// rewritten as hosts.
+	Domains []string
+
+	// Usernames are rewritten wherever they appear as whole words.
+	Usernames []string
+
+	// Repos are repository names ("repo" or "owner/repo") rewritten
+	// wherever they appear as whole words.
+	Repos []string
+}
+
+// MappingEntry pairs an internal name with its placeholder.
+type MappingEntry struct {
+	Kind        string `json:"kind"`
+	Original    string `json:"original"`
+	Replacement string `json:"replacement"`
+}
+
+// AnonymizeMapping is the reversible mapping from internal names to
+// placeholders. It is kept locally, never in the pack, and reused across
+// pack versions so a name keeps the same placeholder.
+type AnonymizeMapping struct {
+	PackID  string         `json:"pack_id"`
+	Entries []MappingEntry `json:"entries"`
+}
+
+// Rewrite reports one internal name rewritten during anonymization.
+type Rewrite struct {
+	Kind        string   `json:"kind"`
+	Original    string   `json:"original"`
+	Replacement string   `json:"replacement"`
+	Count       int      `json:"count"`
+	BehaviorIDs []string `json:"behavior_ids"`
+}
+
+// AnonymizeReport summarizes an anonymization pass.
+type AnonymizeReport struct {
+	BehaviorsChanged int       `json:"behaviors_changed"`
+	Rewrites         []Rewrite `json:"rewrites"`
+	MappingPath      string    `json:"mapping_path,omitempty"`
+}
+
+// DefaultMappingPath returns where the mapping for packID is stored:
+// ~/.floop/anonymize/<namespace>/<name>.json.
+func DefaultMappingPath(packID string) (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+	return filepath.Join(home, ".floop", "anonymize", filepath.FromSlash(packID)+".json"), nil
+}
+
+// LoadMapping reads the mapping at path. A missing file yields an empty
+// mapping for packID.
+func LoadMapping(path, packID string) (*AnonymizeMapping, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &AnonymizeMapping{PackID: packID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading anonymization mapping: %w", err)
+	}
+	var m AnonymizeMapping
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, fmt.Errorf("parsing anonymization mapping %s: %w", path, err)
+	}
+	if m.PackID == "" {
+		m.PackID = packID
+	}
+	return &m, nil
+}
+
+// Save writes the mapping to path, readable only by the current user.
+func (m *AnonymizeMapping) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating mapping directory: %w", err)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing anonymization mapping: %w", err)
+	}
+	return nil
+}
+
+// replacement returns the placeholder for original, allocating one if
+// needed. Hosts use the reserved .example TLD so they never resolve.
+func (m *AnonymizeMapping) replacement(kind, original string) string {
+	n := 0
+	for _, e := range m.Entries {
+		if e.Kind != kind {
+			continue
+		}
+		if e.Original == original {
+			return e.Replacement
+		}
+		n++
+	}
+	r := fmt.Sprintf("%s-%d", kind, n+1)
+	if kind == AnonymizeHost {
+		r += ".example"
+	}
+	m.Entries = append(m.Entries, MappingEntry{Kind: kind, Original: original, Replacement: r})
+	return r
+}
+
+// Reverse restores the internal names in text anonymized with m.
+func (m *AnonymizeMapping) Reverse(text string) string {
+	entries := append([]MappingEntry(nil), m.Entries...)
+	// Longest first, so "user-10" is not restored as "user-1" + "0".
+	sort.Slice(entries, func(i, j int) bool { return len(entries[i].Replacement) > len(entries[j].Replacement) })
+	for _, e := range entries {
+		re := regexp.MustCompile(`\b` + regexp.QuoteMeta(e.Replacement) + `\b`)
+		text = re.ReplaceAllLiteralString(text, e.Original)
+	}
+	return text
+}
+
+// Anonymizer rewrites internal names in behavior nodes.
+type Anonymizer struct {
+	rules    AnonymizeRules
+	mapping  *AnonymizeMapping
+	names    []namePattern
+	rewrites map[string]*Rewrite
+	changed  map[string]bool
+	current  string
+}
+
+// namePattern matches one configured username or repo name.
+type namePattern struct {
+	kind string
+	re   *regexp.Regexp
+}
+
+// NewAnonymizer returns an Anonymizer that allocates placeholders from
+// mapping.
+func NewAnonymizer(rules AnonymizeRules, mapping *AnonymizeMapping) *Anonymizer {
+	a := &Anonymizer{
+		rules:    rules,
+		mapping:  mapping,
+		rewrites: make(map[string]*Rewrite),
+		changed:  make(map[string]bool),
+	}
+	add := func(kind string, names []string) {
+		for _, name := range names {
+			if name = strings.TrimSpace(name); name != "" {
+				a.names = append(a.names, namePattern{kind: kind, re: regexp.MustCompile(`\b` + regexp.QuoteMeta(name) + `\b`)})
+			}
+		}
+	}
+	add(AnonymizeRepo, rules.Repos)
+	add(AnonymizeUser, rules.Usernames)
+	// Longer names first so "acme/api" wins over "api".
+	sort.SliceStable(a.names, func(i, j int) bool { return len(a.names[i].re.String()) > len(a.names[j].re.String()) })
+	return a
+}
+
+// Node returns a copy of node with internal names rewritten in its name,
+// content, when conditions, and provenance. The original is not modified.
+func (a *Anonymizer) Node(node store.Node) store.Node {
+	a.current = node.ID
+
+	out := node
+	out.Content = make(map[string]interface{}, len(node.Content))
+	for k, v := range node.Content {
+		switch k {
+		case "name", "content", "when":
+			out.Content[k] = a.value(v)
+		default:
+			out.Content[k] = v
+		}
+	}
+	if node.Metadata != nil {
+		out.Metadata = make(map[string]interface{}, len(node.Metadata))
+		for k, v := range node.Metadata {
+			if k == "provenance" {
+				v = a.value(v)
+			}
+			out.Metadata[k] = v
+		}
+	}
+	return out
+}
+
+// Report returns the rewrites made so far, grouped by internal name.
+func (a *Anonymizer) Report() AnonymizeReport {
+	report := AnonymizeReport{BehaviorsChanged: len(a.changed), Rewrites: []Rewrite{}}
+	for _, r := range a.rewrites {
+		sort.Strings(r.BehaviorIDs)
+		report.Rewrites = append(report.Rewrites, *r)
+	}
+	sort.Slice(report.Rewrites, func(i, j int) bool {
+		if report.Rewrites[i].Kind != report.Rewrites[j].Kind {
+			return report.Rewrites[i].Kind < report.Rewrites[j].Kind
+		}
+		return report.Rewrites[i].Original < report.Rewrites[j].Original
+	})
+	return report
+}
+
+// value rewrites strings in v, copying maps and slices.
+func (a *Anonymizer) value(v interface{}) interface{} {
+	switch x := v.(type) {
+	case string:
+		return a.text(x)
+	case []string:
+		out := make([]string, len(x))
+		for i, s := range x {
+			out[i] = a.text(s)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(x))
+		for i, item := range x {
+			out[i] = a.value(item)
+		}
+		return out
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(x))
+		for k, item := range x {
+			out[k] = a.value(item)
+		}
+		return out
+	case models.BehaviorContent:
+		x.Canonical = a.text(x.Canonical)
+		x.Summary = a.text(x.Summary)
+		if x.Structured != nil {
+			x.Structured = a.value(x.Structured).(map[string]interface{})
+		}
+		if x.Tags != nil {
+			x.Tags = a.value(x.Tags).([]string)
+		}
+		return x
+	}
+	return v
+}
+
+// text rewrites the internal names in s.
+func (a *Anonymizer) text(s string) string {
+	s = homeDirPattern.ReplaceAllStringFunc(s, func(m string) string {
+		parts := homeDirPattern.FindStringSubmatch(m)
+		return parts[1] + a.record(AnonymizeUser, parts[2])
+	})
+	s = hostnamePattern.ReplaceAllStringFunc(s, func(host string) string {
+		if !a.isInternalHost(host) {
+			return host
+		}
+		return a.record(AnonymizeHost, strings.ToLower(host))
+	})
+	for _, n := range a.names {
+		s = n.re.ReplaceAllStringFunc(s, func(name string) string {
+			return a.record(n.kind, name)
+		})
+	}
+	return s
+}
+
+// isInternalHost reports whether host is on an internal suffix or one of
+// the configured domains.
+func (a *Anonymizer) isInternalHost(host string) bool {
+	host = strings.ToLower(host)
+	if strings.HasSuffix(host, ".example") {
+		return false // already a placeholder
+	}
+	for _, suffix := range internalHostSuffixes {
+		if strings.HasSuffix(host, suffix) {
+			return true
+		}
+	}
+	for _, d := range a.rules.Domains {
+		d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "."))
+		if d != "" && (host == d || strings.HasSuffix(host, "."+d)) {
+			return true
+		}
+	}
+	return false
+}
+
+// record allocates the placeholder for original and counts the rewrite.
+func (a *Anonymizer) record(kind, original string) string {
+	replacement := a.mapping.replacement(kind, original)
+	key := kind + "\x00" + original
+	r, ok := a.rewrites[key]
+	if !ok {
+		r = &Rewrite{Kind: kind, Original: original, Replacement: replacement}
+		a.rewrites[key] = r
+	}
+	r.Count++
+	a.changed[a.current] = true
+	if len(r.BehaviorIDs) == 0 || r.BehaviorIDs[len(r.BehaviorIDs)-1] != a.current {
+		r.BehaviorIDs = append(r.BehaviorIDs, a.current)
+	}
+	return replacement
+}
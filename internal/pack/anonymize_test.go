@@ -0,0 +1,162 @@
+package pack
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func anonymizeTestNode() store.Node {
+	return store.Node{
+		ID:   "b-anon",
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name": "deploy-via-bastion",
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": "Deploy acme/payments through bastion.corp and db1.acme.io; keys live in /home/jdoe/.ssh",
+				"tags":      []interface{}{"deploy"},
+			},
+			"when": map[string]interface{}{"file_path": "/Users/jdoe/src/payments/*.go"},
+		},
+		Metadata: map[string]interface{}{
+			"provenance": map[string]interface{}{"source_type": "learned", "scope": "local"},
+		},
+	}
+}
+
+func TestAnonymizer_Node(t *testing.T) {
+	mapping := &AnonymizeMapping{PackID: "acme/ops"}
+	a := NewAnonymizer(AnonymizeRules{Domains: []string{"acme.io"}, Repos: []string{"acme/payments"}}, mapping)
+
+	orig := anonymizeTestNode()
+	got := a.Node(orig)
+
+	canonical := got.Content["content"].(map[string]interface{})["canonical"].(string)
+	want := "Deploy repo-1 through host-1.example and host-2.example; keys live in /home/user-1/.ssh"
+	if canonical != want {
+		t.Errorf("canonical = %q, want %q", canonical, want)
+	}
+	when := got.Content["when"].(map[string]interface{})["file_path"].(string)
+	if when != "/Users/user-1/src/payments/*.go" {
+		t.Errorf("when.file_path = %q", when)
+	}
+
+	// The input node must not be modified.
+	origCanonical := orig.Content["content"].(map[string]interface{})["canonical"].(string)
+	if !strings.Contains(origCanonical, "bastion.corp") {
+		t.Errorf("original node was modified: %q", origCanonical)
+	}
+
+	// The mapping reverses the rewrite.
+	if back := mapping.Reverse(canonical); back != origCanonical {
+		t.Errorf("Reverse() = %q, want %q", back, origCanonical)
+	}
+
+	report := a.Report()
+	if report.BehaviorsChanged != 1 {
+		t.Errorf("BehaviorsChanged = %d, want 1", report.BehaviorsChanged)
+	}
+	counts := make(map[string]int)
+	for _, r := range report.Rewrites {
+		counts[r.Kind+":"+r.Original] = r.Count
+	}
+	wantCounts := map[string]int{
+		"host:bastion.corp":  1,
+		"host:db1.acme.io":   1,
+		"repo:acme/payments": 1,
+		"user:jdoe":          2,
+	}
+	for k, n := range wantCounts {
+		if counts[k] != n {
+			t.Errorf("rewrite count %s = %d, want %d (report %+v)", k, counts[k], n, report.Rewrites)
+		}
+	}
+}
+
+func TestAnonymizer_LeavesPublicNamesAlone(t *testing.T) {
+	a := NewAnonymizer(AnonymizeRules{}, &AnonymizeMapping{})
+	node := store.Node{
+		ID:      "b-public",
+		Content: map[string]interface{}{"content": map[string]interface{}{"canonical": "Use slog from golang.org and see docs.github.com"}},
+	}
+	got := a.Node(node)
+	canonical := got.Content["content"].(map[string]interface{})["canonical"].(string)
+	if canonical != "Use slog from golang.org and see docs.github.com" {
+		t.Errorf("canonical = %q, want unchanged", canonical)
+	}
+	if report := a.Report(); report.BehaviorsChanged != 0 || len(report.Rewrites) != 0 {
+		t.Errorf("report = %+v, want no rewrites", report)
+	}
+}
+
+func TestAnonymizeMapping_StableAcrossRuns(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "acme", "ops.json")
+
+	m1, err := LoadMapping(path, "acme/ops")
+	if err != nil {
+		t.Fatalf("LoadMapping() error = %v", err)
+	}
+	NewAnonymizer(AnonymizeRules{}, m1).Node(anonymizeTestNode())
+	if err := m1.Save(path); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	m2, err := LoadMapping(path, "acme/ops")
+	if err != nil {
+		t.Fatalf("LoadMapping() error = %v", err)
+	}
+	a := NewAnonymizer(AnonymizeRules{Usernames: []string{"asmith"}}, m2)
+	got := a.Node(store.Node{
+		ID:      "b-2",
+		Content: map[string]interface{}{"name": "asmith owns bastion.corp, jdoe owns the rest"},
+	})
+	if name := got.Content["name"].(string); name != "user-2 owns host-1.example, jdoe owns the rest" {
+		t.Errorf("name = %q", name)
+	}
+	if m2.Reverse("user-1 and user-2") != "jdoe and asmith" {
+		t.Errorf("Reverse() = %q", m2.Reverse("user-1 and user-2"))
+	}
+}
+
+func TestCreate_Anonymize(t *testing.T) {
+	s := makeTestStore(t)
+	ctx := context.Background()
+	s.AddNode(ctx, anonymizeTestNode())
+	outputPath := filepath.Join(t.TempDir(), "anon.fpack")
+
+	a := NewAnonymizer(AnonymizeRules{Domains: []string{"acme.io"}}, &AnonymizeMapping{})
+	result, err := Create(ctx, s, CreateFilter{}, PackManifest{ID: "acme/ops", Version: "1.0.0"}, outputPath, CreateOptions{Anonymizer: a})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if result.Anonymize == nil || result.Anonymize.BehaviorsChanged != 1 {
+		t.Fatalf("Anonymize report = %+v, want 1 behavior changed", result.Anonymize)
+	}
+
+	data, _, err := ReadPackFile(outputPath)
+	if err != nil {
+		t.Fatalf("ReadPackFile() error = %v", err)
+	}
+	for _, n := range data.Nodes {
+		if n.ID != "b-anon" {
+			continue
+		}
+		canonical := n.Content["content"].(map[string]interface{})["canonical"].(string)
+		if strings.Contains(canonical, "bastion.corp") || strings.Contains(canonical, "jdoe") {
+			t.Errorf("pack content not anonymized: %q", canonical)
+		}
+	}
+
+	stored, err := s.GetNode(ctx, "b-anon")
+	if err != nil {
+		t.Fatalf("GetNode() error = %v", err)
+	}
+	canonical := stored.Content["content"].(map[string]interface{})["canonical"].(string)
+	if !strings.Contains(canonical, "bastion.corp") {
+		t.Errorf("store was modified: %q", canonical)
+	}
+}
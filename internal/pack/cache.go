@@ -0,0 +1,203 @@
+package pack
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+)
+
+// staleDownloadAge is how old an interrupted download's temp file must be
+// before it is treated as orphaned, so in-flight downloads are left alone.
+const staleDownloadAge = time.Hour
+
+// CacheFile is a file in the pack download cache.
+type CacheFile struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// Cache file statuses, as reported by ListCache.
+const (
+	CacheInstalled = "installed" // an installed pack was installed from it
+	CacheKept      = "kept"      // a rollback version, or from a source still installed
+	CacheDownload  = "download"  // a temp or partial file from a download
+	CacheOrphaned  = "orphaned"  // no installed pack refers to it
+)
+
+// CacheEntry describes a file in the pack download cache. LastUsed is the
+// file's modification time, which cache hits refresh.
+type CacheEntry struct {
+	CacheFile
+	LastUsed time.Time `json:"last_used"`
+	Status   string    `json:"status"`
+}
+
+// ListCache returns every file in cacheDir with its status relative to the
+// installed packs. GitHub downloads are kept for every release of a repo
+// that still has an installed pack, since the cache layout records the
+// release tag rather than the pack version, and likewise for GitLab
+// projects and git repositories; registry downloads are kept for every
+// version of an installed pack. Files that a pack's rollback history
+// refers to are kept as well. A missing cacheDir is empty.
+func ListCache(cacheDir string, installed []config.InstalledPack) ([]CacheEntry, error) {
+	installedFiles := make(map[string]bool)
+	keepFiles := make(map[string]bool)
+	var keepDirs []string
+	keepRegistry := make(map[string]bool) // pack IDs, kept across registries and versions
+	var sources []string
+	for _, p := range installed {
+		installedFiles[p.Path] = true
+		sources = append(sources, p.Source)
+		for _, h := range p.History {
+			keepFiles[h.Path] = true
+			sources = append(sources, h.Source)
+		}
+	}
+	for _, source := range sources {
+		if source == "" {
+			continue
+		}
+		resolved, err := ResolveSource(source)
+		if err != nil {
+			continue
+		}
+		switch resolved.Kind {
+		case SourceHTTP:
+			keepFiles[HTTPCachePath(cacheDir, resolved.URL)] = true
+		case SourceGitHub:
+			keepDirs = append(keepDirs, filepath.Join(cacheDir, resolved.Owner, resolved.Repo)+string(filepath.Separator))
+		case SourceGitLab:
+			keepDirs = append(keepDirs, filepath.Join(cacheDir, "gitlab", NewGitLabClient().Host(), filepath.FromSlash(resolved.Owner), resolved.Repo)+string(filepath.Separator))
+		case SourceGit:
+			keepDirs = append(keepDirs, gitRepoCacheDir(cacheDir, resolved.URL)+string(filepath.Separator))
+		case SourceRegistry:
+			keepRegistry[resolved.PackID] = true
+		}
+	}
+
+	kept := func(path string) bool {
+		if keepFiles[path] {
+			return true
+		}
+		for _, dir := range keepDirs {
+			if strings.HasPrefix(path, dir) {
+				return true
+			}
+		}
+		if rel, err := filepath.Rel(filepath.Join(cacheDir, "registry"), path); err == nil {
+			// registry/<registry>/<namespace>/<name>/<version>.fpack
+			if parts := strings.Split(filepath.ToSlash(rel), "/"); len(parts) == 4 && keepRegistry[parts[1]+"/"+parts[2]] {
+				return true
+			}
+		}
+		return false
+	}
+
+	var entries []CacheEntry
+	err := filepath.WalkDir(cacheDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && path == cacheDir {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		entry := CacheEntry{CacheFile: CacheFile{Path: path, Size: info.Size()}, LastUsed: info.ModTime()}
+		switch {
+		case strings.HasPrefix(d.Name(), "fpack-download-") && strings.HasSuffix(d.Name(), ".tmp"),
+			strings.HasSuffix(d.Name(), partialSuffix):
+			entry.Status = CacheDownload
+		case installedFiles[path]:
+			entry.Status = CacheInstalled
+		case kept(path):
+			entry.Status = CacheKept
+		default:
+			entry.Status = CacheOrphaned
+		}
+		entries = append(entries, entry)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("scanning pack cache: %w", err)
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+	return entries, nil
+}
+
+// FindOrphanedCache returns files in cacheDir that no installed pack refers
+// to: downloads for packs that were removed or installed from elsewhere, and
+// temp and partial files left by interrupted downloads. See ListCache for
+// the files that are kept.
+func FindOrphanedCache(cacheDir string, installed []config.InstalledPack, now time.Time) ([]CacheFile, error) {
+	return FindEvictableCache(cacheDir, installed, now, 0)
+}
+
+// FindEvictableCache returns the orphaned files in cacheDir, as
+// FindOrphanedCache does, and, if maxAge is positive, every other file not
+// used for maxAge, except those installed packs were installed from.
+// Evicted rollback versions are downloaded again if a rollback needs them.
+func FindEvictableCache(cacheDir string, installed []config.InstalledPack, now time.Time, maxAge time.Duration) ([]CacheFile, error) {
+	entries, err := ListCache(cacheDir, installed)
+	if err != nil {
+		return nil, err
+	}
+	var files []CacheFile
+	for _, e := range entries {
+		age := now.Sub(e.LastUsed)
+		switch {
+		case e.Status == CacheOrphaned,
+			e.Status == CacheDownload && age >= staleDownloadAge,
+			e.Status == CacheKept && maxAge > 0 && age >= maxAge:
+			files = append(files, e.CacheFile)
+		}
+	}
+	return files, nil
+}
+
+// touchCacheFile marks a cache file as used now, so that age-based
+// eviction counts from its last use rather than its download.
+func touchCacheFile(path string) {
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+}
+
+// RemoveCacheFiles deletes files from cacheDir and prunes directories left
+// empty. It returns the bytes reclaimed before any error.
+func RemoveCacheFiles(cacheDir string, files []CacheFile) (int64, error) {
+	var reclaimed int64
+	dirs := make(map[string]bool)
+	for _, f := range files {
+		if err := os.Remove(f.Path); err != nil && !os.IsNotExist(err) {
+			return reclaimed, fmt.Errorf("removing %s: %w", f.Path, err)
+		}
+		reclaimed += f.Size
+		for dir := filepath.Dir(f.Path); dir != cacheDir && strings.HasPrefix(dir, cacheDir); dir = filepath.Dir(dir) {
+			dirs[dir] = true
+		}
+	}
+
+	// Remove deepest directories first; os.Remove fails on non-empty ones.
+	sorted := make([]string, 0, len(dirs))
+	for dir := range dirs {
+		sorted = append(sorted, dir)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+	for _, dir := range sorted {
+		_ = os.Remove(dir)
+	}
+	return reclaimed, nil
+}
@@ -0,0 +1,173 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+)
+
+func writeCacheFile(t *testing.T, path, content string, modTime time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFindOrphanedCache(t *testing.T) {
+	t.Setenv("GITLAB_HOST", "")
+	cacheDir := t.TempDir()
+	now := time.Now()
+	url := "https://example.com/packs/go.fpack"
+
+	keptHTTP := HTTPCachePath(cacheDir, url)
+	keptHistory := HTTPCachePath(cacheDir, "https://example.com/packs/go-0.9.fpack")
+	keptGitHub := filepath.Join(cacheDir, "acme", "packs", "v1.0.0", "go.fpack")
+	removedGitHub := filepath.Join(cacheDir, "acme", "old", "v0.1.0", "old.fpack")
+	removedHTTP := HTTPCachePath(cacheDir, "https://example.com/gone.fpack")
+	keptRegistry, _ := RegistryCachePath(cacheDir, "main", "acme/style", "0.9.0")
+	removedRegistry, _ := RegistryCachePath(cacheDir, "main", "acme/gone", "1.0.0")
+	keptGitLab := GitLabCachePath(cacheDir, "gitlab.com", "acme/platform/packs", "1.0.0", "go.fpack")
+	keptGit := GitCachePath(cacheDir, "https://git.example.com/packs.git", "v1.0.0", "go.fpack")
+	removedGit := GitCachePath(cacheDir, "https://git.example.com/gone.git", "", "go.fpack")
+	staleTmp := filepath.Join(cacheDir, "url", "fpack-download-123.tmp")
+	freshTmp := filepath.Join(cacheDir, "url", "fpack-download-456.tmp")
+	stalePartial := keptGitHub + partialSuffix
+
+	writeCacheFile(t, keptHTTP, "http", now)
+	writeCacheFile(t, keptHistory, "previous", now)
+	writeCacheFile(t, keptGitHub, "github", now)
+	writeCacheFile(t, removedGitHub, "old-github", now)
+	writeCacheFile(t, removedHTTP, "gone", now)
+	writeCacheFile(t, keptRegistry, "registry", now)
+	writeCacheFile(t, removedRegistry, "old-reg", now)
+	writeCacheFile(t, keptGitLab, "gitlab", now)
+	writeCacheFile(t, keptGit, "git", now)
+	writeCacheFile(t, removedGit, "old-git", now)
+	writeCacheFile(t, staleTmp, "partial", now.Add(-2*time.Hour))
+	writeCacheFile(t, freshTmp, "in-flight", now)
+	writeCacheFile(t, stalePartial, "resumable", now.Add(-2*time.Hour))
+
+	installed := []config.InstalledPack{
+		{ID: "acme/go", Source: url, History: []config.PackVersion{{Version: "0.9.0", Source: "https://example.com/packs/go-0.9.fpack"}}},
+		{ID: "acme/packs", Source: "gh:acme/packs@v1.0.0"},
+		{ID: "acme/style", Source: "registry:acme/style"},
+		{ID: "acme/platform", Source: "gl:acme/platform/packs"},
+		{ID: "acme/git", Source: "git+https://git.example.com/packs.git#path=go.fpack"},
+		{ID: "local/pack"},
+	}
+
+	orphans, err := FindOrphanedCache(cacheDir, installed, now)
+	if err != nil {
+		t.Fatalf("FindOrphanedCache() error = %v", err)
+	}
+
+	want := map[string]int64{
+		removedGitHub:   int64(len("old-github")),
+		removedHTTP:     int64(len("gone")),
+		removedRegistry: int64(len("old-reg")),
+		removedGit:      int64(len("old-git")),
+		staleTmp:        int64(len("partial")),
+		stalePartial:    int64(len("resumable")),
+	}
+	if len(orphans) != len(want) {
+		t.Fatalf("got %d orphans %v, want %d", len(orphans), orphans, len(want))
+	}
+	for _, o := range orphans {
+		size, ok := want[o.Path]
+		if !ok {
+			t.Errorf("unexpected orphan %s", o.Path)
+			continue
+		}
+		if o.Size != size {
+			t.Errorf("%s size = %d, want %d", o.Path, o.Size, size)
+		}
+	}
+
+	reclaimed, err := RemoveCacheFiles(cacheDir, orphans)
+	if err != nil {
+		t.Fatalf("RemoveCacheFiles() error = %v", err)
+	}
+	if reclaimed != int64(len("old-github")+len("gone")+len("old-reg")+len("old-git")+len("partial")+len("resumable")) {
+		t.Errorf("reclaimed = %d", reclaimed)
+	}
+
+	for _, kept := range []string{keptHTTP, keptHistory, keptGitHub, keptRegistry, keptGitLab, keptGit, freshTmp} {
+		if _, err := os.Stat(kept); err != nil {
+			t.Errorf("%s should be kept: %v", kept, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, "acme", "old")); !os.IsNotExist(err) {
+		t.Error("empty directories for removed downloads should be pruned")
+	}
+}
+
+func TestFindOrphanedCache_MissingDir(t *testing.T) {
+	orphans, err := FindOrphanedCache(filepath.Join(t.TempDir(), "missing"), nil, time.Now())
+	if err != nil {
+		t.Fatalf("FindOrphanedCache() error = %v", err)
+	}
+	if len(orphans) != 0 {
+		t.Errorf("got %d orphans, want 0", len(orphans))
+	}
+}
+
+func TestFindEvictableCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	now := time.Now()
+	old := now.Add(-100 * 24 * time.Hour)
+
+	installedFile := GitHubCachePath(cacheDir, "acme", "packs", "1.0.0", "go.fpack")
+	oldRelease := GitHubCachePath(cacheDir, "acme", "packs", "0.9.0", "go.fpack")
+	recentRelease := GitHubCachePath(cacheDir, "acme", "packs", "0.8.0", "go.fpack")
+	writeCacheFile(t, installedFile, "installed", old)
+	writeCacheFile(t, oldRelease, "old", old)
+	writeCacheFile(t, recentRelease, "recent", now)
+	installed := []config.InstalledPack{{ID: "acme/go", Source: "gh:acme/packs", Path: installedFile}}
+
+	tests := []struct {
+		name   string
+		maxAge time.Duration
+		want   []string
+	}{
+		{name: "no max age", want: nil},
+		{name: "90 days", maxAge: 90 * 24 * time.Hour, want: []string{oldRelease}},
+		{name: "200 days", maxAge: 200 * 24 * time.Hour, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files, err := FindEvictableCache(cacheDir, installed, now, tt.maxAge)
+			if err != nil {
+				t.Fatalf("FindEvictableCache() error = %v", err)
+			}
+			var got []string
+			for _, f := range files {
+				got = append(got, f.Path)
+			}
+			if len(got) != len(tt.want) || (len(got) > 0 && got[0] != tt.want[0]) {
+				t.Errorf("FindEvictableCache() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// A cache hit counts as a use.
+	if _, err := Fetch(context.Background(), "http://127.0.0.1:0/unused", oldRelease, FetchOptions{}); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	files, err := FindEvictableCache(cacheDir, installed, now, 90*24*time.Hour)
+	if err != nil {
+		t.Fatalf("FindEvictableCache() error = %v", err)
+	}
+	if len(files) != 0 {
+		t.Errorf("FindEvictableCache() after a cache hit = %v, want none", files)
+	}
+}
@@ -0,0 +1,176 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/dedup"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Collision kinds.
+const (
+	CollisionSameID      = "id"      // same ID, different content
+	CollisionSameContent = "content" // different ID, near-identical content
+)
+
+// Collision is a pack behavior that clashes with a behavior already in the
+// store that is not from the pack: one with the same ID but different
+// content, or one with near-identical content under another ID.
+type Collision struct {
+	Kind          string  `json:"kind"`
+	PackBehavior  string  `json:"pack_behavior"`
+	LocalBehavior string  `json:"local_behavior"`
+	LocalPack     string  `json:"local_pack,omitempty"` // pack the local behavior came from, if any
+	Similarity    float64 `json:"similarity"`
+	PackContent   string  `json:"pack_content"`
+	LocalContent  string  `json:"local_content"`
+
+	// Action is how the collision was resolved: keep-local, prefer-pack,
+	// or rename. A pack behavior with exactly the content of a local one
+	// under another ID is always kept local.
+	Action string `json:"action"`
+
+	// InstalledAs is the ID a renamed pack behavior was installed under.
+	InstalledAs string `json:"installed_as,omitempty"`
+}
+
+// CollisionResolver decides a collision under the prompt policy, returning
+// keep-local, prefer-pack, or rename.
+type CollisionResolver func(Collision) (string, error)
+
+// collisionDetector finds the local behavior, if any, that a pack behavior
+// collides with.
+type collisionDetector struct {
+	packID    string
+	threshold float64
+	locals    []models.Behavior // loaded on first content check
+	packs     []string          // the pack each local came from, if any
+	loaded    bool
+}
+
+// detect returns the collision between node, as stamped for the pack, and
+// existing, the store's node with the same ID (or nil). Same-ID behaviors
+// with the same content are not collisions: installing adopts them into
+// the pack.
+func (d *collisionDetector) detect(ctx context.Context, s store.GraphStore, node store.Node, existing *store.Node) (*Collision, error) {
+	pb := models.NodeToBehavior(node)
+
+	if existing != nil {
+		if existing.Kind != store.NodeKindBehavior || models.ExtractPackageName(existing.Metadata) == d.packID {
+			return nil, nil
+		}
+		local := models.NodeToBehavior(*existing)
+		if local.Content.Canonical == pb.Content.Canonical {
+			return nil, nil
+		}
+		return newCollision(CollisionSameID, &pb, &local, models.ExtractPackageName(existing.Metadata)), nil
+	}
+
+	if !d.loaded {
+		nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+		if err != nil {
+			return nil, fmt.Errorf("querying behaviors: %w", err)
+		}
+		for _, n := range nodes {
+			if pkg := models.ExtractPackageName(n.Metadata); pkg != d.packID {
+				d.locals = append(d.locals, models.NodeToBehavior(n))
+				d.packs = append(d.packs, pkg)
+			}
+		}
+		d.loaded = true
+	}
+
+	simCfg := dedup.SimilarityConfig{SimilarityThreshold: d.threshold}
+	best, bestScore := -1, 0.0
+	for i := range d.locals {
+		score := 1.0
+		if d.locals[i].Content.Canonical != pb.Content.Canonical {
+			score = dedup.ComputeSimilarity(&pb, &d.locals[i], simCfg).Score
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	if best < 0 || bestScore < d.threshold {
+		return nil, nil
+	}
+	c := newCollision(CollisionSameContent, &pb, &d.locals[best], d.packs[best])
+	c.Similarity = bestScore
+	return c, nil
+}
+
+func newCollision(kind string, pb, local *models.Behavior, localPack string) *Collision {
+	return &Collision{
+		Kind:          kind,
+		PackBehavior:  pb.ID,
+		LocalBehavior: local.ID,
+		LocalPack:     localPack,
+		PackContent:   pb.Content.Canonical,
+		LocalContent:  local.Content.Canonical,
+	}
+}
+
+// resolveCollision returns the action for c under policy, asking resolver
+// under the prompt policy. Without a resolver, prompt keeps the local
+// behavior.
+func resolveCollision(policy string, c Collision, resolver CollisionResolver) (string, error) {
+	if policy != config.CollisionPrompt {
+		return policy, nil
+	}
+	if resolver == nil {
+		return config.CollisionKeepLocal, nil
+	}
+	action, err := resolver(c)
+	if err != nil {
+		return "", err
+	}
+	if action == config.CollisionPrompt || !slices.Contains(config.CollisionPolicies, action) {
+		return "", fmt.Errorf("invalid collision action %q", action)
+	}
+	return action, nil
+}
+
+// RenamedID is the ID a pack behavior is installed under when the rename
+// policy resolves an ID collision. It is stable, so later versions of the
+// pack update the renamed behavior.
+func RenamedID(id, packID string) string {
+	return id + "-" + strings.ReplaceAll(packID, "/", "-")
+}
+
+// mergeIntoPack marks a local behavior as merged into the pack behavior
+// that replaced it under the prefer-pack policy.
+func mergeIntoPack(ctx context.Context, s store.GraphStore, localID, packBehaviorID, packID string) error {
+	local, err := s.GetNode(ctx, localID)
+	if err != nil {
+		return fmt.Errorf("getting behavior %s: %w", localID, err)
+	}
+	if local == nil {
+		return fmt.Errorf("behavior %s not found", localID)
+	}
+	now := time.Now()
+	if local.Metadata == nil {
+		local.Metadata = make(map[string]interface{})
+	}
+	local.Metadata["original_kind"] = string(local.Kind)
+	local.Metadata["merged_into"] = packBehaviorID
+	local.Metadata["merged_at"] = now.Format(time.RFC3339)
+	local.Metadata["merged_reason"] = "replaced by pack " + packID
+	local.Kind = store.NodeKindMerged
+	if err := s.UpdateNode(ctx, *local); err != nil {
+		return fmt.Errorf("marking %s merged: %w", localID, err)
+	}
+	return s.AddEdge(ctx, store.Edge{
+		Source:    localID,
+		Target:    packBehaviorID,
+		Kind:      store.EdgeKindMergedInto,
+		Weight:    1.0,
+		CreatedAt: now,
+		Metadata:  map[string]interface{}{"merged_at": now.Format(time.RFC3339)},
+	})
+}
@@ -0,0 +1,197 @@
+package pack
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+const (
+	localRule = "Always run go test with the race detector before committing changes"
+	packRule  = "Always run go test with the race detector before committing any changes"
+)
+
+// collisionStore returns a store holding a locally learned behavior, b-1.
+func collisionStore(t *testing.T, canonical string) store.GraphStore {
+	t.Helper()
+	s := store.NewInMemoryGraphStore()
+	if _, err := s.AddNode(context.Background(), rollbackNode("b-1", canonical)); err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+	return s
+}
+
+func TestInstall_IDCollision(t *testing.T) {
+	tests := []struct {
+		policy    string
+		wantLocal string // content left under b-1
+		wantAs    string // ID the pack behavior is installed under, if any
+	}{
+		{config.CollisionPreferPack, "Use go test -race", "b-1"},
+		{config.CollisionKeepLocal, "Use go test", ""},
+		{config.CollisionRename, "Use go test", "b-1-test-org-go-pack"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			ctx := context.Background()
+			s := collisionStore(t, "Use go test")
+			path := writeTestPack(t, t.TempDir(),
+				[]store.Node{rollbackNode("b-1", "Use go test -race"), rollbackNode("b-2", "Wrap errors")},
+				[]store.Edge{{Source: "b-1", Target: "b-2", Kind: store.EdgeKindSimilarTo, Weight: 0.8, CreatedAt: time.Now()}},
+				PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+
+			result, err := Install(ctx, s, path, config.Default(), InstallOptions{CollisionPolicy: tt.policy})
+			if err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			if len(result.Collisions) != 1 {
+				t.Fatalf("Collisions = %+v, want 1", result.Collisions)
+			}
+			c := result.Collisions[0]
+			if c.Kind != CollisionSameID || c.LocalBehavior != "b-1" || c.Action != tt.policy {
+				t.Errorf("Collision = %+v", c)
+			}
+
+			local, _ := s.GetNode(ctx, "b-1")
+			if got := models.NodeToBehavior(*local).Content.Canonical; got != tt.wantLocal {
+				t.Errorf("b-1 content = %q, want %q", got, tt.wantLocal)
+			}
+			if tt.wantAs == "" {
+				if len(result.Skipped) != 1 {
+					t.Errorf("Skipped = %v, want [b-1]", result.Skipped)
+				}
+				return
+			}
+			installed, _ := s.GetNode(ctx, tt.wantAs)
+			if installed == nil || models.ExtractPackageName(installed.Metadata) != "test-org/go-pack" {
+				t.Fatalf("%s = %+v, want a test-org/go-pack behavior", tt.wantAs, installed)
+			}
+			edges, _ := s.GetEdges(ctx, tt.wantAs, store.DirectionOutbound, store.EdgeKindSimilarTo)
+			if len(edges) != 1 || edges[0].Target != "b-2" {
+				t.Errorf("edges from %s = %+v, want one to b-2", tt.wantAs, edges)
+			}
+		})
+	}
+}
+
+func TestInstall_ContentCollision(t *testing.T) {
+	tests := []struct {
+		policy     string
+		wantKind   store.NodeKind // kind of the local behavior afterwards
+		wantPack   bool           // whether the pack behavior is installed
+		wantSource string         // source of the pack's edge to b-3
+	}{
+		{config.CollisionPreferPack, store.NodeKindMerged, true, "p-1"},
+		{config.CollisionKeepLocal, store.NodeKindBehavior, false, "b-1"},
+		{config.CollisionRename, store.NodeKindBehavior, true, "p-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			ctx := context.Background()
+			s := collisionStore(t, localRule)
+			path := writeTestPack(t, t.TempDir(),
+				[]store.Node{rollbackNode("p-1", packRule), rollbackNode("b-3", "Wrap errors")},
+				[]store.Edge{{Source: "p-1", Target: "b-3", Kind: store.EdgeKindSimilarTo, Weight: 0.8, CreatedAt: time.Now()}},
+				PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+
+			result, err := Install(ctx, s, path, config.Default(), InstallOptions{CollisionPolicy: tt.policy})
+			if err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			if len(result.Collisions) != 1 {
+				t.Fatalf("Collisions = %+v, want 1", result.Collisions)
+			}
+			c := result.Collisions[0]
+			if c.Kind != CollisionSameContent || c.PackBehavior != "p-1" || c.LocalBehavior != "b-1" || c.Similarity < 0.9 {
+				t.Errorf("Collision = %+v", c)
+			}
+
+			local, _ := s.GetNode(ctx, "b-1")
+			if local.Kind != tt.wantKind {
+				t.Errorf("b-1 kind = %s, want %s", local.Kind, tt.wantKind)
+			}
+			if tt.wantKind == store.NodeKindMerged && local.Metadata["merged_into"] != "p-1" {
+				t.Errorf("b-1 merged_into = %v, want p-1", local.Metadata["merged_into"])
+			}
+			if p, _ := s.GetNode(ctx, "p-1"); (p != nil) != tt.wantPack {
+				t.Errorf("p-1 installed = %v, want %v", p != nil, tt.wantPack)
+			}
+			edges, _ := s.GetEdges(ctx, tt.wantSource, store.DirectionOutbound, store.EdgeKindSimilarTo)
+			if len(edges) != 1 || edges[0].Target != "b-3" {
+				t.Errorf("edges from %s = %+v, want one to b-3", tt.wantSource, edges)
+			}
+		})
+	}
+}
+
+func TestInstall_CollisionPrompt(t *testing.T) {
+	ctx := context.Background()
+	s := collisionStore(t, "Use go test")
+	path := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("b-1", "Use go test -race")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+
+	var asked []Collision
+	result, err := Install(ctx, s, path, config.Default(), InstallOptions{
+		CollisionPolicy: config.CollisionPrompt,
+		ResolveCollision: func(c Collision) (string, error) {
+			asked = append(asked, c)
+			return config.CollisionRename, nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if len(asked) != 1 || asked[0].PackContent != "Use go test -race" || asked[0].LocalContent != "Use go test" {
+		t.Errorf("resolver asked %+v", asked)
+	}
+	if len(result.Added) != 1 || result.Added[0] != "b-1-test-org-go-pack" {
+		t.Errorf("Added = %v, want the renamed behavior", result.Added)
+	}
+
+	// Without a resolver, prompt keeps the local behavior.
+	s = collisionStore(t, "Use go test")
+	result, err = Install(ctx, s, path, config.Default(), InstallOptions{CollisionPolicy: config.CollisionPrompt})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if len(result.Collisions) != 1 || result.Collisions[0].Action != config.CollisionKeepLocal {
+		t.Errorf("Collisions = %+v, want keep-local", result.Collisions)
+	}
+}
+
+func TestInstall_CollisionExactDuplicate(t *testing.T) {
+	ctx := context.Background()
+	s := collisionStore(t, localRule)
+	path := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("p-1", localRule)}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+
+	for i := 0; i < 2; i++ {
+		result, err := Install(ctx, s, path, config.Default(), InstallOptions{CollisionPolicy: config.CollisionPreferPack})
+		if err != nil {
+			t.Fatalf("Install() error = %v", err)
+		}
+		if len(result.Collisions) != 1 || result.Collisions[0].Action != config.CollisionKeepLocal {
+			t.Errorf("Collisions = %+v, want keep-local", result.Collisions)
+		}
+	}
+}
+
+func TestInstall_InvalidCollisionPolicy(t *testing.T) {
+	path := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("b-1", "Use go test")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+	_, err := Install(context.Background(), store.NewInMemoryGraphStore(), path, config.Default(),
+		InstallOptions{CollisionPolicy: "merge"})
+	if err == nil {
+		t.Error("Install() with an invalid policy succeeded, want error")
+	}
+}
+
+func TestRenamedID(t *testing.T) {
+	if got := RenamedID("b-1", "acme/go-pack"); got != "b-1-acme-go-pack" {
+		t.Errorf("RenamedID() = %q", got)
+	}
+}
@@ -2,7 +2,10 @@ package pack
 
 import (
 	"context"
+	"crypto/ed25519"
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/nvandessel/floop/internal/backup"
@@ -16,11 +19,19 @@ type CreateFilter struct {
 	Scope    string   // "global", "local", or "" (all)
 	Kinds    []string // behavior kinds to include (empty = all)
 	FromPack string   // only include behaviors where provenance.package matches (empty = all)
+	IDs      []string // only include these behaviors, e.g. a curated selection (empty = all)
 }
 
 // CreateOptions configures pack creation.
 type CreateOptions struct {
 	FloopVersion string
+
+	// Anonymizer, if set, rewrites internal names in each exported
+	// behavior. The store is not modified.
+	Anonymizer *Anonymizer
+
+	// SigningKey, if set, embeds an ed25519 signature in the pack header.
+	SigningKey ed25519.PrivateKey
 }
 
 // CreateResult reports what was created.
@@ -28,6 +39,13 @@ type CreateResult struct {
 	Path          string
 	BehaviorCount int
 	EdgeCount     int
+
+	// Signer is the fingerprint of the signing key, if the pack was signed.
+	Signer string
+
+	// Anonymize reports the rewrites made when CreateOptions.Anonymizer
+	// was set.
+	Anonymize *AnonymizeReport
 }
 
 // Create exports filtered behaviors and their connecting edges into a pack file.
@@ -36,47 +54,19 @@ func Create(ctx context.Context, s store.GraphStore, filter CreateFilter, manife
 		return nil, err
 	}
 
-	// 1. Query all nodes
-	nodes, err := s.QueryNodes(ctx, map[string]interface{}{})
+	nodes, edges, _, err := selectContent(ctx, s, filter)
 	if err != nil {
-		return nil, fmt.Errorf("querying nodes: %w", err)
+		return nil, err
 	}
-
-	// 2. Convert and filter
-	filteredIDs := make(map[string]bool)
-	var filteredNodes []backup.BackupNode
+	filteredNodes := make([]backup.BackupNode, 0, len(nodes))
 	for _, node := range nodes {
-		if !matchesFilter(node, filter) {
-			continue
+		if opts.Anonymizer != nil {
+			node = opts.Anonymizer.Node(node)
 		}
-		filteredIDs[node.ID] = true
 		filteredNodes = append(filteredNodes, backup.BackupNode{Node: node})
 	}
 
-	// 3. Collect only edges where BOTH endpoints are in the filtered set
-	edgeSet := make(map[string]store.Edge)
-	for _, node := range nodes {
-		if !filteredIDs[node.ID] {
-			continue
-		}
-		edges, err := s.GetEdges(ctx, node.ID, store.DirectionOutbound, "")
-		if err != nil {
-			return nil, fmt.Errorf("getting edges for %s: %w", node.ID, err)
-		}
-		for _, e := range edges {
-			if filteredIDs[e.Source] && filteredIDs[e.Target] {
-				key := fmt.Sprintf("%s:%s:%s", e.Source, e.Target, e.Kind)
-				edgeSet[key] = e
-			}
-		}
-	}
-
-	edges := make([]store.Edge, 0, len(edgeSet))
-	for _, e := range edgeSet {
-		edges = append(edges, e)
-	}
-
-	// 4. Build BackupFormat
+	// Build BackupFormat
 	bf := &backup.BackupFormat{
 		Version:   backup.FormatV2,
 		CreatedAt: time.Now(),
@@ -84,37 +74,147 @@ func Create(ctx context.Context, s store.GraphStore, filter CreateFilter, manife
 		Edges:     edges,
 	}
 
-	// 5. Write pack file
+	// Write pack file
 	writeOpts := &backup.WriteOptions{
 		FloopVersion: opts.FloopVersion,
 	}
+	if opts.SigningKey != nil {
+		writeOpts.Finalize = SignHeader(opts.SigningKey)
+	}
 	if err := WritePackFile(outputPath, bf, manifest, writeOpts); err != nil {
 		return nil, fmt.Errorf("writing pack file: %w", err)
 	}
 
-	return &CreateResult{
+	result := &CreateResult{
 		Path:          outputPath,
 		BehaviorCount: len(filteredNodes),
 		EdgeCount:     len(edges),
-	}, nil
+	}
+	if opts.SigningKey != nil {
+		result.Signer = KeyFingerprint(opts.SigningKey.Public().(ed25519.PublicKey))
+	}
+	if opts.Anonymizer != nil {
+		report := opts.Anonymizer.Report()
+		result.Anonymize = &report
+	}
+	return result, nil
+}
+
+// PlannedBehavior is a behavior considered for a pack.
+type PlannedBehavior struct {
+	ID     string   `json:"id"`
+	Name   string   `json:"name"`
+	Kind   string   `json:"kind"`
+	Tags   []string `json:"tags,omitempty"`
+	Reason string   `json:"reason,omitempty"` // why it was excluded
+}
+
+// PlannedEdge is an edge considered for a pack.
+type PlannedEdge struct {
+	Source string         `json:"source"`
+	Target string         `json:"target"`
+	Kind   store.EdgeKind `json:"kind"`
+	Reason string         `json:"reason,omitempty"` // why it was excluded
 }
 
-// matchesFilter checks if a node passes the given filter criteria.
-func matchesFilter(node store.Node, filter CreateFilter) bool {
+// CreatePlan lists what Create would put in a pack for a filter, and what
+// it would leave out and why.
+type CreatePlan struct {
+	Included      []PlannedBehavior `json:"included"`
+	Excluded      []PlannedBehavior `json:"excluded"`
+	Edges         []PlannedEdge     `json:"edges"`
+	ExcludedEdges []PlannedEdge     `json:"excluded_edges"`
+}
+
+// PlanCreate reports which behaviors and edges Create would include for
+// filter, without writing anything. Edges are excluded when either end is.
+func PlanCreate(ctx context.Context, s store.GraphStore, filter CreateFilter) (*CreatePlan, error) {
+	_, _, plan, err := selectContent(ctx, s, filter)
+	return plan, err
+}
+
+// selectContent returns the nodes that pass filter and the edges between
+// them, sorted by ID, along with the plan describing the selection.
+func selectContent(ctx context.Context, s store.GraphStore, filter CreateFilter) ([]store.Node, []store.Edge, *CreatePlan, error) {
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("querying nodes: %w", err)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+
+	plan := &CreatePlan{
+		Included:      []PlannedBehavior{},
+		Excluded:      []PlannedBehavior{},
+		Edges:         []PlannedEdge{},
+		ExcludedEdges: []PlannedEdge{},
+	}
+	included := make(map[string]bool)
+	var selected []store.Node
+	for _, node := range nodes {
+		b := models.NodeToBehavior(node)
+		entry := PlannedBehavior{ID: node.ID, Name: b.Name, Kind: string(b.Kind), Tags: b.Content.Tags}
+		if entry.Reason = filterReason(node, filter); entry.Reason != "" {
+			plan.Excluded = append(plan.Excluded, entry)
+			continue
+		}
+		included[node.ID] = true
+		selected = append(selected, node)
+		plan.Included = append(plan.Included, entry)
+	}
+
+	// Keep edges where both endpoints are included; report those that
+	// touch an included node but leave the pack.
+	seen := make(map[string]bool)
+	edges := []store.Edge{}
+	for _, node := range selected {
+		nodeEdges, err := s.GetEdges(ctx, node.ID, store.DirectionBoth, "")
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("getting edges for %s: %w", node.ID, err)
+		}
+		for _, e := range nodeEdges {
+			key := fmt.Sprintf("%s:%s:%s", e.Source, e.Target, e.Kind)
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			planned := PlannedEdge{Source: e.Source, Target: e.Target, Kind: e.Kind}
+			switch {
+			case !included[e.Source]:
+				planned.Reason = e.Source + " is not in the pack"
+				plan.ExcludedEdges = append(plan.ExcludedEdges, planned)
+			case !included[e.Target]:
+				planned.Reason = e.Target + " is not in the pack"
+				plan.ExcludedEdges = append(plan.ExcludedEdges, planned)
+			default:
+				edges = append(edges, e)
+				plan.Edges = append(plan.Edges, planned)
+			}
+		}
+	}
+	return selected, edges, plan, nil
+}
+
+// filterReason returns why node fails filter, or "" if it passes.
+func filterReason(node store.Node, filter CreateFilter) string {
 	b := models.NodeToBehavior(node)
 
 	// Filter by pack membership
 	if filter.FromPack != "" {
-		if models.ExtractPackageName(node.Metadata) != filter.FromPack {
-			return false
+		if pkg := models.ExtractPackageName(node.Metadata); pkg != filter.FromPack {
+			if pkg == "" {
+				return "not from pack " + filter.FromPack
+			}
+			return fmt.Sprintf("from pack %s, not %s", pkg, filter.FromPack)
 		}
 	}
 
 	// Filter by scope
 	if filter.Scope != "" {
-		nodeScope := extractScope(node)
-		if nodeScope != filter.Scope {
-			return false
+		if nodeScope := extractScope(node); nodeScope != filter.Scope {
+			if nodeScope == "" {
+				return "scope unknown, not " + filter.Scope
+			}
+			return fmt.Sprintf("scope %s, not %s", nodeScope, filter.Scope)
 		}
 	}
 
@@ -122,18 +222,23 @@ func matchesFilter(node store.Node, filter CreateFilter) bool {
 	if len(filter.Kinds) > 0 {
 		kindStr := string(b.Kind)
 		if !containsString(filter.Kinds, kindStr) {
-			return false
+			return fmt.Sprintf("kind %s not in %s", kindStr, strings.Join(filter.Kinds, ", "))
 		}
 	}
 
 	// Filter by tags
 	if len(filter.Tags) > 0 {
 		if !hasAnyTag(b.Content.Tags, filter.Tags) {
-			return false
+			return "no tag in " + strings.Join(filter.Tags, ", ")
 		}
 	}
 
-	return true
+	// Filter by selection
+	if len(filter.IDs) > 0 && !containsString(filter.IDs, node.ID) {
+		return "not selected"
+	}
+
+	return ""
 }
 
 // extractScope determines the scope of a node from its metadata.
@@ -338,3 +338,71 @@ func TestCreate_FromPackNoMatch(t *testing.T) {
 		t.Errorf("BehaviorCount = %d, want 0", result.BehaviorCount)
 	}
 }
+
+func TestPlanCreate(t *testing.T) {
+	s := makeTestStore(t)
+	ctx := context.Background()
+
+	plan, err := PlanCreate(ctx, s, CreateFilter{Tags: []string{"go"}, Kinds: []string{"directive", "constraint"}})
+	if err != nil {
+		t.Fatalf("PlanCreate() error = %v", err)
+	}
+	if len(plan.Included) != 2 || plan.Included[0].ID != "b-1" || plan.Included[1].ID != "b-3" {
+		t.Errorf("Included = %+v, want b-1 and b-3", plan.Included)
+	}
+	if len(plan.Excluded) != 1 || plan.Excluded[0].ID != "b-2" || plan.Excluded[0].Reason != "kind preference not in directive, constraint" {
+		t.Errorf("Excluded = %+v, want b-2 excluded by kind", plan.Excluded)
+	}
+	if len(plan.Edges) != 1 || plan.Edges[0].Target != "b-3" {
+		t.Errorf("Edges = %+v, want b-1 -> b-3", plan.Edges)
+	}
+	if len(plan.ExcludedEdges) != 1 || plan.ExcludedEdges[0].Reason != "b-2 is not in the pack" {
+		t.Errorf("ExcludedEdges = %+v, want b-1 -> b-2 excluded", plan.ExcludedEdges)
+	}
+
+	// The plan must match what Create writes
+	result, err := Create(ctx, s, CreateFilter{Tags: []string{"go"}, Kinds: []string{"directive", "constraint"}},
+		PackManifest{ID: "test-org/plan-pack", Version: "1.0.0"}, filepath.Join(t.TempDir(), "plan.fpack"), CreateOptions{})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if result.BehaviorCount != len(plan.Included) || result.EdgeCount != len(plan.Edges) {
+		t.Errorf("Create wrote %d behaviors, %d edges; plan has %d, %d",
+			result.BehaviorCount, result.EdgeCount, len(plan.Included), len(plan.Edges))
+	}
+}
+
+func TestPlanCreate_Reasons(t *testing.T) {
+	s := makeTestStore(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		filter CreateFilter
+		id     string
+		want   string
+	}{
+		{"tag", CreateFilter{Tags: []string{"go", "testing"}}, "b-2", "no tag in go, testing"},
+		{"scope", CreateFilter{Scope: "global"}, "b-2", "scope local, not global"},
+		{"pack", CreateFilter{FromPack: "test-org/go-pack"}, "b-2", "not from pack test-org/go-pack"},
+		{"other pack", CreateFilter{FromPack: "test-org/py-pack"}, "b-1", "from pack test-org/go-pack, not test-org/py-pack"},
+		{"selection", CreateFilter{IDs: []string{"b-1", "b-2"}}, "b-3", "not selected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := PlanCreate(ctx, s, tt.filter)
+			if err != nil {
+				t.Fatalf("PlanCreate() error = %v", err)
+			}
+			for _, b := range plan.Excluded {
+				if b.ID == tt.id {
+					if b.Reason != tt.want {
+						t.Errorf("reason = %q, want %q", b.Reason, tt.want)
+					}
+					return
+				}
+			}
+			t.Errorf("%s not excluded: %+v", tt.id, plan)
+		})
+	}
+}
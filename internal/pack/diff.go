@@ -0,0 +1,128 @@
+package pack
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// ChangelogEntry is a behavior added, changed, or removed between two
+// versions of a pack.
+type ChangelogEntry struct {
+	ID      string `json:"id"`
+	Name    string `json:"name,omitempty"`
+	Kind    string `json:"kind,omitempty"`
+	Content string `json:"content,omitempty"` // the newer text; for removals, the last one
+
+	// For changed behaviors: what changed, and the text before if the
+	// content did.
+	Fields []string `json:"fields,omitempty"`
+	Before string   `json:"before,omitempty"`
+}
+
+// Changelog describes how a pack changed between two versions. It is the
+// machine-readable form of 'floop pack diff'; Markdown renders it for
+// release notes.
+type Changelog struct {
+	PackID       string           `json:"pack_id"`
+	FromVersion  string           `json:"from_version"`
+	ToVersion    string           `json:"to_version"`
+	Added        []ChangelogEntry `json:"added"`
+	Changed      []ChangelogEntry `json:"changed"`
+	Removed      []ChangelogEntry `json:"removed"`
+	Unchanged    int              `json:"unchanged"`
+	EdgesAdded   int              `json:"edges_added"`
+	EdgesRemoved int              `json:"edges_removed"`
+}
+
+// Empty reports whether the two versions hold the same behaviors.
+func (c *Changelog) Empty() bool {
+	return len(c.Added)+len(c.Changed)+len(c.Removed) == 0 && c.EdgesAdded+c.EdgesRemoved == 0
+}
+
+// Diff compares two versions of a pack, as read by ReadPackFile. Entries
+// are sorted by ID.
+func Diff(oldData *backup.BackupFormat, oldManifest *PackManifest, newData *backup.BackupFormat, newManifest *PackManifest) *Changelog {
+	c := &Changelog{
+		PackID:      string(newManifest.ID),
+		FromVersion: oldManifest.Version,
+		ToVersion:   newManifest.Version,
+		Added:       []ChangelogEntry{},
+		Changed:     []ChangelogEntry{},
+		Removed:     []ChangelogEntry{},
+	}
+	oldNodes, newNodes := nodesByID(oldData), nodesByID(newData)
+
+	cmp := backup.Compare(oldData, newData)
+	c.Unchanged = cmp.Unchanged
+	for _, b := range cmp.Added {
+		c.Added = append(c.Added, changelogEntry(newNodes[b.ID]))
+	}
+	for _, b := range cmp.Removed {
+		c.Removed = append(c.Removed, changelogEntry(oldNodes[b.ID]))
+	}
+	for _, ch := range cmp.Changed {
+		entry := changelogEntry(newNodes[ch.ID])
+		entry.Fields = ch.Fields
+		if before := models.NodeToBehavior(oldNodes[ch.ID]).Content.Canonical; before != entry.Content {
+			entry.Before = before
+		}
+		c.Changed = append(c.Changed, entry)
+	}
+	c.EdgesAdded, c.EdgesRemoved = len(cmp.EdgesAdded), len(cmp.EdgesRemoved)
+	return c
+}
+
+// Markdown renders the changelog as a release notes section.
+func (c *Changelog) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s %s\n\n", c.PackID, c.ToVersion)
+	if c.Empty() {
+		fmt.Fprintf(&sb, "No behavior changes since %s.\n", c.FromVersion)
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "Changes since %s: %d added, %d changed, %d removed.\n", c.FromVersion, len(c.Added), len(c.Changed), len(c.Removed))
+
+	section := func(title string, entries []ChangelogEntry, line func(ChangelogEntry) string) {
+		if len(entries) == 0 {
+			return
+		}
+		fmt.Fprintf(&sb, "\n### %s\n\n", title)
+		for _, e := range entries {
+			sb.WriteString("- " + line(e) + "\n")
+		}
+	}
+	section("Added", c.Added, func(e ChangelogEntry) string {
+		return fmt.Sprintf("**%s** (%s): %s", e.Name, e.Kind, e.Content)
+	})
+	section("Changed", c.Changed, func(e ChangelogEntry) string {
+		line := fmt.Sprintf("**%s** (%s changed)", e.Name, strings.Join(e.Fields, ", "))
+		if e.Before != "" {
+			line += ": " + e.Content
+		}
+		return line
+	})
+	section("Removed", c.Removed, func(e ChangelogEntry) string {
+		return fmt.Sprintf("**%s** (%s): %s", e.Name, e.Kind, e.Content)
+	})
+	if c.EdgesAdded+c.EdgesRemoved > 0 {
+		fmt.Fprintf(&sb, "\nEdges: %d added, %d removed.\n", c.EdgesAdded, c.EdgesRemoved)
+	}
+	return sb.String()
+}
+
+func changelogEntry(n store.Node) ChangelogEntry {
+	b := models.NodeToBehavior(n)
+	return ChangelogEntry{ID: n.ID, Name: b.Name, Kind: string(b.Kind), Content: b.Content.Canonical}
+}
+
+func nodesByID(data *backup.BackupFormat) map[string]store.Node {
+	nodes := make(map[string]store.Node, len(data.Nodes))
+	for _, bn := range data.Nodes {
+		nodes[bn.Node.ID] = bn.Node
+	}
+	return nodes
+}
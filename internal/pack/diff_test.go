@@ -0,0 +1,92 @@
+package pack
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func diffNode(id, name, canonical string) backup.BackupNode {
+	return backup.BackupNode{Node: store.Node{
+		ID:   id,
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name": name,
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": canonical,
+			},
+		},
+	}}
+}
+
+func TestDiff(t *testing.T) {
+	oldData := &backup.BackupFormat{Nodes: []backup.BackupNode{
+		diffNode("b-1", "use-go-test", "Use go test for testing"),
+		diffNode("b-2", "wrap-errors", "Wrap errors with %w"),
+		diffNode("b-3", "no-panics", "Never panic in library code"),
+	}}
+	newData := &backup.BackupFormat{
+		Nodes: []backup.BackupNode{
+			diffNode("b-1", "use-go-test", "Use go test for testing"),
+			diffNode("b-2", "wrap-errors", "Wrap errors with fmt.Errorf and %w"),
+			diffNode("b-4", "table-tests", "Prefer table-driven tests"),
+		},
+		Edges: []store.Edge{{Source: "b-1", Target: "b-4", Kind: "similar-to", Weight: 0.8}},
+	}
+	oldManifest := &PackManifest{ID: "test-org/go-pack", Version: "1.0.0"}
+	newManifest := &PackManifest{ID: "test-org/go-pack", Version: "1.1.0"}
+
+	c := Diff(oldData, oldManifest, newData, newManifest)
+
+	if c.PackID != "test-org/go-pack" || c.FromVersion != "1.0.0" || c.ToVersion != "1.1.0" {
+		t.Errorf("header = %s %s -> %s", c.PackID, c.FromVersion, c.ToVersion)
+	}
+	if len(c.Added) != 1 || c.Added[0].ID != "b-4" || c.Added[0].Name != "table-tests" || c.Added[0].Kind != "directive" {
+		t.Errorf("Added = %+v, want b-4", c.Added)
+	}
+	if len(c.Removed) != 1 || c.Removed[0].ID != "b-3" || c.Removed[0].Content != "Never panic in library code" {
+		t.Errorf("Removed = %+v, want b-3 with its last content", c.Removed)
+	}
+	if len(c.Changed) != 1 {
+		t.Fatalf("Changed = %+v, want b-2", c.Changed)
+	}
+	changed := c.Changed[0]
+	if changed.ID != "b-2" || changed.Before != "Wrap errors with %w" || changed.Content != "Wrap errors with fmt.Errorf and %w" {
+		t.Errorf("Changed[0] = %+v", changed)
+	}
+	if c.Unchanged != 1 || c.EdgesAdded != 1 || c.EdgesRemoved != 0 {
+		t.Errorf("Unchanged = %d, edges +%d -%d", c.Unchanged, c.EdgesAdded, c.EdgesRemoved)
+	}
+	if c.Empty() {
+		t.Error("Empty() = true")
+	}
+
+	md := c.Markdown()
+	for _, want := range []string{
+		"## test-org/go-pack 1.1.0",
+		"Changes since 1.0.0: 1 added, 1 changed, 1 removed.",
+		"### Added\n\n- **table-tests** (directive): Prefer table-driven tests",
+		"### Changed\n\n- **wrap-errors** (",
+		"### Removed\n\n- **no-panics**",
+		"Edges: 1 added, 0 removed.",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("Markdown() missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestDiff_Identical(t *testing.T) {
+	data := &backup.BackupFormat{Nodes: []backup.BackupNode{diffNode("b-1", "use-go-test", "Use go test for testing")}}
+	c := Diff(data, &PackManifest{ID: "test-org/go-pack", Version: "1.0.0"}, data, &PackManifest{ID: "test-org/go-pack", Version: "1.0.1"})
+
+	if !c.Empty() || c.Unchanged != 1 {
+		t.Errorf("Diff of identical packs = %+v, want empty", c)
+	}
+	if md := c.Markdown(); !strings.Contains(md, "No behavior changes since 1.0.0.") {
+		t.Errorf("Markdown() = %q", md)
+	}
+}
@@ -2,12 +2,21 @@ package pack
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	neturl "net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
+
+	"github.com/nvandessel/floop/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 const (
@@ -19,9 +28,23 @@ const (
 
 // FetchOptions configures pack file downloading.
 type FetchOptions struct {
-	CacheDir  string // override cache dir (default: ~/.floop/cache/packs)
-	Force     bool   // re-download even if cached
-	AuthToken string // optional Bearer token for authenticated downloads
+	CacheDir    string // override cache dir (default: ~/.floop/cache/packs)
+	Force       bool   // re-download even if cached
+	AuthToken   string // optional Bearer token for authenticated downloads
+	Accept      string // optional Accept header
+	SHA256      string // expected hex SHA-256 digest of the file, if known
+	ChecksumURL string // sidecar .sha256 file to verify against when SHA256 is empty
+}
+
+// HTTPStatusError is returned by Fetch when the server answers with a
+// status other than 200 OK.
+type HTTPStatusError struct {
+	StatusCode int
+	URL        string
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("download failed: HTTP %d from %s", e.StatusCode, e.URL)
 }
 
 // FetchResult reports the outcome of a fetch operation.
@@ -29,6 +52,7 @@ type FetchResult struct {
 	LocalPath string // path to the downloaded file
 	Cached    bool   // true if served from cache (no download)
 	Size      int64  // file size in bytes
+	Verified  bool   // true if checked against a SHA-256 digest
 }
 
 // DefaultCacheDir returns the default pack cache directory.
@@ -41,19 +65,54 @@ func DefaultCacheDir() (string, error) {
 }
 
 // Fetch downloads a URL to the given cachePath. If the file already exists
-// and Force is false, it returns immediately with Cached=true.
+// and Force is false, it returns immediately with Cached=true, unless it
+// fails a known SHA256 digest, in which case it is downloaded again.
 //
-// Downloads use atomic write (tmp file + rename) to prevent partial files.
-// File size is limited to MaxPackSize (50MB).
+// The download is written to cachePath plus ".partial" and moved into the
+// cache only once complete and verified, so the cache never holds a partial
+// or corrupt file. Network errors and 5xx responses are retried, resuming
+// with a range request from where the download stopped. A partial file left
+// by an earlier, interrupted fetch is resumed only when a digest will verify
+// the result. File size is limited to MaxPackSize (50MB).
 func Fetch(ctx context.Context, url string, cachePath string, opts FetchOptions) (*FetchResult, error) {
+	ctx, span := tracing.Start(ctx, "pack.Fetch", attribute.String("url.full", traceURL(url)))
+	result, err := fetch(ctx, url, cachePath, opts)
+	if result != nil {
+		span.SetAttributes(
+			attribute.Bool("floop.pack.cached", result.Cached),
+			attribute.Bool("floop.pack.verified", result.Verified),
+			attribute.Int64("floop.pack.size", result.Size),
+		)
+	}
+	tracing.End(span, err)
+	return result, err
+}
+
+// traceURL returns rawURL without credentials or query, for spans.
+func traceURL(rawURL string) string {
+	u, err := neturl.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	u.User = nil
+	u.RawQuery = ""
+	u.Fragment = ""
+	return u.String()
+}
+
+func fetch(ctx context.Context, url string, cachePath string, opts FetchOptions) (*FetchResult, error) {
 	// Check cache
 	if !opts.Force {
 		if info, err := os.Stat(cachePath); err == nil {
-			return &FetchResult{
-				LocalPath: cachePath,
-				Cached:    true,
-				Size:      info.Size(),
-			}, nil
+			if opts.SHA256 == "" || verifySHA256(cachePath, opts.SHA256) == nil {
+				touchCacheFile(cachePath)
+				return &FetchResult{
+					LocalPath: cachePath,
+					Cached:    true,
+					Size:      info.Size(),
+					Verified:  opts.SHA256 != "",
+				}, nil
+			}
 		}
 	}
 
@@ -63,58 +122,241 @@ func Fetch(ctx context.Context, url string, cachePath string, opts FetchOptions)
 		return nil, fmt.Errorf("creating cache directory: %w", err)
 	}
 
-	// Download
 	client := &http.Client{Timeout: FetchTimeout}
+	want := opts.SHA256
+	if want == "" && opts.ChecksumURL != "" {
+		var err error
+		if want, err = fetchChecksum(ctx, client, opts.ChecksumURL, url, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	partialPath := cachePath + partialSuffix
+	if want == "" || opts.Force {
+		// Without a digest, a leftover partial file could belong to an
+		// older version of the file; start over.
+		os.Remove(partialPath)
+	}
+
+	for attempt := 1; want == "" || verifySHA256(partialPath, want) != nil; attempt++ {
+		from, err := fetchPartial(ctx, client, url, partialPath, opts)
+		if err == nil {
+			if err = verifySHA256(partialPath, want); err == nil {
+				break
+			}
+			os.Remove(partialPath)
+			// A resumed download may have joined two versions of the file;
+			// start over once before giving up.
+			if from == 0 || attempt >= maxFetchAttempts {
+				return nil, fmt.Errorf("verifying %s: %w", url, err)
+			}
+			continue
+		}
+		var transient *transientError
+		if !errors.As(err, &transient) || attempt >= maxFetchAttempts {
+			return nil, err
+		}
+		if err := sleepContext(ctx, time.Duration(attempt)*fetchRetryDelay); err != nil {
+			return nil, err
+		}
+	}
+
+	info, err := os.Stat(partialPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading download: %w", err)
+	}
+	if err := os.Rename(partialPath, cachePath); err != nil {
+		return nil, fmt.Errorf("moving download to cache: %w", err)
+	}
+
+	return &FetchResult{
+		LocalPath: cachePath,
+		Cached:    false,
+		Size:      info.Size(),
+		Verified:  want != "",
+	}, nil
+}
+
+const (
+	// partialSuffix marks a download in progress, next to its cache path.
+	partialSuffix = ".partial"
+	// maxFetchAttempts bounds the attempts Fetch makes at one download.
+	maxFetchAttempts = 3
+)
+
+// fetchRetryDelay is the wait before a second attempt at a download; later
+// attempts wait proportionally longer.
+var fetchRetryDelay = time.Second
+
+// transientError marks a download failure worth retrying.
+type transientError struct {
+	err error
+}
+
+func (e *transientError) Error() string { return e.err.Error() }
+func (e *transientError) Unwrap() error { return e.err }
+
+// fetchPartial downloads url into partialPath, resuming from the end of an
+// existing partial file when the server supports range requests. It returns
+// the offset the download resumed from.
+func fetchPartial(ctx context.Context, client *http.Client, url, partialPath string, opts FetchOptions) (int64, error) {
+	var offset int64
+	if info, err := os.Stat(partialPath); err == nil {
+		offset = info.Size()
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating download request: %w", err)
+		return 0, fmt.Errorf("creating download request: %w", err)
 	}
 	if opts.AuthToken != "" {
 		req.Header.Set("Authorization", "Bearer "+opts.AuthToken)
 	}
+	if opts.Accept != "" {
+		req.Header.Set("Accept", opts.Accept)
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
 
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("downloading %s: %w", url, err)
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, &transientError{fmt.Errorf("downloading %s: %w", url, err)}
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("download failed: HTTP %d from %s", resp.StatusCode, url)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		offset = 0 // the server sent the whole file
+	case resp.StatusCode == http.StatusPartialContent && offset > 0:
+		if start, ok := contentRangeStart(resp.Header.Get("Content-Range")); !ok || start != offset {
+			os.Remove(partialPath)
+			return 0, &transientError{fmt.Errorf("downloading %s: server resumed at the wrong offset", url)}
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable && offset > 0:
+		// The partial file is no prefix of the current file.
+		os.Remove(partialPath)
+		return 0, &transientError{&HTTPStatusError{StatusCode: resp.StatusCode, URL: url}}
+	case resp.StatusCode >= 500:
+		return 0, &transientError{&HTTPStatusError{StatusCode: resp.StatusCode, URL: url}}
+	default:
+		return 0, &HTTPStatusError{StatusCode: resp.StatusCode, URL: url}
 	}
 
-	// Atomic write: download to tmp, then rename
-	tmpFile, err := os.CreateTemp(dir, "fpack-download-*.tmp")
+	flag := os.O_CREATE | os.O_WRONLY | os.O_APPEND
+	if offset == 0 {
+		flag |= os.O_TRUNC
+	}
+	f, err := os.OpenFile(partialPath, flag, 0644)
 	if err != nil {
-		return nil, fmt.Errorf("creating temp file: %w", err)
+		return 0, fmt.Errorf("creating partial file: %w", err)
+	}
+	n, copyErr := io.Copy(f, io.LimitReader(resp.Body, MaxPackSize-offset+1))
+	if err := f.Close(); err != nil && copyErr == nil {
+		copyErr = err
+	}
+	if offset+n > MaxPackSize {
+		os.Remove(partialPath)
+		return 0, fmt.Errorf("download exceeds maximum size (%dMB)", MaxPackSize>>20)
+	}
+	if copyErr != nil {
+		if ctx.Err() != nil {
+			return 0, ctx.Err()
+		}
+		return 0, &transientError{fmt.Errorf("writing download: %w", copyErr)}
 	}
-	tmpPath := tmpFile.Name()
-	defer func() {
-		tmpFile.Close()
-		os.Remove(tmpPath) // cleanup on error; no-op after successful rename
-	}()
+	return offset, nil
+}
+
+// contentRangeStart parses the first byte position of a Content-Range
+// header such as "bytes 100-199/200".
+func contentRangeStart(header string) (int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, false
+	}
+	start, _, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, false
+	}
+	n, err := strconv.ParseInt(start, 10, 64)
+	return n, err == nil
+}
 
-	// Copy with size limit
-	n, err := io.Copy(tmpFile, io.LimitReader(resp.Body, MaxPackSize+1))
+// fetchChecksum downloads a sidecar checksum file and returns the SHA-256
+// digest it holds. The auth token is only sent if the checksum file is on
+// the same host as the download it verifies.
+func fetchChecksum(ctx context.Context, client *http.Client, checksumURL, downloadURL string, opts FetchOptions) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, checksumURL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("writing download: %w", err)
+		return "", fmt.Errorf("creating checksum request: %w", err)
 	}
-	if n > MaxPackSize {
-		return nil, fmt.Errorf("download exceeds maximum size (%dMB)", MaxPackSize>>20)
+	if opts.AuthToken != "" && sameHost(checksumURL, downloadURL) {
+		req.Header.Set("Authorization", "Bearer "+opts.AuthToken)
+	}
+	if opts.Accept != "" {
+		req.Header.Set("Accept", opts.Accept)
 	}
 
-	if err := tmpFile.Close(); err != nil {
-		return nil, fmt.Errorf("closing temp file: %w", err)
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetching checksum %s: %w", checksumURL, err)
 	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetching checksum: %w", &HTTPStatusError{StatusCode: resp.StatusCode, URL: checksumURL})
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+	if err != nil {
+		return "", fmt.Errorf("reading checksum %s: %w", checksumURL, err)
+	}
+	sum, ok := ParseSHA256(string(body))
+	if !ok {
+		return "", fmt.Errorf("checksum file %s holds no SHA-256 digest", checksumURL)
+	}
+	return sum, nil
+}
 
-	// Rename to final path
-	if err := os.Rename(tmpPath, cachePath); err != nil {
-		return nil, fmt.Errorf("moving download to cache: %w", err)
+// ParseSHA256 extracts a hex SHA-256 digest from a checksum: a bare digest,
+// a "sha256:<hex>" digest as GitHub reports for release assets, or the
+// first line of a sha256sum file ("<hex>  <name>").
+func ParseSHA256(s string) (string, bool) {
+	fields := strings.Fields(s)
+	if len(fields) == 0 {
+		return "", false
+	}
+	sum := strings.ToLower(strings.TrimPrefix(fields[0], "sha256:"))
+	if len(sum) != sha256.Size*2 {
+		return "", false
+	}
+	if _, err := hex.DecodeString(sum); err != nil {
+		return "", false
+	}
+	return sum, true
+}
+
+// verifySHA256 checks a file against a hex SHA-256 digest; an empty digest
+// always passes.
+func verifySHA256(path, want string) error {
+	if want == "" {
+		return nil
+	}
+	got, err := FileSHA256(path)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(got, want) {
+		return fmt.Errorf("checksum mismatch: got sha256 %s, want %s", got, want)
 	}
+	return nil
+}
 
-	return &FetchResult{
-		LocalPath: cachePath,
-		Cached:    false,
-		Size:      n,
-	}, nil
+// sameHost reports whether two URLs are on the same host.
+func sameHost(a, b string) bool {
+	ua, errA := neturl.Parse(a)
+	ub, errB := neturl.Parse(b)
+	return errA == nil && errB == nil && ua.Host == ub.Host
 }
@@ -2,12 +2,15 @@ package pack
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestFetch_Download(t *testing.T) {
@@ -168,6 +171,246 @@ func TestFetch_CreatesParentDirs(t *testing.T) {
 	}
 }
 
+// noRetryDelay makes Fetch retry immediately for the rest of the test.
+func noRetryDelay(t *testing.T) {
+	t.Helper()
+	old := fetchRetryDelay
+	fetchRetryDelay = 0
+	t.Cleanup(func() { fetchRetryDelay = old })
+}
+
+func TestFetch_ResumesAfterDroppedConnection(t *testing.T) {
+	noRetryDelay(t)
+	content := strings.Repeat("fpack-", 1000)
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if len(ranges) == 1 {
+			// Promise the whole file, send half, and drop the connection.
+			w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+			w.Write([]byte(content[:len(content)/2]))
+			return
+		}
+		var start int
+		fmt.Sscanf(r.Header.Get("Range"), "bytes=%d-", &start)
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, len(content)-1, len(content)))
+		w.WriteHeader(http.StatusPartialContent)
+		w.Write([]byte(content[start:]))
+	}))
+	defer srv.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "resume.fpack")
+	result, err := Fetch(context.Background(), srv.URL+"/resume.fpack", cachePath, FetchOptions{SHA256: sha256Hex([]byte(content))})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if !result.Verified || result.Size != int64(len(content)) {
+		t.Errorf("Fetch() = %+v, want a verified %d byte file", result, len(content))
+	}
+	want := []string{"", fmt.Sprintf("bytes=%d-", len(content)/2)}
+	if strings.Join(ranges, ",") != strings.Join(want, ",") {
+		t.Errorf("Range headers = %q, want %q", ranges, want)
+	}
+	if _, err := os.Stat(cachePath + partialSuffix); !os.IsNotExist(err) {
+		t.Error("partial file left behind after a complete download")
+	}
+}
+
+func TestFetch_ResumesLeftoverPartial(t *testing.T) {
+	content := "0123456789"
+	var gotRange string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotRange = r.Header.Get("Range")
+		http.ServeContent(w, r, "pack.fpack", time.Time{}, strings.NewReader(content))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		sha256    string
+		wantRange string
+	}{
+		{name: "with digest", sha256: sha256Hex([]byte(content)), wantRange: "bytes=4-"},
+		{name: "without digest", wantRange: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cachePath := filepath.Join(t.TempDir(), "pack.fpack")
+			if err := os.WriteFile(cachePath+partialSuffix, []byte("0123"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := Fetch(context.Background(), srv.URL+"/pack.fpack", cachePath, FetchOptions{SHA256: tt.sha256}); err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if gotRange != tt.wantRange {
+				t.Errorf("Range = %q, want %q", gotRange, tt.wantRange)
+			}
+			if data, _ := os.ReadFile(cachePath); string(data) != content {
+				t.Errorf("content = %q, want %q", data, content)
+			}
+		})
+	}
+}
+
+func TestFetch_RestartsMismatchedResume(t *testing.T) {
+	noRetryDelay(t)
+	content := "new-version-of-the-pack"
+	cachePath := filepath.Join(t.TempDir(), "pack.fpack")
+	// A partial file from an older version of the pack.
+	if err := os.WriteFile(cachePath+partialSuffix, []byte("old-"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.ServeContent(w, r, "pack.fpack", time.Time{}, strings.NewReader(content))
+	}))
+	defer srv.Close()
+
+	if _, err := Fetch(context.Background(), srv.URL+"/pack.fpack", cachePath, FetchOptions{SHA256: sha256Hex([]byte(content))}); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if data, _ := os.ReadFile(cachePath); string(data) != content {
+		t.Errorf("content = %q, want %q", data, content)
+	}
+}
+
+func TestFetch_ChecksumMismatch(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tampered"))
+	}))
+	defer srv.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "pack.fpack")
+	_, err := Fetch(context.Background(), srv.URL+"/pack.fpack", cachePath, FetchOptions{SHA256: sha256Hex([]byte("original"))})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("Fetch() error = %v, want a checksum mismatch", err)
+	}
+	for _, path := range []string{cachePath, cachePath + partialSuffix} {
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			t.Errorf("%s exists after a failed verification", filepath.Base(path))
+		}
+	}
+}
+
+func TestFetch_ReplacesCorruptCache(t *testing.T) {
+	content := "good-content"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "pack.fpack")
+	if err := os.WriteFile(cachePath, []byte("good-cont"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	result, err := Fetch(context.Background(), srv.URL+"/pack.fpack", cachePath, FetchOptions{SHA256: sha256Hex([]byte(content))})
+	if err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+	if result.Cached {
+		t.Error("Cached = true for a cached file that fails its digest")
+	}
+	if data, _ := os.ReadFile(cachePath); string(data) != content {
+		t.Errorf("content = %q, want %q", data, content)
+	}
+}
+
+func TestFetch_RetriesServerErrors(t *testing.T) {
+	noRetryDelay(t)
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < maxFetchAttempts {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	if _, err := Fetch(context.Background(), srv.URL+"/pack.fpack", filepath.Join(t.TempDir(), "pack.fpack"), FetchOptions{}); err != nil {
+		t.Fatalf("Fetch() error = %v", err)
+	}
+
+	// Client errors are not retried.
+	calls = 0
+	srv.Config.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusNotFound)
+	})
+	if _, err := Fetch(context.Background(), srv.URL+"/pack.fpack", filepath.Join(t.TempDir(), "pack.fpack"), FetchOptions{}); err == nil {
+		t.Fatal("Fetch() succeeded on a 404")
+	}
+	if calls != 1 {
+		t.Errorf("404 requested %d times, want 1", calls)
+	}
+}
+
+func TestFetch_ChecksumURL(t *testing.T) {
+	content := "pack"
+	var checksumAuth string
+	checksums := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		checksumAuth = r.Header.Get("Authorization")
+		w.Write([]byte(sha256Hex([]byte(content)) + "  pack.fpack\n"))
+	}))
+	defer checksums.Close()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pack.fpack.sha256" {
+			checksumAuth = r.Header.Get("Authorization")
+			w.Write([]byte(sha256Hex([]byte(content))))
+			return
+		}
+		w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		checksumURL string
+		wantAuth    string
+	}{
+		{name: "same host", checksumURL: srv.URL + "/pack.fpack.sha256", wantAuth: "Bearer secret"},
+		{name: "other host", checksumURL: checksums.URL + "/pack.fpack.sha256", wantAuth: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Fetch(context.Background(), srv.URL+"/pack.fpack", filepath.Join(t.TempDir(), "pack.fpack"),
+				FetchOptions{AuthToken: "secret", ChecksumURL: tt.checksumURL})
+			if err != nil {
+				t.Fatalf("Fetch() error = %v", err)
+			}
+			if !result.Verified {
+				t.Error("Verified = false, want true")
+			}
+			if checksumAuth != tt.wantAuth {
+				t.Errorf("checksum Authorization = %q, want %q", checksumAuth, tt.wantAuth)
+			}
+		})
+	}
+}
+
+func TestParseSHA256(t *testing.T) {
+	sum := sha256Hex([]byte("pack"))
+	tests := []struct {
+		in     string
+		want   string
+		wantOK bool
+	}{
+		{sum, sum, true},
+		{strings.ToUpper(sum) + "\n", sum, true},
+		{"sha256:" + sum, sum, true},
+		{sum + "  go.fpack\n" + sha256Hex([]byte("other")) + "  py.fpack\n", sum, true},
+		{"", "", false},
+		{"not-a-digest", "", false},
+		{strings.Repeat("z", 64), "", false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseSHA256(tt.in)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseSHA256(%q) = %q, %v, want %q, %v", tt.in, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
 func TestDefaultCacheDir(t *testing.T) {
 	dir, err := DefaultCacheDir()
 	if err != nil {
@@ -17,6 +17,7 @@ func WritePackFile(path string, data *backup.BackupFormat, manifest PackManifest
 	opts := &backup.WriteOptions{}
 	if writeOpts != nil {
 		opts.FloopVersion = writeOpts.FloopVersion
+		opts.Finalize = writeOpts.Finalize
 		// Start with user metadata
 		opts.Metadata = make(map[string]string)
 		for k, v := range writeOpts.Metadata {
@@ -54,6 +55,15 @@ func WritePackFile(path string, data *backup.BackupFormat, manifest PackManifest
 // ReadPackFile reads a pack file and extracts the manifest from the header.
 // Uses a single file open to avoid TOCTOU races between header and data reads.
 func ReadPackFile(path string) (*backup.BackupFormat, *PackManifest, error) {
+	data, header, err := readPackFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, headerToManifest(header), nil
+}
+
+// readPackFile reads a pack file and returns its data and raw header.
+func readPackFile(path string) (*backup.BackupFormat, *backup.BackupHeader, error) {
 	data, header, err := backup.ReadV2WithHeader(path)
 	if err != nil {
 		return nil, nil, fmt.Errorf("reading pack file: %w", err)
@@ -62,9 +72,7 @@ func ReadPackFile(path string) (*backup.BackupFormat, *PackManifest, error) {
 	if header.Metadata[MetaKeyType] != PackFileType {
 		return nil, nil, fmt.Errorf("file is not a skill pack (type=%q)", header.Metadata[MetaKeyType])
 	}
-
-	manifest := headerToManifest(header)
-	return data, manifest, nil
+	return data, header, nil
 }
 
 // ReadPackHeader reads only the header of a pack file and returns the manifest.
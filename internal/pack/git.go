@@ -0,0 +1,164 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// GitCachePath returns the cache file path for a pack file from a git
+// repository, checked out at tag ("" for the default branch).
+func GitCachePath(cacheDir, repoURL, tag, name string) string {
+	if tag == "" {
+		tag = "HEAD"
+	}
+	return filepath.Join(gitRepoCacheDir(cacheDir, repoURL), tag, name)
+}
+
+// gitRepoCacheDir returns the cache directory for a git repository.
+func gitRepoCacheDir(cacheDir, repoURL string) string {
+	return filepath.Join(cacheDir, "git", fmt.Sprintf("%x", fnvHash(repoURL)))
+}
+
+// FetchGit copies the pack files of a git source into the cache and
+// returns their paths. The repository is shallow-cloned with the git
+// command, so its credential helpers and SSH keys apply. Packs from a tag
+// are served from the cache once fetched; the default branch is fetched
+// every time.
+//
+// With a path, that file is fetched. Without one, the .fpack file at the
+// repository root is; more than one is an error unless allAssets is set.
+func FetchGit(ctx context.Context, resolved *ResolvedSource, cacheDir string, allAssets bool) ([]string, error) {
+	ctx, span := tracing.Start(ctx, "pack.FetchGit",
+		attribute.String("url.full", traceURL(resolved.URL)),
+		attribute.String("floop.pack.version", resolved.Version),
+	)
+	paths, err := fetchGit(ctx, resolved, cacheDir, allAssets)
+	span.SetAttributes(attribute.Int("floop.pack.files", len(paths)))
+	tracing.End(span, err)
+	return paths, err
+}
+
+func fetchGit(ctx context.Context, resolved *ResolvedSource, cacheDir string, allAssets bool) ([]string, error) {
+	if resolved.Version != "" && resolved.Path != "" {
+		cachePath := GitCachePath(cacheDir, resolved.URL, resolved.Version, filepath.Base(resolved.Path))
+		if _, err := os.Stat(cachePath); err == nil {
+			touchCacheFile(cachePath)
+			return []string{cachePath}, nil
+		}
+	}
+
+	checkout, err := os.MkdirTemp("", "floop-git-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating checkout directory: %w", err)
+	}
+	defer os.RemoveAll(checkout)
+
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if resolved.Version != "" {
+		args = append(args, "--branch", resolved.Version)
+	}
+	args = append(args, "--", resolved.URL, checkout)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("cloning %s: %s", resolved.URL, msg)
+		}
+		return nil, fmt.Errorf("cloning %s: %w", resolved.URL, err)
+	}
+
+	var files []string
+	if resolved.Path != "" {
+		files = []string{filepath.Join(checkout, filepath.FromSlash(resolved.Path))}
+	} else {
+		files, _ = filepath.Glob(filepath.Join(checkout, "*.fpack"))
+		switch {
+		case len(files) == 0:
+			return nil, fmt.Errorf("no .fpack file at the root of %s; add #path=<file> to the source", resolved.URL)
+		case len(files) > 1 && !allAssets:
+			names := make([]string, len(files))
+			for i, f := range files {
+				names[i] = filepath.Base(f)
+			}
+			return nil, fmt.Errorf("%s contains multiple .fpack files: %s; use --all-assets to install all, or add #path=<file> to the source",
+				resolved.URL, strings.Join(names, ", "))
+		}
+	}
+
+	root, err := filepath.EvalSymlinks(checkout)
+	if err != nil {
+		return nil, fmt.Errorf("resolving checkout directory: %w", err)
+	}
+	paths := make([]string, 0, len(files))
+	for _, f := range files {
+		name := filepath.Base(f)
+		if resolved.Path != "" {
+			name = resolved.Path
+		}
+		// Symlinks in the repository could point outside the checkout.
+		real, err := filepath.EvalSymlinks(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s not found in %s", name, resolved.URL)
+		}
+		rel, err := filepath.Rel(root, real)
+		if info, statErr := os.Stat(real); err != nil || !filepath.IsLocal(rel) || statErr != nil || !info.Mode().IsRegular() {
+			return nil, fmt.Errorf("%s is not a file in %s", name, resolved.URL)
+		}
+		cachePath := GitCachePath(cacheDir, resolved.URL, resolved.Version, filepath.Base(f))
+		if err := copyToCache(real, cachePath); err != nil {
+			return nil, err
+		}
+		paths = append(paths, cachePath)
+	}
+	return paths, nil
+}
+
+// copyToCache copies a pack file into the cache, atomically and within
+// MaxPackSize.
+func copyToCache(src, cachePath string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", filepath.Base(src), err)
+	}
+	defer in.Close()
+
+	dir := filepath.Dir(cachePath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	tmpFile, err := os.CreateTemp(dir, "fpack-download-*.tmp")
+	if err != nil {
+		return fmt.Errorf("creating temp file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		tmpFile.Close()
+		os.Remove(tmpPath) // cleanup on error; no-op after successful rename
+	}()
+
+	n, err := io.Copy(tmpFile, io.LimitReader(in, MaxPackSize+1))
+	if err != nil {
+		return fmt.Errorf("copying %s: %w", filepath.Base(src), err)
+	}
+	if n > MaxPackSize {
+		return fmt.Errorf("%s exceeds maximum size (%dMB)", filepath.Base(src), MaxPackSize>>20)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("closing temp file: %w", err)
+	}
+	if err := os.Rename(tmpPath, cachePath); err != nil {
+		return fmt.Errorf("moving %s to cache: %w", filepath.Base(src), err)
+	}
+	return nil
+}
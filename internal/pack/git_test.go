@@ -0,0 +1,151 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// gitRepo creates a git repository holding the given files, committed and
+// tagged with tag, and returns its git+file:// URL.
+func gitRepo(t *testing.T, files map[string]string, tag string) string {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	dir := t.TempDir()
+	for name, src := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, out)
+		}
+	}
+	git("init", "--quiet")
+	git("add", ".")
+	git("commit", "--quiet", "-m", "packs")
+	git("tag", tag)
+	return "git+file://" + filepath.ToSlash(dir)
+}
+
+func TestFetchGit(t *testing.T) {
+	goPack := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("b-1", "Use go test")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+	pyPack := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("b-2", "Use pytest")}, nil,
+		PackManifest{ID: "test-org/py-pack", Version: "1.0.0"})
+	single := gitRepo(t, map[string]string{"go.fpack": goPack}, "v1.0.0")
+	multi := gitRepo(t, map[string]string{"go.fpack": goPack, "packs/py.fpack": pyPack, "py.fpack": pyPack}, "v1.0.0")
+
+	tests := []struct {
+		name      string
+		source    string
+		allAssets bool
+		wantIDs   []string
+		wantErr   string
+	}{
+		{name: "only pack at root", source: single, wantIDs: []string{"test-org/go-pack"}},
+		{name: "tag", source: single + "#tag=v1.0.0", wantIDs: []string{"test-org/go-pack"}},
+		{name: "path", source: multi + "#tag=v1.0.0;path=packs/py.fpack", wantIDs: []string{"test-org/py-pack"}},
+		{name: "all assets", source: multi, allAssets: true, wantIDs: []string{"test-org/go-pack", "test-org/py-pack"}},
+		{name: "several packs", source: multi, wantErr: "multiple .fpack files"},
+		{name: "missing path", source: single + "#path=nope.fpack", wantErr: "nope.fpack not found"},
+		{name: "missing tag", source: single + "#tag=v9.9.9", wantErr: "cloning"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolved, err := ResolveSource(tt.source)
+			if err != nil {
+				t.Fatalf("ResolveSource() error = %v", err)
+			}
+			paths, err := FetchGit(context.Background(), resolved, t.TempDir(), tt.allAssets)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("FetchGit() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchGit() error = %v", err)
+			}
+			var ids []string
+			for _, path := range paths {
+				manifest, err := ReadPackHeader(path)
+				if err != nil {
+					t.Fatalf("ReadPackHeader(%s) error = %v", path, err)
+				}
+				ids = append(ids, string(manifest.ID))
+			}
+			if strings.Join(ids, ",") != strings.Join(tt.wantIDs, ",") {
+				t.Errorf("fetched %v, want %v", ids, tt.wantIDs)
+			}
+		})
+	}
+}
+
+func TestFetchGit_SymlinkOutsideRepo(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not available")
+	}
+	secret := filepath.Join(t.TempDir(), "secret.fpack")
+	if err := os.WriteFile(secret, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	repo := t.TempDir()
+	if err := os.Symlink(secret, filepath.Join(repo, "link.fpack")); err != nil {
+		t.Skip("symlinks not supported")
+	}
+	for _, args := range [][]string{{"init", "--quiet"}, {"add", "."}, {"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "link"}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repo
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	resolved, err := ResolveSource("git+file://" + filepath.ToSlash(repo) + "#path=link.fpack")
+	if err != nil {
+		t.Fatalf("ResolveSource() error = %v", err)
+	}
+	if _, err := FetchGit(context.Background(), resolved, t.TempDir(), false); err == nil || !strings.Contains(err.Error(), "not a file") {
+		t.Errorf("FetchGit() error = %v, want the symlink rejected", err)
+	}
+}
+
+func TestFetchLocked_Git(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	packPath := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("b-1", "Use go test")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+	source := gitRepo(t, map[string]string{"go.fpack": packPath}, "v1.0.0")
+	sum, err := FileSHA256(packPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An untagged source is fetched at the locked version's tag.
+	path, err := FetchLocked(context.Background(), LockedPack{ID: "test-org/go-pack", Version: "1.0.0", Source: source, SHA256: sum}, t.TempDir(), nil)
+	if err != nil {
+		t.Fatalf("FetchLocked() error = %v", err)
+	}
+	if !strings.Contains(filepath.ToSlash(path), "/v1.0.0/") {
+		t.Errorf("FetchLocked() = %s, want the v1.0.0 tag", path)
+	}
+}
@@ -3,14 +3,18 @@ package pack
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/nvandessel/floop/internal/config"
 )
 
 // GitHubRelease represents a GitHub release.
@@ -23,38 +27,60 @@ type GitHubRelease struct {
 // GitHubAsset represents a file attached to a release.
 type GitHubAsset struct {
 	Name               string `json:"name"`
+	URL                string `json:"url"` // API URL; serves private assets to an authenticated client
 	BrowserDownloadURL string `json:"browser_download_url"`
 	Size               int    `json:"size"`
 	ContentType        string `json:"content_type"`
+	Digest             string `json:"digest"` // e.g. "sha256:<hex>"; absent on older releases
 }
 
+// Rate-limit backoff: a request rejected by a rate limit that lifts within
+// maxRateLimitWait is retried, up to maxRateLimitRetries times.
+const (
+	maxRateLimitWait    = time.Minute
+	maxRateLimitRetries = 3
+)
+
 // GitHubClient interacts with the GitHub REST API.
 type GitHubClient struct {
-	httpClient *http.Client
-	token      string
-	baseURL    string // for testing; defaults to https://api.github.com
+	httpClient  *http.Client
+	token       string
+	tokenSource string // where the token came from, for error messages
+	baseURL     string // for testing; defaults to https://api.github.com
+
+	maxWait time.Duration
+	sleep   func(context.Context, time.Duration) error
 }
 
-// NewGitHubClient creates a GitHubClient with token resolved from environment.
+// NewGitHubClient creates a GitHubClient with a token resolved from the
+// environment or cfg, which may be nil.
 //
 // Token resolution order:
 //  1. GITHUB_TOKEN env var
-//  2. `gh auth token` command output
-//  3. empty (unauthenticated, subject to rate limits)
-func NewGitHubClient() *GitHubClient {
+//  2. packs.github_token in config
+//  3. `gh auth token` command output
+//  4. empty (unauthenticated: public repositories only, subject to rate limits)
+func NewGitHubClient(cfg *config.FloopConfig) *GitHubClient {
+	token, source := resolveGitHubToken(cfg)
 	return &GitHubClient{
-		httpClient: &http.Client{Timeout: 30 * time.Second},
-		token:      resolveGitHubToken(),
-		baseURL:    "https://api.github.com",
+		httpClient:  &http.Client{Timeout: 30 * time.Second},
+		token:       token,
+		tokenSource: source,
+		baseURL:     "https://api.github.com",
+		maxWait:     maxRateLimitWait,
+		sleep:       sleepContext,
 	}
 }
 
 // newGitHubClientForTest creates a GitHubClient pointed at a test server.
 func newGitHubClientForTest(baseURL, token string) *GitHubClient {
 	return &GitHubClient{
-		httpClient: &http.Client{Timeout: 5 * time.Second},
-		token:      token,
-		baseURL:    baseURL,
+		httpClient:  &http.Client{Timeout: 5 * time.Second},
+		token:       token,
+		tokenSource: "test",
+		baseURL:     baseURL,
+		maxWait:     maxRateLimitWait,
+		sleep:       func(context.Context, time.Duration) error { return nil },
 	}
 }
 
@@ -69,16 +95,7 @@ func (c *GitHubClient) ResolveRelease(ctx context.Context, owner, repo, version
 		endpoint = fmt.Sprintf("%s/repos/%s/%s/releases/tags/%s", c.baseURL, owner, repo, version)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
-	if err != nil {
-		return nil, fmt.Errorf("creating request: %w", err)
-	}
-	req.Header.Set("Accept", "application/vnd.github+json")
-	if c.token != "" {
-		req.Header.Set("Authorization", "Bearer "+c.token)
-	}
-
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.get(ctx, endpoint, "application/vnd.github+json")
 	if err != nil {
 		return nil, fmt.Errorf("fetching release: %w", err)
 	}
@@ -89,18 +106,12 @@ func (c *GitHubClient) ResolveRelease(ctx context.Context, owner, repo, version
 		return nil, fmt.Errorf("reading response: %w", err)
 	}
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		// success
-	case http.StatusNotFound:
+	if resp.StatusCode != http.StatusOK {
+		notFound := fmt.Sprintf("no releases found for %s/%s", owner, repo)
 		if version != "" {
-			return nil, fmt.Errorf("release %q not found for %s/%s", version, owner, repo)
+			notFound = fmt.Sprintf("release %q not found for %s/%s", version, owner, repo)
 		}
-		return nil, fmt.Errorf("no releases found for %s/%s", owner, repo)
-	case http.StatusForbidden:
-		return nil, fmt.Errorf("GitHub API rate limit exceeded for %s/%s; set GITHUB_TOKEN env var to authenticate", owner, repo)
-	default:
-		return nil, fmt.Errorf("GitHub API error %d for %s/%s: %s", resp.StatusCode, owner, repo, string(body))
+		return nil, c.statusError(resp, body, owner+"/"+repo, notFound)
 	}
 
 	var release GitHubRelease
@@ -111,6 +122,139 @@ func (c *GitHubClient) ResolveRelease(ctx context.Context, owner, repo, version
 	return &release, nil
 }
 
+// DownloadAsset downloads a release asset to cachePath. An authenticated
+// client downloads through the API, which private repositories require;
+// the browser URL only serves public ones.
+//
+// The download is verified against the digest GitHub reports for the
+// asset or, for releases without digests, a sidecar asset named after it
+// with a .sha256 suffix, if the release has one.
+func (c *GitHubClient) DownloadAsset(ctx context.Context, release *GitHubRelease, asset GitHubAsset, cachePath string) (*FetchResult, error) {
+	url, opts := c.assetURL(asset)
+	if sum, ok := ParseSHA256(asset.Digest); ok {
+		opts.SHA256 = sum
+	} else {
+		for _, a := range release.Assets {
+			if a.Name == asset.Name+".sha256" {
+				opts.ChecksumURL, _ = c.assetURL(a)
+				break
+			}
+		}
+	}
+
+	result, err := Fetch(ctx, url, cachePath, opts)
+	var status *HTTPStatusError
+	if errors.As(err, &status) {
+		switch {
+		case status.StatusCode == http.StatusNotFound && c.token == "":
+			return nil, fmt.Errorf("%w; if the repository is private, set GITHUB_TOKEN or packs.github_token", err)
+		case status.StatusCode == http.StatusUnauthorized || status.StatusCode == http.StatusForbidden || status.StatusCode == http.StatusNotFound:
+			return nil, fmt.Errorf("%w; check that the token from %s can read the repository", err, c.tokenSource)
+		}
+	}
+	return result, err
+}
+
+// assetURL returns the URL to download a release asset from, and the
+// options to download it with.
+func (c *GitHubClient) assetURL(asset GitHubAsset) (string, FetchOptions) {
+	if c.token != "" && strings.HasPrefix(asset.URL, c.baseURL+"/") {
+		return asset.URL, FetchOptions{AuthToken: c.token, Accept: "application/octet-stream"}
+	}
+	return asset.BrowserDownloadURL, FetchOptions{}
+}
+
+// get sends a GET request to the API, waiting out rate limits that lift
+// within the client's maximum wait.
+func (c *GitHubClient) get(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+		if err != nil {
+			return nil, fmt.Errorf("creating request: %w", err)
+		}
+		req.Header.Set("Accept", accept)
+		if c.token != "" {
+			req.Header.Set("Authorization", "Bearer "+c.token)
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		wait, limited := rateLimitWait(resp, time.Now())
+		if !limited || wait < 0 || wait > c.maxWait || attempt >= maxRateLimitRetries {
+			return resp, nil
+		}
+		resp.Body.Close()
+		if err := c.sleep(ctx, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// statusError explains a failed API response for repo.
+func (c *GitHubClient) statusError(resp *http.Response, body []byte, repo, notFound string) error {
+	authHint := "set GITHUB_TOKEN or packs.github_token to authenticate"
+	switch resp.StatusCode {
+	case http.StatusNotFound:
+		if c.token == "" {
+			return fmt.Errorf("%s; if the repository is private, %s", notFound, authHint)
+		}
+		return fmt.Errorf("%s; if the repository is private, check that the token from %s can read it", notFound, c.tokenSource)
+	case http.StatusUnauthorized:
+		return fmt.Errorf("GitHub rejected the token from %s for %s (401): check that it is valid and not expired", c.tokenSource, repo)
+	case http.StatusForbidden, http.StatusTooManyRequests:
+		if wait, limited := rateLimitWait(resp, time.Now()); limited {
+			msg := fmt.Sprintf("GitHub API rate limit exceeded for %s", repo)
+			if wait >= 0 {
+				msg += fmt.Sprintf("; it resets in %s", wait.Round(time.Second))
+			}
+			if c.token == "" {
+				msg += "; " + authHint
+			}
+			return errors.New(msg)
+		}
+		if c.token == "" {
+			return fmt.Errorf("GitHub API rate limit exceeded or access denied for %s; %s", repo, authHint)
+		}
+		return fmt.Errorf("GitHub denied access to %s (403): the token from %s needs read access to the repository's contents", repo, c.tokenSource)
+	default:
+		return fmt.Errorf("GitHub API error %d for %s: %s", resp.StatusCode, repo, string(body))
+	}
+}
+
+// rateLimitWait reports whether resp was rejected by a rate limit, and
+// how long until it lifts: -1 if the response does not say.
+func rateLimitWait(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusTooManyRequests {
+		return 0, false
+	}
+	// Secondary rate limits send Retry-After.
+	if secs, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") != "0" {
+		return 0, false
+	}
+	reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return -1, true
+	}
+	return max(time.Unix(reset, 0).Sub(now), 0), true
+}
+
+// sleepContext waits for d, or until ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
 // FindPackAssets returns all .fpack assets from a release.
 func FindPackAssets(release *GitHubRelease) []GitHubAsset {
 	var assets []GitHubAsset
@@ -122,25 +266,27 @@ func FindPackAssets(release *GitHubRelease) []GitHubAsset {
 	return assets
 }
 
-// AssetDownloadURL returns the download URL for a release asset.
-// It prefers browser_download_url which works without authentication.
-func AssetDownloadURL(asset GitHubAsset) string {
-	return asset.BrowserDownloadURL
-}
-
 // ReleaseVersion returns the version from a release tag, normalizing the v prefix.
 func ReleaseVersion(release *GitHubRelease) string {
 	return strings.TrimPrefix(release.TagName, "v")
 }
 
-// resolveGitHubToken tries to find a GitHub token from environment or gh CLI.
-func resolveGitHubToken() string {
+// resolveGitHubToken finds a GitHub token, returning it and where it came
+// from.
+func resolveGitHubToken(cfg *config.FloopConfig) (string, string) {
 	// 1. GITHUB_TOKEN env var
 	if token := os.Getenv("GITHUB_TOKEN"); token != "" {
-		return token
+		return token, "GITHUB_TOKEN"
+	}
+
+	// 2. packs.github_token in config
+	if cfg != nil {
+		if token := cfg.Packs.GitHubAuthToken(); token != "" {
+			return token, "packs.github_token"
+		}
 	}
 
-	// 2. gh auth token (with timeout to avoid hanging)
+	// 3. gh auth token (with timeout to avoid hanging)
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 	cmd := exec.CommandContext(ctx, "gh", "auth", "token")
@@ -148,11 +294,11 @@ func resolveGitHubToken() string {
 	if err == nil {
 		token := strings.TrimSpace(string(out))
 		if token != "" {
-			return token
+			return token, "gh auth token"
 		}
 	}
 
-	return ""
+	return "", ""
 }
 
 // CachePath returns the cache file path for a GitHub release asset.
@@ -2,11 +2,19 @@ package pack
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
 )
 
 func TestResolveRelease_Latest(t *testing.T) {
@@ -98,6 +106,196 @@ func TestResolveRelease_RateLimit(t *testing.T) {
 	}
 }
 
+func TestResolveRelease_RateLimitBackoff(t *testing.T) {
+	requests := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.Header().Set("X-RateLimit-Remaining", "0")
+			w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(10*time.Second).Unix(), 10))
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		json.NewEncoder(w).Encode(GitHubRelease{TagName: "v1.0.0"})
+	}))
+	defer srv.Close()
+
+	client := newGitHubClientForTest(srv.URL, "")
+	var slept []time.Duration
+	client.sleep = func(_ context.Context, d time.Duration) error {
+		slept = append(slept, d)
+		return nil
+	}
+	if _, err := client.ResolveRelease(context.Background(), "owner", "repo", ""); err != nil {
+		t.Fatalf("ResolveRelease() error = %v", err)
+	}
+	if requests != 2 || len(slept) != 1 || slept[0] <= 0 || slept[0] > 11*time.Second {
+		t.Errorf("requests = %d, slept %v; want one wait of about 10s", requests, slept)
+	}
+}
+
+func TestResolveRelease_RateLimitTooLong(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	client := newGitHubClientForTest(srv.URL, "test-token")
+	client.sleep = func(context.Context, time.Duration) error {
+		t.Error("waited for a rate limit that resets in an hour")
+		return nil
+	}
+	_, err := client.ResolveRelease(context.Background(), "owner", "repo", "")
+	if err == nil || !contains(err.Error(), "rate limit exceeded for owner/repo; it resets in") {
+		t.Errorf("error = %v, want the reset time", err)
+	}
+}
+
+func TestResolveRelease_AuthErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		token  string
+		want   string
+	}{
+		{"bad token", http.StatusUnauthorized, "test-token", "rejected the token from test"},
+		{"token without access", http.StatusForbidden, "test-token", "needs read access"},
+		{"private without token", http.StatusNotFound, "", "if the repository is private, set GITHUB_TOKEN"},
+		{"private with token", http.StatusNotFound, "test-token", "check that the token from test can read it"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			client := newGitHubClientForTest(srv.URL, tt.token)
+			_, err := client.ResolveRelease(context.Background(), "owner", "repo", "v1.0.0")
+			if err == nil || !contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDownloadAsset_Private(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/owner/repo/releases/assets/1" ||
+			r.Header.Get("Authorization") != "Bearer test-token" || r.Header.Get("Accept") != "application/octet-stream" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte("pack"))
+	}))
+	defer srv.Close()
+
+	asset := GitHubAsset{
+		Name:               "go.fpack",
+		URL:                srv.URL + "/repos/owner/repo/releases/assets/1",
+		BrowserDownloadURL: srv.URL + "/owner/repo/releases/download/v1.0.0/go.fpack",
+	}
+
+	release := &GitHubRelease{TagName: "v1.0.0", Assets: []GitHubAsset{asset}}
+	client := newGitHubClientForTest(srv.URL, "test-token")
+	result, err := client.DownloadAsset(context.Background(), release, asset, filepath.Join(t.TempDir(), "go.fpack"))
+	if err != nil {
+		t.Fatalf("DownloadAsset() error = %v", err)
+	}
+	if result.Size != int64(len("pack")) {
+		t.Errorf("Size = %d, want %d", result.Size, len("pack"))
+	}
+
+	// Without a token, the browser URL is used, which private repos 404.
+	client = newGitHubClientForTest(srv.URL, "")
+	_, err = client.DownloadAsset(context.Background(), release, asset, filepath.Join(t.TempDir(), "go.fpack"))
+	if err == nil || !contains(err.Error(), "if the repository is private") {
+		t.Errorf("DownloadAsset() without a token: error = %v", err)
+	}
+}
+
+func TestDownloadAsset_Checksum(t *testing.T) {
+	sum := sha256.Sum256([]byte("pack"))
+	digest := hex.EncodeToString(sum[:])
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/go.fpack":
+			w.Write([]byte("pack"))
+		case "/go.fpack.sha256":
+			w.Write([]byte(digest + "  go.fpack\n"))
+		case "/bad.sha256":
+			w.Write([]byte(strings.Repeat("0", 64)))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	asset := GitHubAsset{Name: "go.fpack", BrowserDownloadURL: srv.URL + "/go.fpack"}
+	sidecar := GitHubAsset{Name: "go.fpack.sha256", BrowserDownloadURL: srv.URL + "/go.fpack.sha256"}
+	badSidecar := GitHubAsset{Name: "go.fpack.sha256", BrowserDownloadURL: srv.URL + "/bad.sha256"}
+	withDigest := asset
+	withDigest.Digest = "sha256:" + digest
+	badDigest := asset
+	badDigest.Digest = "sha256:" + strings.Repeat("0", 64)
+
+	tests := []struct {
+		name         string
+		asset        GitHubAsset
+		assets       []GitHubAsset
+		wantVerified bool
+		wantErr      bool
+	}{
+		{name: "digest", asset: withDigest, wantVerified: true},
+		{name: "sidecar", asset: asset, assets: []GitHubAsset{sidecar}, wantVerified: true},
+		{name: "digest over sidecar", asset: withDigest, assets: []GitHubAsset{badSidecar}, wantVerified: true},
+		{name: "neither", asset: asset},
+		{name: "digest mismatch", asset: badDigest, wantErr: true},
+		{name: "sidecar mismatch", asset: asset, assets: []GitHubAsset{badSidecar}, wantErr: true},
+	}
+	client := newGitHubClientForTest(srv.URL, "")
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			release := &GitHubRelease{TagName: "v1.0.0", Assets: append([]GitHubAsset{tt.asset}, tt.assets...)}
+			cachePath := filepath.Join(t.TempDir(), "go.fpack")
+			result, err := client.DownloadAsset(context.Background(), release, tt.asset, cachePath)
+			if tt.wantErr {
+				if err == nil || !contains(err.Error(), "checksum mismatch") {
+					t.Fatalf("DownloadAsset() error = %v, want a checksum mismatch", err)
+				}
+				if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+					t.Errorf("cache holds %s after a failed verification", cachePath)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("DownloadAsset() error = %v", err)
+			}
+			if result.Verified != tt.wantVerified {
+				t.Errorf("Verified = %v, want %v", result.Verified, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func TestResolveGitHubToken(t *testing.T) {
+	cfg := config.Default()
+	cfg.Packs.GitHubToken = "${FLOOP_TEST_GH_TOKEN}"
+	t.Setenv("FLOOP_TEST_GH_TOKEN", "from-config")
+
+	t.Setenv("GITHUB_TOKEN", "from-env")
+	if token, source := resolveGitHubToken(cfg); token != "from-env" || source != "GITHUB_TOKEN" {
+		t.Errorf("resolveGitHubToken() = %q, %q; want GITHUB_TOKEN first", token, source)
+	}
+
+	t.Setenv("GITHUB_TOKEN", "")
+	if token, source := resolveGitHubToken(cfg); token != "from-config" || source != "packs.github_token" {
+		t.Errorf("resolveGitHubToken() = %q, %q; want packs.github_token", token, source)
+	}
+}
+
 func TestResolveRelease_AuthHeader(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		auth := r.Header.Get("Authorization")
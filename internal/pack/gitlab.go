@@ -0,0 +1,187 @@
+package pack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// GitLabRelease represents a GitLab release.
+type GitLabRelease struct {
+	TagName string `json:"tag_name"`
+	Name    string `json:"name"`
+	Assets  struct {
+		Links []GitLabAssetLink `json:"links"`
+	} `json:"assets"`
+}
+
+// GitLabAssetLink represents a file linked to a release. GitLab releases
+// carry files as links, usually to the project's package registry.
+type GitLabAssetLink struct {
+	Name           string `json:"name"`
+	URL            string `json:"url"`
+	DirectAssetURL string `json:"direct_asset_url"`
+}
+
+// GitLabClient interacts with the GitLab REST API.
+type GitLabClient struct {
+	httpClient *http.Client
+	token      string
+	baseURL    string // instance URL, e.g. https://gitlab.com
+}
+
+// NewGitLabClient creates a GitLabClient for the instance named by the
+// GITLAB_HOST env var (as used by the glab CLI), or gitlab.com. The
+// GITLAB_TOKEN env var, if set, authenticates requests, which private
+// projects need.
+func NewGitLabClient() *GitLabClient {
+	return &GitLabClient{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		token:      os.Getenv("GITLAB_TOKEN"),
+		baseURL:    gitLabBaseURL(),
+	}
+}
+
+// newGitLabClientForTest creates a GitLabClient pointed at a test server.
+func newGitLabClientForTest(baseURL, token string) *GitLabClient {
+	return &GitLabClient{
+		httpClient: &http.Client{Timeout: 5 * time.Second},
+		token:      token,
+		baseURL:    baseURL,
+	}
+}
+
+// gitLabBaseURL returns the URL of the GitLab instance to use.
+func gitLabBaseURL() string {
+	host := strings.TrimSuffix(os.Getenv("GITLAB_HOST"), "/")
+	if host == "" {
+		return "https://gitlab.com"
+	}
+	if !strings.Contains(host, "://") {
+		host = "https://" + host
+	}
+	return host
+}
+
+// Host returns the host name of the client's GitLab instance.
+func (c *GitLabClient) Host() string {
+	if u, err := url.Parse(c.baseURL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return c.baseURL
+}
+
+// ResolveRelease fetches release metadata for a project, given by its
+// full path (group/project). If version is empty, it fetches the latest
+// release; otherwise the release tagged with version.
+func (c *GitLabClient) ResolveRelease(ctx context.Context, project, version string) (*GitLabRelease, error) {
+	endpoint := fmt.Sprintf("%s/api/v4/projects/%s/releases", c.baseURL, url.PathEscape(project))
+	if version == "" {
+		endpoint += "?per_page=1" // newest first
+	} else {
+		endpoint += "/" + url.PathEscape(version)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching release: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)) // 1MB limit for JSON response
+	if err != nil {
+		return nil, fmt.Errorf("reading response: %w", err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		// success
+	case http.StatusNotFound:
+		if version != "" {
+			return nil, fmt.Errorf("release %q not found for %s", version, project)
+		}
+		return nil, fmt.Errorf("project %s not found on %s; set GITLAB_TOKEN for private projects", project, c.Host())
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("GitLab denied access to %s; set GITLAB_TOKEN to authenticate", project)
+	default:
+		return nil, fmt.Errorf("GitLab API error %d for %s: %s", resp.StatusCode, project, string(body))
+	}
+
+	if version != "" {
+		var release GitLabRelease
+		if err := json.Unmarshal(body, &release); err != nil {
+			return nil, fmt.Errorf("parsing release JSON: %w", err)
+		}
+		return &release, nil
+	}
+
+	var releases []GitLabRelease
+	if err := json.Unmarshal(body, &releases); err != nil {
+		return nil, fmt.Errorf("parsing releases JSON: %w", err)
+	}
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("no releases found for %s", project)
+	}
+	return &releases[0], nil
+}
+
+// FindGitLabPackLinks returns all .fpack links from a release.
+func FindGitLabPackLinks(release *GitLabRelease) []GitLabAssetLink {
+	var links []GitLabAssetLink
+	for _, l := range release.Assets.Links {
+		if strings.HasSuffix(l.Name, ".fpack") {
+			links = append(links, l)
+		}
+	}
+	return links
+}
+
+// FindGitLabChecksumURL returns the download URL of the sidecar checksum
+// link for the named asset (its name with a .sha256 suffix), or "" if the
+// release has none.
+func FindGitLabChecksumURL(release *GitLabRelease, assetName string) string {
+	for _, l := range release.Assets.Links {
+		if l.Name == assetName+".sha256" {
+			return l.DownloadURL()
+		}
+	}
+	return ""
+}
+
+// DownloadURL returns the URL to download a release link from, preferring
+// the permanent direct asset URL.
+func (l GitLabAssetLink) DownloadURL() string {
+	if l.DirectAssetURL != "" {
+		return l.DirectAssetURL
+	}
+	return l.URL
+}
+
+// downloadToken returns the token to send when downloading from
+// downloadURL: only links on the client's own instance get it.
+func (c *GitLabClient) downloadToken(downloadURL string) string {
+	if u, err := url.Parse(downloadURL); err == nil && u.Host == c.Host() {
+		return c.token
+	}
+	return ""
+}
+
+// GitLabCachePath returns the cache file path for a GitLab release asset.
+func GitLabCachePath(cacheDir, host, project, version, assetName string) string {
+	return filepath.Join(cacheDir, "gitlab", host, filepath.FromSlash(project), version, assetName)
+}
@@ -0,0 +1,140 @@
+package pack
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+)
+
+func TestGitLabResolveRelease_Latest(t *testing.T) {
+	release := GitLabRelease{TagName: "v1.0.0"}
+	release.Assets.Links = []GitLabAssetLink{{Name: "go.fpack", URL: "https://example.com/go.fpack"}}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/acme%2Fplatform%2Fpacks/releases" || r.URL.Query().Get("per_page") != "1" {
+			t.Errorf("unexpected request: %s", r.URL)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode([]GitLabRelease{release})
+	}))
+	defer srv.Close()
+
+	client := newGitLabClientForTest(srv.URL, "")
+	got, err := client.ResolveRelease(context.Background(), "acme/platform/packs", "")
+	if err != nil {
+		t.Fatalf("ResolveRelease() error = %v", err)
+	}
+	if got.TagName != "v1.0.0" || len(got.Assets.Links) != 1 {
+		t.Errorf("ResolveRelease() = %+v", got)
+	}
+}
+
+func TestGitLabResolveRelease_SpecificVersion(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.EscapedPath() != "/api/v4/projects/acme%2Fpacks/releases/v2.0.0" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(GitLabRelease{TagName: "v2.0.0"})
+	}))
+	defer srv.Close()
+
+	client := newGitLabClientForTest(srv.URL, "")
+	got, err := client.ResolveRelease(context.Background(), "acme/packs", "v2.0.0")
+	if err != nil {
+		t.Fatalf("ResolveRelease() error = %v", err)
+	}
+	if got.TagName != "v2.0.0" {
+		t.Errorf("TagName = %q, want %q", got.TagName, "v2.0.0")
+	}
+
+	if _, err := client.ResolveRelease(context.Background(), "acme/packs", "v9.9.9"); err == nil {
+		t.Error("expected error for a missing release")
+	}
+}
+
+func TestGitLabResolveRelease_NoReleases(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	client := newGitLabClientForTest(srv.URL, "")
+	if _, err := client.ResolveRelease(context.Background(), "acme/packs", ""); err == nil {
+		t.Error("expected error for a project without releases")
+	}
+}
+
+func TestGitLabResolveRelease_AuthHeader(t *testing.T) {
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+		json.NewEncoder(w).Encode([]GitLabRelease{{TagName: "v1.0.0"}})
+	}))
+	defer srv.Close()
+
+	client := newGitLabClientForTest(srv.URL, "glpat-test")
+	if _, err := client.ResolveRelease(context.Background(), "acme/packs", ""); err != nil {
+		t.Fatalf("ResolveRelease() error = %v", err)
+	}
+	if gotAuth != "Bearer glpat-test" {
+		t.Errorf("Authorization = %q, want %q", gotAuth, "Bearer glpat-test")
+	}
+}
+
+func TestFindGitLabPackLinks(t *testing.T) {
+	release := &GitLabRelease{}
+	release.Assets.Links = []GitLabAssetLink{
+		{Name: "go.fpack", URL: "https://gitlab.com/go.fpack", DirectAssetURL: "https://gitlab.com/-/releases/v1/downloads/go.fpack"},
+		{Name: "README.md", URL: "https://gitlab.com/README.md"},
+		{Name: "py.fpack", URL: "https://example.com/py.fpack"},
+	}
+
+	links := FindGitLabPackLinks(release)
+	if len(links) != 2 {
+		t.Fatalf("FindGitLabPackLinks() = %d links, want 2", len(links))
+	}
+	if got := links[0].DownloadURL(); got != "https://gitlab.com/-/releases/v1/downloads/go.fpack" {
+		t.Errorf("DownloadURL() = %q, want the direct asset URL", got)
+	}
+	if got := links[1].DownloadURL(); got != "https://example.com/py.fpack" {
+		t.Errorf("DownloadURL() = %q, want the link URL", got)
+	}
+
+	client := newGitLabClientForTest("https://gitlab.com", "glpat-test")
+	if got := client.downloadToken(links[0].DownloadURL()); got != "glpat-test" {
+		t.Errorf("downloadToken(gitlab.com) = %q, want the token", got)
+	}
+	if got := client.downloadToken(links[1].DownloadURL()); got != "" {
+		t.Errorf("downloadToken(example.com) = %q, want none", got)
+	}
+}
+
+func TestGitLabBaseURL(t *testing.T) {
+	tests := []struct {
+		host string
+		want string
+	}{
+		{"", "https://gitlab.com"},
+		{"gitlab.example.com", "https://gitlab.example.com"},
+		{"http://gitlab.internal:8080/", "http://gitlab.internal:8080"},
+	}
+	for _, tt := range tests {
+		t.Setenv("GITLAB_HOST", tt.host)
+		if got := gitLabBaseURL(); got != tt.want {
+			t.Errorf("gitLabBaseURL() with GITLAB_HOST=%q = %q, want %q", tt.host, got, tt.want)
+		}
+	}
+}
+
+func TestGitLabCachePath(t *testing.T) {
+	got := GitLabCachePath("/cache", "gitlab.com", "acme/platform/packs", "1.0.0", "go.fpack")
+	want := filepath.Join("/cache", "gitlab", "gitlab.com", "acme", "platform", "packs", "1.0.0", "go.fpack")
+	if got != want {
+		t.Errorf("GitLabCachePath() = %q, want %q", got, want)
+	}
+}
@@ -3,19 +3,31 @@ package pack
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/nvandessel/floop/internal/confidence"
 	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
 )
 
 // InstallOptions configures pack installation.
 type InstallOptions struct {
 	DeriveEdges bool   // Automatically derive edges between pack behaviors and existing behaviors
 	Source      string // Canonical source string to record (e.g., "gh:owner/repo@v1.0.0")
+
+	// CollisionPolicy overrides packs.collision_policy from config.
+	CollisionPolicy string
+	// ResolveCollision decides each collision under the prompt policy;
+	// without it, prompt keeps the local behavior.
+	ResolveCollision CollisionResolver
 }
 
 // InstallResult reports what was installed.
@@ -28,23 +40,74 @@ type InstallResult struct {
 	EdgesAdded   int
 	EdgesSkipped int
 	DerivedEdges int // Edges automatically derived between new and existing behaviors
+
+	// Collisions are pack behaviors that clashed with local ones, and how
+	// each was resolved.
+	Collisions []Collision
+
+	Source string // source recorded in config
+	File   string // absolute path of the pack file installed
+	SHA256 string // checksum of the pack file
+
+	// Signature is the pack's signature status; nil when the signature
+	// policy is off.
+	Signature *SignatureStatus
 }
 
 // Install loads a pack file and installs its behaviors into the store.
 // Follows the seeder pattern: skip forgotten, version-gate updates, stamp provenance.
 func Install(ctx context.Context, s store.GraphStore, filePath string, cfg *config.FloopConfig, opts InstallOptions) (*InstallResult, error) {
-	// 1. Read pack file
-	data, manifest, err := ReadPackFile(filePath)
+	// 1. Read pack file and check its signature
+	data, header, err := readPackFile(filePath)
 	if err != nil {
 		return nil, fmt.Errorf("reading pack file: %w", err)
 	}
+	manifest := headerToManifest(header)
+
+	var packs config.PacksConfig
+	if cfg != nil {
+		packs = cfg.Packs
+	}
+	signature, warning, err := checkSignaturePolicy(header, packs)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", manifest.ID, err)
+	}
+	if warning != "" {
+		slog.Warn("pack "+warning, "pack", manifest.ID)
+	}
 
 	result := &InstallResult{
-		PackID:  string(manifest.ID),
-		Version: manifest.Version,
+		PackID:    string(manifest.ID),
+		Version:   manifest.Version,
+		Signature: signature,
+		Source:    opts.Source,
+		File:      filePath,
+	}
+	if result.Source == "" {
+		result.Source = manifest.Source
+	}
+	if abs, err := filepath.Abs(filePath); err == nil {
+		result.File = abs
+	}
+	if result.SHA256, err = FileSHA256(filePath); err != nil {
+		return nil, err
 	}
 
-	// 2. Install nodes
+	policy := opts.CollisionPolicy
+	if policy == "" {
+		policy = packs.EffectiveCollisionPolicy()
+	}
+	if !slices.Contains(config.CollisionPolicies, policy) {
+		return nil, fmt.Errorf("invalid collision policy %q (valid: %s)", policy, strings.Join(config.CollisionPolicies, ", "))
+	}
+	detector := &collisionDetector{packID: string(manifest.ID), threshold: constants.DefaultAutoMergeThreshold}
+
+	// 2. Install nodes; new ones are added together after the loop.
+	// Pack behaviors that collide with local ones are resolved by policy:
+	// remap redirects the pack's edges for renamed or skipped behaviors.
+	var added []store.Node
+	remap := make(map[string]string)
+	merges := make(map[string]string) // local ID -> pack behavior replacing it
 	for _, bn := range data.Nodes {
 		node := bn.Node
 
@@ -56,11 +119,51 @@ func Install(ctx context.Context, s store.GraphStore, filePath string, cfg *conf
 			return nil, fmt.Errorf("checking node %s: %w", node.ID, err)
 		}
 
+		collision, err := detector.detect(ctx, s, node, existing)
+		if err != nil {
+			return nil, err
+		}
+		if collision != nil {
+			// The store holds one behavior per content, so an exact
+			// duplicate under another ID is always kept local.
+			collision.Action = config.CollisionKeepLocal
+			if collision.Kind == CollisionSameID || collision.PackContent != collision.LocalContent {
+				if collision.Action, err = resolveCollision(policy, *collision, opts.ResolveCollision); err != nil {
+					return nil, fmt.Errorf("resolving collision for %s: %w", node.ID, err)
+				}
+			}
+			switch {
+			case collision.Action == config.CollisionKeepLocal:
+				remap[node.ID] = collision.LocalBehavior
+				result.Skipped = append(result.Skipped, node.ID)
+				result.Collisions = append(result.Collisions, *collision)
+				continue
+
+			case collision.Action == config.CollisionRename && collision.Kind == CollisionSameID:
+				collision.InstalledAs = RenamedID(node.ID, string(manifest.ID))
+				remap[node.ID] = collision.InstalledAs
+				node.ID = collision.InstalledAs
+				if existing, err = s.GetNode(ctx, node.ID); err != nil {
+					return nil, fmt.Errorf("checking node %s: %w", node.ID, err)
+				}
+
+			case collision.Action == config.CollisionPreferPack && collision.Kind == CollisionSameID:
+				result.Collisions = append(result.Collisions, *collision)
+				if err := s.UpdateNode(ctx, node); err != nil {
+					return nil, fmt.Errorf("updating node %s: %w", node.ID, err)
+				}
+				result.Updated = append(result.Updated, node.ID)
+				continue
+
+			case collision.Action == config.CollisionPreferPack:
+				merges[collision.LocalBehavior] = node.ID
+			}
+			result.Collisions = append(result.Collisions, *collision)
+		}
+
 		if existing == nil {
 			// New node -- add it
-			if _, err := s.AddNode(ctx, node); err != nil {
-				return nil, fmt.Errorf("adding node %s: %w", node.ID, err)
-			}
+			added = append(added, node)
 			result.Added = append(result.Added, node.ID)
 			continue
 		}
@@ -86,15 +189,42 @@ func Install(ctx context.Context, s store.GraphStore, filePath string, cfg *conf
 		result.Updated = append(result.Updated, node.ID)
 	}
 
-	// 3. Install edges
-	for _, edge := range data.Edges {
-		if err := s.AddEdge(ctx, edge); err != nil {
-			fmt.Fprintf(os.Stderr, "warning: failed to add edge %s -> %s (%s): %v\n",
-				edge.Source, edge.Target, edge.Kind, err)
-			result.EdgesSkipped++
-			continue
+	if err := store.BatchAdd(ctx, s, added, nil); err != nil {
+		return nil, fmt.Errorf("adding nodes: %w", err)
+	}
+	for localID, packBehaviorID := range merges {
+		if err := mergeIntoPack(ctx, s, localID, packBehaviorID, string(manifest.ID)); err != nil {
+			return nil, err
+		}
+	}
+	edges := data.Edges
+	if len(remap) > 0 {
+		edges = make([]store.Edge, len(data.Edges))
+		for i, e := range data.Edges {
+			if id, ok := remap[e.Source]; ok {
+				e.Source = id
+			}
+			if id, ok := remap[e.Target]; ok {
+				e.Target = id
+			}
+			edges[i] = e
+		}
+	}
+
+	// 3. Install edges in one batch, falling back to one at a time so a bad
+	// edge is skipped rather than failing the install. A failed batch wrote
+	// nothing, so retrying its edges cannot duplicate them.
+	if bw, ok := s.(store.BatchWriter); ok && len(edges) > 0 && bw.BatchAdd(ctx, nil, edges) == nil {
+		result.EdgesAdded = len(edges)
+	} else {
+		for _, edge := range edges {
+			if err := s.AddEdge(ctx, edge); err != nil {
+				slog.Warn("failed to add edge", "source", edge.Source, "target", edge.Target, "kind", edge.Kind, "error", err)
+				result.EdgesSkipped++
+				continue
+			}
+			result.EdgesAdded++
 		}
-		result.EdgesAdded++
 	}
 
 	// 4. Sync store
@@ -107,9 +237,9 @@ func Install(ctx context.Context, s store.GraphStore, filePath string, cfg *conf
 		newIDs := make([]string, 0, len(result.Added)+len(result.Updated))
 		newIDs = append(newIDs, result.Added...)
 		newIDs = append(newIDs, result.Updated...)
-		intResult, intErr := IntegratePackBehaviors(ctx, s, newIDs)
+		intResult, intErr := IntegratePackBehaviors(ctx, s, newIDs, packSimilarityTuning(cfg))
 		if intErr != nil {
-			fmt.Fprintf(os.Stderr, "warning: edge derivation failed: %v\n", intErr)
+			slog.Warn("edge derivation failed", "error", intErr)
 		} else {
 			result.DerivedEdges = intResult.EdgesCreated
 		}
@@ -117,13 +247,14 @@ func Install(ctx context.Context, s store.GraphStore, filePath string, cfg *conf
 
 	// 5. Record in config
 	if cfg != nil {
-		recordInstall(cfg, manifest, result, opts.Source)
+		recordInstall(cfg, manifest, result)
 	}
 
 	return result, nil
 }
 
-// stampProvenance sets package and package_version in the node's provenance metadata.
+// stampProvenance sets package and package_version in the node's provenance
+// metadata and records the pack's confidence as the behavior's base.
 func stampProvenance(node *store.Node, manifest *PackManifest) {
 	if node.Metadata == nil {
 		node.Metadata = make(map[string]interface{})
@@ -137,23 +268,39 @@ func stampProvenance(node *store.Node, manifest *PackManifest) {
 	prov["package"] = string(manifest.ID)
 	prov["package_version"] = manifest.Version
 	node.Metadata["provenance"] = prov
+
+	confidence.SetBase(node.Metadata, utils.GetFloat64(node.Metadata, "confidence", 0.6), confidence.OriginPack)
 }
 
-// recordInstall updates the config's installed packs list.
-// source is the canonical source string (e.g., "gh:owner/repo@v1.0.0"); falls back to manifest.Source.
-func recordInstall(cfg *config.FloopConfig, manifest *PackManifest, result *InstallResult, source string) {
+// maxPackHistory is how many previous versions of a pack are kept for
+// rollback.
+const maxPackHistory = 5
+
+// recordInstall updates the config's installed packs list with the
+// result's source and pack file. When a different version replaces an
+// installed one, the old version is pushed onto the pack's history so it
+// can be rolled back to.
+func recordInstall(cfg *config.FloopConfig, manifest *PackManifest, result *InstallResult) {
 	// Remove existing entry for this pack if present
+	var history []config.PackVersion
 	filtered := make([]config.InstalledPack, 0, len(cfg.Packs.Installed))
 	for _, p := range cfg.Packs.Installed {
 		if p.ID != string(manifest.ID) {
 			filtered = append(filtered, p)
+			continue
+		}
+		history = p.History
+		if p.Version != manifest.Version {
+			history = append(history, config.PackVersion{
+				Version:     p.Version,
+				InstalledAt: p.InstalledAt,
+				Source:      p.Source,
+				Path:        p.Path,
+			})
 		}
 	}
-
-	// Resolve source: prefer explicit source, fall back to manifest
-	recordedSource := source
-	if recordedSource == "" {
-		recordedSource = manifest.Source
+	if len(history) > maxPackHistory {
+		history = history[len(history)-maxPackHistory:]
 	}
 
 	// Add new entry
@@ -161,9 +308,11 @@ func recordInstall(cfg *config.FloopConfig, manifest *PackManifest, result *Inst
 		ID:            string(manifest.ID),
 		Version:       manifest.Version,
 		InstalledAt:   time.Now(),
-		Source:        recordedSource,
+		Source:        result.Source,
 		BehaviorCount: len(result.Added) + len(result.Updated) + len(result.Skipped),
 		EdgeCount:     result.EdgesAdded,
+		Path:          result.File,
+		History:       history,
 	})
 
 	cfg.Packs.Installed = filtered
@@ -173,6 +322,14 @@ func recordInstall(cfg *config.FloopConfig, manifest *PackManifest, result *Inst
 type InstallFromSourceOptions struct {
 	DeriveEdges bool
 	AllAssets   bool // install all .fpack assets from a multi-asset GitHub release
+
+	// Lock, when set, makes the install frozen: every pack fetched must be
+	// in the lockfile at its locked version and checksum, or nothing is
+	// installed.
+	Lock *Lockfile
+
+	CollisionPolicy  string            // overrides packs.collision_policy
+	ResolveCollision CollisionResolver // decides collisions under the prompt policy
 }
 
 // InstallFromSource resolves a source string, fetches remote packs if needed,
@@ -182,96 +339,218 @@ type InstallFromSourceOptions struct {
 //   - Local path: ./pack.fpack, /abs/path.fpack
 //   - HTTP URL: https://example.com/pack.fpack
 //   - GitHub shorthand: gh:owner/repo, gh:owner/repo@v1.2.3
+//   - GitLab shorthand: gl:group/project, gl:group/project@v1.2.3
+//   - Git repository: git+https://host/repo.git#tag=v1.2.3;path=pack.fpack
+//   - Registry pack: registry:namespace/name, registry:namespace/name@1.2.3
 func InstallFromSource(ctx context.Context, s store.GraphStore, source string, cfg *config.FloopConfig, opts InstallFromSourceOptions) ([]*InstallResult, error) {
-	resolved, err := ResolveSource(source)
+	resolved, paths, err := FetchSource(ctx, source, cfg, opts.AllAssets)
 	if err != nil {
-		return nil, fmt.Errorf("resolving source: %w", err)
+		return nil, err
+	}
+
+	if opts.Lock != nil {
+		for _, path := range paths {
+			if err := opts.Lock.Check(path); err != nil {
+				return nil, err
+			}
+		}
 	}
 
 	installOpts := InstallOptions{
-		DeriveEdges: opts.DeriveEdges,
-		Source:      resolved.Canonical,
+		DeriveEdges:      opts.DeriveEdges,
+		Source:           resolved.Canonical,
+		CollisionPolicy:  opts.CollisionPolicy,
+		ResolveCollision: opts.ResolveCollision,
 	}
 
-	switch resolved.Kind {
-	case SourceLocal:
-		result, err := Install(ctx, s, resolved.FilePath, cfg, installOpts)
+	var results []*InstallResult
+	for _, path := range paths {
+		result, err := Install(ctx, s, path, cfg, installOpts)
 		if err != nil {
+			if resolved.Kind == SourceGitHub || resolved.Kind == SourceGitLab || resolved.Kind == SourceGit {
+				return nil, fmt.Errorf("installing %s: %w", filepath.Base(path), err)
+			}
 			return nil, err
 		}
-		return []*InstallResult{result}, nil
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// FetchSource resolves a source string, as accepted by InstallFromSource,
+// and downloads remote packs into the pack cache. It returns the local path
+// of each pack file: one, unless allAssets is set and a GitHub or GitLab
+// release, or a git repository, has several .fpack files.
+func FetchSource(ctx context.Context, source string, cfg *config.FloopConfig, allAssets bool) (*ResolvedSource, []string, error) {
+	resolved, err := ResolveSource(source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("resolving source: %w", err)
+	}
+
+	switch resolved.Kind {
+	case SourceLocal:
+		return resolved, []string{resolved.FilePath}, nil
 
 	case SourceHTTP:
 		cacheDir, err := DefaultCacheDir()
 		if err != nil {
-			return nil, fmt.Errorf("getting cache directory: %w", err)
+			return nil, nil, fmt.Errorf("getting cache directory: %w", err)
 		}
 		cachePath := HTTPCachePath(cacheDir, resolved.URL)
 
 		fetchResult, err := Fetch(ctx, resolved.URL, cachePath, FetchOptions{})
 		if err != nil {
-			return nil, fmt.Errorf("fetching %s: %w", resolved.URL, err)
-		}
-
-		result, err := Install(ctx, s, fetchResult.LocalPath, cfg, installOpts)
-		if err != nil {
-			return nil, err
+			return nil, nil, fmt.Errorf("fetching %s: %w", resolved.URL, err)
 		}
-		return []*InstallResult{result}, nil
+		return resolved, []string{fetchResult.LocalPath}, nil
 
 	case SourceGitHub:
-		gh := NewGitHubClient()
+		gh := NewGitHubClient(cfg)
 
 		release, err := gh.ResolveRelease(ctx, resolved.Owner, resolved.Repo, resolved.Version)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 
 		packAssets := FindPackAssets(release)
-		if len(packAssets) == 0 {
-			assetNames := make([]string, len(release.Assets))
-			for i, a := range release.Assets {
-				assetNames[i] = a.Name
-			}
-			return nil, fmt.Errorf("no .fpack assets found in release %s; available assets: %s",
-				release.TagName, strings.Join(assetNames, ", "))
+		assetNames := make([]string, len(release.Assets))
+		for i, a := range release.Assets {
+			assetNames[i] = a.Name
 		}
-
-		if len(packAssets) > 1 && !opts.AllAssets {
-			names := make([]string, len(packAssets))
-			for i, a := range packAssets {
-				names[i] = a.Name
-			}
-			return nil, fmt.Errorf("release %s contains multiple .fpack assets: %s; use --all-assets to install all",
-				release.TagName, strings.Join(names, ", "))
+		if err := checkReleaseAssets(release.TagName, assetNames, allAssets); err != nil {
+			return nil, nil, err
 		}
 
 		cacheDir, err := DefaultCacheDir()
 		if err != nil {
-			return nil, fmt.Errorf("getting cache directory: %w", err)
+			return nil, nil, fmt.Errorf("getting cache directory: %w", err)
 		}
 
 		version := ReleaseVersion(release)
 
-		var results []*InstallResult
+		var paths []string
 		for _, asset := range packAssets {
 			cachePath := GitHubCachePath(cacheDir, resolved.Owner, resolved.Repo, version, asset.Name)
-			downloadURL := AssetDownloadURL(asset)
 
-			fetchResult, err := Fetch(ctx, downloadURL, cachePath, FetchOptions{})
+			fetchResult, err := gh.DownloadAsset(ctx, release, asset, cachePath)
 			if err != nil {
-				return nil, fmt.Errorf("fetching %s: %w", asset.Name, err)
+				return nil, nil, fmt.Errorf("fetching %s: %w", asset.Name, err)
 			}
+			paths = append(paths, fetchResult.LocalPath)
+		}
+		return resolved, paths, nil
+
+	case SourceGitLab:
+		gl := NewGitLabClient()
+		project := resolved.Owner + "/" + resolved.Repo
 
-			result, err := Install(ctx, s, fetchResult.LocalPath, cfg, installOpts)
+		release, err := gl.ResolveRelease(ctx, project, resolved.Version)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		packLinks := FindGitLabPackLinks(release)
+		linkNames := make([]string, len(release.Assets.Links))
+		for i, l := range release.Assets.Links {
+			linkNames[i] = l.Name
+		}
+		if err := checkReleaseAssets(release.TagName, linkNames, allAssets); err != nil {
+			return nil, nil, err
+		}
+
+		cacheDir, err := DefaultCacheDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting cache directory: %w", err)
+		}
+
+		version := strings.TrimPrefix(release.TagName, "v")
+
+		var paths []string
+		for _, link := range packLinks {
+			cachePath := GitLabCachePath(cacheDir, gl.Host(), project, version, link.Name)
+			downloadURL := link.DownloadURL()
+
+			fetchResult, err := Fetch(ctx, downloadURL, cachePath, FetchOptions{
+				AuthToken:   gl.downloadToken(downloadURL),
+				ChecksumURL: FindGitLabChecksumURL(release, link.Name),
+			})
 			if err != nil {
-				return nil, fmt.Errorf("installing %s: %w", asset.Name, err)
+				return nil, nil, fmt.Errorf("fetching %s: %w", link.Name, err)
 			}
-			results = append(results, result)
+			paths = append(paths, fetchResult.LocalPath)
+		}
+		return resolved, paths, nil
+
+	case SourceGit:
+		cacheDir, err := DefaultCacheDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting cache directory: %w", err)
+		}
+		paths, err := FetchGit(ctx, resolved, cacheDir, allAssets)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resolved, paths, nil
+
+	case SourceRegistry:
+		var registries []config.Registry
+		if cfg != nil {
+			registries = cfg.Packs.Registries
+		}
+		reg, version, err := NewRegistryClient().Resolve(ctx, registries, resolved.PackID, resolved.Version)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		cacheDir, err := DefaultCacheDir()
+		if err != nil {
+			return nil, nil, fmt.Errorf("getting cache directory: %w", err)
+		}
+		cachePath, err := RegistryCachePath(cacheDir, reg.Name, resolved.PackID, version.Version)
+		if err != nil {
+			return nil, nil, err
 		}
-		return results, nil
+
+		// With the digest, Fetch replaces a cached file that fails it.
+		fetchResult, err := Fetch(ctx, version.URL, cachePath, FetchOptions{SHA256: version.SHA256})
+		if err != nil {
+			return nil, nil, fmt.Errorf("fetching %s: %w", version.URL, err)
+		}
+		// Verify cached files too: the cache is keyed by version, not content.
+		if err := VerifyDownload(fetchResult.LocalPath, reg, version); err != nil {
+			os.Remove(fetchResult.LocalPath)
+			return nil, nil, fmt.Errorf("verifying %s@%s: %w", resolved.PackID, version.Version, err)
+		}
+		manifest, err := ReadPackHeader(fetchResult.LocalPath)
+		if err != nil {
+			return nil, nil, err
+		}
+		if string(manifest.ID) != resolved.PackID {
+			return nil, nil, fmt.Errorf("registry %s served pack %s for %s", reg.Name, manifest.ID, resolved.PackID)
+		}
+		return resolved, []string{fetchResult.LocalPath}, nil
 
 	default:
-		return nil, fmt.Errorf("unsupported source kind: %s", resolved.Kind)
+		return nil, nil, fmt.Errorf("unsupported source kind: %s", resolved.Kind)
+	}
+}
+
+// checkReleaseAssets checks that a release has a pack to install: at least
+// one .fpack asset, and only one unless allAssets is set.
+func checkReleaseAssets(tag string, assetNames []string, allAssets bool) error {
+	var packNames []string
+	for _, name := range assetNames {
+		if strings.HasSuffix(name, ".fpack") {
+			packNames = append(packNames, name)
+		}
+	}
+	if len(packNames) == 0 {
+		return fmt.Errorf("no .fpack assets found in release %s; available assets: %s",
+			tag, strings.Join(assetNames, ", "))
+	}
+	if len(packNames) > 1 && !allAssets {
+		return fmt.Errorf("release %s contains multiple .fpack assets: %s; use --all-assets to install all",
+			tag, strings.Join(packNames, ", "))
 	}
+	return nil
 }
@@ -3,14 +3,17 @@ package pack
 import (
 	"context"
 
+	"github.com/nvandessel/floop/internal/config"
 	"github.com/nvandessel/floop/internal/edges"
+	"github.com/nvandessel/floop/internal/similarity"
 	"github.com/nvandessel/floop/internal/store"
 )
 
 // IntegratePackBehaviors derives edges between newly installed pack behaviors
-// and existing behaviors. Only computes new<->new and new<->existing pairs,
-// skipping existing<->existing pairs for efficiency.
-func IntegratePackBehaviors(ctx context.Context, s store.GraphStore, newNodeIDs []string) (*edges.SubsetResult, error) {
+// and existing behaviors with the given similarity tuning. Only computes
+// new<->new and new<->existing pairs, skipping existing<->existing pairs for
+// efficiency.
+func IntegratePackBehaviors(ctx context.Context, s store.GraphStore, newNodeIDs []string, tuning similarity.Tuning) (*edges.SubsetResult, error) {
 	if len(newNodeIDs) == 0 {
 		return &edges.SubsetResult{}, nil
 	}
@@ -21,5 +24,15 @@ func IntegratePackBehaviors(ctx context.Context, s store.GraphStore, newNodeIDs
 		return nil, err
 	}
 
-	return edges.DeriveEdgesForSubset(ctx, s, newNodeIDs, allBehaviors)
+	return edges.DeriveEdgesForSubsetWithTuning(ctx, s, newNodeIDs, allBehaviors, tuning)
+}
+
+// packSimilarityTuning returns the similarity tuning of the global store,
+// where pack behaviors are installed, or the defaults without a config.
+func packSimilarityTuning(cfg *config.FloopConfig) similarity.Tuning {
+	globalDir, err := store.GlobalFloopPath()
+	if cfg == nil || err != nil {
+		return similarity.DefaultTuning()
+	}
+	return cfg.Similarity.TuningFor(globalDir)
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/similarity"
 	"github.com/nvandessel/floop/internal/store"
 )
 
@@ -46,7 +47,7 @@ func TestIntegratePackBehaviors_CreatesEdges(t *testing.T) {
 	}
 	addTestBehavior(t, ctx, s, newBehavior)
 
-	result, err := IntegratePackBehaviors(ctx, s, []string{"b-pack-new"})
+	result, err := IntegratePackBehaviors(ctx, s, []string{"b-pack-new"}, similarity.DefaultTuning())
 	if err != nil {
 		t.Fatalf("IntegratePackBehaviors() error = %v", err)
 	}
@@ -71,7 +72,7 @@ func TestIntegratePackBehaviors_EmptyNewIDs(t *testing.T) {
 	ctx := context.Background()
 	s := store.NewInMemoryGraphStore()
 
-	result, err := IntegratePackBehaviors(ctx, s, []string{})
+	result, err := IntegratePackBehaviors(ctx, s, []string{}, similarity.DefaultTuning())
 	if err != nil {
 		t.Fatalf("IntegratePackBehaviors() error = %v", err)
 	}
@@ -126,7 +127,7 @@ func TestIntegratePackBehaviors_SkipsExistingExisting(t *testing.T) {
 		addTestBehavior(t, ctx, s, b)
 	}
 
-	result, err := IntegratePackBehaviors(ctx, s, []string{"b-new"})
+	result, err := IntegratePackBehaviors(ctx, s, []string{"b-new"}, similarity.DefaultTuning())
 	if err != nil {
 		t.Fatalf("IntegratePackBehaviors() error = %v", err)
 	}
@@ -0,0 +1,297 @@
+package pack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+	"gopkg.in/yaml.v3"
+)
+
+// LockFileName is the name of the pack lockfile in a project's .floop
+// directory.
+const LockFileName = "packs.lock"
+
+// lockFormatVersion is the current lockfile format.
+const lockFormatVersion = 1
+
+const lockHeader = `# Pack lockfile, maintained by 'floop pack install', 'update', 'rollback',
+# and 'pin'. Commit it: 'floop pack install --frozen' installs exactly these
+# versions and fails if any pack has drifted.
+`
+
+// Lockfile records the exact pack versions installed for a project, so
+// they can be reproduced elsewhere with 'floop pack install --frozen'.
+type Lockfile struct {
+	Version int          `json:"version" yaml:"version"`
+	Packs   []LockedPack `json:"packs" yaml:"packs"`
+}
+
+// LockedPack is one pack in the lockfile.
+type LockedPack struct {
+	ID      string `json:"id" yaml:"id"`
+	Version string `json:"version" yaml:"version"`
+	// Source is where the pack was installed from; local paths inside the
+	// project are relative to the project root.
+	Source string `json:"source" yaml:"source"`
+	// SHA256 is the checksum of the .fpack file.
+	SHA256 string `json:"sha256,omitempty" yaml:"sha256,omitempty"`
+	// Pinned packs are skipped by 'floop pack update'.
+	Pinned bool `json:"pinned,omitempty" yaml:"pinned,omitempty"`
+}
+
+// LockPath returns the lockfile path for a project root.
+func LockPath(root string) string {
+	return filepath.Join(root, ".floop", LockFileName)
+}
+
+// ReadLock reads a lockfile. A missing file yields an empty lockfile.
+func ReadLock(path string) (*Lockfile, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return &Lockfile{Version: lockFormatVersion}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", LockFileName, err)
+	}
+
+	var lock Lockfile
+	if err := yaml.Unmarshal(data, &lock); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", LockFileName, err)
+	}
+	if lock.Version > lockFormatVersion {
+		return nil, fmt.Errorf("%s format version %d is newer than this floop supports (%d)", LockFileName, lock.Version, lockFormatVersion)
+	}
+	lock.Version = lockFormatVersion
+	return &lock, nil
+}
+
+// Write saves the lockfile, sorted by pack ID.
+func (l *Lockfile) Write(path string) error {
+	sort.Slice(l.Packs, func(i, j int) bool { return l.Packs[i].ID < l.Packs[j].ID })
+	data, err := yaml.Marshal(l)
+	if err != nil {
+		return fmt.Errorf("encoding %s: %w", LockFileName, err)
+	}
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, append([]byte(lockHeader), data...), 0644); err != nil {
+		return fmt.Errorf("writing %s: %w", LockFileName, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("writing %s: %w", LockFileName, err)
+	}
+	return nil
+}
+
+// Get returns the locked entry for a pack, or nil.
+func (l *Lockfile) Get(packID string) *LockedPack {
+	for i := range l.Packs {
+		if l.Packs[i].ID == packID {
+			return &l.Packs[i]
+		}
+	}
+	return nil
+}
+
+// Record locks the version an install produced, keeping the pack's pin.
+// root is the project root, against which local sources are made relative.
+func (l *Lockfile) Record(result *InstallResult, root string) {
+	source := result.Source
+	if resolved, err := ResolveSource(source); source == "" || err != nil || resolved.Kind == SourceLocal {
+		source = lockLocalSource(result.File, root)
+	}
+
+	entry := LockedPack{ID: result.PackID, Version: result.Version, Source: source, SHA256: result.SHA256}
+	if existing := l.Get(result.PackID); existing != nil {
+		entry.Pinned = existing.Pinned
+		*existing = entry
+		return
+	}
+	l.Packs = append(l.Packs, entry)
+}
+
+// Remove drops a pack from the lockfile.
+func (l *Lockfile) Remove(packID string) {
+	filtered := l.Packs[:0]
+	for _, p := range l.Packs {
+		if p.ID != packID {
+			filtered = append(filtered, p)
+		}
+	}
+	l.Packs = filtered
+}
+
+// lockLocalSource returns path relative to root when it is inside root,
+// so a committed lockfile works from any checkout.
+func lockLocalSource(path, root string) string {
+	absRoot, err := filepath.Abs(root)
+	if err != nil {
+		return path
+	}
+	if rel, err := filepath.Rel(absRoot, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(rel)
+	}
+	return path
+}
+
+// FetchLocked downloads the exact version of a locked pack and verifies
+// it against the lockfile, returning the local path of the pack file.
+// Unversioned GitHub, GitLab, and registry sources are fetched at the locked
+// version rather than the latest, as are git sources without a tag.
+func FetchLocked(ctx context.Context, p LockedPack, root string, cfg *config.FloopConfig) (string, error) {
+	resolved, err := ResolveSource(p.Source)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", p.ID, err)
+	}
+
+	candidates := []string{p.Source}
+	switch {
+	case resolved.Kind == SourceLocal:
+		path := filepath.FromSlash(p.Source)
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(root, path)
+		}
+		candidates = []string{path}
+	case resolved.Kind == SourceRegistry && resolved.Version == "":
+		candidates[0], _ = resolved.AtVersion(p.Version)
+	case resolved.Version == "":
+		// Release and git tags may or may not carry a "v" prefix.
+		if tagged, ok := resolved.AtVersion("v" + p.Version); ok {
+			plain, _ := resolved.AtVersion(p.Version)
+			candidates = []string{tagged, plain}
+		}
+		if resolved.Kind == SourceGit {
+			// Untagged repositories: the checksum still catches drift.
+			candidates = append(candidates, p.Source)
+		}
+	}
+
+	var path string
+	for _, source := range candidates {
+		var paths []string
+		if _, paths, err = FetchSource(ctx, source, cfg, true); err != nil {
+			continue
+		}
+		for _, candidate := range paths {
+			if manifest, headerErr := ReadPackHeader(candidate); headerErr == nil && string(manifest.ID) == p.ID {
+				path = candidate
+			}
+		}
+		if path != "" {
+			break
+		}
+	}
+	if path == "" {
+		if err == nil {
+			err = fmt.Errorf("no pack %s at %s", p.ID, p.Source)
+		}
+		return "", fmt.Errorf("fetching %s %s: %w", p.ID, p.Version, err)
+	}
+	return path, p.Verify(path)
+}
+
+// Check verifies that the pack file at path is locked, at the version and
+// checksum it has.
+func (l *Lockfile) Check(path string) error {
+	manifest, err := ReadPackHeader(path)
+	if err != nil {
+		return err
+	}
+	locked := l.Get(string(manifest.ID))
+	if locked == nil {
+		return fmt.Errorf("%s is not in %s", manifest.ID, LockFileName)
+	}
+	return locked.Verify(path)
+}
+
+// InstallLocked installs every pack in the lockfile at its locked version.
+// All packs are fetched and verified before any is installed, so drift
+// fails the install without changing the store.
+func InstallLocked(ctx context.Context, s store.GraphStore, lock *Lockfile, root string, cfg *config.FloopConfig, opts InstallOptions) ([]*InstallResult, error) {
+	paths := make([]string, len(lock.Packs))
+	for i, p := range lock.Packs {
+		path, err := FetchLocked(ctx, p, root, cfg)
+		if err != nil {
+			return nil, err
+		}
+		paths[i] = path
+	}
+
+	var results []*InstallResult
+	for i, p := range lock.Packs {
+		opts.Source = p.Source
+		if resolved, err := ResolveSource(p.Source); err == nil && resolved.Kind == SourceLocal {
+			opts.Source = paths[i]
+		}
+		result, err := Install(ctx, s, paths[i], cfg, opts)
+		if err != nil {
+			return nil, fmt.Errorf("installing %s: %w", p.ID, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// UpdateLock applies update to the project's lockfile and saves it. It does
+// nothing in a project without a .floop directory, and does not create an
+// empty lockfile.
+func UpdateLock(root string, update func(*Lockfile)) error {
+	if _, err := os.Stat(filepath.Join(root, ".floop")); err != nil {
+		return nil
+	}
+	path := LockPath(root)
+	lock, err := ReadLock(path)
+	if err != nil {
+		return err
+	}
+	update(lock)
+	if _, err := os.Stat(path); os.IsNotExist(err) && len(lock.Packs) == 0 {
+		return nil
+	}
+	return lock.Write(path)
+}
+
+// Verify checks that the pack file at path is the locked version, with the
+// locked checksum.
+func (p LockedPack) Verify(path string) error {
+	manifest, err := ReadPackHeader(path)
+	if err != nil {
+		return err
+	}
+	if string(manifest.ID) != p.ID {
+		return fmt.Errorf("%s is pack %s, %s locks %s", path, manifest.ID, LockFileName, p.ID)
+	}
+	if manifest.Version != p.Version {
+		return fmt.Errorf("%s drifted: found version %s, %s locks %s", p.ID, manifest.Version, LockFileName, p.Version)
+	}
+	if p.SHA256 == "" {
+		return nil
+	}
+	sum, err := FileSHA256(path)
+	if err != nil {
+		return err
+	}
+	if sum != p.SHA256 {
+		return fmt.Errorf("%s %s drifted: checksum %s, %s locks %s", p.ID, p.Version, sum, LockFileName, p.SHA256)
+	}
+	return nil
+}
+
+// FileSHA256 returns the hex SHA-256 checksum of a file.
+func FileSHA256(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("reading %s: %w", path, err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,123 @@
+package pack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestLockfile_RecordAndWrite(t *testing.T) {
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, ".floop"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	path := LockPath(root)
+
+	lock, err := ReadLock(path)
+	if err != nil || len(lock.Packs) != 0 {
+		t.Fatalf("ReadLock(missing) = %+v, %v; want empty", lock, err)
+	}
+
+	local := filepath.Join(root, "packs", "go.fpack")
+	lock.Record(&InstallResult{PackID: "test-org/go", Version: "1.0.0", Source: local, File: local, SHA256: "abc"}, root)
+	lock.Record(&InstallResult{PackID: "test-org/api", Version: "2.0.0", Source: "registry:test-org/api", SHA256: "def"}, root)
+	lock.Get("test-org/api").Pinned = true
+	lock.Record(&InstallResult{PackID: "test-org/api", Version: "2.1.0", Source: "registry:test-org/api", SHA256: "123"}, root)
+	if err := lock.Write(path); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	got, err := ReadLock(path)
+	if err != nil {
+		t.Fatalf("ReadLock() error = %v", err)
+	}
+	if len(got.Packs) != 2 || got.Packs[0].ID != "test-org/api" {
+		t.Fatalf("Packs = %+v, want 2 sorted by ID", got.Packs)
+	}
+	if api := got.Packs[0]; api.Version != "2.1.0" || api.SHA256 != "123" || !api.Pinned {
+		t.Errorf("api = %+v, want 2.1.0 still pinned", api)
+	}
+	if goPack := got.Packs[1]; goPack.Source != "packs/go.fpack" {
+		t.Errorf("local source = %q, want relative to the project root", goPack.Source)
+	}
+
+	got.Remove("test-org/go")
+	if len(got.Packs) != 1 || got.Get("test-org/go") != nil {
+		t.Errorf("after Remove, Packs = %+v", got.Packs)
+	}
+}
+
+func TestUpdateLock_NoFloopDir(t *testing.T) {
+	root := t.TempDir()
+	err := UpdateLock(root, func(lock *Lockfile) {
+		lock.Record(&InstallResult{PackID: "test-org/go", Version: "1.0.0", Source: "registry:test-org/go"}, root)
+	})
+	if err != nil {
+		t.Fatalf("UpdateLock() error = %v", err)
+	}
+	if _, err := os.Stat(LockPath(root)); !os.IsNotExist(err) {
+		t.Error("UpdateLock wrote a lockfile without a .floop directory")
+	}
+}
+
+func TestInstallFrozen(t *testing.T) {
+	ctx := context.Background()
+	root := t.TempDir()
+	packDir := filepath.Join(root, "packs")
+	if err := os.MkdirAll(packDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	packPath := writeTestPack(t, packDir, []store.Node{rollbackNode("b-1", "Use go test")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+
+	s := store.NewInMemoryGraphStore()
+	result, err := Install(ctx, s, packPath, config.Default(), InstallOptions{Source: packPath})
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	lock := &Lockfile{Version: lockFormatVersion}
+	lock.Record(result, root)
+
+	// Installing the lockfile into a fresh store reproduces the pack.
+	fresh := store.NewInMemoryGraphStore()
+	results, err := InstallLocked(ctx, fresh, lock, root, config.Default(), InstallOptions{})
+	if err != nil {
+		t.Fatalf("InstallLocked() error = %v", err)
+	}
+	if len(results) != 1 || len(results[0].Added) != 1 {
+		t.Errorf("InstallLocked() = %+v, want b-1 added", results)
+	}
+	if _, err := InstallFromSource(ctx, fresh, packPath, config.Default(), InstallFromSourceOptions{Lock: lock}); err != nil {
+		t.Errorf("frozen install of the locked file: %v", err)
+	}
+
+	// A rebuilt pack with the same version drifts on its checksum.
+	writeTestPack(t, packDir, []store.Node{rollbackNode("b-1", "Use go test -race")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+	drifted := store.NewInMemoryGraphStore()
+	if _, err := InstallLocked(ctx, drifted, lock, root, config.Default(), InstallOptions{}); err == nil || !strings.Contains(err.Error(), "checksum") {
+		t.Errorf("InstallLocked() after rebuild error = %v, want checksum drift", err)
+	}
+	if node, _ := drifted.GetNode(ctx, "b-1"); node != nil {
+		t.Error("drifted frozen install wrote to the store")
+	}
+
+	// A new version drifts on its version.
+	writeTestPack(t, packDir, []store.Node{rollbackNode("b-1", "Use go test")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.1.0"})
+	if _, err := InstallFromSource(ctx, drifted, packPath, config.Default(), InstallFromSourceOptions{Lock: lock}); err == nil || !strings.Contains(err.Error(), "locks 1.0.0") {
+		t.Errorf("frozen install of 1.1.0 error = %v, want version drift", err)
+	}
+
+	// Packs missing from the lockfile are drift too.
+	other := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("b-2", "Wrap errors")}, nil,
+		PackManifest{ID: "test-org/other", Version: "1.0.0"})
+	if _, err := InstallFromSource(ctx, drifted, other, config.Default(), InstallFromSourceOptions{Lock: lock}); err == nil || !strings.Contains(err.Error(), "not in packs.lock") {
+		t.Errorf("frozen install of an unlocked pack error = %v", err)
+	}
+}
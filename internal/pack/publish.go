@@ -0,0 +1,225 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/sanitize"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Pre-publish lint checks.
+const (
+	LintManifest    = "manifest"
+	LintForgotten   = "forgotten"
+	LintUnsanitized = "unsanitized"
+	LintEdge        = "edge"
+)
+
+// LintIssue is a problem that blocks publishing a pack.
+type LintIssue struct {
+	Check      string `json:"check"`
+	BehaviorID string `json:"behavior_id,omitempty"`
+	Message    string `json:"message"`
+}
+
+func (i LintIssue) String() string {
+	if i.BehaviorID == "" {
+		return fmt.Sprintf("[%s] %s", i.Check, i.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", i.Check, i.BehaviorID, i.Message)
+}
+
+// LintError is returned by Publish when the pre-publish lint fails.
+type LintError struct {
+	Issues []LintIssue
+}
+
+func (e *LintError) Error() string {
+	return fmt.Sprintf("pre-publish lint found %d issues", len(e.Issues))
+}
+
+// validEdgeKinds are the edge kinds a pack may carry.
+var validEdgeKinds = map[store.EdgeKind]bool{
+	store.EdgeKindRequires:     true,
+	store.EdgeKindOverrides:    true,
+	store.EdgeKindConflicts:    true,
+	store.EdgeKindSimilarTo:    true,
+	store.EdgeKindLearnedFrom:  true,
+	store.EdgeKindCoActivated:  true,
+	store.EdgeKindDeprecatedTo: true,
+	store.EdgeKindMergedInto:   true,
+}
+
+// LintPack checks a pack file before publishing: the manifest must have a
+// valid ID, a version, and a description; no behavior may be forgotten;
+// names and content must already be sanitized, as learned behaviors are;
+// and every edge must have a known kind, a positive weight, and both
+// endpoints in the pack.
+func LintPack(path string) (*PackManifest, []LintIssue, error) {
+	data, manifest, err := ReadPackFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var issues []LintIssue
+	if err := ValidatePackID(string(manifest.ID)); err != nil {
+		issues = append(issues, LintIssue{Check: LintManifest, Message: err.Error()})
+	}
+	if manifest.Version == "" {
+		issues = append(issues, LintIssue{Check: LintManifest, Message: "version is required"})
+	}
+	if strings.TrimSpace(manifest.Description) == "" {
+		issues = append(issues, LintIssue{Check: LintManifest, Message: "description is required for registry search"})
+	}
+	if len(data.Nodes) == 0 {
+		issues = append(issues, LintIssue{Check: LintManifest, Message: "pack has no behaviors"})
+	}
+
+	ids := make(map[string]bool, len(data.Nodes))
+	for _, bn := range data.Nodes {
+		node := bn.Node
+		ids[node.ID] = true
+		if node.Kind == store.NodeKindForgotten {
+			issues = append(issues, LintIssue{Check: LintForgotten, BehaviorID: node.ID, Message: "behavior is forgotten"})
+			continue
+		}
+		if name, _ := node.Content["name"].(string); name != sanitize.SanitizeBehaviorName(name) {
+			issues = append(issues, LintIssue{Check: LintUnsanitized, BehaviorID: node.ID, Message: fmt.Sprintf("name %q contains disallowed characters", name)})
+		}
+		content, _ := node.Content["content"].(map[string]interface{})
+		for _, field := range []string{"canonical", "summary"} {
+			text, _ := content[field].(string)
+			if text != sanitize.SanitizeBehaviorContent(text) {
+				issues = append(issues, LintIssue{Check: LintUnsanitized, BehaviorID: node.ID, Message: field + " contains markup or control characters that would be stripped on learn"})
+			}
+		}
+	}
+
+	for _, e := range data.Edges {
+		label := fmt.Sprintf("%s -> %s (%s)", e.Source, e.Target, e.Kind)
+		switch {
+		case !validEdgeKinds[e.Kind]:
+			issues = append(issues, LintIssue{Check: LintEdge, BehaviorID: e.Source, Message: label + ": unknown edge kind"})
+		case !ids[e.Source] || !ids[e.Target]:
+			issues = append(issues, LintIssue{Check: LintEdge, BehaviorID: e.Source, Message: label + ": endpoint not in pack"})
+		case e.Weight <= 0 || e.Weight > 1:
+			issues = append(issues, LintIssue{Check: LintEdge, BehaviorID: e.Source, Message: fmt.Sprintf("%s: weight %g outside (0, 1]", label, e.Weight)})
+		}
+	}
+	return manifest, issues, nil
+}
+
+// PublishOptions configures Publish.
+type PublishOptions struct {
+	// SigningKey signs the upload so registries with a public key accept it.
+	SigningKey ed25519.PrivateKey
+	// DryRun lints and checksums the pack without uploading it.
+	DryRun bool
+}
+
+// PublishResult reports a publish.
+type PublishResult struct {
+	PackID   string `json:"pack_id"`
+	Version  string `json:"version"`
+	Registry string `json:"registry"`
+	SHA256   string `json:"sha256"`
+	Size     int64  `json:"size"`
+	Signed   bool   `json:"signed"`
+	// URL is where the registry serves the pack, if it said.
+	URL    string `json:"url,omitempty"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// Publish lints a pack file and uploads it to a registry with
+// PUT <registry>/packs/<namespace>/<name>/<version>, authenticated by the
+// registry's token. The body is the .fpack file; its SHA-256 digest and,
+// when a signing key is given, its ed25519 signature travel in the
+// X-Floop-Sha256 and X-Floop-Signature headers. Lint failures are returned
+// as a *LintError.
+func Publish(ctx context.Context, path string, reg config.Registry, opts PublishOptions) (*PublishResult, error) {
+	manifest, issues, err := LintPack(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) > 0 {
+		return nil, &LintError{Issues: issues}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading pack file: %w", err)
+	}
+	if len(data) > MaxPackSize {
+		return nil, fmt.Errorf("pack exceeds maximum size (%dMB)", MaxPackSize>>20)
+	}
+	sum := sha256.Sum256(data)
+	result := &PublishResult{
+		PackID:   string(manifest.ID),
+		Version:  manifest.Version,
+		Registry: reg.Name,
+		SHA256:   hex.EncodeToString(sum[:]),
+		Size:     int64(len(data)),
+		Signed:   opts.SigningKey != nil,
+		DryRun:   opts.DryRun,
+	}
+	if opts.DryRun {
+		return result, nil
+	}
+
+	token := reg.AuthToken()
+	if token == "" {
+		return nil, fmt.Errorf("registry %s has no token; set token (or token: ${ENV_VAR}) on the registry in ~/.floop/config.yaml", reg.Name)
+	}
+	uploadURL, err := resolveRegistryURL(reg, "packs/"+string(manifest.ID)+"/"+manifest.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, uploadURL, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("creating upload request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Floop-Sha256", result.SHA256)
+	if opts.SigningKey != nil {
+		req.Header.Set("X-Floop-Signature", base64.StdEncoding.EncodeToString(ed25519.Sign(opts.SigningKey, data)))
+	}
+
+	client := &http.Client{Timeout: FetchTimeout}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("uploading to registry %s: %w", reg.Name, err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+		var reply struct {
+			URL string `json:"url"`
+		}
+		if json.Unmarshal(body, &reply) == nil {
+			result.URL = reply.URL
+		}
+		return result, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("registry %s rejected the token (HTTP %d)", reg.Name, resp.StatusCode)
+	case http.StatusConflict:
+		return nil, fmt.Errorf("%s v%s is already published to registry %s; bump the version", manifest.ID, manifest.Version, reg.Name)
+	default:
+		return nil, fmt.Errorf("registry %s upload failed: HTTP %d: %s", reg.Name, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+}
@@ -0,0 +1,327 @@
+package pack
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/config"
+)
+
+// RegistryIndexFile is the name of the index served at a registry's URL.
+const RegistryIndexFile = "index.json"
+
+// RegistryIndex is the pack listing served by a registry.
+type RegistryIndex struct {
+	Packs []RegistryPack `json:"packs"`
+}
+
+// RegistryPack describes one pack in a registry index.
+type RegistryPack struct {
+	ID          string            `json:"id"`
+	Description string            `json:"description,omitempty"`
+	Author      string            `json:"author,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	Latest      string            `json:"latest,omitempty"` // defaults to the highest version
+	Versions    []RegistryVersion `json:"versions"`
+}
+
+// RegistryVersion is one downloadable release of a registry pack.
+type RegistryVersion struct {
+	Version string `json:"version"`
+	// URL of the .fpack file, absolute or relative to the index.
+	URL string `json:"url"`
+	// SHA256 is the hex digest of the .fpack file. Required.
+	SHA256 string `json:"sha256"`
+	// Signature is a base64 ed25519 signature of the .fpack file, required
+	// when the registry is configured with a public key.
+	Signature string `json:"signature,omitempty"`
+	Size      int64  `json:"size,omitempty"`
+}
+
+// LatestVersion returns the pack's latest release, or nil if it has none.
+func (p RegistryPack) LatestVersion() *RegistryVersion {
+	if p.Latest != "" {
+		return p.Version(p.Latest)
+	}
+	var latest *RegistryVersion
+	for i := range p.Versions {
+		if latest == nil || compareVersions(p.Versions[i].Version, latest.Version) > 0 {
+			latest = &p.Versions[i]
+		}
+	}
+	return latest
+}
+
+// Version returns the release with the given version, ignoring a "v" prefix.
+func (p RegistryPack) Version(version string) *RegistryVersion {
+	want := strings.TrimPrefix(version, "v")
+	for i := range p.Versions {
+		if strings.TrimPrefix(p.Versions[i].Version, "v") == want {
+			return &p.Versions[i]
+		}
+	}
+	return nil
+}
+
+// RegistryHit is a search result: a pack and the registry that lists it.
+type RegistryHit struct {
+	Registry string       `json:"registry"`
+	Pack     RegistryPack `json:"pack"`
+}
+
+// RegistryClient queries pack registries over HTTP.
+type RegistryClient struct {
+	httpClient *http.Client
+}
+
+// NewRegistryClient creates a RegistryClient.
+func NewRegistryClient() *RegistryClient {
+	return &RegistryClient{httpClient: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// FetchIndex downloads and parses a registry's index.
+func (c *RegistryClient) FetchIndex(ctx context.Context, reg config.Registry) (*RegistryIndex, error) {
+	indexURL, err := registryIndexURL(reg)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, indexURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("fetching registry %s: %w", reg.Name, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("registry %s: HTTP %d from %s", reg.Name, resp.StatusCode, indexURL)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 10<<20)) // 10MB limit for the index
+	if err != nil {
+		return nil, fmt.Errorf("reading registry %s: %w", reg.Name, err)
+	}
+	var index RegistryIndex
+	if err := json.Unmarshal(body, &index); err != nil {
+		return nil, fmt.Errorf("parsing registry %s index: %w", reg.Name, err)
+	}
+	return &index, nil
+}
+
+// Search returns packs whose ID, description, or tags contain every word of
+// query, case-insensitively, from all registries in order. An empty query
+// lists every pack. A registry that cannot be reached fails the search.
+func (c *RegistryClient) Search(ctx context.Context, registries []config.Registry, query string) ([]RegistryHit, error) {
+	terms := strings.Fields(strings.ToLower(query))
+	var hits []RegistryHit
+	for _, reg := range registries {
+		index, err := c.FetchIndex(ctx, reg)
+		if err != nil {
+			return nil, err
+		}
+		for _, p := range index.Packs {
+			haystack := strings.ToLower(p.ID + " " + p.Description + " " + strings.Join(p.Tags, " "))
+			match := true
+			for _, term := range terms {
+				if !strings.Contains(haystack, term) {
+					match = false
+					break
+				}
+			}
+			if match {
+				hits = append(hits, RegistryHit{Registry: reg.Name, Pack: p})
+			}
+		}
+	}
+	sort.SliceStable(hits, func(i, j int) bool { return hits[i].Pack.ID < hits[j].Pack.ID })
+	return hits, nil
+}
+
+// Resolve finds a pack in the first registry that lists it and returns the
+// requested release (latest if version is empty) with its absolute URL.
+func (c *RegistryClient) Resolve(ctx context.Context, registries []config.Registry, packID, version string) (config.Registry, *RegistryVersion, error) {
+	if len(registries) == 0 {
+		return config.Registry{}, nil, fmt.Errorf("no pack registries configured; add one under packs.registries in ~/.floop/config.yaml")
+	}
+	for _, reg := range registries {
+		index, err := c.FetchIndex(ctx, reg)
+		if err != nil {
+			return config.Registry{}, nil, err
+		}
+		for _, p := range index.Packs {
+			if p.ID != packID {
+				continue
+			}
+			var v *RegistryVersion
+			if version == "" {
+				v = p.LatestVersion()
+			} else {
+				v = p.Version(version)
+			}
+			if v == nil {
+				return config.Registry{}, nil, fmt.Errorf("pack %s has no version %q in registry %s", packID, version, reg.Name)
+			}
+			resolved := *v
+			if resolved.URL, err = resolveRegistryURL(reg, v.URL); err != nil {
+				return config.Registry{}, nil, err
+			}
+			return reg, &resolved, nil
+		}
+	}
+	return config.Registry{}, nil, fmt.Errorf("pack %s not found in any configured registry", packID)
+}
+
+// VerifyDownload checks a downloaded pack file against its registry entry:
+// the SHA-256 digest always, and the ed25519 signature when the registry
+// has a public key.
+func VerifyDownload(path string, reg config.Registry, v *RegistryVersion) error {
+	if v.SHA256 == "" {
+		return fmt.Errorf("registry %s lists no sha256 checksum for version %s", reg.Name, v.Version)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("reading download: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	if got := hex.EncodeToString(sum[:]); !strings.EqualFold(got, v.SHA256) {
+		return fmt.Errorf("checksum mismatch: got sha256 %s, registry %s lists %s", got, reg.Name, v.SHA256)
+	}
+
+	if reg.PublicKey == "" {
+		return nil
+	}
+	key, err := ParsePublicKey(reg.PublicKey)
+	if err != nil {
+		return fmt.Errorf("registry %s: public_key: %w", reg.Name, err)
+	}
+	if v.Signature == "" {
+		return fmt.Errorf("registry %s requires signed packs but version %s is unsigned", reg.Name, v.Version)
+	}
+	sig, err := base64.StdEncoding.DecodeString(v.Signature)
+	if err != nil || !ed25519.Verify(key, data, sig) {
+		return fmt.Errorf("signature verification failed for version %s from registry %s", v.Version, reg.Name)
+	}
+	return nil
+}
+
+// FindRegistry returns the configured registry with the given name. An
+// empty name selects the only registry when exactly one is configured.
+func FindRegistry(registries []config.Registry, name string) (config.Registry, error) {
+	if len(registries) == 0 {
+		return config.Registry{}, fmt.Errorf("no pack registries configured; add one under packs.registries in ~/.floop/config.yaml")
+	}
+	if name == "" {
+		if len(registries) == 1 {
+			return registries[0], nil
+		}
+		names := make([]string, len(registries))
+		for i, r := range registries {
+			names[i] = r.Name
+		}
+		return config.Registry{}, fmt.Errorf("multiple registries configured (%s); choose one with --registry", strings.Join(names, ", "))
+	}
+	for _, r := range registries {
+		if r.Name == name {
+			return r, nil
+		}
+	}
+	return config.Registry{}, fmt.Errorf("registry %q is not configured", name)
+}
+
+// RegistryCachePath returns the cache file path for a registry pack download.
+// The registry name and version come from config and the registry index, so
+// a name or version that is not a single path element, or a path that would
+// leave the registry cache, is an error.
+func RegistryCachePath(cacheDir, registry, packID, version string) (string, error) {
+	if err := ValidatePackID(packID); err != nil {
+		return "", err
+	}
+	for _, part := range []string{registry, version} {
+		if part == "" || part == "." || strings.ContainsAny(part, `/\`) || strings.Contains(part, "..") {
+			return "", fmt.Errorf("invalid registry cache path element %q", part)
+		}
+	}
+	base := filepath.Join(cacheDir, "registry")
+	path := filepath.Join(base, registry, filepath.FromSlash(packID), strings.TrimPrefix(version, "v")+".fpack")
+	if rel, err := filepath.Rel(base, path); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("registry cache path %s escapes %s", path, base)
+	}
+	return path, nil
+}
+
+// registryIndexURL returns the index URL for a registry. A URL ending in
+// .json is used as-is; otherwise index.json is appended.
+func registryIndexURL(reg config.Registry) (string, error) {
+	if reg.URL == "" {
+		return "", fmt.Errorf("registry %s has no url", reg.Name)
+	}
+	if strings.HasSuffix(reg.URL, ".json") {
+		return reg.URL, nil
+	}
+	return strings.TrimSuffix(reg.URL, "/") + "/" + RegistryIndexFile, nil
+}
+
+// resolveRegistryURL resolves a pack URL from an index against the index URL.
+func resolveRegistryURL(reg config.Registry, ref string) (string, error) {
+	indexURL, err := registryIndexURL(reg)
+	if err != nil {
+		return "", err
+	}
+	base, err := url.Parse(indexURL)
+	if err != nil {
+		return "", fmt.Errorf("registry %s: invalid url: %w", reg.Name, err)
+	}
+	target, err := url.Parse(ref)
+	if err != nil {
+		return "", fmt.Errorf("registry %s: invalid pack url %q: %w", reg.Name, ref, err)
+	}
+	return base.ResolveReference(target).String(), nil
+}
+
+// compareVersions compares dotted numeric versions such as "1.10.0" and
+// "v1.9", returning -1, 0, or 1. Non-numeric parts compare as strings.
+func compareVersions(a, b string) int {
+	pa := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	pb := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y string
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		nx, errX := strconv.Atoi(x)
+		ny, errY := strconv.Atoi(y)
+		switch {
+		case errX == nil && errY == nil && nx != ny:
+			if nx < ny {
+				return -1
+			}
+			return 1
+		case (errX != nil || errY != nil) && x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,258 @@
+package pack
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// newTestRegistry serves index at /index.json and the given files by path.
+func newTestRegistry(t *testing.T, index RegistryIndex, files map[string][]byte) *httptest.Server {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/index.json" {
+			json.NewEncoder(w).Encode(index)
+			return
+		}
+		data, ok := files[r.URL.Path]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func TestRegistryClient_Search(t *testing.T) {
+	srv := newTestRegistry(t, RegistryIndex{Packs: []RegistryPack{
+		{ID: "acme/go-style", Description: "Go conventions", Tags: []string{"go", "style"}},
+		{ID: "acme/go-testing", Description: "Table-driven tests", Tags: []string{"go", "testing"}},
+		{ID: "acme/python", Description: "Python tooling"},
+	}}, nil)
+	registries := []config.Registry{{Name: "main", URL: srv.URL}}
+	client := NewRegistryClient()
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"go", []string{"acme/go-style", "acme/go-testing"}},
+		{"GO tests", []string{"acme/go-testing"}},
+		{"", []string{"acme/go-style", "acme/go-testing", "acme/python"}},
+		{"rust", nil},
+	}
+	for _, tt := range tests {
+		hits, err := client.Search(context.Background(), registries, tt.query)
+		if err != nil {
+			t.Fatalf("Search(%q) error = %v", tt.query, err)
+		}
+		var got []string
+		for _, h := range hits {
+			got = append(got, h.Pack.ID)
+			if h.Registry != "main" {
+				t.Errorf("hit registry = %q, want main", h.Registry)
+			}
+		}
+		if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+			t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	broken := append(registries, config.Registry{Name: "down", URL: srv.URL + "/missing"})
+	if _, err := client.Search(context.Background(), broken, "go"); err == nil {
+		t.Error("expected error for unreachable registry")
+	}
+}
+
+func TestRegistryClient_Resolve(t *testing.T) {
+	srv := newTestRegistry(t, RegistryIndex{Packs: []RegistryPack{{
+		ID: "acme/go-style",
+		Versions: []RegistryVersion{
+			{Version: "1.9.0", URL: "packs/go-style-1.9.0.fpack"},
+			{Version: "1.10.0", URL: "https://cdn.example.com/go-style-1.10.0.fpack"},
+		},
+	}}}, nil)
+	registries := []config.Registry{{Name: "main", URL: srv.URL + "/"}}
+	client := NewRegistryClient()
+	ctx := context.Background()
+
+	_, v, err := client.Resolve(ctx, registries, "acme/go-style", "")
+	if err != nil {
+		t.Fatalf("Resolve(latest) error = %v", err)
+	}
+	if v.Version != "1.10.0" || v.URL != "https://cdn.example.com/go-style-1.10.0.fpack" {
+		t.Errorf("Resolve(latest) = %+v", v)
+	}
+
+	_, v, err = client.Resolve(ctx, registries, "acme/go-style", "v1.9.0")
+	if err != nil {
+		t.Fatalf("Resolve(1.9.0) error = %v", err)
+	}
+	if v.URL != srv.URL+"/packs/go-style-1.9.0.fpack" {
+		t.Errorf("relative URL resolved to %q", v.URL)
+	}
+
+	for _, tc := range []struct{ id, version string }{{"acme/missing", ""}, {"acme/go-style", "2.0.0"}} {
+		if _, _, err := client.Resolve(ctx, registries, tc.id, tc.version); err == nil {
+			t.Errorf("Resolve(%s@%s) expected error", tc.id, tc.version)
+		}
+	}
+	if _, _, err := client.Resolve(ctx, nil, "acme/go-style", ""); err == nil || !strings.Contains(err.Error(), "no pack registries") {
+		t.Errorf("Resolve(no registries) error = %v", err)
+	}
+}
+
+func TestVerifyDownload(t *testing.T) {
+	data := []byte("pack-bytes")
+	path := filepath.Join(t.TempDir(), "p.fpack")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+	pub, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(priv, data))
+	otherPub, _, _ := ed25519.GenerateKey(nil)
+
+	unsigned := config.Registry{Name: "open"}
+	signed := config.Registry{Name: "signed", PublicKey: base64.StdEncoding.EncodeToString(pub)}
+	wrongKey := config.Registry{Name: "signed", PublicKey: base64.StdEncoding.EncodeToString(otherPub)}
+
+	tests := []struct {
+		name    string
+		reg     config.Registry
+		version RegistryVersion
+		wantErr string
+	}{
+		{"checksum ok", unsigned, RegistryVersion{SHA256: strings.ToUpper(sha256Hex(data))}, ""},
+		{"checksum missing", unsigned, RegistryVersion{}, "no sha256"},
+		{"checksum mismatch", unsigned, RegistryVersion{SHA256: sha256Hex([]byte("other"))}, "checksum mismatch"},
+		{"signature ok", signed, RegistryVersion{SHA256: sha256Hex(data), Signature: signature}, ""},
+		{"signature missing", signed, RegistryVersion{SHA256: sha256Hex(data)}, "unsigned"},
+		{"signature wrong key", wrongKey, RegistryVersion{SHA256: sha256Hex(data), Signature: signature}, "signature verification failed"},
+		{"bad public key", config.Registry{Name: "bad", PublicKey: "xyz"}, RegistryVersion{SHA256: sha256Hex(data), Signature: signature}, "public_key"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := VerifyDownload(path, tt.reg, &tt.version)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("VerifyDownload() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("VerifyDownload() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstallFromSource_Registry(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	packPath := writeTestPack(t, t.TempDir(), []store.Node{{
+		ID:       "b-style",
+		Kind:     "behavior",
+		Content:  map[string]interface{}{"name": "go-style", "kind": "directive"},
+		Metadata: map[string]interface{}{},
+	}}, nil, PackManifest{ID: "acme/go-style", Version: "1.0.0"})
+	data, err := os.ReadFile(packPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	version := RegistryVersion{Version: "1.0.0", URL: "go-style.fpack", SHA256: sha256Hex(data)}
+	index := RegistryIndex{Packs: []RegistryPack{
+		{ID: "acme/go-style", Versions: []RegistryVersion{version}},
+		{ID: "acme/tampered", Versions: []RegistryVersion{{Version: "1.0.0", URL: "go-style.fpack", SHA256: sha256Hex([]byte("x"))}}},
+	}}
+	srv := newTestRegistry(t, index, map[string][]byte{"/go-style.fpack": data})
+
+	cfg := config.Default()
+	cfg.Packs.Registries = []config.Registry{{Name: "main", URL: srv.URL}}
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+
+	results, err := InstallFromSource(ctx, s, "registry:acme/go-style", cfg, InstallFromSourceOptions{})
+	if err != nil {
+		t.Fatalf("InstallFromSource() error = %v", err)
+	}
+	if len(results) != 1 || len(results[0].Added) != 1 {
+		t.Fatalf("results = %+v", results)
+	}
+	if got := cfg.Packs.Installed[0].Source; got != "registry:acme/go-style" {
+		t.Errorf("recorded source = %q", got)
+	}
+
+	_, err = InstallFromSource(ctx, s, "registry:acme/tampered", cfg, InstallFromSourceOptions{})
+	if err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("tampered install error = %v", err)
+	}
+	cacheDir, _ := DefaultCacheDir()
+	cachePath, _ := RegistryCachePath(cacheDir, "main", "acme/tampered", "1.0.0")
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Error("a download that fails verification should be removed from the cache")
+	}
+}
+
+func TestRegistryCachePath(t *testing.T) {
+	cacheDir := t.TempDir()
+	path, err := RegistryCachePath(cacheDir, "main", "acme/style", "v1.2.0")
+	if err != nil {
+		t.Fatalf("RegistryCachePath: %v", err)
+	}
+	if want := filepath.Join(cacheDir, "registry", "main", "acme", "style", "1.2.0.fpack"); path != want {
+		t.Errorf("path = %s, want %s", path, want)
+	}
+
+	for _, tt := range []struct{ registry, packID, version string }{
+		{"main", "acme/style", "../../../../.bashrc"},
+		{"main", "acme/style", "1.0.0/../../x"},
+		{"main", "acme/style", `..\x`},
+		{"main", "acme/style", ""},
+		{"..", "acme/style", "1.0.0"},
+		{"a/b", "acme/style", "1.0.0"},
+		{"main", "../style", "1.0.0"},
+	} {
+		if _, err := RegistryCachePath(cacheDir, tt.registry, tt.packID, tt.version); err == nil {
+			t.Errorf("RegistryCachePath(%q, %q, %q) succeeded, want an error", tt.registry, tt.packID, tt.version)
+		}
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.10.0", "1.9.0", 1},
+		{"v1.2.0", "1.2.0", 0},
+		{"1.2", "1.2.1", -1},
+		{"2.0.0-beta", "2.0.0-alpha", 1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package pack
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -16,8 +17,17 @@ const (
 	SourceHTTP
 	// SourceGitHub is a GitHub shorthand (gh:owner/repo[@version]).
 	SourceGitHub
+	// SourceRegistry is a pack in a configured registry (registry:namespace/name[@version]).
+	SourceRegistry
+	// SourceGitLab is a GitLab shorthand (gl:group/project[@version]).
+	SourceGitLab
+	// SourceGit is a pack file in a git repository (git+https://host/repo.git#tag=...;path=...).
+	SourceGit
 )
 
+// gitSchemes are the URL schemes a git+ source may use.
+var gitSchemes = []string{"https://", "http://", "ssh://", "file://"}
+
 // String returns a human-readable name for the source kind.
 func (k SourceKind) String() string {
 	switch k {
@@ -27,6 +37,12 @@ func (k SourceKind) String() string {
 		return "http"
 	case SourceGitHub:
 		return "github"
+	case SourceRegistry:
+		return "registry"
+	case SourceGitLab:
+		return "gitlab"
+	case SourceGit:
+		return "git"
 	default:
 		return "unknown"
 	}
@@ -38,10 +54,29 @@ type ResolvedSource struct {
 	Raw       string // original input
 	Canonical string // normalized for storage in config
 	FilePath  string // for SourceLocal: absolute path
-	URL       string // for SourceHTTP: full URL
-	Owner     string // for SourceGitHub
-	Repo      string // for SourceGitHub
-	Version   string // for SourceGitHub ("" = latest)
+	URL       string // for SourceHTTP: full URL; for SourceGit: the repository to clone
+	Owner     string // for SourceGitHub; for SourceGitLab: the group path
+	Repo      string // for SourceGitHub and SourceGitLab
+	Version   string // release or tag; for SourceGit, "" is the default branch, otherwise "" = latest
+	PackID    string // for SourceRegistry
+	Path      string // for SourceGit: the pack file within the repository ("" = the one at its root)
+}
+
+// AtVersion returns the source at version, for the kinds that can name
+// one: GitHub and GitLab releases, git tags, and registry packs.
+func (r *ResolvedSource) AtVersion(version string) (string, bool) {
+	switch r.Kind {
+	case SourceGitHub:
+		return "gh:" + r.Owner + "/" + r.Repo + "@" + version, true
+	case SourceGitLab:
+		return "gl:" + r.Owner + "/" + r.Repo + "@" + version, true
+	case SourceGit:
+		return gitSource(r.URL, version, r.Path), true
+	case SourceRegistry:
+		return "registry:" + r.PackID + "@" + version, true
+	default:
+		return "", false
+	}
 }
 
 // ResolveSource parses a source string into its components.
@@ -49,6 +84,9 @@ type ResolvedSource struct {
 // Supported formats:
 //   - gh:owner/repo          → SourceGitHub (latest release)
 //   - gh:owner/repo@v1.2.3   → SourceGitHub (specific version)
+//   - gl:group/project[@v1]  → SourceGitLab (latest or specific release)
+//   - git+https://host/repo.git#tag=v1;path=x.fpack → SourceGit
+//   - registry:ns/name[@1.0] → SourceRegistry (latest or specific version)
 //   - https://example.com/x  → SourceHTTP
 //   - http://example.com/x   → SourceHTTP
 //   - ./path or /abs/path    → SourceLocal
@@ -62,6 +100,21 @@ func ResolveSource(source string) (*ResolvedSource, error) {
 		return resolveGitHub(source)
 	}
 
+	// GitLab shorthand: gl:group/project[@version]
+	if strings.HasPrefix(source, "gl:") {
+		return resolveGitLab(source)
+	}
+
+	// Git repository: git+<url>[#tag=...;path=...]
+	if strings.HasPrefix(source, "git+") {
+		return resolveGit(source)
+	}
+
+	// Registry pack: registry:namespace/name[@version]
+	if strings.HasPrefix(source, "registry:") {
+		return resolveRegistry(source)
+	}
+
 	// HTTP/HTTPS URL
 	if strings.HasPrefix(source, "https://") || strings.HasPrefix(source, "http://") {
 		return &ResolvedSource{
@@ -116,6 +169,113 @@ func resolveGitHub(source string) (*ResolvedSource, error) {
 	}, nil
 }
 
+// resolveGitLab parses gl:group/project[@version]. Projects may sit in
+// nested groups (gl:group/subgroup/project).
+func resolveGitLab(source string) (*ResolvedSource, error) {
+	rest := strings.TrimPrefix(source, "gl:")
+	project, version, hasVersion := strings.Cut(rest, "@")
+	if hasVersion && version == "" {
+		return nil, fmt.Errorf("invalid GitLab source %q: version after @ is empty", source)
+	}
+	idx := strings.LastIndex(project, "/")
+	if idx <= 0 || idx == len(project)-1 || slices.Contains(strings.Split(project, "/"), "") {
+		return nil, fmt.Errorf("invalid GitLab source %q: expected gl:group/project", source)
+	}
+
+	canonical := "gl:" + project
+	if version != "" {
+		canonical += "@" + version
+	}
+
+	return &ResolvedSource{
+		Kind:      SourceGitLab,
+		Raw:       source,
+		Canonical: canonical,
+		Owner:     project[:idx],
+		Repo:      project[idx+1:],
+		Version:   version,
+	}, nil
+}
+
+// resolveGit parses git+<url>[#tag=...;path=...], where the fragment names
+// the tag to check out and the pack file within the repository.
+func resolveGit(source string) (*ResolvedSource, error) {
+	repoURL, fragment, _ := strings.Cut(strings.TrimPrefix(source, "git+"), "#")
+	if !slices.ContainsFunc(gitSchemes, func(scheme string) bool {
+		return strings.HasPrefix(repoURL, scheme) && len(repoURL) > len(scheme)
+	}) {
+		return nil, fmt.Errorf("invalid git source %q: expected git+https://host/repo.git", source)
+	}
+
+	var tag, path string
+	for _, param := range strings.FieldsFunc(fragment, func(r rune) bool { return r == ';' || r == '&' }) {
+		key, value, _ := strings.Cut(param, "=")
+		if value == "" {
+			return nil, fmt.Errorf("invalid git source %q: %s needs a value", source, key)
+		}
+		switch key {
+		case "tag":
+			tag = value
+		case "path":
+			path = value
+		default:
+			return nil, fmt.Errorf("invalid git source %q: unknown parameter %q (valid: tag, path)", source, key)
+		}
+	}
+	if path != "" && !filepath.IsLocal(filepath.FromSlash(path)) {
+		return nil, fmt.Errorf("invalid git source %q: path must be inside the repository", source)
+	}
+
+	return &ResolvedSource{
+		Kind:      SourceGit,
+		Raw:       source,
+		Canonical: gitSource(repoURL, tag, path),
+		URL:       repoURL,
+		Version:   tag,
+		Path:      path,
+	}, nil
+}
+
+// gitSource formats a git+ source string.
+func gitSource(repoURL, tag, path string) string {
+	var params []string
+	if tag != "" {
+		params = append(params, "tag="+tag)
+	}
+	if path != "" {
+		params = append(params, "path="+path)
+	}
+	if len(params) == 0 {
+		return "git+" + repoURL
+	}
+	return "git+" + repoURL + "#" + strings.Join(params, ";")
+}
+
+// resolveRegistry parses registry:namespace/name[@version].
+func resolveRegistry(source string) (*ResolvedSource, error) {
+	rest := strings.TrimPrefix(source, "registry:")
+	packID, version, hasVersion := strings.Cut(rest, "@")
+	if hasVersion && version == "" {
+		return nil, fmt.Errorf("invalid registry source %q: version after @ is empty", source)
+	}
+	if err := ValidatePackID(packID); err != nil {
+		return nil, fmt.Errorf("invalid registry source %q: %w", source, err)
+	}
+
+	canonical := "registry:" + packID
+	if version != "" {
+		canonical += "@" + version
+	}
+
+	return &ResolvedSource{
+		Kind:      SourceRegistry,
+		Raw:       source,
+		Canonical: canonical,
+		PackID:    packID,
+		Version:   version,
+	}, nil
+}
+
 // resolveLocal resolves a local file path to an absolute path.
 func resolveLocal(source string) (*ResolvedSource, error) {
 	absPath, err := filepath.Abs(source)
@@ -36,6 +36,85 @@ func TestResolveSource(t *testing.T) {
 			wantRepo:  "floop",
 			wantVer:   "v1.2.3",
 		},
+		{
+			name:      "gitlab latest",
+			source:    "gl:acme/floop-packs",
+			wantKind:  SourceGitLab,
+			wantOwner: "acme",
+			wantRepo:  "floop-packs",
+		},
+		{
+			name:      "gitlab nested group with version",
+			source:    "gl:acme/platform/floop-packs@v2.0.0",
+			wantKind:  SourceGitLab,
+			wantOwner: "acme/platform",
+			wantRepo:  "floop-packs",
+			wantVer:   "v2.0.0",
+		},
+		{
+			name:    "gitlab missing project",
+			source:  "gl:acme",
+			wantErr: true,
+		},
+		{
+			name:    "gitlab empty group",
+			source:  "gl:acme//packs",
+			wantErr: true,
+		},
+		{
+			name:    "gitlab empty version after @",
+			source:  "gl:acme/packs@",
+			wantErr: true,
+		},
+		{
+			name:     "git with tag and path",
+			source:   "git+https://git.example.com/packs.git#tag=v1.0.0;path=go/style.fpack",
+			wantKind: SourceGit,
+			wantURL:  "https://git.example.com/packs.git",
+			wantVer:  "v1.0.0",
+		},
+		{
+			name:     "git default branch",
+			source:   "git+ssh://git@git.example.com/packs.git",
+			wantKind: SourceGit,
+			wantURL:  "ssh://git@git.example.com/packs.git",
+		},
+		{
+			name:    "git unsupported scheme",
+			source:  "git+ext::example",
+			wantErr: true,
+		},
+		{
+			name:    "git unknown parameter",
+			source:  "git+https://git.example.com/packs.git#branch=main",
+			wantErr: true,
+		},
+		{
+			name:    "git path outside repository",
+			source:  "git+https://git.example.com/packs.git#path=../secret.fpack",
+			wantErr: true,
+		},
+		{
+			name:     "registry latest",
+			source:   "registry:acme/go-style",
+			wantKind: SourceRegistry,
+		},
+		{
+			name:     "registry with version",
+			source:   "registry:acme/go-style@1.2.0",
+			wantKind: SourceRegistry,
+			wantVer:  "1.2.0",
+		},
+		{
+			name:    "registry invalid pack id",
+			source:  "registry:go-style",
+			wantErr: true,
+		},
+		{
+			name:    "registry empty version after @",
+			source:  "registry:acme/go-style@",
+			wantErr: true,
+		},
 		{
 			name:    "github missing repo",
 			source:  "gh:nvandessel",
@@ -108,7 +187,7 @@ func TestResolveSource(t *testing.T) {
 			}
 
 			switch tt.wantKind {
-			case SourceGitHub:
+			case SourceGitHub, SourceGitLab:
 				if got.Owner != tt.wantOwner {
 					t.Errorf("Owner = %q, want %q", got.Owner, tt.wantOwner)
 				}
@@ -122,6 +201,13 @@ func TestResolveSource(t *testing.T) {
 				if got.URL != tt.wantURL {
 					t.Errorf("URL = %q, want %q", got.URL, tt.wantURL)
 				}
+			case SourceGit:
+				if got.URL != tt.wantURL {
+					t.Errorf("URL = %q, want %q", got.URL, tt.wantURL)
+				}
+				if got.Version != tt.wantVer {
+					t.Errorf("Version = %q, want %q", got.Version, tt.wantVer)
+				}
 			case SourceLocal:
 				if !filepath.IsAbs(got.FilePath) {
 					t.Errorf("FilePath = %q, want absolute path", got.FilePath)
@@ -147,6 +233,16 @@ func TestResolveSource_Canonical(t *testing.T) {
 			source:        "gh:owner/repo@v1.0.0",
 			wantCanonical: "gh:owner/repo@v1.0.0",
 		},
+		{
+			name:          "gitlab canonical with version",
+			source:        "gl:acme/platform/packs@v1.0.0",
+			wantCanonical: "gl:acme/platform/packs@v1.0.0",
+		},
+		{
+			name:          "git canonical orders parameters",
+			source:        "git+https://git.example.com/packs.git#path=go.fpack&tag=v1.0.0",
+			wantCanonical: "git+https://git.example.com/packs.git#tag=v1.0.0;path=go.fpack",
+		},
 		{
 			name:          "http canonical is identity",
 			source:        "https://example.com/pack.fpack",
@@ -187,6 +283,9 @@ func TestSourceKind_String(t *testing.T) {
 		{SourceLocal, "local"},
 		{SourceHTTP, "http"},
 		{SourceGitHub, "github"},
+		{SourceRegistry, "registry"},
+		{SourceGitLab, "gitlab"},
+		{SourceGit, "git"},
 		{SourceKind(99), "unknown"},
 	}
 
@@ -198,3 +297,29 @@ func TestSourceKind_String(t *testing.T) {
 		})
 	}
 }
+
+func TestResolvedSource_AtVersion(t *testing.T) {
+	tests := []struct {
+		source string
+		want   string
+	}{
+		{"gh:owner/repo@v1.0.0", "gh:owner/repo@v2.0.0"},
+		{"gl:acme/platform/packs", "gl:acme/platform/packs@v2.0.0"},
+		{"git+https://git.example.com/packs.git#path=go.fpack", "git+https://git.example.com/packs.git#tag=v2.0.0;path=go.fpack"},
+		{"registry:acme/go-style", "registry:acme/go-style@v2.0.0"},
+		{"https://example.com/pack.fpack", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.source, func(t *testing.T) {
+			resolved, err := ResolveSource(tt.source)
+			if err != nil {
+				t.Fatalf("ResolveSource() error = %v", err)
+			}
+			got, ok := resolved.AtVersion("v2.0.0")
+			if got != tt.want || ok != (tt.want != "") {
+				t.Errorf("AtVersion() = %q, %v, want %q", got, ok, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// RollbackResult reports what a rollback changed.
+type RollbackResult struct {
+	PackID       string
+	FromVersion  string
+	ToVersion    string
+	Reverted     []string // IDs of behaviors restored to the previous version's content
+	Restored     []string // IDs of behaviors the newer version dropped, added back
+	Removed      []string // IDs of behaviors only the newer version had, deleted
+	EdgesRemoved int      // edges only the newer version had
+
+	// Installed is the result of reinstalling the previous version.
+	Installed *InstallResult
+}
+
+// Rollback restores the previously installed version of a pack, as
+// recorded in the pack's history in config. The previous pack file is
+// reinstalled from the cache, or downloaded again if it was pruned, which
+// reverts updated behaviors to their earlier content. Behaviors the newer
+// version added are deleted, so a later update adds them again; ones the
+// user has forgotten are left alone. Edges only the newer version had are
+// removed when its pack file is still available.
+//
+// The rolled-back version is not kept in the history: rolling back again
+// goes to the version before it.
+func Rollback(ctx context.Context, s store.GraphStore, packID string, cfg *config.FloopConfig) (*RollbackResult, error) {
+	if err := ValidatePackID(packID); err != nil {
+		return nil, fmt.Errorf("invalid pack ID: %w", err)
+	}
+	if cfg == nil {
+		return nil, fmt.Errorf("pack %s is not installed", packID)
+	}
+
+	var current *config.InstalledPack
+	for i := range cfg.Packs.Installed {
+		if cfg.Packs.Installed[i].ID == packID {
+			current = &cfg.Packs.Installed[i]
+			break
+		}
+	}
+	if current == nil {
+		return nil, fmt.Errorf("pack %s is not installed", packID)
+	}
+	if len(current.History) == 0 {
+		return nil, fmt.Errorf("no previous version of %s recorded", packID)
+	}
+	currentVersion, currentPath := current.Version, current.Path
+	history := current.History[:len(current.History)-1]
+	previous := current.History[len(current.History)-1]
+
+	path, err := rollbackFile(ctx, packID, previous, cfg)
+	if err != nil {
+		return nil, err
+	}
+	oldData, _, err := ReadPackFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading pack file: %w", err)
+	}
+
+	// 1. Reinstall the previous version; this reverts updated behaviors.
+	installed, err := Install(ctx, s, path, cfg, InstallOptions{Source: previous.Source})
+	if err != nil {
+		return nil, fmt.Errorf("reinstalling %s %s: %w", packID, previous.Version, err)
+	}
+	result := &RollbackResult{
+		PackID:      packID,
+		FromVersion: currentVersion,
+		ToVersion:   previous.Version,
+		Reverted:    installed.Updated,
+		Restored:    installed.Added,
+		Installed:   installed,
+	}
+
+	// 2. Remove edges only the newer version had.
+	if currentPath != "" && packFileIs(currentPath, packID, currentVersion) {
+		if newData, _, err := ReadPackFile(currentPath); err == nil {
+			oldEdges := make(map[string]bool, len(oldData.Edges))
+			for _, e := range oldData.Edges {
+				oldEdges[edgeKey(e)] = true
+			}
+			for _, e := range newData.Edges {
+				if oldEdges[edgeKey(e)] {
+					continue
+				}
+				if err := s.RemoveEdge(ctx, e.Source, e.Target, e.Kind); err != nil {
+					return nil, fmt.Errorf("removing edge %s -> %s: %w", e.Source, e.Target, err)
+				}
+				result.EdgesRemoved++
+			}
+		}
+	}
+
+	// 3. Delete behaviors only the newer version had.
+	oldIDs := make(map[string]bool, len(oldData.Nodes))
+	for _, bn := range oldData.Nodes {
+		oldIDs[bn.ID] = true
+	}
+	nodes, err := FindByPack(ctx, s, packID)
+	if err != nil {
+		return nil, err
+	}
+	for _, node := range nodes {
+		if oldIDs[node.ID] || node.Kind == store.NodeKindForgotten ||
+			models.ExtractPackageVersion(node.Metadata) != currentVersion {
+			continue
+		}
+		if err := s.DeleteNode(ctx, node.ID); err != nil {
+			return nil, fmt.Errorf("deleting node %s: %w", node.ID, err)
+		}
+		result.Removed = append(result.Removed, node.ID)
+	}
+
+	if err := s.Sync(ctx); err != nil {
+		return nil, fmt.Errorf("syncing after rollback: %w", err)
+	}
+
+	// 4. Install recorded the rolled-back version as history; drop it.
+	for i := range cfg.Packs.Installed {
+		if cfg.Packs.Installed[i].ID == packID {
+			cfg.Packs.Installed[i].History = history
+		}
+	}
+
+	return result, nil
+}
+
+// rollbackFile returns the pack file for a previous version: the file it
+// was installed from if that still holds it, or else a fresh download from
+// its source.
+func rollbackFile(ctx context.Context, packID string, v config.PackVersion, cfg *config.FloopConfig) (string, error) {
+	if v.Path != "" && packFileIs(v.Path, packID, v.Version) {
+		return v.Path, nil
+	}
+	if resolved, err := ResolveSource(v.Source); err == nil && resolved.Kind != SourceLocal {
+		_, paths, err := FetchSource(ctx, v.Source, cfg, true)
+		if err != nil {
+			return "", fmt.Errorf("fetching %s %s: %w", packID, v.Version, err)
+		}
+		for _, path := range paths {
+			if packFileIs(path, packID, v.Version) {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("the pack file for %s %s is no longer available", packID, v.Version)
+}
+
+// packFileIs reports whether the pack file at path is version of packID.
+func packFileIs(path, packID, version string) bool {
+	manifest, err := ReadPackHeader(path)
+	return err == nil && string(manifest.ID) == packID && manifest.Version == version
+}
+
+func edgeKey(e store.Edge) string {
+	return e.Source + "\x00" + e.Target + "\x00" + string(e.Kind)
+}
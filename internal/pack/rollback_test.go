@@ -0,0 +1,138 @@
+package pack
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func rollbackNode(id, canonical string) store.Node {
+	return store.Node{
+		ID:   id,
+		Kind: "behavior",
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": canonical},
+		},
+		Metadata: map[string]interface{}{},
+	}
+}
+
+func TestRollback(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+	cfg := config.Default()
+
+	v1 := writeTestPack(t, t.TempDir(),
+		[]store.Node{rollbackNode("b-1", "Use go test"), rollbackNode("b-2", "Wrap errors")},
+		[]store.Edge{{Source: "b-1", Target: "b-2", Kind: store.EdgeKindSimilarTo, Weight: 0.8}},
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+	v2 := writeTestPack(t, t.TempDir(),
+		[]store.Node{rollbackNode("b-1", "Use go test -race"), rollbackNode("b-2", "Wrap errors"), rollbackNode("b-3", "Prefer table tests")},
+		[]store.Edge{
+			{Source: "b-1", Target: "b-2", Kind: store.EdgeKindSimilarTo, Weight: 0.8},
+			{Source: "b-2", Target: "b-1", Kind: store.EdgeKindSimilarTo, Weight: 0.8},
+			{Source: "b-1", Target: "b-3", Kind: store.EdgeKindSimilarTo, Weight: 0.8},
+		},
+		PackManifest{ID: "test-org/go-pack", Version: "2.0.0"})
+
+	for _, path := range []string{v1, v2} {
+		if _, err := Install(ctx, s, path, cfg, InstallOptions{}); err != nil {
+			t.Fatalf("Install(%s) error = %v", path, err)
+		}
+	}
+	if h := cfg.Packs.Installed[0].History; len(h) != 1 || h[0].Version != "1.0.0" || h[0].Path != v1 {
+		t.Fatalf("History after update = %+v, want 1.0.0 at %s", h, v1)
+	}
+
+	result, err := Rollback(ctx, s, "test-org/go-pack", cfg)
+	if err != nil {
+		t.Fatalf("Rollback() error = %v", err)
+	}
+	if result.FromVersion != "2.0.0" || result.ToVersion != "1.0.0" {
+		t.Errorf("rolled back %s -> %s, want 2.0.0 -> 1.0.0", result.FromVersion, result.ToVersion)
+	}
+	if len(result.Reverted) != 2 || len(result.Removed) != 1 || result.Removed[0] != "b-3" {
+		t.Errorf("Reverted = %v, Removed = %v; want b-1, b-2 reverted and b-3 removed", result.Reverted, result.Removed)
+	}
+	if result.EdgesRemoved != 2 {
+		t.Errorf("EdgesRemoved = %d, want 2", result.EdgesRemoved)
+	}
+
+	node, _ := s.GetNode(ctx, "b-1")
+	if b := models.NodeToBehavior(*node); b.Content.Canonical != "Use go test" || models.ExtractPackageVersion(node.Metadata) != "1.0.0" {
+		t.Errorf("b-1 = %q at %s, want v1 content", b.Content.Canonical, models.ExtractPackageVersion(node.Metadata))
+	}
+	if node, _ := s.GetNode(ctx, "b-3"); node != nil {
+		t.Error("b-3 still in the store")
+	}
+	if edges, _ := s.GetEdges(ctx, "b-2", store.DirectionOutbound, ""); len(edges) != 0 {
+		t.Errorf("b-2 edges = %v, want none", edges)
+	}
+
+	installed := cfg.Packs.Installed[0]
+	if installed.Version != "1.0.0" || len(installed.History) != 0 {
+		t.Errorf("config after rollback = %s with history %+v, want 1.0.0 and none", installed.Version, installed.History)
+	}
+	if _, err := Rollback(ctx, s, "test-org/go-pack", cfg); err == nil || !strings.Contains(err.Error(), "no previous version") {
+		t.Errorf("second Rollback() error = %v, want no previous version", err)
+	}
+}
+
+func TestRollback_FileGone(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+	cfg := config.Default()
+
+	v1 := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("b-1", "Use go test")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "1.0.0"})
+	v2 := writeTestPack(t, t.TempDir(), []store.Node{rollbackNode("b-1", "Use go test -race")}, nil,
+		PackManifest{ID: "test-org/go-pack", Version: "2.0.0"})
+	for _, path := range []string{v1, v2} {
+		if _, err := Install(ctx, s, path, cfg, InstallOptions{Source: path}); err != nil {
+			t.Fatalf("Install(%s) error = %v", path, err)
+		}
+	}
+	os.Remove(v1)
+
+	if _, err := Rollback(ctx, s, "test-org/go-pack", cfg); err == nil || !strings.Contains(err.Error(), "no longer available") {
+		t.Errorf("Rollback() error = %v, want file no longer available", err)
+	}
+	if cfg.Packs.Installed[0].Version != "2.0.0" {
+		t.Errorf("failed rollback changed the installed version to %s", cfg.Packs.Installed[0].Version)
+	}
+	if _, err := Rollback(ctx, s, "test-org/other", cfg); err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("Rollback() of unknown pack error = %v", err)
+	}
+}
+
+func TestRecordInstall_History(t *testing.T) {
+	cfg := config.Default()
+	install := func(version string) {
+		recordInstall(cfg, &PackManifest{ID: "test-org/go-pack", Version: version}, &InstallResult{File: "/cache/" + version + ".fpack"})
+	}
+
+	install("1.0.0")
+	install("1.0.0")
+	if h := cfg.Packs.Installed[0].History; len(h) != 0 {
+		t.Errorf("reinstalling the same version recorded history %+v", h)
+	}
+
+	for i := 1; i <= maxPackHistory+2; i++ {
+		install(fmt.Sprintf("1.%d.0", i))
+	}
+	h := cfg.Packs.Installed[0].History
+	if len(h) != maxPackHistory {
+		t.Fatalf("len(History) = %d, want %d", len(h), maxPackHistory)
+	}
+	if last := h[len(h)-1]; last.Version != fmt.Sprintf("1.%d.0", maxPackHistory+1) || last.Path != "/cache/"+last.Version+".fpack" {
+		t.Errorf("latest history entry = %+v", last)
+	}
+}
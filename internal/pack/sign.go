@@ -0,0 +1,192 @@
+package pack
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+)
+
+// Header metadata keys for embedded pack signatures.
+const (
+	MetaKeySignature = "pack_signature" // base64 ed25519 signature of the header
+	MetaKeySigner    = "pack_signer"    // base64 ed25519 public key of the signer
+)
+
+// SignatureStatus describes a pack's embedded signature.
+type SignatureStatus struct {
+	Signed      bool   `json:"signed"`
+	Trusted     bool   `json:"trusted"`
+	KeyName     string `json:"key_name,omitempty"` // trusted key name from config
+	Fingerprint string `json:"fingerprint,omitempty"`
+}
+
+// SignHeader returns a backup.WriteOptions finalizer that embeds an ed25519
+// signature in a V2 header. The signature covers the whole header,
+// including the payload checksum, so it also covers the behaviors.
+func SignHeader(key ed25519.PrivateKey) func(*backup.BackupHeader) error {
+	return func(header *backup.BackupHeader) error {
+		if header.Metadata == nil {
+			header.Metadata = make(map[string]string)
+		}
+		header.Metadata[MetaKeySigner] = base64.StdEncoding.EncodeToString(key.Public().(ed25519.PublicKey))
+		msg, err := signingMessage(header)
+		if err != nil {
+			return err
+		}
+		header.Metadata[MetaKeySignature] = base64.StdEncoding.EncodeToString(ed25519.Sign(key, msg))
+		return nil
+	}
+}
+
+// VerifySignature checks the signature embedded in a pack header against
+// the trusted keys. An unsigned pack is reported, not rejected; a signature
+// that is malformed or does not verify is an error, since the pack was
+// modified after signing.
+func VerifySignature(header *backup.BackupHeader, trusted []config.TrustedKey) (*SignatureStatus, error) {
+	sigB64, signerB64 := header.Metadata[MetaKeySignature], header.Metadata[MetaKeySigner]
+	if sigB64 == "" && signerB64 == "" {
+		return &SignatureStatus{}, nil
+	}
+	signer, err := base64.StdEncoding.DecodeString(signerB64)
+	if err != nil || len(signer) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("pack signature has an invalid signer key")
+	}
+	sig, err := base64.StdEncoding.DecodeString(sigB64)
+	if err != nil {
+		return nil, fmt.Errorf("pack signature is not valid base64")
+	}
+	msg, err := signingMessage(header)
+	if err != nil {
+		return nil, err
+	}
+	pub := ed25519.PublicKey(signer)
+	if !ed25519.Verify(pub, msg, sig) {
+		return nil, fmt.Errorf("pack signature does not verify; the pack was modified after signing")
+	}
+
+	status := &SignatureStatus{Signed: true, Fingerprint: KeyFingerprint(pub)}
+	for _, tk := range trusted {
+		key, err := ParsePublicKey(tk.PublicKey)
+		if err != nil {
+			return nil, fmt.Errorf("trusted key %q: %w", tk.Name, err)
+		}
+		if key.Equal(pub) {
+			status.Trusted = true
+			status.KeyName = tk.Name
+			break
+		}
+	}
+	return status, nil
+}
+
+// checkSignaturePolicy applies the configured policy to a pack's signature.
+// It returns a warning to show ("is unsigned"), or an error when the pack
+// must not be installed.
+func checkSignaturePolicy(header *backup.BackupHeader, packs config.PacksConfig) (*SignatureStatus, string, error) {
+	policy := packs.EffectiveSignaturePolicy()
+	if policy == config.SignaturePolicyOff {
+		return nil, "", nil
+	}
+	status, err := VerifySignature(header, packs.TrustedKeys)
+	if err != nil {
+		return nil, "", err
+	}
+	if status.Trusted {
+		return status, "", nil
+	}
+
+	problem := "is unsigned"
+	if status.Signed {
+		problem = "is signed by untrusted key " + status.Fingerprint
+	}
+	if policy == config.SignaturePolicyRequire {
+		return nil, "", fmt.Errorf("pack %s and packs.signature_policy is %q; add the signer to packs.trusted_keys", problem, policy)
+	}
+	return status, problem, nil
+}
+
+// signingMessage is the header as JSON with the signature removed.
+func signingMessage(header *backup.BackupHeader) ([]byte, error) {
+	unsigned := *header
+	unsigned.Metadata = make(map[string]string, len(header.Metadata))
+	for k, v := range header.Metadata {
+		if k != MetaKeySignature {
+			unsigned.Metadata[k] = v
+		}
+	}
+	msg, err := json.Marshal(unsigned)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling header for signature: %w", err)
+	}
+	return msg, nil
+}
+
+// KeyFingerprint returns a short, stable identifier for a public key.
+func KeyFingerprint(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "SHA256:" + base64.RawStdEncoding.EncodeToString(sum[:])
+}
+
+// LoadSigningKey reads an ed25519 private key from a file: PEM (PKCS #8,
+// as written by `openssl genpkey -algorithm ed25519`), or a base64 private
+// key (64 bytes) or seed (32 bytes).
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading signing key: %w", err)
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing signing key: %w", err)
+		}
+		edKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("signing key must be ed25519, got %T", key)
+		}
+		return edKey, nil
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil {
+		return nil, fmt.Errorf("signing key must be PEM or base64: %w", err)
+	}
+	switch len(raw) {
+	case ed25519.PrivateKeySize:
+		return ed25519.PrivateKey(raw), nil
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(raw), nil
+	default:
+		return nil, fmt.Errorf("signing key must be an ed25519 private key or seed, got %d bytes", len(raw))
+	}
+}
+
+// ParsePublicKey parses an ed25519 public key given as PEM (PKIX, as
+// written by `openssl pkey -pubout`) or as base64 raw bytes.
+func ParsePublicKey(s string) (ed25519.PublicKey, error) {
+	if block, _ := pem.Decode([]byte(s)); block != nil {
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("parsing public key: %w", err)
+		}
+		edKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("public key must be ed25519, got %T", key)
+		}
+		return edKey, nil
+	}
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key must be PEM or a base64 ed25519 key")
+	}
+	return ed25519.PublicKey(raw), nil
+}
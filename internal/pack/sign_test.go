@@ -0,0 +1,171 @@
+package pack
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/backup"
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// writeSignedPack writes a one-behavior pack signed with key (unsigned if nil).
+func writeSignedPack(t *testing.T, key ed25519.PrivateKey) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "signed.fpack")
+	data := &backup.BackupFormat{
+		Version:   backup.FormatV2,
+		CreatedAt: time.Now(),
+		Nodes:     []backup.BackupNode{{Node: publishableNode("b-signed", "Sign your packs")}},
+	}
+	opts := &backup.WriteOptions{}
+	if key != nil {
+		opts.Finalize = SignHeader(key)
+	}
+	if err := WritePackFile(path, data, PackManifest{ID: "acme/signed", Version: "1.0.0"}, opts); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func pemPublicKey(t *testing.T, pub ed25519.PublicKey) string {
+	t.Helper()
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
+
+func TestVerifySignature(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	trusted := []config.TrustedKey{{Name: "acme", PublicKey: pemPublicKey(t, pub)}}
+
+	header, err := backup.ReadV2Header(writeSignedPack(t, priv))
+	if err != nil {
+		t.Fatal(err)
+	}
+	status, err := VerifySignature(header, trusted)
+	if err != nil || !status.Trusted || status.KeyName != "acme" {
+		t.Fatalf("VerifySignature(trusted) = %+v, %v", status, err)
+	}
+	if status, err := VerifySignature(header, nil); err != nil || !status.Signed || status.Trusted || status.Fingerprint != KeyFingerprint(pub) {
+		t.Errorf("VerifySignature(untrusted) = %+v, %v", status, err)
+	}
+
+	header.Metadata[MetaKeyPackVer] = "9.9.9"
+	if _, err := VerifySignature(header, trusted); err == nil || !strings.Contains(err.Error(), "modified after signing") {
+		t.Errorf("tampered header error = %v", err)
+	}
+
+	unsigned, err := backup.ReadV2Header(writeSignedPack(t, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status, err := VerifySignature(unsigned, trusted); err != nil || status.Signed {
+		t.Errorf("VerifySignature(unsigned) = %+v, %v", status, err)
+	}
+}
+
+func TestVerifySignature_TamperedPayload(t *testing.T) {
+	_, priv, _ := ed25519.GenerateKey(nil)
+	signed := writeSignedPack(t, priv)
+	other := writeSignedPack(t, nil)
+
+	// Graft another pack's payload (with its checksum) under the signed header.
+	signedData, _ := os.ReadFile(signed)
+	otherData, _ := os.ReadFile(other)
+	signedHeader := signedData[:bytes.IndexByte(signedData, '\n')+1]
+	otherHeader, _ := backup.ReadV2Header(other)
+	h, _ := backup.ReadV2Header(signed)
+	forged := strings.Replace(string(signedHeader), h.Checksum, otherHeader.Checksum, 1)
+	path := filepath.Join(t.TempDir(), "forged.fpack")
+	os.WriteFile(path, append([]byte(forged), otherData[bytes.IndexByte(otherData, '\n')+1:]...), 0600)
+
+	header, err := backup.ReadV2Header(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := VerifySignature(header, nil); err == nil {
+		t.Error("a header whose checksum was swapped should not verify")
+	}
+}
+
+func TestInstall_SignaturePolicy(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+	signed := writeSignedPack(t, priv)
+	unsigned := writeSignedPack(t, nil)
+	trusted := []config.TrustedKey{{Name: "acme", PublicKey: base64.StdEncoding.EncodeToString(pub)}}
+
+	tests := []struct {
+		name    string
+		path    string
+		packs   config.PacksConfig
+		wantErr bool
+		trusted bool
+	}{
+		{"trusted signature", signed, config.PacksConfig{TrustedKeys: trusted, SignaturePolicy: config.SignaturePolicyRequire}, false, true},
+		{"unsigned warns by default", unsigned, config.PacksConfig{}, false, false},
+		{"unsigned blocked by require", unsigned, config.PacksConfig{SignaturePolicy: config.SignaturePolicyRequire}, true, false},
+		{"untrusted blocked by require", signed, config.PacksConfig{SignaturePolicy: config.SignaturePolicyRequire}, true, false},
+		{"untrusted warns", signed, config.PacksConfig{}, false, false},
+		{"off skips checks", unsigned, config.PacksConfig{SignaturePolicy: config.SignaturePolicyOff}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Packs = tt.packs
+			result, err := Install(context.Background(), store.NewInMemoryGraphStore(), tt.path, cfg, InstallOptions{})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Install() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if got := result.Signature != nil && result.Signature.Trusted; got != tt.trusted {
+				t.Errorf("trusted = %v, want %v (%+v)", got, tt.trusted, result.Signature)
+			}
+		})
+	}
+}
+
+func TestLoadSigningKey(t *testing.T) {
+	_, priv, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"pem":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"full": base64.StdEncoding.EncodeToString(priv) + "\n",
+		"seed": base64.StdEncoding.EncodeToString(priv.Seed()),
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		key, err := LoadSigningKey(path)
+		if err != nil || !key.Equal(priv) {
+			t.Errorf("LoadSigningKey(%s) = %v", name, err)
+		}
+	}
+
+	bad := filepath.Join(dir, "bad")
+	os.WriteFile(bad, []byte(base64.StdEncoding.EncodeToString([]byte("short"))), 0600)
+	if _, err := LoadSigningKey(bad); err == nil {
+		t.Error("expected error for a key of the wrong size")
+	}
+}
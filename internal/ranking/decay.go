@@ -10,11 +10,17 @@ import (
 // and 0 approaches as time goes to infinity.
 // The halfLife parameter determines how quickly the score decays.
 func ExponentialDecay(t time.Time, halfLife time.Duration) float64 {
+	return ExponentialDecayAt(t, time.Now(), halfLife)
+}
+
+// ExponentialDecayAt is ExponentialDecay measured at now instead of the
+// current time.
+func ExponentialDecayAt(t, now time.Time, halfLife time.Duration) float64 {
 	if t.IsZero() {
 		return 0.0
 	}
 
-	elapsed := time.Since(t)
+	elapsed := now.Sub(t)
 	if elapsed <= 0 {
 		return 1.0
 	}
@@ -14,6 +14,10 @@ import (
 //   - BaseLevelScore (ACT-R: frequency + recency in one principled formula)
 //   - FeedbackScore (quality ratio: confirmed vs overridden)
 //   - PriorityScore (user-assigned priority + kind boost)
+//
+// When PageRank scores are supplied, GraphWeight blends graph centrality
+// into the result, so well-connected core behaviors rank above isolated
+// one-offs.
 type ScorerConfig struct {
 	// Weight for context match specificity (0.0-1.0)
 	ContextWeight float64
@@ -36,16 +40,27 @@ type ScorerConfig struct {
 
 	// KindBoosts are score multipliers for behavior kinds
 	KindBoosts map[models.BehaviorKind]float64
+
+	// Weight for graph centrality (PageRank) (0.0-1.0). The weighted sum of
+	// the other signals is blended as (1-GraphWeight)*sum +
+	// GraphWeight*pageRank. 0 disables the signal.
+	GraphWeight float64
+
+	// PageRank holds per-behavior centrality scores (see ComputePageRank).
+	// Behaviors without a score are not blended. May be nil.
+	PageRank map[string]float64
 }
 
 // DefaultScorerConfig returns the default scoring configuration.
-// Weights: Context 35%, BaseLevel 30%, Feedback 15%, Priority 20%
+// Weights: Context 35%, BaseLevel 30%, Feedback 15%, Priority 20%,
+// blended 85/15 with PageRank when it is supplied.
 func DefaultScorerConfig() ScorerConfig {
 	return ScorerConfig{
 		ContextWeight:     0.35,
 		BaseLevelWeight:   0.30,
 		FeedbackWeight:    0.15,
 		PriorityWeight:    0.20,
+		GraphWeight:       DefaultGraphWeight,
 		ACTR:              DefaultACTRConfig(),
 		FeedbackMinSample: 3,
 		KindBoosts: map[models.BehaviorKind]float64{
@@ -57,6 +72,10 @@ func DefaultScorerConfig() ScorerConfig {
 	}
 }
 
+// DefaultGraphWeight is the default weight of graph centrality in the
+// relevance score.
+const DefaultGraphWeight = 0.15
+
 // RelevanceScorer calculates relevance scores for behaviors
 type RelevanceScorer struct {
 	config ScorerConfig
@@ -75,6 +94,8 @@ func NewRelevanceScorer(config ScorerConfig) *RelevanceScorer {
 		config.PriorityWeight /= totalWeight
 	}
 
+	config.GraphWeight = max(0, min(config.GraphWeight, 1))
+
 	if config.FeedbackMinSample <= 0 {
 		config.FeedbackMinSample = 3
 	}
@@ -96,6 +117,7 @@ type ScoredBehavior struct {
 	BaseLevelScore float64
 	FeedbackScore  float64
 	PriorityScore  float64
+	GraphScore     float64
 	KindBoost      float64
 
 	// Deprecated: kept for backward compatibility with tests that reference old fields.
@@ -131,6 +153,12 @@ func (s *RelevanceScorer) Score(behavior *models.Behavior, ctx *models.ContextSn
 		scored.FeedbackScore*s.config.FeedbackWeight +
 		scored.PriorityScore*s.config.PriorityWeight
 
+	// Blend in graph centrality
+	if pr, ok := s.config.PageRank[behavior.ID]; ok && s.config.GraphWeight > 0 {
+		scored.GraphScore = pr
+		baseScore = (1-s.config.GraphWeight)*baseScore + s.config.GraphWeight*pr
+	}
+
 	// Apply kind boost
 	scored.Score = baseScore * scored.KindBoost
 
@@ -182,7 +210,7 @@ func (s *RelevanceScorer) contextScore(behavior *models.Behavior, ctx *models.Co
 // predicateMatches checks if a predicate key matches the context
 func (s *RelevanceScorer) predicateMatches(key string, ctx *models.ContextSnapshot) bool {
 	switch key {
-	case "file", "file_path":
+	case "file", "file_path", models.PathPrefixKey:
 		return ctx.FilePath != ""
 	case "language":
 		return ctx.FileLanguage != ""
@@ -203,9 +231,14 @@ func (s *RelevanceScorer) predicateMatches(key string, ctx *models.ContextSnapsh
 
 // baseLevelScore computes the ACT-R base-level activation score,
 // combining frequency (TimesActivated) and recency (age since CreatedAt)
-// into a single principled signal.
+// into a single principled signal. Frequency counts distinct sessions when
+// they are tracked, so repeated activations within one session are not
+// boosted over and over.
 func (s *RelevanceScorer) baseLevelScore(behavior *models.Behavior) float64 {
 	n := behavior.Stats.TimesActivated
+	if behavior.Stats.SessionsActivated > 0 {
+		n = behavior.Stats.SessionsActivated
+	}
 	if n <= 0 {
 		// New behavior with no activations — give a fair starting score.
 		// ACT-R would return near-zero for n=0, but new behaviors shouldn't
@@ -147,6 +147,39 @@ func TestRelevanceScorer_Score_BaseLevelScore(t *testing.T) {
 	}
 }
 
+func TestRelevanceScorer_Score_BaseLevelCountsSessions(t *testing.T) {
+	scorer := NewRelevanceScorer(DefaultScorerConfig())
+	now := time.Now()
+
+	// 100 activations in a single session should not outrank a behavior
+	// activated across 5 distinct sessions.
+	oneSession := &models.Behavior{
+		ID:   "one-session",
+		Kind: models.BehaviorKindDirective,
+		Stats: models.BehaviorStats{
+			TimesActivated:    100,
+			SessionsActivated: 1,
+			CreatedAt:         now.Add(-24 * time.Hour),
+		},
+	}
+	manySessions := &models.Behavior{
+		ID:   "many-sessions",
+		Kind: models.BehaviorKindDirective,
+		Stats: models.BehaviorStats{
+			TimesActivated:    5,
+			SessionsActivated: 5,
+			CreatedAt:         now.Add(-24 * time.Hour),
+		},
+	}
+
+	one := scorer.Score(oneSession, nil)
+	many := scorer.Score(manySessions, nil)
+	if one.BaseLevelScore >= many.BaseLevelScore {
+		t.Errorf("single-session base-level (%f) should be < multi-session base-level (%f)",
+			one.BaseLevelScore, many.BaseLevelScore)
+	}
+}
+
 func TestRelevanceScorer_Score_BaseLevelNewBehavior(t *testing.T) {
 	scorer := NewRelevanceScorer(DefaultScorerConfig())
 
@@ -280,6 +313,37 @@ func TestRelevanceScorer_ScoreBatch(t *testing.T) {
 	}
 }
 
+func TestRelevanceScorer_Score_GraphWeight(t *testing.T) {
+	now := time.Now()
+	core := models.Behavior{ID: "core", Kind: models.BehaviorKindDirective, Stats: models.BehaviorStats{CreatedAt: now}}
+	oneOff := models.Behavior{ID: "one-off", Kind: models.BehaviorKindDirective, Stats: models.BehaviorStats{CreatedAt: now}}
+	unranked := models.Behavior{ID: "unranked", Kind: models.BehaviorKindDirective, Stats: models.BehaviorStats{CreatedAt: now}}
+
+	plain := NewRelevanceScorer(DefaultScorerConfig()).Score(&core, nil)
+
+	cfg := DefaultScorerConfig()
+	cfg.PageRank = map[string]float64{"core": 1.0, "one-off": 0.1}
+	scorer := NewRelevanceScorer(cfg)
+
+	coreScore := scorer.Score(&core, nil)
+	oneOffScore := scorer.Score(&oneOff, nil)
+	if coreScore.Score <= oneOffScore.Score {
+		t.Errorf("well-connected behavior should outrank isolated one: %f <= %f", coreScore.Score, oneOffScore.Score)
+	}
+	if coreScore.GraphScore != 1.0 {
+		t.Errorf("GraphScore = %f, want 1.0", coreScore.GraphScore)
+	}
+	if got := scorer.Score(&unranked, nil); got.Score != plain.Score {
+		t.Errorf("behavior without PageRank should score as without the signal: %f != %f", got.Score, plain.Score)
+	}
+
+	cfg.GraphWeight = 0
+	disabled := NewRelevanceScorer(cfg)
+	if got := disabled.Score(&core, nil); got.Score != plain.Score || got.GraphScore != 0 {
+		t.Errorf("GraphWeight 0 should disable the signal, got score %f (graph %f), want %f", got.Score, got.GraphScore, plain.Score)
+	}
+}
+
 func TestFeedbackScore_NoFeedback(t *testing.T) {
 	scorer := NewRelevanceScorer(DefaultScorerConfig())
 	now := time.Now()
@@ -458,4 +522,7 @@ func TestDefaultScorerConfig_Weights(t *testing.T) {
 	if cfg.FeedbackMinSample != 3 {
 		t.Errorf("FeedbackMinSample = %d, want 3", cfg.FeedbackMinSample)
 	}
+	if cfg.GraphWeight != DefaultGraphWeight {
+		t.Errorf("GraphWeight = %f, want %f", cfg.GraphWeight, DefaultGraphWeight)
+	}
 }
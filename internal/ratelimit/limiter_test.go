@@ -180,11 +180,14 @@ func TestNewToolLimiters(t *testing.T) {
 	expectedTools := []string{
 		"floop_learn",
 		"floop_active",
+		"floop_context",
 		"floop_backup",
 		"floop_restore",
 		"floop_connect",
 		"floop_deduplicate",
 		"floop_list",
+		"floop_show",
+		"floop_why",
 		"floop_validate",
 	}
 
@@ -0,0 +1,286 @@
+// Package review tracks behaviors awaiting human review and enforces review
+// service-level agreements (SLAs).
+//
+// The learning loop stamps behaviors that need review with the time review
+// was requested, and with review.hold_pending stores them as pending behaviors
+// that do not activate until approved. This package reports how long each has
+// waited, flags items past the reminder SLA, escalates items ignored past a
+// second threshold, and approves or rejects them.
+package review
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/confidence"
+	"github.com/nvandessel/floop/internal/store"
+	"github.com/nvandessel/floop/internal/utils"
+)
+
+// Metadata keys recorded on behaviors awaiting review.
+const (
+	// MetaRequestedAt is the RFC 3339 time review was requested.
+	MetaRequestedAt = "review_requested_at"
+
+	// MetaReasons lists why review was requested.
+	MetaReasons = "review_reasons"
+
+	// MetaEscalatedAt is the RFC 3339 time the item was escalated.
+	MetaEscalatedAt = "review_escalated_at"
+
+	// MetaApprovedAt is the RFC 3339 time the behavior was approved.
+	MetaApprovedAt = "review_approved_at"
+)
+
+// EscalationAction is what happens to an item ignored past Policy.EscalateAfter.
+type EscalationAction string
+
+const (
+	// EscalateDowngrade halves the behavior's confidence and keeps it pending.
+	EscalateDowngrade EscalationAction = "downgrade"
+
+	// EscalateQuarantine deprecates the behavior so it stops activating.
+	// 'floop restore' undoes this.
+	EscalateQuarantine EscalationAction = "quarantine"
+)
+
+// ValidEscalationActions lists accepted escalation actions.
+var ValidEscalationActions = []EscalationAction{EscalateDowngrade, EscalateQuarantine}
+
+// Policy holds review SLA thresholds. Zero durations disable the
+// corresponding check.
+type Policy struct {
+	// SLA is how long an item may await review before reminders fire.
+	SLA time.Duration
+
+	// EscalateAfter is how long an item may await review before it is escalated.
+	EscalateAfter time.Duration
+
+	// Action is applied by Escalate.
+	Action EscalationAction
+}
+
+// Item is a behavior awaiting review.
+type Item struct {
+	BehaviorID    string        `json:"behavior_id"`
+	Name          string        `json:"name"`
+	Canonical     string        `json:"canonical"`
+	Scope         string        `json:"scope,omitempty"`
+	Held          bool          `json:"held"`
+	Reasons       []string      `json:"reasons"`
+	RequestedAt   time.Time     `json:"requested_at"`
+	Age           time.Duration `json:"-"`
+	AgeHours      float64       `json:"age_hours"`
+	Overdue       bool          `json:"overdue"`
+	EscalationDue bool          `json:"escalation_due"`
+	EscalatedAt   *time.Time    `json:"escalated_at,omitempty"`
+}
+
+// Pending returns behaviors awaiting review, oldest first, with SLA status
+// evaluated at now. Both active behaviors flagged for review and pending
+// behaviors held out of activation are included.
+func Pending(ctx context.Context, s store.GraphStore, policy Policy, now time.Time) ([]Item, error) {
+	var nodes []store.Node
+	for _, kind := range []store.NodeKind{store.NodeKindBehavior, store.NodeKindPending} {
+		found, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(kind)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to query behaviors: %w", err)
+		}
+		nodes = append(nodes, found...)
+	}
+
+	var items []Item
+	for _, node := range nodes {
+		requestedAt, ok := metaTime(node.Metadata, MetaRequestedAt)
+		if !ok {
+			if node.Kind != store.NodeKindPending {
+				continue
+			}
+			// A pending behavior is always awaiting review, even if its
+			// request time was lost.
+			requestedAt = now
+		}
+
+		item := Item{
+			BehaviorID:  node.ID,
+			Name:        utils.GetString(node.Content, "name", node.ID),
+			Scope:       utils.GetString(node.Metadata, "scope", ""),
+			Held:        node.Kind == store.NodeKindPending,
+			Reasons:     utils.GetStringSlice(node.Metadata, MetaReasons),
+			RequestedAt: requestedAt,
+			Age:         now.Sub(requestedAt),
+		}
+		if content := utils.GetMap(node.Content, "content"); content != nil {
+			item.Canonical = utils.GetString(content, "canonical", "")
+		}
+		if item.Reasons == nil {
+			item.Reasons = []string{}
+		}
+		if escalatedAt, ok := metaTime(node.Metadata, MetaEscalatedAt); ok {
+			item.EscalatedAt = &escalatedAt
+		}
+		item.AgeHours = item.Age.Hours()
+		item.Overdue = policy.SLA > 0 && item.Age >= policy.SLA
+		item.EscalationDue = policy.EscalateAfter > 0 && item.Age >= policy.EscalateAfter && item.EscalatedAt == nil
+
+		items = append(items, item)
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].RequestedAt.Before(items[j].RequestedAt) })
+	return items, nil
+}
+
+// Escalate applies the policy's escalation action to a pending behavior and
+// records when it was escalated.
+func Escalate(ctx context.Context, s store.GraphStore, behaviorID string, action EscalationAction, now time.Time) error {
+	node, err := s.GetNode(ctx, behaviorID)
+	if err != nil {
+		return fmt.Errorf("failed to get behavior: %w", err)
+	}
+	if node == nil {
+		return fmt.Errorf("behavior not found: %s", behaviorID)
+	}
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+
+	switch action {
+	case EscalateDowngrade:
+		current := utils.GetFloat64(node.Metadata, "confidence", 0.6)
+		confidence.Adjust(node.Metadata, confidence.Approval, current/2)
+	case EscalateQuarantine:
+		node.Metadata["original_kind"] = node.Kind
+		node.Metadata["deprecated_at"] = now.Format(time.RFC3339)
+		node.Metadata["deprecated_by"] = "floop review"
+		node.Metadata["deprecation_reason"] = "review SLA exceeded"
+		node.Kind = store.NodeKindDeprecated
+	default:
+		return fmt.Errorf("invalid escalation action: %q", action)
+	}
+	node.Metadata[MetaEscalatedAt] = now.Format(time.RFC3339)
+
+	if err := s.UpdateNode(ctx, *node); err != nil {
+		return fmt.Errorf("failed to update behavior: %w", err)
+	}
+	return nil
+}
+
+// Approve accepts a behavior awaiting review: a pending behavior becomes an
+// active behavior, and the review request is cleared. The approved node is
+// returned.
+func Approve(ctx context.Context, s store.GraphStore, behaviorID string, now time.Time) (*store.Node, error) {
+	node, err := getPending(ctx, s, behaviorID)
+	if err != nil {
+		return nil, err
+	}
+
+	node.Kind = store.NodeKindBehavior
+	clearRequest(node.Metadata)
+	node.Metadata[MetaApprovedAt] = now.Format(time.RFC3339)
+
+	if err := s.UpdateNode(ctx, *node); err != nil {
+		return nil, fmt.Errorf("failed to update behavior: %w", err)
+	}
+	return node, nil
+}
+
+// Reject declines a behavior awaiting review by forgetting it, so the
+// learning loop does not recreate it from similar corrections. 'floop
+// restore' undoes this. The rejected node is returned.
+func Reject(ctx context.Context, s store.GraphStore, behaviorID, reason string, now time.Time) (*store.Node, error) {
+	node, err := getPending(ctx, s, behaviorID)
+	if err != nil {
+		return nil, err
+	}
+	if reason == "" {
+		reason = "rejected in review"
+	}
+
+	clearRequest(node.Metadata)
+	node.Metadata["original_kind"] = node.Kind
+	node.Metadata["forgotten_at"] = now.Format(time.RFC3339)
+	node.Metadata["forgotten_by"] = "floop review"
+	node.Metadata["forget_reason"] = reason
+	node.Kind = store.NodeKindForgotten
+
+	if err := s.UpdateNode(ctx, *node); err != nil {
+		return nil, fmt.Errorf("failed to update behavior: %w", err)
+	}
+	return node, nil
+}
+
+// getPending loads a behavior and checks that it is awaiting review.
+func getPending(ctx context.Context, s store.GraphStore, behaviorID string) (*store.Node, error) {
+	node, err := s.GetNode(ctx, behaviorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get behavior: %w", err)
+	}
+	if node == nil {
+		return nil, fmt.Errorf("behavior not found: %s", behaviorID)
+	}
+	if node.Metadata == nil {
+		node.Metadata = make(map[string]interface{})
+	}
+	_, requested := metaTime(node.Metadata, MetaRequestedAt)
+	if node.Kind == store.NodeKindPending || (node.Kind == store.NodeKindBehavior && requested) {
+		return node, nil
+	}
+	return nil, fmt.Errorf("behavior %s is not awaiting review", behaviorID)
+}
+
+// clearRequest removes the review request from behavior metadata.
+func clearRequest(metadata map[string]interface{}) {
+	delete(metadata, MetaRequestedAt)
+	delete(metadata, MetaReasons)
+	delete(metadata, MetaEscalatedAt)
+}
+
+// Reminder is the JSON payload posted to the review webhook.
+type Reminder struct {
+	Event   string    `json:"event"`
+	SentAt  time.Time `json:"sent_at"`
+	Overdue []Item    `json:"overdue"`
+}
+
+// Notify posts a reminder listing overdue items to webhookURL.
+func Notify(ctx context.Context, client *http.Client, webhookURL string, overdue []Item, now time.Time) error {
+	body, err := json.Marshal(Reminder{Event: "review_overdue", SentAt: now, Overdue: overdue})
+	if err != nil {
+		return fmt.Errorf("failed to encode reminder: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// metaTime parses an RFC 3339 timestamp stored in metadata.
+func metaTime(m map[string]interface{}, key string) (time.Time, bool) {
+	s, ok := m[key].(string)
+	if !ok || s == "" {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
@@ -0,0 +1,249 @@
+package review
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/confidence"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func addPending(t *testing.T, s store.GraphStore, id string, requestedAt time.Time) {
+	t.Helper()
+	_, err := s.AddNode(context.Background(), store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"content": map[string]interface{}{"canonical": "never push to main (" + id + ")"},
+		},
+		Metadata: map[string]interface{}{
+			"confidence":    0.8,
+			MetaRequestedAt: requestedAt.Format(time.RFC3339),
+			MetaReasons:     []interface{}{"Constraints require human review"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddNode(%s): %v", id, err)
+	}
+}
+
+func TestPending(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	addPending(t, s, "b-new", now.Add(-2*time.Hour))
+	addPending(t, s, "b-old", now.Add(-10*24*time.Hour))
+	addPending(t, s, "b-mid", now.Add(-8*24*time.Hour))
+	// Behaviors without a review timestamp are not pending.
+	if _, err := s.AddNode(ctx, store.Node{ID: "b-ok", Kind: store.NodeKindBehavior, Content: map[string]interface{}{"name": "b-ok"}}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+
+	policy := Policy{SLA: 7 * 24 * time.Hour, EscalateAfter: 9 * 24 * time.Hour, Action: EscalateDowngrade}
+	items, err := Pending(ctx, s, policy, now)
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(items) != 3 {
+		t.Fatalf("len(items) = %d, want 3", len(items))
+	}
+
+	wantOrder := []string{"b-old", "b-mid", "b-new"}
+	for i, id := range wantOrder {
+		if items[i].BehaviorID != id {
+			t.Errorf("items[%d] = %s, want %s", i, items[i].BehaviorID, id)
+		}
+	}
+
+	old, mid, recent := items[0], items[1], items[2]
+	if !old.Overdue || !old.EscalationDue {
+		t.Errorf("b-old overdue=%v escalationDue=%v, want both true", old.Overdue, old.EscalationDue)
+	}
+	if !mid.Overdue || mid.EscalationDue {
+		t.Errorf("b-mid overdue=%v escalationDue=%v, want true/false", mid.Overdue, mid.EscalationDue)
+	}
+	if recent.Overdue || recent.EscalationDue {
+		t.Errorf("b-new overdue=%v escalationDue=%v, want both false", recent.Overdue, recent.EscalationDue)
+	}
+	if recent.AgeHours != 2 {
+		t.Errorf("b-new AgeHours = %v, want 2", recent.AgeHours)
+	}
+	if len(old.Reasons) != 1 || old.Canonical != "never push to main (b-old)" {
+		t.Errorf("b-old reasons=%v canonical=%q", old.Reasons, old.Canonical)
+	}
+}
+
+func TestEscalate(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	policy := Policy{EscalateAfter: 24 * time.Hour}
+
+	t.Run("downgrade", func(t *testing.T) {
+		s := store.NewInMemoryGraphStore()
+		addPending(t, s, "b-1", now.Add(-48*time.Hour))
+
+		if err := Escalate(ctx, s, "b-1", EscalateDowngrade, now); err != nil {
+			t.Fatalf("Escalate: %v", err)
+		}
+		node, _ := s.GetNode(ctx, "b-1")
+		if node.Kind != store.NodeKindBehavior {
+			t.Errorf("Kind = %s, want behavior", node.Kind)
+		}
+		if got := node.Metadata["confidence"]; got != 0.4 {
+			t.Errorf("confidence = %v, want 0.4", got)
+		}
+		if sources, ok := confidence.FromMetadata(node.Metadata); !ok || sources.Approval != -0.4 {
+			t.Errorf("confidence sources = %+v, want approval -0.4", sources)
+		}
+
+		// Escalated items stay pending but are not escalated again.
+		items, err := Pending(ctx, s, policy, now)
+		if err != nil {
+			t.Fatalf("Pending: %v", err)
+		}
+		if len(items) != 1 || items[0].EscalatedAt == nil || items[0].EscalationDue {
+			t.Errorf("items after escalation = %+v", items)
+		}
+	})
+
+	t.Run("quarantine", func(t *testing.T) {
+		s := store.NewInMemoryGraphStore()
+		addPending(t, s, "b-1", now.Add(-48*time.Hour))
+
+		if err := Escalate(ctx, s, "b-1", EscalateQuarantine, now); err != nil {
+			t.Fatalf("Escalate: %v", err)
+		}
+		node, _ := s.GetNode(ctx, "b-1")
+		if node.Kind != store.NodeKindDeprecated {
+			t.Errorf("Kind = %s, want deprecated", node.Kind)
+		}
+		if node.Metadata["original_kind"] != store.NodeKindBehavior {
+			t.Errorf("original_kind = %v", node.Metadata["original_kind"])
+		}
+
+		items, err := Pending(ctx, s, policy, now)
+		if err != nil {
+			t.Fatalf("Pending: %v", err)
+		}
+		if len(items) != 0 {
+			t.Errorf("quarantined behavior still pending: %+v", items)
+		}
+	})
+
+	t.Run("invalid action", func(t *testing.T) {
+		s := store.NewInMemoryGraphStore()
+		addPending(t, s, "b-1", now)
+		if err := Escalate(ctx, s, "b-1", "delete", now); err == nil {
+			t.Error("expected error for invalid action")
+		}
+	})
+
+	t.Run("missing behavior", func(t *testing.T) {
+		if err := Escalate(ctx, store.NewInMemoryGraphStore(), "b-missing", EscalateDowngrade, now); err == nil {
+			t.Error("expected error for missing behavior")
+		}
+	})
+}
+
+func TestNotify(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	overdue := []Item{{BehaviorID: "b-1", Name: "b-1", Reasons: []string{}, Overdue: true}}
+
+	var got Reminder
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/json" {
+			t.Errorf("Content-Type = %q", ct)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			t.Errorf("decode: %v", err)
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	if err := Notify(context.Background(), srv.Client(), srv.URL, overdue, now); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if got.Event != "review_overdue" || len(got.Overdue) != 1 || got.Overdue[0].BehaviorID != "b-1" {
+		t.Errorf("reminder = %+v", got)
+	}
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	if err := Notify(context.Background(), failing.Client(), failing.URL, overdue, now); err == nil {
+		t.Error("expected error for non-2xx response")
+	}
+}
+
+func TestApproveReject(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	addPending(t, s, "b-flagged", now.Add(-time.Hour))
+	if _, err := s.AddNode(ctx, store.Node{
+		ID:       "b-held",
+		Kind:     store.NodeKindPending,
+		Content:  map[string]interface{}{"name": "b-held"},
+		Metadata: map[string]interface{}{MetaRequestedAt: now.Add(-2 * time.Hour).Format(time.RFC3339)},
+	}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	if _, err := s.AddNode(ctx, store.Node{ID: "b-ok", Kind: store.NodeKindBehavior, Content: map[string]interface{}{"name": "b-ok"}}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+
+	items, err := Pending(ctx, s, Policy{}, now)
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(items) != 2 || items[0].BehaviorID != "b-held" || !items[0].Held || items[1].Held {
+		t.Fatalf("Pending = %+v, want b-held (held) then b-flagged", items)
+	}
+
+	if _, err := Approve(ctx, s, "b-ok", now); err == nil {
+		t.Error("Approve should fail for a behavior not awaiting review")
+	}
+
+	node, err := Approve(ctx, s, "b-held", now)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if node.Kind != store.NodeKindBehavior {
+		t.Errorf("approved Kind = %s, want behavior", node.Kind)
+	}
+	if _, ok := node.Metadata[MetaRequestedAt]; ok {
+		t.Error("approved behavior still has a review request")
+	}
+	if node.Metadata[MetaApprovedAt] != now.Format(time.RFC3339) {
+		t.Errorf("%s = %v", MetaApprovedAt, node.Metadata[MetaApprovedAt])
+	}
+
+	node, err = Reject(ctx, s, "b-flagged", "", now)
+	if err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if node.Kind != store.NodeKindForgotten || node.Metadata["original_kind"] != store.NodeKindBehavior {
+		t.Errorf("rejected Kind = %s, original_kind = %v", node.Kind, node.Metadata["original_kind"])
+	}
+	if node.Metadata["forget_reason"] != "rejected in review" {
+		t.Errorf("forget_reason = %v", node.Metadata["forget_reason"])
+	}
+
+	items, err = Pending(ctx, s, Policy{}, now)
+	if err != nil {
+		t.Fatalf("Pending: %v", err)
+	}
+	if len(items) != 0 {
+		t.Errorf("Pending after approve/reject = %+v, want none", items)
+	}
+}
@@ -0,0 +1,118 @@
+package sandbox
+
+import (
+	"encoding/json"
+	"strings"
+	"text/template"
+)
+
+// FuncMap returns the functions available to sandboxed templates, in
+// addition to the text/template builtins. Every function is a pure
+// transformation of its arguments.
+func FuncMap() template.FuncMap {
+	return funcMap(DefaultMaxOutputBytes)
+}
+
+// funcMap returns FuncMap with the growing helpers (replace, join, indent)
+// refusing to build a string longer than max bytes. The output cap only
+// applies once a result is written, so without this a single call could
+// allocate gigabytes first.
+func funcMap(max int) template.FuncMap {
+	return template.FuncMap{
+		"upper":     strings.ToUpper,
+		"lower":     strings.ToLower,
+		"trim":      strings.TrimSpace,
+		"replace":   func(s, old, new string) (string, error) { return replace(max, s, old, new) },
+		"contains":  strings.Contains,
+		"hasPrefix": strings.HasPrefix,
+		"join":      func(sep string, items interface{}) (string, error) { return join(max, sep, items) },
+		"indent":    func(n int, s string) (string, error) { return indent(max, n, s) },
+		"truncate":  truncate,
+		"default":   defaultValue,
+		"toJSON":    toJSON,
+	}
+}
+
+// replace replaces every old in s with new.
+func replace(max int, s, old, new string) (string, error) {
+	if n := strings.Count(s, old); n > 0 && len(new) > len(old) {
+		if len(new)-len(old) > (max-len(s))/n {
+			return "", ErrOutputTooLarge
+		}
+	}
+	return strings.ReplaceAll(s, old, new), nil
+}
+
+// join joins a string slice, accepting []interface{} from decoded JSON.
+func join(max int, sep string, items interface{}) (string, error) {
+	var parts []string
+	switch v := items.(type) {
+	case []string:
+		parts = v
+	case []interface{}:
+		parts = make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+	}
+	size := 0
+	for i, p := range parts {
+		if i > 0 {
+			size += len(sep)
+		}
+		size += len(p)
+		if size > max {
+			return "", ErrOutputTooLarge
+		}
+	}
+	return strings.Join(parts, sep), nil
+}
+
+// indent prefixes every line of s with n spaces.
+func indent(max, n int, s string) (string, error) {
+	if n <= 0 {
+		return s, nil
+	}
+	lines := strings.Count(s, "\n") + 1
+	if n > (max-len(s))/lines {
+		return "", ErrOutputTooLarge
+	}
+	pad := strings.Repeat(" ", n)
+	return pad + strings.ReplaceAll(s, "\n", "\n"+pad), nil
+}
+
+// truncate shortens s to at most n runes, appending "..." when cut.
+func truncate(n int, s string) string {
+	r := []rune(s)
+	if n < 0 || len(r) <= n {
+		return s
+	}
+	if n <= 3 {
+		return string(r[:n])
+	}
+	return string(r[:n-3]) + "..."
+}
+
+// defaultValue returns def when v is empty.
+func defaultValue(def, v interface{}) interface{} {
+	switch x := v.(type) {
+	case nil:
+		return def
+	case string:
+		if x == "" {
+			return def
+		}
+	}
+	return v
+}
+
+// toJSON renders v as compact JSON.
+func toJSON(v interface{}) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
@@ -0,0 +1,213 @@
+// Package sandbox executes user-supplied Go text templates with bounded
+// capabilities and resources.
+//
+// Templates only see the data they are given and a small function map of
+// pure string helpers; nothing reads files, the environment, or the network.
+// Execution is bounded by a timeout and an output size cap, and parse or
+// execution errors point at the offending template line.
+package sandbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Default resource limits for template execution.
+const (
+	DefaultTimeout        = 2 * time.Second
+	DefaultMaxOutputBytes = 4 << 20 // 4 MiB
+)
+
+// ErrTimeout is returned when template execution exceeds Limits.Timeout.
+var ErrTimeout = errors.New("template execution timed out")
+
+// ErrOutputTooLarge is returned when template output exceeds
+// Limits.MaxOutputBytes.
+var ErrOutputTooLarge = errors.New("template output exceeds size limit")
+
+// Limits bounds the resources a template may use.
+type Limits struct {
+	// Timeout is the maximum execution time. Zero uses DefaultTimeout.
+	Timeout time.Duration
+
+	// MaxOutputBytes is the maximum rendered size. Zero uses
+	// DefaultMaxOutputBytes.
+	MaxOutputBytes int
+}
+
+// DefaultLimits returns the default execution limits.
+func DefaultLimits() Limits {
+	return Limits{Timeout: DefaultTimeout, MaxOutputBytes: DefaultMaxOutputBytes}
+}
+
+func (l Limits) withDefaults() Limits {
+	if l.Timeout <= 0 {
+		l.Timeout = DefaultTimeout
+	}
+	if l.MaxOutputBytes <= 0 {
+		l.MaxOutputBytes = DefaultMaxOutputBytes
+	}
+	return l
+}
+
+// Error is a template parse or execution error located in the source.
+type Error struct {
+	// Template is the template name, usually its file path.
+	Template string
+
+	// Line and Column are 1-based; zero when unknown.
+	Line   int
+	Column int
+
+	// Message describes the problem without location details.
+	Message string
+
+	// Source is the offending source line, if known.
+	Source string
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString(e.Template)
+	if e.Line > 0 {
+		fmt.Fprintf(&b, ":%d", e.Line)
+		if e.Column > 0 {
+			fmt.Fprintf(&b, ":%d", e.Column)
+		}
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Message)
+	if e.Source != "" {
+		fmt.Fprintf(&b, "\n  %d | %s", e.Line, e.Source)
+		if e.Column > 0 {
+			prefix := len(strconv.Itoa(e.Line)) + 5 // "  N | "
+			fmt.Fprintf(&b, "\n%s^", strings.Repeat(" ", prefix+e.Column-1))
+		}
+	}
+	return b.String()
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+// Template is a parsed, sandboxed template.
+type Template struct {
+	name   string
+	lines  []string
+	tmpl   *template.Template
+	limits Limits
+}
+
+// Parse parses text as a sandboxed template. Only the functions in FuncMap
+// and the text/template builtins are available.
+func Parse(name, text string, limits Limits) (*Template, error) {
+	t := &Template{
+		name:   name,
+		lines:  strings.Split(text, "\n"),
+		limits: limits.withDefaults(),
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Funcs(funcMap(t.limits.MaxOutputBytes)).Parse(text)
+	if err != nil {
+		return nil, t.locate(err)
+	}
+	t.tmpl = tmpl
+	return t, nil
+}
+
+// Execute renders the template with data. It returns ErrTimeout or
+// ErrOutputTooLarge (wrapped in *Error when located) if a limit is hit.
+//
+// text/template cannot be interrupted, so a template that loops without
+// producing output keeps running in the background after a timeout until it
+// finishes; the output cap stops loops that do produce output.
+func (t *Template) Execute(ctx context.Context, data interface{}) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.limits.Timeout)
+	defer cancel()
+
+	w := &limitedBuffer{ctx: ctx, max: t.limits.MaxOutputBytes}
+	done := make(chan error, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("template panicked: %v", r)
+			}
+		}()
+		done <- t.tmpl.Execute(w, data)
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return nil, t.locate(err)
+		}
+		return w.buf.Bytes(), nil
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return nil, &Error{Template: t.name, Message: fmt.Sprintf("%v after %s", ErrTimeout, t.limits.Timeout), Err: ErrTimeout}
+		}
+		return nil, ctx.Err()
+	}
+}
+
+// locRe matches the "template: name:line[:col]: msg" prefix used by
+// text/template errors.
+var locRe = regexp.MustCompile(`^template: (?:.*?):(\d+)(?::(\d+))?: (.*)$`)
+
+// locate converts a text/template error into an *Error pointing at the
+// offending line.
+func (t *Template) locate(err error) error {
+	e := &Error{Template: t.name, Message: err.Error(), Err: err}
+
+	switch {
+	case errors.Is(err, ErrOutputTooLarge):
+		e.Message = fmt.Sprintf("%v (%d bytes)", ErrOutputTooLarge, t.limits.MaxOutputBytes)
+		e.Err = ErrOutputTooLarge
+	case errors.Is(err, context.DeadlineExceeded):
+		e.Message = fmt.Sprintf("%v after %s", ErrTimeout, t.limits.Timeout)
+		e.Err = ErrTimeout
+	}
+
+	// Execution errors wrapping a writer error keep the location prefix.
+	msg := err.Error()
+	if m := locRe.FindStringSubmatch(msg); m != nil {
+		e.Line, _ = strconv.Atoi(m[1])
+		if m[2] != "" {
+			// text/template reports 0-based byte columns.
+			col, _ := strconv.Atoi(m[2])
+			e.Column = col + 1
+		}
+		if e.Err != ErrOutputTooLarge && e.Err != ErrTimeout {
+			e.Message = strings.TrimPrefix(m[3], fmt.Sprintf("executing %q at ", t.name))
+		}
+	}
+	if e.Line > 0 && e.Line <= len(t.lines) {
+		e.Source = t.lines[e.Line-1]
+	}
+	return e
+}
+
+// limitedBuffer caps output size and stops execution once ctx is done.
+type limitedBuffer struct {
+	ctx context.Context
+	buf bytes.Buffer
+	max int
+}
+
+func (w *limitedBuffer) Write(p []byte) (int, error) {
+	if err := w.ctx.Err(); err != nil {
+		return 0, err
+	}
+	if w.buf.Len()+len(p) > w.max {
+		return 0, ErrOutputTooLarge
+	}
+	return w.buf.Write(p)
+}
@@ -0,0 +1,134 @@
+package sandbox
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExecute(t *testing.T) {
+	tmpl, err := Parse("ok.tmpl", `{{range .Items}}- {{upper .Name}} [{{join ", " .Tags}}]
+{{end}}{{truncate 8 .Title}}`, Limits{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	data := map[string]interface{}{
+		"Title": "a long title",
+		"Items": []map[string]interface{}{
+			{"Name": "one", "Tags": []string{"a", "b"}},
+			{"Name": "two", "Tags": []interface{}{"c"}},
+		},
+	}
+	got, err := tmpl.Execute(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	want := "- ONE [a, b]\n- TWO [c]\na lon..."
+	if string(got) != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestParse_UnknownFunction(t *testing.T) {
+	_, err := Parse("bad.tmpl", "line one\n{{ readFile \"/etc/passwd\" }}", Limits{})
+	var te *Error
+	if !errors.As(err, &te) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if te.Line != 2 || !strings.Contains(te.Message, `"readFile" not defined`) {
+		t.Errorf("unexpected error: %+v", te)
+	}
+	if !strings.Contains(err.Error(), "bad.tmpl:2: ") || !strings.Contains(err.Error(), `2 | {{ readFile "/etc/passwd" }}`) {
+		t.Errorf("error should point at the line:\n%s", err)
+	}
+}
+
+func TestExecute_ErrorLocation(t *testing.T) {
+	tmpl, err := Parse("exec.tmpl", "ok\n  {{ .Missing.Field }}", Limits{})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	_, err = tmpl.Execute(context.Background(), map[string]interface{}{})
+	var te *Error
+	if !errors.As(err, &te) {
+		t.Fatalf("expected *Error, got %v", err)
+	}
+	if te.Line != 2 || te.Column == 0 || te.Source != "  {{ .Missing.Field }}" {
+		t.Errorf("unexpected location: %+v", te)
+	}
+	if !strings.Contains(err.Error(), "^") {
+		t.Errorf("expected caret in error:\n%s", err)
+	}
+}
+
+func TestExecute_OutputCap(t *testing.T) {
+	tmpl, err := Parse("big.tmpl", `{{range 100000}}xxxxxxxxxx{{end}}`, Limits{MaxOutputBytes: 1024})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	_, err = tmpl.Execute(context.Background(), nil)
+	if !errors.Is(err, ErrOutputTooLarge) {
+		t.Fatalf("expected ErrOutputTooLarge, got %v", err)
+	}
+}
+
+func TestExecute_HelpersRespectOutputCap(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"indent", `{{indent 2000000000 "x"}}`},
+		{"replace", `{{replace "aaaa" "a" (indent 1000 "b")}}`},
+		{"join", `{{join (indent 1000 "-") .items}}`},
+	}
+	data := map[string]interface{}{"items": []interface{}{"a", "b", "c", "d"}}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := Parse(tt.name+".tmpl", tt.text, Limits{MaxOutputBytes: 2048})
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			_, err = tmpl.Execute(context.Background(), data)
+			if !errors.Is(err, ErrOutputTooLarge) {
+				t.Fatalf("expected ErrOutputTooLarge, got %v", err)
+			}
+		})
+	}
+
+	tmpl, err := Parse("ok.tmpl", `{{indent 2 "a\nb"}}|{{replace "ab" "b" "cd"}}|{{join ", " .items}}`, Limits{MaxOutputBytes: 2048})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	out, err := tmpl.Execute(context.Background(), data)
+	if err != nil {
+		t.Fatalf("Execute: %v", err)
+	}
+	if want := "  a\n  b|acd|a, b, c, d"; string(out) != want {
+		t.Errorf("output = %q, want %q", out, want)
+	}
+}
+
+func TestExecute_Timeout(t *testing.T) {
+	tmpl, err := Parse("slow.tmpl", `{{range 100000000}}{{end}}`, Limits{Timeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Parse: %v", err)
+	}
+	start := time.Now()
+	_, err = tmpl.Execute(context.Background(), nil)
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected ErrTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Execute returned after %s, want prompt timeout", elapsed)
+	}
+}
+
+func TestFuncMap_NoSideEffects(t *testing.T) {
+	for _, name := range []string{"readFile", "env", "exec", "include", "getenv"} {
+		if _, ok := FuncMap()[name]; ok {
+			t.Errorf("FuncMap exposes %q", name)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+// Package schema publishes JSON Schemas for the payloads third-party agents
+// exchange with floop: the activation context snapshot and the floop_active
+// and floop_learn request and response bodies. Schemas are generated from
+// the Go types, so they cannot drift from the code.
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/nvandessel/floop/internal/mcp"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// Draft is the JSON Schema dialect of the generated schemas.
+const Draft = "https://json-schema.org/draft/2020-12/schema"
+
+// IDBase prefixes each schema's $id.
+const IDBase = "https://github.com/nvandessel/floop/schemas/"
+
+// Definition describes one published schema.
+type Definition struct {
+	// Name identifies the schema on the command line and in file names.
+	Name string
+
+	// Description is copied into the schema.
+	Description string
+
+	// Type is the Go type the schema is generated from.
+	Type reflect.Type
+}
+
+// Definitions lists the published schemas in a stable order.
+var Definitions = []Definition{
+	{
+		Name:        "context-snapshot",
+		Description: "Activation context: the environment a behavior's 'when' conditions are evaluated against.",
+		Type:        reflect.TypeFor[models.ContextSnapshot](),
+	},
+	{
+		Name:        "active-request",
+		Description: "Request for floop_active: the context to activate behaviors for.",
+		Type:        reflect.TypeFor[mcp.FloopActiveInput](),
+	},
+	{
+		Name:        "active-response",
+		Description: "Response from floop_active: the behaviors active in the given context.",
+		Type:        reflect.TypeFor[mcp.FloopActiveOutput](),
+	},
+	{
+		Name:        "learn-request",
+		Description: "Request for floop_learn: a correction to learn a behavior from.",
+		Type:        reflect.TypeFor[mcp.FloopLearnInput](),
+	},
+	{
+		Name:        "learn-response",
+		Description: "Response from floop_learn: the captured correction and resulting behavior.",
+		Type:        reflect.TypeFor[mcp.FloopLearnOutput](),
+	},
+}
+
+// Names returns the names of the published schemas.
+func Names() []string {
+	names := make([]string, len(Definitions))
+	for i, d := range Definitions {
+		names[i] = d.Name
+	}
+	return names
+}
+
+// Lookup returns the definition called name.
+func Lookup(name string) (Definition, bool) {
+	for _, d := range Definitions {
+		if d.Name == name {
+			return d, true
+		}
+	}
+	return Definition{}, false
+}
+
+// Generate builds the JSON Schema for d.
+func Generate(d Definition) (*jsonschema.Schema, error) {
+	s, err := jsonschema.ForType(d.Type, &jsonschema.ForOptions{
+		TypeSchemas: map[reflect.Type]*jsonschema.Schema{
+			reflect.TypeFor[time.Time](): {Type: "string", Format: "date-time"},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generating %s schema: %w", d.Name, err)
+	}
+	s.Schema = Draft
+	s.ID = IDBase + d.Name + ".schema.json"
+	s.Title = d.Type.Name()
+	s.Description = d.Description
+	return s, nil
+}
+
+// Marshal renders s as indented JSON with a trailing newline, the form
+// written by 'floop schema dump'.
+func Marshal(s *jsonschema.Schema) ([]byte, error) {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
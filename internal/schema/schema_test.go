@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/nvandessel/floop/internal/mcp"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// publishedDir holds the committed schemas, regenerated with
+// 'floop schema dump --dir docs/schemas'.
+var publishedDir = filepath.Join("..", "..", "docs", "schemas")
+
+func resolve(t *testing.T, name string) *jsonschema.Resolved {
+	t.Helper()
+	def, ok := Lookup(name)
+	if !ok {
+		t.Fatalf("Lookup(%q) not found", name)
+	}
+	s, err := Generate(def)
+	if err != nil {
+		t.Fatalf("Generate(%s): %v", name, err)
+	}
+	resolved, err := s.Resolve(nil)
+	if err != nil {
+		t.Fatalf("Resolve(%s): %v", name, err)
+	}
+	return resolved
+}
+
+// toInstance converts v to the generic form a JSON decoder produces.
+func toInstance(t *testing.T, v interface{}) interface{} {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var instance interface{}
+	if err := json.Unmarshal(data, &instance); err != nil {
+		t.Fatal(err)
+	}
+	return instance
+}
+
+func TestPublishedSchemasMatchCode(t *testing.T) {
+	for _, def := range Definitions {
+		t.Run(def.Name, func(t *testing.T) {
+			s, err := Generate(def)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := Marshal(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := os.ReadFile(filepath.Join(publishedDir, def.Name+".schema.json"))
+			if err != nil {
+				t.Fatalf("reading published schema: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("docs/schemas/%s.schema.json is out of date; run 'floop schema dump --dir docs/schemas'", def.Name)
+			}
+		})
+	}
+}
+
+func TestSchemasAcceptGoValues(t *testing.T) {
+	samples := map[string]interface{}{
+		"context-snapshot": models.ContextSnapshot{
+			Timestamp:    time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+			Repo:         "floop",
+			RepoRoot:     "/src/floop",
+			Branch:       "main",
+			ProjectType:  models.ProjectTypeGo,
+			FilePath:     "cmd/floop/main.go",
+			FileLanguage: "go",
+			FileExt:      ".go",
+			Task:         "refactor",
+			User:         "dev",
+			Roles:        []string{"maintainer"},
+			Environment:  "dev",
+			Custom:       map[string]interface{}{"team": "core"},
+		},
+		"active-request": mcp.FloopActiveInput{File: "main.go", Task: "development", Language: "go"},
+		"active-response": mcp.FloopActiveOutput{
+			Context: map[string]interface{}{"file_language": "go"},
+			Active: []mcp.BehaviorSummary{{
+				ID:         "b-1",
+				Name:       "use-slog",
+				Kind:       "directive",
+				Tier:       "full",
+				Content:    map[string]interface{}{"canonical": "Use slog"},
+				Confidence: 0.6,
+				When:       map[string]interface{}{"language": "go"},
+				Tags:       []string{"logging"},
+				Activation: 0.8,
+				Distance:   1,
+				SeedSource: "b-0",
+			}},
+			Count:      1,
+			TokenStats: &mcp.TokenStats{TotalCanonicalTokens: 10, BudgetDefault: 2000, BehaviorCount: 1, FullCount: 1},
+		},
+		"learn-request": mcp.FloopLearnInput{
+			Wrong:     "used fmt.Println",
+			Right:     "use slog",
+			File:      "main.go",
+			Task:      "coding",
+			Language:  "go",
+			AutoMerge: true,
+			Tags:      []string{"logging"},
+		},
+		"learn-response": mcp.FloopLearnOutput{
+			CorrectionID:    "c-1",
+			BehaviorID:      "b-1",
+			Scope:           "local",
+			AutoAccepted:    true,
+			Confidence:      0.7,
+			RequiresReview:  true,
+			ReviewReasons:   []string{"Constraints require human review"},
+			MergedIntoID:    "b-0",
+			MergeSimilarity: 0.9,
+			Message:         "Learned",
+		},
+	}
+
+	for _, name := range Names() {
+		t.Run(name, func(t *testing.T) {
+			sample, ok := samples[name]
+			if !ok {
+				t.Fatalf("no sample value for schema %s", name)
+			}
+			if err := resolve(t, name).Validate(toInstance(t, sample)); err != nil {
+				t.Errorf("schema rejects a valid %s: %v", name, err)
+			}
+		})
+	}
+}
+
+func TestSchemasRejectInvalidPayloads(t *testing.T) {
+	tests := []struct {
+		schema  string
+		payload string
+	}{
+		{"learn-request", `{"wrong": "x"}`},
+		{"learn-request", `{"right": 42}`},
+		{"active-request", `{"file": "main.go", "unknown": true}`},
+		{"context-snapshot", `{"timestamp": "2026-01-02T03:04:05Z", "roles": "admin"}`},
+	}
+	for _, tt := range tests {
+		var instance interface{}
+		if err := json.Unmarshal([]byte(tt.payload), &instance); err != nil {
+			t.Fatal(err)
+		}
+		if err := resolve(t, tt.schema).Validate(instance); err == nil {
+			t.Errorf("%s accepted invalid payload %s", tt.schema, tt.payload)
+		}
+	}
+}
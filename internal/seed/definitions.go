@@ -9,6 +9,15 @@ import (
 // Bump this when seed content changes to trigger updates.
 const SeedVersion = "0.4.0"
 
+// Rollout stability levels. Experimental seeds are only installed when
+// seeds.experimental is enabled, so new core behaviors can be trialed
+// before they become defaults; promote one by marking it stable and
+// bumping SeedVersion.
+const (
+	StabilityStable       = "stable"
+	StabilityExperimental = "experimental"
+)
+
 // coreBehaviors returns the seed behaviors that bootstrap floop's self-teaching.
 func coreBehaviors() []store.Node {
 	return []store.Node{
@@ -30,6 +39,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 		{
@@ -50,6 +63,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 		{
@@ -70,6 +87,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 		{
@@ -90,6 +111,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 		{
@@ -110,6 +135,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 		{
@@ -130,6 +159,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 		{
@@ -150,6 +183,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 		{
@@ -170,6 +207,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 		{
@@ -190,6 +231,10 @@ func coreBehaviors() []store.Node {
 					"package":         "floop/core",
 					"package_version": SeedVersion,
 				},
+				"rollout": map[string]interface{}{
+					"introduced_in": "0.4.0",
+					"stability":     StabilityStable,
+				},
 			},
 		},
 	}
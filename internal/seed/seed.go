@@ -10,40 +10,86 @@ import (
 
 // Seeder handles injecting seed behaviors into a store.
 type Seeder struct {
-	store store.GraphStore
+	store        store.GraphStore
+	seeds        []store.Node
+	experimental bool
 }
 
 // NewSeeder creates a new Seeder for the given store.
+// Only stable seeds are installed unless WithExperimental is set.
 func NewSeeder(s store.GraphStore) *Seeder {
-	return &Seeder{store: s}
+	return &Seeder{store: s, seeds: coreBehaviors()}
+}
+
+// WithExperimental sets whether experimental seeds are installed.
+func (s *Seeder) WithExperimental(include bool) *Seeder {
+	s.experimental = include
+	return s
 }
 
 // SeedResult reports what the seeder did.
 type SeedResult struct {
-	Added   []string // IDs of newly added seeds
-	Updated []string // IDs of seeds updated (version upgrade)
-	Skipped []string // IDs of seeds skipped (up-to-date or forgotten)
-	Total   int      // Total number of seed definitions
+	Added     []string // IDs of newly added seeds
+	Updated   []string // IDs of seeds updated (version upgrade)
+	Skipped   []string // IDs of seeds skipped (up-to-date or forgotten)
+	Held      []string // IDs of experimental seeds not installed
+	Withdrawn []string // IDs of experimental seeds removed after opting out
+	Total     int      // Total number of seed definitions
+}
+
+// Rollout describes a seed's staged rollout metadata.
+type Rollout struct {
+	IntroducedIn string // SeedVersion the seed first shipped in
+	Stability    string // StabilityStable or StabilityExperimental
+}
+
+// RolloutOf returns the rollout metadata of a seed node. Seeds without
+// rollout metadata are stable.
+func RolloutOf(node store.Node) Rollout {
+	r := Rollout{Stability: StabilityStable}
+	rollout, ok := node.Metadata["rollout"].(map[string]interface{})
+	if !ok {
+		return r
+	}
+	if v, ok := rollout["introduced_in"].(string); ok {
+		r.IntroducedIn = v
+	}
+	if v, ok := rollout["stability"].(string); ok && v != "" {
+		r.Stability = v
+	}
+	return r
 }
 
 // SeedGlobalStore ensures all seed behaviors exist in the store.
 // It is idempotent: seeds at the current version are skipped,
 // outdated seeds are updated, and forgotten seeds are respected.
+// Experimental seeds are held back unless WithExperimental is set; if they
+// were installed while opted in, they are withdrawn again.
 func (s *Seeder) SeedGlobalStore(ctx context.Context) (*SeedResult, error) {
-	seeds := coreBehaviors()
-	result := &SeedResult{Total: len(seeds)}
+	result := &SeedResult{Total: len(s.seeds)}
+	var added []store.Node
 
-	for _, seed := range seeds {
+	for _, seed := range s.seeds {
 		existing, err := s.store.GetNode(ctx, seed.ID)
 		if err != nil {
 			return nil, fmt.Errorf("checking seed %s: %w", seed.ID, err)
 		}
 
-		if existing == nil {
-			// New seed — add it
-			if _, err := s.store.AddNode(ctx, seed); err != nil {
-				return nil, fmt.Errorf("adding seed %s: %w", seed.ID, err)
+		if RolloutOf(seed).Stability == StabilityExperimental && !s.experimental {
+			if existing != nil && existing.Kind == store.NodeKindBehavior && RolloutOf(*existing).Stability == StabilityExperimental {
+				if err := s.store.DeleteNode(ctx, seed.ID); err != nil {
+					return nil, fmt.Errorf("withdrawing seed %s: %w", seed.ID, err)
+				}
+				result.Withdrawn = append(result.Withdrawn, seed.ID)
+				continue
 			}
+			result.Held = append(result.Held, seed.ID)
+			continue
+		}
+
+		if existing == nil {
+			// New seed — added with the others below
+			added = append(added, seed)
 			result.Added = append(result.Added, seed.ID)
 			continue
 		}
@@ -69,6 +115,10 @@ func (s *Seeder) SeedGlobalStore(ctx context.Context) (*SeedResult, error) {
 		result.Skipped = append(result.Skipped, seed.ID)
 	}
 
+	if err := store.BatchAdd(ctx, s.store, added, nil); err != nil {
+		return nil, fmt.Errorf("adding seeds: %w", err)
+	}
+
 	if err := s.store.Sync(ctx); err != nil {
 		return nil, fmt.Errorf("syncing after seeding: %w", err)
 	}
@@ -357,3 +357,104 @@ func TestSeedGlobalStore_VersionUpgradeMultiple(t *testing.T) {
 		}
 	}
 }
+
+func TestCoreBehaviors_Rollout(t *testing.T) {
+	for _, node := range coreBehaviors() {
+		r := RolloutOf(node)
+		if r.IntroducedIn == "" {
+			t.Errorf("seed %s missing rollout.introduced_in", node.ID)
+		}
+		if r.Stability != StabilityStable && r.Stability != StabilityExperimental {
+			t.Errorf("seed %s stability = %q", node.ID, r.Stability)
+		}
+	}
+}
+
+// withSeeds replaces the seed definitions for testing.
+func (s *Seeder) withSeeds(seeds []store.Node) *Seeder {
+	s.seeds = seeds
+	return s
+}
+
+// experimentalSeed returns a seed node marked experimental.
+func experimentalSeed() store.Node {
+	return store.Node{
+		ID:   "seed-trial",
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "core/trial",
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "A behavior being trialed."},
+		},
+		Metadata: map[string]interface{}{
+			"provenance": map[string]interface{}{"package": "floop/core", "package_version": SeedVersion},
+			"rollout":    map[string]interface{}{"introduced_in": SeedVersion, "stability": StabilityExperimental},
+		},
+	}
+}
+
+func TestSeedGlobalStore_ExperimentalRollout(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+	seeds := append(coreBehaviors(), experimentalSeed())
+
+	// Default: experimental seeds are held back.
+	result, err := NewSeeder(s).withSeeds(seeds).SeedGlobalStore(ctx)
+	if err != nil {
+		t.Fatalf("SeedGlobalStore() error = %v", err)
+	}
+	if len(result.Held) != 1 || result.Held[0] != "seed-trial" {
+		t.Errorf("Held = %v, want [seed-trial]", result.Held)
+	}
+	if node, _ := s.GetNode(ctx, "seed-trial"); node != nil {
+		t.Fatal("experimental seed installed without opting in")
+	}
+
+	// Opting in installs it.
+	result, err = NewSeeder(s).WithExperimental(true).withSeeds(seeds).SeedGlobalStore(ctx)
+	if err != nil {
+		t.Fatalf("SeedGlobalStore() error = %v", err)
+	}
+	if len(result.Added) != 1 || result.Added[0] != "seed-trial" {
+		t.Errorf("Added = %v, want [seed-trial]", result.Added)
+	}
+
+	// Opting out again withdraws it.
+	result, err = NewSeeder(s).withSeeds(seeds).SeedGlobalStore(ctx)
+	if err != nil {
+		t.Fatalf("SeedGlobalStore() error = %v", err)
+	}
+	if len(result.Withdrawn) != 1 || result.Withdrawn[0] != "seed-trial" {
+		t.Errorf("Withdrawn = %v, want [seed-trial]", result.Withdrawn)
+	}
+	if node, _ := s.GetNode(ctx, "seed-trial"); node != nil {
+		t.Error("experimental seed not withdrawn after opting out")
+	}
+}
+
+func TestSeedGlobalStore_ExperimentalPromoted(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	ctx := context.Background()
+
+	trial := experimentalSeed()
+	trial.Metadata["provenance"] = map[string]interface{}{"package": "floop/core", "package_version": "0.0.1"}
+	if _, err := NewSeeder(s).WithExperimental(true).withSeeds([]store.Node{trial}).SeedGlobalStore(ctx); err != nil {
+		t.Fatalf("SeedGlobalStore() error = %v", err)
+	}
+
+	// A later release marks it stable: opted-out users get it too, and the
+	// opted-in copy is updated rather than withdrawn.
+	promoted := experimentalSeed()
+	promoted.Metadata["rollout"] = map[string]interface{}{"introduced_in": "0.0.1", "stability": StabilityStable}
+	result, err := NewSeeder(s).withSeeds([]store.Node{promoted}).SeedGlobalStore(ctx)
+	if err != nil {
+		t.Fatalf("SeedGlobalStore() error = %v", err)
+	}
+	if len(result.Updated) != 1 {
+		t.Errorf("Updated = %v, want [seed-trial]", result.Updated)
+	}
+	node, _ := s.GetNode(ctx, "seed-trial")
+	if node == nil || RolloutOf(*node).Stability != StabilityStable {
+		t.Errorf("promoted seed = %+v, want stable", node)
+	}
+}
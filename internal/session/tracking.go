@@ -0,0 +1,220 @@
+package session
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// CurrentFile is the file in .floop/ holding the ID of the tracked session
+// started by 'floop session start'.
+const CurrentFile = "session"
+
+// ErrNoSession is returned when no tracked session is active.
+var ErrNoSession = errors.New("no active session; run 'floop session start' first")
+
+// Event is a kind of activity counted against a tracked session.
+type Event string
+
+const (
+	EventCorrection Event = "correction"
+	EventActivation Event = "activation"
+	EventFeedback   Event = "feedback"
+)
+
+// Record is a tracked session as stored in its session node. It groups the
+// corrections, activations, and feedback recorded while the session was open.
+type Record struct {
+	ID          string     `json:"id"`
+	Label       string     `json:"label,omitempty"`
+	StartedAt   time.Time  `json:"started_at"`
+	EndedAt     *time.Time `json:"ended_at,omitempty"`
+	Corrections int        `json:"corrections"`
+	Activations int        `json:"activations"`
+	Feedback    int        `json:"feedback"`
+
+	// CorrectionIDs are the corrections captured during the session.
+	CorrectionIDs []string `json:"correction_ids,omitempty"`
+	// BehaviorIDs are the distinct behaviors activated or given feedback.
+	BehaviorIDs []string `json:"behavior_ids,omitempty"`
+}
+
+// scopedAdder is implemented by stores that can write to a chosen scope.
+type scopedAdder interface {
+	AddNodeToScope(ctx context.Context, node store.Node, scope store.StoreScope) (string, error)
+}
+
+// NewID returns a fresh session ID, e.g. "session-20260102-150405-1a2b3c4d".
+func NewID(now time.Time) (string, error) {
+	var rnd [4]byte
+	if _, err := rand.Read(rnd[:]); err != nil {
+		return "", fmt.Errorf("failed to generate session id: %w", err)
+	}
+	return fmt.Sprintf("session-%s-%x", now.UTC().Format("20060102-150405"), rnd), nil
+}
+
+// Current returns the ID of the tracked session active under root, or "" if
+// none is.
+func Current(root string) string {
+	data, err := os.ReadFile(filepath.Join(root, ".floop", CurrentFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// Start creates a session node and marks it as the active session under
+// root. It fails if a session is already active.
+func Start(ctx context.Context, s store.GraphStore, root, label string, now time.Time) (*Record, error) {
+	if id := Current(root); id != "" {
+		return nil, fmt.Errorf("session %s is already active; run 'floop session end' first", id)
+	}
+	id, err := NewID(now)
+	if err != nil {
+		return nil, err
+	}
+	rec := &Record{ID: id, Label: label, StartedAt: now.UTC()}
+	node, err := rec.node()
+	if err != nil {
+		return nil, err
+	}
+	// Sessions belong to the project, so a multi-scope store keeps them local.
+	if scoped, ok := s.(scopedAdder); ok {
+		_, err = scoped.AddNodeToScope(ctx, node, store.ScopeLocal)
+	} else {
+		_, err = s.AddNode(ctx, node)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create session node: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, ".floop", CurrentFile), []byte(id+"\n"), 0600); err != nil {
+		return nil, fmt.Errorf("failed to record active session: %w", err)
+	}
+	return rec, nil
+}
+
+// End closes the session active under root and clears it.
+func End(ctx context.Context, s store.GraphStore, root string, now time.Time) (*Record, error) {
+	id := Current(root)
+	if id == "" {
+		return nil, ErrNoSession
+	}
+	rec, err := Get(ctx, s, id)
+	if err != nil {
+		return nil, err
+	}
+	ended := now.UTC()
+	rec.EndedAt = &ended
+	if err := put(ctx, s, rec); err != nil {
+		return nil, err
+	}
+	if err := os.Remove(filepath.Join(root, ".floop", CurrentFile)); err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to clear active session: %w", err)
+	}
+	return rec, nil
+}
+
+// Get loads the session with the given ID.
+func Get(ctx context.Context, s store.GraphStore, id string) (*Record, error) {
+	node, err := s.GetNode(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load session: %w", err)
+	}
+	if node == nil || node.Kind != store.NodeKindSession {
+		return nil, fmt.Errorf("session not found: %s", id)
+	}
+	return decode(node.Content)
+}
+
+// Track counts one event against the session and records the IDs involved:
+// correction IDs for EventCorrection, behavior IDs otherwise.
+func Track(ctx context.Context, s store.GraphStore, id string, event Event, refs ...string) error {
+	rec, err := Get(ctx, s, id)
+	if err != nil {
+		return err
+	}
+	switch event {
+	case EventCorrection:
+		rec.Corrections++
+		rec.CorrectionIDs = appendUnique(rec.CorrectionIDs, refs)
+	case EventActivation:
+		rec.Activations++
+		rec.BehaviorIDs = appendUnique(rec.BehaviorIDs, refs)
+	case EventFeedback:
+		rec.Feedback++
+		rec.BehaviorIDs = appendUnique(rec.BehaviorIDs, refs)
+	default:
+		return fmt.Errorf("unknown session event %q", event)
+	}
+	return put(ctx, s, rec)
+}
+
+// put writes rec back to its session node.
+func put(ctx context.Context, s store.GraphStore, rec *Record) error {
+	node, err := rec.node()
+	if err != nil {
+		return err
+	}
+	if err := s.UpdateNode(ctx, node); err != nil {
+		return fmt.Errorf("failed to update session: %w", err)
+	}
+	return nil
+}
+
+// node converts rec to a session node. Everything lives in Content since
+// stores do not persist Metadata for non-behavior nodes.
+func (rec *Record) node() (store.Node, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return store.Node{}, fmt.Errorf("failed to encode session: %w", err)
+	}
+	var content map[string]interface{}
+	if err := json.Unmarshal(data, &content); err != nil {
+		return store.Node{}, fmt.Errorf("failed to encode session: %w", err)
+	}
+	return store.Node{ID: rec.ID, Kind: store.NodeKindSession, Content: content}, nil
+}
+
+// decode reads a Record from session node content. The SQLite store returns
+// non-behavior content nested under content.structured, so that wrapping is
+// peeled off until the session fields are found.
+func decode(content map[string]interface{}) (*Record, error) {
+	for content != nil {
+		if _, ok := content["started_at"]; ok {
+			break
+		}
+		inner, _ := content["content"].(map[string]interface{})
+		content, _ = inner["structured"].(map[string]interface{})
+	}
+	if content == nil {
+		return nil, fmt.Errorf("malformed session node")
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	var rec Record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("failed to decode session: %w", err)
+	}
+	return &rec, nil
+}
+
+// appendUnique appends the non-empty refs not already in ids.
+func appendUnique(ids, refs []string) []string {
+	for _, ref := range refs {
+		if ref != "" && !slices.Contains(ids, ref) {
+			ids = append(ids, ref)
+		}
+	}
+	return ids
+}
@@ -0,0 +1,121 @@
+package session
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestTrackedSessionLifecycle(t *testing.T) {
+	stores := map[string]func(t *testing.T, root string) store.GraphStore{
+		"memory": func(t *testing.T, root string) store.GraphStore {
+			return store.NewInMemoryGraphStore()
+		},
+		"sqlite": func(t *testing.T, root string) store.GraphStore {
+			s, err := store.NewSQLiteGraphStore(root)
+			if err != nil {
+				t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+			}
+			t.Cleanup(func() { s.Close() })
+			return s
+		},
+	}
+
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			ctx := context.Background()
+			root := t.TempDir()
+			if err := os.MkdirAll(filepath.Join(root, ".floop"), 0700); err != nil {
+				t.Fatal(err)
+			}
+			s := open(t, root)
+			now := time.Date(2026, 1, 2, 15, 4, 5, 0, time.UTC)
+
+			if Current(root) != "" {
+				t.Fatal("Current() should be empty before Start")
+			}
+			if _, err := End(ctx, s, root, now); err != ErrNoSession {
+				t.Errorf("End() without session error = %v, want ErrNoSession", err)
+			}
+
+			rec, err := Start(ctx, s, root, "refactor", now)
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			if Current(root) != rec.ID {
+				t.Errorf("Current() = %q, want %q", Current(root), rec.ID)
+			}
+			if _, err := Start(ctx, s, root, "again", now); err == nil {
+				t.Error("Start() with an active session should fail")
+			}
+
+			steps := []struct {
+				event Event
+				refs  []string
+			}{
+				{EventCorrection, []string{"c-1"}},
+				{EventActivation, []string{"b-1", "b-2"}},
+				{EventActivation, []string{"b-1"}},
+				{EventFeedback, []string{"b-3"}},
+			}
+			for _, step := range steps {
+				if err := Track(ctx, s, rec.ID, step.event, step.refs...); err != nil {
+					t.Fatalf("Track(%s) error = %v", step.event, err)
+				}
+			}
+			if err := Track(ctx, s, rec.ID, Event("bogus")); err == nil {
+				t.Error("Track() with unknown event should fail")
+			}
+
+			ended, err := End(ctx, s, root, now.Add(time.Hour))
+			if err != nil {
+				t.Fatalf("End() error = %v", err)
+			}
+			if Current(root) != "" {
+				t.Error("Current() should be empty after End")
+			}
+			if ended.Label != "refactor" || !ended.StartedAt.Equal(now) {
+				t.Errorf("ended = %+v, want label and start preserved", ended)
+			}
+			if ended.EndedAt == nil || !ended.EndedAt.Equal(now.Add(time.Hour)) {
+				t.Errorf("EndedAt = %v, want %v", ended.EndedAt, now.Add(time.Hour))
+			}
+			if ended.Corrections != 1 || ended.Activations != 2 || ended.Feedback != 1 {
+				t.Errorf("counters = %d/%d/%d, want 1/2/1", ended.Corrections, ended.Activations, ended.Feedback)
+			}
+			if !slices.Equal(ended.CorrectionIDs, []string{"c-1"}) {
+				t.Errorf("CorrectionIDs = %v", ended.CorrectionIDs)
+			}
+			if !slices.Equal(ended.BehaviorIDs, []string{"b-1", "b-2", "b-3"}) {
+				t.Errorf("BehaviorIDs = %v", ended.BehaviorIDs)
+			}
+
+			got, err := Get(ctx, s, rec.ID)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			if got.Activations != 2 || got.EndedAt == nil {
+				t.Errorf("Get() = %+v, want persisted end state", got)
+			}
+		})
+	}
+}
+
+func TestGet_NotSession(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	if _, err := s.AddNode(ctx, store.Node{ID: "b-1", Kind: store.NodeKindBehavior}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Get(ctx, s, "b-1"); err == nil {
+		t.Error("Get() on a behavior node should fail")
+	}
+	if _, err := Get(ctx, s, "missing"); err == nil {
+		t.Error("Get() on a missing node should fail")
+	}
+}
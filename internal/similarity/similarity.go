@@ -3,7 +3,6 @@ package similarity
 import (
 	"strings"
 
-	"github.com/nvandessel/floop/internal/constants"
 	"github.com/nvandessel/floop/internal/tagging"
 )
 
@@ -114,14 +113,20 @@ func ComputeContentSimilarity(a, b string) float64 {
 // are treated as missing, and their weight is redistributed proportionally
 // to the remaining signals.
 func WeightedScoreWithTags(whenOverlap, contentSimilarity, tagSimilarity float64) float64 {
+	return WeightedScoreWithWeights(whenOverlap, contentSimilarity, tagSimilarity, DefaultWeights())
+}
+
+// WeightedScoreWithWeights is WeightedScoreWithTags with caller-supplied weights,
+// used when a store has tuned weights (see Tune).
+func WeightedScoreWithWeights(whenOverlap, contentSimilarity, tagSimilarity float64, w Weights) float64 {
 	type signal struct {
 		value  float64
 		weight float64
 	}
 	signals := []signal{
-		{whenOverlap, constants.WhenOverlapWeight},
-		{contentSimilarity, constants.ContentSimilarityWeight},
-		{tagSimilarity, constants.TagSimilarityWeight},
+		{whenOverlap, w.When},
+		{contentSimilarity, w.Content},
+		{tagSimilarity, w.Tags},
 	}
 
 	var totalWeight float64
@@ -1,14 +1,17 @@
 package similarity
 
-// IsMoreSpecific returns true if a has all of b's conditions plus additional ones.
+import "github.com/nvandessel/floop/internal/models"
+
+// IsMoreSpecific returns true if a narrows all of b's conditions.
 // a is more specific than b if:
-//  1. a has more conditions than b
-//  2. a includes all of b's conditions with the same values
+//  1. a includes all of b's conditions, each with the same value or, for
+//     path_prefix, a subdirectory of b's prefix
+//  2. a has more conditions than b, or a narrower path_prefix
 //
 // Empty b means "unscoped" (applies everywhere); a scoped behavior is not
 // a specialization of an unscoped one, so this returns false.
 func IsMoreSpecific(a, b map[string]interface{}) bool {
-	if len(a) <= len(b) {
+	if len(a) < len(b) {
 		return false
 	}
 
@@ -18,15 +21,31 @@ func IsMoreSpecific(a, b map[string]interface{}) bool {
 		return false
 	}
 
+	narrower := len(a) > len(b)
 	for key, valueB := range b {
 		valueA, exists := a[key]
 		if !exists {
 			return false
 		}
-		if !ValuesEqual(valueA, valueB) {
-			return false
+		if ValuesEqual(valueA, valueB) {
+			continue
 		}
+		if key == models.PathPrefixKey && isSubdirectory(valueA, valueB) {
+			narrower = true
+			continue
+		}
+		return false
 	}
 
-	return true
+	return narrower
+}
+
+// isSubdirectory reports whether path prefix a lies strictly below prefix b.
+func isSubdirectory(a, b interface{}) bool {
+	aStr, aIsStr := a.(string)
+	bStr, bIsStr := b.(string)
+	if !aIsStr || !bIsStr {
+		return false
+	}
+	return models.PathWithin(aStr, bStr) && models.PathPrefixDepth(aStr) > models.PathPrefixDepth(bStr)
 }
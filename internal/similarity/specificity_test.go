@@ -51,6 +51,30 @@ func TestIsMoreSpecific(t *testing.T) {
 			b:    map[string]interface{}{"language": "go"},
 			want: false,
 		},
+		{
+			name: "nested path_prefix returns true",
+			a:    map[string]interface{}{"path_prefix": "internal/store"},
+			b:    map[string]interface{}{"path_prefix": "internal"},
+			want: true,
+		},
+		{
+			name: "parent path_prefix returns false",
+			a:    map[string]interface{}{"path_prefix": "internal"},
+			b:    map[string]interface{}{"path_prefix": "internal/store"},
+			want: false,
+		},
+		{
+			name: "sibling path_prefix returns false",
+			a:    map[string]interface{}{"path_prefix": "internal/storage"},
+			b:    map[string]interface{}{"path_prefix": "internal/store"},
+			want: false,
+		},
+		{
+			name: "nested path_prefix with other conditions returns true",
+			a:    map[string]interface{}{"path_prefix": "internal/store", "language": "go"},
+			b:    map[string]interface{}{"path_prefix": "internal", "language": "go"},
+			want: true,
+		},
 		{
 			name: "nil b returns false",
 			a:    map[string]interface{}{"language": "go"},
@@ -0,0 +1,273 @@
+package similarity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/nvandessel/floop/internal/constants"
+)
+
+// Weights holds the relative weights of the signals combined by
+// WeightedScoreWithWeights. Weights are proportional and need not sum to 1.0.
+type Weights struct {
+	When    float64 `json:"when" yaml:"when"`
+	Content float64 `json:"content" yaml:"content"`
+	Tags    float64 `json:"tags" yaml:"tags"`
+}
+
+// DefaultWeights returns the built-in signal weights.
+func DefaultWeights() Weights {
+	return Weights{
+		When:    constants.WhenOverlapWeight,
+		Content: constants.ContentSimilarityWeight,
+		Tags:    constants.TagSimilarityWeight,
+	}
+}
+
+// Thresholds are the score boundaries used by edge derivation and graph placement.
+type Thresholds struct {
+	// SimilarTo is the minimum score for a similar-to relationship.
+	SimilarTo float64 `json:"similar_to" yaml:"similar_to"`
+
+	// Specialize is the minimum score for proposing a specialization.
+	Specialize float64 `json:"specialize" yaml:"specialize"`
+
+	// UpperBound is the score above which behaviors are potential duplicates.
+	UpperBound float64 `json:"upper_bound" yaml:"upper_bound"`
+}
+
+// DefaultThresholds returns the built-in score thresholds.
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		SimilarTo:  constants.SimilarToThreshold,
+		Specialize: constants.SpecializeThreshold,
+		UpperBound: constants.SimilarToUpperBound,
+	}
+}
+
+// NeverDuplicate is the smallest UpperBound no score reaches. Scores are at
+// most 1.0, so a fit whose labels hold no duplicates above the related
+// pairs uses it to keep pairs scoring exactly 1.0 out of the duplicate class.
+var NeverDuplicate = math.Nextafter(1, 2)
+
+// Tuning bundles signal weights and score thresholds for one store.
+type Tuning struct {
+	Weights    Weights    `json:"weights" yaml:"weights"`
+	Thresholds Thresholds `json:"thresholds" yaml:"thresholds"`
+}
+
+// DefaultTuning returns the built-in weights and thresholds.
+func DefaultTuning() Tuning {
+	return Tuning{Weights: DefaultWeights(), Thresholds: DefaultThresholds()}
+}
+
+// Score computes the weighted similarity score for the given signals using
+// this tuning's weights.
+func (t Tuning) Score(whenOverlap, contentSimilarity, tagSimilarity float64) float64 {
+	return WeightedScoreWithWeights(whenOverlap, contentSimilarity, tagSimilarity, t.Weights)
+}
+
+// PairLabel is a user-assigned relationship between two behaviors.
+type PairLabel string
+
+const (
+	LabelDuplicate PairLabel = "duplicate"
+	LabelRelated   PairLabel = "related"
+	LabelUnrelated PairLabel = "unrelated"
+)
+
+// Valid reports whether l is a known label.
+func (l PairLabel) Valid() bool {
+	switch l {
+	case LabelDuplicate, LabelRelated, LabelUnrelated:
+		return true
+	}
+	return false
+}
+
+// LabeledPair holds the raw similarity signals for a behavior pair together
+// with its user-assigned label. Signals use the same -1.0 sentinel for
+// missing values as the Compute* functions.
+type LabeledPair struct {
+	WhenOverlap       float64   `json:"when_overlap"`
+	ContentSimilarity float64   `json:"content_similarity"`
+	TagSimilarity     float64   `json:"tag_similarity"`
+	Label             PairLabel `json:"label"`
+}
+
+// TuneResult reports the fitted tuning and how well it separates the labels.
+type TuneResult struct {
+	Tuning           Tuning  `json:"tuning"`
+	Pairs            int     `json:"pairs"`
+	Accuracy         float64 `json:"accuracy"`
+	BaselineAccuracy float64 `json:"baseline_accuracy"`
+}
+
+// MinTunePairsPerClass is the minimum number of duplicate pairs, and of
+// related or unrelated pairs, that Tune requires. With fewer labels the grid
+// search fits noise: a handful of pairs can zero out a signal's weight and
+// move the thresholds for the whole store.
+const MinTunePairsPerClass = 10
+
+// tuneWeightStep is the grid resolution for the weight search.
+const tuneWeightStep = 0.1
+
+// Tune fits signal weights and thresholds to labeled behavior pairs.
+//
+// For every weight combination on a coarse grid it scores all pairs and picks
+// the two cut points that best separate unrelated < related < duplicate. The
+// combination with the highest classification accuracy wins; ties keep the
+// default weights so that uninformative labels do not perturb behavior.
+// Specialize is placed at the same relative position between SimilarTo and
+// UpperBound as in the defaults.
+func Tune(pairs []LabeledPair) (*TuneResult, error) {
+	duplicates := 0
+	for _, p := range pairs {
+		if !p.Label.Valid() {
+			return nil, fmt.Errorf("invalid label %q", p.Label)
+		}
+		if p.Label == LabelDuplicate {
+			duplicates++
+		}
+	}
+	if others := len(pairs) - duplicates; duplicates < MinTunePairsPerClass || others < MinTunePairsPerClass {
+		return nil, fmt.Errorf("need at least %d duplicate and %d related or unrelated labeled pairs, got %d and %d",
+			MinTunePairsPerClass, MinTunePairsPerClass, duplicates, others)
+	}
+
+	result := &TuneResult{
+		Pairs:            len(pairs),
+		BaselineAccuracy: Accuracy(pairs, DefaultTuning()),
+	}
+
+	defaults := DefaultWeights()
+	bestAcc, lo, hi := fitThresholds(pairs, defaults)
+	best := Tuning{Weights: defaults, Thresholds: Thresholds{SimilarTo: lo, UpperBound: hi}}
+
+	steps := int(math.Round(1 / tuneWeightStep))
+	for wi := 0; wi <= steps; wi++ {
+		for ci := 0; ci <= steps; ci++ {
+			for ti := 0; ti <= steps; ti++ {
+				if wi+ci+ti == 0 {
+					continue
+				}
+				w := Weights{
+					When:    float64(wi) * tuneWeightStep,
+					Content: float64(ci) * tuneWeightStep,
+					Tags:    float64(ti) * tuneWeightStep,
+				}
+				acc, lo, hi := fitThresholds(pairs, w)
+				if acc > bestAcc+1e-9 {
+					bestAcc = acc
+					best = Tuning{Weights: w, Thresholds: Thresholds{SimilarTo: lo, UpperBound: hi}}
+				}
+			}
+		}
+	}
+
+	d := DefaultThresholds()
+	ratio := (d.Specialize - d.SimilarTo) / (d.UpperBound - d.SimilarTo)
+	best.Thresholds.Specialize = best.Thresholds.SimilarTo + ratio*(best.Thresholds.UpperBound-best.Thresholds.SimilarTo)
+
+	result.Tuning = best
+	result.Accuracy = bestAcc
+	return result, nil
+}
+
+// Accuracy returns the fraction of pairs whose label matches the class implied
+// by t: score >= UpperBound is a duplicate, score >= SimilarTo is related, and
+// anything lower is unrelated.
+func Accuracy(pairs []LabeledPair, t Tuning) float64 {
+	if len(pairs) == 0 {
+		return 0
+	}
+	correct := 0
+	for _, p := range pairs {
+		if classify(t.Score(p.WhenOverlap, p.ContentSimilarity, p.TagSimilarity), t.Thresholds) == p.Label {
+			correct++
+		}
+	}
+	return float64(correct) / float64(len(pairs))
+}
+
+// classify maps a score to the label implied by the thresholds.
+func classify(score float64, th Thresholds) PairLabel {
+	switch {
+	case score >= th.UpperBound:
+		return LabelDuplicate
+	case score >= th.SimilarTo:
+		return LabelRelated
+	default:
+		return LabelUnrelated
+	}
+}
+
+// fitThresholds finds the SimilarTo/UpperBound cut points that maximize
+// accuracy for the given weights. Runs in O(n log n) using prefix counts:
+// for cut indexes i <= j over the sorted scores, accuracy is
+// unrelated[0,i) + related[i,j) + duplicate[j,n).
+func fitThresholds(pairs []LabeledPair, w Weights) (acc, lo, hi float64) {
+	type scored struct {
+		score float64
+		label PairLabel
+	}
+	items := make([]scored, len(pairs))
+	for i, p := range pairs {
+		items[i] = scored{WeightedScoreWithWeights(p.WhenOverlap, p.ContentSimilarity, p.TagSimilarity, w), p.Label}
+	}
+	sort.Slice(items, func(a, b int) bool { return items[a].score < items[b].score })
+
+	n := len(items)
+	unrel := make([]int, n+1)
+	rel := make([]int, n+1)
+	dup := make([]int, n+1)
+	for i, it := range items {
+		unrel[i+1], rel[i+1], dup[i+1] = unrel[i], rel[i], dup[i]
+		switch it.label {
+		case LabelUnrelated:
+			unrel[i+1]++
+		case LabelRelated:
+			rel[i+1]++
+		case LabelDuplicate:
+			dup[i+1]++
+		}
+	}
+
+	// cut returns the threshold value that splits items at index k. Past
+	// the last item it is NeverDuplicate, so that pairs scoring exactly 1.0
+	// stay below the cut.
+	cut := func(k int) float64 {
+		switch {
+		case k <= 0:
+			return 0
+		case k >= n:
+			return NeverDuplicate
+		default:
+			return (items[k-1].score + items[k].score) / 2
+		}
+	}
+	// validCut reports whether a threshold can be placed at k without
+	// splitting equal scores.
+	validCut := func(k int) bool {
+		return k <= 0 || k >= n || items[k-1].score < items[k].score
+	}
+
+	bestCorrect := -1
+	bestI, bestJ := 0, n
+	maxI, argMaxI := math.MinInt, 0
+	for j := 0; j <= n; j++ {
+		if validCut(j) && unrel[j]-rel[j] > maxI {
+			maxI, argMaxI = unrel[j]-rel[j], j
+		}
+		if !validCut(j) {
+			continue
+		}
+		correct := maxI + rel[j] + dup[n] - dup[j]
+		if correct > bestCorrect {
+			bestCorrect, bestI, bestJ = correct, argMaxI, j
+		}
+	}
+
+	return float64(bestCorrect) / float64(n), cut(bestI), cut(bestJ)
+}
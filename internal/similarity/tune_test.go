@@ -0,0 +1,145 @@
+package similarity
+
+import (
+	"math"
+	"testing"
+)
+
+func TestWeightedScoreWithWeightsMatchesDefaults(t *testing.T) {
+	got := WeightedScoreWithWeights(0.5, 0.8, 0.2, DefaultWeights())
+	want := WeightedScoreWithTags(0.5, 0.8, 0.2)
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("WeightedScoreWithWeights() = %v, want %v", got, want)
+	}
+}
+
+func TestWeightedScoreWithWeightsZeroWeight(t *testing.T) {
+	// Content-only weights ignore when and tag signals entirely.
+	got := WeightedScoreWithWeights(1.0, 0.3, 1.0, Weights{Content: 1})
+	if math.Abs(got-0.3) > 1e-9 {
+		t.Errorf("WeightedScoreWithWeights() = %v, want 0.3", got)
+	}
+}
+
+// labeled returns a content-only pair with label for each score.
+func labeled(label PairLabel, scores ...float64) []LabeledPair {
+	pairs := make([]LabeledPair, len(scores))
+	for i, score := range scores {
+		pairs[i] = LabeledPair{WhenOverlap: -1, ContentSimilarity: score, TagSimilarity: -1, Label: label}
+	}
+	return pairs
+}
+
+// spread returns n scores starting at from, step apart.
+func spread(n int, from, step float64) []float64 {
+	scores := make([]float64, n)
+	for i := range scores {
+		scores[i] = from + float64(i)*step
+	}
+	return scores
+}
+
+func TestTuneErrors(t *testing.T) {
+	enough := func(label PairLabel) []LabeledPair {
+		return labeled(label, spread(MinTunePairsPerClass, 0.1, 0.01)...)
+	}
+	tests := []struct {
+		name  string
+		pairs []LabeledPair
+	}{
+		{"too few pairs", labeled(LabelDuplicate, 1)},
+		{"single class", append(enough(LabelDuplicate), enough(LabelDuplicate)...)},
+		{"too few duplicates", append(enough(LabelUnrelated), labeled(LabelDuplicate, 0.9, 0.95, 1)...)},
+		{"too few non-duplicates", append(enough(LabelDuplicate), labeled(LabelRelated, 0.5, 0.6)...)},
+		{"invalid label", append(append(enough(LabelDuplicate), enough(LabelRelated)...),
+			LabeledPair{ContentSimilarity: 0.9, Label: "maybe"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Tune(tt.pairs); err == nil {
+				t.Error("expected error")
+			}
+		})
+	}
+}
+
+func TestTuneSeparatesLabels(t *testing.T) {
+	// Content similarity cleanly separates the classes at roughly 0.3 and 0.6,
+	// well below the default thresholds of 0.5 and 0.9.
+	var pairs []LabeledPair
+	pairs = append(pairs, labeled(LabelUnrelated, spread(5, 0.10, 0.02)...)...)
+	pairs = append(pairs, labeled(LabelRelated, spread(5, 0.40, 0.02)...)...)
+	pairs = append(pairs, labeled(LabelDuplicate, spread(10, 0.70, 0.01)...)...)
+
+	result, err := Tune(pairs)
+	if err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+
+	if result.Accuracy != 1.0 {
+		t.Errorf("Accuracy = %v, want 1.0", result.Accuracy)
+	}
+	if result.BaselineAccuracy >= result.Accuracy {
+		t.Errorf("expected tuned accuracy %v to beat baseline %v", result.Accuracy, result.BaselineAccuracy)
+	}
+
+	th := result.Tuning.Thresholds
+	if th.SimilarTo <= 0.2 || th.SimilarTo >= 0.4 {
+		t.Errorf("SimilarTo = %v, want in (0.2, 0.4)", th.SimilarTo)
+	}
+	if th.UpperBound <= 0.5 || th.UpperBound >= 0.7 {
+		t.Errorf("UpperBound = %v, want in (0.5, 0.7)", th.UpperBound)
+	}
+	if th.Specialize <= th.SimilarTo || th.Specialize >= th.UpperBound {
+		t.Errorf("Specialize = %v, want between SimilarTo and UpperBound", th.Specialize)
+	}
+	if got := Accuracy(pairs, result.Tuning); got != result.Accuracy {
+		t.Errorf("Accuracy() = %v, want %v", got, result.Accuracy)
+	}
+}
+
+func TestTuneKeepsDefaultWeightsOnTie(t *testing.T) {
+	// A single signal is present, so every weighting scores identically.
+	var pairs []LabeledPair
+	pairs = append(pairs, labeled(LabelUnrelated, spread(5, 0.1, 0.01)...)...)
+	pairs = append(pairs, labeled(LabelRelated, spread(5, 0.6, 0.01)...)...)
+	pairs = append(pairs, labeled(LabelDuplicate, spread(10, 0.95, 0.005)...)...)
+
+	result, err := Tune(pairs)
+	if err != nil {
+		t.Fatalf("Tune() error = %v", err)
+	}
+	if result.Tuning.Weights != DefaultWeights() {
+		t.Errorf("Weights = %+v, want defaults %+v", result.Tuning.Weights, DefaultWeights())
+	}
+}
+
+func TestFitThresholdsScoreOfOne(t *testing.T) {
+	// The best fit has no duplicates, yet one related pair scores exactly
+	// 1.0: the upper cut must stay above it.
+	pairs := append(labeled(LabelUnrelated, 0.1, 0.2), labeled(LabelRelated, 0.8, 1.0)...)
+
+	acc, lo, hi := fitThresholds(pairs, DefaultWeights())
+	if acc != 1.0 {
+		t.Errorf("accuracy = %v, want 1.0", acc)
+	}
+	if hi <= 1 {
+		t.Errorf("upper cut = %v, want above 1", hi)
+	}
+	tuning := Tuning{Weights: DefaultWeights(), Thresholds: Thresholds{SimilarTo: lo, Specialize: lo, UpperBound: hi}}
+	if got := Accuracy(pairs, tuning); got != 1.0 {
+		t.Errorf("Accuracy() = %v, want 1.0", got)
+	}
+}
+
+func TestPairLabelValid(t *testing.T) {
+	for _, l := range []PairLabel{LabelDuplicate, LabelRelated, LabelUnrelated} {
+		if !l.Valid() {
+			t.Errorf("%q should be valid", l)
+		}
+	}
+	if PairLabel("other").Valid() {
+		t.Error("unknown label should be invalid")
+	}
+}
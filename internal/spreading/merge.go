@@ -0,0 +1,54 @@
+package spreading
+
+import (
+	"context"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// SeedsFromMatches converts direct activation matches to spreading seeds,
+// scaling each seed's activation by how specifically it matched.
+func SeedsFromMatches(matches []activation.ActivationResult) []Seed {
+	seeds := make([]Seed, len(matches))
+	for i, m := range matches {
+		seeds[i] = Seed{
+			BehaviorID: m.Behavior.ID,
+			Activation: MatchScoreToActivation(len(m.Behavior.When), m.MatchScore),
+			Source:     BuildSourceLabel(m.MatchedConditions),
+		}
+	}
+	return seeds
+}
+
+// MergeMatches merges spreading results back into the activation matches.
+// Behaviors already present via direct match are kept as-is; spread-only
+// behaviors are loaded from the store and appended with Specificity 0 so the
+// Resolver ranks them below direct matches. Results that are not active
+// behaviors, or that are expired or snoozed, are skipped.
+func MergeMatches(ctx context.Context, gs store.GraphStore, matches []activation.ActivationResult, spread []Result) []activation.ActivationResult {
+	now := time.Now()
+	seen := make(map[string]bool, len(matches))
+	for _, m := range matches {
+		seen[m.Behavior.ID] = true
+	}
+
+	for _, sr := range spread {
+		if seen[sr.BehaviorID] {
+			continue
+		}
+		node, err := gs.GetNode(ctx, sr.BehaviorID)
+		if err != nil || node == nil || node.Kind != store.NodeKindBehavior || resting(*node, now) {
+			continue
+		}
+		matches = append(matches, activation.ActivationResult{
+			Behavior:    models.NodeToBehavior(*node),
+			Specificity: 0, // spread-only: always lower than direct matches
+		})
+		seen[sr.BehaviorID] = true
+	}
+
+	return matches
+}
@@ -0,0 +1,70 @@
+package spreading
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/activation"
+	"github.com/nvandessel/floop/internal/models"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func TestSeedsFromMatches(t *testing.T) {
+	matches := []activation.ActivationResult{
+		{
+			Behavior:          models.Behavior{ID: "go", When: map[string]interface{}{"language": "go"}},
+			MatchScore:        1.0,
+			MatchedConditions: map[string]interface{}{"language": "go"},
+		},
+		{Behavior: models.Behavior{ID: "always"}},
+	}
+
+	seeds := SeedsFromMatches(matches)
+	if len(seeds) != 2 {
+		t.Fatalf("len(seeds) = %d, want 2", len(seeds))
+	}
+	if seeds[0].BehaviorID != "go" || seeds[0].Source != "context:language=go" {
+		t.Errorf("seeds[0] = %+v", seeds[0])
+	}
+	if seeds[1].Source != "context:always" || seeds[1].Activation >= seeds[0].Activation {
+		t.Errorf("seeds[1] = %+v, want a weaker always-active seed", seeds[1])
+	}
+}
+
+func TestMergeMatches(t *testing.T) {
+	ctx := context.Background()
+	s := store.NewInMemoryGraphStore()
+	addBehaviorNode(t, s, "direct", "direct", map[string]interface{}{"language": "go"})
+	addBehaviorNode(t, s, "related", "related", map[string]interface{}{"task": "review"})
+	if _, err := s.AddNode(ctx, store.Node{ID: "gone", Kind: store.NodeKindForgotten}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	past, future := time.Now().Add(-time.Hour), time.Now().Add(time.Hour)
+	for _, b := range []models.Behavior{
+		{ID: "expired", Name: "expired", Kind: models.BehaviorKindDirective, ExpiresAt: &past},
+		{ID: "snoozed", Name: "snoozed", Kind: models.BehaviorKindDirective, SnoozedUntil: &future},
+	} {
+		if _, err := s.AddNode(ctx, models.BehaviorToNode(&b)); err != nil {
+			t.Fatalf("AddNode: %v", err)
+		}
+	}
+
+	matches := []activation.ActivationResult{{Behavior: models.Behavior{ID: "direct"}, Specificity: 1}}
+	spread := []Result{
+		{BehaviorID: "direct", Activation: 0.9},
+		{BehaviorID: "related", Activation: 0.5, Distance: 1},
+		{BehaviorID: "gone", Activation: 0.4, Distance: 1},
+		{BehaviorID: "missing", Activation: 0.3, Distance: 2},
+		{BehaviorID: "expired", Activation: 0.3, Distance: 1},
+		{BehaviorID: "snoozed", Activation: 0.3, Distance: 1},
+	}
+
+	merged := MergeMatches(ctx, s, matches, spread)
+	if len(merged) != 2 {
+		t.Fatalf("len(merged) = %d, want 2: %+v", len(merged), merged)
+	}
+	if merged[1].Behavior.ID != "related" || merged[1].Specificity != 0 {
+		t.Errorf("merged[1] = %s (specificity %d), want related with specificity 0", merged[1].Behavior.ID, merged[1].Specificity)
+	}
+}
@@ -3,7 +3,10 @@ package spreading
 import (
 	"context"
 	"fmt"
+	"slices"
+	"time"
 
+	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
 )
@@ -28,12 +31,52 @@ func NewPipeline(s store.GraphStore, config Config) *Pipeline {
 // Run performs the full activation pipeline for the given context.
 // Returns activated behaviors sorted by activation level.
 func (p *Pipeline) Run(ctx context.Context, actCtx models.ContextSnapshot) ([]Result, error) {
-	seeds, err := p.selector.SelectSeeds(ctx, actCtx)
+	results, _, err := p.RunWithin(ctx, actCtx, activation.Budget{})
+	return results, err
+}
+
+// RunWithin runs the pipeline like Run, bounded by budget. If seed selection
+// overruns the budget, or leaves none of it for propagation, spreading is
+// skipped and the seeds are returned as results with degraded set.
+func (p *Pipeline) RunWithin(ctx context.Context, actCtx models.ContextSnapshot, budget activation.Budget) (results []Result, degraded bool, err error) {
+	seeds, degraded, err := p.selector.SelectSeedsWithin(ctx, actCtx, budget)
 	if err != nil {
-		return nil, fmt.Errorf("seed selection: %w", err)
+		return nil, false, fmt.Errorf("seed selection: %w", err)
 	}
 	if len(seeds) == 0 {
-		return nil, nil
+		return nil, degraded, nil
+	}
+	if degraded || budget.Expired() {
+		results = make([]Result, len(seeds))
+		for i, seed := range seeds {
+			results[i] = Result{BehaviorID: seed.BehaviorID, Activation: seed.Activation, SeedSource: seed.Source}
+		}
+		return results, true, nil
+	}
+	results, err = p.engine.Activate(ctx, seeds)
+	if err != nil {
+		return nil, false, err
 	}
-	return p.engine.Activate(ctx, seeds)
+	now := actCtx.Timestamp
+	if now.IsZero() {
+		now = time.Now()
+	}
+	return p.dropResting(ctx, results, now), false, nil
+}
+
+// dropResting removes results for behaviors that are expired or snoozed at
+// now. Seeds were already evaluated, but activation reaches neighbors
+// through edges without checking them.
+func (p *Pipeline) dropResting(ctx context.Context, results []Result, now time.Time) []Result {
+	return slices.DeleteFunc(results, func(r Result) bool {
+		node, err := p.store.GetNode(ctx, r.BehaviorID)
+		return err == nil && node != nil && resting(*node, now)
+	})
+}
+
+// resting reports whether node is a behavior that must not activate at now
+// because it has expired or is snoozed.
+func resting(node store.Node, now time.Time) bool {
+	b := models.NodeToBehavior(node)
+	return b.Expired(now) || b.Snoozed(now)
 }
@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/activation"
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/store"
 )
@@ -140,3 +141,33 @@ func TestPipeline_EmptyStore(t *testing.T) {
 		t.Errorf("expected nil results for empty store, got %d results", len(results))
 	}
 }
+
+func TestPipeline_RunWithinExpiredBudget(t *testing.T) {
+	s := store.NewInMemoryGraphStore()
+	now := time.Now()
+	addBehaviorNode(t, s, "go-directive", "go-directive", map[string]interface{}{"language": "go"})
+	addBehaviorNode(t, s, "neighbor", "neighbor", map[string]interface{}{"language": "python"})
+	addEdge(t, s, "go-directive", "neighbor", store.EdgeKindSimilarTo, 0.8, timePtr(now))
+
+	pipeline := NewPipeline(s, DefaultConfig())
+	actCtx := models.ContextSnapshot{FileLanguage: "go"}
+
+	results, degraded, err := pipeline.RunWithin(context.Background(), actCtx, activation.Budget{Deadline: now.Add(-time.Second), TopN: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !degraded {
+		t.Fatal("expired budget not reported as degraded")
+	}
+	if len(results) != 1 {
+		t.Fatalf("results = %+v, want 1 (shed to TopN, no spreading)", results)
+	}
+
+	results, degraded, err = pipeline.RunWithin(context.Background(), actCtx, activation.Budget{Deadline: now.Add(time.Hour)})
+	if err != nil || degraded {
+		t.Fatalf("RunWithin = degraded %v, err %v; want neither", degraded, err)
+	}
+	if findResult(results, "neighbor") == nil {
+		t.Error("spreading skipped within budget")
+	}
+}
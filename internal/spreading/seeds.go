@@ -40,14 +40,22 @@ func NewSeedSelector(s store.GraphStore) *SeedSelector {
 //
 // Returns seeds sorted by activation descending.
 func (s *SeedSelector) SelectSeeds(ctx context.Context, actCtx models.ContextSnapshot) ([]Seed, error) {
+	seeds, _, err := s.SelectSeedsWithin(ctx, actCtx, activation.Budget{})
+	return seeds, err
+}
+
+// SelectSeedsWithin selects seeds like SelectSeeds, but evaluation is
+// bounded by budget (see activation.Evaluator.EvaluateWithinBudget).
+// degraded reports whether evaluation overran and shed load.
+func (s *SeedSelector) SelectSeedsWithin(ctx context.Context, actCtx models.ContextSnapshot, budget activation.Budget) (seeds []Seed, degraded bool, err error) {
 	// Step 1: Query all behaviors from the store.
 	nodes, err := s.store.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
 	if err != nil {
-		return nil, fmt.Errorf("querying behavior nodes: %w", err)
+		return nil, false, fmt.Errorf("querying behavior nodes: %w", err)
 	}
 
 	if len(nodes) == 0 {
-		return []Seed{}, nil
+		return []Seed{}, false, nil
 	}
 
 	// Step 2: Convert nodes to Behavior models.
@@ -57,15 +65,15 @@ func (s *SeedSelector) SelectSeeds(ctx context.Context, actCtx models.ContextSna
 	}
 
 	// Step 3: Evaluate which behaviors match the context.
-	matches := s.evaluator.Evaluate(actCtx, behaviors)
+	matches, _, degraded := s.evaluator.EvaluateWithinBudget(actCtx, behaviors, 0, budget)
 	if len(matches) == 0 {
-		return []Seed{}, nil
+		return []Seed{}, degraded, nil
 	}
 
 	// Step 4: Convert ActivationResult to Seed.
 	// Use MatchScoreToActivation to factor in partial matching:
 	// fully confirmed conditions get high activation, absent conditions get floor.
-	seeds := make([]Seed, 0, len(matches))
+	seeds = make([]Seed, 0, len(matches))
 	for _, match := range matches {
 		seeds = append(seeds, Seed{
 			BehaviorID: match.Behavior.ID,
@@ -79,7 +87,7 @@ func (s *SeedSelector) SelectSeeds(ctx context.Context, actCtx models.ContextSna
 		return seeds[i].Activation > seeds[j].Activation
 	})
 
-	return seeds, nil
+	return seeds, degraded, nil
 }
 
 // SpecificityToActivation maps specificity (number of matched conditions)
@@ -0,0 +1,55 @@
+package store
+
+import (
+	"context"
+	"fmt"
+)
+
+// BatchWriter is implemented by stores that can add many nodes and edges
+// in one write, which is much faster than adding them one at a time.
+type BatchWriter interface {
+	// BatchAdd adds nodes, then edges, with the semantics of AddNode and
+	// AddEdge. Either all of them are written or, on error, none are.
+	BatchAdd(ctx context.Context, nodes []Node, edges []Edge) error
+}
+
+// Compile-time interface checks.
+var (
+	_ BatchWriter = (*SQLiteGraphStore)(nil)
+	_ BatchWriter = (*MultiGraphStore)(nil)
+)
+
+// BatchAdd adds nodes, then edges, to gs in one batch when gs is a
+// BatchWriter. Other stores get them one at a time, stopping at the first
+// error, so a failed batch may be partly written there.
+func BatchAdd(ctx context.Context, gs GraphStore, nodes []Node, edges []Edge) error {
+	if len(nodes) == 0 && len(edges) == 0 {
+		return nil
+	}
+	if bw, ok := gs.(BatchWriter); ok {
+		return bw.BatchAdd(ctx, nodes, edges)
+	}
+	for _, node := range nodes {
+		if _, err := gs.AddNode(ctx, node); err != nil {
+			return fmt.Errorf("adding node %s: %w", node.ID, err)
+		}
+	}
+	for _, edge := range edges {
+		if err := gs.AddEdge(ctx, edge); err != nil {
+			return fmt.Errorf("adding edge %s -> %s: %w", edge.Source, edge.Target, err)
+		}
+	}
+	return nil
+}
+
+// ValidateEdge checks the invariants AddEdge enforces: a weight in
+// (0.0, 1.0] and a non-zero CreatedAt.
+func ValidateEdge(edge Edge) error {
+	if edge.Weight <= 0 || edge.Weight > 1.0 {
+		return fmt.Errorf("edge weight must be in (0.0, 1.0], got %f", edge.Weight)
+	}
+	if edge.CreatedAt.IsZero() {
+		return fmt.Errorf("edge CreatedAt must be set")
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSQLiteGraphStore_BatchAdd(t *testing.T) {
+	s, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	now := time.Now()
+	edges := []Edge{{Source: "b1", Target: "b2", Kind: EdgeKindRequires, Weight: 1, CreatedAt: now}}
+	if err := s.BatchAdd(ctx, []Node{pageTestNode("b1"), pageTestNode("b2")}, edges); err != nil {
+		t.Fatalf("BatchAdd() error = %v", err)
+	}
+	for _, id := range []string{"b1", "b2"} {
+		if node, err := s.GetNode(ctx, id); err != nil || node == nil {
+			t.Errorf("GetNode(%s) = %v, %v; want the added node", id, node, err)
+		}
+	}
+	if got, _ := s.GetEdges(ctx, "b1", DirectionOutbound, EdgeKindRequires); len(got) != 1 {
+		t.Errorf("edges from b1 = %d, want 1", len(got))
+	}
+	if history, _ := s.BehaviorHistory(ctx, "b1"); len(history) != 1 {
+		t.Errorf("history of b1 = %d revisions, want 1", len(history))
+	}
+
+	// A failing batch writes nothing
+	dup := pageTestNode("b3")
+	dup.Content["content"] = map[string]interface{}{"canonical": "behavior b1"}
+	err := s.BatchAdd(ctx, []Node{pageTestNode("b4"), dup}, nil)
+	if !errors.Is(err, ErrDuplicateContent) {
+		t.Errorf("BatchAdd(duplicate) error = %v, want ErrDuplicateContent", err)
+	}
+	if node, _ := s.GetNode(ctx, "b4"); node != nil {
+		t.Error("failed batch left b4 behind")
+	}
+
+	if err := s.BatchAdd(ctx, []Node{pageTestNode("b5")}, []Edge{{Source: "b5", Target: "b1", Kind: EdgeKindRequires, Weight: 2, CreatedAt: now}}); err == nil {
+		t.Error("BatchAdd(invalid edge) succeeded, want error")
+	}
+	if node, _ := s.GetNode(ctx, "b5"); node != nil {
+		t.Error("batch with an invalid edge left b5 behind")
+	}
+}
+
+func TestMultiGraphStore_BatchAdd(t *testing.T) {
+	m := newTestMultiStoreInMemory(t)
+	ctx := context.Background()
+	mustAddNode(t, m.localStore, ctx, pageTestNode("l1"))
+	mustAddNode(t, m.localStore, ctx, pageTestNode("l2"))
+
+	now := time.Now()
+	edges := []Edge{
+		{Source: "l1", Target: "l2", Kind: EdgeKindRequires, Weight: 1, CreatedAt: now},
+		{Source: "g1", Target: "l1", Kind: EdgeKindSimilarTo, Weight: 0.5, CreatedAt: now},
+	}
+	if err := m.BatchAdd(ctx, []Node{pageTestNode("g1")}, edges); err != nil {
+		t.Fatalf("BatchAdd() error = %v", err)
+	}
+
+	if node, _ := m.globalStore.GetNode(ctx, "g1"); node == nil || node.Metadata["scope"] != "global" {
+		t.Errorf("g1 in global store = %v, want it with global scope", node)
+	}
+	if got, _ := m.localStore.GetEdges(ctx, "l1", DirectionOutbound, ""); len(got) != 1 {
+		t.Errorf("local edges from l1 = %d, want 1", len(got))
+	}
+	if got, _ := m.globalStore.GetEdges(ctx, "g1", DirectionOutbound, ""); len(got) != 1 {
+		t.Errorf("global edges from g1 = %d, want 1 (cross-store)", len(got))
+	}
+
+	missing := []Edge{{Source: "g1", Target: "nowhere", Kind: EdgeKindRequires, Weight: 1, CreatedAt: now}}
+	if err := m.BatchAdd(ctx, nil, missing); err == nil {
+		t.Error("BatchAdd(edge to missing node) succeeded, want error")
+	}
+}
@@ -0,0 +1,529 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EnvChaos enables fault injection in stores opened by NewMultiGraphStore.
+// The value is a chaos spec accepted by ParseChaosSpec. Intended for
+// resilience testing only.
+const EnvChaos = "FLOOP_CHAOS"
+
+// ErrInjectedFault is returned (wrapped) by every fault a ChaosGraphStore injects.
+var ErrInjectedFault = errors.New("chaos: injected fault")
+
+// ChaosConfig controls which faults a ChaosGraphStore injects.
+type ChaosConfig struct {
+	// WriteFailureRate is the probability (0-1) that a mutating call fails
+	// before it reaches the underlying store.
+	WriteFailureRate float64
+
+	// SyncFailureRate is the probability that Sync fails without flushing.
+	SyncFailureRate float64
+
+	// PartialSyncRate is the probability that Sync flushes to disk but then
+	// reports failure, leaving the caller unsure whether its changes persisted.
+	PartialSyncRate float64
+
+	// Latency is added before every read.
+	Latency time.Duration
+
+	// Seed makes fault sequences reproducible. Zero uses the current time.
+	Seed int64
+}
+
+// Enabled reports whether any fault is configured.
+func (c ChaosConfig) Enabled() bool {
+	return c.WriteFailureRate > 0 || c.SyncFailureRate > 0 || c.PartialSyncRate > 0 || c.Latency > 0
+}
+
+// ParseChaosSpec parses a comma-separated chaos spec such as
+// "write=0.2,sync=0.1,partial=0.1,latency=50ms,seed=42".
+// An empty spec yields a disabled config.
+func ParseChaosSpec(spec string) (ChaosConfig, error) {
+	var cfg ChaosConfig
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return ChaosConfig{}, fmt.Errorf("invalid chaos setting %q: expected key=value", part)
+		}
+
+		var err error
+		switch strings.TrimSpace(key) {
+		case "write":
+			cfg.WriteFailureRate, err = parseChaosRate(value)
+		case "sync":
+			cfg.SyncFailureRate, err = parseChaosRate(value)
+		case "partial":
+			cfg.PartialSyncRate, err = parseChaosRate(value)
+		case "latency":
+			cfg.Latency, err = time.ParseDuration(strings.TrimSpace(value))
+			if err == nil && cfg.Latency < 0 {
+				err = fmt.Errorf("must be non-negative")
+			}
+		case "seed":
+			cfg.Seed, err = strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		default:
+			return ChaosConfig{}, fmt.Errorf("unknown chaos setting %q (valid: write, sync, partial, latency, seed)", key)
+		}
+		if err != nil {
+			return ChaosConfig{}, fmt.Errorf("invalid chaos setting %q: %w", part, err)
+		}
+	}
+	return cfg, nil
+}
+
+func parseChaosRate(s string) (float64, error) {
+	rate, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, err
+	}
+	if rate < 0 || rate > 1 {
+		return 0, fmt.Errorf("rate must be between 0 and 1")
+	}
+	return rate, nil
+}
+
+// ChaosConfigFromEnv parses FLOOP_CHAOS. An unset variable yields a disabled config.
+func ChaosConfigFromEnv() (ChaosConfig, error) {
+	return ParseChaosSpec(os.Getenv(EnvChaos))
+}
+
+// ChaosGraphStore wraps a GraphStore and injects faults according to a
+// ChaosConfig. Extended, embedding, and co-activation operations are
+// delegated when the wrapped store supports them.
+// Thread-safe if the wrapped store is.
+type ChaosGraphStore struct {
+	inner GraphStore
+	cfg   ChaosConfig
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// NewChaosGraphStore wraps inner with fault injection.
+func NewChaosGraphStore(inner GraphStore, cfg ChaosConfig) *ChaosGraphStore {
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &ChaosGraphStore{
+		inner: inner,
+		cfg:   cfg,
+		rng:   rand.New(rand.NewSource(seed)),
+	}
+}
+
+// Unwrap returns the wrapped store.
+func (c *ChaosGraphStore) Unwrap() GraphStore {
+	return c.inner
+}
+
+// roll reports whether an event with the given probability fires.
+func (c *ChaosGraphStore) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.rng.Float64() < rate
+}
+
+// beforeWrite returns an injected fault for op with probability WriteFailureRate.
+func (c *ChaosGraphStore) beforeWrite(op string) error {
+	if c.roll(c.cfg.WriteFailureRate) {
+		return fmt.Errorf("%s: %w", op, ErrInjectedFault)
+	}
+	return nil
+}
+
+// beforeRead sleeps for the configured latency or until ctx is done.
+func (c *ChaosGraphStore) beforeRead(ctx context.Context) error {
+	if c.cfg.Latency <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(c.cfg.Latency)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// AddNode adds a node unless a write fault is injected.
+func (c *ChaosGraphStore) AddNode(ctx context.Context, node Node) (string, error) {
+	if err := c.beforeWrite("AddNode"); err != nil {
+		return "", err
+	}
+	return c.inner.AddNode(ctx, node)
+}
+
+// UpdateNode updates a node unless a write fault is injected.
+func (c *ChaosGraphStore) UpdateNode(ctx context.Context, node Node) error {
+	if err := c.beforeWrite("UpdateNode"); err != nil {
+		return err
+	}
+	return c.inner.UpdateNode(ctx, node)
+}
+
+// GetNode retrieves a node after the configured latency.
+func (c *ChaosGraphStore) GetNode(ctx context.Context, id string) (*Node, error) {
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.GetNode(ctx, id)
+}
+
+// DeleteNode deletes a node unless a write fault is injected.
+func (c *ChaosGraphStore) DeleteNode(ctx context.Context, id string) error {
+	if err := c.beforeWrite("DeleteNode"); err != nil {
+		return err
+	}
+	return c.inner.DeleteNode(ctx, id)
+}
+
+// QueryNodes queries nodes after the configured latency.
+func (c *ChaosGraphStore) QueryNodes(ctx context.Context, predicate map[string]interface{}) ([]Node, error) {
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.QueryNodes(ctx, predicate)
+}
+
+// AddEdge adds an edge unless a write fault is injected.
+func (c *ChaosGraphStore) AddEdge(ctx context.Context, edge Edge) error {
+	if err := c.beforeWrite("AddEdge"); err != nil {
+		return err
+	}
+	return c.inner.AddEdge(ctx, edge)
+}
+
+// RemoveEdge removes an edge unless a write fault is injected.
+func (c *ChaosGraphStore) RemoveEdge(ctx context.Context, source, target string, kind EdgeKind) error {
+	if err := c.beforeWrite("RemoveEdge"); err != nil {
+		return err
+	}
+	return c.inner.RemoveEdge(ctx, source, target, kind)
+}
+
+// GetEdges retrieves edges after the configured latency.
+func (c *ChaosGraphStore) GetEdges(ctx context.Context, nodeID string, direction Direction, kind EdgeKind) ([]Edge, error) {
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.GetEdges(ctx, nodeID, direction, kind)
+}
+
+// Traverse walks the graph after the configured latency.
+func (c *ChaosGraphStore) Traverse(ctx context.Context, start string, edgeKinds []EdgeKind, direction Direction, maxDepth int) ([]Node, error) {
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return c.inner.Traverse(ctx, start, edgeKinds, direction, maxDepth)
+}
+
+// Sync flushes the wrapped store. It may fail before flushing (SyncFailureRate)
+// or after flushing (PartialSyncRate).
+func (c *ChaosGraphStore) Sync(ctx context.Context) error {
+	if c.roll(c.cfg.SyncFailureRate) {
+		return fmt.Errorf("Sync: %w", ErrInjectedFault)
+	}
+	if err := c.inner.Sync(ctx); err != nil {
+		return err
+	}
+	if c.roll(c.cfg.PartialSyncRate) {
+		return fmt.Errorf("Sync (partial): %w", ErrInjectedFault)
+	}
+	return nil
+}
+
+// Close closes the wrapped store. Faults are never injected on close so that
+// resources are always released.
+func (c *ChaosGraphStore) Close() error {
+	return c.inner.Close()
+}
+
+// extended returns the wrapped store as an ExtendedGraphStore.
+func (c *ChaosGraphStore) extended(op string) (ExtendedGraphStore, error) {
+	es, ok := c.inner.(ExtendedGraphStore)
+	if !ok {
+		return nil, fmt.Errorf("%s: wrapped store does not support extended operations", op)
+	}
+	return es, nil
+}
+
+// UpdateConfidence delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) UpdateConfidence(ctx context.Context, behaviorID string, newConfidence float64) error {
+	es, err := c.extended("UpdateConfidence")
+	if err != nil {
+		return err
+	}
+	if err := c.beforeWrite("UpdateConfidence"); err != nil {
+		return err
+	}
+	return es.UpdateConfidence(ctx, behaviorID, newConfidence)
+}
+
+// RecordActivationHit delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) RecordActivationHit(ctx context.Context, behaviorID string) error {
+	es, err := c.extended("RecordActivationHit")
+	if err != nil {
+		return err
+	}
+	if err := c.beforeWrite("RecordActivationHit"); err != nil {
+		return err
+	}
+	return es.RecordActivationHit(ctx, behaviorID)
+}
+
+// RecordSessionActivation delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) RecordSessionActivation(ctx context.Context, behaviorID, sessionID string) error {
+	es, err := c.extended("RecordSessionActivation")
+	if err != nil {
+		return err
+	}
+	if err := c.beforeWrite("RecordSessionActivation"); err != nil {
+		return err
+	}
+	return es.RecordSessionActivation(ctx, behaviorID, sessionID)
+}
+
+// RecordConfirmed delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) RecordConfirmed(ctx context.Context, behaviorID string) error {
+	es, err := c.extended("RecordConfirmed")
+	if err != nil {
+		return err
+	}
+	if err := c.beforeWrite("RecordConfirmed"); err != nil {
+		return err
+	}
+	return es.RecordConfirmed(ctx, behaviorID)
+}
+
+// RecordFollowed delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) RecordFollowed(ctx context.Context, behaviorID string) error {
+	es, err := c.extended("RecordFollowed")
+	if err != nil {
+		return err
+	}
+	if err := c.beforeWrite("RecordFollowed"); err != nil {
+		return err
+	}
+	return es.RecordFollowed(ctx, behaviorID)
+}
+
+// BehaviorHistory delegates to the wrapped store after the injected latency.
+func (c *ChaosGraphStore) BehaviorHistory(ctx context.Context, behaviorID string) ([]Revision, error) {
+	es, err := c.extended("BehaviorHistory")
+	if err != nil {
+		return nil, err
+	}
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return es.BehaviorHistory(ctx, behaviorID)
+}
+
+// RecordOverridden delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) RecordOverridden(ctx context.Context, behaviorID string) error {
+	es, err := c.extended("RecordOverridden")
+	if err != nil {
+		return err
+	}
+	if err := c.beforeWrite("RecordOverridden"); err != nil {
+		return err
+	}
+	return es.RecordOverridden(ctx, behaviorID)
+}
+
+// TouchEdges delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) TouchEdges(ctx context.Context, behaviorIDs []string) error {
+	es, err := c.extended("TouchEdges")
+	if err != nil {
+		return err
+	}
+	if err := c.beforeWrite("TouchEdges"); err != nil {
+		return err
+	}
+	return es.TouchEdges(ctx, behaviorIDs)
+}
+
+// BatchUpdateEdgeWeights delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) BatchUpdateEdgeWeights(ctx context.Context, updates []EdgeWeightUpdate) error {
+	es, err := c.extended("BatchUpdateEdgeWeights")
+	if err != nil {
+		return err
+	}
+	if err := c.beforeWrite("BatchUpdateEdgeWeights"); err != nil {
+		return err
+	}
+	return es.BatchUpdateEdgeWeights(ctx, updates)
+}
+
+// PruneWeakEdges delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) PruneWeakEdges(ctx context.Context, kind EdgeKind, threshold float64) (int, error) {
+	es, err := c.extended("PruneWeakEdges")
+	if err != nil {
+		return 0, err
+	}
+	if err := c.beforeWrite("PruneWeakEdges"); err != nil {
+		return 0, err
+	}
+	return es.PruneWeakEdges(ctx, kind, threshold)
+}
+
+// ValidateBehaviorGraph delegates to the wrapped store. Validation is the
+// invariant check run after chaos, so faults are never injected into it.
+func (c *ChaosGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]ValidationError, error) {
+	es, err := c.extended("ValidateBehaviorGraph")
+	if err != nil {
+		return nil, err
+	}
+	return es.ValidateBehaviorGraph(ctx)
+}
+
+// StoreEmbedding delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) StoreEmbedding(ctx context.Context, behaviorID string, embedding []float32, modelName string) error {
+	es, ok := c.inner.(EmbeddingStore)
+	if !ok {
+		return fmt.Errorf("StoreEmbedding: wrapped store does not support embeddings")
+	}
+	if err := c.beforeWrite("StoreEmbedding"); err != nil {
+		return err
+	}
+	return es.StoreEmbedding(ctx, behaviorID, embedding, modelName)
+}
+
+// GetAllEmbeddings delegates to the wrapped store after the configured latency.
+func (c *ChaosGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbedding, error) {
+	es, ok := c.inner.(EmbeddingStore)
+	if !ok {
+		return nil, fmt.Errorf("GetAllEmbeddings: wrapped store does not support embeddings")
+	}
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return es.GetAllEmbeddings(ctx)
+}
+
+// GetBehaviorIDsWithoutEmbeddings delegates to the wrapped store after the configured latency.
+func (c *ChaosGraphStore) GetBehaviorIDsWithoutEmbeddings(ctx context.Context) ([]string, error) {
+	es, ok := c.inner.(EmbeddingStore)
+	if !ok {
+		return nil, fmt.Errorf("GetBehaviorIDsWithoutEmbeddings: wrapped store does not support embeddings")
+	}
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return es.GetBehaviorIDsWithoutEmbeddings(ctx)
+}
+
+// SearchText delegates to the wrapped store after the configured latency.
+func (c *ChaosGraphStore) SearchText(ctx context.Context, query string, matchAll bool, limit int) ([]TextMatch, error) {
+	ts, ok := c.inner.(TextSearcher)
+	if !ok {
+		return nil, fmt.Errorf("SearchText: wrapped store does not support full-text search")
+	}
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return ts.SearchText(ctx, query, matchAll, limit)
+}
+
+// QueryNodesPage delegates to the wrapped store after the configured latency.
+func (c *ChaosGraphStore) QueryNodesPage(ctx context.Context, predicate map[string]interface{}, opts PageOptions) (NodePage, error) {
+	if err := c.beforeRead(ctx); err != nil {
+		return NodePage{}, err
+	}
+	return QueryPage(ctx, c.inner, predicate, opts)
+}
+
+// BatchAdd delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) BatchAdd(ctx context.Context, nodes []Node, edges []Edge) error {
+	if err := c.beforeWrite("BatchAdd"); err != nil {
+		return err
+	}
+	return BatchAdd(ctx, c.inner, nodes, edges)
+}
+
+// RecordCoActivation delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) RecordCoActivation(ctx context.Context, pairKey string, at time.Time) error {
+	cs, ok := c.inner.(CoActivationStore)
+	if !ok {
+		return fmt.Errorf("RecordCoActivation: wrapped store does not support co-activations")
+	}
+	if err := c.beforeWrite("RecordCoActivation"); err != nil {
+		return err
+	}
+	return cs.RecordCoActivation(ctx, pairKey, at)
+}
+
+// GetCoActivations delegates to the wrapped store after the configured latency.
+func (c *ChaosGraphStore) GetCoActivations(ctx context.Context, pairKey string, since time.Time) ([]time.Time, error) {
+	cs, ok := c.inner.(CoActivationStore)
+	if !ok {
+		return nil, fmt.Errorf("GetCoActivations: wrapped store does not support co-activations")
+	}
+	if err := c.beforeRead(ctx); err != nil {
+		return nil, err
+	}
+	return cs.GetCoActivations(ctx, pairKey, since)
+}
+
+// PruneCoActivations delegates to the wrapped store unless a write fault is injected.
+func (c *ChaosGraphStore) PruneCoActivations(ctx context.Context, before time.Time) (int, error) {
+	cs, ok := c.inner.(CoActivationStore)
+	if !ok {
+		return 0, fmt.Errorf("PruneCoActivations: wrapped store does not support co-activations")
+	}
+	if err := c.beforeWrite("PruneCoActivations"); err != nil {
+		return 0, err
+	}
+	return cs.PruneCoActivations(ctx, before)
+}
+
+// Compile-time interface checks.
+var (
+	_ ExtendedGraphStore = (*ChaosGraphStore)(nil)
+	_ EmbeddingStore     = (*ChaosGraphStore)(nil)
+	_ TextSearcher       = (*ChaosGraphStore)(nil)
+	_ NodePager          = (*ChaosGraphStore)(nil)
+	_ BatchWriter        = (*ChaosGraphStore)(nil)
+	_ CoActivationStore  = (*ChaosGraphStore)(nil)
+)
+
+// unwrapSQLite returns the SQLiteGraphStore behind gs, looking through
+// ChaosGraphStore, TracedGraphStore, and LazyGraphStore wrappers (opening
+// a lazy store).
+func unwrapSQLite(gs GraphStore) (*SQLiteGraphStore, bool) {
+	for {
+		switch w := gs.(type) {
+		case *SQLiteGraphStore:
+			return w, true
+		case *ChaosGraphStore:
+			gs = w.Unwrap()
+		case *TracedGraphStore:
+			gs = w.Unwrap()
+		case *LazyGraphStore:
+			if gs = w.Unwrap(); gs == nil {
+				return nil, false
+			}
+		default:
+			return nil, false
+		}
+	}
+}
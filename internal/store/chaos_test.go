@@ -0,0 +1,134 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseChaosSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		want    ChaosConfig
+		wantErr bool
+	}{
+		{"empty", "", ChaosConfig{}, false},
+		{"all settings", "write=0.2, sync=0.1,partial=0.5,latency=50ms,seed=7",
+			ChaosConfig{WriteFailureRate: 0.2, SyncFailureRate: 0.1, PartialSyncRate: 0.5, Latency: 50 * time.Millisecond, Seed: 7}, false},
+		{"missing value", "write", ChaosConfig{}, true},
+		{"rate out of range", "write=1.5", ChaosConfig{}, true},
+		{"negative latency", "latency=-1s", ChaosConfig{}, true},
+		{"unknown key", "explode=1", ChaosConfig{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseChaosSpec(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseChaosSpec(%q) error = %v, wantErr %v", tt.spec, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseChaosSpec(%q) = %+v, want %+v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChaosGraphStore_WriteFailure(t *testing.T) {
+	ctx := context.Background()
+	inner := NewInMemoryGraphStore()
+	c := NewChaosGraphStore(inner, ChaosConfig{WriteFailureRate: 1})
+
+	_, err := c.AddNode(ctx, Node{ID: "b-1", Kind: NodeKindBehavior})
+	if !errors.Is(err, ErrInjectedFault) {
+		t.Fatalf("AddNode error = %v, want ErrInjectedFault", err)
+	}
+	if node, _ := inner.GetNode(ctx, "b-1"); node != nil {
+		t.Error("failed write should not reach the wrapped store")
+	}
+
+	// Reads are unaffected by write faults.
+	if _, err := c.QueryNodes(ctx, nil); err != nil {
+		t.Errorf("QueryNodes error = %v", err)
+	}
+}
+
+func TestChaosGraphStore_Sync(t *testing.T) {
+	ctx := context.Background()
+	tmpDir := t.TempDir()
+	inner, err := NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore: %v", err)
+	}
+
+	syncFail := NewChaosGraphStore(inner, ChaosConfig{SyncFailureRate: 1})
+	mustAddNode(t, syncFail, ctx, Node{
+		ID:      "b-1",
+		Kind:    NodeKindBehavior,
+		Content: map[string]interface{}{"name": "b-1", "kind": "directive", "content": map[string]interface{}{"canonical": "one"}},
+	})
+	if err := syncFail.Sync(ctx); !errors.Is(err, ErrInjectedFault) {
+		t.Fatalf("Sync error = %v, want ErrInjectedFault", err)
+	}
+
+	partial := NewChaosGraphStore(inner, ChaosConfig{PartialSyncRate: 1})
+	if err := partial.Sync(ctx); !errors.Is(err, ErrInjectedFault) {
+		t.Fatalf("partial Sync error = %v, want ErrInjectedFault", err)
+	}
+	if err := partial.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	// The partial sync flushed before failing, so the data survives a reopen.
+	reopened, err := NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer reopened.Close()
+	if mustGetNode(t, reopened, ctx, "b-1") == nil {
+		t.Error("expected b-1 to persist after partial sync")
+	}
+}
+
+func TestChaosGraphStore_LatencyRespectsContext(t *testing.T) {
+	c := NewChaosGraphStore(NewInMemoryGraphStore(), ChaosConfig{Latency: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	if _, err := c.GetNode(ctx, "b-1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("GetNode error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestNewMultiGraphStore_ChaosFromEnv(t *testing.T) {
+	localRoot := t.TempDir()
+	t.Setenv("HOME", t.TempDir())
+
+	t.Setenv(EnvChaos, "write=1")
+	m, err := NewMultiGraphStore(localRoot)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore: %v", err)
+	}
+	defer m.Close()
+
+	if _, ok := m.LocalStore().(*ChaosGraphStore); !ok {
+		t.Fatalf("local store = %T, want *ChaosGraphStore", m.LocalStore())
+	}
+	_, err = m.AddNodeToScope(context.Background(), Node{ID: "b-1", Kind: NodeKindBehavior}, ScopeLocal)
+	if !errors.Is(err, ErrInjectedFault) {
+		t.Errorf("AddNodeToScope error = %v, want ErrInjectedFault", err)
+	}
+
+	// Validation sees through the wrapper.
+	if _, err := m.ValidateBehaviorGraph(context.Background()); err != nil {
+		t.Errorf("ValidateBehaviorGraph: %v", err)
+	}
+
+	t.Setenv(EnvChaos, "write=2")
+	if _, err := NewMultiGraphStore(localRoot); err == nil {
+		t.Error("expected error for invalid FLOOP_CHAOS")
+	}
+}
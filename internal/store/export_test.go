@@ -108,6 +108,9 @@ func TestExportImportPreservesEmbeddings(t *testing.T) {
 	if storedModel != modelName {
 		t.Errorf("embedding_model = %s, want %s", storedModel, modelName)
 	}
+	if embeddings[0].ModelName != modelName {
+		t.Errorf("ModelName = %s, want %s", embeddings[0].ModelName, modelName)
+	}
 }
 
 func TestImportJSONL_NoEmbedding(t *testing.T) {
@@ -0,0 +1,231 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DatabaseInfo describes a floop.db as found on disk.
+type DatabaseInfo struct {
+	// SchemaVersion is the highest applied migration; 0 for databases that
+	// predate version tracking.
+	SchemaVersion int
+
+	// Problems lists PRAGMA integrity_check and foreign_key_check findings.
+	// Empty when the database is healthy.
+	Problems []string
+}
+
+// InspectDatabase opens the database at dbPath read-only and reports its
+// schema version and integrity. Unlike NewSQLiteGraphStore it runs no
+// migrations and does not refuse to open a corrupt database.
+func InspectDatabase(ctx context.Context, dbPath string) (*DatabaseInfo, error) {
+	if _, err := os.Stat(dbPath); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", "file:"+dbPath+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	defer db.Close()
+
+	info := &DatabaseInfo{}
+	rows, err := db.QueryContext(ctx, `PRAGMA integrity_check`)
+	if err != nil {
+		// A file that is not a database at all fails here.
+		info.Problems = append(info.Problems, err.Error())
+		return info, nil
+	}
+	for rows.Next() {
+		var result string
+		if err := rows.Scan(&result); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan integrity_check result: %w", err)
+		}
+		if result != "ok" {
+			info.Problems = append(info.Problems, result)
+		}
+	}
+	rows.Close()
+	if len(info.Problems) > 0 {
+		return info, nil
+	}
+
+	fkRows, err := db.QueryContext(ctx, `PRAGMA foreign_key_check`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run foreign_key_check: %w", err)
+	}
+	for fkRows.Next() {
+		var table, parent string
+		var rowid, fkid sql.NullInt64
+		if err := fkRows.Scan(&table, &rowid, &parent, &fkid); err != nil {
+			fkRows.Close()
+			return nil, fmt.Errorf("failed to scan foreign_key_check result: %w", err)
+		}
+		info.Problems = append(info.Problems, fmt.Sprintf("foreign key violation: %s row %d references missing %s", table, rowid.Int64, parent))
+	}
+	fkRows.Close()
+
+	if tableExists(ctx, db, "schema_version") {
+		if v, err := getSchemaVersion(ctx, db); err == nil {
+			info.SchemaVersion = v
+		}
+	}
+	return info, nil
+}
+
+// JSONLDrift describes where nodes.jsonl and edges.jsonl disagree with the
+// database.
+type JSONLDrift struct {
+	// MissingFromJSONL are node IDs in the database but not in nodes.jsonl.
+	MissingFromJSONL []string
+	// StaleInJSONL are node IDs in nodes.jsonl but not in the database.
+	StaleInJSONL []string
+	// DBEdges and JSONLEdges count the edges in each.
+	DBEdges    int
+	JSONLEdges int
+}
+
+// Empty reports whether the JSONL files match the database.
+func (d *JSONLDrift) Empty() bool {
+	return len(d.MissingFromJSONL) == 0 && len(d.StaleInJSONL) == 0 && d.DBEdges == d.JSONLEdges
+}
+
+// CheckJSONLDrift compares the node IDs and edge count in the JSONL export
+// with the database. Missing JSONL files count as empty.
+func (s *SQLiteGraphStore) CheckJSONLDrift(ctx context.Context) (*JSONLDrift, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	dbIDs := make(map[string]bool)
+	rows, err := s.db.QueryContext(ctx, `SELECT id FROM behaviors`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query behaviors: %w", err)
+	}
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan behavior ID: %w", err)
+		}
+		dbIDs[id] = true
+	}
+	rows.Close()
+
+	drift := &JSONLDrift{}
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM edges`).Scan(&drift.DBEdges); err != nil {
+		return nil, fmt.Errorf("failed to count edges: %w", err)
+	}
+
+	jsonlIDs := make(map[string]bool)
+	if _, err := os.Stat(s.nodesFile); err == nil {
+		nodes, err := s.readNodesFromJSONL()
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range nodes {
+			jsonlIDs[n.ID] = true
+		}
+	}
+	for id := range dbIDs {
+		if !jsonlIDs[id] {
+			drift.MissingFromJSONL = append(drift.MissingFromJSONL, id)
+		}
+	}
+	for id := range jsonlIDs {
+		if !dbIDs[id] {
+			drift.StaleInJSONL = append(drift.StaleInJSONL, id)
+		}
+	}
+	sort.Strings(drift.MissingFromJSONL)
+	sort.Strings(drift.StaleInJSONL)
+
+	drift.JSONLEdges, err = countLines(s.edgesFile)
+	if err != nil {
+		return nil, err
+	}
+	return drift, nil
+}
+
+// countLines counts the non-empty lines in a file; a missing file has none.
+func countLines(path string) (int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	n := 0
+	reader := bufio.NewReader(f)
+	for {
+		line, err := reader.ReadString('\n')
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+		if err == io.EOF {
+			return n, nil
+		}
+		if err != nil {
+			return n, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+	}
+}
+
+// ExportJSONL rewrites nodes.jsonl and edges.jsonl from the database.
+func (s *SQLiteGraphStore) ExportJSONL(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.exportNodesToJSONL(ctx); err != nil {
+		return fmt.Errorf("failed to export nodes: %w", err)
+	}
+	if err := s.exportEdgesToJSONL(ctx); err != nil {
+		return fmt.Errorf("failed to export edges: %w", err)
+	}
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM dirty_behaviors`); err != nil {
+		return fmt.Errorf("failed to clear dirty flags: %w", err)
+	}
+	return nil
+}
+
+// AllEdges returns every edge in the store.
+func (s *SQLiteGraphStore) AllEdges(ctx context.Context) ([]Edge, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT source, target, kind, weight, created_at FROM edges`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query edges: %w", err)
+	}
+	defer rows.Close()
+
+	var edges []Edge
+	for rows.Next() {
+		var e Edge
+		var kind string
+		var weight sql.NullFloat64
+		var createdAt sql.NullString
+		if err := rows.Scan(&e.Source, &e.Target, &kind, &weight, &createdAt); err != nil {
+			return nil, fmt.Errorf("failed to scan edge: %w", err)
+		}
+		e.Kind = EdgeKind(kind)
+		e.Weight = weight.Float64
+		if createdAt.Valid {
+			if t, err := time.Parse(time.RFC3339, createdAt.String); err == nil {
+				e.CreatedAt = t
+			}
+		}
+		edges = append(edges, e)
+	}
+	return edges, rows.Err()
+}
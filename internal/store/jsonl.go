@@ -0,0 +1,195 @@
+package store
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrReadOnly is returned by writes to a read-only store.
+var ErrReadOnly = errors.New("store is read-only")
+
+// Backend selects how a store is read.
+type Backend string
+
+const (
+	// BackendSQLite opens the SQLite database, creating it from the JSONL
+	// export if needed.
+	BackendSQLite Backend = "sqlite"
+
+	// BackendJSONL reads the JSONL export directly into a read-only
+	// JSONLGraphStore, without SQLite.
+	BackendJSONL Backend = "jsonl"
+
+	// BackendAuto reads the JSONL export directly when the store has no
+	// database yet, as in a fresh CI checkout, and opens SQLite otherwise.
+	BackendAuto Backend = "auto"
+)
+
+// ParseBackend parses a backend name: sqlite, jsonl, or auto.
+func ParseBackend(name string) (Backend, error) {
+	switch b := Backend(name); b {
+	case BackendSQLite, BackendJSONL, BackendAuto:
+		return b, nil
+	default:
+		return "", fmt.Errorf("invalid store backend %q (valid: sqlite, jsonl, auto)", name)
+	}
+}
+
+// resolve returns the concrete backend for the store at projectRoot.
+func (b Backend) resolve(projectRoot string) Backend {
+	if b != BackendAuto {
+		return b
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, ".floop", "floop.db")); err != nil {
+		return BackendJSONL
+	}
+	return BackendSQLite
+}
+
+// OpenGraphStore opens the store at projectRoot with backend. SQLite stores
+// are read-write; JSONL stores are read-only.
+func OpenGraphStore(projectRoot string, backend Backend) (GraphStore, error) {
+	if backend.resolve(projectRoot) == BackendJSONL {
+		return NewJSONLGraphStore(projectRoot)
+	}
+	return NewSQLiteGraphStore(projectRoot)
+}
+
+// JSONLGraphStore is a read-only GraphStore over a store's JSONL export
+// (.floop/nodes.jsonl and .floop/edges.jsonl). It needs no database, so
+// read-only commands like 'floop active' can run where SQLite is
+// unavailable or the database has not been built. Writes fail with
+// ErrReadOnly.
+type JSONLGraphStore struct {
+	mem *InMemoryGraphStore
+
+	// LoadErrors records malformed lines, which are skipped.
+	LoadErrors []LoadError
+}
+
+// Compile-time interface check.
+var _ GraphStore = (*JSONLGraphStore)(nil)
+
+// NewJSONLGraphStore loads the JSONL export of the store at projectRoot. A
+// missing export is an empty store; nothing is created on disk.
+func NewJSONLGraphStore(projectRoot string) (*JSONLGraphStore, error) {
+	floopDir := filepath.Join(projectRoot, ".floop")
+	s := &JSONLGraphStore{mem: NewInMemoryGraphStore()}
+
+	err := s.load(filepath.Join(floopDir, "nodes.jsonl"), func(line []byte) error {
+		var node Node
+		if err := json.Unmarshal(line, &node); err != nil {
+			return err
+		}
+		s.mem.nodes[node.ID] = node
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load nodes: %w", err)
+	}
+	err = s.load(filepath.Join(floopDir, "edges.jsonl"), func(line []byte) error {
+		var edge Edge
+		if err := json.Unmarshal(line, &edge); err != nil {
+			return err
+		}
+		s.mem.edges = append(s.mem.edges, edge)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load edges: %w", err)
+	}
+	return s, nil
+}
+
+// load calls parse for each non-empty line of path, recording lines it
+// rejects in LoadErrors.
+func (s *JSONLGraphStore) load(path string, parse func(line []byte) error) error {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		if err := parse(line); err != nil {
+			s.LoadErrors = append(s.LoadErrors, LoadError{
+				File:    path,
+				Line:    lineNum,
+				Content: truncateForError(string(line)),
+				Error:   err.Error(),
+			})
+		}
+	}
+	return scanner.Err()
+}
+
+// AddNode fails: the store is read-only.
+func (s *JSONLGraphStore) AddNode(ctx context.Context, node Node) (string, error) {
+	return "", ErrReadOnly
+}
+
+// UpdateNode fails: the store is read-only.
+func (s *JSONLGraphStore) UpdateNode(ctx context.Context, node Node) error {
+	return ErrReadOnly
+}
+
+// GetNode retrieves a node by ID.
+func (s *JSONLGraphStore) GetNode(ctx context.Context, id string) (*Node, error) {
+	return s.mem.GetNode(ctx, id)
+}
+
+// DeleteNode fails: the store is read-only.
+func (s *JSONLGraphStore) DeleteNode(ctx context.Context, id string) error {
+	return ErrReadOnly
+}
+
+// QueryNodes returns nodes matching the predicate.
+func (s *JSONLGraphStore) QueryNodes(ctx context.Context, predicate map[string]interface{}) ([]Node, error) {
+	return s.mem.QueryNodes(ctx, predicate)
+}
+
+// AddEdge fails: the store is read-only.
+func (s *JSONLGraphStore) AddEdge(ctx context.Context, edge Edge) error {
+	return ErrReadOnly
+}
+
+// RemoveEdge fails: the store is read-only.
+func (s *JSONLGraphStore) RemoveEdge(ctx context.Context, source, target string, kind EdgeKind) error {
+	return ErrReadOnly
+}
+
+// GetEdges returns edges connected to a node.
+func (s *JSONLGraphStore) GetEdges(ctx context.Context, nodeID string, direction Direction, kind EdgeKind) ([]Edge, error) {
+	return s.mem.GetEdges(ctx, nodeID, direction, kind)
+}
+
+// Traverse returns all nodes reachable from start by following edges of the given kinds.
+func (s *JSONLGraphStore) Traverse(ctx context.Context, start string, edgeKinds []EdgeKind, direction Direction, maxDepth int) ([]Node, error) {
+	return s.mem.Traverse(ctx, start, edgeKinds, direction, maxDepth)
+}
+
+// Sync is a no-op: the store never has changes to persist.
+func (s *JSONLGraphStore) Sync(ctx context.Context) error {
+	return nil
+}
+
+// Close is a no-op.
+func (s *JSONLGraphStore) Close() error {
+	return nil
+}
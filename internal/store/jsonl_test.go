@@ -0,0 +1,132 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// exportOnly builds a store at a new project root, exports it to JSONL,
+// and deletes the database, as in a fresh checkout of a committed .floop.
+func exportOnly(t *testing.T) string {
+	t.Helper()
+	t.Setenv(EnvLockWait, "")
+	root := t.TempDir()
+	s, err := NewSQLiteGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore: %v", err)
+	}
+	ctx := context.Background()
+	for _, id := range []string{"b1", "b2"} {
+		mustAddNode(t, s, ctx, pageTestNode(id))
+	}
+	if err := s.AddEdge(ctx, Edge{Source: "b1", Target: "b2", Kind: EdgeKindSimilarTo, Weight: 0.7, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("AddEdge: %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	for _, name := range []string{"floop.db", "floop.db-wal", "floop.db-shm"} {
+		os.Remove(filepath.Join(root, ".floop", name))
+	}
+	return root
+}
+
+func TestJSONLGraphStore(t *testing.T) {
+	root := exportOnly(t)
+	ctx := context.Background()
+
+	s, err := NewJSONLGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewJSONLGraphStore: %v", err)
+	}
+	defer s.Close()
+
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{"kind": string(NodeKindBehavior)})
+	if err != nil {
+		t.Fatalf("QueryNodes: %v", err)
+	}
+	if len(nodes) != 2 {
+		t.Errorf("QueryNodes returned %d nodes, want 2", len(nodes))
+	}
+	node, err := s.GetNode(ctx, "b1")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode(b1) = %v, %v", node, err)
+	}
+	if got := canonicalContent(*node); got != "behavior b1" {
+		t.Errorf("canonical = %q, want %q", got, "behavior b1")
+	}
+	edges, err := s.GetEdges(ctx, "b1", DirectionOutbound, EdgeKindSimilarTo)
+	if err != nil || len(edges) != 1 || edges[0].Target != "b2" {
+		t.Errorf("GetEdges(b1) = %v, %v; want one edge to b2", edges, err)
+	}
+
+	if _, err := s.AddNode(ctx, pageTestNode("b3")); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("AddNode = %v, want ErrReadOnly", err)
+	}
+	if err := s.DeleteNode(ctx, "b1"); !errors.Is(err, ErrReadOnly) {
+		t.Errorf("DeleteNode = %v, want ErrReadOnly", err)
+	}
+	if err := s.Sync(ctx); err != nil {
+		t.Errorf("Sync: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(root, ".floop", "floop.db")); !os.IsNotExist(err) {
+		t.Error("reading the JSONL store created a database")
+	}
+}
+
+func TestJSONLGraphStore_SkipsMalformedLines(t *testing.T) {
+	root := exportOnly(t)
+	f, err := os.OpenFile(filepath.Join(root, ".floop", "nodes.jsonl"), os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("{not json\n")
+	f.Close()
+
+	s, err := NewJSONLGraphStore(root)
+	if err != nil {
+		t.Fatalf("NewJSONLGraphStore: %v", err)
+	}
+	if len(s.LoadErrors) != 1 {
+		t.Errorf("LoadErrors = %v, want 1", s.LoadErrors)
+	}
+	nodes, _ := s.QueryNodes(context.Background(), nil)
+	if len(nodes) != 2 {
+		t.Errorf("loaded %d nodes, want 2", len(nodes))
+	}
+}
+
+func TestOpenGraphStore_Backends(t *testing.T) {
+	root := exportOnly(t)
+
+	if _, err := ParseBackend("postgres"); err == nil {
+		t.Error("ParseBackend accepted an unknown backend")
+	}
+
+	gs, err := OpenGraphStore(root, BackendAuto)
+	if err != nil {
+		t.Fatalf("OpenGraphStore(auto): %v", err)
+	}
+	if _, ok := gs.(*JSONLGraphStore); !ok {
+		t.Errorf("auto without a database opened %T, want *JSONLGraphStore", gs)
+	}
+
+	gs, err = OpenGraphStore(root, BackendSQLite)
+	if err != nil {
+		t.Fatalf("OpenGraphStore(sqlite): %v", err)
+	}
+	gs.Close()
+
+	gs, err = OpenGraphStore(root, BackendAuto)
+	if err != nil {
+		t.Fatalf("OpenGraphStore(auto): %v", err)
+	}
+	defer gs.Close()
+	if _, ok := gs.(*SQLiteGraphStore); !ok {
+		t.Errorf("auto with a database opened %T, want *SQLiteGraphStore", gs)
+	}
+}
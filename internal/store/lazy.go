@@ -0,0 +1,411 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// LazyGraphStore defers opening a GraphStore until its first operation, so
+// commands that never touch a store never pay for opening it (creating the
+// .floop directory, schema migration, JSONL auto-import). Extended,
+// embedding, and co-activation operations are delegated when the opened
+// store supports them. Thread-safe if the opened store is.
+type LazyGraphStore struct {
+	open func() (GraphStore, error)
+
+	mu     sync.Mutex
+	opened bool
+	inner  GraphStore
+	err    error
+}
+
+// NewLazyGraphStore returns a store that calls open on first use. An open
+// error is returned by that and every later operation.
+func NewLazyGraphStore(open func() (GraphStore, error)) *LazyGraphStore {
+	return &LazyGraphStore{open: open}
+}
+
+// store opens the wrapped store if it is not open yet.
+func (l *LazyGraphStore) store() (GraphStore, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.opened {
+		l.inner, l.err = l.open()
+		l.opened = true
+	}
+	return l.inner, l.err
+}
+
+// openedStore returns the wrapped store without opening it, or nil.
+func (l *LazyGraphStore) openedStore() GraphStore {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.inner
+}
+
+// Opened reports whether the wrapped store has been opened (or attempted).
+func (l *LazyGraphStore) Opened() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.opened
+}
+
+// Unwrap opens and returns the wrapped store, or nil if it failed to open.
+func (l *LazyGraphStore) Unwrap() GraphStore {
+	s, err := l.store()
+	if err != nil {
+		return nil
+	}
+	return s
+}
+
+// AddNode opens the store and adds a node.
+func (l *LazyGraphStore) AddNode(ctx context.Context, node Node) (string, error) {
+	s, err := l.store()
+	if err != nil {
+		return "", err
+	}
+	return s.AddNode(ctx, node)
+}
+
+// UpdateNode opens the store and updates a node.
+func (l *LazyGraphStore) UpdateNode(ctx context.Context, node Node) error {
+	s, err := l.store()
+	if err != nil {
+		return err
+	}
+	return s.UpdateNode(ctx, node)
+}
+
+// GetNode opens the store and gets a node.
+func (l *LazyGraphStore) GetNode(ctx context.Context, id string) (*Node, error) {
+	s, err := l.store()
+	if err != nil {
+		return nil, err
+	}
+	return s.GetNode(ctx, id)
+}
+
+// DeleteNode opens the store and deletes a node.
+func (l *LazyGraphStore) DeleteNode(ctx context.Context, id string) error {
+	s, err := l.store()
+	if err != nil {
+		return err
+	}
+	return s.DeleteNode(ctx, id)
+}
+
+// QueryNodes opens the store and queries nodes.
+func (l *LazyGraphStore) QueryNodes(ctx context.Context, predicate map[string]interface{}) ([]Node, error) {
+	s, err := l.store()
+	if err != nil {
+		return nil, err
+	}
+	return s.QueryNodes(ctx, predicate)
+}
+
+// AddEdge opens the store and adds an edge.
+func (l *LazyGraphStore) AddEdge(ctx context.Context, edge Edge) error {
+	s, err := l.store()
+	if err != nil {
+		return err
+	}
+	return s.AddEdge(ctx, edge)
+}
+
+// RemoveEdge opens the store and removes an edge.
+func (l *LazyGraphStore) RemoveEdge(ctx context.Context, source, target string, kind EdgeKind) error {
+	s, err := l.store()
+	if err != nil {
+		return err
+	}
+	return s.RemoveEdge(ctx, source, target, kind)
+}
+
+// GetEdges opens the store and gets edges.
+func (l *LazyGraphStore) GetEdges(ctx context.Context, nodeID string, direction Direction, kind EdgeKind) ([]Edge, error) {
+	s, err := l.store()
+	if err != nil {
+		return nil, err
+	}
+	return s.GetEdges(ctx, nodeID, direction, kind)
+}
+
+// Traverse opens the store and traverses the graph.
+func (l *LazyGraphStore) Traverse(ctx context.Context, start string, edgeKinds []EdgeKind, direction Direction, maxDepth int) ([]Node, error) {
+	s, err := l.store()
+	if err != nil {
+		return nil, err
+	}
+	return s.Traverse(ctx, start, edgeKinds, direction, maxDepth)
+}
+
+// Sync syncs the wrapped store. A store that was never opened has nothing
+// to sync.
+func (l *LazyGraphStore) Sync(ctx context.Context) error {
+	s := l.openedStore()
+	if s == nil {
+		return nil
+	}
+	return s.Sync(ctx)
+}
+
+// Close closes the wrapped store if it was opened.
+func (l *LazyGraphStore) Close() error {
+	s := l.openedStore()
+	if s == nil {
+		return nil
+	}
+	return s.Close()
+}
+
+// extended opens the store and returns it as an ExtendedGraphStore.
+func (l *LazyGraphStore) extended(op string) (ExtendedGraphStore, error) {
+	s, err := l.store()
+	if err != nil {
+		return nil, err
+	}
+	es, ok := s.(ExtendedGraphStore)
+	if !ok {
+		return nil, fmt.Errorf("%s: wrapped store does not support extended operations", op)
+	}
+	return es, nil
+}
+
+// UpdateConfidence delegates to the wrapped store.
+func (l *LazyGraphStore) UpdateConfidence(ctx context.Context, behaviorID string, newConfidence float64) error {
+	es, err := l.extended("UpdateConfidence")
+	if err != nil {
+		return err
+	}
+	return es.UpdateConfidence(ctx, behaviorID, newConfidence)
+}
+
+// RecordActivationHit delegates to the wrapped store.
+func (l *LazyGraphStore) RecordActivationHit(ctx context.Context, behaviorID string) error {
+	es, err := l.extended("RecordActivationHit")
+	if err != nil {
+		return err
+	}
+	return es.RecordActivationHit(ctx, behaviorID)
+}
+
+// RecordSessionActivation delegates to the wrapped store.
+func (l *LazyGraphStore) RecordSessionActivation(ctx context.Context, behaviorID, sessionID string) error {
+	es, err := l.extended("RecordSessionActivation")
+	if err != nil {
+		return err
+	}
+	return es.RecordSessionActivation(ctx, behaviorID, sessionID)
+}
+
+// RecordConfirmed delegates to the wrapped store.
+func (l *LazyGraphStore) RecordConfirmed(ctx context.Context, behaviorID string) error {
+	es, err := l.extended("RecordConfirmed")
+	if err != nil {
+		return err
+	}
+	return es.RecordConfirmed(ctx, behaviorID)
+}
+
+// RecordFollowed delegates to the wrapped store.
+func (l *LazyGraphStore) RecordFollowed(ctx context.Context, behaviorID string) error {
+	es, err := l.extended("RecordFollowed")
+	if err != nil {
+		return err
+	}
+	return es.RecordFollowed(ctx, behaviorID)
+}
+
+// BehaviorHistory delegates to the wrapped store.
+func (l *LazyGraphStore) BehaviorHistory(ctx context.Context, behaviorID string) ([]Revision, error) {
+	es, err := l.extended("BehaviorHistory")
+	if err != nil {
+		return nil, err
+	}
+	return es.BehaviorHistory(ctx, behaviorID)
+}
+
+// RecordOverridden delegates to the wrapped store.
+func (l *LazyGraphStore) RecordOverridden(ctx context.Context, behaviorID string) error {
+	es, err := l.extended("RecordOverridden")
+	if err != nil {
+		return err
+	}
+	return es.RecordOverridden(ctx, behaviorID)
+}
+
+// TouchEdges delegates to the wrapped store.
+func (l *LazyGraphStore) TouchEdges(ctx context.Context, behaviorIDs []string) error {
+	es, err := l.extended("TouchEdges")
+	if err != nil {
+		return err
+	}
+	return es.TouchEdges(ctx, behaviorIDs)
+}
+
+// BatchUpdateEdgeWeights delegates to the wrapped store.
+func (l *LazyGraphStore) BatchUpdateEdgeWeights(ctx context.Context, updates []EdgeWeightUpdate) error {
+	es, err := l.extended("BatchUpdateEdgeWeights")
+	if err != nil {
+		return err
+	}
+	return es.BatchUpdateEdgeWeights(ctx, updates)
+}
+
+// PruneWeakEdges delegates to the wrapped store.
+func (l *LazyGraphStore) PruneWeakEdges(ctx context.Context, kind EdgeKind, threshold float64) (int, error) {
+	es, err := l.extended("PruneWeakEdges")
+	if err != nil {
+		return 0, err
+	}
+	return es.PruneWeakEdges(ctx, kind, threshold)
+}
+
+// ValidateBehaviorGraph delegates to the wrapped store.
+func (l *LazyGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]ValidationError, error) {
+	es, err := l.extended("ValidateBehaviorGraph")
+	if err != nil {
+		return nil, err
+	}
+	return es.ValidateBehaviorGraph(ctx)
+}
+
+// embeddings opens the store and returns it as an EmbeddingStore.
+func (l *LazyGraphStore) embeddings(op string) (EmbeddingStore, error) {
+	s, err := l.store()
+	if err != nil {
+		return nil, err
+	}
+	es, ok := s.(EmbeddingStore)
+	if !ok {
+		return nil, fmt.Errorf("%s: wrapped store does not support embeddings", op)
+	}
+	return es, nil
+}
+
+// StoreEmbedding delegates to the wrapped store.
+func (l *LazyGraphStore) StoreEmbedding(ctx context.Context, behaviorID string, embedding []float32, modelName string) error {
+	es, err := l.embeddings("StoreEmbedding")
+	if err != nil {
+		return err
+	}
+	return es.StoreEmbedding(ctx, behaviorID, embedding, modelName)
+}
+
+// GetAllEmbeddings delegates to the wrapped store.
+func (l *LazyGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbedding, error) {
+	es, err := l.embeddings("GetAllEmbeddings")
+	if err != nil {
+		return nil, err
+	}
+	return es.GetAllEmbeddings(ctx)
+}
+
+// GetBehaviorIDsWithoutEmbeddings delegates to the wrapped store.
+func (l *LazyGraphStore) GetBehaviorIDsWithoutEmbeddings(ctx context.Context) ([]string, error) {
+	es, err := l.embeddings("GetBehaviorIDsWithoutEmbeddings")
+	if err != nil {
+		return nil, err
+	}
+	return es.GetBehaviorIDsWithoutEmbeddings(ctx)
+}
+
+// ClearOrphanedEmbeddings delegates to the wrapped store when it supports
+// clearing embeddings.
+func (l *LazyGraphStore) ClearOrphanedEmbeddings(ctx context.Context) (int, error) {
+	s, err := l.store()
+	if err != nil {
+		return 0, err
+	}
+	c, ok := s.(interface {
+		ClearOrphanedEmbeddings(ctx context.Context) (int, error)
+	})
+	if !ok {
+		return 0, nil
+	}
+	return c.ClearOrphanedEmbeddings(ctx)
+}
+
+// SearchText delegates to the wrapped store.
+func (l *LazyGraphStore) SearchText(ctx context.Context, query string, matchAll bool, limit int) ([]TextMatch, error) {
+	s, err := l.store()
+	if err != nil {
+		return nil, err
+	}
+	ts, ok := s.(TextSearcher)
+	if !ok {
+		return nil, fmt.Errorf("SearchText: wrapped store does not support full-text search")
+	}
+	return ts.SearchText(ctx, query, matchAll, limit)
+}
+
+// QueryNodesPage delegates to the wrapped store.
+func (l *LazyGraphStore) QueryNodesPage(ctx context.Context, predicate map[string]interface{}, opts PageOptions) (NodePage, error) {
+	s, err := l.store()
+	if err != nil {
+		return NodePage{}, err
+	}
+	return QueryPage(ctx, s, predicate, opts)
+}
+
+// BatchAdd delegates to the wrapped store.
+func (l *LazyGraphStore) BatchAdd(ctx context.Context, nodes []Node, edges []Edge) error {
+	s, err := l.store()
+	if err != nil {
+		return err
+	}
+	return BatchAdd(ctx, s, nodes, edges)
+}
+
+// coActivations opens the store and returns it as a CoActivationStore.
+func (l *LazyGraphStore) coActivations(op string) (CoActivationStore, error) {
+	s, err := l.store()
+	if err != nil {
+		return nil, err
+	}
+	cs, ok := s.(CoActivationStore)
+	if !ok {
+		return nil, fmt.Errorf("%s: wrapped store does not support co-activations", op)
+	}
+	return cs, nil
+}
+
+// RecordCoActivation delegates to the wrapped store.
+func (l *LazyGraphStore) RecordCoActivation(ctx context.Context, pairKey string, at time.Time) error {
+	cs, err := l.coActivations("RecordCoActivation")
+	if err != nil {
+		return err
+	}
+	return cs.RecordCoActivation(ctx, pairKey, at)
+}
+
+// GetCoActivations delegates to the wrapped store.
+func (l *LazyGraphStore) GetCoActivations(ctx context.Context, pairKey string, since time.Time) ([]time.Time, error) {
+	cs, err := l.coActivations("GetCoActivations")
+	if err != nil {
+		return nil, err
+	}
+	return cs.GetCoActivations(ctx, pairKey, since)
+}
+
+// PruneCoActivations delegates to the wrapped store.
+func (l *LazyGraphStore) PruneCoActivations(ctx context.Context, before time.Time) (int, error) {
+	cs, err := l.coActivations("PruneCoActivations")
+	if err != nil {
+		return 0, err
+	}
+	return cs.PruneCoActivations(ctx, before)
+}
+
+// Compile-time interface checks.
+var (
+	_ ExtendedGraphStore = (*LazyGraphStore)(nil)
+	_ EmbeddingStore     = (*LazyGraphStore)(nil)
+	_ TextSearcher       = (*LazyGraphStore)(nil)
+	_ NodePager          = (*LazyGraphStore)(nil)
+	_ BatchWriter        = (*LazyGraphStore)(nil)
+	_ CoActivationStore  = (*LazyGraphStore)(nil)
+)
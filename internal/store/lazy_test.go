@@ -0,0 +1,99 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLazyGraphStore_OpensOnFirstUse(t *testing.T) {
+	opens := 0
+	l := NewLazyGraphStore(func() (GraphStore, error) {
+		opens++
+		return NewInMemoryGraphStore(), nil
+	})
+
+	if l.Opened() {
+		t.Fatal("store opened before first use")
+	}
+	if err := l.Sync(context.Background()); err != nil {
+		t.Errorf("Sync on unopened store: %v", err)
+	}
+	if l.Opened() {
+		t.Fatal("Sync opened the store")
+	}
+
+	ctx := context.Background()
+	if _, err := l.AddNode(ctx, Node{ID: "b-1", Kind: NodeKindBehavior}); err != nil {
+		t.Fatalf("AddNode: %v", err)
+	}
+	if n, err := l.GetNode(ctx, "b-1"); err != nil || n == nil {
+		t.Fatalf("GetNode = %v, %v", n, err)
+	}
+	if opens != 1 {
+		t.Errorf("opened %d times, want 1", opens)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close: %v", err)
+	}
+}
+
+func TestLazyGraphStore_OpenErrorIsSticky(t *testing.T) {
+	openErr := errors.New("disk on fire")
+	opens := 0
+	l := NewLazyGraphStore(func() (GraphStore, error) {
+		opens++
+		return nil, openErr
+	})
+
+	ctx := context.Background()
+	if _, err := l.QueryNodes(ctx, nil); !errors.Is(err, openErr) {
+		t.Errorf("QueryNodes error = %v, want %v", err, openErr)
+	}
+	if err := l.UpdateConfidence(ctx, "b-1", 0.5); !errors.Is(err, openErr) {
+		t.Errorf("UpdateConfidence error = %v, want %v", err, openErr)
+	}
+	if opens != 1 {
+		t.Errorf("opened %d times, want 1", opens)
+	}
+	if err := l.Close(); err != nil {
+		t.Errorf("Close after failed open: %v", err)
+	}
+}
+
+func TestNewMultiGraphStore_OpensStoresLazily(t *testing.T) {
+	projectRoot := t.TempDir()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	m, err := NewMultiGraphStore(projectRoot)
+	if err != nil {
+		t.Fatalf("NewMultiGraphStore: %v", err)
+	}
+	defer m.Close()
+
+	for _, dir := range []string{projectRoot, home} {
+		if _, err := os.Stat(filepath.Join(dir, ".floop")); err == nil {
+			t.Fatalf("%s/.floop created before any store operation", dir)
+		}
+	}
+
+	// A local-only write opens only the local store.
+	ctx := context.Background()
+	if _, err := m.AddNodeToScope(ctx, Node{ID: "b-1", Kind: NodeKindBehavior}, ScopeLocal); err != nil {
+		t.Fatalf("AddNodeToScope: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(projectRoot, ".floop", "floop.db")); err != nil {
+		t.Errorf("local store not opened: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".floop")); err == nil {
+		t.Error("global store opened by a local-only write")
+	}
+
+	// SQLite-specific operations see through the lazy wrapper.
+	if _, err := m.ValidateBehaviorGraph(ctx); err != nil {
+		t.Errorf("ValidateBehaviorGraph: %v", err)
+	}
+}
@@ -0,0 +1,92 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// EnvLockWait sets how long a store waits for another floop process to
+// release it, as a Go duration such as "30s". "0" fails at once instead of
+// waiting. Unset uses DefaultLockWait.
+const EnvLockWait = "FLOOP_LOCK_WAIT"
+
+// DefaultLockWait is how long a store waits for another floop process to
+// release it when FLOOP_LOCK_WAIT is unset.
+const DefaultLockWait = 10 * time.Second
+
+// LockFile is the name of the advisory lock file in a .floop directory.
+const LockFile = "floop.lock"
+
+// lockPollInterval is how often a waiting AcquireLock retries.
+const lockPollInterval = 25 * time.Millisecond
+
+// ErrLocked is returned when another floop process holds a store's lock
+// for longer than the caller is willing to wait.
+var ErrLocked = errors.New("store is locked by another floop process")
+
+// LockWaitFromEnv returns the lock wait configured by FLOOP_LOCK_WAIT.
+func LockWaitFromEnv() (time.Duration, error) {
+	value := os.Getenv(EnvLockWait)
+	if value == "" {
+		return DefaultLockWait, nil
+	}
+	wait, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s %q: %w", EnvLockWait, value, err)
+	}
+	if wait < 0 {
+		return 0, fmt.Errorf("invalid %s %q: must be non-negative", EnvLockWait, value)
+	}
+	return wait, nil
+}
+
+// FileLock is an exclusive advisory lock on a file, shared by every floop
+// process using the same .floop directory. The operating system releases
+// it if the process exits without calling Release.
+type FileLock struct {
+	f *os.File
+}
+
+// AcquireLock takes the exclusive lock on the file at path, creating the
+// file if needed. It retries until wait elapses or ctx is done, then
+// returns ErrLocked; a zero wait tries once.
+func AcquireLock(ctx context.Context, path string, wait time.Duration) (*FileLock, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		locked, err := tryLockFile(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			return &FileLock{f: f}, nil
+		}
+		if !time.Now().Before(deadline) {
+			f.Close()
+			return nil, fmt.Errorf("%w: %s (waited %s)", ErrLocked, path, wait)
+		}
+		select {
+		case <-ctx.Done():
+			f.Close()
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+}
+
+// Release releases the lock.
+func (l *FileLock) Release() error {
+	if err := unlockFile(l.f); err != nil {
+		l.f.Close()
+		return fmt.Errorf("failed to unlock: %w", err)
+	}
+	return l.f.Close()
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestAcquireLock(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), LockFile)
+
+	lock, err := AcquireLock(ctx, path, 0)
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+
+	if _, err := AcquireLock(ctx, path, 0); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second AcquireLock with no wait = %v, want ErrLocked", err)
+	}
+	start := time.Now()
+	if _, err := AcquireLock(ctx, path, 100*time.Millisecond); !errors.Is(err, ErrLocked) {
+		t.Fatalf("second AcquireLock with wait = %v, want ErrLocked", err)
+	}
+	if waited := time.Since(start); waited < 100*time.Millisecond {
+		t.Errorf("AcquireLock gave up after %s, want at least 100ms", waited)
+	}
+
+	// A waiting caller gets the lock once it is released
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		lock.Release()
+	}()
+	second, err := AcquireLock(ctx, path, 5*time.Second)
+	if err != nil {
+		t.Fatalf("AcquireLock after release: %v", err)
+	}
+	if err := second.Release(); err != nil {
+		t.Fatalf("Release: %v", err)
+	}
+}
+
+func TestAcquireLock_ContextCanceled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), LockFile)
+	lock, err := AcquireLock(context.Background(), path, 0)
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+	defer lock.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := AcquireLock(ctx, path, time.Minute); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("AcquireLock = %v, want context deadline exceeded", err)
+	}
+}
+
+func TestLockWaitFromEnv(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{"", DefaultLockWait, false},
+		{"0", 0, false},
+		{"30s", 30 * time.Second, false},
+		{"-1s", 0, true},
+		{"soon", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			t.Setenv(EnvLockWait, tt.value)
+			got, err := LockWaitFromEnv()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LockWaitFromEnv() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("LockWaitFromEnv() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSQLiteGraphStore_SyncWhileLocked(t *testing.T) {
+	t.Setenv(EnvLockWait, "0")
+	tmpDir := t.TempDir()
+	s, err := NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore: %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	lock, err := AcquireLock(ctx, filepath.Join(tmpDir, ".floop", LockFile), 0)
+	if err != nil {
+		t.Fatalf("AcquireLock: %v", err)
+	}
+	if err := s.Sync(ctx); !errors.Is(err, ErrLocked) {
+		t.Errorf("Sync while locked = %v, want ErrLocked", err)
+	}
+	if _, err := NewSQLiteGraphStore(tmpDir); !errors.Is(err, ErrLocked) {
+		t.Errorf("NewSQLiteGraphStore while locked = %v, want ErrLocked", err)
+	}
+	lock.Release()
+
+	if err := s.Sync(ctx); err != nil {
+		t.Errorf("Sync after release: %v", err)
+	}
+}
@@ -2,8 +2,12 @@ package store
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"sync"
+	"time"
+
+	"github.com/nvandessel/floop/internal/utils"
 )
 
 // canonicalContent extracts the canonical string from a node's nested content map.
@@ -258,6 +262,7 @@ func (s *InMemoryGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEm
 		results = append(results, BehaviorEmbedding{
 			BehaviorID: id,
 			Embedding:  entry.embedding,
+			ModelName:  entry.modelName,
 		})
 	}
 	return results, nil
@@ -304,6 +309,45 @@ func matchesPredicate(node Node, predicate map[string]interface{}) bool {
 			continue
 		case "id":
 			actual = node.ID
+		case PredicateBehaviorKind:
+			if fmt.Sprintf("%v", node.Content["kind"]) != fmt.Sprintf("%v", required) {
+				return false
+			}
+			continue
+		case PredicateTag:
+			if !hasTag(node, fmt.Sprintf("%v", required)) {
+				return false
+			}
+			continue
+		case PredicateMinConfidence:
+			minConfidence, _ := required.(float64)
+			if utils.GetFloat64(node.Metadata, "confidence", 0.6) < minConfidence {
+				return false
+			}
+			continue
+		case PredicatePack:
+			actual = nodeProvenance(node)["package"]
+		case PredicateCreatedAfter:
+			after, ok := required.(time.Time)
+			createdAt, known := nodeCreatedAt(node)
+			if !ok || !known || createdAt.Before(after) {
+				return false
+			}
+			continue
+		case PredicateExpiresBefore:
+			before, ok := required.(time.Time)
+			expiresAt, known := metadataTime(node, "expires_at")
+			if !ok || !known || expiresAt.After(before) {
+				return false
+			}
+			continue
+		case PredicateSnoozedAfter:
+			after, ok := required.(time.Time)
+			snoozedUntil, known := metadataTime(node, "snoozed_until")
+			if !ok || !known || !snoozedUntil.After(after) {
+				return false
+			}
+			continue
 		default:
 			// Check content first, then metadata
 			if val, ok := node.Content[key]; ok {
@@ -320,6 +364,71 @@ func matchesPredicate(node Node, predicate map[string]interface{}) bool {
 	return true
 }
 
+// asMap returns v as a map, converting structs via a JSON round-trip.
+func asMap(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	if v == nil {
+		return nil
+	}
+	var m map[string]interface{}
+	if b, err := json.Marshal(v); err == nil {
+		_ = json.Unmarshal(b, &m)
+	}
+	return m
+}
+
+// hasTag reports whether the node's behavior content carries tag.
+func hasTag(node Node, tag string) bool {
+	for _, t := range utils.GetStringSlice(asMap(node.Content["content"]), "tags") {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// nodeProvenance returns the node's provenance, which may be kept in its
+// metadata or its content.
+func nodeProvenance(node Node) map[string]interface{} {
+	if p := asMap(node.Metadata["provenance"]); p != nil {
+		return p
+	}
+	return asMap(node.Content["provenance"])
+}
+
+// nodeCreatedAt returns when the node's behavior was created, from its
+// provenance or else its stats.
+func nodeCreatedAt(node Node) (time.Time, bool) {
+	for _, m := range []map[string]interface{}{nodeProvenance(node), asMap(node.Metadata["stats"])} {
+		switch v := m["created_at"].(type) {
+		case time.Time:
+			if !v.IsZero() {
+				return v, true
+			}
+		case string:
+			if t, err := time.Parse(time.RFC3339Nano, v); err == nil && !t.IsZero() {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// metadataTime returns the time recorded under key in the node's metadata.
+func metadataTime(node Node, key string) (time.Time, bool) {
+	switch v := node.Metadata[key].(type) {
+	case time.Time:
+		return v, true
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
 // edgeKindMatches checks if an edge kind is in the allowed list.
 func edgeKindMatches(kind EdgeKind, allowed []EdgeKind) bool {
 	if len(allowed) == 0 {
@@ -5,9 +5,11 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"sync"
 
 	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/tracing"
 )
 
 // StoreScope is an alias for constants.Scope for backward compatibility.
@@ -36,27 +38,50 @@ type MultiGraphStore struct {
 // NewMultiGraphStore creates a MultiGraphStore with local and global stores.
 // projectRoot is used for the local store path.
 // AddNode defaults to global; use AddNodeToScope for explicit routing.
+// When FLOOP_CHAOS is set, both stores are wrapped in a ChaosGraphStore;
+// when tracing is enabled, in a TracedGraphStore.
+//
+// Each store is opened on its first operation, so commands only open the
+// stores they touch and a store that fails to open reports the error then.
 func NewMultiGraphStore(projectRoot string) (*MultiGraphStore, error) {
-	// Create local store (SQLite-backed with JSONL export)
-	localStore, err := NewSQLiteGraphStore(projectRoot)
+	return NewMultiGraphStoreWithBackend(projectRoot, BackendSQLite)
+}
+
+// NewMultiGraphStoreWithBackend is NewMultiGraphStore with each store
+// opened by backend (see OpenGraphStore).
+func NewMultiGraphStoreWithBackend(projectRoot string, backend Backend) (*MultiGraphStore, error) {
+	chaos, err := ChaosConfigFromEnv()
 	if err != nil {
-		return nil, fmt.Errorf("failed to create local store: %w", err)
+		return nil, fmt.Errorf("invalid %s: %w", EnvChaos, err)
 	}
 
-	// Create global store (at $HOME/.floop/)
+	// Resolve the global store location ($HOME/.floop/) up front so a
+	// missing home directory is still reported here.
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get home directory: %w", err)
 	}
 
-	globalStore, err := NewSQLiteGraphStore(homeDir)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create global store: %w", err)
+	open := func(root, name string) GraphStore {
+		var s GraphStore = NewLazyGraphStore(func() (GraphStore, error) {
+			s, err := OpenGraphStore(root, backend)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create %s store: %w", name, err)
+			}
+			return s, nil
+		})
+		if chaos.Enabled() {
+			s = NewChaosGraphStore(s, chaos)
+		}
+		if tracing.Enabled() {
+			s = NewTracedGraphStore(s, name)
+		}
+		return s
 	}
 
 	return &MultiGraphStore{
-		localStore:  localStore,
-		globalStore: globalStore,
+		localStore:  open(projectRoot, "local"),
+		globalStore: open(homeDir, "global"),
 	}, nil
 }
 
@@ -197,6 +222,92 @@ func (m *MultiGraphStore) QueryNodes(ctx context.Context, predicate map[string]i
 	return mergeNodes(localResult.nodes, globalResult.nodes), nil
 }
 
+// QueryNodesPage pages through both stores in ID order, with local winning
+// on conflicts. Each store contributes at most one page, which is enough to
+// fill the merged page.
+func (m *MultiGraphStore) QueryNodesPage(ctx context.Context, predicate map[string]interface{}, opts PageOptions) (NodePage, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	local, err := QueryPage(ctx, m.localStore, predicate, opts)
+	if err != nil {
+		return NodePage{}, fmt.Errorf("local query failed: %w", err)
+	}
+	global, err := QueryPage(ctx, m.globalStore, predicate, opts)
+	if err != nil {
+		return NodePage{}, fmt.Errorf("global query failed: %w", err)
+	}
+
+	page := pageNodes(mergeNodes(local.Nodes, global.Nodes), PageOptions{Limit: opts.Limit})
+	if page.NextCursor == "" && (local.NextCursor != "" || global.NextCursor != "") && len(page.Nodes) > 0 {
+		// Either store may hold more nodes beyond this page
+		page.NextCursor = page.Nodes[len(page.Nodes)-1].ID
+	}
+	return page, nil
+}
+
+// BatchAdd adds nodes to the global store, as AddNode does, and routes
+// each edge like AddEdge, counting the new nodes as global. Each store's
+// share is written in one batch; the global batch is written first.
+func (m *MultiGraphStore) BatchAdd(ctx context.Context, nodes []Node, edges []Edge) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	nodes = append([]Node(nil), nodes...)
+	added := make(map[string]bool, len(nodes))
+	for i := range nodes {
+		if nodes[i].Metadata == nil {
+			nodes[i].Metadata = make(map[string]interface{})
+		}
+		nodes[i].Metadata["scope"] = string(constants.ScopeGlobal)
+		added[nodes[i].ID] = true
+	}
+
+	var localEdges, globalEdges []Edge
+	for _, edge := range edges {
+		srcLocal, srcGlobal, err := m.locate(ctx, edge.Source)
+		if err != nil {
+			return err
+		}
+		tgtLocal, tgtGlobal, err := m.locate(ctx, edge.Target)
+		if err != nil {
+			return err
+		}
+		srcGlobal = srcGlobal || added[edge.Source]
+		tgtGlobal = tgtGlobal || added[edge.Target]
+
+		switch {
+		case srcLocal && tgtLocal:
+			localEdges = append(localEdges, edge)
+		case (srcLocal || srcGlobal) && (tgtLocal || tgtGlobal):
+			globalEdges = append(globalEdges, edge)
+		default:
+			return fmt.Errorf("source or target not found in either store: source=%s, target=%s", edge.Source, edge.Target)
+		}
+	}
+
+	if err := BatchAdd(ctx, m.globalStore, nodes, globalEdges); err != nil {
+		return fmt.Errorf("global batch failed: %w", err)
+	}
+	if err := BatchAdd(ctx, m.localStore, nil, localEdges); err != nil {
+		return fmt.Errorf("local batch failed: %w", err)
+	}
+	return nil
+}
+
+// locate reports which stores hold the node with the given ID.
+func (m *MultiGraphStore) locate(ctx context.Context, id string) (inLocal, inGlobal bool, err error) {
+	local, err := m.localStore.GetNode(ctx, id)
+	if err != nil {
+		return false, false, fmt.Errorf("error checking local store for %s: %w", id, err)
+	}
+	global, err := m.globalStore.GetNode(ctx, id)
+	if err != nil {
+		return false, false, fmt.Errorf("error checking global store for %s: %w", id, err)
+	}
+	return local != nil, global != nil, nil
+}
+
 // AddEdge adds an edge, routing it based on endpoint locations:
 //   - Both endpoints in same store → store edge there
 //   - Endpoints in different stores → store edge in global store
@@ -415,6 +526,15 @@ func (m *MultiGraphStore) RecordActivationHit(ctx context.Context, behaviorID st
 	})
 }
 
+// RecordSessionActivation delegates to whichever store contains the behavior.
+func (m *MultiGraphStore) RecordSessionActivation(ctx context.Context, behaviorID, sessionID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withExtendedStore(ctx, behaviorID, func(es ExtendedGraphStore) error {
+		return es.RecordSessionActivation(ctx, behaviorID, sessionID)
+	})
+}
+
 // RecordConfirmed delegates to whichever store contains the behavior.
 func (m *MultiGraphStore) RecordConfirmed(ctx context.Context, behaviorID string) error {
 	m.mu.Lock()
@@ -424,6 +544,28 @@ func (m *MultiGraphStore) RecordConfirmed(ctx context.Context, behaviorID string
 	})
 }
 
+// RecordFollowed delegates to whichever store contains the behavior.
+func (m *MultiGraphStore) RecordFollowed(ctx context.Context, behaviorID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.withExtendedStore(ctx, behaviorID, func(es ExtendedGraphStore) error {
+		return es.RecordFollowed(ctx, behaviorID)
+	})
+}
+
+// BehaviorHistory delegates to whichever store contains the behavior.
+func (m *MultiGraphStore) BehaviorHistory(ctx context.Context, behaviorID string) ([]Revision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	var revisions []Revision
+	err := m.withExtendedStore(ctx, behaviorID, func(es ExtendedGraphStore) error {
+		var err error
+		revisions, err = es.BehaviorHistory(ctx, behaviorID)
+		return err
+	})
+	return revisions, err
+}
+
 // RecordOverridden delegates to whichever store contains the behavior.
 func (m *MultiGraphStore) RecordOverridden(ctx context.Context, behaviorID string) error {
 	m.mu.Lock()
@@ -485,14 +627,14 @@ func (m *MultiGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]Validati
 	// Collect IDs from both stores for cross-store resolution
 	var localIDs, globalIDs map[string]bool
 
-	if sqlStore, ok := m.localStore.(*SQLiteGraphStore); ok {
+	if sqlStore, ok := unwrapSQLite(m.localStore); ok {
 		var err error
 		localIDs, err = sqlStore.AllBehaviorIDs(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get local behavior IDs: %w", err)
 		}
 	}
-	if sqlStore, ok := m.globalStore.(*SQLiteGraphStore); ok {
+	if sqlStore, ok := unwrapSQLite(m.globalStore); ok {
 		var err error
 		globalIDs, err = sqlStore.AllBehaviorIDs(ctx)
 		if err != nil {
@@ -503,7 +645,7 @@ func (m *MultiGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]Validati
 	var allErrors []ValidationError
 
 	// Local store: validate with global IDs as external (in case of any cross-store edges)
-	if sqlStore, ok := m.localStore.(*SQLiteGraphStore); ok {
+	if sqlStore, ok := unwrapSQLite(m.localStore); ok {
 		errors, err := sqlStore.ValidateWithExternalIDs(ctx, globalIDs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to validate local store: %w", err)
@@ -515,7 +657,7 @@ func (m *MultiGraphStore) ValidateBehaviorGraph(ctx context.Context) ([]Validati
 	}
 
 	// Global store: validate with local IDs as external (cross-store edges reference local behaviors)
-	if sqlStore, ok := m.globalStore.(*SQLiteGraphStore); ok {
+	if sqlStore, ok := unwrapSQLite(m.globalStore); ok {
 		errors, err := sqlStore.ValidateWithExternalIDs(ctx, localIDs)
 		if err != nil {
 			return nil, fmt.Errorf("failed to validate global store: %w", err)
@@ -585,6 +727,70 @@ func (m *MultiGraphStore) GetBehaviorIDsWithoutEmbeddings(ctx context.Context) (
 	return all, nil
 }
 
+// SearchText searches both stores and merges the matches by score. A
+// behavior found in both is reported once, with its local score.
+func (m *MultiGraphStore) SearchText(ctx context.Context, query string, matchAll bool, limit int) ([]TextMatch, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	var all []TextMatch
+	if ts, ok := m.localStore.(TextSearcher); ok {
+		matches, err := ts.SearchText(ctx, query, matchAll, limit)
+		if err != nil {
+			return nil, fmt.Errorf("local SearchText: %w", err)
+		}
+		for _, match := range matches {
+			seen[match.BehaviorID] = true
+		}
+		all = append(all, matches...)
+	}
+	if ts, ok := m.globalStore.(TextSearcher); ok {
+		matches, err := ts.SearchText(ctx, query, matchAll, limit)
+		if err != nil {
+			return nil, fmt.Errorf("global SearchText: %w", err)
+		}
+		for _, match := range matches {
+			if !seen[match.BehaviorID] {
+				all = append(all, match)
+			}
+		}
+	}
+
+	sort.SliceStable(all, func(i, j int) bool { return all[i].Score > all[j].Score })
+	if limit > 0 && len(all) > limit {
+		all = all[:limit]
+	}
+	return all, nil
+}
+
+// ClearOrphanedEmbeddings clears embeddings of inactive behaviors in both
+// stores and returns the total cleared.
+func (m *MultiGraphStore) ClearOrphanedEmbeddings(ctx context.Context) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	type clearer interface {
+		ClearOrphanedEmbeddings(ctx context.Context) (int, error)
+	}
+	total := 0
+	if c, ok := m.localStore.(clearer); ok {
+		n, err := c.ClearOrphanedEmbeddings(ctx)
+		if err != nil {
+			return total, fmt.Errorf("local ClearOrphanedEmbeddings: %w", err)
+		}
+		total += n
+	}
+	if c, ok := m.globalStore.(clearer); ok {
+		n, err := c.ClearOrphanedEmbeddings(ctx)
+		if err != nil {
+			return total, fmt.Errorf("global ClearOrphanedEmbeddings: %w", err)
+		}
+		total += n
+	}
+	return total, nil
+}
+
 // withEmbeddingStore finds the store containing the given behavior and calls fn
 // with the EmbeddingStore that owns it. Tries local first, then global.
 // The caller must hold m.mu.
@@ -1381,6 +1381,26 @@ func TestMultiGraphStore_ExtendedStore_RecordOverridden(t *testing.T) {
 	}
 }
 
+func TestMultiGraphStore_ExtendedStore_RecordFollowed(t *testing.T) {
+	m := newTestMultiStore(t)
+	ctx := context.Background()
+
+	m.localStore.AddNode(ctx, Node{
+		ID:   "b1",
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"right": "do this",
+		},
+	})
+
+	if err := m.RecordFollowed(ctx, "b1"); err != nil {
+		t.Fatalf("RecordFollowed() error = %v", err)
+	}
+	if err := m.RecordFollowed(ctx, "nonexistent"); err == nil {
+		t.Error("RecordFollowed() should error for non-existent behavior")
+	}
+}
+
 func TestMultiGraphStore_ExtendedStore_TouchEdges(t *testing.T) {
 	m := newTestMultiStore(t)
 	ctx := context.Background()
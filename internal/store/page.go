@@ -0,0 +1,93 @@
+package store
+
+import (
+	"context"
+	"iter"
+	"sort"
+)
+
+// PageOptions selects one page of a node query. Pages are ordered by node
+// ID, so a cursor stays valid while nodes are added or removed.
+type PageOptions struct {
+	// Limit is the maximum number of nodes in the page. Values below 1
+	// return every remaining node.
+	Limit int
+
+	// Cursor resumes after the page it came from (NodePage.NextCursor).
+	// Empty starts at the first node.
+	Cursor string
+}
+
+// NodePage is one page of a node query.
+type NodePage struct {
+	Nodes []Node
+
+	// NextCursor is passed as PageOptions.Cursor to fetch the following
+	// page; it is empty on the last page.
+	NextCursor string
+}
+
+// NodePager is implemented by stores that can page through query results
+// without loading every match.
+type NodePager interface {
+	// QueryNodesPage returns the page of nodes matching predicate (see
+	// GraphStore.QueryNodes) selected by opts.
+	QueryNodesPage(ctx context.Context, predicate map[string]interface{}, opts PageOptions) (NodePage, error)
+}
+
+// Compile-time interface checks.
+var (
+	_ NodePager = (*SQLiteGraphStore)(nil)
+	_ NodePager = (*MultiGraphStore)(nil)
+)
+
+// QueryPage returns one page of the nodes in gs matching predicate. Stores
+// that are not NodePagers are queried in full and paged in memory.
+func QueryPage(ctx context.Context, gs GraphStore, predicate map[string]interface{}, opts PageOptions) (NodePage, error) {
+	if p, ok := gs.(NodePager); ok {
+		return p.QueryNodesPage(ctx, predicate, opts)
+	}
+	nodes, err := gs.QueryNodes(ctx, predicate)
+	if err != nil {
+		return NodePage{}, err
+	}
+	return pageNodes(nodes, opts), nil
+}
+
+// Nodes iterates over the nodes in gs matching predicate in ID order,
+// fetching pageSize at a time. Iteration stops after the first error,
+// which is yielded with a zero Node.
+func Nodes(ctx context.Context, gs GraphStore, predicate map[string]interface{}, pageSize int) iter.Seq2[Node, error] {
+	return func(yield func(Node, error) bool) {
+		opts := PageOptions{Limit: pageSize}
+		for {
+			page, err := QueryPage(ctx, gs, predicate, opts)
+			if err != nil {
+				yield(Node{}, err)
+				return
+			}
+			for _, node := range page.Nodes {
+				if !yield(node, nil) {
+					return
+				}
+			}
+			if page.NextCursor == "" {
+				return
+			}
+			opts.Cursor = page.NextCursor
+		}
+	}
+}
+
+// pageNodes selects the page opts describes from nodes, which it sorts by
+// ID in place.
+func pageNodes(nodes []Node, opts PageOptions) NodePage {
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	start := sort.Search(len(nodes), func(i int) bool { return nodes[i].ID > opts.Cursor })
+	nodes = nodes[start:]
+	if opts.Limit < 1 || len(nodes) <= opts.Limit {
+		return NodePage{Nodes: nodes}
+	}
+	nodes = nodes[:opts.Limit]
+	return NodePage{Nodes: nodes, NextCursor: nodes[len(nodes)-1].ID}
+}
@@ -0,0 +1,111 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// pageTestNode returns a behavior node with canonical content unique to id.
+func pageTestNode(id string) Node {
+	return Node{
+		ID:   id,
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "behavior " + id},
+		},
+	}
+}
+
+func TestQueryPage(t *testing.T) {
+	ctx := context.Background()
+	sqliteStore, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+	multi := newTestMultiStore(t)
+
+	stores := map[string]GraphStore{
+		"sqlite": sqliteStore,
+		"memory": NewInMemoryGraphStore(),
+		"multi":  multi,
+	}
+	for _, s := range stores {
+		for i := 7; i >= 1; i-- {
+			mustAddNode(t, s, ctx, pageTestNode(fmt.Sprintf("b%d", i)))
+		}
+		mustAddNode(t, s, ctx, Node{ID: "c1", Kind: NodeKindCorrection, Content: map[string]interface{}{"name": "c1"}})
+	}
+	// The multi store holds b1 in both stores; it is still listed once
+	mustAddNode(t, multi.globalStore, ctx, pageTestNode("b1"))
+	mustAddNode(t, multi.globalStore, ctx, pageTestNode("b8"))
+
+	for name, s := range stores {
+		t.Run(name, func(t *testing.T) {
+			want := "b1,b2,b3,b4,b5,b6,b7"
+			if name == "multi" {
+				want += ",b8"
+			}
+
+			var pages []string
+			opts := PageOptions{Limit: 3}
+			for {
+				page, err := QueryPage(ctx, s, map[string]interface{}{"kind": "behavior"}, opts)
+				if err != nil {
+					t.Fatalf("QueryPage() error = %v", err)
+				}
+				if len(page.Nodes) > 3 {
+					t.Fatalf("QueryPage() returned %d nodes, limit 3", len(page.Nodes))
+				}
+				for _, n := range page.Nodes {
+					pages = append(pages, n.ID)
+				}
+				if page.NextCursor == "" {
+					break
+				}
+				opts.Cursor = page.NextCursor
+			}
+			if got := strings.Join(pages, ","); got != want {
+				t.Errorf("paged = %s, want %s", got, want)
+			}
+
+			var iterated []string
+			for node, err := range Nodes(ctx, s, map[string]interface{}{"kind": "behavior"}, 2) {
+				if err != nil {
+					t.Fatalf("Nodes() error = %v", err)
+				}
+				iterated = append(iterated, node.ID)
+			}
+			if got := strings.Join(iterated, ","); got != want {
+				t.Errorf("Nodes() = %s, want %s", got, want)
+			}
+
+			page, err := QueryPage(ctx, s, map[string]interface{}{"kind": "behavior"}, PageOptions{Cursor: "b5"})
+			if err != nil {
+				t.Fatalf("QueryPage() error = %v", err)
+			}
+			if len(page.Nodes) == 0 || page.Nodes[0].ID != "b6" || page.NextCursor != "" {
+				t.Errorf("QueryPage(cursor b5, no limit) = %d nodes from %v, next %q", len(page.Nodes), page.Nodes, page.NextCursor)
+			}
+		})
+	}
+}
+
+func TestNodes_StopsEarly(t *testing.T) {
+	ctx := context.Background()
+	s := NewInMemoryGraphStore()
+	for i := 1; i <= 5; i++ {
+		mustAddNode(t, s, ctx, pageTestNode(fmt.Sprintf("b%d", i)))
+	}
+	count := 0
+	for range Nodes(ctx, s, nil, 2) {
+		count++
+		if count == 3 {
+			break
+		}
+	}
+	if count != 3 {
+		t.Errorf("iterated %d nodes after break, want 3", count)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 )
 
 // GlobalFloopPath returns the path to the global .floop directory.
@@ -45,8 +47,24 @@ floop.db
 floop.db-shm
 floop.db-wal
 
+# Lock shared by concurrent floop processes
+floop.lock
+
 # Audit logs (runtime data, not version controlled)
 audit.jsonl
+
+# Git hook records (see 'floop hooks install')
+git-pending.json
+git-commits.jsonl
+
+# Learn operation log (see 'floop undo')
+oplog.jsonl
+
+# Cached activation results (see 'floop active')
+cache/
+
+# Local usage metrics (see 'floop insights')
+metrics.jsonl
 `
 
 // EnsureGitignore creates a .gitignore in the given .floop directory if one
@@ -62,3 +80,38 @@ func EnsureGitignore(floopDir string) error {
 	}
 	return nil
 }
+
+// NestedFloopDirs returns the directories between projectRoot and filePath
+// that hold their own .floop store, such as services/api in a monorepo.
+// filePath is relative to projectRoot or absolute; a file outside the
+// project has no nested stores. The directories are slash-separated,
+// relative to projectRoot, and ordered outermost first. The project root's
+// own store is not included.
+func NestedFloopDirs(projectRoot, filePath string) []string {
+	if filePath == "" {
+		return nil
+	}
+	rel := filePath
+	if filepath.IsAbs(filePath) {
+		absRoot, err := filepath.Abs(projectRoot)
+		if err != nil {
+			return nil
+		}
+		if rel, err = filepath.Rel(absRoot, filePath); err != nil {
+			return nil
+		}
+	}
+	rel = filepath.Clean(rel)
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return nil
+	}
+
+	var dirs []string
+	for dir := filepath.Dir(rel); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+		if info, err := os.Stat(LocalFloopPath(filepath.Join(projectRoot, dir))); err == nil && info.IsDir() {
+			dirs = append(dirs, filepath.ToSlash(dir))
+		}
+	}
+	slices.Reverse(dirs)
+	return dirs
+}
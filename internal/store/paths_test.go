@@ -160,3 +160,33 @@ func TestEnsureGlobalFloopDir(t *testing.T) {
 		})
 	}
 }
+
+func TestNestedFloopDirs(t *testing.T) {
+	root := t.TempDir()
+	for _, dir := range []string{".floop", "services/.floop", "services/api/.floop", "services/web"} {
+		if err := os.MkdirAll(filepath.Join(root, dir), 0700); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		file string
+		want []string
+	}{
+		{"root file", "main.go", nil},
+		{"nearest and ancestor", "services/api/handlers/user.go", []string{"services", "services/api"}},
+		{"sibling without store", "services/web/index.ts", []string{"services"}},
+		{"absolute path", filepath.Join(root, "services", "api", "main.go"), []string{"services", "services/api"}},
+		{"outside project", "../other/main.go", nil},
+		{"no file", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NestedFloopDirs(root, tt.file)
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("NestedFloopDirs(%q) = %v, want %v", tt.file, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,290 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// IDCollisionError is returned by Relocate when the destination store holds
+// a different behavior under the same ID.
+type IDCollisionError struct {
+	ID    string
+	Scope StoreScope
+}
+
+func (e *IDCollisionError) Error() string {
+	return fmt.Sprintf("%s store already has a different behavior with ID %s", e.Scope, e.ID)
+}
+
+// RelocateOptions configures Relocate.
+type RelocateOptions struct {
+	// NewID moves the behavior under a different ID, for when the
+	// destination already uses its ID. Edges and requires/overrides/conflicts
+	// references are rewritten to the new ID.
+	NewID string
+}
+
+// RelocateResult describes a behavior moved between stores.
+type RelocateResult struct {
+	// PreviousID is the behavior's ID in the source store.
+	PreviousID string `json:"previous_id"`
+	// ID is the behavior's ID in the destination store.
+	ID   string     `json:"id"`
+	From StoreScope `json:"from"`
+	To   StoreScope `json:"to"`
+	// Merged is set when the destination already held the same behavior
+	// (same ID with identical content, or identical content under another
+	// ID); the source copy was folded into it.
+	Merged bool `json:"merged"`
+	// Edges counts the edges re-homed or rewritten.
+	Edges int `json:"edges"`
+	// References counts behaviors whose requires/overrides/conflicts lists
+	// were rewritten to the new ID.
+	References int `json:"references"`
+}
+
+// Relocate moves a behavior from one store to the other (to is ScopeLocal
+// or ScopeGlobal). The node keeps its content, metadata, stats, provenance,
+// and embedding. Its edges are re-homed using the same rule as AddEdge:
+// edges between two behaviors in one store live there, all others in the
+// global store.
+//
+// When the destination already has a behavior with the same ID, identical
+// content is treated as the same behavior and merged; otherwise an
+// *IDCollisionError is returned unless opts.NewID is set. A behavior whose
+// content already exists in the destination under another ID is merged
+// into it.
+func (m *MultiGraphStore) Relocate(ctx context.Context, id string, to StoreScope, opts RelocateOptions) (*RelocateResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var src, dst GraphStore
+	var from StoreScope
+	switch to {
+	case ScopeGlobal:
+		src, dst, from = m.localStore, m.globalStore, ScopeLocal
+	case ScopeLocal:
+		src, dst, from = m.globalStore, m.localStore, ScopeGlobal
+	default:
+		return nil, fmt.Errorf("invalid destination scope: %s (use ScopeLocal or ScopeGlobal)", to)
+	}
+
+	node, err := src.GetNode(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("error checking %s store: %w", from, err)
+	}
+	if node == nil {
+		existing, err := dst.GetNode(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("error checking %s store: %w", to, err)
+		}
+		if existing != nil {
+			return nil, fmt.Errorf("behavior %s is already in the %s store", id, to)
+		}
+		return nil, fmt.Errorf("behavior not found in %s store: %s", from, id)
+	}
+	if !isBehaviorKind(node.Kind) {
+		return nil, fmt.Errorf("%s is a %s node, not a behavior", id, node.Kind)
+	}
+
+	moved, err := portableNode(*node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to copy %s: %w", id, err)
+	}
+	moved.Metadata["scope"] = string(to)
+
+	result := &RelocateResult{PreviousID: id, ID: id, From: from, To: to}
+	if opts.NewID != "" {
+		result.ID = opts.NewID
+	}
+
+	existing, err := dst.GetNode(ctx, result.ID)
+	if err != nil {
+		return nil, fmt.Errorf("error checking %s store: %w", to, err)
+	}
+	if existing != nil {
+		if canonicalOf(*existing) != canonicalOf(moved) {
+			return nil, &IDCollisionError{ID: result.ID, Scope: to}
+		}
+		result.Merged = true
+	}
+
+	// Collect edges before the source node, and its edges, are deleted.
+	var edges []Edge
+	for _, s := range []GraphStore{src, dst} {
+		e, err := s.GetEdges(ctx, id, DirectionBoth, "")
+		if err != nil {
+			return nil, fmt.Errorf("failed to get edges for %s: %w", id, err)
+		}
+		edges = append(edges, e...)
+	}
+
+	if !result.Merged {
+		moved.ID = result.ID
+		if _, err := dst.AddNode(ctx, moved); err != nil {
+			var dup *DuplicateContentError
+			if !errors.As(err, &dup) {
+				return nil, fmt.Errorf("failed to add %s to %s store: %w", result.ID, to, err)
+			}
+			result.ID = dup.ExistingID
+			result.Merged = true
+		}
+	}
+	if !result.Merged {
+		if err := copyEmbedding(ctx, src, dst, id, result.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := src.DeleteNode(ctx, id); err != nil {
+		return nil, fmt.Errorf("failed to remove %s from %s store: %w", id, from, err)
+	}
+
+	for _, e := range mergeEdges(edges, nil) {
+		for _, s := range []GraphStore{m.localStore, m.globalStore} {
+			if err := s.RemoveEdge(ctx, e.Source, e.Target, e.Kind); err != nil {
+				return nil, fmt.Errorf("failed to remove edge %s -> %s: %w", e.Source, e.Target, err)
+			}
+		}
+		if e.Source == id {
+			e.Source = result.ID
+		}
+		if e.Target == id {
+			e.Target = result.ID
+		}
+		if e.Source == e.Target {
+			continue
+		}
+		if err := m.placeEdge(ctx, e); err != nil {
+			return nil, err
+		}
+		result.Edges++
+	}
+
+	if result.ID != id {
+		n, err := m.rewriteReferences(ctx, id, result.ID)
+		if err != nil {
+			return nil, err
+		}
+		result.References = n
+	}
+	return result, nil
+}
+
+// placeEdge adds an edge to the store AddEdge would choose. Unlike AddEdge
+// it keeps edges whose other endpoint is in neither store (a behavior in
+// another project's local store) in the global store. The caller must hold
+// m.mu.
+func (m *MultiGraphStore) placeEdge(ctx context.Context, edge Edge) error {
+	in := func(s GraphStore, id string) (bool, error) {
+		n, err := s.GetNode(ctx, id)
+		return n != nil, err
+	}
+	srcLocal, err := in(m.localStore, edge.Source)
+	if err != nil {
+		return fmt.Errorf("error checking local store for source: %w", err)
+	}
+	tgtLocal, err := in(m.localStore, edge.Target)
+	if err != nil {
+		return fmt.Errorf("error checking local store for target: %w", err)
+	}
+	if srcLocal && tgtLocal {
+		return m.localStore.AddEdge(ctx, edge)
+	}
+	return m.globalStore.AddEdge(ctx, edge)
+}
+
+// rewriteReferences replaces oldID with newID in the requires, overrides,
+// and conflicts lists of behaviors in both stores. Returns the number of
+// behaviors changed. The caller must hold m.mu.
+func (m *MultiGraphStore) rewriteReferences(ctx context.Context, oldID, newID string) (int, error) {
+	changed := 0
+	for _, s := range []GraphStore{m.localStore, m.globalStore} {
+		nodes, err := s.QueryNodes(ctx, map[string]interface{}{})
+		if err != nil {
+			return changed, fmt.Errorf("failed to query nodes: %w", err)
+		}
+		for _, n := range nodes {
+			touched := false
+			for _, field := range []string{"requires", "overrides", "conflicts"} {
+				list, ok := n.Content[field].([]interface{})
+				if !ok {
+					continue
+				}
+				for i, v := range list {
+					if v == oldID {
+						list[i] = newID
+						touched = true
+					}
+				}
+			}
+			if !touched {
+				continue
+			}
+			if err := s.UpdateNode(ctx, n); err != nil {
+				return changed, fmt.Errorf("failed to update references in %s: %w", n.ID, err)
+			}
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// copyEmbedding copies the stored embedding for id in src to newID in dst,
+// when both stores persist embeddings and src has one.
+func copyEmbedding(ctx context.Context, src, dst GraphStore, id, newID string) error {
+	srcEmb, ok := src.(EmbeddingStore)
+	if !ok {
+		return nil
+	}
+	dstEmb, ok := dst.(EmbeddingStore)
+	if !ok {
+		return nil
+	}
+	all, err := srcEmb.GetAllEmbeddings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read embeddings: %w", err)
+	}
+	for _, e := range all {
+		if e.BehaviorID == id {
+			if err := dstEmb.StoreEmbedding(ctx, newID, e.Embedding, e.ModelName); err != nil {
+				return fmt.Errorf("failed to copy embedding for %s: %w", id, err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// portableNode returns a deep copy of n with values in the shape a JSONL
+// import produces (numbers as float64, times as RFC 3339 strings), which is
+// what AddNode reads back without loss.
+func portableNode(n Node) (Node, error) {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return Node{}, err
+	}
+	var out Node
+	if err := json.Unmarshal(data, &out); err != nil {
+		return Node{}, err
+	}
+	if out.Content == nil {
+		out.Content = make(map[string]interface{})
+	}
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]interface{})
+	}
+	return out, nil
+}
+
+// canonicalOf returns a behavior node's canonical content.
+func canonicalOf(n Node) string {
+	if c, ok := n.Content["content"].(map[string]interface{}); ok {
+		if s, ok := c["canonical"].(string); ok {
+			return s
+		}
+	}
+	return ""
+}
@@ -0,0 +1,147 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func relocateNode(id, canonical string) Node {
+	return Node{
+		ID:   id,
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": canonical},
+			"provenance": map[string]interface{}{
+				"source_type":   "learned",
+				"correction_id": "c-1",
+				"created_at":    "2026-01-02T03:04:05Z",
+			},
+		},
+		Metadata: map[string]interface{}{
+			"confidence": 0.8,
+			"priority":   3.0,
+			"stats":      map[string]interface{}{"times_activated": 7.0},
+		},
+	}
+}
+
+func TestMultiGraphStore_Relocate(t *testing.T) {
+	m := newTestMultiStore(t)
+	ctx := context.Background()
+
+	mustAddNode(t, m.localStore, ctx, relocateNode("mover", "Use table-driven tests"))
+	mustAddNode(t, m.localStore, ctx, relocateNode("stays-local", "Run go vet before commits"))
+	mustAddNode(t, m.globalStore, ctx, relocateNode("global", "Wrap errors with %w"))
+	mustAddEdge(t, m.localStore, ctx, Edge{Source: "mover", Target: "stays-local", Kind: EdgeKindRequires, Weight: 1, CreatedAt: time.Now()})
+	mustAddEdge(t, m.globalStore, ctx, Edge{Source: "global", Target: "mover", Kind: EdgeKindSimilarTo, Weight: 0.8, CreatedAt: time.Now()})
+	if err := m.localStore.(EmbeddingStore).StoreEmbedding(ctx, "mover", []float32{1, 0}, "test-model"); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := m.Relocate(ctx, "mover", ScopeGlobal, RelocateOptions{})
+	if err != nil {
+		t.Fatalf("Relocate(global) error = %v", err)
+	}
+	if result.ID != "mover" || result.From != ScopeLocal || result.Merged || result.Edges != 2 {
+		t.Errorf("result = %+v", result)
+	}
+	if mustGetNode(t, m.localStore, ctx, "mover") != nil {
+		t.Error("mover still in local store")
+	}
+	node := mustGetNode(t, m.globalStore, ctx, "mover")
+	if node == nil {
+		t.Fatal("mover not in global store")
+	}
+	if node.Metadata["scope"] != "global" || node.Metadata["priority"] != 3 {
+		t.Errorf("metadata = %v", node.Metadata)
+	}
+	if stats := node.Metadata["stats"].(map[string]interface{}); stats["times_activated"] != 7 {
+		t.Errorf("stats = %v, want times_activated 7", stats)
+	}
+	prov := node.Content["provenance"].(map[string]interface{})
+	if prov["correction_id"] != "c-1" || prov["created_at"] == nil {
+		t.Errorf("provenance = %v", prov)
+	}
+	embeddings, _ := m.globalStore.(EmbeddingStore).GetAllEmbeddings(ctx)
+	if len(embeddings) != 1 || embeddings[0].BehaviorID != "mover" {
+		t.Errorf("global embeddings = %+v", embeddings)
+	}
+
+	// mover -> stays-local is now cross-store, so it lives in the global store.
+	if e := mustGetEdges(t, m.localStore, ctx, "stays-local", DirectionBoth, ""); len(e) != 0 {
+		t.Errorf("local edges = %+v, want none", e)
+	}
+	if e := mustGetEdges(t, m.globalStore, ctx, "mover", DirectionBoth, ""); len(e) != 2 {
+		t.Errorf("global edges = %+v, want 2", e)
+	}
+
+	// Demoting moves the edge between two local behaviors back.
+	if _, err := m.Relocate(ctx, "mover", ScopeLocal, RelocateOptions{}); err != nil {
+		t.Fatalf("Relocate(local) error = %v", err)
+	}
+	if e := mustGetEdges(t, m.localStore, ctx, "mover", DirectionBoth, ""); len(e) != 1 || e[0].Target != "stays-local" {
+		t.Errorf("local edges after demote = %+v", e)
+	}
+	if e := mustGetEdges(t, m.globalStore, ctx, "mover", DirectionBoth, ""); len(e) != 1 || e[0].Source != "global" {
+		t.Errorf("global edges after demote = %+v", e)
+	}
+
+	if _, err := m.Relocate(ctx, "mover", ScopeLocal, RelocateOptions{}); err == nil {
+		t.Error("expected error relocating to the store that already has it")
+	}
+	if _, err := m.Relocate(ctx, "missing", ScopeGlobal, RelocateOptions{}); err == nil {
+		t.Error("expected error for a missing behavior")
+	}
+}
+
+func TestMultiGraphStore_Relocate_Collisions(t *testing.T) {
+	m := newTestMultiStore(t)
+	ctx := context.Background()
+
+	mustAddNode(t, m.localStore, ctx, relocateNode("shared-id", "Use uv for Python installs"))
+	mustAddNode(t, m.globalStore, ctx, relocateNode("shared-id", "Sign commits with GPG"))
+	referrer := relocateNode("referrer", "Pin Python dependencies")
+	referrer.Content["requires"] = []interface{}{"shared-id"}
+	mustAddNode(t, m.localStore, ctx, referrer)
+	mustAddEdge(t, m.localStore, ctx, Edge{Source: "referrer", Target: "shared-id", Kind: EdgeKindRequires, Weight: 1, CreatedAt: time.Now()})
+
+	_, err := m.Relocate(ctx, "shared-id", ScopeGlobal, RelocateOptions{})
+	var collision *IDCollisionError
+	if !errors.As(err, &collision) || collision.ID != "shared-id" || collision.Scope != ScopeGlobal {
+		t.Fatalf("Relocate() error = %v, want IDCollisionError", err)
+	}
+
+	result, err := m.Relocate(ctx, "shared-id", ScopeGlobal, RelocateOptions{NewID: "uv-installs"})
+	if err != nil {
+		t.Fatalf("Relocate(NewID) error = %v", err)
+	}
+	if result.ID != "uv-installs" || result.References != 1 || result.Edges != 1 {
+		t.Errorf("result = %+v", result)
+	}
+	if n := mustGetNode(t, m.globalStore, ctx, "shared-id"); canonicalOf(*n) != "Sign commits with GPG" {
+		t.Error("existing global behavior was overwritten")
+	}
+	if e := mustGetEdges(t, m.globalStore, ctx, "uv-installs", DirectionInbound, EdgeKindRequires); len(e) != 1 || e[0].Source != "referrer" {
+		t.Errorf("edges to renamed behavior = %+v", e)
+	}
+	if got := mustGetNode(t, m.localStore, ctx, "referrer").Content["requires"]; len(got.([]interface{})) != 1 || got.([]interface{})[0] != "uv-installs" {
+		t.Errorf("referrer requires = %v", got)
+	}
+
+	// Identical content under another ID merges into the existing behavior.
+	mustAddNode(t, m.localStore, ctx, relocateNode("local-copy", "Sign commits with GPG"))
+	result, err = m.Relocate(ctx, "local-copy", ScopeGlobal, RelocateOptions{})
+	if err != nil {
+		t.Fatalf("Relocate(duplicate) error = %v", err)
+	}
+	if !result.Merged || result.ID != "shared-id" {
+		t.Errorf("result = %+v, want merged into shared-id", result)
+	}
+	if mustGetNode(t, m.localStore, ctx, "local-copy") != nil {
+		t.Error("local copy not removed after merge")
+	}
+}
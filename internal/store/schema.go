@@ -10,7 +10,7 @@ import (
 )
 
 // SchemaVersion is the current schema version.
-const SchemaVersion = 10
+const SchemaVersion = 14
 
 // EventsTableDDL is the canonical DDL for the events table.
 // Both the initial schema and migrations reference this constant.
@@ -35,6 +35,66 @@ CREATE INDEX IF NOT EXISTS idx_events_timestamp ON events(timestamp);
 CREATE INDEX IF NOT EXISTS idx_events_project ON events(project_id);
 CREATE INDEX IF NOT EXISTS idx_events_consolidated ON events(consolidated)`
 
+// behaviorRevisionsDDL is the canonical DDL for the behavior_revisions table,
+// which keeps a snapshot of each behavior every time its kind or content
+// changes.
+const behaviorRevisionsDDL = `CREATE TABLE IF NOT EXISTS behavior_revisions (
+    behavior_id TEXT NOT NULL,
+    rev INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    content TEXT NOT NULL,  -- JSON of the node content, including when
+    confidence REAL,
+    recorded_at TEXT NOT NULL,
+    PRIMARY KEY (behavior_id, rev)
+)`
+
+// queryIndexesDDL is the canonical DDL for the indexes behind common node
+// and edge queries: node kind and behavior type, edge kind, and the pack a
+// behavior was installed from. The pack index is on the exact expression
+// QueryNodes filters with, so SQLite can use it.
+const queryIndexesDDL = `CREATE INDEX IF NOT EXISTS idx_behaviors_kind ON behaviors(kind);
+CREATE INDEX IF NOT EXISTS idx_behaviors_behavior_type ON behaviors(behavior_type);
+CREATE INDEX IF NOT EXISTS idx_behaviors_pack ON behaviors(json_extract(metadata_extra, '$.provenance.package'));
+CREATE INDEX IF NOT EXISTS idx_edges_kind ON edges(kind);
+CREATE INDEX IF NOT EXISTS idx_edges_target_kind ON edges(target, kind)`
+
+// behaviorsFTSDDL is the canonical DDL for the behaviors_fts full-text index
+// over behavior names, content, and tags, and the triggers that keep it in
+// sync. Each index row shares the rowid of its behaviors row.
+const behaviorsFTSDDL = `CREATE VIRTUAL TABLE IF NOT EXISTS behaviors_fts USING fts5(
+    name, canonical, summary, tags,
+    tokenize = 'porter unicode61'
+);
+
+-- INSERT OR REPLACE deletes the old row without firing delete triggers
+CREATE TRIGGER IF NOT EXISTS behaviors_fts_before_insert
+BEFORE INSERT ON behaviors
+BEGIN
+    DELETE FROM behaviors_fts WHERE rowid = (SELECT rowid FROM behaviors WHERE id = NEW.id);
+END;
+
+CREATE TRIGGER IF NOT EXISTS behaviors_fts_insert
+AFTER INSERT ON behaviors
+BEGIN
+    DELETE FROM behaviors_fts WHERE rowid = NEW.rowid;
+    INSERT INTO behaviors_fts (rowid, name, canonical, summary, tags)
+    VALUES (NEW.rowid, NEW.name, NEW.content_canonical, NEW.content_summary, NEW.content_tags);
+END;
+
+CREATE TRIGGER IF NOT EXISTS behaviors_fts_update
+AFTER UPDATE OF name, content_canonical, content_summary, content_tags ON behaviors
+BEGIN
+    DELETE FROM behaviors_fts WHERE rowid = OLD.rowid;
+    INSERT INTO behaviors_fts (rowid, name, canonical, summary, tags)
+    VALUES (NEW.rowid, NEW.name, NEW.content_canonical, NEW.content_summary, NEW.content_tags);
+END;
+
+CREATE TRIGGER IF NOT EXISTS behaviors_fts_delete
+AFTER DELETE ON behaviors
+BEGIN
+    DELETE FROM behaviors_fts WHERE rowid = OLD.rowid;
+END`
+
 // schemaV1 is the initial schema for the SQLite store.
 const schemaV1 = `
 -- Core behavior table (denormalized for single-query retrieval)
@@ -99,7 +159,9 @@ CREATE TABLE IF NOT EXISTS behavior_stats (
     times_overridden INTEGER DEFAULT 0,
     times_confirmed INTEGER DEFAULT 0,
     last_activated TEXT,
-    last_confirmed TEXT
+    last_confirmed TEXT,
+    sessions_activated INTEGER DEFAULT 0,
+    last_session_id TEXT
 );
 
 -- Corrections
@@ -180,6 +242,15 @@ CREATE TABLE IF NOT EXISTS consolidation_runs (
 CREATE INDEX IF NOT EXISTS idx_consolidation_runs_project ON consolidation_runs(project_id);
 CREATE INDEX IF NOT EXISTS idx_consolidation_runs_session ON consolidation_runs(session_id);
 
+-- Behavior revision history (V12)
+` + behaviorRevisionsDDL + `;
+
+-- Full-text index over behavior content (V13)
+` + behaviorsFTSDDL + `;
+
+-- Indexes for node and edge queries (V14)
+` + queryIndexesDDL + `;
+
 -- Schema version
 CREATE TABLE IF NOT EXISTS schema_version (
     version INTEGER PRIMARY KEY,
@@ -372,6 +443,26 @@ func migrateSchema(ctx context.Context, db *sql.DB, currentVersion int, projectI
 			return fmt.Errorf("migrate v9 to v10: %w", err)
 		}
 	}
+	if currentVersion < 11 {
+		if err := migrateV10ToV11(ctx, db); err != nil {
+			return fmt.Errorf("migrate v10 to v11: %w", err)
+		}
+	}
+	if currentVersion < 12 {
+		if err := migrateV11ToV12(ctx, db); err != nil {
+			return fmt.Errorf("migrate v11 to v12: %w", err)
+		}
+	}
+	if currentVersion < 13 {
+		if err := migrateV12ToV13(ctx, db); err != nil {
+			return fmt.Errorf("migrate v12 to v13: %w", err)
+		}
+	}
+	if currentVersion < 14 {
+		if err := migrateV13ToV14(ctx, db); err != nil {
+			return fmt.Errorf("migrate v13 to v14: %w", err)
+		}
+	}
 	return nil
 }
 
@@ -903,6 +994,131 @@ func migrateV9ToV10(ctx context.Context, db *sql.DB) error {
 	return tx.Commit()
 }
 
+// migrateV10ToV11 adds per-session activation tracking to behavior_stats:
+//   - sessions_activated: number of distinct sessions the behavior activated in
+//   - last_session_id: the session that last activated it
+func migrateV10ToV11(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Add new columns idempotently (safe if migration is retried after partial failure)
+	existingCols := make(map[string]bool)
+	colRows, err := tx.QueryContext(ctx, `PRAGMA table_info(behavior_stats)`)
+	if err != nil {
+		return fmt.Errorf("check behavior_stats columns: %w", err)
+	}
+	for colRows.Next() {
+		var cid int
+		var name, ctype string
+		var notnull, pk int
+		var dfltValue interface{}
+		if err := colRows.Scan(&cid, &name, &ctype, &notnull, &dfltValue, &pk); err != nil {
+			colRows.Close()
+			return fmt.Errorf("scan column info: %w", err)
+		}
+		existingCols[name] = true
+	}
+	colRows.Close()
+	if err := colRows.Err(); err != nil {
+		return fmt.Errorf("iterating column info: %w", err)
+	}
+
+	v11Columns := []struct {
+		name string
+		def  string
+	}{
+		{"sessions_activated", "INTEGER DEFAULT 0"},
+		{"last_session_id", "TEXT"},
+	}
+	for _, col := range v11Columns {
+		if !existingCols[col.name] {
+			if _, err := tx.ExecContext(ctx,
+				fmt.Sprintf(`ALTER TABLE behavior_stats ADD COLUMN %s %s`, col.name, col.def)); err != nil {
+				return fmt.Errorf("add %s column: %w", col.name, err)
+			}
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 11)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// migrateV11ToV12 creates the behavior_revisions table for behavior history.
+// Existing behaviors get their first revision the next time they are written.
+func migrateV11ToV12(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, behaviorRevisionsDDL); err != nil {
+		return fmt.Errorf("create behavior_revisions table: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 12)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// migrateV12ToV13 creates the behaviors_fts full-text index and its sync
+// triggers, and indexes existing behaviors.
+func migrateV12ToV13(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, behaviorsFTSDDL); err != nil {
+		return fmt.Errorf("create behaviors_fts: %w", err)
+	}
+	if err := rebuildTextIndex(ctx, tx); err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 13)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
+// migrateV13ToV14 adds indexes for node and edge queries.
+func migrateV13ToV14(ctx context.Context, db *sql.DB) error {
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, queryIndexesDDL); err != nil {
+		return fmt.Errorf("create query indexes: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		`INSERT INTO schema_version (version, applied_at) VALUES (?, datetime('now'))`, 14)
+	if err != nil {
+		return fmt.Errorf("record schema version: %w", err)
+	}
+
+	return tx.Commit()
+}
+
 // validateStructuralIntegrity checks for SQLite database corruption.
 // It only runs PRAGMA integrity_check — not foreign_key_check.
 // Use ValidateIntegrity for full validation including FK checks.
@@ -3,6 +3,7 @@ package store
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"testing"
 
 	_ "modernc.org/sqlite"
@@ -800,6 +801,108 @@ func TestMigrateV8ToV9_NoProjectID(t *testing.T) {
 	}
 }
 
+func TestMigrateV12ToV13_IndexesExistingBehaviors(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+
+	// Build a current schema, then roll it back to v12 by dropping the index.
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+	for _, stmt := range []string{
+		`DROP TRIGGER behaviors_fts_before_insert`,
+		`DROP TRIGGER behaviors_fts_insert`,
+		`DROP TRIGGER behaviors_fts_update`,
+		`DROP TRIGGER behaviors_fts_delete`,
+		`DROP TABLE behaviors_fts`,
+		`DELETE FROM schema_version WHERE version >= 13`,
+		`INSERT INTO behaviors (id, name, kind, content_canonical, created_at, updated_at)
+		 VALUES ('test-1', 'wrap-errors', 'behavior', 'Wrap errors with context', '2024-01-01', '2024-01-01')`,
+	} {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+
+	var id string
+	if err := db.QueryRowContext(ctx, `
+		SELECT b.id FROM behaviors_fts JOIN behaviors b ON b.rowid = behaviors_fts.rowid
+		WHERE behaviors_fts MATCH 'wrapping'`).Scan(&id); err != nil {
+		t.Fatalf("existing behavior not indexed: %v", err)
+	}
+	if id != "test-1" {
+		t.Errorf("matched %q, want test-1", id)
+	}
+}
+
+func TestMigrateV13ToV14_AddsQueryIndexes(t *testing.T) {
+	db, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	ctx := context.Background()
+
+	// Build a current schema, then roll it back to v13 by dropping the indexes.
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+	indexes := []string{"idx_behaviors_kind", "idx_behaviors_behavior_type", "idx_behaviors_pack", "idx_edges_kind", "idx_edges_target_kind"}
+	for _, name := range indexes {
+		if _, err := db.ExecContext(ctx, `DROP INDEX `+name); err != nil {
+			t.Fatalf("drop %s: %v", name, err)
+		}
+	}
+	if _, err := db.ExecContext(ctx, `DELETE FROM schema_version WHERE version = 14`); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := InitSchema(ctx, db); err != nil {
+		t.Fatalf("InitSchema failed: %v", err)
+	}
+	for _, name := range indexes {
+		var got string
+		if err := db.QueryRowContext(ctx, `SELECT name FROM sqlite_master WHERE type = 'index' AND name = ?`, name).Scan(&got); err != nil {
+			t.Errorf("index %s missing after migration: %v", name, err)
+		}
+	}
+
+	// The pack filter QueryNodes builds must be able to use its index
+	where, args, err := nodeFilter(map[string]interface{}{PredicatePack: "org/pack"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	rows, err := db.QueryContext(ctx, `EXPLAIN QUERY PLAN SELECT id FROM behaviors WHERE `+strings.Join(where, " AND "), args...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	var plan []string
+	for rows.Next() {
+		var id, parent, notUsed int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notUsed, &detail); err != nil {
+			t.Fatal(err)
+		}
+		plan = append(plan, detail)
+	}
+	if !strings.Contains(strings.Join(plan, "\n"), "idx_behaviors_pack") {
+		t.Errorf("pack filter does not use idx_behaviors_pack: %v", plan)
+	}
+}
+
 func TestInitSchema_FreshDB_HasEventsTable(t *testing.T) {
 	// Verify that a fresh database created from scratch includes the events table
 	// and the new behavior columns.
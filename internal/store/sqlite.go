@@ -3,6 +3,7 @@ package store
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"database/sql"
@@ -28,10 +29,13 @@ import (
 type SQLiteGraphStore struct {
 	mu        sync.RWMutex
 	db        *sql.DB
+	stmts     *stmtCache // prepared node and edge lookups
 	floopDir  string
 	dbPath    string
 	nodesFile string
 	edgesFile string
+	lockPath  string
+	lockWait  time.Duration
 }
 
 // DB returns the underlying *sql.DB for direct SQL access (e.g., persistRun).
@@ -41,7 +45,16 @@ func (s *SQLiteGraphStore) DB() *sql.DB {
 
 // NewSQLiteGraphStore creates a new SQLiteGraphStore rooted at projectRoot.
 // It creates the database at .floop/floop.db and auto-imports existing JSONL files.
+//
+// Other floop processes may use the same store: writes wait up to
+// FLOOP_LOCK_WAIT (see LockWaitFromEnv) for the database, and importing,
+// batch writes, and JSONL export hold .floop/floop.lock.
 func NewSQLiteGraphStore(projectRoot string) (*SQLiteGraphStore, error) {
+	lockWait, err := LockWaitFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
 	floopDir := filepath.Join(projectRoot, ".floop")
 
 	// Ensure .floop directory exists
@@ -58,8 +71,13 @@ func NewSQLiteGraphStore(projectRoot string) (*SQLiteGraphStore, error) {
 	nodesFile := filepath.Join(floopDir, "nodes.jsonl")
 	edgesFile := filepath.Join(floopDir, "edges.jsonl")
 
-	// Open database
-	db, err := sql.Open("sqlite", dbPath+"?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)")
+	// Open database. WAL lets readers run alongside a writer, and with it
+	// synchronous=NORMAL only syncs at checkpoints: a power loss may drop
+	// the last commits but never corrupts the database. busy_timeout makes
+	// a write wait for one in progress in another process instead of
+	// failing with SQLITE_BUSY.
+	dsn := fmt.Sprintf("%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(%d)", dbPath, lockWait.Milliseconds())
+	db, err := sql.Open("sqlite", dsn)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database: %w", err)
 	}
@@ -88,14 +106,17 @@ func NewSQLiteGraphStore(projectRoot string) (*SQLiteGraphStore, error) {
 
 	s := &SQLiteGraphStore{
 		db:        db,
+		stmts:     newStmtCache(db),
 		floopDir:  floopDir,
 		dbPath:    dbPath,
 		nodesFile: nodesFile,
 		edgesFile: edgesFile,
+		lockPath:  filepath.Join(floopDir, LockFile),
+		lockWait:  lockWait,
 	}
 
 	// Auto-import existing JSONL if database is empty or JSONL is newer
-	if err := s.autoImport(ctx); err != nil {
+	if err := s.withFileLock(ctx, s.autoImport); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("failed to auto-import JSONL: %w", err)
 	}
@@ -103,6 +124,17 @@ func NewSQLiteGraphStore(projectRoot string) (*SQLiteGraphStore, error) {
 	return s, nil
 }
 
+// withFileLock runs fn holding the store's lock file, so other floop
+// processes cannot interleave their own multi-step writes with it.
+func (s *SQLiteGraphStore) withFileLock(ctx context.Context, fn func(context.Context) error) error {
+	lock, err := AcquireLock(ctx, s.lockPath, s.lockWait)
+	if err != nil {
+		return err
+	}
+	defer lock.Release()
+	return fn(ctx)
+}
+
 // autoImport imports existing JSONL files if the database is empty or JSONL is newer.
 func (s *SQLiteGraphStore) autoImport(ctx context.Context) error {
 	// Check if database has any behaviors
@@ -128,10 +160,16 @@ func (s *SQLiteGraphStore) autoImport(ctx context.Context) error {
 			return fmt.Errorf("failed to stat nodes.jsonl: %w", err)
 		}
 
-		// If JSONL is older than DB, no need to import
-		if nodesInfo.ModTime().Before(dbInfo.ModTime()) {
+		// Import only a JSONL newer than the DB. Sync exports it just before
+		// the DB's final write, often within the same filesystem timestamp
+		// tick, so equal times mean it is already imported.
+		if !nodesInfo.ModTime().After(dbInfo.ModTime()) {
 			return nil
 		}
+	} else if info, err := os.Stat(s.nodesFile); err != nil || info.Size() == 0 {
+		// An empty store with no exported behaviors has nothing to import;
+		// skipping keeps read-only opens from rewriting the database.
+		return nil
 	}
 
 	// Import nodes.jsonl if it exists
@@ -165,9 +203,17 @@ func (s *SQLiteGraphStore) AddNode(ctx context.Context, node Node) (string, erro
 		return "", fmt.Errorf("node ID is required")
 	}
 
-	// Use addBehavior for all behavior-related kinds
+	// Use addBehavior for all behavior-related kinds, recording any state it
+	// replaces as well as the new one in the behavior's history.
 	if isBehaviorKind(node.Kind) {
-		return s.addBehavior(ctx, node)
+		if err := s.recordRevision(ctx, node.ID); err != nil {
+			return "", err
+		}
+		id, err := s.addBehavior(ctx, node)
+		if err != nil {
+			return "", err
+		}
+		return id, s.recordRevision(ctx, id)
 	}
 
 	// For non-behavior nodes, store in a generic way using the behaviors table
@@ -181,7 +227,9 @@ func isBehaviorKind(kind NodeKind) bool {
 	case NodeKindBehavior,
 		NodeKindForgotten,
 		NodeKindDeprecated,
-		NodeKindMerged:
+		NodeKindMerged,
+		NodeKindPending,
+		NodeKindDormant:
 		return true
 	default:
 		return false
@@ -192,6 +240,7 @@ func isBehaviorKind(kind NodeKind) bool {
 // allowing addBehaviorWith to operate within or outside a transaction.
 type dbQuerier interface {
 	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
 	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
 }
 
@@ -425,14 +474,16 @@ func (s *SQLiteGraphStore) addBehaviorWith(ctx context.Context, q dbQuerier, nod
 	timesConfirmed := utils.GetFloat64(stats, "times_confirmed", 0)
 	lastActivated := utils.GetString(stats, "last_activated", "")
 	lastConfirmed := utils.GetString(stats, "last_confirmed", "")
+	sessionsActivated := utils.GetFloat64(stats, "sessions_activated", 0)
+	lastSessionID := utils.GetString(stats, "last_session_id", "")
 
 	_, err = q.ExecContext(ctx, `
 		INSERT OR REPLACE INTO behavior_stats (
 			behavior_id, times_activated, times_followed, times_overridden, times_confirmed,
-			last_activated, last_confirmed
-		) VALUES (?, ?, ?, ?, ?, ?, ?)
+			last_activated, last_confirmed, sessions_activated, last_session_id
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`, node.ID, int(timesActivated), int(timesFollowed), int(timesOverridden), int(timesConfirmed),
-		nullString(lastActivated), nullString(lastConfirmed))
+		nullString(lastActivated), nullString(lastConfirmed), int(sessionsActivated), nullString(lastSessionID))
 	if err != nil {
 		return "", fmt.Errorf("failed to insert stats: %w", err)
 	}
@@ -474,10 +525,15 @@ func (s *SQLiteGraphStore) addGenericNodeWith(ctx context.Context, q dbQuerier,
 
 // UpdateNode updates an existing node in the store.
 // The existence check, when-condition delete, and re-insert are atomic.
+// A behavior's state before and after the update is kept in its history.
 func (s *SQLiteGraphStore) UpdateNode(ctx context.Context, node Node) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	if err := s.recordRevision(ctx, node.ID); err != nil {
+		return err
+	}
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("begin transaction: %w", err)
@@ -509,7 +565,10 @@ func (s *SQLiteGraphStore) UpdateNode(ctx context.Context, node Node) error {
 		return err
 	}
 
-	return tx.Commit()
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	return s.recordRevision(ctx, node.ID)
 }
 
 // GetNode retrieves a node by ID. Returns nil if not found.
@@ -522,6 +581,11 @@ func (s *SQLiteGraphStore) GetNode(ctx context.Context, id string) (*Node, error
 
 // getNodeUnlocked retrieves a node without locking (caller must hold lock).
 func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Node, error) {
+	return s.getNodeWith(ctx, s.stmts, id)
+}
+
+// getNodeWith retrieves a node using the provided querier (DB or Tx).
+func (s *SQLiteGraphStore) getNodeWith(ctx context.Context, q dbQuerier, id string) (*Node, error) {
 	var (
 		name, kind                                    string
 		behaviorType                                  sql.NullString
@@ -536,7 +600,7 @@ func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Nod
 		createdAt, updatedAt                          string
 	)
 
-	err := s.db.QueryRowContext(ctx, `
+	err := q.QueryRowContext(ctx, `
 		SELECT
 			name, kind, behavior_type,
 			content_canonical, content_summary, content_structured, content_tags,
@@ -562,7 +626,7 @@ func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Nod
 	}
 
 	// Query when conditions
-	whenRows, err := s.db.QueryContext(ctx, `
+	whenRows, err := q.QueryContext(ctx, `
 		SELECT field, value, value_type FROM behavior_when WHERE behavior_id = ?
 	`, id)
 	if err != nil {
@@ -585,13 +649,14 @@ func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Nod
 
 	// Query stats
 	var timesActivated, timesFollowed, timesOverridden, timesConfirmed int
-	var lastActivated, lastConfirmed sql.NullString
-	err = s.db.QueryRowContext(ctx, `
+	var sessionsActivated sql.NullInt64
+	var lastActivated, lastConfirmed, lastSessionID sql.NullString
+	err = q.QueryRowContext(ctx, `
 		SELECT times_activated, times_followed, times_overridden, times_confirmed,
-		       last_activated, last_confirmed
+		       last_activated, last_confirmed, sessions_activated, last_session_id
 		FROM behavior_stats WHERE behavior_id = ?
 	`, id).Scan(&timesActivated, &timesFollowed, &timesOverridden, &timesConfirmed,
-		&lastActivated, &lastConfirmed)
+		&lastActivated, &lastConfirmed, &sessionsActivated, &lastSessionID)
 	if err != nil && err != sql.ErrNoRows {
 		return nil, fmt.Errorf("failed to get stats: %w", err)
 	}
@@ -687,6 +752,12 @@ func (s *SQLiteGraphStore) getNodeUnlocked(ctx context.Context, id string) (*Nod
 		"created_at":       createdAt,
 		"updated_at":       updatedAt,
 	}
+	if sessionsActivated.Valid && sessionsActivated.Int64 > 0 {
+		stats["sessions_activated"] = int(sessionsActivated.Int64)
+	}
+	if lastSessionID.Valid {
+		stats["last_session_id"] = lastSessionID.String
+	}
 	if lastActivated.Valid {
 		stats["last_activated"] = lastActivated.String
 	}
@@ -730,6 +801,10 @@ func (s *SQLiteGraphStore) DeleteNode(ctx context.Context, id string) error {
 		return fmt.Errorf("failed to delete edges: %w", err)
 	}
 
+	if _, err := s.db.ExecContext(ctx, `DELETE FROM behavior_revisions WHERE behavior_id = ?`, id); err != nil {
+		return fmt.Errorf("failed to delete revisions: %w", err)
+	}
+
 	return nil
 }
 
@@ -738,7 +813,46 @@ func (s *SQLiteGraphStore) QueryNodes(ctx context.Context, predicate map[string]
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	// Build WHERE clause from predicate
+	whereClauses, args, err := nodeFilter(predicate)
+	if err != nil {
+		return nil, err
+	}
+	return s.selectNodes(ctx, whereClauses, args, "")
+}
+
+// QueryNodesPage returns one page of the nodes matching the predicate,
+// letting SQLite skip to the cursor and stop at the limit.
+func (s *SQLiteGraphStore) QueryNodesPage(ctx context.Context, predicate map[string]interface{}, opts PageOptions) (NodePage, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	whereClauses, args, err := nodeFilter(predicate)
+	if err != nil {
+		return NodePage{}, err
+	}
+	if opts.Cursor != "" {
+		whereClauses = append(whereClauses, "id > ?")
+		args = append(args, opts.Cursor)
+	}
+	suffix := " ORDER BY id"
+	if opts.Limit > 0 {
+		// One extra row tells whether another page follows
+		suffix += " LIMIT ?"
+		args = append(args, opts.Limit+1)
+	}
+	nodes, err := s.selectNodes(ctx, whereClauses, args, suffix)
+	if err != nil {
+		return NodePage{}, err
+	}
+	if opts.Limit > 0 && len(nodes) > opts.Limit {
+		nodes = nodes[:opts.Limit]
+		return NodePage{Nodes: nodes, NextCursor: nodes[len(nodes)-1].ID}, nil
+	}
+	return NodePage{Nodes: nodes}, nil
+}
+
+// nodeFilter translates a QueryNodes predicate into WHERE clauses.
+func nodeFilter(predicate map[string]interface{}) ([]string, []interface{}, error) {
 	var whereClauses []string
 	var args []interface{}
 
@@ -753,13 +867,56 @@ func (s *SQLiteGraphStore) QueryNodes(ctx context.Context, predicate map[string]
 		case "scope":
 			whereClauses = append(whereClauses, "scope = ?")
 			args = append(args, value)
+		case PredicateBehaviorKind:
+			whereClauses = append(whereClauses, "behavior_type = ?")
+			args = append(args, value)
+		case PredicateTag:
+			whereClauses = append(whereClauses, "EXISTS (SELECT 1 FROM json_each(behaviors.content_tags) WHERE value = ?)")
+			args = append(args, value)
+		case PredicateMinConfidence:
+			whereClauses = append(whereClauses, "confidence >= ?")
+			args = append(args, value)
+		case PredicatePack:
+			whereClauses = append(whereClauses, "json_extract(metadata_extra, '$.provenance.package') = ?")
+			args = append(args, value)
+		case PredicateCreatedAfter:
+			t, ok := value.(time.Time)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s must be a time.Time, got %T", key, value)
+			}
+			// Provenance time when known (a zero time.Time is not), else when the row was written
+			whereClauses = append(whereClauses, `julianday(COALESCE(provenance_created_at,
+				NULLIF(json_extract(metadata_extra, '$.provenance.created_at'), '0001-01-01T00:00:00Z'),
+				created_at)) >= julianday(?)`)
+			args = append(args, t.UTC().Format(time.RFC3339Nano))
+		case PredicateExpiresBefore:
+			t, ok := value.(time.Time)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s must be a time.Time, got %T", key, value)
+			}
+			whereClauses = append(whereClauses, `julianday(json_extract(metadata_extra, '$.expires_at')) <= julianday(?)`)
+			args = append(args, t.UTC().Format(time.RFC3339Nano))
+		case PredicateSnoozedAfter:
+			t, ok := value.(time.Time)
+			if !ok {
+				return nil, nil, fmt.Errorf("%s must be a time.Time, got %T", key, value)
+			}
+			whereClauses = append(whereClauses, `julianday(json_extract(metadata_extra, '$.snoozed_until')) > julianday(?)`)
+			args = append(args, t.UTC().Format(time.RFC3339Nano))
 		}
 	}
 
+	return whereClauses, args, nil
+}
+
+// selectNodes loads the nodes whose rows match whereClauses, in the order
+// given by suffix (ORDER BY/LIMIT). Callers hold s.mu.
+func (s *SQLiteGraphStore) selectNodes(ctx context.Context, whereClauses []string, args []interface{}, suffix string) ([]Node, error) {
 	query := `SELECT id FROM behaviors`
 	if len(whereClauses) > 0 {
 		query += " WHERE " + joinStrings(whereClauses, " AND ") //nolint:gosec // G202: whereClauses contains only hardcoded column filters, not user input
 	}
+	query += suffix
 
 	rows, err := s.db.QueryContext(ctx, query, args...)
 	if err != nil {
@@ -806,6 +963,11 @@ func (s *SQLiteGraphStore) AddEdge(ctx context.Context, edge Edge) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return addEdgeWith(ctx, s.db, edge)
+}
+
+// addEdgeWith adds an edge using the provided querier (DB or Tx).
+func addEdgeWith(ctx context.Context, q dbQuerier, edge Edge) error {
 	var metadataJSON []byte
 	var err error
 	if edge.Metadata != nil {
@@ -827,7 +989,7 @@ func (s *SQLiteGraphStore) AddEdge(ctx context.Context, edge Edge) error {
 		lastActivatedStr = sql.NullString{String: edge.LastActivated.Format(time.RFC3339), Valid: true}
 	}
 
-	_, err = s.db.ExecContext(ctx, `
+	_, err = q.ExecContext(ctx, `
 		INSERT OR REPLACE INTO edges (source, target, kind, weight, created_at, last_activated, metadata)
 		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`, edge.Source, edge.Target, edge.Kind, edge.Weight, createdAtStr, lastActivatedStr, nullBytes(metadataJSON))
@@ -838,6 +1000,66 @@ func (s *SQLiteGraphStore) AddEdge(ctx context.Context, edge Edge) error {
 	return nil
 }
 
+// BatchAdd adds nodes, then edges, in a single transaction, so large
+// installs and restores commit once instead of once per write.
+func (s *SQLiteGraphStore) BatchAdd(ctx context.Context, nodes []Node, edges []Edge) error {
+	for _, node := range nodes {
+		if node.ID == "" {
+			return fmt.Errorf("node ID is required")
+		}
+	}
+	for _, edge := range edges {
+		if err := ValidateEdge(edge); err != nil {
+			return fmt.Errorf("edge %s -> %s: %w", edge.Source, edge.Target, err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.withFileLock(ctx, func(ctx context.Context) error {
+		return s.batchAdd(ctx, nodes, edges)
+	})
+}
+
+// batchAdd writes the transaction for BatchAdd. Caller must hold s.mu.
+func (s *SQLiteGraphStore) batchAdd(ctx context.Context, nodes []Node, edges []Edge) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback() // no-op if already committed
+
+	for _, node := range nodes {
+		if !isBehaviorKind(node.Kind) {
+			if _, err := s.addGenericNodeWith(ctx, tx, node); err != nil {
+				return fmt.Errorf("adding node %s: %w", node.ID, err)
+			}
+			continue
+		}
+		// Keep the replaced and the new state in the behavior's history, as AddNode does
+		if err := s.recordRevisionWith(ctx, tx, node.ID); err != nil {
+			return err
+		}
+		if _, err := s.addBehaviorWith(ctx, tx, node); err != nil {
+			return fmt.Errorf("adding node %s: %w", node.ID, err)
+		}
+		if err := s.recordRevisionWith(ctx, tx, node.ID); err != nil {
+			return err
+		}
+	}
+	for _, edge := range edges {
+		if err := addEdgeWith(ctx, tx, edge); err != nil {
+			return fmt.Errorf("adding edge %s -> %s: %w", edge.Source, edge.Target, err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
+	return nil
+}
+
 // RemoveEdge removes an edge matching source, target, and kind.
 func (s *SQLiteGraphStore) RemoveEdge(ctx context.Context, source, target string, kind EdgeKind) error {
 	s.mu.Lock()
@@ -883,7 +1105,7 @@ func (s *SQLiteGraphStore) getEdgesUnlocked(ctx context.Context, nodeID string,
 		args = append(args, kind)
 	}
 
-	rows, err := s.db.QueryContext(ctx, query, args...)
+	rows, err := s.stmts.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query edges: %w", err)
 	}
@@ -1008,6 +1230,14 @@ func (s *SQLiteGraphStore) Sync(ctx context.Context) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// Another process syncing at the same time could export the JSONL
+	// without our dirty behaviors and then clear their flags.
+	return s.withFileLock(ctx, s.sync)
+}
+
+// sync exports dirty behaviors and all edges to JSONL. Caller must hold
+// s.mu and the lock file.
+func (s *SQLiteGraphStore) sync(ctx context.Context) error {
 	// Check if we have dirty behaviors
 	dirtyOps, err := s.getDirtyOperations(ctx)
 	if err != nil {
@@ -1015,6 +1245,7 @@ func (s *SQLiteGraphStore) Sync(ctx context.Context) error {
 	}
 
 	// If there are dirty behaviors, use incremental export
+	exported := len(dirtyOps) > 0
 	if len(dirtyOps) > 0 {
 		if err := s.incrementalExportNodes(ctx, dirtyOps); err != nil {
 			// Fall back to full export on error
@@ -1035,6 +1266,7 @@ func (s *SQLiteGraphStore) Sync(ctx context.Context) error {
 			if err := s.exportNodesToJSONL(ctx); err != nil {
 				return fmt.Errorf("failed to export nodes: %w", err)
 			}
+			exported = true
 		}
 	}
 
@@ -1043,9 +1275,14 @@ func (s *SQLiteGraphStore) Sync(ctx context.Context) error {
 		return fmt.Errorf("failed to export edges: %w", err)
 	}
 
-	// Clear dirty flags
-	if _, err := s.db.ExecContext(ctx, `DELETE FROM dirty_behaviors`); err != nil {
-		return fmt.Errorf("failed to clear dirty flags: %w", err)
+	// Clear dirty flags. The write also leaves the database newer than the
+	// nodes.jsonl just exported, so the next open doesn't re-import it; skip
+	// it when nothing was exported, so read-only sessions leave the store's
+	// mtime alone.
+	if exported {
+		if _, err := s.db.ExecContext(ctx, `DELETE FROM dirty_behaviors`); err != nil {
+			return fmt.Errorf("failed to clear dirty flags: %w", err)
+		}
 	}
 
 	return nil
@@ -1265,54 +1502,62 @@ func (s *SQLiteGraphStore) exportEdgesToJSONL(ctx context.Context) error {
 	}
 	defer rows.Close()
 
-	return atomicWriteFile(s.edgesFile, func(f *os.File) error {
-		encoder := json.NewEncoder(f)
-		for rows.Next() {
-			var source, target, kind string
-			var weight sql.NullFloat64
-			var createdAtStr, lastActivatedStr, metadataJSON sql.NullString
-
-			if err := rows.Scan(&source, &target, &kind, &weight, &createdAtStr, &lastActivatedStr, &metadataJSON); err != nil {
-				return fmt.Errorf("failed to scan edge: %w", err)
-			}
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	for rows.Next() {
+		var source, target, kind string
+		var weight sql.NullFloat64
+		var createdAtStr, lastActivatedStr, metadataJSON sql.NullString
 
-			edge := Edge{
-				Source: source,
-				Target: target,
-				Kind:   EdgeKind(kind),
-			}
+		if err := rows.Scan(&source, &target, &kind, &weight, &createdAtStr, &lastActivatedStr, &metadataJSON); err != nil {
+			return fmt.Errorf("failed to scan edge: %w", err)
+		}
 
-			if weight.Valid {
-				edge.Weight = weight.Float64
-			}
+		edge := Edge{
+			Source: source,
+			Target: target,
+			Kind:   EdgeKind(kind),
+		}
 
-			if createdAtStr.Valid {
-				if t, err := time.Parse(time.RFC3339, createdAtStr.String); err == nil {
-					edge.CreatedAt = t
-				}
-			}
+		if weight.Valid {
+			edge.Weight = weight.Float64
+		}
 
-			if lastActivatedStr.Valid {
-				if t, err := time.Parse(time.RFC3339, lastActivatedStr.String); err == nil {
-					edge.LastActivated = &t
-				}
+		if createdAtStr.Valid {
+			if t, err := time.Parse(time.RFC3339, createdAtStr.String); err == nil {
+				edge.CreatedAt = t
 			}
+		}
 
-			if metadataJSON.Valid {
-				var metadata map[string]interface{}
-				if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err == nil {
-					edge.Metadata = metadata
-				}
+		if lastActivatedStr.Valid {
+			if t, err := time.Parse(time.RFC3339, lastActivatedStr.String); err == nil {
+				edge.LastActivated = &t
 			}
+		}
 
-			if err := encoder.Encode(edge); err != nil {
-				return fmt.Errorf("failed to encode edge: %w", err)
+		if metadataJSON.Valid {
+			var metadata map[string]interface{}
+			if err := json.Unmarshal([]byte(metadataJSON.String), &metadata); err == nil {
+				edge.Metadata = metadata
 			}
 		}
-		if err := rows.Err(); err != nil {
-			return fmt.Errorf("failed to iterate edges: %w", err)
+
+		if err := encoder.Encode(edge); err != nil {
+			return fmt.Errorf("failed to encode edge: %w", err)
 		}
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate edges: %w", err)
+	}
+
+	// Leave an unchanged file alone, so read-only sessions don't touch the
+	// store's mtime (which the active context cache keys on).
+	if existing, err := os.ReadFile(s.edgesFile); err == nil && bytes.Equal(existing, buf.Bytes()) {
 		return nil
+	}
+	return atomicWriteFile(s.edgesFile, func(f *os.File) error {
+		_, err := f.Write(buf.Bytes())
+		return err
 	})
 }
 
@@ -1322,6 +1567,7 @@ func (s *SQLiteGraphStore) Close() error {
 		// Log but don't fail on sync error during close
 		fmt.Fprintf(os.Stderr, "warning: failed to sync during close: %v\n", err)
 	}
+	s.stmts.Close()
 	return s.db.Close()
 }
 
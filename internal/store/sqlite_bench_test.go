@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// benchBehaviors is the store size the benchmarks measure query latency at.
+const benchBehaviors = 10000
+
+// newBenchStore returns a SQLite store holding benchBehaviors behaviors,
+// every tenth installed from a pack, each with a similar-to edge to the
+// next.
+func newBenchStore(b *testing.B) *SQLiteGraphStore {
+	b.Helper()
+	b.Setenv(EnvLockWait, "")
+	s, err := NewSQLiteGraphStore(b.TempDir())
+	if err != nil {
+		b.Fatalf("NewSQLiteGraphStore: %v", err)
+	}
+	b.Cleanup(func() { s.Close() })
+
+	kinds := []string{"directive", "constraint", "procedure", "preference"}
+	nodes := make([]Node, benchBehaviors)
+	edges := make([]Edge, 0, benchBehaviors-1)
+	now := time.Now()
+	for i := range nodes {
+		id := benchID(i)
+		metadata := map[string]interface{}{"confidence": 0.5 + float64(i%50)/100}
+		if i%10 == 0 {
+			metadata["provenance"] = map[string]interface{}{"package": fmt.Sprintf("org/pack-%d", i%7)}
+		}
+		nodes[i] = Node{
+			ID:   id,
+			Kind: NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name": id,
+				"kind": kinds[i%len(kinds)],
+				"content": map[string]interface{}{
+					"canonical": fmt.Sprintf("Behavior %d: prefer approach %d for task %d", i, i%97, i%13),
+					"tags":      []string{fmt.Sprintf("tag-%d", i%20)},
+				},
+			},
+			Metadata: metadata,
+		}
+		if i > 0 {
+			edges = append(edges, Edge{Source: benchID(i - 1), Target: id, Kind: EdgeKindSimilarTo, Weight: 0.8, CreatedAt: now})
+		}
+	}
+	if err := s.BatchAdd(context.Background(), nodes, edges); err != nil {
+		b.Fatalf("BatchAdd: %v", err)
+	}
+	return s
+}
+
+func benchID(i int) string {
+	return fmt.Sprintf("bench-%05d", i)
+}
+
+func BenchmarkSQLiteGetNode(b *testing.B) {
+	s := newBenchStore(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.GetNode(ctx, benchID(i%benchBehaviors)); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSQLiteGetEdges(b *testing.B) {
+	s := newBenchStore(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.GetEdges(ctx, benchID(i%benchBehaviors), DirectionBoth, EdgeKindSimilarTo); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSQLiteQueryNodes(b *testing.B) {
+	s := newBenchStore(b)
+	ctx := context.Background()
+	predicates := map[string]map[string]interface{}{
+		"kind":           {"kind": string(NodeKindBehavior)},
+		"behavior_kind":  {PredicateBehaviorKind: "constraint"},
+		"tag":            {PredicateTag: "tag-3"},
+		"pack":           {PredicatePack: "org/pack-3"},
+		"min_confidence": {PredicateMinConfidence: 0.95},
+	}
+	for name, predicate := range predicates {
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := s.QueryNodes(ctx, predicate); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSQLiteQueryNodesPage(b *testing.B) {
+	s := newBenchStore(b)
+	ctx := context.Background()
+	predicate := map[string]interface{}{"kind": string(NodeKindBehavior)}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := s.QueryNodesPage(ctx, predicate, PageOptions{Limit: 100, Cursor: benchID(i % benchBehaviors)}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSQLiteAddNode(b *testing.B) {
+	s := newBenchStore(b)
+	ctx := context.Background()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		node := pageTestNode(fmt.Sprintf("added-%d", i))
+		if _, err := s.AddNode(ctx, node); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
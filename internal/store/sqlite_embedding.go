@@ -2,6 +2,7 @@ package store
 
 import (
 	"context"
+	"database/sql"
 	"encoding/binary"
 	"fmt"
 	"math"
@@ -64,7 +65,7 @@ func (s *SQLiteGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbe
 	defer s.mu.RUnlock()
 
 	rows, err := s.db.QueryContext(ctx,
-		`SELECT id, embedding FROM behaviors WHERE embedding IS NOT NULL`)
+		`SELECT id, embedding, embedding_model FROM behaviors WHERE embedding IS NOT NULL`)
 	if err != nil {
 		return nil, fmt.Errorf("query embeddings: %w", err)
 	}
@@ -74,7 +75,8 @@ func (s *SQLiteGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbe
 	for rows.Next() {
 		var id string
 		var blob []byte
-		if err := rows.Scan(&id, &blob); err != nil {
+		var model sql.NullString
+		if err := rows.Scan(&id, &blob, &model); err != nil {
 			return nil, fmt.Errorf("scan embedding: %w", err)
 		}
 		vec := decodeEmbedding(blob)
@@ -84,6 +86,7 @@ func (s *SQLiteGraphStore) GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbe
 		results = append(results, BehaviorEmbedding{
 			BehaviorID: id,
 			Embedding:  vec,
+			ModelName:  model.String,
 		})
 	}
 
@@ -113,3 +116,25 @@ func (s *SQLiteGraphStore) GetBehaviorIDsWithoutEmbeddings(ctx context.Context)
 
 	return ids, nil
 }
+
+// ClearOrphanedEmbeddings removes embeddings from behaviors that are gone
+// for good (forgotten, deprecated, or merged) and returns how many were
+// cleared. Pending and dormant behaviors keep theirs since they may return. Such vectors are never retrieved but would otherwise be
+// re-added to the vector index on every sync.
+func (s *SQLiteGraphStore) ClearOrphanedEmbeddings(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE behaviors SET embedding = NULL, embedding_model = NULL
+		 WHERE embedding IS NOT NULL AND kind IN (?, ?, ?)`,
+		string(NodeKindForgotten), string(NodeKindDeprecated), string(NodeKindMerged))
+	if err != nil {
+		return 0, fmt.Errorf("clear orphaned embeddings: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("check rows affected: %w", err)
+	}
+	return int(n), nil
+}
@@ -2,28 +2,52 @@ package store
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/nvandessel/floop/internal/confidence"
 )
 
-// UpdateConfidence efficiently updates just the confidence value for a behavior.
+// UpdateConfidence efficiently updates just the confidence value for a
+// behavior. It is the reinforcement path, so the change is attributed to
+// reinforcement in the behavior's confidence sources.
 func (s *SQLiteGraphStore) UpdateConfidence(ctx context.Context, behaviorID string, newConfidence float64) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	result, err := s.db.ExecContext(ctx,
-		`UPDATE behaviors SET confidence = ?, updated_at = ? WHERE id = ?`,
-		newConfidence, time.Now().Format(time.RFC3339), behaviorID)
+	var current float64
+	var extraJSON sql.NullString
+	err := s.db.QueryRowContext(ctx,
+		`SELECT confidence, metadata_extra FROM behaviors WHERE id = ?`, behaviorID).Scan(&current, &extraJSON)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("behavior not found: %s", behaviorID)
+	}
 	if err != nil {
-		return fmt.Errorf("failed to update confidence for %s: %w", behaviorID, err)
+		return fmt.Errorf("failed to read confidence for %s: %w", behaviorID, err)
 	}
 
-	rows, err := result.RowsAffected()
+	extra := make(map[string]interface{})
+	if extraJSON.Valid && extraJSON.String != "" {
+		if err := json.Unmarshal([]byte(extraJSON.String), &extra); err != nil {
+			return fmt.Errorf("unmarshal metadata for %s: %w", behaviorID, err)
+		}
+	}
+	extra["confidence"] = current
+	confidence.Adjust(extra, confidence.Reinforcement, newConfidence)
+	delete(extra, "confidence")
+	updated, err := json.Marshal(extra)
 	if err != nil {
-		return fmt.Errorf("failed to check rows affected: %w", err)
+		return fmt.Errorf("marshal metadata for %s: %w", behaviorID, err)
 	}
-	if rows == 0 {
-		return fmt.Errorf("behavior not found: %s", behaviorID)
+
+	_, err = s.db.ExecContext(ctx,
+		`UPDATE behaviors SET confidence = ?, metadata_extra = ?, updated_at = ? WHERE id = ?`,
+		newConfidence, string(updated), time.Now().Format(time.RFC3339), behaviorID)
+	if err != nil {
+		return fmt.Errorf("failed to update confidence for %s: %w", behaviorID, err)
 	}
 
 	return nil
@@ -54,6 +78,39 @@ func (s *SQLiteGraphStore) RecordActivationHit(ctx context.Context, behaviorID s
 	return nil
 }
 
+// RecordSessionActivation records an activation hit like RecordActivationHit
+// and, the first time the behavior activates in sessionID, also increments
+// sessions_activated, so ranking can count a session once no matter how often
+// the behavior activated within it.
+func (s *SQLiteGraphStore) RecordSessionActivation(ctx context.Context, behaviorID, sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().Format(time.RFC3339)
+	result, err := s.db.ExecContext(ctx, `
+		UPDATE behavior_stats SET
+			times_activated = times_activated + 1,
+			last_activated = ?,
+			sessions_activated = COALESCE(sessions_activated, 0) +
+				CASE WHEN last_session_id IS ? THEN 0 ELSE 1 END,
+			last_session_id = ?
+		WHERE behavior_id = ?`,
+		now, sessionID, sessionID, behaviorID)
+	if err != nil {
+		return fmt.Errorf("failed to record session activation for %s: %w", behaviorID, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("behavior not found: %s", behaviorID)
+	}
+
+	return nil
+}
+
 // RecordConfirmed increments times_confirmed and updates last_confirmed for a behavior.
 // This is called when the user explicitly confirms or implicitly continues using a behavior.
 func (s *SQLiteGraphStore) RecordConfirmed(ctx context.Context, behaviorID string) error {
@@ -79,6 +136,30 @@ func (s *SQLiteGraphStore) RecordConfirmed(ctx context.Context, behaviorID strin
 	return nil
 }
 
+// RecordFollowed increments times_followed for a behavior.
+// This is called when the behavior was observed being applied.
+func (s *SQLiteGraphStore) RecordFollowed(ctx context.Context, behaviorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result, err := s.db.ExecContext(ctx,
+		`UPDATE behavior_stats SET times_followed = times_followed + 1 WHERE behavior_id = ?`,
+		behaviorID)
+	if err != nil {
+		return fmt.Errorf("failed to record followed for %s: %w", behaviorID, err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check rows affected: %w", err)
+	}
+	if rows == 0 {
+		return fmt.Errorf("behavior not found: %s", behaviorID)
+	}
+
+	return nil
+}
+
 // RecordOverridden increments times_overridden for a behavior.
 // This is called when the user or agent contradicted the behavior.
 func (s *SQLiteGraphStore) RecordOverridden(ctx context.Context, behaviorID string) error {
@@ -0,0 +1,97 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// TextMatch is a behavior found by full-text search, with its relevance
+// (negated BM25, so higher is better).
+type TextMatch struct {
+	BehaviorID string
+	Score      float64
+}
+
+// ftsWeights are the BM25 column weights for behaviors_fts, in column
+// order: name, canonical, summary, tags.
+const ftsWeights = "2.0, 4.0, 1.0, 2.0"
+
+// SearchText returns active behaviors whose name, content, or tags match
+// the words of query, best match first. Words are stemmed (so "wrapping"
+// matches "wrap"); the last word also matches as a prefix. With matchAll,
+// every word must match; otherwise any word does. A limit below 1 returns
+// all matches.
+func (s *SQLiteGraphStore) SearchText(ctx context.Context, query string, matchAll bool, limit int) ([]TextMatch, error) {
+	match := ftsQuery(query, matchAll)
+	if match == "" {
+		return nil, nil
+	}
+	if limit < 1 {
+		limit = -1
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT b.id, -bm25(behaviors_fts, `+ftsWeights+`) AS score
+		FROM behaviors_fts
+		JOIN behaviors b ON b.rowid = behaviors_fts.rowid
+		WHERE behaviors_fts MATCH ? AND b.kind = 'behavior'
+		ORDER BY score DESC, b.id
+		LIMIT ?`, match, limit)
+	if err != nil {
+		return nil, fmt.Errorf("full-text search: %w", err)
+	}
+	defer rows.Close()
+
+	var matches []TextMatch
+	for rows.Next() {
+		var m TextMatch
+		if err := rows.Scan(&m.BehaviorID, &m.Score); err != nil {
+			return nil, fmt.Errorf("scan text match: %w", err)
+		}
+		matches = append(matches, m)
+	}
+	return matches, rows.Err()
+}
+
+// ftsQuery turns free text into an FTS5 query of quoted words, so user
+// input can never be parsed as FTS5 syntax. The last word is a prefix
+// match, since it is often still being typed.
+func ftsQuery(query string, matchAll bool) string {
+	words := strings.FieldsFunc(query, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	terms := make([]string, len(words))
+	for i, w := range words {
+		terms[i] = `"` + w + `"`
+	}
+	terms[len(terms)-1] += "*"
+
+	op := " OR "
+	if matchAll {
+		op = " AND "
+	}
+	return strings.Join(terms, op)
+}
+
+// rebuildTextIndex repopulates behaviors_fts from the behaviors table.
+func rebuildTextIndex(ctx context.Context, q dbQuerier) error {
+	if _, err := q.ExecContext(ctx, `DELETE FROM behaviors_fts`); err != nil {
+		return fmt.Errorf("clear behaviors_fts: %w", err)
+	}
+	_, err := q.ExecContext(ctx, `
+		INSERT INTO behaviors_fts (rowid, name, canonical, summary, tags)
+		SELECT rowid, name, content_canonical, content_summary, content_tags
+		FROM behaviors`)
+	if err != nil {
+		return fmt.Errorf("populate behaviors_fts: %w", err)
+	}
+	return nil
+}
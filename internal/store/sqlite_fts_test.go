@@ -0,0 +1,115 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func ftsTestNode(id, canonical string, tags ...interface{}) Node {
+	return Node{
+		ID:   id,
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name": id,
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": canonical,
+				"tags":      tags,
+			},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.6},
+	}
+}
+
+func searchIDs(t *testing.T, s *SQLiteGraphStore, query string, matchAll bool) []string {
+	t.Helper()
+	matches, err := s.SearchText(context.Background(), query, matchAll, 0)
+	if err != nil {
+		t.Fatalf("SearchText(%q) failed: %v", query, err)
+	}
+	var ids []string
+	for _, m := range matches {
+		ids = append(ids, m.BehaviorID)
+	}
+	return ids
+}
+
+func TestSearchText(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSQLiteStore(t)
+
+	mustAddNode(t, s, ctx, ftsTestNode("b-wrap", "Wrap errors with context using fmt.Errorf", "errors"))
+	mustAddNode(t, s, ctx, ftsTestNode("b-slog", "Use slog for structured logging", "logging"))
+	mustAddNode(t, s, ctx, ftsTestNode("b-test", "Write table-driven tests for error paths", "testing"))
+
+	tests := []struct {
+		query    string
+		matchAll bool
+		want     []string
+	}{
+		{"wrapping", false, []string{"b-wrap"}},                 // stemmed
+		{"struct", false, []string{"b-slog"}},                   // prefix of the last word
+		{"errors context", false, []string{"b-wrap", "b-test"}}, // any word, best first
+		{"error context", true, []string{"b-wrap"}},             // every word
+		{"testing", false, []string{"b-test"}},                  // tags
+		{`"NEAR(`, false, nil},                                  // syntax is not interpreted
+	}
+	for _, tt := range tests {
+		got := searchIDs(t, s, tt.query, tt.matchAll)
+		if len(got) != len(tt.want) {
+			t.Errorf("SearchText(%q) = %v, want %v", tt.query, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("SearchText(%q) = %v, want %v", tt.query, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestSearchText_TracksWrites(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSQLiteStore(t)
+
+	mustAddNode(t, s, ctx, ftsTestNode("b-1", "Prefer table-driven tests"))
+	if err := s.UpdateNode(ctx, ftsTestNode("b-1", "Prefer golden files")); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if got := searchIDs(t, s, "table", false); len(got) != 0 {
+		t.Errorf("old content still indexed after update: %v", got)
+	}
+	if got := searchIDs(t, s, "golden", false); len(got) != 1 {
+		t.Errorf("new content not indexed after update: %v", got)
+	}
+
+	// Re-adding replaces the row, which must not leave a stale index entry
+	mustAddNode(t, s, ctx, ftsTestNode("b-1", "Prefer fuzz tests"))
+	if got := searchIDs(t, s, "golden", false); len(got) != 0 {
+		t.Errorf("old content still indexed after replace: %v", got)
+	}
+	if got := searchIDs(t, s, "fuzz", false); len(got) != 1 {
+		t.Errorf("replaced content not indexed: %v", got)
+	}
+
+	forgotten := ftsTestNode("b-1", "Prefer fuzz tests")
+	forgotten.Kind = NodeKindForgotten
+	if err := s.UpdateNode(ctx, forgotten); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	if got := searchIDs(t, s, "fuzz", false); len(got) != 0 {
+		t.Errorf("forgotten behavior returned: %v", got)
+	}
+
+	if err := s.DeleteNode(ctx, "b-1"); err != nil {
+		t.Fatalf("DeleteNode failed: %v", err)
+	}
+	var n int
+	if err := s.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM behaviors_fts`).Scan(&n); err != nil {
+		t.Fatal(err)
+	}
+	if n != 0 {
+		t.Errorf("behaviors_fts has %d rows after delete, want 0", n)
+	}
+}
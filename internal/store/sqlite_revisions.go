@@ -0,0 +1,84 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// recordRevision snapshots a behavior as a new revision when its kind or
+// content differs from its latest revision, or when it has none yet.
+// Non-behavior nodes and missing nodes are ignored. The caller must hold s.mu.
+func (s *SQLiteGraphStore) recordRevision(ctx context.Context, id string) error {
+	return s.recordRevisionWith(ctx, s.db, id)
+}
+
+// recordRevisionWith is recordRevision using the provided querier (DB or Tx).
+func (s *SQLiteGraphStore) recordRevisionWith(ctx context.Context, q dbQuerier, id string) error {
+	node, err := s.getNodeWith(ctx, q, id)
+	if err != nil || node == nil || !isBehaviorKind(node.Kind) {
+		return err
+	}
+	contentJSON, err := json.Marshal(node.Content)
+	if err != nil {
+		return fmt.Errorf("failed to marshal revision content: %w", err)
+	}
+
+	var rev int
+	var kind, content string
+	err = q.QueryRowContext(ctx, `
+		SELECT rev, kind, content FROM behavior_revisions
+		WHERE behavior_id = ? ORDER BY rev DESC LIMIT 1
+	`, id).Scan(&rev, &kind, &content)
+	if err != nil && err != sql.ErrNoRows {
+		return fmt.Errorf("failed to get latest revision: %w", err)
+	}
+	if err == nil && kind == string(node.Kind) && content == string(contentJSON) {
+		return nil
+	}
+
+	confidence, _ := node.Metadata["confidence"].(float64)
+	if _, err := q.ExecContext(ctx, `
+		INSERT INTO behavior_revisions (behavior_id, rev, kind, content, confidence, recorded_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`, id, rev+1, string(node.Kind), string(contentJSON), confidence, time.Now().Format(time.RFC3339Nano)); err != nil {
+		return fmt.Errorf("failed to record revision: %w", err)
+	}
+	return nil
+}
+
+// BehaviorHistory returns the revisions of a behavior, oldest first.
+func (s *SQLiteGraphStore) BehaviorHistory(ctx context.Context, behaviorID string) ([]Revision, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT rev, kind, content, confidence, recorded_at FROM behavior_revisions
+		WHERE behavior_id = ? ORDER BY rev
+	`, behaviorID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query revisions: %w", err)
+	}
+	defer rows.Close()
+
+	var revisions []Revision
+	for rows.Next() {
+		var r Revision
+		var kind, content, recordedAt string
+		var confidence sql.NullFloat64
+		if err := rows.Scan(&r.Rev, &kind, &content, &confidence, &recordedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan revision: %w", err)
+		}
+		if err := json.Unmarshal([]byte(content), &r.Content); err != nil {
+			return nil, fmt.Errorf("unmarshal revision %d of %s: %w", r.Rev, behaviorID, err)
+		}
+		r.BehaviorID = behaviorID
+		r.Kind = NodeKind(kind)
+		r.Confidence = confidence.Float64
+		r.RecordedAt, _ = time.Parse(time.RFC3339Nano, recordedAt)
+		revisions = append(revisions, r)
+	}
+	return revisions, rows.Err()
+}
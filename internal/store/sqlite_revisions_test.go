@@ -0,0 +1,105 @@
+package store
+
+import (
+	"context"
+	"testing"
+)
+
+func revisionTestNode(canonical string) Node {
+	return Node{
+		ID:   "b-rev",
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "rev-test",
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": canonical},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.6},
+	}
+}
+
+func TestBehaviorHistory(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSQLiteStore(t)
+
+	mustAddNode(t, s, ctx, revisionTestNode("Use slog"))
+
+	// Rewriting the same content is not a new revision.
+	if err := s.UpdateNode(ctx, revisionTestNode("Use slog")); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+	// Confidence changes alone are not revisions either.
+	if err := s.UpdateConfidence(ctx, "b-rev", 0.8); err != nil {
+		t.Fatalf("UpdateConfidence failed: %v", err)
+	}
+	updated := revisionTestNode("Use slog with structured fields")
+	updated.Metadata["confidence"] = 0.8
+	if err := s.UpdateNode(ctx, updated); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+
+	revisions, err := s.BehaviorHistory(ctx, "b-rev")
+	if err != nil {
+		t.Fatalf("BehaviorHistory failed: %v", err)
+	}
+	if len(revisions) != 2 {
+		t.Fatalf("expected 2 revisions, got %d: %+v", len(revisions), revisions)
+	}
+	for i, want := range []struct {
+		canonical  string
+		confidence float64
+	}{
+		{"Use slog", 0.6},
+		{"Use slog with structured fields", 0.8},
+	} {
+		r := revisions[i]
+		if r.Rev != i+1 || r.Kind != NodeKindBehavior || r.Confidence != want.confidence {
+			t.Errorf("revision %d = rev %d, kind %s, confidence %v", i, r.Rev, r.Kind, r.Confidence)
+		}
+		content, _ := r.Content["content"].(map[string]interface{})
+		if content["canonical"] != want.canonical {
+			t.Errorf("revision %d canonical = %v, want %q", i, content["canonical"], want.canonical)
+		}
+		if r.RecordedAt.IsZero() {
+			t.Errorf("revision %d has no recorded time", i)
+		}
+	}
+}
+
+func TestBehaviorHistory_KindChange(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSQLiteStore(t)
+
+	mustAddNode(t, s, ctx, revisionTestNode("Use slog"))
+	forgotten := revisionTestNode("Use slog")
+	forgotten.Kind = NodeKindForgotten
+	if err := s.UpdateNode(ctx, forgotten); err != nil {
+		t.Fatalf("UpdateNode failed: %v", err)
+	}
+
+	revisions, err := s.BehaviorHistory(ctx, "b-rev")
+	if err != nil {
+		t.Fatalf("BehaviorHistory failed: %v", err)
+	}
+	if len(revisions) != 2 || revisions[1].Kind != NodeKindForgotten {
+		t.Fatalf("expected a second, forgotten revision, got %+v", revisions)
+	}
+}
+
+func TestBehaviorHistory_DeleteClears(t *testing.T) {
+	ctx := context.Background()
+	s := newTestSQLiteStore(t)
+
+	mustAddNode(t, s, ctx, revisionTestNode("Use slog"))
+	if err := s.DeleteNode(ctx, "b-rev"); err != nil {
+		t.Fatalf("DeleteNode failed: %v", err)
+	}
+
+	revisions, err := s.BehaviorHistory(ctx, "b-rev")
+	if err != nil {
+		t.Fatalf("BehaviorHistory failed: %v", err)
+	}
+	if len(revisions) != 0 {
+		t.Errorf("expected no revisions after delete, got %d", len(revisions))
+	}
+}
@@ -6,12 +6,15 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/nvandessel/floop/internal/confidence"
 	_ "modernc.org/sqlite"
 )
 
@@ -1912,6 +1915,55 @@ func TestSQLiteStore_RecordActivationHit(t *testing.T) {
 	}
 }
 
+func TestSQLiteStore_RecordSessionActivation(t *testing.T) {
+	tmpDir := t.TempDir()
+	s, err := NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	_, err = s.AddNode(ctx, Node{
+		ID:   "session-hit",
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "Session Hit",
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": "Count sessions"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+
+	// Three activations across two sessions.
+	for _, sessionID := range []string{"s1", "s1", "s2"} {
+		if err := s.RecordSessionActivation(ctx, "session-hit", sessionID); err != nil {
+			t.Fatalf("RecordSessionActivation(%s) error = %v", sessionID, err)
+		}
+	}
+
+	node, err := s.GetNode(ctx, "session-hit")
+	if err != nil || node == nil {
+		t.Fatalf("GetNode() = %v, %v", node, err)
+	}
+	stats, _ := node.Metadata["stats"].(map[string]interface{})
+	if got := stats["times_activated"]; got != 3 {
+		t.Errorf("times_activated = %v, want 3", got)
+	}
+	if got := stats["sessions_activated"]; got != 2 {
+		t.Errorf("sessions_activated = %v, want 2", got)
+	}
+	if got := stats["last_session_id"]; got != "s2" {
+		t.Errorf("last_session_id = %v, want s2", got)
+	}
+
+	if err := s.RecordSessionActivation(ctx, "missing", "s1"); err == nil {
+		t.Error("RecordSessionActivation() should error for non-existent behavior")
+	}
+}
+
 func TestSQLiteStore_RecordActivationHit_NotFound(t *testing.T) {
 	tmpDir := t.TempDir()
 	s, err := NewSQLiteGraphStore(tmpDir)
@@ -2157,6 +2209,52 @@ func TestSQLiteStore_RecordOverridden_NotFound(t *testing.T) {
 	}
 }
 
+func TestSQLiteStore_RecordFollowed(t *testing.T) {
+	tmpDir := t.TempDir()
+	s, err := NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+	_, err = s.AddNode(ctx, Node{
+		ID:   "follow-test",
+		Kind: NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name": "Follow Test",
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": "Test follow recording",
+			},
+		},
+	})
+	if err != nil {
+		t.Fatalf("AddNode() error = %v", err)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := s.RecordFollowed(ctx, "follow-test"); err != nil {
+			t.Fatalf("RecordFollowed() error = %v", err)
+		}
+	}
+
+	var timesFollowed int
+	err = s.db.QueryRowContext(ctx,
+		`SELECT times_followed FROM behavior_stats WHERE behavior_id = ?`,
+		"follow-test").Scan(&timesFollowed)
+	if err != nil {
+		t.Fatalf("query stats error = %v", err)
+	}
+	if timesFollowed != 2 {
+		t.Errorf("times_followed = %d, want 2", timesFollowed)
+	}
+
+	if err := s.RecordFollowed(ctx, "nonexistent"); err == nil {
+		t.Error("RecordFollowed() should error for non-existent behavior")
+	}
+}
+
 func TestSQLiteGraphStore_BatchUpdateEdgeWeights(t *testing.T) {
 	tmpDir := t.TempDir()
 	s, err := NewSQLiteGraphStore(tmpDir)
@@ -2836,6 +2934,18 @@ func TestSQLiteStore_UpdateConfidence(t *testing.T) {
 		}
 	}
 
+	// The change is attributed to reinforcement
+	sources, ok := confidence.FromMetadata(node.Metadata)
+	if !ok {
+		t.Fatal("confidence sources should be recorded")
+	}
+	if sources.Origin != confidence.OriginUntracked || sources.Base != 0.6 {
+		t.Errorf("base = %v (%s), want 0.6 (untracked)", sources.Base, sources.Origin)
+	}
+	if math.Abs(sources.Reinforcement-0.35) > 1e-9 {
+		t.Errorf("reinforcement = %v, want 0.35", sources.Reinforcement)
+	}
+
 	// Update confidence for non-existent behavior
 	err = store.UpdateConfidence(ctx, "nonexistent", 0.5)
 	if err == nil {
@@ -3310,6 +3420,84 @@ func TestSQLiteGraphStore_QueryNodes_ByScope(t *testing.T) {
 	}
 }
 
+func TestQueryNodes_BehaviorPredicates(t *testing.T) {
+	jan := time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)
+	jun := time.Date(2026, 6, 10, 9, 0, 0, 0, time.FixedZone("PDT", -7*3600))
+	nodes := []Node{
+		{
+			ID:   "learned",
+			Kind: NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":       "wrap errors",
+				"kind":       "directive",
+				"content":    map[string]interface{}{"canonical": "Wrap errors with context", "tags": []string{"go", "errors"}},
+				"provenance": map[string]interface{}{"source_type": "learned", "created_at": jun.Format(time.RFC3339)},
+			},
+			Metadata: map[string]interface{}{"confidence": 0.9, "snoozed_until": jun.AddDate(0, 0, 7).Format(time.RFC3339)},
+		},
+		{
+			ID:   "packed",
+			Kind: NodeKindBehavior,
+			Content: map[string]interface{}{
+				"name":    "no secrets",
+				"kind":    "constraint",
+				"content": map[string]interface{}{"canonical": "Never commit secrets", "tags": []string{"security"}},
+			},
+			Metadata: map[string]interface{}{
+				"confidence": 0.5,
+				"provenance": map[string]interface{}{"package": "team/core", "created_at": jan.Format(time.RFC3339)},
+				"expires_at": jan.AddDate(0, 1, 0).Format(time.RFC3339),
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		predicate map[string]interface{}
+		want      []string
+	}{
+		{"behavior kind", map[string]interface{}{PredicateBehaviorKind: "constraint"}, []string{"packed"}},
+		{"tag", map[string]interface{}{PredicateTag: "go"}, []string{"learned"}},
+		{"tag exact", map[string]interface{}{PredicateTag: "err"}, nil},
+		{"min confidence", map[string]interface{}{PredicateMinConfidence: 0.8}, []string{"learned"}},
+		{"pack", map[string]interface{}{PredicatePack: "team/core"}, []string{"packed"}},
+		{"created after", map[string]interface{}{PredicateCreatedAfter: jan.AddDate(0, 1, 0)}, []string{"learned"}},
+		{"created at", map[string]interface{}{PredicateCreatedAfter: jan}, []string{"learned", "packed"}},
+		{"expires before", map[string]interface{}{PredicateExpiresBefore: jun}, []string{"packed"}},
+		{"expires at", map[string]interface{}{PredicateExpiresBefore: jan.AddDate(0, 1, 0)}, []string{"packed"}},
+		{"not yet expiring", map[string]interface{}{PredicateExpiresBefore: jan}, nil},
+		{"snoozed", map[string]interface{}{PredicateSnoozedAfter: jun}, []string{"learned"}},
+		{"snooze over", map[string]interface{}{PredicateSnoozedAfter: jun.AddDate(0, 0, 7)}, nil},
+		{"combined", map[string]interface{}{"kind": "behavior", PredicateTag: "security", PredicateMinConfidence: 0.5}, []string{"packed"}},
+	}
+
+	sqliteStore, cleanup := setupTestSQLiteStore(t)
+	defer cleanup()
+	stores := map[string]GraphStore{"sqlite": sqliteStore, "memory": NewInMemoryGraphStore()}
+	ctx := context.Background()
+	for name, s := range stores {
+		for _, n := range nodes {
+			mustAddNode(t, s, ctx, n)
+		}
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				results, err := s.QueryNodes(ctx, tt.predicate)
+				if err != nil {
+					t.Fatalf("QueryNodes() error = %v", err)
+				}
+				var got []string
+				for _, n := range results {
+					got = append(got, n.ID)
+				}
+				sort.Strings(got)
+				if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+					t.Errorf("QueryNodes(%v) = %v, want %v", tt.predicate, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
 func TestSQLiteGraphStore_AddBehavior_StructProvenance(t *testing.T) {
 	store, cleanup := setupTestSQLiteStore(t)
 	defer cleanup()
@@ -3524,3 +3712,57 @@ func TestSQLiteGraphStore_DeleteNode_WithEdges(t *testing.T) {
 		t.Errorf("edges should be removed after DeleteNode, got %d", len(edges))
 	}
 }
+
+func TestClearOrphanedEmbeddings(t *testing.T) {
+	tmpDir := t.TempDir()
+	s, err := NewSQLiteGraphStore(tmpDir)
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore() error = %v", err)
+	}
+	defer s.Close()
+
+	ctx := context.Background()
+
+	nodes := []Node{
+		{ID: "active", Kind: NodeKindBehavior},
+		{ID: "forgotten", Kind: NodeKindForgotten},
+		{ID: "merged", Kind: NodeKindMerged},
+		{ID: "pending", Kind: NodeKindPending},
+		{ID: "dormant", Kind: NodeKindDormant},
+	}
+	for _, n := range nodes {
+		n.Content = map[string]interface{}{
+			"name": n.ID,
+			"kind": "directive",
+			"content": map[string]interface{}{
+				"canonical": n.ID + " content",
+			},
+		}
+		if _, err := s.AddNode(ctx, n); err != nil {
+			t.Fatalf("AddNode(%s) error = %v", n.ID, err)
+		}
+		if err := s.StoreEmbedding(ctx, n.ID, []float32{1, 2, 3}, "test-model"); err != nil {
+			t.Fatalf("StoreEmbedding(%s) error = %v", n.ID, err)
+		}
+	}
+
+	cleared, err := s.ClearOrphanedEmbeddings(ctx)
+	if err != nil {
+		t.Fatalf("ClearOrphanedEmbeddings() error = %v", err)
+	}
+	if cleared != 2 {
+		t.Errorf("cleared = %d, want 2", cleared)
+	}
+
+	embeddings, err := s.GetAllEmbeddings(ctx)
+	if err != nil {
+		t.Fatalf("GetAllEmbeddings() error = %v", err)
+	}
+	kept := make(map[string]bool, len(embeddings))
+	for _, e := range embeddings {
+		kept[e.BehaviorID] = true
+	}
+	if len(kept) != 3 || !kept["active"] || !kept["pending"] || !kept["dormant"] {
+		t.Errorf("remaining embeddings = %v, want active, pending, and dormant", kept)
+	}
+}
@@ -55,6 +55,17 @@ type EdgeWeightUpdate struct {
 	NewWeight float64  // Updated weight value
 }
 
+// Revision is a snapshot of a behavior, recorded each time its kind or
+// content changes.
+type Revision struct {
+	BehaviorID string                 `json:"behavior_id"`
+	Rev        int                    `json:"rev"`
+	Kind       NodeKind               `json:"kind"`
+	Content    map[string]interface{} `json:"content"` // Node content, including when conditions
+	Confidence float64                `json:"confidence"`
+	RecordedAt time.Time              `json:"recorded_at"`
+}
+
 // EdgeKind represents the type of relationship between nodes.
 type EdgeKind string
 
@@ -89,6 +100,40 @@ const (
 	NodeKindForgotten       NodeKind = "forgotten-behavior"
 	NodeKindDeprecated      NodeKind = "deprecated-behavior"
 	NodeKindMerged          NodeKind = "merged-behavior"
+	NodeKindPending         NodeKind = "pending-behavior"
+	NodeKindDormant         NodeKind = "dormant-behavior"
+	NodeKindSession         NodeKind = "session"
+)
+
+// Predicate keys that QueryNodes understands beyond plain field equality,
+// so callers can filter behaviors without loading every node.
+const (
+	// PredicateBehaviorKind matches the behavior kind (content "kind":
+	// directive, constraint, procedure, preference).
+	PredicateBehaviorKind = "behavior_kind"
+
+	// PredicateTag matches behaviors carrying the tag (exact match).
+	PredicateTag = "tag"
+
+	// PredicateMinConfidence matches behaviors whose confidence is at
+	// least the float64 value.
+	PredicateMinConfidence = "min_confidence"
+
+	// PredicatePack matches behaviors installed from the skill pack
+	// (provenance "package").
+	PredicatePack = "pack"
+
+	// PredicateCreatedAfter matches behaviors created at or after the
+	// time.Time value.
+	PredicateCreatedAfter = "created_after"
+
+	// PredicateExpiresBefore matches behaviors with an expiry (metadata
+	// "expires_at") at or before the time.Time value.
+	PredicateExpiresBefore = "expires_before"
+
+	// PredicateSnoozedAfter matches behaviors snoozed (metadata
+	// "snoozed_until") past the time.Time value.
+	PredicateSnoozedAfter = "snoozed_after"
 )
 
 // Direction specifies edge traversal direction.
@@ -110,8 +155,9 @@ type GraphStore interface {
 
 	// QueryNodes queries nodes by predicate.
 	// Predicate is a map of field names to required values.
-	// Supports flat key matching only (e.g., "kind", "id").
-	// e.g., {"kind": "behavior"}
+	// Supports flat key matching (e.g., "kind", "id", "scope") and the
+	// Predicate* keys.
+	// e.g., {"kind": "behavior", PredicateTag: "go"}
 	QueryNodes(ctx context.Context, predicate map[string]interface{}) ([]Node, error)
 
 	// Edge operations
@@ -139,9 +185,16 @@ type ExtendedGraphStore interface {
 	// RecordActivationHit records that a behavior was activated.
 	RecordActivationHit(ctx context.Context, behaviorID string) error
 
+	// RecordSessionActivation records that a behavior was activated in a
+	// session, counting each session once in sessions_activated.
+	RecordSessionActivation(ctx context.Context, behaviorID, sessionID string) error
+
 	// RecordConfirmed records that a behavior was confirmed by the user.
 	RecordConfirmed(ctx context.Context, behaviorID string) error
 
+	// RecordFollowed records that a behavior was observed being followed.
+	RecordFollowed(ctx context.Context, behaviorID string) error
+
 	// RecordOverridden records that a behavior was overridden by the user.
 	RecordOverridden(ctx context.Context, behaviorID string) error
 
@@ -156,6 +209,9 @@ type ExtendedGraphStore interface {
 
 	// ValidateBehaviorGraph checks the graph for consistency issues.
 	ValidateBehaviorGraph(ctx context.Context) ([]ValidationError, error)
+
+	// BehaviorHistory returns the revisions of a behavior, oldest first.
+	BehaviorHistory(ctx context.Context, behaviorID string) ([]Revision, error)
 }
 
 // CoActivationStore provides persistence for Hebbian co-activation tracking.
@@ -175,6 +231,7 @@ type CoActivationStore interface {
 type BehaviorEmbedding struct {
 	BehaviorID string
 	Embedding  []float32
+	ModelName  string // model that produced the embedding; empty if unknown
 }
 
 // EmbeddingStore provides embedding vector persistence.
@@ -185,3 +242,10 @@ type EmbeddingStore interface {
 	GetAllEmbeddings(ctx context.Context) ([]BehaviorEmbedding, error)
 	GetBehaviorIDsWithoutEmbeddings(ctx context.Context) ([]string, error)
 }
+
+// TextSearcher provides full-text search over behavior content.
+// SQLiteGraphStore implements this interface; consumers type-assert for it
+// and fall back to scanning behaviors when it is missing.
+type TextSearcher interface {
+	SearchText(ctx context.Context, query string, matchAll bool, limit int) ([]TextMatch, error)
+}
@@ -0,0 +1,377 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/nvandessel/floop/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracedGraphStore wraps a GraphStore and records an OpenTelemetry span for
+// every operation, named store.<Operation> and tagged with the store's
+// name. Extended, embedding, and co-activation operations are delegated
+// when the wrapped store supports them. Thread-safe if the wrapped store is.
+type TracedGraphStore struct {
+	inner GraphStore
+	name  string
+}
+
+// NewTracedGraphStore wraps inner with tracing. name identifies the store
+// in spans, such as "local" or "global".
+func NewTracedGraphStore(inner GraphStore, name string) *TracedGraphStore {
+	return &TracedGraphStore{inner: inner, name: name}
+}
+
+// Unwrap returns the wrapped store.
+func (t *TracedGraphStore) Unwrap() GraphStore {
+	return t.inner
+}
+
+// start starts the span for op.
+func (t *TracedGraphStore) start(ctx context.Context, op string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "store."+op, attribute.String("floop.store", t.name))
+}
+
+// AddNode adds a node in a span.
+func (t *TracedGraphStore) AddNode(ctx context.Context, node Node) (id string, err error) {
+	ctx, span := t.start(ctx, "AddNode")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.AddNode(ctx, node)
+}
+
+// UpdateNode updates a node in a span.
+func (t *TracedGraphStore) UpdateNode(ctx context.Context, node Node) (err error) {
+	ctx, span := t.start(ctx, "UpdateNode")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.UpdateNode(ctx, node)
+}
+
+// GetNode retrieves a node in a span.
+func (t *TracedGraphStore) GetNode(ctx context.Context, id string) (node *Node, err error) {
+	ctx, span := t.start(ctx, "GetNode")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.GetNode(ctx, id)
+}
+
+// DeleteNode deletes a node in a span.
+func (t *TracedGraphStore) DeleteNode(ctx context.Context, id string) (err error) {
+	ctx, span := t.start(ctx, "DeleteNode")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.DeleteNode(ctx, id)
+}
+
+// QueryNodes queries nodes in a span that records the result count.
+func (t *TracedGraphStore) QueryNodes(ctx context.Context, predicate map[string]interface{}) (nodes []Node, err error) {
+	ctx, span := t.start(ctx, "QueryNodes")
+	defer func() {
+		span.SetAttributes(attribute.Int("floop.store.results", len(nodes)))
+		tracing.End(span, err)
+	}()
+	return t.inner.QueryNodes(ctx, predicate)
+}
+
+// AddEdge adds an edge in a span.
+func (t *TracedGraphStore) AddEdge(ctx context.Context, edge Edge) (err error) {
+	ctx, span := t.start(ctx, "AddEdge")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.AddEdge(ctx, edge)
+}
+
+// RemoveEdge removes an edge in a span.
+func (t *TracedGraphStore) RemoveEdge(ctx context.Context, source, target string, kind EdgeKind) (err error) {
+	ctx, span := t.start(ctx, "RemoveEdge")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.RemoveEdge(ctx, source, target, kind)
+}
+
+// GetEdges retrieves edges in a span.
+func (t *TracedGraphStore) GetEdges(ctx context.Context, nodeID string, direction Direction, kind EdgeKind) (edges []Edge, err error) {
+	ctx, span := t.start(ctx, "GetEdges")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.GetEdges(ctx, nodeID, direction, kind)
+}
+
+// Traverse walks the graph in a span.
+func (t *TracedGraphStore) Traverse(ctx context.Context, start string, edgeKinds []EdgeKind, direction Direction, maxDepth int) (nodes []Node, err error) {
+	ctx, span := t.start(ctx, "Traverse")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.Traverse(ctx, start, edgeKinds, direction, maxDepth)
+}
+
+// Sync flushes the wrapped store in a span.
+func (t *TracedGraphStore) Sync(ctx context.Context) (err error) {
+	ctx, span := t.start(ctx, "Sync")
+	defer func() { tracing.End(span, err) }()
+	return t.inner.Sync(ctx)
+}
+
+// Close closes the wrapped store. It has no context, so it is not traced.
+func (t *TracedGraphStore) Close() error {
+	return t.inner.Close()
+}
+
+// extended returns the wrapped store as an ExtendedGraphStore.
+func (t *TracedGraphStore) extended(op string) (ExtendedGraphStore, error) {
+	es, ok := t.inner.(ExtendedGraphStore)
+	if !ok {
+		return nil, fmt.Errorf("%s: wrapped store does not support extended operations", op)
+	}
+	return es, nil
+}
+
+// UpdateConfidence delegates to the wrapped store in a span.
+func (t *TracedGraphStore) UpdateConfidence(ctx context.Context, behaviorID string, newConfidence float64) (err error) {
+	es, err := t.extended("UpdateConfidence")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "UpdateConfidence")
+	defer func() { tracing.End(span, err) }()
+	return es.UpdateConfidence(ctx, behaviorID, newConfidence)
+}
+
+// RecordActivationHit delegates to the wrapped store in a span.
+func (t *TracedGraphStore) RecordActivationHit(ctx context.Context, behaviorID string) (err error) {
+	es, err := t.extended("RecordActivationHit")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "RecordActivationHit")
+	defer func() { tracing.End(span, err) }()
+	return es.RecordActivationHit(ctx, behaviorID)
+}
+
+// RecordSessionActivation delegates to the wrapped store in a span.
+func (t *TracedGraphStore) RecordSessionActivation(ctx context.Context, behaviorID, sessionID string) (err error) {
+	es, err := t.extended("RecordSessionActivation")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "RecordSessionActivation")
+	defer func() { tracing.End(span, err) }()
+	return es.RecordSessionActivation(ctx, behaviorID, sessionID)
+}
+
+// RecordConfirmed delegates to the wrapped store in a span.
+func (t *TracedGraphStore) RecordConfirmed(ctx context.Context, behaviorID string) (err error) {
+	es, err := t.extended("RecordConfirmed")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "RecordConfirmed")
+	defer func() { tracing.End(span, err) }()
+	return es.RecordConfirmed(ctx, behaviorID)
+}
+
+// RecordFollowed delegates to the wrapped store in a span.
+func (t *TracedGraphStore) RecordFollowed(ctx context.Context, behaviorID string) (err error) {
+	es, err := t.extended("RecordFollowed")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "RecordFollowed")
+	defer func() { tracing.End(span, err) }()
+	return es.RecordFollowed(ctx, behaviorID)
+}
+
+// BehaviorHistory delegates to the wrapped store in a span.
+func (t *TracedGraphStore) BehaviorHistory(ctx context.Context, behaviorID string) (revisions []Revision, err error) {
+	es, err := t.extended("BehaviorHistory")
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := t.start(ctx, "BehaviorHistory")
+	defer func() { tracing.End(span, err) }()
+	return es.BehaviorHistory(ctx, behaviorID)
+}
+
+// RecordOverridden delegates to the wrapped store in a span.
+func (t *TracedGraphStore) RecordOverridden(ctx context.Context, behaviorID string) (err error) {
+	es, err := t.extended("RecordOverridden")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "RecordOverridden")
+	defer func() { tracing.End(span, err) }()
+	return es.RecordOverridden(ctx, behaviorID)
+}
+
+// TouchEdges delegates to the wrapped store in a span.
+func (t *TracedGraphStore) TouchEdges(ctx context.Context, behaviorIDs []string) (err error) {
+	es, err := t.extended("TouchEdges")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "TouchEdges")
+	defer func() { tracing.End(span, err) }()
+	return es.TouchEdges(ctx, behaviorIDs)
+}
+
+// BatchUpdateEdgeWeights delegates to the wrapped store in a span.
+func (t *TracedGraphStore) BatchUpdateEdgeWeights(ctx context.Context, updates []EdgeWeightUpdate) (err error) {
+	es, err := t.extended("BatchUpdateEdgeWeights")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "BatchUpdateEdgeWeights")
+	defer func() { tracing.End(span, err) }()
+	return es.BatchUpdateEdgeWeights(ctx, updates)
+}
+
+// PruneWeakEdges delegates to the wrapped store in a span.
+func (t *TracedGraphStore) PruneWeakEdges(ctx context.Context, kind EdgeKind, threshold float64) (pruned int, err error) {
+	es, err := t.extended("PruneWeakEdges")
+	if err != nil {
+		return 0, err
+	}
+	ctx, span := t.start(ctx, "PruneWeakEdges")
+	defer func() { tracing.End(span, err) }()
+	return es.PruneWeakEdges(ctx, kind, threshold)
+}
+
+// ValidateBehaviorGraph delegates to the wrapped store in a span.
+func (t *TracedGraphStore) ValidateBehaviorGraph(ctx context.Context) (errs []ValidationError, err error) {
+	es, err := t.extended("ValidateBehaviorGraph")
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := t.start(ctx, "ValidateBehaviorGraph")
+	defer func() { tracing.End(span, err) }()
+	return es.ValidateBehaviorGraph(ctx)
+}
+
+// embeddings returns the wrapped store as an EmbeddingStore.
+func (t *TracedGraphStore) embeddings(op string) (EmbeddingStore, error) {
+	es, ok := t.inner.(EmbeddingStore)
+	if !ok {
+		return nil, fmt.Errorf("%s: wrapped store does not support embeddings", op)
+	}
+	return es, nil
+}
+
+// StoreEmbedding delegates to the wrapped store in a span.
+func (t *TracedGraphStore) StoreEmbedding(ctx context.Context, behaviorID string, embedding []float32, modelName string) (err error) {
+	es, err := t.embeddings("StoreEmbedding")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "StoreEmbedding")
+	defer func() { tracing.End(span, err) }()
+	return es.StoreEmbedding(ctx, behaviorID, embedding, modelName)
+}
+
+// GetAllEmbeddings delegates to the wrapped store in a span.
+func (t *TracedGraphStore) GetAllEmbeddings(ctx context.Context) (embeddings []BehaviorEmbedding, err error) {
+	es, err := t.embeddings("GetAllEmbeddings")
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := t.start(ctx, "GetAllEmbeddings")
+	defer func() { tracing.End(span, err) }()
+	return es.GetAllEmbeddings(ctx)
+}
+
+// GetBehaviorIDsWithoutEmbeddings delegates to the wrapped store in a span.
+func (t *TracedGraphStore) GetBehaviorIDsWithoutEmbeddings(ctx context.Context) (ids []string, err error) {
+	es, err := t.embeddings("GetBehaviorIDsWithoutEmbeddings")
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := t.start(ctx, "GetBehaviorIDsWithoutEmbeddings")
+	defer func() { tracing.End(span, err) }()
+	return es.GetBehaviorIDsWithoutEmbeddings(ctx)
+}
+
+// ClearOrphanedEmbeddings delegates to the wrapped store in a span when it
+// supports clearing embeddings.
+func (t *TracedGraphStore) ClearOrphanedEmbeddings(ctx context.Context) (cleared int, err error) {
+	c, ok := t.inner.(interface {
+		ClearOrphanedEmbeddings(ctx context.Context) (int, error)
+	})
+	if !ok {
+		return 0, nil
+	}
+	ctx, span := t.start(ctx, "ClearOrphanedEmbeddings")
+	defer func() { tracing.End(span, err) }()
+	return c.ClearOrphanedEmbeddings(ctx)
+}
+
+// SearchText delegates to the wrapped store in a span.
+func (t *TracedGraphStore) SearchText(ctx context.Context, query string, matchAll bool, limit int) (matches []TextMatch, err error) {
+	ts, ok := t.inner.(TextSearcher)
+	if !ok {
+		return nil, fmt.Errorf("SearchText: wrapped store does not support full-text search")
+	}
+	ctx, span := t.start(ctx, "SearchText")
+	defer func() { tracing.End(span, err) }()
+	return ts.SearchText(ctx, query, matchAll, limit)
+}
+
+// QueryNodesPage delegates to the wrapped store in a span.
+func (t *TracedGraphStore) QueryNodesPage(ctx context.Context, predicate map[string]interface{}, opts PageOptions) (page NodePage, err error) {
+	ctx, span := t.start(ctx, "QueryNodesPage")
+	defer func() { tracing.End(span, err) }()
+	return QueryPage(ctx, t.inner, predicate, opts)
+}
+
+// BatchAdd delegates to the wrapped store in a span.
+func (t *TracedGraphStore) BatchAdd(ctx context.Context, nodes []Node, edges []Edge) (err error) {
+	ctx, span := t.start(ctx, "BatchAdd")
+	defer func() { tracing.End(span, err) }()
+	return BatchAdd(ctx, t.inner, nodes, edges)
+}
+
+// coActivations returns the wrapped store as a CoActivationStore.
+func (t *TracedGraphStore) coActivations(op string) (CoActivationStore, error) {
+	cs, ok := t.inner.(CoActivationStore)
+	if !ok {
+		return nil, fmt.Errorf("%s: wrapped store does not support co-activations", op)
+	}
+	return cs, nil
+}
+
+// RecordCoActivation delegates to the wrapped store in a span.
+func (t *TracedGraphStore) RecordCoActivation(ctx context.Context, pairKey string, at time.Time) (err error) {
+	cs, err := t.coActivations("RecordCoActivation")
+	if err != nil {
+		return err
+	}
+	ctx, span := t.start(ctx, "RecordCoActivation")
+	defer func() { tracing.End(span, err) }()
+	return cs.RecordCoActivation(ctx, pairKey, at)
+}
+
+// GetCoActivations delegates to the wrapped store in a span.
+func (t *TracedGraphStore) GetCoActivations(ctx context.Context, pairKey string, since time.Time) (times []time.Time, err error) {
+	cs, err := t.coActivations("GetCoActivations")
+	if err != nil {
+		return nil, err
+	}
+	ctx, span := t.start(ctx, "GetCoActivations")
+	defer func() { tracing.End(span, err) }()
+	return cs.GetCoActivations(ctx, pairKey, since)
+}
+
+// PruneCoActivations delegates to the wrapped store in a span.
+func (t *TracedGraphStore) PruneCoActivations(ctx context.Context, before time.Time) (pruned int, err error) {
+	cs, err := t.coActivations("PruneCoActivations")
+	if err != nil {
+		return 0, err
+	}
+	ctx, span := t.start(ctx, "PruneCoActivations")
+	defer func() { tracing.End(span, err) }()
+	return cs.PruneCoActivations(ctx, before)
+}
+
+// Compile-time interface checks.
+var (
+	_ ExtendedGraphStore = (*TracedGraphStore)(nil)
+	_ EmbeddingStore     = (*TracedGraphStore)(nil)
+	_ TextSearcher       = (*TracedGraphStore)(nil)
+	_ NodePager          = (*TracedGraphStore)(nil)
+	_ BatchWriter        = (*TracedGraphStore)(nil)
+	_ CoActivationStore  = (*TracedGraphStore)(nil)
+)
@@ -0,0 +1,68 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedGraphStore_Spans(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ctx := context.Background()
+	s := NewTracedGraphStore(NewChaosGraphStore(NewInMemoryGraphStore(), ChaosConfig{WriteFailureRate: 1}), "local")
+
+	if _, err := s.QueryNodes(ctx, nil); err != nil {
+		t.Fatalf("QueryNodes error = %v", err)
+	}
+	if _, err := s.AddNode(ctx, Node{ID: "b-1", Kind: NodeKindBehavior}); !errors.Is(err, ErrInjectedFault) {
+		t.Fatalf("AddNode error = %v, want ErrInjectedFault", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	if spans[0].Name() != "store.QueryNodes" || spans[1].Name() != "store.AddNode" {
+		t.Errorf("span names = %q, %q", spans[0].Name(), spans[1].Name())
+	}
+	for _, span := range spans {
+		if !hasAttribute(span.Attributes(), attribute.String("floop.store", "local")) {
+			t.Errorf("%s missing floop.store attribute", span.Name())
+		}
+	}
+	if spans[1].Status().Code != codes.Error {
+		t.Errorf("failed AddNode span status = %v, want error", spans[1].Status().Code)
+	}
+}
+
+func TestUnwrapSQLite_Traced(t *testing.T) {
+	sqlite, err := NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewSQLiteGraphStore: %v", err)
+	}
+	t.Cleanup(func() { sqlite.Close() })
+
+	got, ok := unwrapSQLite(NewTracedGraphStore(sqlite, "local"))
+	if !ok || got != sqlite {
+		t.Error("unwrapSQLite should see through TracedGraphStore")
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, kv := range attrs {
+		if kv == want {
+			return true
+		}
+	}
+	return false
+}
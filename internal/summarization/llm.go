@@ -0,0 +1,142 @@
+package summarization
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+// LLMSummarizer implements Summarizer with rule-based compression first and
+// an LLM as the fallback for canonical content the rules can only truncate.
+// Generated summaries are cached by behavior ID and canonical content, so
+// repeated token estimates during tiering do not repeat LLM calls.
+type LLMSummarizer struct {
+	rules  *RuleSummarizer
+	client llm.Client
+	config SummarizerConfig
+
+	mu    sync.Mutex
+	cache map[string]string
+}
+
+// NewLLMSummarizer creates a summarizer that falls back to client when
+// rule-based compression would truncate. A nil or unavailable client makes
+// it behave like the rule summarizer.
+func NewLLMSummarizer(client llm.Client, config SummarizerConfig) *LLMSummarizer {
+	if config.MaxLength <= 0 {
+		config.MaxLength = 60
+	}
+	return &LLMSummarizer{
+		rules:  &RuleSummarizer{config: config},
+		client: client,
+		config: config,
+		cache:  make(map[string]string),
+	}
+}
+
+// Summarize generates a short summary for a behavior. LLM failures are not
+// returned: the rule-based summary is used instead.
+func (s *LLMSummarizer) Summarize(behavior *models.Behavior) (string, error) {
+	summary, err := s.rules.Summarize(behavior)
+	if err != nil || !strings.HasSuffix(summary, "...") || summary == behavior.Content.Summary {
+		return summary, err
+	}
+	if s.client == nil || !s.client.Available() {
+		return summary, nil
+	}
+
+	key := behavior.ID + "\x00" + behavior.Content.Canonical
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	if generated, err := s.generate(behavior); err == nil {
+		summary = generated
+	}
+	s.mu.Lock()
+	s.cache[key] = summary
+	s.mu.Unlock()
+	return summary, nil
+}
+
+// SummarizeBatch generates summaries for multiple behaviors
+func (s *LLMSummarizer) SummarizeBatch(behaviors []*models.Behavior) (map[string]string, error) {
+	results := make(map[string]string, len(behaviors))
+	for _, b := range behaviors {
+		if b == nil {
+			continue
+		}
+		summary, err := s.Summarize(b)
+		if err != nil {
+			return nil, err
+		}
+		results[b.ID] = summary
+	}
+	return results, nil
+}
+
+// generate asks the LLM for a summary within MaxLength.
+func (s *LLMSummarizer) generate(behavior *models.Behavior) (string, error) {
+	response, err := s.client.Complete(context.Background(), []llm.Message{
+		{Role: "user", Content: summaryPrompt(behavior, s.config.MaxLength)},
+	})
+	if err != nil {
+		return "", fmt.Errorf("requesting summary: %w", err)
+	}
+	summary, err := parseSummaryResponse(response)
+	if err != nil {
+		return "", err
+	}
+	if len(summary) > s.config.MaxLength {
+		return "", fmt.Errorf("summary exceeds %d characters", s.config.MaxLength)
+	}
+	return summary, nil
+}
+
+// summaryPrompt builds the LLM prompt for summarizing a behavior.
+func summaryPrompt(behavior *models.Behavior, maxLength int) string {
+	return fmt.Sprintf(`Summarize this coding agent %s in at most %d characters.
+Keep the actionable instruction; drop rationale and examples.
+%s
+Behavior:
+%s
+
+## Response Format
+Respond with ONLY a JSON object (no markdown code blocks, no additional text):
+{"summary": "<the summary>"}`, behavior.Kind, maxLength, constraintHint(behavior.Kind), behavior.Content.Canonical)
+}
+
+// constraintHint asks for constraint summaries to keep their prohibition.
+func constraintHint(kind models.BehaviorKind) string {
+	if kind == models.BehaviorKindConstraint {
+		return "It is a constraint: start the summary with \"Never\".\n"
+	}
+	return ""
+}
+
+// parseSummaryResponse extracts the summary from an LLM response.
+func parseSummaryResponse(response string) (string, error) {
+	jsonStr := llm.ExtractJSON(response)
+	if jsonStr == "" {
+		return "", fmt.Errorf("no JSON found in response")
+	}
+	var result struct {
+		Summary string `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return "", fmt.Errorf("parsing summary response: %w", err)
+	}
+	summary := strings.TrimSpace(result.Summary)
+	if summary == "" {
+		return "", fmt.Errorf("empty summary in response")
+	}
+	return summary, nil
+}
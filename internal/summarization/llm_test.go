@@ -0,0 +1,84 @@
+package summarization
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/models"
+)
+
+const longCanonical = "Wrap every error returned from a storage call with the operation name and the offending identifier so logs can be traced"
+
+func TestLLMSummarizer_ShortContentSkipsLLM(t *testing.T) {
+	client := llm.NewMockClient().WithCompleteResponse(`{"summary": "unused"}`)
+	s := NewLLMSummarizer(client, DefaultConfig())
+
+	got, err := s.Summarize(&models.Behavior{ID: "b1", Kind: models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: "Use table-driven tests"}})
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if got != "Use table-driven tests" {
+		t.Errorf("Summarize() = %q", got)
+	}
+	if len(client.CompleteCalls) != 0 {
+		t.Errorf("LLM called %d times for content the rules can compress", len(client.CompleteCalls))
+	}
+}
+
+func TestLLMSummarizer_FallsBackToLLM(t *testing.T) {
+	client := llm.NewMockClient().WithCompleteResponse(`{"summary": "Wrap storage errors with op and ID"}`)
+	s := NewLLMSummarizer(client, DefaultConfig())
+	b := &models.Behavior{ID: "b1", Kind: models.BehaviorKindDirective,
+		Content: models.BehaviorContent{Canonical: longCanonical}}
+
+	for i := 0; i < 2; i++ {
+		got, err := s.Summarize(b)
+		if err != nil {
+			t.Fatalf("Summarize() error = %v", err)
+		}
+		if got != "Wrap storage errors with op and ID" {
+			t.Errorf("Summarize() = %q", got)
+		}
+	}
+	if len(client.CompleteCalls) != 1 {
+		t.Errorf("LLM called %d times, want 1 (cached)", len(client.CompleteCalls))
+	}
+}
+
+func TestLLMSummarizer_LLMFailureUsesRules(t *testing.T) {
+	rules, _ := NewRuleSummarizer(DefaultConfig()).Summarize(&models.Behavior{
+		Kind: models.BehaviorKindDirective, Content: models.BehaviorContent{Canonical: longCanonical}})
+
+	tests := []struct {
+		name   string
+		client llm.Client
+	}{
+		{"nil client", nil},
+		{"unavailable", llm.NewMockClient().WithAvailable(false)},
+		{"error", llm.NewMockClient().WithError(errors.New("boom"))},
+		{"no json", llm.NewMockClient().WithCompleteResponse("sure thing")},
+		{"too long", llm.NewMockClient().WithCompleteResponse(`{"summary": "` + longCanonical + `"}`)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewLLMSummarizer(tt.client, DefaultConfig())
+			got, err := s.Summarize(&models.Behavior{ID: "b1", Kind: models.BehaviorKindDirective,
+				Content: models.BehaviorContent{Canonical: longCanonical}})
+			if err != nil {
+				t.Fatalf("Summarize() error = %v", err)
+			}
+			if got != rules {
+				t.Errorf("Summarize() = %q, want rule summary %q", got, rules)
+			}
+		})
+	}
+}
+
+func TestLLMSummarizer_NilBehavior(t *testing.T) {
+	s := NewLLMSummarizer(llm.NewMockClient(), DefaultConfig())
+	if got, err := s.Summarize(nil); got != "" || err != nil {
+		t.Errorf("Summarize(nil) = %q, %v", got, err)
+	}
+}
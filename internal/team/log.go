@@ -0,0 +1,204 @@
+// Package team shares behaviors between teammates through a git-tracked,
+// append-only log at .floop/team.jsonl.
+//
+// Each line of the log is a Record: one revision of one behavior. Records
+// are only ever appended, and the current state of a behavior is the
+// record that wins under a fixed total order (revision, then timestamp,
+// then content hash). Because the winner does not depend on line order or
+// duplicates, concatenating two teammates' logs — which is what git's
+// union merge driver does — yields the same state whichever way the merge
+// goes.
+//
+// Shared behaviors live in the project's local store with scope "team";
+// Sync reconciles them with the log.
+package team
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// FileName is the team log's name inside the .floop directory.
+const FileName = "team.jsonl"
+
+// gitattributesLine tells git to merge the log by keeping both sides'
+// lines, so concurrent appends never conflict.
+const gitattributesLine = FileName + " merge=union"
+
+// Record is one revision of a shared behavior.
+type Record struct {
+	ID        string    `json:"id"`
+	Rev       int       `json:"rev"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Author    string    `json:"author,omitempty"`
+	// Deleted marks a tombstone: the behavior is no longer shared.
+	Deleted bool `json:"deleted,omitempty"`
+	// Node is the behavior as shared; nil for tombstones.
+	Node *store.Node `json:"node,omitempty"`
+}
+
+// Hash identifies the shared content of the record's node.
+func (r Record) Hash() string {
+	if r.Node == nil {
+		return ""
+	}
+	return NodeHash(*r.Node)
+}
+
+// supersedes reports whether r wins over o for the same behavior ID.
+func (r Record) supersedes(o Record) bool {
+	if r.Rev != o.Rev {
+		return r.Rev > o.Rev
+	}
+	if !r.UpdatedAt.Equal(o.UpdatedAt) {
+		return r.UpdatedAt.After(o.UpdatedAt)
+	}
+	if r.Deleted != o.Deleted {
+		return r.Deleted
+	}
+	return r.Hash() > o.Hash()
+}
+
+// NodeHash hashes the parts of a node that are shared with the team: its
+// kind and content. Metadata such as stats and confidence stays personal.
+func NodeHash(n store.Node) string {
+	data, _ := json.Marshal(struct {
+		Kind    store.NodeKind         `json:"kind"`
+		Content map[string]interface{} `json:"content"`
+	}{n.Kind, n.Content})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Path returns the team log path for a .floop directory.
+func Path(floopDir string) string {
+	return filepath.Join(floopDir, FileName)
+}
+
+// Read returns the winning record for each behavior ID in the log at path.
+// A missing log is empty. Malformed lines, such as conflict markers from a
+// merge without the union driver, are skipped and counted.
+func Read(path string) (map[string]Record, int, error) {
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return map[string]Record{}, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to open team log: %w", err)
+	}
+	defer f.Close()
+
+	winners := make(map[string]Record)
+	skipped := 0
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 10*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var r Record
+		if err := json.Unmarshal([]byte(line), &r); err != nil || r.ID == "" || (!r.Deleted && r.Node == nil) {
+			skipped++
+			continue
+		}
+		if cur, ok := winners[r.ID]; !ok || r.supersedes(cur) {
+			winners[r.ID] = r
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, fmt.Errorf("failed to read team log: %w", err)
+	}
+	return winners, skipped, nil
+}
+
+// Append adds records to the end of the log at path.
+func Append(path string, records []Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open team log: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			f.Close()
+			return fmt.Errorf("failed to append to team log: %w", err)
+		}
+	}
+	return f.Close()
+}
+
+// Compact rewrites the log at path to hold only the winning record for
+// each behavior, sorted by ID. Tombstones are kept so teammates who have
+// not synced yet still remove the behavior.
+func Compact(path string) (int, error) {
+	winners, _, err := Read(path)
+	if err != nil {
+		return 0, err
+	}
+	ids := make([]string, 0, len(winners))
+	for id := range winners {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	tmp := path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create team log: %w", err)
+	}
+	enc := json.NewEncoder(f)
+	for _, id := range ids {
+		if err := enc.Encode(winners[id]); err != nil {
+			f.Close()
+			os.Remove(tmp)
+			return 0, fmt.Errorf("failed to write team log: %w", err)
+		}
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return 0, err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to replace team log: %w", err)
+	}
+	return len(ids), nil
+}
+
+// EnsureGitattributes adds the union merge rule for the team log to
+// .floop/.gitattributes, creating the file if needed.
+func EnsureGitattributes(floopDir string) error {
+	path := filepath.Join(floopDir, ".gitattributes")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to read .gitattributes: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.TrimSpace(line) == gitattributesLine {
+			return nil
+		}
+	}
+	content := string(data)
+	if content != "" && !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += gitattributesLine + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		return fmt.Errorf("failed to write .gitattributes: %w", err)
+	}
+	return nil
+}
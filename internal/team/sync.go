@@ -0,0 +1,294 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/nvandessel/floop/internal/constants"
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Metadata keys recording a team behavior's last synced revision and the
+// hash of its content at that point, used to spot local edits.
+const (
+	MetaRev  = "team_rev"
+	MetaHash = "team_hash"
+)
+
+// Options configures Sync.
+type Options struct {
+	// Author is recorded on published revisions.
+	Author string
+	// Now stamps published revisions; defaults to time.Now().
+	Now time.Time
+	// DryRun reports what would change without writing.
+	DryRun bool
+}
+
+// Result summarizes a sync. Each list holds behavior IDs.
+type Result struct {
+	// Imported are team behaviors new to the local store.
+	Imported []string `json:"imported"`
+	// Updated are local copies replaced by a newer team revision.
+	Updated []string `json:"updated"`
+	// Removed are local copies of behaviors a teammate stopped sharing.
+	Removed []string `json:"removed"`
+	// Published are local edits and newly shared behaviors appended to the log.
+	Published []string `json:"published"`
+	// Conflicts describe local edits overwritten by a newer team revision,
+	// and team behaviors that could not be imported.
+	Conflicts []string `json:"conflicts"`
+	// Skipped counts malformed log lines.
+	Skipped int `json:"skipped"`
+}
+
+// IsTeam reports whether a node is a shared team behavior.
+func IsTeam(n store.Node) bool {
+	scope, _ := n.Metadata["scope"].(string)
+	return scope == string(constants.ScopeTeam)
+}
+
+// Sync reconciles the team behaviors in s, the project's local store, with
+// the team log at logPath. Newer team revisions are imported, and local
+// edits and newly shared behaviors are published as new revisions. When a
+// behavior changed on both sides, the team revision wins and the local
+// edit is reported as a conflict.
+func Sync(ctx context.Context, s store.GraphStore, logPath string, opts Options) (*Result, error) {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	winners, skipped, err := Read(logPath)
+	if err != nil {
+		return nil, err
+	}
+	result := &Result{Skipped: skipped}
+
+	nodes, err := s.QueryNodes(ctx, map[string]interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to query local store: %w", err)
+	}
+	local := make(map[string]store.Node, len(nodes))
+	for _, n := range nodes {
+		local[n.ID] = n
+	}
+
+	var publish []Record
+	ids := make([]string, 0, len(winners))
+	for id := range winners {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		rec := winners[id]
+		n, exists := local[id]
+		switch {
+		case !exists:
+			if rec.Deleted {
+				continue
+			}
+			if err := importRecord(ctx, s, rec, nil, opts.DryRun); err != nil {
+				if !isDuplicate(err) {
+					return nil, err
+				}
+				result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s: same content as local behavior %s; not imported", id, duplicateOf(err)))
+				continue
+			}
+			result.Imported = append(result.Imported, id)
+
+		case !IsTeam(n):
+			if !rec.Deleted {
+				result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s: a local behavior that is not shared has the same ID; not imported", id))
+			}
+
+		default:
+			lastRev, synced := syncedRev(n)
+			edited := !synced || NodeHash(n) != n.Metadata[MetaHash]
+			switch {
+			case !synced || (rec.Rev <= lastRev && edited) || rec.Rev < lastRev:
+				// Shared again after removal, edited locally since the last
+				// sync, or missing from a log that was reset.
+				publish = append(publish, newRecord(n, max(rec.Rev, lastRev)+1, opts))
+				result.Published = append(result.Published, id)
+			case rec.Rev > lastRev:
+				if edited {
+					result.Conflicts = append(result.Conflicts, fmt.Sprintf("%s: local edit replaced by team revision %d", id, rec.Rev))
+				}
+				if rec.Deleted {
+					if !opts.DryRun {
+						if err := s.DeleteNode(ctx, id); err != nil {
+							return nil, fmt.Errorf("failed to remove %s: %w", id, err)
+						}
+					}
+					result.Removed = append(result.Removed, id)
+					continue
+				}
+				if err := importRecord(ctx, s, rec, &n, opts.DryRun); err != nil {
+					return nil, err
+				}
+				result.Updated = append(result.Updated, id)
+			}
+		}
+	}
+
+	// Team behaviors the log has never seen are newly shared.
+	var fresh []string
+	for id, n := range local {
+		if _, inLog := winners[id]; !inLog && IsTeam(n) {
+			fresh = append(fresh, id)
+		}
+	}
+	sort.Strings(fresh)
+	for _, id := range fresh {
+		n := local[id]
+		lastRev, _ := syncedRev(n)
+		publish = append(publish, newRecord(n, lastRev+1, opts))
+		result.Published = append(result.Published, id)
+	}
+
+	if opts.DryRun {
+		return result, nil
+	}
+	if err := Append(logPath, publish); err != nil {
+		return nil, err
+	}
+	for _, r := range publish {
+		n := local[r.ID]
+		if err := markSynced(ctx, s, n, r.Rev); err != nil {
+			return nil, err
+		}
+	}
+	return result, nil
+}
+
+// Share marks a behavior in the local store as a team behavior; the next
+// Sync publishes it.
+func Share(ctx context.Context, s store.GraphStore, id string) error {
+	n, err := s.GetNode(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", id, err)
+	}
+	if n == nil {
+		return fmt.Errorf("behavior not found in local store: %s", id)
+	}
+	if IsTeam(*n) {
+		return nil
+	}
+	n.Metadata["scope"] = string(constants.ScopeTeam)
+	delete(n.Metadata, MetaRev)
+	delete(n.Metadata, MetaHash)
+	return s.UpdateNode(ctx, *n)
+}
+
+// Unshare stops sharing a team behavior: a tombstone is appended to the
+// log so teammates remove their copies on their next sync, and the local
+// copy becomes an ordinary local behavior.
+func Unshare(ctx context.Context, s store.GraphStore, logPath, id string, opts Options) error {
+	if opts.Now.IsZero() {
+		opts.Now = time.Now()
+	}
+	n, err := s.GetNode(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get %s: %w", id, err)
+	}
+	if n == nil || !IsTeam(*n) {
+		return fmt.Errorf("not a team behavior: %s", id)
+	}
+	winners, _, err := Read(logPath)
+	if err != nil {
+		return err
+	}
+	lastRev, _ := syncedRev(*n)
+	rev := max(winners[id].Rev, lastRev) + 1
+	if err := Append(logPath, []Record{{ID: id, Rev: rev, UpdatedAt: opts.Now.UTC(), Author: opts.Author, Deleted: true}}); err != nil {
+		return err
+	}
+	n.Metadata["scope"] = string(constants.ScopeLocal)
+	delete(n.Metadata, MetaRev)
+	delete(n.Metadata, MetaHash)
+	return s.UpdateNode(ctx, *n)
+}
+
+// newRecord builds a revision of a local team behavior. Only the kind,
+// content, confidence, and priority are shared.
+func newRecord(n store.Node, rev int, opts Options) Record {
+	shared := store.Node{
+		ID:      n.ID,
+		Kind:    n.Kind,
+		Content: n.Content,
+		Metadata: map[string]interface{}{
+			"confidence": n.Metadata["confidence"],
+			"priority":   n.Metadata["priority"],
+		},
+	}
+	return Record{ID: n.ID, Rev: rev, UpdatedAt: opts.Now.UTC(), Author: opts.Author, Node: &shared}
+}
+
+// importRecord writes a team revision to the local store. An existing
+// local copy keeps its personal metadata (stats, confidence).
+func importRecord(ctx context.Context, s store.GraphStore, rec Record, existing *store.Node, dryRun bool) error {
+	if dryRun {
+		return nil
+	}
+	n := *rec.Node
+	if existing != nil {
+		n.Metadata = existing.Metadata
+		if p, ok := rec.Node.Metadata["priority"]; ok {
+			n.Metadata["priority"] = p
+		}
+	} else if n.Metadata == nil {
+		n.Metadata = make(map[string]interface{})
+	}
+	n.Metadata["scope"] = string(constants.ScopeTeam)
+
+	if existing != nil {
+		if err := s.UpdateNode(ctx, n); err != nil {
+			return fmt.Errorf("failed to update %s: %w", n.ID, err)
+		}
+	} else if _, err := s.AddNode(ctx, n); err != nil {
+		return err
+	}
+
+	written, err := s.GetNode(ctx, n.ID)
+	if err != nil {
+		return fmt.Errorf("failed to read back %s: %w", n.ID, err)
+	}
+	return markSynced(ctx, s, *written, rec.Rev)
+}
+
+// markSynced records that n matches team revision rev.
+func markSynced(ctx context.Context, s store.GraphStore, n store.Node, rev int) error {
+	n.Metadata[MetaRev] = rev
+	n.Metadata[MetaHash] = NodeHash(n)
+	if err := s.UpdateNode(ctx, n); err != nil {
+		return fmt.Errorf("failed to mark %s synced: %w", n.ID, err)
+	}
+	return nil
+}
+
+// syncedRev returns the team revision a local behavior was last synced at.
+func syncedRev(n store.Node) (int, bool) {
+	switch v := n.Metadata[MetaRev].(type) {
+	case int:
+		return v, true
+	case float64:
+		return int(v), true
+	}
+	return 0, false
+}
+
+func isDuplicate(err error) bool {
+	var dup *store.DuplicateContentError
+	return errors.As(err, &dup)
+}
+
+func duplicateOf(err error) string {
+	var dup *store.DuplicateContentError
+	if errors.As(err, &dup) {
+		return dup.ExistingID
+	}
+	return ""
+}
@@ -0,0 +1,246 @@
+package team
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func teamNode(id, canonical string) store.Node {
+	return store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    id,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": canonical},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.7},
+	}
+}
+
+func record(id string, rev int, at time.Time, canonical string) Record {
+	n := teamNode(id, canonical)
+	return Record{ID: id, Rev: rev, UpdatedAt: at, Node: &n}
+}
+
+func writeLines(t *testing.T, path string, records []Record, extra ...string) {
+	t.Helper()
+	var b strings.Builder
+	for _, r := range records {
+		data, _ := json.Marshal(r)
+		b.Write(data)
+		b.WriteByte('\n')
+	}
+	for _, line := range extra {
+		b.WriteString(line + "\n")
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRead_OrderIndependent(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	records := []Record{
+		record("a", 1, t0, "Use uv"),
+		record("a", 2, t0, "Use uv for installs"),
+		record("b", 1, t0, "Sign commits"),
+		record("b", 1, t0.Add(time.Minute), "Sign commits with GPG"),
+		{ID: "c", Rev: 3, UpdatedAt: t0, Deleted: true},
+		record("c", 2, t0.Add(time.Hour), "Old"),
+	}
+	dir := t.TempDir()
+	forward, backward := filepath.Join(dir, "f.jsonl"), filepath.Join(dir, "b.jsonl")
+	writeLines(t, forward, records, "<<<<<<< HEAD", "")
+	reversed := make([]Record, len(records))
+	for i, r := range records {
+		reversed[len(records)-1-i] = r
+	}
+	// Union merges can duplicate lines.
+	writeLines(t, backward, append(reversed, records[0], records[3]))
+
+	f, skipped, err := Read(forward)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if skipped != 1 {
+		t.Errorf("skipped = %d, want 1 (conflict marker)", skipped)
+	}
+	b, _, err := Read(backward)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for id, want := range map[string]string{"a": "Use uv for installs", "b": "Sign commits with GPG"} {
+		for _, got := range []Record{f[id], b[id]} {
+			if c := got.Node.Content["content"].(map[string]interface{})["canonical"]; c != want {
+				t.Errorf("%s winner = %v, want %q", id, c, want)
+			}
+		}
+	}
+	if !f["c"].Deleted || !b["c"].Deleted {
+		t.Error("tombstone at a higher revision should win")
+	}
+
+	if missing, _, err := Read(filepath.Join(dir, "none.jsonl")); err != nil || len(missing) != 0 {
+		t.Errorf("Read(missing) = %v, %v", missing, err)
+	}
+}
+
+func TestCompact(t *testing.T) {
+	t0 := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	path := filepath.Join(t.TempDir(), FileName)
+	writeLines(t, path, []Record{record("b", 1, t0, "x"), record("a", 1, t0, "y"), record("b", 2, t0, "z")})
+
+	n, err := Compact(path)
+	if err != nil || n != 2 {
+		t.Fatalf("Compact() = %d, %v", n, err)
+	}
+	data, _ := os.ReadFile(path)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 || !strings.Contains(lines[0], `"id":"a"`) || !strings.Contains(lines[1], `"rev":2`) {
+		t.Errorf("compacted log:\n%s", data)
+	}
+}
+
+func TestEnsureGitattributes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gitattributes")
+	if err := os.WriteFile(path, []byte("*.db binary"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := EnsureGitattributes(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, _ := os.ReadFile(path)
+	if string(data) != "*.db binary\nteam.jsonl merge=union\n" {
+		t.Errorf(".gitattributes = %q", data)
+	}
+}
+
+func newStore(t *testing.T) store.GraphStore {
+	t.Helper()
+	s, err := store.NewSQLiteGraphStore(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func canonical(t *testing.T, s store.GraphStore, id string) string {
+	t.Helper()
+	n, err := s.GetNode(context.Background(), id)
+	if err != nil || n == nil {
+		return ""
+	}
+	return n.Content["content"].(map[string]interface{})["canonical"].(string)
+}
+
+func edit(t *testing.T, s store.GraphStore, id, text string) {
+	t.Helper()
+	ctx := context.Background()
+	n, _ := s.GetNode(ctx, id)
+	n.Content["content"].(map[string]interface{})["canonical"] = text
+	if err := s.UpdateNode(ctx, *n); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSync(t *testing.T) {
+	ctx := context.Background()
+	logPath := filepath.Join(t.TempDir(), FileName)
+	alice, bob := newStore(t), newStore(t)
+	sync := func(s store.GraphStore, who string) *Result {
+		t.Helper()
+		r, err := Sync(ctx, s, logPath, Options{Author: who})
+		if err != nil {
+			t.Fatalf("Sync(%s) error = %v", who, err)
+		}
+		return r
+	}
+
+	if _, err := alice.AddNode(ctx, teamNode("uv", "Use uv for installs")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := alice.AddNode(ctx, teamNode("private", "Alice's own shortcut")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Share(ctx, alice, "uv"); err != nil {
+		t.Fatal(err)
+	}
+
+	if r := sync(alice, "alice"); len(r.Published) != 1 || r.Published[0] != "uv" {
+		t.Fatalf("alice publish = %+v", r)
+	}
+	if r := sync(alice, "alice"); len(r.Published)+len(r.Updated)+len(r.Imported) != 0 {
+		t.Errorf("second sync should be a no-op, got %+v", r)
+	}
+
+	if r := sync(bob, "bob"); len(r.Imported) != 1 {
+		t.Fatalf("bob import = %+v", r)
+	}
+	if canonical(t, bob, "uv") != "Use uv for installs" || canonical(t, bob, "private") != "" {
+		t.Error("bob should have the shared behavior and not the private one")
+	}
+
+	// Bob edits; Alice picks it up.
+	edit(t, bob, "uv", "Use uv for all Python installs")
+	if r := sync(bob, "bob"); len(r.Published) != 1 {
+		t.Fatalf("bob publish = %+v", r)
+	}
+	if r := sync(alice, "alice"); len(r.Updated) != 1 || canonical(t, alice, "uv") != "Use uv for all Python installs" {
+		t.Fatalf("alice update = %+v", r)
+	}
+
+	// Concurrent edits: the first to sync wins, the other is reported.
+	edit(t, alice, "uv", "Use uv, never pip")
+	edit(t, bob, "uv", "Use uv and pin versions")
+	sync(alice, "alice")
+	r := sync(bob, "bob")
+	if len(r.Conflicts) != 1 || canonical(t, bob, "uv") != "Use uv, never pip" {
+		t.Errorf("bob conflict = %+v, content %q", r, canonical(t, bob, "uv"))
+	}
+
+	// Unsharing removes teammates' copies but keeps the local one.
+	if err := Unshare(ctx, alice, logPath, "uv", Options{Author: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if r := sync(bob, "bob"); len(r.Removed) != 1 || canonical(t, bob, "uv") != "" {
+		t.Errorf("bob removal = %+v", r)
+	}
+	if canonical(t, alice, "uv") == "" {
+		t.Error("unshare removed alice's local copy")
+	}
+	if r := sync(alice, "alice"); len(r.Published)+len(r.Removed) != 0 {
+		t.Errorf("alice after unshare = %+v, want no-op", r)
+	}
+}
+
+func TestSync_DryRun(t *testing.T) {
+	ctx := context.Background()
+	logPath := filepath.Join(t.TempDir(), FileName)
+	s := newStore(t)
+	if _, err := s.AddNode(ctx, teamNode("uv", "Use uv")); err != nil {
+		t.Fatal(err)
+	}
+	if err := Share(ctx, s, "uv"); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Sync(ctx, s, logPath, Options{DryRun: true})
+	if err != nil || len(r.Published) != 1 {
+		t.Fatalf("Sync(dry run) = %+v, %v", r, err)
+	}
+	if _, err := os.Stat(logPath); !os.IsNotExist(err) {
+		t.Error("dry run wrote the team log")
+	}
+}
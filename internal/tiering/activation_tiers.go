@@ -7,6 +7,7 @@ import (
 
 	"github.com/nvandessel/floop/internal/models"
 	"github.com/nvandessel/floop/internal/spreading"
+	"github.com/nvandessel/floop/internal/summarization"
 	"github.com/nvandessel/floop/internal/tokens"
 )
 
@@ -39,12 +40,25 @@ func DefaultActivationTierConfig() ActivationTierConfig {
 
 // ActivationTierMapper maps spreading activation results to injection tiers.
 type ActivationTierMapper struct {
-	config ActivationTierConfig
+	config     ActivationTierConfig
+	summarizer summarization.Summarizer
 }
 
 // NewActivationTierMapper creates a new mapper with the given configuration.
+// Summary-tier content for behaviors without a stored summary is generated
+// from their canonical content by the rule-based summarizer.
 func NewActivationTierMapper(config ActivationTierConfig) *ActivationTierMapper {
-	return &ActivationTierMapper{config: config}
+	return &ActivationTierMapper{
+		config:     config,
+		summarizer: summarization.NewRuleSummarizer(summarization.DefaultConfig()),
+	}
+}
+
+// WithSummarizer sets the summarizer used to generate summary-tier content
+// for behaviors without a stored summary.
+func (m *ActivationTierMapper) WithSummarizer(s summarization.Summarizer) *ActivationTierMapper {
+	m.summarizer = s
+	return m
 }
 
 // MapTier returns the appropriate tier for a given activation level and behavior kind.
@@ -102,7 +116,7 @@ func (m *ActivationTierMapper) MapResults(
 			continue
 		}
 		tier := m.MapTier(r.Activation, b.Kind)
-		tokens := estimateTokensForTier(b, tier)
+		tokens := m.estimateTokensForTier(b, tier)
 		entries = append(entries, tierEntry{
 			result:   r,
 			behavior: b,
@@ -132,7 +146,7 @@ func (m *ActivationTierMapper) MapResults(
 				}
 				// Demote one level.
 				newTier := entries[i].tier + 1
-				newTokens := estimateTokensForTier(entries[i].behavior, newTier)
+				newTokens := m.estimateTokensForTier(entries[i].behavior, newTier)
 				totalTokens -= entries[i].tokens - newTokens
 				entries[i].tier = newTier
 				entries[i].tokens = newTokens
@@ -152,7 +166,7 @@ func (m *ActivationTierMapper) MapResults(
 		ib := models.InjectedBehavior{
 			Behavior:  e.behavior,
 			Tier:      e.tier,
-			Content:   contentForTier(e.behavior, e.tier),
+			Content:   m.contentForTier(e.behavior, e.tier),
 			TokenCost: e.tokens,
 			Score:     e.result.Activation,
 		}
@@ -174,26 +188,18 @@ func (m *ActivationTierMapper) MapResults(
 }
 
 // estimateTokensForTier estimates the token cost for a behavior at a given tier.
-func estimateTokensForTier(b *models.Behavior, tier models.InjectionTier) int {
-	content := contentForTier(b, tier)
+func (m *ActivationTierMapper) estimateTokensForTier(b *models.Behavior, tier models.InjectionTier) int {
+	content := m.contentForTier(b, tier)
 	return tokens.EstimateTokens(content)
 }
 
 // contentForTier returns the content string for a behavior at a given tier.
-func contentForTier(b *models.Behavior, tier models.InjectionTier) string {
+func (m *ActivationTierMapper) contentForTier(b *models.Behavior, tier models.InjectionTier) string {
 	switch tier {
 	case models.TierFull:
 		return b.Content.Canonical
 	case models.TierSummary:
-		if b.Content.Summary != "" {
-			return b.Content.Summary
-		}
-		// Fallback: truncate canonical.
-		canonical := b.Content.Canonical
-		if len(canonical) > 60 {
-			return canonical[:57] + "..."
-		}
-		return canonical
+		return m.summaryFor(b)
 	case models.TierNameOnly:
 		return formatNameOnly(b)
 	default:
@@ -201,6 +207,25 @@ func contentForTier(b *models.Behavior, tier models.InjectionTier) string {
 	}
 }
 
+// summaryFor returns the behavior's stored summary, or one generated from
+// its canonical content. If the summarizer fails, the canonical content is
+// truncated instead.
+func (m *ActivationTierMapper) summaryFor(b *models.Behavior) string {
+	if b.Content.Summary != "" {
+		return b.Content.Summary
+	}
+	if m.summarizer != nil {
+		if summary, err := m.summarizer.Summarize(b); err == nil && summary != "" {
+			return summary
+		}
+	}
+	canonical := b.Content.Canonical
+	if len(canonical) > 60 {
+		return canonical[:57] + "..."
+	}
+	return canonical
+}
+
 // formatNameOnly produces a compact name-only representation of a behavior.
 // For episodic and workflow kinds, a type prefix is used instead of the
 // generic `name` [kind] format.
@@ -1,6 +1,7 @@
 package tiering
 
 import (
+	"errors"
 	"testing"
 
 	"github.com/nvandessel/floop/internal/models"
@@ -436,9 +437,10 @@ func TestContentForTier(t *testing.T) {
 		{"omitted tier", models.TierOmitted, ""},
 	}
 
+	m := NewActivationTierMapper(DefaultActivationTierConfig())
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := contentForTier(b, tt.tier)
+			got := m.contentForTier(b, tt.tier)
 			if got != tt.want {
 				t.Errorf("contentForTier(%s) = %q, want %q", tt.tier, got, tt.want)
 			}
@@ -446,7 +448,7 @@ func TestContentForTier(t *testing.T) {
 	}
 }
 
-func TestContentForTier_SummaryFallback(t *testing.T) {
+func TestContentForTier_SummaryGenerated(t *testing.T) {
 	b := &models.Behavior{
 		Name: "test",
 		Kind: models.BehaviorKindDirective,
@@ -455,11 +457,45 @@ func TestContentForTier_SummaryFallback(t *testing.T) {
 		},
 	}
 
-	got := contentForTier(b, models.TierSummary)
+	got := NewActivationTierMapper(DefaultActivationTierConfig()).contentForTier(b, models.TierSummary)
 	if len(got) > 60 {
-		t.Errorf("summary fallback should be <= 60 chars, got %d", len(got))
+		t.Errorf("generated summary should be <= 60 chars, got %d", len(got))
 	}
-	if got != "This is a very long canonical content that exceeds sixty ..." {
-		t.Errorf("unexpected fallback truncation: %q", got)
+	if got != "This is a very long canonical content that exceeds sixty..." {
+		t.Errorf("unexpected generated summary: %q", got)
+	}
+}
+
+// stubSummarizer returns a fixed summary or error.
+type stubSummarizer struct {
+	summary string
+	err     error
+}
+
+func (s stubSummarizer) Summarize(*models.Behavior) (string, error) { return s.summary, s.err }
+
+func (s stubSummarizer) SummarizeBatch([]*models.Behavior) (map[string]string, error) {
+	return nil, s.err
+}
+
+func TestContentForTier_CustomSummarizer(t *testing.T) {
+	canonical := "This is a very long canonical content that exceeds sixty characters and should be truncated for summary fallback"
+	b := &models.Behavior{Name: "test", Content: models.BehaviorContent{Canonical: canonical}}
+
+	m := NewActivationTierMapper(DefaultActivationTierConfig()).WithSummarizer(stubSummarizer{summary: "Keep it short"})
+	if got := m.contentForTier(b, models.TierSummary); got != "Keep it short" {
+		t.Errorf("custom summarizer content = %q", got)
+	}
+
+	// A failing summarizer falls back to truncating the canonical content.
+	m.WithSummarizer(stubSummarizer{err: errors.New("unavailable")})
+	if got := m.contentForTier(b, models.TierSummary); got != canonical[:57]+"..." {
+		t.Errorf("fallback content = %q", got)
+	}
+
+	// A stored summary always wins.
+	b.Content.Summary = "Stored"
+	if got := m.contentForTier(b, models.TierSummary); got != "Stored" {
+		t.Errorf("stored summary content = %q", got)
 	}
 }
@@ -33,7 +33,13 @@ func ScoredBehaviorsToResults(scored []ranking.ScoredBehavior) ([]spreading.Resu
 // the convenience path used by QuickAssign and other callers that start with
 // []models.Behavior rather than pre-scored results.
 func BehaviorsToResults(behaviors []models.Behavior) ([]spreading.Result, map[string]*models.Behavior) {
-	scorer := ranking.NewRelevanceScorer(ranking.DefaultScorerConfig())
+	return BehaviorsToResultsWithConfig(behaviors, ranking.DefaultScorerConfig())
+}
+
+// BehaviorsToResultsWithConfig is BehaviorsToResults with a custom scorer
+// configuration, such as an agent profile's kind boosts.
+func BehaviorsToResultsWithConfig(behaviors []models.Behavior, config ranking.ScorerConfig) ([]spreading.Result, map[string]*models.Behavior) {
+	scorer := ranking.NewRelevanceScorer(config)
 	scored := scorer.ScoreBatch(behaviors, nil)
 	return ScoredBehaviorsToResults(scored)
 }
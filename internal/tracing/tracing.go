@@ -0,0 +1,147 @@
+// Package tracing emits optional OpenTelemetry spans around store
+// operations, learning pipeline stages, LLM calls, and pack fetches, and
+// exports them over OTLP/HTTP once Setup is called. Until then spans go to
+// the global no-op tracer and cost almost nothing.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of floop's spans.
+const TracerName = "github.com/nvandessel/floop"
+
+// ServiceName is the service.name resource attribute of exported spans.
+const ServiceName = "floop"
+
+// tracesPath is the OTLP/HTTP traces path, appended to endpoints without one.
+const tracesPath = "/v1/traces"
+
+var enabled atomic.Bool
+
+// Enabled reports whether Setup installed an exporting tracer provider.
+// Instrumentation that is costly to set up, such as wrapping stores, is
+// skipped otherwise.
+func Enabled() bool {
+	return enabled.Load()
+}
+
+// Options configures span export.
+type Options struct {
+	// Endpoint is the OTLP/HTTP collector URL, such as
+	// http://localhost:4318. A URL without a path gets /v1/traces. Empty
+	// uses the OTEL_EXPORTER_OTLP_ENDPOINT environment variables, then
+	// https://localhost:4318.
+	Endpoint string
+
+	// SampleRatio is the fraction of traces to sample, from 0 to 1. Zero
+	// samples every trace. Child spans follow their parent's decision.
+	SampleRatio float64
+
+	// ServiceVersion is the service.version resource attribute.
+	ServiceVersion string
+}
+
+// Setup installs a global tracer provider that batches spans to an OTLP/HTTP
+// collector. The returned shutdown flushes pending spans and must be called
+// before the process exits; it reports export failures.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	var exporterOpts []otlptracehttp.Option
+	if opts.Endpoint != "" {
+		endpoint, err := EndpointURL(opts.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("creating OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", ServiceName),
+		attribute.String("service.version", opts.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("creating trace resource: %w", err)
+	}
+
+	sampler := sdktrace.AlwaysSample()
+	if opts.SampleRatio > 0 && opts.SampleRatio < 1 {
+		sampler = sdktrace.TraceIDRatioBased(opts.SampleRatio)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sampler)),
+	)
+
+	// Export failures are reported by shutdown rather than logged to
+	// stderr from a background goroutine mid-command.
+	var (
+		exportErrOnce sync.Once
+		exportErr     error
+	)
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		exportErrOnce.Do(func() { exportErr = err })
+	}))
+	otel.SetTracerProvider(provider)
+	enabled.Store(true)
+
+	return func(ctx context.Context) error {
+		enabled.Store(false)
+		err := provider.ForceFlush(ctx)
+		if shutdownErr := provider.Shutdown(ctx); err == nil {
+			err = shutdownErr
+		}
+		exportErrOnce.Do(func() {}) // no later handler can race the read
+		if err == nil {
+			err = exportErr
+		}
+		if err != nil {
+			return fmt.Errorf("exporting traces: %w", err)
+		}
+		return nil
+	}, nil
+}
+
+// EndpointURL validates a collector URL and adds the OTLP traces path if
+// it has none.
+func EndpointURL(endpoint string) (string, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid tracing endpoint %q: expected an http or https URL", endpoint)
+	}
+	if strings.TrimSuffix(u.Path, "/") == "" {
+		u.Path = tracesPath
+	}
+	return u.String(), nil
+}
+
+// Start starts a span named name as a child of any span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End ends span, marking it failed with err if err is non-nil.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
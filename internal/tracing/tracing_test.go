@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEndpointURL(t *testing.T) {
+	tests := []struct {
+		name     string
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{"adds traces path", "http://localhost:4318", "http://localhost:4318/v1/traces", false},
+		{"adds path after slash", "https://collector.example.com/", "https://collector.example.com/v1/traces", false},
+		{"keeps custom path", "http://localhost:4318/otlp/v1/traces", "http://localhost:4318/otlp/v1/traces", false},
+		{"missing scheme", "localhost:4318", "", true},
+		{"unsupported scheme", "grpc://localhost:4317", "", true},
+		{"missing host", "http://", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := EndpointURL(tt.endpoint)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EndpointURL(%q) error = %v, wantErr %v", tt.endpoint, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("EndpointURL(%q) = %q, want %q", tt.endpoint, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStartEnd(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	End(child, errors.New("boom"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+	gotChild, gotParent := spans[0], spans[1]
+	if gotChild.Parent().SpanID() != gotParent.SpanContext().SpanID() {
+		t.Error("child span is not parented to the span in its context")
+	}
+	if gotChild.Status().Code != codes.Error || gotChild.Status().Description != "boom" {
+		t.Errorf("child status = %+v, want error boom", gotChild.Status())
+	}
+	if gotParent.Status().Code != codes.Unset {
+		t.Errorf("parent status = %+v, want unset", gotParent.Status())
+	}
+}
@@ -0,0 +1,351 @@
+// Package tui implements floop's interactive terminal browser for curating
+// behaviors.
+//
+// Model is a plain state machine: keys go in, view text and actions come
+// out, so it can be tested without a terminal. Run drives a Model on a
+// raw-mode terminal and carries out its actions through a Backend.
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// Status is a behavior's activation status for the synthetic context.
+type Status string
+
+const (
+	StatusActive     Status = "active"     // matches the context and survives conflict resolution
+	StatusOverridden Status = "overridden" // matches but is overridden by a more specific behavior
+	StatusExcluded   Status = "excluded"   // matches but loses a conflict
+	StatusInactive   Status = "inactive"   // when-conditions do not match
+	StatusPending    Status = "pending"    // held for review; never activates
+)
+
+// statusMarkers are the list-view markers for each status.
+var statusMarkers = map[Status]string{
+	StatusActive:     "●",
+	StatusOverridden: "◐",
+	StatusExcluded:   "◌",
+	StatusInactive:   "○",
+	StatusPending:    "?",
+}
+
+// Item is a behavior row in the browser.
+type Item struct {
+	ID             string
+	Name           string
+	Kind           string
+	Canonical      string
+	Tags           []string
+	Confidence     float64
+	TimesActivated int
+	Status         Status
+	// AwaitingReview is set for pending behaviors and active behaviors
+	// flagged for review; only these can be approved.
+	AwaitingReview bool
+}
+
+// Neighbor is a behavior one edge away from the selected behavior.
+type Neighbor struct {
+	ID       string
+	Name     string
+	Kind     store.EdgeKind
+	Weight   float64
+	Outbound bool // edge points from the selected behavior to this one
+}
+
+// Backend loads behaviors and applies curation actions.
+type Backend interface {
+	Items(ctx context.Context) ([]Item, error)
+	Neighbors(ctx context.Context, id string) ([]Neighbor, error)
+	Approve(ctx context.Context, id string) error
+	Forget(ctx context.Context, id string) error
+	// Edit lets the user change a behavior's canonical content. It runs
+	// with the terminal restored, so it may start an external editor.
+	Edit(ctx context.Context, id string) error
+}
+
+// ActionKind is something the Model asks its driver to do.
+type ActionKind int
+
+const (
+	ActionNone ActionKind = iota
+	ActionApprove
+	ActionForget
+	ActionEdit
+	ActionReload
+	ActionQuit
+)
+
+// Action is an ActionKind applied to a behavior.
+type Action struct {
+	Kind ActionKind
+	ID   string
+}
+
+// Key is a decoded key press: a single character, or one of the named keys.
+type Key string
+
+const (
+	KeyUp       Key = "up"
+	KeyDown     Key = "down"
+	KeyPageUp   Key = "pgup"
+	KeyPageDown Key = "pgdown"
+	KeyHome     Key = "home"
+	KeyEnd      Key = "end"
+	KeyEnter    Key = "enter"
+	KeyEscape   Key = "esc"
+	KeyCtrlC    Key = "ctrl+c"
+)
+
+// detailHeight is the number of lines reserved for the detail pane.
+const detailHeight = 10
+
+// Model is the browser state.
+type Model struct {
+	contextLabel string
+	items        []Item
+	cursor       int
+	offset       int
+	neighbors    []Neighbor
+	confirming   bool
+	status       string
+	width        int
+	height       int
+}
+
+// NewModel creates a browser over items. contextLabel describes the
+// synthetic context activation status was evaluated against.
+func NewModel(contextLabel string, items []Item) *Model {
+	m := &Model{contextLabel: contextLabel, width: 80, height: 24}
+	m.SetItems(items)
+	return m
+}
+
+// SetItems replaces the behavior list, keeping the cursor on the same
+// behavior when it is still present.
+func (m *Model) SetItems(items []Item) {
+	selected, hadSelection := m.Selected()
+	m.items = items
+	if hadSelection {
+		for i, it := range items {
+			if it.ID == selected.ID {
+				m.cursor = i
+				m.scroll()
+				return
+			}
+		}
+	}
+	if m.cursor >= len(items) {
+		m.cursor = max(len(items)-1, 0)
+	}
+	m.scroll()
+}
+
+// SetNeighbors sets the graph neighborhood shown for the selected behavior.
+func (m *Model) SetNeighbors(neighbors []Neighbor) { m.neighbors = neighbors }
+
+// SetStatus sets the message shown in the footer.
+func (m *Model) SetStatus(status string) { m.status = status }
+
+// SetSize sets the terminal size. Non-positive values are ignored.
+func (m *Model) SetSize(width, height int) {
+	if width > 0 {
+		m.width = width
+	}
+	if height > 0 {
+		m.height = height
+	}
+	m.scroll()
+}
+
+// Selected returns the behavior under the cursor.
+func (m *Model) Selected() (Item, bool) {
+	if m.cursor < 0 || m.cursor >= len(m.items) {
+		return Item{}, false
+	}
+	return m.items[m.cursor], true
+}
+
+// Update applies a key press and returns the action it requests.
+func (m *Model) Update(key Key) Action {
+	if m.confirming {
+		m.confirming = false
+		if key == "y" || key == "Y" {
+			if it, ok := m.Selected(); ok {
+				return Action{Kind: ActionForget, ID: it.ID}
+			}
+		}
+		m.status = "Forget cancelled."
+		return Action{}
+	}
+
+	m.status = ""
+	switch key {
+	case "q", KeyCtrlC, KeyEscape:
+		return Action{Kind: ActionQuit}
+	case "j", KeyDown:
+		m.move(1)
+	case "k", KeyUp:
+		m.move(-1)
+	case KeyPageDown:
+		m.move(m.listHeight())
+	case KeyPageUp:
+		m.move(-m.listHeight())
+	case "g", KeyHome:
+		m.move(-len(m.items))
+	case "G", KeyEnd:
+		m.move(len(m.items))
+	case "r":
+		return Action{Kind: ActionReload}
+	case "a":
+		it, ok := m.Selected()
+		if !ok {
+			break
+		}
+		if !it.AwaitingReview {
+			m.status = fmt.Sprintf("%s is not awaiting review.", it.Name)
+			break
+		}
+		return Action{Kind: ActionApprove, ID: it.ID}
+	case "f":
+		if it, ok := m.Selected(); ok {
+			m.confirming = true
+			m.status = fmt.Sprintf("Forget %s? (y/n)", it.Name)
+		}
+	case "e", KeyEnter:
+		if it, ok := m.Selected(); ok {
+			return Action{Kind: ActionEdit, ID: it.ID}
+		}
+	}
+	return Action{}
+}
+
+func (m *Model) move(delta int) {
+	if len(m.items) == 0 {
+		return
+	}
+	m.cursor = min(max(m.cursor+delta, 0), len(m.items)-1)
+	m.scroll()
+}
+
+// scroll keeps the cursor inside the visible list window.
+func (m *Model) scroll() {
+	h := m.listHeight()
+	if m.cursor < m.offset {
+		m.offset = m.cursor
+	}
+	if m.cursor >= m.offset+h {
+		m.offset = m.cursor - h + 1
+	}
+	m.offset = max(m.offset, 0)
+}
+
+// listHeight is the number of list rows that fit between the header,
+// the detail pane, and the footer.
+func (m *Model) listHeight() int {
+	return max(m.height-detailHeight-5, 3)
+}
+
+// View renders the browser as newline-separated lines.
+func (m *Model) View() string {
+	var lines []string
+	counts := make(map[Status]int)
+	for _, it := range m.items {
+		counts[it.Status]++
+	}
+	lines = append(lines,
+		m.fit(fmt.Sprintf("floop tui — %d behaviors (%d active, %d pending) — context: %s",
+			len(m.items), counts[StatusActive], counts[StatusPending], m.contextLabel)),
+		m.fit(strings.Repeat("─", m.width)))
+
+	h := m.listHeight()
+	for i := m.offset; i < m.offset+h; i++ {
+		if i >= len(m.items) {
+			lines = append(lines, "")
+			continue
+		}
+		it := m.items[i]
+		cursor := "  "
+		if i == m.cursor {
+			cursor = "> "
+		}
+		review := " "
+		if it.AwaitingReview {
+			review = "!"
+		}
+		lines = append(lines, m.fit(fmt.Sprintf("%s%s%s %-40s %-11s %5d  %.2f",
+			cursor, statusMarkers[it.Status], review, clip(it.Name, 40), clip(it.Kind, 11), it.TimesActivated, it.Confidence)))
+	}
+	if len(m.items) == 0 {
+		lines[2] = "  No behaviors found."
+	}
+
+	lines = append(lines, m.fit(strings.Repeat("─", m.width)))
+	lines = append(lines, m.detail()...)
+
+	footer := "j/k move  a approve  f forget  e edit  r reload  q quit"
+	if m.status != "" {
+		footer = m.status
+	}
+	lines = append(lines, m.fit(strings.Repeat("─", m.width)), m.fit(footer))
+	return strings.Join(lines, "\n")
+}
+
+// detail renders the selected behavior and its graph neighborhood,
+// padded to detailHeight lines.
+func (m *Model) detail() []string {
+	var lines []string
+	if it, ok := m.Selected(); ok {
+		lines = append(lines, m.fit(fmt.Sprintf("%s  [%s, %s]", it.ID, it.Kind, it.Status)))
+		lines = append(lines, m.fit("  "+strings.ReplaceAll(it.Canonical, "\n", " ")))
+		if len(it.Tags) > 0 {
+			lines = append(lines, m.fit("  tags: "+strings.Join(it.Tags, ", ")))
+		}
+		if len(m.neighbors) == 0 {
+			lines = append(lines, m.fit("  no edges"))
+		} else {
+			lines = append(lines, m.fit(fmt.Sprintf("  neighborhood (%d edges):", len(m.neighbors))))
+		}
+		for _, n := range m.neighbors {
+			arrow := "←"
+			if n.Outbound {
+				arrow = "→"
+			}
+			name := n.Name
+			if name == "" {
+				name = n.ID
+			}
+			lines = append(lines, m.fit(fmt.Sprintf("    %s %-13s %s (%.2f)", arrow, n.Kind, name, n.Weight)))
+		}
+	}
+	if len(lines) > detailHeight {
+		more := len(lines) - detailHeight + 1
+		lines = append(lines[:detailHeight-1], m.fit(fmt.Sprintf("    … %d more", more)))
+	}
+	for len(lines) < detailHeight {
+		lines = append(lines, "")
+	}
+	return lines
+}
+
+// fit truncates a line to the terminal width.
+func (m *Model) fit(s string) string {
+	return clip(s, m.width)
+}
+
+// clip truncates s to n runes, marking truncation with an ellipsis.
+func clip(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	if n <= 1 {
+		return string(r[:n])
+	}
+	return string(r[:n-1]) + "…"
+}
@@ -0,0 +1,174 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func testItems() []Item {
+	return []Item{
+		{ID: "b-1", Name: "pending-one", Kind: "directive", Status: StatusPending, AwaitingReview: true},
+		{ID: "b-2", Name: "active-two", Kind: "constraint", Status: StatusActive, Canonical: "Never log secrets", Tags: []string{"security"}},
+		{ID: "b-3", Name: "inactive-three", Kind: "directive", Status: StatusInactive},
+	}
+}
+
+func TestModelNavigation(t *testing.T) {
+	m := NewModel("task=testing", testItems())
+
+	steps := []struct {
+		key  Key
+		want string
+	}{
+		{"j", "b-2"},
+		{KeyDown, "b-3"},
+		{"j", "b-3"}, // clamps at the end
+		{"g", "b-1"},
+		{KeyUp, "b-1"}, // clamps at the start
+		{"G", "b-3"},
+		{"k", "b-2"},
+	}
+	for i, step := range steps {
+		if a := m.Update(step.key); a.Kind != ActionNone {
+			t.Fatalf("step %d: Update(%q) = %+v, want no action", i, step.key, a)
+		}
+		if it, _ := m.Selected(); it.ID != step.want {
+			t.Errorf("step %d: after %q selected %s, want %s", i, step.key, it.ID, step.want)
+		}
+	}
+
+	if a := m.Update("q"); a.Kind != ActionQuit {
+		t.Errorf("q = %+v, want quit", a)
+	}
+}
+
+func TestModelActions(t *testing.T) {
+	m := NewModel("", testItems())
+
+	if a := m.Update("a"); a != (Action{Kind: ActionApprove, ID: "b-1"}) {
+		t.Errorf("approve pending = %+v", a)
+	}
+
+	m.Update("j")
+	if a := m.Update("a"); a.Kind != ActionNone || !strings.Contains(m.View(), "not awaiting review") {
+		t.Errorf("approve active = %+v, want refusal in footer", a)
+	}
+
+	if a := m.Update("f"); a.Kind != ActionNone || !strings.Contains(m.View(), "Forget active-two? (y/n)") {
+		t.Errorf("f = %+v, want confirmation prompt", a)
+	}
+	if a := m.Update("n"); a.Kind != ActionNone || !strings.Contains(m.View(), "Forget cancelled.") {
+		t.Errorf("n = %+v, want cancel", a)
+	}
+	m.Update("f")
+	if a := m.Update("y"); a != (Action{Kind: ActionForget, ID: "b-2"}) {
+		t.Errorf("f y = %+v", a)
+	}
+
+	if a := m.Update(KeyEnter); a != (Action{Kind: ActionEdit, ID: "b-2"}) {
+		t.Errorf("enter = %+v", a)
+	}
+	if a := m.Update("r"); a.Kind != ActionReload {
+		t.Errorf("r = %+v", a)
+	}
+}
+
+func TestModelSetItemsKeepsSelection(t *testing.T) {
+	m := NewModel("", testItems())
+	m.Update("G")
+
+	items := testItems()
+	slices.Reverse(items)
+	m.SetItems(items)
+	if it, _ := m.Selected(); it.ID != "b-3" {
+		t.Errorf("selected %s after reorder, want b-3", it.ID)
+	}
+
+	m.SetItems(items[1:]) // b-3 removed
+	if it, ok := m.Selected(); !ok || it.ID != "b-2" {
+		t.Errorf("selected %s, %v after removal, want b-2", it.ID, ok)
+	}
+
+	m.SetItems(nil)
+	if _, ok := m.Selected(); ok {
+		t.Error("Selected() on empty list should be false")
+	}
+	if !strings.Contains(m.View(), "No behaviors found.") {
+		t.Errorf("empty view = %q", m.View())
+	}
+}
+
+func TestModelView(t *testing.T) {
+	m := NewModel("task=testing", testItems())
+	m.SetSize(100, 30)
+	m.Update("j")
+	m.SetNeighbors([]Neighbor{
+		{ID: "b-3", Name: "inactive-three", Kind: "similar-to", Weight: 0.8, Outbound: true},
+		{ID: "b-9", Kind: "overrides", Weight: 1},
+	})
+
+	view := m.View()
+	for _, want := range []string{
+		"3 behaviors (1 active, 1 pending) — context: task=testing",
+		"  ?! pending-one",
+		"> ●  active-two",
+		"Never log secrets",
+		"tags: security",
+		"neighborhood (2 edges):",
+		"→ similar-to    inactive-three (0.80)",
+		"← overrides     b-9 (1.00)",
+	} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	lines := strings.Split(view, "\n")
+	if len(lines) != 30 {
+		t.Errorf("view has %d lines, want terminal height 30", len(lines))
+	}
+	for _, line := range lines {
+		if n := len([]rune(line)); n > 100 {
+			t.Errorf("line exceeds width (%d): %q", n, line)
+		}
+	}
+}
+
+func TestModelScrollsToCursor(t *testing.T) {
+	var items []Item
+	for i := 0; i < 50; i++ {
+		items = append(items, Item{ID: string(rune('A' + i)), Name: "item-" + string(rune('A'+i)), Status: StatusInactive})
+	}
+	m := NewModel("", items)
+	m.SetSize(80, 24)
+	m.Update("G")
+	if !strings.Contains(m.View(), "> ○  item-"+string(rune('A'+49))) {
+		t.Errorf("last item not visible after G:\n%s", m.View())
+	}
+	m.Update(KeyPageUp)
+	if it, _ := m.Selected(); it.ID != string(rune('A'+49-m.listHeight())) {
+		t.Errorf("page up selected %s", it.ID)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	got := ParseKeys([]byte("jk\x1b[A\x1b[B\x1b[5~\x1b[6~\r\x03\x1b\x1b[99Zq"))
+	want := []Key{"j", "k", KeyUp, KeyDown, KeyPageUp, KeyPageDown, KeyEnter, KeyCtrlC, KeyEscape, "q"}
+	if !slices.Equal(got, want) {
+		t.Errorf("ParseKeys() = %v, want %v", got, want)
+	}
+}
+
+func TestRunRequiresTerminal(t *testing.T) {
+	f, err := os.Create(filepath.Join(t.TempDir(), "not-a-tty"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := Run(t.Context(), f, f, nil, ""); err != ErrNotTerminal {
+		t.Errorf("Run() error = %v, want ErrNotTerminal", err)
+	}
+}
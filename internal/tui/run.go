@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// ErrNotTerminal is returned by Run when stdin or stdout is not a terminal.
+var ErrNotTerminal = errors.New("floop tui requires an interactive terminal")
+
+// Escape sequences for the alternate screen, cursor visibility, and redraw.
+const (
+	enterAltScreen = "\x1b[?1049h\x1b[?25l"
+	leaveAltScreen = "\x1b[?25h\x1b[?1049l"
+	clearScreen    = "\x1b[H\x1b[2J"
+)
+
+// Run shows the browser on the terminal attached to in and out until the
+// user quits. Backend errors from actions are shown in the footer rather
+// than returned; only failures to load behaviors end the session.
+func Run(ctx context.Context, in, out *os.File, backend Backend, contextLabel string) error {
+	inFd, outFd := int(in.Fd()), int(out.Fd())
+	if !term.IsTerminal(inFd) || !term.IsTerminal(outFd) {
+		return ErrNotTerminal
+	}
+
+	items, err := backend.Items(ctx)
+	if err != nil {
+		return err
+	}
+	m := NewModel(contextLabel, items)
+
+	restore, err := enterRaw(inFd, out)
+	if err != nil {
+		return err
+	}
+	defer func() { restore() }()
+
+	neighborsOf := ""
+	buf := make([]byte, 64)
+	for {
+		if w, h, err := term.GetSize(outFd); err == nil {
+			m.SetSize(w, h)
+		}
+		if it, ok := m.Selected(); ok && it.ID != neighborsOf {
+			neighbors, err := backend.Neighbors(ctx, it.ID)
+			if err != nil {
+				m.SetStatus(fmt.Sprintf("Error loading edges: %v", err))
+			}
+			m.SetNeighbors(neighbors)
+			neighborsOf = it.ID
+		}
+		draw(out, m)
+
+		n, err := in.Read(buf)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("reading input: %w", err)
+		}
+
+		for _, key := range ParseKeys(buf[:n]) {
+			action := m.Update(key)
+			switch action.Kind {
+			case ActionNone:
+				continue
+			case ActionQuit:
+				return nil
+			case ActionApprove:
+				err = backend.Approve(ctx, action.ID)
+				report(m, err, "Approved %s.", action.ID)
+			case ActionForget:
+				err = backend.Forget(ctx, action.ID)
+				report(m, err, "Forgot %s. Use 'floop restore' to undo.", action.ID)
+			case ActionEdit:
+				restore()
+				editErr := backend.Edit(ctx, action.ID)
+				if restore, err = enterRaw(inFd, out); err != nil {
+					return err
+				}
+				report(m, editErr, "Saved %s.", action.ID)
+			case ActionReload:
+				m.SetStatus("Reloaded.")
+			}
+
+			items, err := backend.Items(ctx)
+			if err != nil {
+				return err
+			}
+			m.SetItems(items)
+			neighborsOf = ""
+		}
+	}
+}
+
+// enterRaw puts the terminal in raw mode on the alternate screen and
+// returns a function that undoes both.
+func enterRaw(fd int, out io.Writer) (func(), error) {
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, fmt.Errorf("entering raw mode: %w", err)
+	}
+	fmt.Fprint(out, enterAltScreen)
+	return func() {
+		fmt.Fprint(out, leaveAltScreen)
+		term.Restore(fd, state)
+	}, nil
+}
+
+// draw redraws the whole screen. Raw mode needs explicit carriage returns.
+func draw(out io.Writer, m *Model) {
+	fmt.Fprint(out, clearScreen+strings.ReplaceAll(m.View(), "\n", "\r\n"))
+}
+
+// report sets the footer to the action's outcome.
+func report(m *Model, err error, format string, id string) {
+	if err != nil {
+		m.SetStatus(fmt.Sprintf("Error: %v", err))
+		return
+	}
+	m.SetStatus(fmt.Sprintf(format, id))
+}
+
+// escapeKeys maps terminal escape sequences to named keys.
+var escapeKeys = map[string]Key{
+	"\x1b[A":  KeyUp,
+	"\x1b[B":  KeyDown,
+	"\x1b[5~": KeyPageUp,
+	"\x1b[6~": KeyPageDown,
+	"\x1b[H":  KeyHome,
+	"\x1b[F":  KeyEnd,
+	"\x1bOA":  KeyUp,
+	"\x1bOB":  KeyDown,
+}
+
+// ParseKeys decodes raw terminal input into key presses. Unknown escape
+// sequences are dropped.
+func ParseKeys(b []byte) []Key {
+	var keys []Key
+	for i := 0; i < len(b); {
+		switch c := b[i]; {
+		case c == 0x1b:
+			matched := false
+			for seq, key := range escapeKeys {
+				if strings.HasPrefix(string(b[i:]), seq) {
+					keys = append(keys, key)
+					i += len(seq)
+					matched = true
+					break
+				}
+			}
+			if matched {
+				continue
+			}
+			if i+1 < len(b) && (b[i+1] == '[' || b[i+1] == 'O') {
+				// Unknown sequence: skip to its final byte.
+				j := i + 2
+				for j < len(b) && (b[j] < 0x40 || b[j] > 0x7e) {
+					j++
+				}
+				i = j + 1
+				continue
+			}
+			keys = append(keys, KeyEscape)
+			i++
+		case c == 0x03:
+			keys = append(keys, KeyCtrlC)
+			i++
+		case c == '\r' || c == '\n':
+			keys = append(keys, KeyEnter)
+			i++
+		case c < 0x20 || c == 0x7f:
+			i++
+		default:
+			keys = append(keys, Key(string(rune(c))))
+			i++
+		}
+	}
+	return keys
+}
@@ -80,6 +80,18 @@ func (b *BruteForceIndex) Len() int {
 	return len(b.vectors)
 }
 
+// IDs returns the behavior IDs in the index.
+func (b *BruteForceIndex) IDs(_ context.Context) ([]string, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	ids := make([]string, 0, len(b.vectors))
+	for id := range b.vectors {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
 // Save is a no-op for the in-memory brute-force index.
 func (b *BruteForceIndex) Save(_ context.Context) error {
 	return nil
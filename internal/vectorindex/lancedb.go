@@ -309,6 +309,37 @@ func (l *LanceDBIndex) Len() int {
 	return int(count)
 }
 
+// IDs returns the behavior IDs in the index.
+func (l *LanceDBIndex) IDs(ctx context.Context) ([]string, error) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	rows, err := l.table.SelectWithColumns(ctx, []string{"id"})
+	if err != nil {
+		return nil, fmt.Errorf("select ids: %w", err)
+	}
+	ids := make([]string, 0, len(rows))
+	for _, row := range rows {
+		if id, ok := row["id"].(string); ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// Compact rewrites data files and prunes old versions so space held by
+// deleted vectors is returned to the filesystem.
+func (l *LanceDBIndex) Compact(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.table.Optimize(ctx); err != nil {
+		return fmt.Errorf("optimize table: %w", err)
+	}
+	return nil
+}
+
 // Save is a no-op. LanceDB auto-persists on write.
 func (l *LanceDBIndex) Save(_ context.Context) error {
 	return nil
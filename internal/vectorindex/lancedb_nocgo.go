@@ -31,6 +31,14 @@ func (l *LanceDBIndex) Search(_ context.Context, _ []float32, _ int) ([]SearchRe
 
 func (l *LanceDBIndex) Len() int { return 0 }
 
+func (l *LanceDBIndex) IDs(_ context.Context) ([]string, error) {
+	return nil, errors.New("LanceDB requires CGO")
+}
+
+func (l *LanceDBIndex) Compact(_ context.Context) error {
+	return errors.New("LanceDB requires CGO")
+}
+
 func (l *LanceDBIndex) Save(_ context.Context) error {
 	return errors.New("LanceDB requires CGO")
 }
@@ -0,0 +1,58 @@
+package vectorindex
+
+import (
+	"context"
+	"fmt"
+)
+
+// IDLister is implemented by indexes that can enumerate their behavior IDs.
+type IDLister interface {
+	IDs(ctx context.Context) ([]string, error)
+}
+
+// Compactor is implemented by persistent indexes that keep deleted vectors
+// on disk until compacted.
+type Compactor interface {
+	Compact(ctx context.Context) error
+}
+
+// FindOrphans returns the IDs in idx that are not in live. The index must
+// implement IDLister.
+func FindOrphans(ctx context.Context, idx VectorIndex, live map[string]bool) ([]string, error) {
+	lister, ok := idx.(IDLister)
+	if !ok {
+		return nil, fmt.Errorf("vector index %T cannot list its entries", idx)
+	}
+	ids, err := lister.IDs(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("listing vector index: %w", err)
+	}
+
+	var orphans []string
+	for _, id := range ids {
+		if !live[id] {
+			orphans = append(orphans, id)
+		}
+	}
+	return orphans, nil
+}
+
+// RemoveOrphans deletes the vectors for ids and compacts the index when it
+// supports compaction. It returns the number removed before any error.
+func RemoveOrphans(ctx context.Context, idx VectorIndex, ids []string) (int, error) {
+	removed := 0
+	for _, id := range ids {
+		if err := idx.Remove(ctx, id); err != nil {
+			return removed, fmt.Errorf("removing vector %s: %w", id, err)
+		}
+		removed++
+	}
+	if removed > 0 {
+		if c, ok := idx.(Compactor); ok {
+			if err := c.Compact(ctx); err != nil {
+				return removed, err
+			}
+		}
+	}
+	return removed, nil
+}
@@ -0,0 +1,43 @@
+package vectorindex
+
+import (
+	"context"
+	"testing"
+)
+
+func TestFindAndRemoveOrphans(t *testing.T) {
+	idx := NewBruteForceIndex()
+	ctx := context.Background()
+
+	mustAdd(t, idx, ctx, "live", []float32{1, 0, 0})
+	mustAdd(t, idx, ctx, "gone-a", []float32{0, 1, 0})
+	mustAdd(t, idx, ctx, "gone-b", []float32{0, 0, 1})
+
+	orphans, err := FindOrphans(ctx, idx, map[string]bool{"live": true})
+	if err != nil {
+		t.Fatalf("FindOrphans failed: %v", err)
+	}
+	if len(orphans) != 2 || orphans[0] != "gone-a" || orphans[1] != "gone-b" {
+		t.Fatalf("orphans = %v, want [gone-a gone-b]", orphans)
+	}
+
+	removed, err := RemoveOrphans(ctx, idx, orphans)
+	if err != nil {
+		t.Fatalf("RemoveOrphans failed: %v", err)
+	}
+	if removed != 2 {
+		t.Errorf("removed = %d, want 2", removed)
+	}
+	if idx.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", idx.Len())
+	}
+}
+
+// unlistedIndex hides IDs so FindOrphans cannot enumerate it.
+type unlistedIndex struct{ VectorIndex }
+
+func TestFindOrphans_Unlistable(t *testing.T) {
+	if _, err := FindOrphans(context.Background(), unlistedIndex{NewBruteForceIndex()}, nil); err == nil {
+		t.Error("expected error for an index without IDs")
+	}
+}
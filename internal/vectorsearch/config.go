@@ -0,0 +1,89 @@
+package vectorsearch
+
+import (
+	"path/filepath"
+
+	"github.com/nvandessel/floop/internal/config"
+	"github.com/nvandessel/floop/internal/llm"
+	"github.com/nvandessel/floop/internal/setup"
+)
+
+// serverModelName is recorded with embeddings from a server backend
+// configured without a model name.
+const serverModelName = "embedding-server"
+
+// NewEmbedderFromConfig builds the embedder described by cfg.Embedding.
+//
+// The server backend embeds through an OpenAI-compatible endpoint. The
+// local backend runs a GGUF model in-process, taken from embedding.model_path,
+// then llm.local_embedding_model_path or llm.local_model_path when
+// llm.provider is "local", then the model installed by 'floop init
+// --embeddings'.
+//
+// For the local backend the llama.cpp client is returned too; the caller
+// owns it and must close it. Returns nil, nil if no embedding model is
+// configured or installed.
+func NewEmbedderFromConfig(cfg *config.FloopConfig) (*Embedder, *llm.LocalClient) {
+	ec := cfg.Embedding
+	batchSize := ec.BatchSize
+	if batchSize < 1 {
+		batchSize = config.DefaultEmbeddingBatchSize
+	}
+
+	if ec.Backend == config.EmbeddingBackendServer {
+		server := llm.NewServerEmbedder(llm.ClientConfig{BaseURL: ec.URL, Model: ec.Model})
+		if !server.Available() {
+			return nil, nil
+		}
+		name := ec.Model
+		if name == "" {
+			name = serverModelName
+		}
+		return NewEmbedder(server.Embed, name).WithBatch(server.EmbedBatch, batchSize).WithDimension(ec.Dimension), nil
+	}
+
+	// Candidates in priority order; the first whose files exist wins.
+	detected := setup.DetectInstalled(setup.DefaultFloopDir())
+	var candidates []llm.LocalConfig
+	if ec.ModelPath != "" {
+		libPath := cfg.LLM.LocalLibPath
+		if libPath == "" {
+			libPath = detected.LibPath
+		}
+		candidates = append(candidates, llm.LocalConfig{
+			LibPath:            libPath,
+			EmbeddingModelPath: ec.ModelPath,
+			GPULayers:          cfg.LLM.LocalGPULayers,
+			ContextSize:        cfg.LLM.LocalContextSize,
+		})
+	}
+	if cfg.LLM.Provider == "local" {
+		embModelPath := cfg.LLM.LocalEmbeddingModelPath
+		if embModelPath == "" {
+			embModelPath = cfg.LLM.LocalModelPath
+		}
+		if embModelPath != "" {
+			candidates = append(candidates, llm.LocalConfig{
+				LibPath:            cfg.LLM.LocalLibPath,
+				EmbeddingModelPath: embModelPath,
+				GPULayers:          cfg.LLM.LocalGPULayers,
+				ContextSize:        cfg.LLM.LocalContextSize,
+			})
+		}
+	}
+	if detected.Available {
+		candidates = append(candidates, llm.LocalConfig{
+			LibPath:            detected.LibPath,
+			EmbeddingModelPath: detected.ModelPath,
+		})
+	}
+
+	for _, lc := range candidates {
+		localClient := llm.NewLocalClient(lc)
+		if localClient.Available() {
+			modelName := filepath.Base(lc.EmbeddingModelPath)
+			return NewEmbedder(localClient.Embed, modelName).WithDimension(ec.Dimension), localClient
+		}
+	}
+	return nil, nil
+}
@@ -32,6 +32,11 @@ func ComposeContextQuery(ctx models.ContextSnapshot) string {
 		parts = append(parts, "in a "+string(ctx.ProjectType)+" project")
 	}
 
+	// Add frameworks if detected (e.g., "using react")
+	if len(ctx.Frameworks) > 0 {
+		parts = append(parts, "using "+strings.Join(ctx.Frameworks, " "))
+	}
+
 	// Add environment if present
 	if ctx.Environment != "" {
 		parts = append(parts, ctx.Environment+" environment")
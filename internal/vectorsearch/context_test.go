@@ -101,3 +101,17 @@ func TestComposeContextQuery_ProjectTypeIncluded(t *testing.T) {
 		t.Errorf("ComposeContextQuery() = %q, want %q", got, want)
 	}
 }
+
+func TestComposeContextQuery_FrameworksIncluded(t *testing.T) {
+	ctx := models.ContextSnapshot{
+		ProjectType: models.ProjectTypeNode,
+		Frameworks:  []string{"jest", "react"},
+	}
+
+	got := ComposeContextQuery(ctx)
+	want := "in a node project using jest react"
+
+	if got != want {
+		t.Errorf("ComposeContextQuery() = %q, want %q", got, want)
+	}
+}
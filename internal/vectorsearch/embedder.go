@@ -11,6 +11,10 @@ import (
 // This matches the signature of llm.EmbeddingComparer.Embed.
 type EmbedFunc func(ctx context.Context, text string) ([]float32, error)
 
+// BatchEmbedFunc returns one embedding per text, in order.
+// This matches the signature of llm.BatchEmbedder.EmbedBatch.
+type BatchEmbedFunc func(ctx context.Context, texts []string) ([][]float32, error)
+
 // NodeGetter provides access to individual nodes. This is a subset of store.GraphStore
 // needed by BackfillMissing to load behavior content.
 type NodeGetter interface {
@@ -22,6 +26,9 @@ type NodeGetter interface {
 // It handles nomic-embed-text task prefixes and orchestrates embed + store operations.
 type Embedder struct {
 	embed     EmbedFunc
+	batch     BatchEmbedFunc
+	batchSize int
+	dims      int
 	modelName string
 }
 
@@ -37,11 +44,45 @@ func NewEmbedder(embedFn EmbedFunc, modelName string) *Embedder {
 	}
 }
 
+// WithBatch makes EmbedBehaviors embed up to size texts per call to fn.
+// Returns e for chaining.
+func (e *Embedder) WithBatch(fn BatchEmbedFunc, size int) *Embedder {
+	e.batch = fn
+	e.batchSize = size
+	return e
+}
+
+// WithDimension makes the embedder reject vectors that are not dims long,
+// so a misconfigured model cannot mix vector sizes in the index. 0 accepts
+// any size. Returns e for chaining.
+func (e *Embedder) WithDimension(dims int) *Embedder {
+	e.dims = dims
+	return e
+}
+
 // Available returns true if the embedder is ready to produce embeddings.
 func (e *Embedder) Available() bool {
 	return e != nil && e.embed != nil
 }
 
+// ModelName returns the name recorded with stored embeddings.
+func (e *Embedder) ModelName() string {
+	return e.modelName
+}
+
+// Dimension returns the configured vector size, or 0 if any size is accepted.
+func (e *Embedder) Dimension() int {
+	return e.dims
+}
+
+// checkDims returns an error if vec does not have the configured dimension.
+func (e *Embedder) checkDims(vec []float32) error {
+	if e.dims > 0 && len(vec) != e.dims {
+		return fmt.Errorf("model %s produced a %d-dim vector, want %d (check embedding.dimension)", e.modelName, len(vec), e.dims)
+	}
+	return nil
+}
+
 // EmbedAndStore embeds the given text with a search_document prefix and stores
 // the resulting vector in the embedding store. Returns the embedding vector
 // so callers can also insert it into the in-memory vector index.
@@ -51,6 +92,9 @@ func (e *Embedder) EmbedAndStore(ctx context.Context, es store.EmbeddingStore, b
 	if err != nil {
 		return nil, fmt.Errorf("embed behavior %s: %w", behaviorID, err)
 	}
+	if err := e.checkDims(vec); err != nil {
+		return nil, fmt.Errorf("embed behavior %s: %w", behaviorID, err)
+	}
 	if err := es.StoreEmbedding(ctx, behaviorID, vec, e.modelName); err != nil {
 		return nil, err
 	}
@@ -60,7 +104,14 @@ func (e *Embedder) EmbedAndStore(ctx context.Context, es store.EmbeddingStore, b
 // EmbedQuery embeds a context query with a search_query prefix for retrieval.
 func (e *Embedder) EmbedQuery(ctx context.Context, queryText string) ([]float32, error) {
 	prefixed := "search_query: " + queryText
-	return e.embed(ctx, prefixed)
+	vec, err := e.embed(ctx, prefixed)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.checkDims(vec); err != nil {
+		return nil, err
+	}
+	return vec, nil
 }
 
 // BackfillMissing embeds all behaviors that don't yet have embedding vectors.
@@ -71,33 +122,100 @@ func (e *Embedder) BackfillMissing(ctx context.Context, ns NodeGetter) (int, err
 		return 0, fmt.Errorf("get unembedded behaviors: %w", err)
 	}
 
-	count := 0
+	count, _ := e.EmbedBehaviors(ctx, ns, ids) // best-effort backfill
+	return count, nil
+}
+
+// EmbedBehaviors embeds and stores the behaviors with the given IDs,
+// replacing any existing embeddings. Behaviors that are missing or have no
+// canonical text are skipped. Texts are embedded in batches when the
+// embedder has a batch function, and one at a time otherwise. Returns the
+// number of behaviors embedded and, if any failed, an error describing the
+// first failure.
+func (e *Embedder) EmbedBehaviors(ctx context.Context, ns NodeGetter, ids []string) (int, error) {
+	var pendingIDs, texts []string
 	for _, id := range ids {
 		node, err := ns.GetNode(ctx, id)
 		if err != nil {
 			continue // skip missing nodes
 		}
-
 		text, ok := extractCanonical(node)
 		if !ok {
 			continue // skip behaviors without canonical text
 		}
+		pendingIDs = append(pendingIDs, id)
+		texts = append(texts, text)
+	}
 
-		if _, err := e.EmbedAndStore(ctx, ns, id, text); err != nil {
-			continue // skip failures, best-effort backfill
+	count, failed := 0, 0
+	var firstErr error
+	fail := func(n int, err error) {
+		failed += n
+		if firstErr == nil {
+			firstErr = err
 		}
-		count++
 	}
 
+	if e.batch == nil || e.batchSize < 1 {
+		for i, id := range pendingIDs {
+			if err := ctx.Err(); err != nil {
+				fail(len(pendingIDs)-i, err)
+				break
+			}
+			if _, err := e.EmbedAndStore(ctx, ns, id, texts[i]); err != nil {
+				fail(1, err)
+				continue
+			}
+			count++
+		}
+	} else {
+		for start := 0; start < len(pendingIDs); start += e.batchSize {
+			end := min(start+e.batchSize, len(pendingIDs))
+			prefixed := make([]string, 0, end-start)
+			for _, text := range texts[start:end] {
+				prefixed = append(prefixed, "search_document: "+text)
+			}
+			vecs, err := e.batch(ctx, prefixed)
+			if err == nil && len(vecs) != len(prefixed) {
+				err = fmt.Errorf("got %d embeddings for %d texts", len(vecs), len(prefixed))
+			}
+			if err != nil {
+				fail(end-start, fmt.Errorf("embed batch: %w", err))
+				continue
+			}
+			for i, vec := range vecs {
+				id := pendingIDs[start+i]
+				if err := e.checkDims(vec); err != nil {
+					fail(1, fmt.Errorf("embed behavior %s: %w", id, err))
+					continue
+				}
+				if err := ns.StoreEmbedding(ctx, id, vec, e.modelName); err != nil {
+					fail(1, err)
+					continue
+				}
+				count++
+			}
+		}
+	}
+
+	if firstErr != nil {
+		return count, fmt.Errorf("%d of %d behaviors failed to embed: %w", failed, len(pendingIDs), firstErr)
+	}
 	return count, nil
 }
 
-// extractCanonical extracts the canonical text from a behavior node's content map.
+// extractCanonical extracts the canonical text from a behavior node's content
+// map, either at the top level or nested under "content" as stored nodes have it.
 func extractCanonical(node *store.Node) (string, bool) {
 	if node == nil || node.Content == nil {
 		return "", false
 	}
 	val, ok := node.Content["canonical"]
+	if !ok {
+		if content, isMap := node.Content["content"].(map[string]interface{}); isMap {
+			val, ok = content["canonical"]
+		}
+	}
 	if !ok {
 		return "", false
 	}
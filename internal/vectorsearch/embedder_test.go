@@ -3,6 +3,7 @@ package vectorsearch
 import (
 	"context"
 	"errors"
+	"strings"
 	"sync"
 	"testing"
 
@@ -279,6 +280,54 @@ func TestEmbedder_BackfillMissing(t *testing.T) {
 	})
 }
 
+func TestEmbedder_EmbedBehaviors(t *testing.T) {
+	es := newMockEmbeddingStore()
+	for _, id := range []string{"b1", "b2", "b3"} {
+		es.nodes[id] = store.Node{ID: id, Kind: "behavior", Content: map[string]interface{}{"canonical": "text " + id}}
+	}
+	es.embeddings["b1"] = embeddingRecord{embedding: []float32{0.5, 0.5, 0.5, 0.5}, model: "old-model"}
+
+	t.Run("embeds in batches and replaces existing vectors", func(t *testing.T) {
+		var batches [][]string
+		batch := func(_ context.Context, texts []string) ([][]float32, error) {
+			batches = append(batches, texts)
+			vecs := make([][]float32, len(texts))
+			for i := range texts {
+				vecs[i] = []float32{1, 0}
+			}
+			return vecs, nil
+		}
+		e := NewEmbedder((&mockEmbedder{}).embedCall, "new-model").WithBatch(batch, 2).WithDimension(2)
+
+		count, err := e.EmbedBehaviors(context.Background(), es, []string{"b1", "b2", "b3", "missing"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if count != 3 {
+			t.Errorf("expected 3 embedded, got %d", count)
+		}
+		if len(batches) != 2 || len(batches[0]) != 2 || batches[0][0] != "search_document: text b1" {
+			t.Errorf("unexpected batches: %v", batches)
+		}
+		if rec := es.embeddings["b1"]; rec.model != "new-model" || len(rec.embedding) != 2 {
+			t.Errorf("b1 not re-embedded: %+v", rec)
+		}
+	})
+
+	t.Run("rejects vectors of the wrong dimension", func(t *testing.T) {
+		mock := &mockEmbedder{} // returns 3-dim vectors
+		e := NewEmbedder(mock.embedCall, "test-model").WithDimension(2)
+
+		count, err := e.EmbedBehaviors(context.Background(), es, []string{"b2", "b3"})
+		if count != 0 || err == nil || !strings.Contains(err.Error(), "2 of 2 behaviors failed") {
+			t.Errorf("EmbedBehaviors() = %d, %v; want dimension errors", count, err)
+		}
+		if _, err := e.EmbedQuery(context.Background(), "query"); err == nil {
+			t.Error("EmbedQuery() accepted a vector of the wrong dimension")
+		}
+	})
+}
+
 func TestExtractCanonical(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -294,6 +343,14 @@ func TestExtractCanonical(t *testing.T) {
 			want:   "use snake_case",
 			wantOK: true,
 		},
+		{
+			name: "canonical nested under content",
+			node: store.Node{
+				Content: map[string]interface{}{"content": map[string]interface{}{"canonical": "use slog"}},
+			},
+			want:   "use slog",
+			wantOK: true,
+		},
 		{
 			name: "nil content",
 			node: store.Node{
@@ -0,0 +1,274 @@
+package visualization
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+// FormatGraphML is the GraphML output format, readable by Gephi and yEd.
+const FormatGraphML Format = "graphml"
+
+// ScopedStore pairs a graph store with the scope label its nodes get.
+type ScopedStore struct {
+	Scope string
+	Store store.GraphStore
+}
+
+// ExportFilter restricts which nodes and edges an export includes.
+// Zero values include everything.
+type ExportFilter struct {
+	// EdgeKinds keeps only edges of these kinds.
+	EdgeKinds []store.EdgeKind
+	// Tags keeps only behaviors carrying at least one of these tags.
+	// Edges are kept only when both endpoints are kept.
+	Tags []string
+}
+
+// ExportNode is a behavior in an exported graph.
+type ExportNode struct {
+	ID         string   `json:"id"`
+	Name       string   `json:"name"`
+	Kind       string   `json:"kind"`
+	Scope      string   `json:"scope"`
+	Confidence float64  `json:"confidence"`
+	Tags       []string `json:"tags"`
+}
+
+// ExportEdge is an edge between two exported behaviors.
+type ExportEdge struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Kind   string  `json:"kind"`
+	Weight float64 `json:"weight"`
+}
+
+// ExportGraph is a filtered snapshot of the behavior graph.
+type ExportGraph struct {
+	Nodes     []ExportNode `json:"nodes"`
+	Edges     []ExportEdge `json:"edges"`
+	NodeCount int          `json:"node_count"`
+	EdgeCount int          `json:"edge_count"`
+}
+
+// BuildExportGraph collects behaviors and edges from stores, applying filter.
+// When several stores hold the same behavior ID, the first store wins.
+// Edges are read from every store, so edges stored globally between a local
+// and a global behavior are included when both endpoints are.
+func BuildExportGraph(ctx context.Context, stores []ScopedStore, filter ExportFilter) (*ExportGraph, error) {
+	g := &ExportGraph{Nodes: []ExportNode{}, Edges: []ExportEdge{}}
+	included := make(map[string]bool)
+
+	for _, ss := range stores {
+		nodes, err := ss.Store.QueryNodes(ctx, map[string]interface{}{"kind": string(store.NodeKindBehavior)})
+		if err != nil {
+			return nil, fmt.Errorf("query %s nodes: %w", ss.Scope, err)
+		}
+		// Sort within each store so exports are stable and diffable.
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+		for _, node := range nodes {
+			if included[node.ID] {
+				continue
+			}
+			n := exportNode(node, ss.Scope)
+			if len(filter.Tags) > 0 && !hasAnyTag(n.Tags, filter.Tags) {
+				continue
+			}
+			included[node.ID] = true
+			g.Nodes = append(g.Nodes, n)
+		}
+	}
+
+	seen := make(map[string]bool) // dedup "src|tgt|kind"
+	for _, ss := range stores {
+		for _, n := range g.Nodes {
+			edges, err := ss.Store.GetEdges(ctx, n.ID, store.DirectionOutbound, "")
+			if err != nil {
+				return nil, fmt.Errorf("get edges for node %s: %w", n.ID, err)
+			}
+			for _, edge := range edges {
+				if !included[edge.Target] {
+					continue
+				}
+				if len(filter.EdgeKinds) > 0 && !slices.Contains(filter.EdgeKinds, edge.Kind) {
+					continue
+				}
+				key := edge.Source + "|" + edge.Target + "|" + string(edge.Kind)
+				if seen[key] {
+					continue
+				}
+				seen[key] = true
+				g.Edges = append(g.Edges, ExportEdge{
+					Source: edge.Source,
+					Target: edge.Target,
+					Kind:   string(edge.Kind),
+					Weight: edge.Weight,
+				})
+			}
+		}
+	}
+
+	g.NodeCount = len(g.Nodes)
+	g.EdgeCount = len(g.Edges)
+	return g, nil
+}
+
+// exportNode extracts the exported fields from a behavior node.
+func exportNode(node store.Node, scope string) ExportNode {
+	n := ExportNode{ID: node.ID, Scope: scope, Confidence: 0.6, Tags: []string{}}
+	if name, ok := node.Content["name"].(string); ok {
+		n.Name = name
+	}
+	if kind, ok := node.Content["kind"].(string); ok {
+		n.Kind = kind
+	}
+	if c, ok := node.Metadata["confidence"].(float64); ok {
+		n.Confidence = c
+	}
+	if content, ok := node.Content["content"].(map[string]interface{}); ok {
+		switch tags := content["tags"].(type) {
+		case []string:
+			n.Tags = append(n.Tags, tags...)
+		case []interface{}:
+			for _, t := range tags {
+				if s, ok := t.(string); ok {
+					n.Tags = append(n.Tags, s)
+				}
+			}
+		}
+	}
+	return n
+}
+
+func hasAnyTag(tags, want []string) bool {
+	for _, t := range want {
+		if slices.Contains(tags, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// RenderExportDOT renders an exported graph as Graphviz DOT.
+func RenderExportDOT(g *ExportGraph) string {
+	var b strings.Builder
+	b.WriteString("digraph floop {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=filled, fontname=\"Helvetica\"];\n")
+	b.WriteString("  edge [fontname=\"Helvetica\", fontsize=10];\n\n")
+
+	for _, n := range g.Nodes {
+		color := nodeColors[n.Kind]
+		if color == "" {
+			color = "lightgray"
+		}
+		b.WriteString(fmt.Sprintf("  %q [label=%q, fillcolor=%q, tooltip=\"confidence=%.2f scope=%s\"];\n",
+			n.ID, truncate(n.Name, 40), color, n.Confidence, n.Scope))
+	}
+	b.WriteString("\n")
+
+	for _, e := range g.Edges {
+		style := edgeStyles[store.EdgeKind(e.Kind)]
+		if style == "" {
+			style = "solid"
+		}
+		b.WriteString(fmt.Sprintf("  %q -> %q [label=%q, style=%s, weight=\"%.1f\"];\n",
+			e.Source, e.Target, e.Kind, style, e.Weight))
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// GraphML document structure. Attribute keys are declared once and
+// referenced from each node and edge.
+type graphMLDoc struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   graphMLGraph `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLGraph struct {
+	ID          string        `xml:"id,attr"`
+	EdgeDefault string        `xml:"edgedefault,attr"`
+	Nodes       []graphMLNode `xml:"node"`
+	Edges       []graphMLEdge `xml:"edge"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// RenderExportGraphML renders an exported graph as GraphML.
+// Tags are joined with commas into a single string attribute.
+func RenderExportGraphML(g *ExportGraph) ([]byte, error) {
+	doc := graphMLDoc{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "name", For: "node", AttrName: "name", AttrType: "string"},
+			{ID: "kind", For: "node", AttrName: "kind", AttrType: "string"},
+			{ID: "scope", For: "node", AttrName: "scope", AttrType: "string"},
+			{ID: "confidence", For: "node", AttrName: "confidence", AttrType: "double"},
+			{ID: "tags", For: "node", AttrName: "tags", AttrType: "string"},
+			{ID: "edge_kind", For: "edge", AttrName: "kind", AttrType: "string"},
+			{ID: "weight", For: "edge", AttrName: "weight", AttrType: "double"},
+		},
+		Graph: graphMLGraph{ID: "floop", EdgeDefault: "directed"},
+	}
+
+	for _, n := range g.Nodes {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: n.ID,
+			Data: []graphMLData{
+				{Key: "name", Value: n.Name},
+				{Key: "kind", Value: n.Kind},
+				{Key: "scope", Value: n.Scope},
+				{Key: "confidence", Value: fmt.Sprintf("%g", n.Confidence)},
+				{Key: "tags", Value: strings.Join(n.Tags, ",")},
+			},
+		})
+	}
+	for i, e := range g.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%d", i),
+			Source: e.Source,
+			Target: e.Target,
+			Data: []graphMLData{
+				{Key: "edge_kind", Value: e.Kind},
+				{Key: "weight", Value: fmt.Sprintf("%g", e.Weight)},
+			},
+		})
+	}
+
+	body, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal GraphML: %w", err)
+	}
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
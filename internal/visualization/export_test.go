@@ -0,0 +1,152 @@
+package visualization
+
+import (
+	"context"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nvandessel/floop/internal/store"
+)
+
+func addTaggedBehavior(t *testing.T, gs store.GraphStore, id string, tags ...interface{}) {
+	t.Helper()
+	if _, err := gs.AddNode(context.Background(), store.Node{
+		ID:   id,
+		Kind: store.NodeKindBehavior,
+		Content: map[string]interface{}{
+			"name":    "name-" + id,
+			"kind":    "directive",
+			"content": map[string]interface{}{"canonical": id, "tags": tags},
+		},
+		Metadata: map[string]interface{}{"confidence": 0.8},
+	}); err != nil {
+		t.Fatalf("add node %s: %v", id, err)
+	}
+}
+
+func addTestEdge(t *testing.T, gs store.GraphStore, source, target string, kind store.EdgeKind) {
+	t.Helper()
+	if err := gs.AddEdge(context.Background(), store.Edge{Source: source, Target: target, Kind: kind, Weight: 0.5, CreatedAt: time.Now()}); err != nil {
+		t.Fatalf("add edge %s->%s: %v", source, target, err)
+	}
+}
+
+func setupExportStores(t *testing.T) []ScopedStore {
+	t.Helper()
+	local := store.NewInMemoryGraphStore()
+	global := store.NewInMemoryGraphStore()
+
+	addTaggedBehavior(t, local, "l-1", "go")
+	addTaggedBehavior(t, local, "l-2", "testing")
+	addTaggedBehavior(t, global, "g-1", "go")
+	addTaggedBehavior(t, global, "l-1") // shadowed by the local copy
+
+	addTestEdge(t, local, "l-1", "l-2", store.EdgeKindRequires)
+	addTestEdge(t, global, "l-1", "g-1", store.EdgeKindSimilarTo) // cross-store edge lives in global
+
+	return []ScopedStore{{Scope: "local", Store: local}, {Scope: "global", Store: global}}
+}
+
+func TestBuildExportGraph(t *testing.T) {
+	ctx := context.Background()
+	stores := setupExportStores(t)
+
+	tests := []struct {
+		name      string
+		stores    []ScopedStore
+		filter    ExportFilter
+		wantNodes []string
+		wantEdges []string
+	}{
+		{"all", stores, ExportFilter{}, []string{"l-1", "l-2", "g-1"}, []string{"l-1|l-2|requires", "l-1|g-1|similar-to"}},
+		{"edge kind", stores, ExportFilter{EdgeKinds: []store.EdgeKind{store.EdgeKindSimilarTo}}, []string{"l-1", "l-2", "g-1"}, []string{"l-1|g-1|similar-to"}},
+		{"tag", stores, ExportFilter{Tags: []string{"go"}}, []string{"l-1", "g-1"}, []string{"l-1|g-1|similar-to"}},
+		{"local scope", stores[:1], ExportFilter{}, []string{"l-1", "l-2"}, []string{"l-1|l-2|requires"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := BuildExportGraph(ctx, tt.stores, tt.filter)
+			if err != nil {
+				t.Fatalf("BuildExportGraph() error = %v", err)
+			}
+			var nodes, edges []string
+			for _, n := range g.Nodes {
+				nodes = append(nodes, n.ID)
+			}
+			for _, e := range g.Edges {
+				edges = append(edges, e.Source+"|"+e.Target+"|"+e.Kind)
+			}
+			if strings.Join(nodes, " ") != strings.Join(tt.wantNodes, " ") {
+				t.Errorf("nodes = %v, want %v", nodes, tt.wantNodes)
+			}
+			if strings.Join(edges, " ") != strings.Join(tt.wantEdges, " ") {
+				t.Errorf("edges = %v, want %v", edges, tt.wantEdges)
+			}
+			if g.NodeCount != len(g.Nodes) || g.EdgeCount != len(g.Edges) {
+				t.Errorf("counts = %d/%d", g.NodeCount, g.EdgeCount)
+			}
+		})
+	}
+
+	g, err := BuildExportGraph(ctx, stores, ExportFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if l1 := g.Nodes[0]; l1.Scope != "local" || l1.Tags[0] != "go" || l1.Confidence != 0.8 {
+		t.Errorf("l-1 = %+v, want local copy with tag go", l1)
+	}
+}
+
+func TestRenderExportDOT(t *testing.T) {
+	g, err := BuildExportGraph(context.Background(), setupExportStores(t), ExportFilter{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := RenderExportDOT(g)
+	for _, want := range []string{
+		"digraph floop {",
+		`"g-1" [label="name-g-1", fillcolor="steelblue", tooltip="confidence=0.80 scope=global"];`,
+		`"l-1" -> "l-2" [label="requires", style=solid, weight="0.5"];`,
+	} {
+		if !strings.Contains(dot, want) {
+			t.Errorf("DOT missing %q:\n%s", want, dot)
+		}
+	}
+}
+
+func TestRenderExportGraphML(t *testing.T) {
+	g := &ExportGraph{
+		Nodes: []ExportNode{
+			{ID: "a", Name: "use <T> & friends", Kind: "directive", Scope: "local", Confidence: 0.7, Tags: []string{"go", "generics"}},
+			{ID: "b", Name: "b", Kind: "constraint", Scope: "global", Confidence: 0.6},
+		},
+		Edges: []ExportEdge{{Source: "a", Target: "b", Kind: "overrides", Weight: 1}},
+	}
+
+	data, err := RenderExportGraphML(g)
+	if err != nil {
+		t.Fatalf("RenderExportGraphML() error = %v", err)
+	}
+	if !strings.HasPrefix(string(data), "<?xml") {
+		t.Errorf("missing XML header: %s", data)
+	}
+
+	var doc graphMLDoc
+	if err := xml.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("output is not valid XML: %v\n%s", err, data)
+	}
+	if len(doc.Graph.Nodes) != 2 || len(doc.Graph.Edges) != 1 {
+		t.Fatalf("got %d nodes, %d edges", len(doc.Graph.Nodes), len(doc.Graph.Edges))
+	}
+	if got := doc.Graph.Nodes[0].Data[0].Value; got != "use <T> & friends" {
+		t.Errorf("name round-trip = %q", got)
+	}
+	if got := doc.Graph.Nodes[0].Data[4].Value; got != "go,generics" {
+		t.Errorf("tags = %q", got)
+	}
+	if e := doc.Graph.Edges[0]; e.Source != "a" || e.Target != "b" || e.Data[0].Value != "overrides" {
+		t.Errorf("edge = %+v", e)
+	}
+}
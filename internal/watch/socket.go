@@ -0,0 +1,120 @@
+package watch
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+// writeTimeout bounds how long a slow listener may hold up a broadcast.
+const writeTimeout = time.Second
+
+// Broadcaster serves a JSONL stream on a unix socket. Every connected client
+// receives each line sent after it connects, preceded by the most recent
+// line so it starts from the current state.
+type Broadcaster struct {
+	path     string
+	listener net.Listener
+
+	mu      sync.Mutex
+	clients map[net.Conn]bool
+	last    []byte
+	wg      sync.WaitGroup
+}
+
+// Listen creates a unix socket at path and starts accepting clients. A stale
+// socket left by a previous run is replaced; one still in use is an error.
+func Listen(path string) (*Broadcaster, error) {
+	if _, err := os.Stat(path); err == nil {
+		if conn, err := net.Dial("unix", path); err == nil {
+			conn.Close()
+			return nil, fmt.Errorf("socket %s is already in use", path)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("removing stale socket: %w", err)
+		}
+	}
+
+	l, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("listening on %s: %w", path, err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("securing socket: %w", err)
+	}
+
+	b := &Broadcaster{path: path, listener: l, clients: make(map[net.Conn]bool)}
+	b.wg.Add(1)
+	go b.accept()
+	return b, nil
+}
+
+// accept registers clients until the listener is closed.
+func (b *Broadcaster) accept() {
+	defer b.wg.Done()
+	for {
+		conn, err := b.listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			continue
+		}
+		b.mu.Lock()
+		if b.last != nil && !write(conn, b.last) {
+			b.mu.Unlock()
+			continue
+		}
+		b.clients[conn] = true
+		b.mu.Unlock()
+	}
+}
+
+// Write sends one line to every client, dropping clients that fail to keep
+// up. It implements io.Writer so it can back a json.Encoder.
+func (b *Broadcaster) Write(line []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.last = append([]byte(nil), line...)
+	for conn := range b.clients {
+		if !write(conn, line) {
+			delete(b.clients, conn)
+		}
+	}
+	return len(line), nil
+}
+
+// Clients returns the number of connected clients.
+func (b *Broadcaster) Clients() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.clients)
+}
+
+// Close disconnects all clients and removes the socket.
+func (b *Broadcaster) Close() error {
+	err := b.listener.Close()
+	b.wg.Wait()
+	b.mu.Lock()
+	for conn := range b.clients {
+		conn.Close()
+	}
+	b.clients = nil
+	b.mu.Unlock()
+	os.Remove(b.path)
+	return err
+}
+
+// write sends data to conn, closing it on failure.
+func write(conn net.Conn, data []byte) bool {
+	conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	if _, err := conn.Write(data); err != nil {
+		conn.Close()
+		return false
+	}
+	return true
+}
@@ -0,0 +1,166 @@
+// Package watch detects changes to the activation context of a repository —
+// the file most recently edited, the checked-out git branch, and the task
+// named in .floop/task — by polling, and fans updates out to listeners.
+package watch
+
+import (
+	"bufio"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// DefaultInterval is the default polling interval.
+const DefaultInterval = time.Second
+
+// TaskFile is the file in .floop/ whose first line names the current task.
+const TaskFile = "task"
+
+// maxScanFiles bounds how many files one poll inspects, so a huge checkout
+// cannot stall the watcher.
+const maxScanFiles = 50000
+
+// skipDirs are directories never scanned for edits, in addition to any
+// directory whose name starts with a dot.
+var skipDirs = map[string]bool{"node_modules": true, "vendor": true}
+
+// State is the activation context observed in a repository.
+type State struct {
+	File   string `json:"file,omitempty"`   // most recently modified file, relative to the root
+	Branch string `json:"branch,omitempty"` // checked-out branch, or short commit when detached
+	Task   string `json:"task,omitempty"`   // first line of .floop/task
+}
+
+// Poller observes a repository's State across calls to Poll.
+type Poller struct {
+	root    string
+	state   State
+	newest  time.Time
+	started bool
+}
+
+// NewPoller returns a Poller for the repository at root.
+func NewPoller(root string) *Poller {
+	return &Poller{root: root}
+}
+
+// Poll reads the repository's current State and reports whether it differs
+// from the previous poll. The first poll always reports a change; files
+// modified before it are not treated as edits.
+func (p *Poller) Poll() (State, bool) {
+	next := p.state
+	next.Branch = ReadBranch(p.root)
+	next.Task = ReadTask(p.root)
+
+	if file, mod := p.newestFile(); mod.After(p.newest) {
+		if p.started {
+			next.File = file
+		}
+		p.newest = mod
+	}
+
+	changed := !p.started || next != p.state
+	p.state = next
+	p.started = true
+	return next, changed
+}
+
+// newestFile returns the most recently modified file under the root.
+func (p *Poller) newestFile() (string, time.Time) {
+	var (
+		file    string
+		newest  time.Time
+		scanned int
+	)
+	errStop := errors.New("stop")
+	_ = filepath.WalkDir(p.root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // unreadable entries are skipped, not fatal
+		}
+		if d.IsDir() {
+			name := d.Name()
+			if path != p.root && (strings.HasPrefix(name, ".") || skipDirs[name]) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if scanned++; scanned > maxScanFiles {
+			return errStop
+		}
+		info, err := d.Info()
+		if err != nil {
+			return nil
+		}
+		if info.ModTime().After(newest) {
+			newest = info.ModTime()
+			file = path
+		}
+		return nil
+	})
+	if file == "" {
+		return "", newest
+	}
+	rel, err := filepath.Rel(p.root, file)
+	if err != nil {
+		return file, newest
+	}
+	return filepath.ToSlash(rel), newest
+}
+
+// ReadBranch returns the branch checked out in the repository at root, the
+// short commit hash when HEAD is detached, or "" outside a git repository.
+// It reads .git/HEAD directly so polling does not spawn git processes.
+func ReadBranch(root string) string {
+	gitDir := filepath.Join(root, ".git")
+	if info, err := os.Stat(gitDir); err == nil && !info.IsDir() {
+		// Worktrees and submodules use a .git file pointing at the git dir.
+		data, err := os.ReadFile(gitDir)
+		if err != nil {
+			return ""
+		}
+		ref, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: ")
+		if !ok {
+			return ""
+		}
+		if !filepath.IsAbs(ref) {
+			ref = filepath.Join(root, ref)
+		}
+		gitDir = ref
+	}
+
+	data, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+	if err != nil {
+		return ""
+	}
+	head := strings.TrimSpace(string(data))
+	if ref, ok := strings.CutPrefix(head, "ref: "); ok {
+		return strings.TrimPrefix(ref, "refs/heads/")
+	}
+	if len(head) > 7 {
+		return head[:7]
+	}
+	return head
+}
+
+// ReadTask returns the first non-empty line of .floop/task under root, or ""
+// if the file does not exist.
+func ReadTask(root string) string {
+	f, err := os.Open(filepath.Join(root, ".floop", TaskFile))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			return line
+		}
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package watch
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeFile(t *testing.T, path, content string, mod time.Time) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, mod, mod); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestPollerDetectsChanges(t *testing.T) {
+	root := t.TempDir()
+	base := time.Now().Add(-time.Hour)
+	writeFile(t, filepath.Join(root, "main.go"), "package main", base)
+	writeFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/main\n", base)
+
+	p := NewPoller(root)
+	state, changed := p.Poll()
+	if !changed {
+		t.Error("first poll should report a change")
+	}
+	if state != (State{Branch: "main"}) {
+		t.Errorf("initial state = %+v, want branch only", state)
+	}
+
+	if _, changed := p.Poll(); changed {
+		t.Error("poll without edits should not report a change")
+	}
+
+	writeFile(t, filepath.Join(root, "pkg", "util.py"), "x = 1", base.Add(time.Minute))
+	if state, changed = p.Poll(); !changed || state.File != "pkg/util.py" {
+		t.Errorf("after edit: state = %+v, changed = %v", state, changed)
+	}
+
+	// Edits in dot-directories and node_modules are ignored.
+	writeFile(t, filepath.Join(root, ".floop", "nodes.jsonl"), "{}", base.Add(2*time.Minute))
+	writeFile(t, filepath.Join(root, "node_modules", "x.js"), "", base.Add(2*time.Minute))
+	if _, changed := p.Poll(); changed {
+		t.Error("edits in ignored directories should not report a change")
+	}
+
+	writeFile(t, filepath.Join(root, ".git", "HEAD"), "ref: refs/heads/feature/x\n", base)
+	writeFile(t, filepath.Join(root, ".floop", TaskFile), "\nrefactoring\n", base)
+	state, changed = p.Poll()
+	if !changed || state.Branch != "feature/x" || state.Task != "refactoring" {
+		t.Errorf("after branch and task switch: state = %+v, changed = %v", state, changed)
+	}
+	if state.File != "pkg/util.py" {
+		t.Errorf("file should persist across polls, got %q", state.File)
+	}
+}
+
+func TestReadBranch(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, root string)
+		want  string
+	}{
+		{"no repo", func(t *testing.T, root string) {}, ""},
+		{"detached", func(t *testing.T, root string) {
+			writeFile(t, filepath.Join(root, ".git", "HEAD"), "0123456789abcdef\n", time.Now())
+		}, "0123456"},
+		{"worktree", func(t *testing.T, root string) {
+			writeFile(t, filepath.Join(root, "gitdir", "HEAD"), "ref: refs/heads/wt\n", time.Now())
+			writeFile(t, filepath.Join(root, ".git"), "gitdir: gitdir\n", time.Now())
+		}, "wt"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			root := t.TempDir()
+			tt.setup(t, root)
+			if got := ReadBranch(root); got != tt.want {
+				t.Errorf("ReadBranch() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBroadcaster(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "w.sock")
+	b, err := Listen(path)
+	if err != nil {
+		t.Skipf("unix sockets unavailable: %v", err)
+	}
+	defer b.Close()
+
+	if _, err := Listen(path); err == nil {
+		t.Error("second Listen on an active socket should fail")
+	}
+
+	b.Write([]byte("first\n"))
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Dial() error = %v", err)
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	if line, _ := r.ReadString('\n'); line != "first\n" {
+		t.Errorf("on connect got %q, want latest line", line)
+	}
+
+	for deadline := time.Now().Add(5 * time.Second); b.Clients() == 0 && time.Now().Before(deadline); {
+		time.Sleep(10 * time.Millisecond)
+	}
+	b.Write([]byte("second\n"))
+	if line, _ := r.ReadString('\n'); line != "second\n" {
+		t.Errorf("got %q, want broadcast line", line)
+	}
+}